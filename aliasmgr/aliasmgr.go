@@ -84,6 +84,10 @@ var (
 	// ErrAliasNotFound is returned when the alias is not found and can't
 	// be mapped to a base SCID.
 	ErrAliasNotFound = fmt.Errorf("alias not found")
+
+	// ErrBaseNotFound is returned when an alias is allocated for a base
+	// SCID that the manager doesn't know of.
+	ErrBaseNotFound = fmt.Errorf("base scid not found")
 )
 
 // Manager is a struct that handles aliases for LND. It has an underlying
@@ -603,6 +607,57 @@ func (m *Manager) RequestAlias() (lnwire.ShortChannelID, error) {
 	return nextAlias, nil
 }
 
+// AllocateLocalAlias allocates the next un-allocated alias and adds it as a
+// local alias of the passed base SCID. Like other aliases added after the
+// channel was created, it is not used for gossip and isn't communicated to the
+// channel peer. ErrBaseNotFound is returned if the base SCID doesn't belong to
+// an option-scid-alias or zero-conf channel.
+func (m *Manager) AllocateLocalAlias(
+	baseScid lnwire.ShortChannelID) (lnwire.ShortChannelID, error) {
+
+	m.RLock()
+	_, ok := m.baseToSet[baseScid]
+	m.RUnlock()
+
+	if !ok {
+		return lnwire.ShortChannelID{}, fmt.Errorf("%w: %v",
+			ErrBaseNotFound, baseScid)
+	}
+
+	alias, err := m.RequestAlias()
+	if err != nil {
+		return lnwire.ShortChannelID{}, err
+	}
+
+	err = m.AddLocalAlias(alias, baseScid, false, true)
+	if err != nil {
+		return lnwire.ShortChannelID{}, err
+	}
+
+	return alias, nil
+}
+
+// LookupBase returns the base SCID of the passed alias. Unlike FindBaseSCID,
+// which is only aware of the aliases used for gossip, all local aliases are
+// considered. ErrAliasNotFound is returned if the alias isn't known.
+func (m *Manager) LookupBase(
+	alias lnwire.ShortChannelID) (lnwire.ShortChannelID, error) {
+
+	m.RLock()
+	defer m.RUnlock()
+
+	for base, aliases := range m.baseToSet {
+		for _, a := range aliases {
+			if a == alias {
+				return base, nil
+			}
+		}
+	}
+
+	return lnwire.ShortChannelID{}, fmt.Errorf("%w: %v", ErrAliasNotFound,
+		alias)
+}
+
 // ListAliases returns a carbon copy of baseToSet. This is used by the rpc
 // layer.
 func (m *Manager) ListAliases() ScidAliasMap {
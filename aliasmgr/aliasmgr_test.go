@@ -188,6 +188,62 @@ func TestAliasLifecycle(t *testing.T) {
 	require.EqualValues(t, 2, thirdRequested.TxPosition)
 }
 
+// TestAllocateLocalAlias tests that aliases can be allocated for known base
+// SCIDs and mapped back to their base SCID.
+func TestAllocateLocalAlias(t *testing.T) {
+	t.Parallel()
+
+	// Create the backend database and use this to create the aliasStore.
+	dbPath := filepath.Join(t.TempDir(), "testdb")
+	db, err := kvdb.Create(
+		kvdb.BoltBackendName, dbPath, true, kvdb.DefaultDBTimeout,
+	)
+	require.NoError(t, err)
+	defer db.Close()
+
+	linkUpdater := func(shortID lnwire.ShortChannelID) error {
+		return nil
+	}
+
+	aliasStore, err := NewManager(db, linkUpdater)
+	require.NoError(t, err)
+
+	baseScid := lnwire.NewShortChanIDFromInt(123123123)
+	unknownScid := lnwire.NewShortChanIDFromInt(789789789)
+
+	// Allocating an alias for a channel we don't know of fails.
+	_, err = aliasStore.AllocateLocalAlias(baseScid)
+	require.ErrorIs(t, err, ErrBaseNotFound)
+
+	// Once the channel has its first alias, more can be allocated.
+	firstAlias, err := aliasStore.RequestAlias()
+	require.NoError(t, err)
+	err = aliasStore.AddLocalAlias(firstAlias, baseScid, true, false)
+	require.NoError(t, err)
+
+	secondAlias, err := aliasStore.AllocateLocalAlias(baseScid)
+	require.NoError(t, err)
+	require.Equal(t, getNextScid(firstAlias), secondAlias)
+	require.Equal(
+		t, []lnwire.ShortChannelID{firstAlias, secondAlias},
+		aliasStore.GetAliases(baseScid),
+	)
+
+	// Both aliases map back to the base SCID, even though only the first
+	// one is used for gossip.
+	for _, alias := range []lnwire.ShortChannelID{firstAlias, secondAlias} {
+		base, err := aliasStore.LookupBase(alias)
+		require.NoError(t, err)
+		require.Equal(t, baseScid, base)
+	}
+
+	_, err = aliasStore.FindBaseSCID(secondAlias)
+	require.Error(t, err)
+
+	_, err = aliasStore.LookupBase(unknownScid)
+	require.ErrorIs(t, err, ErrAliasNotFound)
+}
+
 // TestGetNextScid tests that given a current lnwire.ShortChannelID,
 // getNextScid returns the expected alias to use next.
 func TestGetNextScid(t *testing.T) {
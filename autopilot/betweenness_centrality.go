@@ -174,6 +174,14 @@ func (bc *BetweennessCentrality) Refresh(graph ChannelGraph) error {
 		return err
 	}
 
+	bc.refreshSimpleGraph(cache)
+
+	return nil
+}
+
+// refreshSimpleGraph recalculates and stores centrality values of the passed
+// simplified graph.
+func (bc *BetweennessCentrality) refreshSimpleGraph(cache *SimpleGraph) {
 	var wg sync.WaitGroup
 	work := make(chan int)
 	partials := make(chan []float64, bc.workers)
@@ -240,8 +248,6 @@ func (bc *BetweennessCentrality) Refresh(graph ChannelGraph) error {
 		// Divide by two as this is an undirected graph.
 		bc.centrality[cache.Nodes[u]] = value / 2.0
 	}
-
-	return nil
 }
 
 // GetMetric returns the current centrality values for each node indexed
@@ -0,0 +1,402 @@
+package autopilot
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/graph"
+	"github.com/lightningnetwork/lnd/ticker"
+)
+
+// DefaultCentralityRefreshInterval is the default interval at which the
+// centrality service checks whether the graph changed and its centrality
+// values need to be recomputed.
+const DefaultCentralityRefreshInterval = 10 * time.Minute
+
+// ErrCentralityServiceExiting is returned when the centrality service is
+// shutting down.
+var ErrCentralityServiceExiting = errors.New("centrality service exiting")
+
+// CentralityPosition describes the position of a node within the graph when
+// ranking all nodes by their betweenness centrality.
+type CentralityPosition struct {
+	// Rank is the 1-based rank of the node, where the most central node
+	// has rank 1. It is zero if the node isn't part of the graph.
+	Rank int
+
+	// NumNodes is the total number of nodes in the graph.
+	NumNodes int
+
+	// Centrality is the betweenness centrality of the node.
+	Centrality float64
+
+	// NormalizedCentrality is the betweenness centrality of the node,
+	// normalized to the range [0, 1].
+	NormalizedCentrality float64
+}
+
+// CentralityServiceConfig houses the values and methods that are passed to
+// the CentralityService.
+type CentralityServiceConfig struct {
+	// Self is the node ID of our own node.
+	Self NodeID
+
+	// Graph is the channel graph that is used to load the initial graph
+	// before changes are applied incrementally.
+	Graph ChannelGraph
+
+	// SubscribeTopology is used to get a subscription for topology changes
+	// on the network.
+	SubscribeTopology func() (*graph.TopologyClient, error)
+
+	// Workers is the number of goroutines used to compute the centrality.
+	Workers int
+
+	// RefreshTicker signals when the service should check whether the
+	// graph changed since the centrality values were last computed.
+	RefreshTicker ticker.Ticker
+}
+
+// CentralityService maintains the betweenness centrality of all nodes in the
+// graph. Instead of loading the graph from the database and computing the
+// centrality on every request, the service keeps an in-memory copy of the
+// graph's structure up to date with the topology changes and only recomputes
+// the centrality values if the structure changed. As this is expensive for
+// large graphs, nothing is computed until the values are first requested.
+type CentralityService struct {
+	started  sync.Once
+	stopped  sync.Once
+	activate sync.Once
+
+	cfg *CentralityServiceConfig
+
+	// nodes and edges hold the structure of the graph. Policy updates
+	// aren't tracked as they don't affect the centrality. They're only
+	// accessed by the main goroutine.
+	nodes map[NodeID]struct{}
+	edges map[uint64][2]NodeID
+
+	// dirty is set if the structure of the graph changed since the
+	// centrality values were last computed.
+	dirty bool
+
+	// activated is closed once the centrality values are first requested.
+	activated chan struct{}
+
+	// ready is closed once the centrality values have been computed for
+	// the first time, or the service failed to do so.
+	ready chan struct{}
+
+	// metric, self and err are the results of the last computation.
+	metric *BetweennessCentrality
+	self   CentralityPosition
+	err    error
+	mtx    sync.RWMutex
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewCentralityService creates a new CentralityService from the passed
+// config.
+func NewCentralityService(cfg *CentralityServiceConfig) (*CentralityService,
+	error) {
+
+	if cfg.Workers < 1 {
+		return nil, errors.New("workers must be positive")
+	}
+
+	return &CentralityService{
+		cfg:       cfg,
+		nodes:     make(map[NodeID]struct{}),
+		edges:     make(map[uint64][2]NodeID),
+		activated: make(chan struct{}),
+		ready:     make(chan struct{}),
+		quit:      make(chan struct{}),
+	}, nil
+}
+
+// Start starts the CentralityService.
+func (s *CentralityService) Start() error {
+	s.started.Do(func() {
+		log.Debugf("Centrality service starting")
+
+		s.wg.Add(1)
+		go s.centralityHandler()
+	})
+
+	return nil
+}
+
+// Stop stops the CentralityService.
+func (s *CentralityService) Stop() error {
+	s.stopped.Do(func() {
+		log.Debugf("Centrality service shutting down")
+
+		close(s.quit)
+		s.wg.Wait()
+	})
+
+	return nil
+}
+
+// BetweennessCentrality returns the betweenness centrality of all nodes in
+// the graph, optionally normalized to the range [0, 1]. The first call blocks
+// until the centrality values have been computed.
+func (s *CentralityService) BetweennessCentrality(ctx context.Context,
+	normalize bool) (map[NodeID]float64, error) {
+
+	if err := s.waitReady(ctx); err != nil {
+		return nil, err
+	}
+
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return s.metric.GetMetric(normalize), nil
+}
+
+// SelfPosition returns the position of our own node within the graph when
+// ranking all nodes by their betweenness centrality. The first call blocks
+// until the centrality values have been computed.
+func (s *CentralityService) SelfPosition(
+	ctx context.Context) (*CentralityPosition, error) {
+
+	if err := s.waitReady(ctx); err != nil {
+		return nil, err
+	}
+
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	self := s.self
+
+	return &self, nil
+}
+
+// waitReady activates the service if it isn't active yet and waits until the
+// centrality values have been computed for the first time.
+func (s *CentralityService) waitReady(ctx context.Context) error {
+	s.activate.Do(func() {
+		close(s.activated)
+	})
+
+	select {
+	case <-s.ready:
+		return nil
+
+	case <-ctx.Done():
+		return ctx.Err()
+
+	case <-s.quit:
+		return ErrCentralityServiceExiting
+	}
+}
+
+// centralityHandler is the main goroutine of the service. Once activated, it
+// applies topology changes to the in-memory graph and recomputes the
+// centrality values if the structure of the graph changed.
+//
+// NOTE: This MUST be run as a goroutine.
+func (s *CentralityService) centralityHandler() {
+	defer s.wg.Done()
+
+	select {
+	case <-s.activated:
+	case <-s.quit:
+		return
+	}
+
+	// We subscribe to topology changes before loading the graph, so we
+	// don't miss any changes. Changes that are already part of the loaded
+	// graph are simply ignored.
+	client, err := s.cfg.SubscribeTopology()
+	if err != nil {
+		s.fail(err)
+		return
+	}
+	defer client.Cancel()
+
+	if err := s.loadGraph(); err != nil {
+		s.fail(err)
+		return
+	}
+
+	s.refresh()
+	close(s.ready)
+
+	s.cfg.RefreshTicker.Resume()
+	defer s.cfg.RefreshTicker.Stop()
+
+	for {
+		select {
+		case change, ok := <-client.TopologyChanges:
+			if !ok {
+				return
+			}
+
+			s.applyTopologyChange(change)
+
+		case <-s.cfg.RefreshTicker.Ticks():
+			if s.dirty {
+				s.refresh()
+			}
+
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// fail records the error that prevented the service from computing the
+// centrality values and unblocks all waiting callers.
+func (s *CentralityService) fail(err error) {
+	log.Errorf("Unable to compute centrality: %v", err)
+
+	s.mtx.Lock()
+	s.err = err
+	s.mtx.Unlock()
+
+	close(s.ready)
+}
+
+// loadGraph loads the structure of the graph from the channel graph.
+func (s *CentralityService) loadGraph() error {
+	return s.cfg.Graph.ForEachNode(func(node Node) error {
+		u := NodeID(node.PubKey())
+		s.nodes[u] = struct{}{}
+
+		return node.ForEachChannel(func(edge ChannelEdge) error {
+			v := NodeID(edge.Peer.PubKey())
+			s.nodes[v] = struct{}{}
+			s.edges[edge.ChanID.ToUint64()] = [2]NodeID{u, v}
+
+			return nil
+		})
+	})
+}
+
+// applyTopologyChange applies the passed topology change to the in-memory
+// graph and marks it dirty if its structure changed.
+func (s *CentralityService) applyTopologyChange(change *graph.TopologyChange) {
+	for _, update := range change.NodeUpdates {
+		node := NewNodeID(update.IdentityKey)
+		if _, ok := s.nodes[node]; !ok {
+			s.nodes[node] = struct{}{}
+			s.dirty = true
+		}
+	}
+
+	for _, update := range change.ChannelEdgeUpdates {
+		// Updates for known channels only change their policy.
+		if _, ok := s.edges[update.ChanID]; ok {
+			continue
+		}
+
+		if update.AdvertisingNode == nil ||
+			update.ConnectingNode == nil {
+
+			continue
+		}
+
+		u := NewNodeID(update.AdvertisingNode)
+		v := NewNodeID(update.ConnectingNode)
+		s.nodes[u] = struct{}{}
+		s.nodes[v] = struct{}{}
+		s.edges[update.ChanID] = [2]NodeID{u, v}
+		s.dirty = true
+	}
+
+	for _, closed := range change.ClosedChannels {
+		if _, ok := s.edges[closed.ChanID]; ok {
+			delete(s.edges, closed.ChanID)
+			s.dirty = true
+		}
+	}
+}
+
+// simpleGraph creates a simplified graph from the in-memory graph.
+func (s *CentralityService) simpleGraph() *SimpleGraph {
+	index := make(map[NodeID]int, len(s.nodes))
+	g := &SimpleGraph{
+		Nodes: make([]NodeID, 0, len(s.nodes)),
+		Adj:   make([][]int, len(s.nodes)),
+	}
+	for node := range s.nodes {
+		index[node] = len(g.Nodes)
+		g.Nodes = append(g.Nodes, node)
+	}
+
+	for _, edge := range s.edges {
+		u, v := index[edge[0]], index[edge[1]]
+		g.Adj[u] = append(g.Adj[u], v)
+		g.Adj[v] = append(g.Adj[v], u)
+	}
+
+	return g
+}
+
+// refresh recomputes the centrality values and our own node's position.
+func (s *CentralityService) refresh() {
+	start := time.Now()
+
+	metric := &BetweennessCentrality{
+		workers: s.cfg.Workers,
+	}
+	metric.refreshSimpleGraph(s.simpleGraph())
+	s.dirty = false
+
+	self := selfPosition(
+		s.cfg.Self, metric.GetMetric(false), metric.GetMetric(true),
+	)
+
+	log.Infof("Computed betweenness centrality of %d nodes in %v, our "+
+		"node ranks %d with centrality=%v", self.NumNodes,
+		time.Since(start), self.Rank, self.NormalizedCentrality)
+
+	s.mtx.Lock()
+	s.metric = metric
+	s.self = self
+	s.mtx.Unlock()
+}
+
+// selfPosition determines the position of our own node given the centrality
+// values of all nodes.
+func selfPosition(self NodeID, centrality,
+	normalized map[NodeID]float64) CentralityPosition {
+
+	position := CentralityPosition{
+		NumNodes: len(centrality),
+	}
+
+	selfCentrality, ok := centrality[self]
+	if !ok {
+		return position
+	}
+
+	values := make([]float64, 0, len(centrality))
+	for _, value := range centrality {
+		values = append(values, value)
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(values)))
+
+	// Nodes with the same centrality share the same rank.
+	position.Rank = sort.Search(len(values), func(i int) bool {
+		return values[i] <= selfCentrality
+	}) + 1
+	position.Centrality = selfCentrality
+	position.NormalizedCentrality = normalized[self]
+
+	return position
+}
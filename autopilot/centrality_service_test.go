@@ -0,0 +1,165 @@
+package autopilot
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/graph"
+	"github.com/lightningnetwork/lnd/lntest/wait"
+	"github.com/lightningnetwork/lnd/ticker"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCentralityService tests that the centrality service computes the same
+// centrality values as a full computation, and that it keeps them up to date
+// as the structure of the graph changes.
+func TestCentralityService(t *testing.T) {
+	t.Parallel()
+
+	chanGraph, err := newDiskChanGraph(t)
+	require.NoError(t, err)
+
+	nodes := buildTestGraph(t, chanGraph, centralityTestGraph)
+	self := NewNodeID(nodes[3])
+
+	topologyChanges := make(chan *graph.TopologyChange)
+	refreshTicker := ticker.NewForce(time.Hour)
+	service, err := NewCentralityService(&CentralityServiceConfig{
+		Self:  self,
+		Graph: chanGraph,
+		SubscribeTopology: func() (*graph.TopologyClient, error) {
+			return &graph.TopologyClient{
+				TopologyChanges: topologyChanges,
+				Cancel:          func() {},
+			}, nil
+		},
+		Workers:       2,
+		RefreshTicker: refreshTicker,
+	})
+	require.NoError(t, err)
+	require.NoError(t, service.Start())
+	t.Cleanup(func() {
+		require.NoError(t, service.Stop())
+	})
+
+	ctx := context.Background()
+
+	// assertCentrality asserts that the service eventually returns the
+	// same centrality values as a full computation over the channel
+	// graph.
+	assertCentrality := func() {
+		t.Helper()
+
+		metric, err := NewBetweennessCentralityMetric(1)
+		require.NoError(t, err)
+		require.NoError(t, metric.Refresh(chanGraph))
+		expected := metric.GetMetric(false)
+
+		err = wait.NoError(func() error {
+			centrality, err := service.BetweennessCentrality(
+				ctx, false,
+			)
+			if err != nil {
+				return err
+			}
+
+			if len(centrality) != len(expected) {
+				return fmt.Errorf("expected %d nodes, got %d",
+					len(expected), len(centrality))
+			}
+
+			for node, value := range expected {
+				diff := math.Abs(centrality[node] - value)
+				if diff > 1e-9 {
+					return fmt.Errorf("expected %v, got %v",
+						value, centrality[node])
+				}
+			}
+
+			return nil
+		}, time.Second)
+		require.NoError(t, err)
+	}
+
+	// The first request should compute the centrality of the initial
+	// graph.
+	centrality, err := service.BetweennessCentrality(ctx, false)
+	require.NoError(t, err)
+	for i, expected := range testGraphCentrality {
+		require.InDelta(
+			t, expected, centrality[NewNodeID(nodes[i])], 1e-9,
+		)
+	}
+	assertCentrality()
+
+	position, err := service.SelfPosition(ctx)
+	require.NoError(t, err)
+	require.Equal(t, &CentralityPosition{
+		Rank:                 1,
+		NumNodes:             centralityTestGraph.nodes,
+		Centrality:           15,
+		NormalizedCentrality: 1,
+	}, position)
+
+	// Add a new channel that bypasses our node. The centrality shouldn't
+	// be recomputed until the next refresh.
+	edge, _, err := chanGraph.addRandChannel(
+		nodes[0], nodes[8], btcutil.SatoshiPerBitcoin,
+	)
+	require.NoError(t, err)
+
+	newChanID := edge.ChanID.ToUint64()
+	topologyChanges <- &graph.TopologyChange{
+		ChannelEdgeUpdates: []*graph.ChannelEdgeUpdate{{
+			ChanID:          newChanID,
+			AdvertisingNode: nodes[0],
+			ConnectingNode:  nodes[8],
+		}},
+	}
+
+	centrality, err = service.BetweennessCentrality(ctx, false)
+	require.NoError(t, err)
+	require.InDelta(t, testGraphCentrality[3], centrality[self], 1e-9)
+
+	refreshTicker.Force <- time.Time{}
+	assertCentrality()
+
+	// A policy update of a known channel shouldn't change anything.
+	topologyChanges <- &graph.TopologyChange{
+		ChannelEdgeUpdates: []*graph.ChannelEdgeUpdate{{
+			ChanID:          newChanID,
+			AdvertisingNode: nodes[8],
+			ConnectingNode:  nodes[0],
+			Disabled:        true,
+		}},
+	}
+	refreshTicker.Force <- time.Time{}
+	assertCentrality()
+
+	// Once the channel is closed, we should be back to the initial
+	// values.
+	topologyChanges <- &graph.TopologyChange{
+		ClosedChannels: []*graph.ClosedChanSummary{{
+			ChanID: newChanID,
+		}},
+	}
+	refreshTicker.Force <- time.Time{}
+
+	err = wait.NoError(func() error {
+		centrality, err := service.BetweennessCentrality(ctx, false)
+		if err != nil {
+			return err
+		}
+
+		if math.Abs(centrality[self]-testGraphCentrality[3]) > 1e-9 {
+			return fmt.Errorf("centrality not refreshed")
+		}
+
+		return nil
+	}, time.Second)
+	require.NoError(t, err)
+}
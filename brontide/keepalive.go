@@ -0,0 +1,57 @@
+package brontide
+
+import (
+	"net"
+	"time"
+)
+
+// TCPKeepalive holds the TCP socket options that are used to detect dead
+// connections on the transport level, independent of the pings that are
+// exchanged on the message level.
+type TCPKeepalive struct {
+	// Idle is the duration the connection needs to be idle before the
+	// first keepalive probe is sent. A zero value disables TCP
+	// keepalives.
+	Idle time.Duration
+
+	// Interval is the duration between unacknowledged keepalive probes.
+	//
+	// NOTE: This is only supported on Linux, other platforms use Idle.
+	Interval time.Duration
+
+	// Count is the number of unacknowledged keepalive probes after which
+	// the connection is considered dead.
+	//
+	// NOTE: This is only supported on Linux.
+	Count int
+
+	// UserTimeout is the maximum duration transmitted data may remain
+	// unacknowledged before the connection is considered dead
+	// (TCP_USER_TIMEOUT). A zero value uses the system default.
+	//
+	// NOTE: This is only supported on Linux.
+	UserTimeout time.Duration
+}
+
+// SetTCPKeepalive applies the given TCP socket options to the connection
+// underlying the brontide connection. Connections that aren't TCP
+// connections are left untouched.
+func (c *Conn) SetTCPKeepalive(cfg *TCPKeepalive) error {
+	tcpConn, ok := c.conn.(*net.TCPConn)
+	if !ok {
+		return nil
+	}
+
+	if cfg.Idle == 0 {
+		return tcpConn.SetKeepAlive(false)
+	}
+
+	if err := tcpConn.SetKeepAlive(true); err != nil {
+		return err
+	}
+	if err := tcpConn.SetKeepAlivePeriod(cfg.Idle); err != nil {
+		return err
+	}
+
+	return setTCPSockOpts(tcpConn, cfg)
+}
@@ -0,0 +1,40 @@
+package brontide
+
+import (
+	"net"
+
+	"golang.org/x/sys/unix"
+)
+
+// setTCPSockOpts sets the Linux specific TCP socket options of the
+// connection.
+func setTCPSockOpts(conn *net.TCPConn, cfg *TCPKeepalive) error {
+	rawConn, err := conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	var sockErr error
+	err = rawConn.Control(func(fd uintptr) {
+		setOpt := func(opt, value int) {
+			if sockErr != nil || value == 0 {
+				return
+			}
+
+			sockErr = unix.SetsockoptInt(
+				int(fd), unix.IPPROTO_TCP, opt, value,
+			)
+		}
+
+		userTimeout := int(cfg.UserTimeout.Milliseconds())
+
+		setOpt(unix.TCP_KEEPINTVL, int(cfg.Interval.Seconds()))
+		setOpt(unix.TCP_KEEPCNT, cfg.Count)
+		setOpt(unix.TCP_USER_TIMEOUT, userTimeout)
+	})
+	if err != nil {
+		return err
+	}
+
+	return sockErr
+}
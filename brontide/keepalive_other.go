@@ -0,0 +1,11 @@
+//go:build !linux
+
+package brontide
+
+import "net"
+
+// setTCPSockOpts is a no-op on platforms other than Linux, as the socket
+// options aren't portable.
+func setTCPSockOpts(_ *net.TCPConn, _ *TCPKeepalive) error {
+	return nil
+}
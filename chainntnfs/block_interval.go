@@ -0,0 +1,216 @@
+package chainntnfs
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultBlockIntervalWindow is the default number of recent block
+	// intervals the BlockIntervalTracker computes its statistics over,
+	// which corresponds to roughly one day of blocks.
+	DefaultBlockIntervalWindow = 144
+)
+
+// ErrNoBlockIntervals is returned when no block intervals have been observed
+// yet.
+var ErrNoBlockIntervals = errors.New("no block intervals observed yet")
+
+// BlockIntervalStats summarizes the intervals between recently mined blocks.
+type BlockIntervalStats struct {
+	// Samples is the number of block intervals the statistics are based
+	// on.
+	Samples int
+
+	// Mean is the mean interval between blocks.
+	Mean time.Duration
+
+	// StdDev is the standard deviation of the intervals between blocks.
+	StdDev time.Duration
+}
+
+// StdErr returns the standard error of the mean block interval, which
+// expresses how accurately the mean reflects the current block rate.
+func (s *BlockIntervalStats) StdErr() time.Duration {
+	if s.Samples == 0 {
+		return 0
+	}
+
+	return time.Duration(float64(s.StdDev) / math.Sqrt(float64(s.Samples)))
+}
+
+// BlockIntervalTracker is a small subsystem that observes the timestamps of
+// newly connected blocks and keeps statistics about the intervals between the
+// most recent ones. As block timestamps are set by miners, the individual
+// intervals are only approximate, but averaged over a window they reflect the
+// actual block rate well.
+type BlockIntervalTracker struct {
+	notifier        ChainNotifier
+	window          int
+	blockNtfnStream *BlockEpochEvent
+
+	// lastHeight and lastTimestamp describe the most recently observed
+	// block.
+	lastHeight    int32
+	lastTimestamp time.Time
+
+	// intervals is a ring buffer of the most recent block intervals, with
+	// next being the position the next interval is written to.
+	intervals []time.Duration
+	next      int
+
+	mu     sync.Mutex
+	quit   chan struct{}
+	wg     sync.WaitGroup
+	dataMu sync.RWMutex
+}
+
+// NewBlockIntervalTracker creates a new BlockIntervalTracker that computes
+// its statistics over the given number of recent block intervals. It will
+// only start observing blocks once it has been started, with the
+// ChainNotifier having to be started prior to that.
+func NewBlockIntervalTracker(chainNotifier ChainNotifier,
+	window int) *BlockIntervalTracker {
+
+	if window <= 0 {
+		window = DefaultBlockIntervalWindow
+	}
+
+	return &BlockIntervalTracker{
+		notifier:  chainNotifier,
+		window:    window,
+		intervals: make([]time.Duration, 0, window),
+		quit:      make(chan struct{}),
+	}
+}
+
+// BlockIntervalStats returns statistics about the recently observed block
+// intervals. ErrNoBlockIntervals is returned if no interval has been observed
+// yet.
+func (t *BlockIntervalTracker) BlockIntervalStats() (*BlockIntervalStats,
+	error) {
+
+	t.dataMu.RLock()
+	defer t.dataMu.RUnlock()
+
+	if len(t.intervals) == 0 {
+		return nil, ErrNoBlockIntervals
+	}
+
+	var sum float64
+	for _, interval := range t.intervals {
+		sum += float64(interval)
+	}
+	mean := sum / float64(len(t.intervals))
+
+	var squaredDiffs float64
+	for _, interval := range t.intervals {
+		diff := float64(interval) - mean
+		squaredDiffs += diff * diff
+	}
+	variance := squaredDiffs / float64(len(t.intervals))
+
+	return &BlockIntervalStats{
+		Samples: len(t.intervals),
+		Mean:    time.Duration(mean),
+		StdDev:  time.Duration(math.Sqrt(variance)),
+	}, nil
+}
+
+// addBlock records a newly connected block.
+func (t *BlockIntervalTracker) addBlock(epoch *BlockEpoch) {
+	if epoch.BlockHeader == nil {
+		return
+	}
+
+	t.dataMu.Lock()
+	defer t.dataMu.Unlock()
+
+	height, timestamp := epoch.Height, epoch.BlockHeader.Timestamp
+
+	// Only the interval to the direct predecessor of a block is
+	// meaningful. If we missed blocks or the chain was reorganized, we'll
+	// skip the interval and only remember the block.
+	if !t.lastTimestamp.IsZero() && height == t.lastHeight+1 {
+		// Block timestamps are only loosely ordered, so a block may
+		// have an earlier timestamp than its predecessor.
+		interval := timestamp.Sub(t.lastTimestamp)
+		if interval < 0 {
+			interval = 0
+		}
+
+		if len(t.intervals) < t.window {
+			t.intervals = append(t.intervals, interval)
+		} else {
+			t.intervals[t.next] = interval
+		}
+		t.next = (t.next + 1) % t.window
+	}
+
+	t.lastHeight = height
+	t.lastTimestamp = timestamp
+}
+
+// updateLoop consumes the block epoch stream and records each new block.
+//
+// MUST be run as a goroutine.
+func (t *BlockIntervalTracker) updateLoop() {
+	defer t.wg.Done()
+	for {
+		select {
+		case epoch, ok := <-t.blockNtfnStream.Epochs:
+			if !ok {
+				Log.Error("dead epoch stream in " +
+					"BlockIntervalTracker")
+
+				return
+			}
+			t.addBlock(epoch)
+
+		case <-t.quit:
+			return
+		}
+	}
+}
+
+// Start starts the BlockIntervalTracker. It is an error to start it if it is
+// already started.
+func (t *BlockIntervalTracker) Start() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.blockNtfnStream != nil {
+		return fmt.Errorf("BlockIntervalTracker is already started")
+	}
+
+	var err error
+	t.blockNtfnStream, err = t.notifier.RegisterBlockEpochNtfn(nil)
+	if err != nil {
+		return err
+	}
+
+	t.wg.Add(1)
+	go t.updateLoop()
+
+	return nil
+}
+
+// Stop stops the BlockIntervalTracker. It is an error to stop it if it has
+// not been started or if it has already been stopped.
+func (t *BlockIntervalTracker) Stop() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.blockNtfnStream == nil {
+		return fmt.Errorf("BlockIntervalTracker is not running")
+	}
+	close(t.quit)
+	t.wg.Wait()
+	t.blockNtfnStream.Cancel()
+	t.blockNtfnStream = nil
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package chainntnfs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBlockIntervalTracker tests that the block interval tracker computes its
+// statistics over the intervals between consecutive blocks within its window.
+func TestBlockIntervalTracker(t *testing.T) {
+	t.Parallel()
+
+	tracker := NewBlockIntervalTracker(nil, 3)
+
+	start := time.Unix(1_700_000_000, 0)
+	addBlock := func(height int32, timestamp time.Time) {
+		tracker.addBlock(&BlockEpoch{
+			Height: height,
+			BlockHeader: &wire.BlockHeader{
+				Timestamp: timestamp,
+			},
+		})
+	}
+
+	// A single block doesn't yield any interval.
+	addBlock(100, start)
+	_, err := tracker.BlockIntervalStats()
+	require.ErrorIs(t, err, ErrNoBlockIntervals)
+
+	addBlock(101, start.Add(10*time.Minute))
+	addBlock(102, start.Add(30*time.Minute))
+
+	stats, err := tracker.BlockIntervalStats()
+	require.NoError(t, err)
+	require.Equal(t, 2, stats.Samples)
+	require.Equal(t, 15*time.Minute, stats.Mean)
+	require.Equal(t, 5*time.Minute, stats.StdDev)
+
+	// A gap in the heights doesn't count as a single interval.
+	addBlock(110, start.Add(2*time.Hour))
+	stats, err = tracker.BlockIntervalStats()
+	require.NoError(t, err)
+	require.Equal(t, 2, stats.Samples)
+
+	// A block with an earlier timestamp than its predecessor counts as an
+	// interval of zero, and only the most recent intervals are kept.
+	addBlock(111, start.Add(2*time.Hour-time.Minute))
+	addBlock(112, start.Add(2*time.Hour+5*time.Minute))
+	addBlock(113, start.Add(2*time.Hour+10*time.Minute))
+	addBlock(114, start.Add(2*time.Hour+20*time.Minute))
+
+	stats, err = tracker.BlockIntervalStats()
+	require.NoError(t, err)
+	require.Equal(t, 3, stats.Samples)
+	require.Equal(t, 7*time.Minute, stats.Mean)
+}
@@ -0,0 +1,477 @@
+package electrumnotify
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/queue"
+)
+
+const (
+	// protocolVersion is the version of the Electrum protocol that is
+	// negotiated with the server.
+	protocolVersion = "1.4"
+
+	// clientName is the name the client identifies itself with.
+	clientName = "lnd"
+
+	// blockHeaderSize is the size of a serialized block header.
+	blockHeaderSize = 80
+)
+
+var (
+	// errClientClosed is returned for requests that are pending or made
+	// once the connection to the server is closed.
+	errClientClosed = errors.New("electrum client closed")
+
+	// errRequestTimeout is returned if the server doesn't answer a
+	// request in time.
+	errRequestTimeout = errors.New("electrum request timed out")
+)
+
+// rpcError is an error returned by the Electrum server.
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error returns the error message of the server.
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("electrum error %d: %s", e.Code, e.Message)
+}
+
+// rpcRequest is a JSON-RPC request sent to the server.
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+// rpcMessage is a message received from the server. It's either the
+// response to a request, identified by its ID, or a notification of a
+// subscription, identified by its method.
+type rpcMessage struct {
+	ID     *uint64         `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+// notification is a subscription notification received from the server.
+type notification struct {
+	method string
+	params json.RawMessage
+}
+
+// headerResult is a block header together with its height, as returned by
+// the header subscription.
+type headerResult struct {
+	Height int32  `json:"height"`
+	Hex    string `json:"hex"`
+}
+
+// historyEntry is a transaction that touches a script hash. A height of zero
+// or below indicates that the transaction is unconfirmed.
+type historyEntry struct {
+	TxHash string `json:"tx_hash"`
+	Height int32  `json:"height"`
+}
+
+// merkleResult is the merkle branch proving the inclusion of a transaction in
+// a block.
+type merkleResult struct {
+	BlockHeight int32    `json:"block_height"`
+	Merkle      []string `json:"merkle"`
+	Pos         uint32   `json:"pos"`
+}
+
+// client is a minimal client of the Electrum protocol. Responses and
+// notifications are read from the connection in a separate goroutine, so
+// requests may be made concurrently.
+type client struct {
+	conn           net.Conn
+	requestTimeout time.Duration
+
+	// writeMtx serializes the requests written to the connection.
+	writeMtx sync.Mutex
+
+	// mtx guards the fields below.
+	mtx     sync.Mutex
+	nextID  uint64
+	pending map[uint64]chan *rpcMessage
+	err     error
+
+	// notifications queues the subscription notifications received from
+	// the server, such that reading responses is never blocked.
+	notifications *queue.ConcurrentQueue
+
+	// done is closed once the connection is closed.
+	done chan struct{}
+
+	// quit aborts pending requests when the notifier shuts down.
+	quit <-chan struct{}
+
+	wg sync.WaitGroup
+}
+
+// newClient creates a new client for the given connection and starts reading
+// from it. Pending requests are aborted once the quit channel is closed.
+func newClient(conn net.Conn, requestTimeout time.Duration,
+	quit <-chan struct{}) *client {
+
+	c := &client{
+		conn:           conn,
+		requestTimeout: requestTimeout,
+		pending:        make(map[uint64]chan *rpcMessage),
+		notifications:  queue.NewConcurrentQueue(20),
+		done:           make(chan struct{}),
+		quit:           quit,
+	}
+	c.notifications.Start()
+
+	c.wg.Add(1)
+	go c.readLoop()
+
+	return c
+}
+
+// readLoop reads the messages sent by the server until the connection is
+// closed.
+//
+// NOTE: Must be run as a goroutine.
+func (c *client) readLoop() {
+	defer c.wg.Done()
+
+	decoder := json.NewDecoder(c.conn)
+	for {
+		var msg rpcMessage
+		if err := decoder.Decode(&msg); err != nil {
+			c.shutdown(fmt.Errorf("%w: %v", errClientClosed, err))
+			return
+		}
+
+		// Messages without an ID are notifications of a
+		// subscription.
+		if msg.ID == nil {
+			select {
+			case c.notifications.ChanIn() <- &notification{
+				method: msg.Method,
+				params: msg.Params,
+			}:
+			case <-c.done:
+				return
+			}
+
+			continue
+		}
+
+		c.mtx.Lock()
+		respChan, ok := c.pending[*msg.ID]
+		delete(c.pending, *msg.ID)
+		c.mtx.Unlock()
+
+		if !ok {
+			chainntnfs.Log.Debugf("Received response for unknown "+
+				"request %d", *msg.ID)
+
+			continue
+		}
+
+		respChan <- &msg
+	}
+}
+
+// shutdown closes the connection and fails all pending requests with the
+// given error.
+func (c *client) shutdown(err error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.err != nil {
+		return
+	}
+
+	c.err = err
+	c.conn.Close()
+	close(c.done)
+}
+
+// close closes the connection to the server and waits for the client to
+// exit.
+func (c *client) close() {
+	c.shutdown(errClientClosed)
+	c.wg.Wait()
+	c.notifications.Stop()
+}
+
+// call sends a request to the server and decodes its result into the given
+// value.
+func (c *client) call(method string, result interface{},
+	params ...interface{}) error {
+
+	if params == nil {
+		params = []interface{}{}
+	}
+
+	respChan := make(chan *rpcMessage, 1)
+
+	c.mtx.Lock()
+	if c.err != nil {
+		err := c.err
+		c.mtx.Unlock()
+
+		return err
+	}
+	id := c.nextID
+	c.nextID++
+	c.pending[id] = respChan
+	c.mtx.Unlock()
+
+	req, err := json.Marshal(&rpcRequest{
+		JSONRPC: "2.0",
+		ID:      id,
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		c.removePending(id)
+		return err
+	}
+
+	c.writeMtx.Lock()
+	_ = c.conn.SetWriteDeadline(time.Now().Add(c.requestTimeout))
+	_, err = c.conn.Write(append(req, '\n'))
+	c.writeMtx.Unlock()
+	if err != nil {
+		c.shutdown(fmt.Errorf("%w: %v", errClientClosed, err))
+		return err
+	}
+
+	var resp *rpcMessage
+	select {
+	case resp = <-respChan:
+	case <-time.After(c.requestTimeout):
+		c.removePending(id)
+		return fmt.Errorf("%w: %v", errRequestTimeout, method)
+
+	case <-c.done:
+		return errClientClosed
+
+	case <-c.quit:
+		c.removePending(id)
+		return errClientClosed
+	}
+
+	if resp.Error != nil {
+		return resp.Error
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(resp.Result, result); err != nil {
+		return fmt.Errorf("unable to decode result of %v: %w", method,
+			err)
+	}
+
+	return nil
+}
+
+// removePending removes the response channel of a request that is no longer
+// waited for.
+func (c *client) removePending(id uint64) {
+	c.mtx.Lock()
+	delete(c.pending, id)
+	c.mtx.Unlock()
+}
+
+// serverVersion negotiates the protocol version with the server.
+func (c *client) serverVersion() error {
+	var version []string
+	err := c.call(
+		"server.version", &version, clientName, protocolVersion,
+	)
+	if err != nil {
+		return err
+	}
+
+	chainntnfs.Log.Debugf("Connected to Electrum server %v", version)
+
+	return nil
+}
+
+// subscribeHeaders subscribes to new block headers and returns the current
+// tip of the server's chain.
+func (c *client) subscribeHeaders() (int32, *wire.BlockHeader, error) {
+	var tip headerResult
+	err := c.call("blockchain.headers.subscribe", &tip)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	header, err := decodeHeader(tip.Hex)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return tip.Height, header, nil
+}
+
+// blockHeader returns the block header at the given height.
+func (c *client) blockHeader(height int32) (*wire.BlockHeader, error) {
+	var headerHex string
+	err := c.call("blockchain.block.header", &headerHex, height)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeHeader(headerHex)
+}
+
+// subscribeScriptHash subscribes to changes of the history of the given
+// script hash.
+func (c *client) subscribeScriptHash(scriptHash string) error {
+	var status *string
+	return c.call("blockchain.scripthash.subscribe", &status, scriptHash)
+}
+
+// unsubscribeScriptHash cancels the subscription of the given script hash.
+func (c *client) unsubscribeScriptHash(scriptHash string) error {
+	var ok bool
+	return c.call("blockchain.scripthash.unsubscribe", &ok, scriptHash)
+}
+
+// scriptHashHistory returns the confirmed and unconfirmed transactions that
+// touch the given script hash.
+func (c *client) scriptHashHistory(scriptHash string) ([]historyEntry,
+	error) {
+
+	var history []historyEntry
+	err := c.call("blockchain.scripthash.get_history", &history, scriptHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// transaction returns the transaction with the given hash.
+func (c *client) transaction(txid *chainhash.Hash) (*wire.MsgTx, error) {
+	var txHex string
+	err := c.call("blockchain.transaction.get", &txHex, txid.String(),
+		false)
+	if err != nil {
+		return nil, err
+	}
+
+	rawTx, err := hex.DecodeString(txHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transaction %v: %w", txid, err)
+	}
+
+	tx := &wire.MsgTx{}
+	if err := tx.Deserialize(bytes.NewReader(rawTx)); err != nil {
+		return nil, fmt.Errorf("invalid transaction %v: %w", txid, err)
+	}
+
+	if tx.TxHash() != *txid {
+		return nil, fmt.Errorf("server returned transaction %v "+
+			"instead of %v", tx.TxHash(), txid)
+	}
+
+	return tx, nil
+}
+
+// transactionMerkle returns the merkle branch proving the inclusion of the
+// given transaction in the block at the given height.
+func (c *client) transactionMerkle(txid *chainhash.Hash,
+	height int32) (*merkleResult, error) {
+
+	var result merkleResult
+	err := c.call("blockchain.transaction.get_merkle", &result,
+		txid.String(), height)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// decodeHeader decodes a hex encoded block header.
+func decodeHeader(headerHex string) (*wire.BlockHeader, error) {
+	rawHeader, err := hex.DecodeString(headerHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid block header: %w", err)
+	}
+
+	if len(rawHeader) != blockHeaderSize {
+		return nil, fmt.Errorf("invalid block header size %d",
+			len(rawHeader))
+	}
+
+	header := &wire.BlockHeader{}
+	err = header.Deserialize(bytes.NewReader(rawHeader))
+	if err != nil {
+		return nil, fmt.Errorf("invalid block header: %w", err)
+	}
+
+	return header, nil
+}
+
+// scriptHash returns the Electrum script hash of the given output script,
+// which is the reversed SHA256 hash of the script.
+func scriptHash(pkScript []byte) string {
+	hash := sha256.Sum256(pkScript)
+	for i, j := 0, len(hash)-1; i < j; i, j = i+1, j-1 {
+		hash[i], hash[j] = hash[j], hash[i]
+	}
+
+	return hex.EncodeToString(hash[:])
+}
+
+// verifyMerkleProof checks that the merkle branch proves the inclusion of the
+// transaction at the given position in a block with the given merkle root.
+func verifyMerkleProof(txid *chainhash.Hash, proof *merkleResult,
+	merkleRoot *chainhash.Hash) error {
+
+	hash := *txid
+	pos := proof.Pos
+	for _, branchHex := range proof.Merkle {
+		branch, err := chainhash.NewHashFromStr(branchHex)
+		if err != nil {
+			return fmt.Errorf("invalid merkle branch: %w", err)
+		}
+
+		var buf [chainhash.HashSize * 2]byte
+		if pos&1 == 1 {
+			copy(buf[:], branch[:])
+			copy(buf[chainhash.HashSize:], hash[:])
+		} else {
+			copy(buf[:], hash[:])
+			copy(buf[chainhash.HashSize:], branch[:])
+		}
+
+		hash = chainhash.DoubleHashH(buf[:])
+		pos >>= 1
+	}
+
+	if hash != *merkleRoot {
+		return fmt.Errorf("merkle proof of %v doesn't match the "+
+			"block's merkle root", txid)
+	}
+
+	return nil
+}
@@ -0,0 +1,1312 @@
+package electrumnotify
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/queue"
+)
+
+const (
+	// notifierType uniquely identifies this concrete implementation of the
+	// ChainNotifier interface.
+	notifierType = "electrum"
+
+	// DefaultRequestTimeout is the default time to wait for the Electrum
+	// server to answer a request.
+	DefaultRequestTimeout = 30 * time.Second
+
+	// minReconnectBackoff is the time to wait before the first attempt to
+	// reconnect to the server.
+	minReconnectBackoff = time.Second
+
+	// maxReconnectBackoff is the maximum time to wait between two attempts
+	// to reconnect to the server.
+	maxReconnectBackoff = time.Minute
+
+	// maxCachedHeaders is the number of block headers that are cached
+	// before the cache is cleared.
+	maxCachedHeaders = 1000
+)
+
+// Config holds the configuration of the ElectrumNotifier.
+type Config struct {
+	// Server is the host:port of the Electrum server.
+	Server string
+
+	// TLSConfig is the TLS configuration used to connect to the server. If
+	// nil, an unencrypted connection is used.
+	TLSConfig *tls.Config
+
+	// RequestTimeout is the time to wait for the server to answer a
+	// request. If zero, DefaultRequestTimeout is used.
+	RequestTimeout time.Duration
+
+	// Dial is an optional function used to connect to the server, e.g.
+	// through a proxy. If set, Server and TLSConfig are ignored.
+	Dial func() (net.Conn, error)
+}
+
+// ElectrumNotifier is a version of ChainNotifier that's backed by an Electrum
+// server. Block epochs are driven by the server's header subscription, while
+// confirmations and spends are detected by subscribing to the history of the
+// output scripts of all requests. Since the server indexes the full history of
+// each script, no historical rescan of the chain is necessary.
+//
+// NOTE: Only the headers of the blocks are fetched, so confirmation requests
+// made with WithIncludeBlock won't carry the block.
+type ElectrumNotifier struct {
+	epochClientCounter uint64 // To be used atomically.
+
+	start   sync.Once
+	active  int32 // To be used atomically.
+	stopped int32 // To be used atomically.
+
+	cfg *Config
+
+	// client is the connection to the server. It's replaced when the
+	// connection is re-established and must only be accessed by the
+	// notificationDispatcher once the notifier is started.
+	client *client
+
+	bestBlockMtx sync.RWMutex
+	bestBlock    chainntnfs.BlockEpoch
+
+	// connectedHashes are the hashes of the recently connected blocks by
+	// their height, which are used to find the fork point of a reorg.
+	connectedHashes map[int32]chainhash.Hash
+
+	chainConn *chainConn
+
+	notificationCancels  chan interface{}
+	notificationRegistry chan interface{}
+
+	txNotifier *chainntnfs.TxNotifier
+
+	blockEpochClients map[uint64]*blockEpochRegistration
+
+	// scripts are the output scripts whose history is watched, by their
+	// Electrum script hash.
+	scripts map[string]*watchedScript
+
+	// spendHintCache is a cache used to query and update the latest height
+	// hints for an outpoint. Each height hint represents the earliest
+	// height at which the outpoint could have been spent within the chain.
+	spendHintCache chainntnfs.SpendHintCache
+
+	// confirmHintCache is a cache used to query the latest height hints for
+	// a transaction. Each height hint represents the earliest height at
+	// which the transaction could have confirmed within the chain.
+	confirmHintCache chainntnfs.ConfirmHintCache
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// Ensure ElectrumNotifier implements the ChainNotifier interface at compile
+// time.
+var _ chainntnfs.ChainNotifier = (*ElectrumNotifier)(nil)
+
+// New creates a new instance of the ElectrumNotifier concrete implementation
+// of the ChainNotifier interface.
+func New(cfg *Config, spendHintCache chainntnfs.SpendHintCache,
+	confirmHintCache chainntnfs.ConfirmHintCache) *ElectrumNotifier {
+
+	n := &ElectrumNotifier{
+		cfg: cfg,
+
+		connectedHashes: make(map[int32]chainhash.Hash),
+
+		notificationCancels:  make(chan interface{}),
+		notificationRegistry: make(chan interface{}),
+
+		blockEpochClients: make(map[uint64]*blockEpochRegistration),
+
+		scripts: make(map[string]*watchedScript),
+
+		spendHintCache:   spendHintCache,
+		confirmHintCache: confirmHintCache,
+
+		quit: make(chan struct{}),
+	}
+	n.chainConn = &chainConn{
+		notifier: n,
+		headers:  make(map[chainhash.Hash]*cachedHeader),
+	}
+
+	return n
+}
+
+// Start connects to the Electrum server and subscribes to new block headers.
+func (n *ElectrumNotifier) Start() error {
+	var startErr error
+	n.start.Do(func() {
+		startErr = n.startNotifier()
+	})
+	return startErr
+}
+
+// Stop shuts down the ElectrumNotifier.
+func (n *ElectrumNotifier) Stop() error {
+	// Already shutting down?
+	if atomic.AddInt32(&n.stopped, 1) != 1 {
+		return nil
+	}
+
+	chainntnfs.Log.Info("electrum notifier shutting down...")
+	defer chainntnfs.Log.Debug("electrum notifier shutdown complete")
+
+	close(n.quit)
+	n.wg.Wait()
+
+	if n.client != nil {
+		n.client.close()
+	}
+
+	// Notify all pending clients of our shutdown by closing the related
+	// notification channels.
+	for _, epochClient := range n.blockEpochClients {
+		close(epochClient.cancelChan)
+		epochClient.wg.Wait()
+
+		close(epochClient.epochChan)
+	}
+
+	// The txNotifier is only initialized in the start method therefore we
+	// need to make sure we don't access a nil pointer here.
+	if n.txNotifier != nil {
+		n.txNotifier.TearDown()
+	}
+
+	return nil
+}
+
+// Started returns true if this instance has been started, and false otherwise.
+func (n *ElectrumNotifier) Started() bool {
+	return atomic.LoadInt32(&n.active) != 0
+}
+
+func (n *ElectrumNotifier) startNotifier() error {
+	c, height, header, err := n.connect()
+	if err != nil {
+		return err
+	}
+	n.client = c
+
+	hash := header.BlockHash()
+	n.chainConn.addHeader(header, height)
+	n.connectedHashes[height] = hash
+
+	n.bestBlock.Hash = &hash
+	n.bestBlock.Height = height
+	n.bestBlock.BlockHeader = header
+
+	n.txNotifier = chainntnfs.NewTxNotifier(
+		uint32(height), chainntnfs.ReorgSafetyLimit,
+		n.confirmHintCache, n.spendHintCache,
+	)
+
+	n.wg.Add(1)
+	go n.notificationDispatcher()
+
+	// Set the active flag now that we've completed the full
+	// startup.
+	atomic.StoreInt32(&n.active, 1)
+
+	return nil
+}
+
+// connect establishes a new connection to the server, negotiates the
+// protocol version and subscribes to new block headers. The current tip of
+// the server's chain is returned.
+func (n *ElectrumNotifier) connect() (*client, int32, *wire.BlockHeader,
+	error) {
+
+	var (
+		conn net.Conn
+		err  error
+	)
+	switch {
+	case n.cfg.Dial != nil:
+		conn, err = n.cfg.Dial()
+
+	case n.cfg.TLSConfig != nil:
+		conn, err = tls.Dial("tcp", n.cfg.Server, n.cfg.TLSConfig)
+
+	default:
+		conn, err = net.Dial("tcp", n.cfg.Server)
+	}
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("unable to connect to electrum "+
+			"server: %w", err)
+	}
+
+	requestTimeout := n.cfg.RequestTimeout
+	if requestTimeout == 0 {
+		requestTimeout = DefaultRequestTimeout
+	}
+
+	c := newClient(conn, requestTimeout, n.quit)
+	if err := c.serverVersion(); err != nil {
+		c.close()
+		return nil, 0, nil, fmt.Errorf("unable to negotiate protocol "+
+			"version: %w", err)
+	}
+
+	height, header, err := c.subscribeHeaders()
+	if err != nil {
+		c.close()
+		return nil, 0, nil, fmt.Errorf("unable to subscribe to block "+
+			"headers: %w", err)
+	}
+
+	return c, height, header, nil
+}
+
+// reconnect replaces the closed connection to the server, retrying with an
+// increasing backoff until it succeeds. Once connected, the notifier is
+// synced to the server's tip and all scripts are checked again, as
+// notifications may have been missed while disconnected.
+func (n *ElectrumNotifier) reconnect() {
+	n.client.close()
+
+	backoff := minReconnectBackoff
+	for {
+		chainntnfs.Log.Infof("Reconnecting to electrum server in %v",
+			backoff)
+
+		select {
+		case <-time.After(backoff):
+		case <-n.quit:
+			return
+		}
+
+		c, height, header, err := n.connect()
+		if err == nil {
+			n.client = c
+
+			for sh, ws := range n.scripts {
+				err := c.subscribeScriptHash(sh)
+				if err != nil {
+					chainntnfs.Log.Errorf("Unable to "+
+						"subscribe to script hash %v: "+
+						"%v", sh, err)
+				}
+				ws.dirty = true
+			}
+
+			if err := n.syncTip(height, header); err != nil {
+				chainntnfs.Log.Errorf("Unable to sync tip: %v",
+					err)
+			}
+
+			n.checkScripts()
+
+			return
+		}
+
+		chainntnfs.Log.Errorf("Unable to reconnect to electrum "+
+			"server: %v", err)
+
+		backoff *= 2
+		if backoff > maxReconnectBackoff {
+			backoff = maxReconnectBackoff
+		}
+	}
+}
+
+// notificationDispatcher is the primary goroutine which handles client
+// notification registrations, as well as notification dispatches.
+func (n *ElectrumNotifier) notificationDispatcher() {
+	defer n.wg.Done()
+
+	for {
+		select {
+		case cancelMsg := <-n.notificationCancels:
+			switch msg := cancelMsg.(type) {
+			case *epochCancel:
+				chainntnfs.Log.Infof("Cancelling epoch "+
+					"notification, epoch_id=%v",
+					msg.epochID)
+
+				// First, we'll lookup the original
+				// registration in order to stop the active
+				// queue goroutine.
+				reg := n.blockEpochClients[msg.epochID]
+				reg.epochQueue.Stop()
+
+				// Next, close the cancel channel for this
+				// specific client, and wait for the client to
+				// exit.
+				close(reg.cancelChan)
+				reg.wg.Wait()
+
+				// Once the client has exited, we can then
+				// safely close the channel used to send epoch
+				// notifications, in order to notify any
+				// listeners that the intent has been
+				// canceled.
+				close(reg.epochChan)
+				delete(n.blockEpochClients, msg.epochID)
+			}
+
+		case registerMsg := <-n.notificationRegistry:
+			switch msg := registerMsg.(type) {
+			case *watchRequest:
+				n.handleWatchRequest(msg)
+
+			case *blockEpochRegistration:
+				chainntnfs.Log.Infof("New block epoch " +
+					"subscription")
+
+				n.blockEpochClients[msg.epochID] = msg
+
+				// If the client did not provide their best
+				// known block, then we'll immediately dispatch
+				// a notification for the current tip.
+				if msg.bestBlock == nil {
+					n.notifyBlockEpochClient(
+						msg, n.bestBlock.Height,
+						n.bestBlock.Hash,
+						n.bestBlock.BlockHeader,
+					)
+
+					msg.errorChan <- nil
+					continue
+				}
+
+				// Otherwise, we'll attempt to deliver the
+				// backlog of notifications from their best
+				// known block.
+				missed, err := chainntnfs.GetClientMissedBlocks(
+					n.chainConn, msg.bestBlock,
+					n.bestBlock.Height, false,
+				)
+				if err != nil {
+					msg.errorChan <- err
+					continue
+				}
+
+				for _, block := range missed {
+					n.notifyBlockEpochClient(
+						msg, block.Height, block.Hash,
+						block.BlockHeader,
+					)
+				}
+
+				msg.errorChan <- nil
+			}
+
+		case item := <-n.client.notifications.ChanOut():
+			n.handleNotification(item.(*notification))
+
+		case <-n.client.done:
+			chainntnfs.Log.Errorf("Lost connection to electrum "+
+				"server: %v", n.client.err)
+
+			n.reconnect()
+
+		case <-n.quit:
+			return
+		}
+	}
+}
+
+// handleNotification processes a subscription notification received from
+// the server.
+func (n *ElectrumNotifier) handleNotification(ntfn *notification) {
+	switch ntfn.method {
+	case "blockchain.headers.subscribe":
+		var tips []headerResult
+		if err := json.Unmarshal(ntfn.params, &tips); err != nil {
+			chainntnfs.Log.Errorf("Invalid header notification: %v",
+				err)
+			return
+		}
+
+		for _, tip := range tips {
+			header, err := decodeHeader(tip.Hex)
+			if err != nil {
+				chainntnfs.Log.Errorf("Invalid header "+
+					"notification: %v", err)
+				return
+			}
+
+			if err := n.syncTip(tip.Height, header); err != nil {
+				chainntnfs.Log.Errorf("Unable to sync tip: %v",
+					err)
+			}
+		}
+
+	case "blockchain.scripthash.subscribe":
+		var params []json.RawMessage
+		err := json.Unmarshal(ntfn.params, &params)
+		if err != nil || len(params) == 0 {
+			chainntnfs.Log.Errorf("Invalid script hash "+
+				"notification: %v", err)
+			return
+		}
+
+		var sh string
+		if err := json.Unmarshal(params[0], &sh); err != nil {
+			chainntnfs.Log.Errorf("Invalid script hash "+
+				"notification: %v", err)
+			return
+		}
+
+		ws, ok := n.scripts[sh]
+		if !ok {
+			return
+		}
+		ws.dirty = true
+
+	default:
+		chainntnfs.Log.Debugf("Ignoring electrum notification %v",
+			ntfn.method)
+
+		return
+	}
+
+	n.checkScripts()
+}
+
+// syncTip brings the notifier to the given tip of the server's chain. Blocks
+// that were reorged out are disconnected, and all blocks up to the new tip
+// are connected.
+func (n *ElectrumNotifier) syncTip(height int32,
+	header *wire.BlockHeader) error {
+
+	hash := header.BlockHash()
+	n.chainConn.addHeader(header, height)
+
+	n.bestBlockMtx.Lock()
+	defer n.bestBlockMtx.Unlock()
+
+	if hash == *n.bestBlock.Hash {
+		return nil
+	}
+
+	// If the new tip doesn't extend our best block, our best block may
+	// have been reorged out of the chain.
+	if height <= n.bestBlock.Height ||
+		header.PrevBlock != *n.bestBlock.Hash {
+
+		if err := n.handleReorg(); err != nil {
+			return err
+		}
+	}
+
+	// The server may still be catching up to our best block after it
+	// was restarted, in which case there's nothing to connect.
+	if height <= n.bestBlock.Height {
+		return nil
+	}
+
+	if height != n.bestBlock.Height+1 {
+		chainntnfs.Log.Infof("Missed blocks, attempting to catch up")
+
+		_, missedBlocks, err := chainntnfs.HandleMissedBlocks(
+			n.chainConn, n.txNotifier, n.bestBlock, height, false,
+		)
+		if err != nil {
+			return err
+		}
+
+		for _, block := range missedBlocks {
+			if err := n.connectBlock(block); err != nil {
+				return err
+			}
+		}
+	}
+
+	return n.connectBlock(chainntnfs.BlockEpoch{
+		Height:      height,
+		Hash:        &hash,
+		BlockHeader: header,
+	})
+}
+
+// handleReorg checks whether our best block is still part of the server's
+// chain and, if not, rewinds the chain to the fork point.
+//
+// NOTE: This method must be called with the bestBlockMtx lock held.
+func (n *ElectrumNotifier) handleReorg() error {
+	forkHeight := n.bestBlock.Height
+	for {
+		knownHash, ok := n.connectedHashes[forkHeight]
+		if !ok {
+			return fmt.Errorf("unable to find fork point of reorg "+
+				"below height %d", forkHeight)
+		}
+
+		hash, err := n.chainConn.GetBlockHash(int64(forkHeight))
+
+		// The server's chain may be shorter than ours after the reorg,
+		// in which case it doesn't know the height yet.
+		var rpcErr *rpcError
+		switch {
+		case errors.As(err, &rpcErr):
+
+		case err != nil:
+			return err
+
+		case *hash == knownHash:
+			if forkHeight == n.bestBlock.Height {
+				return nil
+			}
+
+			return n.rewindChain(forkHeight)
+		}
+
+		forkHeight--
+	}
+}
+
+// rewindChain disconnects all blocks above the given height. All scripts are
+// checked again afterwards, as the confirmations and spends found in the
+// disconnected blocks may be part of the new chain at a different height.
+//
+// NOTE: This method must be called with the bestBlockMtx lock held.
+func (n *ElectrumNotifier) rewindChain(forkHeight int32) error {
+	chainntnfs.Log.Infof("Block %v at height %d was reorged out of the "+
+		"chain, rewinding to height %d", n.bestBlock.Hash,
+		n.bestBlock.Height, forkHeight)
+
+	newBestBlock, err := chainntnfs.RewindChain(
+		n.chainConn, n.txNotifier, n.bestBlock, forkHeight,
+	)
+
+	// The chain may have been rewound partially, so we'll update our best
+	// block even if an error occurred.
+	for h := newBestBlock.Height + 1; h <= n.bestBlock.Height; h++ {
+		delete(n.connectedHashes, h)
+	}
+	n.bestBlock = newBestBlock
+
+	for _, ws := range n.scripts {
+		ws.rewind(uint32(newBestBlock.Height))
+	}
+
+	return err
+}
+
+// connectBlock connects the given block to our best block and dispatches
+// the notifications it satisfies.
+//
+// NOTE: This method must be called with the bestBlockMtx lock held.
+func (n *ElectrumNotifier) connectBlock(block chainntnfs.BlockEpoch) error {
+	// Only the block header is known, the relevant transactions of the
+	// block are found through the history of the watched scripts instead.
+	err := n.txNotifier.ConnectTip(nil, uint32(block.Height))
+	if err != nil {
+		return fmt.Errorf("unable to connect tip: %w", err)
+	}
+
+	chainntnfs.Log.Infof("New block: height=%v, sha=%v", block.Height,
+		block.Hash)
+
+	n.bestBlock = block
+	n.connectedHashes[block.Height] = *block.Hash
+	delete(
+		n.connectedHashes,
+		block.Height-int32(chainntnfs.ReorgSafetyLimit),
+	)
+
+	// Scripts that had transactions in the mempool or above our previous
+	// best block need to be checked again.
+	for _, ws := range n.scripts {
+		if ws.awaitingTip {
+			ws.dirty = true
+		}
+	}
+
+	n.notifyBlockEpochs(block.Height, block.Hash, block.BlockHeader)
+
+	return n.txNotifier.NotifyHeight(uint32(block.Height))
+}
+
+// notifyBlockEpochs notifies all registered block epoch clients of the newly
+// connected block to the main chain.
+func (n *ElectrumNotifier) notifyBlockEpochs(newHeight int32,
+	newSha *chainhash.Hash, blockHeader *wire.BlockHeader) {
+
+	for _, client := range n.blockEpochClients {
+		n.notifyBlockEpochClient(client, newHeight, newSha, blockHeader)
+	}
+}
+
+// notifyBlockEpochClient sends a registered block epoch client a notification
+// about a specific block.
+func (n *ElectrumNotifier) notifyBlockEpochClient(
+	epochClient *blockEpochRegistration, height int32,
+	sha *chainhash.Hash, blockHeader *wire.BlockHeader) {
+
+	epoch := &chainntnfs.BlockEpoch{
+		Height:      height,
+		Hash:        sha,
+		BlockHeader: blockHeader,
+	}
+
+	select {
+	case epochClient.epochQueue.ChanIn() <- epoch:
+	case <-epochClient.cancelChan:
+	case <-n.quit:
+	}
+}
+
+// confWatch is the state of a confirmation request of a watched script.
+type confWatch struct {
+	// rescanned is true once the history of the script was checked for
+	// the request for the first time.
+	rescanned bool
+
+	// confirmedAt is the height at which the request was confirmed, or
+	// zero if it's unconfirmed.
+	confirmedAt uint32
+}
+
+// spendWatch is the state of a spend request of a watched script.
+type spendWatch struct {
+	// rescanned is true once the history of the script was checked for
+	// the request for the first time.
+	rescanned bool
+
+	// spentAt is the height at which the request was spent, or zero if
+	// it's unspent.
+	spentAt uint32
+}
+
+// watchedScript is an output script whose history is subscribed to, together
+// with the confirmation and spend requests that involve it.
+type watchedScript struct {
+	confs  map[chainntnfs.ConfRequest]*confWatch
+	spends map[chainntnfs.SpendRequest]*spendWatch
+
+	// dirty is true if the history of the script needs to be checked.
+	dirty bool
+
+	// awaitingTip is true if the history of the script contained
+	// transactions in the mempool or above our best block when it was last
+	// checked, so it needs to be checked again once a new block is
+	// connected.
+	awaitingTip bool
+}
+
+// rewind resets the confirmations and spends of the script above the given
+// height and marks it to be checked again.
+func (w *watchedScript) rewind(height uint32) {
+	for _, conf := range w.confs {
+		if conf.confirmedAt > height {
+			conf.confirmedAt = 0
+		}
+	}
+	for _, spend := range w.spends {
+		if spend.spentAt > height {
+			spend.spentAt = 0
+		}
+	}
+
+	w.dirty = true
+}
+
+// watchRequest is a request sent to the notificationDispatcher to watch the
+// history of an output script for a confirmation or spend request.
+type watchRequest struct {
+	scriptHash string
+	conf       *chainntnfs.ConfRequest
+	spend      *chainntnfs.SpendRequest
+}
+
+// handleWatchRequest subscribes to the history of the requested script and
+// checks whether the request was already satisfied.
+func (n *ElectrumNotifier) handleWatchRequest(req *watchRequest) {
+	ws, ok := n.scripts[req.scriptHash]
+	if !ok {
+		ws = &watchedScript{
+			confs:  make(map[chainntnfs.ConfRequest]*confWatch),
+			spends: make(map[chainntnfs.SpendRequest]*spendWatch),
+		}
+		n.scripts[req.scriptHash] = ws
+
+		// If the subscription fails because we lost the connection,
+		// the script will be subscribed to once we've reconnected.
+		err := n.client.subscribeScriptHash(req.scriptHash)
+		if err != nil {
+			chainntnfs.Log.Errorf("Unable to subscribe to script "+
+				"hash %v: %v", req.scriptHash, err)
+		}
+	}
+
+	if req.conf != nil {
+		if _, ok := ws.confs[*req.conf]; !ok {
+			ws.confs[*req.conf] = &confWatch{}
+		}
+	}
+	if req.spend != nil {
+		if _, ok := ws.spends[*req.spend]; !ok {
+			ws.spends[*req.spend] = &spendWatch{}
+		}
+	}
+	ws.dirty = true
+
+	n.checkScripts()
+}
+
+// checkScripts checks the history of all scripts that changed and prunes the
+// requests that can no longer be reorged out of the chain.
+func (n *ElectrumNotifier) checkScripts() {
+	bestHeight := uint32(n.bestBlock.Height)
+
+	for sh, ws := range n.scripts {
+		if ws.dirty {
+			if err := n.checkScript(sh, ws); err != nil {
+				chainntnfs.Log.Errorf("Unable to check "+
+					"history of script hash %v: %v", sh,
+					err)
+			}
+		}
+
+		for req, conf := range ws.confs {
+			if conf.confirmedAt != 0 && conf.confirmedAt+
+				chainntnfs.ReorgSafetyLimit <= bestHeight {
+
+				delete(ws.confs, req)
+			}
+		}
+		for req, spend := range ws.spends {
+			if spend.spentAt != 0 && spend.spentAt+
+				chainntnfs.ReorgSafetyLimit <= bestHeight {
+
+				delete(ws.spends, req)
+			}
+		}
+
+		if len(ws.confs) != 0 || len(ws.spends) != 0 {
+			continue
+		}
+
+		delete(n.scripts, sh)
+		if err := n.client.unsubscribeScriptHash(sh); err != nil {
+			chainntnfs.Log.Debugf("Unable to unsubscribe from "+
+				"script hash %v: %v", sh, err)
+		}
+	}
+}
+
+// checkScript fetches the history of the script and hands the confirmations
+// and spends it contains to the TxNotifier.
+func (n *ElectrumNotifier) checkScript(sh string, ws *watchedScript) error {
+	history, err := n.client.scriptHashHistory(sh)
+	if err != nil {
+		return err
+	}
+
+	ws.dirty = false
+	ws.awaitingTip = false
+
+	bestHeight := n.bestBlock.Height
+
+	// Only the transactions that are confirmed within our view of the
+	// chain are considered. The others are looked at again once they are.
+	var confirmed []historyEntry
+	for _, entry := range history {
+		if entry.Height <= 0 || entry.Height > bestHeight {
+			ws.awaitingTip = true
+			continue
+		}
+
+		confirmed = append(confirmed, entry)
+	}
+
+	txns := make(map[chainhash.Hash]*wire.MsgTx)
+	fetchTx := func(txid *chainhash.Hash) (*wire.MsgTx, error) {
+		if tx, ok := txns[*txid]; ok {
+			return tx, nil
+		}
+
+		tx, err := n.client.transaction(txid)
+		if err != nil {
+			return nil, err
+		}
+		txns[*txid] = tx
+
+		return tx, nil
+	}
+
+	for req, conf := range ws.confs {
+		if conf.confirmedAt != 0 {
+			continue
+		}
+
+		details, err := n.findConf(req, confirmed, fetchTx)
+		if err != nil {
+			ws.dirty = true
+			return err
+		}
+
+		// We only need to report that the request is unconfirmed once,
+		// such that the TxNotifier starts updating its height hint.
+		if details == nil && conf.rescanned {
+			continue
+		}
+		conf.rescanned = true
+
+		err = n.txNotifier.UpdateConfDetails(req, details)
+		switch {
+		// The request was canceled or pruned by the TxNotifier, so we
+		// no longer need to watch it.
+		case err != nil && strings.Contains(err.Error(), "not found"):
+			delete(ws.confs, req)
+
+		case err != nil:
+			return err
+
+		case details != nil:
+			conf.confirmedAt = details.BlockHeight
+		}
+	}
+
+	for req, spend := range ws.spends {
+		if spend.spentAt != 0 {
+			continue
+		}
+
+		details, err := n.findSpend(req, confirmed, fetchTx)
+		if err != nil {
+			ws.dirty = true
+			return err
+		}
+
+		// We only need to report that the request is unspent once,
+		// such that the TxNotifier starts updating its height hint.
+		if details == nil && spend.rescanned {
+			continue
+		}
+		spend.rescanned = true
+
+		err = n.txNotifier.UpdateSpendDetails(req, details)
+		switch {
+		// The request was canceled or pruned by the TxNotifier, so we
+		// no longer need to watch it.
+		case err != nil && strings.Contains(err.Error(), "not found"):
+			delete(ws.spends, req)
+
+		case err != nil:
+			return err
+
+		case details != nil:
+			spend.spentAt = uint32(details.SpendingHeight)
+		}
+	}
+
+	return nil
+}
+
+// findConf returns the details of the earliest confirmation of the request
+// within the given history, or nil if it isn't confirmed. The inclusion of the
+// transaction is verified against the merkle root of its block.
+func (n *ElectrumNotifier) findConf(req chainntnfs.ConfRequest,
+	history []historyEntry,
+	fetchTx func(*chainhash.Hash) (*wire.MsgTx, error)) (
+	*chainntnfs.TxConfirmation, error) {
+
+	for _, entry := range history {
+		txid, err := chainhash.NewHashFromStr(entry.TxHash)
+		if err != nil {
+			return nil, fmt.Errorf("invalid history entry: %w", err)
+		}
+
+		if req.TxID != chainntnfs.ZeroHash && *txid != req.TxID {
+			continue
+		}
+
+		tx, err := fetchTx(txid)
+		if err != nil {
+			return nil, err
+		}
+
+		if !req.MatchesTx(tx) {
+			continue
+		}
+
+		blockHash, txIndex, err := n.verifyInclusion(
+			txid, entry.Height,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return &chainntnfs.TxConfirmation{
+			BlockHash:   blockHash,
+			BlockHeight: uint32(entry.Height),
+			TxIndex:     txIndex,
+			Tx:          tx,
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// findSpend returns the details of the earliest spend of the request within
+// the given history, or nil if it isn't spent.
+func (n *ElectrumNotifier) findSpend(req chainntnfs.SpendRequest,
+	history []historyEntry,
+	fetchTx func(*chainhash.Hash) (*wire.MsgTx, error)) (
+	*chainntnfs.SpendDetail, error) {
+
+	for _, entry := range history {
+		txid, err := chainhash.NewHashFromStr(entry.TxHash)
+		if err != nil {
+			return nil, fmt.Errorf("invalid history entry: %w", err)
+		}
+
+		tx, err := fetchTx(txid)
+		if err != nil {
+			return nil, err
+		}
+
+		matches, inputIndex, err := req.MatchesTx(tx)
+		if err != nil {
+			return nil, err
+		}
+		if !matches {
+			continue
+		}
+
+		_, _, err = n.verifyInclusion(txid, entry.Height)
+		if err != nil {
+			return nil, err
+		}
+
+		spentOutPoint := tx.TxIn[inputIndex].PreviousOutPoint
+
+		return &chainntnfs.SpendDetail{
+			SpentOutPoint:     &spentOutPoint,
+			SpenderTxHash:     txid,
+			SpendingTx:        tx,
+			SpenderInputIndex: inputIndex,
+			SpendingHeight:    entry.Height,
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// verifyInclusion verifies the merkle proof of the transaction's inclusion in
+// the block at the given height and returns the hash of the block and the
+// index of the transaction within it.
+func (n *ElectrumNotifier) verifyInclusion(txid *chainhash.Hash,
+	height int32) (*chainhash.Hash, uint32, error) {
+
+	proof, err := n.client.transactionMerkle(txid, height)
+	if err != nil {
+		return nil, 0, fmt.Errorf("unable to get merkle proof of "+
+			"%v: %w", txid, err)
+	}
+
+	blockHash, err := n.chainConn.GetBlockHash(int64(height))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	header, err := n.chainConn.GetBlockHeader(blockHash)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = verifyMerkleProof(txid, proof, &header.MerkleRoot)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return blockHash, proof.Pos, nil
+}
+
+// RegisterSpendNtfn registers an intent to be notified once the target
+// outpoint/output script has been spent by a transaction on-chain. When
+// intending to be notified of the spend of an output script, a nil outpoint
+// must be used. The heightHint should represent the earliest height in the
+// chain of the transaction that spent the outpoint/output script.
+//
+// Once a spend of has been detected, the details of the spending event will be
+// sent across the 'Spend' channel.
+func (n *ElectrumNotifier) RegisterSpendNtfn(outpoint *wire.OutPoint,
+	pkScript []byte, heightHint uint32) (*chainntnfs.SpendEvent, error) {
+
+	// Register the spend notification with the TxNotifier. The history of
+	// the script is checked as soon as it is watched, which replaces the
+	// historical rescan of the other backends.
+	ntfn, err := n.txNotifier.RegisterSpend(outpoint, pkScript, heightHint)
+	if err != nil {
+		return nil, err
+	}
+
+	spendRequest, err := chainntnfs.NewSpendRequest(outpoint, pkScript)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case n.notificationRegistry <- &watchRequest{
+		scriptHash: scriptHash(pkScript),
+		spend:      &spendRequest,
+	}:
+	case <-n.quit:
+		return nil, chainntnfs.ErrChainNotifierShuttingDown
+	}
+
+	return ntfn.Event, nil
+}
+
+// RegisterConfirmationsNtfn registers an intent to be notified once the target
+// txid/output script has reached numConfs confirmations on-chain. When
+// intending to be notified of the confirmation of an output script, a nil txid
+// must be used. The heightHint should represent the earliest height at which
+// the txid/output script could have been included in the chain.
+//
+// Progress on the number of confirmations left can be read from the 'Updates'
+// channel. Once it has reached all of its confirmations, a notification will be
+// sent across the 'Confirmed' channel.
+func (n *ElectrumNotifier) RegisterConfirmationsNtfn(txid *chainhash.Hash,
+	pkScript []byte, numConfs, heightHint uint32,
+	opts ...chainntnfs.NotifierOption) (*chainntnfs.ConfirmationEvent,
+	error) {
+
+	// Register the conf notification with the TxNotifier. The history of
+	// the script is checked as soon as it is watched, which replaces the
+	// historical rescan of the other backends.
+	ntfn, err := n.txNotifier.RegisterConf(
+		txid, pkScript, numConfs, heightHint, opts...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	confRequest, err := chainntnfs.NewConfRequest(txid, pkScript)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case n.notificationRegistry <- &watchRequest{
+		scriptHash: scriptHash(pkScript),
+		conf:       &confRequest,
+	}:
+	case <-n.quit:
+		return nil, chainntnfs.ErrChainNotifierShuttingDown
+	}
+
+	return ntfn.Event, nil
+}
+
+// blockEpochRegistration represents a client's intent to receive a
+// notification with each newly connected block.
+type blockEpochRegistration struct {
+	epochID uint64
+
+	epochChan chan *chainntnfs.BlockEpoch
+
+	epochQueue *queue.ConcurrentQueue
+
+	cancelChan chan struct{}
+
+	bestBlock *chainntnfs.BlockEpoch
+
+	errorChan chan error
+
+	wg sync.WaitGroup
+}
+
+// epochCancel is a message sent to the ElectrumNotifier when a client wishes
+// to cancel an outstanding epoch notification that has yet to be dispatched.
+type epochCancel struct {
+	epochID uint64
+}
+
+// RegisterBlockEpochNtfn returns a BlockEpochEvent which subscribes the
+// caller to receive notifications, of each new block connected to the main
+// chain. Clients have the option of passing in their best known block, which
+// the notifier uses to check if they are behind on blocks and catch them up. If
+// they do not provide one, then a notification will be dispatched immediately
+// for the current tip of the chain upon a successful registration.
+func (n *ElectrumNotifier) RegisterBlockEpochNtfn(
+	bestBlock *chainntnfs.BlockEpoch) (*chainntnfs.BlockEpochEvent, error) {
+
+	reg := &blockEpochRegistration{
+		epochQueue: queue.NewConcurrentQueue(20),
+		epochChan:  make(chan *chainntnfs.BlockEpoch, 20),
+		cancelChan: make(chan struct{}),
+		epochID:    atomic.AddUint64(&n.epochClientCounter, 1),
+		bestBlock:  bestBlock,
+		errorChan:  make(chan error, 1),
+	}
+	reg.epochQueue.Start()
+
+	// Before we send the request to the main goroutine, we'll launch a new
+	// goroutine to proxy items added to our queue to the client itself.
+	// This ensures that all notifications are received *in order*.
+	reg.wg.Add(1)
+	go func() {
+		defer reg.wg.Done()
+
+		for {
+			select {
+			case ntfn := <-reg.epochQueue.ChanOut():
+				blockNtfn := ntfn.(*chainntnfs.BlockEpoch)
+				select {
+				case reg.epochChan <- blockNtfn:
+
+				case <-reg.cancelChan:
+					return
+
+				case <-n.quit:
+					return
+				}
+
+			case <-reg.cancelChan:
+				return
+
+			case <-n.quit:
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-n.quit:
+		// As we're exiting before the registration could be sent,
+		// we'll stop the queue now ourselves.
+		reg.epochQueue.Stop()
+
+		return nil, errors.New("chainntnfs: system interrupt while " +
+			"attempting to register for block epoch notification.")
+
+	case n.notificationRegistry <- reg:
+	}
+
+	select {
+	case err := <-reg.errorChan:
+		if err != nil {
+			return nil, err
+		}
+
+	case <-n.quit:
+		return nil, chainntnfs.ErrChainNotifierShuttingDown
+	}
+
+	return &chainntnfs.BlockEpochEvent{
+		Epochs: reg.epochChan,
+		Cancel: func() {
+			cancel := &epochCancel{
+				epochID: reg.epochID,
+			}
+
+			// Submit epoch cancellation to notification dispatcher.
+			select {
+			case n.notificationCancels <- cancel:
+				// Cancellation is being handled, drain the
+				// epoch channel until it is closed before
+				// yielding to caller.
+				for {
+					select {
+					case _, ok := <-reg.epochChan:
+						if !ok {
+							return
+						}
+					case <-n.quit:
+						return
+					}
+				}
+			case <-n.quit:
+			}
+		},
+	}, nil
+}
+
+// cachedHeader is a block header together with its height.
+type cachedHeader struct {
+	header *wire.BlockHeader
+	height int32
+}
+
+// chainConn implements the chainntnfs.ChainConn interface on top of the
+// Electrum server. Since blocks can only be looked up by their height, the
+// headers that were fetched are cached by their hash.
+//
+// NOTE: The chainConn must only be used by the notificationDispatcher.
+type chainConn struct {
+	notifier *ElectrumNotifier
+	headers  map[chainhash.Hash]*cachedHeader
+}
+
+// A compile-time check to ensure chainConn implements the ChainConn
+// interface.
+var _ chainntnfs.ChainConn = (*chainConn)(nil)
+
+// addHeader adds the header at the given height to the cache.
+func (c *chainConn) addHeader(header *wire.BlockHeader, height int32) {
+	if len(c.headers) >= maxCachedHeaders {
+		c.headers = make(map[chainhash.Hash]*cachedHeader)
+	}
+
+	c.headers[header.BlockHash()] = &cachedHeader{
+		header: header,
+		height: height,
+	}
+}
+
+// GetBlockHeader returns the block header for a hash.
+//
+// NOTE: This is part of the chainntnfs.ChainConn interface.
+func (c *chainConn) GetBlockHeader(
+	blockHash *chainhash.Hash) (*wire.BlockHeader, error) {
+
+	cached, ok := c.headers[*blockHash]
+	if !ok {
+		return nil, fmt.Errorf("unknown block %v", blockHash)
+	}
+
+	return cached.header, nil
+}
+
+// GetBlockHeaderVerbose returns a verbose block header result for a hash. This
+// result only contains the height with a nil hash.
+//
+// NOTE: This is part of the chainntnfs.ChainConn interface.
+func (c *chainConn) GetBlockHeaderVerbose(blockHash *chainhash.Hash) (
+	*btcjson.GetBlockHeaderVerboseResult, error) {
+
+	cached, ok := c.headers[*blockHash]
+	if !ok {
+		return nil, fmt.Errorf("unknown block %v", blockHash)
+	}
+
+	// Since only the height is used from the result, leave the hash nil.
+	return &btcjson.GetBlockHeaderVerboseResult{Height: cached.height}, nil
+}
+
+// GetBlockHash returns the hash from a block height.
+//
+// NOTE: This is part of the chainntnfs.ChainConn interface.
+func (c *chainConn) GetBlockHash(blockHeight int64) (*chainhash.Hash, error) {
+	header, err := c.notifier.client.blockHeader(int32(blockHeight))
+	if err != nil {
+		return nil, err
+	}
+	c.addHeader(header, int32(blockHeight))
+
+	hash := header.BlockHash()
+
+	return &hash, nil
+}
@@ -0,0 +1,573 @@
+package electrumnotify
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/stretchr/testify/require"
+)
+
+const testTimeout = 5 * time.Second
+
+var (
+	testScript = []byte{
+		// OP_0
+		0x00,
+		// OP_DATA_32
+		0x20,
+		// <32-byte hash>
+		0xec, 0x6f, 0x7a, 0x5a, 0xa8, 0xf2, 0xb1, 0x0c, 0xa5, 0x15,
+		0x04, 0x52, 0x3a, 0x60, 0xd4, 0x03, 0x06, 0xf6, 0x96, 0xcd,
+		0xec, 0x6f, 0x7a, 0x5a, 0xa8, 0xf2, 0xb1, 0x0c, 0xa5, 0x15,
+		0x04, 0x52,
+	}
+)
+
+func initHintCache(t *testing.T) *channeldb.HeightHintCache {
+	t.Helper()
+
+	db, err := channeldb.Open(t.TempDir())
+	require.NoError(t, err, "unable to create db")
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	testCfg := channeldb.CacheConfig{
+		QueryDisable: false,
+	}
+	hintCache, err := channeldb.NewHeightHintCache(testCfg, db.Backend)
+	require.NoError(t, err, "unable to create hint cache")
+
+	return hintCache
+}
+
+// fakeBlock is a block of the fakeServer. Besides its coinbase, a block
+// contains at most one transaction.
+type fakeBlock struct {
+	header   *wire.BlockHeader
+	coinbase *wire.MsgTx
+	tx       *wire.MsgTx
+}
+
+// fakeServer is an in-memory implementation of the subset of the Electrum
+// protocol used by the ElectrumNotifier.
+type fakeServer struct {
+	mtx sync.Mutex
+
+	blocks []*fakeBlock
+
+	// txns are all transactions known to the server by their hash,
+	// including the ones that were reorged out.
+	txns map[chainhash.Hash]*wire.MsgTx
+
+	subscribed map[string]struct{}
+	nonce      uint32
+
+	conn     net.Conn
+	writeMtx sync.Mutex
+}
+
+// newFakeServer creates a new server whose chain has the given number of
+// blocks.
+func newFakeServer(numBlocks int) *fakeServer {
+	s := &fakeServer{
+		txns:       make(map[chainhash.Hash]*wire.MsgTx),
+		subscribed: make(map[string]struct{}),
+	}
+	for i := 0; i < numBlocks; i++ {
+		s.addBlock(nil)
+	}
+
+	return s
+}
+
+// dial connects a new client to the server.
+func (s *fakeServer) dial() (net.Conn, error) {
+	clientConn, serverConn := net.Pipe()
+
+	s.mtx.Lock()
+	s.conn = serverConn
+	s.mtx.Unlock()
+
+	go s.serve(serverConn)
+
+	return clientConn, nil
+}
+
+// addBlock adds a block containing the given transaction to the tip of the
+// chain.
+//
+// NOTE: The mutex must be held.
+func (s *fakeServer) addBlock(tx *wire.MsgTx) {
+	s.nonce++
+	height := len(s.blocks)
+
+	coinbase := wire.NewMsgTx(2)
+	coinbase.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Index: math.MaxUint32},
+		SignatureScript:  []byte{byte(s.nonce), byte(s.nonce >> 8)},
+	})
+	coinbase.AddTxOut(wire.NewTxOut(50, []byte{txscript.OP_TRUE}))
+
+	merkleRoot := coinbase.TxHash()
+	if tx != nil {
+		s.txns[tx.TxHash()] = tx
+
+		txHash := tx.TxHash()
+		var buf [chainhash.HashSize * 2]byte
+		copy(buf[:], merkleRoot[:])
+		copy(buf[chainhash.HashSize:], txHash[:])
+		merkleRoot = chainhash.DoubleHashH(buf[:])
+	}
+
+	var prevBlock chainhash.Hash
+	if height > 0 {
+		prevBlock = s.blocks[height-1].header.BlockHash()
+	}
+
+	s.blocks = append(s.blocks, &fakeBlock{
+		header: &wire.BlockHeader{
+			Version:    1,
+			PrevBlock:  prevBlock,
+			MerkleRoot: merkleRoot,
+			Timestamp:  time.Unix(int64(height)*600, 0),
+			Bits:       0x207fffff,
+			Nonce:      s.nonce,
+		},
+		coinbase: coinbase,
+		tx:       tx,
+	})
+}
+
+// mine adds a block containing the given transaction to the tip of the chain
+// and notifies the client.
+func (s *fakeServer) mine(t *testing.T, tx *wire.MsgTx) {
+	t.Helper()
+
+	s.mtx.Lock()
+	s.addBlock(tx)
+	tip := s.tipLocked()
+	subscribed := make([]string, 0, len(s.subscribed))
+	for sh := range s.subscribed {
+		subscribed = append(subscribed, sh)
+	}
+	s.mtx.Unlock()
+
+	s.notify(t, "blockchain.headers.subscribe", tip)
+	for _, sh := range subscribed {
+		s.notify(t, "blockchain.scripthash.subscribe", sh, "status")
+	}
+}
+
+// reorg removes all blocks above the given height from the chain.
+func (s *fakeServer) reorg(height int) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.blocks = s.blocks[:height+1]
+}
+
+// tipLocked returns the tip of the chain.
+//
+// NOTE: The mutex must be held.
+func (s *fakeServer) tipLocked() *headerResult {
+	height := len(s.blocks) - 1
+
+	return &headerResult{
+		Height: int32(height),
+		Hex:    serializeHeader(s.blocks[height].header),
+	}
+}
+
+// notify sends a subscription notification to the client.
+func (s *fakeServer) notify(t *testing.T, method string,
+	params ...interface{}) {
+
+	t.Helper()
+
+	s.mtx.Lock()
+	conn := s.conn
+	s.mtx.Unlock()
+
+	err := s.send(conn, map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  method,
+		"params":  params,
+	})
+	require.NoError(t, err)
+}
+
+// send writes a message to the given connection.
+func (s *fakeServer) send(conn net.Conn, msg interface{}) error {
+	s.writeMtx.Lock()
+	defer s.writeMtx.Unlock()
+
+	return json.NewEncoder(conn).Encode(msg)
+}
+
+// serve answers the requests received over the connection until it's
+// closed.
+func (s *fakeServer) serve(conn net.Conn) {
+	decoder := json.NewDecoder(conn)
+	for {
+		var req struct {
+			ID     uint64            `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := decoder.Decode(&req); err != nil {
+			return
+		}
+
+		resp := map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      req.ID,
+		}
+
+		s.mtx.Lock()
+		result, err := s.handle(req.Method, req.Params)
+		s.mtx.Unlock()
+
+		if err != nil {
+			resp["error"] = err
+		} else {
+			resp["result"] = result
+		}
+
+		if err := s.send(conn, resp); err != nil {
+			return
+		}
+	}
+}
+
+// handle processes a single request.
+//
+// NOTE: The mutex must be held.
+func (s *fakeServer) handle(method string,
+	params []json.RawMessage) (interface{}, *rpcError) {
+
+	var (
+		height int32
+		sh     string
+		txid   string
+	)
+	switch method {
+	case "blockchain.block.header":
+		_ = json.Unmarshal(params[0], &height)
+
+	case "blockchain.scripthash.subscribe",
+		"blockchain.scripthash.unsubscribe",
+		"blockchain.scripthash.get_history":
+
+		_ = json.Unmarshal(params[0], &sh)
+
+	case "blockchain.transaction.get":
+		_ = json.Unmarshal(params[0], &txid)
+
+	case "blockchain.transaction.get_merkle":
+		_ = json.Unmarshal(params[0], &txid)
+		_ = json.Unmarshal(params[1], &height)
+	}
+
+	switch method {
+	case "server.version":
+		return []string{"fake", protocolVersion}, nil
+
+	case "blockchain.headers.subscribe":
+		return s.tipLocked(), nil
+
+	case "blockchain.block.header":
+		if int(height) >= len(s.blocks) {
+			return nil, &rpcError{
+				Code:    1,
+				Message: "unknown height",
+			}
+		}
+
+		return serializeHeader(s.blocks[height].header), nil
+
+	case "blockchain.scripthash.subscribe":
+		s.subscribed[sh] = struct{}{}
+		return nil, nil
+
+	case "blockchain.scripthash.unsubscribe":
+		delete(s.subscribed, sh)
+		return true, nil
+
+	case "blockchain.scripthash.get_history":
+		history := []historyEntry{}
+		for height, block := range s.blocks {
+			if block.tx == nil || !s.touches(block.tx, sh) {
+				continue
+			}
+
+			history = append(history, historyEntry{
+				TxHash: block.tx.TxHash().String(),
+				Height: int32(height),
+			})
+		}
+
+		return history, nil
+
+	case "blockchain.transaction.get":
+		hash, _ := chainhash.NewHashFromStr(txid)
+		tx, ok := s.txns[*hash]
+		if !ok {
+			return nil, &rpcError{Code: 2, Message: "unknown tx"}
+		}
+
+		var buf bytes.Buffer
+		_ = tx.Serialize(&buf)
+
+		return hex.EncodeToString(buf.Bytes()), nil
+
+	case "blockchain.transaction.get_merkle":
+		if int(height) >= len(s.blocks) {
+			return nil, &rpcError{
+				Code:    1,
+				Message: "unknown height",
+			}
+		}
+
+		block := s.blocks[height]
+		if block.tx == nil || block.tx.TxHash().String() != txid {
+			return nil, &rpcError{Code: 2, Message: "not in block"}
+		}
+
+		return &merkleResult{
+			BlockHeight: height,
+			Merkle:      []string{block.coinbase.TxHash().String()},
+			Pos:         1,
+		}, nil
+
+	default:
+		return nil, &rpcError{Code: 3, Message: "unknown method"}
+	}
+}
+
+// touches returns true if the transaction creates or spends an output with
+// the given script hash.
+//
+// NOTE: The mutex must be held.
+func (s *fakeServer) touches(tx *wire.MsgTx, sh string) bool {
+	for _, txOut := range tx.TxOut {
+		if scriptHash(txOut.PkScript) == sh {
+			return true
+		}
+	}
+
+	for _, txIn := range tx.TxIn {
+		prevTx, ok := s.txns[txIn.PreviousOutPoint.Hash]
+		if !ok {
+			continue
+		}
+
+		prevOut := prevTx.TxOut[txIn.PreviousOutPoint.Index]
+		if scriptHash(prevOut.PkScript) == sh {
+			return true
+		}
+	}
+
+	return false
+}
+
+// serializeHeader returns the hex encoding of the block header.
+func serializeHeader(header *wire.BlockHeader) string {
+	var buf bytes.Buffer
+	_ = header.Serialize(&buf)
+
+	return hex.EncodeToString(buf.Bytes())
+}
+
+// expectEpoch asserts that the next block epoch is at the given height.
+func expectEpoch(t *testing.T, epochs <-chan *chainntnfs.BlockEpoch,
+	height int32) {
+
+	t.Helper()
+
+	select {
+	case epoch := <-epochs:
+		require.Equal(t, height, epoch.Height)
+
+	case <-time.After(testTimeout):
+		t.Fatalf("no block epoch at height %d", height)
+	}
+}
+
+// TestScriptHash tests that the script hash of an output script is the
+// reversed SHA256 hash of the script.
+func TestScriptHash(t *testing.T) {
+	t.Parallel()
+
+	// The script hash of the P2PKH script of the genesis block's coinbase
+	// output, as given by the protocol documentation.
+	pkScript, err := hex.DecodeString(
+		"76a91462e907b15cbf27d5425399ebf6f0fb50ebb88f1888ac",
+	)
+	require.NoError(t, err)
+	require.Equal(
+		t, "8b01df4e368ea28f8dc0423bcf7a4923"+
+			"e3a12d307c875e47a0cfbf90b5c39161",
+		scriptHash(pkScript),
+	)
+}
+
+// TestElectrumNotifier tests that block epochs, confirmations and spends are
+// dispatched based on the notifications of the server, and that spends are
+// reorged out when the server's chain reorgs.
+func TestElectrumNotifier(t *testing.T) {
+	t.Parallel()
+
+	server := newFakeServer(10)
+	hintCache := initHintCache(t)
+
+	notifier := New(&Config{
+		Dial:           server.dial,
+		RequestTimeout: testTimeout,
+	}, hintCache, hintCache)
+	require.NoError(t, notifier.Start())
+	t.Cleanup(func() {
+		require.NoError(t, notifier.Stop())
+	})
+
+	epochEvent, err := notifier.RegisterBlockEpochNtfn(nil)
+	require.NoError(t, err)
+	expectEpoch(t, epochEvent.Epochs, 9)
+
+	fundingTx := wire.NewMsgTx(2)
+	fundingTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Index: 1},
+	})
+	fundingTx.AddTxOut(wire.NewTxOut(100_000, testScript))
+	fundingHash := fundingTx.TxHash()
+	fundingOutpoint := wire.OutPoint{Hash: fundingHash}
+
+	confEvent, err := notifier.RegisterConfirmationsNtfn(
+		&fundingHash, testScript, 2, 1,
+	)
+	require.NoError(t, err)
+
+	spendEvent, err := notifier.RegisterSpendNtfn(
+		&fundingOutpoint, testScript, 1,
+	)
+	require.NoError(t, err)
+
+	// The funding transaction only has a single confirmation once it's
+	// mined, so the notification isn't dispatched yet.
+	server.mine(t, fundingTx)
+	expectEpoch(t, epochEvent.Epochs, 10)
+
+	server.mine(t, nil)
+	expectEpoch(t, epochEvent.Epochs, 11)
+
+	select {
+	case conf := <-confEvent.Confirmed:
+		require.EqualValues(t, 10, conf.BlockHeight)
+		require.EqualValues(t, 1, conf.TxIndex)
+		require.Equal(t, fundingHash, conf.Tx.TxHash())
+
+	case <-time.After(testTimeout):
+		t.Fatalf("confirmation not dispatched")
+	}
+
+	// A request for a transaction that's confirmed already is dispatched
+	// right away.
+	confEvent, err = notifier.RegisterConfirmationsNtfn(
+		&fundingHash, testScript, 1, 1,
+	)
+	require.NoError(t, err)
+
+	select {
+	case conf := <-confEvent.Confirmed:
+		require.EqualValues(t, 10, conf.BlockHeight)
+
+	case <-time.After(testTimeout):
+		t.Fatalf("historical confirmation not dispatched")
+	}
+
+	spendTx := wire.NewMsgTx(2)
+	spendTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: fundingOutpoint,
+		Witness:          wire.TxWitness{{0x01}},
+	})
+	spendTx.AddTxOut(wire.NewTxOut(90_000, []byte{txscript.OP_TRUE}))
+	spendHash := spendTx.TxHash()
+
+	server.mine(t, spendTx)
+	expectEpoch(t, epochEvent.Epochs, 12)
+
+	select {
+	case spend := <-spendEvent.Spend:
+		require.Equal(t, spendHash, *spend.SpenderTxHash)
+		require.Equal(t, fundingOutpoint, *spend.SpentOutPoint)
+		require.EqualValues(t, 0, spend.SpenderInputIndex)
+		require.EqualValues(t, 12, spend.SpendingHeight)
+
+	case <-time.After(testTimeout):
+		t.Fatalf("spend not dispatched")
+	}
+
+	// Replace the block of the spend with a block that doesn't contain
+	// it, which reorgs the spend out of the chain.
+	server.reorg(11)
+	server.mine(t, nil)
+
+	select {
+	case <-spendEvent.Reorg:
+	case <-time.After(testTimeout):
+		t.Fatalf("spend reorg not dispatched")
+	}
+	expectEpoch(t, epochEvent.Epochs, 12)
+
+	// Once the spend is mined again, it's dispatched again.
+	server.mine(t, spendTx)
+	expectEpoch(t, epochEvent.Epochs, 13)
+
+	select {
+	case spend := <-spendEvent.Spend:
+		require.EqualValues(t, 13, spend.SpendingHeight)
+
+	case <-time.After(testTimeout):
+		t.Fatalf("spend not dispatched after reorg")
+	}
+}
+
+// TestVerifyMerkleProof tests that a merkle proof is only accepted if it
+// leads to the merkle root of the block.
+func TestVerifyMerkleProof(t *testing.T) {
+	t.Parallel()
+
+	txids := []chainhash.Hash{{1}, {2}, {3}, {4}}
+	hashPair := func(a, b chainhash.Hash) chainhash.Hash {
+		return chainhash.DoubleHashH(append(a[:], b[:]...))
+	}
+
+	left := hashPair(txids[0], txids[1])
+	right := hashPair(txids[2], txids[3])
+	merkleRoot := hashPair(left, right)
+
+	proof := &merkleResult{
+		Merkle: []string{txids[3].String(), left.String()},
+		Pos:    2,
+	}
+	require.NoError(t, verifyMerkleProof(&txids[2], proof, &merkleRoot))
+
+	// The proof doesn't hold for a different position.
+	proof.Pos = 3
+	require.Error(t, verifyMerkleProof(&txids[2], proof, &merkleRoot))
+
+	// Nor for a different transaction.
+	proof.Pos = 2
+	require.Error(t, verifyMerkleProof(&txids[3], proof, &merkleRoot))
+}
@@ -1,6 +1,8 @@
 package chainreg
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -21,6 +23,7 @@ import (
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/chainntnfs/bitcoindnotify"
 	"github.com/lightningnetwork/lnd/chainntnfs/btcdnotify"
+	"github.com/lightningnetwork/lnd/chainntnfs/electrumnotify"
 	"github.com/lightningnetwork/lnd/chainntnfs/neutrinonotify"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/channeldb/models"
@@ -31,8 +34,10 @@ import (
 	"github.com/lightningnetwork/lnd/lncfg"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwallet/signpolicy"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/routing/chainview"
+	"github.com/lightningnetwork/lnd/sweep"
 	"github.com/lightningnetwork/lnd/walletunlocker"
 )
 
@@ -50,6 +55,10 @@ type Config struct {
 	// light-client.
 	NeutrinoMode *lncfg.Neutrino
 
+	// ElectrumMode defines settings for connecting to an Electrum server
+	// that replaces neutrino as the chain notifier, if set.
+	ElectrumMode *lncfg.Electrum
+
 	// BitcoindMode defines settings for connecting to a bitcoind node.
 	BitcoindMode *lncfg.Bitcoind
 
@@ -167,6 +176,10 @@ type PartialChainControl struct {
 	// chain state that changes over time
 	BestBlockTracker *chainntnfs.BestBlockTracker
 
+	// BlockIntervalTracker keeps statistics about the intervals between
+	// recently mined blocks.
+	BlockIntervalTracker *chainntnfs.BlockIntervalTracker
+
 	// MempoolNotifier is used to watch for spending events happened in
 	// mempool.
 	MempoolNotifier chainntnfs.MempoolWatcher
@@ -184,6 +197,11 @@ type PartialChainControl struct {
 
 	// MinHtlcIn is the minimum HTLC we will accept.
 	MinHtlcIn lnwire.MilliSatoshi
+
+	// PackagePublisher is used to publish sweeping transactions along with
+	// their unconfirmed parent as a package. It's only set if the chain
+	// backend supports the submitpackage RPC.
+	PackagePublisher fn.Option[sweep.PackagePublisher]
 }
 
 // ChainControl couples the three primary interfaces lnd utilizes for a
@@ -216,6 +234,10 @@ type ChainControl struct {
 	// Wallet is our LightningWallet that also contains the abstract Wc
 	// above. This wallet handles all of the lightning operations.
 	Wallet *lnwallet.LightningWallet
+
+	// SignPolicy enforces the signing policy in front of the internal
+	// signer. This is nil if a remote signer is used.
+	SignPolicy *signpolicy.Enforcer
 }
 
 // NewPartialChainControl creates a new partial chain control that contains all
@@ -277,6 +299,24 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 		cc.ChainNotifier = neutrinonotify.New(
 			cfg.NeutrinoCS, hintCache, hintCache, cfg.BlockCache,
 		)
+
+		// If an Electrum server is configured, it's used to watch the
+		// chain instead, which saves us from fetching the blocks that
+		// match our filters.
+		if cfg.ElectrumMode != nil && cfg.ElectrumMode.Server != "" {
+			electrumCfg, err := newElectrumConfig(cfg)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			log.Infof("Using Electrum server %v as chain notifier",
+				cfg.ElectrumMode.Server)
+
+			cc.ChainNotifier = electrumnotify.New(
+				electrumCfg, hintCache, hintCache,
+			)
+		}
+
 		cc.ChainView, err = chainview.NewCfFilteredChainView(
 			cfg.NeutrinoCS, cfg.BlockCache,
 		)
@@ -348,7 +388,9 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 			bitcoindCfg.PollingConfig = &chain.PollingConfig{
 				BlockPollingInterval:    bitcoindMode.BlockPollingInterval,
 				TxPollingInterval:       bitcoindMode.TxPollingInterval,
-				TxPollingIntervalJitter: lncfg.DefaultTxPollingJitter,
+				TxPollingIntervalJitter: bitcoindMode.TxPollingJitter,
+				RPCBatchSize:            bitcoindMode.RPCBatchSize,
+				RPCBatchInterval:        bitcoindMode.RPCBatchInterval,
 			}
 		} else {
 			bitcoindCfg.ZMQConfig = &chain.ZMQConfig{
@@ -356,7 +398,9 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 				ZMQTxHost:              bitcoindMode.ZMQPubRawTx,
 				ZMQReadDeadline:        bitcoindMode.ZMQReadDeadline,
 				MempoolPollingInterval: bitcoindMode.TxPollingInterval,
-				PollingIntervalJitter:  lncfg.DefaultTxPollingJitter,
+				PollingIntervalJitter:  bitcoindMode.TxPollingJitter,
+				RPCBatchSize:           bitcoindMode.RPCBatchSize,
+				RPCBatchInterval:       bitcoindMode.RPCBatchInterval,
 			}
 		}
 
@@ -437,6 +481,12 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 			return nil, nil, err
 		}
 
+		// The same connection is used to publish packages, the support
+		// for which is detected once the first package is published.
+		cc.PackagePublisher = fn.Some[sweep.PackagePublisher](
+			sweep.NewBitcoindPackagePublisher(chainConn),
+		)
+
 		// If the getzmqnotifications api is available (was added in
 		// version 0.17.0) we make sure lnd subscribes to the correct
 		// zmq events. We do this to avoid a situation in which we are
@@ -700,6 +750,9 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 
 	cc.BestBlockTracker =
 		chainntnfs.NewBestBlockTracker(cc.ChainNotifier)
+	cc.BlockIntervalTracker = chainntnfs.NewBlockIntervalTracker(
+		cc.ChainNotifier, chainntnfs.DefaultBlockIntervalWindow,
+	)
 
 	switch {
 	// If the fee URL isn't set, and the user is running mainnet, then
@@ -795,6 +848,67 @@ func NewChainControl(walletConfig lnwallet.Config,
 	return cc, ccCleanup, nil
 }
 
+// newElectrumConfig creates the configuration of the Electrum chain notifier.
+// The server is connected to through our dialer, so that the connection is
+// routed through Tor if it's enabled.
+func newElectrumConfig(cfg *Config) (*electrumnotify.Config, error) {
+	electrumMode := cfg.ElectrumMode
+
+	var tlsConfig *tls.Config
+	if !electrumMode.NoTLS {
+		host, _, err := net.SplitHostPort(electrumMode.Server)
+		if err != nil {
+			return nil, fmt.Errorf("invalid electrum server %v: %w",
+				electrumMode.Server, err)
+		}
+
+		tlsConfig = &tls.Config{
+			ServerName: host,
+			MinVersion: tls.VersionTLS12,
+		}
+
+		// Many Electrum servers use self-signed certificates, which
+		// need to be trusted explicitly.
+		if electrumMode.TLSCertPath != "" {
+			cert, err := os.ReadFile(electrumMode.TLSCertPath)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read "+
+					"electrum tls certificate: %w", err)
+			}
+
+			certPool := x509.NewCertPool()
+			if !certPool.AppendCertsFromPEM(cert) {
+				return nil, fmt.Errorf("unable to parse "+
+					"electrum tls certificate %v",
+					electrumMode.TLSCertPath)
+			}
+			tlsConfig.RootCAs = certPool
+		}
+	}
+
+	electrumCfg := &electrumnotify.Config{
+		Server:         electrumMode.Server,
+		TLSConfig:      tlsConfig,
+		RequestTimeout: electrumMode.RequestTimeout,
+	}
+	if cfg.Dialer != nil {
+		electrumCfg.Dial = func() (net.Conn, error) {
+			conn, err := cfg.Dialer(electrumMode.Server)
+			if err != nil {
+				return nil, err
+			}
+
+			if tlsConfig == nil {
+				return conn, nil
+			}
+
+			return tls.Client(conn, tlsConfig), nil
+		}
+	}
+
+	return electrumCfg, nil
+}
+
 // getBitcoindHealthCheckCmd queries bitcoind for its version to decide which
 // api we should use for our health check. We prefer to use the uptime
 // command, because it has no locking and is an inexpensive call, which was
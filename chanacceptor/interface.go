@@ -66,6 +66,11 @@ type ChannelAcceptResponse struct {
 	// ZeroConf indicates that the fundee wishes to send min_depth = 0 and
 	// request a zero-conf channel with the counter-party.
 	ZeroConf bool
+
+	// CustomRecords is an optional set of custom records in the custom
+	// TLV range that should be sent to the initiator in our accept_channel
+	// message.
+	CustomRecords lnwire.CustomRecords
 }
 
 // NewChannelAcceptResponse is a constructor for a channel accept response,
@@ -18,6 +18,7 @@ const (
 	fieldMinIn           = "min htlc in"
 	fieldInFlightTotal   = "in flight total"
 	fieldUpfrontShutdown = "upfront shutdown"
+	fieldCustomRecords   = "custom records"
 )
 
 var (
@@ -96,6 +97,35 @@ func mergeDeliveryAddress(name string, current,
 	}
 }
 
+// mergeCustomRecords merges two sets of custom records, failing if both of
+// them contain the same record type with different values.
+func mergeCustomRecords(name string, current,
+	newValue lnwire.CustomRecords) (lnwire.CustomRecords, error) {
+
+	switch {
+	case len(current) == 0:
+		return newValue, nil
+
+	case len(newValue) == 0:
+		return current, nil
+	}
+
+	merged := current.Copy()
+	for recordType, value := range newValue {
+		currentValue, ok := merged[recordType]
+		if ok && !bytes.Equal(currentValue, value) {
+			return nil, fieldMismatchError(
+				fmt.Sprintf("%v (type %d)", name, recordType),
+				currentValue, value,
+			)
+		}
+
+		merged[recordType] = value
+	}
+
+	return merged, nil
+}
+
 // mergeResponse takes two channel accept responses, and attempts to merge their
 // fields, failing if any fields conflict (are non-zero and not equal). It
 // returns a new response that has all the merged fields in it.
@@ -166,5 +196,13 @@ func mergeResponse(current,
 		return current, err
 	}
 
+	current.CustomRecords, err = mergeCustomRecords(
+		fieldCustomRecords, current.CustomRecords,
+		newValue.CustomRecords,
+	)
+	if err != nil {
+		return current, err
+	}
+
 	return current, nil
 }
@@ -1,6 +1,7 @@
 package chanacceptor
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/lightningnetwork/lnd/lnwire"
@@ -179,6 +180,49 @@ func TestMergeResponse(t *testing.T) {
 			},
 			err: errZeroConf,
 		},
+		{
+			// Test that custom records of different types are
+			// combined.
+			name: "custom records merged",
+			current: ChannelAcceptResponse{
+				CustomRecords: lnwire.CustomRecords{
+					lnwire.MinCustomRecordsTlvType: {1},
+				},
+			},
+			new: ChannelAcceptResponse{
+				CustomRecords: lnwire.CustomRecords{
+					lnwire.MinCustomRecordsTlvType:     {1},
+					lnwire.MinCustomRecordsTlvType + 1: {2},
+				},
+			},
+			merged: ChannelAcceptResponse{
+				CustomRecords: lnwire.CustomRecords{
+					lnwire.MinCustomRecordsTlvType:     {1},
+					lnwire.MinCustomRecordsTlvType + 1: {2},
+				},
+			},
+			err: nil,
+		},
+		{
+			// Test that the same custom record type with different
+			// values conflicts.
+			name: "custom records conflict",
+			current: ChannelAcceptResponse{
+				CustomRecords: lnwire.CustomRecords{
+					lnwire.MinCustomRecordsTlvType: {1},
+				},
+			},
+			new: ChannelAcceptResponse{
+				CustomRecords: lnwire.CustomRecords{
+					lnwire.MinCustomRecordsTlvType: {2},
+				},
+			},
+			err: fieldMismatchError(
+				fmt.Sprintf("%v (type %d)", fieldCustomRecords,
+					lnwire.MinCustomRecordsTlvType),
+				[]byte{1}, []byte{2},
+			),
+		},
 	}
 
 	for _, test := range tests {
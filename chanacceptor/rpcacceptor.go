@@ -33,6 +33,10 @@ var (
 	errInsufficientReserve = fmt.Errorf("reserve lower than proposed dust " +
 		"limit")
 
+	// errInvalidCustomRecords is returned when the custom records returned
+	// can't be sent in an accept_channel message.
+	errInvalidCustomRecords = fmt.Errorf("invalid custom records")
+
 	// errAcceptWithError is returned when we get a response which accepts
 	// a channel but ambiguously also sets a custom error message.
 	errAcceptWithError = errors.New("channel acceptor response accepts " +
@@ -217,6 +221,7 @@ func (r *RPCAcceptor) receiveResponses(errChan chan error,
 			MinHtlcIn:       resp.MinHtlcIn,
 			MinAcceptDepth:  resp.MinAcceptDepth,
 			ZeroConf:        resp.ZeroConf,
+			CustomRecords:   resp.CustomRecords,
 		}
 
 		// We have received a decision for one of our channel
@@ -435,7 +440,7 @@ func (r *RPCAcceptor) sendAcceptRequests(errChan chan error,
 				log.Errorf("Invalid acceptor response: %v", err)
 			}
 
-			requestInfo.response <- NewChannelAcceptResponse(
+			chanAcceptResp := NewChannelAcceptResponse(
 				accept, acceptErr, shutdown,
 				uint16(resp.CsvDelay),
 				uint16(resp.MaxHtlcCount),
@@ -446,6 +451,15 @@ func (r *RPCAcceptor) sendAcceptRequests(errChan chan error,
 				resp.ZeroConf,
 			)
 
+			// The custom records are only relevant if we accept
+			// the channel.
+			customRecords := resp.CustomRecords
+			if accept && len(customRecords) != 0 {
+				chanAcceptResp.CustomRecords = customRecords
+			}
+
+			requestInfo.response <- chanAcceptResp
+
 			// Delete the channel from the acceptRequests map.
 			delete(acceptRequests, pendingID)
 
@@ -504,6 +518,16 @@ func (r *RPCAcceptor) validateAcceptorResponse(dustLimit btcutil.Amount,
 		return false, errChannelRejected, nil, errInvalidUpfrontShutdown
 	}
 
+	// Check that the custom records can be sent in our accept_channel
+	// message.
+	err = lnwire.ValidateAcceptChannelRecords(req.CustomRecords)
+	if err != nil {
+		log.Errorf("Invalid custom records for channel: %v: %v",
+			channelStr, err)
+
+		return false, errChannelRejected, nil, errInvalidCustomRecords
+	}
+
 	// Check that the custom error provided is valid.
 	if len(req.Error) > maxErrorLength {
 		return false, errChannelRejected, nil, errCustomLength
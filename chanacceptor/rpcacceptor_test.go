@@ -17,9 +17,10 @@ import (
 // TestValidateAcceptorResponse test validation of acceptor responses.
 func TestValidateAcceptorResponse(t *testing.T) {
 	var (
-		customError = errors.New("custom error")
-		validAddr   = "bcrt1qwrmq9uca0t3dy9t9wtuq5tm4405r7tfzyqn9pp"
-		addr, _     = chancloser.ParseUpfrontShutdownAddress(
+		customError     = errors.New("custom error")
+		leaseExpiryType = uint64(lnwire.LeaseExpiryRecordType)
+		validAddr       = "bcrt1qwrmq9uca0t3dy9t9wtuq5tm4405r7tfzyqn9pp"
+		addr, _         = chancloser.ParseUpfrontShutdownAddress(
 			validAddr, &chaincfg.RegressionNetParams,
 		)
 	)
@@ -115,6 +116,40 @@ func TestValidateAcceptorResponse(t *testing.T) {
 			acceptorErr: errChannelRejected,
 			error:       errMaxHtlcTooHigh,
 		},
+		{
+			name: "custom records out of range",
+			response: &lnrpc.ChannelAcceptResponse{
+				Accept:        true,
+				CustomRecords: map[uint64][]byte{1: {1}},
+			},
+			accept:      false,
+			acceptorErr: errChannelRejected,
+			error:       errInvalidCustomRecords,
+		},
+		{
+			name: "custom records use lease expiry type",
+			response: &lnrpc.ChannelAcceptResponse{
+				Accept: true,
+				CustomRecords: map[uint64][]byte{
+					leaseExpiryType: {1},
+				},
+			},
+			accept:      false,
+			acceptorErr: errChannelRejected,
+			error:       errInvalidCustomRecords,
+		},
+		{
+			name: "accepted with custom records",
+			response: &lnrpc.ChannelAcceptResponse{
+				Accept: true,
+				CustomRecords: map[uint64][]byte{
+					lnwire.MinCustomRecordsTlvType + 1: {1},
+				},
+			},
+			accept:      true,
+			acceptorErr: nil,
+			error:       nil,
+		},
 	}
 
 	for _, test := range tests {
@@ -0,0 +1,129 @@
+package chanbackup
+
+import (
+	"bytes"
+	"errors"
+	"os"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/keychain"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// Below are the scrypt parameters used to stretch a user supplied
+	// backup passphrase into the private key that backs the backup
+	// encryption key. They match the parameters used for the cipher seed.
+	scryptN = 32768
+	scryptR = 8
+	scryptP = 1
+)
+
+var (
+	// passphraseSalt is the static salt used when stretching a backup
+	// passphrase. A static salt is required as the packed backup format
+	// has no room to carry one, and the same passphrase must always
+	// result in the same key in order to be able to restore.
+	passphraseSalt = []byte("lnd-static-channel-backup")
+
+	// ErrEmptyBackupPassphrase is returned when an attempt is made to
+	// create a PassphraseKeyRing with an empty passphrase.
+	ErrEmptyBackupPassphrase = errors.New("backup passphrase must not " +
+		"be empty")
+)
+
+// PassphraseKeyRing is a keychain.KeyRing that overrides the derivation of the
+// base encryption key with a key derived from a user supplied passphrase. All
+// other derivations are passed through to the wrapped key ring. This allows
+// static channel backups to be encrypted with a key that is independent of the
+// wallet seed, so they can be handed to a third party for custody and still be
+// decrypted after the seed has been rotated.
+type PassphraseKeyRing struct {
+	keychain.KeyRing
+
+	// encryptionKey is the key descriptor that is returned in place of
+	// the base encryption key of the wrapped key ring.
+	encryptionKey keychain.KeyDescriptor
+}
+
+// A compile time check to ensure PassphraseKeyRing implements the
+// keychain.KeyRing interface.
+var _ keychain.KeyRing = (*PassphraseKeyRing)(nil)
+
+// NewPassphraseKeyRing creates a new PassphraseKeyRing that wraps the passed
+// key ring and uses the given passphrase to derive the backup encryption key.
+func NewPassphraseKeyRing(keyRing keychain.KeyRing,
+	passphrase []byte) (*PassphraseKeyRing, error) {
+
+	if len(passphrase) == 0 {
+		return nil, ErrEmptyBackupPassphrase
+	}
+
+	privKeyBytes, err := scrypt.Key(
+		passphrase, passphraseSalt, scryptN, scryptR, scryptP,
+		btcec.PrivKeyBytesLen,
+	)
+	if err != nil {
+		return nil, err
+	}
+	_, pubKey := btcec.PrivKeyFromBytes(privKeyBytes)
+
+	return &PassphraseKeyRing{
+		KeyRing: keyRing,
+		encryptionKey: keychain.KeyDescriptor{
+			KeyLocator: keychain.KeyLocator{
+				Family: keychain.KeyFamilyBaseEncryption,
+			},
+			PubKey: pubKey,
+		},
+	}, nil
+}
+
+// DeriveKey attempts to derive an arbitrary key specified by the passed
+// KeyLocator. If the locator points into the base encryption key family, then
+// the passphrase derived key is returned instead.
+//
+// NOTE: This is part of the keychain.KeyRing interface.
+func (p *PassphraseKeyRing) DeriveKey(
+	keyLoc keychain.KeyLocator) (keychain.KeyDescriptor, error) {
+
+	if keyLoc.Family == keychain.KeyFamilyBaseEncryption {
+		return p.encryptionKey, nil
+	}
+
+	return p.KeyRing.DeriveKey(keyLoc)
+}
+
+// ReencryptMultiFile re-packs the multi backup found in the passed file with
+// the new key ring if it can only be decrypted with the old one. This is used
+// when switching the backup encryption key, so the existing file on disk can
+// still be extended by the SubSwapper. If the file doesn't exist, or is
+// already encrypted with the new key ring, then this is a noop.
+func ReencryptMultiFile(backupFile *MultiFile, oldKeyRing,
+	newKeyRing keychain.KeyRing) error {
+
+	_, err := backupFile.ExtractMulti(newKeyRing)
+	if err == nil {
+		return nil
+	}
+
+	multi, err := backupFile.ExtractMulti(oldKeyRing)
+	switch {
+	// If there's no file on disk yet, then there's nothing to migrate.
+	case errors.Is(err, ErrNoBackupFileExists) || os.IsNotExist(err):
+		return nil
+
+	case err != nil:
+		return err
+	}
+
+	log.Infof("Re-encrypting backup file %v with new backup encryption "+
+		"key", backupFile.fileName)
+
+	var b bytes.Buffer
+	if err := multi.PackToWriter(&b, newKeyRing); err != nil {
+		return err
+	}
+
+	return backupFile.UpdateAndSwap(PackedMulti(b.Bytes()))
+}
@@ -0,0 +1,103 @@
+package chanbackup
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnencrypt"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPassphraseKeyRing tests that a multi backup packed with a passphrase key
+// ring can only be unpacked with a key ring derived from the same passphrase.
+func TestPassphraseKeyRing(t *testing.T) {
+	t.Parallel()
+
+	walletKeyRing := &lnencrypt.MockKeyRing{}
+
+	// An empty passphrase should be rejected.
+	_, err := NewPassphraseKeyRing(walletKeyRing, nil)
+	require.ErrorIs(t, err, ErrEmptyBackupPassphrase)
+
+	keyRing, err := NewPassphraseKeyRing(walletKeyRing, []byte("hodl"))
+	require.NoError(t, err)
+
+	channel, err := genRandomOpenChannelShell()
+	require.NoError(t, err)
+
+	multi := Multi{
+		StaticBackups: []Single{NewSingle(channel, nil)},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, multi.PackToWriter(&b, keyRing))
+	packedMulti := PackedMulti(b.Bytes())
+
+	// The wallet key ring shouldn't be able to decrypt the backup.
+	_, err = packedMulti.Unpack(walletKeyRing)
+	require.Error(t, err)
+
+	// Neither should a key ring derived from another passphrase.
+	otherKeyRing, err := NewPassphraseKeyRing(
+		walletKeyRing, []byte("hodl!"),
+	)
+	require.NoError(t, err)
+	_, err = packedMulti.Unpack(otherKeyRing)
+	require.Error(t, err)
+
+	// A fresh key ring derived from the same passphrase, as would be the
+	// case after a seed rotation, should be able to decrypt it.
+	freshKeyRing, err := NewPassphraseKeyRing(
+		&lnencrypt.MockKeyRing{}, []byte("hodl"),
+	)
+	require.NoError(t, err)
+
+	unpackedMulti, err := packedMulti.Unpack(freshKeyRing)
+	require.NoError(t, err)
+	assertMultiEqual(t, &multi, unpackedMulti)
+}
+
+// TestReencryptMultiFile tests that an existing backup file encrypted with the
+// wallet key ring is re-encrypted with the passphrase key ring.
+func TestReencryptMultiFile(t *testing.T) {
+	t.Parallel()
+
+	walletKeyRing := &lnencrypt.MockKeyRing{}
+	keyRing, err := NewPassphraseKeyRing(walletKeyRing, []byte("hodl"))
+	require.NoError(t, err)
+
+	backupFile := NewMultiFile(
+		filepath.Join(t.TempDir(), DefaultBackupFileName),
+	)
+
+	// If there's no backup file yet, then there's nothing to do.
+	err = ReencryptMultiFile(backupFile, walletKeyRing, keyRing)
+	require.NoError(t, err)
+
+	channel, err := genRandomOpenChannelShell()
+	require.NoError(t, err)
+
+	multi := Multi{
+		StaticBackups: []Single{NewSingle(channel, nil)},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, multi.PackToWriter(&b, walletKeyRing))
+	require.NoError(t, backupFile.UpdateAndSwap(PackedMulti(b.Bytes())))
+
+	err = ReencryptMultiFile(backupFile, walletKeyRing, keyRing)
+	require.NoError(t, err)
+
+	// The file should now only be readable with the passphrase key ring.
+	_, err = backupFile.ExtractMulti(walletKeyRing)
+	require.Error(t, err)
+
+	unpackedMulti, err := backupFile.ExtractMulti(keyRing)
+	require.NoError(t, err)
+	assertMultiEqual(t, &multi, unpackedMulti)
+
+	// Running the migration again should be a noop.
+	err = ReencryptMultiFile(backupFile, walletKeyRing, keyRing)
+	require.NoError(t, err)
+}
@@ -0,0 +1,462 @@
+package chanbackup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMaxBackupVersions is the default number of backup versions
+	// that are kept at each replication target.
+	DefaultMaxBackupVersions = 10
+
+	// DefaultReplicationTimeout is the default time a single replication
+	// attempt to a target may take.
+	DefaultReplicationTimeout = time.Minute
+
+	// DefaultMinReplicationBackoff is the default time we wait before
+	// retrying a failed replication for the first time.
+	DefaultMinReplicationBackoff = 5 * time.Second
+
+	// DefaultMaxReplicationBackoff is the default upper bound of the time
+	// we wait between retries of a failed replication.
+	DefaultMaxReplicationBackoff = 10 * time.Minute
+
+	// backupVersionPrefix is the prefix of the name under which each
+	// version of the multi backup is stored at a replication target.
+	backupVersionPrefix = DefaultBackupFileName + "."
+
+	// backupVersionTimeFormat is the format of the creation time that
+	// follows the backupVersionPrefix. The format has a fixed width, so
+	// the versions sort lexicographically by their creation time.
+	backupVersionTimeFormat = "20060102T150405.000000000Z"
+)
+
+var (
+	// ErrBackupVersionNotFound is returned by a BackupTarget if the
+	// requested backup version doesn't exist.
+	ErrBackupVersionNotFound = errors.New("backup version not found")
+
+	// ErrNoBackupVersions is returned if a BackupTarget doesn't hold any
+	// backup version.
+	ErrNoBackupVersions = errors.New("no backup versions found")
+)
+
+// BackupVersion describes a single version of the packed multi backup that is
+// stored at a BackupTarget.
+type BackupVersion struct {
+	// ID identifies the version at the target.
+	ID string
+
+	// Timestamp is the time at which the version was created.
+	Timestamp time.Time
+
+	// Size is the size of the packed multi backup in bytes.
+	Size int64
+}
+
+// newBackupVersionID returns the ID of a backup version created at the given
+// time.
+func newBackupVersionID(t time.Time) string {
+	return backupVersionPrefix + t.UTC().Format(backupVersionTimeFormat)
+}
+
+// parseBackupVersionID returns the creation time encoded in the given backup
+// version ID. False is returned if the ID isn't a valid version ID, e.g.
+// because it belongs to a different file stored at the target.
+func parseBackupVersionID(id string) (time.Time, bool) {
+	if !strings.HasPrefix(id, backupVersionPrefix) {
+		return time.Time{}, false
+	}
+
+	t, err := time.Parse(
+		backupVersionTimeFormat,
+		strings.TrimPrefix(id, backupVersionPrefix),
+	)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return t, true
+}
+
+// sortBackupVersions sorts the given versions from oldest to newest.
+func sortBackupVersions(versions []BackupVersion) {
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Timestamp.Before(versions[j].Timestamp)
+	})
+}
+
+// BackupTarget is a remote destination the packed multi backup is replicated
+// to. Each replicated backup is stored as a new version, such that an
+// interrupted upload never replaces the last good backup.
+type BackupTarget interface {
+	// Name returns a unique, human-readable name of the target that is
+	// used for logging and status tracking. It must not contain any
+	// credentials.
+	Name() string
+
+	// Put stores the packed multi backup as a new version at the target.
+	Put(ctx context.Context, backup PackedMulti) (*BackupVersion, error)
+
+	// Get returns the packed multi backup of the given version.
+	// ErrBackupVersionNotFound is returned if the version doesn't exist.
+	Get(ctx context.Context, id string) (PackedMulti, error)
+
+	// List returns all backup versions stored at the target, sorted from
+	// oldest to newest.
+	List(ctx context.Context) ([]BackupVersion, error)
+
+	// Delete removes the given backup version from the target.
+	Delete(ctx context.Context, id string) error
+}
+
+// FetchLatestBackup returns the newest packed multi backup that is stored at
+// the given target. This can be used to restore the channel backup after the
+// local copy was lost.
+func FetchLatestBackup(ctx context.Context,
+	target BackupTarget) (PackedMulti, error) {
+
+	versions, err := target.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(versions) == 0 {
+		return nil, ErrNoBackupVersions
+	}
+
+	return target.Get(ctx, versions[len(versions)-1].ID)
+}
+
+// ReplicationStatus is the replication state of a single target.
+type ReplicationStatus struct {
+	// LastSuccess is the time of the last successful replication.
+	LastSuccess time.Time
+
+	// LastVersion is the ID of the last backup version that was
+	// replicated successfully.
+	LastVersion string
+
+	// LastAttempt is the time of the last replication attempt.
+	LastAttempt time.Time
+
+	// LastErr is the error of the last replication attempt, if it failed.
+	LastErr error
+
+	// Pending is true if the latest backup hasn't been replicated to the
+	// target yet.
+	Pending bool
+}
+
+// ReplicatorConfig houses the configuration of a Replicator.
+type ReplicatorConfig struct {
+	// Targets are the remote destinations the backup is replicated to.
+	Targets []BackupTarget
+
+	// MaxVersions is the number of backup versions that are kept at each
+	// target. Older versions are deleted after each successful
+	// replication. If zero, all versions are kept.
+	MaxVersions int
+
+	// Timeout is the time a single replication attempt may take.
+	Timeout time.Duration
+
+	// MinBackoff is the time we wait before retrying a failed
+	// replication for the first time. The time is doubled for each
+	// further failure.
+	MinBackoff time.Duration
+
+	// MaxBackoff is the upper bound of the time we wait between retries
+	// of a failed replication.
+	MaxBackoff time.Duration
+}
+
+// targetReplicator holds the replication state of a single target.
+type targetReplicator struct {
+	target BackupTarget
+
+	// newBackup is signalled when a new backup is pending.
+	newBackup chan struct{}
+
+	mu sync.Mutex
+
+	// pending is the latest backup that still needs to be replicated. A
+	// newer backup replaces an older one that's still pending, as only
+	// the latest backup is of interest.
+	pending PackedMulti
+
+	status ReplicationStatus
+}
+
+// takePending returns the pending backup, or nil if there is none, and
+// clears it.
+func (t *targetReplicator) takePending() PackedMulti {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	backup := t.pending
+	t.pending = nil
+
+	return backup
+}
+
+// Replicator replicates the packed multi backup to a set of remote targets.
+// Replication happens in the background, failed replications are retried
+// with an exponential backoff until they succeed or are superseded by a newer
+// backup.
+type Replicator struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg ReplicatorConfig
+
+	targets []*targetReplicator
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewReplicator creates a new Replicator from the given config.
+func NewReplicator(cfg ReplicatorConfig) *Replicator {
+	targets := make([]*targetReplicator, 0, len(cfg.Targets))
+	for _, target := range cfg.Targets {
+		targets = append(targets, &targetReplicator{
+			target:    target,
+			newBackup: make(chan struct{}, 1),
+		})
+	}
+
+	return &Replicator{
+		cfg:     cfg,
+		targets: targets,
+		quit:    make(chan struct{}),
+	}
+}
+
+// Start launches a replication goroutine for each target.
+func (r *Replicator) Start() error {
+	r.started.Do(func() {
+		log.Infof("chanbackup.Replicator starting with %d targets",
+			len(r.targets))
+
+		for _, t := range r.targets {
+			r.wg.Add(1)
+			go r.replicateTarget(t)
+		}
+	})
+
+	return nil
+}
+
+// Stop aborts all pending replications and waits for the replication
+// goroutines to exit.
+func (r *Replicator) Stop() error {
+	r.stopped.Do(func() {
+		log.Infof("chanbackup.Replicator shutting down...")
+		defer log.Debug("chanbackup.Replicator shutdown complete")
+
+		close(r.quit)
+		r.wg.Wait()
+	})
+
+	return nil
+}
+
+// Replicate queues the given packed multi backup for replication to all
+// targets. It doesn't block.
+func (r *Replicator) Replicate(backup PackedMulti) {
+	for _, t := range r.targets {
+		t.mu.Lock()
+		t.pending = backup
+		t.status.Pending = true
+		t.mu.Unlock()
+
+		select {
+		case t.newBackup <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Status returns a snapshot of the replication state of all targets, keyed by
+// their name.
+func (r *Replicator) Status() map[string]ReplicationStatus {
+	status := make(map[string]ReplicationStatus, len(r.targets))
+	for _, t := range r.targets {
+		t.mu.Lock()
+		status[t.target.Name()] = t.status
+		t.mu.Unlock()
+	}
+
+	return status
+}
+
+// replicateTarget replicates each new backup to the given target, retrying
+// failed replications with an exponential backoff.
+//
+// NOTE: This MUST be run as a goroutine.
+func (r *Replicator) replicateTarget(t *targetReplicator) {
+	defer r.wg.Done()
+
+	var (
+		backoff = r.cfg.MinBackoff
+		retry   <-chan time.Time
+	)
+
+	for {
+		select {
+		case <-t.newBackup:
+		case <-retry:
+		case <-r.quit:
+			return
+		}
+
+		backup := t.takePending()
+		if backup == nil {
+			continue
+		}
+
+		err := r.replicate(t, backup)
+		if err == nil {
+			backoff = r.cfg.MinBackoff
+			retry = nil
+
+			continue
+		}
+
+		// Retry the failed backup, unless it has been superseded by a
+		// newer one in the meantime.
+		t.mu.Lock()
+		if t.pending == nil {
+			t.pending = backup
+		}
+		t.mu.Unlock()
+
+		log.Warnf("Unable to replicate channel backup to %v, "+
+			"retrying in %v: %v", t.target.Name(), backoff, err)
+
+		retry = time.After(backoff)
+
+		backoff *= 2
+		if backoff > r.cfg.MaxBackoff {
+			backoff = r.cfg.MaxBackoff
+		}
+	}
+}
+
+// replicate stores the given backup as a new version at the target and prunes
+// the versions exceeding the configured maximum.
+func (r *Replicator) replicate(t *targetReplicator,
+	backup PackedMulti) error {
+
+	ctx, cancel := context.WithTimeout(
+		context.Background(), r.cfg.Timeout,
+	)
+	defer cancel()
+
+	// Abort the replication if we're shutting down.
+	go func() {
+		select {
+		case <-r.quit:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	version, err := t.target.Put(ctx, backup)
+
+	t.mu.Lock()
+	t.status.LastAttempt = time.Now()
+	t.status.LastErr = err
+	if err == nil {
+		t.status.LastSuccess = t.status.LastAttempt
+		t.status.LastVersion = version.ID
+		t.status.Pending = t.pending != nil
+	}
+	t.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Replicated channel backup to %v as version %v",
+		t.target.Name(), version.ID)
+
+	// Pruning old versions is best effort, the replication itself
+	// succeeded.
+	if err := r.prune(ctx, t.target); err != nil {
+		log.Warnf("Unable to prune old channel backups at %v: %v",
+			t.target.Name(), err)
+	}
+
+	return nil
+}
+
+// prune deletes the oldest backup versions at the target that exceed the
+// configured maximum number of versions.
+func (r *Replicator) prune(ctx context.Context, target BackupTarget) error {
+	if r.cfg.MaxVersions <= 0 {
+		return nil
+	}
+
+	versions, err := target.List(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to list backup versions: %w", err)
+	}
+
+	for len(versions) > r.cfg.MaxVersions {
+		if err := target.Delete(ctx, versions[0].ID); err != nil {
+			return fmt.Errorf("unable to delete backup version "+
+				"%v: %w", versions[0].ID, err)
+		}
+
+		log.Debugf("Deleted channel backup version %v at %v",
+			versions[0].ID, target.Name())
+
+		versions = versions[1:]
+	}
+
+	return nil
+}
+
+// ReplicatingSwapper is a Swapper that replicates each backup that was
+// swapped in successfully to the targets of a Replicator.
+type ReplicatingSwapper struct {
+	Swapper
+
+	replicator *Replicator
+}
+
+// A compile-time check to ensure ReplicatingSwapper implements Swapper.
+var _ Swapper = (*ReplicatingSwapper)(nil)
+
+// NewReplicatingSwapper wraps the given Swapper such that each new backup is
+// also replicated by the given Replicator.
+func NewReplicatingSwapper(swapper Swapper,
+	replicator *Replicator) *ReplicatingSwapper {
+
+	return &ReplicatingSwapper{
+		Swapper:    swapper,
+		replicator: replicator,
+	}
+}
+
+// UpdateAndSwap swaps in the new backup using the wrapped Swapper and then
+// queues it for replication.
+//
+// NOTE: This is part of the Swapper interface.
+func (s *ReplicatingSwapper) UpdateAndSwap(newBackup PackedMulti) error {
+	if err := s.Swapper.UpdateAndSwap(newBackup); err != nil {
+		return err
+	}
+
+	s.replicator.Replicate(newBackup)
+
+	return nil
+}
@@ -0,0 +1,279 @@
+package chanbackup
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/stretchr/testify/require"
+)
+
+var errMockTarget = errors.New("mock target failure")
+
+// mockBackupTarget is an in-memory BackupTarget.
+type mockBackupTarget struct {
+	mu sync.Mutex
+
+	// failPuts is the number of Put calls that fail before the first
+	// one succeeds.
+	failPuts int
+
+	// now is the creation time of the next version. It's increased with
+	// each version.
+	now time.Time
+
+	versions map[string]PackedMulti
+}
+
+func newMockBackupTarget() *mockBackupTarget {
+	return &mockBackupTarget{
+		now:      time.Unix(1_700_000_000, 0),
+		versions: make(map[string]PackedMulti),
+	}
+}
+
+func (m *mockBackupTarget) Name() string {
+	return "mock"
+}
+
+func (m *mockBackupTarget) Put(_ context.Context,
+	backup PackedMulti) (*BackupVersion, error) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.failPuts > 0 {
+		m.failPuts--
+		return nil, errMockTarget
+	}
+
+	m.now = m.now.Add(time.Second)
+	id := newBackupVersionID(m.now)
+	m.versions[id] = backup
+
+	return &BackupVersion{
+		ID:        id,
+		Timestamp: m.now,
+		Size:      int64(len(backup)),
+	}, nil
+}
+
+func (m *mockBackupTarget) Get(_ context.Context,
+	id string) (PackedMulti, error) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	backup, ok := m.versions[id]
+	if !ok {
+		return nil, ErrBackupVersionNotFound
+	}
+
+	return backup, nil
+}
+
+func (m *mockBackupTarget) List(_ context.Context) ([]BackupVersion, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	versions := make([]BackupVersion, 0, len(m.versions))
+	for id, backup := range m.versions {
+		timestamp, ok := parseBackupVersionID(id)
+		if !ok {
+			return nil, errMockTarget
+		}
+
+		versions = append(versions, BackupVersion{
+			ID:        id,
+			Timestamp: timestamp,
+			Size:      int64(len(backup)),
+		})
+	}
+	sortBackupVersions(versions)
+
+	return versions, nil
+}
+
+func (m *mockBackupTarget) Delete(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.versions, id)
+
+	return nil
+}
+
+// numVersions returns the number of versions stored at the target.
+func (m *mockBackupTarget) numVersions() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.versions)
+}
+
+// newTestReplicator creates and starts a Replicator for the given target.
+func newTestReplicator(t *testing.T, target BackupTarget,
+	maxVersions int) *Replicator {
+
+	r := NewReplicator(ReplicatorConfig{
+		Targets:     []BackupTarget{target},
+		MaxVersions: maxVersions,
+		Timeout:     time.Second,
+		MinBackoff:  10 * time.Millisecond,
+		MaxBackoff:  20 * time.Millisecond,
+	})
+	require.NoError(t, r.Start())
+	t.Cleanup(func() {
+		require.NoError(t, r.Stop())
+	})
+
+	return r
+}
+
+// TestBackupVersionID tests that the creation time of a backup version can be
+// recovered from its ID.
+func TestBackupVersionID(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1_700_000_000, 123_456_789)
+	id := newBackupVersionID(now)
+	require.Equal(t, "channel.backup.20231114T221320.123456789Z", id)
+
+	timestamp, ok := parseBackupVersionID(id)
+	require.True(t, ok)
+	require.True(t, now.Equal(timestamp))
+
+	// Other files stored at the target aren't considered versions.
+	_, ok = parseBackupVersionID(id + ".tmp")
+	require.False(t, ok)
+
+	_, ok = parseBackupVersionID(DefaultBackupFileName)
+	require.False(t, ok)
+}
+
+// TestReplicatorRetry tests that a failed replication is retried until it
+// succeeds.
+func TestReplicatorRetry(t *testing.T) {
+	t.Parallel()
+
+	target := newMockBackupTarget()
+	target.failPuts = 2
+
+	r := newTestReplicator(t, target, 0)
+
+	backup := PackedMulti("backup")
+	r.Replicate(backup)
+
+	require.Eventually(t, func() bool {
+		return !r.Status()[target.Name()].Pending
+	}, 5*time.Second, 10*time.Millisecond)
+
+	versions, err := target.List(context.Background())
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+
+	latest, err := FetchLatestBackup(context.Background(), target)
+	require.NoError(t, err)
+	require.Equal(t, backup, latest)
+
+	status := r.Status()[target.Name()]
+	require.NoError(t, status.LastErr)
+	require.False(t, status.LastSuccess.IsZero())
+	require.Equal(t, versions[0].ID, status.LastVersion)
+}
+
+// TestReplicatorPrune tests that only the configured number of versions is
+// kept at a target.
+func TestReplicatorPrune(t *testing.T) {
+	t.Parallel()
+
+	target := newMockBackupTarget()
+	r := newTestReplicator(t, target, 2)
+
+	backups := []PackedMulti{
+		PackedMulti("first"), PackedMulti("second"),
+		PackedMulti("third"),
+	}
+	for _, backup := range backups {
+		r.Replicate(backup)
+
+		require.Eventually(t, func() bool {
+			return !r.Status()[target.Name()].Pending
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+
+	// Old versions are pruned after the status is updated.
+	require.Eventually(t, func() bool {
+		return target.numVersions() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	versions, err := target.List(context.Background())
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+
+	for i, version := range versions {
+		backup, err := target.Get(context.Background(), version.ID)
+		require.NoError(t, err)
+		require.Equal(t, backups[i+1], backup)
+	}
+}
+
+// TestFetchLatestBackupNoVersions tests that an error is returned if a target
+// doesn't hold any backup.
+func TestFetchLatestBackupNoVersions(t *testing.T) {
+	t.Parallel()
+
+	_, err := FetchLatestBackup(
+		context.Background(), newMockBackupTarget(),
+	)
+	require.ErrorIs(t, err, ErrNoBackupVersions)
+}
+
+// stubSwapper is a Swapper that stores the latest backup in memory.
+type stubSwapper struct {
+	fail   bool
+	backup PackedMulti
+}
+
+func (s *stubSwapper) UpdateAndSwap(newBackup PackedMulti) error {
+	if s.fail {
+		return errMockTarget
+	}
+
+	s.backup = newBackup
+
+	return nil
+}
+
+func (s *stubSwapper) ExtractMulti(keychain.KeyRing) (*Multi, error) {
+	return &Multi{}, nil
+}
+
+// TestReplicatingSwapper tests that only backups that were swapped in
+// successfully are replicated.
+func TestReplicatingSwapper(t *testing.T) {
+	t.Parallel()
+
+	target := newMockBackupTarget()
+	r := NewReplicator(ReplicatorConfig{
+		Targets: []BackupTarget{target},
+	})
+
+	// The replicator isn't started, so the replicated backup stays
+	// pending.
+	swapper := &stubSwapper{fail: true}
+	replicatingSwapper := NewReplicatingSwapper(swapper, r)
+
+	err := replicatingSwapper.UpdateAndSwap(PackedMulti("backup"))
+	require.ErrorIs(t, err, errMockTarget)
+	require.False(t, r.Status()[target.Name()].Pending)
+
+	swapper.fail = false
+	err = replicatingSwapper.UpdateAndSwap(PackedMulti("backup"))
+	require.NoError(t, err)
+	require.Equal(t, PackedMulti("backup"), swapper.backup)
+	require.True(t, r.Status()[target.Name()].Pending)
+}
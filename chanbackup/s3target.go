@@ -0,0 +1,406 @@
+package chanbackup
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// maxS3ErrorResponseSize is the maximum number of bytes we read from
+	// the body of an S3 error response.
+	maxS3ErrorResponseSize = 4096
+
+	// maxBackupSize is the maximum size of a packed multi backup we read
+	// from a replication target.
+	maxBackupSize = 64 * 1024 * 1024
+
+	// s3SigningAlgorithm is the AWS signature version 4 algorithm that is
+	// used to sign the requests to the S3 API.
+	s3SigningAlgorithm = "AWS4-HMAC-SHA256"
+
+	// s3SignedHeaders is the list of headers that are covered by the
+	// request signature.
+	s3SignedHeaders = "host;x-amz-content-sha256;x-amz-date"
+
+	// s3DateTimeFormat is the format of the x-amz-date header.
+	s3DateTimeFormat = "20060102T150405Z"
+
+	// s3DateFormat is the format of the date in the credential scope.
+	s3DateFormat = "20060102"
+)
+
+// S3TargetConfig houses the configuration of an S3Target.
+type S3TargetConfig struct {
+	// Endpoint is the URL of the S3 compatible API, e.g.
+	// https://s3.us-east-1.amazonaws.com.
+	Endpoint string
+
+	// Bucket is the bucket the backups are stored in.
+	Bucket string
+
+	// Region is the region of the bucket that's used to sign requests.
+	Region string
+
+	// AccessKeyID is the ID of the access key used to sign requests.
+	AccessKeyID string
+
+	// SecretAccessKey is the secret of the access key.
+	SecretAccessKey string
+
+	// Prefix is an optional key prefix under which the backups are stored
+	// in the bucket.
+	Prefix string
+}
+
+// S3Target is a BackupTarget that stores the backup versions as objects in a
+// bucket of an S3 compatible object storage. Path style requests are used, as
+// they are supported by most S3 compatible services.
+type S3Target struct {
+	cfg      S3TargetConfig
+	endpoint *url.URL
+	client   *http.Client
+
+	// now returns the current time. It's used to sign requests and to
+	// name new backup versions.
+	now func() time.Time
+}
+
+// A compile-time check to ensure S3Target implements BackupTarget.
+var _ BackupTarget = (*S3Target)(nil)
+
+// NewS3Target creates a new S3Target from the given config.
+func NewS3Target(cfg S3TargetConfig) (*S3Target, error) {
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid s3 endpoint: %w", err)
+	}
+
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return nil, fmt.Errorf("s3 endpoint %v must use http or https",
+			cfg.Endpoint)
+	}
+
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("s3 bucket must be set")
+	}
+
+	cfg.Prefix = strings.Trim(cfg.Prefix, "/")
+
+	return &S3Target{
+		cfg:      cfg,
+		endpoint: endpoint,
+		client:   &http.Client{},
+		now:      time.Now,
+	}, nil
+}
+
+// Name returns the bucket and prefix of the target.
+//
+// NOTE: This is part of the BackupTarget interface.
+func (s *S3Target) Name() string {
+	return fmt.Sprintf("s3://%s/%s", s.cfg.Bucket, s.cfg.Prefix)
+}
+
+// Put uploads the packed multi backup as a new object.
+//
+// NOTE: This is part of the BackupTarget interface.
+func (s *S3Target) Put(ctx context.Context,
+	backup PackedMulti) (*BackupVersion, error) {
+
+	now := s.now()
+	id := newBackupVersionID(now)
+
+	resp, err := s.do(ctx, http.MethodPut, s.objectKey(id), nil, backup)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return &BackupVersion{
+		ID:        id,
+		Timestamp: now,
+		Size:      int64(len(backup)),
+	}, nil
+}
+
+// Get downloads the object of the given backup version.
+//
+// NOTE: This is part of the BackupTarget interface.
+func (s *S3Target) Get(ctx context.Context, id string) (PackedMulti, error) {
+	resp, err := s.do(ctx, http.MethodGet, s.objectKey(id), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return io.ReadAll(io.LimitReader(resp.Body, maxBackupSize))
+}
+
+// s3ListResult is the response of the ListObjectsV2 API.
+type s3ListResult struct {
+	Contents []struct {
+		Key  string `xml:"Key"`
+		Size int64  `xml:"Size"`
+	} `xml:"Contents"`
+
+	IsTruncated bool `xml:"IsTruncated"`
+
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// List lists the objects of all backup versions using the ListObjectsV2 API.
+//
+// NOTE: This is part of the BackupTarget interface.
+func (s *S3Target) List(ctx context.Context) ([]BackupVersion, error) {
+	var (
+		versions          []BackupVersion
+		continuationToken string
+	)
+	for {
+		query := url.Values{
+			"list-type": {"2"},
+			"prefix":    {s.objectKey(backupVersionPrefix)},
+		}
+		if continuationToken != "" {
+			query.Set("continuation-token", continuationToken)
+		}
+
+		resp, err := s.do(ctx, http.MethodGet, "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var result s3ListResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode list "+
+				"response: %w", err)
+		}
+
+		for _, object := range result.Contents {
+			id := path.Base(object.Key)
+			timestamp, ok := parseBackupVersionID(id)
+			if !ok {
+				continue
+			}
+
+			versions = append(versions, BackupVersion{
+				ID:        id,
+				Timestamp: timestamp,
+				Size:      object.Size,
+			})
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			break
+		}
+		continuationToken = result.NextContinuationToken
+	}
+
+	sortBackupVersions(versions)
+
+	return versions, nil
+}
+
+// Delete deletes the object of the given backup version.
+//
+// NOTE: This is part of the BackupTarget interface.
+func (s *S3Target) Delete(ctx context.Context, id string) error {
+	resp, err := s.do(ctx, http.MethodDelete, s.objectKey(id), nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// objectKey returns the key of the object with the given name.
+func (s *S3Target) objectKey(name string) string {
+	if s.cfg.Prefix == "" {
+		return name
+	}
+
+	return s.cfg.Prefix + "/" + name
+}
+
+// do sends a signed request for the given object key, or for the bucket if the
+// key is empty. An error is returned for any non-2xx response, in which case
+// the response body is already closed.
+func (s *S3Target) do(ctx context.Context, method, key string,
+	query url.Values, body []byte) (*http.Response, error) {
+
+	u := *s.endpoint
+	u.Path = path.Join("/", u.Path, s.cfg.Bucket)
+	if key != "" {
+		u.Path += "/" + key
+	}
+	u.RawPath = s3EncodeURI(u.Path, false)
+	u.RawQuery = s3CanonicalQuery(query)
+
+	req, err := http.NewRequestWithContext(
+		ctx, method, u.String(), bytes.NewReader(body),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	signS3Request(req, body, s.cfg, s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound && key != "" {
+		return nil, fmt.Errorf("%w: %v", ErrBackupVersionNotFound, key)
+	}
+
+	respBody, _ := io.ReadAll(
+		io.LimitReader(resp.Body, maxS3ErrorResponseSize),
+	)
+
+	return nil, fmt.Errorf("unexpected status %v: %s", resp.Status,
+		strings.TrimSpace(string(respBody)))
+}
+
+// signS3Request signs the request with the AWS signature version 4 using the
+// credentials of the given config.
+func signS3Request(req *http.Request, body []byte, cfg S3TargetConfig,
+	now time.Time) {
+
+	now = now.UTC()
+	dateTime := now.Format(s3DateTimeFormat)
+	date := now.Format(s3DateFormat)
+
+	payloadHash := sha256.Sum256(body)
+	payloadHashHex := hex.EncodeToString(payloadHash[:])
+
+	req.Header.Set("X-Amz-Date", dateTime)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHashHex)
+
+	canonicalHeaders := fmt.Sprintf(
+		"host:%s\nx-amz-content-sha256:%s\nx-amz-date:%s\n",
+		req.URL.Host, payloadHashHex, dateTime,
+	)
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		s3EncodeURI(req.URL.Path, false),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		s3SignedHeaders,
+		payloadHashHex,
+	}, "\n")
+
+	scope := strings.Join(
+		[]string{date, cfg.Region, "s3", "aws4_request"}, "/",
+	)
+
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		s3SigningAlgorithm,
+		dateTime,
+		scope,
+		hex.EncodeToString(canonicalRequestHash[:]),
+	}, "\n")
+
+	signingKey := s3HMAC([]byte("AWS4"+cfg.SecretAccessKey), date)
+	signingKey = s3HMAC(signingKey, cfg.Region)
+	signingKey = s3HMAC(signingKey, "s3")
+	signingKey = s3HMAC(signingKey, "aws4_request")
+
+	signature := hex.EncodeToString(s3HMAC(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3SigningAlgorithm, cfg.AccessKeyID, scope, s3SignedHeaders,
+		signature,
+	))
+}
+
+// s3HMAC returns the HMAC-SHA256 of the data using the given key.
+func s3HMAC(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+
+	return mac.Sum(nil)
+}
+
+// s3CanonicalQuery returns the query string in the canonical form required by
+// the AWS signature, with the parameters sorted by name and all reserved
+// characters percent-encoded.
+func s3CanonicalQuery(query url.Values) string {
+	type param struct {
+		name, value string
+	}
+
+	params := make([]param, 0, len(query))
+	for name, values := range query {
+		for _, value := range values {
+			params = append(params, param{
+				name:  s3EncodeURI(name, true),
+				value: s3EncodeURI(value, true),
+			})
+		}
+	}
+
+	sort.Slice(params, func(i, j int) bool {
+		if params[i].name != params[j].name {
+			return params[i].name < params[j].name
+		}
+
+		return params[i].value < params[j].value
+	})
+
+	encoded := make([]string, 0, len(params))
+	for _, p := range params {
+		encoded = append(encoded, p.name+"="+p.value)
+	}
+
+	return strings.Join(encoded, "&")
+}
+
+// s3EncodeURI percent-encodes all characters of the string except for the
+// unreserved characters defined by RFC 3986, as required by the AWS signature.
+// Slashes are only encoded if encodeSlash is true.
+func s3EncodeURI(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z',
+			'0' <= c && c <= '9', c == '-', c == '_', c == '.',
+			c == '~':
+
+			b.WriteByte(c)
+
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+
+	return b.String()
+}
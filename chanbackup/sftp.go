@@ -0,0 +1,512 @@
+package chanbackup
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// The SFTP packet types and constants of version 3 of the protocol, which is
+// supported by all common servers. Only the subset required to store and
+// retrieve the backup versions is implemented.
+const (
+	sftpProtocolVersion = 3
+
+	sftpPacketInit    = 1
+	sftpPacketVersion = 2
+	sftpPacketOpen    = 3
+	sftpPacketClose   = 4
+	sftpPacketRead    = 5
+	sftpPacketWrite   = 6
+	sftpPacketOpenDir = 11
+	sftpPacketReadDir = 12
+	sftpPacketRemove  = 13
+	sftpPacketRename  = 18
+	sftpPacketStatus  = 101
+	sftpPacketHandle  = 102
+	sftpPacketData    = 103
+	sftpPacketName    = 104
+
+	sftpOpenFlagRead   = 0x01
+	sftpOpenFlagWrite  = 0x02
+	sftpOpenFlagCreate = 0x08
+	sftpOpenFlagTrunc  = 0x10
+
+	sftpStatusOK         = 0
+	sftpStatusEOF        = 1
+	sftpStatusNoSuchFile = 2
+
+	sftpAttrSize        = 0x00000001
+	sftpAttrUIDGID      = 0x00000002
+	sftpAttrPermissions = 0x00000004
+	sftpAttrACModTime   = 0x00000008
+	sftpAttrExtended    = 0x80000000
+
+	// sftpChunkSize is the number of bytes that are read or written with
+	// a single request. Servers must support at least 32 KiB.
+	sftpChunkSize = 32 * 1024
+
+	// maxSFTPPacketSize is the maximum size of a packet we accept from
+	// the server.
+	maxSFTPPacketSize = 256 * 1024
+)
+
+// errSFTPMalformedPacket is returned if a packet received from the server
+// can't be decoded.
+var errSFTPMalformedPacket = errors.New("malformed sftp packet")
+
+// sftpStatusError is an error status returned by the SFTP server.
+type sftpStatusError struct {
+	code uint32
+	msg  string
+}
+
+// Error returns the error message of the server.
+func (e *sftpStatusError) Error() string {
+	return fmt.Sprintf("sftp status %d: %s", e.code, e.msg)
+}
+
+// sftpPacket is used to encode the payload of a packet.
+type sftpPacket []byte
+
+// appendUint32 appends a big-endian uint32.
+func (p sftpPacket) appendUint32(v uint32) sftpPacket {
+	return binary.BigEndian.AppendUint32(p, v)
+}
+
+// appendUint64 appends a big-endian uint64.
+func (p sftpPacket) appendUint64(v uint64) sftpPacket {
+	return binary.BigEndian.AppendUint64(p, v)
+}
+
+// appendString appends a length-prefixed string.
+func (p sftpPacket) appendString(s []byte) sftpPacket {
+	return append(p.appendUint32(uint32(len(s))), s...)
+}
+
+// sftpReader is used to decode the payload of a packet. The first decoding
+// error is stored and all further reads return zero values.
+type sftpReader struct {
+	b   []byte
+	err error
+}
+
+// readUint32 reads a big-endian uint32.
+func (r *sftpReader) readUint32() uint32 {
+	if r.err != nil || len(r.b) < 4 {
+		r.err = errSFTPMalformedPacket
+		return 0
+	}
+
+	v := binary.BigEndian.Uint32(r.b)
+	r.b = r.b[4:]
+
+	return v
+}
+
+// readUint64 reads a big-endian uint64.
+func (r *sftpReader) readUint64() uint64 {
+	if r.err != nil || len(r.b) < 8 {
+		r.err = errSFTPMalformedPacket
+		return 0
+	}
+
+	v := binary.BigEndian.Uint64(r.b)
+	r.b = r.b[8:]
+
+	return v
+}
+
+// readString reads a length-prefixed string.
+func (r *sftpReader) readString() []byte {
+	n := r.readUint32()
+	if r.err != nil || uint32(len(r.b)) < n {
+		r.err = errSFTPMalformedPacket
+		return nil
+	}
+
+	s := r.b[:n]
+	r.b = r.b[n:]
+
+	return s
+}
+
+// readAttrs decodes file attributes and returns the file size, if present.
+func (r *sftpReader) readAttrs() int64 {
+	var size int64
+
+	flags := r.readUint32()
+	if flags&sftpAttrSize != 0 {
+		size = int64(r.readUint64())
+	}
+	if flags&sftpAttrUIDGID != 0 {
+		r.readUint32()
+		r.readUint32()
+	}
+	if flags&sftpAttrPermissions != 0 {
+		r.readUint32()
+	}
+	if flags&sftpAttrACModTime != 0 {
+		r.readUint32()
+		r.readUint32()
+	}
+	if flags&sftpAttrExtended != 0 {
+		count := r.readUint32()
+		for i := uint32(0); i < count && r.err == nil; i++ {
+			r.readString()
+			r.readString()
+		}
+	}
+
+	return size
+}
+
+// sftpDirEntry is an entry of a remote directory.
+type sftpDirEntry struct {
+	name string
+	size int64
+}
+
+// sftpClient is a minimal client of version 3 of the SFTP protocol. Requests
+// are sent one at a time, so it must not be used concurrently.
+type sftpClient struct {
+	r io.Reader
+	w io.Writer
+
+	nextID uint32
+}
+
+// newSFTPClient performs the version handshake with the server on the other
+// end of the given stream.
+func newSFTPClient(r io.Reader, w io.Writer) (*sftpClient, error) {
+	c := &sftpClient{
+		r: r,
+		w: w,
+	}
+
+	initPayload := sftpPacket{}.appendUint32(sftpProtocolVersion)
+	if err := c.send(sftpPacketInit, initPayload); err != nil {
+		return nil, err
+	}
+
+	// The server may list supported extensions after its version, which
+	// we don't use.
+	typ, payload, err := c.recv()
+	if err != nil {
+		return nil, err
+	}
+
+	if typ != sftpPacketVersion {
+		return nil, fmt.Errorf("unexpected sftp packet type %d "+
+			"during handshake", typ)
+	}
+
+	version := payload.readUint32()
+	if payload.err != nil {
+		return nil, payload.err
+	}
+
+	if version < sftpProtocolVersion {
+		return nil, fmt.Errorf("unsupported sftp version %d", version)
+	}
+
+	return c, nil
+}
+
+// send writes a single packet.
+func (c *sftpClient) send(typ byte, payload sftpPacket) error {
+	packet := make(sftpPacket, 0, 5+len(payload))
+	packet = packet.appendUint32(uint32(len(payload) + 1))
+	packet = append(packet, typ)
+	packet = append(packet, payload...)
+
+	_, err := c.w.Write(packet)
+
+	return err
+}
+
+// recv reads a single packet.
+func (c *sftpClient) recv() (byte, *sftpReader, error) {
+	var lenBuf [4]byte
+	if _, err := io.ReadFull(c.r, lenBuf[:]); err != nil {
+		return 0, nil, err
+	}
+
+	length := binary.BigEndian.Uint32(lenBuf[:])
+	if length == 0 || length > maxSFTPPacketSize {
+		return 0, nil, fmt.Errorf("invalid sftp packet length %d",
+			length)
+	}
+
+	packet := make([]byte, length)
+	if _, err := io.ReadFull(c.r, packet); err != nil {
+		return 0, nil, err
+	}
+
+	return packet[0], &sftpReader{b: packet[1:]}, nil
+}
+
+// request sends a request with the given payload, which is prefixed with a
+// new request ID, and returns the type and payload of the response.
+func (c *sftpClient) request(typ byte, payload sftpPacket) (byte,
+	*sftpReader, error) {
+
+	c.nextID++
+	id := c.nextID
+
+	packet := sftpPacket{}.appendUint32(id)
+	packet = append(packet, payload...)
+	if err := c.send(typ, packet); err != nil {
+		return 0, nil, err
+	}
+
+	respType, resp, err := c.recv()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if respID := resp.readUint32(); respID != id {
+		return 0, nil, fmt.Errorf("unexpected sftp response id %d, "+
+			"expected %d", respID, id)
+	}
+
+	return respType, resp, resp.err
+}
+
+// statusError decodes a status response and returns nil if it signals
+// success.
+func statusError(resp *sftpReader) error {
+	code := resp.readUint32()
+	msg := resp.readString()
+	if resp.err != nil {
+		return resp.err
+	}
+
+	if code == sftpStatusOK {
+		return nil
+	}
+
+	return &sftpStatusError{code: code, msg: string(msg)}
+}
+
+// unexpectedResponse returns the error for a response of an unexpected type.
+// Status responses are decoded into the returned error.
+func unexpectedResponse(typ byte, resp *sftpReader) error {
+	if typ != sftpPacketStatus {
+		return fmt.Errorf("unexpected sftp packet type %d", typ)
+	}
+
+	if err := statusError(resp); err != nil {
+		return err
+	}
+
+	return fmt.Errorf("unexpected sftp status ok")
+}
+
+// expectStatus sends a request that is answered with a status and returns
+// the decoded status error.
+func (c *sftpClient) expectStatus(typ byte, payload sftpPacket) error {
+	respType, resp, err := c.request(typ, payload)
+	if err != nil {
+		return err
+	}
+
+	if respType != sftpPacketStatus {
+		return fmt.Errorf("unexpected sftp packet type %d", respType)
+	}
+
+	return statusError(resp)
+}
+
+// expectHandle sends a request that is answered with a handle and returns
+// the handle.
+func (c *sftpClient) expectHandle(typ byte,
+	payload sftpPacket) ([]byte, error) {
+
+	respType, resp, err := c.request(typ, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if respType != sftpPacketHandle {
+		return nil, unexpectedResponse(respType, resp)
+	}
+
+	handle := resp.readString()
+
+	return handle, resp.err
+}
+
+// isEOF returns true if the error is an end of file status.
+func isEOF(err error) bool {
+	var statusErr *sftpStatusError
+
+	return errors.As(err, &statusErr) && statusErr.code == sftpStatusEOF
+}
+
+// isNoSuchFile returns true if the error is a no such file status.
+func isNoSuchFile(err error) bool {
+	var statusErr *sftpStatusError
+
+	return errors.As(err, &statusErr) &&
+		statusErr.code == sftpStatusNoSuchFile
+}
+
+// closeHandle closes the given file or directory handle.
+func (c *sftpClient) closeHandle(handle []byte) error {
+	return c.expectStatus(
+		sftpPacketClose, sftpPacket{}.appendString(handle),
+	)
+}
+
+// writeFile creates or truncates the file at the given path and writes the
+// data to it.
+func (c *sftpClient) writeFile(path string, data []byte) error {
+	flags := uint32(sftpOpenFlagWrite | sftpOpenFlagCreate |
+		sftpOpenFlagTrunc)
+
+	// No attributes are set on the new file.
+	open := sftpPacket{}.appendString([]byte(path))
+	open = open.appendUint32(flags).appendUint32(0)
+
+	handle, err := c.expectHandle(sftpPacketOpen, open)
+	if err != nil {
+		return fmt.Errorf("unable to open %v: %w", path, err)
+	}
+
+	for offset := 0; offset < len(data); offset += sftpChunkSize {
+		end := offset + sftpChunkSize
+		if end > len(data) {
+			end = len(data)
+		}
+
+		write := sftpPacket{}.appendString(handle)
+		write = write.appendUint64(uint64(offset))
+		write = write.appendString(data[offset:end])
+
+		err := c.expectStatus(sftpPacketWrite, write)
+		if err != nil {
+			_ = c.closeHandle(handle)
+
+			return fmt.Errorf("unable to write %v: %w", path, err)
+		}
+	}
+
+	// Servers may only report write errors once the file is closed.
+	if err := c.closeHandle(handle); err != nil {
+		return fmt.Errorf("unable to close %v: %w", path, err)
+	}
+
+	return nil
+}
+
+// readFile reads the file at the given path, up to the given size limit.
+func (c *sftpClient) readFile(path string, limit int) ([]byte, error) {
+	open := sftpPacket{}.appendString([]byte(path))
+	open = open.appendUint32(sftpOpenFlagRead).appendUint32(0)
+
+	handle, err := c.expectHandle(sftpPacketOpen, open)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = c.closeHandle(handle)
+	}()
+
+	var data []byte
+	for len(data) < limit {
+		read := sftpPacket{}.appendString(handle)
+		read = read.appendUint64(uint64(len(data)))
+		read = read.appendUint32(sftpChunkSize)
+
+		respType, resp, err := c.request(sftpPacketRead, read)
+		if err != nil {
+			return nil, err
+		}
+
+		if respType != sftpPacketData {
+			err := unexpectedResponse(respType, resp)
+			if isEOF(err) {
+				return data, nil
+			}
+
+			return nil, err
+		}
+
+		chunk := resp.readString()
+		if resp.err != nil {
+			return nil, resp.err
+		}
+
+		data = append(data, chunk...)
+	}
+
+	return nil, fmt.Errorf("%v exceeds the size limit of %d bytes", path,
+		limit)
+}
+
+// readDir lists the entries of the directory at the given path.
+func (c *sftpClient) readDir(path string) ([]sftpDirEntry, error) {
+	handle, err := c.expectHandle(
+		sftpPacketOpenDir, sftpPacket{}.appendString([]byte(path)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = c.closeHandle(handle)
+	}()
+
+	var entries []sftpDirEntry
+	for {
+		respType, resp, err := c.request(
+			sftpPacketReadDir, sftpPacket{}.appendString(handle),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if respType != sftpPacketName {
+			err := unexpectedResponse(respType, resp)
+			if isEOF(err) {
+				return entries, nil
+			}
+
+			return nil, err
+		}
+
+		count := resp.readUint32()
+		for i := uint32(0); i < count && resp.err == nil; i++ {
+			name := resp.readString()
+
+			// The long name is meant for display only.
+			resp.readString()
+
+			size := resp.readAttrs()
+
+			entries = append(entries, sftpDirEntry{
+				name: string(name),
+				size: size,
+			})
+		}
+		if resp.err != nil {
+			return nil, resp.err
+		}
+	}
+}
+
+// rename renames the file at oldPath to newPath.
+func (c *sftpClient) rename(oldPath, newPath string) error {
+	rename := sftpPacket{}.appendString([]byte(oldPath))
+	rename = rename.appendString([]byte(newPath))
+
+	return c.expectStatus(sftpPacketRename, rename)
+}
+
+// remove removes the file at the given path.
+func (c *sftpClient) remove(path string) error {
+	return c.expectStatus(
+		sftpPacketRemove, sftpPacket{}.appendString([]byte(path)),
+	)
+}
@@ -0,0 +1,282 @@
+package chanbackup
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"path"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+// fakeSFTPServer is an in-memory implementation of the subset of the SFTP
+// protocol used by the sftpClient.
+type fakeSFTPServer struct {
+	files map[string][]byte
+
+	handles    map[string]*fakeSFTPHandle
+	nextHandle int
+}
+
+// fakeSFTPHandle is an open file or directory of the fakeSFTPServer.
+type fakeSFTPHandle struct {
+	path   string
+	listed bool
+}
+
+func newFakeSFTPServer() *fakeSFTPServer {
+	return &fakeSFTPServer{
+		files:   make(map[string][]byte),
+		handles: make(map[string]*fakeSFTPHandle),
+	}
+}
+
+// serve answers the requests received over the connection until it's
+// closed.
+func (f *fakeSFTPServer) serve(conn net.Conn) {
+	// The framing of the packets is the same in both directions.
+	c := &sftpClient{r: conn, w: conn}
+
+	typ, _, err := c.recv()
+	if err != nil || typ != sftpPacketInit {
+		return
+	}
+
+	version := sftpPacket{}.appendUint32(sftpProtocolVersion)
+	if err := c.send(sftpPacketVersion, version); err != nil {
+		return
+	}
+
+	for {
+		typ, req, err := c.recv()
+		if err != nil {
+			return
+		}
+
+		id := req.readUint32()
+		respType, payload := f.handle(typ, req)
+
+		resp := sftpPacket{}.appendUint32(id)
+		resp = append(resp, payload...)
+		if err := c.send(respType, resp); err != nil {
+			return
+		}
+	}
+}
+
+// fakeSFTPStatus returns a status response with the given code.
+func fakeSFTPStatus(code uint32) (byte, sftpPacket) {
+	status := sftpPacket{}.appendUint32(code)
+	status = status.appendString([]byte("status")).appendString(nil)
+
+	return sftpPacketStatus, status
+}
+
+// newHandle returns a handle response for a new handle of the given path.
+func (f *fakeSFTPServer) newHandle(p string) (byte, sftpPacket) {
+	f.nextHandle++
+	handle := strconv.Itoa(f.nextHandle)
+	f.handles[handle] = &fakeSFTPHandle{path: p}
+
+	return sftpPacketHandle, sftpPacket{}.appendString([]byte(handle))
+}
+
+// handle processes a single request.
+func (f *fakeSFTPServer) handle(typ byte, req *sftpReader) (byte,
+	sftpPacket) {
+
+	switch typ {
+	case sftpPacketOpen:
+		p := string(req.readString())
+		flags := req.readUint32()
+		req.readAttrs()
+
+		_, ok := f.files[p]
+		switch {
+		case flags&sftpOpenFlagCreate != 0:
+			if !ok || flags&sftpOpenFlagTrunc != 0 {
+				f.files[p] = nil
+			}
+
+		case !ok:
+			return fakeSFTPStatus(sftpStatusNoSuchFile)
+		}
+
+		return f.newHandle(p)
+
+	case sftpPacketOpenDir:
+		return f.newHandle(string(req.readString()))
+
+	case sftpPacketClose:
+		delete(f.handles, string(req.readString()))
+
+		return fakeSFTPStatus(sftpStatusOK)
+
+	case sftpPacketWrite:
+		h := f.handles[string(req.readString())]
+		offset := int(req.readUint64())
+		data := req.readString()
+
+		file := f.files[h.path]
+		if end := offset + len(data); len(file) < end {
+			file = append(file, make([]byte, end-len(file))...)
+		}
+		copy(file[offset:], data)
+		f.files[h.path] = file
+
+		return fakeSFTPStatus(sftpStatusOK)
+
+	case sftpPacketRead:
+		h := f.handles[string(req.readString())]
+		offset := int(req.readUint64())
+		length := int(req.readUint32())
+
+		file := f.files[h.path]
+		if offset >= len(file) {
+			return fakeSFTPStatus(sftpStatusEOF)
+		}
+
+		end := offset + length
+		if end > len(file) {
+			end = len(file)
+		}
+
+		return sftpPacketData, sftpPacket{}.appendString(
+			file[offset:end],
+		)
+
+	case sftpPacketReadDir:
+		h := f.handles[string(req.readString())]
+		if h.listed {
+			return fakeSFTPStatus(sftpStatusEOF)
+		}
+		h.listed = true
+
+		var (
+			count   uint32
+			entries sftpPacket
+		)
+		for p, file := range f.files {
+			if path.Dir(p) != h.path {
+				continue
+			}
+
+			count++
+			entries = entries.appendString([]byte(path.Base(p)))
+			entries = entries.appendString([]byte(p))
+			entries = entries.appendUint32(sftpAttrSize)
+			entries = entries.appendUint64(uint64(len(file)))
+		}
+
+		names := sftpPacket{}.appendUint32(count)
+
+		return sftpPacketName, append(names, entries...)
+
+	case sftpPacketRename:
+		oldPath := string(req.readString())
+		newPath := string(req.readString())
+
+		file, ok := f.files[oldPath]
+		if !ok {
+			return fakeSFTPStatus(sftpStatusNoSuchFile)
+		}
+		delete(f.files, oldPath)
+		f.files[newPath] = file
+
+		return fakeSFTPStatus(sftpStatusOK)
+
+	case sftpPacketRemove:
+		p := string(req.readString())
+		if _, ok := f.files[p]; !ok {
+			return fakeSFTPStatus(sftpStatusNoSuchFile)
+		}
+		delete(f.files, p)
+
+		return fakeSFTPStatus(sftpStatusOK)
+
+	default:
+		// The operation is unsupported.
+		return fakeSFTPStatus(8)
+	}
+}
+
+// TestSFTPClient tests the file operations of the sftpClient.
+func TestSFTPClient(t *testing.T) {
+	t.Parallel()
+
+	clientConn, serverConn := net.Pipe()
+	t.Cleanup(func() {
+		clientConn.Close()
+		serverConn.Close()
+	})
+
+	server := newFakeSFTPServer()
+	go server.serve(serverConn)
+
+	c, err := newSFTPClient(clientConn, clientConn)
+	require.NoError(t, err)
+
+	// Write a file that spans multiple chunks.
+	data := bytes.Repeat([]byte("backup"), sftpChunkSize/2)
+	require.NoError(t, c.writeFile("/backups/file.tmp", data))
+	require.NoError(t, c.rename("/backups/file.tmp", "/backups/file"))
+
+	readData, err := c.readFile("/backups/file", maxBackupSize)
+	require.NoError(t, err)
+	require.Equal(t, data, readData)
+
+	// A file exceeding the size limit isn't read.
+	_, err = c.readFile("/backups/file", sftpChunkSize)
+	require.Error(t, err)
+
+	entries, err := c.readDir("/backups")
+	require.NoError(t, err)
+	require.Equal(t, []sftpDirEntry{{
+		name: "file",
+		size: int64(len(data)),
+	}}, entries)
+
+	require.NoError(t, c.remove("/backups/file"))
+
+	_, err = c.readFile("/backups/file", maxBackupSize)
+	require.True(t, isNoSuchFile(err))
+
+	err = c.remove("/backups/file")
+	require.True(t, isNoSuchFile(err))
+}
+
+// TestNewSFTPTarget tests that an SFTPTarget requires the host key of the
+// server and credentials.
+func TestNewSFTPTarget(t *testing.T) {
+	t.Parallel()
+
+	pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	sshPubKey, err := ssh.NewPublicKey(pubKey)
+	require.NoError(t, err)
+	hostKey := string(ssh.MarshalAuthorizedKey(sshPubKey))
+
+	cfg := SFTPTargetConfig{
+		Addr:     "localhost:22",
+		User:     "user",
+		Password: "password",
+		Dir:      "/backups",
+	}
+
+	_, err = NewSFTPTarget(cfg)
+	require.ErrorContains(t, err, "host key")
+
+	cfg.HostKey = hostKey
+	cfg.Password = ""
+	_, err = NewSFTPTarget(cfg)
+	require.ErrorContains(t, err, "password or private key")
+
+	cfg.Password = "password"
+	target, err := NewSFTPTarget(cfg)
+	require.NoError(t, err)
+	require.Equal(t, "sftp://user@localhost:22//backups", target.Name())
+}
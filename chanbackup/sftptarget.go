@@ -0,0 +1,266 @@
+package chanbackup
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// SFTPTargetConfig houses the configuration of an SFTPTarget.
+type SFTPTargetConfig struct {
+	// Addr is the host:port of the SSH server.
+	Addr string
+
+	// User is the user to log in as.
+	User string
+
+	// Password is the password of the user. Either the password or a
+	// private key must be set.
+	Password string
+
+	// PrivateKey is the PEM encoded private key of the user.
+	PrivateKey []byte
+
+	// HostKey is the public key of the SSH server in the authorized_keys
+	// format. It's required, as an unauthenticated server could otherwise
+	// pose as the target and withhold the backups.
+	HostKey string
+
+	// Dir is the remote directory the backups are stored in. It must
+	// exist.
+	Dir string
+}
+
+// SFTPTarget is a BackupTarget that stores the backup versions as files on an
+// SFTP server. A new SSH connection is established for each operation, as
+// backups are updated rarely.
+type SFTPTarget struct {
+	cfg       SFTPTargetConfig
+	sshConfig *ssh.ClientConfig
+
+	// now returns the current time. It's used to name new backup
+	// versions.
+	now func() time.Time
+}
+
+// A compile-time check to ensure SFTPTarget implements BackupTarget.
+var _ BackupTarget = (*SFTPTarget)(nil)
+
+// NewSFTPTarget creates a new SFTPTarget from the given config.
+func NewSFTPTarget(cfg SFTPTargetConfig) (*SFTPTarget, error) {
+	if cfg.HostKey == "" {
+		return nil, fmt.Errorf("sftp host key must be set")
+	}
+
+	hostKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(cfg.HostKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid sftp host key: %w", err)
+	}
+
+	var auth []ssh.AuthMethod
+	if len(cfg.PrivateKey) > 0 {
+		signer, err := ssh.ParsePrivateKey(cfg.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sftp private key: %w",
+				err)
+		}
+
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if cfg.Password != "" {
+		auth = append(auth, ssh.Password(cfg.Password))
+	}
+
+	if len(auth) == 0 {
+		return nil, fmt.Errorf("sftp password or private key must be " +
+			"set")
+	}
+
+	return &SFTPTarget{
+		cfg: cfg,
+		sshConfig: &ssh.ClientConfig{
+			User:            cfg.User,
+			Auth:            auth,
+			HostKeyCallback: ssh.FixedHostKey(hostKey),
+		},
+		now: time.Now,
+	}, nil
+}
+
+// Name returns the user, address and directory of the target.
+//
+// NOTE: This is part of the BackupTarget interface.
+func (s *SFTPTarget) Name() string {
+	return fmt.Sprintf("sftp://%s@%s/%s", s.cfg.User, s.cfg.Addr,
+		s.cfg.Dir)
+}
+
+// withClient establishes a new SFTP session and executes the given function
+// with it. The connection is closed once the context is done.
+func (s *SFTPTarget) withClient(ctx context.Context,
+	f func(c *sftpClient) error) error {
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", s.cfg.Addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// Abort any blocking read or write once the context is done.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	sshConn, chans, reqs, err := ssh.NewClientConn(
+		conn, s.cfg.Addr, s.sshConfig,
+	)
+	if err != nil {
+		return err
+	}
+	client := ssh.NewClient(sshConn, chans, reqs)
+	defer client.Close()
+
+	session, err := client.NewSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	w, err := session.StdinPipe()
+	if err != nil {
+		return err
+	}
+	r, err := session.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	if err := session.RequestSubsystem("sftp"); err != nil {
+		return fmt.Errorf("unable to start sftp subsystem: %w", err)
+	}
+
+	c, err := newSFTPClient(r, w)
+	if err != nil {
+		return err
+	}
+
+	err = f(c)
+
+	// If the context is done, the error is caused by the closed
+	// connection, so we return the more descriptive context error.
+	if ctxErr := ctx.Err(); err != nil && ctxErr != nil {
+		return ctxErr
+	}
+
+	return err
+}
+
+// Put writes the packed multi backup to a temporary file that is then renamed
+// to the name of the new version, such that a partially written file is never
+// considered a valid version.
+//
+// NOTE: This is part of the BackupTarget interface.
+func (s *SFTPTarget) Put(ctx context.Context,
+	backup PackedMulti) (*BackupVersion, error) {
+
+	now := s.now()
+	id := newBackupVersionID(now)
+	filePath := path.Join(s.cfg.Dir, id)
+	tempPath := filePath + ".tmp"
+
+	err := s.withClient(ctx, func(c *sftpClient) error {
+		if err := c.writeFile(tempPath, backup); err != nil {
+			return err
+		}
+
+		return c.rename(tempPath, filePath)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &BackupVersion{
+		ID:        id,
+		Timestamp: now,
+		Size:      int64(len(backup)),
+	}, nil
+}
+
+// Get reads the file of the given backup version.
+//
+// NOTE: This is part of the BackupTarget interface.
+func (s *SFTPTarget) Get(ctx context.Context,
+	id string) (PackedMulti, error) {
+
+	var backup []byte
+	err := s.withClient(ctx, func(c *sftpClient) error {
+		var err error
+		backup, err = c.readFile(
+			path.Join(s.cfg.Dir, id), maxBackupSize,
+		)
+
+		return err
+	})
+	if isNoSuchFile(err) {
+		return nil, fmt.Errorf("%w: %v", ErrBackupVersionNotFound, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return backup, nil
+}
+
+// List lists the files of all backup versions in the directory.
+//
+// NOTE: This is part of the BackupTarget interface.
+func (s *SFTPTarget) List(ctx context.Context) ([]BackupVersion, error) {
+	var entries []sftpDirEntry
+	err := s.withClient(ctx, func(c *sftpClient) error {
+		var err error
+		entries, err = c.readDir(s.cfg.Dir)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []BackupVersion
+	for _, entry := range entries {
+		timestamp, ok := parseBackupVersionID(entry.name)
+		if !ok {
+			continue
+		}
+
+		versions = append(versions, BackupVersion{
+			ID:        entry.name,
+			Timestamp: timestamp,
+			Size:      entry.size,
+		})
+	}
+
+	sortBackupVersions(versions)
+
+	return versions, nil
+}
+
+// Delete removes the file of the given backup version.
+//
+// NOTE: This is part of the BackupTarget interface.
+func (s *SFTPTarget) Delete(ctx context.Context, id string) error {
+	return s.withClient(ctx, func(c *sftpClient) error {
+		return c.remove(path.Join(s.cfg.Dir, id))
+	})
+}
@@ -784,6 +784,12 @@ var (
 	// ChanStatusRemoteCloseInitiator indicates that the remote node
 	// initiated closing the channel.
 	ChanStatusRemoteCloseInitiator ChannelStatus = 1 << 6
+
+	// ChanStatusRemoteDataLoss indicates that the remote party proved in
+	// channel_reestablish that it lost channel state. A revoked commitment
+	// broadcast by the remote after this is likely a stale force close
+	// rather than a deliberate breach.
+	ChanStatusRemoteDataLoss ChannelStatus = 1 << 7
 )
 
 // chanStatusStrings maps a ChannelStatus to a human friendly string that
@@ -797,6 +803,7 @@ var chanStatusStrings = map[ChannelStatus]string{
 	ChanStatusCoopBroadcasted:      "ChanStatusCoopBroadcasted",
 	ChanStatusLocalCloseInitiator:  "ChanStatusLocalCloseInitiator",
 	ChanStatusRemoteCloseInitiator: "ChanStatusRemoteCloseInitiator",
+	ChanStatusRemoteDataLoss:       "ChanStatusRemoteDataLoss",
 }
 
 // orderedChanStatusFlags is an in-order list of all that channel status flags.
@@ -808,6 +815,7 @@ var orderedChanStatusFlags = []ChannelStatus{
 	ChanStatusCoopBroadcasted,
 	ChanStatusLocalCloseInitiator,
 	ChanStatusRemoteCloseInitiator,
+	ChanStatusRemoteDataLoss,
 }
 
 // String returns a human-readable representation of the ChannelStatus.
@@ -1654,6 +1662,47 @@ func (c *OpenChannel) DataLossCommitPoint() (*btcec.PublicKey, error) {
 	return commitPoint, nil
 }
 
+// MarkRemoteDataLoss sets the channel status to RemoteDataLoss, recording
+// that the remote party proved during channel sync that it lost state.
+func (c *OpenChannel) MarkRemoteDataLoss() error {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.putChanStatus(ChanStatusRemoteDataLoss)
+}
+
+// HasRemoteDataLoss returns true if the channel was marked with
+// MarkRemoteDataLoss. Unlike HasChanStatus, the status is read from the
+// database, so it also reflects updates made through other instances of the
+// channel.
+func (c *OpenChannel) HasRemoteDataLoss() (bool, error) {
+	var remoteDataLoss bool
+	err := kvdb.View(c.Db.backend, func(tx kvdb.RTx) error {
+		chanBucket, err := fetchChanBucket(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		channel, err := fetchOpenChannel(chanBucket, &c.FundingOutpoint)
+		if err != nil {
+			return err
+		}
+
+		remoteDataLoss = channel.hasChanStatus(ChanStatusRemoteDataLoss)
+
+		return nil
+	}, func() {
+		remoteDataLoss = false
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return remoteDataLoss, nil
+}
+
 // MarkBorked marks the event when the channel as reached an irreconcilable
 // state, such as a channel breach or state desynchronization. Borked channels
 // should never be added to the switch.
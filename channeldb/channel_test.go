@@ -1262,6 +1262,35 @@ func testShutdownInfo(t *testing.T, locallyInitiated bool) {
 	})
 }
 
+// TestRemoteDataLoss asserts that marking a channel with remote data loss is
+// visible through other instances of the same channel.
+func TestRemoteDataLoss(t *testing.T) {
+	t.Parallel()
+
+	fullDB, err := MakeTestDB(t)
+	require.NoError(t, err, "unable to make test database")
+
+	cdb := fullDB.ChannelStateDB()
+
+	channel := createTestChannel(t, cdb, openChannelOption())
+
+	// Fetch a second instance of the channel, like the chain watcher
+	// would hold.
+	other, err := cdb.FetchChannel(nil, channel.FundingOutpoint)
+	require.NoError(t, err)
+
+	remoteDataLoss, err := other.HasRemoteDataLoss()
+	require.NoError(t, err)
+	require.False(t, remoteDataLoss)
+
+	require.NoError(t, channel.MarkRemoteDataLoss())
+	require.True(t, channel.HasChanStatus(ChanStatusRemoteDataLoss))
+
+	remoteDataLoss, err = other.HasRemoteDataLoss()
+	require.NoError(t, err)
+	require.True(t, remoteDataLoss)
+}
+
 // TestRefresh asserts that Refresh updates the in-memory state of another
 // OpenChannel to reflect a preceding call to MarkOpen on a different
 // OpenChannel.
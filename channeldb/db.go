@@ -335,7 +335,7 @@ type DB struct {
 	channelStateDB *ChannelStateDB
 
 	dbPath                    string
-	graph                     *ChannelGraph
+	graph                     GraphStore
 	clock                     clock.Clock
 	dryRun                    bool
 	keepFailedPaymentAttempts bool
@@ -408,15 +408,18 @@ func CreateWithBackend(backend kvdb.Backend,
 	// Set the parent pointer (only used in tests).
 	chanDB.channelStateDB.parent = chanDB
 
-	var err error
-	chanDB.graph, err = NewChannelGraph(
+	// If the graph is stored in SQL, the key-value graph is only read to
+	// migrate it, so there's no need to populate its cache.
+	useKVGraphCache := opts.UseGraphCache && opts.sqlGraph == nil
+	kvGraph, err := NewChannelGraph(
 		backend, opts.RejectCacheSize, opts.ChannelCacheSize,
 		opts.BatchCommitInterval, opts.PreAllocCacheNumNodes,
-		opts.UseGraphCache, opts.NoMigration,
+		useKVGraphCache, opts.NoMigration,
 	)
 	if err != nil {
 		return nil, err
 	}
+	chanDB.graph = kvGraph
 
 	// Synchronize the version of database and apply migrations if needed.
 	if !opts.NoMigration {
@@ -433,6 +436,25 @@ func CreateWithBackend(backend kvdb.Backend,
 		}
 	}
 
+	if opts.sqlGraph != nil {
+		err := MigrateGraphToSQL(kvGraph, opts.sqlGraph)
+		if err != nil {
+			backend.Close()
+			return nil, fmt.Errorf("unable to migrate channel "+
+				"graph to SQL: %w", err)
+		}
+
+		chanDB.graph, err = NewSQLGraph(
+			opts.sqlGraph, opts.RejectCacheSize,
+			opts.ChannelCacheSize, opts.PreAllocCacheNumNodes,
+			opts.UseGraphCache,
+		)
+		if err != nil {
+			backend.Close()
+			return nil, err
+		}
+	}
+
 	return chanDB, nil
 }
 
@@ -1639,7 +1661,7 @@ func (d *DB) applyOptionalVersions(cfg OptionalMiragtionConfig) error {
 }
 
 // ChannelGraph returns the current instance of the directed channel graph.
-func (d *DB) ChannelGraph() *ChannelGraph {
+func (d *DB) ChannelGraph() GraphStore {
 	return d.graph
 }
 
@@ -0,0 +1,521 @@
+package encrypteddb
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+const (
+	// KeySize is the size of the key that is used to encrypt the values
+	// of the database.
+	KeySize = chacha20poly1305.KeySize
+)
+
+var (
+	// metaBucket is the top level bucket that marks a database as
+	// encrypted. It is hidden from the users of the encrypted database.
+	metaBucket = []byte("encrypted-db-meta")
+
+	// checkKey is the key within the meta bucket under which an encrypted
+	// known value is stored, which allows us to detect a wrong key before
+	// any other value is accessed.
+	checkKey = []byte("key-check")
+
+	// checkValue is the plaintext of the value stored under checkKey.
+	checkValue = []byte("lnd encrypted database")
+
+	// ErrWrongKey is returned when an encrypted database is opened with a
+	// key that differs from the one it was created with.
+	ErrWrongKey = errors.New("unable to decrypt database, wrong " +
+		"encryption key")
+
+	// ErrNotEncrypted is returned when encryption is enabled for an
+	// existing database that wasn't created with encryption enabled.
+	ErrNotEncrypted = errors.New("existing database isn't encrypted " +
+		"and can't be encrypted in place")
+
+	// ErrValueTooShort is returned when a stored value is too short to be
+	// an encrypted value.
+	ErrValueTooShort = errors.New("encrypted value too short")
+)
+
+// DB is a kvdb.Backend that transparently encrypts all values stored in the
+// wrapped backend using XChaCha20-Poly1305. The keys and the bucket structure
+// are left in plaintext, as they determine the iteration order of the
+// database. Each value is authenticated together with its key, so values
+// can't be moved to a different key unnoticed.
+type DB struct {
+	kvdb.Backend
+
+	aead cipher.AEAD
+}
+
+// A compile-time check to ensure that DB implements the walletdb.BatchDB
+// interface.
+var _ walletdb.BatchDB = (*DB)(nil)
+
+// New wraps the passed backend so that all values are encrypted with the given
+// key. A new, empty database is marked as encrypted. An existing database must
+// have been created with the same key, otherwise an error is returned.
+func New(db kvdb.Backend, key []byte) (*DB, error) {
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, err
+	}
+
+	encDB := &DB{
+		Backend: db,
+		aead:    aead,
+	}
+
+	err = kvdb.Update(db, func(tx kvdb.RwTx) error {
+		meta := tx.ReadWriteBucket(metaBucket)
+		if meta != nil {
+			check, err := encDB.decrypt(
+				checkKey, meta.Get(checkKey),
+			)
+			if err != nil || !bytes.Equal(check, checkValue) {
+				return ErrWrongKey
+			}
+
+			return nil
+		}
+
+		// Only an empty database can be marked as encrypted, as the
+		// existing values would be in plaintext.
+		err := tx.ForEachBucket(func(_ []byte) error {
+			return ErrNotEncrypted
+		})
+		if err != nil {
+			return err
+		}
+
+		meta, err = tx.CreateTopLevelBucket(metaBucket)
+		if err != nil {
+			return err
+		}
+
+		check, err := encDB.encrypt(checkKey, checkValue)
+		if err != nil {
+			return err
+		}
+
+		return meta.Put(checkKey, check)
+	}, func() {})
+	if err != nil {
+		return nil, err
+	}
+
+	return encDB, nil
+}
+
+// IsEncrypted returns true if the passed backend was marked as encrypted. This
+// can be used to refuse opening an encrypted database without its key.
+func IsEncrypted(db kvdb.Backend) (bool, error) {
+	var encrypted bool
+	err := kvdb.View(db, func(tx kvdb.RTx) error {
+		encrypted = tx.ReadBucket(metaBucket) != nil
+
+		return nil
+	}, func() {
+		encrypted = false
+	})
+
+	return encrypted, err
+}
+
+// encrypt encrypts the value stored under the given key. The result is the
+// random nonce followed by the ciphertext.
+func (d *DB) encrypt(key, value []byte) ([]byte, error) {
+	nonceSize := d.aead.NonceSize()
+	ciphertext := make(
+		[]byte, nonceSize, nonceSize+len(value)+d.aead.Overhead(),
+	)
+	if _, err := rand.Read(ciphertext); err != nil {
+		return nil, err
+	}
+
+	return d.aead.Seal(ciphertext, ciphertext, value, key), nil
+}
+
+// decrypt decrypts the value stored under the given key. Nil values denote
+// nested buckets and are returned as is.
+func (d *DB) decrypt(key, value []byte) ([]byte, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	nonceSize := d.aead.NonceSize()
+	if len(value) < nonceSize+d.aead.Overhead() {
+		return nil, ErrValueTooShort
+	}
+
+	plaintext, err := d.aead.Open(
+		nil, value[:nonceSize], value[nonceSize:], key,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Empty values must stay distinguishable from nested buckets.
+	if plaintext == nil {
+		plaintext = []byte{}
+	}
+
+	return plaintext, nil
+}
+
+// mustDecrypt decrypts the value stored under the given key for the methods
+// that can't return an error. As the key is verified when the database is
+// opened, a failure means the database was corrupted or tampered with. In
+// that case the failure is logged and the value is treated as non-existent.
+func (d *DB) mustDecrypt(key, value []byte) []byte {
+	plaintext, err := d.decrypt(key, value)
+	if err != nil {
+		log.Errorf("Unable to decrypt value of key %x: %v", key, err)
+
+		return nil
+	}
+
+	return plaintext
+}
+
+// BeginReadTx opens a database read transaction.
+func (d *DB) BeginReadTx() (kvdb.RTx, error) {
+	tx, err := d.Backend.BeginReadTx()
+	if err != nil {
+		return nil, err
+	}
+
+	return &readTx{RTx: tx, db: d}, nil
+}
+
+// BeginReadWriteTx opens a database read+write transaction.
+func (d *DB) BeginReadWriteTx() (kvdb.RwTx, error) {
+	tx, err := d.Backend.BeginReadWriteTx()
+	if err != nil {
+		return nil, err
+	}
+
+	return &rwTx{RwTx: tx, db: d}, nil
+}
+
+// View opens a database read transaction and executes the function f with the
+// transaction passed as a parameter.
+func (d *DB) View(f func(tx kvdb.RTx) error, reset func()) error {
+	return d.Backend.View(func(tx kvdb.RTx) error {
+		return f(&readTx{RTx: tx, db: d})
+	}, reset)
+}
+
+// Update opens a database read/write transaction and executes the function f
+// with the transaction passed as a parameter.
+func (d *DB) Update(f func(tx kvdb.RwTx) error, reset func()) error {
+	return d.Backend.Update(func(tx kvdb.RwTx) error {
+		return f(&rwTx{RwTx: tx, db: d})
+	}, reset)
+}
+
+// Batch is similar to Update, but it allows the wrapped backend to coalesce
+// multiple calls into a single transaction if it supports doing so.
+func (d *DB) Batch(f func(tx kvdb.RwTx) error) error {
+	return kvdb.Batch(d.Backend, func(tx kvdb.RwTx) error {
+		return f(&rwTx{RwTx: tx, db: d})
+	})
+}
+
+// readTx wraps a read transaction of the underlying backend.
+type readTx struct {
+	kvdb.RTx
+
+	db *DB
+}
+
+// ReadBucket opens the root bucket for read only access. If the bucket
+// described by the key does not exist, nil is returned.
+func (t *readTx) ReadBucket(key []byte) kvdb.RBucket {
+	return wrapReadBucket(t.RTx.ReadBucket(key), t.db)
+}
+
+// ForEachBucket iterates through all top level buckets, skipping the bucket
+// that marks the database as encrypted.
+func (t *readTx) ForEachBucket(f func(key []byte) error) error {
+	return forEachBucket(t.RTx, f)
+}
+
+// rwTx wraps a read/write transaction of the underlying backend.
+type rwTx struct {
+	kvdb.RwTx
+
+	db *DB
+}
+
+// ReadBucket opens the root bucket for read only access. If the bucket
+// described by the key does not exist, nil is returned.
+func (t *rwTx) ReadBucket(key []byte) kvdb.RBucket {
+	return wrapReadBucket(t.RwTx.ReadBucket(key), t.db)
+}
+
+// ForEachBucket iterates through all top level buckets, skipping the bucket
+// that marks the database as encrypted.
+func (t *rwTx) ForEachBucket(f func(key []byte) error) error {
+	return forEachBucket(t.RwTx, f)
+}
+
+// ReadWriteBucket opens the root bucket for read/write access. If the bucket
+// described by the key does not exist, nil is returned.
+func (t *rwTx) ReadWriteBucket(key []byte) kvdb.RwBucket {
+	return wrapRwBucket(t.RwTx.ReadWriteBucket(key), t.db)
+}
+
+// CreateTopLevelBucket creates the top level bucket for a key if it does not
+// exist.
+func (t *rwTx) CreateTopLevelBucket(key []byte) (kvdb.RwBucket, error) {
+	bucket, err := t.RwTx.CreateTopLevelBucket(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return wrapRwBucket(bucket, t.db), nil
+}
+
+// forEachBucket iterates through all top level buckets of the transaction,
+// skipping the bucket that marks the database as encrypted.
+func forEachBucket(tx kvdb.RTx, f func(key []byte) error) error {
+	return tx.ForEachBucket(func(key []byte) error {
+		if bytes.Equal(key, metaBucket) {
+			return nil
+		}
+
+		return f(key)
+	})
+}
+
+// readBucket wraps a read only bucket of the underlying backend.
+type readBucket struct {
+	kvdb.RBucket
+
+	db *DB
+}
+
+// wrapReadBucket wraps the passed bucket, making sure a nil bucket results in
+// a nil interface.
+func wrapReadBucket(bucket kvdb.RBucket, db *DB) kvdb.RBucket {
+	if bucket == nil {
+		return nil
+	}
+
+	return &readBucket{RBucket: bucket, db: db}
+}
+
+// NestedReadBucket retrieves a nested bucket with the given key. Returns nil
+// if the bucket does not exist.
+func (b *readBucket) NestedReadBucket(key []byte) kvdb.RBucket {
+	return wrapReadBucket(b.RBucket.NestedReadBucket(key), b.db)
+}
+
+// ForEach invokes the passed function with every key/value pair in the
+// bucket, with the values decrypted.
+func (b *readBucket) ForEach(f func(k, v []byte) error) error {
+	return forEach(b.RBucket, b.db, f)
+}
+
+// Get returns the decrypted value for the given key. Returns nil if the key
+// does not exist in this bucket.
+func (b *readBucket) Get(key []byte) []byte {
+	return b.db.mustDecrypt(key, b.RBucket.Get(key))
+}
+
+// ReadCursor returns a new read-only cursor for this bucket.
+func (b *readBucket) ReadCursor() kvdb.RCursor {
+	return &readCursor{RCursor: b.RBucket.ReadCursor(), db: b.db}
+}
+
+// rwBucket wraps a read/write bucket of the underlying backend.
+type rwBucket struct {
+	kvdb.RwBucket
+
+	db *DB
+}
+
+// wrapRwBucket wraps the passed bucket, making sure a nil bucket results in a
+// nil interface.
+func wrapRwBucket(bucket kvdb.RwBucket, db *DB) kvdb.RwBucket {
+	if bucket == nil {
+		return nil
+	}
+
+	return &rwBucket{RwBucket: bucket, db: db}
+}
+
+// NestedReadBucket retrieves a nested bucket with the given key. Returns nil
+// if the bucket does not exist.
+func (b *rwBucket) NestedReadBucket(key []byte) kvdb.RBucket {
+	return wrapReadBucket(b.RwBucket.NestedReadBucket(key), b.db)
+}
+
+// NestedReadWriteBucket retrieves a nested bucket with the given key. Returns
+// nil if the bucket does not exist.
+func (b *rwBucket) NestedReadWriteBucket(key []byte) kvdb.RwBucket {
+	return wrapRwBucket(b.RwBucket.NestedReadWriteBucket(key), b.db)
+}
+
+// CreateBucket creates and returns a new nested bucket with the given key.
+func (b *rwBucket) CreateBucket(key []byte) (kvdb.RwBucket, error) {
+	bucket, err := b.RwBucket.CreateBucket(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return wrapRwBucket(bucket, b.db), nil
+}
+
+// CreateBucketIfNotExists creates and returns a new nested bucket with the
+// given key if it does not already exist.
+func (b *rwBucket) CreateBucketIfNotExists(key []byte) (kvdb.RwBucket,
+	error) {
+
+	bucket, err := b.RwBucket.CreateBucketIfNotExists(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return wrapRwBucket(bucket, b.db), nil
+}
+
+// ForEach invokes the passed function with every key/value pair in the
+// bucket, with the values decrypted.
+func (b *rwBucket) ForEach(f func(k, v []byte) error) error {
+	return forEach(b.RwBucket, b.db, f)
+}
+
+// Get returns the decrypted value for the given key. Returns nil if the key
+// does not exist in this bucket.
+func (b *rwBucket) Get(key []byte) []byte {
+	return b.db.mustDecrypt(key, b.RwBucket.Get(key))
+}
+
+// Put encrypts the value and saves it under the specified key.
+func (b *rwBucket) Put(key, value []byte) error {
+	ciphertext, err := b.db.encrypt(key, value)
+	if err != nil {
+		return err
+	}
+
+	return b.RwBucket.Put(key, ciphertext)
+}
+
+// ReadCursor returns a new read-only cursor for this bucket.
+func (b *rwBucket) ReadCursor() kvdb.RCursor {
+	return &readCursor{RCursor: b.RwBucket.ReadCursor(), db: b.db}
+}
+
+// ReadWriteCursor returns a new read/write cursor for this bucket.
+func (b *rwBucket) ReadWriteCursor() kvdb.RwCursor {
+	return &rwCursor{RwCursor: b.RwBucket.ReadWriteCursor(), db: b.db}
+}
+
+// Tx returns the bucket's transaction.
+func (b *rwBucket) Tx() kvdb.RwTx {
+	return &rwTx{RwTx: b.RwBucket.Tx(), db: b.db}
+}
+
+// forEach invokes the passed function with every key/value pair in the
+// bucket, with the values decrypted.
+func forEach(bucket kvdb.RBucket, db *DB, f func(k, v []byte) error) error {
+	return bucket.ForEach(func(k, v []byte) error {
+		plaintext, err := db.decrypt(k, v)
+		if err != nil {
+			return fmt.Errorf("unable to decrypt value of key "+
+				"%x: %w", k, err)
+		}
+
+		return f(k, plaintext)
+	})
+}
+
+// readCursor wraps a read-only cursor of the underlying backend.
+type readCursor struct {
+	kvdb.RCursor
+
+	db *DB
+}
+
+// First positions the cursor at the first key/value pair and returns the
+// pair.
+func (c *readCursor) First() ([]byte, []byte) {
+	return c.db.decryptPair(c.RCursor.First())
+}
+
+// Last positions the cursor at the last key/value pair and returns the pair.
+func (c *readCursor) Last() ([]byte, []byte) {
+	return c.db.decryptPair(c.RCursor.Last())
+}
+
+// Next moves the cursor one key/value pair forward and returns the new pair.
+func (c *readCursor) Next() ([]byte, []byte) {
+	return c.db.decryptPair(c.RCursor.Next())
+}
+
+// Prev moves the cursor one key/value pair backward and returns the new pair.
+func (c *readCursor) Prev() ([]byte, []byte) {
+	return c.db.decryptPair(c.RCursor.Prev())
+}
+
+// Seek positions the cursor at the passed seek key. If the key does not
+// exist, the cursor is moved to the next key after seek. Returns the new
+// pair.
+func (c *readCursor) Seek(seek []byte) ([]byte, []byte) {
+	return c.db.decryptPair(c.RCursor.Seek(seek))
+}
+
+// rwCursor wraps a read/write cursor of the underlying backend.
+type rwCursor struct {
+	kvdb.RwCursor
+
+	db *DB
+}
+
+// First positions the cursor at the first key/value pair and returns the
+// pair.
+func (c *rwCursor) First() ([]byte, []byte) {
+	return c.db.decryptPair(c.RwCursor.First())
+}
+
+// Last positions the cursor at the last key/value pair and returns the pair.
+func (c *rwCursor) Last() ([]byte, []byte) {
+	return c.db.decryptPair(c.RwCursor.Last())
+}
+
+// Next moves the cursor one key/value pair forward and returns the new pair.
+func (c *rwCursor) Next() ([]byte, []byte) {
+	return c.db.decryptPair(c.RwCursor.Next())
+}
+
+// Prev moves the cursor one key/value pair backward and returns the new pair.
+func (c *rwCursor) Prev() ([]byte, []byte) {
+	return c.db.decryptPair(c.RwCursor.Prev())
+}
+
+// Seek positions the cursor at the passed seek key. If the key does not
+// exist, the cursor is moved to the next key after seek. Returns the new
+// pair.
+func (c *rwCursor) Seek(seek []byte) ([]byte, []byte) {
+	return c.db.decryptPair(c.RwCursor.Seek(seek))
+}
+
+// decryptPair decrypts the value of a key/value pair returned by a cursor.
+func (d *DB) decryptPair(key, value []byte) ([]byte, []byte) {
+	if key == nil {
+		return nil, nil
+	}
+
+	return key, d.mustDecrypt(key, value)
+}
@@ -0,0 +1,152 @@
+package encrypteddb
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestBackend creates a new bolt backend in a temporary directory.
+func newTestBackend(t *testing.T, name string) kvdb.Backend {
+	db, err := kvdb.Create(
+		kvdb.BoltBackendName, filepath.Join(t.TempDir(), name), true,
+		kvdb.DefaultDBTimeout,
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	return db
+}
+
+// TestEncryptedDB tests that values are transparently encrypted and decrypted,
+// and that they're not readable from the underlying backend.
+func TestEncryptedDB(t *testing.T) {
+	t.Parallel()
+
+	var (
+		key         = bytes.Repeat([]byte{1}, KeySize)
+		bucketKey   = []byte("bucket")
+		nestedKey   = []byte("nested")
+		valueKey    = []byte("key")
+		value       = []byte("secret value")
+		emptyKey    = []byte("empty")
+		sequenceVal = uint64(42)
+	)
+
+	backend := newTestBackend(t, "encrypted.db")
+
+	db, err := New(backend, key)
+	require.NoError(t, err)
+
+	err = kvdb.Update(db, func(tx kvdb.RwTx) error {
+		bucket, err := tx.CreateTopLevelBucket(bucketKey)
+		if err != nil {
+			return err
+		}
+
+		nested, err := bucket.CreateBucketIfNotExists(nestedKey)
+		if err != nil {
+			return err
+		}
+
+		if err := bucket.SetSequence(sequenceVal); err != nil {
+			return err
+		}
+
+		if err := bucket.Put(emptyKey, nil); err != nil {
+			return err
+		}
+
+		return nested.Put(valueKey, value)
+	}, func() {})
+	require.NoError(t, err)
+
+	err = kvdb.View(db, func(tx kvdb.RTx) error {
+		// The meta bucket should be hidden.
+		var buckets [][]byte
+		err := tx.ForEachBucket(func(k []byte) error {
+			buckets = append(buckets, k)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, [][]byte{bucketKey}, buckets)
+
+		bucket := tx.ReadBucket(bucketKey)
+		require.NotNil(t, bucket)
+		require.Nil(t, tx.ReadBucket([]byte("unknown")))
+
+		// Empty values must stay distinguishable from nested
+		// buckets.
+		values := make(map[string][]byte)
+		err = bucket.ForEach(func(k, v []byte) error {
+			values[string(k)] = v
+			return nil
+		})
+		require.NoError(t, err)
+		require.Nil(t, values[string(nestedKey)])
+		require.NotNil(t, values[string(emptyKey)])
+		require.Empty(t, values[string(emptyKey)])
+
+		nested := bucket.NestedReadBucket(nestedKey)
+		require.Equal(t, value, nested.Get(valueKey))
+
+		k, v := nested.ReadCursor().First()
+		require.Equal(t, valueKey, k)
+		require.Equal(t, value, v)
+
+		return nil
+	}, func() {})
+	require.NoError(t, err)
+
+	// The value shouldn't be readable from the underlying backend. Bucket
+	// sequences are only exposed by read-write buckets, so we use an
+	// update transaction even though nothing is written.
+	err = kvdb.Update(backend, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(bucketKey)
+		require.Equal(t, sequenceVal, bucket.Sequence())
+
+		stored := bucket.NestedReadBucket(nestedKey).Get(valueKey)
+		require.NotContains(t, string(stored), string(value))
+
+		return nil
+	}, func() {})
+	require.NoError(t, err)
+
+	encrypted, err := IsEncrypted(backend)
+	require.NoError(t, err)
+	require.True(t, encrypted)
+
+	// Reopening the database requires the same key.
+	_, err = New(backend, key)
+	require.NoError(t, err)
+
+	wrongKey := bytes.Repeat([]byte{2}, KeySize)
+	_, err = New(backend, wrongKey)
+	require.ErrorIs(t, err, ErrWrongKey)
+}
+
+// TestEncryptedDBExistingPlaintext tests that an existing unencrypted database
+// can't be opened with encryption enabled.
+func TestEncryptedDBExistingPlaintext(t *testing.T) {
+	t.Parallel()
+
+	backend := newTestBackend(t, "plaintext.db")
+
+	err := kvdb.Update(backend, func(tx kvdb.RwTx) error {
+		_, err := tx.CreateTopLevelBucket([]byte("bucket"))
+		return err
+	}, func() {})
+	require.NoError(t, err)
+
+	encrypted, err := IsEncrypted(backend)
+	require.NoError(t, err)
+	require.False(t, encrypted)
+
+	_, err = New(backend, bytes.Repeat([]byte{1}, KeySize))
+	require.ErrorIs(t, err, ErrNotEncrypted)
+}
@@ -0,0 +1,2775 @@
+package channeldb
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"image/color"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/aliasmgr"
+	"github.com/lightningnetwork/lnd/batch"
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/sqldb"
+	"github.com/lightningnetwork/lnd/sqldb/sqlc"
+)
+
+// sqlGraphPageSize is the number of nodes or channels that are loaded within
+// a single transaction when iterating over the whole graph.
+const sqlGraphPageSize = 1000
+
+// SQLGraphQueries is an interface that defines the set of operations that can
+// be executed against the channel graph SQL database.
+type SQLGraphQueries interface {
+	UpsertGraphNode(ctx context.Context,
+		arg sqlc.UpsertGraphNodeParams) (int64, error)
+
+	InsertGraphShellNode(ctx context.Context, pubKey []byte) error
+
+	GetGraphNode(ctx context.Context, pubKey []byte) (sqlc.GraphNode,
+		error)
+
+	ListGraphNodes(ctx context.Context,
+		arg sqlc.ListGraphNodesParams) ([]sqlc.GraphNode, error)
+
+	ListGraphNodesInHorizon(ctx context.Context,
+		arg sqlc.ListGraphNodesInHorizonParams) ([]sqlc.GraphNode,
+		error)
+
+	ListGraphNodesWithoutChannels(ctx context.Context) ([][]byte, error)
+
+	CountGraphNodes(ctx context.Context) (int64, error)
+
+	DeleteGraphNode(ctx context.Context, pubKey []byte) (sql.Result, error)
+
+	UpsertGraphSourceNode(ctx context.Context, pubKey []byte) error
+
+	GetGraphSourceNode(ctx context.Context) ([]byte, error)
+
+	InsertGraphChannel(ctx context.Context,
+		arg sqlc.InsertGraphChannelParams) (int64, error)
+
+	UpdateGraphChannel(ctx context.Context,
+		arg sqlc.UpdateGraphChannelParams) (sql.Result, error)
+
+	GetGraphChannel(ctx context.Context, scid []byte) (sqlc.GraphChannel,
+		error)
+
+	GetGraphChannelByOutpoint(ctx context.Context,
+		outpoint string) (sqlc.GraphChannel, error)
+
+	ListGraphChannels(ctx context.Context,
+		arg sqlc.ListGraphChannelsParams) ([]sqlc.GraphChannel, error)
+
+	ListGraphNodeChannels(ctx context.Context,
+		nodeKey []byte) ([]sqlc.GraphChannel, error)
+
+	ListGraphChannelsInSCIDRange(ctx context.Context,
+		arg sqlc.ListGraphChannelsInSCIDRangeParams) (
+		[]sqlc.GraphChannel, error)
+
+	ListGraphChannelsInHorizon(ctx context.Context,
+		arg sqlc.ListGraphChannelsInHorizonParams) (
+		[]sqlc.GraphChannel, error)
+
+	ListGraphDisabledChannels(ctx context.Context) ([][]byte, error)
+
+	GetGraphHighestSCID(ctx context.Context) ([]byte, error)
+
+	DeleteGraphChannel(ctx context.Context, scid []byte) (sql.Result,
+		error)
+
+	UpsertGraphChannelPolicy(ctx context.Context,
+		arg sqlc.UpsertGraphChannelPolicyParams) error
+
+	ListGraphChannelPolicies(ctx context.Context,
+		arg sqlc.ListGraphChannelPoliciesParams) (
+		[]sqlc.GraphChannelPolicy, error)
+
+	ListGraphChannelPoliciesInHorizon(ctx context.Context,
+		arg sqlc.ListGraphChannelPoliciesInHorizonParams) (
+		[]sqlc.GraphChannelPolicy, error)
+
+	ListGraphChannelPoliciesInSCIDRange(ctx context.Context,
+		arg sqlc.ListGraphChannelPoliciesInSCIDRangeParams) (
+		[]sqlc.GraphChannelPolicy, error)
+
+	ListGraphNodeChannelPolicies(ctx context.Context,
+		nodeKey []byte) ([]sqlc.GraphChannelPolicy, error)
+
+	UpsertGraphZombieChannel(ctx context.Context,
+		arg sqlc.UpsertGraphZombieChannelParams) error
+
+	GetGraphZombieChannel(ctx context.Context,
+		scid []byte) (sqlc.GraphZombieChannel, error)
+
+	DeleteGraphZombieChannel(ctx context.Context, scid []byte) (sql.Result,
+		error)
+
+	CountGraphZombieChannels(ctx context.Context) (int64, error)
+
+	InsertGraphClosedSCID(ctx context.Context, scid []byte) error
+
+	IsGraphClosedSCID(ctx context.Context, scid []byte) (bool, error)
+
+	UpsertGraphPruneLogEntry(ctx context.Context,
+		arg sqlc.UpsertGraphPruneLogEntryParams) error
+
+	GetGraphPruneTip(ctx context.Context) (sqlc.GraphPruneLog, error)
+
+	DeleteGraphPruneLogEntriesFrom(ctx context.Context,
+		blockHeight int32) error
+}
+
+// SQLGraphQueriesTxOptions defines the set of db txn options the
+// SQLGraphQueries understands.
+type SQLGraphQueriesTxOptions struct {
+	// readOnly governs if a read only transaction is needed or not.
+	readOnly bool
+}
+
+// ReadOnly returns true if the transaction should be read only.
+//
+// NOTE: This implements the TxOptions.
+func (a *SQLGraphQueriesTxOptions) ReadOnly() bool {
+	return a.readOnly
+}
+
+// NewSQLGraphQueryReadTx creates a new read transaction option set.
+func NewSQLGraphQueryReadTx() SQLGraphQueriesTxOptions {
+	return SQLGraphQueriesTxOptions{
+		readOnly: true,
+	}
+}
+
+// BatchedSQLGraphQueries is a version of the SQLGraphQueries that's capable of
+// batched database operations.
+type BatchedSQLGraphQueries interface {
+	SQLGraphQueries
+
+	sqldb.BatchedTx[SQLGraphQueries]
+}
+
+// SQLGraph is a native SQL implementation of the channel graph. It keeps the
+// same caches as the ChannelGraph, but stores nodes, channels and policies in
+// their own tables, so that they can be queried through indexes rather than
+// by scanning buckets.
+//
+// NOTE: The SQL graph doesn't hand out key-value transactions. The callbacks
+// of the iteration methods are invoked with a nil transaction outside of any
+// database transaction, and transactions passed to it are ignored.
+type SQLGraph struct {
+	db BatchedSQLGraphQueries
+
+	// cacheMu guards all caches (rejectCache, chanCache, graphCache).
+	cacheMu     sync.RWMutex
+	rejectCache *rejectCache
+	chanCache   *channelCache
+	graphCache  *GraphCache
+}
+
+// NewSQLGraph creates a new SQLGraph instance given an open
+// BatchedSQLGraphQueries storage backend. If the graph cache is used, it's
+// populated from the database before the graph is returned.
+func NewSQLGraph(db BatchedSQLGraphQueries, rejectCacheSize, chanCacheSize,
+	preAllocCacheNumNodes int, useGraphCache bool) (*SQLGraph, error) {
+
+	g := &SQLGraph{
+		db:          db,
+		rejectCache: newRejectCache(rejectCacheSize),
+		chanCache:   newChannelCache(chanCacheSize),
+	}
+
+	if !useGraphCache {
+		return g, nil
+	}
+
+	graphCache := NewGraphCache(preAllocCacheNumNodes)
+	startTime := time.Now()
+	log.Debugf("Populating in-memory channel graph from SQL, this might " +
+		"take a while...")
+
+	err := g.ForEachNode(func(_ kvdb.RTx, node *LightningNode) error {
+		graphCache.AddNodeFeatures(
+			newGraphCacheNode(node.PubKeyBytes, node.Features),
+		)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = g.ForEachChannel(func(info *models.ChannelEdgeInfo,
+		policy1, policy2 *models.ChannelEdgePolicy) error {
+
+		graphCache.AddChannel(info, policy1, policy2)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Finished populating in-memory channel graph (took %v, %s)",
+		time.Since(startTime), graphCache.Stats())
+
+	g.graphCache = graphCache
+
+	return g, nil
+}
+
+// NewPathFindTx returns a new read transaction that can be used for a single
+// path finding session. The SQL graph doesn't use key-value transactions, so
+// nil is always returned.
+func (s *SQLGraph) NewPathFindTx() (kvdb.RTx, error) {
+	return nil, nil
+}
+
+// ForEachChannel iterates through all the channel edges stored within the
+// graph and invokes the passed callback for each edge. The channels are loaded
+// in pages, so the callback isn't executed within a database transaction.
+//
+// NOTE: If an edge can't be found, or wasn't advertised, then a nil pointer
+// for that particular channel edge routing policy will be passed into the
+// callback.
+func (s *SQLGraph) ForEachChannel(cb func(*models.ChannelEdgeInfo,
+	*models.ChannelEdgePolicy, *models.ChannelEdgePolicy) error) error {
+
+	var (
+		ctx        = context.TODO()
+		readTxOpts = NewSQLGraphQueryReadTx()
+		lastID     int64
+	)
+
+	for {
+		var (
+			channels []ChannelEdge
+			pageEnd  int64
+		)
+		err := s.db.ExecTx(ctx, &readTxOpts, func(
+			db SQLGraphQueries) error {
+
+			dbChans, err := db.ListGraphChannels(
+				ctx, sqlc.ListGraphChannelsParams{
+					AfterID:  lastID,
+					NumLimit: sqlGraphPageSize,
+				},
+			)
+			if err != nil || len(dbChans) == 0 {
+				return err
+			}
+
+			// The channels are ordered by their id, so we can load
+			// the policies of the whole page at once.
+			policies, err := db.ListGraphChannelPolicies(
+				ctx, sqlc.ListGraphChannelPoliciesParams{
+					StartID: dbChans[0].ID,
+					EndID:   dbChans[len(dbChans)-1].ID,
+				},
+			)
+			if err != nil {
+				return err
+			}
+
+			channels, err = unmarshalChannelEdges(dbChans, policies)
+			if err != nil {
+				return err
+			}
+			pageEnd = dbChans[len(dbChans)-1].ID
+
+			return nil
+		}, func() {
+			channels = nil
+			pageEnd = 0
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, channel := range channels {
+			err := cb(
+				channel.Info, channel.Policy1, channel.Policy2,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(channels) < sqlGraphPageSize {
+			return nil
+		}
+		lastID = pageEnd
+	}
+}
+
+// ForEachNodeDirectedChannel iterates through all channels of a given node,
+// executing the passed callback on the directed edge representing the channel
+// and its incoming policy. If the callback returns an error, then the iteration
+// is halted with the error propagated back up to the caller.
+//
+// Unknown policies are passed into the callback as nil values.
+func (s *SQLGraph) ForEachNodeDirectedChannel(_ kvdb.RTx,
+	node route.Vertex, cb func(channel *DirectedChannel) error) error {
+
+	if s.graphCache != nil {
+		return s.graphCache.ForEachChannel(node, cb)
+	}
+
+	// Fallback that uses the database.
+	toNodeCallback := func() route.Vertex {
+		return node
+	}
+	toNodeFeatures, err := s.FetchNodeFeatures(node)
+	if err != nil {
+		return err
+	}
+
+	dbCallback := func(_ kvdb.RTx, e *models.ChannelEdgeInfo, p1,
+		p2 *models.ChannelEdgePolicy) error {
+
+		var cachedInPolicy *models.CachedEdgePolicy
+		if p2 != nil {
+			cachedInPolicy = models.NewCachedPolicy(p2)
+			cachedInPolicy.ToNodePubKey = toNodeCallback
+			cachedInPolicy.ToNodeFeatures = toNodeFeatures
+		}
+
+		var inboundFee lnwire.Fee
+		if p1 != nil {
+			// Extract inbound fee. If there is a decoding error,
+			// skip this edge.
+			_, err := p1.ExtraOpaqueData.ExtractRecords(&inboundFee)
+			if err != nil {
+				return nil
+			}
+		}
+
+		directedChannel := &DirectedChannel{
+			ChannelID:    e.ChannelID,
+			IsNode1:      node == e.NodeKey1Bytes,
+			OtherNode:    e.NodeKey2Bytes,
+			Capacity:     e.Capacity,
+			OutPolicySet: p1 != nil,
+			InPolicy:     cachedInPolicy,
+			InboundFee:   inboundFee,
+		}
+
+		if node == e.NodeKey2Bytes {
+			directedChannel.OtherNode = e.NodeKey1Bytes
+		}
+
+		return cb(directedChannel)
+	}
+
+	return s.ForEachNodeChannel(node, dbCallback)
+}
+
+// FetchNodeFeatures returns the features of a given node. If no features are
+// known for the node, an empty feature vector is returned.
+func (s *SQLGraph) FetchNodeFeatures(
+	node route.Vertex) (*lnwire.FeatureVector, error) {
+
+	if s.graphCache != nil {
+		return s.graphCache.GetFeatures(node), nil
+	}
+
+	// Fallback that uses the database.
+	targetNode, err := s.FetchLightningNode(node)
+	switch {
+	// If the node exists and has features, return them directly.
+	case err == nil:
+		return targetNode.Features, nil
+
+	// If we couldn't find a node announcement, populate a blank feature
+	// vector.
+	case errors.Is(err, ErrGraphNodeNotFound):
+		return lnwire.EmptyFeatureVector(), nil
+
+	// Otherwise, bubble the error up.
+	default:
+		return nil, err
+	}
+}
+
+// ForEachNodeCached is similar to ForEachNode, but it utilizes the channel
+// graph cache instead. Note that this doesn't return all the information the
+// regular ForEachNode method does.
+//
+// NOTE: The callback contents MUST not be modified.
+func (s *SQLGraph) ForEachNodeCached(cb func(node route.Vertex,
+	chans map[uint64]*DirectedChannel) error) error {
+
+	if s.graphCache != nil {
+		return s.graphCache.ForEachNode(cb)
+	}
+
+	// Otherwise call back to a version that uses the database directly.
+	return s.ForEachNode(func(_ kvdb.RTx, node *LightningNode) error {
+		channels := make(map[uint64]*DirectedChannel)
+
+		toNodeCallback := func() route.Vertex {
+			return node.PubKeyBytes
+		}
+		toNodeFeatures, err := s.FetchNodeFeatures(node.PubKeyBytes)
+		if err != nil {
+			return err
+		}
+
+		err = s.ForEachNodeChannel(node.PubKeyBytes,
+			func(_ kvdb.RTx, e *models.ChannelEdgeInfo,
+				p1 *models.ChannelEdgePolicy,
+				p2 *models.ChannelEdgePolicy) error {
+
+				var cachedInPolicy *models.CachedEdgePolicy
+				if p2 != nil {
+					cachedInPolicy =
+						models.NewCachedPolicy(p2)
+					cachedInPolicy.ToNodePubKey =
+						toNodeCallback
+					cachedInPolicy.ToNodeFeatures =
+						toNodeFeatures
+				}
+
+				directedChannel := &DirectedChannel{
+					ChannelID: e.ChannelID,
+					IsNode1: node.PubKeyBytes ==
+						e.NodeKey1Bytes,
+					OtherNode:    e.NodeKey2Bytes,
+					Capacity:     e.Capacity,
+					OutPolicySet: p1 != nil,
+					InPolicy:     cachedInPolicy,
+				}
+
+				if node.PubKeyBytes == e.NodeKey2Bytes {
+					directedChannel.OtherNode =
+						e.NodeKey1Bytes
+				}
+
+				channels[e.ChannelID] = directedChannel
+
+				return nil
+			})
+		if err != nil {
+			return err
+		}
+
+		return cb(node.PubKeyBytes, channels)
+	})
+}
+
+// DisabledChannelIDs returns the channel ids of disabled channels. A channel
+// is disabled when the policies of both its directions have their disabled bit
+// on.
+func (s *SQLGraph) DisabledChannelIDs() ([]uint64, error) {
+	var (
+		ctx             = context.TODO()
+		readTxOpts      = NewSQLGraphQueryReadTx()
+		disabledChanIDs []uint64
+	)
+
+	err := s.db.ExecTx(ctx, &readTxOpts, func(db SQLGraphQueries) error {
+		scids, err := db.ListGraphDisabledChannels(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, scid := range scids {
+			disabledChanIDs = append(
+				disabledChanIDs, byteOrder.Uint64(scid),
+			)
+		}
+
+		return nil
+	}, func() {
+		disabledChanIDs = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return disabledChanIDs, nil
+}
+
+// ForEachNode iterates through all the stored vertices/nodes in the graph,
+// executing the passed callback with each node encountered. The nodes are
+// loaded in pages, so the callback isn't executed within a database
+// transaction.
+func (s *SQLGraph) ForEachNode(cb func(kvdb.RTx, *LightningNode) error) error {
+	var (
+		ctx        = context.TODO()
+		readTxOpts = NewSQLGraphQueryReadTx()
+		lastID     int64
+	)
+
+	for {
+		var (
+			nodes   []*LightningNode
+			pageEnd int64
+		)
+		err := s.db.ExecTx(ctx, &readTxOpts, func(
+			db SQLGraphQueries) error {
+
+			dbNodes, err := db.ListGraphNodes(
+				ctx, sqlc.ListGraphNodesParams{
+					AfterID:  lastID,
+					NumLimit: sqlGraphPageSize,
+				},
+			)
+			if err != nil {
+				return err
+			}
+
+			for _, dbNode := range dbNodes {
+				node, err := unmarshalGraphNode(dbNode)
+				if err != nil {
+					return err
+				}
+
+				nodes = append(nodes, node)
+				pageEnd = dbNode.ID
+			}
+
+			return nil
+		}, func() {
+			nodes = nil
+			pageEnd = 0
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, node := range nodes {
+			if err := cb(nil, node); err != nil {
+				return err
+			}
+		}
+
+		if len(nodes) < sqlGraphPageSize {
+			return nil
+		}
+		lastID = pageEnd
+	}
+}
+
+// SourceNode returns the source node of the graph. The source node is treated
+// as the center node within a star-graph.
+func (s *SQLGraph) SourceNode() (*LightningNode, error) {
+	var (
+		ctx        = context.TODO()
+		readTxOpts = NewSQLGraphQueryReadTx()
+		source     *LightningNode
+	)
+
+	err := s.db.ExecTx(ctx, &readTxOpts, func(db SQLGraphQueries) error {
+		pubKey, err := db.GetGraphSourceNode(ctx)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrSourceNodeNotSet
+
+		case err != nil:
+			return err
+		}
+
+		source, err = fetchSQLGraphNode(ctx, db, pubKey)
+
+		return err
+	}, func() {
+		source = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return source, nil
+}
+
+// SetSourceNode sets the source node within the graph database. The node
+// itself is added to the graph as well.
+func (s *SQLGraph) SetSourceNode(node *LightningNode) error {
+	var (
+		ctx         = context.TODO()
+		writeTxOpts SQLGraphQueriesTxOptions
+	)
+
+	params, err := marshalGraphNode(node)
+	if err != nil {
+		return err
+	}
+
+	return s.db.ExecTx(ctx, &writeTxOpts, func(db SQLGraphQueries) error {
+		if _, err := db.UpsertGraphNode(ctx, params); err != nil {
+			return err
+		}
+
+		return db.UpsertGraphSourceNode(ctx, node.PubKeyBytes[:])
+	}, func() {})
+}
+
+// AddLightningNode adds a vertex/node to the graph database. If the node is not
+// in the database from before, this will add a new, unconnected one to the
+// graph. If it is present from before, this will update that node's
+// information.
+//
+// NOTE: The SQL graph doesn't batch writes, so the scheduler options are
+// ignored.
+func (s *SQLGraph) AddLightningNode(node *LightningNode,
+	_ ...batch.SchedulerOption) error {
+
+	var (
+		ctx         = context.TODO()
+		writeTxOpts SQLGraphQueriesTxOptions
+	)
+
+	params, err := marshalGraphNode(node)
+	if err != nil {
+		return err
+	}
+
+	err = s.db.ExecTx(ctx, &writeTxOpts, func(db SQLGraphQueries) error {
+		_, err := db.UpsertGraphNode(ctx, params)
+
+		return err
+	}, func() {})
+	if err != nil {
+		return err
+	}
+
+	if s.graphCache != nil {
+		cNode := &sqlGraphCacheNode{
+			graph:    s,
+			pubKey:   node.PubKeyBytes,
+			features: node.Features,
+		}
+
+		return s.graphCache.AddNode(nil, cNode)
+	}
+
+	return nil
+}
+
+// LookupAlias attempts to return the alias as advertised by the target node.
+func (s *SQLGraph) LookupAlias(pub *btcec.PublicKey) (string, error) {
+	var (
+		ctx        = context.TODO()
+		readTxOpts = NewSQLGraphQueryReadTx()
+		alias      string
+	)
+
+	err := s.db.ExecTx(ctx, &readTxOpts, func(db SQLGraphQueries) error {
+		dbNode, err := db.GetGraphNode(ctx, pub.SerializeCompressed())
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrNodeAliasNotFound
+
+		case err != nil:
+			return err
+		}
+
+		// Only nodes that announced themselves have an alias.
+		if !dbNode.HasAnnouncement {
+			return ErrNodeAliasNotFound
+		}
+		alias = dbNode.Alias.String
+
+		return nil
+	}, func() {
+		alias = ""
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return alias, nil
+}
+
+// DeleteLightningNode removes a vertex/node from the database according to the
+// node's public key.
+func (s *SQLGraph) DeleteLightningNode(nodePub route.Vertex) error {
+	var (
+		ctx         = context.TODO()
+		writeTxOpts SQLGraphQueriesTxOptions
+	)
+
+	err := s.db.ExecTx(ctx, &writeTxOpts, func(db SQLGraphQueries) error {
+		res, err := db.DeleteGraphNode(ctx, nodePub[:])
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return ErrGraphNodeNotFound
+		}
+
+		return nil
+	}, func() {})
+	if err != nil {
+		return err
+	}
+
+	if s.graphCache != nil {
+		s.graphCache.RemoveNode(nodePub)
+	}
+
+	return nil
+}
+
+// AddChannelEdge adds a new (undirected, blank) edge to the graph database.
+// Shell nodes are created for the nodes of the channel that aren't known yet.
+// ErrEdgeAlreadyExist is returned if the channel is already known.
+//
+// NOTE: The SQL graph doesn't batch writes, so the scheduler options are
+// ignored.
+func (s *SQLGraph) AddChannelEdge(edge *models.ChannelEdgeInfo,
+	_ ...batch.SchedulerOption) error {
+
+	var (
+		ctx         = context.TODO()
+		writeTxOpts SQLGraphQueriesTxOptions
+	)
+
+	params, err := marshalChannelInfo(edge)
+	if err != nil {
+		return err
+	}
+
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+
+	err = s.db.ExecTx(ctx, &writeTxOpts, func(db SQLGraphQueries) error {
+		_, err := db.GetGraphChannel(ctx, params.Scid)
+		switch {
+		case err == nil:
+			return ErrEdgeAlreadyExist
+
+		case !errors.Is(err, sql.ErrNoRows):
+			return err
+		}
+
+		// Before we insert the channel, we'll ensure that both nodes
+		// already exist in the graph. If either node doesn't, then
+		// we'll insert a "shell" node that just includes its public
+		// key.
+		err = db.InsertGraphShellNode(ctx, edge.NodeKey1Bytes[:])
+		if err != nil {
+			return fmt.Errorf("unable to create shell node for: "+
+				"%x: %w", edge.NodeKey1Bytes, err)
+		}
+		err = db.InsertGraphShellNode(ctx, edge.NodeKey2Bytes[:])
+		if err != nil {
+			return fmt.Errorf("unable to create shell node for: "+
+				"%x: %w", edge.NodeKey2Bytes, err)
+		}
+
+		_, err = db.InsertGraphChannel(ctx, params)
+
+		return err
+	}, func() {})
+	if err != nil {
+		return err
+	}
+
+	s.rejectCache.remove(edge.ChannelID)
+	s.chanCache.remove(edge.ChannelID)
+
+	if s.graphCache != nil {
+		s.graphCache.AddChannel(edge, nil, nil)
+	}
+
+	return nil
+}
+
+// HasChannelEdge returns true if the database knows of a channel edge with the
+// passed channel ID, and false otherwise. If an edge with that ID is found
+// within the graph, then two time stamps representing the last time the edge
+// was updated for both directed edges are returned along with the boolean. If
+// it is not found, then the zombie index is checked and its result is returned
+// as the second boolean.
+func (s *SQLGraph) HasChannelEdge(
+	chanID uint64) (time.Time, time.Time, bool, bool, error) {
+
+	var (
+		ctx        = context.TODO()
+		readTxOpts = NewSQLGraphQueryReadTx()
+		upd1Time   time.Time
+		upd2Time   time.Time
+		exists     bool
+		isZombie   bool
+	)
+
+	// We'll query the cache with the shared lock held to allow multiple
+	// readers to access values in the cache concurrently if they exist.
+	s.cacheMu.RLock()
+	if entry, ok := s.rejectCache.get(chanID); ok {
+		s.cacheMu.RUnlock()
+		upd1Time = time.Unix(entry.upd1Time, 0)
+		upd2Time = time.Unix(entry.upd2Time, 0)
+		exists, isZombie = entry.flags.unpack()
+
+		return upd1Time, upd2Time, exists, isZombie, nil
+	}
+	s.cacheMu.RUnlock()
+
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+
+	// The item was not found with the shared lock, so we'll acquire the
+	// exclusive lock and check the cache again in case another method added
+	// the entry to the cache while no lock was held.
+	if entry, ok := s.rejectCache.get(chanID); ok {
+		upd1Time = time.Unix(entry.upd1Time, 0)
+		upd2Time = time.Unix(entry.upd2Time, 0)
+		exists, isZombie = entry.flags.unpack()
+
+		return upd1Time, upd2Time, exists, isZombie, nil
+	}
+
+	scid := scidBytes(chanID)
+	err := s.db.ExecTx(ctx, &readTxOpts, func(db SQLGraphQueries) error {
+		dbChan, err := db.GetGraphChannel(ctx, scid)
+		switch {
+		// If the edge doesn't exist, then we'll also check our zombie
+		// index.
+		case errors.Is(err, sql.ErrNoRows):
+			_, err := db.GetGraphZombieChannel(ctx, scid)
+			switch {
+			case err == nil:
+				isZombie = true
+
+			case !errors.Is(err, sql.ErrNoRows):
+				return err
+			}
+
+			return nil
+
+		case err != nil:
+			return err
+		}
+
+		exists = true
+
+		// As we may have only one of the policies, only set the update
+		// time if the policy was found in the database.
+		policies, err := db.ListGraphChannelPolicies(
+			ctx, sqlc.ListGraphChannelPoliciesParams{
+				StartID: dbChan.ID,
+				EndID:   dbChan.ID,
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		for _, policy := range policies {
+			if policy.Direction == 0 {
+				upd1Time = time.Unix(policy.LastUpdate, 0)
+			} else {
+				upd2Time = time.Unix(policy.LastUpdate, 0)
+			}
+		}
+
+		return nil
+	}, func() {
+		upd1Time = time.Time{}
+		upd2Time = time.Time{}
+		exists = false
+		isZombie = false
+	})
+	if err != nil {
+		return time.Time{}, time.Time{}, exists, isZombie, err
+	}
+
+	s.rejectCache.insert(chanID, rejectCacheEntry{
+		upd1Time: upd1Time.Unix(),
+		upd2Time: upd2Time.Unix(),
+		flags:    packRejectFlags(exists, isZombie),
+	})
+
+	return upd1Time, upd2Time, exists, isZombie, nil
+}
+
+// UpdateChannelEdge updates the info of an edge that already exists in the
+// graph. ErrEdgeNotFound is returned if the edge isn't known yet.
+func (s *SQLGraph) UpdateChannelEdge(edge *models.ChannelEdgeInfo) error {
+	var (
+		ctx         = context.TODO()
+		writeTxOpts SQLGraphQueriesTxOptions
+	)
+
+	params, err := marshalChannelInfo(edge)
+	if err != nil {
+		return err
+	}
+
+	err = s.db.ExecTx(ctx, &writeTxOpts, func(db SQLGraphQueries) error {
+		res, err := db.UpdateGraphChannel(
+			ctx, sqlc.UpdateGraphChannelParams{
+				ChainHash:       params.ChainHash,
+				NodeKey1:        params.NodeKey1,
+				NodeKey2:        params.NodeKey2,
+				BitcoinKey1:     params.BitcoinKey1,
+				BitcoinKey2:     params.BitcoinKey2,
+				Features:        params.Features,
+				NodeSig1:        params.NodeSig1,
+				NodeSig2:        params.NodeSig2,
+				BitcoinSig1:     params.BitcoinSig1,
+				BitcoinSig2:     params.BitcoinSig2,
+				Outpoint:        params.Outpoint,
+				Capacity:        params.Capacity,
+				TapscriptRoot:   params.TapscriptRoot,
+				ExtraOpaqueData: params.ExtraOpaqueData,
+				Scid:            params.Scid,
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return ErrEdgeNotFound
+		}
+
+		return nil
+	}, func() {})
+	if err != nil {
+		return err
+	}
+
+	if s.graphCache != nil {
+		s.graphCache.UpdateChannel(edge)
+	}
+
+	return nil
+}
+
+// PruneGraph prunes newly closed channels from the channel graph in response
+// to a new block being solved on the network. Any channels whose funding
+// output is spent within the block are deleted, the block is added to the
+// prune log and nodes that are left without channels are pruned as well. The
+// channels that have been closed by the target block are returned.
+func (s *SQLGraph) PruneGraph(spentOutputs []*wire.OutPoint,
+	blockHash *chainhash.Hash, blockHeight uint32) (
+	[]*models.ChannelEdgeInfo, error) {
+
+	var (
+		ctx         = context.TODO()
+		writeTxOpts SQLGraphQueriesTxOptions
+		chansClosed []*models.ChannelEdgeInfo
+		prunedNodes []route.Vertex
+	)
+
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+
+	err := s.db.ExecTx(ctx, &writeTxOpts, func(db SQLGraphQueries) error {
+		for _, chanPoint := range spentOutputs {
+			dbChan, err := db.GetGraphChannelByOutpoint(
+				ctx, chanPoint.String(),
+			)
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				continue
+
+			case err != nil:
+				return err
+			}
+
+			info, err := deleteSQLChannel(
+				ctx, db, dbChan, false, false,
+			)
+			if err != nil {
+				return err
+			}
+
+			chansClosed = append(chansClosed, info)
+		}
+
+		// With the graph pruned, add a new entry to the prune log,
+		// which can be used to check if the graph is fully synced with
+		// the current UTXO state.
+		err := db.UpsertGraphPruneLogEntry(
+			ctx, sqlc.UpsertGraphPruneLogEntryParams{
+				BlockHeight: int32(blockHeight),
+				BlockHash:   blockHash[:],
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		// Now that the graph has been pruned, we'll also attempt to
+		// prune any nodes that are left without channels.
+		prunedNodes, err = pruneSQLGraphNodes(ctx, db)
+
+		return err
+	}, func() {
+		chansClosed = nil
+		prunedNodes = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, channel := range chansClosed {
+		s.removeChannelFromCaches(channel)
+	}
+	s.removeNodesFromCache(prunedNodes)
+
+	if s.graphCache != nil {
+		log.Debugf("Pruned graph, cache now has %s",
+			s.graphCache.Stats())
+	}
+
+	return chansClosed, nil
+}
+
+// PruneGraphNodes is a garbage collection method which attempts to prune out
+// any nodes from the channel graph that are currently unconnected.
+func (s *SQLGraph) PruneGraphNodes() error {
+	var (
+		ctx         = context.TODO()
+		writeTxOpts SQLGraphQueriesTxOptions
+		prunedNodes []route.Vertex
+	)
+
+	err := s.db.ExecTx(ctx, &writeTxOpts, func(db SQLGraphQueries) error {
+		var err error
+		prunedNodes, err = pruneSQLGraphNodes(ctx, db)
+
+		return err
+	}, func() {
+		prunedNodes = nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.removeNodesFromCache(prunedNodes)
+
+	return nil
+}
+
+// DisconnectBlockAtHeight is used to indicate that the block specified by the
+// passed height has been disconnected from the main chain. This will "rewind"
+// the graph back to the height below, deleting channels that are no longer
+// confirmed from the graph. The prune log will be set to the last prune height
+// valid for the remaining chain. Channels that were removed from the graph
+// resulting from the disconnected block are returned.
+func (s *SQLGraph) DisconnectBlockAtHeight(height uint32) (
+	[]*models.ChannelEdgeInfo, error) {
+
+	var (
+		ctx          = context.TODO()
+		writeTxOpts  SQLGraphQueriesTxOptions
+		removedChans []*models.ChannelEdgeInfo
+	)
+
+	// Every channel having a ShortChannelID starting at 'height' will no
+	// longer be confirmed. We delete everything up until the SCID alias
+	// range, but not the StartingAlias itself.
+	startShortChanID := lnwire.ShortChannelID{
+		BlockHeight: height,
+	}
+	endShortChanID := aliasmgr.StartingAlias.ToUint64() - 1
+
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+
+	startScid := scidBytes(startShortChanID.ToUint64())
+	endScid := scidBytes(endShortChanID)
+	err := s.db.ExecTx(ctx, &writeTxOpts, func(db SQLGraphQueries) error {
+		dbChans, err := db.ListGraphChannelsInSCIDRange(
+			ctx, sqlc.ListGraphChannelsInSCIDRangeParams{
+				StartScid: startScid,
+				EndScid:   endScid,
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		for _, dbChan := range dbChans {
+			info, err := deleteSQLChannel(
+				ctx, db, dbChan, false, false,
+			)
+			if err != nil {
+				return err
+			}
+
+			removedChans = append(removedChans, info)
+		}
+
+		// Delete all the entries in the prune log having a height
+		// greater or equal to the block disconnected.
+		return db.DeleteGraphPruneLogEntriesFrom(ctx, int32(height))
+	}, func() {
+		removedChans = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, channel := range removedChans {
+		s.removeChannelFromCaches(channel)
+	}
+
+	return removedChans, nil
+}
+
+// PruneTip returns the block height and hash of the latest block that has been
+// used to prune channels in the graph.
+func (s *SQLGraph) PruneTip() (*chainhash.Hash, uint32, error) {
+	var (
+		ctx        = context.TODO()
+		readTxOpts = NewSQLGraphQueryReadTx()
+		tipHash    chainhash.Hash
+		tipHeight  uint32
+	)
+
+	err := s.db.ExecTx(ctx, &readTxOpts, func(db SQLGraphQueries) error {
+		tip, err := db.GetGraphPruneTip(ctx)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrGraphNeverPruned
+
+		case err != nil:
+			return err
+		}
+
+		copy(tipHash[:], tip.BlockHash)
+		tipHeight = uint32(tip.BlockHeight)
+
+		return nil
+	}, func() {})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return &tipHash, tipHeight, nil
+}
+
+// DeleteChannelEdges removes edges with the given channel IDs from the
+// database and optionally marks them as zombies. If an edge does not exist
+// within the database, then ErrEdgeNotFound will be returned. If
+// strictZombiePruning is true, then when we mark these edges as zombies, we'll
+// set up the keys such that we require the node that failed to send the fresh
+// update to be the one that resurrects the channel from its zombie state.
+func (s *SQLGraph) DeleteChannelEdges(strictZombiePruning, markZombie bool,
+	chanIDs ...uint64) error {
+
+	var (
+		ctx          = context.TODO()
+		writeTxOpts  SQLGraphQueriesTxOptions
+		removedChans []*models.ChannelEdgeInfo
+	)
+
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+
+	err := s.db.ExecTx(ctx, &writeTxOpts, func(db SQLGraphQueries) error {
+		for _, chanID := range chanIDs {
+			scid := scidBytes(chanID)
+			dbChan, err := db.GetGraphChannel(ctx, scid)
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				return ErrEdgeNotFound
+
+			case err != nil:
+				return err
+			}
+
+			info, err := deleteSQLChannel(
+				ctx, db, dbChan, markZombie,
+				strictZombiePruning,
+			)
+			if err != nil {
+				return err
+			}
+
+			removedChans = append(removedChans, info)
+		}
+
+		return nil
+	}, func() {
+		removedChans = nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, channel := range removedChans {
+		s.removeChannelFromCaches(channel)
+	}
+
+	return nil
+}
+
+// ChannelID attempts to look up the 8-byte compact channel ID which maps to
+// the passed channel point (outpoint). If the passed channel doesn't exist
+// within the database, then ErrEdgeNotFound is returned.
+func (s *SQLGraph) ChannelID(chanPoint *wire.OutPoint) (uint64, error) {
+	var (
+		ctx        = context.TODO()
+		readTxOpts = NewSQLGraphQueryReadTx()
+		chanID     uint64
+	)
+
+	err := s.db.ExecTx(ctx, &readTxOpts, func(db SQLGraphQueries) error {
+		dbChan, err := db.GetGraphChannelByOutpoint(
+			ctx, chanPoint.String(),
+		)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrEdgeNotFound
+
+		case err != nil:
+			return err
+		}
+
+		chanID = byteOrder.Uint64(dbChan.Scid)
+
+		return nil
+	}, func() {
+		chanID = 0
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return chanID, nil
+}
+
+// HighestChanID returns the "highest" known channel ID in the channel graph.
+// Zero is returned if we don't know of any channels.
+func (s *SQLGraph) HighestChanID() (uint64, error) {
+	var (
+		ctx        = context.TODO()
+		readTxOpts = NewSQLGraphQueryReadTx()
+		cid        uint64
+	)
+
+	err := s.db.ExecTx(ctx, &readTxOpts, func(db SQLGraphQueries) error {
+		scid, err := db.GetGraphHighestSCID(ctx)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil
+
+		case err != nil:
+			return err
+		}
+
+		cid = byteOrder.Uint64(scid)
+
+		return nil
+	}, func() {
+		cid = 0
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return cid, nil
+}
+
+// ChanUpdatesInHorizon returns all the known channel edges which have at least
+// one edge that has an update timestamp within the specified horizon.
+func (s *SQLGraph) ChanUpdatesInHorizon(startTime,
+	endTime time.Time) ([]ChannelEdge, error) {
+
+	var (
+		ctx            = context.TODO()
+		readTxOpts     = NewSQLGraphQueryReadTx()
+		edgesToCache   map[uint64]ChannelEdge
+		edgesInHorizon []ChannelEdge
+		hits           int
+	)
+
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+
+	err := s.db.ExecTx(ctx, &readTxOpts, func(db SQLGraphQueries) error {
+		dbChans, err := db.ListGraphChannelsInHorizon(
+			ctx, sqlc.ListGraphChannelsInHorizonParams{
+				StartTime: startTime.Unix(),
+				EndTime:   endTime.Unix(),
+			},
+		)
+		if err != nil || len(dbChans) == 0 {
+			return err
+		}
+
+		policies, err := db.ListGraphChannelPoliciesInHorizon(
+			ctx, sqlc.ListGraphChannelPoliciesInHorizonParams{
+				StartTime: startTime.Unix(),
+				EndTime:   endTime.Unix(),
+			},
+		)
+		if err != nil {
+			return err
+		}
+		channels, err := unmarshalChannelEdges(dbChans, policies)
+		if err != nil {
+			return err
+		}
+
+		nodes := make(map[route.Vertex]*LightningNode)
+		fetchNode := func(pubKey route.Vertex) (*LightningNode, error) {
+			if node, ok := nodes[pubKey]; ok {
+				return node, nil
+			}
+
+			node, err := fetchSQLGraphNode(ctx, db, pubKey[:])
+			if err != nil {
+				return nil, err
+			}
+			nodes[pubKey] = node
+
+			return node, nil
+		}
+
+		for _, channel := range channels {
+			chanID := channel.Info.ChannelID
+			if cached, ok := s.chanCache.get(chanID); ok {
+				hits++
+				edgesInHorizon = append(edgesInHorizon, cached)
+
+				continue
+			}
+
+			info := channel.Info
+			channel.Node1, err = fetchNode(info.NodeKey1Bytes)
+			if err != nil {
+				return err
+			}
+			channel.Node2, err = fetchNode(info.NodeKey2Bytes)
+			if err != nil {
+				return err
+			}
+
+			edgesInHorizon = append(edgesInHorizon, channel)
+			edgesToCache[chanID] = channel
+		}
+
+		return nil
+	}, func() {
+		edgesToCache = make(map[uint64]ChannelEdge)
+		edgesInHorizon = nil
+		hits = 0
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Insert any edges loaded from disk into the cache.
+	for chanid, channel := range edgesToCache {
+		s.chanCache.insert(chanid, channel)
+	}
+
+	log.Debugf("ChanUpdatesInHorizon hit percentage: %f (%d/%d)",
+		float64(hits)/float64(len(edgesInHorizon)), hits,
+		len(edgesInHorizon))
+
+	return edgesInHorizon, nil
+}
+
+// NodeUpdatesInHorizon returns all the known lightning nodes which have an
+// update timestamp within the passed range.
+func (s *SQLGraph) NodeUpdatesInHorizon(startTime,
+	endTime time.Time) ([]LightningNode, error) {
+
+	var (
+		ctx            = context.TODO()
+		readTxOpts     = NewSQLGraphQueryReadTx()
+		nodesInHorizon []LightningNode
+	)
+
+	err := s.db.ExecTx(ctx, &readTxOpts, func(db SQLGraphQueries) error {
+		dbNodes, err := db.ListGraphNodesInHorizon(
+			ctx, sqlc.ListGraphNodesInHorizonParams{
+				StartTime: startTime.Unix(),
+				EndTime:   endTime.Unix(),
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		for _, dbNode := range dbNodes {
+			node, err := unmarshalGraphNode(dbNode)
+			if err != nil {
+				return err
+			}
+
+			nodesInHorizon = append(nodesInHorizon, *node)
+		}
+
+		return nil
+	}, func() {
+		nodesInHorizon = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nodesInHorizon, nil
+}
+
+// FilterKnownChanIDs takes a set of channel IDs and return the subset of chan
+// ID's that we don't know and are not known zombies of the passed set. Known
+// zombies that wouldn't be considered zombies anymore given the passed update
+// timestamps are marked as live.
+func (s *SQLGraph) FilterKnownChanIDs(chansInfo []ChannelUpdateInfo,
+	isZombieChan func(time.Time, time.Time) bool) ([]uint64, error) {
+
+	var (
+		ctx         = context.TODO()
+		writeTxOpts SQLGraphQueriesTxOptions
+		newChanIDs  []uint64
+		liveChanIDs []uint64
+	)
+
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+
+	err := s.db.ExecTx(ctx, &writeTxOpts, func(db SQLGraphQueries) error {
+		for _, info := range chansInfo {
+			scid := info.ShortChannelID.ToUint64()
+
+			// If the edge is already known, skip it.
+			_, err := db.GetGraphChannel(ctx, scidBytes(scid))
+			switch {
+			case err == nil:
+				continue
+
+			case !errors.Is(err, sql.ErrNoRows):
+				return err
+			}
+
+			_, err = db.GetGraphZombieChannel(ctx, scidBytes(scid))
+			switch {
+			// The edge isn't a known zombie either.
+			case errors.Is(err, sql.ErrNoRows):
+
+			case err != nil:
+				return err
+
+			// If we would still consider the known zombie a zombie
+			// given the latest update timestamps, then we skip it.
+			case isZombieChan(
+				info.Node1UpdateTimestamp,
+				info.Node2UpdateTimestamp,
+			):
+				continue
+
+			// Otherwise the latest update timestamps could bring
+			// it back from the dead, so we mark it alive and let
+			// it be added to the set of IDs to query our peer for.
+			default:
+				_, err := db.DeleteGraphZombieChannel(
+					ctx, scidBytes(scid),
+				)
+				if err != nil {
+					return err
+				}
+
+				liveChanIDs = append(liveChanIDs, scid)
+			}
+
+			newChanIDs = append(newChanIDs, scid)
+		}
+
+		return nil
+	}, func() {
+		newChanIDs = nil
+		liveChanIDs = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, chanID := range liveChanIDs {
+		s.rejectCache.remove(chanID)
+		s.chanCache.remove(chanID)
+	}
+
+	return newChanIDs, nil
+}
+
+// FilterChannelRange returns the channel ID's of all known channels which were
+// mined in a block height within the passed range. The channel IDs are grouped
+// by their common block height. If withTimestamps is true then the timestamp
+// info of the latest received channel update messages of the channel will be
+// included in the response.
+func (s *SQLGraph) FilterChannelRange(startHeight,
+	endHeight uint32, withTimestamps bool) ([]BlockChannelRange, error) {
+
+	var (
+		ctx              = context.TODO()
+		readTxOpts       = NewSQLGraphQueryReadTx()
+		channelsPerBlock map[uint32][]ChannelUpdateInfo
+	)
+
+	startChanID := lnwire.ShortChannelID{
+		BlockHeight: startHeight,
+	}
+	endChanID := lnwire.ShortChannelID{
+		BlockHeight: endHeight,
+		TxIndex:     0x00ffffff,
+		TxPosition:  0xffff,
+	}
+	startScid := scidBytes(startChanID.ToUint64())
+	endScid := scidBytes(endChanID.ToUint64())
+	policyParams := sqlc.ListGraphChannelPoliciesInSCIDRangeParams{
+		StartScid: startScid,
+		EndScid:   endScid,
+	}
+
+	err := s.db.ExecTx(ctx, &readTxOpts, func(db SQLGraphQueries) error {
+		dbChans, err := db.ListGraphChannelsInSCIDRange(
+			ctx, sqlc.ListGraphChannelsInSCIDRangeParams{
+				StartScid: startScid,
+				EndScid:   endScid,
+			},
+		)
+		if err != nil || len(dbChans) == 0 {
+			return err
+		}
+
+		var policies []sqlc.GraphChannelPolicy
+		if withTimestamps {
+			policies, err = db.ListGraphChannelPoliciesInSCIDRange(
+				ctx, policyParams,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		channels, err := unmarshalChannelEdges(dbChans, policies)
+		if err != nil {
+			return err
+		}
+
+		for _, channel := range channels {
+			// Only channels that were announced are shared with
+			// our peers.
+			if channel.Info.AuthProof == nil {
+				continue
+			}
+
+			cid := lnwire.NewShortChanIDFromInt(
+				channel.Info.ChannelID,
+			)
+
+			var upd1Time, upd2Time time.Time
+			if channel.Policy1 != nil {
+				upd1Time = channel.Policy1.LastUpdate
+			}
+			if channel.Policy2 != nil {
+				upd2Time = channel.Policy2.LastUpdate
+			}
+			chanInfo := NewChannelUpdateInfo(
+				cid, upd1Time, upd2Time,
+			)
+
+			channelsPerBlock[cid.BlockHeight] = append(
+				channelsPerBlock[cid.BlockHeight], chanInfo,
+			)
+		}
+
+		return nil
+	}, func() {
+		channelsPerBlock = make(map[uint32][]ChannelUpdateInfo)
+	})
+	switch {
+	case err != nil:
+		return nil, err
+
+	// If we don't know of any channels yet, then there's nothing to
+	// filter, so we'll return an empty slice.
+	case len(channelsPerBlock) == 0:
+		return nil, nil
+	}
+
+	// Return the channel ranges in ascending block height order.
+	blocks := make([]uint32, 0, len(channelsPerBlock))
+	for block := range channelsPerBlock {
+		blocks = append(blocks, block)
+	}
+	sort.Slice(blocks, func(i, j int) bool {
+		return blocks[i] < blocks[j]
+	})
+
+	channelRanges := make([]BlockChannelRange, 0, len(channelsPerBlock))
+	for _, block := range blocks {
+		channelRanges = append(channelRanges, BlockChannelRange{
+			Height:   block,
+			Channels: channelsPerBlock[block],
+		})
+	}
+
+	return channelRanges, nil
+}
+
+// FetchChanInfos returns the set of channel edges that correspond to the passed
+// channel ID's. If an edge is the query is unknown to the database, it will
+// skipped and the result will contain only those edges that exist at the time
+// of the query.
+func (s *SQLGraph) FetchChanInfos(chanIDs []uint64) ([]ChannelEdge, error) {
+	var (
+		ctx        = context.TODO()
+		readTxOpts = NewSQLGraphQueryReadTx()
+		chanEdges  []ChannelEdge
+	)
+
+	err := s.db.ExecTx(ctx, &readTxOpts, func(db SQLGraphQueries) error {
+		for _, chanID := range chanIDs {
+			scid := scidBytes(chanID)
+			dbChan, err := db.GetGraphChannel(ctx, scid)
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				continue
+
+			case err != nil:
+				return err
+			}
+
+			channel, err := fetchSQLChannelEdge(ctx, db, dbChan)
+			if err != nil {
+				return err
+			}
+
+			channel.Node1, err = fetchSQLGraphNode(
+				ctx, db, channel.Info.NodeKey1Bytes[:],
+			)
+			if err != nil {
+				return err
+			}
+			channel.Node2, err = fetchSQLGraphNode(
+				ctx, db, channel.Info.NodeKey2Bytes[:],
+			)
+			if err != nil {
+				return err
+			}
+
+			chanEdges = append(chanEdges, *channel)
+		}
+
+		return nil
+	}, func() {
+		chanEdges = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return chanEdges, nil
+}
+
+// UpdateEdgePolicy updates the edge routing policy for a single directed edge
+// within the database for the referenced channel. ErrEdgeNotFound is returned
+// if the channel isn't known.
+//
+// NOTE: The SQL graph doesn't batch writes, so the scheduler options are
+// ignored.
+func (s *SQLGraph) UpdateEdgePolicy(edge *models.ChannelEdgePolicy,
+	_ ...batch.SchedulerOption) error {
+
+	var (
+		ctx              = context.TODO()
+		writeTxOpts      SQLGraphQueriesTxOptions
+		fromNode, toNode route.Vertex
+		isUpdate1        bool
+	)
+
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+
+	err := s.db.ExecTx(ctx, &writeTxOpts, func(db SQLGraphQueries) error {
+		dbChan, err := db.GetGraphChannel(
+			ctx, scidBytes(edge.ChannelID),
+		)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrEdgeNotFound
+
+		case err != nil:
+			return err
+		}
+
+		// Depending on the flags value passed above, either the first
+		// or second edge policy is being updated.
+		var direction int32
+		if edge.ChannelFlags&lnwire.ChanUpdateDirection == 0 {
+			copy(fromNode[:], dbChan.NodeKey1)
+			copy(toNode[:], dbChan.NodeKey2)
+			isUpdate1 = true
+		} else {
+			copy(fromNode[:], dbChan.NodeKey2)
+			copy(toNode[:], dbChan.NodeKey1)
+			isUpdate1 = false
+			direction = 1
+		}
+
+		params, err := marshalChannelPolicy(dbChan.ID, direction, edge)
+		if err != nil {
+			return err
+		}
+
+		return db.UpsertGraphChannelPolicy(ctx, params)
+	}, func() {
+		fromNode = route.Vertex{}
+		toNode = route.Vertex{}
+		isUpdate1 = false
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.graphCache != nil {
+		s.graphCache.UpdatePolicy(edge, fromNode, toNode, isUpdate1)
+	}
+
+	// Update the reject and channel caches in place, if the channel is
+	// cached already.
+	if entry, ok := s.rejectCache.get(edge.ChannelID); ok {
+		if isUpdate1 {
+			entry.upd1Time = edge.LastUpdate.Unix()
+		} else {
+			entry.upd2Time = edge.LastUpdate.Unix()
+		}
+		s.rejectCache.insert(edge.ChannelID, entry)
+	}
+
+	if channel, ok := s.chanCache.get(edge.ChannelID); ok {
+		if isUpdate1 {
+			channel.Policy1 = edge
+		} else {
+			channel.Policy2 = edge
+		}
+		s.chanCache.insert(edge.ChannelID, channel)
+	}
+
+	return nil
+}
+
+// FetchLightningNodeTx attempts to look up a target node by its identity
+// public key. ErrGraphNodeNotFound is returned if the node doesn't exist.
+//
+// NOTE: The passed transaction is ignored.
+func (s *SQLGraph) FetchLightningNodeTx(_ kvdb.RTx, nodePub route.Vertex) (
+	*LightningNode, error) {
+
+	return s.FetchLightningNode(nodePub)
+}
+
+// FetchLightningNode attempts to look up a target node by its identity public
+// key. ErrGraphNodeNotFound is returned if the node doesn't exist.
+func (s *SQLGraph) FetchLightningNode(nodePub route.Vertex) (*LightningNode,
+	error) {
+
+	var (
+		ctx        = context.TODO()
+		readTxOpts = NewSQLGraphQueryReadTx()
+		node       *LightningNode
+	)
+
+	err := s.db.ExecTx(ctx, &readTxOpts, func(db SQLGraphQueries) error {
+		var err error
+		node, err = fetchSQLGraphNode(ctx, db, nodePub[:])
+
+		return err
+	}, func() {
+		node = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// HasLightningNode determines if the graph has a vertex identified by the
+// target node identity public key. If the node exists in the database, a
+// timestamp of when the data for the node was lasted updated is returned along
+// with a true boolean. Otherwise, an empty time.Time is returned with a false
+// boolean.
+func (s *SQLGraph) HasLightningNode(nodePub [33]byte) (time.Time, bool,
+	error) {
+
+	node, err := s.FetchLightningNode(nodePub)
+	switch {
+	case errors.Is(err, ErrGraphNodeNotFound):
+		return time.Time{}, false, nil
+
+	case err != nil:
+		return time.Time{}, false, err
+	}
+
+	return node.LastUpdate, true, nil
+}
+
+// ForEachNodeChannel iterates through all channels of the given node,
+// executing the passed callback with an edge info structure and the policies
+// of each end of the channel. The first edge policy is the outgoing edge *to*
+// the connecting node, while the second is the incoming edge *from* the
+// connecting node. The channels are loaded up front, so the callback isn't
+// executed within a database transaction.
+//
+// Unknown policies are passed into the callback as nil values.
+func (s *SQLGraph) ForEachNodeChannel(nodePub route.Vertex,
+	cb func(kvdb.RTx, *models.ChannelEdgeInfo, *models.ChannelEdgePolicy,
+		*models.ChannelEdgePolicy) error) error {
+
+	var (
+		ctx        = context.TODO()
+		readTxOpts = NewSQLGraphQueryReadTx()
+		channels   []ChannelEdge
+	)
+
+	err := s.db.ExecTx(ctx, &readTxOpts, func(db SQLGraphQueries) error {
+		dbChans, err := db.ListGraphNodeChannels(ctx, nodePub[:])
+		if err != nil || len(dbChans) == 0 {
+			return err
+		}
+
+		policies, err := db.ListGraphNodeChannelPolicies(
+			ctx, nodePub[:],
+		)
+		if err != nil {
+			return err
+		}
+
+		channels, err = unmarshalChannelEdges(dbChans, policies)
+
+		return err
+	}, func() {
+		channels = nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, channel := range channels {
+		outPolicy, inPolicy := channel.Policy1, channel.Policy2
+		if channel.Info.NodeKey2Bytes == nodePub {
+			outPolicy, inPolicy = inPolicy, outPolicy
+		}
+
+		err := cb(nil, channel.Info, outPolicy, inPolicy)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ForEachNodeChannelTx iterates through all channels of the given node. See
+// ForEachNodeChannel for the details.
+//
+// NOTE: The passed transaction is ignored.
+func (s *SQLGraph) ForEachNodeChannelTx(_ kvdb.RTx,
+	nodePub route.Vertex, cb func(kvdb.RTx, *models.ChannelEdgeInfo,
+		*models.ChannelEdgePolicy,
+		*models.ChannelEdgePolicy) error) error {
+
+	return s.ForEachNodeChannel(nodePub, cb)
+}
+
+// FetchOtherNode attempts to fetch the full LightningNode that's opposite of
+// the target node in the channel.
+//
+// NOTE: The passed transaction is ignored.
+func (s *SQLGraph) FetchOtherNode(_ kvdb.RTx,
+	channel *models.ChannelEdgeInfo, thisNodeKey []byte) (*LightningNode,
+	error) {
+
+	var targetNodeBytes [33]byte
+	switch {
+	case bytes.Equal(channel.NodeKey1Bytes[:], thisNodeKey):
+		targetNodeBytes = channel.NodeKey2Bytes
+	case bytes.Equal(channel.NodeKey2Bytes[:], thisNodeKey):
+		targetNodeBytes = channel.NodeKey1Bytes
+	default:
+		return nil, fmt.Errorf("node not participating in this channel")
+	}
+
+	return s.FetchLightningNode(targetNodeBytes)
+}
+
+// FetchChannelEdgesByOutpoint attempts to look up the two directed edges for
+// the channel identified by the funding outpoint. If the channel can't be
+// found, then ErrEdgeNotFound is returned.
+func (s *SQLGraph) FetchChannelEdgesByOutpoint(op *wire.OutPoint) (
+	*models.ChannelEdgeInfo, *models.ChannelEdgePolicy,
+	*models.ChannelEdgePolicy, error) {
+
+	var (
+		ctx        = context.TODO()
+		readTxOpts = NewSQLGraphQueryReadTx()
+		channel    *ChannelEdge
+	)
+
+	err := s.db.ExecTx(ctx, &readTxOpts, func(db SQLGraphQueries) error {
+		dbChan, err := db.GetGraphChannelByOutpoint(ctx, op.String())
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return fmt.Errorf("%w: op=%v", ErrEdgeNotFound, op)
+
+		case err != nil:
+			return err
+		}
+
+		channel, err = fetchSQLChannelEdge(ctx, db, dbChan)
+
+		return err
+	}, func() {
+		channel = nil
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return channel.Info, channel.Policy1, channel.Policy2, nil
+}
+
+// FetchChannelEdgesByID attempts to look up the two directed edges for the
+// channel identified by the channel ID. If the channel can't be found, then
+// ErrEdgeNotFound is returned. If the channel is a zombie, ErrZombieEdge is
+// returned along with an edge info that only has the node keys set.
+func (s *SQLGraph) FetchChannelEdgesByID(chanID uint64) (
+	*models.ChannelEdgeInfo, *models.ChannelEdgePolicy,
+	*models.ChannelEdgePolicy, error) {
+
+	var (
+		ctx        = context.TODO()
+		readTxOpts = NewSQLGraphQueryReadTx()
+		channel    *ChannelEdge
+	)
+
+	err := s.db.ExecTx(ctx, &readTxOpts, func(db SQLGraphQueries) error {
+		dbChan, err := db.GetGraphChannel(ctx, scidBytes(chanID))
+		switch {
+		// If the channel isn't found, we'll check whether it's a
+		// zombie, in which case we return the keys of the nodes that
+		// are allowed to resurrect it.
+		case errors.Is(err, sql.ErrNoRows):
+			zombie, err := db.GetGraphZombieChannel(
+				ctx, scidBytes(chanID),
+			)
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				return ErrEdgeNotFound
+
+			case err != nil:
+				return err
+			}
+
+			info := &models.ChannelEdgeInfo{}
+			copy(info.NodeKey1Bytes[:], zombie.NodeKey1)
+			copy(info.NodeKey2Bytes[:], zombie.NodeKey2)
+			channel = &ChannelEdge{Info: info}
+
+			return ErrZombieEdge
+
+		case err != nil:
+			return err
+		}
+
+		channel, err = fetchSQLChannelEdge(ctx, db, dbChan)
+
+		return err
+	}, func() {
+		channel = nil
+	})
+	switch {
+	case errors.Is(err, ErrZombieEdge):
+		return channel.Info, nil, nil, err
+
+	case err != nil:
+		return nil, nil, nil, err
+	}
+
+	return channel.Info, channel.Policy1, channel.Policy2, nil
+}
+
+// IsPublicNode is a helper method that determines whether the node with the
+// given public key is seen as a public node in the graph from the graph's
+// source node's point of view.
+func (s *SQLGraph) IsPublicNode(pubKey [33]byte) (bool, error) {
+	var (
+		ctx          = context.TODO()
+		readTxOpts   = NewSQLGraphQueryReadTx()
+		nodeIsPublic bool
+	)
+
+	err := s.db.ExecTx(ctx, &readTxOpts, func(db SQLGraphQueries) error {
+		ourPubKey, err := db.GetGraphSourceNode(ctx)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrSourceNodeNotSet
+
+		case err != nil:
+			return err
+		}
+
+		if _, err := fetchSQLGraphNode(ctx, db, pubKey[:]); err != nil {
+			return err
+		}
+
+		dbChans, err := db.ListGraphNodeChannels(ctx, pubKey[:])
+		if err != nil {
+			return err
+		}
+
+		// A node is public if it has a channel with a node other than
+		// us, or an announced channel with us.
+		for _, dbChan := range dbChans {
+			if !bytes.Equal(dbChan.NodeKey1, ourPubKey) &&
+				!bytes.Equal(dbChan.NodeKey2, ourPubKey) {
+
+				nodeIsPublic = true
+				return nil
+			}
+
+			info, err := unmarshalChannelInfo(dbChan)
+			if err != nil {
+				return err
+			}
+			if info.AuthProof != nil {
+				nodeIsPublic = true
+				return nil
+			}
+		}
+
+		return nil
+	}, func() {
+		nodeIsPublic = false
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return nodeIsPublic, nil
+}
+
+// ChannelView returns the verifiable edge information for each active channel
+// within the known channel graph.
+func (s *SQLGraph) ChannelView() ([]EdgePoint, error) {
+	var edgePoints []EdgePoint
+	err := s.ForEachChannel(func(info *models.ChannelEdgeInfo,
+		_, _ *models.ChannelEdgePolicy) error {
+
+		pkScript, err := genMultiSigP2WSH(
+			info.BitcoinKey1Bytes[:], info.BitcoinKey2Bytes[:],
+		)
+		if err != nil {
+			return err
+		}
+
+		edgePoints = append(edgePoints, EdgePoint{
+			FundingPkScript: pkScript,
+			OutPoint:        info.ChannelPoint,
+		})
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return edgePoints, nil
+}
+
+// MarkEdgeZombie attempts to mark a channel identified by its channel ID as a
+// zombie. This method is used on an ad-hoc basis, when channels need to be
+// marked as zombies outside the normal pruning cycle.
+func (s *SQLGraph) MarkEdgeZombie(chanID uint64,
+	pubKey1, pubKey2 [33]byte) error {
+
+	var (
+		ctx         = context.TODO()
+		writeTxOpts SQLGraphQueriesTxOptions
+	)
+
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+
+	err := s.db.ExecTx(ctx, &writeTxOpts, func(db SQLGraphQueries) error {
+		return db.UpsertGraphZombieChannel(
+			ctx, sqlc.UpsertGraphZombieChannelParams{
+				Scid:     scidBytes(chanID),
+				NodeKey1: pubKey1[:],
+				NodeKey2: pubKey2[:],
+			},
+		)
+	}, func() {})
+	if err != nil {
+		return err
+	}
+
+	if s.graphCache != nil {
+		s.graphCache.RemoveChannel(pubKey1, pubKey2, chanID)
+	}
+
+	s.rejectCache.remove(chanID)
+	s.chanCache.remove(chanID)
+
+	return nil
+}
+
+// MarkEdgeLive clears an edge from our zombie index, deeming it as live.
+// ErrZombieEdgeNotFound is returned if the edge isn't a zombie.
+func (s *SQLGraph) MarkEdgeLive(chanID uint64) error {
+	var (
+		ctx         = context.TODO()
+		writeTxOpts SQLGraphQueriesTxOptions
+	)
+
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+
+	err := s.db.ExecTx(ctx, &writeTxOpts, func(db SQLGraphQueries) error {
+		res, err := db.DeleteGraphZombieChannel(ctx, scidBytes(chanID))
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return ErrZombieEdgeNotFound
+		}
+
+		return nil
+	}, func() {})
+	if err != nil {
+		return err
+	}
+
+	s.rejectCache.remove(chanID)
+	s.chanCache.remove(chanID)
+
+	// We need to add the channel back into our graph cache, otherwise we
+	// won't use it for path finding.
+	if s.graphCache != nil {
+		edgeInfos, err := s.FetchChanInfos([]uint64{chanID})
+		if err != nil {
+			return err
+		}
+
+		for _, edgeInfo := range edgeInfos {
+			s.graphCache.AddChannel(
+				edgeInfo.Info, edgeInfo.Policy1,
+				edgeInfo.Policy2,
+			)
+		}
+	}
+
+	return nil
+}
+
+// IsZombieEdge returns whether the edge is considered zombie. If it is a
+// zombie, then the two node public keys corresponding to this edge are also
+// returned.
+func (s *SQLGraph) IsZombieEdge(chanID uint64) (bool, [33]byte, [33]byte) {
+	var (
+		ctx              = context.TODO()
+		readTxOpts       = NewSQLGraphQueryReadTx()
+		pubKey1, pubKey2 [33]byte
+	)
+
+	err := s.db.ExecTx(ctx, &readTxOpts, func(db SQLGraphQueries) error {
+		zombie, err := db.GetGraphZombieChannel(ctx, scidBytes(chanID))
+		if err != nil {
+			return err
+		}
+
+		copy(pubKey1[:], zombie.NodeKey1)
+		copy(pubKey2[:], zombie.NodeKey2)
+
+		return nil
+	}, func() {
+		pubKey1 = [33]byte{}
+		pubKey2 = [33]byte{}
+	})
+	if err != nil {
+		return false, [33]byte{}, [33]byte{}
+	}
+
+	return true, pubKey1, pubKey2
+}
+
+// NumZombies returns the current number of zombie channels in the graph.
+func (s *SQLGraph) NumZombies() (uint64, error) {
+	var (
+		ctx        = context.TODO()
+		readTxOpts = NewSQLGraphQueryReadTx()
+		numZombies int64
+	)
+
+	err := s.db.ExecTx(ctx, &readTxOpts, func(db SQLGraphQueries) error {
+		var err error
+		numZombies, err = db.CountGraphZombieChannels(ctx)
+
+		return err
+	}, func() {
+		numZombies = 0
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(numZombies), nil
+}
+
+// PutClosedScid stores a SCID for a closed channel in the database. This is so
+// that we can ignore channel announcements that we know to be closed without
+// having to validate them and fetch a block.
+func (s *SQLGraph) PutClosedScid(scid lnwire.ShortChannelID) error {
+	var (
+		ctx         = context.TODO()
+		writeTxOpts SQLGraphQueriesTxOptions
+	)
+
+	return s.db.ExecTx(ctx, &writeTxOpts, func(db SQLGraphQueries) error {
+		return db.InsertGraphClosedSCID(ctx, scidBytes(scid.ToUint64()))
+	}, func() {})
+}
+
+// IsClosedScid checks whether a channel identified by the passed in scid is
+// closed. This helps avoid having to perform expensive validation checks.
+func (s *SQLGraph) IsClosedScid(scid lnwire.ShortChannelID) (bool, error) {
+	var (
+		ctx        = context.TODO()
+		readTxOpts = NewSQLGraphQueryReadTx()
+		isClosed   bool
+	)
+
+	err := s.db.ExecTx(ctx, &readTxOpts, func(db SQLGraphQueries) error {
+		var err error
+		isClosed, err = db.IsGraphClosedSCID(
+			ctx, scidBytes(scid.ToUint64()),
+		)
+
+		return err
+	}, func() {
+		isClosed = false
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return isClosed, nil
+}
+
+// removeChannelFromCaches removes a deleted channel from all the caches.
+//
+// NOTE: The cacheMu MUST be held when calling this method.
+func (s *SQLGraph) removeChannelFromCaches(info *models.ChannelEdgeInfo) {
+	s.rejectCache.remove(info.ChannelID)
+	s.chanCache.remove(info.ChannelID)
+
+	if s.graphCache != nil {
+		s.graphCache.RemoveChannel(
+			info.NodeKey1Bytes, info.NodeKey2Bytes, info.ChannelID,
+		)
+	}
+}
+
+// removeNodesFromCache removes pruned nodes from the graph cache.
+func (s *SQLGraph) removeNodesFromCache(nodes []route.Vertex) {
+	for _, node := range nodes {
+		if s.graphCache != nil {
+			s.graphCache.RemoveNode(node)
+		}
+
+		log.Infof("Pruned unconnected node %x from channel graph",
+			node[:])
+	}
+
+	if len(nodes) > 0 {
+		log.Infof("Pruned %v unconnected nodes from the channel graph",
+			len(nodes))
+	}
+}
+
+// sqlGraphCacheNode is the GraphCacheNode of a node of the SQL graph. Its
+// channels are loaded from the SQL graph when it's added to the graph cache.
+type sqlGraphCacheNode struct {
+	graph    *SQLGraph
+	pubKey   route.Vertex
+	features *lnwire.FeatureVector
+}
+
+// PubKey returns the node's public identity key.
+func (n *sqlGraphCacheNode) PubKey() route.Vertex {
+	return n.pubKey
+}
+
+// Features returns the node's features.
+func (n *sqlGraphCacheNode) Features() *lnwire.FeatureVector {
+	return n.features
+}
+
+// ForEachChannel iterates through all channels of this node, executing the
+// passed callback with an edge info structure and the policies of each end
+// of the channel.
+//
+// NOTE: The passed transaction is ignored.
+func (n *sqlGraphCacheNode) ForEachChannel(_ kvdb.RTx,
+	cb func(kvdb.RTx, *models.ChannelEdgeInfo, *models.ChannelEdgePolicy,
+		*models.ChannelEdgePolicy) error) error {
+
+	return n.graph.ForEachNodeChannel(n.pubKey, cb)
+}
+
+var _ GraphCacheNode = (*sqlGraphCacheNode)(nil)
+
+// pruneSQLGraphNodes deletes the nodes that don't have any channels left,
+// except for the source node, and returns their public keys.
+func pruneSQLGraphNodes(ctx context.Context,
+	db SQLGraphQueries) ([]route.Vertex, error) {
+
+	// We'll retrieve the graph's source node to ensure we don't remove it
+	// even if it no longer has any open channels.
+	sourcePubKey, err := db.GetGraphSourceNode(ctx)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, ErrSourceNodeNotSet
+
+	case err != nil:
+		return nil, err
+	}
+
+	pubKeys, err := db.ListGraphNodesWithoutChannels(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var prunedNodes []route.Vertex
+	for _, pubKey := range pubKeys {
+		if bytes.Equal(pubKey, sourcePubKey) {
+			continue
+		}
+
+		if _, err := db.DeleteGraphNode(ctx, pubKey); err != nil {
+			return nil, err
+		}
+
+		nodePub, err := route.NewVertexFromBytes(pubKey)
+		if err != nil {
+			return nil, err
+		}
+		prunedNodes = append(prunedNodes, nodePub)
+	}
+
+	return prunedNodes, nil
+}
+
+// deleteSQLChannel deletes the given channel along with its policies and
+// returns its info. If markZombie is true, the channel is added to the zombie
+// index as well.
+func deleteSQLChannel(ctx context.Context, db SQLGraphQueries,
+	dbChan sqlc.GraphChannel, markZombie,
+	strictZombie bool) (*models.ChannelEdgeInfo, error) {
+
+	// The policies are needed to determine which node may resurrect the
+	// channel, so we load them before the channel is deleted.
+	channel, err := fetchSQLChannelEdge(ctx, db, dbChan)
+	if err != nil {
+		return nil, err
+	}
+	info := channel.Info
+
+	// The policies of the channel are deleted through the foreign key
+	// cascade.
+	if _, err := db.DeleteGraphChannel(ctx, dbChan.Scid); err != nil {
+		return nil, err
+	}
+
+	if !markZombie {
+		return info, nil
+	}
+
+	nodeKey1, nodeKey2 := info.NodeKey1Bytes, info.NodeKey2Bytes
+	if strictZombie {
+		nodeKey1, nodeKey2 = makeZombiePubkeys(
+			info, channel.Policy1, channel.Policy2,
+		)
+	}
+
+	err = db.UpsertGraphZombieChannel(
+		ctx, sqlc.UpsertGraphZombieChannelParams{
+			Scid:     dbChan.Scid,
+			NodeKey1: nodeKey1[:],
+			NodeKey2: nodeKey2[:],
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// fetchSQLGraphNode returns the node with the given public key.
+// ErrGraphNodeNotFound is returned if the node doesn't exist.
+func fetchSQLGraphNode(ctx context.Context, db SQLGraphQueries,
+	pubKey []byte) (*LightningNode, error) {
+
+	dbNode, err := db.GetGraphNode(ctx, pubKey)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, ErrGraphNodeNotFound
+
+	case err != nil:
+		return nil, err
+	}
+
+	return unmarshalGraphNode(dbNode)
+}
+
+// fetchSQLChannelEdge loads the policies of the given channel and returns it
+// as a ChannelEdge without its nodes.
+func fetchSQLChannelEdge(ctx context.Context, db SQLGraphQueries,
+	dbChan sqlc.GraphChannel) (*ChannelEdge, error) {
+
+	policies, err := db.ListGraphChannelPolicies(
+		ctx, sqlc.ListGraphChannelPoliciesParams{
+			StartID: dbChan.ID,
+			EndID:   dbChan.ID,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	channels, err := unmarshalChannelEdges(
+		[]sqlc.GraphChannel{dbChan}, policies,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &channels[0], nil
+}
+
+// scidBytes returns the 8-byte big-endian encoding of a short channel ID,
+// which is how channels are keyed in the SQL graph.
+func scidBytes(chanID uint64) []byte {
+	var scid [8]byte
+	byteOrder.PutUint64(scid[:], chanID)
+
+	return scid[:]
+}
+
+// marshalGraphNode converts a node into the parameters of its row in the
+// graph_nodes table.
+func marshalGraphNode(node *LightningNode) (sqlc.UpsertGraphNodeParams,
+	error) {
+
+	// Like the key-value graph, we make sure that the public key is valid
+	// before the node is stored.
+	if _, err := node.PubKey(); err != nil {
+		return sqlc.UpsertGraphNodeParams{}, err
+	}
+
+	var updateUnix int64
+	if node.LastUpdate.Unix() > 0 {
+		updateUnix = node.LastUpdate.Unix()
+	}
+
+	params := sqlc.UpsertGraphNodeParams{
+		PubKey:          node.PubKeyBytes[:],
+		HasAnnouncement: node.HaveNodeAnnouncement,
+		LastUpdate:      updateUnix,
+	}
+
+	// Shell nodes only have their public key.
+	if !node.HaveNodeAnnouncement {
+		return params, nil
+	}
+
+	if len(node.AuthSigBytes) > 80 {
+		return params, fmt.Errorf("max sig len allowed is 80, had %v",
+			len(node.AuthSigBytes))
+	}
+
+	if len(node.ExtraOpaqueData) > MaxAllowedExtraOpaqueBytes {
+		return params, ErrTooManyExtraOpaqueBytes(
+			len(node.ExtraOpaqueData),
+		)
+	}
+
+	var features bytes.Buffer
+	if err := node.Features.Encode(&features); err != nil {
+		return params, err
+	}
+
+	var addrs bytes.Buffer
+	for _, addr := range node.Addresses {
+		if err := serializeAddr(&addrs, addr); err != nil {
+			return params, err
+		}
+	}
+
+	params.Alias = sql.NullString{String: node.Alias, Valid: true}
+	params.Color = []byte{node.Color.R, node.Color.G, node.Color.B}
+	params.Features = features.Bytes()
+	params.Addresses = addrs.Bytes()
+	params.Signature = node.AuthSigBytes
+	params.ExtraOpaqueData = node.ExtraOpaqueData
+
+	return params, nil
+}
+
+// unmarshalGraphNode converts a row of the graph_nodes table into a node.
+func unmarshalGraphNode(dbNode sqlc.GraphNode) (*LightningNode, error) {
+	node := &LightningNode{
+		HaveNodeAnnouncement: dbNode.HasAnnouncement,
+		LastUpdate:           time.Unix(dbNode.LastUpdate, 0),
+		Features:             lnwire.EmptyFeatureVector(),
+	}
+	copy(node.PubKeyBytes[:], dbNode.PubKey)
+
+	if !node.HaveNodeAnnouncement {
+		return node, nil
+	}
+
+	if len(dbNode.Color) == 3 {
+		node.Color = color.RGBA{
+			R: dbNode.Color[0],
+			G: dbNode.Color[1],
+			B: dbNode.Color[2],
+		}
+	}
+	node.Alias = dbNode.Alias.String
+
+	err := node.Features.Decode(bytes.NewReader(dbNode.Features))
+	if err != nil {
+		return nil, err
+	}
+
+	addrReader := bytes.NewReader(dbNode.Addresses)
+	for addrReader.Len() > 0 {
+		addr, err := deserializeAddr(addrReader)
+		if err != nil {
+			return nil, err
+		}
+
+		node.Addresses = append(node.Addresses, addr)
+	}
+
+	node.AuthSigBytes = dbNode.Signature
+	node.ExtraOpaqueData = dbNode.ExtraOpaqueData
+
+	return node, nil
+}
+
+// marshalChannelInfo converts a channel into the parameters of its row in the
+// graph_channels table.
+func marshalChannelInfo(
+	edge *models.ChannelEdgeInfo) (sqlc.InsertGraphChannelParams, error) {
+
+	if len(edge.ExtraOpaqueData) > MaxAllowedExtraOpaqueBytes {
+		return sqlc.InsertGraphChannelParams{},
+			ErrTooManyExtraOpaqueBytes(len(edge.ExtraOpaqueData))
+	}
+
+	params := sqlc.InsertGraphChannelParams{
+		Scid:            scidBytes(edge.ChannelID),
+		ChainHash:       edge.ChainHash[:],
+		NodeKey1:        edge.NodeKey1Bytes[:],
+		NodeKey2:        edge.NodeKey2Bytes[:],
+		BitcoinKey1:     edge.BitcoinKey1Bytes[:],
+		BitcoinKey2:     edge.BitcoinKey2Bytes[:],
+		Features:        edge.Features,
+		Outpoint:        edge.ChannelPoint.String(),
+		Capacity:        int64(edge.Capacity),
+		ExtraOpaqueData: edge.ExtraOpaqueData,
+	}
+
+	if edge.AuthProof != nil {
+		params.NodeSig1 = edge.AuthProof.NodeSig1Bytes
+		params.NodeSig2 = edge.AuthProof.NodeSig2Bytes
+		params.BitcoinSig1 = edge.AuthProof.BitcoinSig1Bytes
+		params.BitcoinSig2 = edge.AuthProof.BitcoinSig2Bytes
+	}
+
+	edge.TapscriptRoot.WhenSome(func(root chainhash.Hash) {
+		params.TapscriptRoot = root[:]
+	})
+
+	return params, nil
+}
+
+// marshalChannelPolicy converts the policy of the given direction of a
+// channel into the parameters of its row in the graph_channel_policies table.
+func marshalChannelPolicy(channelID int64, direction int32,
+	edge *models.ChannelEdgePolicy) (sqlc.UpsertGraphChannelPolicyParams,
+	error) {
+
+	if len(edge.ExtraOpaqueData) > MaxAllowedExtraOpaqueBytes {
+		return sqlc.UpsertGraphChannelPolicyParams{},
+			ErrTooManyExtraOpaqueBytes(len(edge.ExtraOpaqueData))
+	}
+
+	// Like in the key-value graph, the max HTLC is only stored if the
+	// message flags signal that it's set.
+	var maxHTLC lnwire.MilliSatoshi
+	if edge.MessageFlags.HasMaxHtlc() {
+		maxHTLC = edge.MaxHTLC
+	}
+
+	return sqlc.UpsertGraphChannelPolicyParams{
+		ChannelID:       channelID,
+		Direction:       direction,
+		LastUpdate:      edge.LastUpdate.Unix(),
+		MessageFlags:    int32(edge.MessageFlags),
+		ChannelFlags:    int32(edge.ChannelFlags),
+		TimeLockDelta:   int32(edge.TimeLockDelta),
+		MinHtlcMsat:     int64(edge.MinHTLC),
+		MaxHtlcMsat:     int64(maxHTLC),
+		FeeBaseMsat:     int64(edge.FeeBaseMSat),
+		FeeRatePpm:      int64(edge.FeeProportionalMillionths),
+		Signature:       edge.SigBytes,
+		ExtraOpaqueData: edge.ExtraOpaqueData,
+	}, nil
+}
+
+// unmarshalChannelEdges converts rows of the graph_channels table into channel
+// edges along with the policies found for them among the passed rows of the
+// graph_channel_policies table. The nodes of the edges aren't set.
+func unmarshalChannelEdges(dbChans []sqlc.GraphChannel,
+	dbPolicies []sqlc.GraphChannelPolicy) ([]ChannelEdge, error) {
+
+	policies := make(map[int64][]sqlc.GraphChannelPolicy, len(dbChans))
+	for _, dbPolicy := range dbPolicies {
+		policies[dbPolicy.ChannelID] = append(
+			policies[dbPolicy.ChannelID], dbPolicy,
+		)
+	}
+
+	channels := make([]ChannelEdge, 0, len(dbChans))
+	for _, dbChan := range dbChans {
+		info, err := unmarshalChannelInfo(dbChan)
+		if err != nil {
+			return nil, err
+		}
+
+		channel := ChannelEdge{
+			Info: info,
+		}
+		for _, dbPolicy := range policies[dbChan.ID] {
+			policy := unmarshalChannelPolicy(info, dbPolicy)
+			if dbPolicy.Direction == 0 {
+				channel.Policy1 = policy
+			} else {
+				channel.Policy2 = policy
+			}
+		}
+
+		channels = append(channels, channel)
+	}
+
+	return channels, nil
+}
+
+// unmarshalChannelInfo converts a row of the graph_channels table into a
+// channel.
+func unmarshalChannelInfo(
+	dbChan sqlc.GraphChannel) (*models.ChannelEdgeInfo, error) {
+
+	chanPoint, err := wire.NewOutPointFromString(dbChan.Outpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &models.ChannelEdgeInfo{
+		ChannelID:       byteOrder.Uint64(dbChan.Scid),
+		Features:        dbChan.Features,
+		ChannelPoint:    *chanPoint,
+		Capacity:        btcutil.Amount(dbChan.Capacity),
+		ExtraOpaqueData: dbChan.ExtraOpaqueData,
+	}
+	copy(info.ChainHash[:], dbChan.ChainHash)
+	copy(info.NodeKey1Bytes[:], dbChan.NodeKey1)
+	copy(info.NodeKey2Bytes[:], dbChan.NodeKey2)
+	copy(info.BitcoinKey1Bytes[:], dbChan.BitcoinKey1)
+	copy(info.BitcoinKey2Bytes[:], dbChan.BitcoinKey2)
+
+	proof := &models.ChannelAuthProof{
+		NodeSig1Bytes:    dbChan.NodeSig1,
+		NodeSig2Bytes:    dbChan.NodeSig2,
+		BitcoinSig1Bytes: dbChan.BitcoinSig1,
+		BitcoinSig2Bytes: dbChan.BitcoinSig2,
+	}
+	if !proof.IsEmpty() {
+		info.AuthProof = proof
+	}
+
+	if len(dbChan.TapscriptRoot) == chainhash.HashSize {
+		var root chainhash.Hash
+		copy(root[:], dbChan.TapscriptRoot)
+		info.TapscriptRoot = fn.Some(root)
+	}
+
+	return info, nil
+}
+
+// unmarshalChannelPolicy converts a row of the graph_channel_policies table
+// into the policy of the given channel.
+func unmarshalChannelPolicy(info *models.ChannelEdgeInfo,
+	dbPolicy sqlc.GraphChannelPolicy) *models.ChannelEdgePolicy {
+
+	policy := &models.ChannelEdgePolicy{
+		SigBytes:     dbPolicy.Signature,
+		ChannelID:    info.ChannelID,
+		LastUpdate:   time.Unix(dbPolicy.LastUpdate, 0),
+		MessageFlags: lnwire.ChanUpdateMsgFlags(dbPolicy.MessageFlags),
+		ChannelFlags: lnwire.ChanUpdateChanFlags(
+			dbPolicy.ChannelFlags,
+		),
+		TimeLockDelta:   uint16(dbPolicy.TimeLockDelta),
+		MinHTLC:         lnwire.MilliSatoshi(dbPolicy.MinHtlcMsat),
+		MaxHTLC:         lnwire.MilliSatoshi(dbPolicy.MaxHtlcMsat),
+		FeeBaseMSat:     lnwire.MilliSatoshi(dbPolicy.FeeBaseMsat),
+		ExtraOpaqueData: dbPolicy.ExtraOpaqueData,
+		FeeProportionalMillionths: lnwire.MilliSatoshi(
+			dbPolicy.FeeRatePpm,
+		),
+	}
+
+	// The policy of the first node is for the channel towards the second
+	// node and vice versa.
+	policy.ToNode = info.NodeKey2Bytes
+	if dbPolicy.Direction == 1 {
+		policy.ToNode = info.NodeKey1Bytes
+	}
+
+	return policy
+}
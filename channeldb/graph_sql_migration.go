@@ -0,0 +1,259 @@
+package channeldb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/sqldb/sqlc"
+)
+
+// MigrateGraphToSQL copies the channel graph of the given key-value backed
+// graph into the SQL graph tables. The copy is done within a single SQL
+// transaction, so either the whole graph is migrated or nothing is. The
+// migration is skipped if the SQL graph already has nodes, which means it was
+// either migrated before or has been in use on its own.
+//
+// NOTE: The key-value graph is left untouched, so the node can go back to it
+// by disabling the SQL graph again.
+func MigrateGraphToSQL(kvGraph *ChannelGraph, db BatchedSQLGraphQueries) error {
+	var (
+		ctx         = context.TODO()
+		writeTxOpts SQLGraphQueriesTxOptions
+		numNodes    int
+		numChannels int
+		numZombies  int
+		startTime   = time.Now()
+	)
+
+	err := db.ExecTx(ctx, &writeTxOpts, func(db SQLGraphQueries) error {
+		count, err := db.CountGraphNodes(ctx)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			log.Debugf("SQL channel graph already populated, " +
+				"skipping migration")
+
+			return nil
+		}
+
+		numNodes, err = migrateGraphNodesToSQL(ctx, kvGraph, db)
+		if err != nil {
+			return fmt.Errorf("unable to migrate nodes: %w", err)
+		}
+
+		numChannels, err = migrateGraphChannelsToSQL(ctx, kvGraph, db)
+		if err != nil {
+			return fmt.Errorf("unable to migrate channels: %w", err)
+		}
+
+		numZombies, err = migrateGraphIndexesToSQL(ctx, kvGraph, db)
+		if err != nil {
+			return fmt.Errorf("unable to migrate graph indexes: %w",
+				err)
+		}
+
+		return nil
+	}, func() {
+		numNodes = 0
+		numChannels = 0
+		numZombies = 0
+	})
+	if err != nil {
+		return err
+	}
+
+	if numNodes == 0 {
+		return nil
+	}
+
+	log.Infof("Migrated channel graph to SQL (%d nodes, %d channels, %d "+
+		"zombies) in %v", numNodes, numChannels, numZombies,
+		time.Since(startTime))
+
+	return nil
+}
+
+// migrateGraphNodesToSQL copies all nodes and the source node of the
+// key-value graph into the SQL graph.
+func migrateGraphNodesToSQL(ctx context.Context, kvGraph *ChannelGraph,
+	db SQLGraphQueries) (int, error) {
+
+	var numNodes int
+	err := kvGraph.ForEachNode(func(_ kvdb.RTx, node *LightningNode) error {
+		params, err := marshalGraphNode(node)
+		if err != nil {
+			return err
+		}
+
+		if _, err := db.UpsertGraphNode(ctx, params); err != nil {
+			return err
+		}
+		numNodes++
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	sourceNode, err := kvGraph.SourceNode()
+	switch {
+	// A graph without a source node can still be migrated, lnd will set it
+	// on startup.
+	case errors.Is(err, ErrSourceNodeNotSet):
+		return numNodes, nil
+
+	case err != nil:
+		return 0, err
+	}
+
+	err = db.UpsertGraphSourceNode(ctx, sourceNode.PubKeyBytes[:])
+	if err != nil {
+		return 0, err
+	}
+
+	return numNodes, nil
+}
+
+// migrateGraphChannelsToSQL copies all channels of the key-value graph along
+// with their policies into the SQL graph.
+func migrateGraphChannelsToSQL(ctx context.Context, kvGraph *ChannelGraph,
+	db SQLGraphQueries) (int, error) {
+
+	var numChannels int
+	err := kvGraph.ForEachChannel(func(info *models.ChannelEdgeInfo,
+		policy1, policy2 *models.ChannelEdgePolicy) error {
+
+		params, err := marshalChannelInfo(info)
+		if err != nil {
+			return err
+		}
+
+		// The nodes have been migrated already, but we make sure they
+		// exist in case the key-value graph has dangling channels.
+		err = db.InsertGraphShellNode(ctx, info.NodeKey1Bytes[:])
+		if err != nil {
+			return err
+		}
+		err = db.InsertGraphShellNode(ctx, info.NodeKey2Bytes[:])
+		if err != nil {
+			return err
+		}
+
+		channelID, err := db.InsertGraphChannel(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		policies := []*models.ChannelEdgePolicy{policy1, policy2}
+		for direction, policy := range policies {
+			if policy == nil {
+				continue
+			}
+
+			params, err := marshalChannelPolicy(
+				channelID, int32(direction), policy,
+			)
+			if err != nil {
+				return err
+			}
+
+			err = db.UpsertGraphChannelPolicy(ctx, params)
+			if err != nil {
+				return err
+			}
+		}
+		numChannels++
+
+		return nil
+	})
+	if err != nil && !errors.Is(err, ErrGraphNoEdgesFound) {
+		return 0, err
+	}
+
+	return numChannels, nil
+}
+
+// migrateGraphIndexesToSQL copies the zombie index, the closed SCIDs and the
+// prune log of the key-value graph into the SQL graph. The number of migrated
+// zombies is returned.
+func migrateGraphIndexesToSQL(ctx context.Context, kvGraph *ChannelGraph,
+	db SQLGraphQueries) (int, error) {
+
+	var numZombies int
+	err := kvdb.View(kvGraph.db, func(tx kvdb.RTx) error {
+		edges := tx.ReadBucket(edgeBucket)
+		if edges == nil {
+			return ErrGraphNoEdgesFound
+		}
+
+		zombies := edges.NestedReadBucket(zombieBucket)
+		if zombies != nil {
+			err := zombies.ForEach(func(k, v []byte) error {
+				if len(k) != 8 || len(v) != 66 {
+					return fmt.Errorf("invalid zombie "+
+						"entry for %x", k)
+				}
+
+				numZombies++
+
+				params := sqlc.UpsertGraphZombieChannelParams{
+					Scid:     k,
+					NodeKey1: v[:33],
+					NodeKey2: v[33:],
+				}
+
+				return db.UpsertGraphZombieChannel(ctx, params)
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		closedScids := tx.ReadBucket(closedScidBucket)
+		if closedScids != nil {
+			err := closedScids.ForEach(func(k, _ []byte) error {
+				return db.InsertGraphClosedSCID(ctx, k)
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		metaBucket := tx.ReadBucket(graphMetaBucket)
+		if metaBucket == nil {
+			return nil
+		}
+
+		pruneBucket := metaBucket.NestedReadBucket(pruneLogBucket)
+		if pruneBucket == nil {
+			return nil
+		}
+
+		return pruneBucket.ForEach(func(k, v []byte) error {
+			if len(k) != 4 {
+				return fmt.Errorf("invalid prune log height "+
+					"%x", k)
+			}
+
+			return db.UpsertGraphPruneLogEntry(
+				ctx, sqlc.UpsertGraphPruneLogEntryParams{
+					BlockHeight: int32(byteOrder.Uint32(k)),
+					BlockHash:   v,
+				},
+			)
+		})
+	}, func() {
+		numZombies = 0
+	})
+	if err != nil && !errors.Is(err, ErrGraphNoEdgesFound) {
+		return 0, err
+	}
+
+	return numZombies, nil
+}
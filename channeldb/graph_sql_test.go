@@ -0,0 +1,415 @@
+package channeldb
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/sqldb"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSQLGraphDB creates a new SQLite backed graph database.
+func newTestSQLGraphDB(t *testing.T) BatchedSQLGraphQueries {
+	db := sqldb.NewTestSqliteDB(t).BaseDB
+
+	createQuery := func(tx *sql.Tx) SQLGraphQueries {
+		return db.WithTx(tx)
+	}
+
+	return sqldb.NewTransactionExecutor(db, createQuery)
+}
+
+// newTestSQLGraph creates a new SQL graph with the graph cache enabled.
+func newTestSQLGraph(t *testing.T) *SQLGraph {
+	graph, err := NewSQLGraph(
+		newTestSQLGraphDB(t), DefaultRejectCacheSize,
+		DefaultChannelCacheSize, 0, true,
+	)
+	require.NoError(t, err)
+
+	return graph
+}
+
+// TestSQLGraphNodes tests that nodes can be added, fetched and deleted from
+// the SQL graph.
+func TestSQLGraphNodes(t *testing.T) {
+	t.Parallel()
+
+	graph := newTestSQLGraph(t)
+
+	_, err := graph.SourceNode()
+	require.ErrorIs(t, err, ErrSourceNodeNotSet)
+
+	source, err := createTestVertex(nil)
+	require.NoError(t, err)
+	require.NoError(t, graph.SetSourceNode(source))
+
+	dbSource, err := graph.SourceNode()
+	require.NoError(t, err)
+	require.NoError(t, compareNodes(source, dbSource))
+
+	node, err := createTestVertex(nil)
+	require.NoError(t, err)
+	node.ExtraOpaqueData = []byte("extra")
+	require.NoError(t, graph.AddLightningNode(node))
+
+	dbNode, err := graph.FetchLightningNode(node.PubKeyBytes)
+	require.NoError(t, err)
+	require.NoError(t, compareNodes(node, dbNode))
+	require.Equal(t, node.Features, dbNode.Features)
+	require.Equal(t, node.AuthSigBytes, dbNode.AuthSigBytes)
+
+	updateTime, exists, err := graph.HasLightningNode(node.PubKeyBytes)
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, node.LastUpdate, updateTime)
+
+	pub, err := node.PubKey()
+	require.NoError(t, err)
+	alias, err := graph.LookupAlias(pub)
+	require.NoError(t, err)
+	require.Equal(t, node.Alias, alias)
+
+	var numNodes int
+	err = graph.ForEachNode(func(_ kvdb.RTx, _ *LightningNode) error {
+		numNodes++
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, numNodes)
+
+	require.NoError(t, graph.DeleteLightningNode(node.PubKeyBytes))
+	_, err = graph.FetchLightningNode(node.PubKeyBytes)
+	require.ErrorIs(t, err, ErrGraphNodeNotFound)
+	_, err = graph.LookupAlias(pub)
+	require.ErrorIs(t, err, ErrNodeAliasNotFound)
+
+	err = graph.DeleteLightningNode(node.PubKeyBytes)
+	require.ErrorIs(t, err, ErrGraphNodeNotFound)
+}
+
+// TestSQLGraphEdges tests that channels and their policies can be added,
+// fetched and deleted from the SQL graph, and that deleted channels can be
+// marked as zombies.
+func TestSQLGraphEdges(t *testing.T) {
+	t.Parallel()
+
+	graph := newTestSQLGraph(t)
+
+	node1, err := createTestVertex(nil)
+	require.NoError(t, err)
+	node2, err := createTestVertex(nil)
+	require.NoError(t, err)
+	require.NoError(t, graph.SetSourceNode(node1))
+
+	// Adding the channel creates a shell node for the second node.
+	edgeInfo, edge1, edge2 := createChannelEdge(nil, node1, node2)
+	require.NoError(t, graph.AddChannelEdge(edgeInfo))
+	err = graph.AddChannelEdge(edgeInfo)
+	require.ErrorIs(t, err, ErrEdgeAlreadyExist)
+
+	shellNode, err := graph.FetchLightningNode(node2.PubKeyBytes)
+	require.NoError(t, err)
+	require.False(t, shellNode.HaveNodeAnnouncement)
+
+	_, _, exists, isZombie, err := graph.HasChannelEdge(edgeInfo.ChannelID)
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.False(t, isZombie)
+
+	require.NoError(t, graph.UpdateEdgePolicy(edge1))
+	require.NoError(t, graph.UpdateEdgePolicy(edge2))
+
+	// The update times of the policies must be returned now, which also
+	// checks that the reject cache was updated.
+	upd1, upd2, _, _, err := graph.HasChannelEdge(edgeInfo.ChannelID)
+	require.NoError(t, err)
+	require.Equal(t, edge1.LastUpdate, upd1)
+	require.Equal(t, edge2.LastUpdate, upd2)
+
+	dbInfo, dbEdge1, dbEdge2, err := graph.FetchChannelEdgesByID(
+		edgeInfo.ChannelID,
+	)
+	require.NoError(t, err)
+	assertEdgeInfoEqual(t, dbInfo, edgeInfo)
+	require.NoError(t, compareEdgePolicies(dbEdge1, edge1))
+	require.NoError(t, compareEdgePolicies(dbEdge2, edge2))
+
+	dbInfo, _, _, err = graph.FetchChannelEdgesByOutpoint(
+		&edgeInfo.ChannelPoint,
+	)
+	require.NoError(t, err)
+	assertEdgeInfoEqual(t, dbInfo, edgeInfo)
+
+	chanID, err := graph.ChannelID(&edgeInfo.ChannelPoint)
+	require.NoError(t, err)
+	require.Equal(t, edgeInfo.ChannelID, chanID)
+
+	highestChanID, err := graph.HighestChanID()
+	require.NoError(t, err)
+	require.Equal(t, edgeInfo.ChannelID, highestChanID)
+
+	// The outgoing policy of the second node is the second policy.
+	err = graph.ForEachNodeChannel(edgeInfo.NodeKey2Bytes,
+		func(_ kvdb.RTx, info *models.ChannelEdgeInfo, out,
+			in *models.ChannelEdgePolicy) error {
+
+			require.Equal(t, edgeInfo.ChannelID, info.ChannelID)
+			require.NoError(t, compareEdgePolicies(out, edge2))
+			require.NoError(t, compareEdgePolicies(in, edge1))
+
+			return nil
+		},
+	)
+	require.NoError(t, err)
+
+	edges, err := graph.ChanUpdatesInHorizon(
+		edge1.LastUpdate.Add(-time.Second),
+		edge1.LastUpdate.Add(time.Second),
+	)
+	require.NoError(t, err)
+	require.Len(t, edges, 1)
+	require.Equal(t, edgeInfo.ChannelID, edges[0].Info.ChannelID)
+	require.Equal(t, edgeInfo.NodeKey1Bytes, edges[0].Node1.PubKeyBytes)
+
+	edges, err = graph.ChanUpdatesInHorizon(
+		edge1.LastUpdate.Add(time.Second),
+		edge1.LastUpdate.Add(time.Hour),
+	)
+	require.NoError(t, err)
+	require.Empty(t, edges)
+
+	// Deleting the channel with the zombie flag set should move it to the
+	// zombie index.
+	require.NoError(t, graph.DeleteChannelEdges(
+		false, true, edgeInfo.ChannelID,
+	))
+	_, _, _, err = graph.FetchChannelEdgesByID(edgeInfo.ChannelID)
+	require.ErrorIs(t, err, ErrZombieEdge)
+
+	isZombie, pub1, pub2 := graph.IsZombieEdge(edgeInfo.ChannelID)
+	require.True(t, isZombie)
+	require.Equal(t, edgeInfo.NodeKey1Bytes, pub1)
+	require.Equal(t, edgeInfo.NodeKey2Bytes, pub2)
+
+	numZombies, err := graph.NumZombies()
+	require.NoError(t, err)
+	require.EqualValues(t, 1, numZombies)
+
+	err = graph.DeleteChannelEdges(false, true, edgeInfo.ChannelID)
+	require.ErrorIs(t, err, ErrEdgeNotFound)
+
+	require.NoError(t, graph.MarkEdgeLive(edgeInfo.ChannelID))
+	err = graph.MarkEdgeLive(edgeInfo.ChannelID)
+	require.ErrorIs(t, err, ErrZombieEdgeNotFound)
+
+	_, _, _, err = graph.FetchChannelEdgesByID(edgeInfo.ChannelID)
+	require.ErrorIs(t, err, ErrEdgeNotFound)
+}
+
+// TestSQLGraphFilterChannelRange tests that the SQL graph returns the
+// announced channels within a block range grouped by their block height.
+func TestSQLGraphFilterChannelRange(t *testing.T) {
+	t.Parallel()
+
+	graph := newTestSQLGraph(t)
+
+	node1, err := createTestVertex(nil)
+	require.NoError(t, err)
+	node2, err := createTestVertex(nil)
+	require.NoError(t, err)
+
+	var chanIDs []lnwire.ShortChannelID
+	for i, height := range []uint32{100, 100, 200} {
+		edgeInfo, chanID := createEdge(
+			height, uint32(i), 0, uint32(i), node1, node2,
+		)
+		require.NoError(t, graph.AddChannelEdge(&edgeInfo))
+		chanIDs = append(chanIDs, chanID)
+	}
+
+	// A channel without an announcement isn't returned.
+	private, _ := createEdge(150, 0, 0, 10, node1, node2)
+	private.AuthProof = nil
+	require.NoError(t, graph.AddChannelEdge(&private))
+
+	ranges, err := graph.FilterChannelRange(0, 1000, false)
+	require.NoError(t, err)
+	require.Len(t, ranges, 2)
+	require.EqualValues(t, 100, ranges[0].Height)
+	require.Len(t, ranges[0].Channels, 2)
+	require.Equal(t, chanIDs[2], ranges[1].Channels[0].ShortChannelID)
+
+	ranges, err = graph.FilterChannelRange(300, 1000, false)
+	require.NoError(t, err)
+	require.Empty(t, ranges)
+
+	// The channels we already know are filtered out.
+	newChanIDs, err := graph.FilterKnownChanIDs(
+		[]ChannelUpdateInfo{
+			{ShortChannelID: chanIDs[0]},
+			{ShortChannelID: lnwire.NewShortChanIDFromInt(42)},
+		}, func(time.Time, time.Time) bool {
+			return false
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{42}, newChanIDs)
+}
+
+// TestSQLGraphPruning tests that pruning the SQL graph removes the spent
+// channels and the nodes left without channels, and that disconnecting a
+// block rewinds the graph.
+func TestSQLGraphPruning(t *testing.T) {
+	t.Parallel()
+
+	graph := newTestSQLGraph(t)
+
+	_, _, err := graph.PruneTip()
+	require.ErrorIs(t, err, ErrGraphNeverPruned)
+
+	source, err := createTestVertex(nil)
+	require.NoError(t, err)
+	node1, err := createTestVertex(nil)
+	require.NoError(t, err)
+	node2, err := createTestVertex(nil)
+	require.NoError(t, err)
+	require.NoError(t, graph.SetSourceNode(source))
+	require.NoError(t, graph.AddLightningNode(node1))
+	require.NoError(t, graph.AddLightningNode(node2))
+
+	edgeInfo1, _ := createEdge(100, 0, 0, 0, source, node1)
+	require.NoError(t, graph.AddChannelEdge(&edgeInfo1))
+	edgeInfo2, _ := createEdge(200, 0, 0, 1, node1, node2)
+	require.NoError(t, graph.AddChannelEdge(&edgeInfo2))
+
+	// Spending the channel between the two nodes should prune the second
+	// node, as it doesn't have any channels left.
+	blockHash := chainhash.Hash{1}
+	closed, err := graph.PruneGraph(
+		[]*wire.OutPoint{&edgeInfo2.ChannelPoint}, &blockHash, 300,
+	)
+	require.NoError(t, err)
+	require.Len(t, closed, 1)
+	require.Equal(t, edgeInfo2.ChannelID, closed[0].ChannelID)
+
+	tipHash, tipHeight, err := graph.PruneTip()
+	require.NoError(t, err)
+	require.Equal(t, blockHash, *tipHash)
+	require.EqualValues(t, 300, tipHeight)
+
+	_, err = graph.FetchLightningNode(node2.PubKeyBytes)
+	require.ErrorIs(t, err, ErrGraphNodeNotFound)
+	_, err = graph.FetchLightningNode(node1.PubKeyBytes)
+	require.NoError(t, err)
+
+	// Disconnecting the block of the remaining channel removes it, along
+	// with the prune log entry.
+	removed, err := graph.DisconnectBlockAtHeight(100)
+	require.NoError(t, err)
+	require.Len(t, removed, 1)
+	require.Equal(t, edgeInfo1.ChannelID, removed[0].ChannelID)
+
+	_, _, err = graph.PruneTip()
+	require.ErrorIs(t, err, ErrGraphNeverPruned)
+
+	// Pruning the nodes now removes all nodes but the source node.
+	require.NoError(t, graph.PruneGraphNodes())
+	_, err = graph.FetchLightningNode(node1.PubKeyBytes)
+	require.ErrorIs(t, err, ErrGraphNodeNotFound)
+	_, err = graph.SourceNode()
+	require.NoError(t, err)
+}
+
+// TestMigrateGraphToSQL tests that the key-value graph is copied to the SQL
+// graph, and that the migration is only done once.
+func TestMigrateGraphToSQL(t *testing.T) {
+	t.Parallel()
+
+	kvGraph, err := MakeTestGraph(t)
+	require.NoError(t, err)
+
+	source, err := createTestVertex(nil)
+	require.NoError(t, err)
+	node, err := createTestVertex(nil)
+	require.NoError(t, err)
+	require.NoError(t, kvGraph.SetSourceNode(source))
+	require.NoError(t, kvGraph.AddLightningNode(node))
+
+	edgeInfo, edge1, edge2 := createChannelEdge(nil, source, node)
+	require.NoError(t, kvGraph.AddChannelEdge(edgeInfo))
+	require.NoError(t, kvGraph.UpdateEdgePolicy(edge1))
+	require.NoError(t, kvGraph.UpdateEdgePolicy(edge2))
+
+	zombie, _ := createEdge(100, 0, 0, 5, source, node)
+	require.NoError(t, kvGraph.MarkEdgeZombie(
+		zombie.ChannelID, zombie.NodeKey1Bytes, zombie.NodeKey2Bytes,
+	))
+
+	closedScid := lnwire.NewShortChanIDFromInt(1234)
+	require.NoError(t, kvGraph.PutClosedScid(closedScid))
+
+	blockHash := chainhash.Hash{2}
+	_, err = kvGraph.PruneGraph(nil, &blockHash, 500)
+	require.NoError(t, err)
+
+	db := newTestSQLGraphDB(t)
+	require.NoError(t, MigrateGraphToSQL(kvGraph, db))
+
+	sqlGraph, err := NewSQLGraph(
+		db, DefaultRejectCacheSize, DefaultChannelCacheSize, 0, true,
+	)
+	require.NoError(t, err)
+
+	dbSource, err := sqlGraph.SourceNode()
+	require.NoError(t, err)
+	require.NoError(t, compareNodes(source, dbSource))
+
+	dbNode, err := sqlGraph.FetchLightningNode(node.PubKeyBytes)
+	require.NoError(t, err)
+	require.NoError(t, compareNodes(node, dbNode))
+
+	dbInfo, dbEdge1, dbEdge2, err := sqlGraph.FetchChannelEdgesByID(
+		edgeInfo.ChannelID,
+	)
+	require.NoError(t, err)
+	assertEdgeInfoEqual(t, dbInfo, edgeInfo)
+	require.NoError(t, compareEdgePolicies(dbEdge1, edge1))
+	require.NoError(t, compareEdgePolicies(dbEdge2, edge2))
+
+	isZombie, _, _ := sqlGraph.IsZombieEdge(zombie.ChannelID)
+	require.True(t, isZombie)
+
+	isClosed, err := sqlGraph.IsClosedScid(closedScid)
+	require.NoError(t, err)
+	require.True(t, isClosed)
+
+	tipHash, tipHeight, err := sqlGraph.PruneTip()
+	require.NoError(t, err)
+	require.Equal(t, blockHash, *tipHash)
+	require.EqualValues(t, 500, tipHeight)
+
+	// The graph cache must have been populated from the migrated graph.
+	var numChans int
+	err = sqlGraph.ForEachNodeDirectedChannel(nil, source.PubKeyBytes,
+		func(*DirectedChannel) error {
+			numChans++
+			return nil
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 1, numChans)
+
+	// Changes of the SQL graph must not be overwritten by a second
+	// migration.
+	require.NoError(t, sqlGraph.DeleteLightningNode(node.PubKeyBytes))
+	require.NoError(t, MigrateGraphToSQL(kvGraph, db))
+	_, err = sqlGraph.FetchLightningNode(node.PubKeyBytes)
+	require.ErrorIs(t, err, ErrGraphNodeNotFound)
+}
@@ -0,0 +1,228 @@
+package channeldb
+
+import (
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/batch"
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+// GraphStore is the storage interface of the channel graph. It's implemented
+// by the key-value backed ChannelGraph and the native SQL backed SQLGraph.
+//
+// NOTE: Implementations that aren't backed by a key-value store pass nil
+// transactions to the callbacks, and ignore the transactions passed to them.
+type GraphStore interface {
+	// NewPathFindTx returns a new read transaction that can be used for a
+	// single path finding session. Will return nil if the graph cache is
+	// enabled.
+	NewPathFindTx() (kvdb.RTx, error)
+
+	// ForEachChannel iterates through all the channel edges stored within
+	// the graph and invokes the passed callback for each edge.
+	ForEachChannel(cb func(*models.ChannelEdgeInfo,
+		*models.ChannelEdgePolicy,
+		*models.ChannelEdgePolicy) error) error
+
+	// ForEachNodeDirectedChannel iterates through all channels of a given
+	// node, executing the passed callback on the directed edge
+	// representing the channel and its incoming policy.
+	ForEachNodeDirectedChannel(tx kvdb.RTx, node route.Vertex,
+		cb func(channel *DirectedChannel) error) error
+
+	// FetchNodeFeatures returns the features of a given node. If no
+	// features are known for the node, an empty feature vector is
+	// returned.
+	FetchNodeFeatures(node route.Vertex) (*lnwire.FeatureVector, error)
+
+	// ForEachNodeCached is similar to ForEachNode, but it utilizes the
+	// channel graph cache instead.
+	ForEachNodeCached(cb func(node route.Vertex,
+		chans map[uint64]*DirectedChannel) error) error
+
+	// DisabledChannelIDs returns the channel ids of disabled channels.
+	DisabledChannelIDs() ([]uint64, error)
+
+	// ForEachNode iterates through all the stored vertices/nodes in the
+	// graph, executing the passed callback with each node encountered.
+	ForEachNode(cb func(kvdb.RTx, *LightningNode) error) error
+
+	// SourceNode returns the source node of the graph.
+	SourceNode() (*LightningNode, error)
+
+	// SetSourceNode sets the source node within the graph database.
+	SetSourceNode(node *LightningNode) error
+
+	// AddLightningNode adds a vertex/node to the graph database, or
+	// updates it if it's already known.
+	AddLightningNode(node *LightningNode, op ...batch.SchedulerOption) error
+
+	// LookupAlias attempts to return the alias as advertised by the target
+	// node.
+	LookupAlias(pub *btcec.PublicKey) (string, error)
+
+	// DeleteLightningNode removes a vertex/node from the database
+	// according to the node's public key.
+	DeleteLightningNode(nodePub route.Vertex) error
+
+	// AddChannelEdge adds a new (undirected, blank) edge to the graph
+	// database.
+	AddChannelEdge(edge *models.ChannelEdgeInfo,
+		op ...batch.SchedulerOption) error
+
+	// HasChannelEdge returns the update times of both directions of the
+	// channel with the passed channel ID, whether the channel is known
+	// and whether it's a zombie.
+	HasChannelEdge(chanID uint64) (time.Time, time.Time, bool, bool, error)
+
+	// UpdateChannelEdge updates the info of an existing edge of the graph.
+	UpdateChannelEdge(edge *models.ChannelEdgeInfo) error
+
+	// PruneGraph prunes newly closed channels from the channel graph in
+	// response to a new block being solved on the network.
+	PruneGraph(spentOutputs []*wire.OutPoint, blockHash *chainhash.Hash,
+		blockHeight uint32) ([]*models.ChannelEdgeInfo, error)
+
+	// PruneGraphNodes prunes the nodes that don't have any channels from
+	// the graph.
+	PruneGraphNodes() error
+
+	// DisconnectBlockAtHeight rewinds the graph back to the height below
+	// the passed height.
+	DisconnectBlockAtHeight(height uint32) ([]*models.ChannelEdgeInfo,
+		error)
+
+	// PruneTip returns the block height and hash of the latest block that
+	// has been used to prune channels in the graph.
+	PruneTip() (*chainhash.Hash, uint32, error)
+
+	// DeleteChannelEdges removes edges with the given channel IDs from the
+	// database and optionally marks them as zombies.
+	DeleteChannelEdges(strictZombiePruning, markZombie bool,
+		chanIDs ...uint64) error
+
+	// ChannelID attempts to look up the channel ID of the passed channel
+	// point.
+	ChannelID(chanPoint *wire.OutPoint) (uint64, error)
+
+	// HighestChanID returns the "highest" known channel ID in the channel
+	// graph.
+	HighestChanID() (uint64, error)
+
+	// ChanUpdatesInHorizon returns all the known channel edges which have
+	// at least one edge that has an update timestamp within the specified
+	// horizon.
+	ChanUpdatesInHorizon(startTime, endTime time.Time) ([]ChannelEdge,
+		error)
+
+	// NodeUpdatesInHorizon returns all the known lightning nodes which
+	// have an update timestamp within the passed range.
+	NodeUpdatesInHorizon(startTime, endTime time.Time) ([]LightningNode,
+		error)
+
+	// FilterKnownChanIDs returns the subset of the passed channels that we
+	// don't know and that aren't known zombies.
+	FilterKnownChanIDs(chansInfo []ChannelUpdateInfo,
+		isZombieChan func(time.Time, time.Time) bool) ([]uint64, error)
+
+	// FilterChannelRange returns the channel ID's of all known channels
+	// which were mined in a block height within the passed range.
+	FilterChannelRange(startHeight, endHeight uint32,
+		withTimestamps bool) ([]BlockChannelRange, error)
+
+	// FetchChanInfos returns the set of channel edges that correspond to
+	// the passed channel ID's.
+	FetchChanInfos(chanIDs []uint64) ([]ChannelEdge, error)
+
+	// UpdateEdgePolicy updates the edge routing policy for a single
+	// directed edge within the database.
+	UpdateEdgePolicy(edge *models.ChannelEdgePolicy,
+		op ...batch.SchedulerOption) error
+
+	// FetchLightningNodeTx attempts to look up a target node by its
+	// identity public key, using the passed transaction if any.
+	FetchLightningNodeTx(tx kvdb.RTx, nodePub route.Vertex) (
+		*LightningNode, error)
+
+	// FetchLightningNode attempts to look up a target node by its identity
+	// public key.
+	FetchLightningNode(nodePub route.Vertex) (*LightningNode, error)
+
+	// HasLightningNode determines if the graph has a vertex identified by
+	// the target node identity public key.
+	HasLightningNode(nodePub [33]byte) (time.Time, bool, error)
+
+	// ForEachNodeChannel iterates through all channels of the given node.
+	ForEachNodeChannel(nodePub route.Vertex, cb func(kvdb.RTx,
+		*models.ChannelEdgeInfo, *models.ChannelEdgePolicy,
+		*models.ChannelEdgePolicy) error) error
+
+	// ForEachNodeChannelTx iterates through all channels of the given
+	// node, using the passed transaction if any.
+	ForEachNodeChannelTx(tx kvdb.RTx, nodePub route.Vertex,
+		cb func(kvdb.RTx, *models.ChannelEdgeInfo,
+			*models.ChannelEdgePolicy,
+			*models.ChannelEdgePolicy) error) error
+
+	// FetchOtherNode attempts to fetch the full LightningNode that's
+	// opposite of the target node in the channel.
+	FetchOtherNode(tx kvdb.RTx, channel *models.ChannelEdgeInfo,
+		thisNodeKey []byte) (*LightningNode, error)
+
+	// FetchChannelEdgesByOutpoint attempts to look up the two directed
+	// edges for the channel identified by the funding outpoint.
+	FetchChannelEdgesByOutpoint(op *wire.OutPoint) (
+		*models.ChannelEdgeInfo, *models.ChannelEdgePolicy,
+		*models.ChannelEdgePolicy, error)
+
+	// FetchChannelEdgesByID attempts to look up the two directed edges for
+	// the channel identified by the channel ID.
+	FetchChannelEdgesByID(chanID uint64) (*models.ChannelEdgeInfo,
+		*models.ChannelEdgePolicy, *models.ChannelEdgePolicy, error)
+
+	// IsPublicNode is a helper method that determines whether the node
+	// with the given public key is seen as a public node in the graph from
+	// the graph's source node's point of view.
+	IsPublicNode(pubKey [33]byte) (bool, error)
+
+	// ChannelView returns the verifiable edge information for each active
+	// channel within the known channel graph.
+	ChannelView() ([]EdgePoint, error)
+
+	// MarkEdgeZombie attempts to mark a channel identified by its channel
+	// ID as a zombie.
+	MarkEdgeZombie(chanID uint64, pubKey1, pubKey2 [33]byte) error
+
+	// MarkEdgeLive clears an edge from our zombie index, deeming it as
+	// live.
+	MarkEdgeLive(chanID uint64) error
+
+	// IsZombieEdge returns whether the edge is considered zombie. If it is
+	// a zombie, then the two node public keys corresponding to this edge
+	// are also returned.
+	IsZombieEdge(chanID uint64) (bool, [33]byte, [33]byte)
+
+	// NumZombies returns the current number of zombie channels in the
+	// graph.
+	NumZombies() (uint64, error)
+
+	// PutClosedScid stores a SCID for a closed channel in the database.
+	PutClosedScid(scid lnwire.ShortChannelID) error
+
+	// IsClosedScid checks whether a channel identified by the passed in
+	// scid is closed.
+	IsClosedScid(scid lnwire.ShortChannelID) (bool, error)
+}
+
+// A compile-time check to ensure both graph implementations satisfy the
+// GraphStore interface.
+var (
+	_ GraphStore = (*ChannelGraph)(nil)
+	_ GraphStore = (*SQLGraph)(nil)
+)
@@ -136,6 +136,9 @@ type graph interface {
 	FetchNodeFeatures(node route.Vertex) (*lnwire.FeatureVector, error)
 }
 
-// A compile-time check to ensure that *channeldb.ChannelGraph implements the
-// graph interface.
-var _ graph = (*channeldb.ChannelGraph)(nil)
+// A compile-time check to ensure that both channel graph implementations
+// implement the graph interface.
+var (
+	_ graph = (*channeldb.ChannelGraph)(nil)
+	_ graph = (*channeldb.SQLGraph)(nil)
+)
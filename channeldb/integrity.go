@@ -0,0 +1,223 @@
+package channeldb
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// OrphanedEdge is an edge of our own node in the channel graph for which no
+// channel exists in the channel state database.
+type OrphanedEdge struct {
+	// ChannelID is the short channel ID of the edge.
+	ChannelID uint64
+
+	// ChannelPoint is the funding outpoint of the edge.
+	ChannelPoint wire.OutPoint
+}
+
+// IntegrityReport lists the references between the channel graph and the
+// channel state database that point to channels we no longer have.
+type IntegrityReport struct {
+	// OrphanedEdges are the edges of our own node in the channel graph
+	// whose channel is neither open, pending nor waiting to be closed.
+	OrphanedEdges []OrphanedEdge
+
+	// OrphanedFwdPkgs are the short channel IDs of the channels that
+	// forwarding packages are stored for, but that are neither open,
+	// pending nor waiting to be closed.
+	OrphanedFwdPkgs []lnwire.ShortChannelID
+}
+
+// IsEmpty returns true if no orphaned references were found.
+func (r *IntegrityReport) IsEmpty() bool {
+	return len(r.OrphanedEdges) == 0 && len(r.OrphanedFwdPkgs) == 0
+}
+
+// CheckIntegrity validates the references between the channel graph and the
+// channel state database. Edges of our own node and forwarding packages that
+// refer to channels that aren't in the channel state database anymore are
+// reported as orphans.
+func CheckIntegrity(graph GraphStore,
+	chanState *ChannelStateDB) (*IntegrityReport, error) {
+
+	// Both the edges and the forwarding packages of channels that are
+	// still waiting to be closed are in use, so we only consider channels
+	// that have been fully removed from the open channel bucket.
+	channels, err := chanState.FetchAllChannels()
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch channels: %w", err)
+	}
+
+	chanPoints := make(map[wire.OutPoint]struct{}, len(channels))
+	scids := make(map[lnwire.ShortChannelID]struct{}, len(channels))
+	for _, channel := range channels {
+		chanPoints[channel.FundingOutpoint] = struct{}{}
+		scids[channel.ShortChannelID] = struct{}{}
+	}
+
+	report := &IntegrityReport{}
+
+	report.OrphanedEdges, err = findOrphanedEdges(graph, chanPoints)
+	if err != nil {
+		return nil, err
+	}
+
+	report.OrphanedFwdPkgs, err = findOrphanedFwdPkgs(chanState, scids)
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// findOrphanedEdges returns the edges of our own node whose funding outpoint
+// isn't among the given channel points.
+func findOrphanedEdges(graph GraphStore,
+	chanPoints map[wire.OutPoint]struct{}) ([]OrphanedEdge, error) {
+
+	sourceNode, err := graph.SourceNode()
+	switch {
+	// Without a source node, there are no edges of our own node.
+	case errors.Is(err, ErrSourceNodeNotSet),
+		errors.Is(err, ErrGraphNotFound):
+
+		return nil, nil
+
+	case err != nil:
+		return nil, fmt.Errorf("unable to fetch source node: %w", err)
+	}
+
+	var orphans []OrphanedEdge
+	err = graph.ForEachNodeChannel(sourceNode.PubKeyBytes,
+		func(_ kvdb.RTx, info *models.ChannelEdgeInfo,
+			_, _ *models.ChannelEdgePolicy) error {
+
+			if _, ok := chanPoints[info.ChannelPoint]; ok {
+				return nil
+			}
+
+			orphans = append(orphans, OrphanedEdge{
+				ChannelID:    info.ChannelID,
+				ChannelPoint: info.ChannelPoint,
+			})
+
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to iterate edges: %w", err)
+	}
+
+	return orphans, nil
+}
+
+// findOrphanedFwdPkgs returns the sources of the stored forwarding packages
+// that aren't among the given short channel IDs.
+func findOrphanedFwdPkgs(chanState *ChannelStateDB,
+	scids map[lnwire.ShortChannelID]struct{}) ([]lnwire.ShortChannelID,
+	error) {
+
+	var orphans []lnwire.ShortChannelID
+	err := kvdb.View(chanState.backend, func(tx kvdb.RTx) error {
+		fwdPkgBkt := tx.ReadBucket(fwdPackagesKey)
+		if fwdPkgBkt == nil {
+			return nil
+		}
+
+		return fwdPkgBkt.ForEach(func(k, _ []byte) error {
+			if len(k) != 8 {
+				return ErrCorruptedFwdPkg
+			}
+
+			source := lnwire.NewShortChanIDFromInt(
+				byteOrder.Uint64(k),
+			)
+			if _, ok := scids[source]; ok {
+				return nil
+			}
+
+			orphans = append(orphans, source)
+
+			return nil
+		})
+	}, func() {
+		orphans = nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to iterate forwarding "+
+			"packages: %w", err)
+	}
+
+	return orphans, nil
+}
+
+// RemoveOrphans removes the orphaned references listed in the report. To not
+// act on a stale report, the references are checked again first, and only
+// those that are still orphaned are removed. The removed references are
+// returned.
+//
+// Orphaned edges are removed from the graph without being marked as zombies,
+// so they can be re-added if they're announced again.
+func RemoveOrphans(graph GraphStore, chanState *ChannelStateDB,
+	report *IntegrityReport) (*IntegrityReport, error) {
+
+	current, err := CheckIntegrity(graph, chanState)
+	if err != nil {
+		return nil, err
+	}
+
+	removed := &IntegrityReport{}
+
+	stillOrphanedEdges := fn.NewSet(current.OrphanedEdges...)
+	var chanIDs []uint64
+	for _, edge := range report.OrphanedEdges {
+		if !stillOrphanedEdges.Contains(edge) {
+			continue
+		}
+
+		chanIDs = append(chanIDs, edge.ChannelID)
+		removed.OrphanedEdges = append(removed.OrphanedEdges, edge)
+	}
+
+	if len(chanIDs) > 0 {
+		err := graph.DeleteChannelEdges(false, false, chanIDs...)
+		if err != nil {
+			return nil, fmt.Errorf("unable to delete orphaned "+
+				"edges: %w", err)
+		}
+	}
+
+	stillOrphanedPkgs := fn.NewSet(current.OrphanedFwdPkgs...)
+	for _, source := range report.OrphanedFwdPkgs {
+		if !stillOrphanedPkgs.Contains(source) {
+			continue
+		}
+
+		removed.OrphanedFwdPkgs = append(
+			removed.OrphanedFwdPkgs, source,
+		)
+	}
+
+	err = kvdb.Update(chanState.backend, func(tx kvdb.RwTx) error {
+		for _, source := range removed.OrphanedFwdPkgs {
+			err := NewChannelPackager(source).Wipe(tx)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func() {})
+	if err != nil {
+		return nil, fmt.Errorf("unable to delete orphaned forwarding "+
+			"packages: %w", err)
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,112 @@
+package channeldb
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestIntegrityCheck tests that edges of our own node and forwarding packages
+// of channels we no longer have are reported and removed as orphans, while
+// those of open channels are kept.
+func TestIntegrityCheck(t *testing.T) {
+	t.Parallel()
+
+	fullDB, err := MakeTestDB(t)
+	require.NoError(t, err, "unable to make test database")
+
+	graph := fullDB.ChannelGraph()
+	chanState := fullDB.ChannelStateDB()
+
+	// An empty database has no orphans.
+	report, err := CheckIntegrity(graph, chanState)
+	require.NoError(t, err)
+	require.True(t, report.IsEmpty())
+
+	sourceNode, err := createTestVertex(fullDB)
+	require.NoError(t, err)
+	require.NoError(t, graph.SetSourceNode(sourceNode))
+
+	remoteNode, err := createTestVertex(fullDB)
+	require.NoError(t, err)
+	require.NoError(t, graph.AddLightningNode(remoteNode))
+
+	// addEdge adds an edge between our node and the remote node with the
+	// given funding outpoint.
+	addEdge := func(chanID uint64, chanPoint wire.OutPoint) {
+		edge, _, _ := createChannelEdge(fullDB, sourceNode, remoteNode)
+		edge.ChannelID = chanID
+		edge.ChannelPoint = chanPoint
+		require.NoError(t, graph.AddChannelEdge(edge))
+	}
+
+	// addFwdPkg adds a forwarding package for the given channel.
+	addFwdPkg := func(source lnwire.ShortChannelID) {
+		err := kvdb.Update(fullDB, func(tx kvdb.RwTx) error {
+			return NewChannelPackager(source).AddFwdPkg(
+				tx, NewFwdPkg(source, 0, nil, nil),
+			)
+		}, func() {})
+		require.NoError(t, err)
+	}
+
+	// We have one open channel with both an edge and a forwarding
+	// package.
+	channel := createTestChannel(t, chanState, openChannelOption())
+	addEdge(channel.ShortChannelID.ToUint64(), channel.FundingOutpoint)
+	addFwdPkg(channel.ShortChannelID)
+
+	// A channel we no longer have left both behind.
+	orphanedSCID := lnwire.NewShortChanIDFromInt(
+		channel.ShortChannelID.ToUint64() + 1,
+	)
+	orphanedChanPoint := wire.OutPoint{Index: 1000}
+	addEdge(orphanedSCID.ToUint64(), orphanedChanPoint)
+	addFwdPkg(orphanedSCID)
+
+	expected := &IntegrityReport{
+		OrphanedEdges: []OrphanedEdge{{
+			ChannelID:    orphanedSCID.ToUint64(),
+			ChannelPoint: orphanedChanPoint,
+		}},
+		OrphanedFwdPkgs: []lnwire.ShortChannelID{orphanedSCID},
+	}
+
+	report, err = CheckIntegrity(graph, chanState)
+	require.NoError(t, err)
+	require.Equal(t, expected, report)
+
+	// Removing the orphans leaves the references of the open channel
+	// intact.
+	removed, err := RemoveOrphans(graph, chanState, report)
+	require.NoError(t, err)
+	require.Equal(t, expected, removed)
+
+	report, err = CheckIntegrity(graph, chanState)
+	require.NoError(t, err)
+	require.True(t, report.IsEmpty())
+
+	_, _, exists, _, err := graph.HasChannelEdge(
+		channel.ShortChannelID.ToUint64(),
+	)
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Len(t, loadFwdPkgs(t, fullDB, channel.Packager), 1)
+
+	// The removed edge isn't marked as a zombie.
+	_, _, exists, isZombie, err := graph.HasChannelEdge(
+		orphanedSCID.ToUint64(),
+	)
+	require.NoError(t, err)
+	require.False(t, exists)
+	require.False(t, isZombie)
+
+	// A stale report doesn't remove references that aren't orphaned
+	// anymore.
+	removed, err = RemoveOrphans(graph, chanState, expected)
+	require.NoError(t, err)
+	require.True(t, removed.IsEmpty())
+}
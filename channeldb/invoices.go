@@ -126,6 +126,11 @@ const (
 	hodlInvoiceType     tlv.Type = 14
 	invoiceAmpStateType tlv.Type = 15
 
+	// The overpayment policy types are odd, so that older versions that
+	// don't know about them can still read the invoice.
+	overpaymentModeType       tlv.Type = 17
+	overpaymentMaxPercentType tlv.Type = 19
+
 	// A set of tlv type definitions used to serialize the invoice AMP
 	// state along-side the main invoice body.
 	ampStateSetIDType       tlv.Type = 0
@@ -1246,7 +1251,10 @@ func serializeInvoice(w io.Writer, i *invpkg.Invoice) error {
 		hodlInvoice = 1
 	}
 
-	tlvStream, err := tlv.NewStream(
+	overpaymentMode := uint8(i.OverpaymentPolicy.Mode)
+	overpaymentMaxPercent := i.OverpaymentPolicy.MaxPercent
+
+	records := []tlv.Record{
 		// Memo and payreq.
 		tlv.MakePrimitiveRecord(memoType, &i.Memo),
 		tlv.MakePrimitiveRecord(payReqType, &i.PaymentRequest),
@@ -1277,7 +1285,23 @@ func serializeInvoice(w io.Writer, i *invpkg.Invoice) error {
 			ampRecordSize(&i.AMPState),
 			ampStateEncoder, ampStateDecoder,
 		),
-	)
+	}
+
+	// Only store the overpayment policy if the invoice doesn't use the
+	// node wide one, so the format of other invoices stays the same.
+	if !i.OverpaymentPolicy.IsDefault() {
+		records = append(records,
+			tlv.MakePrimitiveRecord(
+				overpaymentModeType, &overpaymentMode,
+			),
+			tlv.MakePrimitiveRecord(
+				overpaymentMaxPercentType,
+				&overpaymentMaxPercent,
+			),
+		)
+	}
+
+	tlvStream, err := tlv.NewStream(records...)
 	if err != nil {
 		return err
 	}
@@ -1637,6 +1661,9 @@ func deserializeInvoice(r io.Reader) (invpkg.Invoice, error) {
 		state         uint8
 		hodlInvoice   uint8
 
+		overpaymentMode       uint8
+		overpaymentMaxPercent uint32
+
 		creationDateBytes []byte
 		settleDateBytes   []byte
 		featureBytes      []byte
@@ -1674,6 +1701,12 @@ func deserializeInvoice(r io.Reader) (invpkg.Invoice, error) {
 			invoiceAmpStateType, &i.AMPState, nil,
 			ampStateEncoder, ampStateDecoder,
 		),
+
+		// Overpayment policy.
+		tlv.MakePrimitiveRecord(overpaymentModeType, &overpaymentMode),
+		tlv.MakePrimitiveRecord(
+			overpaymentMaxPercentType, &overpaymentMaxPercent,
+		),
 	)
 	if err != nil {
 		return i, err
@@ -1705,6 +1738,11 @@ func deserializeInvoice(r io.Reader) (invpkg.Invoice, error) {
 		i.HodlInvoice = true
 	}
 
+	i.OverpaymentPolicy = invpkg.OverpaymentPolicy{
+		Mode:       invpkg.OverpaymentMode(overpaymentMode),
+		MaxPercent: overpaymentMaxPercent,
+	}
+
 	err = i.CreationDate.UnmarshalBinary(creationDateBytes)
 	if err != nil {
 		return i, err
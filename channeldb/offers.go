@@ -0,0 +1,333 @@
+package channeldb
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sort"
+
+	invpkg "github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// offersBucket is the name of the top level bucket that stores the
+	// BOLT 12 offers that we issued, together with the state of their
+	// payments.
+	//
+	// offers-bucket
+	//      |
+	//      |-- offer-index
+	//      |        |--<offer-id>: <offer-state>
+	//      |
+	//      |-- offer-invoice-index
+	//      |        |--<payment-hash>: <offer-id>
+	//      |
+	//      |-- offer-settle-index: <settle index>
+	offersBucket = []byte("offers-bucket")
+
+	// offerIndexBucket is the sub-bucket of the offers bucket that stores
+	// the offers keyed by their id.
+	offerIndexBucket = []byte("offer-index")
+
+	// offerInvoiceIndexBucket is the sub-bucket of the offers bucket that
+	// maps the payment hashes of the invoices that were created for
+	// offers to the ids of their offers.
+	offerInvoiceIndexBucket = []byte("offer-invoice-index")
+
+	// offerSettleIndexKey is the key in the offers bucket that stores the
+	// settle index of the last invoice settlement that was recorded.
+	offerSettleIndexKey = []byte("offer-settle-index")
+)
+
+// A compile-time check to ensure DB implements the invoices.OfferDB
+// interface.
+var _ invpkg.OfferDB = (*DB)(nil)
+
+// AddOffer inserts the offer into the database. If an offer with the same id
+// already exists, ErrDuplicateOffer is returned.
+func (d *DB) AddOffer(_ context.Context, offer *invpkg.OfferState) error {
+	var b bytes.Buffer
+	if err := serializeOfferState(&b, offer); err != nil {
+		return err
+	}
+
+	return kvdb.Update(d, func(tx kvdb.RwTx) error {
+		offers, err := tx.CreateTopLevelBucket(offersBucket)
+		if err != nil {
+			return err
+		}
+
+		offerIndex, err := offers.CreateBucketIfNotExists(
+			offerIndexBucket,
+		)
+		if err != nil {
+			return err
+		}
+
+		if offerIndex.Get(offer.ID[:]) != nil {
+			return invpkg.ErrDuplicateOffer
+		}
+
+		return offerIndex.Put(offer.ID[:], b.Bytes())
+	}, func() {})
+}
+
+// FetchOffer returns the offer with the given id. If the offer isn't found,
+// ErrOfferNotFound is returned.
+func (d *DB) FetchOffer(_ context.Context,
+	id invpkg.OfferID) (*invpkg.OfferState, error) {
+
+	var offer *invpkg.OfferState
+	err := kvdb.View(d, func(tx kvdb.RTx) error {
+		offerIndex := fetchOfferIndex(tx)
+		if offerIndex == nil {
+			return invpkg.ErrOfferNotFound
+		}
+
+		offerBytes := offerIndex.Get(id[:])
+		if offerBytes == nil {
+			return invpkg.ErrOfferNotFound
+		}
+
+		var err error
+		offer, err = deserializeOfferState(
+			bytes.NewReader(offerBytes), id,
+		)
+
+		return err
+	}, func() {
+		offer = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return offer, nil
+}
+
+// FetchOffers returns all offers, ordered by their creation time.
+func (d *DB) FetchOffers(_ context.Context) ([]*invpkg.OfferState, error) {
+	var offers []*invpkg.OfferState
+	err := kvdb.View(d, func(tx kvdb.RTx) error {
+		offerIndex := fetchOfferIndex(tx)
+		if offerIndex == nil {
+			return nil
+		}
+
+		return offerIndex.ForEach(func(k, v []byte) error {
+			var id invpkg.OfferID
+			copy(id[:], k)
+
+			offer, err := deserializeOfferState(
+				bytes.NewReader(v), id,
+			)
+			if err != nil {
+				return err
+			}
+
+			offers = append(offers, offer)
+
+			return nil
+		})
+	}, func() {
+		offers = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(offers, func(i, j int) bool {
+		return offers[i].CreationDate.Before(offers[j].CreationDate)
+	})
+
+	return offers, nil
+}
+
+// AddOfferInvoice links the invoice with the given payment hash to the offer
+// that it was created for.
+func (d *DB) AddOfferInvoice(_ context.Context, id invpkg.OfferID,
+	paymentHash lntypes.Hash) error {
+
+	return kvdb.Update(d, func(tx kvdb.RwTx) error {
+		offers := tx.ReadWriteBucket(offersBucket)
+		if offers == nil {
+			return invpkg.ErrOfferNotFound
+		}
+
+		offerIndex := offers.NestedReadWriteBucket(offerIndexBucket)
+		if offerIndex == nil || offerIndex.Get(id[:]) == nil {
+			return invpkg.ErrOfferNotFound
+		}
+
+		invoiceIndex, err := offers.CreateBucketIfNotExists(
+			offerInvoiceIndexBucket,
+		)
+		if err != nil {
+			return err
+		}
+
+		return invoiceIndex.Put(paymentHash[:], id[:])
+	}, func() {})
+}
+
+// RecordOfferPayment records the settlement of the invoice with the given
+// payment hash and settle index. If the invoice belongs to an offer, the
+// amount is added to the payments of the offer. The settle index is stored
+// either way, so that settlements can be resumed after a restart.
+func (d *DB) RecordOfferPayment(_ context.Context, paymentHash lntypes.Hash,
+	amt lnwire.MilliSatoshi, settleIndex uint64) error {
+
+	return kvdb.Update(d, func(tx kvdb.RwTx) error {
+		offers, err := tx.CreateTopLevelBucket(offersBucket)
+		if err != nil {
+			return err
+		}
+
+		var settleIndexBytes [8]byte
+		byteOrder.PutUint64(settleIndexBytes[:], settleIndex)
+		err = offers.Put(offerSettleIndexKey, settleIndexBytes[:])
+		if err != nil {
+			return err
+		}
+
+		invoiceIndex := offers.NestedReadWriteBucket(
+			offerInvoiceIndexBucket,
+		)
+		if invoiceIndex == nil {
+			return nil
+		}
+
+		idBytes := invoiceIndex.Get(paymentHash[:])
+		if idBytes == nil {
+			return nil
+		}
+
+		var id invpkg.OfferID
+		copy(id[:], idBytes)
+
+		offerIndex := offers.NestedReadWriteBucket(offerIndexBucket)
+		if offerIndex == nil {
+			return invpkg.ErrOfferNotFound
+		}
+
+		offerBytes := offerIndex.Get(id[:])
+		if offerBytes == nil {
+			return invpkg.ErrOfferNotFound
+		}
+
+		offer, err := deserializeOfferState(
+			bytes.NewReader(offerBytes), id,
+		)
+		if err != nil {
+			return err
+		}
+
+		offer.NumPayments++
+		offer.AmtPaid += amt
+
+		var b bytes.Buffer
+		if err := serializeOfferState(&b, offer); err != nil {
+			return err
+		}
+
+		// The invoice can only be settled once, so there's no need to
+		// keep it in the index.
+		if err := invoiceIndex.Delete(paymentHash[:]); err != nil {
+			return err
+		}
+
+		return offerIndex.Put(id[:], b.Bytes())
+	}, func() {})
+}
+
+// OfferSettleIndex returns the settle index of the last invoice settlement
+// that was recorded.
+func (d *DB) OfferSettleIndex(_ context.Context) (uint64, error) {
+	var settleIndex uint64
+	err := kvdb.View(d, func(tx kvdb.RTx) error {
+		offers := tx.ReadBucket(offersBucket)
+		if offers == nil {
+			return nil
+		}
+
+		settleIndexBytes := offers.Get(offerSettleIndexKey)
+		if len(settleIndexBytes) != 8 {
+			return nil
+		}
+
+		settleIndex = byteOrder.Uint64(settleIndexBytes)
+
+		return nil
+	}, func() {
+		settleIndex = 0
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return settleIndex, nil
+}
+
+// fetchOfferIndex returns the bucket that stores the offers, or nil if no
+// offer was added yet.
+func fetchOfferIndex(tx kvdb.RTx) kvdb.RBucket {
+	offers := tx.ReadBucket(offersBucket)
+	if offers == nil {
+		return nil
+	}
+
+	return offers.NestedReadBucket(offerIndexBucket)
+}
+
+// serializeOfferState serializes the offer and the state of its payments.
+func serializeOfferState(w io.Writer, offer *invpkg.OfferState) error {
+	var offerBytes bytes.Buffer
+	if err := offer.Offer.Encode(&offerBytes); err != nil {
+		return err
+	}
+
+	if err := WriteElement(w, offerBytes.Bytes()); err != nil {
+		return err
+	}
+
+	if err := serializeTime(w, offer.CreationDate); err != nil {
+		return err
+	}
+
+	return WriteElements(w, offer.NumPayments, offer.AmtPaid)
+}
+
+// deserializeOfferState deserializes the offer with the given id and the state
+// of its payments.
+func deserializeOfferState(r io.Reader,
+	id invpkg.OfferID) (*invpkg.OfferState, error) {
+
+	var offerBytes []byte
+	if err := ReadElement(r, &offerBytes); err != nil {
+		return nil, err
+	}
+
+	offer := &invpkg.OfferState{
+		ID:    id,
+		Offer: &invpkg.Offer{},
+	}
+	err := offer.Offer.Decode(bytes.NewReader(offerBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	offer.CreationDate, err = deserializeTime(r)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ReadElements(r, &offer.NumPayments, &offer.AmtPaid)
+	if err != nil {
+		return nil, err
+	}
+
+	return offer, nil
+}
@@ -0,0 +1,106 @@
+package channeldb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	invpkg "github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOffers tests that offers are stored and that the payments of their
+// invoices are recorded.
+func TestOffers(t *testing.T) {
+	t.Parallel()
+
+	db, err := MakeTestDB(t)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	// Nothing is stored yet.
+	offers, err := db.FetchOffers(ctx)
+	require.NoError(t, err)
+	require.Empty(t, offers)
+
+	settleIndex, err := db.OfferSettleIndex(ctx)
+	require.NoError(t, err)
+	require.Zero(t, settleIndex)
+
+	_, err = db.FetchOffer(ctx, invpkg.OfferID{1})
+	require.ErrorIs(t, err, invpkg.ErrOfferNotFound)
+
+	priv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	newOffer := func(id invpkg.OfferID,
+		created time.Time) *invpkg.OfferState {
+
+		return &invpkg.OfferState{
+			ID: id,
+			Offer: &invpkg.Offer{
+				Metadata:    id[:],
+				Amount:      1_000,
+				Description: "coffee",
+				IssuerID:    priv.PubKey(),
+			},
+			CreationDate: created,
+		}
+	}
+
+	// Add two offers, the second one created before the first one.
+	offer1 := newOffer(invpkg.OfferID{1}, time.Unix(200, 0))
+	offer2 := newOffer(invpkg.OfferID{2}, time.Unix(100, 0))
+	require.NoError(t, db.AddOffer(ctx, offer1))
+	require.NoError(t, db.AddOffer(ctx, offer2))
+
+	err = db.AddOffer(ctx, offer1)
+	require.ErrorIs(t, err, invpkg.ErrDuplicateOffer)
+
+	offers, err = db.FetchOffers(ctx)
+	require.NoError(t, err)
+	require.Len(t, offers, 2)
+	require.Equal(t, offer2.ID, offers[0].ID)
+	require.Equal(t, offer1.ID, offers[1].ID)
+
+	fetched, err := db.FetchOffer(ctx, offer1.ID)
+	require.NoError(t, err)
+	require.Equal(t, offer1.CreationDate, fetched.CreationDate)
+	require.Equal(t, offer1.Offer.Metadata, fetched.Offer.Metadata)
+	require.Equal(t, offer1.Offer.Amount, fetched.Offer.Amount)
+	require.True(t, offer1.Offer.IssuerID.IsEqual(fetched.Offer.IssuerID))
+
+	// Invoices can only be linked to known offers.
+	hash := lntypes.Hash{1}
+	err = db.AddOfferInvoice(ctx, invpkg.OfferID{3}, hash)
+	require.ErrorIs(t, err, invpkg.ErrOfferNotFound)
+	require.NoError(t, db.AddOfferInvoice(ctx, offer1.ID, hash))
+
+	// The payment of an invoice that doesn't belong to an offer only
+	// advances the settle index.
+	require.NoError(t, db.RecordOfferPayment(ctx, lntypes.Hash{2}, 500, 1))
+
+	settleIndex, err = db.OfferSettleIndex(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, settleIndex)
+
+	// The payment of the offer invoice is added to its offer.
+	require.NoError(t, db.RecordOfferPayment(ctx, hash, 1_000, 2))
+
+	fetched, err = db.FetchOffer(ctx, offer1.ID)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, fetched.NumPayments)
+	require.EqualValues(t, 1_000, fetched.AmtPaid)
+
+	settleIndex, err = db.OfferSettleIndex(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, settleIndex)
+
+	// The other offer wasn't paid.
+	fetched, err = db.FetchOffer(ctx, offer2.ID)
+	require.NoError(t, err)
+	require.Zero(t, fetched.NumPayments)
+}
@@ -82,6 +82,10 @@ type Options struct {
 	// storeFinalHtlcResolutions determines whether to persistently store
 	// the final resolution of incoming htlcs.
 	storeFinalHtlcResolutions bool
+
+	// sqlGraph, if set, is the SQL database the channel graph is stored in
+	// instead of the key-value backend.
+	sqlGraph BatchedSQLGraphQueries
 }
 
 // DefaultOptions returns an Options populated with default values.
@@ -220,3 +224,12 @@ func OptionPruneRevocationLog(prune bool) OptionModifier {
 		o.OptionalMiragtionConfig.PruneRevocationLog = prune
 	}
 }
+
+// OptionSetSQLGraph sets the SQL database the channel graph is stored in. The
+// key-value graph is migrated to it when the database is opened, unless the
+// SQL graph has been populated already.
+func OptionSetSQLGraph(db BatchedSQLGraphQueries) OptionModifier {
+	return func(o *Options) {
+		o.sqlGraph = db
+	}
+}
@@ -227,6 +227,18 @@ func (p *PaymentControl) InitPayment(paymentHash lntypes.Hash,
 			return err
 		}
 
+		// Store the group of the payment, if any. A payment that is
+		// retried without a group, such as by the payment retry
+		// queue, keeps the group of its earlier attempt.
+		if info.PaymentGroup != "" {
+			err = bucket.Put(
+				paymentGroupKey, []byte(info.PaymentGroup),
+			)
+			if err != nil {
+				return err
+			}
+		}
+
 		// We'll delete any lingering HTLCs to start with, in case we
 		// are initializing a payment that was attempted earlier, but
 		// left in a state where we could retry.
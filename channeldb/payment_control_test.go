@@ -1366,3 +1366,44 @@ func assertPayments(t *testing.T, db *DB, payments []*payment) {
 	// Check that each payment we want to assert exists in the database.
 	require.Equal(t, payments, p)
 }
+
+// TestPaymentControlPaymentGroup checks that the group of a payment is
+// persisted, and retained if the payment is initialized again without one.
+func TestPaymentControlPaymentGroup(t *testing.T) {
+	t.Parallel()
+
+	db, err := MakeTestDB(t)
+	require.NoError(t, err, "unable to init db")
+
+	pControl := NewPaymentControl(db)
+
+	info, _, _, err := genInfo()
+	require.NoError(t, err, "unable to generate htlc message")
+
+	info.PaymentGroup = "payroll"
+	err = pControl.InitPayment(info.PaymentIdentifier, info)
+	require.NoError(t, err)
+
+	payment, err := pControl.FetchPayment(info.PaymentIdentifier)
+	require.NoError(t, err)
+	require.Equal(t, "payroll", payment.Info.PaymentGroup)
+
+	// Fail the payment and initialize it again without a group, which
+	// must keep the group of the first attempt.
+	_, err = pControl.Fail(info.PaymentIdentifier, FailureReasonNoRoute)
+	require.NoError(t, err)
+
+	info.PaymentGroup = ""
+	err = pControl.InitPayment(info.PaymentIdentifier, info)
+	require.NoError(t, err)
+
+	payment, err = pControl.FetchPayment(info.PaymentIdentifier)
+	require.NoError(t, err)
+	require.Equal(t, "payroll", payment.Info.PaymentGroup)
+
+	// The group also shows up when listing payments.
+	payments, err := db.FetchPayments()
+	require.NoError(t, err)
+	require.Len(t, payments, 1)
+	require.Equal(t, "payroll", payments[0].Info.PaymentGroup)
+}
@@ -32,6 +32,7 @@ var (
 	//      |-- <paymenthash>
 	//      |        |--sequence-key: <sequence number>
 	//      |        |--creation-info-key: <creation info>
+	//      |        |--payment-group: <(optional) payment group>
 	//      |        |--fail-info-key: <(optional) fail info>
 	//      |        |
 	//      |        |--payment-htlcs-bucket (shard-bucket)
@@ -71,6 +72,10 @@ var (
 	// store the creation info of the payment.
 	paymentCreationInfoKey = []byte("payment-creation-info")
 
+	// paymentGroupKey is a key used in the payment's sub-bucket to store
+	// the caller-supplied group of the payment, if any.
+	paymentGroupKey = []byte("payment-group")
+
 	// paymentHtlcsBucket is a bucket where we'll store the information
 	// about the HTLCs that were attempted for a payment.
 	paymentHtlcsBucket = []byte("payment-htlcs-bucket")
@@ -200,6 +205,12 @@ type PaymentCreationInfo struct {
 	// first hop of this payment. These records will be transmitted via the
 	// wire message only and therefore do not affect the onion payload size.
 	FirstHopCustomRecords lnwire.CustomRecords
+
+	// PaymentGroup is an optional caller-supplied identifier that groups
+	// related payments, so they can be tracked together. It is stored
+	// separately from the serialized creation info and retained if the
+	// payment is initialized again without a group.
+	PaymentGroup string
 }
 
 // htlcBucketKey creates a composite key from prefix and id where the result is
@@ -273,7 +284,14 @@ func fetchCreationInfo(bucket kvdb.RBucket) (*PaymentCreationInfo, error) {
 	}
 
 	r := bytes.NewReader(b)
-	return deserializePaymentCreationInfo(r)
+	info, err := deserializePaymentCreationInfo(r)
+	if err != nil {
+		return nil, err
+	}
+
+	info.PaymentGroup = string(bucket.Get(paymentGroupKey))
+
+	return info, nil
 }
 
 func fetchPayment(bucket kvdb.RBucket) (*MPPayment, error) {
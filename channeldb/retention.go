@@ -0,0 +1,124 @@
+package channeldb
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+// PruneClosedChannels removes the data of fully closed channels that isn't
+// needed anymore once they're resolved, for all channels that were closed
+// below the given height:
+//   - their historical channel state,
+//   - their final htlc resolutions,
+//   - any forwarding packages that were left behind.
+//
+// The close summaries are kept, so the channels are still listed as closed.
+// Channels that are still waiting for their contracts to be resolved are
+// never pruned. The number of channels that data was removed for is
+// returned.
+func (c *ChannelStateDB) PruneClosedChannels(closeHeight uint32) (int,
+	error) {
+
+	var numPruned int
+	err := kvdb.Update(c.backend, func(tx kvdb.RwTx) error {
+		closeBucket := tx.ReadBucket(closedChannelBucket)
+		if closeBucket == nil {
+			return nil
+		}
+
+		// We first gather the summaries of the channels to prune, as
+		// the buckets can't be modified while iterating.
+		var summaries []*ChannelCloseSummary
+		err := closeBucket.ForEach(func(_, summaryBytes []byte) error {
+			summary, err := deserializeCloseChannelSummary(
+				bytes.NewReader(summaryBytes),
+			)
+			if err != nil {
+				return err
+			}
+
+			if summary.IsPending ||
+				summary.CloseHeight >= closeHeight {
+
+				return nil
+			}
+
+			summaries = append(summaries, summary)
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, summary := range summaries {
+			pruned, err := pruneClosedChannel(tx, summary)
+			if err != nil {
+				return fmt.Errorf("unable to prune channel "+
+					"%v: %w", summary.ChanPoint, err)
+			}
+
+			if pruned {
+				numPruned++
+			}
+		}
+
+		return nil
+	}, func() {
+		numPruned = 0
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return numPruned, nil
+}
+
+// pruneClosedChannel removes the historical state, the final htlc resolutions
+// and the forwarding packages of the given closed channel. It returns true if
+// any data was removed.
+func pruneClosedChannel(tx kvdb.RwTx, summary *ChannelCloseSummary) (bool,
+	error) {
+
+	var pruned bool
+
+	var chanPointBuf bytes.Buffer
+	if err := writeOutpoint(&chanPointBuf, &summary.ChanPoint); err != nil {
+		return false, err
+	}
+	chanKey := chanPointBuf.Bytes()
+
+	// deleteNested deletes the nested bucket with the given key from the
+	// given top level bucket, if it exists.
+	deleteNested := func(topLevel, key []byte) error {
+		bucket := tx.ReadWriteBucket(topLevel)
+		if bucket == nil || bucket.NestedReadWriteBucket(key) == nil {
+			return nil
+		}
+
+		pruned = true
+
+		return bucket.DeleteNestedBucket(key)
+	}
+
+	if err := deleteNested(historicalChannelBucket, chanKey); err != nil {
+		return false, err
+	}
+
+	var scidKey [8]byte
+	byteOrder.PutUint64(scidKey[:], summary.ShortChanID.ToUint64())
+
+	if err := deleteNested(finalHtlcsBucket, scidKey[:]); err != nil {
+		return false, err
+	}
+
+	// The forwarding packages are wiped when the channel is closed, but
+	// channels closed by older versions may have left some behind.
+	if err := deleteNested(fwdPackagesKey, scidKey[:]); err != nil {
+		return false, err
+	}
+
+	return pruned, nil
+}
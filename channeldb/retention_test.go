@@ -0,0 +1,116 @@
+package channeldb
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPruneClosedChannels tests that the historical state, final htlc
+// resolutions and forwarding packages of fully closed channels are pruned
+// once they're past the retention height, while pending closes and the close
+// summaries are kept.
+func TestPruneClosedChannels(t *testing.T) {
+	t.Parallel()
+
+	fullDB, err := MakeTestDB(t, OptionStoreFinalHtlcResolutions(true))
+	require.NoError(t, err, "unable to make test database")
+
+	cdb := fullDB.ChannelStateDB()
+
+	// closeChannel creates a channel and closes it at height 100,
+	// leaving a final htlc resolution and a forwarding package behind.
+	closeChannel := func(index uint32, pending bool) *OpenChannel {
+		scid := lnwire.NewShortChanIDFromInt(uint64(index))
+		channel := createTestChannel(
+			t, cdb, openChannelOption(),
+			fundingPointOption(wire.OutPoint{Index: index}),
+			channelIDOption(scid),
+		)
+
+		require.NoError(t, channel.CloseChannel(&ChannelCloseSummary{
+			ChanPoint:   channel.FundingOutpoint,
+			ShortChanID: scid,
+			RemotePub:   channel.IdentityPub,
+			CloseHeight: 100,
+			IsPending:   pending,
+		}))
+
+		err := cdb.PutOnchainFinalHtlcOutcome(scid, 0, true)
+		require.NoError(t, err)
+
+		// Older versions didn't always wipe the forwarding packages.
+		err = kvdb.Update(fullDB, func(tx kvdb.RwTx) error {
+			return NewChannelPackager(scid).AddFwdPkg(
+				tx, NewFwdPkg(scid, 0, nil, nil),
+			)
+		}, func() {})
+		require.NoError(t, err)
+
+		return channel
+	}
+
+	// requirePruned asserts whether the data of the channel was pruned.
+	requirePruned := func(channel *OpenChannel, pruned bool) {
+		t.Helper()
+
+		scid := channel.ShortChannelID
+
+		var fwdPkgs []*FwdPkg
+		err := kvdb.View(fullDB, func(tx kvdb.RTx) error {
+			var err error
+			fwdPkgs, err = NewChannelPackager(scid).LoadFwdPkgs(tx)
+
+			return err
+		}, func() {
+			fwdPkgs = nil
+		})
+		require.NoError(t, err)
+
+		chanPoint := &channel.FundingOutpoint
+		_, histErr := cdb.FetchHistoricalChannel(chanPoint)
+		_, htlcErr := cdb.LookupFinalHtlc(scid, 0)
+
+		if pruned {
+			require.ErrorIs(t, histErr, ErrChannelNotFound)
+			require.ErrorIs(t, htlcErr, ErrHtlcUnknown)
+			require.Empty(t, fwdPkgs)
+
+			return
+		}
+
+		require.NoError(t, histErr)
+		require.NoError(t, htlcErr)
+		require.Len(t, fwdPkgs, 1)
+	}
+
+	closed := closeChannel(1, false)
+	pending := closeChannel(2, true)
+
+	// Channels closed at the retention height are kept.
+	numPruned, err := cdb.PruneClosedChannels(100)
+	require.NoError(t, err)
+	require.Zero(t, numPruned)
+	requirePruned(closed, false)
+
+	// Once past the retention height, only the fully closed channel is
+	// pruned.
+	numPruned, err = cdb.PruneClosedChannels(101)
+	require.NoError(t, err)
+	require.Equal(t, 1, numPruned)
+	requirePruned(closed, true)
+	requirePruned(pending, false)
+
+	// The close summaries are kept.
+	summaries, err := cdb.FetchClosedChannels(false)
+	require.NoError(t, err)
+	require.Len(t, summaries, 2)
+
+	// There's nothing left to prune for the channel.
+	numPruned, err = cdb.PruneClosedChannels(101)
+	require.NoError(t, err)
+	require.Zero(t, numPruned)
+}
@@ -72,6 +72,16 @@ type ClosedChannelEvent struct {
 	CloseSummary *channeldb.ChannelCloseSummary
 }
 
+// StaleRemoteCloseEvent represents a new event where the remote party closed a
+// channel with a revoked state after proving in channel_reestablish that it
+// lost channel state. The close is still handled as a breach, but it is most
+// likely caused by the remote's data loss rather than an attempt to steal
+// funds.
+type StaleRemoteCloseEvent struct {
+	// ChannelPoint is the channelpoint of the closed channel.
+	ChannelPoint *wire.OutPoint
+}
+
 // FullyResolvedChannelEvent represents a new event where a channel becomes
 // fully resolved.
 type FullyResolvedChannelEvent struct {
@@ -184,6 +194,15 @@ func (c *ChannelNotifier) NotifyFullyResolvedChannelEvent(
 	}
 }
 
+// NotifyStaleRemoteCloseEvent notifies the channelEventNotifier goroutine that
+// the remote party closed a channel with a stale state after losing data.
+func (c *ChannelNotifier) NotifyStaleRemoteCloseEvent(chanPoint wire.OutPoint) {
+	event := StaleRemoteCloseEvent{ChannelPoint: &chanPoint}
+	if err := c.ntfnServer.SendUpdate(event); err != nil {
+		log.Warnf("Unable to send stale remote close update: %v", err)
+	}
+}
+
 // NotifyActiveLinkEvent notifies the channelEventNotifier goroutine that a
 // link has been added to the switch.
 func (c *ChannelNotifier) NotifyActiveLinkEvent(chanPoint wire.OutPoint) {
@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/urfave/cli"
+)
+
+var fwdLatenciesCommand = cli.Command{
+	Name:     "fwdlatencies",
+	Category: "Payments",
+	Usage:    "Display the forward latency histograms of all channels.",
+	Description: `
+	Display per channel histograms of the time htlcs spent between being
+	locked in and forwarded (incoming) and between being forwarded and
+	resolved by the peer (outgoing). The histograms are aggregated since
+	startup and help to find slow peers.`,
+	Action: actionDecorator(fwdLatencies),
+}
+
+func fwdLatencies(ctx *cli.Context) error {
+	ctxc := getContext()
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	client := routerrpc.NewRouterClient(conn)
+	resp, err := client.ForwardLatencies(
+		ctxc, &routerrpc.ForwardLatenciesRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/urfave/cli"
+)
+
+var subscribeGossipCommand = cli.Command{
+	Name:     "subscribegossip",
+	Category: "Graph",
+	Usage:    "Stream the announcements accepted by the gossiper.",
+	Description: `
+	Print every channel announcement, channel update and node announcement
+	accepted by the gossiper as a raw, hex encoded wire message. Requires
+	the gossip bridge to be enabled with gossip.bridge.`,
+	Action: actionDecorator(subscribeGossip),
+}
+
+func subscribeGossip(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	stream, err := client.SubscribeGossipBridge(
+		ctxc, &lnrpc.GossipBridgeSubscription{},
+	)
+	if err != nil {
+		return err
+	}
+
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		printRespJSON(msg)
+	}
+}
+
+var injectGossipCommand = cli.Command{
+	Name:      "injectgossip",
+	Category:  "Graph",
+	Usage:     "Inject an announcement into the gossiper.",
+	ArgsUsage: "raw_msg",
+	Description: `
+	Hand a hex encoded channel announcement, channel update or node
+	announcement, including its two byte type prefix, to the gossiper. The
+	announcement is fully validated before it is added to the graph and
+	broadcast to our peers. Requires the gossip bridge to be enabled with
+	gossip.bridge.`,
+	Action: actionDecorator(injectGossip),
+}
+
+func injectGossip(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	if ctx.NArg() != 1 {
+		return cli.ShowCommandHelp(ctx, "injectgossip")
+	}
+
+	rawMsg, err := hex.DecodeString(ctx.Args().First())
+	if err != nil {
+		return fmt.Errorf("unable to decode message: %w", err)
+	}
+
+	resp, err := client.InjectGossip(
+		ctxc, &lnrpc.GossipMessage{RawMsg: rawMsg},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
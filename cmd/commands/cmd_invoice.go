@@ -116,6 +116,20 @@ var AddInvoiceCommand = cli.Command{
 				"use on a blinded path. The flag may be " +
 				"specified multiple times.",
 		},
+		cli.StringFlag{
+			Name: "overpayment_policy",
+			Usage: "how payments that pay more than the invoice " +
+				"amount are handled, one of accept, limit or " +
+				"reject. If not set, the node wide policy is " +
+				"used.",
+		},
+		cli.UintFlag{
+			Name: "max_overpayment_percent",
+			Usage: "the maximum overpayment that is accepted, " +
+				"as a percentage of the invoice amount. Can " +
+				"only be used with the limit overpayment " +
+				"policy.",
+		},
 	},
 	Action: actionDecorator(addInvoice),
 }
@@ -172,6 +186,11 @@ func addInvoice(ctx *cli.Context) error {
 			err)
 	}
 
+	overpaymentPolicy, err := parseOverpaymentPolicy(ctx)
+	if err != nil {
+		return err
+	}
+
 	invoice := &lnrpc.Invoice{
 		Memo:              ctx.String("memo"),
 		RPreimage:         preimage,
@@ -185,6 +204,7 @@ func addInvoice(ctx *cli.Context) error {
 		IsAmp:             ctx.Bool("amp"),
 		IsBlinded:         ctx.Bool("blind"),
 		BlindedPathConfig: blindedPathCfg,
+		OverpaymentPolicy: overpaymentPolicy,
 	}
 
 	resp, err := client.AddInvoice(ctxc, invoice)
@@ -197,6 +217,40 @@ func addInvoice(ctx *cli.Context) error {
 	return nil
 }
 
+func parseOverpaymentPolicy(ctx *cli.Context) (*lnrpc.OverpaymentPolicy,
+	error) {
+
+	if !ctx.IsSet("overpayment_policy") {
+		if ctx.IsSet("max_overpayment_percent") {
+			return nil, fmt.Errorf("max_overpayment_percent can " +
+				"only be used with an overpayment_policy")
+		}
+
+		return nil, nil
+	}
+
+	var mode lnrpc.OverpaymentMode
+	switch ctx.String("overpayment_policy") {
+	case "accept":
+		mode = lnrpc.OverpaymentMode_OVERPAYMENT_ACCEPT
+
+	case "limit":
+		mode = lnrpc.OverpaymentMode_OVERPAYMENT_LIMIT
+
+	case "reject":
+		mode = lnrpc.OverpaymentMode_OVERPAYMENT_REJECT
+
+	default:
+		return nil, fmt.Errorf("unknown overpayment_policy: %v",
+			ctx.String("overpayment_policy"))
+	}
+
+	return &lnrpc.OverpaymentPolicy{
+		Mode:       mode,
+		MaxPercent: uint32(ctx.Uint("max_overpayment_percent")),
+	}, nil
+}
+
 func parseBlindedPathCfg(ctx *cli.Context) (*lnrpc.BlindedPathConfig, error) {
 	if !ctx.Bool("blind") {
 		if ctx.IsSet("min_real_blinded_hops") ||
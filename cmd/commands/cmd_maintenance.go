@@ -0,0 +1,143 @@
+package commands
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/urfave/cli"
+)
+
+var scheduleMaintenanceCommand = cli.Command{
+	Name:     "schedulemaintenance",
+	Category: "Channels",
+	Usage:    "Schedule a window during which channels are disabled.",
+	Description: `
+	Schedule a maintenance window during which the given channels, or all
+	public channels if none are given, are disabled on the network. The
+	channels are re-enabled once the window ends. Channels that were
+	manually disabled before the window started stay disabled.
+
+	Maintenance windows aren't persisted and need to be scheduled again
+	after a restart.`,
+	Flags: []cli.Flag{
+		cli.Int64Flag{
+			Name: "start",
+			Usage: "the unix timestamp at which the channels are " +
+				"disabled, the window starts immediately if " +
+				"not set",
+		},
+		cli.DurationFlag{
+			Name:  "duration",
+			Usage: "how long the channels stay disabled, e.g. 2h",
+		},
+		cli.StringSliceFlag{
+			Name: "chan_point",
+			Usage: "a channel to disable during the window, in " +
+				"the form of txid:output_index. Can be " +
+				"specified multiple times",
+		},
+	},
+	Action: actionDecorator(scheduleMaintenance),
+}
+
+func scheduleMaintenance(ctx *cli.Context) error {
+	ctxc := getContext()
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	if ctx.NumFlags() == 0 {
+		return cli.ShowCommandHelp(ctx, "schedulemaintenance")
+	}
+
+	duration := ctx.Duration("duration")
+	if duration <= 0 {
+		return errors.New("duration must be positive")
+	}
+
+	req := &routerrpc.ScheduleMaintenanceRequest{
+		StartTime:       ctx.Int64("start"),
+		DurationSeconds: uint64(duration.Seconds()),
+	}
+	for _, s := range ctx.StringSlice("chan_point") {
+		chanPoint, err := parseChanPoint(s)
+		if err != nil {
+			return err
+		}
+
+		req.ChanPoints = append(req.ChanPoints, chanPoint)
+	}
+
+	client := routerrpc.NewRouterClient(conn)
+	resp, err := client.ScheduleMaintenance(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var cancelMaintenanceCommand = cli.Command{
+	Name:      "cancelmaintenance",
+	Category:  "Channels",
+	Usage:     "Cancel a maintenance window.",
+	ArgsUsage: "window_id",
+	Description: `
+	Cancel a scheduled maintenance window. If the window already started,
+	its channels are re-enabled.`,
+	Action: actionDecorator(cancelMaintenance),
+}
+
+func cancelMaintenance(ctx *cli.Context) error {
+	ctxc := getContext()
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	if ctx.NArg() != 1 {
+		return cli.ShowCommandHelp(ctx, "cancelmaintenance")
+	}
+
+	id, err := strconv.ParseUint(ctx.Args().First(), 10, 64)
+	if err != nil {
+		return err
+	}
+
+	client := routerrpc.NewRouterClient(conn)
+	resp, err := client.CancelMaintenance(
+		ctxc, &routerrpc.CancelMaintenanceRequest{WindowId: id},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var listMaintenanceCommand = cli.Command{
+	Name:     "listmaintenance",
+	Category: "Channels",
+	Usage:    "List the scheduled and active maintenance windows.",
+	Action:   actionDecorator(listMaintenance),
+}
+
+func listMaintenance(ctx *cli.Context) error {
+	ctxc := getContext()
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	client := routerrpc.NewRouterClient(conn)
+	resp, err := client.ListMaintenanceWindows(
+		ctxc, &routerrpc.ListMaintenanceWindowsRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
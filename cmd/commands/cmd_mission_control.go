@@ -355,7 +355,28 @@ var resetMissionControlCommand = cli.Command{
 	Name:     "resetmc",
 	Category: "Mission Control",
 	Usage:    "Reset internal mission control state.",
-	Action:   actionDecorator(resetMissionControl),
+	Description: `
+	Clear the internal mission control state. If any of the filter flags
+	are set, only the matching part of the state is cleared.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "node",
+			Usage: "only clear the state of node pairs that " +
+				"involve this node",
+		},
+		cli.Int64Flag{
+			Name: "older_than",
+			Usage: "only clear the state that was recorded " +
+				"before this unix timestamp (in seconds)",
+		},
+		cli.BoolFlag{
+			Name: "failures_only",
+			Usage: "only clear the state of node pairs that have " +
+				"failed, but never succeeded",
+		},
+	},
+	Action: actionDecorator(resetMissionControl),
 }
 
 func resetMissionControl(ctx *cli.Context) error {
@@ -365,8 +386,24 @@ func resetMissionControl(ctx *cli.Context) error {
 
 	client := routerrpc.NewRouterClient(conn)
 
-	req := &routerrpc.ResetMissionControlRequest{}
-	_, err := client.ResetMissionControl(ctxc, req)
+	req := &routerrpc.ResetMissionControlRequest{
+		OlderThan:    ctx.Int64("older_than"),
+		FailuresOnly: ctx.Bool("failures_only"),
+	}
+	if ctx.IsSet("node") {
+		node, err := route.NewVertexFromStr(ctx.String("node"))
+		if err != nil {
+			return fmt.Errorf("invalid node: %w", err)
+		}
+		req.Node = node[:]
+	}
 
-	return err
+	resp, err := client.ResetMissionControl(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
 }
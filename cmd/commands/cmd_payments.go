@@ -151,6 +151,15 @@ var (
 			"Canceling will only prevent further attempts from " +
 			"being sent",
 	}
+
+	scheduleRetriesFlag = cli.BoolFlag{
+		Name: "schedule_retries",
+		Usage: "if set to true, the payment is retried " +
+			"automatically in the background if it fails " +
+			"because no route was found, the balance was " +
+			"insufficient or the payment timed out. Requires " +
+			"routerrpc.paymentretry.enable to be set on the node",
+	}
 )
 
 // PaymentFlags returns common flags for sendpayment and payinvoice.
@@ -179,6 +188,7 @@ func PaymentFlags() []cli.Flag {
 			Value: paymentTimeout,
 		},
 		cancelableFlag,
+		scheduleRetriesFlag,
 		cltvLimitFlag,
 		lastHopFlag,
 		cli.Int64SliceFlag{
@@ -346,6 +356,7 @@ func SendPayment(ctx *cli.Context) error {
 			DestCustomRecords: make(map[uint64][]byte),
 			Amp:               ctx.Bool(ampFlag.Name),
 			Cancelable:        ctx.Bool(cancelableFlag.Name),
+			ScheduleRetries:   ctx.Bool(scheduleRetriesFlag.Name),
 		}
 
 		// We'll attempt to parse a payment address as well, given that
@@ -407,6 +418,7 @@ func SendPayment(ctx *cli.Context) error {
 		DestCustomRecords: make(map[uint64][]byte),
 		Amp:               ctx.Bool(ampFlag.Name),
 		Cancelable:        ctx.Bool(cancelableFlag.Name),
+		ScheduleRetries:   ctx.Bool(scheduleRetriesFlag.Name),
 	}
 
 	var rHash []byte
@@ -925,6 +937,7 @@ func payInvoice(ctx *cli.Context) error {
 		DestCustomRecords: make(map[uint64][]byte),
 		Amp:               ctx.Bool(ampFlag.Name),
 		Cancelable:        ctx.Bool(cancelableFlag.Name),
+		ScheduleRetries:   ctx.Bool(scheduleRetriesFlag.Name),
 	}
 
 	return SendPaymentRequest(ctx, req, conn, conn, routerRPCSendPayment)
@@ -1941,6 +1954,106 @@ func estimateRouteFee(ctx *cli.Context) error {
 	return nil
 }
 
+var listScheduledPaymentsCommand = cli.Command{
+	Name:     "listscheduledpayments",
+	Category: "Payments",
+	Usage:    "List the payments that are scheduled for automatic retries.",
+	Description: `
+	Returns all payments that are tracked by the payment retry queue,
+	ordered by the time of their next attempt. Payments are only added to
+	the queue if they were sent with --schedule_retries.
+	`,
+	Action: actionDecorator(listScheduledPayments),
+}
+
+func listScheduledPayments(ctx *cli.Context) error {
+	ctxc := getContext()
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	client := routerrpc.NewRouterClient(conn)
+
+	resp, err := client.ListScheduledPayments(
+		ctxc, &routerrpc.ListScheduledPaymentsRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var sendPaymentBatchCommand = cli.Command{
+	Name:     "sendpaymentbatch",
+	Category: "Payments",
+	Usage:    "Pay a batch of invoices with a shared fee budget.",
+	Description: `
+	Pays all given invoices on a best-effort basis and prints the outcome
+	of each of them once all of them completed. A failed payment doesn't
+	abort the remaining ones. The payments are sent one after the other,
+	largest first, so they don't compete for the same local liquidity. The
+	fee budget is shared between the payments proportionally to their
+	amount, and any share that a payment doesn't use is handed on to the
+	payments after it.
+	`,
+	ArgsUsage: "pay_req [pay_req...]",
+	Flags: []cli.Flag{
+		cli.Int64Flag{
+			Name: "fee_budget_sat",
+			Usage: "the maximum amount of fees in satoshis that " +
+				"all payments may pay together",
+		},
+		cli.DurationFlag{
+			Name: "timeout",
+			Usage: "the maximum amount of time we should spend " +
+				"trying to fulfill each payment, failing " +
+				"after the timeout has elapsed",
+			Value: paymentTimeout,
+		},
+	},
+	Action: actionDecorator(sendPaymentBatch),
+}
+
+func sendPaymentBatch(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	if ctx.NArg() == 0 {
+		return cli.ShowCommandHelp(ctx, "sendpaymentbatch")
+	}
+
+	pmtTimeout := ctx.Duration("timeout")
+	if pmtTimeout <= 0 {
+		return errors.New("payment timeout must be greater than zero")
+	}
+
+	req := &routerrpc.SendPaymentBatchRequest{
+		FeeBudgetMsat: ctx.Int64("fee_budget_sat") * 1000,
+	}
+	for _, payReq := range ctx.Args() {
+		payment := &routerrpc.SendPaymentRequest{
+			PaymentRequest: StripPrefix(payReq),
+			TimeoutSeconds: int32(pmtTimeout.Seconds()),
+		}
+		req.Payments = append(req.Payments, payment)
+	}
+
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	client := routerrpc.NewRouterClient(conn)
+
+	resp, err := client.SendPaymentBatch(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
 // ESC is the ASCII code for escape character.
 const ESC = 27
 
@@ -838,6 +838,323 @@ func disconnectPeer(ctx *cli.Context) error {
 	return nil
 }
 
+var allowPeerCommand = cli.Command{
+	Name:      "allowpeer",
+	Category:  "Peers",
+	Usage:     "Add a peer to the allow list.",
+	ArgsUsage: "<pubkey>",
+	Description: `
+	Add a peer to the allow list, so it may connect to us and we may connect
+	to it. Requires the allow list to be enabled with allowlist.enable.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "node_key",
+			Usage: "The hex-encoded compressed public key of the " +
+				"peer to allow",
+		},
+	},
+	Action: actionDecorator(allowPeer),
+}
+
+func allowPeer(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	pubKey, err := allowListPubKey(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.AllowPeer(ctxc, &lnrpc.AllowPeerRequest{
+		PubKey: pubKey,
+	})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var disallowPeerCommand = cli.Command{
+	Name:      "disallowpeer",
+	Category:  "Peers",
+	Usage:     "Remove a peer from the allow list.",
+	ArgsUsage: "<pubkey>",
+	Description: `
+	Remove a peer from the allow list. If we're connected to the peer, the
+	connection is closed and we stop reconnecting to it. Requires the allow
+	list to be enabled with allowlist.enable.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "node_key",
+			Usage: "The hex-encoded compressed public key of the " +
+				"peer to disallow",
+		},
+	},
+	Action: actionDecorator(disallowPeer),
+}
+
+func disallowPeer(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	pubKey, err := allowListPubKey(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.DisallowPeer(ctxc, &lnrpc.DisallowPeerRequest{
+		PubKey: pubKey,
+	})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+// allowListPubKey returns the public key of the peer that was passed either
+// as a flag or as the first positional argument.
+func allowListPubKey(ctx *cli.Context) (string, error) {
+	switch {
+	case ctx.IsSet("node_key"):
+		return ctx.String("node_key"), nil
+	case ctx.Args().Present():
+		return ctx.Args().First(), nil
+	default:
+		return "", fmt.Errorf("must specify target public key")
+	}
+}
+
+var listAllowedPeersCommand = cli.Command{
+	Name:     "listallowedpeers",
+	Category: "Peers",
+	Usage:    "List all peers on the allow list.",
+	Action:   actionDecorator(listAllowedPeers),
+}
+
+func listAllowedPeers(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListAllowedPeers(
+		ctxc, &lnrpc.ListAllowedPeersRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var getGossipEgressCommand = cli.Command{
+	Name:     "getgossipegress",
+	Category: "Peers",
+	Usage: "Show the limits of the gossip that is proactively sent " +
+		"to peers.",
+	Action: actionDecorator(getGossipEgress),
+}
+
+func getGossipEgress(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.GetGossipEgressLimits(
+		ctxc, &lnrpc.GetGossipEgressLimitsRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var setGossipEgressCommand = cli.Command{
+	Name:     "setgossipegress",
+	Category: "Peers",
+	Usage: "Update the limits of the gossip that is proactively sent " +
+		"to peers.",
+	Description: `
+	Update the limits of the gossip that is proactively sent to peers, i.e.
+	the announcements flooded to them and the backlog sent once they set
+	their gossip timestamp range. Limits that aren't set keep their current
+	value. The update isn't persisted, so the configured limits apply again
+	after a restart.`,
+	Flags: []cli.Flag{
+		cli.Uint64Flag{
+			Name: "rate_bytes",
+			Usage: "the maximum number of bytes per second sent " +
+				"to all peers combined, 0 disables the limit",
+		},
+		cli.Uint64Flag{
+			Name: "burst_bytes",
+			Usage: "the number of bytes that may be sent to all " +
+				"peers at once",
+		},
+		cli.Uint64Flag{
+			Name: "peer_rate_bytes",
+			Usage: "the maximum number of bytes per second sent " +
+				"to a single peer, 0 disables the limit",
+		},
+		cli.Uint64Flag{
+			Name: "peer_burst_bytes",
+			Usage: "the number of bytes that may be sent to a " +
+				"single peer at once",
+		},
+		cli.StringSliceFlag{
+			Name: "passive_peer",
+			Usage: "the hex-encoded public key of a peer that " +
+				"gossip is never flooded to, replaces the " +
+				"current passive peers. Can be set multiple " +
+				"times",
+		},
+		cli.BoolFlag{
+			Name:  "clear_passive_peers",
+			Usage: "remove all passive peers",
+		},
+	},
+	Action: actionDecorator(setGossipEgress),
+}
+
+func setGossipEgress(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	if ctx.IsSet("passive_peer") && ctx.Bool("clear_passive_peers") {
+		return fmt.Errorf("passive_peer and clear_passive_peers " +
+			"can't be set together")
+	}
+
+	// Start from the current limits, so only the given ones change.
+	limits, err := client.GetGossipEgressLimits(
+		ctxc, &lnrpc.GetGossipEgressLimitsRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	if ctx.IsSet("rate_bytes") {
+		limits.MaxBytesPerSec = ctx.Uint64("rate_bytes")
+	}
+	if ctx.IsSet("burst_bytes") {
+		limits.MaxBurstBytes = ctx.Uint64("burst_bytes")
+	}
+	if ctx.IsSet("peer_rate_bytes") {
+		limits.PeerBytesPerSec = ctx.Uint64("peer_rate_bytes")
+	}
+	if ctx.IsSet("peer_burst_bytes") {
+		limits.PeerBurstBytes = ctx.Uint64("peer_burst_bytes")
+	}
+	switch {
+	case ctx.IsSet("passive_peer"):
+		limits.PassivePeers = ctx.StringSlice("passive_peer")
+
+	case ctx.Bool("clear_passive_peers"):
+		limits.PassivePeers = nil
+	}
+
+	resp, err := client.UpdateGossipEgressLimits(ctxc, limits)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var checkDBIntegrityCommand = cli.Command{
+	Name:     "checkdbintegrity",
+	Category: "Channels",
+	Usage: "Check the channel graph and the channel state database " +
+		"for orphaned references.",
+	Description: `
+	Validate the references between the channel graph and the channel state
+	database. Edges of our own node and forwarding packages that refer to
+	channels that don't exist anymore are reported as orphans. Use
+	prunedborphans to remove them.`,
+	Action: actionDecorator(checkDBIntegrity),
+}
+
+func checkDBIntegrity(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.CheckDBIntegrity(
+		ctxc, &lnrpc.CheckDBIntegrityRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var pruneDBOrphansCommand = cli.Command{
+	Name:     "prunedborphans",
+	Category: "Channels",
+	Usage: "Remove orphaned references from the channel graph and the " +
+		"channel state database.",
+	Description: `
+	Run the same check as checkdbintegrity and remove all orphaned
+	references it finds. Orphaned edges are removed without being marked as
+	zombies, so they can be re-added if they're announced again.`,
+	Action: actionDecorator(pruneDBOrphans),
+}
+
+func pruneDBOrphans(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.PruneDBOrphans(ctxc, &lnrpc.PruneDBOrphansRequest{})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var createDBSnapshotCommand = cli.Command{
+	Name:     "createdbsnapshot",
+	Category: "Channels",
+	Usage:    "Take a snapshot of the local bolt databases.",
+	Description: `
+	Take a consistent snapshot of the local bolt databases while lnd keeps
+	running. The snapshot is written to its own directory within the
+	directory set by db.bolt.snapshot-dir. Old snapshots exceeding
+	db.bolt.snapshots-to-keep are removed. Only supported with the bolt
+	database backend.`,
+	Action: actionDecorator(createDBSnapshot),
+}
+
+func createDBSnapshot(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.CreateDBSnapshot(
+		ctxc, &lnrpc.CreateDBSnapshotRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
 // TODO(roasbeef): also allow short relative channel ID.
 
 var closeChannelCommand = cli.Command{
@@ -1680,6 +1997,216 @@ func listAliases(ctx *cli.Context) error {
 	return nil
 }
 
+var commitmentFeeInfoCommand = cli.Command{
+	Name:     "commitmentfeeinfo",
+	Category: "Channels",
+	Usage: "Show the weight, fee and dust htlcs of a channel's " +
+		"commitment transaction.",
+	Description: `
+	Shows the weight, fee and set of dust HTLCs of the current local or
+	remote commitment transaction of a channel, along with the same values
+	at each of the given alternative fee rates. This can be used to reason
+	about the impact of an update_fee, or the cost of a force close.
+
+	The format for a channel_point is 'funding_txid:output_index'.`,
+	ArgsUsage: "funding_txid [output_index]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "funding_txid",
+			Usage: "the txid of the channel's funding transaction",
+		},
+		cli.IntFlag{
+			Name: "output_index",
+			Usage: "the output index for the funding output of " +
+				"the funding transaction",
+		},
+		cli.StringFlag{
+			Name: "chan_point",
+			Usage: "(optional) the channel point. If set, " +
+				"funding_txid and output_index flags and " +
+				"positional arguments will be ignored",
+		},
+		cli.BoolFlag{
+			Name: "remote",
+			Usage: "describe the remote party's commitment " +
+				"transaction instead of ours",
+		},
+		cli.Int64SliceFlag{
+			Name: "alt_sat_per_kw",
+			Usage: "an alternative fee rate in sat/kw to " +
+				"estimate the commitment fee at, can be " +
+				"specified multiple times",
+		},
+	},
+	Action: actionDecorator(commitmentFeeInfo),
+}
+
+func commitmentFeeInfo(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	// Show command help if no arguments and flags were provided.
+	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
+		cli.ShowCommandHelp(ctx, "commitmentfeeinfo")
+		return nil
+	}
+
+	channelPoint, err := parseChannelPoint(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := &lnrpc.CommitmentFeeInfoRequest{
+		ChannelPoint: channelPoint,
+		RemoteCommit: ctx.Bool("remote"),
+	}
+	for _, feeRate := range ctx.Int64Slice("alt_sat_per_kw") {
+		if feeRate <= 0 {
+			return fmt.Errorf("invalid fee rate %v", feeRate)
+		}
+
+		req.AlternativeSatPerKw = append(
+			req.AlternativeSatPerKw, uint64(feeRate),
+		)
+	}
+
+	resp, err := client.CommitmentFeeInfo(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var exportResolutionsCommand = cli.Command{
+	Name:     "exportresolutions",
+	Category: "Channels",
+	Usage:    "Export a signed snapshot of the pending resolutions.",
+	Description: `
+	Exports a snapshot of all contracts that are currently being resolved
+	on-chain, including their type, amounts, maturity height and the
+	witness required to sweep them. The snapshot is a JSON document that
+	is signed by the node's identity key, so it can be handed to an
+	auditor as proof of the funds that are in flight.
+
+	The signature is a compact, public key recoverable signature over the
+	double SHA256 of the snapshot. Both are hex encoded.`,
+	Action: actionDecorator(exportResolutions),
+}
+
+func exportResolutions(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.ExportResolutionsRequest{}
+	resp, err := client.ExportResolutions(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var timeLockedFundsCommand = cli.Command{
+	Name:     "timelockedfunds",
+	Category: "Channels",
+	Usage:    "List the time-locked outputs of force closed channels.",
+	Description: `
+	Lists the outputs of force closed channels that are still encumbered
+	by a CSV or CLTV lock, together with the heights and projected times
+	at which they can be swept back to the wallet, and the total amount
+	that is locked.`,
+	Action: actionDecorator(timeLockedFunds),
+}
+
+func timeLockedFunds(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.TimeLockedFundsRequest{}
+	resp, err := client.TimeLockedFunds(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var pendingResolutionsCommand = cli.Command{
+	Name:     "pendingresolutions",
+	Category: "Channels",
+	Usage:    "List the contracts that are being resolved on-chain.",
+	Description: `
+	Lists all contracts that are currently being resolved on-chain,
+	together with the state of their resolver, the height by which they
+	must be swept and the estimated fee to do so. The most urgent
+	contracts are listed first.`,
+	Action: actionDecorator(pendingResolutions),
+}
+
+func pendingResolutions(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.ListPendingResolutionsRequest{}
+	resp, err := client.ListPendingResolutions(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var auditScidLeaksCommand = cli.Command{
+	Name:     "auditscidleaks",
+	Category: "Invoices",
+	Usage: "List invoice route hints that reveal the confirmed scid " +
+		"of an alias-only channel.",
+	Description: `
+	Scans the payment requests of our invoices for route hints that reveal
+	the confirmed SCID of an unannounced channel which negotiated the
+	option-scid-alias feature, and should therefore only have been
+	referred to by its alias.`,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name: "pending_only",
+			Usage: "only scan invoices that can still be " +
+				"paid",
+		},
+	},
+	Action: actionDecorator(auditScidLeaks),
+}
+
+func auditScidLeaks(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.AuditScidLeaksRequest{
+		PendingOnly: ctx.Bool("pending_only"),
+	}
+	resp, err := client.AuditScidLeaks(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
 func ListChannels(ctx *cli.Context) error {
 	ctxc := getContext()
 	client, cleanUp := getClient(ctx)
@@ -2027,6 +2554,82 @@ func debugLevel(ctx *cli.Context) error {
 	return nil
 }
 
+var workerPoolsCommand = cli.Command{
+	Name:  "workerpools",
+	Usage: "Display the utilization of the peer message worker pools.",
+	Description: `
+	Display the number of workers, the maximum number of workers, the
+	number of processed tasks and the average queue latency of the worker
+	pools that serialize and deserialize peer messages.`,
+	Action: actionDecorator(workerPools),
+}
+
+func workerPools(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.GetWorkerPoolStats(
+		ctxc, &lnrpc.GetWorkerPoolStatsRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var resizeWorkerPoolCommand = cli.Command{
+	Name:      "resizeworkerpool",
+	Usage:     "Set the maximum number of workers of a worker pool.",
+	ArgsUsage: "read|write num_workers",
+	Description: `
+	Set the maximum number of workers of the worker pool that deserializes
+	messages read from peers (read) or serializes messages written to peers
+	(write). Excess workers retire once they finish their current task. If
+	workers.autotune is enabled, the pool keeps adjusting its size starting
+	from the new size. The size is not persisted across restarts.`,
+	Action: actionDecorator(resizeWorkerPool),
+}
+
+func resizeWorkerPool(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	if ctx.NArg() != 2 {
+		return cli.ShowCommandHelp(ctx, "resizeworkerpool")
+	}
+
+	var workerPool lnrpc.WorkerPool
+	switch ctx.Args().Get(0) {
+	case "read":
+		workerPool = lnrpc.WorkerPool_WORKER_POOL_READ
+	case "write":
+		workerPool = lnrpc.WorkerPool_WORKER_POOL_WRITE
+	default:
+		return errors.New(`pool must be one of "read" or "write"`)
+	}
+
+	numWorkers, err := strconv.ParseUint(ctx.Args().Get(1), 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid number of workers: %w", err)
+	}
+
+	req := &lnrpc.ResizeWorkerPoolRequest{
+		Pool:       workerPool,
+		NumWorkers: uint32(numWorkers),
+	}
+	resp, err := client.ResizeWorkerPool(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
 var listChainTxnsCommand = cli.Command{
 	Name:     "listchaintxns",
 	Category: "On-chain",
@@ -2240,6 +2843,54 @@ func feeReport(ctx *cli.Context) error {
 	return nil
 }
 
+var suggestFeesCommand = cli.Command{
+	Name:     "suggestfees",
+	Category: "Channels",
+	Usage:    "Suggest fee rates for all channels.",
+	Description: `
+	Returns fee rate suggestions for all channels based on their forwarding
+	history, their local balance and the fee rates other nodes charge for
+	forwarding to the same peer.
+
+	By default this is a dry run and no channel policies are changed. If
+	--apply is set, the fee rate of every channel whose suggested fee rate
+	differs from its current one is updated.`,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name: "apply",
+			Usage: "apply the suggested fee rates instead " +
+				"of only displaying them",
+		},
+	},
+	Action: actionDecorator(suggestFees),
+}
+
+func suggestFees(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	if ctx.Bool("apply") {
+		resp, err := client.ApplyFeeSuggestions(
+			ctxc, &lnrpc.ApplyFeeSuggestionsRequest{},
+		)
+		if err != nil {
+			return err
+		}
+
+		printRespJSON(resp)
+		return nil
+	}
+
+	resp, err := client.SuggestFees(ctxc, &lnrpc.SuggestFeesRequest{})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
 var updateChannelPolicyCommand = cli.Command{
 	Name:     "updatechanpolicy",
 	Category: "Channels",
@@ -2974,3 +3625,31 @@ func restoreChanBackup(ctx *cli.Context) error {
 
 	return nil
 }
+
+var backupReplicationStatusCommand = cli.Command{
+	Name:     "backupreplicationstatus",
+	Category: "Channels",
+	Usage: "Display the replication state of the channel backup to " +
+		"remote targets.",
+	Description: `
+	Display the state of the replication of the channel backup file to
+	each remote target configured in the backupreplication group, including
+	the time of the last successful replication.`,
+	Action: actionDecorator(backupReplicationStatus),
+}
+
+func backupReplicationStatus(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.BackupReplicationStatus(
+		ctxc, &lnrpc.BackupReplicationStatusRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
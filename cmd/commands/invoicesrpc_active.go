@@ -18,6 +18,15 @@ func invoicesCommands() []cli.Command {
 		cancelInvoiceCommand,
 		addHoldInvoiceCommand,
 		settleInvoiceCommand,
+		lookupInvoiceV2Command,
+		presentInvoiceCommand,
+		addOfferCommand,
+		listOffersCommand,
+		decodeOfferCommand,
+		addInvoiceTemplateCommand,
+		removeInvoiceTemplateCommand,
+		listInvoiceTemplatesCommand,
+		mintInvoiceCommand,
 	}
 }
 
@@ -262,3 +271,505 @@ func addHoldInvoice(ctx *cli.Context) error {
 
 	return nil
 }
+
+var lookupInvoiceV2Command = cli.Command{
+	Name:     "lookupinvoicev2",
+	Category: "Invoices",
+	Usage: "Lookup an existing invoice by its payment hash, payment " +
+		"address or AMP set ID.",
+	Description: `
+	Looks up an invoice using exactly one of the payment hash, the payment
+	address or the set ID of an AMP HTLC set.
+
+	When looking up an invoice by its payment address, --blank_htlcs can be
+	set to omit all HTLCs from the response. When looking up an AMP invoice
+	by set ID, --set_htlcs_only can be set to only return the HTLCs and
+	state of that set.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "rhash",
+			Usage: "the hex-encoded payment hash (32 byte) of " +
+				"the invoice to look up",
+		},
+		cli.StringFlag{
+			Name: "payaddr",
+			Usage: "the hex-encoded payment address (32 byte) of " +
+				"the invoice to look up",
+		},
+		cli.StringFlag{
+			Name: "setid",
+			Usage: "the hex-encoded set ID (32 byte) of an AMP " +
+				"HTLC set paying to the invoice to look up",
+		},
+		cli.BoolFlag{
+			Name: "blank_htlcs",
+			Usage: "if set together with --payaddr, no HTLCs are " +
+				"returned",
+		},
+		cli.BoolFlag{
+			Name: "set_htlcs_only",
+			Usage: "if set together with --setid, only the HTLCs " +
+				"of the given set are returned",
+		},
+	},
+	Action: actionDecorator(lookupInvoiceV2),
+}
+
+func lookupInvoiceV2(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getInvoicesClient(ctx)
+	defer cleanUp()
+
+	numRefs := 0
+	for _, flag := range []string{"rhash", "payaddr", "setid"} {
+		if ctx.IsSet(flag) {
+			numRefs++
+		}
+	}
+	if numRefs != 1 {
+		return fmt.Errorf("exactly one of --rhash, --payaddr or " +
+			"--setid must be set")
+	}
+
+	if ctx.Bool("blank_htlcs") && !ctx.IsSet("payaddr") {
+		return fmt.Errorf("--blank_htlcs requires --payaddr")
+	}
+	if ctx.Bool("set_htlcs_only") && !ctx.IsSet("setid") {
+		return fmt.Errorf("--set_htlcs_only requires --setid")
+	}
+
+	req := &invoicesrpc.LookupInvoiceMsg{}
+	switch {
+	case ctx.IsSet("rhash"):
+		rHash, err := hex.DecodeString(ctx.String("rhash"))
+		if err != nil {
+			return fmt.Errorf("unable to decode rhash: %w", err)
+		}
+
+		req.InvoiceRef = &invoicesrpc.LookupInvoiceMsg_PaymentHash{
+			PaymentHash: rHash,
+		}
+
+	case ctx.IsSet("payaddr"):
+		payAddr, err := hex.DecodeString(ctx.String("payaddr"))
+		if err != nil {
+			return fmt.Errorf("unable to decode payaddr: %w", err)
+		}
+
+		req.InvoiceRef = &invoicesrpc.LookupInvoiceMsg_PaymentAddr{
+			PaymentAddr: payAddr,
+		}
+		if ctx.Bool("blank_htlcs") {
+			req.LookupModifier =
+				invoicesrpc.LookupModifier_HTLC_SET_BLANK
+		}
+
+	case ctx.IsSet("setid"):
+		setID, err := hex.DecodeString(ctx.String("setid"))
+		if err != nil {
+			return fmt.Errorf("unable to decode setid: %w", err)
+		}
+
+		req.InvoiceRef = &invoicesrpc.LookupInvoiceMsg_SetId{
+			SetId: setID,
+		}
+		if ctx.Bool("set_htlcs_only") {
+			req.LookupModifier =
+				invoicesrpc.LookupModifier_HTLC_SET_ONLY
+		}
+	}
+
+	invoice, err := client.LookupInvoiceV2(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(invoice)
+
+	return nil
+}
+
+var presentInvoiceCommand = cli.Command{
+	Name:      "presentinvoice",
+	Category:  "Invoices",
+	Usage:     "Turn a payment request into a unified BIP-21 URI.",
+	ArgsUsage: "pay_req",
+	Description: `
+	Turns a payment request into a unified BIP-21 URI that can be paid
+	both on-chain and via lightning, so wallets can display the invoice as
+	a single QR code. If the invoice doesn't carry a fallback address, a
+	new address of the default wallet account is created for it.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "pay_req",
+			Usage: "the BOLT 11 payment request to present",
+		},
+		cli.StringFlag{
+			Name:  "label",
+			Usage: "(optional) a label to add to the URI",
+		},
+	},
+	Action: actionDecorator(presentInvoice),
+}
+
+func presentInvoice(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getInvoicesClient(ctx)
+	defer cleanUp()
+
+	var payReq string
+	switch {
+	case ctx.IsSet("pay_req"):
+		payReq = ctx.String("pay_req")
+
+	case ctx.Args().Present():
+		payReq = ctx.Args().First()
+
+	default:
+		return fmt.Errorf("pay_req argument missing")
+	}
+
+	req := &invoicesrpc.PresentInvoiceRequest{
+		PaymentRequest: StripPrefix(payReq),
+		Label:          ctx.String("label"),
+	}
+	resp, err := client.PresentInvoice(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var addOfferCommand = cli.Command{
+	Name:     "addoffer",
+	Category: "Invoices",
+	Usage:    "Create a new BOLT 12 offer.",
+	Description: `
+	Creates a new BOLT 12 offer that is issued by our node. The offer can
+	be paid repeatedly, payers request an invoice for it from our node over
+	onion messages.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "description",
+			Usage: "a description of the purpose of the payment",
+		},
+		cli.Uint64Flag{
+			Name: "amt_msat",
+			Usage: "(optional) the amount in millisatoshis " +
+				"that is requested for a single item, if not " +
+				"set the payer chooses the amount",
+		},
+		cli.StringFlag{
+			Name:  "issuer",
+			Usage: "(optional) a human-readable name of the issuer",
+		},
+		cli.BoolFlag{
+			Name: "allow_quantity",
+			Usage: "allow payers to request multiple items " +
+				"with a single invoice request",
+		},
+		cli.Uint64Flag{
+			Name: "quantity_max",
+			Usage: "(optional) the maximum number of items that " +
+				"can be requested, unlimited if not set",
+		},
+		cli.Int64Flag{
+			Name: "absolute_expiry",
+			Usage: "(optional) the unix timestamp after which " +
+				"the offer can no longer be paid",
+		},
+	},
+	Action: actionDecorator(addOffer),
+}
+
+func addOffer(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getInvoicesClient(ctx)
+	defer cleanUp()
+
+	req := &invoicesrpc.AddOfferRequest{
+		Description:    ctx.String("description"),
+		AmountMsat:     ctx.Uint64("amt_msat"),
+		Issuer:         ctx.String("issuer"),
+		AllowQuantity:  ctx.Bool("allow_quantity"),
+		QuantityMax:    ctx.Uint64("quantity_max"),
+		AbsoluteExpiry: ctx.Int64("absolute_expiry"),
+	}
+	resp, err := client.AddOffer(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var listOffersCommand = cli.Command{
+	Name:     "listoffers",
+	Category: "Invoices",
+	Usage:    "List the BOLT 12 offers issued by our node.",
+	Action:   actionDecorator(listOffers),
+}
+
+func listOffers(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getInvoicesClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListOffers(ctxc, &invoicesrpc.ListOffersRequest{})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var decodeOfferCommand = cli.Command{
+	Name:      "decodeoffer",
+	Category:  "Invoices",
+	Usage:     "Decode a BOLT 12 offer.",
+	ArgsUsage: "offer",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "offer",
+			Usage: "the BOLT 12 offer string to decode",
+		},
+	},
+	Action: actionDecorator(decodeOffer),
+}
+
+func decodeOffer(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getInvoicesClient(ctx)
+	defer cleanUp()
+
+	var offer string
+	switch {
+	case ctx.IsSet("offer"):
+		offer = ctx.String("offer")
+
+	case ctx.Args().Present():
+		offer = ctx.Args().First()
+
+	default:
+		return fmt.Errorf("offer argument missing")
+	}
+
+	resp, err := client.DecodeOffer(ctxc, &invoicesrpc.DecodeOfferRequest{
+		Offer: offer,
+	})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var addInvoiceTemplateCommand = cli.Command{
+	Name:      "addinvoicetemplate",
+	Category:  "Invoices",
+	Usage:     "Add a template that invoices can be minted from.",
+	ArgsUsage: "name",
+	Description: `
+	Adds a named invoice template. Invoices can be minted from the template
+	on demand with the mintinvoice command. If a recurrence interval is set,
+	a new invoice is minted automatically each time the interval elapses.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "name",
+			Usage: "the unique name of the template",
+		},
+		cli.Uint64Flag{
+			Name: "amt_msat",
+			Usage: "(optional) the amount in millisatoshis " +
+				"of the minted invoices",
+		},
+		cli.StringFlag{
+			Name: "memo",
+			Usage: "(optional) a description of the minted " +
+				"invoices",
+		},
+		cli.Int64Flag{
+			Name: "expiry",
+			Usage: "(optional) the expiry in seconds of the " +
+				"minted invoices",
+		},
+		cli.StringFlag{
+			Name: "fallback_addr",
+			Usage: "(optional) an on-chain fallback address to " +
+				"include in the minted invoices",
+		},
+		cli.Uint64Flag{
+			Name: "cltv_expiry_delta",
+			Usage: "(optional) the minimum CLTV delta of the " +
+				"minted invoices",
+		},
+		cli.BoolFlag{
+			Name: "private",
+			Usage: "include routing hints for private channels " +
+				"in the minted invoices",
+		},
+		cli.BoolFlag{
+			Name: "blind",
+			Usage: "use blinded paths in the minted invoices " +
+				"instead of our node's public key",
+		},
+		cli.Uint64Flag{
+			Name: "recurrence_interval",
+			Usage: "(optional) the interval in seconds after " +
+				"which a new invoice is minted automatically",
+		},
+	},
+	Action: actionDecorator(addInvoiceTemplate),
+}
+
+func addInvoiceTemplate(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getInvoicesClient(ctx)
+	defer cleanUp()
+
+	name, err := templateName(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := &invoicesrpc.InvoiceTemplateInfo{
+		Name:               name,
+		ValueMsat:          ctx.Uint64("amt_msat"),
+		Memo:               ctx.String("memo"),
+		Expiry:             ctx.Int64("expiry"),
+		FallbackAddr:       ctx.String("fallback_addr"),
+		CltvExpiry:         ctx.Uint64("cltv_expiry_delta"),
+		Private:            ctx.Bool("private"),
+		IsBlinded:          ctx.Bool("blind"),
+		RecurrenceInterval: ctx.Uint64("recurrence_interval"),
+	}
+	resp, err := client.AddInvoiceTemplate(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var removeInvoiceTemplateCommand = cli.Command{
+	Name:      "removeinvoicetemplate",
+	Category:  "Invoices",
+	Usage:     "Remove an invoice template.",
+	ArgsUsage: "name",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "name",
+			Usage: "the name of the template to remove",
+		},
+	},
+	Action: actionDecorator(removeInvoiceTemplate),
+}
+
+func removeInvoiceTemplate(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getInvoicesClient(ctx)
+	defer cleanUp()
+
+	name, err := templateName(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.RemoveInvoiceTemplate(
+		ctxc, &invoicesrpc.RemoveInvoiceTemplateRequest{Name: name},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var listInvoiceTemplatesCommand = cli.Command{
+	Name:     "listinvoicetemplates",
+	Category: "Invoices",
+	Usage:    "List all invoice templates.",
+	Action:   actionDecorator(listInvoiceTemplates),
+}
+
+func listInvoiceTemplates(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getInvoicesClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListInvoiceTemplates(
+		ctxc, &invoicesrpc.ListInvoiceTemplatesRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var mintInvoiceCommand = cli.Command{
+	Name:      "mintinvoice",
+	Category:  "Invoices",
+	Usage:     "Create a new invoice from an invoice template.",
+	ArgsUsage: "name",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "name",
+			Usage: "the name of the template to mint an " +
+				"invoice from",
+		},
+	},
+	Action: actionDecorator(mintInvoice),
+}
+
+func mintInvoice(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getInvoicesClient(ctx)
+	defer cleanUp()
+
+	name, err := templateName(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.MintInvoice(
+		ctxc, &invoicesrpc.MintInvoiceRequest{Name: name},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+// templateName returns the invoice template name that was passed either as a
+// flag or as the first positional argument.
+func templateName(ctx *cli.Context) (string, error) {
+	switch {
+	case ctx.IsSet("name"):
+		return ctx.String("name"), nil
+
+	case ctx.Args().Present():
+		return ctx.Args().First(), nil
+
+	default:
+		return "", fmt.Errorf("template name argument missing")
+	}
+}
@@ -457,6 +457,14 @@ func Main() {
 		listUnspentCommand,
 		connectCommand,
 		disconnectCommand,
+		allowPeerCommand,
+		disallowPeerCommand,
+		listAllowedPeersCommand,
+		getGossipEgressCommand,
+		setGossipEgressCommand,
+		checkDBIntegrityCommand,
+		pruneDBOrphansCommand,
+		createDBSnapshotCommand,
 		openChannelCommand,
 		batchOpenChannelCommand,
 		closeChannelCommand,
@@ -482,22 +490,28 @@ func Main() {
 		listPaymentsCommand,
 		describeGraphCommand,
 		getNodeMetricsCommand,
+		subscribeGossipCommand,
+		injectGossipCommand,
 		getChanInfoCommand,
 		getNodeInfoCommand,
 		queryRoutesCommand,
 		getNetworkInfoCommand,
 		debugLevelCommand,
+		workerPoolsCommand,
+		resizeWorkerPoolCommand,
 		decodePayReqCommand,
 		listChainTxnsCommand,
 		stopCommand,
 		signMessageCommand,
 		verifyMessageCommand,
 		feeReportCommand,
+		suggestFeesCommand,
 		updateChannelPolicyCommand,
 		forwardingHistoryCommand,
 		exportChanBackupCommand,
 		verifyChanBackupCommand,
 		restoreChanBackupCommand,
+		backupReplicationStatusCommand,
 		bakeMacaroonCommand,
 		listMacaroonIDsCommand,
 		deleteMacaroonIDCommand,
@@ -513,6 +527,11 @@ func Main() {
 		subscribeCustomCommand,
 		fishCompletionCommand,
 		listAliasesCommand,
+		commitmentFeeInfoCommand,
+		exportResolutionsCommand,
+		timeLockedFundsCommand,
+		pendingResolutionsCommand,
+		auditScidLeaksCommand,
 		estimateRouteFeeCommand,
 		generateManPageCommand,
 	}
@@ -4,7 +4,10 @@
 package commands
 
 import (
+	"encoding/hex"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/peersrpc"
@@ -43,11 +46,13 @@ var updateNodeAnnouncementCommand = cli.Command{
 	Update the node's information and broadcast a new node announcement.
 
 	Add or remove addresses where your node can be reached at, change the
-	alias/color of the node or enable/disable supported feature bits without
-	restarting the node. A node announcement with the new information will
-	be created and brodcasted to the network.`,
+	alias/color of the node, enable/disable supported feature bits or
+	advertise custom TLV records without restarting the node. A node
+	announcement with the new information will be created and brodcasted to
+	the network.`,
 	ArgsUsage: "[--address_add=] [--address_remove=] [--alias=] " +
-		"[--color=] [--feature_bit_add=] [--feature_bit_remove=]",
+		"[--color=] [--feature_bit_add=] [--feature_bit_remove=] " +
+		"[--custom_record_add=] [--custom_record_remove=]",
 	Flags: []cli.Flag{
 		cli.StringSliceFlag{
 			Name: "address_add",
@@ -79,6 +84,19 @@ var updateNodeAnnouncementCommand = cli.Command{
 			Usage: "a feature bit that needs to be disabled. " +
 				"Can be set multiple times in the same command",
 		},
+		cli.StringSliceFlag{
+			Name: "custom_record_add",
+			Usage: "a custom TLV record to advertise, in the " +
+				"format <record_type>=<hex_value>. Record " +
+				"types must be odd and start from 65536. Can " +
+				"be set multiple times in the same command",
+		},
+		cli.StringSliceFlag{
+			Name: "custom_record_remove",
+			Usage: "the type of a custom TLV record that should " +
+				"no longer be advertised. Can be set " +
+				"multiple times in the same command",
+		},
 	},
 	Action: actionDecorator(updateNodeAnnouncement),
 }
@@ -146,6 +164,57 @@ func updateNodeAnnouncement(ctx *cli.Context) error {
 		}
 	}
 
+	if ctx.IsSet("custom_record_add") {
+		change = true
+		for _, r := range ctx.StringSlice("custom_record_add") {
+			kv := strings.Split(r, "=")
+			if len(kv) != 2 {
+				return fmt.Errorf("invalid custom record "+
+					"format: %v", r)
+			}
+
+			recordType, err := strconv.ParseUint(kv[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid custom record "+
+					"type: %w", err)
+			}
+
+			value, err := hex.DecodeString(kv[1])
+			if err != nil {
+				return fmt.Errorf("invalid custom record "+
+					"value: %w", err)
+			}
+
+			action := &peersrpc.UpdateCustomRecordAction{
+				Action: peersrpc.UpdateAction_ADD,
+				Type:   recordType,
+				Value:  value,
+			}
+			req.CustomRecordUpdates = append(
+				req.CustomRecordUpdates, action,
+			)
+		}
+	}
+
+	if ctx.IsSet("custom_record_remove") {
+		change = true
+		for _, r := range ctx.StringSlice("custom_record_remove") {
+			recordType, err := strconv.ParseUint(r, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid custom record "+
+					"type: %w", err)
+			}
+
+			action := &peersrpc.UpdateCustomRecordAction{
+				Action: peersrpc.UpdateAction_REMOVE,
+				Type:   recordType,
+			}
+			req.CustomRecordUpdates = append(
+				req.CustomRecordUpdates, action,
+			)
+		}
+	}
+
 	if !change {
 		return fmt.Errorf("no changes for the node information " +
 			"detected")
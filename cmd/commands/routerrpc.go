@@ -13,5 +13,11 @@ func routerCommands() []cli.Command {
 		getCfgCommand,
 		setCfgCommand,
 		updateChanStatusCommand,
+		scheduleMaintenanceCommand,
+		cancelMaintenanceCommand,
+		listMaintenanceCommand,
+		fwdLatenciesCommand,
+		listScheduledPaymentsCommand,
+		sendPaymentBatchCommand,
 	}
 }
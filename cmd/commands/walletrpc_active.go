@@ -64,6 +64,17 @@ var (
 		},
 	}
 
+	// descriptorsCommand is a wallet subcommand that is responsible for
+	// exporting and importing output descriptors.
+	descriptorsCommand = cli.Command{
+		Name:  "descriptors",
+		Usage: "Export and import output descriptors.",
+		Subcommands: []cli.Command{
+			exportDescriptorsCommand,
+			importDescriptorsCommand,
+		},
+	}
+
 	p2TrChangeType = walletrpc.ChangeAddressType_CHANGE_ADDRESS_TYPE_P2TR
 )
 
@@ -83,6 +94,10 @@ func walletCommands() []cli.Command {
 				bumpCloseFeeCommand,
 				bumpForceCloseFeeCommand,
 				listSweepsCommand,
+				sweepJournalCommand,
+				sweepFeeReportCommand,
+				getSignPolicyCommand,
+				setSignPolicyCommand,
 				labelTxCommand,
 				publishTxCommand,
 				getTxCommand,
@@ -94,6 +109,9 @@ func walletCommands() []cli.Command {
 				accountsCommand,
 				requiredReserveCommand,
 				addressesCommand,
+				fundingPreviewCommand,
+				batchSendCommand,
+				descriptorsCommand,
 			},
 		},
 	}
@@ -575,6 +593,253 @@ func listSweeps(ctx *cli.Context) error {
 	return nil
 }
 
+var sweepJournalCommand = cli.Command{
+	Name:  "sweepjournal",
+	Usage: "Lists the recorded outcomes of past sweep attempts.",
+	Flags: []cli.Flag{
+		cli.Uint64Flag{
+			Name: "index_offset",
+			Usage: "the index after which entries are returned, " +
+				"set it to the last_index_offset of the " +
+				"previous response to fetch the next page",
+		},
+		cli.UintFlag{
+			Name: "max_entries",
+			Usage: "the maximum number of entries to return, at " +
+				"most 1000",
+		},
+		cli.StringSliceFlag{
+			Name: "outcome",
+			Usage: "only return entries with this outcome, one " +
+				"of confirmed, replaced, conflicted or " +
+				"abandoned. Can be set multiple times",
+		},
+		cli.IntFlag{
+			Name: "start_height",
+			Usage: "only return entries recorded at or after " +
+				"this height",
+		},
+		cli.IntFlag{
+			Name: "end_height",
+			Usage: "only return entries recorded at or before " +
+				"this height",
+		},
+	},
+	Description: `
+	List the recorded outcomes of past sweep attempts, in the order they
+	were recorded. The response summarizes the fees per outcome and lists
+	the inputs of conflicted attempts, ordered by how often they were
+	conflicted. Inputs that are conflicted repeatedly may be the target of
+	a pinning attack.
+	`,
+	Action: actionDecorator(sweepJournal),
+}
+
+func sweepJournal(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getWalletClient(ctx)
+	defer cleanUp()
+
+	req := &walletrpc.SweepJournalRequest{
+		IndexOffset: ctx.Uint64("index_offset"),
+		MaxEntries:  uint32(ctx.Uint("max_entries")),
+		StartHeight: int32(ctx.Int("start_height")),
+		EndHeight:   int32(ctx.Int("end_height")),
+	}
+
+	for _, outcome := range ctx.StringSlice("outcome") {
+		name := "SWEEP_" + strings.ToUpper(outcome)
+		value, ok := walletrpc.SweepOutcome_value[name]
+		if !ok {
+			return fmt.Errorf("unknown sweep outcome: %v", outcome)
+		}
+
+		req.Outcomes = append(
+			req.Outcomes, walletrpc.SweepOutcome(value),
+		)
+	}
+
+	resp, err := client.SweepJournal(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var sweepFeeReportCommand = cli.Command{
+	Name: "sweepfeereport",
+	Usage: "Reports the outcome of sweeping the pending inputs at " +
+		"alternative fee rates.",
+	Flags: []cli.Flag{
+		cli.Int64SliceFlag{
+			Name: "sat_per_vbyte",
+			Usage: "a fee rate in sat/vbyte to evaluate the " +
+				"pending sweeps at. Can be set multiple times",
+		},
+		cli.Int64SliceFlag{
+			Name: "conf_target",
+			Usage: "a conf target to estimate a fee rate for and " +
+				"evaluate the pending sweeps at. Can be set " +
+				"multiple times",
+		},
+	},
+	Description: `
+	Report, for each of the given fee rates, the outcome of sweeping the
+	inputs that are currently pending in the sweeper. The inputs are
+	clustered into sets the same way they would be swept, and for each set
+	the fee and whether its budget covers the fee are reported. No
+	transactions are created or published.
+	`,
+	Action: actionDecorator(sweepFeeReport),
+}
+
+func sweepFeeReport(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getWalletClient(ctx)
+	defer cleanUp()
+
+	req := &walletrpc.SweepFeeReportRequest{}
+	for _, feeRate := range ctx.Int64Slice("sat_per_vbyte") {
+		if feeRate <= 0 {
+			return fmt.Errorf("invalid fee rate: %v", feeRate)
+		}
+
+		req.Candidates = append(req.Candidates,
+			&walletrpc.SweepFeeCandidate{
+				SatPerVbyte: uint64(feeRate),
+			},
+		)
+	}
+	for _, confTarget := range ctx.Int64Slice("conf_target") {
+		if confTarget <= 0 || confTarget > math.MaxUint32 {
+			return fmt.Errorf("invalid conf target: %v",
+				confTarget)
+		}
+
+		req.Candidates = append(req.Candidates,
+			&walletrpc.SweepFeeCandidate{
+				TargetConf: uint32(confTarget),
+			},
+		)
+	}
+
+	if len(req.Candidates) == 0 {
+		return fmt.Errorf("at least one of sat_per_vbyte or " +
+			"conf_target must be set")
+	}
+
+	resp, err := client.SweepFeeReport(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var getSignPolicyCommand = cli.Command{
+	Name:  "getsignpolicy",
+	Usage: "Display the policy enforced by the internal signer.",
+	Description: `
+	Display the policy the internal signer enforces before it signs away
+	the funds of the wallet.
+
+	This command requires the dedicated signpolicy macaroon, which has to
+	be passed with --macaroonpath. It's written to the network directory
+	as signpolicy.macaroon.
+	`,
+	Action: actionDecorator(getSignPolicy),
+}
+
+func getSignPolicy(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getWalletClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.GetSignPolicy(
+		ctxc, &walletrpc.GetSignPolicyRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var setSignPolicyCommand = cli.Command{
+	Name:  "setsignpolicy",
+	Usage: "Replace the policy enforced by the internal signer.",
+	Flags: []cli.Flag{
+		cli.Int64Flag{
+			Name: "max_spend",
+			Usage: "the maximum total amount in satoshis that " +
+				"may be sent to outputs not belonging to " +
+				"the wallet within the spend window, 0 " +
+				"disables the limit",
+		},
+		cli.DurationFlag{
+			Name: "spend_window",
+			Usage: "the sliding time window over which max_spend " +
+				"is enforced, e.g. 24h",
+		},
+		cli.StringSliceFlag{
+			Name: "allowed_address",
+			Usage: "an address the funds of the wallet may be " +
+				"sent to. Can be set multiple times",
+		},
+		cli.StringSliceFlag{
+			Name: "allowed_peer",
+			Usage: "the pubkey of a node channels may be funded " +
+				"with. Can be set multiple times",
+		},
+	},
+	Description: `
+	Replace the policy the internal signer enforces before it signs away
+	the funds of the wallet. All rules of the policy are replaced, so rules
+	that aren't set are lifted. Spends that were authorized before still
+	count towards the velocity limit of the new policy. The policy is reset
+	to the configured one when lnd restarts.
+
+	This command requires the dedicated signpolicy macaroon, which has to
+	be passed with --macaroonpath. It's written to the network directory
+	as signpolicy.macaroon.
+	`,
+	Action: actionDecorator(setSignPolicy),
+}
+
+func setSignPolicy(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getWalletClient(ctx)
+	defer cleanUp()
+
+	spendWindow := ctx.Duration("spend_window")
+	if spendWindow < 0 {
+		return fmt.Errorf("invalid spend window: %v", spendWindow)
+	}
+
+	req := &walletrpc.SignPolicy{
+		MaxSpendSat:        ctx.Int64("max_spend"),
+		SpendWindowSeconds: uint64(spendWindow.Seconds()),
+		AllowedAddresses:   ctx.StringSlice("allowed_address"),
+		AllowedPeers:       ctx.StringSlice("allowed_peer"),
+	}
+
+	resp, err := client.SetSignPolicy(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
 var labelTxCommand = cli.Command{
 	Name:      "labeltx",
 	Usage:     "Adds a label to a transaction.",
@@ -1395,6 +1660,405 @@ func fundPsbt(ctx *cli.Context) error {
 	return nil
 }
 
+var fundingPreviewCommand = cli.Command{
+	Name: "fundingpreview",
+	Usage: "Preview the coins the wallet would select to fund an " +
+		"output.",
+	ArgsUsage: "--amt=A [--conf_target=C | --sat_per_vbyte=S]",
+	Description: `
+	Shows the inputs, change and fee the wallet would choose to fund an
+	output of the given amount, without locking any coins or otherwise
+	modifying the wallet. This can be used to see what a channel open or
+	send will do before committing to it.
+
+	The optional '--output_script' flag sets the hex encoded pkScript of
+	the output to fund, which is used to estimate the weight of the
+	transaction. A P2WSH channel funding output is assumed by default.
+	`,
+	Flags: []cli.Flag{
+		cli.Int64Flag{
+			Name:  "amt",
+			Usage: "the value of the output to fund in satoshis",
+		},
+		cli.StringFlag{
+			Name: "output_script",
+			Usage: "(optional) the hex encoded pkScript of the " +
+				"output to fund",
+		},
+		cli.Uint64Flag{
+			Name: "conf_target",
+			Usage: "the number of blocks that the transaction " +
+				"should be confirmed on-chain within",
+			Value: 6,
+		},
+		cli.Uint64Flag{
+			Name: "sat_per_vbyte",
+			Usage: "a manual fee expressed in sat/vbyte that " +
+				"should be used when funding the output",
+		},
+		cli.StringFlag{
+			Name: "account",
+			Usage: "(optional) the name of the account to select " +
+				"coins from",
+		},
+		cli.Uint64Flag{
+			Name: "min_confs",
+			Usage: "(optional) the minimum number of " +
+				"confirmations of the selected coins",
+			Value: defaultUtxoMinConf,
+		},
+		cli.Uint64Flag{
+			Name: "max_confs",
+			Usage: "(optional) the maximum number of " +
+				"confirmations of the selected coins",
+		},
+		cli.StringSliceFlag{
+			Name: "utxo",
+			Usage: "a utxo specified as outpoint(tx:idx) that " +
+				"may be selected, can be set multiple times " +
+				"to restrict the selection to several utxos",
+		},
+		cli.StringSliceFlag{
+			Name: "exclude_utxo",
+			Usage: "a utxo specified as outpoint(tx:idx) that " +
+				"must not be selected, can be set multiple " +
+				"times",
+		},
+		cli.StringFlag{
+			Name: "change_type",
+			Usage: "(optional) the type of the change output, " +
+				"only p2tr is supported (default to p2wkh)",
+		},
+		coinSelectionStrategyFlag,
+	},
+	Action: actionDecorator(fundingPreview),
+}
+
+func fundingPreview(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	// Display the command's help message if there aren't any flags
+	// specified.
+	if ctx.NumFlags() == 0 {
+		return cli.ShowCommandHelp(ctx, "fundingpreview")
+	}
+
+	coinSelectionStrategy, err := parseCoinSelectionStrategy(ctx)
+	if err != nil {
+		return err
+	}
+
+	outputScript, err := hex.DecodeString(ctx.String("output_script"))
+	if err != nil {
+		return fmt.Errorf("unable to decode output script: %w", err)
+	}
+
+	req := &walletrpc.FundingPreviewRequest{
+		AmountSat:             ctx.Int64("amt"),
+		OutputScript:          outputScript,
+		Account:               ctx.String("account"),
+		MinConfs:              int32(ctx.Uint64("min_confs")),
+		MaxConfs:              int32(ctx.Uint64("max_confs")),
+		CoinSelectionStrategy: coinSelectionStrategy,
+	}
+
+	if ctx.IsSet("utxo") {
+		req.Outpoints, err = UtxosToOutpoints(ctx.StringSlice("utxo"))
+		if err != nil {
+			return fmt.Errorf("unable to decode utxos: %w", err)
+		}
+	}
+
+	if ctx.IsSet("exclude_utxo") {
+		req.ExcludeOutpoints, err = UtxosToOutpoints(
+			ctx.StringSlice("exclude_utxo"),
+		)
+		if err != nil {
+			return fmt.Errorf("unable to decode excluded utxos: "+
+				"%w", err)
+		}
+	}
+
+	// Parse fee flags.
+	switch {
+	case ctx.IsSet("conf_target") && ctx.IsSet("sat_per_vbyte"):
+		return fmt.Errorf("cannot set conf_target and sat_per_vbyte " +
+			"at the same time")
+
+	case ctx.Uint64("sat_per_vbyte") > 0:
+		req.Fees = &walletrpc.FundingPreviewRequest_SatPerVbyte{
+			SatPerVbyte: ctx.Uint64("sat_per_vbyte"),
+		}
+
+	// Check conf_target last because it has a default value.
+	case ctx.Uint64("conf_target") > 0:
+		req.Fees = &walletrpc.FundingPreviewRequest_TargetConf{
+			TargetConf: uint32(ctx.Uint64("conf_target")),
+		}
+	}
+
+	if ctx.IsSet("change_type") {
+		switch addressType := ctx.String("change_type"); addressType {
+		case "p2tr":
+			req.ChangeType = p2TrChangeType
+
+		default:
+			return fmt.Errorf("invalid type for the change type: "+
+				"%s. At the moment, the only address type "+
+				"supported is p2tr (default to p2wkh)",
+				addressType)
+		}
+	}
+
+	walletClient, cleanUp := getWalletClient(ctx)
+	defer cleanUp()
+
+	resp, err := walletClient.FundingPreview(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var batchSendCommand = cli.Command{
+	Name:      "batchsend",
+	Usage:     "Pay to multiple outputs in a single transaction.",
+	ArgsUsage: "--outputs=O [--conf_target=C | --sat_per_vbyte=S]",
+	Description: `
+	Builds, signs and publishes a single transaction paying to all the
+	given outputs, and prints the outpoint of each of them.
+
+	The 'outputs' flag decodes a JSON list of outputs, each with an address,
+	an amount in satoshis and an optional label that is returned alongside
+	the output's outpoint:
+
+	    --outputs='[{"addr": "ExampleAddr", "amount_sat": 1000,
+	    "label": "withdrawal 1"}, ...]'
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "outputs",
+			Usage: "a JSON list of the outputs to pay to",
+		},
+		cli.StringFlag{
+			Name:  "label",
+			Usage: "(optional) a label for the transaction",
+		},
+		cli.Uint64Flag{
+			Name: "conf_target",
+			Usage: "the number of blocks that the transaction " +
+				"should be confirmed on-chain within",
+			Value: 6,
+		},
+		cli.Uint64Flag{
+			Name: "sat_per_vbyte",
+			Usage: "a manual fee expressed in sat/vbyte that " +
+				"should be used when creating the transaction",
+		},
+		cli.Uint64Flag{
+			Name: "min_confs",
+			Usage: "(optional) the minimum number of " +
+				"confirmations of the spent coins",
+			Value: defaultUtxoMinConf,
+		},
+		cli.StringSliceFlag{
+			Name: "utxo",
+			Usage: "a utxo specified as outpoint(tx:idx) that " +
+				"may be spent, can be set multiple times to " +
+				"restrict the selection to several utxos",
+		},
+		cli.StringSliceFlag{
+			Name: "exclude_utxo",
+			Usage: "a utxo specified as outpoint(tx:idx) that " +
+				"must not be spent, can be set multiple times",
+		},
+		coinSelectionStrategyFlag,
+	},
+	Action: actionDecorator(batchSend),
+}
+
+// batchSendOutput is a single output of the batchsend command's outputs flag.
+type batchSendOutput struct {
+	Addr      string `json:"addr"`
+	AmountSat int64  `json:"amount_sat"`
+	Label     string `json:"label"`
+}
+
+func batchSend(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	// Display the command's help message if there aren't any flags
+	// specified.
+	if ctx.NumFlags() == 0 {
+		return cli.ShowCommandHelp(ctx, "batchsend")
+	}
+
+	var outputs []batchSendOutput
+	err := json.Unmarshal([]byte(ctx.String("outputs")), &outputs)
+	if err != nil {
+		return fmt.Errorf("error parsing outputs JSON: %w", err)
+	}
+
+	coinSelectionStrategy, err := parseCoinSelectionStrategy(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := &walletrpc.BatchWalletSendRequest{
+		Label:                 ctx.String("label"),
+		MinConfs:              int32(ctx.Uint64("min_confs")),
+		CoinSelectionStrategy: coinSelectionStrategy,
+	}
+	for _, output := range outputs {
+		req.Outputs = append(req.Outputs, &walletrpc.BatchSendOutput{
+			Addr:      output.Addr,
+			AmountSat: output.AmountSat,
+			Label:     output.Label,
+		})
+	}
+
+	if ctx.IsSet("utxo") {
+		req.IncludeOutpoints, err = UtxosToOutpoints(
+			ctx.StringSlice("utxo"),
+		)
+		if err != nil {
+			return fmt.Errorf("unable to decode utxos: %w", err)
+		}
+	}
+
+	if ctx.IsSet("exclude_utxo") {
+		req.ExcludeOutpoints, err = UtxosToOutpoints(
+			ctx.StringSlice("exclude_utxo"),
+		)
+		if err != nil {
+			return fmt.Errorf("unable to decode excluded utxos: "+
+				"%w", err)
+		}
+	}
+
+	// Parse fee flags.
+	switch {
+	case ctx.IsSet("conf_target") && ctx.IsSet("sat_per_vbyte"):
+		return fmt.Errorf("cannot set conf_target and sat_per_vbyte " +
+			"at the same time")
+
+	case ctx.Uint64("sat_per_vbyte") > 0:
+		req.SatPerVbyte = ctx.Uint64("sat_per_vbyte")
+
+	default:
+		req.TargetConf = uint32(ctx.Uint64("conf_target"))
+	}
+
+	walletClient, cleanUp := getWalletClient(ctx)
+	defer cleanUp()
+
+	resp, err := walletClient.BatchWalletSend(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var exportDescriptorsCommand = cli.Command{
+	Name:  "export",
+	Usage: "Export output descriptors of the wallet.",
+	Description: `
+	Exports output descriptors covering the scripts of the wallet accounts
+	and, optionally, the channel outputs paying to us. They can be imported
+	into external watch-only wallets, such as bitcoind, to monitor the
+	funds of the node.
+
+	NOTE: The descriptors don't contain any private keys, but they reveal
+	all addresses of the wallet to whoever obtains them.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "account_name",
+			Usage: "(optional) only export the descriptors of " +
+				"the account with this name",
+		},
+		cli.BoolFlag{
+			Name: "include_channels",
+			Usage: "also export descriptors for the channel " +
+				"outputs paying to us",
+		},
+	},
+	Action: actionDecorator(exportDescriptors),
+}
+
+func exportDescriptors(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	// Display the command's help message if we do not have the expected
+	// number of arguments/flags.
+	if ctx.NArg() > 0 {
+		return cli.ShowCommandHelp(ctx, "export")
+	}
+
+	walletClient, cleanUp := getWalletClient(ctx)
+	defer cleanUp()
+
+	req := &walletrpc.ExportDescriptorsRequest{
+		AccountName:     ctx.String("account_name"),
+		IncludeChannels: ctx.Bool("include_channels"),
+	}
+	resp, err := walletClient.ExportDescriptors(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var importDescriptorsCommand = cli.Command{
+	Name:      "import",
+	Usage:     "Import output descriptors into the wallet.",
+	ArgsUsage: "account_name descriptor [descriptor...]",
+	Description: `
+	Imports the given output descriptors into the wallet to watch the
+	funds they describe, for example after recovering a node. Ranged
+	descriptors of an account key are imported as account, named after
+	account_name, and single key descriptors as public key. Address
+	descriptors are skipped.
+
+	NOTE: The wallet only finds past transactions of the imported keys
+	after a rescan from a height before their first use.
+	`,
+	Action: actionDecorator(importDescriptors),
+}
+
+func importDescriptors(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	// Display the command's help message if we do not have the expected
+	// number of arguments/flags.
+	if ctx.NArg() < 2 || ctx.NumFlags() > 0 {
+		return cli.ShowCommandHelp(ctx, "import")
+	}
+
+	walletClient, cleanUp := getWalletClient(ctx)
+	defer cleanUp()
+
+	req := &walletrpc.ImportDescriptorsRequest{
+		AccountName: ctx.Args().First(),
+		Descriptors: ctx.Args().Tail(),
+	}
+	resp, err := walletClient.ImportDescriptors(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
 // marshallLocks converts the rpc lease information to a more json-friendly
 // format.
 func marshallLocks(lockedUtxos []*walletrpc.UtxoLease) []*utxoLease {
@@ -25,6 +25,7 @@ import (
 	"github.com/lightninglabs/neutrino"
 	"github.com/lightningnetwork/lnd/autopilot"
 	"github.com/lightningnetwork/lnd/build"
+	"github.com/lightningnetwork/lnd/chainntnfs/electrumnotify"
 	"github.com/lightningnetwork/lnd/chainreg"
 	"github.com/lightningnetwork/lnd/chanbackup"
 	"github.com/lightningnetwork/lnd/channeldb"
@@ -364,12 +365,15 @@ type Config struct {
 	MaxPendingChannels int    `long:"maxpendingchannels" description:"The maximum number of incoming pending channels permitted per peer."`
 	BackupFilePath     string `long:"backupfilepath" description:"The target location of the channel backup file"`
 
+	BackupPassphraseFile string `long:"backuppassphrasefile" description:"The full path to a file that contains a passphrase used to encrypt the static channel backups instead of the key derived from the wallet seed. Backups encrypted with the seed derived key can still be restored when this is set."`
+
 	FeeURL string `long:"feeurl" description:"DEPRECATED: Use 'fee.url' option. Optional URL for external fee estimation. If no URL is specified, the method for fee estimation will depend on the chosen backend and network. Must be set for neutrino on mainnet." hidden:"true"`
 
 	Bitcoin      *lncfg.Chain    `group:"Bitcoin" namespace:"bitcoin"`
 	BtcdMode     *lncfg.Btcd     `group:"btcd" namespace:"btcd"`
 	BitcoindMode *lncfg.Bitcoind `group:"bitcoind" namespace:"bitcoind"`
 	NeutrinoMode *lncfg.Neutrino `group:"neutrino" namespace:"neutrino"`
+	ElectrumMode *lncfg.Electrum `group:"electrum" namespace:"electrum"`
 
 	BlockCacheSize uint64 `long:"blockcachesize" description:"The maximum capacity of the block cache"`
 
@@ -464,6 +468,12 @@ type Config struct {
 
 	Gossip *lncfg.Gossip `group:"gossip" namespace:"gossip"`
 
+	AllowList *lncfg.AllowList `group:"allowlist" namespace:"allowlist"`
+
+	PendingChannels *lncfg.PendingChannels `group:"pendingchannels" namespace:"pendingchannels"`
+
+	Funding *lncfg.Funding `group:"funding" namespace:"funding"`
+
 	Workers *lncfg.Workers `group:"workers" namespace:"workers"`
 
 	Caches *lncfg.Caches `group:"caches" namespace:"caches"`
@@ -490,8 +500,14 @@ type Config struct {
 
 	Sweeper *lncfg.Sweeper `group:"sweeper" namespace:"sweeper"`
 
+	BackupReplication *lncfg.BackupReplication `group:"backupreplication" namespace:"backupreplication"`
+
+	SignPolicy *lncfg.SignPolicy `group:"signpolicy" namespace:"signpolicy"`
+
 	Htlcswitch *lncfg.Htlcswitch `group:"htlcswitch" namespace:"htlcswitch"`
 
+	Keepalive *lncfg.Keepalive `group:"keepalive" namespace:"keepalive"`
+
 	GRPC *GRPCConfig `group:"grpc" namespace:"grpc"`
 
 	// LogWriter is the root logger that all of the daemon's subloggers are
@@ -588,11 +604,15 @@ func DefaultConfig() Config {
 			EstimateMode:       defaultBitcoindEstimateMode,
 			PrunedNodeMaxPeers: defaultPrunedNodeMaxPeers,
 			ZMQReadDeadline:    defaultZMQReadDeadline,
+			TxPollingJitter:    lncfg.DefaultTxPollingJitter,
 		},
 		NeutrinoMode: &lncfg.Neutrino{
 			UserAgentName:    neutrino.UserAgentName,
 			UserAgentVersion: neutrino.UserAgentVersion,
 		},
+		ElectrumMode: &lncfg.Electrum{
+			RequestTimeout: electrumnotify.DefaultRequestTimeout,
+		},
 		BlockCacheSize:     defaultBlockCacheSize,
 		MaxPendingChannels: lncfg.DefaultMaxPendingChannels,
 		NoSeedBackup:       defaultNoSeedBackup,
@@ -697,9 +717,14 @@ func DefaultConfig() Config {
 			MaxChannelUpdateBurst: discovery.DefaultMaxChannelUpdateBurst,
 			ChannelUpdateInterval: discovery.DefaultChannelUpdateInterval,
 			SubBatchDelay:         discovery.DefaultSubBatchDelay,
+			InitialSyncPeers:      1,
 		},
+		AllowList:       &lncfg.AllowList{},
+		PendingChannels: lncfg.DefaultPendingChannels(),
+		Funding:         lncfg.DefaultFunding(),
 		Invoices: &lncfg.Invoices{
-			HoldExpiryDelta: lncfg.DefaultHoldInvoiceExpiryDelta,
+			HoldExpiryDelta:   lncfg.DefaultHoldInvoiceExpiryDelta,
+			OverpaymentPolicy: lncfg.DefaultOverpaymentPolicy,
 		},
 		Routing: &lncfg.Routing{
 			BlindedPaths: lncfg.BlindedPaths{
@@ -709,6 +734,11 @@ func DefaultConfig() Config {
 				PolicyIncreaseMultiplier: lncfg.DefaultBlindedPathPolicyIncreaseMultiplier,
 				PolicyDecreaseMultiplier: lncfg.DefaultBlindedPathPolicyDecreaseMultiplier,
 			},
+			FeeSuggest: lncfg.FeeSuggest{
+				Lookback:   lncfg.DefaultFeeSuggestLookback,
+				MaxFeeRate: lncfg.DefaultFeeSuggestMaxFeeRate,
+				MaxChange:  lncfg.DefaultFeeSuggestMaxChange,
+			},
 		},
 		MaxOutgoingCltvExpiry:     htlcswitch.DefaultMaxOutgoingCltvExpiry,
 		MaxChannelFeeAllocation:   htlcswitch.DefaultMaxLinkFeeAllocation,
@@ -725,12 +755,18 @@ func DefaultConfig() Config {
 		CoinSelectionStrategy:     defaultCoinSelectionStrategy,
 		KeepFailedPaymentAttempts: defaultKeepFailedPaymentAttempts,
 		RemoteSigner: &lncfg.RemoteSigner{
-			Timeout: lncfg.DefaultRemoteSignerRPCTimeout,
+			Timeout:               lncfg.DefaultRemoteSignerRPCTimeout,
+			GossipSignConcurrency: lncfg.DefaultGossipSignConcurrency,
+			GossipSigCacheSize:    lncfg.DefaultGossipSigCacheSize,
 		},
-		Sweeper: lncfg.DefaultSweeperConfig(),
+		Sweeper:           lncfg.DefaultSweeperConfig(),
+		BackupReplication: lncfg.DefaultBackupReplication(),
+		SignPolicy:        lncfg.DefaultSignPolicy(),
 		Htlcswitch: &lncfg.Htlcswitch{
-			MailboxDeliveryTimeout: htlcswitch.DefaultMailboxDeliveryTimeout,
+			MailboxDeliveryTimeout:  htlcswitch.DefaultMailboxDeliveryTimeout,
+			OutgoingCltvRejectDelta: lncfg.DefaultOutgoingCltvRejectDelta,
 		},
+		Keepalive: lncfg.DefaultKeepaliveConfig(),
 		GRPC: &GRPCConfig{
 			ServerPingTime:    defaultGrpcServerPingTime,
 			ServerPingTimeout: defaultGrpcServerPingTimeout,
@@ -980,7 +1016,9 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 	cfg.Tor.PrivateKeyPath = CleanAndExpandPath(cfg.Tor.PrivateKeyPath)
 	cfg.Tor.WatchtowerKeyPath = CleanAndExpandPath(cfg.Tor.WatchtowerKeyPath)
 	cfg.Watchtower.TowerDir = CleanAndExpandPath(cfg.Watchtower.TowerDir)
+	cfg.DB.Bolt.SnapshotDir = CleanAndExpandPath(cfg.DB.Bolt.SnapshotDir)
 	cfg.BackupFilePath = CleanAndExpandPath(cfg.BackupFilePath)
+	cfg.BackupPassphraseFile = CleanAndExpandPath(cfg.BackupPassphraseFile)
 	cfg.WalletUnlockPasswordFile = CleanAndExpandPath(
 		cfg.WalletUnlockPasswordFile,
 	)
@@ -1275,6 +1313,15 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 		return nil, mkErr("error validating bitcoin params: %v", err)
 	}
 
+	// The Electrum server only replaces the chain notifier, so it needs to
+	// be paired with a backend for the wallet.
+	if cfg.ElectrumMode.Server != "" &&
+		cfg.Bitcoin.Node != neutrinoBackendName {
+
+		return nil, mkErr("electrum.server is only supported with " +
+			"bitcoin.node=neutrino")
+	}
+
 	switch cfg.Bitcoin.Node {
 	case btcdBackendName:
 		err := parseRPCParams(
@@ -1290,6 +1337,10 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 				"support simnet")
 		}
 
+		if err := cfg.BitcoindMode.Validate(); err != nil {
+			return nil, mkErr("invalid bitcoind config: %v", err)
+		}
+
 		err := parseRPCParams(
 			cfg.Bitcoin, cfg.BitcoindMode, cfg.ActiveNetParams,
 		)
@@ -1299,6 +1350,9 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 		}
 	case neutrinoBackendName:
 		// No need to get RPC parameters.
+		if err := cfg.ElectrumMode.Validate(); err != nil {
+			return nil, mkErr("invalid electrum config: %v", err)
+		}
 
 	case "nochainbackend":
 		// Nothing to configure, we're running without any chain
@@ -1535,6 +1589,13 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 
 		return nil, mkErr("wallet unlock password file %s does "+
 			"not exist", cfg.WalletUnlockPasswordFile)
+
+	// If a backup passphrase file was specified, we need it to exist.
+	case cfg.BackupPassphraseFile != "" &&
+		!lnrpc.FileExists(cfg.BackupPassphraseFile):
+
+		return nil, mkErr("backup passphrase file %s does not exist",
+			cfg.BackupPassphraseFile)
 	}
 
 	// For each of the RPC listeners (REST+gRPC), we'll ensure that users
@@ -1690,6 +1751,17 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 		return nil, mkErr("error parsing gossip syncer: %v", err)
 	}
 
+	// The gossip bridge lets anyone with RPC access inject gossip, so we
+	// only allow it on private test networks.
+	if cfg.Gossip.Bridge && (cfg.Bitcoin.MainNet || cfg.Bitcoin.TestNet3) {
+		return nil, mkErr("gossip.bridge is only allowed on regtest, " +
+			"simnet and signet")
+	}
+
+	if err := cfg.AllowList.Parse(); err != nil {
+		return nil, mkErr("error parsing allow list: %v", err)
+	}
+
 	// If the experimental protocol options specify any protocol messages
 	// that we want to handle as custom messages, set them now.
 	customMsg := cfg.ProtocolOptions.CustomMessageOverrides()
@@ -1711,9 +1783,15 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 		cfg.RPCMiddleware,
 		cfg.RemoteSigner,
 		cfg.Sweeper,
+		cfg.BackupReplication,
+		cfg.SignPolicy,
 		cfg.Htlcswitch,
+		cfg.Keepalive,
 		cfg.Invoices,
 		cfg.Routing,
+		cfg.PendingChannels,
+		cfg.Funding,
+		cfg.SubRPCServers.RouterRPC.PaymentRetry,
 	)
 	if err != nil {
 		return nil, err
@@ -1915,7 +1993,8 @@ func parseRPCParams(cConfig *lncfg.Chain, nodeConfig interface{},
 
 			return fmt.Errorf("please set %[1]v.rpcuser and "+
 				"%[1]v.rpcpass (or %[1]v.rpccookie) together "+
-				"with %[1]v.zmqpubrawblock, %[1]v.zmqpubrawtx",
+				"with either %[1]v.zmqpubrawblock and "+
+				"%[1]v.zmqpubrawtx, or %[1]v.rpcpolling",
 				daemonName)
 		}
 	}
@@ -1948,7 +2027,8 @@ func parseRPCParams(cConfig *lncfg.Chain, nodeConfig interface{},
 		nConf := nodeConfig.(*lncfg.Bitcoind)
 		rpcUser, rpcPass, zmqBlockHost, zmqTxHost, err :=
 			extractBitcoindRPCParams(netParams.Params.Name,
-				nConf.Dir, confFile, nConf.RPCCookie)
+				nConf.Dir, confFile, nConf.RPCCookie,
+				nConf.RPCPolling)
 		if err != nil {
 			return fmt.Errorf("unable to extract RPC credentials: "+
 				"%v, cannot start w/o RPC connection", err)
@@ -2075,9 +2155,11 @@ func extractBtcdRPCParams(btcdConfigPath string) (string, string, error) {
 // extractBitcoindRPCParams attempts to extract the RPC credentials for an
 // existing bitcoind node instance. The routine looks for a cookie first,
 // optionally following the datadir configuration option in the bitcoin.conf. If
-// it doesn't find one, it looks for rpcuser/rpcpassword.
+// it doesn't find one, it looks for rpcuser/rpcpassword. The ZMQ hosts are
+// only extracted if rpcPolling isn't set, as they aren't used otherwise.
 func extractBitcoindRPCParams(networkName, bitcoindDataDir, bitcoindConfigPath,
-	rpcCookiePath string) (string, string, string, string, error) {
+	rpcCookiePath string, rpcPolling bool) (string, string, string, string,
+	error) {
 
 	// First, we'll open up the bitcoind configuration file found at the
 	// target destination.
@@ -2095,31 +2177,15 @@ func extractBitcoindRPCParams(networkName, bitcoindDataDir, bitcoindConfigPath,
 	}
 
 	// First, we'll look for the ZMQ hosts providing raw block and raw
-	// transaction notifications.
-	zmqBlockHostRE, err := regexp.Compile(
-		`(?m)^\s*zmqpubrawblock\s*=\s*([^\s]+)`,
-	)
-	if err != nil {
-		return "", "", "", "", err
-	}
-	zmqBlockHostSubmatches := zmqBlockHostRE.FindSubmatch(configContents)
-	if len(zmqBlockHostSubmatches) < 2 {
-		return "", "", "", "", fmt.Errorf("unable to find " +
-			"zmqpubrawblock in config")
-	}
-	zmqTxHostRE, err := regexp.Compile(`(?m)^\s*zmqpubrawtx\s*=\s*([^\s]+)`)
-	if err != nil {
-		return "", "", "", "", err
-	}
-	zmqTxHostSubmatches := zmqTxHostRE.FindSubmatch(configContents)
-	if len(zmqTxHostSubmatches) < 2 {
-		return "", "", "", "", errors.New("unable to find zmqpubrawtx " +
-			"in config")
-	}
-	zmqBlockHost := string(zmqBlockHostSubmatches[1])
-	zmqTxHost := string(zmqTxHostSubmatches[1])
-	if err := checkZMQOptions(zmqBlockHost, zmqTxHost); err != nil {
-		return "", "", "", "", err
+	// transaction notifications, unless we poll the RPC interface instead.
+	var zmqBlockHost, zmqTxHost string
+	if !rpcPolling {
+		zmqBlockHost, zmqTxHost, err = extractBitcoindZMQHosts(
+			configContents,
+		)
+		if err != nil {
+			return "", "", "", "", err
+		}
 	}
 
 	// Next, we'll try to find an auth cookie. We need to detect the chain
@@ -2199,6 +2265,39 @@ func extractBitcoindRPCParams(networkName, bitcoindDataDir, bitcoindConfigPath,
 		zmqBlockHost, zmqTxHost, nil
 }
 
+// extractBitcoindZMQHosts extracts the hosts providing raw block and raw
+// transaction notifications from the contents of a bitcoind configuration
+// file.
+func extractBitcoindZMQHosts(configContents []byte) (string, string, error) {
+	zmqBlockHostRE, err := regexp.Compile(
+		`(?m)^\s*zmqpubrawblock\s*=\s*([^\s]+)`,
+	)
+	if err != nil {
+		return "", "", err
+	}
+	zmqBlockHostSubmatches := zmqBlockHostRE.FindSubmatch(configContents)
+	if len(zmqBlockHostSubmatches) < 2 {
+		return "", "", fmt.Errorf("unable to find zmqpubrawblock in " +
+			"config")
+	}
+	zmqTxHostRE, err := regexp.Compile(`(?m)^\s*zmqpubrawtx\s*=\s*([^\s]+)`)
+	if err != nil {
+		return "", "", err
+	}
+	zmqTxHostSubmatches := zmqTxHostRE.FindSubmatch(configContents)
+	if len(zmqTxHostSubmatches) < 2 {
+		return "", "", errors.New("unable to find zmqpubrawtx in " +
+			"config")
+	}
+	zmqBlockHost := string(zmqBlockHostSubmatches[1])
+	zmqTxHost := string(zmqTxHostSubmatches[1])
+	if err := checkZMQOptions(zmqBlockHost, zmqTxHost); err != nil {
+		return "", "", err
+	}
+
+	return zmqBlockHost, zmqTxHost, nil
+}
+
 // checkZMQOptions ensures that the provided addresses to use as the hosts for
 // ZMQ rawblock and rawtx notifications are different.
 func checkZMQOptions(zmqBlockHost, zmqTxHost string) error {
@@ -17,6 +17,7 @@ import (
 
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btclog"
 	"github.com/btcsuite/btcwallet/chain"
@@ -44,7 +45,9 @@ import (
 	"github.com/lightningnetwork/lnd/lnwallet/btcwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chancloser"
 	"github.com/lightningnetwork/lnd/lnwallet/rpcwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/signpolicy"
 	"github.com/lightningnetwork/lnd/macaroons"
+	"github.com/lightningnetwork/lnd/monitoring"
 	"github.com/lightningnetwork/lnd/msgmux"
 	"github.com/lightningnetwork/lnd/routing"
 	"github.com/lightningnetwork/lnd/rpcperms"
@@ -596,6 +599,7 @@ func (d *DefaultWalletImpl) BuildWalletConfig(ctx context.Context,
 		Bitcoin:                     d.cfg.Bitcoin,
 		HeightHintCacheQueryDisable: d.cfg.HeightHintCacheQueryDisable,
 		NeutrinoMode:                d.cfg.NeutrinoMode,
+		ElectrumMode:                d.cfg.ElectrumMode,
 		BitcoindMode:                d.cfg.BitcoindMode,
 		BtcdMode:                    d.cfg.BtcdMode,
 		HeightHintDB:                dbs.HeightHintDB,
@@ -747,13 +751,42 @@ func (d *DefaultWalletImpl) BuildChainControl(
 		walletController.InternalWallet(), walletConfig.CoinType,
 	)
 
+	// All spends of the wallet's funds through the internal signer are
+	// checked against the configured signing policy first.
+	policy, err := d.cfg.SignPolicy.Policy(walletConfig.NetParams)
+	if err != nil {
+		d.logger.Error(err)
+		return nil, nil, err
+	}
+	policyEnforcer, err := signpolicy.NewEnforcer(signpolicy.Config{
+		IsOwnScript: func(pkScript []byte) bool {
+			_, addrs, _, err := txscript.ExtractPkScriptAddrs(
+				pkScript, walletConfig.NetParams,
+			)
+			if err != nil || len(addrs) != 1 {
+				return false
+			}
+
+			return walletController.IsOurAddress(addrs[0])
+		},
+		Clock: clock.NewDefaultClock(),
+	}, policy)
+	if err != nil {
+		err := fmt.Errorf("unable to create signing policy "+
+			"enforcer: %w", err)
+		d.logger.Error(err)
+		return nil, nil, err
+	}
+	policySigner := signpolicy.NewSigner(walletController, policyEnforcer)
+	fundingPolicy := fn.Some[lnwallet.FundingPolicy](policyEnforcer)
+
 	// Create, and start the lnwallet, which handles the core payment
 	// channel logic, and exposes control via proxy state machines.
 	lnWalletConfig := lnwallet.Config{
 		Database:              partialChainControl.Cfg.ChanStateDB,
 		Notifier:              partialChainControl.ChainNotifier,
 		WalletController:      walletController,
-		Signer:                walletController,
+		Signer:                policySigner,
 		FeeEstimator:          partialChainControl.FeeEstimator,
 		SecretKeyRing:         keyRing,
 		ChainIO:               walletController,
@@ -761,6 +794,7 @@ func (d *DefaultWalletImpl) BuildChainControl(
 		CoinSelectionStrategy: walletConfig.CoinSelectionStrategy,
 		AuxLeafStore:          partialChainControl.Cfg.AuxLeafStore,
 		AuxSigner:             partialChainControl.Cfg.AuxSigner,
+		FundingPolicy:         fundingPolicy,
 	}
 
 	// The broadcast is already always active for neutrino nodes, so we
@@ -803,6 +837,7 @@ func (d *DefaultWalletImpl) BuildChainControl(
 		d.logger.Error(err)
 		return nil, nil, err
 	}
+	activeChainControl.SignPolicy = policyEnforcer
 
 	return activeChainControl, cleanUp, nil
 }
@@ -943,6 +978,10 @@ type DatabaseInstances struct {
 	// for native SQL queries for tables that already support it. This may
 	// be nil if the use-native-sql flag was not set.
 	NativeSQLStore *sqldb.BaseDB
+
+	// Snapshotter takes online snapshots of the local bolt databases. This
+	// is nil if a remote database backend is used.
+	Snapshotter *kvdb.Snapshotter
 }
 
 // DefaultDatabaseBuilder is a type that builds the default database backends
@@ -964,6 +1003,45 @@ func NewDefaultDatabaseBuilder(cfg *Config,
 	}
 }
 
+// instrumentDatabases wraps the database backends of all subsystems to record
+// their transaction latencies and registers them to be exported to
+// Prometheus. Backends that are shared by multiple subsystems are wrapped
+// once per subsystem, so their latencies are reported separately.
+func instrumentDatabases(backends *lncfg.DatabaseBackends) error {
+	var instrumented []*kvdb.InstrumentedBackend
+	instrument := func(db kvdb.Backend, subsystem string) kvdb.Backend {
+		// The watchtower databases are nil if the respective
+		// subsystem is disabled.
+		if db == nil {
+			return nil
+		}
+
+		wrapped := kvdb.NewInstrumentedBackend(db, subsystem)
+		instrumented = append(instrumented, wrapped)
+
+		return wrapped
+	}
+
+	backends.GraphDB = instrument(backends.GraphDB, lncfg.NSChannelDB)
+	backends.HeightHintDB = instrument(
+		backends.HeightHintDB, "heighthintdb",
+	)
+	backends.MacaroonDB = instrument(
+		backends.MacaroonDB, lncfg.NSMacaroonDB,
+	)
+	backends.DecayedLogDB = instrument(
+		backends.DecayedLogDB, lncfg.NSDecayedLogDB,
+	)
+	backends.TowerClientDB = instrument(
+		backends.TowerClientDB, lncfg.NSTowerClientDB,
+	)
+	backends.TowerServerDB = instrument(
+		backends.TowerServerDB, lncfg.NSTowerServerDB,
+	)
+
+	return monitoring.RegisterDBMetrics(instrumented)
+}
+
 // BuildDatabase extracts the current databases that we'll use for normal
 // operation in the daemon. A function closure that closes all opened databases
 // is also returned.
@@ -993,6 +1071,16 @@ func (d *DefaultDatabaseBuilder) BuildDatabase(
 			"backends: %v", err)
 	}
 
+	// If requested, record the transaction latencies of the databases
+	// per subsystem and export them together with the bucket sizes.
+	if cfg.Prometheus.DBMetricsEnabled() {
+		err := instrumentDatabases(databaseBackends)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to register "+
+				"database metrics: %w", err)
+		}
+	}
+
 	// With the full remote mode we made sure both the graph and channel
 	// state DB point to the same local or remote DB and the same namespace
 	// within that DB.
@@ -1003,7 +1091,37 @@ func (d *DefaultDatabaseBuilder) BuildDatabase(
 		WalletDB:       databaseBackends.WalletDB,
 		NativeSQLStore: databaseBackends.NativeSQLStore,
 	}
+
+	// Snapshots of the local bolt databases can be taken on demand while
+	// we're running, and if enabled on a schedule as well.
+	var snapshotter *kvdb.Snapshotter
+	if len(databaseBackends.BoltDBs) > 0 {
+		snapshotDir := cfg.DB.Bolt.SnapshotDir
+		if snapshotDir == "" {
+			snapshotDir = filepath.Join(
+				cfg.graphDatabaseDir(), "snapshots",
+			)
+		}
+
+		snapshotter = kvdb.NewSnapshotter(&kvdb.SnapshotConfig{
+			DBs:       databaseBackends.BoltDBs,
+			Dir:       snapshotDir,
+			Interval:  cfg.DB.Bolt.SnapshotInterval,
+			RateLimit: cfg.DB.Bolt.SnapshotRateLimit,
+			NumToKeep: cfg.DB.Bolt.SnapshotsToKeep,
+		})
+		snapshotter.Start()
+
+		dbs.Snapshotter = snapshotter
+	}
+
 	cleanUp := func() {
+		// Make sure no snapshot is running while we close the
+		// databases.
+		if snapshotter != nil {
+			snapshotter.Stop()
+		}
+
 		// We can just close the returned close functions directly. Even
 		// if we decorate the channel DB with an additional struct, its
 		// close function still just points to the kvdb backend.
@@ -1052,6 +1170,21 @@ func (d *DefaultDatabaseBuilder) BuildDatabase(
 		)
 	}
 
+	// If the channel graph should be stored in native SQL, we hand the SQL
+	// store to the channel DB, which migrates the KV graph to it if needed.
+	if cfg.DB.UseNativeSQLGraph {
+		executor := sqldb.NewTransactionExecutor(
+			dbs.NativeSQLStore,
+			func(tx *sql.Tx) channeldb.SQLGraphQueries {
+				return dbs.NativeSQLStore.WithTx(tx)
+			},
+		)
+
+		dbOptions = append(
+			dbOptions, channeldb.OptionSetSQLGraph(executor),
+		)
+	}
+
 	// Otherwise, we'll open two instances, one for the state we only need
 	// locally, and the other for things we want to ensure are replicated.
 	dbs.GraphDB, err = channeldb.CreateWithBackend(
@@ -1086,6 +1219,18 @@ func (d *DefaultDatabaseBuilder) BuildDatabase(
 	// using the same struct (and DB backend) instance.
 	dbs.ChanStateDB = dbs.GraphDB
 
+	// Check the references between the graph and the channel state DB if
+	// requested, removing any orphans if we're asked to prune them.
+	if cfg.DB.CheckIntegrity || cfg.DB.PruneOrphans {
+		err := checkDBIntegrity(dbs, cfg.DB.PruneOrphans, d.logger)
+		if err != nil {
+			cleanUp()
+			d.logger.Error(err)
+
+			return nil, nil, err
+		}
+	}
+
 	// Instantiate a native SQL invoice store if the flag is set.
 	if d.cfg.DB.UseNativeSQL {
 		// KV invoice db resides in the same database as the graph and
@@ -1147,7 +1292,7 @@ func (d *DefaultDatabaseBuilder) BuildDatabase(
 
 	// Wrap the watchtower server DB and make sure we clean up.
 	if cfg.Watchtower.Active {
-		dbs.TowerServerDB, err = wtdb.OpenTowerDB(
+		towerDB, err := wtdb.OpenTowerDB(
 			databaseBackends.TowerServerDB,
 		)
 		if err != nil {
@@ -1158,6 +1303,43 @@ func (d *DefaultDatabaseBuilder) BuildDatabase(
 			d.logger.Error(err)
 			return nil, nil, err
 		}
+		dbs.TowerServerDB = towerDB
+
+		// Instantiate a native SQL tower store if the flag is set.
+		if d.cfg.DB.UseNativeSQL {
+			// Just like for invoices, we don't migrate existing
+			// sessions to the new database schema. So we refuse to
+			// start if there are any in the KV tower DB, as the
+			// tower would otherwise lose the backups of its
+			// clients.
+			hasSessions, err := towerDB.HasSessions()
+			if err != nil {
+				cleanUp()
+				d.logger.Errorf("Unable to query KV tower DB: "+
+					"%v", err)
+
+				return nil, nil, err
+			}
+
+			if hasSessions {
+				cleanUp()
+				err := fmt.Errorf("found sessions in the KV " +
+					"tower DB, migration to native SQL is " +
+					"not yet supported")
+				d.logger.Error(err)
+
+				return nil, nil, err
+			}
+
+			executor := sqldb.NewTransactionExecutor(
+				dbs.NativeSQLStore,
+				func(tx *sql.Tx) wtdb.SQLTowerQueries {
+					return dbs.NativeSQLStore.WithTx(tx)
+				},
+			)
+
+			dbs.TowerServerDB = wtdb.NewSQLTowerStore(executor)
+		}
 	}
 
 	openTime := time.Since(startOpenTime)
@@ -1166,6 +1348,51 @@ func (d *DefaultDatabaseBuilder) BuildDatabase(
 	return dbs, cleanUp, nil
 }
 
+// checkDBIntegrity checks the references between the channel graph and the
+// channel state DB, logging any orphaned references. If prune is true, the
+// orphans are removed.
+func checkDBIntegrity(dbs *DatabaseInstances, prune bool,
+	logger btclog.Logger) error {
+
+	graph := dbs.GraphDB.ChannelGraph()
+	chanState := dbs.ChanStateDB.ChannelStateDB()
+
+	report, err := channeldb.CheckIntegrity(graph, chanState)
+	if err != nil {
+		return fmt.Errorf("unable to check DB integrity: %w", err)
+	}
+
+	if report.IsEmpty() {
+		logger.Infof("DB integrity check found no orphaned references")
+		return nil
+	}
+
+	for _, edge := range report.OrphanedEdges {
+		logger.Warnf("Found orphaned graph edge for channel %v "+
+			"(chan_id=%v)", edge.ChannelPoint, edge.ChannelID)
+	}
+	for _, source := range report.OrphanedFwdPkgs {
+		logger.Warnf("Found orphaned forwarding packages for "+
+			"channel %v", source)
+	}
+
+	if !prune {
+		return nil
+	}
+
+	removed, err := channeldb.RemoveOrphans(graph, chanState, report)
+	if err != nil {
+		return fmt.Errorf("unable to remove orphaned references: %w",
+			err)
+	}
+
+	logger.Infof("Removed %d orphaned graph edges and forwarding "+
+		"packages of %d channels", len(removed.OrphanedEdges),
+		len(removed.OrphanedFwdPkgs))
+
+	return nil
+}
+
 // waitForWalletPassword blocks until a password is provided by the user to
 // this RPC server.
 func waitForWalletPassword(cfg *Config,
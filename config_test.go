@@ -2,6 +2,8 @@ package lnd
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/lightningnetwork/lnd/chainreg"
@@ -115,3 +117,26 @@ func TestSupplyEnvValue(t *testing.T) {
 		})
 	}
 }
+
+// TestExtractBitcoindRPCParamsPolling tests that the ZMQ hosts are only
+// required in bitcoind's configuration file if rpc polling isn't used.
+func TestExtractBitcoindRPCParamsPolling(t *testing.T) {
+	dir := t.TempDir()
+	confPath := filepath.Join(dir, "bitcoin.conf")
+	conf := "rpcuser=user\nrpcpassword=pass\n"
+	require.NoError(t, os.WriteFile(confPath, []byte(conf), 0600))
+
+	_, _, _, _, err := extractBitcoindRPCParams(
+		"regtest", dir, confPath, "", false,
+	)
+	require.ErrorContains(t, err, "zmqpubrawblock")
+
+	user, pass, zmqBlockHost, zmqTxHost, err := extractBitcoindRPCParams(
+		"regtest", dir, confPath, "", true,
+	)
+	require.NoError(t, err)
+	require.Equal(t, "user", user)
+	require.Equal(t, "pass", pass)
+	require.Empty(t, zmqBlockHost)
+	require.Empty(t, zmqTxHost)
+}
@@ -179,6 +179,10 @@ type BreachConfig struct {
 	// AuxSweeper is an optional interface that can be used to modify the
 	// way sweep transaction are generated.
 	AuxSweeper fn.Option[sweep.AuxSweeper]
+
+	// JusticeDelegate is an optional external party that breaches are
+	// handed to in addition to being handled locally.
+	JusticeDelegate fn.Option[JusticeDelegate]
 }
 
 // BreachArbitrator is a special subsystem which is responsible for watching and
@@ -768,6 +772,10 @@ justiceTxBroadcast:
 		brarLog.Errorf("Unable to broadcast justice tx: %v", err)
 	}
 
+	// Regardless of the local publication, we also hand the breach to our
+	// justice delegate, if any, in case our justice tx fails to confirm.
+	b.delegateJustice(breachInfo, justiceTxs)
+
 	// Regardless of publication succeeded or not, we now wait for any of
 	// the inputs to be spent. If any input got spent by the remote, we
 	// must recreate our justice transaction.
@@ -1591,6 +1591,12 @@ func testBreachSpends(t *testing.T, test breachTest) {
 		return err
 	}
 
+	// Hand all breaches to a justice delegate as well.
+	delegated := make(chan *BreachJustice, 10)
+	brar.cfg.JusticeDelegate = fn.Some[JusticeDelegate](
+		&mockJusticeDelegate{delegated: delegated},
+	)
+
 	// Notify the breach arbiter about the breach.
 	retribution, err := lnwallet.NewBreachRetribution(
 		alice.State(), height, 1, forceCloseTx,
@@ -1677,6 +1683,18 @@ func testBreachSpends(t *testing.T, test breachTest) {
 		}
 	}
 
+	// The same justice tx should have been handed to the delegate.
+	select {
+	case justice := <-delegated:
+		require.Equal(t, chanPoint, justice.ChanPoint)
+		require.Equal(t, forceTxID, justice.BreachTxid)
+		require.Equal(t, tx.TxHash(), justice.SpendAll.TxHash())
+		require.NotEmpty(t, justice.Fallbacks)
+
+	case <-time.After(5 * time.Second):
+		t.Fatalf("justice was not delegated")
+	}
+
 	localOutpoint := retribution.LocalOutpoint
 	remoteOutpoint := retribution.RemoteOutpoint
 	htlcOutpoint := retribution.HtlcRetributions[0].OutPoint
@@ -2124,6 +2142,21 @@ func assertNotPendingClosed(t *testing.T, c *lnwallet.LightningChannel) {
 
 // createTestArbiter instantiates a breach arbiter with a failing retribution
 // store, so that controlled failures can be tested.
+// mockJusticeDelegate is a JusticeDelegate that forwards all delegated
+// breaches to a channel.
+type mockJusticeDelegate struct {
+	delegated chan *BreachJustice
+}
+
+func (m *mockJusticeDelegate) DelegateJustice(justice *BreachJustice) error {
+	select {
+	case m.delegated <- justice:
+	default:
+	}
+
+	return nil
+}
+
 func createTestArbiter(t *testing.T, contractBreaches chan *ContractBreachEvent,
 	db *channeldb.DB) (*BreachArbitrator, error) {
 
@@ -92,6 +92,13 @@ type ChainArbitratorConfig struct {
 	// settling on-chain to the incoming link.
 	DeliverResolutionMsg func(...ResolutionMsg) error
 
+	// FailStrandedHTLCs is a function that fails back the incoming side of
+	// any HTLC that is still waiting for a resolution on the given
+	// outgoing channel. It is called once the channel has been fully
+	// resolved, at which point such HTLCs can no longer be resolved
+	// on-chain.
+	FailStrandedHTLCs func(lnwire.ShortChannelID) error
+
 	// MarkLinkInactive is a function closure that the ChainArbitrator will
 	// use to mark that active HTLC's shouldn't be attempted to be routed
 	// over a particular channel. This function will be called in that a
@@ -173,6 +180,12 @@ type ChainArbitratorConfig struct {
 	// resolved (which includes sweeping any time locked funds).
 	NotifyFullyResolvedChannel func(point wire.OutPoint)
 
+	// NotifyStaleRemoteClose is a function closure that the
+	// ChainArbitrator will use to notify the ChannelNotifier that the
+	// remote party closed a channel with a revoked state after proving
+	// that it lost channel state, as opposed to a deliberate breach.
+	NotifyStaleRemoteClose func(point wire.OutPoint)
+
 	// OnionProcessor is used to decode onion payloads for on-chain
 	// resolution.
 	OnionProcessor OnionProcessor
@@ -207,6 +220,12 @@ type ChainArbitratorConfig struct {
 	// Budget is the configured budget for the arbitrator.
 	Budget BudgetConfig
 
+	// CommitRebroadcastInterval is the number of blocks after which our
+	// unconfirmed force close transaction is rebroadcast and the budget
+	// used to CPFP it through its anchor is escalated. A zero value
+	// disables periodic rebroadcasts.
+	CommitRebroadcastInterval uint32
+
 	// QueryIncomingCircuit is used to find the outgoing HTLC's
 	// corresponding incoming HTLC circuit. It queries the circuit map for
 	// a given outgoing circuit key and returns the incoming circuit key.
@@ -440,6 +459,7 @@ func newActiveChannelArbitrator(channel *channeldb.OpenChannel,
 				channel.ShortChanID(), htlc,
 			)
 		},
+		BroadcastedCommitment: channel.BroadcastedCommitment,
 	}
 
 	// The final component needed is an arbitrator log that the arbitrator
@@ -456,11 +476,7 @@ func newActiveChannelArbitrator(channel *channeldb.OpenChannel,
 	}
 
 	arbCfg.MarkChannelResolved = func() error {
-		if c.cfg.NotifyFullyResolvedChannel != nil {
-			c.cfg.NotifyFullyResolvedChannel(chanPoint)
-		}
-
-		return c.ResolveContract(chanPoint)
+		return c.markChannelResolved(chanPoint, channel.ShortChanID())
 	}
 
 	// Finally, we'll need to construct a series of htlc Sets based on all
@@ -494,6 +510,30 @@ func (c *ChainArbitrator) getArbChannel(
 	}
 }
 
+// markChannelResolved is called by a channel arbitrator once all contracts of
+// the channel have been resolved on-chain. It fails back any HTLC that is
+// stranded on the channel, notifies subscribers and marks the contract as
+// fully resolved.
+func (c *ChainArbitrator) markChannelResolved(chanPoint wire.OutPoint,
+	scid lnwire.ShortChannelID) error {
+
+	// Before we forget about the channel, we'll fail back any HTLC that
+	// never made it on-chain, so the incoming HTLCs don't linger until
+	// they time out.
+	if c.cfg.FailStrandedHTLCs != nil {
+		if err := c.cfg.FailStrandedHTLCs(scid); err != nil {
+			return fmt.Errorf("unable to fail stranded htlcs: %w",
+				err)
+		}
+	}
+
+	if c.cfg.NotifyFullyResolvedChannel != nil {
+		c.cfg.NotifyFullyResolvedChannel(chanPoint)
+	}
+
+	return c.ResolveContract(chanPoint)
+}
+
 // ResolveContract marks a contract as fully resolved within the database.
 // This is only to be done once all contracts which were live on the channel
 // before hitting the chain have been resolved.
@@ -556,8 +596,9 @@ func (c *ChainArbitrator) Start() error {
 		return nil
 	}
 
-	log.Infof("ChainArbitrator starting with config: budget=[%v]",
-		&c.cfg.Budget)
+	log.Infof("ChainArbitrator starting with config: budget=[%v], "+
+		"commit_rebroadcast_interval=%v", &c.cfg.Budget,
+		c.cfg.CommitRebroadcastInterval)
 
 	// First, we'll fetch all the channels that are still open, in order to
 	// collect them within our set of active contracts.
@@ -674,12 +715,9 @@ func (c *ChainArbitrator) Start() error {
 		if err != nil {
 			return err
 		}
+		scid := closeChanInfo.ShortChanID
 		arbCfg.MarkChannelResolved = func() error {
-			if c.cfg.NotifyFullyResolvedChannel != nil {
-				c.cfg.NotifyFullyResolvedChannel(chanPoint)
-			}
-
-			return c.ResolveContract(chanPoint)
+			return c.markChannelResolved(chanPoint, scid)
 		}
 
 		// We create an empty map of HTLC's here since it's possible
@@ -85,6 +85,12 @@ type BreachCloseInfo struct {
 	// CloseSummary gives the recipient of the BreachCloseInfo information
 	// to mark the channel closed in the database.
 	CloseSummary channeldb.ChannelCloseSummary
+
+	// RemoteDataLoss is true if the remote party proved that it lost
+	// channel state before broadcasting the revoked commitment. In that
+	// case the breach is most likely a stale force close caused by the
+	// data loss rather than a deliberate attempt to steal funds.
+	RemoteDataLoss bool
 }
 
 // CommitSet is a collection of the set of known valid commitments at a given
@@ -1278,9 +1284,26 @@ func (c *chainWatcher) dispatchContractBreach(spendEvent *chainntnfs.SpendDetail
 	retribution *lnwallet.BreachRetribution,
 	anchorRes *lnwallet.AnchorResolution) error {
 
-	log.Warnf("Remote peer has breached the channel contract for "+
-		"ChannelPoint(%v). Revoked state #%v was broadcast!!!",
-		c.cfg.chanState.FundingOutpoint, broadcastStateNum)
+	// If the remote told us during channel sync that it lost state, the
+	// revoked commitment is most likely the last state it still knew
+	// about. We still sweep all outputs through the BreachArbitrator, as
+	// the claimed data loss can't be verified and a stale state may move
+	// funds from us to the remote, but we label the close accordingly.
+	remoteDataLoss, err := c.cfg.chanState.HasRemoteDataLoss()
+	if err != nil {
+		log.Errorf("ChannelPoint(%v): unable to check for remote "+
+			"data loss: %v", c.cfg.chanState.FundingOutpoint, err)
+	}
+
+	if remoteDataLoss {
+		log.Warnf("Remote peer force closed ChannelPoint(%v) with "+
+			"stale state #%v after reporting data loss",
+			c.cfg.chanState.FundingOutpoint, broadcastStateNum)
+	} else {
+		log.Warnf("Remote peer has breached the channel contract for "+
+			"ChannelPoint(%v). Revoked state #%v was broadcast!!!",
+			c.cfg.chanState.FundingOutpoint, broadcastStateNum)
+	}
 
 	if err := c.cfg.chanState.MarkBorked(); err != nil {
 		return fmt.Errorf("unable to mark channel as borked: %w", err)
@@ -1345,6 +1368,7 @@ func (c *chainWatcher) dispatchContractBreach(spendEvent *chainntnfs.SpendDetail
 		AnchorResolution: anchorRes,
 		CommitSet:        chainSet.commitSet,
 		CloseSummary:     closeSummary,
+		RemoteDataLoss:   remoteDataLoss,
 	}
 
 	// With the event processed and channel closed, we'll now notify all
@@ -178,6 +178,12 @@ type ChannelArbitratorConfig struct {
 	// spend his/her outgoing HTLC via the timeout path.
 	FindOutgoingHTLCDeadline func(htlc channeldb.HTLC) fn.Option[int32]
 
+	// BroadcastedCommitment returns the force close transaction that was
+	// stored before it was broadcast. It's used to rebroadcast the
+	// transaction while it's unconfirmed and may be nil, in which case no
+	// rebroadcasts are attempted.
+	BroadcastedCommitment func() (*wire.MsgTx, error)
+
 	ChainArbitratorConfig
 }
 
@@ -382,6 +388,11 @@ type ChannelArbitrator struct {
 	// upon start up to decide which actions to take.
 	state ArbitratorState
 
+	// rebroadcaster keeps track of our force close transaction while it's
+	// unconfirmed, and decides when it should be rebroadcast and how much
+	// the budget to CPFP it should be escalated.
+	rebroadcaster *commitRebroadcaster
+
 	wg   sync.WaitGroup
 	quit chan struct{}
 }
@@ -413,7 +424,11 @@ func NewChannelArbitrator(cfg ChannelArbitratorConfig,
 		activeHTLCs:      htlcSets,
 		unmergedSet:      unmerged,
 		cfg:              cfg,
-		quit:             make(chan struct{}),
+		rebroadcaster: newCommitRebroadcaster(
+			cfg.CommitRebroadcastInterval,
+			cfg.Budget.AnchorCPFPEscalation,
+		),
+		quit: make(chan struct{}),
 	}
 }
 
@@ -1104,7 +1119,11 @@ func (c *ChannelArbitrator) stateStep(
 		label := labels.MakeLabel(
 			labels.LabelTypeChannelClose, &c.cfg.ShortChanID,
 		)
-		if err := c.cfg.PublishTx(closeTx, label); err != nil {
+		err = c.cfg.PublishTx(closeTx, label)
+		c.rebroadcaster.recordBroadcast(
+			closeTx.TxHash(), triggerHeight, err,
+		)
+		if err != nil {
 			log.Errorf("ChannelArbitrator(%v): unable to broadcast "+
 				"close tx: %v", c.cfg.ChanPoint, err)
 
@@ -1143,7 +1162,11 @@ func (c *ChannelArbitrator) stateStep(
 			// Note that the sweeper is idempotent. If we ever
 			// happen to end up at this point in the code again, no
 			// harm is done by re-offering the anchors to the
-			// sweeper.
+			// sweeper. If our commitment stayed unconfirmed for too
+			// long, we'll rebroadcast it and re-offer the anchors
+			// with an escalated budget.
+			c.maybeRebroadcastCommitment(triggerHeight)
+
 			anchors, err := c.cfg.Channel.NewAnchorResolutions()
 			if err != nil {
 				return StateError, closeTx, err
@@ -1295,6 +1318,55 @@ func (c *ChannelArbitrator) stateStep(
 	return nextState, closeTx, nil
 }
 
+// maybeRebroadcastCommitment rebroadcasts our force close transaction if it
+// stayed unconfirmed for the configured rebroadcast interval. The mempool's
+// rejection reason, if any, is recorded so it can be inspected later on.
+func (c *ChannelArbitrator) maybeRebroadcastCommitment(height uint32) {
+	if c.cfg.BroadcastedCommitment == nil ||
+		c.cfg.CommitRebroadcastInterval == 0 {
+
+		return
+	}
+
+	closeTx, err := c.cfg.BroadcastedCommitment()
+	if err != nil {
+		log.Debugf("ChannelArbitrator(%v): no force close tx to "+
+			"rebroadcast: %v", c.cfg.ChanPoint, err)
+
+		return
+	}
+
+	// If we were restarted since the commitment was broadcast, we'll
+	// start tracking it from the current height. It's republished by the
+	// chain arbitrator on startup.
+	txid := closeTx.TxHash()
+	c.rebroadcaster.track(txid, height)
+
+	if !c.rebroadcaster.rebroadcastDue(height) {
+		return
+	}
+
+	log.Infof("ChannelArbitrator(%v): force close tx %v still "+
+		"unconfirmed at height %v, rebroadcasting", c.cfg.ChanPoint,
+		txid, height)
+
+	label := labels.MakeLabel(
+		labels.LabelTypeChannelClose, &c.cfg.ShortChanID,
+	)
+	err = c.cfg.PublishTx(closeTx, label)
+	c.rebroadcaster.recordBroadcast(txid, height, err)
+	if err != nil {
+		log.Warnf("ChannelArbitrator(%v): force close tx %v "+
+			"rejected: %v", c.cfg.ChanPoint, txid, err)
+	}
+}
+
+// CommitBroadcastStatus returns the broadcast status of our force close
+// transaction, or nil if we haven't broadcast one.
+func (c *ChannelArbitrator) CommitBroadcastStatus() *CommitBroadcastStatus {
+	return c.rebroadcaster.broadcastStatus()
+}
+
 // sweepAnchors offers all given anchor resolutions to the sweeper. It requests
 // sweeping at the minimum fee rate. This fee rate can be upped manually by the
 // user via the BumpFee rpc.
@@ -1367,9 +1439,14 @@ func (c *ChannelArbitrator) sweepAnchors(anchors *lnwallet.AnchorResolutions,
 		// The anchor output in itself has a small output value of 330
 		// sats so we also include it in the budget to pay for the
 		// cpfp transaction.
+		//
+		// The budget ratio is escalated the longer our commitment
+		// stays unconfirmed.
+		ratio := c.rebroadcaster.budgetRatio(
+			c.cfg.Budget.AnchorCPFPRatio, heightHint,
+		)
 		budget := calculateBudget(
-			value, c.cfg.Budget.AnchorCPFPRatio,
-			c.cfg.Budget.AnchorCPFP,
+			value, ratio, c.cfg.Budget.AnchorCPFP,
 		) + AnchorOutputValue
 
 		log.Infof("ChannelArbitrator(%v): offering anchor from %s "+
@@ -3003,8 +3080,16 @@ func (c *ChannelArbitrator) channelAttendant(bestHeight int32) {
 		// anything in particular, so just advance our state and
 		// gracefully exit.
 		case breachInfo := <-c.cfg.ChainEvents.ContractBreach:
-			log.Infof("ChannelArbitrator(%v): remote party has "+
-				"breached channel!", c.cfg.ChanPoint)
+			if breachInfo.RemoteDataLoss {
+				log.Infof("ChannelArbitrator(%v): remote "+
+					"party closed channel with stale "+
+					"state after data loss",
+					c.cfg.ChanPoint)
+			} else {
+				log.Infof("ChannelArbitrator(%v): remote "+
+					"party has breached channel!",
+					c.cfg.ChanPoint)
+			}
 
 			// In the breach case, we'll only have anchor and
 			// breach resolutions.
@@ -3050,6 +3135,14 @@ func (c *ChannelArbitrator) channelAttendant(bestHeight int32) {
 			log.Infof("Breached channel=%v marked pending-closed",
 				breachInfo.BreachResolution.FundingOutPoint)
 
+			// Let operators know that this breach was most likely
+			// caused by the remote's data loss, so they can follow
+			// up with the peer instead of treating it as an
+			// attack.
+			if breachInfo.RemoteDataLoss {
+				c.cfg.NotifyStaleRemoteClose(c.cfg.ChanPoint)
+			}
+
 			// We'll advance our state machine until it reaches a
 			// terminal state.
 			_, _, err = c.advanceState(
@@ -0,0 +1,179 @@
+package contractcourt
+
+import (
+	"math"
+	"sync"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+const (
+	// DefaultCommitRebroadcastInterval is the default number of blocks
+	// after which an unconfirmed force close transaction is rebroadcast.
+	DefaultCommitRebroadcastInterval = 6
+
+	// DefaultAnchorCPFPEscalation is the default factor by which the
+	// anchor CPFP budget ratio is multiplied each time an unconfirmed
+	// force close transaction is rebroadcast.
+	DefaultAnchorCPFPEscalation = 1.25
+
+	// MinAnchorCPFPEscalation is the smallest escalation factor that we
+	// allow. A factor of one keeps the budget constant.
+	MinAnchorCPFPEscalation = 1.0
+)
+
+// CommitBroadcastStatus describes the broadcast history of a force close
+// transaction that hasn't confirmed yet.
+type CommitBroadcastStatus struct {
+	// CloseTxid is the txid of the force close transaction.
+	CloseTxid chainhash.Hash
+
+	// FirstBroadcastHeight is the height at which we started tracking
+	// the broadcast of the force close transaction.
+	FirstBroadcastHeight uint32
+
+	// LastBroadcastHeight is the height at which the force close
+	// transaction was last (re)broadcast.
+	LastBroadcastHeight uint32
+
+	// Attempts is the number of times the force close transaction has
+	// been broadcast.
+	Attempts uint32
+
+	// BudgetEscalations is the number of times the anchor CPFP budget has
+	// been escalated.
+	BudgetEscalations uint32
+
+	// LastRejection is the reason the mempool rejected the last broadcast
+	// attempt, or nil if it was accepted.
+	LastRejection error
+}
+
+// commitRebroadcaster keeps track of an unconfirmed force close transaction.
+// It decides when the transaction should be rebroadcast and how much the
+// budget used to CPFP it through its anchor should be escalated.
+type commitRebroadcaster struct {
+	// interval is the number of blocks between two rebroadcasts. A zero
+	// value disables rebroadcasting and budget escalation.
+	interval uint32
+
+	// escalation is the factor by which the anchor CPFP budget ratio is
+	// multiplied after each interval.
+	escalation float64
+
+	// status is nil until the first broadcast is tracked.
+	status *CommitBroadcastStatus
+	mtx    sync.Mutex
+}
+
+// newCommitRebroadcaster creates a new rebroadcaster with the given interval
+// and escalation factor. An unset escalation factor falls back to the
+// default.
+func newCommitRebroadcaster(interval uint32,
+	escalation float64) *commitRebroadcaster {
+
+	if escalation == 0 {
+		escalation = DefaultAnchorCPFPEscalation
+	}
+
+	return &commitRebroadcaster{
+		interval:   interval,
+		escalation: escalation,
+	}
+}
+
+// track starts tracking the given force close transaction at the given height
+// if it isn't tracked yet. This is used when the arbitrator is restarted with
+// a commitment that was broadcast before.
+func (r *commitRebroadcaster) track(txid chainhash.Hash, height uint32) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if r.status != nil && r.status.CloseTxid == txid {
+		return
+	}
+
+	r.status = &CommitBroadcastStatus{
+		CloseTxid:            txid,
+		FirstBroadcastHeight: height,
+		LastBroadcastHeight:  height,
+	}
+}
+
+// recordBroadcast records a broadcast attempt of the given transaction at the
+// given height along with the mempool's rejection reason, if any.
+func (r *commitRebroadcaster) recordBroadcast(txid chainhash.Hash,
+	height uint32, rejection error) {
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if r.status == nil || r.status.CloseTxid != txid {
+		r.status = &CommitBroadcastStatus{
+			CloseTxid:            txid,
+			FirstBroadcastHeight: height,
+		}
+	}
+
+	r.status.LastBroadcastHeight = height
+	r.status.Attempts++
+	r.status.LastRejection = rejection
+}
+
+// rebroadcastDue returns true if the tracked transaction should be broadcast
+// again at the given height.
+func (r *commitRebroadcaster) rebroadcastDue(height uint32) bool {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if r.interval == 0 || r.status == nil {
+		return false
+	}
+
+	return height >= r.status.LastBroadcastHeight+r.interval
+}
+
+// budgetRatio returns the anchor CPFP budget ratio to use at the given height.
+// The base ratio is escalated once for every full interval the tracked
+// transaction stayed unconfirmed, but never beyond the full value under
+// protection.
+func (r *commitRebroadcaster) budgetRatio(base float64,
+	height uint32) float64 {
+
+	if base == 0 {
+		base = DefaultBudgetRatio
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if r.interval == 0 || r.status == nil ||
+		height <= r.status.FirstBroadcastHeight {
+
+		return base
+	}
+
+	steps := (height - r.status.FirstBroadcastHeight) / r.interval
+	if steps > r.status.BudgetEscalations {
+		r.status.BudgetEscalations = steps
+	}
+
+	ratio := base * math.Pow(r.escalation, float64(steps))
+
+	return math.Min(ratio, 1)
+}
+
+// broadcastStatus returns a copy of the current broadcast status, or nil if
+// no broadcast is tracked.
+func (r *commitRebroadcaster) broadcastStatus() *CommitBroadcastStatus {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if r.status == nil {
+		return nil
+	}
+
+	status := *r.status
+
+	return &status
+}
@@ -0,0 +1,70 @@
+package contractcourt
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCommitRebroadcaster checks that the rebroadcaster schedules rebroadcasts
+// at the configured interval, escalates the budget ratio and records the
+// mempool's rejection reasons.
+func TestCommitRebroadcaster(t *testing.T) {
+	t.Parallel()
+
+	r := newCommitRebroadcaster(6, 1.5)
+	txid := chainhash.Hash{1}
+
+	// Nothing is due and the base ratio is used as long as no broadcast
+	// is tracked.
+	require.Nil(t, r.broadcastStatus())
+	require.False(t, r.rebroadcastDue(1000))
+	require.Equal(t, 0.4, r.budgetRatio(0.4, 1000))
+
+	// A rejected broadcast should be recorded.
+	r.recordBroadcast(txid, 100, lnwallet.ErrMempoolFee)
+	status := r.broadcastStatus()
+	require.Equal(t, txid, status.CloseTxid)
+	require.EqualValues(t, 100, status.FirstBroadcastHeight)
+	require.EqualValues(t, 100, status.LastBroadcastHeight)
+	require.EqualValues(t, 1, status.Attempts)
+	require.ErrorIs(t, status.LastRejection, lnwallet.ErrMempoolFee)
+
+	// Tracking the same transaction again shouldn't reset its status.
+	r.track(txid, 103)
+	require.Equal(t, status, r.broadcastStatus())
+
+	// A rebroadcast is only due once the interval elapsed, after which
+	// the ratio is escalated.
+	require.False(t, r.rebroadcastDue(105))
+	require.Equal(t, 0.4, r.budgetRatio(0.4, 105))
+
+	require.True(t, r.rebroadcastDue(106))
+	require.InDelta(t, 0.6, r.budgetRatio(0.4, 106), 1e-9)
+
+	r.recordBroadcast(txid, 106, nil)
+	status = r.broadcastStatus()
+	require.EqualValues(t, 2, status.Attempts)
+	require.EqualValues(t, 1, status.BudgetEscalations)
+	require.NoError(t, status.LastRejection)
+	require.False(t, r.rebroadcastDue(111))
+
+	// The escalated ratio is capped at the full value.
+	require.Equal(t, 1.0, r.budgetRatio(0.4, 130))
+	require.EqualValues(t, 5, r.broadcastStatus().BudgetEscalations)
+
+	// A new commitment should restart the tracking.
+	r.track(chainhash.Hash{2}, 200)
+	status = r.broadcastStatus()
+	require.EqualValues(t, 200, status.FirstBroadcastHeight)
+	require.Zero(t, status.Attempts)
+	require.Equal(t, 0.4, r.budgetRatio(0.4, 200))
+
+	// A zero interval disables rebroadcasts and escalation.
+	r = newCommitRebroadcaster(0, 1.5)
+	r.recordBroadcast(txid, 100, nil)
+	require.False(t, r.rebroadcastDue(1000))
+	require.Equal(t, 0.4, r.budgetRatio(0.4, 1000))
+}
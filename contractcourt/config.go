@@ -33,8 +33,9 @@ type BudgetConfig struct {
 	ToLocal      btcutil.Amount `long:"tolocal" description:"The amount in satoshis to allocate as the budget to pay fees when sweeping the to_local output. If set, the budget calculated using the ratio (if set) will be capped at this value."`
 	ToLocalRatio float64        `long:"tolocalratio" description:"The ratio of the value in to_local output to allocate as the budget to pay fees when sweeping it."`
 
-	AnchorCPFP      btcutil.Amount `long:"anchorcpfp" description:"The amount in satoshis to allocate as the budget to pay fees when CPFPing a force close tx using the anchor output. If set, the budget calculated using the ratio (if set) will be capped at this value."`
-	AnchorCPFPRatio float64        `long:"anchorcpfpratio" description:"The ratio of a special value to allocate as the budget to pay fees when CPFPing a force close tx using the anchor output. The special value is the sum of all time-sensitive HTLCs on this commitment subtracted by their budgets."`
+	AnchorCPFP           btcutil.Amount `long:"anchorcpfp" description:"The amount in satoshis to allocate as the budget to pay fees when CPFPing a force close tx using the anchor output. If set, the budget calculated using the ratio (if set) will be capped at this value."`
+	AnchorCPFPRatio      float64        `long:"anchorcpfpratio" description:"The ratio of a special value to allocate as the budget to pay fees when CPFPing a force close tx using the anchor output. The special value is the sum of all time-sensitive HTLCs on this commitment subtracted by their budgets."`
+	AnchorCPFPEscalation float64        `long:"anchorcpfpescalation" description:"The factor by which the anchor CPFP ratio is multiplied each time an unconfirmed force close tx is rebroadcast, so the budget grows the longer the force close tx stays unconfirmed. The escalated ratio is capped at 1. A value of 1 disables the escalation."`
 
 	DeadlineHTLC      btcutil.Amount `long:"deadlinehtlc" description:"The amount in satoshis to allocate as the budget to pay fees when sweeping a time-sensitive (first-level) HTLC. If set, the budget calculated using the ratio (if set) will be capped at this value."`
 	DeadlineHTLCRatio float64        `long:"deadlinehtlcratio" description:"The ratio of the value in a time-sensitive (first-level) HTLC to allocate as the budget to pay fees when sweeping it."`
@@ -69,6 +70,13 @@ func (b *BudgetConfig) Validate() error {
 			MinBudgetRatio)
 	}
 
+	if b.AnchorCPFPEscalation != 0 &&
+		b.AnchorCPFPEscalation < MinAnchorCPFPEscalation {
+
+		return fmt.Errorf("anchorcpfpescalation must be at least %v",
+			MinAnchorCPFPEscalation)
+	}
+
 	if b.DeadlineHTLC != 0 && b.DeadlineHTLC < MinBudgetValue {
 		return fmt.Errorf("deadlinehtlc must be at least %v",
 			MinBudgetValue)
@@ -95,20 +103,21 @@ func (b *BudgetConfig) Validate() error {
 // String returns a human-readable description of the budget configuration.
 func (b *BudgetConfig) String() string {
 	return fmt.Sprintf("tolocal=%v tolocalratio=%v anchorcpfp=%v "+
-		"anchorcpfpratio=%v deadlinehtlc=%v deadlinehtlcratio=%v "+
-		"nodeadlinehtlc=%v nodeadlinehtlcratio=%v",
+		"anchorcpfpratio=%v anchorcpfpescalation=%v deadlinehtlc=%v "+
+		"deadlinehtlcratio=%v nodeadlinehtlc=%v nodeadlinehtlcratio=%v",
 		b.ToLocal, b.ToLocalRatio, b.AnchorCPFP, b.AnchorCPFPRatio,
-		b.DeadlineHTLC, b.DeadlineHTLCRatio, b.NoDeadlineHTLC,
-		b.NoDeadlineHTLCRatio)
+		b.AnchorCPFPEscalation, b.DeadlineHTLC, b.DeadlineHTLCRatio,
+		b.NoDeadlineHTLC, b.NoDeadlineHTLCRatio)
 }
 
 // DefaultSweeperConfig returns the default configuration for the sweeper.
 func DefaultBudgetConfig() *BudgetConfig {
 	return &BudgetConfig{
-		ToLocalRatio:        DefaultBudgetRatio,
-		AnchorCPFPRatio:     DefaultBudgetRatio,
-		DeadlineHTLCRatio:   DefaultBudgetRatio,
-		NoDeadlineHTLCRatio: DefaultBudgetRatio,
+		ToLocalRatio:         DefaultBudgetRatio,
+		AnchorCPFPRatio:      DefaultBudgetRatio,
+		AnchorCPFPEscalation: DefaultAnchorCPFPEscalation,
+		DeadlineHTLCRatio:    DefaultBudgetRatio,
+		NoDeadlineHTLCRatio:  DefaultBudgetRatio,
 	}
 }
 
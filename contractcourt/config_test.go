@@ -46,6 +46,13 @@ func TestBudgetConfigValidate(t *testing.T) {
 			cfg:            &BudgetConfig{AnchorCPFPRatio: -1},
 			expectedErrStr: "anchorcpfpratio",
 		},
+		{
+			name: "invalid anchorcpfpescalation",
+			cfg: &BudgetConfig{
+				AnchorCPFPEscalation: 0.5,
+			},
+			expectedErrStr: "anchorcpfpescalation",
+		},
 		{
 			name:           "invalid deadlinehtlc",
 			cfg:            &BudgetConfig{DeadlineHTLC: -1},
@@ -5,6 +5,8 @@ import (
 	"io"
 
 	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/fn"
 	"github.com/lightningnetwork/lnd/lnwallet"
@@ -107,6 +109,33 @@ func (h *htlcOutgoingContestResolver) Resolve(
 	default:
 	}
 
+	// If our backend is able to watch the mempool, we'll also look for the
+	// remote party's claim there. The preimage is revealed as soon as the
+	// claim is broadcast, so this allows us to settle the incoming HTLC
+	// upstream without waiting for the claim to confirm. This is crucial
+	// if the incoming HTLC is itself close to its expiry.
+	var mempoolSpend <-chan *chainntnfs.SpendDetail
+	if h.Mempool != nil {
+		mempoolSub, err := h.Mempool.SubscribeMempoolSpent(
+			*outPointToWatch,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("register mempool spend: %w",
+				err)
+		}
+		defer h.Mempool.CancelMempoolSpendEvent(mempoolSub)
+
+		mempoolSpend = mempoolSub.Spend
+
+		// The claim may have entered the mempool before we
+		// subscribed, e.g. if we were restarted in the meantime, so
+		// we'll check for it once upfront.
+		spend := h.lookupMempoolSpend(*outPointToWatch)
+		if spend != nil && h.isMempoolPreimageSpend(spend) {
+			return h.claimCleanUp(spend)
+		}
+	}
+
 	// If we reach this point, then we can't fully act yet, so we'll await
 	// either of our signals triggering: the HTLC expires, or we learn of
 	// the preimage.
@@ -159,12 +188,80 @@ func (h *htlcOutgoingContestResolver) Resolve(
 			// claimed.
 			return h.claimCleanUp(commitSpend)
 
+		// The output has been spent by a transaction in the mempool.
+		// If it reveals the preimage, we can clean up the contract
+		// right away. Otherwise, we keep waiting for a confirmed
+		// spend or the expiry of the HTLC.
+		case spend, ok := <-mempoolSpend:
+			if !ok {
+				return nil, errResolverShuttingDown
+			}
+
+			if !h.isMempoolPreimageSpend(spend) {
+				continue
+			}
+
+			return h.claimCleanUp(spend)
+
 		case <-h.quit:
 			return nil, fmt.Errorf("resolver canceled")
 		}
 	}
 }
 
+// lookupMempoolSpend returns the details of the mempool transaction spending
+// the given outpoint, or nil if there is none.
+func (h *htlcOutgoingContestResolver) lookupMempoolSpend(
+	op wire.OutPoint) *chainntnfs.SpendDetail {
+
+	var spend *chainntnfs.SpendDetail
+	h.Mempool.LookupInputMempoolSpend(op).WhenSome(func(tx wire.MsgTx) {
+		for i, txIn := range tx.TxIn {
+			if txIn.PreviousOutPoint != op {
+				continue
+			}
+
+			txHash := tx.TxHash()
+			spend = &chainntnfs.SpendDetail{
+				SpentOutPoint:     &op,
+				SpenderTxHash:     &txHash,
+				SpendingTx:        &tx,
+				SpenderInputIndex: uint32(i),
+			}
+
+			return
+		}
+	})
+
+	return spend
+}
+
+// isMempoolPreimageSpend returns true if the given unconfirmed spend of the
+// HTLC output reveals the preimage.
+func (h *htlcOutgoingContestResolver) isMempoolPreimageSpend(
+	spend *chainntnfs.SpendDetail) bool {
+
+	op := h.HtlcPoint()
+
+	log.Debugf("%T(%v): found mempool spend of HTLC output %v in tx=%v",
+		h, h.htlcResolution.ClaimOutpoint, op, spend.SpenderTxHash)
+
+	if !spend.HasSpenderWitness() {
+		return false
+	}
+
+	hasPreimage := isPreimageSpend(
+		h.isTaproot(), spend, h.htlcResolution.SignedTimeoutTx != nil,
+	)
+	if !hasPreimage {
+		log.Debugf("%T(%v): mempool spend of HTLC output %v doesn't "+
+			"reveal preimage", h, h.htlcResolution.ClaimOutpoint,
+			op)
+	}
+
+	return hasPreimage
+}
+
 // report returns a report on the resolution state of the contract.
 func (h *htlcOutgoingContestResolver) report() *ContractReport {
 	// No locking needed as these values are read-only.
@@ -8,6 +8,7 @@ import (
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/fn"
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lnmock"
@@ -15,6 +16,7 @@ import (
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -110,6 +112,71 @@ func TestHtlcOutgoingResolverRemoteClaim(t *testing.T) {
 	ctx.waitForResult(false)
 }
 
+// TestHtlcOutgoingResolverMempoolClaim tests that the preimage of an offered
+// htlc is extracted from the remote party's claim once it shows up in the
+// mempool, without waiting for it to confirm.
+func TestHtlcOutgoingResolverMempoolClaim(t *testing.T) {
+	t.Parallel()
+	defer timeout()()
+
+	ctx := newOutgoingResolverTestContext(t)
+
+	// Equip the resolver with a mempool watcher that doesn't know of any
+	// spend of the htlc output yet.
+	op := ctx.resolver.HtlcPoint()
+	mempoolSpend := make(chan *chainntnfs.SpendDetail, 1)
+	mempoolSub := &chainntnfs.MempoolSpendEvent{Spend: mempoolSpend}
+
+	mempool := chainntnfs.NewMockMempoolWatcher()
+	mempool.On("SubscribeMempoolSpent", op).Return(mempoolSub, nil)
+	mempool.On("LookupInputMempoolSpend", op).Return(
+		fn.None[wire.MsgTx](),
+	)
+	mempool.On("CancelMempoolSpendEvent", mempoolSub).Return()
+	ctx.resolver.Mempool = mempool
+
+	ctx.resolve()
+
+	// A spend that doesn't reveal the preimage is ignored.
+	sweepTx := &wire.MsgTx{
+		TxIn: []*wire.TxIn{{
+			Witness: [][]byte{{0}, {1}},
+		}},
+	}
+	sweepHash := sweepTx.TxHash()
+	mempoolSpend <- &chainntnfs.SpendDetail{
+		SpendingTx:    sweepTx,
+		SpenderTxHash: &sweepHash,
+	}
+
+	// The remote party broadcasts its second-level success transaction,
+	// revealing the preimage.
+	preimage := lntypes.Preimage{1}
+	spendTx := &wire.MsgTx{
+		TxIn: []*wire.TxIn{{
+			Witness: [][]byte{
+				{0}, {1}, {2}, preimage[:], {4},
+			},
+		}},
+	}
+	spendHash := spendTx.TxHash()
+	mempoolSpend <- &chainntnfs.SpendDetail{
+		SpendingTx:    spendTx,
+		SpenderTxHash: &spendHash,
+	}
+
+	// We expect the extracted preimage to be added to the witness beacon
+	// and a resolution message to settle the incoming side of the circuit.
+	newPreimages := <-ctx.preimageDB.newPreimages
+	require.Equal(t, []lntypes.Preimage{preimage}, newPreimages)
+
+	resolution := <-ctx.resolutionChan
+	require.Equal(t, [32]byte(preimage), *resolution.PreImage)
+
+	ctx.waitForResult(false)
+	mempool.AssertExpectations(t)
+}
+
 type resolveResult struct {
 	err          error
 	nextResolver ContractResolver
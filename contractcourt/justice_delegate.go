@@ -0,0 +1,102 @@
+package contractcourt
+
+import (
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// BreachJustice describes a breach that is handed to a JusticeDelegate along
+// with the justice transactions that we created for it.
+type BreachJustice struct {
+	// ChanPoint is the funding outpoint of the breached channel.
+	ChanPoint wire.OutPoint
+
+	// BreachTxid is the txid of the revoked commitment transaction that
+	// was broadcast by our peer.
+	BreachTxid chainhash.Hash
+
+	// BreachHeight is the height at which the breach transaction
+	// confirmed.
+	BreachHeight uint32
+
+	// SpendAll is the justice transaction that sweeps all breached
+	// outputs. It is the one we publish first.
+	SpendAll *wire.MsgTx
+
+	// Fallbacks are the justice transactions that sweep the commitment
+	// outputs, the HTLC outputs and the second-level HTLC outputs
+	// separately. We publish them if SpendAll doesn't confirm in time,
+	// e.g. because the HTLC outputs were pinned. They conflict with
+	// SpendAll.
+	Fallbacks []*wire.MsgTx
+}
+
+// JusticeDelegate is an external party, e.g. a watchtower or a justice
+// service, that breaches are handed to in addition to being handled locally.
+// This provides defense in depth in case our own justice transactions fail
+// to confirm.
+type JusticeDelegate interface {
+	// DelegateJustice hands the breach and its justice transactions to
+	// the delegate. It is called each time the justice transactions are
+	// (re)created, so it must be idempotent.
+	DelegateJustice(justice *BreachJustice) error
+}
+
+// newBreachJustice creates the BreachJustice handed to the justice delegate
+// for the given breach and justice transactions.
+func newBreachJustice(breachInfo *retributionInfo,
+	justiceTxs *justiceTxVariants) *BreachJustice {
+
+	justice := &BreachJustice{
+		ChanPoint:    breachInfo.chanPoint,
+		BreachTxid:   breachInfo.commitHash,
+		BreachHeight: breachInfo.breachHeight,
+	}
+
+	if justiceTxs.spendAll != nil {
+		justice.SpendAll = justiceTxs.spendAll.justiceTx
+	}
+
+	fallbacks := []*justiceTxCtx{
+		justiceTxs.spendCommitOuts, justiceTxs.spendHTLCs,
+	}
+	fallbacks = append(fallbacks, justiceTxs.spendSecondLevelHTLCs...)
+	for _, tx := range fallbacks {
+		if tx == nil {
+			continue
+		}
+
+		justice.Fallbacks = append(justice.Fallbacks, tx.justiceTx)
+	}
+
+	return justice
+}
+
+// delegateJustice hands the breach to the justice delegate, if one is
+// configured. The delegate is called in a goroutine so it can't delay our
+// own justice transactions.
+func (b *BreachArbitrator) delegateJustice(breachInfo *retributionInfo,
+	justiceTxs *justiceTxVariants) {
+
+	b.cfg.JusticeDelegate.WhenSome(func(delegate JusticeDelegate) {
+		justice := newBreachJustice(breachInfo, justiceTxs)
+
+		b.wg.Add(1)
+		go func() {
+			defer b.wg.Done()
+
+			err := delegate.DelegateJustice(justice)
+			if err != nil {
+				brarLog.Errorf("Unable to delegate justice "+
+					"for ChannelPoint(%v): %v",
+					justice.ChanPoint, err)
+
+				return
+			}
+
+			brarLog.Infof("Delegated justice for ChannelPoint(%v) "+
+				"with breach tx %v", justice.ChanPoint,
+				justice.BreachTxid)
+		}()
+	})
+}
@@ -0,0 +1,310 @@
+package contractcourt
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/sweep"
+)
+
+// ResolutionState describes what the resolver of a contract is currently
+// waiting for.
+type ResolutionState uint8
+
+const (
+	// ResolutionStateUnknown is used when the state of the resolver can't
+	// be determined.
+	ResolutionStateUnknown ResolutionState = iota
+
+	// ResolutionStateContesting is used for htlcs that are waiting for
+	// either the preimage to be revealed or the htlc to expire.
+	ResolutionStateContesting
+
+	// ResolutionStateAwaitingMaturity is used for outputs that can't be
+	// swept before their maturity height.
+	ResolutionStateAwaitingMaturity
+
+	// ResolutionStateSweeping is used for outputs that can be swept and
+	// are waiting for the sweep to confirm.
+	ResolutionStateSweeping
+
+	// ResolutionStateResolved is used for outputs that were swept back to
+	// the wallet.
+	ResolutionStateResolved
+)
+
+// String returns a human-readable description of the resolution state.
+func (s ResolutionState) String() string {
+	switch s {
+	case ResolutionStateContesting:
+		return "contesting"
+
+	case ResolutionStateAwaitingMaturity:
+		return "awaiting_maturity"
+
+	case ResolutionStateSweeping:
+		return "sweeping"
+
+	case ResolutionStateResolved:
+		return "resolved"
+
+	default:
+		return "unknown"
+	}
+}
+
+// PendingResolution describes a contract that is being resolved on chain,
+// together with projections of when and at what cost it needs to be swept.
+type PendingResolution struct {
+	// ChanPoint is the funding outpoint of the channel the contract
+	// belongs to.
+	ChanPoint wire.OutPoint
+
+	// Report is the resolver's report on the contract.
+	Report ContractReport
+
+	// RequiredWitness is the witness needed to sweep the output.
+	RequiredWitness RequiredWitness
+
+	// State is what the resolver is currently waiting for.
+	State ResolutionState
+
+	// DeadlineHeight is the height by which the output must be swept to
+	// not lose funds, if there is such a deadline.
+	DeadlineHeight fn.Option[int32]
+
+	// BlocksUntilDeadline is the number of blocks left until the deadline
+	// height, based on the best known height. It is negative if the
+	// deadline has passed, and zero if there's no deadline.
+	BlocksUntilDeadline int32
+
+	// FeeRate is the estimated fee rate needed to sweep the output in
+	// time for its deadline.
+	FeeRate chainfee.SatPerKWeight
+
+	// EstimatedSweepFee is the approximate fee needed to sweep the output
+	// at the estimated fee rate. It's based on a transaction that only
+	// sweeps this output, so batched sweeps will be cheaper. It's zero if
+	// the witness of the output isn't known.
+	EstimatedSweepFee btcutil.Amount
+}
+
+// resolutionDeadline returns the height by which the output of the given
+// resolver must be swept. Only first stage htlc outputs have a deadline, as
+// the counterparty can claim them after it.
+func resolutionDeadline(resolver ContractResolver,
+	report *ContractReport) fn.Option[int32] {
+
+	if report.Stage == 2 {
+		return fn.None[int32]()
+	}
+
+	switch r := resolver.(type) {
+	// Incoming htlcs must be claimed with the preimage before they expire.
+	case *htlcIncomingContestResolver:
+		return fn.Some(int32(r.htlcExpiry))
+
+	case *htlcSuccessResolver:
+		return fn.Some(int32(r.htlc.RefundTimeout))
+
+	// Outgoing htlcs must be timed out before the incoming htlc they were
+	// forwarded for expires.
+	case *htlcOutgoingContestResolver:
+		return r.incomingHTLCExpiryHeight
+
+	case *htlcTimeoutResolver:
+		return r.incomingHTLCExpiryHeight
+
+	default:
+		return fn.None[int32]()
+	}
+}
+
+// resolutionState returns the state of the given resolver at the given
+// height.
+func resolutionState(resolver ContractResolver, report *ContractReport,
+	bestHeight int32) ResolutionState {
+
+	switch {
+	case report.LimboBalance == 0 && report.RecoveredBalance > 0:
+		return ResolutionStateResolved
+
+	case isContestResolver(resolver):
+		return ResolutionStateContesting
+
+	case report.MaturityHeight > uint32(bestHeight):
+		return ResolutionStateAwaitingMaturity
+
+	default:
+		return ResolutionStateSweeping
+	}
+}
+
+// isContestResolver returns true if the given resolver is waiting for an htlc
+// to either be settled or expire.
+func isContestResolver(resolver ContractResolver) bool {
+	switch resolver.(type) {
+	case *htlcIncomingContestResolver, *htlcOutgoingContestResolver:
+		return true
+
+	default:
+		return false
+	}
+}
+
+// sweepWeight returns the approximate weight of a transaction that sweeps an
+// output with the given witness to the wallet. False is returned if the
+// witness isn't known.
+func sweepWeight(witness RequiredWitness) (lntypes.WeightUnit, bool) {
+	var witnessSize lntypes.WeightUnit
+	switch witness {
+	case WitnessPreimage:
+		witnessSize = input.AcceptedHtlcSuccessWitnessSize
+
+	case WitnessTimeout:
+		witnessSize = input.OfferedHtlcTimeoutWitnessSize
+
+	case WitnessCsvDelay:
+		witnessSize = input.ToLocalTimeoutWitnessSize
+
+	case WitnessSignature:
+		witnessSize = input.AnchorWitnessSize
+
+	default:
+		return 0, false
+	}
+
+	var weightEstimate input.TxWeightEstimator
+	weightEstimate.AddWitnessInput(witnessSize)
+	weightEstimate.AddP2TROutput()
+
+	return weightEstimate.Weight(), true
+}
+
+// newPendingResolution creates the pending resolution of the contract of the
+// given resolver, projecting its deadline and sweep fee at the given height.
+func (c *ChainArbitrator) newPendingResolution(chanPoint wire.OutPoint,
+	resolver ContractResolver, report *ContractReport,
+	bestHeight int32) (*PendingResolution, error) {
+
+	resolution := &PendingResolution{
+		ChanPoint:       chanPoint,
+		Report:          *report,
+		RequiredWitness: requiredWitness(resolver, report),
+		State:           resolutionState(resolver, report, bestHeight),
+		DeadlineHeight:  resolutionDeadline(resolver, report),
+	}
+
+	if resolution.State == ResolutionStateResolved {
+		return resolution, nil
+	}
+
+	// Outputs without a deadline are swept with the same default deadline
+	// that the sweeper uses.
+	confTarget := sweep.DefaultDeadlineDelta
+	resolution.DeadlineHeight.WhenSome(func(deadline int32) {
+		resolution.BlocksUntilDeadline = deadline - bestHeight
+		confTarget = resolution.BlocksUntilDeadline
+	})
+
+	// Outputs that are past or close to their deadline should confirm in
+	// the next block.
+	if confTarget < 1 {
+		confTarget = 1
+	}
+
+	feeRate, err := c.cfg.FeeEstimator.EstimateFeePerKW(uint32(confTarget))
+	if err != nil {
+		return nil, fmt.Errorf("unable to estimate fee rate for conf "+
+			"target %d: %w", confTarget, err)
+	}
+	resolution.FeeRate = feeRate
+
+	if weight, ok := sweepWeight(resolution.RequiredWitness); ok {
+		resolution.EstimatedSweepFee = feeRate.FeeForWeight(weight)
+	}
+
+	return resolution, nil
+}
+
+// ListPendingResolutions returns all contracts that are currently being
+// resolved by the channel arbitrators, along with their deadline heights and
+// estimated sweep fees based on the best known height and the current fee
+// estimates.
+//
+// NOTE: Outputs that were handed off to the utxo nursery aren't tracked by a
+// resolver and thus aren't listed.
+func (c *ChainArbitrator) ListPendingResolutions() ([]*PendingResolution,
+	error) {
+
+	_, bestHeight, err := c.cfg.ChainIO.GetBestBlock()
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch best block: %w", err)
+	}
+
+	c.Lock()
+	arbitrators := make([]*ChannelArbitrator, 0, len(c.activeChannels))
+	for _, arbitrator := range c.activeChannels {
+		arbitrators = append(arbitrators, arbitrator)
+	}
+	c.Unlock()
+
+	var resolutions []*PendingResolution
+	for _, arbitrator := range arbitrators {
+		chanPoint := arbitrator.cfg.ChanPoint
+
+		arbitrator.activeResolversLock.RLock()
+		resolvers := make(
+			[]ContractResolver, len(arbitrator.activeResolvers),
+		)
+		copy(resolvers, arbitrator.activeResolvers)
+		arbitrator.activeResolversLock.RUnlock()
+
+		for _, resolver := range resolvers {
+			r, ok := resolver.(reportingContractResolver)
+			if !ok {
+				continue
+			}
+
+			report := r.report()
+			if report == nil {
+				continue
+			}
+
+			resolution, err := c.newPendingResolution(
+				chanPoint, r, report, bestHeight,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			resolutions = append(resolutions, resolution)
+		}
+	}
+
+	// Sort the resolutions by their deadline, so the most urgent ones are
+	// listed first. Resolutions without a deadline come last.
+	sort.SliceStable(resolutions, func(i, j int) bool {
+		deadlineI := resolutions[i].DeadlineHeight.UnwrapOr(
+			math.MaxInt32,
+		)
+		deadlineJ := resolutions[j].DeadlineHeight.UnwrapOr(
+			math.MaxInt32,
+		)
+		if deadlineI != deadlineJ {
+			return deadlineI < deadlineJ
+		}
+
+		return resolutions[i].ChanPoint.String() <
+			resolutions[j].ChanPoint.String()
+	})
+
+	return resolutions, nil
+}
@@ -0,0 +1,132 @@
+package contractcourt
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestListPendingResolutions tests that the pending resolutions of all channel
+// arbitrators are listed by urgency, with their state, deadline and estimated
+// sweep fee.
+func TestListPendingResolutions(t *testing.T) {
+	t.Parallel()
+
+	const feeRate = chainfee.SatPerKWeight(2_000)
+
+	chainArb := NewChainArbitrator(ChainArbitratorConfig{
+		ChainIO:      &mockChainIO{},
+		FeeEstimator: chainfee.NewStaticEstimator(feeRate, 0),
+	}, nil)
+
+	// The first channel has a commitment output that can be swept and an
+	// outgoing htlc that was forwarded for an incoming htlc expiring at
+	// height 300.
+	chanPoint1 := wire.OutPoint{Index: 1}
+	commitResolver := &commitSweepResolver{
+		currentReport: ContractReport{
+			Outpoint:     wire.OutPoint{Index: 2},
+			Type:         ReportOutputUnencumbered,
+			Amount:       50_000,
+			LimboBalance: 50_000,
+		},
+	}
+	outgoingResolver := &htlcOutgoingContestResolver{
+		htlcTimeoutResolver: &htlcTimeoutResolver{
+			htlcResolution: lnwallet.OutgoingHtlcResolution{
+				ClaimOutpoint: wire.OutPoint{Index: 3},
+				Expiry:        250,
+			},
+			htlc: channeldb.HTLC{
+				Amt: lnwire.NewMSatFromSatoshis(10_000),
+			},
+			incomingHTLCExpiryHeight: fn.Some(int32(300)),
+		},
+	}
+	chainArb.activeChannels[chanPoint1] = &ChannelArbitrator{
+		cfg: ChannelArbitratorConfig{
+			ChanPoint: chanPoint1,
+		},
+		activeResolvers: []ContractResolver{
+			commitResolver, outgoingResolver,
+		},
+	}
+
+	// The second channel has an incoming htlc expiring at height 200.
+	chanPoint2 := wire.OutPoint{Index: 4}
+	incomingResolver := &htlcIncomingContestResolver{
+		htlcExpiry: 200,
+		htlcSuccessResolver: &htlcSuccessResolver{
+			htlcResolution: lnwallet.IncomingHtlcResolution{
+				ClaimOutpoint: wire.OutPoint{Index: 5},
+			},
+			htlc: channeldb.HTLC{
+				Amt:           20_000_000,
+				RefundTimeout: 200,
+			},
+		},
+	}
+	chainArb.activeChannels[chanPoint2] = &ChannelArbitrator{
+		cfg: ChannelArbitratorConfig{
+			ChanPoint: chanPoint2,
+		},
+		activeResolvers: []ContractResolver{incomingResolver},
+	}
+
+	resolutions, err := chainArb.ListPendingResolutions()
+	require.NoError(t, err)
+	require.Len(t, resolutions, 3)
+
+	// sweepFee returns the expected sweep fee for the given witness.
+	sweepFee := func(witness RequiredWitness) int64 {
+		weight, ok := sweepWeight(witness)
+		require.True(t, ok)
+
+		return int64(feeRate.FeeForWeight(weight))
+	}
+
+	// The most urgent resolution is listed first.
+	incoming := resolutions[0]
+	require.Equal(t, chanPoint2, incoming.ChanPoint)
+	require.Equal(t, ResolutionStateContesting, incoming.State)
+	require.Equal(t, WitnessPreimage, incoming.RequiredWitness)
+	require.Equal(t, fn.Some(int32(200)), incoming.DeadlineHeight)
+	require.EqualValues(t, 200, incoming.BlocksUntilDeadline)
+	require.Equal(t, feeRate, incoming.FeeRate)
+	require.EqualValues(
+		t, sweepFee(WitnessPreimage), incoming.EstimatedSweepFee,
+	)
+
+	outgoing := resolutions[1]
+	require.Equal(t, chanPoint1, outgoing.ChanPoint)
+	require.Equal(t, ResolutionStateContesting, outgoing.State)
+	require.Equal(t, fn.Some(int32(300)), outgoing.DeadlineHeight)
+	require.EqualValues(t, 300, outgoing.BlocksUntilDeadline)
+	require.EqualValues(
+		t, sweepFee(WitnessTimeout), outgoing.EstimatedSweepFee,
+	)
+
+	// Outputs without a deadline are listed last.
+	commit := resolutions[2]
+	require.Equal(t, ResolutionStateSweeping, commit.State)
+	require.Equal(t, fn.None[int32](), commit.DeadlineHeight)
+	require.Zero(t, commit.BlocksUntilDeadline)
+	require.EqualValues(
+		t, sweepFee(WitnessCsvDelay), commit.EstimatedSweepFee,
+	)
+
+	// Once the commitment output is swept, no fee is projected for it.
+	commitResolver.currentReport.LimboBalance = 0
+	commitResolver.currentReport.RecoveredBalance = 50_000
+
+	resolutions, err = chainArb.ListPendingResolutions()
+	require.NoError(t, err)
+	require.Equal(t, ResolutionStateResolved, resolutions[2].State)
+	require.Zero(t, resolutions[2].EstimatedSweepFee)
+}
@@ -0,0 +1,349 @@
+package contractcourt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// ResolutionSnapshotVersion is the version of the resolution snapshot format.
+// It's increased whenever the format changes in a way that isn't backwards
+// compatible.
+const ResolutionSnapshotVersion = 1
+
+// ErrInvalidSnapshotSignature is returned when the signature of a resolution
+// snapshot doesn't commit to the snapshot or wasn't made by the expected key.
+var ErrInvalidSnapshotSignature = errors.New("invalid resolution snapshot " +
+	"signature")
+
+// RequiredWitness describes the witness that is required to sweep the output
+// of a contract that is being resolved.
+type RequiredWitness uint8
+
+const (
+	// WitnessUnknown is used for contracts of resolvers that don't expose
+	// the witness they'll use.
+	WitnessUnknown RequiredWitness = iota
+
+	// WitnessPreimage requires a signature together with the preimage of
+	// the htlc's payment hash.
+	WitnessPreimage
+
+	// WitnessTimeout requires a signature that is only valid once the
+	// htlc's absolute timeout has expired.
+	WitnessTimeout
+
+	// WitnessCsvDelay requires a signature that is only valid once the
+	// output's relative delay has passed.
+	WitnessCsvDelay
+
+	// WitnessSignature only requires a signature.
+	WitnessSignature
+)
+
+// String returns a human-readable description of the required witness.
+func (w RequiredWitness) String() string {
+	switch w {
+	case WitnessPreimage:
+		return "preimage"
+
+	case WitnessTimeout:
+		return "timeout"
+
+	case WitnessCsvDelay:
+		return "csv_delay"
+
+	case WitnessSignature:
+		return "signature"
+
+	default:
+		return "unknown"
+	}
+}
+
+// String returns a human-readable description of the output type.
+func (r ReportOutputType) String() string {
+	switch r {
+	case ReportOutputIncomingHtlc:
+		return "incoming_htlc"
+
+	case ReportOutputOutgoingHtlc:
+		return "outgoing_htlc"
+
+	case ReportOutputUnencumbered:
+		return "unencumbered"
+
+	case ReportOutputAnchor:
+		return "anchor"
+
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(r))
+	}
+}
+
+// ContractSnapshot is the exported resolution state of a single contract.
+type ContractSnapshot struct {
+	// Outpoint is the output that is being resolved.
+	Outpoint string `json:"outpoint"`
+
+	// ContractType is the type of the output that is being resolved.
+	ContractType string `json:"contract_type"`
+
+	// AmountSat is the value that will be swept back to the wallet.
+	AmountSat int64 `json:"amount_sat"`
+
+	// LimboBalanceSat is the value that is still locked in the contract.
+	LimboBalanceSat int64 `json:"limbo_balance_sat"`
+
+	// RecoveredBalanceSat is the value that has already been swept back
+	// to the wallet.
+	RecoveredBalanceSat int64 `json:"recovered_balance_sat"`
+
+	// MaturityHeight is the absolute height at which the output can be
+	// swept. It's zero if the height isn't known yet.
+	MaturityHeight uint32 `json:"maturity_height"`
+
+	// Stage is the stage of an htlc: 1 for the first level and 2 for the
+	// second level htlc transaction output.
+	Stage uint32 `json:"stage"`
+
+	// RequiredWitness is the witness needed to sweep the output.
+	RequiredWitness string `json:"required_witness"`
+}
+
+// ChannelSnapshot is the exported resolution state of a single channel.
+type ChannelSnapshot struct {
+	// ChannelPoint is the funding outpoint of the channel.
+	ChannelPoint string `json:"channel_point"`
+
+	// ShortChannelID is the short channel ID of the channel.
+	ShortChannelID uint64 `json:"short_channel_id"`
+
+	// Contracts are the contracts of the channel that are still being
+	// resolved.
+	Contracts []ContractSnapshot `json:"contracts"`
+}
+
+// ResolutionSnapshot is a machine-readable export of all contracts that are
+// being resolved on chain, which allows custodial operators to prove the
+// funds that are in flight.
+type ResolutionSnapshot struct {
+	// Version is the version of the snapshot format.
+	Version uint32 `json:"version"`
+
+	// ChainHash is the genesis hash of the chain the contracts are on.
+	ChainHash string `json:"chain_hash"`
+
+	// BestHeight is the best known height when the snapshot was taken.
+	BestHeight int32 `json:"best_height"`
+
+	// Timestamp is the unix time at which the snapshot was taken.
+	Timestamp int64 `json:"timestamp"`
+
+	// TotalLimboBalanceSat is the total value locked in all contracts.
+	TotalLimboBalanceSat int64 `json:"total_limbo_balance_sat"`
+
+	// Channels are the channels with contracts that are being resolved.
+	Channels []ChannelSnapshot `json:"channels"`
+}
+
+// SignedResolutionSnapshot is a serialized resolution snapshot together with
+// a signature over it.
+type SignedResolutionSnapshot struct {
+	// Snapshot is the serialized snapshot that the signature commits to.
+	Snapshot []byte
+
+	// Signature is the compact, public key recoverable signature over the
+	// double SHA256 of the serialized snapshot.
+	Signature []byte
+}
+
+// SnapshotSigner signs resolution snapshots.
+type SnapshotSigner interface {
+	// SignMessageCompact signs the given message, single or double SHA256
+	// hashing it first, and returns the signature in the compact, public
+	// key recoverable format.
+	SignMessageCompact(msg []byte, doubleHash bool) ([]byte, error)
+}
+
+// requiredWitness returns the witness needed to sweep the output of the given
+// resolver.
+func requiredWitness(resolver ContractResolver,
+	report *ContractReport) RequiredWitness {
+
+	// Second level htlc outputs are swept after their relative delay.
+	if report.Stage == 2 {
+		return WitnessCsvDelay
+	}
+
+	switch resolver.(type) {
+	case *htlcIncomingContestResolver, *htlcSuccessResolver:
+		return WitnessPreimage
+
+	case *htlcOutgoingContestResolver, *htlcTimeoutResolver:
+		return WitnessTimeout
+
+	case *commitSweepResolver:
+		return WitnessCsvDelay
+
+	case *anchorResolver:
+		return WitnessSignature
+
+	default:
+		return WitnessUnknown
+	}
+}
+
+// newContractSnapshot creates the snapshot of a contract from its resolver's
+// report.
+func newContractSnapshot(resolver ContractResolver,
+	report *ContractReport) ContractSnapshot {
+
+	return ContractSnapshot{
+		Outpoint:            report.Outpoint.String(),
+		ContractType:        report.Type.String(),
+		AmountSat:           int64(report.Amount),
+		LimboBalanceSat:     int64(report.LimboBalance),
+		RecoveredBalanceSat: int64(report.RecoveredBalance),
+		MaturityHeight:      report.MaturityHeight,
+		Stage:               report.Stage,
+		RequiredWitness:     requiredWitness(resolver, report).String(),
+	}
+}
+
+// contractSnapshots returns the snapshots of the contracts of the active
+// resolvers.
+func (c *ChannelArbitrator) contractSnapshots() []ContractSnapshot {
+	c.activeResolversLock.RLock()
+	defer c.activeResolversLock.RUnlock()
+
+	var snapshots []ContractSnapshot
+	for _, resolver := range c.activeResolvers {
+		r, ok := resolver.(reportingContractResolver)
+		if !ok {
+			continue
+		}
+
+		report := r.report()
+		if report == nil {
+			continue
+		}
+
+		snapshots = append(snapshots, newContractSnapshot(r, report))
+	}
+
+	return snapshots
+}
+
+// ExportResolutions returns a snapshot of all contracts that are currently
+// being resolved by the channel arbitrators.
+//
+// NOTE: Outputs that were handed off to the utxo nursery aren't tracked by a
+// resolver and thus aren't part of the snapshot.
+func (c *ChainArbitrator) ExportResolutions() (*ResolutionSnapshot, error) {
+	_, bestHeight, err := c.cfg.ChainIO.GetBestBlock()
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch best block: %w", err)
+	}
+
+	c.Lock()
+	arbitrators := make([]*ChannelArbitrator, 0, len(c.activeChannels))
+	for _, arbitrator := range c.activeChannels {
+		arbitrators = append(arbitrators, arbitrator)
+	}
+	c.Unlock()
+
+	snapshot := &ResolutionSnapshot{
+		Version:    ResolutionSnapshotVersion,
+		ChainHash:  c.cfg.ChainHash.String(),
+		BestHeight: bestHeight,
+		Timestamp:  time.Now().Unix(),
+	}
+
+	for _, arbitrator := range arbitrators {
+		contracts := arbitrator.contractSnapshots()
+		if len(contracts) == 0 {
+			continue
+		}
+
+		for _, contract := range contracts {
+			limbo := contract.LimboBalanceSat
+			snapshot.TotalLimboBalanceSat += limbo
+		}
+
+		snapshot.Channels = append(snapshot.Channels, ChannelSnapshot{
+			ChannelPoint:   arbitrator.cfg.ChanPoint.String(),
+			ShortChannelID: arbitrator.cfg.ShortChanID.ToUint64(),
+			Contracts:      contracts,
+		})
+	}
+
+	// Sort the channels so the snapshot doesn't depend on map iteration
+	// order.
+	sort.Slice(snapshot.Channels, func(i, j int) bool {
+		return snapshot.Channels[i].ChannelPoint <
+			snapshot.Channels[j].ChannelPoint
+	})
+
+	return snapshot, nil
+}
+
+// SignResolutionSnapshot serializes the snapshot and signs it with the given
+// signer.
+func SignResolutionSnapshot(snapshot *ResolutionSnapshot,
+	signer SnapshotSigner) (*SignedResolutionSnapshot, error) {
+
+	serialized, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("unable to serialize snapshot: %w", err)
+	}
+
+	sig, err := signer.SignMessageCompact(serialized, true)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign snapshot: %w", err)
+	}
+
+	return &SignedResolutionSnapshot{
+		Snapshot:  serialized,
+		Signature: sig,
+	}, nil
+}
+
+// VerifyResolutionSnapshot verifies that the signed snapshot was signed by the
+// given public key and returns the deserialized snapshot.
+func VerifyResolutionSnapshot(signed *SignedResolutionSnapshot,
+	pubKey *btcec.PublicKey) (*ResolutionSnapshot, error) {
+
+	digest := chainhash.DoubleHashB(signed.Snapshot)
+	signer, _, err := ecdsa.RecoverCompact(signed.Signature, digest)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSnapshotSignature,
+			err)
+	}
+
+	if !signer.IsEqual(pubKey) {
+		return nil, ErrInvalidSnapshotSignature
+	}
+
+	var snapshot ResolutionSnapshot
+	decoder := json.NewDecoder(bytes.NewReader(signed.Snapshot))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("unable to deserialize snapshot: %w",
+			err)
+	}
+
+	if snapshot.Version != ResolutionSnapshotVersion {
+		return nil, fmt.Errorf("unknown snapshot version %d",
+			snapshot.Version)
+	}
+
+	return &snapshot, nil
+}
@@ -0,0 +1,118 @@
+package contractcourt
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExportResolutions tests that the resolution snapshot contains the
+// contracts of all channel arbitrators with active resolvers, and that the
+// signed snapshot can only be verified with the signing key.
+func TestExportResolutions(t *testing.T) {
+	t.Parallel()
+
+	chainArb := NewChainArbitrator(ChainArbitratorConfig{
+		ChainIO: &mockChainIO{},
+	}, nil)
+
+	// The first channel has a commitment output and an outgoing htlc that
+	// are being resolved.
+	chanPoint := wire.OutPoint{Index: 1}
+	commitOutpoint := wire.OutPoint{Index: 2}
+	htlcOutpoint := wire.OutPoint{Index: 3}
+
+	commitResolver := &commitSweepResolver{
+		currentReport: ContractReport{
+			Outpoint:     commitOutpoint,
+			Type:         ReportOutputUnencumbered,
+			Amount:       50_000,
+			LimboBalance: 50_000,
+		},
+	}
+	htlcResolver := &htlcOutgoingContestResolver{
+		htlcTimeoutResolver: &htlcTimeoutResolver{
+			htlcResolution: lnwallet.OutgoingHtlcResolution{
+				ClaimOutpoint: htlcOutpoint,
+				Expiry:        500,
+			},
+			htlc: channeldb.HTLC{
+				Amt: lnwire.NewMSatFromSatoshis(10_000),
+			},
+		},
+	}
+
+	chainArb.activeChannels[chanPoint] = &ChannelArbitrator{
+		cfg: ChannelArbitratorConfig{
+			ChanPoint:   chanPoint,
+			ShortChanID: lnwire.NewShortChanIDFromInt(1),
+		},
+		activeResolvers: []ContractResolver{
+			commitResolver, htlcResolver,
+		},
+	}
+
+	// The second channel has no contracts and is left out.
+	idlePoint := wire.OutPoint{Index: 4}
+	chainArb.activeChannels[idlePoint] = &ChannelArbitrator{
+		cfg: ChannelArbitratorConfig{
+			ChanPoint: idlePoint,
+		},
+	}
+
+	snapshot, err := chainArb.ExportResolutions()
+	require.NoError(t, err)
+
+	require.EqualValues(t, ResolutionSnapshotVersion, snapshot.Version)
+	require.EqualValues(t, 60_000, snapshot.TotalLimboBalanceSat)
+	require.Equal(t, []ChannelSnapshot{{
+		ChannelPoint:   chanPoint.String(),
+		ShortChannelID: 1,
+		Contracts: []ContractSnapshot{{
+			Outpoint:        commitOutpoint.String(),
+			ContractType:    "unencumbered",
+			AmountSat:       50_000,
+			LimboBalanceSat: 50_000,
+			RequiredWitness: WitnessCsvDelay.String(),
+		}, {
+			Outpoint:        htlcOutpoint.String(),
+			ContractType:    "outgoing_htlc",
+			AmountSat:       10_000,
+			LimboBalanceSat: 10_000,
+			MaturityHeight:  500,
+			Stage:           1,
+			RequiredWitness: WitnessTimeout.String(),
+		}},
+	}}, snapshot.Channels)
+
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	signer := keychain.NewPrivKeyMessageSigner(
+		privKey, keychain.KeyLocator{},
+	)
+
+	signed, err := SignResolutionSnapshot(snapshot, signer)
+	require.NoError(t, err)
+
+	verified, err := VerifyResolutionSnapshot(signed, privKey.PubKey())
+	require.NoError(t, err)
+	require.Equal(t, snapshot, verified)
+
+	// The snapshot can't be verified with another key.
+	otherKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	_, err = VerifyResolutionSnapshot(signed, otherKey.PubKey())
+	require.ErrorIs(t, err, ErrInvalidSnapshotSignature)
+
+	// Nor can a modified snapshot be verified.
+	signed.Snapshot[len(signed.Snapshot)-2] ^= 1
+	_, err = VerifyResolutionSnapshot(signed, privKey.PubKey())
+	require.Error(t, err)
+}
@@ -0,0 +1,181 @@
+package contractcourt
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// expectedBlockInterval is the average time between two blocks that is used
+// to project when a time-locked output can be swept.
+const expectedBlockInterval = 10 * time.Minute
+
+// TimeLockedOutput is an output of a force closed channel whose value can't
+// be swept back to the wallet before a CSV or CLTV lock expires.
+type TimeLockedOutput struct {
+	// ChanPoint is the funding outpoint of the force closed channel.
+	ChanPoint wire.OutPoint
+
+	// Outpoint is the time-locked output.
+	Outpoint wire.OutPoint
+
+	// Type is the type of the time-locked output.
+	Type ReportOutputType
+
+	// Stage is the stage of an htlc: 1 for the first level and 2 for the
+	// second level htlc transaction output.
+	Stage uint32
+
+	// Amount is the value that is still locked in the output.
+	Amount btcutil.Amount
+
+	// MaturityHeight is the absolute height at which the lock expires. It's
+	// zero if the height isn't known yet, e.g. because the closing
+	// transaction hasn't confirmed.
+	MaturityHeight uint32
+
+	// BlocksTilMaturity is the number of blocks until the lock expires.
+	BlocksTilMaturity uint32
+
+	// ProjectedMaturity is the projected time at which the lock expires,
+	// assuming an average block interval of ten minutes. It's the zero
+	// time if the maturity height isn't known yet.
+	ProjectedMaturity time.Time
+}
+
+// TimeLockedFunds summarizes the funds of all force closed channels that are
+// time-locked.
+type TimeLockedFunds struct {
+	// BestHeight is the best known height when the summary was created.
+	BestHeight int32
+
+	// TotalAmount is the total value locked in all outputs.
+	TotalAmount btcutil.Amount
+
+	// Outputs are the time-locked outputs, ordered by the height at which
+	// they mature. Outputs with an unknown maturity height are last.
+	Outputs []TimeLockedOutput
+}
+
+// timeLockedOutputs returns the outputs of the given contract reports that are
+// still time-locked at the given height.
+func timeLockedOutputs(chanPoint wire.OutPoint, reports []*ContractReport,
+	bestHeight uint32, now time.Time) []TimeLockedOutput {
+
+	var outputs []TimeLockedOutput
+	for _, report := range reports {
+		// Anchors aren't encumbered by a time lock, and outputs that
+		// were swept already don't lock any funds.
+		if report.Type == ReportOutputAnchor ||
+			report.LimboBalance == 0 {
+
+			continue
+		}
+
+		// First level incoming htlcs can be claimed as soon as the
+		// preimage is known, their maturity height is the height at
+		// which the remote party can time them out instead.
+		if report.Type == ReportOutputIncomingHtlc &&
+			report.Stage == 1 {
+
+			continue
+		}
+
+		// Outputs whose lock already expired are only waiting to be
+		// swept.
+		if report.MaturityHeight != 0 &&
+			report.MaturityHeight <= bestHeight {
+
+			continue
+		}
+
+		output := TimeLockedOutput{
+			ChanPoint:      chanPoint,
+			Outpoint:       report.Outpoint,
+			Type:           report.Type,
+			Stage:          report.Stage,
+			Amount:         report.LimboBalance,
+			MaturityHeight: report.MaturityHeight,
+		}
+
+		if report.MaturityHeight != 0 {
+			blocks := report.MaturityHeight - bestHeight
+
+			output.BlocksTilMaturity = blocks
+			output.ProjectedMaturity = now.Add(
+				time.Duration(blocks) * expectedBlockInterval,
+			)
+		}
+
+		outputs = append(outputs, output)
+	}
+
+	return outputs
+}
+
+// sortTimeLockedOutputs orders the outputs by their maturity height, with
+// the outputs of unknown maturity last.
+func sortTimeLockedOutputs(outputs []TimeLockedOutput) {
+	sort.SliceStable(outputs, func(i, j int) bool {
+		hi, hj := outputs[i].MaturityHeight, outputs[j].MaturityHeight
+		switch {
+		case hi == hj:
+			return outputs[i].Outpoint.String() <
+				outputs[j].Outpoint.String()
+
+		case hi == 0:
+			return false
+
+		case hj == 0:
+			return true
+
+		default:
+			return hi < hj
+		}
+	})
+}
+
+// TimeLockedFunds returns a summary of all outputs of force closed channels
+// that are currently time-locked, together with the heights and projected
+// times at which they can be swept back to the wallet.
+//
+// NOTE: Outputs that were handed off to the utxo nursery aren't tracked by a
+// resolver and thus aren't part of the summary.
+func (c *ChainArbitrator) TimeLockedFunds() (*TimeLockedFunds, error) {
+	_, bestHeight, err := c.cfg.ChainIO.GetBestBlock()
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch best block: %w", err)
+	}
+
+	c.Lock()
+	arbitrators := make([]*ChannelArbitrator, 0, len(c.activeChannels))
+	for _, arbitrator := range c.activeChannels {
+		arbitrators = append(arbitrators, arbitrator)
+	}
+	c.Unlock()
+
+	funds := &TimeLockedFunds{
+		BestHeight: bestHeight,
+	}
+
+	now := time.Now()
+	for _, arbitrator := range arbitrators {
+		outputs := timeLockedOutputs(
+			arbitrator.cfg.ChanPoint, arbitrator.Report(),
+			uint32(bestHeight), now,
+		)
+
+		for _, output := range outputs {
+			funds.TotalAmount += output.Amount
+		}
+
+		funds.Outputs = append(funds.Outputs, outputs...)
+	}
+
+	sortTimeLockedOutputs(funds.Outputs)
+
+	return funds, nil
+}
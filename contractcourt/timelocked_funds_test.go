@@ -0,0 +1,147 @@
+package contractcourt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTimeLockedOutputs tests that only the outputs that are still encumbered
+// by a time lock are reported, together with their projected maturity.
+func TestTimeLockedOutputs(t *testing.T) {
+	t.Parallel()
+
+	const bestHeight = 100
+	chanPoint := wire.OutPoint{Index: 1}
+	now := time.Unix(1_700_000_000, 0)
+
+	reports := []*ContractReport{
+		// A commitment output that matures in 10 blocks.
+		{
+			Outpoint:       wire.OutPoint{Index: 2},
+			Type:           ReportOutputUnencumbered,
+			MaturityHeight: 110,
+			LimboBalance:   50_000,
+		},
+		// A commitment output whose lock already expired.
+		{
+			Outpoint:       wire.OutPoint{Index: 3},
+			Type:           ReportOutputUnencumbered,
+			MaturityHeight: 100,
+			LimboBalance:   50_000,
+		},
+		// A commitment output that was swept already.
+		{
+			Outpoint:         wire.OutPoint{Index: 4},
+			Type:             ReportOutputUnencumbered,
+			MaturityHeight:   120,
+			RecoveredBalance: 50_000,
+		},
+		// An anchor isn't time-locked.
+		{
+			Outpoint:     wire.OutPoint{Index: 5},
+			Type:         ReportOutputAnchor,
+			LimboBalance: 330,
+		},
+		// A first level incoming htlc can be claimed with the preimage.
+		{
+			Outpoint:       wire.OutPoint{Index: 6},
+			Type:           ReportOutputIncomingHtlc,
+			MaturityHeight: 150,
+			LimboBalance:   10_000,
+			Stage:          1,
+		},
+		// A second level incoming htlc is subject to a CSV delay.
+		{
+			Outpoint:       wire.OutPoint{Index: 7},
+			Type:           ReportOutputIncomingHtlc,
+			MaturityHeight: 104,
+			LimboBalance:   10_000,
+			Stage:          2,
+		},
+	}
+
+	outputs := timeLockedOutputs(chanPoint, reports, bestHeight, now)
+	require.Equal(t, []TimeLockedOutput{{
+		ChanPoint:         chanPoint,
+		Outpoint:          wire.OutPoint{Index: 2},
+		Type:              ReportOutputUnencumbered,
+		Amount:            50_000,
+		MaturityHeight:    110,
+		BlocksTilMaturity: 10,
+		ProjectedMaturity: now.Add(100 * time.Minute),
+	}, {
+		ChanPoint:         chanPoint,
+		Outpoint:          wire.OutPoint{Index: 7},
+		Type:              ReportOutputIncomingHtlc,
+		Stage:             2,
+		Amount:            10_000,
+		MaturityHeight:    104,
+		BlocksTilMaturity: 4,
+		ProjectedMaturity: now.Add(40 * time.Minute),
+	}}, outputs)
+}
+
+// TestChainArbitratorTimeLockedFunds tests that the time-locked funds of all
+// channel arbitrators are summarized and ordered by their maturity.
+func TestChainArbitratorTimeLockedFunds(t *testing.T) {
+	t.Parallel()
+
+	chainArb := NewChainArbitrator(ChainArbitratorConfig{
+		ChainIO: &mockChainIO{},
+	}, nil)
+
+	// The commitment output's maturity isn't known until the commitment
+	// transaction confirms, so it's ordered after the htlc.
+	chanPoint := wire.OutPoint{Index: 1}
+	commitOutpoint := wire.OutPoint{Index: 2}
+	htlcOutpoint := wire.OutPoint{Index: 3}
+
+	commitResolver := &commitSweepResolver{
+		currentReport: ContractReport{
+			Outpoint:     commitOutpoint,
+			Type:         ReportOutputUnencumbered,
+			Amount:       50_000,
+			LimboBalance: 50_000,
+		},
+	}
+	htlcResolver := &htlcOutgoingContestResolver{
+		htlcTimeoutResolver: &htlcTimeoutResolver{
+			htlcResolution: lnwallet.OutgoingHtlcResolution{
+				ClaimOutpoint: htlcOutpoint,
+				Expiry:        500,
+			},
+			htlc: channeldb.HTLC{
+				Amt: lnwire.NewMSatFromSatoshis(10_000),
+			},
+		},
+	}
+
+	chainArb.activeChannels[chanPoint] = &ChannelArbitrator{
+		cfg: ChannelArbitratorConfig{
+			ChanPoint: chanPoint,
+		},
+		activeResolvers: []ContractResolver{
+			commitResolver, htlcResolver,
+		},
+	}
+
+	funds, err := chainArb.TimeLockedFunds()
+	require.NoError(t, err)
+
+	require.EqualValues(t, 60_000, funds.TotalAmount)
+	require.Len(t, funds.Outputs, 2)
+
+	require.Equal(t, htlcOutpoint, funds.Outputs[0].Outpoint)
+	require.EqualValues(t, 500, funds.Outputs[0].BlocksTilMaturity)
+	require.False(t, funds.Outputs[0].ProjectedMaturity.IsZero())
+
+	require.Equal(t, commitOutpoint, funds.Outputs[1].Outpoint)
+	require.Zero(t, funds.Outputs[1].MaturityHeight)
+	require.True(t, funds.Outputs[1].ProjectedMaturity.IsZero())
+}
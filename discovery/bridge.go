@@ -0,0 +1,224 @@
+package discovery
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/lnpeer"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/subscribe"
+)
+
+var (
+	// ErrGossipBridgeDisabled is returned when the gossip bridge is used
+	// while it isn't enabled.
+	ErrGossipBridgeDisabled = errors.New("gossip bridge is not enabled")
+
+	// ErrUnsupportedBridgeMsg is returned when a message that isn't a
+	// channel announcement, channel update or node announcement is
+	// injected through the gossip bridge.
+	ErrUnsupportedBridgeMsg = errors.New("unsupported gossip bridge " +
+		"message")
+)
+
+// bridgeKeyTag is the tag used to derive the identity of the gossip bridge's
+// pseudo peer.
+const bridgeKeyTag = "lnd-gossip-bridge"
+
+// GossipBridge relays gossip between the gossiper and external tooling, which
+// lets teams running private test networks (regtest, signet) mirror or seed
+// the graph of a node without connecting it to every other node. Validated
+// announcements are exported as a stream, and externally obtained
+// announcements can be injected, in which case they are fully validated as if
+// they had been received from a remote peer.
+type GossipBridge struct {
+	chainHash chainhash.Hash
+
+	// processAnn hands an injected announcement to the gossiper.
+	processAnn func(lnwire.Message, lnpeer.Peer) chan error
+
+	// peer is the pseudo peer injected announcements are attributed to.
+	peer *bridgePeer
+
+	ntfnServer *subscribe.Server
+
+	quit chan struct{}
+}
+
+// newGossipBridge creates a new gossip bridge for the given chain.
+func newGossipBridge(chainHash chainhash.Hash,
+	processAnn func(lnwire.Message, lnpeer.Peer) chan error) *GossipBridge {
+
+	// Injected announcements are attributed to an identity derived from
+	// the chain hash, so any ban score they accumulate is kept separate
+	// from real peers. The key is never used to sign anything.
+	keySeed := append([]byte(bridgeKeyTag), chainHash[:]...)
+	priv, _ := btcec.PrivKeyFromBytes(chainhash.HashB(keySeed))
+
+	quit := make(chan struct{})
+
+	return &GossipBridge{
+		chainHash:  chainHash,
+		processAnn: processAnn,
+		peer: &bridgePeer{
+			identity: priv.PubKey(),
+			quit:     quit,
+		},
+		ntfnServer: subscribe.NewServer(),
+		quit:       quit,
+	}
+}
+
+// start starts the bridge's notification server.
+func (b *GossipBridge) start() error {
+	return b.ntfnServer.Start()
+}
+
+// stop stops the bridge, cancelling all export subscriptions.
+func (b *GossipBridge) stop() error {
+	close(b.quit)
+
+	return b.ntfnServer.Stop()
+}
+
+// SubscribeAnnouncements returns a subscription that receives every
+// announcement accepted by the gossiper, as an lnwire.Message. Only channel
+// announcements, channel updates and node announcements for the bridge's
+// chain are exported.
+func (b *GossipBridge) SubscribeAnnouncements() (*subscribe.Client, error) {
+	return b.ntfnServer.Subscribe()
+}
+
+// InjectAnnouncement processes an announcement obtained outside of the
+// peer-to-peer network. The announcement is fully validated before it's
+// added to the graph and broadcast to our peers.
+func (b *GossipBridge) InjectAnnouncement(msg lnwire.Message) error {
+	if !b.relayable(msg) {
+		return fmt.Errorf("%w: %v", ErrUnsupportedBridgeMsg,
+			msg.MsgType())
+	}
+
+	select {
+	case err := <-b.processAnn(msg, b.peer):
+		return err
+
+	case <-b.quit:
+		return ErrGossiperShuttingDown
+	}
+}
+
+// export sends the given accepted announcements to all export subscribers.
+func (b *GossipBridge) export(anns []networkMsg) {
+	for _, ann := range anns {
+		if !b.relayable(ann.msg) {
+			continue
+		}
+
+		if err := b.ntfnServer.SendUpdate(ann.msg); err != nil {
+			log.Debugf("Unable to export %v over gossip bridge: %v",
+				ann.msg.MsgType(), err)
+		}
+	}
+}
+
+// relayable returns true if the message is gossip that may be relayed over
+// the bridge.
+func (b *GossipBridge) relayable(msg lnwire.Message) bool {
+	switch m := msg.(type) {
+	case *lnwire.ChannelAnnouncement1:
+		return bytes.Equal(m.ChainHash[:], b.chainHash[:])
+
+	case *lnwire.ChannelUpdate1:
+		return bytes.Equal(m.ChainHash[:], b.chainHash[:])
+
+	case *lnwire.NodeAnnouncement:
+		return true
+
+	default:
+		return false
+	}
+}
+
+// bridgePeer is the pseudo peer announcements injected through the gossip
+// bridge are attributed to. Messages the gossiper sends to it in reply are
+// dropped.
+type bridgePeer struct {
+	identity *btcec.PublicKey
+	quit     chan struct{}
+}
+
+// A compile-time check to ensure bridgePeer implements the lnpeer.Peer
+// interface.
+var _ lnpeer.Peer = (*bridgePeer)(nil)
+
+// SendMessage drops the messages, as there is no remote end to send them to.
+func (p *bridgePeer) SendMessage(bool, ...lnwire.Message) error {
+	return nil
+}
+
+// SendMessageLazy drops the messages, as there is no remote end to send them
+// to.
+func (p *bridgePeer) SendMessageLazy(bool, ...lnwire.Message) error {
+	return nil
+}
+
+// AddNewChannel is not supported by the bridge peer.
+func (p *bridgePeer) AddNewChannel(*lnpeer.NewChannel, <-chan struct{}) error {
+	return ErrUnsupportedBridgeMsg
+}
+
+// AddPendingChannel is not supported by the bridge peer.
+func (p *bridgePeer) AddPendingChannel(lnwire.ChannelID,
+	<-chan struct{}) error {
+
+	return ErrUnsupportedBridgeMsg
+}
+
+// RemovePendingChannel is not supported by the bridge peer.
+func (p *bridgePeer) RemovePendingChannel(lnwire.ChannelID) error {
+	return ErrUnsupportedBridgeMsg
+}
+
+// WipeChannel is a no-op for the bridge peer.
+func (p *bridgePeer) WipeChannel(*wire.OutPoint) {}
+
+// PubKey returns the serialized identity of the bridge.
+func (p *bridgePeer) PubKey() [33]byte {
+	var pubKey [33]byte
+	copy(pubKey[:], p.identity.SerializeCompressed())
+
+	return pubKey
+}
+
+// IdentityKey returns the identity of the bridge.
+func (p *bridgePeer) IdentityKey() *btcec.PublicKey {
+	return p.identity
+}
+
+// Address returns nil, as the bridge peer has no network address.
+func (p *bridgePeer) Address() net.Addr {
+	return nil
+}
+
+// QuitSignal is closed once the bridge is stopped.
+func (p *bridgePeer) QuitSignal() <-chan struct{} {
+	return p.quit
+}
+
+// LocalFeatures returns an empty feature vector.
+func (p *bridgePeer) LocalFeatures() *lnwire.FeatureVector {
+	return lnwire.EmptyFeatureVector()
+}
+
+// RemoteFeatures returns an empty feature vector.
+func (p *bridgePeer) RemoteFeatures() *lnwire.FeatureVector {
+	return lnwire.EmptyFeatureVector()
+}
+
+// Disconnect is a no-op, the bridge can't be disconnected.
+func (p *bridgePeer) Disconnect(error) {}
@@ -0,0 +1,84 @@
+package discovery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/lightningnetwork/lnd/lnpeer"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGossipBridge tests that the gossip bridge only relays gossip for its
+// chain, and that injected announcements are attributed to the bridge peer.
+func TestGossipBridge(t *testing.T) {
+	t.Parallel()
+
+	chainHash := *chaincfg.RegressionNetParams.GenesisHash
+
+	var injected []lnwire.Message
+	processAnn := func(msg lnwire.Message, peer lnpeer.Peer) chan error {
+		require.IsType(t, &bridgePeer{}, peer)
+		injected = append(injected, msg)
+
+		errChan := make(chan error, 1)
+		errChan <- nil
+
+		return errChan
+	}
+
+	bridge := newGossipBridge(chainHash, processAnn)
+	require.NoError(t, bridge.start())
+	t.Cleanup(func() {
+		require.NoError(t, bridge.stop())
+	})
+
+	sub, err := bridge.SubscribeAnnouncements()
+	require.NoError(t, err)
+
+	nodeAnn := &lnwire.NodeAnnouncement{Timestamp: 1}
+	chanUpdate := &lnwire.ChannelUpdate1{ChainHash: chainHash}
+	otherChainUpdate := &lnwire.ChannelUpdate1{
+		ChainHash: *chaincfg.MainNetParams.GenesisHash,
+	}
+
+	// Only gossip for the bridge's chain can be injected.
+	require.NoError(t, bridge.InjectAnnouncement(nodeAnn))
+	require.NoError(t, bridge.InjectAnnouncement(chanUpdate))
+	require.ErrorIs(
+		t, bridge.InjectAnnouncement(otherChainUpdate),
+		ErrUnsupportedBridgeMsg,
+	)
+	require.ErrorIs(
+		t, bridge.InjectAnnouncement(&lnwire.AnnounceSignatures1{}),
+		ErrUnsupportedBridgeMsg,
+	)
+	require.Equal(t, []lnwire.Message{nodeAnn, chanUpdate}, injected)
+
+	// The same filter applies to exported announcements.
+	bridge.export([]networkMsg{
+		{msg: otherChainUpdate},
+		{msg: &lnwire.AnnounceSignatures1{}},
+		{msg: nodeAnn},
+		{msg: chanUpdate},
+	})
+
+	for _, expected := range []lnwire.Message{nodeAnn, chanUpdate} {
+		select {
+		case update := <-sub.Updates():
+			require.Equal(t, expected, update)
+
+		case <-time.After(time.Second):
+			t.Fatalf("expected %v to be exported",
+				expected.MsgType())
+		}
+	}
+
+	select {
+	case update := <-sub.Updates():
+		t.Fatalf("unexpected export: %v", update)
+
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,266 @@
+package discovery
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"golang.org/x/time/rate"
+)
+
+// EgressConfig configures how much gossip we proactively send to our peers,
+// i.e. the announcements we flood to them and the backlog we send them once
+// they set their update horizon. Replies to the queries of our peers are
+// rate limited separately.
+type EgressConfig struct {
+	// MaxBytesPerSec is the budget in bytes per second shared by all
+	// peers. Zero disables the global limit.
+	MaxBytesPerSec uint64
+
+	// MaxBurstBytes is the number of bytes that may be sent to all peers
+	// at once before the global limit kicks in.
+	MaxBurstBytes uint64
+
+	// PeerBytesPerSec is the budget in bytes per second of each peer.
+	// Zero disables the per-peer limit.
+	PeerBytesPerSec uint64
+
+	// PeerBurstBytes is the number of bytes that may be sent to a single
+	// peer at once before the per-peer limit kicks in.
+	PeerBurstBytes uint64
+
+	// PassivePeers are the peers we never flood gossip to. We'll still
+	// reply to their queries.
+	PassivePeers []route.Vertex
+}
+
+// Validate checks that the burst sizes of the enabled limits can fit any
+// gossip message.
+func (c *EgressConfig) Validate() error {
+	if c.MaxBytesPerSec > 0 && c.MaxBurstBytes < lnwire.MaxMsgBody {
+		return fmt.Errorf("global gossip egress burst must be at "+
+			"least %d bytes", lnwire.MaxMsgBody)
+	}
+
+	if c.PeerBytesPerSec > 0 && c.PeerBurstBytes < lnwire.MaxMsgBody {
+		return fmt.Errorf("per-peer gossip egress burst must be at "+
+			"least %d bytes", lnwire.MaxMsgBody)
+	}
+
+	return nil
+}
+
+// egressScheduler enforces the EgressConfig. It holds a token bucket shared
+// by all peers and one for each peer, which are drained by the size of each
+// message sent. The config can be updated at runtime.
+type egressScheduler struct {
+	mu sync.RWMutex
+
+	cfg EgressConfig
+
+	// global is the token bucket shared by all peers, or nil if there's no
+	// global limit.
+	global *rate.Limiter
+
+	// peers are the token buckets of the peers, created on demand. It's
+	// empty if there's no per-peer limit.
+	peers map[route.Vertex]*rate.Limiter
+
+	// passive is the set of peers we never flood gossip to.
+	passive map[route.Vertex]struct{}
+}
+
+// newEgressScheduler creates a scheduler enforcing the given config, which
+// must be valid.
+func newEgressScheduler(cfg EgressConfig) *egressScheduler {
+	e := &egressScheduler{
+		peers: make(map[route.Vertex]*rate.Limiter),
+	}
+	e.apply(cfg)
+
+	return e
+}
+
+// update replaces the config enforced by the scheduler. Updated limits apply
+// to the messages waiting to be sent right away.
+func (e *egressScheduler) update(cfg EgressConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.apply(cfg)
+
+	log.Infof("Updated gossip egress config: max_bytes_per_sec=%d, "+
+		"max_burst_bytes=%d, peer_bytes_per_sec=%d, "+
+		"peer_burst_bytes=%d, passive_peers=%d", cfg.MaxBytesPerSec,
+		cfg.MaxBurstBytes, cfg.PeerBytesPerSec, cfg.PeerBurstBytes,
+		len(cfg.PassivePeers))
+
+	return nil
+}
+
+// apply sets up the token buckets and passive set for the given config.
+//
+// NOTE: The mutex must be held.
+func (e *egressScheduler) apply(cfg EgressConfig) {
+	e.cfg = cfg
+
+	switch {
+	case cfg.MaxBytesPerSec == 0:
+		e.global = nil
+
+	case e.global == nil:
+		e.global = rate.NewLimiter(
+			rate.Limit(cfg.MaxBytesPerSec), int(cfg.MaxBurstBytes),
+		)
+
+	default:
+		e.global.SetLimit(rate.Limit(cfg.MaxBytesPerSec))
+		e.global.SetBurst(int(cfg.MaxBurstBytes))
+	}
+
+	// The buckets of the peers are recreated on demand if we no longer
+	// have a per-peer limit.
+	if cfg.PeerBytesPerSec == 0 {
+		e.peers = make(map[route.Vertex]*rate.Limiter)
+	}
+	for _, limiter := range e.peers {
+		limiter.SetLimit(rate.Limit(cfg.PeerBytesPerSec))
+		limiter.SetBurst(int(cfg.PeerBurstBytes))
+	}
+
+	e.passive = make(map[route.Vertex]struct{}, len(cfg.PassivePeers))
+	for _, peer := range cfg.PassivePeers {
+		e.passive[peer] = struct{}{}
+	}
+}
+
+// config returns the config enforced by the scheduler.
+func (e *egressScheduler) config() EgressConfig {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	cfg := e.cfg
+	cfg.PassivePeers = append([]route.Vertex(nil), e.cfg.PassivePeers...)
+
+	return cfg
+}
+
+// limited returns true if any limit is enforced.
+func (e *egressScheduler) limited() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.cfg.MaxBytesPerSec > 0 || e.cfg.PeerBytesPerSec > 0
+}
+
+// isPassive returns true if we shouldn't flood gossip to the peer.
+func (e *egressScheduler) isPassive(peer route.Vertex) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	_, ok := e.passive[peer]
+
+	return ok
+}
+
+// passivePeers returns the set of peers we shouldn't flood gossip to.
+func (e *egressScheduler) passivePeers() map[route.Vertex]struct{} {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	passive := make(map[route.Vertex]struct{}, len(e.passive))
+	for peer := range e.passive {
+		passive[peer] = struct{}{}
+	}
+
+	return passive
+}
+
+// limiters returns the token buckets that sending to the peer drains.
+func (e *egressScheduler) limiters(peer route.Vertex) []*rate.Limiter {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var limiters []*rate.Limiter
+	if e.cfg.PeerBytesPerSec > 0 {
+		limiter, ok := e.peers[peer]
+		if !ok {
+			limiter = rate.NewLimiter(
+				rate.Limit(e.cfg.PeerBytesPerSec),
+				int(e.cfg.PeerBurstBytes),
+			)
+			e.peers[peer] = limiter
+		}
+
+		limiters = append(limiters, limiter)
+	}
+
+	if e.global != nil {
+		limiters = append(limiters, e.global)
+	}
+
+	return limiters
+}
+
+// wait blocks until the message may be sent to the peer according to the
+// enforced limits.
+func (e *egressScheduler) wait(peer route.Vertex, msg lnwire.Message,
+	quit <-chan struct{}) error {
+
+	limiters := e.limiters(peer)
+	if len(limiters) == 0 {
+		return nil
+	}
+
+	var b bytes.Buffer
+	size, err := lnwire.WriteMessage(&b, msg, 0)
+	if err != nil {
+		return err
+	}
+
+	// We'll first wait for the bucket of the peer, such that a peer
+	// that's over its budget doesn't hold on to the global budget.
+	for _, limiter := range limiters {
+		// A message larger than the burst could never be sent, which
+		// is only possible if the burst was lowered at runtime.
+		n := size
+		if n > limiter.Burst() {
+			n = limiter.Burst()
+		}
+
+		reservation := limiter.ReserveN(time.Now(), n)
+		if !reservation.OK() {
+			return fmt.Errorf("unable to reserve %d bytes of "+
+				"gossip egress", n)
+		}
+
+		delay := reservation.Delay()
+		if delay == 0 {
+			continue
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-quit:
+			reservation.Cancel()
+			return ErrGossipSyncerExiting
+		}
+	}
+
+	return nil
+}
+
+// removePeer discards the token bucket of a disconnected peer.
+func (e *egressScheduler) removePeer(peer route.Vertex) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	delete(e.peers, peer)
+}
@@ -0,0 +1,120 @@
+package discovery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	egressPeerA = route.Vertex{0x02, 0x0a}
+	egressPeerB = route.Vertex{0x02, 0x0b}
+)
+
+// TestEgressConfigValidate tests that limits whose burst can't fit a gossip
+// message are rejected.
+func TestEgressConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, (&EgressConfig{}).Validate())
+	require.Error(t, (&EgressConfig{MaxBytesPerSec: 1}).Validate())
+	require.Error(t, (&EgressConfig{PeerBytesPerSec: 1}).Validate())
+	require.NoError(t, (&EgressConfig{
+		MaxBytesPerSec:  1,
+		MaxBurstBytes:   lnwire.MaxMsgBody,
+		PeerBytesPerSec: 1,
+		PeerBurstBytes:  lnwire.MaxMsgBody,
+	}).Validate())
+}
+
+// TestEgressSchedulerUpdate tests that the passive peers and limits of the
+// scheduler can be updated at runtime.
+func TestEgressSchedulerUpdate(t *testing.T) {
+	t.Parallel()
+
+	e := newEgressScheduler(EgressConfig{
+		PassivePeers: []route.Vertex{egressPeerA},
+	})
+	require.False(t, e.limited())
+	require.True(t, e.isPassive(egressPeerA))
+	require.False(t, e.isPassive(egressPeerB))
+	require.Empty(t, e.limiters(egressPeerA))
+
+	// Invalid configs are rejected and don't replace the current one.
+	err := e.update(EgressConfig{PeerBytesPerSec: 1})
+	require.Error(t, err)
+	require.True(t, e.isPassive(egressPeerA))
+
+	cfg := EgressConfig{
+		PeerBytesPerSec: 1,
+		PeerBurstBytes:  lnwire.MaxMsgBody,
+		PassivePeers:    []route.Vertex{egressPeerB},
+	}
+	require.NoError(t, e.update(cfg))
+	require.Equal(t, cfg, e.config())
+	require.True(t, e.limited())
+	require.False(t, e.isPassive(egressPeerA))
+	require.Equal(t, map[route.Vertex]struct{}{
+		egressPeerB: {},
+	}, e.passivePeers())
+
+	// Each peer gets its own bucket.
+	require.Len(t, e.limiters(egressPeerA), 1)
+	require.Len(t, e.limiters(egressPeerB), 1)
+	require.NotSame(
+		t, e.limiters(egressPeerA)[0], e.limiters(egressPeerB)[0],
+	)
+
+	// Lifting the per-peer limit discards the buckets of the peers.
+	require.NoError(t, e.update(EgressConfig{}))
+	require.False(t, e.limited())
+	require.Empty(t, e.peers)
+}
+
+// TestEgressSchedulerWait tests that sending to a peer is delayed once its
+// budget is spent, without affecting other peers, and that the wait can be
+// aborted.
+func TestEgressSchedulerWait(t *testing.T) {
+	t.Parallel()
+
+	e := newEgressScheduler(EgressConfig{
+		PeerBytesPerSec: 1,
+		PeerBurstBytes:  lnwire.MaxMsgBody,
+	})
+
+	// A message that almost fills the burst of the peer is sent right
+	// away.
+	msg := &lnwire.Error{
+		Data: make([]byte, lnwire.MaxMsgBody-64),
+	}
+	quit := make(chan struct{})
+	require.NoError(t, e.wait(egressPeerA, msg, quit))
+
+	// Other peers still have their budget.
+	require.NoError(t, e.wait(egressPeerB, msg, quit))
+
+	// The next message to the first peer has to wait for its budget to
+	// be replenished, which we abort.
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- e.wait(egressPeerA, msg, quit)
+	}()
+
+	select {
+	case err := <-errChan:
+		t.Fatalf("message sent without budget: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(quit)
+
+	select {
+	case err := <-errChan:
+		require.ErrorIs(t, err, ErrGossipSyncerExiting)
+	case <-time.After(time.Second):
+		t.Fatal("wait not aborted")
+	}
+}
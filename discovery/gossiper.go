@@ -280,6 +280,11 @@ type Config struct {
 	// gossip syncers will be passive.
 	NumActiveSyncers int
 
+	// NumInitialSyncPeers is the number of peers the initial historical
+	// sync is split across. A value below 2 performs the initial
+	// historical sync with a single peer.
+	NumInitialSyncPeers int
+
 	// NoTimestampQueries will prevent the GossipSyncer from querying
 	// timestamps of announcement messages from the peer and from replying
 	// to timestamp queries.
@@ -359,6 +364,15 @@ type Config struct {
 	// updates for a channel and returns true if the channel should be
 	// considered a zombie based on these timestamps.
 	IsStillZombieChannel func(time.Time, time.Time) bool
+
+	// Egress limits the gossip we proactively send to our peers. It can
+	// be updated at runtime through the SyncManager.
+	Egress EgressConfig
+
+	// EnableBridge enables the gossip bridge, which exports accepted
+	// announcements and allows externally obtained announcements to be
+	// injected. It is meant for private test networks.
+	EnableBridge bool
 }
 
 // processedNetworkMsg is a wrapper around networkMsg and a boolean. It is
@@ -503,6 +517,10 @@ type AuthenticatedGossiper struct {
 	// network.
 	reliableSender *reliableSender
 
+	// bridge relays gossip to and from external tooling. It is nil if the
+	// gossip bridge isn't enabled.
+	bridge *GossipBridge
+
 	// chanUpdateRateLimiter contains rate limiters for each direction of
 	// a channel update we've processed. We'll use these to determine
 	// whether we should accept a new update for a specific channel and
@@ -543,11 +561,13 @@ func New(cfg Config, selfKeyDesc *keychain.KeyDescriptor) *AuthenticatedGossiper
 		RotateTicker:            cfg.RotateTicker,
 		HistoricalSyncTicker:    cfg.HistoricalSyncTicker,
 		NumActiveSyncers:        cfg.NumActiveSyncers,
+		NumInitialSyncPeers:     cfg.NumInitialSyncPeers,
 		NoTimestampQueries:      cfg.NoTimestampQueries,
 		IgnoreHistoricalFilters: cfg.IgnoreHistoricalFilters,
 		BestHeight:              gossiper.latestHeight,
 		PinnedSyncers:           cfg.PinnedSyncers,
 		IsStillZombieChannel:    cfg.IsStillZombieChannel,
+		Egress:                  cfg.Egress,
 	})
 
 	gossiper.reliableSender = newReliableSender(&reliableSenderCfg{
@@ -557,6 +577,12 @@ func New(cfg Config, selfKeyDesc *keychain.KeyDescriptor) *AuthenticatedGossiper
 		IsMsgStale:        gossiper.isMsgStale,
 	})
 
+	if cfg.EnableBridge {
+		gossiper.bridge = newGossipBridge(
+			cfg.ChainHash, gossiper.ProcessRemoteAnnouncement,
+		)
+	}
+
 	return gossiper
 }
 
@@ -631,6 +657,12 @@ func (d *AuthenticatedGossiper) start() error {
 
 	d.banman.start()
 
+	if d.bridge != nil {
+		if err := d.bridge.start(); err != nil {
+			return err
+		}
+	}
+
 	// Start receiving blocks in its dedicated goroutine.
 	d.wg.Add(2)
 	go d.syncBlockHeight()
@@ -789,6 +821,12 @@ func (d *AuthenticatedGossiper) stop() {
 
 	d.banman.stop()
 
+	if d.bridge != nil {
+		if err := d.bridge.stop(); err != nil {
+			log.Warnf("Unable to stop gossip bridge: %v", err)
+		}
+	}
+
 	close(d.quit)
 	d.wg.Wait()
 
@@ -1338,7 +1376,10 @@ func (d *AuthenticatedGossiper) sendLocalBatch(annBatch []msgWithSenders) {
 		},
 	)
 
-	err := d.cfg.Broadcast(nil, msgsToSend...)
+	// Gossip-passive peers only receive the announcements they query.
+	passivePeers := d.syncMgr.egress.passivePeers()
+
+	err := d.cfg.Broadcast(passivePeers, msgsToSend...)
 	if err != nil {
 		log.Errorf("Unable to send local batch announcements: %v", err)
 	}
@@ -1348,6 +1389,7 @@ func (d *AuthenticatedGossiper) sendLocalBatch(annBatch []msgWithSenders) {
 // peers.
 func (d *AuthenticatedGossiper) sendRemoteBatch(annBatch []msgWithSenders) {
 	syncerPeers := d.syncMgr.GossipSyncers()
+	passivePeers := d.syncMgr.egress.passivePeers()
 
 	// We'll first attempt to filter out this new message for all peers
 	// that have active gossip syncers active.
@@ -1364,6 +1406,12 @@ func (d *AuthenticatedGossiper) sendRemoteBatch(annBatch []msgWithSenders) {
 		// messages.
 		msgChunk.mergeSyncerMap(syncerPeers)
 
+		// We also skip the peers that are gossip-passive, as they only
+		// receive the announcements they query.
+		for peer := range passivePeers {
+			msgChunk.senders[peer] = struct{}{}
+		}
+
 		err := d.cfg.Broadcast(msgChunk.senders, msgChunk.msg)
 		if err != nil {
 			log.Errorf("Unable to send batch "+
@@ -1448,6 +1496,9 @@ func (d *AuthenticatedGossiper) networkHandler() {
 					len(emittedAnnouncements))
 
 				if emittedAnnouncements != nil {
+					d.exportAnnouncements(
+						emittedAnnouncements,
+					)
 					announcements.AddMsgs(
 						emittedAnnouncements...,
 					)
@@ -1568,6 +1619,10 @@ func (d *AuthenticatedGossiper) handleNetworkMessages(nMsg *networkMsg,
 	// continue.
 	vb.SignalDependants(nMsg.msg, allow)
 
+	// Accepted announcements are exported over the gossip bridge
+	// regardless of whether we broadcast them ourselves.
+	d.exportAnnouncements(newAnns)
+
 	// If the announcement was accepted, then add the emitted announcements
 	// to our announce batch to be broadcast once the trickle timer ticks
 	// gain.
@@ -1754,7 +1809,9 @@ func (d *AuthenticatedGossiper) retransmitStaleAnns(now time.Time) error {
 
 	// With all the wire announcements properly crafted, we'll broadcast
 	// our known outgoing channels to all our immediate peers.
-	if err := d.cfg.Broadcast(nil, signedUpdates...); err != nil {
+	passivePeers := d.syncMgr.egress.passivePeers()
+	err = d.cfg.Broadcast(passivePeers, signedUpdates...)
+	if err != nil {
 		return fmt.Errorf("unable to re-broadcast channels: %w", err)
 	}
 
@@ -1766,14 +1823,45 @@ func (d *AuthenticatedGossiper) retransmitStaleAnns(now time.Time) error {
 func (d *AuthenticatedGossiper) processChanPolicyUpdate(
 	edgesToUpdate []EdgeWithInfo) ([]networkMsg, error) {
 
-	var chanUpdates []networkMsg
+	// Now that we've collected all the channels we need to update, we'll
+	// re-sign all of their updates at once. This allows signers that
+	// support batching, such as a remote signer, to avoid a sequential
+	// round trip per channel.
+	var (
+		updates = make([]*lnwire.ChannelUpdate1, 0, len(edgesToUpdate))
+		msgs    = make([]lnwire.Message, 0, len(edgesToUpdate))
+	)
 	for _, edgeInfo := range edgesToUpdate {
-		// Now that we've collected all the channels we need to update,
-		// we'll re-sign and update the backing ChannelGraphSource, and
-		// retrieve our ChannelUpdate to broadcast.
-		_, chanUpdate, err := d.updateChannel(
+		chanUpdate := netann.UnsignedChannelUpdateFromEdge(
 			edgeInfo.Info, edgeInfo.Edge,
 		)
+		netann.ChanUpdSetTimestamp(chanUpdate)
+
+		updates = append(updates, chanUpdate)
+		msgs = append(msgs, chanUpdate)
+	}
+
+	sigs, err := netann.SignAnnouncements(
+		d.cfg.AnnSigner, d.selfKeyLoc, msgs,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var chanUpdates []networkMsg
+	for i, edgeInfo := range edgesToUpdate {
+		chanUpdate := updates[i]
+		chanUpdate.Signature, err = lnwire.NewSigFromSignature(sigs[i])
+		if err != nil {
+			return nil, err
+		}
+
+		// With the update signed, we'll update the backing
+		// ChannelGraphSource, and retrieve our ChannelUpdate to
+		// broadcast.
+		_, chanUpdate, err = d.applyChannelUpdate(
+			edgeInfo.Info, edgeInfo.Edge, chanUpdate,
+		)
 		if err != nil {
 			return nil, err
 		}
@@ -2240,6 +2328,15 @@ func (d *AuthenticatedGossiper) updateChannel(info *models.ChannelEdgeInfo,
 		return nil, nil, err
 	}
 
+	return d.applyChannelUpdate(info, edge, chanUpdate)
+}
+
+// applyChannelUpdate updates the underlying graph with the state of the passed
+// signed channel update, which must have been created from the given edge.
+func (d *AuthenticatedGossiper) applyChannelUpdate(info *models.ChannelEdgeInfo,
+	edge *models.ChannelEdgePolicy, chanUpdate *lnwire.ChannelUpdate1) (
+	*lnwire.ChannelAnnouncement1, *lnwire.ChannelUpdate1, error) {
+
 	// Next, we'll set the new signature in place, and update the reference
 	// in the backing slice.
 	edge.LastUpdate = time.Unix(int64(chanUpdate.Timestamp), 0)
@@ -2247,7 +2344,7 @@ func (d *AuthenticatedGossiper) updateChannel(info *models.ChannelEdgeInfo,
 
 	// To ensure that our signature is valid, we'll verify it ourself
 	// before committing it to the slice returned.
-	err = netann.ValidateChannelUpdateAnn(
+	err := netann.ValidateChannelUpdateAnn(
 		d.selfKey, info.Capacity, chanUpdate,
 	)
 	if err != nil {
@@ -2310,6 +2407,26 @@ func (d *AuthenticatedGossiper) SyncManager() *SyncManager {
 	return d.syncMgr
 }
 
+// Bridge returns the gossiper's GossipBridge instance, or
+// ErrGossipBridgeDisabled if the bridge isn't enabled.
+func (d *AuthenticatedGossiper) Bridge() (*GossipBridge, error) {
+	if d.bridge == nil {
+		return nil, ErrGossipBridgeDisabled
+	}
+
+	return d.bridge, nil
+}
+
+// exportAnnouncements sends the accepted announcements over the gossip
+// bridge, if it is enabled.
+func (d *AuthenticatedGossiper) exportAnnouncements(anns []networkMsg) {
+	if d.bridge == nil || len(anns) == 0 {
+		return
+	}
+
+	d.bridge.export(anns)
+}
+
 // IsKeepAliveUpdate determines whether this channel update is considered a
 // keep-alive update based on the previous channel update processed for the same
 // direction.
@@ -0,0 +1,581 @@
+package discovery
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+// graphSyncRedundancy is the number of distinct peers we'd like to answer
+// the channel range query of each slice of the chain, such that their
+// answers can be cross-checked.
+const graphSyncRedundancy = 2
+
+// graphSyncPhase denotes the phase a graphSyncSession is in.
+type graphSyncPhase uint8
+
+const (
+	// graphSyncRangePhase is the phase in which the participants query
+	// the slices of the chain for the channels they know of.
+	graphSyncRangePhase graphSyncPhase = iota
+
+	// graphSyncFetchPhase is the phase in which the participants query
+	// the announcements of the reconciled set of new channels.
+	graphSyncFetchPhase
+
+	// graphSyncDone is the terminal phase of the session.
+	graphSyncDone
+)
+
+// graphSyncSlice is a range of blocks queried as part of a graphSyncSession.
+type graphSyncSlice struct {
+	firstHeight uint32
+	numBlocks   uint32
+
+	// queriedBy is the set of peers that answered or are currently
+	// answering the range query for this slice.
+	queriedBy map[route.Vertex]struct{}
+
+	// replies are the channels each peer that finished its query reported
+	// within this slice.
+	replies map[route.Vertex][]channeldb.ChannelUpdateInfo
+}
+
+// graphSyncParticipant is the state of a peer taking part in a
+// graphSyncSession.
+type graphSyncParticipant struct {
+	// slice is the index of the slice the peer is currently querying, or
+	// -1 if there's none.
+	slice int
+
+	// assignment is used to deliver the channels the peer should query at
+	// the end of the range phase.
+	assignment chan []lnwire.ShortChannelID
+
+	// fetched is true once the peer queried its assigned channels.
+	fetched bool
+}
+
+// graphSyncSessionCfg holds the dependencies of a graphSyncSession.
+type graphSyncSessionCfg struct {
+	// chainHash is the chain the session syncs the graph of.
+	chainHash chainhash.Hash
+
+	// chanSeries is used to filter out the channels we already know of.
+	chanSeries ChannelGraphTimeSeries
+
+	// numSlices is the number of slices the chain is split into, which is
+	// also the maximum number of participants.
+	numSlices int
+
+	// bestHeight is the height up to which channels are queried.
+	bestHeight uint32
+
+	// isStillZombieChannel takes the timestamps of the latest channel
+	// updates for a channel and returns true if the channel should be
+	// considered a zombie based on these timestamps.
+	isStillZombieChannel func(time.Time, time.Time) bool
+
+	// markGraphSynced is called once the session completed successfully.
+	markGraphSynced func()
+}
+
+// graphSyncSession coordinates an initial historical sync across multiple
+// peers. The chain is split into slices, each of which is queried for its
+// channels by up to graphSyncRedundancy peers in parallel. Peers may join
+// while slices are still left to be queried, so the sync is started as soon
+// as the first peer connects. Once all slices are answered, the replies are
+// reconciled: channels reported by any peer are considered, and the
+// announcements of each new channel are queried from a peer that reported
+// its most recent updates, spreading the load across all participants.
+// That way we neither depend on a single peer to tell us about all channels,
+// nor do we download the whole graph from it.
+type graphSyncSession struct {
+	cfg graphSyncSessionCfg
+
+	mu sync.Mutex
+
+	phase graphSyncPhase
+
+	slices []*graphSyncSlice
+
+	participants map[route.Vertex]*graphSyncParticipant
+
+	// complete is true if the session ended with all new channels having
+	// been queried.
+	complete bool
+
+	done chan struct{}
+}
+
+// newGraphSyncSession creates a new session splitting the chain into the
+// configured number of slices.
+func newGraphSyncSession(cfg graphSyncSessionCfg) *graphSyncSession {
+	// We query the same range as a regular historical sync would.
+	numBlocks := cfg.bestHeight
+	if numBlocks < 1 {
+		numBlocks = 1
+	}
+
+	numSlices := uint32(cfg.numSlices)
+	if numSlices < 1 {
+		numSlices = 1
+	}
+	if numSlices > numBlocks {
+		numSlices = numBlocks
+	}
+	cfg.numSlices = int(numSlices)
+
+	sliceSize := numBlocks / numSlices
+	slices := make([]*graphSyncSlice, 0, numSlices)
+	for i := uint32(0); i < numSlices; i++ {
+		slice := &graphSyncSlice{
+			firstHeight: i * sliceSize,
+			numBlocks:   sliceSize,
+			queriedBy:   make(map[route.Vertex]struct{}),
+			replies: make(
+				map[route.Vertex][]channeldb.ChannelUpdateInfo,
+			),
+		}
+
+		// The last slice covers the remainder of the range.
+		if i == numSlices-1 {
+			slice.numBlocks = numBlocks - slice.firstHeight
+		}
+
+		slices = append(slices, slice)
+	}
+
+	return &graphSyncSession{
+		cfg:          cfg,
+		slices:       slices,
+		participants: make(map[route.Vertex]*graphSyncParticipant),
+		done:         make(chan struct{}),
+	}
+}
+
+// join adds the peer to the session. It returns false if the peer can't take
+// part as the range phase is over or the session already has enough
+// participants.
+func (s *graphSyncSession) join(peer route.Vertex) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.phase != graphSyncRangePhase ||
+		len(s.participants) >= s.cfg.numSlices {
+
+		return false
+	}
+
+	if _, ok := s.participants[peer]; ok {
+		return false
+	}
+
+	s.participants[peer] = &graphSyncParticipant{
+		slice:      -1,
+		assignment: make(chan []lnwire.ShortChannelID, 1),
+	}
+
+	log.Debugf("Peer %v joined initial graph sync (participants=%d)",
+		peer, len(s.participants))
+
+	return true
+}
+
+// nextRange returns the next range of blocks the peer should query for
+// channels. If there's none left for the peer, false is returned and the
+// peer should wait for its assignment.
+func (s *graphSyncSession) nextRange(peer route.Vertex) (uint32, uint32,
+	bool) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.participants[peer]
+	if !ok || s.phase != graphSyncRangePhase {
+		return 0, 0, false
+	}
+
+	idx := s.availableSlice(peer)
+	if idx < 0 {
+		s.maybeReconcile()
+		return 0, 0, false
+	}
+
+	slice := s.slices[idx]
+	slice.queriedBy[peer] = struct{}{}
+	p.slice = idx
+
+	return slice.firstHeight, slice.numBlocks, true
+}
+
+// availableSlice returns the index of the least queried slice the peer
+// hasn't queried yet, or -1 if there's none.
+//
+// NOTE: The mutex must be held.
+func (s *graphSyncSession) availableSlice(peer route.Vertex) int {
+	best := -1
+	for i, slice := range s.slices {
+		if len(slice.queriedBy) >= graphSyncRedundancy {
+			continue
+		}
+
+		if _, ok := slice.queriedBy[peer]; ok {
+			continue
+		}
+
+		if best < 0 ||
+			len(slice.queriedBy) < len(s.slices[best].queriedBy) {
+
+			best = i
+		}
+	}
+
+	return best
+}
+
+// rangeDone records the channels the peer reported for the range it was
+// querying.
+func (s *graphSyncSession) rangeDone(peer route.Vertex,
+	infos []channeldb.ChannelUpdateInfo) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.participants[peer]
+	if !ok || p.slice < 0 {
+		return
+	}
+
+	s.slices[p.slice].replies[peer] = infos
+	p.slice = -1
+}
+
+// assignment returns the channel over which the channels the peer should
+// query are delivered once the range phase is over.
+func (s *graphSyncSession) assignment(
+	peer route.Vertex) <-chan []lnwire.ShortChannelID {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.participants[peer]
+	if !ok {
+		return nil
+	}
+
+	return p.assignment
+}
+
+// fetchDone marks the assigned channels of the peer as queried.
+func (s *graphSyncSession) fetchDone(peer route.Vertex) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.participants[peer]
+	if !ok || s.phase != graphSyncFetchPhase {
+		return
+	}
+
+	p.fetched = true
+	s.maybeFinish()
+}
+
+// removePeer removes a peer that disconnected or whose syncer failed from
+// the session. A range it was querying is handed to the remaining
+// participants. If it didn't query its assigned channels yet, the session
+// won't complete.
+func (s *graphSyncSession) removePeer(peer route.Vertex) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.participants[peer]
+	if !ok {
+		return
+	}
+	delete(s.participants, peer)
+
+	log.Debugf("Peer %v left initial graph sync in phase %d", peer,
+		s.phase)
+
+	switch s.phase {
+	case graphSyncRangePhase:
+		if p.slice >= 0 {
+			delete(s.slices[p.slice].queriedBy, peer)
+		}
+
+		if len(s.participants) == 0 {
+			s.finish(false)
+			return
+		}
+
+		// The peer's replies to previous ranges are kept, but another
+		// participant may now be able to take over its current range.
+		// If they're all waiting already, we're done querying.
+		s.maybeReconcile()
+
+	case graphSyncFetchPhase:
+		if !p.fetched {
+			s.complete = false
+			log.Infof("Peer %v disconnected before querying its "+
+				"share of new channels", peer)
+		}
+
+		s.maybeFinish()
+	}
+}
+
+// maybeReconcile ends the range phase if no participant is querying a range
+// and none of them can query another one.
+//
+// NOTE: The mutex must be held.
+func (s *graphSyncSession) maybeReconcile() {
+	if s.phase != graphSyncRangePhase {
+		return
+	}
+
+	for peer, p := range s.participants {
+		if p.slice >= 0 || s.availableSlice(peer) >= 0 {
+			return
+		}
+	}
+
+	s.reconcile()
+}
+
+// chanReport is the reconciled view of a channel reported by at least one
+// peer.
+type chanReport struct {
+	// info carries the most recent timestamps reported for each direction
+	// of the channel.
+	info channeldb.ChannelUpdateInfo
+
+	// reporters are the peers that reported the channel along with the
+	// timestamps they reported.
+	reporters map[route.Vertex]channeldb.ChannelUpdateInfo
+}
+
+// newChanReport creates a report for a channel from its first reply.
+func newChanReport(info channeldb.ChannelUpdateInfo) *chanReport {
+	return &chanReport{
+		info:      info,
+		reporters: make(map[route.Vertex]channeldb.ChannelUpdateInfo),
+	}
+}
+
+// reconcile merges the replies of all participants and assigns each new
+// channel to a participant to query it from.
+//
+// NOTE: The mutex must be held.
+func (s *graphSyncSession) reconcile() {
+	reports := make(map[lnwire.ShortChannelID]*chanReport)
+
+	var numMissing, numDiff int
+	for _, slice := range s.slices {
+		for peer, infos := range slice.replies {
+			for _, info := range infos {
+				report, ok := reports[info.ShortChannelID]
+				if !ok {
+					report = newChanReport(info)
+					reports[info.ShortChannelID] = report
+				}
+				report.reporters[peer] = info
+
+				// We keep the most recent timestamps of each
+				// direction, such that we don't consider a
+				// channel a zombie just because one peer is
+				// lagging behind.
+				if info.Node1UpdateTimestamp.After(
+					report.info.Node1UpdateTimestamp,
+				) {
+
+					report.info.Node1UpdateTimestamp =
+						info.Node1UpdateTimestamp
+				}
+				if info.Node2UpdateTimestamp.After(
+					report.info.Node2UpdateTimestamp,
+				) {
+
+					report.info.Node2UpdateTimestamp =
+						info.Node2UpdateTimestamp
+				}
+			}
+		}
+
+		// Any channel within the slice not reported by all of the
+		// peers that answered for it is a discrepancy worth noting.
+		for scid, report := range reports {
+			height := scid.BlockHeight
+			if height < slice.firstHeight ||
+				height >= slice.firstHeight+slice.numBlocks {
+
+				continue
+			}
+
+			if len(report.reporters) < len(slice.replies) {
+				numMissing++
+				log.Tracef("Channel %v not reported by all "+
+					"peers for blocks %d-%d", scid,
+					slice.firstHeight,
+					slice.firstHeight+slice.numBlocks-1)
+			}
+
+			for _, info := range report.reporters {
+				if !sameTimestamps(info, report.info) {
+					numDiff++
+					break
+				}
+			}
+		}
+	}
+
+	superSet := make([]channeldb.ChannelUpdateInfo, 0, len(reports))
+	for _, report := range reports {
+		superSet = append(superSet, report.info)
+	}
+
+	log.Infof("Reconciling %d channels reported by %d peers: %d not "+
+		"reported by all peers, %d with differing updates",
+		len(superSet), len(s.participants), numMissing, numDiff)
+
+	newChans, err := s.cfg.chanSeries.FilterKnownChanIDs(
+		s.cfg.chainHash, superSet, s.cfg.isStillZombieChannel,
+	)
+	if err != nil {
+		log.Errorf("Unable to filter chan ids: %v", err)
+		s.finish(false)
+
+		return
+	}
+
+	s.phase = graphSyncFetchPhase
+
+	// We'll query each new channel from a peer that reported its most
+	// recent updates, choosing the one with the least work among them to
+	// spread the load. If none of them is still around, any participant
+	// will do.
+	assignments := make(
+		map[route.Vertex][]lnwire.ShortChannelID, len(s.participants),
+	)
+	for peer := range s.participants {
+		assignments[peer] = nil
+	}
+
+	leastLoaded := func(candidates []route.Vertex) route.Vertex {
+		sort.Slice(candidates, func(i, j int) bool {
+			li := len(assignments[candidates[i]])
+			lj := len(assignments[candidates[j]])
+			if li != lj {
+				return li < lj
+			}
+
+			return string(candidates[i][:]) <
+				string(candidates[j][:])
+		})
+
+		return candidates[0]
+	}
+
+	for _, scid := range newChans {
+		var upToDate, reporters []route.Vertex
+		if report, ok := reports[scid]; ok {
+			for peer, info := range report.reporters {
+				if _, ok := s.participants[peer]; !ok {
+					continue
+				}
+
+				reporters = append(reporters, peer)
+				if sameTimestamps(info, report.info) {
+					upToDate = append(upToDate, peer)
+				}
+			}
+		}
+
+		candidates := upToDate
+		if len(candidates) == 0 {
+			candidates = reporters
+		}
+		if len(candidates) == 0 {
+			for peer := range s.participants {
+				candidates = append(candidates, peer)
+			}
+		}
+
+		peer := leastLoaded(candidates)
+		assignments[peer] = append(assignments[peer], scid)
+	}
+
+	log.Infof("Querying %d new channels from %d peers", len(newChans),
+		len(assignments))
+
+	s.complete = true
+	for peer, scids := range assignments {
+		sort.Slice(scids, func(i, j int) bool {
+			return scids[i].ToUint64() < scids[j].ToUint64()
+		})
+
+		log.Debugf("Assigning %d new channels to peer %v",
+			len(scids), peer)
+
+		s.participants[peer].assignment <- scids
+	}
+}
+
+// maybeFinish ends the session once all participants queried their assigned
+// channels.
+//
+// NOTE: The mutex must be held.
+func (s *graphSyncSession) maybeFinish() {
+	for _, p := range s.participants {
+		if !p.fetched {
+			return
+		}
+	}
+
+	s.finish(s.complete)
+}
+
+// finish ends the session.
+//
+// NOTE: The mutex must be held.
+func (s *graphSyncSession) finish(complete bool) {
+	if s.phase == graphSyncDone {
+		return
+	}
+
+	s.phase = graphSyncDone
+	s.complete = complete
+
+	if complete {
+		log.Info("Initial graph sync across multiple peers completed")
+		s.cfg.markGraphSynced()
+	}
+
+	close(s.done)
+}
+
+// finished returns a channel that is closed once the session has ended.
+func (s *graphSyncSession) finished() <-chan struct{} {
+	return s.done
+}
+
+// isComplete returns true if the session ended with all new channels having
+// been queried.
+func (s *graphSyncSession) isComplete() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.complete
+}
+
+// sameTimestamps returns true if both infos carry the same update
+// timestamps.
+func sameTimestamps(a, b channeldb.ChannelUpdateInfo) bool {
+	return a.Node1UpdateTimestamp.Equal(b.Node1UpdateTimestamp) &&
+		a.Node2UpdateTimestamp.Equal(b.Node2UpdateTimestamp)
+}
@@ -0,0 +1,271 @@
+package discovery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	sessionPeerA = route.Vertex{0x02, 0x0a}
+	sessionPeerB = route.Vertex{0x02, 0x0b}
+)
+
+// newTestGraphSyncSession creates a session splitting the blocks up to the
+// given height into numSlices slices. The returned flag is set once the
+// session marks the graph as synced.
+func newTestGraphSyncSession(numSlices int, bestHeight uint32) (
+	*graphSyncSession, *mockChannelGraphTimeSeries, *bool) {
+
+	chanSeries := newMockChannelGraphTimeSeries(lnwire.ShortChannelID{})
+
+	var synced bool
+	session := newGraphSyncSession(graphSyncSessionCfg{
+		chanSeries: chanSeries,
+		numSlices:  numSlices,
+		bestHeight: bestHeight,
+		isStillZombieChannel: func(time.Time, time.Time) bool {
+			return false
+		},
+		markGraphSynced: func() {
+			synced = true
+		},
+	})
+
+	return session, chanSeries, &synced
+}
+
+// chanInfo creates the range reply for a channel at the given height with
+// both update timestamps set to the given unix time.
+func chanInfo(height uint32, timestamp int64) channeldb.ChannelUpdateInfo {
+	return channeldb.NewChannelUpdateInfo(
+		lnwire.ShortChannelID{BlockHeight: height},
+		time.Unix(timestamp, 0), time.Unix(timestamp, 0),
+	)
+}
+
+// requireNextRange asserts the next range the session hands to the peer.
+func requireNextRange(t *testing.T, session *graphSyncSession,
+	peer route.Vertex, firstHeight, numBlocks uint32) {
+
+	t.Helper()
+
+	first, num, ok := session.nextRange(peer)
+	require.True(t, ok)
+	require.Equal(t, firstHeight, first)
+	require.Equal(t, numBlocks, num)
+}
+
+// requireAssignment asserts the channels assigned to the peer.
+func requireAssignment(t *testing.T, session *graphSyncSession,
+	peer route.Vertex, heights ...uint32) {
+
+	t.Helper()
+
+	var expected []lnwire.ShortChannelID
+	for _, height := range heights {
+		expected = append(
+			expected, lnwire.ShortChannelID{BlockHeight: height},
+		)
+	}
+
+	select {
+	case scids := <-session.assignment(peer):
+		require.Equal(t, expected, scids)
+
+	case <-time.After(time.Second):
+		t.Fatalf("no assignment for peer %v", peer)
+	}
+}
+
+// TestGraphSyncSessionSlices tests that the chain is split into the expected
+// slices.
+func TestGraphSyncSessionSlices(t *testing.T) {
+	t.Parallel()
+
+	session, _, _ := newTestGraphSyncSession(3, 1000)
+	require.Len(t, session.slices, 3)
+	require.EqualValues(t, 0, session.slices[0].firstHeight)
+	require.EqualValues(t, 333, session.slices[0].numBlocks)
+	require.EqualValues(t, 333, session.slices[1].firstHeight)
+	require.EqualValues(t, 333, session.slices[1].numBlocks)
+	require.EqualValues(t, 666, session.slices[2].firstHeight)
+	require.EqualValues(t, 334, session.slices[2].numBlocks)
+
+	// We never create more slices than there are blocks.
+	session, _, _ = newTestGraphSyncSession(3, 2)
+	require.Len(t, session.slices, 2)
+
+	// The number of participants is limited by the number of slices.
+	require.True(t, session.join(sessionPeerA))
+	require.False(t, session.join(sessionPeerA))
+	require.True(t, session.join(sessionPeerB))
+	require.False(t, session.join(route.Vertex{0x02, 0x0c}))
+}
+
+// TestGraphSyncSessionReconcile tests that each slice is queried from two
+// peers, and that the new channels are assigned to the peers that reported
+// their most recent updates while spreading the load.
+func TestGraphSyncSessionReconcile(t *testing.T) {
+	t.Parallel()
+
+	session, chanSeries, synced := newTestGraphSyncSession(2, 1000)
+	require.True(t, session.join(sessionPeerA))
+	require.True(t, session.join(sessionPeerB))
+
+	// Each peer first queries a different slice.
+	requireNextRange(t, session, sessionPeerA, 0, 500)
+	requireNextRange(t, session, sessionPeerB, 500, 500)
+
+	session.rangeDone(sessionPeerA, []channeldb.ChannelUpdateInfo{
+		chanInfo(100, 1000), chanInfo(200, 1000),
+	})
+	session.rangeDone(sessionPeerB, []channeldb.ChannelUpdateInfo{
+		chanInfo(600, 1000), chanInfo(800, 1000),
+	})
+
+	// Then they cross-check the slice of the other one.
+	requireNextRange(t, session, sessionPeerA, 500, 500)
+	requireNextRange(t, session, sessionPeerB, 0, 500)
+
+	// Peer B doesn't know of channel 100, peer A has a more recent update
+	// for channel 600 and knows of channel 700, which peer B doesn't.
+	session.rangeDone(sessionPeerA, []channeldb.ChannelUpdateInfo{
+		chanInfo(600, 2000), chanInfo(700, 1000), chanInfo(800, 1000),
+	})
+	session.rangeDone(sessionPeerB, []channeldb.ChannelUpdateInfo{
+		chanInfo(200, 1000),
+	})
+
+	// All slices are answered twice, so the replies are reconciled once
+	// the peers ask for their next range. We don't know any of the
+	// channels yet.
+	chanSeries.filterResp <- []lnwire.ShortChannelID{
+		{BlockHeight: 100}, {BlockHeight: 200}, {BlockHeight: 600},
+		{BlockHeight: 700}, {BlockHeight: 800},
+	}
+	_, _, ok := session.nextRange(sessionPeerB)
+	require.False(t, ok)
+	_, _, ok = session.nextRange(sessionPeerA)
+	require.False(t, ok)
+
+	superSet := <-chanSeries.filterReq
+	require.Len(t, superSet, 5)
+	for _, info := range superSet {
+		if info.ShortChannelID.BlockHeight != 600 {
+			continue
+		}
+
+		// The most recent timestamps are used to filter the channels.
+		require.Equal(t, time.Unix(2000, 0), info.Node1UpdateTimestamp)
+	}
+
+	// Channels 100, 600 and 700 are queried from peer A, as peer B either
+	// doesn't know them or has an outdated update. Channels 200 and 800
+	// are known by both, so they go to the peer with less work.
+	requireAssignment(t, session, sessionPeerA, 100, 600, 700)
+	requireAssignment(t, session, sessionPeerB, 200, 800)
+
+	// The graph is synced once both peers queried their share.
+	session.fetchDone(sessionPeerA)
+	require.False(t, *synced)
+
+	session.fetchDone(sessionPeerB)
+	<-session.finished()
+	require.True(t, session.isComplete())
+	require.True(t, *synced)
+}
+
+// TestGraphSyncSessionPeerLeaves tests that the range of a peer leaving the
+// session is taken over by the remaining participants, and that the session
+// doesn't complete if a peer leaves before querying its share.
+func TestGraphSyncSessionPeerLeaves(t *testing.T) {
+	t.Parallel()
+
+	session, chanSeries, synced := newTestGraphSyncSession(2, 1000)
+	require.True(t, session.join(sessionPeerA))
+	require.True(t, session.join(sessionPeerB))
+
+	requireNextRange(t, session, sessionPeerA, 0, 500)
+	requireNextRange(t, session, sessionPeerB, 500, 500)
+
+	// Peer B leaves while querying its slice, so peer A takes it over.
+	session.removePeer(sessionPeerB)
+
+	session.rangeDone(sessionPeerA, []channeldb.ChannelUpdateInfo{
+		chanInfo(100, 1000),
+	})
+	requireNextRange(t, session, sessionPeerA, 500, 500)
+	session.rangeDone(sessionPeerA, []channeldb.ChannelUpdateInfo{
+		chanInfo(600, 1000),
+	})
+
+	// A new peer can still join and cross-check the slices.
+	peerC := route.Vertex{0x02, 0x0c}
+	require.True(t, session.join(peerC))
+
+	_, _, ok := session.nextRange(sessionPeerA)
+	require.False(t, ok)
+
+	requireNextRange(t, session, peerC, 0, 500)
+	session.rangeDone(peerC, []channeldb.ChannelUpdateInfo{
+		chanInfo(100, 1000),
+	})
+	requireNextRange(t, session, peerC, 500, 500)
+	session.rangeDone(peerC, []channeldb.ChannelUpdateInfo{
+		chanInfo(600, 1000),
+	})
+
+	chanSeries.filterResp <- []lnwire.ShortChannelID{
+		{BlockHeight: 100}, {BlockHeight: 600},
+	}
+	_, _, ok = session.nextRange(peerC)
+	require.False(t, ok)
+	require.Len(t, <-chanSeries.filterReq, 2)
+
+	// Both channels are known by both peers, so each gets one.
+	select {
+	case scids := <-session.assignment(sessionPeerA):
+		require.Len(t, scids, 1)
+	case <-time.After(time.Second):
+		t.Fatal("no assignment for peer A")
+	}
+	select {
+	case scids := <-session.assignment(peerC):
+		require.Len(t, scids, 1)
+	case <-time.After(time.Second):
+		t.Fatal("no assignment for peer C")
+	}
+
+	// Peer C leaves before querying its share, so the session ends
+	// without marking the graph as synced.
+	session.fetchDone(sessionPeerA)
+	session.removePeer(peerC)
+
+	<-session.finished()
+	require.False(t, session.isComplete())
+	require.False(t, *synced)
+}
+
+// TestGraphSyncSessionAllPeersLeave tests that the session ends if all
+// participants leave during the range phase.
+func TestGraphSyncSessionAllPeersLeave(t *testing.T) {
+	t.Parallel()
+
+	session, _, synced := newTestGraphSyncSession(2, 1000)
+	require.True(t, session.join(sessionPeerA))
+	requireNextRange(t, session, sessionPeerA, 0, 500)
+
+	session.removePeer(sessionPeerA)
+
+	<-session.finished()
+	require.False(t, session.isComplete())
+	require.False(t, *synced)
+
+	// No new peers can join an ended session.
+	require.False(t, session.join(sessionPeerB))
+}
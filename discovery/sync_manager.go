@@ -76,6 +76,12 @@ type SyncManagerCfg struct {
 	// gossip syncers will be passive.
 	NumActiveSyncers int
 
+	// NumInitialSyncPeers is the number of peers the initial historical
+	// sync is split across. Their replies are cross-checked such that we
+	// don't rely on a single peer to learn about all channels. A value
+	// below 2 performs the initial historical sync with a single peer.
+	NumInitialSyncPeers int
+
 	// NoTimestampQueries will prevent the GossipSyncer from querying
 	// timestamps of announcement messages from the peer and from responding
 	// to timestamp queries
@@ -110,6 +116,9 @@ type SyncManagerCfg struct {
 	// updates for a channel and returns true if the channel should be
 	// considered a zombie based on these timestamps.
 	IsStillZombieChannel func(time.Time, time.Time) bool
+
+	// Egress limits the gossip we proactively send to our peers.
+	Egress EgressConfig
 }
 
 // SyncManager is a subsystem of the gossiper that manages the gossip syncers
@@ -168,6 +177,10 @@ type SyncManager struct {
 	// queries.
 	gossipFilterSema chan struct{}
 
+	// egress limits the gossip the syncers proactively send to their
+	// peers.
+	egress *egressScheduler
+
 	wg   sync.WaitGroup
 	quit chan struct{}
 }
@@ -192,6 +205,7 @@ func newSyncManager(cfg *SyncManagerCfg) *SyncManager {
 			map[route.Vertex]*GossipSyncer, len(cfg.PinnedSyncers),
 		),
 		gossipFilterSema: filterSema,
+		egress:           newEgressScheduler(cfg.Egress),
 		quit:             make(chan struct{}),
 	}
 }
@@ -252,6 +266,14 @@ func (m *SyncManager) syncerHandler() {
 		// attempted just because the initialHistoricalSyncer was
 		// disconnected.
 		initialHistoricalSyncSignal chan struct{}
+
+		// graphSync is the session coordinating the initial
+		// historical sync across multiple peers, if any.
+		graphSync *graphSyncSession
+
+		// graphSyncDone is a signal that will fire once the graphSync
+		// session has ended.
+		graphSyncDone <-chan struct{}
 	)
 
 	setInitialHistoricalSyncer := func(s *GossipSyncer) {
@@ -338,6 +360,16 @@ func (m *SyncManager) syncerHandler() {
 			// internal state has been updated.
 			close(newSyncer.doneChan)
 
+			// If the initial historical sync is split across
+			// multiple peers and still needs more of them, this
+			// peer will take part in it.
+			if graphSync != nil && !isPinnedSyncer &&
+				graphSync.join(s.cfg.peerPub) {
+
+				m.joinGraphSync(graphSync, s)
+				continue
+			}
+
 			// We'll force a historical sync with the first peer we
 			// connect to, to ensure we get as much of the graph as
 			// possible.
@@ -345,6 +377,28 @@ func (m *SyncManager) syncerHandler() {
 				continue
 			}
 
+			// If configured, the initial historical sync is split
+			// across multiple peers, starting with this one. The
+			// peers connecting next will join it.
+			if !isPinnedSyncer && graphSync == nil &&
+				m.cfg.NumInitialSyncPeers > 1 &&
+				!m.IsGraphSynced() {
+
+				graphSync = m.newGraphSyncSession()
+				graphSyncDone = graphSync.finished()
+
+				log.Debugf("Starting initial historical sync "+
+					"across up to %d peers with "+
+					"GossipSyncer(%x)",
+					m.cfg.NumInitialSyncPeers,
+					s.cfg.peerPub)
+
+				graphSync.join(s.cfg.peerPub)
+				m.joinGraphSync(graphSync, s)
+
+				continue
+			}
+
 			log.Debugf("Attempting initial historical sync with "+
 				"GossipSyncer(%x)", s.cfg.peerPub)
 
@@ -374,6 +428,12 @@ func (m *SyncManager) syncerHandler() {
 			m.removeGossipSyncer(staleSyncer.peer)
 			close(staleSyncer.doneChan)
 
+			// The other participants of a coordinated initial
+			// historical sync will take over the peer's share.
+			if graphSync != nil {
+				graphSync.removePeer(staleSyncer.peer)
+			}
+
 			// If we don't have an initialHistoricalSyncer, or we do
 			// but it is not the peer being disconnected, then we
 			// have nothing left to do and can proceed.
@@ -413,35 +473,43 @@ func (m *SyncManager) syncerHandler() {
 
 			log.Debug("Initial historical sync completed")
 
-			// With the initial historical sync complete, we can
-			// begin receiving new graph updates at tip. We'll
-			// determine whether we can have any more active
-			// GossipSyncers. If we do, we'll randomly select some
-			// that are currently passive to transition.
-			m.syncersMu.Lock()
-			numActiveLeft := m.cfg.NumActiveSyncers - len(m.activeSyncers)
-			if numActiveLeft <= 0 {
-				m.syncersMu.Unlock()
+			m.activatePassiveSyncers()
+
+		// The initial historical sync across multiple peers has
+		// ended.
+		case <-graphSyncDone:
+			complete := graphSync.isComplete()
+			graphSync = nil
+			graphSyncDone = nil
+
+			if complete {
+				log.Debug("Initial historical sync completed")
+
+				m.activatePassiveSyncers()
 				continue
 			}
 
-			// We may not even have enough inactive syncers to be
-			// transitted. In that case, we will transit all the
-			// inactive syncers.
-			if len(m.inactiveSyncers) < numActiveLeft {
-				numActiveLeft = len(m.inactiveSyncers)
-			}
+			// If it didn't complete, e.g. because a participant
+			// disconnected before querying its share of the new
+			// channels, we'll fall back to a historical sync with
+			// a single peer. If none is available right now, the
+			// HistoricalSyncTicker will take care of it.
+			log.Debug("Initial historical sync across multiple " +
+				"peers incomplete, falling back to a single " +
+				"peer")
 
-			log.Debugf("Attempting to transition %v passive "+
-				"GossipSyncers to active", numActiveLeft)
+			if initialHistoricalSyncer != nil ||
+				m.cfg.NumActiveSyncers == 0 {
 
-			for i := 0; i < numActiveLeft; i++ {
-				chooseRandomSyncer(
-					m.inactiveSyncers, m.transitionPassiveSyncer,
-				)
+				continue
 			}
 
-			m.syncersMu.Unlock()
+			s := m.forceHistoricalSync()
+			if s == nil {
+				continue
+			}
+
+			setInitialHistoricalSyncer(s)
 
 		// Our RotateTicker has ticked, so we'll attempt to rotate a
 		// single active syncer with a passive one.
@@ -461,6 +529,12 @@ func (m *SyncManager) syncerHandler() {
 				continue
 			}
 
+			// The coordinated initial historical sync is still
+			// underway, so there's no need for another one.
+			if graphSync != nil {
+				continue
+			}
+
 			// If we don't have a syncer available we have nothing
 			// to do.
 			s := m.forceHistoricalSync()
@@ -487,6 +561,66 @@ func (m *SyncManager) syncerHandler() {
 	}
 }
 
+// activatePassiveSyncers transitions passive syncers to active ones until we
+// have NumActiveSyncers of them. It's called once the initial historical sync
+// completed, as we can then begin receiving new graph updates at tip.
+func (m *SyncManager) activatePassiveSyncers() {
+	m.syncersMu.Lock()
+	defer m.syncersMu.Unlock()
+
+	// We'll determine whether we can have any more active GossipSyncers.
+	// If we do, we'll randomly select some that are currently passive to
+	// transition.
+	numActiveLeft := m.cfg.NumActiveSyncers - len(m.activeSyncers)
+	if numActiveLeft <= 0 {
+		return
+	}
+
+	// We may not even have enough inactive syncers to be transitted. In
+	// that case, we will transit all the inactive syncers.
+	if len(m.inactiveSyncers) < numActiveLeft {
+		numActiveLeft = len(m.inactiveSyncers)
+	}
+
+	log.Debugf("Attempting to transition %v passive GossipSyncers to "+
+		"active", numActiveLeft)
+
+	for i := 0; i < numActiveLeft; i++ {
+		chooseRandomSyncer(
+			m.inactiveSyncers, m.transitionPassiveSyncer,
+		)
+	}
+}
+
+// newGraphSyncSession creates a session to split the initial historical sync
+// across NumInitialSyncPeers peers.
+func (m *SyncManager) newGraphSyncSession() *graphSyncSession {
+	return newGraphSyncSession(graphSyncSessionCfg{
+		chainHash:            m.cfg.ChainHash,
+		chanSeries:           m.cfg.ChanSeries,
+		numSlices:            m.cfg.NumInitialSyncPeers,
+		bestHeight:           m.cfg.BestHeight(),
+		isStillZombieChannel: m.cfg.IsStillZombieChannel,
+		markGraphSynced:      m.markGraphSynced,
+	})
+}
+
+// joinGraphSync requests the syncer, which already joined the session, to
+// take part in the coordinated historical sync.
+func (m *SyncManager) joinGraphSync(session *graphSyncSession,
+	s *GossipSyncer) {
+
+	log.Debugf("Attempting initial historical sync with "+
+		"GossipSyncer(%x) as part of a multi-peer sync", s.cfg.peerPub)
+
+	if err := s.coordinatedHistoricalSync(session); err != nil {
+		log.Errorf("Unable to attempt initial historical sync with "+
+			"GossipSyncer(%x): %v", s.cfg.peerPub, err)
+
+		session.removePeer(s.cfg.peerPub)
+	}
+}
+
 // isPinnedSyncer returns true if the passed GossipSyncer is one of our pinned
 // sync peers.
 func (m *SyncManager) isPinnedSyncer(s *GossipSyncer) bool {
@@ -521,6 +655,7 @@ func (m *SyncManager) createGossipSyncer(peer lnpeer.Peer) *GossipSyncer {
 		maxQueryChanRangeReplies:  maxQueryChanRangeReplies,
 		noTimestampQueryOption:    m.cfg.NoTimestampQueries,
 		isStillZombieChannel:      m.cfg.IsStillZombieChannel,
+		egress:                    m.egress,
 	}, m.gossipFilterSema)
 
 	// Gossip syncers are initialized by default in a PassiveSync type
@@ -549,6 +684,8 @@ func (m *SyncManager) removeGossipSyncer(peer route.Vertex) {
 
 	log.Infof("Removing GossipSyncer for peer=%v", peer)
 
+	m.egress.removePeer(peer)
+
 	// We'll stop the GossipSyncer for the disconnected peer in a goroutine
 	// to prevent blocking the SyncManager.
 	go s.Stop()
@@ -809,3 +946,16 @@ func (m *SyncManager) markGraphSynced() {
 func (m *SyncManager) IsGraphSynced() bool {
 	return atomic.LoadInt32(&m.initialHistoricalSyncCompleted) == 1
 }
+
+// EgressConfig returns the limits currently enforced on the gossip we
+// proactively send to our peers.
+func (m *SyncManager) EgressConfig() EgressConfig {
+	return m.egress.config()
+}
+
+// UpdateEgressConfig replaces the limits enforced on the gossip we
+// proactively send to our peers. The new limits apply to the gossip that's
+// already queued for our peers as well.
+func (m *SyncManager) UpdateEgressConfig(cfg EgressConfig) error {
+	return m.egress.update(cfg)
+}
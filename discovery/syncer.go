@@ -123,6 +123,12 @@ const (
 	// initial state for pinned syncers, as well as a fallthrough case for
 	// chansSynced allowing fully synced peers to facilitate requests.
 	syncerIdle
+
+	// waitingGraphSyncAssignment is the state of a GossipSyncer taking
+	// part in a graphSyncSession once it queried all the ranges it could.
+	// We'll stay in this state until the session reconciled the replies
+	// of all participants and assigned us the new channels to query.
+	waitingGraphSyncAssignment
 )
 
 // String returns a human readable string describing the target syncerState.
@@ -146,6 +152,9 @@ func (s syncerState) String() string {
 	case syncerIdle:
 		return "syncerIdle"
 
+	case waitingGraphSyncAssignment:
+		return "waitingGraphSyncAssignment"
+
 	default:
 		return "UNKNOWN STATE"
 	}
@@ -181,6 +190,11 @@ const (
 	// requestBatchSize is the maximum number of channels we will query the
 	// remote peer for in a QueryShortChanIDs message.
 	requestBatchSize = 500
+
+	// egressQueueSize is the number of filtered gossip batches we'll
+	// buffer for a peer while waiting for its egress budget. Batches
+	// beyond that are dropped.
+	egressQueueSize = 100
 )
 
 var (
@@ -216,6 +230,10 @@ type historicalSyncReq struct {
 	// doneChan is a channel that serves as a signal and is closed to ensure
 	// the historical sync is attempted by the time we return to the caller.
 	doneChan chan struct{}
+
+	// session, if set, is the graphSyncSession the historical sync is
+	// coordinated by.
+	session *graphSyncSession
 }
 
 // gossipSyncerCfg is a struct that packages all the information a GossipSyncer
@@ -299,6 +317,9 @@ type gossipSyncerCfg struct {
 	// updates for a channel and returns true if the channel should be
 	// considered a zombie based on these timestamps.
 	isStillZombieChannel func(time.Time, time.Time) bool
+
+	// egress, if set, limits the gossip we proactively send to the peer.
+	egress *egressScheduler
 }
 
 // GossipSyncer is a struct that handles synchronizing the channel graph state
@@ -360,6 +381,11 @@ type GossipSyncer struct {
 	// received over, these will be read by the replyHandler.
 	queryMsgs chan lnwire.Message
 
+	// egressQueue buffers the filtered gossip batches to flood to the
+	// remote peer, which are sent by the egressHandler as the egress
+	// budget allows.
+	egressQueue chan []lnwire.Message
+
 	// curQueryRangeMsg keeps track of the latest QueryChannelRange message
 	// we've sent to a peer to ensure we've consumed all expected replies.
 	// This field is primarily used within the waitingQueryChanReply state.
@@ -385,6 +411,11 @@ type GossipSyncer struct {
 	// state.
 	newChansToQuery []lnwire.ShortChannelID
 
+	// graphSync is the graphSyncSession coordinating the historical sync
+	// we're currently performing, if any. It's only accessed by the
+	// channelGraphSyncer.
+	graphSync *graphSyncSession
+
 	cfg gossipSyncerCfg
 
 	// rateLimiter dictates the frequency with which we will reply to gossip
@@ -440,6 +471,9 @@ func newGossipSyncer(cfg gossipSyncerCfg, sema chan struct{}) *GossipSyncer {
 		queryMsgs:          make(chan lnwire.Message, 100),
 		syncerSema:         sema,
 		quit:               make(chan struct{}),
+		egressQueue: make(
+			chan []lnwire.Message, egressQueueSize,
+		),
 	}
 }
 
@@ -460,6 +494,10 @@ func (g *GossipSyncer) Start() {
 			g.wg.Add(1)
 			go g.replyHandler()
 		}
+		if g.cfg.egress != nil {
+			g.wg.Add(1)
+			go g.egressHandler()
+		}
 	})
 }
 
@@ -481,6 +519,10 @@ func (g *GossipSyncer) Stop() {
 func (g *GossipSyncer) channelGraphSyncer() {
 	defer g.wg.Done()
 
+	// If we exit while taking part in a coordinated historical sync, the
+	// other participants need to take over our share.
+	defer g.leaveGraphSync()
+
 	for {
 		state := g.syncState()
 		syncType := g.SyncType()
@@ -496,10 +538,31 @@ func (g *GossipSyncer) channelGraphSyncer() {
 		// them.
 		case syncingChans:
 			// If we're in this state, then we'll send the remote
-			// peer our opening QueryChannelRange message.
-			queryRangeMsg, err := g.genChanRangeQuery(
-				g.genHistoricalChanRangeQuery,
+			// peer our opening QueryChannelRange message. If we're
+			// taking part in a coordinated historical sync, the
+			// session tells us which range to query, if any is
+			// left for us.
+			var (
+				queryRangeMsg *lnwire.QueryChannelRange
+				err           error
 			)
+			if g.graphSync != nil {
+				first, num, ok := g.graphSync.nextRange(
+					g.cfg.peerPub,
+				)
+				if !ok {
+					g.setSyncState(
+						waitingGraphSyncAssignment,
+					)
+					continue
+				}
+
+				queryRangeMsg = g.newChanRangeQuery(first, num)
+			} else {
+				queryRangeMsg, err = g.genChanRangeQuery(
+					g.genHistoricalChanRangeQuery,
+				)
+			}
 			if err != nil {
 				log.Errorf("Unable to gen chan range "+
 					"query: %v", err)
@@ -574,6 +637,16 @@ func (g *GossipSyncer) channelGraphSyncer() {
 			// to our terminal state.
 			g.setSyncState(chansSynced)
 
+			// If we queried our share of a coordinated historical
+			// sync, the session decides whether the graph is
+			// synced once all participants are done.
+			if g.graphSync != nil {
+				g.graphSync.fetchDone(g.cfg.peerPub)
+				g.graphSync = nil
+
+				continue
+			}
+
 			// Ensure that the sync manager becomes aware that the
 			// historical sync completed so synced_to_graph is
 			// updated over rpc.
@@ -605,6 +678,32 @@ func (g *GossipSyncer) channelGraphSyncer() {
 				return
 			}
 
+		// In this state, we've queried all the ranges of a coordinated
+		// historical sync we could, and wait for the session to assign
+		// us our share of the new channels to query.
+		case waitingGraphSyncAssignment:
+			select {
+			case newChans := <-g.graphSync.assignment(
+				g.cfg.peerPub,
+			):
+				g.newChansToQuery = newChans
+				g.setSyncState(queryNewChannels)
+
+			// If the session ended without us, e.g. because it
+			// failed to reconcile the replies, we'll just go back
+			// to our terminal state.
+			case <-g.graphSync.finished():
+				g.graphSync = nil
+				g.setSyncState(chansSynced)
+
+			case msg := <-g.gossipMsgs:
+				log.Warnf("Unexpected message: %T in state=%v",
+					msg, state)
+
+			case <-g.quit:
+				return
+			}
+
 		// This is our final terminal state where we'll only reply to
 		// any further queries by the remote peer.
 		case chansSynced:
@@ -686,6 +785,56 @@ func (g *GossipSyncer) replyHandler() {
 	}
 }
 
+// egressHandler sends the gossip batches queued for the remote peer, waiting
+// for the egress budget before each message.
+//
+// NOTE: This method MUST be run as a goroutine.
+func (g *GossipSyncer) egressHandler() {
+	defer g.wg.Done()
+
+	for {
+		select {
+		case msgs := <-g.egressQueue:
+			for _, msg := range msgs {
+				err := g.cfg.egress.wait(
+					g.cfg.peerPub, msg, g.quit,
+				)
+				if err == ErrGossipSyncerExiting {
+					return
+				}
+				if err != nil {
+					log.Errorf("GossipSyncer(%x): unable "+
+						"to schedule gossip: %v",
+						g.cfg.peerPub[:], err)
+					continue
+				}
+
+				g.cfg.sendToPeer(msg)
+			}
+
+		case <-g.quit:
+			return
+		}
+	}
+}
+
+// floodMsgs sends gossip we proactively flood to the remote peer. If an
+// egress limit is enforced, the messages are queued to be sent by the
+// egressHandler as the budget allows.
+func (g *GossipSyncer) floodMsgs(msgs []lnwire.Message) {
+	if g.cfg.egress == nil || !g.cfg.egress.limited() {
+		g.cfg.sendToPeer(msgs...)
+		return
+	}
+
+	select {
+	case g.egressQueue <- msgs:
+	default:
+		log.Debugf("GossipSyncer(%x): egress queue full, dropping %d "+
+			"gossip msgs", g.cfg.peerPub[:], len(msgs))
+	}
+}
+
 // sendGossipTimestampRange constructs and sets a GossipTimestampRange for the
 // syncer and sends it to the remote peer.
 func (g *GossipSyncer) sendGossipTimestampRange(firstTimestamp time.Time,
@@ -919,6 +1068,26 @@ func (g *GossipSyncer) processChanRangeReply(msg *lnwire.ReplyChannelRange) erro
 		}
 	}
 
+	// If we're taking part in a coordinated historical sync, we'll hand
+	// the replies to the session to reconcile them with the ones of the
+	// other participants, and move on to the next range.
+	if g.graphSync != nil {
+		log.Infof("GossipSyncer(%x): reporting %v chans of range "+
+			"query to graph sync session", g.cfg.peerPub[:],
+			len(g.bufferedChanRangeReplies))
+
+		g.graphSync.rangeDone(g.cfg.peerPub, g.bufferedChanRangeReplies)
+
+		g.curQueryRangeMsg = nil
+		g.prevReplyChannelRange = nil
+		g.bufferedChanRangeReplies = nil
+		g.numChanRangeRepliesRcvd = 0
+
+		g.setSyncState(syncingChans)
+
+		return nil
+	}
+
 	log.Infof("GossipSyncer(%x): filtering through %v chans",
 		g.cfg.peerPub[:], len(g.bufferedChanRangeReplies))
 
@@ -1004,12 +1173,20 @@ func (g *GossipSyncer) genChanRangeQuery(
 		numBlocks = 1
 	}
 
-	log.Infof("GossipSyncer(%x): requesting new chans from height=%v "+
-		"and %v blocks after", g.cfg.peerPub[:], startHeight, numBlocks)
-
 	// Finally, we'll craft the channel range query, using our starting
 	// height, then asking for all known channels to the foreseeable end of
 	// the main chain.
+	return g.newChanRangeQuery(startHeight, numBlocks), nil
+}
+
+// newChanRangeQuery creates the channel range query for the given block range
+// and tracks it as our current query.
+func (g *GossipSyncer) newChanRangeQuery(startHeight,
+	numBlocks uint32) *lnwire.QueryChannelRange {
+
+	log.Infof("GossipSyncer(%x): requesting new chans from height=%v "+
+		"and %v blocks after", g.cfg.peerPub[:], startHeight, numBlocks)
+
 	query := &lnwire.QueryChannelRange{
 		ChainHash:        g.cfg.chainHash,
 		FirstBlockHeight: startHeight,
@@ -1022,7 +1199,7 @@ func (g *GossipSyncer) genChanRangeQuery(
 
 	g.curQueryRangeMsg = query
 
-	return query, nil
+	return query
 }
 
 // replyPeerQueries is called in response to any query by the remote peer.
@@ -1359,6 +1536,22 @@ func (g *GossipSyncer) ApplyGossipFilter(filter *lnwire.GossipTimestampRange) er
 		defer returnSema()
 
 		for _, msg := range newUpdatestoSend {
+			// The backlog counts towards the egress budget of the
+			// peer as well.
+			if g.cfg.egress != nil {
+				err := g.cfg.egress.wait(
+					g.cfg.peerPub, msg, g.quit,
+				)
+				if err == ErrGossipSyncerExiting {
+					return
+				}
+				if err != nil {
+					log.Errorf("Unable to schedule "+
+						"message for peer catch up: "+
+						"%v", err)
+				}
+			}
+
 			err := g.cfg.sendToPeerSync(msg)
 			switch {
 			case err == ErrGossipSyncerExiting:
@@ -1389,6 +1582,14 @@ func (g *GossipSyncer) FilterGossipMsgs(msgs ...msgWithSenders) {
 		return
 	}
 
+	// We never flood gossip to passive peers, they'll only get what they
+	// ask for.
+	if g.cfg.egress != nil && g.cfg.egress.isPassive(g.cfg.peerPub) {
+		log.Tracef("GossipSyncer(%x): skipped gossip-passive peer",
+			g.cfg.peerPub[:])
+		return
+	}
+
 	// If we've been signaled to exit, or are exiting, then we'll stop
 	// short.
 	select {
@@ -1501,7 +1702,7 @@ func (g *GossipSyncer) FilterGossipMsgs(msgs ...msgWithSenders) {
 		return
 	}
 
-	g.cfg.sendToPeer(msgsToSend...)
+	g.floodMsgs(msgsToSend)
 }
 
 // ProcessQueryMsg is used by outside callers to pass new channel time series
@@ -1657,11 +1858,29 @@ func (g *GossipSyncer) SyncType() SyncerType {
 // NOTE: This can only be done once the gossip syncer has reached its final
 // chansSynced state.
 func (g *GossipSyncer) historicalSync() error {
+	return g.requestHistoricalSync(nil)
+}
+
+// coordinatedHistoricalSync sends a request to the gossip syncer to take part
+// in the historical sync coordinated by the given session. The peer must have
+// joined the session already.
+func (g *GossipSyncer) coordinatedHistoricalSync(
+	session *graphSyncSession) error {
+
+	return g.requestHistoricalSync(session)
+}
+
+// requestHistoricalSync sends a request to the gossip syncer to perform a
+// historical sync, coordinated by the session if one is given.
+func (g *GossipSyncer) requestHistoricalSync(
+	session *graphSyncSession) error {
+
 	done := make(chan struct{})
 
 	select {
 	case g.historicalSyncReqs <- &historicalSyncReq{
 		doneChan: done,
+		session:  session,
 	}:
 	case <-time.After(syncTransitionTimeout):
 		return ErrSyncTransitionTimeout
@@ -1684,6 +1903,18 @@ func (g *GossipSyncer) handleHistoricalSync(req *historicalSyncReq) {
 	// the remote peer to give us all of the channel IDs they know of
 	// starting from the genesis block.
 	g.genHistoricalChanRangeQuery = true
+	g.graphSync = req.session
 	g.setSyncState(syncingChans)
 	close(req.doneChan)
 }
+
+// leaveGraphSync removes us from the graphSyncSession we're taking part in,
+// if any.
+func (g *GossipSyncer) leaveGraphSync() {
+	if g.graphSync == nil {
+		return
+	}
+
+	g.graphSync.removePeer(g.cfg.peerPub)
+	g.graphSync = nil
+}
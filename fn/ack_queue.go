@@ -0,0 +1,142 @@
+package fn
+
+import "errors"
+
+// ErrDuplicateQueueKey is returned when an item is added to an AckQueue with
+// a key that is already queued.
+var ErrDuplicateQueueKey = errors.New("queue already has key")
+
+// ackItem is an item of an AckQueue along with the key it's acknowledged by.
+type ackItem[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// AckQueue is a bounded FIFO queue of keyed items that are only removed once
+// they're acknowledged, which makes it suitable for at-least-once delivery.
+// Items are read through a cursor: Peek returns the item at the cursor and
+// Advance moves the cursor past it, while the item itself stays queued until
+// it's acknowledged by its key. Rewind moves the cursor back to the oldest
+// item, so that all items that weren't acknowledged yet are read again.
+//
+// NOTE: AckQueue is not safe for concurrent use.
+type AckQueue[K comparable, V any] struct {
+	items    *List[ackItem[K, V]]
+	index    map[K]*Node[ackItem[K, V]]
+	capacity int
+
+	// cursor is the next item to be read, or nil if all items were read.
+	cursor *Node[ackItem[K, V]]
+}
+
+// NewAckQueue creates a new AckQueue that holds at most capacity items. If
+// capacity is zero, the queue is unbounded.
+func NewAckQueue[K comparable, V any](capacity int) *AckQueue[K, V] {
+	return &AckQueue[K, V]{
+		items:    NewList[ackItem[K, V]](),
+		index:    make(map[K]*Node[ackItem[K, V]]),
+		capacity: capacity,
+	}
+}
+
+// Push adds an item to the end of the queue. ErrDuplicateQueueKey is returned
+// if an item with the same key is already queued, and ErrQueueFull if the
+// queue is at capacity.
+func (q *AckQueue[K, V]) Push(key K, value V) error {
+	if _, ok := q.index[key]; ok {
+		return ErrDuplicateQueueKey
+	}
+
+	if q.IsFull() {
+		return ErrQueueFull
+	}
+
+	node := q.items.PushBack(ackItem[K, V]{key: key, value: value})
+	q.index[key] = node
+
+	// If all other items were read already, the new item is the next one
+	// to be read.
+	if q.cursor == nil {
+		q.cursor = node
+	}
+
+	return nil
+}
+
+// Peek returns the item at the cursor, without moving the cursor. If all items
+// were read, then None is returned.
+func (q *AckQueue[K, V]) Peek() Option[T2[K, V]] {
+	if q.cursor == nil {
+		return None[T2[K, V]]()
+	}
+
+	return Some(NewT2(q.cursor.Value.key, q.cursor.Value.value))
+}
+
+// Advance moves the cursor past the item with the given key, if it's the item
+// at the cursor. This allows a reader to only advance past the item it peeked
+// at, even if the queue was rewound or the item was acknowledged in the
+// meantime. The returned boolean indicates whether the cursor was moved.
+func (q *AckQueue[K, V]) Advance(key K) bool {
+	if q.cursor == nil || q.cursor.Value.key != key {
+		return false
+	}
+
+	q.cursor = q.cursor.Next()
+
+	return true
+}
+
+// Rewind moves the cursor back to the oldest item in the queue, so that all
+// items that weren't acknowledged yet are read again.
+func (q *AckQueue[K, V]) Rewind() {
+	q.cursor = q.items.Front()
+}
+
+// Ack removes the item with the given key from the queue, regardless of
+// whether it was read already. The returned boolean indicates whether an item
+// was removed.
+//
+// NOTE: It is safe to call this method multiple times for the same key.
+func (q *AckQueue[K, V]) Ack(key K) bool {
+	node, ok := q.index[key]
+	if !ok {
+		return false
+	}
+
+	// If the item is the next one to be read, the cursor moves on to the
+	// item after it.
+	if node == q.cursor {
+		q.cursor = node.Next()
+	}
+
+	q.items.Remove(node)
+	delete(q.index, key)
+
+	return true
+}
+
+// Contains returns true if an item with the given key is queued.
+func (q *AckQueue[K, V]) Contains(key K) bool {
+	_, ok := q.index[key]
+	return ok
+}
+
+// ForEach calls the given function for each queued item, from the oldest to
+// the newest, whether it was read already or not.
+func (q *AckQueue[K, V]) ForEach(f func(K, V)) {
+	for node := q.items.Front(); node != nil; node = node.Next() {
+		f(node.Value.key, node.Value.value)
+	}
+}
+
+// Len returns the number of queued items, including the ones that were read
+// but not acknowledged yet.
+func (q *AckQueue[K, V]) Len() int {
+	return q.items.Len()
+}
+
+// IsFull returns true if the queue is bounded and at capacity.
+func (q *AckQueue[K, V]) IsFull() bool {
+	return q.capacity > 0 && q.items.Len() >= q.capacity
+}
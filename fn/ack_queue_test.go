@@ -0,0 +1,99 @@
+package fn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestAckQueueRewind tests that items stay queued until they're acknowledged,
+// and that rewinding the queue makes all unacknowledged items readable again.
+func TestAckQueueRewind(t *testing.T) {
+	t.Parallel()
+
+	q := NewAckQueue[int, string](0)
+	for i, item := range []string{"a", "b", "c"} {
+		require.NoError(t, q.Push(i, item))
+	}
+
+	// Read the first two items, but only acknowledge the first one.
+	require.Equal(t, Some(NewT2(0, "a")), q.Peek())
+	require.True(t, q.Advance(0))
+	require.Equal(t, Some(NewT2(1, "b")), q.Peek())
+	require.True(t, q.Advance(1))
+	require.True(t, q.Ack(0))
+	require.False(t, q.Ack(0))
+	require.Equal(t, 2, q.Len())
+
+	// Advancing past an item that isn't at the cursor has no effect.
+	require.False(t, q.Advance(1))
+	require.Equal(t, Some(NewT2(2, "c")), q.Peek())
+	require.True(t, q.Advance(2))
+	require.True(t, q.Peek().IsNone())
+
+	// A new item becomes the next one to be read once all other items
+	// were read.
+	require.NoError(t, q.Push(3, "d"))
+	require.Equal(t, Some(NewT2(3, "d")), q.Peek())
+
+	// After rewinding, the unacknowledged items are read again in order.
+	q.Rewind()
+	var read []string
+	q.Peek().WhenSome(func(item T2[int, string]) {
+		read = append(read, item.Second())
+	})
+	for !q.Peek().IsNone() {
+		key, _ := q.Peek().UnsafeFromSome().Unpack()
+		require.True(t, q.Advance(key))
+
+		q.Peek().WhenSome(func(item T2[int, string]) {
+			read = append(read, item.Second())
+		})
+	}
+	require.Equal(t, []string{"b", "c", "d"}, read)
+}
+
+// TestAckQueueAckCursor tests that acknowledging the item at the cursor moves
+// the cursor on to the next item.
+func TestAckQueueAckCursor(t *testing.T) {
+	t.Parallel()
+
+	q := NewAckQueue[int, int](0)
+	require.NoError(t, q.Push(1, 10))
+	require.NoError(t, q.Push(2, 20))
+
+	require.True(t, q.Ack(1))
+	require.Equal(t, Some(NewT2(2, 20)), q.Peek())
+
+	require.True(t, q.Ack(2))
+	require.True(t, q.Peek().IsNone())
+	require.Zero(t, q.Len())
+}
+
+// TestAckQueueBounds tests that the queue rejects duplicate keys and enforces
+// its capacity, counting items that were read but not acknowledged.
+func TestAckQueueBounds(t *testing.T) {
+	t.Parallel()
+
+	q := NewAckQueue[int, int](2)
+	require.NoError(t, q.Push(1, 10))
+	require.ErrorIs(t, q.Push(1, 11), ErrDuplicateQueueKey)
+	require.True(t, q.Contains(1))
+	require.False(t, q.Contains(2))
+
+	require.NoError(t, q.Push(2, 20))
+	require.True(t, q.IsFull())
+
+	require.True(t, q.Advance(1))
+	require.ErrorIs(t, q.Push(3, 30), ErrQueueFull)
+
+	require.True(t, q.Ack(1))
+	require.False(t, q.IsFull())
+	require.NoError(t, q.Push(3, 30))
+
+	var keys []int
+	q.ForEach(func(key, _ int) {
+		keys = append(keys, key)
+	})
+	require.Equal(t, []int{2, 3}, keys)
+}
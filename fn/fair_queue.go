@@ -0,0 +1,205 @@
+package fn
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrQueueFull is returned when an item is added to a bounded queue
+	// that is already at capacity.
+	ErrQueueFull = errors.New("queue is full")
+
+	// ErrUnknownQueueClass is returned when an item is added to a class
+	// that the queue wasn't created with.
+	ErrUnknownQueueClass = errors.New("unknown queue class")
+)
+
+// FairClass describes a class of items held by a FairQueue.
+type FairClass struct {
+	// Weight is the maximum number of items of the class that are dequeued
+	// in each round. It must be positive.
+	Weight int
+
+	// Capacity is the maximum number of items of the class that the queue
+	// holds. If it's zero, the number of items of the class is unbounded.
+	Capacity int
+}
+
+// fairClass is a FIFO queue of items of the same class, along with the weight
+// and capacity of the class.
+type fairClass[T any] struct {
+	FairClass
+
+	items *List[T]
+}
+
+// isFull returns true if the class is bounded and at capacity.
+func (c *fairClass[T]) isFull() bool {
+	return c.Capacity > 0 && c.items.Len() >= c.Capacity
+}
+
+// FairQueue is a bounded priority queue that holds items belonging to a fixed
+// set of classes. Items of the same class are dequeued in FIFO order, while the
+// classes themselves are served in weighted round robin order: each round, a
+// class with weight w has up to w items dequeued before the next non-empty
+// class is served. This gives classes with a higher weight priority without
+// starving the classes with a lower weight.
+//
+// Each class is bounded separately, so that a backlog of items of one class
+// never prevents items of another class from being queued.
+//
+// NOTE: FairQueue is not safe for concurrent use.
+type FairQueue[T any] struct {
+	classes []fairClass[T]
+	size    int
+
+	// current is the index of the class that is currently being served.
+	current int
+
+	// served is the number of items dequeued from the current class in
+	// this round.
+	served int
+}
+
+// NewFairQueue creates a new FairQueue with the passed classes. Each class is
+// identified by its index.
+func NewFairQueue[T any](classes ...FairClass) (*FairQueue[T], error) {
+	if len(classes) == 0 {
+		return nil, errors.New("at least one class is required")
+	}
+
+	fairClasses := make([]fairClass[T], len(classes))
+	for i, class := range classes {
+		if class.Weight <= 0 {
+			return nil, fmt.Errorf("weight of class %d must be "+
+				"positive, got %d", i, class.Weight)
+		}
+
+		if class.Capacity < 0 {
+			return nil, fmt.Errorf("capacity of class %d must "+
+				"not be negative, got %d", i, class.Capacity)
+		}
+
+		fairClasses[i] = fairClass[T]{
+			FairClass: class,
+			items:     NewList[T](),
+		}
+	}
+
+	return &FairQueue[T]{
+		classes: fairClasses,
+	}, nil
+}
+
+// Enqueue adds an item to the end of the given class. ErrQueueFull is returned
+// if the class is at capacity.
+func (q *FairQueue[T]) Enqueue(class int, item T) error {
+	if class < 0 || class >= len(q.classes) {
+		return fmt.Errorf("%w: %d", ErrUnknownQueueClass, class)
+	}
+
+	if q.classes[class].isFull() {
+		return ErrQueueFull
+	}
+
+	q.classes[class].items.PushBack(item)
+	q.size++
+
+	return nil
+}
+
+// next returns the index of the class the next item is dequeued from, or -1 if
+// the queue is empty.
+func (q *FairQueue[T]) next() int {
+	if q.size == 0 {
+		return -1
+	}
+
+	// The current class is served until it has used up its weight for
+	// this round or runs out of items.
+	current := q.classes[q.current]
+	if q.served < current.Weight && current.items.Len() > 0 {
+		return q.current
+	}
+
+	// Otherwise we move on to the next class that has items, wrapping
+	// around to the current class if it's the only one that has items
+	// left.
+	for i := 1; i <= len(q.classes); i++ {
+		idx := (q.current + i) % len(q.classes)
+		if q.classes[idx].items.Len() > 0 {
+			return idx
+		}
+	}
+
+	return -1
+}
+
+// Peek returns the item that the next call to Dequeue would return, without
+// removing it. If the queue is empty, then None is returned.
+func (q *FairQueue[T]) Peek() Option[T] {
+	idx := q.next()
+	if idx == -1 {
+		return None[T]()
+	}
+
+	return Some(q.classes[idx].items.Front().Value)
+}
+
+// Dequeue removes the next item from the queue, according to the weighted
+// round robin order of the classes. If the queue is empty, then None is
+// returned.
+func (q *FairQueue[T]) Dequeue() Option[T] {
+	idx := q.next()
+	if idx == -1 {
+		return None[T]()
+	}
+
+	// If we moved on to another class, a new turn starts for it.
+	if idx != q.current {
+		q.current = idx
+		q.served = 0
+	}
+
+	class := q.classes[idx]
+	item := class.items.Remove(class.items.Front())
+	q.size--
+	q.served++
+
+	// Once the class has used up its weight, the next class is up.
+	if q.served >= class.Weight {
+		q.current = (idx + 1) % len(q.classes)
+		q.served = 0
+	}
+
+	return Some(item)
+}
+
+// Len returns the total number of items in the queue.
+func (q *FairQueue[T]) Len() int {
+	return q.size
+}
+
+// ClassLen returns the number of items of the given class in the queue.
+func (q *FairQueue[T]) ClassLen(class int) int {
+	if class < 0 || class >= len(q.classes) {
+		return 0
+	}
+
+	return q.classes[class].items.Len()
+}
+
+// IsEmpty returns true if the queue is empty.
+func (q *FairQueue[T]) IsEmpty() bool {
+	return q.size == 0
+}
+
+// IsClassFull returns true if the given class is at capacity.
+func (q *FairQueue[T]) IsClassFull(class int) bool {
+	if class < 0 || class >= len(q.classes) {
+		return false
+	}
+
+	return q.classes[class].isFull()
+}
@@ -0,0 +1,109 @@
+package fn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestFairQueueOrder tests that classes are served in weighted round robin
+// order, and that items of the same class are dequeued in FIFO order.
+func TestFairQueueOrder(t *testing.T) {
+	t.Parallel()
+
+	q, err := NewFairQueue[string](
+		FairClass{Weight: 2}, FairClass{Weight: 1},
+	)
+	require.NoError(t, err)
+
+	for _, item := range []string{"a1", "a2", "a3", "a4", "a5"} {
+		require.NoError(t, q.Enqueue(0, item))
+	}
+	for _, item := range []string{"b1", "b2"} {
+		require.NoError(t, q.Enqueue(1, item))
+	}
+	require.Equal(t, 7, q.Len())
+	require.Equal(t, 5, q.ClassLen(0))
+	require.Equal(t, 2, q.ClassLen(1))
+
+	// Once the lower weighted class runs out of items, the higher weighted
+	// class is served exclusively.
+	expected := []string{"a1", "a2", "b1", "a3", "a4", "b2", "a5"}
+	for _, item := range expected {
+		require.Equal(t, Some(item), q.Peek())
+		require.Equal(t, Some(item), q.Dequeue())
+	}
+
+	require.True(t, q.IsEmpty())
+	require.Equal(t, None[string](), q.Peek())
+	require.Equal(t, None[string](), q.Dequeue())
+}
+
+// TestFairQueueNoStarvation tests that a class that only gets items after the
+// other class has been served for a while isn't starved.
+func TestFairQueueNoStarvation(t *testing.T) {
+	t.Parallel()
+
+	q, err := NewFairQueue[int](
+		FairClass{Weight: 3}, FairClass{Weight: 1},
+	)
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, q.Enqueue(0, i))
+	}
+
+	// Serve part of the high priority class, then add a low priority
+	// item. It must be dequeued within one round.
+	require.Equal(t, Some(0), q.Dequeue())
+	require.NoError(t, q.Enqueue(1, 100))
+
+	var dequeued []int
+	for !q.IsEmpty() {
+		dequeued = append(dequeued, q.Dequeue().UnsafeFromSome())
+	}
+	require.Equal(t, []int{1, 2, 100, 3, 4, 5, 6, 7, 8, 9}, dequeued)
+}
+
+// TestFairQueueBounds tests that the queue enforces the capacity of each class
+// separately and rejects invalid classes, weights and capacities.
+func TestFairQueueBounds(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewFairQueue[int]()
+	require.Error(t, err)
+
+	_, err = NewFairQueue[int](FairClass{Weight: 1}, FairClass{Weight: 0})
+	require.Error(t, err)
+
+	_, err = NewFairQueue[int](FairClass{Weight: 1, Capacity: -1})
+	require.Error(t, err)
+
+	q, err := NewFairQueue[int](
+		FairClass{Weight: 1}, FairClass{Weight: 1, Capacity: 2},
+	)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, q.Enqueue(2, 0), ErrUnknownQueueClass)
+	require.ErrorIs(t, q.Enqueue(-1, 0), ErrUnknownQueueClass)
+
+	require.NoError(t, q.Enqueue(1, 1))
+	require.NoError(t, q.Enqueue(1, 2))
+	require.True(t, q.IsClassFull(1))
+	require.ErrorIs(t, q.Enqueue(1, 3), ErrQueueFull)
+
+	// The unbounded class still accepts items while the bounded class is
+	// full.
+	for i := 0; i < 10; i++ {
+		require.NoError(t, q.Enqueue(0, 10+i))
+	}
+	require.False(t, q.IsClassFull(0))
+	require.Equal(t, 12, q.Len())
+
+	// Once an item of the bounded class is dequeued, there's room for
+	// another one.
+	require.Equal(t, Some(10), q.Dequeue())
+	require.Equal(t, Some(1), q.Dequeue())
+	require.False(t, q.IsClassFull(1))
+	require.NoError(t, q.Enqueue(1, 3))
+}
@@ -0,0 +1,403 @@
+package funding
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnpeer"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/shachain"
+)
+
+var (
+	// errDualFundingDisabled is sent to peers that initiate a dual funded
+	// channel while we don't accept them.
+	errDualFundingDisabled = errors.New("dual funding is not enabled")
+
+	// errDualFundingNotNegotiated is sent to peers that initiate a dual
+	// funded channel without signaling the dual-fund feature bit.
+	errDualFundingNotNegotiated = errors.New("dual-fund feature bit " +
+		"not negotiated")
+
+	// errDualFundingChanType is sent to peers that request a channel type
+	// that we don't support for dual funded channels.
+	errDualFundingChanType = errors.New("channel type not supported " +
+		"for dual funded channels")
+
+	// errDualFundingInternal is sent to peers if we can't proceed with the
+	// negotiation due to an internal error.
+	errDualFundingInternal = errors.New("dual funding failed due to " +
+		"internal error")
+
+	// errDualFundingSigning is sent to peers in a tx_abort once the
+	// funding transaction was constructed, as we can't exchange the
+	// commitment and funding transaction signatures yet.
+	errDualFundingSigning = errors.New("signing dual funded channels " +
+		"is not supported yet")
+)
+
+// dualFundingNegotiation is a dual funded channel negotiation that a remote
+// party initiated with open_channel2.
+type dualFundingNegotiation struct {
+	// peerKey is the identity key of the remote party.
+	peerKey *btcec.PublicKey
+
+	// session tracks the construction of the funding transaction.
+	session *InteractiveTxSession
+}
+
+// dualFundingKeys are the keys and points we contribute to a dual funded
+// channel in our accept_channel2 message.
+type dualFundingKeys struct {
+	funding           *btcec.PublicKey
+	revocationBase    *btcec.PublicKey
+	paymentBase       *btcec.PublicKey
+	delayBase         *btcec.PublicKey
+	htlcBase          *btcec.PublicKey
+	firstCommitPoint  *btcec.PublicKey
+	secondCommitPoint *btcec.PublicKey
+}
+
+// deriveDualFundingKeys derives fresh keys for a dual funded channel from the
+// key ring. The per-commitment points are derived from a revocation root in
+// the same way as for single funded channels.
+func deriveDualFundingKeys(
+	keyRing keychain.SecretKeyRing) (*dualFundingKeys, error) {
+
+	var keys dualFundingKeys
+	for _, key := range []struct {
+		family keychain.KeyFamily
+		pubKey **btcec.PublicKey
+	}{
+		{keychain.KeyFamilyMultiSig, &keys.funding},
+		{keychain.KeyFamilyRevocationBase, &keys.revocationBase},
+		{keychain.KeyFamilyPaymentBase, &keys.paymentBase},
+		{keychain.KeyFamilyDelayBase, &keys.delayBase},
+		{keychain.KeyFamilyHtlcBase, &keys.htlcBase},
+	} {
+		keyDesc, err := keyRing.DeriveNextKey(key.family)
+		if err != nil {
+			return nil, err
+		}
+
+		*key.pubKey = keyDesc.PubKey
+	}
+
+	revRootKey, err := keyRing.DeriveNextKey(
+		keychain.KeyFamilyRevocationRoot,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	revRoot, err := keyRing.ECDH(revRootKey, keys.funding)
+	if err != nil {
+		return nil, err
+	}
+
+	producer := shachain.NewRevocationProducer(revRoot)
+	firstSecret, err := producer.AtIndex(0)
+	if err != nil {
+		return nil, err
+	}
+	secondSecret, err := producer.AtIndex(1)
+	if err != nil {
+		return nil, err
+	}
+
+	keys.firstCommitPoint = input.ComputeCommitmentPoint(firstSecret[:])
+	keys.secondCommitPoint = input.ComputeCommitmentPoint(secondSecret[:])
+
+	return &keys, nil
+}
+
+// fundeeProcessOpenChannel2 handles an open_channel2 message of a remote party
+// that initiates a dual funded channel. We don't contribute any funds to the
+// channel, so we accept it with a zero funding amount and start the
+// interactive construction of the funding transaction.
+func (f *Manager) fundeeProcessOpenChannel2(peer lnpeer.Peer,
+	msg *lnwire.OpenChannel2) {
+
+	peerKey := peer.IdentityKey()
+	amt := msg.FundingAmount
+
+	if err := f.checkOpenChannel2(peer, msg); err != nil {
+		log.Errorf("Rejecting open_channel2(pendingId=%x) from "+
+			"peer(%x): %v", msg.PendingChannelID,
+			peerKey.SerializeCompressed(), err)
+
+		f.rejectOpenChannel2(peer, msg.PendingChannelID, err)
+
+		return
+	}
+
+	keys, err := deriveDualFundingKeys(f.cfg.Wallet.Cfg.SecretKeyRing)
+	if err != nil {
+		log.Errorf("Unable to derive dual funding keys: %v", err)
+		f.rejectOpenChannel2(
+			peer, msg.PendingChannelID, errDualFundingInternal,
+		)
+
+		return
+	}
+
+	_, fundingOutput, err := input.GenFundingPkScript(
+		keys.funding.SerializeCompressed(),
+		msg.FundingKey.SerializeCompressed(), int64(amt),
+	)
+	if err != nil {
+		log.Errorf("Unable to create funding output: %v", err)
+		f.rejectOpenChannel2(
+			peer, msg.PendingChannelID, errDualFundingInternal,
+		)
+
+		return
+	}
+
+	chanID := lnwire.NewDualFundingChanID(
+		keys.revocationBase, msg.RevocationPoint,
+	)
+	negotiation := &dualFundingNegotiation{
+		peerKey: peerKey,
+		session: NewInteractiveTxSession(
+			chanID, true, msg.LockTime, fundingOutput.PkScript,
+			amt,
+		),
+	}
+	if _, loaded := f.dualFundingNegotiations.LoadOrStore(
+		chanID, negotiation,
+	); loaded {
+
+		log.Errorf("Dual funding negotiation for ChannelID(%v) "+
+			"already exists", chanID)
+		f.rejectOpenChannel2(
+			peer, msg.PendingChannelID, errDualFundingInternal,
+		)
+
+		return
+	}
+
+	log.Infof("Recv'd dual funding request(amt=%v, delay=%v, "+
+		"pendingId=%x) from peer(%x), using ChannelID(%v)", amt,
+		msg.CsvDelay, msg.PendingChannelID,
+		peerKey.SerializeCompressed(), chanID)
+
+	acceptMsg := &lnwire.AcceptChannel2{
+		PendingChannelID: msg.PendingChannelID,
+		DustLimit: lnwallet.DustLimitForSize(
+			input.UnknownWitnessSize,
+		),
+		MaxValueInFlight:      f.cfg.RequiredRemoteMaxValue(amt),
+		HtlcMinimum:           f.cfg.DefaultMinHtlcIn,
+		MinAcceptDepth:        uint32(f.cfg.NumRequiredConfs(amt, 0)),
+		CsvDelay:              f.cfg.RequiredRemoteDelay(amt),
+		MaxAcceptedHTLCs:      f.cfg.RequiredRemoteMaxHTLCs(amt),
+		FundingKey:            keys.funding,
+		RevocationPoint:       keys.revocationBase,
+		PaymentPoint:          keys.paymentBase,
+		DelayedPaymentPoint:   keys.delayBase,
+		HtlcPoint:             keys.htlcBase,
+		FirstCommitmentPoint:  keys.firstCommitPoint,
+		SecondCommitmentPoint: keys.secondCommitPoint,
+		UpfrontShutdownScript: lnwire.DeliveryAddress{},
+		ChannelType:           msg.ChannelType,
+	}
+	if err := peer.SendMessage(true, acceptMsg); err != nil {
+		log.Errorf("Unable to send accept_channel2 to peer: %v", err)
+		f.dualFundingNegotiations.Delete(chanID)
+	}
+}
+
+// checkOpenChannel2 checks whether we accept the dual funded channel the
+// remote party requested.
+func (f *Manager) checkOpenChannel2(peer lnpeer.Peer,
+	msg *lnwire.OpenChannel2) error {
+
+	if !f.cfg.EnableDualFunding {
+		return errDualFundingDisabled
+	}
+
+	if !hasFeatures(
+		peer.LocalFeatures(), peer.RemoteFeatures(),
+		lnwire.DualFundOptional,
+	) {
+
+		return errDualFundingNotNegotiated
+	}
+
+	chainHash := *f.cfg.Wallet.Cfg.NetParams.GenesisHash
+	if msg.ChainHash != chainHash {
+		return fmt.Errorf("unknown chain %v", msg.ChainHash)
+	}
+
+	amt := msg.FundingAmount
+	if amt > f.cfg.MaxChanSize {
+		return lnwallet.ErrChanTooLarge(amt, f.cfg.MaxChanSize)
+	}
+	if amt < f.cfg.MinChanSize {
+		return lnwallet.ErrChanTooSmall(amt, f.cfg.MinChanSize)
+	}
+
+	// We only create the segwit v0 funding output for now.
+	if msg.ChannelType != nil {
+		chanType := lnwire.RawFeatureVector(*msg.ChannelType)
+		taproot := chanType.IsSet(
+			lnwire.SimpleTaprootChannelsRequiredStaging,
+		)
+		overlay := chanType.IsSet(
+			lnwire.SimpleTaprootOverlayChansRequired,
+		)
+		if taproot || overlay {
+			return errDualFundingChanType
+		}
+	}
+
+	isSynced, _, err := f.cfg.Wallet.IsSynced()
+	if err != nil || !isSynced {
+		if err != nil {
+			log.Errorf("unable to query wallet: %v", err)
+		}
+
+		return errors.New("Synchronizing blockchain")
+	}
+
+	return nil
+}
+
+// rejectOpenChannel2 sends an error for the given temporary channel ID to the
+// remote party.
+func (f *Manager) rejectOpenChannel2(peer lnpeer.Peer,
+	pendingChanID PendingChanID, reason error) {
+
+	errMsg := &lnwire.Error{
+		ChanID: pendingChanID,
+		Data:   lnwire.ErrorData(reason.Error()),
+	}
+	if err := peer.SendMessage(false, errMsg); err != nil {
+		log.Errorf("unable to send error message to peer %v", err)
+	}
+}
+
+// processInteractiveTxMsg hands an interactive-tx message of the remote party
+// to the construction of the funding transaction it belongs to. Once the
+// transaction was constructed, the negotiation is aborted, as we can't
+// exchange the commitment and funding transaction signatures yet.
+func (f *Manager) processInteractiveTxMsg(peer lnpeer.Peer,
+	msg lnwire.Message) {
+
+	chanID, ok := interactiveTxChanID(msg)
+	if !ok {
+		return
+	}
+
+	if !f.isDualFundingNegotiation(chanID, peer) {
+		log.Warnf("Received %v for unknown dual funding negotiation "+
+			"ChannelID(%v)", msg.MsgType(), chanID)
+
+		return
+	}
+	negotiation, _ := f.dualFundingNegotiations.Load(chanID)
+
+	reply, err := negotiation.session.ProcessMsg(msg)
+	switch {
+	// The remote party aborted, which we acknowledge with a tx_abort of
+	// our own.
+	case errors.Is(err, ErrInteractiveTxAborted):
+		log.Infof("Dual funding negotiation for ChannelID(%v) "+
+			"aborted by peer: %v", chanID, err)
+		f.dualFundingNegotiations.Delete(chanID)
+
+		ackMsg := &lnwire.TxAbort{ChanID: chanID}
+		if err := peer.SendMessage(false, ackMsg); err != nil {
+			log.Errorf("Unable to send tx_abort: %v", err)
+		}
+
+		return
+
+	case err != nil:
+		log.Errorf("Dual funding negotiation for ChannelID(%v) "+
+			"failed: %v", chanID, err)
+		f.abortDualFunding(peer, chanID, err)
+
+		return
+	}
+
+	if reply != nil {
+		if err := peer.SendMessage(false, reply); err != nil {
+			log.Errorf("Unable to send %v: %v", reply.MsgType(),
+				err)
+			f.dualFundingNegotiations.Delete(chanID)
+
+			return
+		}
+	}
+
+	if !negotiation.session.Complete() {
+		return
+	}
+
+	fundingTx := negotiation.session.Tx()
+	log.Infof("Constructed funding tx %v for dual funded "+
+		"ChannelID(%v)", fundingTx.TxHash(), chanID)
+
+	f.abortDualFunding(peer, chanID, errDualFundingSigning)
+}
+
+// abortDualFunding forgets the dual funding negotiation with the given channel
+// ID and sends a tx_abort with the reason to the remote party.
+func (f *Manager) abortDualFunding(peer lnpeer.Peer, chanID lnwire.ChannelID,
+	reason error) {
+
+	f.dualFundingNegotiations.Delete(chanID)
+
+	abortMsg := &lnwire.TxAbort{
+		ChanID: chanID,
+		Data:   lnwire.ErrorData(reason.Error()),
+	}
+	if err := peer.SendMessage(false, abortMsg); err != nil {
+		log.Errorf("Unable to send tx_abort: %v", err)
+	}
+}
+
+// isDualFundingNegotiation returns true if the channel ID belongs to a dual
+// funding negotiation with the given peer.
+func (f *Manager) isDualFundingNegotiation(chanID lnwire.ChannelID,
+	peer lnpeer.Peer) bool {
+
+	negotiation, ok := f.dualFundingNegotiations.Load(chanID)
+	if !ok {
+		return false
+	}
+
+	return negotiation.peerKey.IsEqual(peer.IdentityKey())
+}
+
+// interactiveTxChanID returns the channel ID of an interactive-tx message.
+func interactiveTxChanID(msg lnwire.Message) (lnwire.ChannelID, bool) {
+	switch msg := msg.(type) {
+	case *lnwire.TxAddInput:
+		return msg.ChanID, true
+
+	case *lnwire.TxAddOutput:
+		return msg.ChanID, true
+
+	case *lnwire.TxRemoveInput:
+		return msg.ChanID, true
+
+	case *lnwire.TxRemoveOutput:
+		return msg.ChanID, true
+
+	case *lnwire.TxComplete:
+		return msg.ChanID, true
+
+	case *lnwire.TxAbort:
+		return msg.ChanID, true
+
+	default:
+		return lnwire.ChannelID{}, false
+	}
+}
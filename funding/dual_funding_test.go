@@ -0,0 +1,201 @@
+package funding
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// receiveMsg waits for the funding manager to send a message of the given
+// type.
+func receiveMsg[T lnwire.Message](t *testing.T,
+	msgChan chan lnwire.Message) T {
+
+	t.Helper()
+
+	select {
+	case msg := <-msgChan:
+		typedMsg, ok := msg.(T)
+		require.Truef(t, ok, "unexpected message %T", msg)
+
+		return typedMsg
+
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no message sent")
+	}
+
+	var empty T
+	return empty
+}
+
+// newTestOpenChannel2 returns an open_channel2 message for a channel with the
+// given funding key.
+func newTestOpenChannel2(fundingKey *btcec.PublicKey) *lnwire.OpenChannel2 {
+	pubKey := alicePrivKey.PubKey()
+
+	return &lnwire.OpenChannel2{
+		ChainHash:             *fundingNetParams.GenesisHash,
+		PendingChannelID:      [32]byte{1},
+		FundingFeePerKw:       253,
+		CommitFeePerKw:        253,
+		FundingAmount:         500_000,
+		DustLimit:             354,
+		MaxValueInFlight:      lnwire.NewMSatFromSatoshis(500_000),
+		HtlcMinimum:           1,
+		CsvDelay:              144,
+		MaxAcceptedHTLCs:      483,
+		LockTime:              100,
+		FundingKey:            fundingKey,
+		RevocationPoint:       pubKey,
+		PaymentPoint:          pubKey,
+		DelayedPaymentPoint:   pubKey,
+		HtlcPoint:             pubKey,
+		FirstCommitmentPoint:  pubKey,
+		SecondCommitmentPoint: pubKey,
+	}
+}
+
+// TestDualFundingNegotiation tests that a dual funded channel initiated by the
+// remote party is accepted, that its funding transaction is constructed
+// interactively and that the negotiation is aborted once it's complete.
+func TestDualFundingNegotiation(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := setupFundingManagers(t, func(cfg *Config) {
+		cfg.EnableDualFunding = true
+	})
+	t.Cleanup(func() {
+		tearDownFundingManagers(t, alice, bob)
+	})
+
+	dualFund := []lnwire.FeatureBit{lnwire.DualFundOptional}
+	alice.localFeatures = dualFund
+	alice.remoteFeatures = dualFund
+
+	// Alice opens a dual funded channel, which Bob accepts without
+	// contributing any funds.
+	fundingKey := alicePrivKey.PubKey()
+	openMsg := newTestOpenChannel2(fundingKey)
+	bob.fundingMgr.ProcessFundingMsg(openMsg, alice)
+
+	acceptMsg := receiveMsg[*lnwire.AcceptChannel2](t, bob.msgChan)
+	require.Equal(t, openMsg.PendingChannelID, acceptMsg.PendingChannelID)
+	require.Zero(t, acceptMsg.FundingAmount)
+	require.NotEqual(
+		t, acceptMsg.FirstCommitmentPoint,
+		acceptMsg.SecondCommitmentPoint,
+	)
+
+	chanID := lnwire.NewDualFundingChanID(
+		acceptMsg.RevocationPoint, openMsg.RevocationPoint,
+	)
+	require.True(t, bob.fundingMgr.IsPendingChannel(chanID, alice))
+
+	// Alice adds an input and the funding output, each of which Bob
+	// answers with tx_complete.
+	_, fundingOutput, err := input.GenFundingPkScript(
+		acceptMsg.FundingKey.SerializeCompressed(),
+		fundingKey.SerializeCompressed(),
+		int64(openMsg.FundingAmount),
+	)
+	require.NoError(t, err)
+
+	bob.fundingMgr.ProcessFundingMsg(&lnwire.TxAddInput{
+		ChanID:     chanID,
+		SerialID:   0,
+		PrevTx:     serializedPrevTx(t, 600_000, testP2WKHScript),
+		PrevTxVout: 0,
+		Sequence:   maxInputSequence,
+	}, alice)
+	receiveMsg[*lnwire.TxComplete](t, bob.msgChan)
+
+	bob.fundingMgr.ProcessFundingMsg(&lnwire.TxAddOutput{
+		ChanID:   chanID,
+		SerialID: 2,
+		Amount:   openMsg.FundingAmount,
+		PkScript: fundingOutput.PkScript,
+	}, alice)
+	receiveMsg[*lnwire.TxComplete](t, bob.msgChan)
+
+	// Once Alice completes the construction as well, Bob aborts the
+	// negotiation, as he can't sign the channel yet.
+	bob.fundingMgr.ProcessFundingMsg(
+		&lnwire.TxComplete{ChanID: chanID}, alice,
+	)
+	abortMsg := receiveMsg[*lnwire.TxAbort](t, bob.msgChan)
+	require.Equal(t, chanID, abortMsg.ChanID)
+	require.Equal(
+		t, errDualFundingSigning.Error(), string(abortMsg.Data),
+	)
+
+	require.Eventually(t, func() bool {
+		return !bob.fundingMgr.IsPendingChannel(chanID, alice)
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+// TestDualFundingRemoteAbort tests that a tx_abort of the remote party is
+// acknowledged and ends the negotiation.
+func TestDualFundingRemoteAbort(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := setupFundingManagers(t, func(cfg *Config) {
+		cfg.EnableDualFunding = true
+	})
+	t.Cleanup(func() {
+		tearDownFundingManagers(t, alice, bob)
+	})
+
+	dualFund := []lnwire.FeatureBit{lnwire.DualFundOptional}
+	alice.localFeatures = dualFund
+	alice.remoteFeatures = dualFund
+
+	openMsg := newTestOpenChannel2(alicePrivKey.PubKey())
+	bob.fundingMgr.ProcessFundingMsg(openMsg, alice)
+	acceptMsg := receiveMsg[*lnwire.AcceptChannel2](t, bob.msgChan)
+
+	chanID := lnwire.NewDualFundingChanID(
+		acceptMsg.RevocationPoint, openMsg.RevocationPoint,
+	)
+
+	bob.fundingMgr.ProcessFundingMsg(&lnwire.TxAbort{
+		ChanID: chanID,
+		Data:   lnwire.ErrorData("changed my mind"),
+	}, alice)
+
+	abortMsg := receiveMsg[*lnwire.TxAbort](t, bob.msgChan)
+	require.Equal(t, chanID, abortMsg.ChanID)
+	require.False(t, bob.fundingMgr.IsPendingChannel(chanID, alice))
+}
+
+// TestDualFundingDisabled tests that dual funded channels are rejected unless
+// dual funding is enabled and the feature bit was negotiated.
+func TestDualFundingDisabled(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := setupFundingManagers(t)
+	t.Cleanup(func() {
+		tearDownFundingManagers(t, alice, bob)
+	})
+
+	openMsg := newTestOpenChannel2(alicePrivKey.PubKey())
+	bob.fundingMgr.ProcessFundingMsg(openMsg, alice)
+
+	errMsg := receiveMsg[*lnwire.Error](t, bob.msgChan)
+	require.Equal(
+		t, lnwire.ChannelID(openMsg.PendingChannelID), errMsg.ChanID,
+	)
+	require.Equal(t, errDualFundingDisabled.Error(), string(errMsg.Data))
+
+	// Even if it's enabled, the peer must have signaled the feature.
+	bob.fundingMgr.cfg.EnableDualFunding = true
+	bob.fundingMgr.ProcessFundingMsg(openMsg, alice)
+
+	errMsg = receiveMsg[*lnwire.Error](t, bob.msgChan)
+	require.Equal(
+		t, errDualFundingNotNegotiated.Error(), string(errMsg.Data),
+	)
+}
@@ -0,0 +1,414 @@
+package funding
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+const (
+	// maxInteractiveTxMsgs is the maximum number of tx_add_input and
+	// tx_add_output messages each we accept from the remote party during
+	// the construction of a transaction.
+	maxInteractiveTxMsgs = 4096
+
+	// maxInteractiveTxInOuts is the maximum number of inputs and outputs
+	// each that the constructed transaction may have.
+	maxInteractiveTxInOuts = 252
+
+	// maxInputSequence is the maximum sequence number an input of the
+	// constructed transaction may have, which ensures that it signals
+	// replaceability.
+	maxInputSequence = wire.MaxTxInSequenceNum - 2
+)
+
+var (
+	// ErrInteractiveTxAborted is returned when the remote party aborted
+	// the construction of the transaction.
+	ErrInteractiveTxAborted = errors.New("interactive tx construction " +
+		"aborted by remote party")
+
+	// ErrInteractiveTxComplete is returned when a message is received
+	// after the construction of the transaction completed.
+	ErrInteractiveTxComplete = errors.New("interactive tx construction " +
+		"already complete")
+
+	// errInvalidSerialID is returned when the remote party uses a serial
+	// ID of the wrong parity, or one that is already in use.
+	errInvalidSerialID = errors.New("invalid serial id")
+
+	// errUnknownSerialID is returned when the remote party removes an
+	// input or output it didn't add.
+	errUnknownSerialID = errors.New("unknown serial id")
+)
+
+// interactiveTxInput is an input that was added to the transaction.
+type interactiveTxInput struct {
+	txIn  *wire.TxIn
+	value btcutil.Amount
+}
+
+// InteractiveTxSession tracks the interactive construction of a transaction
+// as defined by the interactive-tx protocol of BOLT 2. The session only
+// receives: the local node doesn't contribute any inputs or outputs and
+// answers each of the remote party's messages with tx_complete.
+type InteractiveTxSession struct {
+	chanID lnwire.ChannelID
+
+	// remoteInitiator is true if the remote party initiated the
+	// construction, in which case it uses even serial IDs.
+	remoteInitiator bool
+
+	lockTime uint32
+
+	// fundingScript and fundingAmt describe the output that the
+	// constructed transaction must contain.
+	fundingScript []byte
+	fundingAmt    btcutil.Amount
+
+	inputs  map[uint64]*interactiveTxInput
+	outputs map[uint64]*wire.TxOut
+
+	// prevOutPoints are the outpoints spent by the added inputs, used to
+	// reject the same outpoint being added twice.
+	prevOutPoints map[wire.OutPoint]struct{}
+
+	numAddInputs  int
+	numAddOutputs int
+
+	// sentComplete is true if our last message was a tx_complete.
+	sentComplete bool
+
+	complete bool
+}
+
+// NewInteractiveTxSession creates a session for the construction of a
+// transaction with the given channel ID and locktime. Once complete, the
+// transaction must contain an output paying fundingAmt to fundingScript.
+func NewInteractiveTxSession(chanID lnwire.ChannelID, remoteInitiator bool,
+	lockTime uint32, fundingScript []byte,
+	fundingAmt btcutil.Amount) *InteractiveTxSession {
+
+	return &InteractiveTxSession{
+		chanID:          chanID,
+		remoteInitiator: remoteInitiator,
+		lockTime:        lockTime,
+		fundingScript:   fundingScript,
+		fundingAmt:      fundingAmt,
+		inputs:          make(map[uint64]*interactiveTxInput),
+		outputs:         make(map[uint64]*wire.TxOut),
+		prevOutPoints:   make(map[wire.OutPoint]struct{}),
+	}
+}
+
+// ProcessMsg processes a message of the remote party and returns the message
+// to reply with, if any. Once the construction completed, Complete returns
+// true and the transaction can be retrieved with Tx. If an error is returned,
+// the negotiation failed and should be aborted with a tx_abort message.
+func (s *InteractiveTxSession) ProcessMsg(
+	msg lnwire.Message) (lnwire.Message, error) {
+
+	if s.complete {
+		return nil, ErrInteractiveTxComplete
+	}
+
+	var err error
+	switch msg := msg.(type) {
+	case *lnwire.TxAddInput:
+		err = s.checkChanID(msg.ChanID)
+		if err == nil {
+			err = s.addInput(msg)
+		}
+
+	case *lnwire.TxAddOutput:
+		err = s.checkChanID(msg.ChanID)
+		if err == nil {
+			err = s.addOutput(msg)
+		}
+
+	case *lnwire.TxRemoveInput:
+		err = s.checkChanID(msg.ChanID)
+		if err == nil {
+			err = s.removeInput(msg.SerialID)
+		}
+
+	case *lnwire.TxRemoveOutput:
+		err = s.checkChanID(msg.ChanID)
+		if err == nil {
+			err = s.removeOutput(msg.SerialID)
+		}
+
+	case *lnwire.TxComplete:
+		if err := s.checkChanID(msg.ChanID); err != nil {
+			return nil, err
+		}
+
+		return s.processComplete()
+
+	case *lnwire.TxAbort:
+		return nil, fmt.Errorf("%w: %v", ErrInteractiveTxAborted,
+			string(msg.Data))
+
+	default:
+		return nil, fmt.Errorf("unexpected message during interactive "+
+			"tx construction: %v", msg.MsgType())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// We don't contribute anything ourselves, so we answer every addition
+	// or removal with tx_complete.
+	s.sentComplete = true
+
+	return &lnwire.TxComplete{ChanID: s.chanID}, nil
+}
+
+// Complete returns true if both parties sent tx_complete in a row and the
+// transaction was validated.
+func (s *InteractiveTxSession) Complete() bool {
+	return s.complete
+}
+
+// Tx returns the transaction constructed so far, with its inputs and outputs
+// ordered by their serial IDs.
+func (s *InteractiveTxSession) Tx() *wire.MsgTx {
+	tx := wire.NewMsgTx(2)
+	tx.LockTime = s.lockTime
+
+	inputIDs := sortedSerialIDs(s.inputs)
+	for _, serialID := range inputIDs {
+		tx.AddTxIn(s.inputs[serialID].txIn)
+	}
+
+	outputIDs := sortedSerialIDs(s.outputs)
+	for _, serialID := range outputIDs {
+		tx.AddTxOut(s.outputs[serialID])
+	}
+
+	return tx
+}
+
+// checkChanID makes sure that the message is meant for this session.
+func (s *InteractiveTxSession) checkChanID(chanID lnwire.ChannelID) error {
+	if chanID != s.chanID {
+		return fmt.Errorf("message for channel %v received during "+
+			"construction for channel %v", chanID, s.chanID)
+	}
+
+	return nil
+}
+
+// checkRemoteSerialID makes sure that the serial ID has the parity of the
+// remote party.
+func (s *InteractiveTxSession) checkRemoteSerialID(serialID uint64) error {
+	isEven := serialID%2 == 0
+	if isEven != s.remoteInitiator {
+		return fmt.Errorf("%w: %d has the wrong parity",
+			errInvalidSerialID, serialID)
+	}
+
+	return nil
+}
+
+// addInput validates and adds the input of a tx_add_input message.
+func (s *InteractiveTxSession) addInput(msg *lnwire.TxAddInput) error {
+	s.numAddInputs++
+	if s.numAddInputs > maxInteractiveTxMsgs {
+		return fmt.Errorf("received more than %d tx_add_input "+
+			"messages", maxInteractiveTxMsgs)
+	}
+
+	if err := s.checkRemoteSerialID(msg.SerialID); err != nil {
+		return err
+	}
+	if _, ok := s.inputs[msg.SerialID]; ok {
+		return fmt.Errorf("%w: input %d already added",
+			errInvalidSerialID, msg.SerialID)
+	}
+
+	if msg.Sequence > maxInputSequence {
+		return fmt.Errorf("input sequence %d isn't replaceable",
+			msg.Sequence)
+	}
+
+	var prevTx wire.MsgTx
+	if err := prevTx.Deserialize(bytes.NewReader(msg.PrevTx)); err != nil {
+		return fmt.Errorf("unable to parse prevtx: %w", err)
+	}
+
+	if msg.PrevTxVout >= uint32(len(prevTx.TxOut)) {
+		return fmt.Errorf("prevtx has no output %d", msg.PrevTxVout)
+	}
+
+	prevOut := prevTx.TxOut[msg.PrevTxVout]
+	if !txscript.IsWitnessProgram(prevOut.PkScript) {
+		return fmt.Errorf("prevtx output %d isn't segwit",
+			msg.PrevTxVout)
+	}
+
+	outPoint := wire.OutPoint{
+		Hash:  prevTx.TxHash(),
+		Index: msg.PrevTxVout,
+	}
+	if _, ok := s.prevOutPoints[outPoint]; ok {
+		return fmt.Errorf("outpoint %v already added", outPoint)
+	}
+
+	txIn := wire.NewTxIn(&outPoint, nil, nil)
+	txIn.Sequence = msg.Sequence
+
+	s.inputs[msg.SerialID] = &interactiveTxInput{
+		txIn:  txIn,
+		value: btcutil.Amount(prevOut.Value),
+	}
+	s.prevOutPoints[outPoint] = struct{}{}
+
+	return nil
+}
+
+// addOutput validates and adds the output of a tx_add_output message.
+func (s *InteractiveTxSession) addOutput(msg *lnwire.TxAddOutput) error {
+	s.numAddOutputs++
+	if s.numAddOutputs > maxInteractiveTxMsgs {
+		return fmt.Errorf("received more than %d tx_add_output "+
+			"messages", maxInteractiveTxMsgs)
+	}
+
+	if err := s.checkRemoteSerialID(msg.SerialID); err != nil {
+		return err
+	}
+	if _, ok := s.outputs[msg.SerialID]; ok {
+		return fmt.Errorf("%w: output %d already added",
+			errInvalidSerialID, msg.SerialID)
+	}
+
+	if msg.Amount <= 0 || msg.Amount > btcutil.MaxSatoshi {
+		return fmt.Errorf("invalid output amount %v", msg.Amount)
+	}
+
+	s.outputs[msg.SerialID] = wire.NewTxOut(
+		int64(msg.Amount), msg.PkScript,
+	)
+
+	return nil
+}
+
+// removeInput removes an input the remote party added.
+func (s *InteractiveTxSession) removeInput(serialID uint64) error {
+	if err := s.checkRemoteSerialID(serialID); err != nil {
+		return err
+	}
+
+	input, ok := s.inputs[serialID]
+	if !ok {
+		return fmt.Errorf("%w: input %d", errUnknownSerialID, serialID)
+	}
+
+	delete(s.prevOutPoints, input.txIn.PreviousOutPoint)
+	delete(s.inputs, serialID)
+
+	return nil
+}
+
+// removeOutput removes an output the remote party added.
+func (s *InteractiveTxSession) removeOutput(serialID uint64) error {
+	if err := s.checkRemoteSerialID(serialID); err != nil {
+		return err
+	}
+
+	if _, ok := s.outputs[serialID]; !ok {
+		return fmt.Errorf("%w: output %d", errUnknownSerialID,
+			serialID)
+	}
+
+	delete(s.outputs, serialID)
+
+	return nil
+}
+
+// processComplete handles a tx_complete of the remote party. If our last
+// message was a tx_complete as well, the construction is complete and the
+// transaction is validated. Otherwise, we reply with a tx_complete, which
+// completes the construction from our side.
+func (s *InteractiveTxSession) processComplete() (lnwire.Message, error) {
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+
+	s.complete = true
+
+	if s.sentComplete {
+		return nil, nil
+	}
+
+	s.sentComplete = true
+
+	return &lnwire.TxComplete{ChanID: s.chanID}, nil
+}
+
+// validate checks that the constructed transaction is valid and pays into the
+// expected funding output.
+func (s *InteractiveTxSession) validate() error {
+	if len(s.inputs) > maxInteractiveTxInOuts {
+		return fmt.Errorf("transaction has %d inputs, more than %d",
+			len(s.inputs), maxInteractiveTxInOuts)
+	}
+	if len(s.outputs) > maxInteractiveTxInOuts {
+		return fmt.Errorf("transaction has %d outputs, more than %d",
+			len(s.outputs), maxInteractiveTxInOuts)
+	}
+
+	var totalIn, totalOut btcutil.Amount
+	for _, input := range s.inputs {
+		totalIn += input.value
+	}
+
+	var numFundingOutputs int
+	for _, output := range s.outputs {
+		totalOut += btcutil.Amount(output.Value)
+
+		if !bytes.Equal(output.PkScript, s.fundingScript) {
+			continue
+		}
+
+		numFundingOutputs++
+		if btcutil.Amount(output.Value) != s.fundingAmt {
+			return fmt.Errorf("funding output has value %v, "+
+				"expected %v", btcutil.Amount(output.Value),
+				s.fundingAmt)
+		}
+	}
+
+	if numFundingOutputs != 1 {
+		return fmt.Errorf("transaction has %d funding outputs, "+
+			"expected 1", numFundingOutputs)
+	}
+
+	if totalIn < totalOut {
+		return fmt.Errorf("inputs of %v don't cover outputs of %v",
+			totalIn, totalOut)
+	}
+
+	return nil
+}
+
+// sortedSerialIDs returns the keys of the map in ascending order.
+func sortedSerialIDs[V any](m map[uint64]V) []uint64 {
+	serialIDs := make([]uint64, 0, len(m))
+	for serialID := range m {
+		serialIDs = append(serialIDs, serialID)
+	}
+
+	sort.Slice(serialIDs, func(i, j int) bool {
+		return serialIDs[i] < serialIDs[j]
+	})
+
+	return serialIDs
+}
@@ -0,0 +1,223 @@
+package funding
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testInteractiveChanID = lnwire.ChannelID{1}
+
+	testFundingScript = append([]byte{0x00, 0x20}, make([]byte, 32)...)
+
+	testP2WKHScript = append([]byte{0x00, 0x14}, make([]byte, 20)...)
+)
+
+// serializedPrevTx returns a serialized transaction with a single output of
+// the given value and script.
+func serializedPrevTx(t *testing.T, value int64, pkScript []byte) []byte {
+	prevTx := wire.NewMsgTx(2)
+	prevTx.AddTxIn(&wire.TxIn{})
+	prevTx.AddTxOut(wire.NewTxOut(value, pkScript))
+
+	var b bytes.Buffer
+	require.NoError(t, prevTx.Serialize(&b))
+
+	return b.Bytes()
+}
+
+// TestInteractiveTxSession tests that the session constructs the transaction
+// from the remote party's inputs and outputs, and completes once both parties
+// sent tx_complete in a row.
+func TestInteractiveTxSession(t *testing.T) {
+	t.Parallel()
+
+	session := NewInteractiveTxSession(
+		testInteractiveChanID, true, 100, testFundingScript, 50_000,
+	)
+	txComplete := &lnwire.TxComplete{ChanID: testInteractiveChanID}
+
+	msgs := []lnwire.Message{
+		&lnwire.TxAddInput{
+			ChanID:   testInteractiveChanID,
+			SerialID: 2,
+			PrevTx: serializedPrevTx(
+				t, 100_000, testP2WKHScript,
+			),
+			Sequence: maxInputSequence,
+		},
+		&lnwire.TxAddOutput{
+			ChanID:   testInteractiveChanID,
+			SerialID: 4,
+			Amount:   40_000,
+			PkScript: testP2WKHScript,
+		},
+		&lnwire.TxAddOutput{
+			ChanID:   testInteractiveChanID,
+			SerialID: 0,
+			Amount:   50_000,
+			PkScript: testFundingScript,
+		},
+		&lnwire.TxAddOutput{
+			ChanID:   testInteractiveChanID,
+			SerialID: 6,
+			Amount:   1_000,
+			PkScript: testP2WKHScript,
+		},
+		&lnwire.TxRemoveOutput{
+			ChanID:   testInteractiveChanID,
+			SerialID: 6,
+		},
+	}
+	for _, msg := range msgs {
+		reply, err := session.ProcessMsg(msg)
+		require.NoError(t, err)
+		require.Equal(t, txComplete, reply)
+		require.False(t, session.Complete())
+	}
+
+	// Our last message was a tx_complete, so the remote party's
+	// tx_complete completes the construction without a reply.
+	reply, err := session.ProcessMsg(txComplete)
+	require.NoError(t, err)
+	require.Nil(t, reply)
+	require.True(t, session.Complete())
+
+	tx := session.Tx()
+	require.EqualValues(t, 100, tx.LockTime)
+	require.Len(t, tx.TxIn, 1)
+	require.EqualValues(t, maxInputSequence, tx.TxIn[0].Sequence)
+
+	// The outputs are ordered by their serial IDs.
+	require.Equal(t, []*wire.TxOut{
+		wire.NewTxOut(50_000, testFundingScript),
+		wire.NewTxOut(40_000, testP2WKHScript),
+	}, tx.TxOut)
+
+	_, err = session.ProcessMsg(txComplete)
+	require.ErrorIs(t, err, ErrInteractiveTxComplete)
+}
+
+// TestInteractiveTxSessionFailures tests that the session rejects invalid
+// messages of the remote party.
+func TestInteractiveTxSessionFailures(t *testing.T) {
+	t.Parallel()
+
+	prevTx := serializedPrevTx(t, 100_000, testP2WKHScript)
+	fundingOutput := &lnwire.TxAddOutput{
+		ChanID:   testInteractiveChanID,
+		SerialID: 0,
+		Amount:   50_000,
+		PkScript: testFundingScript,
+	}
+
+	testCases := []struct {
+		name       string
+		msgs       []lnwire.Message
+		expectsErr error
+	}{
+		{
+			name: "wrong serial id parity",
+			msgs: []lnwire.Message{
+				&lnwire.TxAddOutput{
+					ChanID:   testInteractiveChanID,
+					SerialID: 1,
+					Amount:   50_000,
+					PkScript: testFundingScript,
+				},
+			},
+			expectsErr: errInvalidSerialID,
+		},
+		{
+			name: "duplicate serial id",
+			msgs: []lnwire.Message{
+				fundingOutput, fundingOutput,
+			},
+			expectsErr: errInvalidSerialID,
+		},
+		{
+			name: "remove unknown input",
+			msgs: []lnwire.Message{
+				&lnwire.TxRemoveInput{
+					ChanID:   testInteractiveChanID,
+					SerialID: 2,
+				},
+			},
+			expectsErr: errUnknownSerialID,
+		},
+		{
+			name: "non segwit input",
+			msgs: []lnwire.Message{
+				&lnwire.TxAddInput{
+					ChanID:   testInteractiveChanID,
+					SerialID: 2,
+					PrevTx: serializedPrevTx(
+						t, 100_000, []byte{0x51},
+					),
+				},
+			},
+		},
+		{
+			name: "missing funding output",
+			msgs: []lnwire.Message{
+				&lnwire.TxAddInput{
+					ChanID:   testInteractiveChanID,
+					SerialID: 2,
+					PrevTx:   prevTx,
+				},
+				&lnwire.TxComplete{
+					ChanID: testInteractiveChanID,
+				},
+			},
+		},
+		{
+			name: "outputs exceed inputs",
+			msgs: []lnwire.Message{
+				fundingOutput,
+				&lnwire.TxComplete{
+					ChanID: testInteractiveChanID,
+				},
+			},
+		},
+		{
+			name: "aborted",
+			msgs: []lnwire.Message{
+				&lnwire.TxAbort{
+					ChanID: testInteractiveChanID,
+					Data:   lnwire.ErrorData("no funds"),
+				},
+			},
+			expectsErr: ErrInteractiveTxAborted,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			session := NewInteractiveTxSession(
+				testInteractiveChanID, true, 0,
+				testFundingScript, 50_000,
+			)
+
+			var err error
+			for _, msg := range testCase.msgs {
+				_, err = session.ProcessMsg(msg)
+				if err != nil {
+					break
+				}
+			}
+
+			require.Error(t, err)
+			if testCase.expectsErr != nil {
+				require.ErrorIs(t, err, testCase.expectsErr)
+			}
+			require.False(t, session.Complete())
+		})
+	}
+}
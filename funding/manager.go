@@ -147,6 +147,9 @@ type reservationWithCtx struct {
 	reservation *lnwallet.ChannelReservation
 	peer        lnpeer.Peer
 
+	// initiator is true if we initiated the funding flow.
+	initiator bool
+
 	chanAmt btcutil.Amount
 
 	// forwardingPolicy is the policy provided by the initFundingMsg.
@@ -162,6 +165,11 @@ type reservationWithCtx struct {
 	// maxLocalCsv is the maximum csv we will accept from the remote.
 	maxLocalCsv uint16
 
+	// negotiationBounds are the bounds we enforce on the remaining
+	// commitment parameters the remote dictates for our commitment. If
+	// nil, the defaults are used.
+	negotiationBounds *lnwallet.NegotiationBounds
+
 	// channelType is the explicit channel type proposed by the initiator of
 	// the channel.
 	channelType *lnwire.ChannelType
@@ -263,6 +271,11 @@ type InitFundingMsg struct {
 	// peer.
 	MaxLocalCsv uint16
 
+	// NegotiationBounds optionally overrides the bounds we enforce on the
+	// commitment parameters our peer dictates for our commitment. If nil,
+	// the bounds of the funding manager's config are used.
+	NegotiationBounds *lnwallet.NegotiationBounds
+
 	// FundUpToMaxAmt is the maximum amount to try to commit to. If set, the
 	// MinFundAmt field denotes the acceptable minimum amount to commit to,
 	// while trying to commit as many coins as possible up to this value.
@@ -353,6 +366,11 @@ type Config struct {
 	// requests, and also reject all outgoing wumbo channel requests.
 	NoWumboChans bool
 
+	// EnableDualFunding indicates that we accept dual funded channel
+	// negotiations that remote parties initiate with open_channel2. The
+	// dual-fund feature bit must be advertised for peers to do so.
+	EnableDualFunding bool
+
 	// IDKey is the PublicKey that is used to identify this node within the
 	// Lightning Network.
 	IDKey *btcec.PublicKey
@@ -485,6 +503,16 @@ type Config struct {
 	// a reservation is considered a zombie.
 	ReservationTimeout time.Duration
 
+	// ResponderConfTimeout is the number of blocks after which a pending
+	// channel we didn't initiate is forgotten if its funding transaction
+	// hasn't confirmed. If zero, MaxWaitNumBlocksFundingConf is used.
+	ResponderConfTimeout uint32
+
+	// InitiatorConfTimeout is the number of blocks after which a pending
+	// channel we initiated and funded from our wallet is abandoned if its
+	// funding transaction hasn't confirmed. Zero disables the timeout.
+	InitiatorConfTimeout uint32
+
 	// MinChanSize is the smallest channel size that we'll accept as an
 	// inbound channel. We have such a parameter, as otherwise, nodes could
 	// flood us with very small channels that would never really be usable
@@ -508,6 +536,12 @@ type Config struct {
 	// commit output. Channels that exceed this value will be failed.
 	MaxLocalCSVDelay uint16
 
+	// NegotiationBounds are the bounds we enforce on the max accepted
+	// htlcs, the channel reserve and the dust limit the remote dictates
+	// for our commitment. Channels that violate them will be failed. If
+	// nil, the defaults are used.
+	NegotiationBounds *lnwallet.NegotiationBounds
+
 	// NotifyOpenChannelEvent informs the ChannelNotifier when channels
 	// transition from pending open to open.
 	NotifyOpenChannelEvent func(wire.OutPoint)
@@ -539,6 +573,13 @@ type Config struct {
 	// abstracts away the handling of many alias functions.
 	AliasManager aliasHandler
 
+	// FetchAliasChanUpdate fetches our latest channel update for an
+	// unannounced channel that negotiated the option-scid-alias feature,
+	// using the peer's alias instead of the confirmed SCID. It is used to
+	// send the peer a new update when it rotates its alias.
+	FetchAliasChanUpdate func(*channeldb.OpenChannel) (
+		*lnwire.ChannelUpdate1, error)
+
 	// IsSweeperOutpoint queries the sweeper store for successfully
 	// published sweeps. This is useful to decide for the internal wallet
 	// backed funding flow to not use utxos still being swept by the sweeper
@@ -630,6 +671,12 @@ type Manager struct {
 
 	handleChannelReadyBarriers *lnutils.SyncMap[lnwire.ChannelID, struct{}]
 
+	// dualFundingNegotiations tracks the dual funded channel negotiations
+	// that remote parties initiated, keyed by their channel ID.
+	dualFundingNegotiations *lnutils.SyncMap[
+		lnwire.ChannelID, *dualFundingNegotiation,
+	]
+
 	quit chan struct{}
 	wg   sync.WaitGroup
 }
@@ -694,6 +741,9 @@ func NewFundingManager(cfg Config) (*Manager, error) {
 		handleChannelReadyBarriers: &lnutils.SyncMap[
 			lnwire.ChannelID, struct{},
 		]{},
+		dualFundingNegotiations: &lnutils.SyncMap[
+			lnwire.ChannelID, *dualFundingNegotiation,
+		]{},
 		pendingMusigNonces: make(
 			map[lnwire.ChannelID]*musig2.Nonces,
 		),
@@ -1043,6 +1093,15 @@ func (f *Manager) reservationCoordinator() {
 				f.wg.Add(1)
 				go f.handleChannelReady(fmsg.peer, msg)
 
+			case *lnwire.OpenChannel2:
+				f.fundeeProcessOpenChannel2(fmsg.peer, msg)
+
+			case *lnwire.TxAddInput, *lnwire.TxAddOutput,
+				*lnwire.TxRemoveInput, *lnwire.TxRemoveOutput,
+				*lnwire.TxComplete, *lnwire.TxAbort:
+
+				f.processInteractiveTxMsg(fmsg.peer, msg)
+
 			case *lnwire.Warning:
 				f.handleWarningMsg(fmsg.peer, msg)
 
@@ -1538,6 +1597,16 @@ func (f *Manager) fundeeProcessOpenChannel(peer lnpeer.Peer,
 		return
 	}
 
+	// Make sure the custom records the acceptor wants us to send can
+	// actually be encoded in our accept_channel message.
+	err = lnwire.ValidateAcceptChannelRecords(acceptorResp.CustomRecords)
+	if err != nil {
+		log.Errorf("Invalid custom records from channel acceptor: %v",
+			err)
+		f.failFundingFlow(peer, cid, err)
+		return
+	}
+
 	log.Infof("Recv'd fundingRequest(amt=%v, push=%v, delay=%v, "+
 		"pendingId=%x) from peer(%x)", amt, msg.PushAmount,
 		msg.CsvDelay, msg.PendingChannelID,
@@ -1737,7 +1806,8 @@ func (f *Manager) fundeeProcessOpenChannel(peer lnpeer.Peer,
 		CsvDelay:  msg.CsvDelay,
 	}
 	err = reservation.CommitConstraints(
-		stateBounds, commitParams, f.cfg.MaxLocalCSVDelay, true,
+		stateBounds, commitParams, f.cfg.MaxLocalCSVDelay,
+		f.cfg.NegotiationBounds, true,
 	)
 	if err != nil {
 		log.Errorf("Unacceptable channel constraints: %v", err)
@@ -1857,6 +1927,7 @@ func (f *Manager) fundeeProcessOpenChannel(peer lnpeer.Peer,
 		remoteMaxHtlcs:    maxHtlcs,
 		remoteChanReserve: chanReserve,
 		maxLocalCsv:       f.cfg.MaxLocalCSVDelay,
+		negotiationBounds: f.cfg.NegotiationBounds,
 		channelType:       chanType,
 		err:               make(chan error, 1),
 		peer:              peer,
@@ -1957,6 +2028,7 @@ func (f *Manager) fundeeProcessOpenChannel(peer lnpeer.Peer,
 		UpfrontShutdownScript: ourContribution.UpfrontShutdown,
 		ChannelType:           chanType,
 		LeaseExpiry:           msg.LeaseExpiry,
+		CustomRecords:         acceptorResp.CustomRecords,
 	}
 
 	if commitType.IsTaproot() {
@@ -2118,7 +2190,8 @@ func (f *Manager) funderProcessAcceptChannel(peer lnpeer.Peer,
 		CsvDelay:  msg.CsvDelay,
 	}
 	err = resCtx.reservation.CommitConstraints(
-		&bounds, &commitParams, resCtx.maxLocalCsv, false,
+		&bounds, &commitParams, resCtx.maxLocalCsv,
+		resCtx.negotiationBounds, false,
 	)
 	if err != nil {
 		log.Warnf("Unacceptable channel constraints: %v", err)
@@ -2899,14 +2972,13 @@ type confirmedChannel struct {
 
 // fundingTimeout is called when callers of waitForFundingWithTimeout receive
 // an ErrConfirmationTimeout. It is used to clean-up channel state and mark the
-// channel as closed. The error is only returned for the responder of the
-// channel flow.
+// channel as closed. If we initiated the channel, the inputs of the funding
+// transaction are released back to our wallet.
 func (f *Manager) fundingTimeout(c *channeldb.OpenChannel,
 	pendingID PendingChanID) error {
 
 	// We'll get a timeout if the number of blocks mined since the channel
-	// was initiated reaches MaxWaitNumBlocksFundingConf and we are not the
-	// channel initiator.
+	// was initiated reaches the configured confirmation timeout.
 	localBalance := c.LocalCommitment.LocalBalance.ToSatoshis()
 	closeInfo := &channeldb.ChannelCloseSummary{
 		ChainHash:               c.ChainHash,
@@ -2932,6 +3004,12 @@ func (f *Manager) fundingTimeout(c *channeldb.OpenChannel,
 	timeoutErr := fmt.Errorf("timeout waiting for funding tx (%v) to "+
 		"confirm", c.FundingOutpoint)
 
+	// If we initiated the channel, we release the inputs of the funding
+	// transaction back to our wallet.
+	if c.IsInitiator {
+		f.releaseFundingInputs(c)
+	}
+
 	// When the peer comes online, we'll notify it that we are now
 	// considering the channel flow canceled.
 	f.wg.Add(1)
@@ -2970,9 +3048,9 @@ func (f *Manager) fundingTimeout(c *channeldb.OpenChannel,
 }
 
 // waitForFundingWithTimeout is a wrapper around waitForFundingConfirmation and
-// waitForTimeout that will return ErrConfirmationTimeout if we are not the
-// channel initiator and the MaxWaitNumBlocksFundingConf has passed from the
-// funding broadcast height. In case of confirmation, the short channel ID of
+// waitForTimeout that will return ErrConfirmationTimeout if the configured
+// confirmation timeout of the channel has passed from the funding broadcast
+// height. In case of confirmation, the short channel ID of
 // the channel and the funding transaction will be returned.
 func (f *Manager) waitForFundingWithTimeout(
 	ch *channeldb.OpenChannel) (*confirmedChannel, error) {
@@ -2986,8 +3064,8 @@ func (f *Manager) waitForFundingWithTimeout(
 
 	// If we are not the initiator, we have no money at stake and will
 	// timeout waiting for the funding transaction to confirm after a
-	// while.
-	if !ch.IsInitiator && !ch.IsZeroConf() {
+	// while. If we are the initiator, we only do so if configured.
+	if f.confTimeout(ch) > 0 {
 		f.wg.Add(1)
 		go f.waitForTimeout(ch, cancelChan, timeoutChan)
 	}
@@ -3143,8 +3221,8 @@ func (f *Manager) waitForFundingConfirmation(
 	}
 }
 
-// waitForTimeout will close the timeout channel if MaxWaitNumBlocksFundingConf
-// has passed from the broadcast height of the given channel. In case of error,
+// waitForTimeout will close the timeout channel if the confirmation timeout of
+// the channel has passed from its broadcast height. In case of error,
 // the error is sent on timeoutChan. The wait can be canceled by closing the
 // cancelChan.
 //
@@ -3165,8 +3243,9 @@ func (f *Manager) waitForTimeout(completeChan *channeldb.OpenChannel,
 	defer epochClient.Cancel()
 
 	// On block maxHeight we will cancel the funding confirmation wait.
+	confTimeout := f.confTimeout(completeChan)
 	broadcastHeight := completeChan.BroadcastHeight()
-	maxHeight := broadcastHeight + MaxWaitNumBlocksFundingConf
+	maxHeight := broadcastHeight + confTimeout
 	for {
 		select {
 		case epoch, ok := <-epochClient.Epochs:
@@ -3181,8 +3260,7 @@ func (f *Manager) waitForTimeout(completeChan *channeldb.OpenChannel,
 			if uint32(epoch.Height) >= maxHeight {
 				log.Warnf("Waited for %v blocks without "+
 					"seeing funding transaction confirmed,"+
-					" cancelling.",
-					MaxWaitNumBlocksFundingConf)
+					" cancelling.", confTimeout)
 
 				// Notify the caller of the timeout.
 				close(timeoutChan)
@@ -3971,6 +4049,7 @@ func (f *Manager) handleChannelReady(peer lnpeer.Peer, //nolint:funlen
 	// call to InsertNextRevocation in case the call to PutPeerAlias fails.
 	// If it were to fail on the first call to handleChannelReady, we
 	// wouldn't want the channel to be usable yet.
+	var aliasRotated bool
 	if channel.NegotiatedAliasFeature() {
 		// If the AliasScid field is nil, we must fail out. We will
 		// most likely not be able to route through the peer.
@@ -3981,6 +4060,12 @@ func (f *Manager) handleChannelReady(peer lnpeer.Peer, //nolint:funlen
 			return
 		}
 
+		// The peer may send a different alias in a subsequent
+		// channel_ready message, in which case the updates it
+		// received from us refer to an alias it no longer uses.
+		prevAlias, err := f.cfg.AliasManager.GetPeerAlias(chanID)
+		aliasRotated = err == nil && prevAlias != *msg.AliasScid
+
 		// We'll store the AliasScid so that invoice creation can use
 		// it.
 		err = f.cfg.AliasManager.PutPeerAlias(chanID, *msg.AliasScid)
@@ -4054,6 +4139,11 @@ func (f *Manager) handleChannelReady(peer lnpeer.Peer, //nolint:funlen
 	if channel.RemoteNextRevocation != nil {
 		log.Infof("Received duplicate channelReady for "+
 			"ChannelID(%v), ignoring.", chanID)
+
+		if aliasRotated {
+			f.sendAliasChanUpdate(channel, peer)
+		}
+
 		return
 	}
 
@@ -4143,6 +4233,34 @@ func (f *Manager) handleChannelReady(peer lnpeer.Peer, //nolint:funlen
 	}
 }
 
+// sendAliasChanUpdate sends the peer our latest channel update using the
+// peer's current alias. This is used after the peer rotated its alias, as the
+// updates it received so far refer to the previous one. Public channels are
+// skipped since their updates use the confirmed SCID.
+func (f *Manager) sendAliasChanUpdate(channel *channeldb.OpenChannel,
+	peer lnpeer.Peer) {
+
+	if channel.ChannelFlags&lnwire.FFAnnounceChannel != 0 {
+		return
+	}
+
+	update, err := f.cfg.FetchAliasChanUpdate(channel)
+	if err != nil {
+		log.Errorf("Unable to fetch alias channel update for "+
+			"ChannelPoint(%v): %v", channel.FundingOutpoint, err)
+		return
+	}
+
+	log.Infof("Peer rotated alias of ChannelPoint(%v), sending channel "+
+		"update with alias %v", channel.FundingOutpoint,
+		update.ShortChannelID)
+
+	if err := peer.SendMessage(false, update); err != nil {
+		log.Errorf("Unable to send alias channel update for "+
+			"ChannelPoint(%v): %v", channel.FundingOutpoint, err)
+	}
+}
+
 // handleChannelReadyReceived is called once the remote's channelReady message
 // is received and processed. At this stage, we must have sent out our
 // channelReady message, once the remote's channelReady is processed, the
@@ -4669,6 +4787,13 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		maxCSV = f.cfg.MaxLocalCSVDelay
 	}
 
+	// Likewise, we use our configured negotiation bounds unless the
+	// request overrides them.
+	negotiationBounds := msg.NegotiationBounds
+	if negotiationBounds == nil {
+		negotiationBounds = f.cfg.NegotiationBounds
+	}
+
 	log.Infof("Initiating fundingRequest(local_amt=%v "+
 		"(subtract_fees=%v), push_amt=%v, chain_hash=%v, peer=%x, "+
 		"min_confs=%v)", localAmt, msg.SubtractFees, msg.PushAmt,
@@ -4772,6 +4897,15 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		commitFeePerKw = f.cfg.MaxAnchorsCommitFeeRate
 	}
 
+	// The fee floor of the backend may have risen since the funding fee
+	// rate was chosen. We'd then fail to publish the funding transaction
+	// once the remote peer signed it, so we bail out early instead.
+	err = chainfee.CheckRelayFee(f.cfg.FeeEstimator, msg.FundingFeePerKw)
+	if err != nil {
+		msg.Err <- fmt.Errorf("unable to fund channel: %w", err)
+		return
+	}
+
 	var scidFeatureVal bool
 	if hasFeatures(
 		msg.Peer.LocalFeatures(), msg.Peer.RemoteFeatures(),
@@ -4941,9 +5075,11 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		remoteMaxHtlcs:    maxHtlcs,
 		remoteChanReserve: chanReserve,
 		maxLocalCsv:       maxCSV,
+		negotiationBounds: negotiationBounds,
 		channelType:       chanType,
 		reservation:       reservation,
 		peer:              msg.Peer,
+		initiator:         true,
 		updates:           msg.Updates,
 		err:               msg.Err,
 	}
@@ -4965,7 +5101,8 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		CsvDelay:  remoteCsvDelay,
 	}
 	err = lnwallet.VerifyConstraints(
-		bounds, commitParams, resCtx.maxLocalCsv, capacity,
+		bounds, commitParams, resCtx.maxLocalCsv,
+		resCtx.negotiationBounds, capacity,
 	)
 	if err != nil {
 		_, reserveErr := f.cancelReservationCtx(peerKey, chanID, false)
@@ -5051,6 +5188,17 @@ func (f *Manager) handleErrorMsg(peer lnpeer.Peer, msg *lnwire.Error) {
 	chanID := msg.ChanID
 	peerKey := peer.IdentityKey()
 
+	// The error may be tied to a dual funding negotiation, which doesn't
+	// have a reservation.
+	if f.isDualFundingNegotiation(chanID, peer) {
+		log.Errorf("Dual funding negotiation for ChannelID(%v) failed "+
+			"by peer %x: %v", chanID, peerKey.SerializeCompressed(),
+			msg.Error())
+		f.dualFundingNegotiations.Delete(chanID)
+
+		return
+	}
+
 	// First, we'll attempt to retrieve and cancel the funding workflow
 	// that this error was tied to. If we're unable to do so, then we'll
 	// exit early as this was an unwarranted error.
@@ -5228,7 +5376,13 @@ func (f *Manager) IsPendingChannel(pendingChanID PendingChanID,
 	_, ok := f.activeReservations[peerIDKey][pendingChanID]
 	f.resMtx.RUnlock()
 
-	return ok
+	if ok {
+		return true
+	}
+
+	// Dual funded channels are referenced by their final channel ID
+	// during the negotiation.
+	return f.isDualFundingNegotiation(pendingChanID, peer)
 }
 
 func copyPubKey(pub *btcec.PublicKey) *btcec.PublicKey {
@@ -559,6 +559,11 @@ func createTestFundingManager(t *testing.T, privKey *btcec.PrivateKey,
 			return nil, nil
 		},
 		AliasManager: aliasMgr,
+		FetchAliasChanUpdate: func(*channeldb.OpenChannel) (
+			*lnwire.ChannelUpdate1, error) {
+
+			return &lnwire.ChannelUpdate1{}, nil
+		},
 		// For unit tests we default to false meaning that no funds
 		// originated from the sweeper.
 		IsSweeperOutpoint: func(wire.OutPoint) bool {
@@ -679,6 +684,7 @@ func recreateAliceFundingManager(t *testing.T, alice *testNode) {
 		OpenChannelPredicate:  chainedAcceptor,
 		DeleteAliasEdge:       oldCfg.DeleteAliasEdge,
 		AliasManager:          oldCfg.AliasManager,
+		FetchAliasChanUpdate:  oldCfg.FetchAliasChanUpdate,
 		AuxLeafStore:          oldCfg.AuxLeafStore,
 		AuxSigner:             oldCfg.AuxSigner,
 	})
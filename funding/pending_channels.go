@@ -0,0 +1,225 @@
+package funding
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+)
+
+// PendingChannelStage describes the stage of the funding flow a pending
+// channel is in.
+type PendingChannelStage uint8
+
+const (
+	// StageNegotiating is the stage in which the channel parameters and
+	// signatures are exchanged with the peer, before the funding
+	// transaction is broadcast.
+	StageNegotiating PendingChannelStage = iota
+
+	// StageAwaitingConfirmation is the stage in which the funding
+	// transaction was broadcast and we wait for it to confirm.
+	StageAwaitingConfirmation
+)
+
+// String returns a human-readable representation of the stage.
+func (s PendingChannelStage) String() string {
+	switch s {
+	case StageNegotiating:
+		return "negotiating"
+
+	case StageAwaitingConfirmation:
+		return "awaiting_confirmation"
+
+	default:
+		return "unknown"
+	}
+}
+
+// PendingChannelStatus describes a channel that is still pending open, along
+// with its age and the action that will be taken next.
+type PendingChannelStatus struct {
+	// Stage is the stage of the funding flow the channel is in.
+	Stage PendingChannelStage
+
+	// PeerKey is the identity public key of the channel peer.
+	PeerKey *btcec.PublicKey
+
+	// ChanPoint is the funding outpoint of the channel. It is the zero
+	// outpoint if it isn't known yet.
+	ChanPoint wire.OutPoint
+
+	// Initiator is true if we initiated the channel.
+	Initiator bool
+
+	// IdleTime is the time since the peer last made progress on the
+	// funding flow. It is only set in the negotiating stage.
+	IdleTime time.Duration
+
+	// AgeBlocks is the number of blocks since the funding transaction was
+	// broadcast. It is only set in the awaiting confirmation stage.
+	AgeBlocks uint32
+
+	// TimeoutHeight is the height at which the channel is abandoned if
+	// its funding transaction hasn't confirmed. It is zero if the channel
+	// doesn't time out.
+	TimeoutHeight uint32
+
+	// NextAction describes what will happen next with the channel.
+	NextAction string
+}
+
+// confTimeout returns the number of blocks after which the channel is
+// abandoned if its funding transaction hasn't confirmed, or zero if the
+// channel doesn't time out.
+func (f *Manager) confTimeout(c *channeldb.OpenChannel) uint32 {
+	// Zero-conf channels are usable before confirmation, so we keep
+	// waiting for the funding transaction.
+	if c.IsZeroConf() {
+		return 0
+	}
+
+	// If we are not the initiator, we have no money at stake.
+	if !c.IsInitiator {
+		if f.cfg.ResponderConfTimeout == 0 {
+			return MaxWaitNumBlocksFundingConf
+		}
+
+		return f.cfg.ResponderConfTimeout
+	}
+
+	// As the initiator, we can only release the inputs of funding
+	// transactions that were created by our wallet.
+	if !c.ChanType.HasFundingTx() || c.FundingTxn == nil {
+		return 0
+	}
+
+	return f.cfg.InitiatorConfTimeout
+}
+
+// releaseFundingInputs removes the unconfirmed funding transaction of a
+// channel we initiated from our wallet, releasing its inputs.
+func (f *Manager) releaseFundingInputs(c *channeldb.OpenChannel) {
+	if !c.ChanType.HasFundingTx() || c.FundingTxn == nil {
+		return
+	}
+
+	err := f.cfg.Wallet.RemoveDescendants(c.FundingTxn)
+	if err != nil {
+		log.Errorf("Unable to release inputs of funding tx for "+
+			"ChannelPoint(%v): %v", c.FundingOutpoint, err)
+
+		return
+	}
+
+	log.Warnf("Released inputs of unconfirmed funding tx for "+
+		"ChannelPoint(%v), they should be double spent to ensure the "+
+		"funding tx can't confirm anymore", c.FundingOutpoint)
+}
+
+// PendingChannelStatuses returns the status of all channels that are still
+// pending open, including the ones that are still being negotiated with the
+// peer.
+func (f *Manager) PendingChannelStatuses(
+	bestHeight uint32) ([]*PendingChannelStatus, error) {
+
+	var statuses []*PendingChannelStatus
+
+	f.resMtx.RLock()
+	for _, pendingReservations := range f.activeReservations {
+		for _, resCtx := range pendingReservations {
+			statuses = append(
+				statuses, f.reservationStatus(resCtx),
+			)
+		}
+	}
+	f.resMtx.RUnlock()
+
+	pendingChans, err := f.cfg.ChannelDB.FetchPendingChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range pendingChans {
+		status := &PendingChannelStatus{
+			Stage:     StageAwaitingConfirmation,
+			PeerKey:   c.IdentityPub,
+			ChanPoint: c.FundingOutpoint,
+			Initiator: c.IsInitiator,
+		}
+
+		broadcastHeight := c.BroadcastHeight()
+		if bestHeight > broadcastHeight {
+			status.AgeBlocks = bestHeight - broadcastHeight
+		}
+
+		confTimeout := f.confTimeout(c)
+		switch {
+		case c.IsZeroConf():
+			status.NextAction = "usable as zero-conf channel, " +
+				"waiting for funding confirmation"
+
+		case confTimeout == 0:
+			status.NextAction = "waiting for funding " +
+				"confirmation, no timeout"
+
+		default:
+			status.TimeoutHeight = broadcastHeight + confTimeout
+			action := "forget"
+			if c.IsInitiator {
+				action = "abandon and release funding inputs"
+			}
+			status.NextAction = fmt.Sprintf("waiting for funding "+
+				"confirmation, %s at height %d", action,
+				status.TimeoutHeight)
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// reservationStatus returns the status of a channel that is still being
+// negotiated with the peer.
+func (f *Manager) reservationStatus(
+	resCtx *reservationWithCtx) *PendingChannelStatus {
+
+	status := &PendingChannelStatus{
+		Stage:     StageNegotiating,
+		PeerKey:   resCtx.peer.IdentityKey(),
+		ChanPoint: *resCtx.reservation.FundingOutpoint(),
+		Initiator: resCtx.initiator,
+	}
+
+	switch {
+	case resCtx.isLocked():
+		status.NextAction = "waiting for funding transaction to be " +
+			"published"
+
+	case resCtx.reservation.IsPsbt():
+		resCtx.updateMtx.RLock()
+		status.IdleTime = time.Since(resCtx.lastUpdated)
+		resCtx.updateMtx.RUnlock()
+
+		status.NextAction = "waiting for PSBT to be finalized, no " +
+			"timeout"
+
+	default:
+		resCtx.updateMtx.RLock()
+		status.IdleTime = time.Since(resCtx.lastUpdated)
+		resCtx.updateMtx.RUnlock()
+
+		remaining := f.cfg.ReservationTimeout - status.IdleTime
+		if remaining < 0 {
+			remaining = 0
+		}
+		status.NextAction = fmt.Sprintf("waiting for peer, cancel "+
+			"and release reserved inputs in %v",
+			remaining.Round(time.Second))
+	}
+
+	return status
+}
@@ -0,0 +1,87 @@
+package funding
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConfTimeout tests that the confirmation timeout of pending channels is
+// determined by the channel's role and type.
+func TestConfTimeout(t *testing.T) {
+	t.Parallel()
+
+	walletFunded := channeldb.SingleFunderTweaklessBit
+	fundingTx := wire.NewMsgTx(2)
+
+	testCases := []struct {
+		name       string
+		cfg        Config
+		channel    *channeldb.OpenChannel
+		expTimeout uint32
+	}{{
+		name: "responder default",
+		channel: &channeldb.OpenChannel{
+			ChanType: walletFunded,
+		},
+		expTimeout: MaxWaitNumBlocksFundingConf,
+	}, {
+		name: "responder configured",
+		cfg: Config{
+			ResponderConfTimeout: 100,
+		},
+		channel: &channeldb.OpenChannel{
+			ChanType: walletFunded,
+		},
+		expTimeout: 100,
+	}, {
+		name: "responder zero conf",
+		channel: &channeldb.OpenChannel{
+			ChanType: walletFunded | channeldb.ZeroConfBit,
+		},
+		expTimeout: 0,
+	}, {
+		name: "initiator disabled",
+		channel: &channeldb.OpenChannel{
+			ChanType:    walletFunded,
+			IsInitiator: true,
+			FundingTxn:  fundingTx,
+		},
+		expTimeout: 0,
+	}, {
+		name: "initiator configured",
+		cfg: Config{
+			InitiatorConfTimeout: 1000,
+		},
+		channel: &channeldb.OpenChannel{
+			ChanType:    walletFunded,
+			IsInitiator: true,
+			FundingTxn:  fundingTx,
+		},
+		expTimeout: 1000,
+	}, {
+		name: "initiator externally funded",
+		cfg: Config{
+			InitiatorConfTimeout: 1000,
+		},
+		channel: &channeldb.OpenChannel{
+			ChanType: walletFunded |
+				channeldb.NoFundingTxBit,
+			IsInitiator: true,
+		},
+		expTimeout: 0,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := &Manager{cfg: &tc.cfg}
+			require.Equal(
+				t, tc.expTimeout, f.confTimeout(tc.channel),
+			)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package htlcswitch
 import (
 	"bytes"
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/go-errors/errors"
@@ -122,6 +123,12 @@ type CircuitMap interface {
 	// circuits that use the given payment hash.
 	LookupByPaymentHash(hash [32]byte) []*PaymentCircuit
 
+	// LookupUnresolvedByChannel queries the circuit map and returns all
+	// open circuits that use the given outgoing channel, and for which no
+	// settle or fail has been received yet.
+	LookupUnresolvedByChannel(
+		chanID lnwire.ShortChannelID) []*PaymentCircuit
+
 	// NumPending returns the total number of active circuits added by
 	// CommitCircuits.
 	NumPending() int
@@ -785,6 +792,37 @@ func (cm *circuitMap) LookupByPaymentHash(hash [32]byte) []*PaymentCircuit {
 	return circuits
 }
 
+// LookupUnresolvedByChannel returns all open circuits that use the given
+// outgoing channel and haven't been marked as closing yet. The circuits are
+// ordered by their outgoing htlc index.
+func (cm *circuitMap) LookupUnresolvedByChannel(
+	chanID lnwire.ShortChannelID) []*PaymentCircuit {
+
+	cm.mtx.RLock()
+	defer cm.mtx.RUnlock()
+
+	var circuits []*PaymentCircuit
+	for outKey, circuit := range cm.opened {
+		if outKey.ChanID != chanID {
+			continue
+		}
+
+		// Skip any circuit for which a settle or fail is already on
+		// its way back to the incoming link.
+		if _, ok := cm.closed[circuit.Incoming]; ok {
+			continue
+		}
+
+		circuits = append(circuits, circuit)
+	}
+
+	sort.Slice(circuits, func(i, j int) bool {
+		return circuits[i].Outgoing.HtlcID < circuits[j].Outgoing.HtlcID
+	})
+
+	return circuits
+}
+
 // CommitCircuits accepts any number of circuits and persistently adds them to
 // the switch's circuit map. The method returns a list of circuits that had not
 // been seen prior by the switch. A link should only forward HTLCs corresponding
@@ -54,6 +54,20 @@ const (
 	// OutgoingFailureForwardsDisabled is returned when the switch is
 	// configured to disallow forwards.
 	OutgoingFailureForwardsDisabled
+
+	// OutgoingFailureExpiryTooSoon is returned when the outgoing htlc
+	// expires within our outgoing cltv reject delta.
+	OutgoingFailureExpiryTooSoon
+
+	// OutgoingFailureExpiryTooFar is returned when the outgoing htlc
+	// expires further in the future than our maximum outgoing cltv
+	// expiry.
+	OutgoingFailureExpiryTooFar
+
+	// OutgoingFailureIncorrectCltvExpiry is returned when the difference
+	// between the incoming and outgoing htlc expiry is smaller than the
+	// time lock delta of our policy.
+	OutgoingFailureIncorrectCltvExpiry
 )
 
 // FailureString returns the string representation of a failure detail.
@@ -91,6 +105,15 @@ func (fd OutgoingFailure) FailureString() string {
 	case OutgoingFailureForwardsDisabled:
 		return "node configured to disallow forwards"
 
+	case OutgoingFailureExpiryTooSoon:
+		return "outgoing htlc expires too soon"
+
+	case OutgoingFailureExpiryTooFar:
+		return "outgoing htlc expires too far in the future"
+
+	case OutgoingFailureIncorrectCltvExpiry:
+		return "htlc expiry delta below time lock delta"
+
 	default:
 		return "unknown failure detail"
 	}
@@ -1,23 +1,234 @@
 package htlcswitch
 
 import (
+	"container/heap"
 	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/lnwire"
 )
 
+const (
+	// heldHtlcBaseSize is the approximate number of bytes that a held
+	// forward occupies in memory, excluding its onion blob and custom
+	// records.
+	heldHtlcBaseSize = 512
+
+	// customRecordOverhead is the approximate number of bytes occupied by
+	// a single custom record in addition to its value.
+	customRecordOverhead = 16
+)
+
+// HeldHtlcLinkStats describes the intercepted forwards held for a single
+// incoming link.
+type HeldHtlcLinkStats struct {
+	// NumHeld is the number of forwards held for the link.
+	NumHeld int
+
+	// MemoryUsage is the approximate number of bytes used by the forwards
+	// held for the link.
+	MemoryUsage uint64
+}
+
+// HeldHtlcStats describes the memory consumed by held intercepted forwards.
+type HeldHtlcStats struct {
+	// NumHeld is the total number of held forwards.
+	NumHeld int
+
+	// MemoryUsage is the approximate number of bytes used by all held
+	// forwards.
+	MemoryUsage uint64
+
+	// NumEvicted is the number of held forwards that have been failed
+	// back because the memory ceiling was reached.
+	NumEvicted uint64
+
+	// Links holds the stats of the held forwards per incoming link.
+	Links map[lnwire.ShortChannelID]HeldHtlcLinkStats
+}
+
+// heldHtlcSize returns the approximate number of bytes that the given held
+// forward occupies in memory.
+func heldHtlcSize(fwd InterceptedForward) uint64 {
+	packet := fwd.Packet()
+
+	size := uint64(heldHtlcBaseSize + len(packet.OnionBlob))
+	for _, value := range packet.InOnionCustomRecords {
+		size += uint64(customRecordOverhead + len(value))
+	}
+	for _, value := range packet.InWireCustomRecords {
+		size += uint64(customRecordOverhead + len(value))
+	}
+
+	return size
+}
+
+// undeliveredHeap is a min-heap of the held forwards that haven't been
+// delivered to an interceptor yet, ordered by their incoming amount.
+type undeliveredHeap struct {
+	keys  []models.CircuitKey
+	fwds  map[models.CircuitKey]InterceptedForward
+	index map[models.CircuitKey]int
+}
+
+// Len returns the number of forwards in the heap.
+//
+// NOTE: This is part of the heap.Interface implementation.
+func (u *undeliveredHeap) Len() int {
+	return len(u.keys)
+}
+
+// Less returns whether the forward at index i has a lower incoming amount
+// than the forward at index j.
+//
+// NOTE: This is part of the heap.Interface implementation.
+func (u *undeliveredHeap) Less(i, j int) bool {
+	return u.fwds[u.keys[i]].Packet().IncomingAmount <
+		u.fwds[u.keys[j]].Packet().IncomingAmount
+}
+
+// Swap swaps the forwards at the given indexes.
+//
+// NOTE: This is part of the heap.Interface implementation.
+func (u *undeliveredHeap) Swap(i, j int) {
+	u.keys[i], u.keys[j] = u.keys[j], u.keys[i]
+	u.index[u.keys[i]] = i
+	u.index[u.keys[j]] = j
+}
+
+// Push adds the circuit key of a forward to the heap.
+//
+// NOTE: This is part of the heap.Interface implementation.
+func (u *undeliveredHeap) Push(x interface{}) {
+	key, _ := x.(models.CircuitKey)
+
+	u.index[key] = len(u.keys)
+	u.keys = append(u.keys, key)
+}
+
+// Pop removes the circuit key at the end of the heap.
+//
+// NOTE: This is part of the heap.Interface implementation.
+func (u *undeliveredHeap) Pop() interface{} {
+	key := u.keys[len(u.keys)-1]
+	u.keys = u.keys[:len(u.keys)-1]
+	delete(u.index, key)
+
+	return key
+}
+
 // heldHtlcSet keeps track of outstanding intercepted forwards. It exposes
 // several methods to manipulate the underlying map structure in a consistent
-// way.
+// way. It also accounts for the memory consumed by the held forwards, which
+// can be queried concurrently.
 type heldHtlcSet struct {
 	set map[models.CircuitKey]InterceptedForward
+
+	// sizes holds the memory usage of each held forward, as determined
+	// when it was added to the set.
+	sizes map[models.CircuitKey]uint64
+
+	// undelivered holds the new forwards that haven't been delivered to
+	// an interceptor yet. Only those can safely be evicted, as an
+	// interceptor may already have acted on any other held forward.
+	undelivered *undeliveredHeap
+
+	// statsMtx guards the stats below, which are read from outside of the
+	// goroutine that manipulates the set.
+	statsMtx sync.Mutex
+
+	// memoryUsage is the total memory usage of all held forwards.
+	memoryUsage uint64
+
+	// numEvicted is the number of forwards evicted from the set.
+	numEvicted uint64
+
+	// links holds the stats of the held forwards per incoming link.
+	links map[lnwire.ShortChannelID]HeldHtlcLinkStats
 }
 
 func newHeldHtlcSet() *heldHtlcSet {
+	set := make(map[models.CircuitKey]InterceptedForward)
+
 	return &heldHtlcSet{
-		set: make(map[models.CircuitKey]InterceptedForward),
+		set:   set,
+		sizes: make(map[models.CircuitKey]uint64),
+		undelivered: &undeliveredHeap{
+			fwds:  set,
+			index: make(map[models.CircuitKey]int),
+		},
+		links: make(map[lnwire.ShortChannelID]HeldHtlcLinkStats),
+	}
+}
+
+// add adds the forward to the set and accounts for its memory usage.
+func (h *heldHtlcSet) add(key models.CircuitKey, fwd InterceptedForward) {
+	size := heldHtlcSize(fwd)
+
+	h.set[key] = fwd
+	h.sizes[key] = size
+
+	h.statsMtx.Lock()
+	defer h.statsMtx.Unlock()
+
+	link := h.links[key.ChanID]
+	link.NumHeld++
+	link.MemoryUsage += size
+	h.links[key.ChanID] = link
+
+	h.memoryUsage += size
+}
+
+// remove removes the forward from the set and releases its memory usage.
+func (h *heldHtlcSet) remove(key models.CircuitKey) {
+	size := h.sizes[key]
+
+	// The heap looks up the forwards in the set, so the forward needs to
+	// be removed from the heap first.
+	if idx, ok := h.undelivered.index[key]; ok {
+		heap.Remove(h.undelivered, idx)
+	}
+
+	delete(h.set, key)
+	delete(h.sizes, key)
+
+	h.statsMtx.Lock()
+	defer h.statsMtx.Unlock()
+
+	link := h.links[key.ChanID]
+	link.NumHeld--
+	link.MemoryUsage -= size
+	if link.NumHeld == 0 {
+		delete(h.links, key.ChanID)
+	} else {
+		h.links[key.ChanID] = link
 	}
+
+	h.memoryUsage -= size
+}
+
+// stats returns a snapshot of the memory consumed by the held forwards. It is
+// safe to be called concurrently with the other methods of the set.
+func (h *heldHtlcSet) stats() HeldHtlcStats {
+	h.statsMtx.Lock()
+	defer h.statsMtx.Unlock()
+
+	stats := HeldHtlcStats{
+		MemoryUsage: h.memoryUsage,
+		NumEvicted:  h.numEvicted,
+		Links: make(
+			map[lnwire.ShortChannelID]HeldHtlcLinkStats,
+			len(h.links),
+		),
+	}
+	for chanID, link := range h.links {
+		stats.NumHeld += link.NumHeld
+		stats.Links[chanID] = link
+	}
+
+	return stats
 }
 
 // forEach iterates over all held forwards and calls the given callback for each
@@ -30,11 +241,11 @@ func (h *heldHtlcSet) forEach(cb func(InterceptedForward)) {
 
 // popAll calls the callback for each forward and removes them from the set.
 func (h *heldHtlcSet) popAll(cb func(InterceptedForward)) {
-	for _, fwd := range h.set {
+	for key, fwd := range h.set {
 		cb(fwd)
-	}
 
-	h.set = make(map[models.CircuitKey]InterceptedForward)
+		h.remove(key)
+	}
 }
 
 // popAutoFails calls the callback for each forward that has an auto-fail height
@@ -47,7 +258,50 @@ func (h *heldHtlcSet) popAutoFails(height uint32, cb func(InterceptedForward)) {
 
 		cb(fwd)
 
-		delete(h.set, key)
+		h.remove(key)
+	}
+}
+
+// popLowestValue calls the callback for the undelivered forwards with the
+// lowest incoming amount and removes them from the set, until the memory
+// consumed by the set doesn't exceed the given limit anymore. Forwards that
+// were delivered to an interceptor or pushed as replay are never evicted, so
+// the memory usage may remain above the limit.
+func (h *heldHtlcSet) popLowestValue(maxMemory uint64,
+	cb func(InterceptedForward)) {
+
+	for h.undelivered.Len() > 0 {
+		h.statsMtx.Lock()
+		memoryUsage := h.memoryUsage
+		h.statsMtx.Unlock()
+
+		if memoryUsage <= maxMemory {
+			return
+		}
+
+		//nolint:forcetypeassert
+		key := heap.Pop(h.undelivered).(models.CircuitKey)
+		fwd := h.set[key]
+
+		cb(fwd)
+
+		h.remove(key)
+
+		h.statsMtx.Lock()
+		h.numEvicted++
+		h.statsMtx.Unlock()
+	}
+}
+
+// popUndelivered calls the callback for each forward that hasn't been
+// delivered to an interceptor yet and marks it as delivered. The forwards
+// remain in the set.
+func (h *heldHtlcSet) popUndelivered(cb func(InterceptedForward)) {
+	for h.undelivered.Len() > 0 {
+		//nolint:forcetypeassert
+		key := heap.Pop(h.undelivered).(models.CircuitKey)
+
+		cb(h.set[key])
 	}
 }
 
@@ -58,7 +312,7 @@ func (h *heldHtlcSet) pop(key models.CircuitKey) (InterceptedForward, error) {
 		return nil, fmt.Errorf("fwd %v not found", key)
 	}
 
-	delete(h.set, key)
+	h.remove(key)
 
 	return intercepted, nil
 }
@@ -83,7 +337,22 @@ func (h *heldHtlcSet) push(key models.CircuitKey,
 		return errors.New("htlc already exists in set")
 	}
 
-	h.set[key] = fwd
+	h.add(key, fwd)
+
+	return nil
+}
+
+// pushUndelivered adds the specified new forward to the set, marked as not
+// delivered to an interceptor yet. Until it is delivered, it may be evicted
+// by popLowestValue. An error is returned if the forward exists already.
+func (h *heldHtlcSet) pushUndelivered(key models.CircuitKey,
+	fwd InterceptedForward) error {
+
+	if err := h.push(key, fwd); err != nil {
+		return err
+	}
+
+	heap.Push(h.undelivered, key)
 
 	return nil
 }
@@ -5,6 +5,7 @@ import (
 
 	"github.com/lightningnetwork/lnd/channeldb/models"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
 	"github.com/stretchr/testify/require"
 )
 
@@ -37,7 +38,8 @@ func TestHeldHtlcSet(t *testing.T) {
 
 	// Test pushing a forward.
 	fwd := &interceptedForward{
-		htlc: &lnwire.UpdateAddHTLC{},
+		packet: &htlcPacket{},
+		htlc:   &lnwire.UpdateAddHTLC{},
 	}
 	require.NoError(t, set.push(key, fwd))
 
@@ -124,3 +126,113 @@ func TestHeldHtlcSetAutoFails(t *testing.T) {
 		},
 	)
 }
+
+func TestHeldHtlcSetMemory(t *testing.T) {
+	set := newHeldHtlcSet()
+
+	newFwd := func(chanID uint64, amt lnwire.MilliSatoshi,
+		records record.CustomSet) (models.CircuitKey,
+		*interceptedForward) {
+
+		key := models.CircuitKey{
+			ChanID: lnwire.NewShortChanIDFromInt(chanID),
+			HtlcID: uint64(amt),
+		}
+		fwd := &interceptedForward{
+			packet: &htlcPacket{
+				incomingChanID:       key.ChanID,
+				incomingHTLCID:       key.HtlcID,
+				incomingAmount:       amt,
+				inOnionCustomRecords: records,
+			},
+			htlc: &lnwire.UpdateAddHTLC{},
+		}
+
+		return key, fwd
+	}
+
+	key1, fwd1 := newFwd(1, 3000, nil)
+	key2, fwd2 := newFwd(1, 1000, record.CustomSet{
+		record.CustomTypeStart: make([]byte, 100),
+	})
+	key3, fwd3 := newFwd(2, 2000, nil)
+	key4, fwd4 := newFwd(2, 500, nil)
+
+	// The first forward was delivered to an interceptor already, the
+	// others are new.
+	require.NoError(t, set.push(key1, fwd1))
+	require.NoError(t, set.pushUndelivered(key2, fwd2))
+	require.NoError(t, set.pushUndelivered(key3, fwd3))
+
+	baseSize := uint64(heldHtlcBaseSize + lnwire.OnionPacketSize)
+	recordSize := uint64(customRecordOverhead + 100)
+
+	stats := set.stats()
+	require.Equal(t, 3, stats.NumHeld)
+	require.Equal(t, 3*baseSize+recordSize, stats.MemoryUsage)
+	require.Equal(t, HeldHtlcLinkStats{
+		NumHeld:     2,
+		MemoryUsage: 2*baseSize + recordSize,
+	}, stats.Links[key1.ChanID])
+	require.Equal(t, HeldHtlcLinkStats{
+		NumHeld:     1,
+		MemoryUsage: baseSize,
+	}, stats.Links[key3.ChanID])
+
+	// Staying within the limit shouldn't evict anything.
+	set.popLowestValue(stats.MemoryUsage, func(_ InterceptedForward) {
+		require.Fail(t, "unexpected fwd")
+	})
+
+	// Lowering the limit should evict the undelivered forwards with the
+	// lowest value first. The delivered forward is kept, even though it
+	// has the highest value.
+	var evicted []InterceptedForward
+	set.popLowestValue(baseSize, func(fwd InterceptedForward) {
+		evicted = append(evicted, fwd)
+	})
+	require.Equal(t, []InterceptedForward{fwd2, fwd3}, evicted)
+	require.True(t, set.exists(key1))
+
+	stats = set.stats()
+	require.Equal(t, 1, stats.NumHeld)
+	require.Equal(t, baseSize, stats.MemoryUsage)
+	require.EqualValues(t, 2, stats.NumEvicted)
+	require.Len(t, stats.Links, 1)
+
+	// A delivered forward is never evicted, even if the set exceeds the
+	// limit.
+	set.popLowestValue(0, func(_ InterceptedForward) {
+		require.Fail(t, "unexpected fwd")
+	})
+	require.True(t, set.exists(key1))
+
+	// A forward that is resolved before it is delivered is removed from
+	// the undelivered forwards as well.
+	require.NoError(t, set.pushUndelivered(key4, fwd4))
+	require.NoError(t, set.pushUndelivered(key3, fwd3))
+	_, err := set.pop(key4)
+	require.NoError(t, err)
+
+	var delivered []InterceptedForward
+	set.popUndelivered(func(fwd InterceptedForward) {
+		delivered = append(delivered, fwd)
+	})
+	require.Equal(t, []InterceptedForward{fwd3}, delivered)
+
+	// Delivered forwards can't be evicted anymore.
+	set.popLowestValue(0, func(_ InterceptedForward) {
+		require.Fail(t, "unexpected fwd")
+	})
+
+	// Popping the remaining forwards should release all memory.
+	_, err = set.pop(key1)
+	require.NoError(t, err)
+	_, err = set.pop(key3)
+	require.NoError(t, err)
+
+	stats = set.stats()
+	require.Zero(t, stats.NumHeld)
+	require.Zero(t, stats.MemoryUsage)
+	require.Empty(t, stats.Links)
+}
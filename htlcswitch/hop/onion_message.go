@@ -0,0 +1,148 @@
+package hop
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+)
+
+var (
+	// ErrOnionMessageSize is returned when an onion message doesn't carry
+	// an onion packet of the size that we're able to process.
+	ErrOnionMessageSize = fmt.Errorf("onion message packets must be %d "+
+		"bytes", onionMessagePacketSize)
+
+	// ErrNoEncryptedData is returned when the payload of an onion message
+	// doesn't include the encrypted data of its blinded path.
+	ErrNoEncryptedData = errors.New("onion message payload has no " +
+		"encrypted data")
+
+	// ErrNoNextHop is returned when the route data of an onion message
+	// that is to be relayed doesn't specify the next node.
+	ErrNoNextHop = errors.New("onion message route data has no next hop")
+)
+
+// onionMessagePacketSize is the size of the onion packets of onion messages
+// that we can process. Onion messages may carry larger packets as well, but
+// our sphinx implementation only supports the packet size of htlc onions.
+const onionMessagePacketSize = lnwire.OnionPacketSize
+
+// OnionMessageHop is an onion message that was decrypted by this node, which
+// is either its final recipient or relays it to the next node of its blinded
+// path.
+type OnionMessageHop struct {
+	// Payload is the payload that the sender included for this node.
+	Payload *lnwire.OnionMessagePayload
+
+	// RouteData is the decrypted route data of this node's hop in the
+	// blinded path.
+	RouteData *record.BlindedRouteData
+
+	// NextMsg is the onion message that's to be sent to the next node. It
+	// is nil if this node is the final recipient of the message.
+	NextMsg *lnwire.OnionMessage
+}
+
+// IsFinal returns true if this node is the final recipient of the message.
+func (o *OnionMessageHop) IsFinal() bool {
+	return o.NextMsg == nil
+}
+
+// ProcessOnionMessage decrypts our layer of the onion packet of the given
+// onion message and the route data of our hop in its blinded path. If the
+// message is to be relayed, the onion message for the next node is derived as
+// well.
+//
+// NOTE: Onion messages don't move any funds, so unlike htlc onions they
+// aren't added to the replay log.
+func (p *OnionProcessor) ProcessOnionMessage(
+	msg *lnwire.OnionMessage) (*OnionMessageHop, error) {
+
+	if len(msg.OnionBlob) != onionMessagePacketSize {
+		return nil, ErrOnionMessageSize
+	}
+
+	onionPkt := &sphinx.OnionPacket{}
+	err := onionPkt.Decode(bytes.NewReader(msg.OnionBlob))
+	if err != nil {
+		return nil, err
+	}
+
+	// The onion is encrypted to our blinded node ID, so we'll need the
+	// path key of the message to derive the shared secret. Onion messages
+	// don't have any associated data.
+	sphinxPacket, err := p.router.ReconstructOnionPacket(
+		onionPkt, nil, sphinx.WithBlindingPoint(msg.BlindingPoint),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := &lnwire.OnionMessagePayload{}
+	err = payload.Decode(bytes.NewReader(sphinxPacket.Payload.Payload))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(payload.EncryptedData) == 0 {
+		return nil, ErrNoEncryptedData
+	}
+
+	// The data is decrypted in place, so we'll pass a copy to keep the
+	// payload intact.
+	decrypted, err := p.router.DecryptBlindedHopData(
+		msg.BlindingPoint, bytes.Clone(payload.EncryptedData),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDecodeFailed, err)
+	}
+
+	routeData, err := record.DecodeBlindedRouteData(
+		bytes.NewReader(decrypted),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDecodeFailed, err)
+	}
+
+	hop := &OnionMessageHop{
+		Payload:   payload,
+		RouteData: routeData,
+	}
+	if sphinxPacket.Action == sphinx.ExitNode {
+		return hop, nil
+	}
+
+	if routeData.NextNodeID.IsNone() &&
+		routeData.ShortChannelID.IsNone() {
+
+		return nil, ErrNoNextHop
+	}
+
+	// The path key of the next node is derived from ours, unless the
+	// creator of the path overrides it, which is how two blinded paths
+	// are concatenated.
+	nextBlinding, err := p.router.NextEphemeral(msg.BlindingPoint)
+	if err != nil {
+		return nil, err
+	}
+	routeData.NextBlindingOverride.WhenSomeV(func(key *btcec.PublicKey) {
+		nextBlinding = key
+	})
+
+	var nextOnion bytes.Buffer
+	if err := sphinxPacket.NextPacket.Encode(&nextOnion); err != nil {
+		return nil, err
+	}
+
+	hop.NextMsg = &lnwire.OnionMessage{
+		BlindingPoint: nextBlinding,
+		OnionBlob:     nextOnion.Bytes(),
+	}
+
+	return hop, nil
+}
@@ -0,0 +1,138 @@
+package hop
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/tlv"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestOnionProcessor returns an onion processor for the given node key.
+func newTestOnionProcessor(key *btcec.PrivateKey) *OnionProcessor {
+	return NewOnionProcessor(sphinx.NewRouter(
+		&sphinx.PrivKeyECDH{PrivKey: key}, sphinx.NewMemoryReplayLog(),
+	))
+}
+
+// TestProcessOnionMessage tests that an onion message sent along a blinded
+// path is relayed by the introduction node and decrypted by the recipient.
+func TestProcessOnionMessage(t *testing.T) {
+	t.Parallel()
+
+	aliceKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	bobKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	// Bob creates a blinded path with Alice as introduction node.
+	aliceData, err := record.EncodeBlindedRouteData(
+		&record.BlindedRouteData{
+			NextNodeID: tlv.SomeRecordT(
+				tlv.NewPrimitiveRecord[tlv.TlvType4](
+					bobKey.PubKey(),
+				),
+			),
+		},
+	)
+	require.NoError(t, err)
+
+	pathID := []byte{1, 2, 3}
+	bobData, err := record.EncodeBlindedRouteData(
+		&record.BlindedRouteData{
+			PathID: tlv.SomeRecordT(
+				tlv.NewPrimitiveRecord[tlv.TlvType6](pathID),
+			),
+		},
+	)
+	require.NoError(t, err)
+
+	pathKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	blindedPath, err := sphinx.BuildBlindedPath(pathKey, []*sphinx.HopInfo{
+		{NodePub: aliceKey.PubKey(), PlainText: aliceData},
+		{NodePub: bobKey.PubKey(), PlainText: bobData},
+	})
+	require.NoError(t, err)
+
+	// The sender then creates an onion to the blinded node IDs of the
+	// path, with an invoice request for Bob.
+	payloads := []*lnwire.OnionMessagePayload{
+		{
+			EncryptedData: blindedPath.BlindedHops[0].CipherText,
+		},
+		{
+			EncryptedData:  blindedPath.BlindedHops[1].CipherText,
+			InvoiceRequest: []byte{4, 5, 6},
+		},
+	}
+
+	var path sphinx.PaymentPath
+	for i, payload := range payloads {
+		var b bytes.Buffer
+		require.NoError(t, payload.Encode(&b))
+
+		hopPayload, err := sphinx.NewTLVHopPayload(b.Bytes())
+		require.NoError(t, err)
+
+		path[i] = sphinx.OnionHop{
+			NodePub:    *blindedPath.BlindedHops[i].BlindedNodePub,
+			HopPayload: hopPayload,
+		}
+	}
+
+	sessionKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	onionPkt, err := sphinx.NewOnionPacket(
+		&path, sessionKey, nil, sphinx.DeterministicPacketFiller,
+	)
+	require.NoError(t, err)
+
+	var onionBlob bytes.Buffer
+	require.NoError(t, onionPkt.Encode(&onionBlob))
+
+	msg := &lnwire.OnionMessage{
+		BlindingPoint: blindedPath.BlindingPoint,
+		OnionBlob:     onionBlob.Bytes(),
+	}
+
+	// Alice relays the message to Bob.
+	aliceHop, err := newTestOnionProcessor(aliceKey).ProcessOnionMessage(
+		msg,
+	)
+	require.NoError(t, err)
+	require.False(t, aliceHop.IsFinal())
+	require.Equal(
+		t, bobKey.PubKey(),
+		aliceHop.RouteData.NextNodeID.UnwrapOrFailV(t),
+	)
+	require.Len(t, aliceHop.NextMsg.OnionBlob, onionMessagePacketSize)
+
+	// Bob is the recipient of the message.
+	bobHop, err := newTestOnionProcessor(bobKey).ProcessOnionMessage(
+		aliceHop.NextMsg,
+	)
+	require.NoError(t, err)
+	require.True(t, bobHop.IsFinal())
+	require.Equal(t, payloads[1], bobHop.Payload)
+	require.Equal(t, pathID, bobHop.RouteData.PathID.UnwrapOrFailV(t))
+
+	// The message can't be processed by a node that's not on the path.
+	_, err = newTestOnionProcessor(bobKey).ProcessOnionMessage(msg)
+	require.Error(t, err)
+
+	// Onion packets of other sizes aren't supported.
+	_, err = newTestOnionProcessor(aliceKey).ProcessOnionMessage(
+		&lnwire.OnionMessage{
+			BlindingPoint: blindedPath.BlindingPoint,
+			OnionBlob:     onionBlob.Bytes()[:100],
+		},
+	)
+	require.ErrorIs(t, err, ErrOnionMessageSize)
+}
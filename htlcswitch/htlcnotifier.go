@@ -64,6 +64,10 @@ type HtlcNotifier struct {
 	// for timestamp mocking in tests.
 	now func() time.Time
 
+	// fwdTracker tracks the timings of in-flight htlcs and aggregates
+	// their latencies per channel.
+	fwdTracker *forwardTracker
+
 	ntfnServer *subscribe.Server
 }
 
@@ -73,6 +77,7 @@ type HtlcNotifier struct {
 func NewHtlcNotifier(now func() time.Time) *HtlcNotifier {
 	return &HtlcNotifier{
 		now:        now,
+		fwdTracker: newForwardTracker(),
 		ntfnServer: subscribe.NewServer(),
 	}
 }
@@ -108,6 +113,13 @@ func (h *HtlcNotifier) SubscribeHtlcEvents() (*subscribe.Client, error) {
 	return h.ntfnServer.Subscribe()
 }
 
+// ForwardLatencies returns the forward latency histograms of all channels
+// that htlcs were sent, received or forwarded over since startup. This allows
+// finding slow peers that delay the resolution of our htlcs.
+func (h *HtlcNotifier) ForwardLatencies() ChannelLatencies {
+	return h.fwdTracker.snapshot()
+}
+
 // HtlcKey uniquely identifies the htlc.
 type HtlcKey struct {
 	// IncomingCircuit is the channel an htlc id of the incoming htlc.
@@ -148,6 +160,10 @@ type HtlcInfo struct {
 
 	// OutgoingAmt is the amount of the htlc on our outgoing channel.
 	OutgoingAmt lnwire.MilliSatoshi
+
+	// ReceivedTime is the time the htlc was locked in on our incoming
+	// channel. It is zero for sends that originate at our node.
+	ReceivedTime time.Time
 }
 
 // String returns a string representation of a htlc.
@@ -273,6 +289,11 @@ type ForwardingFailEvent struct {
 	// receive, or as part of a forward.
 	HtlcEventType
 
+	// ForwardTiming holds the time the htlc was received and forwarded.
+	// It is nil if the forward wasn't tracked, e.g. because it happened
+	// before a restart.
+	ForwardTiming *ForwardTiming
+
 	// Timestamp is the time when the forwarding failure was received.
 	Timestamp time.Time
 }
@@ -293,6 +314,11 @@ type SettleEvent struct {
 	// receive, or as part of a forward.
 	HtlcEventType
 
+	// ForwardTiming holds the time the htlc was received and forwarded.
+	// It is nil for receives and if the forward wasn't tracked, e.g.
+	// because it happened before a restart.
+	ForwardTiming *ForwardTiming
+
 	// Timestamp is the time when this htlc was settled.
 	Timestamp time.Time
 }
@@ -323,6 +349,11 @@ func (h *HtlcNotifier) NotifyForwardingEvent(key HtlcKey, info HtlcInfo,
 		Timestamp:     h.now(),
 	}
 
+	h.fwdTracker.forwarded(key, ForwardTiming{
+		Received:  info.ReceivedTime,
+		Forwarded: event.Timestamp,
+	})
+
 	log.Tracef("Notifying forward event: %v over %v, %v", eventType, key,
 		info)
 
@@ -347,6 +378,12 @@ func (h *HtlcNotifier) NotifyLinkFailEvent(key HtlcKey, info HtlcInfo,
 		Timestamp:     h.now(),
 	}
 
+	// A failure on our outgoing link can happen after the htlc was added
+	// to it, in which case it resolves a tracked forward.
+	if !incoming {
+		h.fwdTracker.resolved(key, event.Timestamp)
+	}
+
 	log.Tracef("Notifying link failure event: %v over %v, %v", eventType,
 		key, info)
 
@@ -367,6 +404,7 @@ func (h *HtlcNotifier) NotifyForwardingFailEvent(key HtlcKey,
 		HtlcEventType: eventType,
 		Timestamp:     h.now(),
 	}
+	event.ForwardTiming = h.fwdTracker.resolved(key, event.Timestamp)
 
 	log.Tracef("Notifying forwarding failure event: %v over %v", eventType,
 		key)
@@ -389,6 +427,7 @@ func (h *HtlcNotifier) NotifySettleEvent(key HtlcKey,
 		HtlcEventType: eventType,
 		Timestamp:     h.now(),
 	}
+	event.ForwardTiming = h.fwdTracker.resolved(key, event.Timestamp)
 
 	log.Tracef("Notifying settle event: %v over %v", eventType, key)
 
@@ -454,6 +493,7 @@ func newHtlcInfo(pkt *htlcPacket) HtlcInfo {
 		OutgoingTimeLock: pkt.outgoingTimeout,
 		IncomingAmt:      pkt.incomingAmount,
 		OutgoingAmt:      pkt.amount,
+		ReceivedTime:     pkt.receivedTime,
 	}
 }
 
@@ -66,6 +66,12 @@ type InterceptableSwitch struct {
 	// heldHtlcSet keeps track of outstanding intercepted forwards.
 	heldHtlcSet *heldHtlcSet
 
+	// maxHeldHtlcMemory is the maximum number of bytes that held forwards
+	// may occupy. If it is exceeded, the new forwards with the lowest
+	// value are failed back before they are offered to the interceptor. A
+	// value of zero disables the limit.
+	maxHeldHtlcMemory uint64
+
 	// cltvRejectDelta defines the number of blocks before the expiry of the
 	// htlc where we no longer intercept it and instead cancel it back.
 	cltvRejectDelta uint32
@@ -141,6 +147,10 @@ type FwdResolution struct {
 	// forwarding if Action is FwdActionResumeModified.
 	OutWireCustomRecords fn.Option[lnwire.CustomRecords]
 
+	// OutExpiry is the absolute CLTV expiry that is to be used for
+	// forwarding if Action is FwdActionResumeModified.
+	OutExpiry fn.Option[uint32]
+
 	// FailureMessage is the encrypted failure message that is to be passed
 	// back to the sender if action is FwdActionFail.
 	FailureMessage []byte
@@ -180,6 +190,13 @@ type InterceptableSwitchConfig struct {
 	// RequireInterceptor indicates whether processing should block if no
 	// interceptor is connected.
 	RequireInterceptor bool
+
+	// MaxHeldHtlcMemory is the maximum number of bytes that held
+	// intercepted forwards may occupy. Once it is exceeded, new forwards
+	// with the lowest incoming amount are failed back before they are
+	// offered to the interceptor. Forwards that the interceptor has seen
+	// are never failed back. A value of zero disables the limit.
+	MaxHeldHtlcMemory uint64
 }
 
 // NewInterceptableSwitch returns an instance of InterceptableSwitch.
@@ -198,6 +215,7 @@ func NewInterceptableSwitch(cfg *InterceptableSwitchConfig) (
 		onchainIntercepted:      make(chan InterceptedForward),
 		interceptorRegistration: make(chan ForwardInterceptor),
 		heldHtlcSet:             newHeldHtlcSet(),
+		maxHeldHtlcMemory:       cfg.MaxHeldHtlcMemory,
 		resolutionChan:          make(chan *fwdResolution),
 		requireInterceptor:      cfg.RequireInterceptor,
 		cltvRejectDelta:         cfg.CltvRejectDelta,
@@ -310,6 +328,13 @@ func (s *InterceptableSwitch) run() error {
 					)
 				}
 			}
+
+			// Enforce the memory ceiling before the new forwards
+			// are offered to the interceptor, which may act on
+			// them right away.
+			s.evictHeldHtlcs()
+			s.heldHtlcSet.popUndelivered(s.sendForward)
+
 			err := s.htlcSwitch.ForwardPackets(
 				packets.linkQuit, notIntercepted...,
 			)
@@ -363,6 +388,41 @@ func (s *InterceptableSwitch) failExpiredHtlcs() {
 	)
 }
 
+// evictHeldHtlcs fails back the new held forwards with the lowest value until
+// the memory they occupy doesn't exceed the configured ceiling anymore. Only
+// forwards that haven't been offered to an interceptor yet are failed back.
+// Replayed forwards and forwards that an interceptor has seen may still be
+// resolved by the interceptor, so they are kept even above the ceiling.
+func (s *InterceptableSwitch) evictHeldHtlcs() {
+	if s.maxHeldHtlcMemory == 0 {
+		return
+	}
+
+	s.heldHtlcSet.popLowestValue(
+		s.maxHeldHtlcMemory,
+		func(fwd InterceptedForward) {
+			packet := fwd.Packet()
+			log.Warnf("Held htlc memory ceiling of %v bytes "+
+				"reached, failing back htlc %v with amount %v",
+				s.maxHeldHtlcMemory, packet.IncomingCircuit,
+				packet.IncomingAmount)
+
+			err := fwd.FailWithCode(
+				lnwire.CodeTemporaryChannelFailure,
+			)
+			if err != nil {
+				log.Errorf("Cannot fail packet: %v", err)
+			}
+		},
+	)
+}
+
+// HeldHtlcStats returns a snapshot of the number of held intercepted forwards
+// and the memory they consume, in total and per incoming link.
+func (s *InterceptableSwitch) HeldHtlcStats() HeldHtlcStats {
+	return s.heldHtlcSet.stats()
+}
+
 func (s *InterceptableSwitch) sendForward(fwd InterceptedForward) {
 	err := s.interceptor(fwd.Packet())
 	if err != nil {
@@ -420,7 +480,7 @@ func (s *InterceptableSwitch) resolve(res *FwdResolution) error {
 	case FwdActionResumeModified:
 		return intercepted.ResumeModified(
 			res.InAmountMsat, res.OutAmountMsat,
-			res.OutWireCustomRecords,
+			res.OutWireCustomRecords, res.OutExpiry,
 		)
 
 	case FwdActionSettle:
@@ -592,8 +652,21 @@ func (s *InterceptableSwitch) forward(
 		return true, nil
 	}
 
-	// There is an interceptor registered. We can forward the packet right now.
-	// Hold it in the queue too to track what is outstanding.
+	// There is an interceptor registered. A new packet is held as
+	// undelivered, so that it can still be failed back if the memory
+	// ceiling is reached. It is offered to the interceptor once the whole
+	// batch has been processed.
+	if !isReplay {
+		err := s.heldHtlcSet.pushUndelivered(inKey, fwd)
+		if err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+
+	// A replayed packet can be forwarded right now. Hold it in the queue
+	// too to track what is outstanding.
 	if err := s.heldHtlcSet.push(inKey, fwd); err != nil {
 		return false, err
 	}
@@ -669,12 +742,25 @@ func (f *interceptedForward) Resume() error {
 // ResumeModified resumes the default behavior with field modifications. The
 // input amount (if provided) specifies that the value of the inbound HTLC
 // should be interpreted differently from the on-chain amount during further
-// validation. The presence of an output amount and/or custom records indicates
-// that those values should be modified on the outgoing HTLC.
+// validation. The presence of an output amount, custom records and/or expiry
+// indicates that those values should be modified on the outgoing HTLC. The
+// modified HTLC is still subject to the policy checks of the outgoing link.
 func (f *interceptedForward) ResumeModified(
 	inAmountMsat fn.Option[lnwire.MilliSatoshi],
 	outAmountMsat fn.Option[lnwire.MilliSatoshi],
-	outWireCustomRecords fn.Option[lnwire.CustomRecords]) error {
+	outWireCustomRecords fn.Option[lnwire.CustomRecords],
+	outExpiry fn.Option[uint32]) error {
+
+	// The outgoing HTLC must expire before the incoming one, otherwise we
+	// wouldn't be able to claim the incoming HTLC in time.
+	invalidExpiry := fn.MapOptionZ(outExpiry, func(expiry uint32) bool {
+		return expiry >= f.packet.incomingTimeout
+	})
+	if invalidExpiry {
+		return fmt.Errorf("outgoing expiry %v must be below incoming "+
+			"expiry %v", outExpiry.UnwrapOr(0),
+			f.packet.incomingTimeout)
+	}
 
 	// Convert the optional custom records to the correct type and validate
 	// them.
@@ -715,6 +801,11 @@ func (f *interceptedForward) ResumeModified(
 			htlc.Amount = amount
 		})
 
+		outExpiry.WhenSome(func(expiry uint32) {
+			f.packet.outgoingTimeout = expiry
+			htlc.Expiry = expiry
+		})
+
 		if len(validatedRecords) > 0 {
 			htlc.CustomRecords = validatedRecords
 		}
@@ -403,7 +403,8 @@ type InterceptedForward interface {
 	// forward with modified fields.
 	ResumeModified(inAmountMsat,
 		outAmountMsat fn.Option[lnwire.MilliSatoshi],
-		outWireCustomRecords fn.Option[lnwire.CustomRecords]) error
+		outWireCustomRecords fn.Option[lnwire.CustomRecords],
+		outExpiry fn.Option[uint32]) error
 
 	// Settle notifies the intention to settle an existing hold
 	// forward with a given preimage.
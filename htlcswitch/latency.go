@@ -0,0 +1,186 @@
+package htlcswitch
+
+import (
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// maxTrackedForwards is the maximum number of in-flight htlcs the
+// HtlcNotifier tracks forward timings for. Htlcs that are forwarded while
+// this limit is reached don't report timings when they are resolved.
+const maxTrackedForwards = 50_000
+
+// LatencyBuckets are the upper bounds of the buckets of a LatencyHistogram.
+// Observations larger than the last bound are counted in an additional
+// overflow bucket.
+var LatencyBuckets = [...]time.Duration{
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+	30 * time.Second,
+	time.Minute,
+}
+
+// LatencyHistogram is a histogram of htlc latencies using the bucket bounds
+// defined in LatencyBuckets.
+type LatencyHistogram struct {
+	// Counts holds the number of observations per bucket. The last entry
+	// is the overflow bucket for observations that exceed the largest
+	// bound.
+	Counts [len(LatencyBuckets) + 1]uint64
+
+	// Count is the total number of observations.
+	Count uint64
+
+	// Sum is the sum of all observations.
+	Sum time.Duration
+}
+
+// Observe adds the given latency to the histogram.
+func (l *LatencyHistogram) Observe(latency time.Duration) {
+	idx := len(LatencyBuckets)
+	for i, bound := range LatencyBuckets {
+		if latency <= bound {
+			idx = i
+			break
+		}
+	}
+
+	l.Counts[idx]++
+	l.Count++
+	l.Sum += latency
+}
+
+// Mean returns the average of all observations, or zero if there are none.
+func (l *LatencyHistogram) Mean() time.Duration {
+	if l.Count == 0 {
+		return 0
+	}
+
+	return l.Sum / time.Duration(l.Count)
+}
+
+// ChannelLatency holds the forward latency histograms of a single channel.
+type ChannelLatency struct {
+	// Incoming tracks the time between an htlc being locked in on this
+	// channel and it being forwarded onwards, for htlcs that we received
+	// on this channel.
+	Incoming LatencyHistogram
+
+	// Outgoing tracks the time between an htlc being forwarded over this
+	// channel and it being settled or failed back by the peer, for htlcs
+	// that we sent or forwarded over this channel.
+	Outgoing LatencyHistogram
+}
+
+// ChannelLatencies maps channels to their forward latency histograms.
+type ChannelLatencies map[lnwire.ShortChannelID]ChannelLatency
+
+// ForwardTiming holds the timestamps of an htlc that we sent or forwarded.
+type ForwardTiming struct {
+	// Received is the time the htlc was locked in on our incoming
+	// channel. It is zero for sends that originate at our node.
+	Received time.Time
+
+	// Forwarded is the time the htlc was added to our outgoing channel.
+	Forwarded time.Time
+}
+
+// forwardTracker tracks the timings of in-flight htlcs and aggregates their
+// latencies per channel.
+type forwardTracker struct {
+	mtx sync.Mutex
+
+	// inFlight holds the timings of htlcs that were forwarded but not yet
+	// resolved.
+	inFlight map[HtlcKey]ForwardTiming
+
+	// latencies holds the latency histograms per channel.
+	latencies map[lnwire.ShortChannelID]*ChannelLatency
+}
+
+// newForwardTracker creates a new forward tracker.
+func newForwardTracker() *forwardTracker {
+	return &forwardTracker{
+		inFlight:  make(map[HtlcKey]ForwardTiming),
+		latencies: make(map[lnwire.ShortChannelID]*ChannelLatency),
+	}
+}
+
+// channelLatency returns the latency histograms of the given channel,
+// creating them if necessary. The caller must hold the mutex.
+func (f *forwardTracker) channelLatency(
+	chanID lnwire.ShortChannelID) *ChannelLatency {
+
+	latency, ok := f.latencies[chanID]
+	if !ok {
+		latency = &ChannelLatency{}
+		f.latencies[chanID] = latency
+	}
+
+	return latency
+}
+
+// forwarded records that the htlc with the given key was forwarded at the
+// given time.
+func (f *forwardTracker) forwarded(key HtlcKey, timing ForwardTiming) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	if !timing.Received.IsZero() {
+		f.channelLatency(key.IncomingCircuit.ChanID).Incoming.Observe(
+			timing.Forwarded.Sub(timing.Received),
+		)
+	}
+
+	if len(f.inFlight) >= maxTrackedForwards {
+		log.Debugf("Not tracking forward timing of %v, limit of %d "+
+			"in-flight htlcs reached", key, maxTrackedForwards)
+
+		return
+	}
+
+	f.inFlight[key] = timing
+}
+
+// resolved records that the htlc with the given key was resolved at the
+// given time and returns its timing, if it was tracked.
+func (f *forwardTracker) resolved(key HtlcKey,
+	resolvedAt time.Time) *ForwardTiming {
+
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	timing, ok := f.inFlight[key]
+	if !ok {
+		return nil
+	}
+	delete(f.inFlight, key)
+
+	f.channelLatency(key.OutgoingCircuit.ChanID).Outgoing.Observe(
+		resolvedAt.Sub(timing.Forwarded),
+	)
+
+	return &timing
+}
+
+// snapshot returns a copy of the latency histograms of all channels.
+func (f *forwardTracker) snapshot() ChannelLatencies {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	latencies := make(ChannelLatencies, len(f.latencies))
+	for chanID, latency := range f.latencies {
+		latencies[chanID] = *latency
+	}
+
+	return latencies
+}
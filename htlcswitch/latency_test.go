@@ -0,0 +1,107 @@
+package htlcswitch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLatencyHistogram tests that observations are sorted into the correct
+// buckets.
+func TestLatencyHistogram(t *testing.T) {
+	t.Parallel()
+
+	var h LatencyHistogram
+	require.Zero(t, h.Mean())
+
+	h.Observe(5 * time.Millisecond)
+	h.Observe(10 * time.Millisecond)
+	h.Observe(200 * time.Millisecond)
+	h.Observe(2 * time.Minute)
+
+	require.EqualValues(t, 2, h.Counts[0])
+	require.EqualValues(t, 1, h.Counts[3])
+	require.EqualValues(t, 1, h.Counts[len(LatencyBuckets)])
+	require.EqualValues(t, 4, h.Count)
+	require.Equal(t, 2*time.Minute+215*time.Millisecond, h.Sum)
+}
+
+// TestForwardLatencies tests that the htlc notifier reports forward timings
+// on resolution and aggregates the latencies per channel.
+func TestForwardLatencies(t *testing.T) {
+	t.Parallel()
+
+	var (
+		start    = time.Unix(1_000_000, 0)
+		now      = start
+		inChan   = lnwire.NewShortChanIDFromInt(1)
+		outChan  = lnwire.NewShortChanIDFromInt(2)
+		notifier = NewHtlcNotifier(func() time.Time {
+			return now
+		})
+	)
+	require.NoError(t, notifier.Start())
+	t.Cleanup(func() {
+		require.NoError(t, notifier.Stop())
+	})
+
+	client, err := notifier.SubscribeHtlcEvents()
+	require.NoError(t, err)
+	t.Cleanup(client.Cancel)
+
+	key := HtlcKey{
+		IncomingCircuit: models.CircuitKey{ChanID: inChan, HtlcID: 1},
+		OutgoingCircuit: models.CircuitKey{ChanID: outChan, HtlcID: 2},
+	}
+
+	// Forward an htlc that was received 100ms ago.
+	now = start.Add(100 * time.Millisecond)
+	notifier.NotifyForwardingEvent(
+		key, HtlcInfo{ReceivedTime: start}, HtlcEventTypeForward,
+	)
+
+	// Settle it two seconds later.
+	now = now.Add(2 * time.Second)
+	notifier.NotifySettleEvent(
+		key, lntypes.Preimage{}, HtlcEventTypeForward,
+	)
+
+	var settle *SettleEvent
+	for settle == nil {
+		select {
+		case event := <-client.Updates():
+			settle, _ = event.(*SettleEvent)
+
+		case <-time.After(time.Second):
+			t.Fatal("no settle event received")
+		}
+	}
+
+	require.Equal(t, &ForwardTiming{
+		Received:  start,
+		Forwarded: start.Add(100 * time.Millisecond),
+	}, settle.ForwardTiming)
+
+	latencies := notifier.ForwardLatencies()
+	require.Len(t, latencies, 2)
+
+	incoming := latencies[inChan].Incoming
+	require.EqualValues(t, 1, incoming.Count)
+	require.Equal(t, 100*time.Millisecond, incoming.Mean())
+	require.Zero(t, latencies[inChan].Outgoing.Count)
+
+	outgoing := latencies[outChan].Outgoing
+	require.EqualValues(t, 1, outgoing.Count)
+	require.Equal(t, 2*time.Second, outgoing.Mean())
+	require.Zero(t, latencies[outChan].Incoming.Count)
+
+	// A second resolution of the same htlc isn't tracked anymore.
+	notifier.NotifyForwardingFailEvent(key, HtlcEventTypeForward)
+	require.EqualValues(
+		t, 1, notifier.ForwardLatencies()[outChan].Outgoing.Count,
+	)
+}
@@ -285,6 +285,20 @@ type ChannelLinkConfig struct {
 	// MaxFeeExposure is the threshold in milli-satoshis after which we'll
 	// restrict the flow of HTLCs and fee updates.
 	MaxFeeExposure lnwire.MilliSatoshi
+
+	// LocalHtlcSlotReserve is the fraction of the HTLC slots in each
+	// direction of the channel that is reserved for our own payments.
+	// Forwards are rejected once they'd use any of the reserved slots, so
+	// heavy forwarding traffic can't prevent us from sending or receiving
+	// payments. A value of zero disables the reservation.
+	LocalHtlcSlotReserve float64
+
+	// HtlcChainCostMultiplier is the multiple of the worst-case on-chain
+	// cost of resolving an outgoing HTLC that a forward must at least be
+	// worth. Forwards of a lower value are rejected, as they aren't worth
+	// the fees we'd pay if the HTLC forces us to close the channel. A
+	// value of zero disables the check.
+	HtlcChainCostMultiplier float64
 }
 
 // channelLink is the service which drives a channel's commitment update
@@ -1087,8 +1101,15 @@ func (l *channelLink) handleChanSyncErr(err error) {
 		return
 
 	// We failed syncing the commit chains, probably because the remote has
-	// lost state. We should force close the channel.
+	// lost state. We should force close the channel. We also record the
+	// remote's data loss, so a stale commitment it might broadcast can be
+	// told apart from a deliberate breach.
 	case errors.Is(err, lnwallet.ErrCommitSyncRemoteDataLoss):
+		if err := l.channel.MarkRemoteDataLoss(); err != nil {
+			l.log.Errorf("unable to mark remote data loss: %v",
+				err)
+		}
+
 		fallthrough
 
 	// The remote sent us an invalid last commit secret, we should force
@@ -1668,6 +1689,7 @@ func (l *channelLink) handleDownstreamUpdateAdd(pkt *htlcPacket) error {
 			IncomingAmt:      pkt.incomingAmount,
 			OutgoingTimeLock: htlc.Expiry,
 			OutgoingAmt:      htlc.Amount,
+			ReceivedTime:     pkt.receivedTime,
 		},
 		getEventType(pkt),
 	)
@@ -2160,6 +2182,20 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 		}
 
 	case *lnwire.CommitSig:
+		// A batch of multiple commitments is only sent while a splice
+		// is pending. As we don't support splicing, we can't apply
+		// such a batch, so we fail the link instead of applying just
+		// one of its commitments.
+		batchSize := msg.BatchSize.ValOpt().UnwrapOr(1)
+		if batchSize != 1 {
+			l.failf(
+				LinkFailureError{code: ErrInvalidCommitment},
+				"unsupported commit sig batch of size %d",
+				batchSize,
+			)
+			return
+		}
+
 		// Since we may have learned new preimages for the first time,
 		// we'll add them to our preimage cache. By doing this, we
 		// ensure any contested contracts watched by any on-chain
@@ -3074,6 +3110,29 @@ func (l *channelLink) CheckHtlcForward(payHash [32]byte,
 		return err
 	}
 
+	// Forwards may not use the outgoing HTLC slots that are reserved for
+	// our own payments.
+	used, available := l.forwardableHtlcSlots(lntypes.Local)
+	if used >= available {
+		l.log.Warnf("outgoing htlc(%x) would use a reserved htlc "+
+			"slot: used=%v, available_for_forwards=%v", payHash[:],
+			used, available)
+
+		cb := func(upd *lnwire.ChannelUpdate1) lnwire.FailureMessage {
+			return lnwire.NewTemporaryChannelFailure(upd)
+		}
+		failure := l.createFailureWithUpdate(false, originalScid, cb)
+		return NewLinkError(failure)
+	}
+
+	// The forward must be worth the fees we'd pay if it ends up being
+	// resolved on-chain.
+	if err := l.checkHtlcChainCost(
+		payHash, amtToForward, originalScid,
+	); err != nil {
+		return err
+	}
+
 	// Finally, we'll ensure that the time-lock on the outgoing HTLC meets
 	// the following constraint: the incoming time-lock minus our time-lock
 	// delta should equal the outgoing time lock. Otherwise, whether the
@@ -3092,7 +3151,9 @@ func (l *channelLink) CheckHtlcForward(payHash [32]byte,
 			)
 		}
 		failure := l.createFailureWithUpdate(false, originalScid, cb)
-		return NewLinkError(failure)
+		return NewDetailedLinkError(
+			failure, OutgoingFailureIncorrectCltvExpiry,
+		)
 	}
 
 	return nil
@@ -3162,14 +3223,17 @@ func (l *channelLink) canSendHtlc(policy models.ForwardingPolicy,
 	// too close to the current height.
 	if timeout <= heightNow+l.cfg.OutgoingCltvRejectDelta {
 		l.log.Warnf("htlc(%x) has an expiry that's too soon: "+
-			"outgoing_expiry=%v, best_height=%v", payHash[:],
-			timeout, heightNow)
+			"outgoing_expiry=%v, best_height=%v, "+
+			"reject_delta=%v", payHash[:], timeout, heightNow,
+			l.cfg.OutgoingCltvRejectDelta)
 
 		cb := func(upd *lnwire.ChannelUpdate1) lnwire.FailureMessage {
 			return lnwire.NewExpiryTooSoon(*upd)
 		}
 		failure := l.createFailureWithUpdate(false, originalScid, cb)
-		return NewLinkError(failure)
+		return NewDetailedLinkError(
+			failure, OutgoingFailureExpiryTooSoon,
+		)
 	}
 
 	// Check absolute max delta.
@@ -3178,7 +3242,9 @@ func (l *channelLink) canSendHtlc(policy models.ForwardingPolicy,
 			"the future: got %v, but maximum is %v", payHash[:],
 			timeout-heightNow, l.cfg.MaxOutgoingCltvExpiry)
 
-		return NewLinkError(&lnwire.FailExpiryTooFar{})
+		return NewDetailedLinkError(
+			&lnwire.FailExpiryTooFar{}, OutgoingFailureExpiryTooFar,
+		)
 	}
 
 	// Check to see if there is enough balance in this channel.
@@ -3197,6 +3263,104 @@ func (l *channelLink) canSendHtlc(policy models.ForwardingPolicy,
 	return nil
 }
 
+// checkHtlcChainCost returns a LinkError if the value of the outgoing htlc is
+// below HtlcChainCostMultiplier times the worst-case on-chain cost of
+// resolving it at the current network fee rate.
+func (l *channelLink) checkHtlcChainCost(payHash [32]byte,
+	amt lnwire.MilliSatoshi,
+	originalScid lnwire.ShortChannelID) *LinkError {
+
+	if l.cfg.HtlcChainCostMultiplier == 0 {
+		return nil
+	}
+
+	// If we can't sample the network fee, we won't hold up the forward
+	// as the check is merely a safeguard.
+	feeRate, err := l.sampleNetworkFee()
+	if err != nil {
+		l.log.Warnf("unable to sample network fee to check the chain "+
+			"cost of htlc(%x): %v", payHash[:], err)
+
+		return nil
+	}
+
+	chanType := l.channel.State().ChanType
+	chainCost := htlcChainCost(chanType, feeRate)
+
+	minAmt := lnwire.NewMSatFromSatoshis(btcutil.Amount(
+		float64(chainCost) * l.cfg.HtlcChainCostMultiplier,
+	))
+	if amt >= minAmt {
+		return nil
+	}
+
+	l.log.Warnf("outgoing htlc(%x) isn't worth its on-chain cost: "+
+		"htlc_value=%v, chain_cost=%v, min_value=%v, fee_rate=%v",
+		payHash[:], amt, chainCost, minAmt, feeRate)
+
+	cb := func(upd *lnwire.ChannelUpdate1) lnwire.FailureMessage {
+		return lnwire.NewTemporaryChannelFailure(upd)
+	}
+	failure := l.createFailureWithUpdate(false, originalScid, cb)
+
+	return NewLinkError(failure)
+}
+
+// htlcChainCost returns the worst-case on-chain cost of resolving an htlc we
+// offered on a channel of the given type at the given fee rate. In the worst
+// case, the htlc is timed out from our own commitment, so we pay for its
+// output on the commitment, the second-level timeout transaction and the
+// sweep of the second-level output. The fee of the second-level transaction
+// is counted for zero-fee htlc channels as well, as we then pay it by
+// attaching wallet inputs.
+func htlcChainCost(chanType channeldb.ChannelType,
+	feeRate chainfee.SatPerKWeight) btcutil.Amount {
+
+	var (
+		timeoutWeight lntypes.WeightUnit
+		sweepWitness  lntypes.WeightUnit
+	)
+	switch {
+	case chanType.IsTaproot():
+		timeoutWeight = input.TaprootHtlcTimeoutWeight
+		sweepWitness = input.TaprootToLocalWitnessSize
+
+	case chanType.HasAnchors():
+		timeoutWeight = input.HtlcTimeoutWeightConfirmed
+		sweepWitness = input.ToLocalTimeoutWitnessSize
+
+	default:
+		timeoutWeight = input.HtlcTimeoutWeight
+		sweepWitness = input.ToLocalTimeoutWitnessSize
+	}
+
+	var sweep input.TxWeightEstimator
+	sweep.AddWitnessInput(sweepWitness)
+	sweep.AddP2TROutput()
+
+	weight := input.HTLCWeight + timeoutWeight + sweep.Weight()
+
+	return feeRate.FeeForWeight(weight)
+}
+
+// forwardableHtlcSlots returns the number of HTLC slots that are used by the
+// HTLCs offered by the given party, and the number of slots that forwards may
+// use in that direction, excluding the slots reserved for our own payments.
+func (l *channelLink) forwardableHtlcSlots(
+	whoseUpdates lntypes.ChannelParty) (uint16, uint16) {
+
+	used, maxAccepted := l.channel.HtlcSlots(whoseUpdates)
+	reserved := ReservedHtlcSlots(l.cfg.LocalHtlcSlotReserve, maxAccepted)
+
+	return used, maxAccepted - reserved
+}
+
+// ReservedHtlcSlots returns the number of HTLC slots out of maxAccepted that
+// are reserved for our own payments with the given reserve fraction.
+func ReservedHtlcSlots(reserve float64, maxAccepted uint16) uint16 {
+	return uint16(reserve * float64(maxAccepted))
+}
+
 // Stats returns the statistics of channel link.
 //
 // NOTE: Part of the ChannelLink interface.
@@ -3442,6 +3606,10 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg) {
 
 	var switchPackets []*htlcPacket
 
+	// Keep track of the incoming HTLC slots, so forwards that use any of
+	// the slots reserved for our own payments can be failed back.
+	usedSlots, forwardableSlots := l.forwardableHtlcSlots(lntypes.Remote)
+
 	for i, update := range fwdPkg.Adds {
 		idx := uint16(i)
 
@@ -3667,6 +3835,7 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg) {
 					inOnionCustomRecords: pld.CustomRecords(),
 					inboundFee:           inboundFee,
 					inWireCustomRecords:  add.CustomRecords.Copy(),
+					receivedTime:         time.Now(),
 				}
 				switchPackets = append(
 					switchPackets, updatePacket,
@@ -3675,6 +3844,37 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg) {
 				continue
 			}
 
+			// If the HTLC uses one of the incoming slots reserved
+			// for our own payments, we fail it back to free the
+			// slot up again. Adds that were already forwarded in a
+			// previous run are never failed back, as their
+			// outgoing HTLC may still be live.
+			if !fwdPkg.FwdFilter.Contains(idx) &&
+				usedSlots > forwardableSlots {
+
+				l.log.Warnf("incoming htlc(%x) uses a "+
+					"reserved htlc slot: used=%v, "+
+					"available_for_forwards=%v",
+					add.PaymentHash[:], usedSlots,
+					forwardableSlots)
+
+				cb := func(upd *lnwire.ChannelUpdate1) lnwire.FailureMessage { //nolint:lll
+					return lnwire.NewTemporaryChannelFailure(upd)
+				}
+
+				failure := l.createFailureWithUpdate(
+					true, hop.Source, cb,
+				)
+
+				l.sendHTLCError(
+					add, sourceRef, NewLinkError(failure),
+					obfuscator, false,
+				)
+
+				usedSlots--
+				continue
+			}
+
 			// TODO(roasbeef): ensure don't accept outrageous
 			// timeout for htlc
 
@@ -3738,6 +3938,7 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg) {
 					inOnionCustomRecords: pld.CustomRecords(),
 					inboundFee:           inboundFee,
 					inWireCustomRecords:  add.CustomRecords.Copy(),
+					receivedTime:         time.Now(),
 				}
 
 				fwdPkg.FwdFilter.Set(idx)
@@ -1497,6 +1497,61 @@ func TestUpdateForwardingPolicy(t *testing.T) {
 	}
 }
 
+// TestChannelLinkIncomingHtlcSlotReserve asserts that incoming forwards that
+// use one of the HTLC slots reserved for our own payments are failed back,
+// while payments to us are still accepted.
+func TestChannelLinkIncomingHtlcSlotReserve(t *testing.T) {
+	t.Parallel()
+
+	channels, _, err := createClusterChannels(
+		t, btcutil.SatoshiPerBitcoin*3, btcutil.SatoshiPerBitcoin*5,
+	)
+	require.NoError(t, err, "unable to create channel")
+
+	n := newThreeHopNetwork(
+		t, channels.aliceToBob, channels.bobToAlice,
+		channels.bobToCarol, channels.carolToBob, testStartingHeight,
+	)
+
+	// Reserve all of Bob's incoming slots on the channel with Alice, so
+	// that no forward can use any of them.
+	n.firstBobChannelLink.cfg.LocalHtlcSlotReserve = 1
+
+	require.NoError(t, n.start())
+	t.Cleanup(n.stop)
+
+	amount := lnwire.NewMSatFromSatoshis(btcutil.SatoshiPerBitcoin / 10)
+	firstHop := n.firstBobChannelLink.ShortChanID()
+
+	// A payment forwarded by Bob to Carol is failed back.
+	htlcAmt, totalTimelock, hops := generateHops(
+		amount, testStartingHeight, n.firstBobChannelLink,
+		n.carolChannelLink,
+	)
+	rhash, err := makePayment(
+		n.aliceServer, n.carolServer, firstHop, hops, amount, htlcAmt,
+		totalTimelock,
+	).Wait(30 * time.Second)
+	require.Error(t, err)
+	assertFailureCode(t, err, lnwire.CodeTemporaryChannelFailure)
+
+	invoice, err := n.carolServer.registry.LookupInvoice(
+		context.Background(), rhash,
+	)
+	require.NoError(t, err, "unable to get invoice")
+	require.NotEqual(t, invpkg.ContractSettled, invoice.State)
+
+	// A payment to Bob himself may use the reserved slots.
+	htlcAmt, totalTimelock, hops = generateHops(
+		amount, testStartingHeight, n.firstBobChannelLink,
+	)
+	_, err = makePayment(
+		n.aliceServer, n.bobServer, firstHop, hops, amount, htlcAmt,
+		totalTimelock,
+	).Wait(30 * time.Second)
+	require.NoError(t, err, "unable to send payment to bob")
+}
+
 // TestChannelLinkMultiHopInsufficientPayment checks that we receive error if
 // bob<->alice channel has insufficient BTC capacity/bandwidth. In this test we
 // send the payment from Carol to Alice over Bob peer. (Carol -> Bob -> Alice)
@@ -4222,6 +4277,41 @@ func TestShouldAdjustCommitFee(t *testing.T) {
 	}
 }
 
+// TestHtlcChainCost tests that the worst-case on-chain cost of an htlc covers
+// its commitment output and second-level timeout transaction, and scales with
+// the fee rate.
+func TestHtlcChainCost(t *testing.T) {
+	t.Parallel()
+
+	const feeRate = chainfee.SatPerKWeight(1000)
+
+	legacy := htlcChainCost(channeldb.SingleFunderTweaklessBit, feeRate)
+	anchors := htlcChainCost(
+		channeldb.AnchorOutputsBit|channeldb.ZeroHtlcTxFeeBit, feeRate,
+	)
+	taproot := htlcChainCost(
+		channeldb.SimpleTaprootFeatureBit|channeldb.AnchorOutputsBit|
+			channeldb.ZeroHtlcTxFeeBit, feeRate,
+	)
+
+	// The second-level transaction and the sweep add to the cost of the
+	// commitment output.
+	minWeight := lntypes.WeightUnit(
+		input.HTLCWeight + input.HtlcTimeoutWeight,
+	)
+	require.Greater(t, legacy, feeRate.FeeForWeight(minWeight))
+
+	// Zero-fee htlc channels still pay for the second level, with a
+	// slightly larger script for anchor channels.
+	require.Equal(t, legacy+3, anchors)
+	require.Positive(t, taproot)
+
+	// The cost scales with the fee rate.
+	require.Equal(t, 2*legacy, htlcChainCost(
+		channeldb.SingleFunderTweaklessBit, 2*feeRate,
+	))
+}
+
 // TestChannelLinkShutdownDuringForward asserts that a link can be fully
 // stopped when it is trying to send synchronously through the switch. The
 // specific case this can occur is when a link forwards incoming Adds. We test
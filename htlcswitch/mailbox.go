@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/fn"
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwire"
@@ -22,6 +23,10 @@ var (
 	// ErrPacketAlreadyExists signals that an attempt to add a packet failed
 	// because it already exists in the mailbox.
 	ErrPacketAlreadyExists = errors.New("mailbox already has packet")
+
+	// ErrMailBoxFull signals that an attempt to add an Add packet failed
+	// because the mailbox already holds the maximum number of Adds.
+	ErrMailBoxFull = errors.New("mailbox is full")
 )
 
 // MailBox is an interface which represents a concurrent-safe, in-order
@@ -109,12 +114,16 @@ type mailBoxConfig struct {
 	// correct SCID if the underlying channel uses aliases.
 	failMailboxUpdate func(outScid,
 		mailboxScid lnwire.ShortChannelID) lnwire.FailureMessage
+
+	// maxAdds is the maximum number of Adds the mailbox holds at once,
+	// including the ones that were delivered but not ACK'd yet. If zero,
+	// the number of Adds is unbounded. Settles and Fails are never
+	// bounded, since they resolve HTLCs rather than create new ones.
+	maxAdds int
 }
 
 // memoryMailBox is an implementation of the MailBox struct backed by purely
 // in-memory queues.
-//
-// TODO(morehouse): use typed lists instead of list.Lists to avoid type asserts.
 type memoryMailBox struct {
 	started sync.Once
 	stopped sync.Once
@@ -129,14 +138,10 @@ type memoryMailBox struct {
 	msgReset      chan chan struct{}
 
 	// repPkts is a queue for reply packets, e.g. Settles and Fails.
-	repPkts  *list.List
-	repIndex map[CircuitKey]*list.Element
-	repHead  *list.Element
+	repPkts *fn.AckQueue[CircuitKey, *htlcPacket]
 
 	// addPkts is a dedicated queue for Adds.
-	addPkts  *list.List
-	addIndex map[CircuitKey]*list.Element
-	addHead  *list.Element
+	addPkts *fn.AckQueue[CircuitKey, *pktWithExpiry]
 
 	pktMtx  sync.Mutex
 	pktCond *sync.Cond
@@ -165,20 +170,18 @@ func newMemoryMailBox(cfg *mailBoxConfig) *memoryMailBox {
 	box := &memoryMailBox{
 		cfg:           cfg,
 		wireMessages:  list.New(),
-		repPkts:       list.New(),
-		addPkts:       list.New(),
+		repPkts:       fn.NewAckQueue[CircuitKey, *htlcPacket](0),
 		messageOutbox: make(chan lnwire.Message),
 		pktOutbox:     make(chan *htlcPacket),
 		msgReset:      make(chan chan struct{}, 1),
 		pktReset:      make(chan chan struct{}, 1),
-		repIndex:      make(map[CircuitKey]*list.Element),
-		addIndex:      make(map[CircuitKey]*list.Element),
 		wireShutdown:  make(chan struct{}),
 		pktShutdown:   make(chan struct{}),
 		quit:          make(chan struct{}),
 	}
 	box.wireCond = sync.NewCond(&box.wireMtx)
 	box.pktCond = sync.NewCond(&box.pktMtx)
+	box.addPkts = fn.NewAckQueue[CircuitKey, *pktWithExpiry](cfg.maxAdds)
 
 	return box
 }
@@ -267,51 +270,22 @@ func (m *memoryMailBox) AckPacket(inKey CircuitKey) bool {
 	m.pktCond.L.Lock()
 	defer m.pktCond.L.Unlock()
 
-	if entry, ok := m.repIndex[inKey]; ok {
-		// Check whether we are removing the head of the queue. If so,
-		// we must advance the head to the next packet before removing.
-		// It's possible that the courier has already advanced the
-		// repHead, so this check prevents the repHead from getting
-		// desynchronized.
-		if entry == m.repHead {
-			m.repHead = entry.Next()
-		}
-		m.repPkts.Remove(entry)
-		delete(m.repIndex, inKey)
-
+	// Acking a packet that the courier hasn't delivered yet moves the
+	// head of its queue on to the next packet. While this is rare for
+	// Settles or Fails, it could be very common for Adds since the mailbox
+	// has the ability to cancel Adds before they are delivered.
+	if m.repPkts.Ack(inKey) {
 		return true
 	}
 
-	if entry, ok := m.addIndex[inKey]; ok {
-		// Check whether we are removing the head of the queue. If so,
-		// we must advance the head to the next add before removing.
-		// It's possible that the courier has already advanced the
-		// addHead, so this check prevents the addHead from getting
-		// desynchronized.
-		//
-		// NOTE: While this event is rare for Settles or Fails, it could
-		// be very common for Adds since the mailbox has the ability to
-		// cancel Adds before they are delivered. When that occurs, the
-		// head of addPkts has only been peeked and we expect to be
-		// removing the head of the queue.
-		if entry == m.addHead {
-			m.addHead = entry.Next()
-		}
-
-		m.addPkts.Remove(entry)
-		delete(m.addIndex, inKey)
-
-		return true
-	}
-
-	return false
+	return m.addPkts.Ack(inKey)
 }
 
 // HasPacket queries the packets for a circuit key, this is used to drop packets
 // bound for the switch that already have a queued response.
 func (m *memoryMailBox) HasPacket(inKey CircuitKey) bool {
 	m.pktCond.L.Lock()
-	_, ok := m.repIndex[inKey]
+	ok := m.repPkts.Contains(inKey)
 	m.pktCond.L.Unlock()
 
 	return ok
@@ -429,7 +403,7 @@ func (m *memoryMailBox) pktMailCourier() {
 		// First, we'll check our condition. If our mailbox is empty,
 		// then we'll wait until a new item is added.
 		m.pktCond.L.Lock()
-		for m.repHead == nil && m.addHead == nil {
+		for m.repPkts.Peek().IsNone() && m.addPkts.Peek().IsNone() {
 			m.pktCond.Wait()
 
 			select {
@@ -437,8 +411,8 @@ func (m *memoryMailBox) pktMailCourier() {
 			// pointer to the front. This ensures that any un-ACK'd
 			// messages are re-delivered upon reconnect.
 			case pktDone := <-m.pktReset:
-				m.repHead = m.repPkts.Front()
-				m.addHead = m.addPkts.Front()
+				m.repPkts.Rewind()
+				m.addPkts.Rewind()
 
 				close(pktDone)
 
@@ -450,10 +424,10 @@ func (m *memoryMailBox) pktMailCourier() {
 		}
 
 		var (
-			nextRep   *htlcPacket
-			nextRepEl *list.Element
-			nextAdd   *pktWithExpiry
-			nextAddEl *list.Element
+			nextRep    *htlcPacket
+			nextRepKey CircuitKey
+			nextAdd    *pktWithExpiry
+			nextAddKey CircuitKey
 		)
 		// For packets, we actually never remove an item until it has
 		// been ACK'd by the link. This ensures that if a read packet
@@ -465,16 +439,16 @@ func (m *memoryMailBox) pktMailCourier() {
 		// to set a deadline for the next pending Add if it's present.
 		// Due to clock monotonicity, we know that the head of the Adds
 		// is the next to expire.
-		if m.repHead != nil {
-			//nolint:forcetypeassert
-			nextRep = m.repHead.Value.(*htlcPacket)
-			nextRepEl = m.repHead
-		}
-		if m.addHead != nil {
-			//nolint:forcetypeassert
-			nextAdd = m.addHead.Value.(*pktWithExpiry)
-			nextAddEl = m.addHead
-		}
+		m.repPkts.Peek().WhenSome(
+			func(r fn.T2[CircuitKey, *htlcPacket]) {
+				nextRepKey, nextRep = r.Unpack()
+			},
+		)
+		m.addPkts.Peek().WhenSome(
+			func(a fn.T2[CircuitKey, *pktWithExpiry]) {
+				nextAddKey, nextAdd = a.Unpack()
+			},
+		)
 
 		// Now that we're done with the condition, we can unlock it to
 		// allow any callers to append to the end of our target queue.
@@ -520,20 +494,16 @@ func (m *memoryMailBox) pktMailCourier() {
 		select {
 		case pktOutbox <- nextRep:
 			m.pktCond.L.Lock()
-			// Only advance the repHead if this Settle or Fail is
-			// still at the head of the queue.
-			if m.repHead != nil && m.repHead == nextRepEl {
-				m.repHead = m.repHead.Next()
-			}
+			// Only advance the head of the queue if this Settle or
+			// Fail is still at the head of the queue.
+			m.repPkts.Advance(nextRepKey)
 			m.pktCond.L.Unlock()
 
 		case addOutbox <- add:
 			m.pktCond.L.Lock()
-			// Only advance the addHead if this Add is still at the
-			// head of the queue.
-			if m.addHead != nil && m.addHead == nextAddEl {
-				m.addHead = m.addHead.Next()
-			}
+			// Only advance the head of the queue if this Add is
+			// still at the head of the queue.
+			m.addPkts.Advance(nextAddKey)
 			m.pktCond.L.Unlock()
 
 		case <-deadline:
@@ -543,8 +513,8 @@ func (m *memoryMailBox) pktMailCourier() {
 
 		case pktDone := <-m.pktReset:
 			m.pktCond.L.Lock()
-			m.repHead = m.repPkts.Front()
-			m.addHead = m.addPkts.Front()
+			m.repPkts.Rewind()
+			m.addPkts.Rewind()
 			m.pktCond.L.Unlock()
 
 			close(pktDone)
@@ -582,31 +552,28 @@ func (m *memoryMailBox) AddPacket(pkt *htlcPacket) error {
 	switch htlc := pkt.htlc.(type) {
 	// Split off Settle/Fail packets into the repPkts queue.
 	case *lnwire.UpdateFulfillHTLC, *lnwire.UpdateFailHTLC:
-		if _, ok := m.repIndex[pkt.inKey()]; ok {
+		err := m.repPkts.Push(pkt.inKey(), pkt)
+		if errors.Is(err, fn.ErrDuplicateQueueKey) {
 			m.pktCond.L.Unlock()
 			return ErrPacketAlreadyExists
 		}
 
-		entry := m.repPkts.PushBack(pkt)
-		m.repIndex[pkt.inKey()] = entry
-		if m.repHead == nil {
-			m.repHead = entry
-		}
-
 	// Split off Add packets into the addPkts queue.
 	case *lnwire.UpdateAddHTLC:
-		if _, ok := m.addIndex[pkt.inKey()]; ok {
-			m.pktCond.L.Unlock()
-			return ErrPacketAlreadyExists
-		}
-
-		entry := m.addPkts.PushBack(&pktWithExpiry{
+		err := m.addPkts.Push(pkt.inKey(), &pktWithExpiry{
 			pkt:    pkt,
 			expiry: m.cfg.clock.Now().Add(m.cfg.expiry),
 		})
-		m.addIndex[pkt.inKey()] = entry
-		if m.addHead == nil {
-			m.addHead = entry
+		switch {
+		case errors.Is(err, fn.ErrDuplicateQueueKey):
+			m.pktCond.L.Unlock()
+			return ErrPacketAlreadyExists
+
+		// If the mailbox already holds too many Adds, the caller is
+		// expected to fail this one back.
+		case errors.Is(err, fn.ErrQueueFull):
+			m.pktCond.L.Unlock()
+			return ErrMailBoxFull
 		}
 
 	default:
@@ -656,8 +623,8 @@ func (m *memoryMailBox) DustPackets() (lnwire.MilliSatoshi,
 	// Run through the map of HTLC's and determine the dust sum with calls
 	// to the memoryMailBox's isDust closure. Note that all mailbox packets
 	// are outgoing so the second argument to isDust will be false.
-	for _, e := range m.addIndex {
-		addPkt := e.Value.(*pktWithExpiry).pkt
+	m.addPkts.ForEach(func(_ CircuitKey, e *pktWithExpiry) {
+		addPkt := e.pkt
 
 		// Evaluate whether this HTLC is dust on the local commitment.
 		if m.isDust(
@@ -676,7 +643,7 @@ func (m *memoryMailBox) DustPackets() (lnwire.MilliSatoshi,
 
 			remoteDustSum += addPkt.amount
 		}
-	}
+	})
 
 	return localDustSum, remoteDustSum
 }
@@ -818,6 +785,10 @@ type mailOrchConfig struct {
 	// correct SCID if the underlying channel uses aliases.
 	failMailboxUpdate func(outScid,
 		mailboxScid lnwire.ShortChannelID) lnwire.FailureMessage
+
+	// maxAdds is the maximum number of Adds each generated mailbox holds
+	// at once. If zero, the number of Adds is unbounded.
+	maxAdds int
 }
 
 // newMailOrchestrator initializes a fresh mailOrchestrator.
@@ -876,6 +847,7 @@ func (mo *mailOrchestrator) exclusiveGetOrCreateMailBox(
 			clock:             mo.cfg.clock,
 			expiry:            mo.cfg.expiry,
 			failMailboxUpdate: mo.cfg.failMailboxUpdate,
+			maxAdds:           mo.cfg.maxAdds,
 		})
 		mailbox.Start()
 		mo.mailboxes[chanID] = mailbox
@@ -571,6 +571,60 @@ func TestMailBoxDuplicateAddPacket(t *testing.T) {
 	})
 }
 
+// TestMailBoxMaxAdds asserts that the mailbox returns an ErrMailBoxFull
+// failure once it holds the maximum number of Adds, counting the ones that were
+// delivered but not ACK'd yet, while Settles and Fails are still accepted.
+func TestMailBoxMaxAdds(t *testing.T) {
+	t.Parallel()
+
+	mailbox := newMemoryMailBox(&mailBoxConfig{
+		clock:   clock.NewTestClock(time.Now()),
+		expiry:  testExpiry,
+		maxAdds: 2,
+	})
+	mailbox.Start()
+	t.Cleanup(mailbox.Stop)
+
+	newAdd := func(id uint64) *htlcPacket {
+		return &htlcPacket{
+			incomingHTLCID: id,
+			htlc:           &lnwire.UpdateAddHTLC{ID: id},
+		}
+	}
+
+	// Fill up the mailbox with Adds, and deliver the first one to the
+	// link without ACK'ing it.
+	require.NoError(t, mailbox.AddPacket(newAdd(0)))
+	require.NoError(t, mailbox.AddPacket(newAdd(1)))
+
+	select {
+	case pkt := <-mailbox.PacketOutBox():
+		require.Equal(t, uint64(0), pkt.incomingHTLCID)
+
+	case <-time.After(time.Second):
+		t.Fatalf("did not receive add")
+	}
+
+	// Since the delivered Add wasn't ACK'd yet, there is no room for
+	// another one.
+	err := mailbox.AddPacket(newAdd(2))
+	require.ErrorIs(t, err, ErrMailBoxFull)
+
+	// Settles and Fails are never bounded.
+	require.NoError(t, mailbox.AddPacket(&htlcPacket{
+		incomingHTLCID: 3,
+		htlc:           &lnwire.UpdateFulfillHTLC{},
+	}))
+	require.NoError(t, mailbox.AddPacket(&htlcPacket{
+		incomingHTLCID: 4,
+		htlc:           &lnwire.UpdateFailHTLC{},
+	}))
+
+	// Once the link ACKs the delivered Add, the next one is accepted.
+	require.True(t, mailbox.AckPacket(newAdd(0).inKey()))
+	require.NoError(t, mailbox.AddPacket(newAdd(2)))
+}
+
 // TestMailBoxDustHandling tests that DustPackets returns the expected values
 // for the local and remote dust sum after calling SetFeeRate and
 // SetDustClosure.
@@ -1134,6 +1134,12 @@ func (m *mockCircuitMap) LookupByPaymentHash(hash [32]byte) []*PaymentCircuit {
 	return nil
 }
 
+func (m *mockCircuitMap) LookupUnresolvedByChannel(
+	chanID lnwire.ShortChannelID) []*PaymentCircuit {
+
+	return nil
+}
+
 func (m *mockCircuitMap) NumPending() int {
 	return 0
 }
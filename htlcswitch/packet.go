@@ -1,6 +1,8 @@
 package htlcswitch
 
 import (
+	"time"
+
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/channeldb/models"
 	"github.com/lightningnetwork/lnd/htlcswitch/hop"
@@ -111,6 +113,10 @@ type htlcPacket struct {
 
 	// inboundFee is the fee schedule of the incoming channel.
 	inboundFee models.InboundFee
+
+	// receivedTime is the time the incoming htlc of a forwarded Add was
+	// processed by the incoming link.
+	receivedTime time.Time
 }
 
 // inKey returns the circuit key used to identify the incoming htlc.
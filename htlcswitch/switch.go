@@ -20,6 +20,7 @@ import (
 	"github.com/lightningnetwork/lnd/contractcourt"
 	"github.com/lightningnetwork/lnd/fn"
 	"github.com/lightningnetwork/lnd/htlcswitch/hop"
+	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnutils"
@@ -45,6 +46,13 @@ const (
 	// DefaultMailboxDeliveryTimeout is the duration after which Adds will
 	// be cancelled if they could not get added to an outgoing commitment.
 	DefaultMailboxDeliveryTimeout = time.Minute
+
+	// DefaultMaxMailboxAdds is the default maximum number of Adds a link's
+	// mailbox holds at once. Since a channel can never have more than this
+	// many HTLCs on its commitments, any Adds beyond it could only sit in
+	// the mailbox until they expire, so they're failed back right away
+	// instead.
+	DefaultMaxMailboxAdds = input.MaxHTLCNumber
 )
 
 var (
@@ -213,6 +221,11 @@ type Config struct {
 	// a mailbox via AddPacket.
 	MailboxDeliveryTimeout time.Duration
 
+	// MaxMailboxAdds is the maximum number of Adds a link's mailbox holds
+	// at once. Adds beyond it are failed back. If zero, the number of Adds
+	// is unbounded.
+	MaxMailboxAdds int
+
 	// MaxFeeExposure is the threshold in milli-satoshis after which we'll
 	// fail incoming or outgoing payments for a particular channel.
 	MaxFeeExposure lnwire.MilliSatoshi
@@ -393,6 +406,7 @@ func New(cfg Config, currentHeight uint32) (*Switch, error) {
 		clock:             s.cfg.Clock,
 		expiry:            s.cfg.MailboxDeliveryTimeout,
 		failMailboxUpdate: s.failMailboxUpdate,
+		maxAdds:           s.cfg.MaxMailboxAdds,
 	})
 
 	return s, nil
@@ -432,6 +446,46 @@ func (s *Switch) ProcessContractResolution(msg contractcourt.ResolutionMsg) erro
 	}
 }
 
+// FailStrandedHTLCs fails back the incoming side of all HTLCs that are still
+// waiting for a resolution on the given outgoing channel. It must only be
+// called once the channel has been fully resolved on-chain. By then, every
+// outgoing HTLC that was part of a commitment has been resolved by the
+// contract court, so any remaining open circuit belongs to an HTLC that never
+// made it on-chain. Failing it right away frees up the incoming HTLC, rather
+// than waiting for it to time out and force closing the incoming channel.
+func (s *Switch) FailStrandedHTLCs(chanID lnwire.ShortChannelID) error {
+	for _, circuit := range s.circuits.LookupUnresolvedByChannel(chanID) {
+		outKey := circuit.OutKey()
+
+		// If the contract court already handed us a resolution for
+		// this HTLC, it's only waiting to be delivered. We must not
+		// overwrite it, as it might be a settle.
+		err := s.resMsgStore.checkResolutionMsg(&outKey)
+		switch {
+		case err == nil:
+			continue
+
+		case !errors.Is(err, errResMsgNotFound):
+			return err
+		}
+
+		log.Infof("Failing back stranded htlc %v of resolved "+
+			"channel %v to incoming circuit %v", outKey.HtlcID,
+			chanID, circuit.Incoming)
+
+		err = s.ProcessContractResolution(contractcourt.ResolutionMsg{
+			SourceChan: outKey.ChanID,
+			HtlcIndex:  outKey.HtlcID,
+			Failure:    &lnwire.FailPermanentChannelFailure{},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // HasAttemptResult reads the network result store to fetch the specified
 // attempt. Returns true if the attempt result exists.
 func (s *Switch) HasAttemptResult(attemptID uint64) (bool, error) {
@@ -620,7 +674,30 @@ func (s *Switch) SendHTLC(firstHop lnwire.ShortChannelID, attemptID uint64,
 	// canceled back if the mailbox timeout elapses.
 	packet.circuit = circuit
 
-	return link.handleSwitchPacket(packet)
+	err = link.handleSwitchPacket(packet)
+	if err != nil {
+		// The packet never made it into the mailbox, e.g. because the
+		// mailbox is full, so we tear down the circuit we just
+		// committed to allow the attempt to be retried.
+		delErr := s.circuits.DeleteCircuits(circuit.Incoming)
+		if delErr != nil {
+			log.Errorf("unable to delete circuit for "+
+				"attempt %d: %v", attemptID, delErr)
+		}
+
+		// Report a full mailbox as a temporary failure of our own
+		// channel, so that the payment can try a different route.
+		if errors.Is(err, ErrMailBoxFull) {
+			return NewDetailedLinkError(
+				&lnwire.FailTemporaryChannelFailure{},
+				OutgoingFailureDownstreamHtlcAdd,
+			)
+		}
+
+		return err
+	}
+
+	return nil
 }
 
 // UpdateForwardingPolicies sends a message to the switch to update the
@@ -2986,7 +3063,22 @@ func (s *Switch) handlePacketAdd(packet *htlcPacket,
 	// channel.
 	packet.outgoingChanID = destination.ShortChanID()
 
-	return destination.handleSwitchPacket(packet)
+	err = destination.handleSwitchPacket(packet)
+	if errors.Is(err, ErrMailBoxFull) {
+		// The destination link already has the maximum number of Adds
+		// queued, so rather than buffering any more we fail this one
+		// back the same way the mailbox fails an expired Add.
+		failure := s.failMailboxUpdate(
+			packet.originalOutgoingChanID, packet.outgoingChanID,
+		)
+		linkErr := NewDetailedLinkError(
+			failure, OutgoingFailureDownstreamHtlcAdd,
+		)
+
+		return s.failAddPacket(packet, linkErr)
+	}
+
+	return err
 }
 
 // handlePacketSettle handles forwarding a settle packet.
@@ -3528,6 +3528,7 @@ func checkHtlcEvents(t *testing.T, events <-chan interface{},
 	for _, expected := range expectedEvents {
 		select {
 		case event := <-events:
+			stripReceivedTime(event)
 			if !reflect.DeepEqual(event, expected) {
 				t.Fatalf("expected %v, got: %v", expected,
 					event)
@@ -3546,6 +3547,29 @@ func checkHtlcEvents(t *testing.T, events <-chan interface{},
 	}
 }
 
+// stripReceivedTime zeroes the time an htlc was received on the incoming
+// link, as it is taken from the wall clock instead of the notifier's mocked
+// time.
+func stripReceivedTime(event interface{}) {
+	switch e := event.(type) {
+	case *ForwardingEvent:
+		e.ReceivedTime = time.Time{}
+
+	case *LinkFailEvent:
+		e.ReceivedTime = time.Time{}
+
+	case *SettleEvent:
+		if e.ForwardTiming != nil {
+			e.ForwardTiming.Received = time.Time{}
+		}
+
+	case *ForwardingFailEvent:
+		if e.ForwardTiming != nil {
+			e.ForwardTiming.Received = time.Time{}
+		}
+	}
+}
+
 // sendThreeHopPayment is a helper function which sends a payment over
 // Alice -> Bob -> Carol in a three hop network and returns Alice's first htlc
 // and the remainder of the hops.
@@ -3597,6 +3621,11 @@ func getThreeHopEvents(channels *clusterChannels, htlcID uint64,
 		},
 	}
 
+	// The received time of forwards is stripped before comparing events,
+	// so both alice's and bob's forwards are expected to only have the
+	// forwarded time set.
+	fwdTiming := &ForwardTiming{Forwarded: ts}
+
 	// Alice always needs a forwarding event because she initiates the
 	// send.
 	aliceEvents := []interface{}{
@@ -3636,6 +3665,7 @@ func getThreeHopEvents(channels *clusterChannels, htlcID uint64,
 			&ForwardingFailEvent{
 				HtlcKey:       aliceKey,
 				HtlcEventType: HtlcEventTypeSend,
+				ForwardTiming: fwdTiming,
 				Timestamp:     ts,
 			},
 		)
@@ -3669,6 +3699,7 @@ func getThreeHopEvents(channels *clusterChannels, htlcID uint64,
 			HtlcKey:       aliceKey,
 			Preimage:      *preimage,
 			HtlcEventType: HtlcEventTypeSend,
+			ForwardTiming: fwdTiming,
 			Timestamp:     ts,
 		},
 	)
@@ -3684,6 +3715,7 @@ func getThreeHopEvents(channels *clusterChannels, htlcID uint64,
 			HtlcKey:       bobKey,
 			Preimage:      *preimage,
 			HtlcEventType: HtlcEventTypeForward,
+			ForwardTiming: fwdTiming,
 			Timestamp:     ts,
 		},
 		&FinalHtlcEvent{
@@ -3998,6 +4030,59 @@ func TestSwitchHoldForward(t *testing.T) {
 	assertOutgoingLinkReceive(t, c.aliceChannelLink, true)
 	assertNumCircuits(t, c.s, 0, 0)
 
+	// Test resume a hold forward with a modified amount, expiry and custom
+	// records.
+	require.NoError(t, switchForwardInterceptor.ForwardPackets(
+		linkQuit, false, c.createTestPacket(),
+	))
+	assertOutgoingLinkReceive(t, c.bobChannelLink, false)
+
+	intercepted := c.forwardInterceptor.getIntercepted()
+	outRecords := lnwire.CustomRecords{
+		lnwire.MinCustomRecordsTlvType: []byte{1, 2, 3},
+	}
+	require.NoError(t, switchForwardInterceptor.Resolve(&FwdResolution{
+		Action:               FwdActionResumeModified,
+		Key:                  intercepted.IncomingCircuit,
+		OutAmountMsat:        fn.Some(lnwire.MilliSatoshi(2)),
+		OutWireCustomRecords: fn.Some(outRecords),
+		OutExpiry:            fn.Some(intercepted.IncomingExpiry - 1),
+	}))
+	receivedPkt = assertOutgoingLinkReceive(t, c.bobChannelLink, true)
+	assertNumCircuits(t, c.s, 1, 1)
+
+	require.EqualValues(t, 2, receivedPkt.amount)
+	require.Equal(
+		t, intercepted.IncomingExpiry-1, receivedPkt.outgoingTimeout,
+	)
+
+	receivedAdd, ok := receivedPkt.htlc.(*lnwire.UpdateAddHTLC)
+	require.True(t, ok)
+	require.EqualValues(t, 2, receivedAdd.Amount)
+	require.Equal(t, intercepted.IncomingExpiry-1, receivedAdd.Expiry)
+	require.Equal(t, outRecords, receivedAdd.CustomRecords)
+
+	require.NoError(t, switchForwardInterceptor.ForwardPackets(
+		linkQuit, false,
+		c.createSettlePacket(receivedPkt.outgoingHTLCID),
+	))
+
+	assertOutgoingLinkReceive(t, c.aliceChannelLink, true)
+	assertNumCircuits(t, c.s, 0, 0)
+
+	// An outgoing expiry that isn't below the incoming expiry is rejected.
+	require.NoError(t, switchForwardInterceptor.ForwardPackets(
+		linkQuit, false, c.createTestPacket(),
+	))
+	intercepted = c.forwardInterceptor.getIntercepted()
+	require.Error(t, switchForwardInterceptor.Resolve(&FwdResolution{
+		Action:    FwdActionResumeModified,
+		Key:       intercepted.IncomingCircuit,
+		OutExpiry: fn.Some(intercepted.IncomingExpiry),
+	}))
+	assertOutgoingLinkReceive(t, c.bobChannelLink, false)
+	assertNumCircuits(t, c.s, 0, 0)
+
 	// Test resume a hold forward after disconnection.
 	require.NoError(t, switchForwardInterceptor.ForwardPackets(
 		linkQuit, false, c.createTestPacket(),
@@ -4177,7 +4262,7 @@ func TestSwitchHoldForward(t *testing.T) {
 	)
 
 	// A replay of the held packet is expected.
-	intercepted := c.forwardInterceptor.getIntercepted()
+	intercepted = c.forwardInterceptor.getIntercepted()
 
 	// Settle the packet.
 	require.NoError(t, switchForwardInterceptor.Resolve(&FwdResolution{
@@ -4199,6 +4284,104 @@ func TestSwitchHoldForward(t *testing.T) {
 	}
 }
 
+// TestSwitchHoldForwardMemoryCeiling asserts that only new forwards that
+// haven't been offered to the interceptor yet are failed back once held
+// forwards reach the memory ceiling.
+func TestSwitchHoldForwardMemoryCeiling(t *testing.T) {
+	t.Parallel()
+
+	c := newInterceptableSwitchTestContext(t)
+	defer c.finish()
+
+	notifier := &mock.ChainNotifier{
+		EpochChan: make(chan *chainntnfs.BlockEpoch, 1),
+	}
+	notifier.EpochChan <- &chainntnfs.BlockEpoch{Height: testStartingHeight}
+
+	// Only a single forward fits within the ceiling.
+	switchForwardInterceptor, err := NewInterceptableSwitch(
+		&InterceptableSwitchConfig{
+			Switch:             c.s,
+			CltvRejectDelta:    c.cltvRejectDelta,
+			CltvInterceptDelta: c.cltvInterceptDelta,
+			Notifier:           notifier,
+			MaxHeldHtlcMemory: heldHtlcBaseSize +
+				lnwire.OnionPacketSize,
+		},
+	)
+	require.NoError(t, err)
+	require.NoError(t, switchForwardInterceptor.Start())
+
+	switchForwardInterceptor.SetInterceptor(
+		c.forwardInterceptor.InterceptForwardHtlc,
+	)
+	linkQuit := make(chan struct{})
+
+	createPacket := func(amt lnwire.MilliSatoshi) *htlcPacket {
+		packet := c.createTestPacket()
+		packet.incomingAmount = amt
+
+		return packet
+	}
+
+	// Forward a batch of two new packets. The one with the lowest value is
+	// failed back before it is offered to the interceptor.
+	require.NoError(t, switchForwardInterceptor.ForwardPackets(
+		linkQuit, false, createPacket(2), createPacket(1),
+	))
+	assertOutgoingLinkReceive(t, c.aliceChannelLink, true)
+
+	held := c.forwardInterceptor.getIntercepted()
+	require.EqualValues(t, 2, held.IncomingAmount)
+
+	// The held forward was offered to the interceptor already, so it is
+	// kept. A new forward is failed back instead, even though it has a
+	// higher value.
+	require.NoError(t, switchForwardInterceptor.ForwardPackets(
+		linkQuit, false, createPacket(3),
+	))
+	assertOutgoingLinkReceive(t, c.aliceChannelLink, true)
+
+	// A replayed forward is never failed back, as the interceptor may
+	// have seen it before.
+	require.NoError(t, switchForwardInterceptor.ForwardPackets(
+		linkQuit, true, createPacket(1),
+	))
+	replayed := c.forwardInterceptor.getIntercepted()
+	require.EqualValues(t, 1, replayed.IncomingAmount)
+
+	stats := switchForwardInterceptor.HeldHtlcStats()
+	require.Equal(t, 2, stats.NumHeld)
+	require.EqualValues(t, 2, stats.NumEvicted)
+
+	// Fail both held forwards.
+	for _, key := range []models.CircuitKey{
+		held.IncomingCircuit, replayed.IncomingCircuit,
+	} {
+		require.NoError(t, switchForwardInterceptor.Resolve(
+			&FwdResolution{
+				Action:      FwdActionFail,
+				Key:         key,
+				FailureCode: lnwire.CodeTemporaryChannelFailure,
+			},
+		))
+		assertOutgoingLinkReceive(t, c.aliceChannelLink, true)
+	}
+
+	assertOutgoingLinkReceive(t, c.bobChannelLink, false)
+	assertNumCircuits(t, c.s, 0, 0)
+	require.Zero(t, switchForwardInterceptor.HeldHtlcStats().NumHeld)
+
+	require.NoError(t, switchForwardInterceptor.Stop())
+
+	select {
+	case <-c.forwardInterceptor.interceptedChan:
+		require.Fail(t, "unexpected interception")
+
+	default:
+	}
+}
+
 func TestInterceptableSwitchWatchDog(t *testing.T) {
 	t.Parallel()
 
@@ -4873,6 +5056,126 @@ func TestSwitchResolution(t *testing.T) {
 	require.Equal(t, 0, len(resMsgs))
 }
 
+// TestSwitchFailStrandedHTLCs checks that the switch fails back HTLCs that are
+// still open on a resolved outgoing channel, without touching those that
+// already received a resolution.
+func TestSwitchFailStrandedHTLCs(t *testing.T) {
+	t.Parallel()
+
+	alicePeer, err := newMockServer(
+		t, "alice", testStartingHeight, nil, testDefaultDelta,
+	)
+	require.NoError(t, err)
+
+	bobPeer, err := newMockServer(
+		t, "bob", testStartingHeight, nil, testDefaultDelta,
+	)
+	require.NoError(t, err)
+
+	s, err := initSwitchWithTempDB(t, testStartingHeight)
+	require.NoError(t, err)
+	require.NoError(t, s.Start())
+	t.Cleanup(func() { var _ = s.Stop() })
+
+	chanID1, chanID2, aliceChanID, bobChanID := genIDs()
+
+	aliceChannelLink := newMockChannelLink(
+		s, chanID1, aliceChanID, emptyScid, alicePeer, true, false,
+		false, false,
+	)
+	bobChannelLink := newMockChannelLink(
+		s, chanID2, bobChanID, emptyScid, bobPeer, true, false, false,
+		false,
+	)
+	require.NoError(t, s.AddLink(aliceChannelLink))
+	require.NoError(t, s.AddLink(bobChannelLink))
+
+	// Forward two HTLCs from Alice to Bob and open their circuits.
+	preimage, err := genPreimage()
+	require.NoError(t, err)
+	rhash := sha256.Sum256(preimage[:])
+
+	for i := uint64(0); i < 2; i++ {
+		packet := &htlcPacket{
+			incomingChanID: aliceChannelLink.ShortChanID(),
+			incomingHTLCID: i,
+			outgoingChanID: bobChannelLink.ShortChanID(),
+			obfuscator:     NewMockObfuscator(),
+			htlc: &lnwire.UpdateAddHTLC{
+				PaymentHash: rhash,
+				Amount:      1,
+			},
+		}
+		require.NoError(t, s.ForwardPackets(nil, packet))
+
+		select {
+		case <-bobChannelLink.packets:
+			err := bobChannelLink.completeCircuit(packet)
+			require.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("request was not propagated to destination")
+		}
+	}
+	require.Equal(t, 2, s.circuits.NumOpen())
+
+	// The first HTLC is settled on-chain, so the contract court hands the
+	// switch a settle resolution for it.
+	err = s.ProcessContractResolution(contractcourt.ResolutionMsg{
+		SourceChan: bobChanID,
+		HtlcIndex:  0,
+		PreImage:   &preimage,
+	})
+	require.NoError(t, err)
+
+	select {
+	case pkt := <-aliceChannelLink.packets:
+		require.IsType(t, &lnwire.UpdateFulfillHTLC{}, pkt.htlc)
+		require.EqualValues(t, 0, pkt.incomingHTLCID)
+	case <-time.After(time.Second):
+		t.Fatal("settle was not propagated to alice")
+	}
+
+	// Only the second HTLC is left unresolved once Bob's channel is fully
+	// resolved, so only that one should be failed back to Alice.
+	require.NoError(t, s.FailStrandedHTLCs(bobChanID))
+
+	select {
+	case pkt := <-aliceChannelLink.packets:
+		require.IsType(t, &lnwire.UpdateFailHTLC{}, pkt.htlc)
+		require.EqualValues(t, 1, pkt.incomingHTLCID)
+	case <-time.After(time.Second):
+		t.Fatal("fail was not propagated to alice")
+	}
+
+	// The settle resolution must not have been overwritten.
+	resMsgs, err := s.resMsgStore.fetchAllResolutionMsg()
+	require.NoError(t, err)
+	require.Len(t, resMsgs, 2)
+
+	for _, resMsg := range resMsgs {
+		switch resMsg.HtlcIndex {
+		case 0:
+			require.Equal(t, preimage, *resMsg.PreImage)
+
+		case 1:
+			require.NotNil(t, resMsg.Failure)
+
+		default:
+			t.Fatalf("unexpected resolution: %v", resMsg.HtlcIndex)
+		}
+	}
+
+	// Failing the stranded HTLCs again is a no-op, as both circuits are
+	// already closing.
+	require.NoError(t, s.FailStrandedHTLCs(bobChanID))
+
+	select {
+	case pkt := <-aliceChannelLink.packets:
+		t.Fatalf("received unexpected packet: %v", pkt)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
 // TestSwitchForwardFailAlias tests that if ForwardPackets returns a failure
 // before actually forwarding, the ChannelUpdate uses the SCID from the
 // incoming channel and does not leak private information like the UTXO.
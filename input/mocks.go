@@ -281,3 +281,29 @@ func (m *MockInputSigner) MuSig2Cleanup(sessionID MuSig2SessionID) error {
 
 	return args.Error(0)
 }
+
+// MuSig2DeriveNonce deterministically derives the public nonce of the local key
+// identified by the key locator for the given derivation.
+func (m *MockInputSigner) MuSig2DeriveNonce(locator keychain.KeyLocator,
+	derivation MuSig2NonceDerivation) ([musig2.PubNonceSize]byte, error) {
+
+	args := m.Called(locator, derivation)
+
+	return args.Get(0).([musig2.PubNonceSize]byte), args.Error(1)
+}
+
+// MuSig2CreateDerivedSession creates a new MuSig2 signing session using the
+// local key identified by the key locator, with the local nonces derived for
+// the given derivation.
+func (m *MockInputSigner) MuSig2CreateDerivedSession(version MuSig2Version,
+	locator keychain.KeyLocator, pubkey []*btcec.PublicKey,
+	tweak *MuSig2Tweaks, pubNonces [][musig2.PubNonceSize]byte,
+	derivation MuSig2NonceDerivation) (*MuSig2SessionInfo, error) {
+
+	args := m.Called(version, locator, pubkey, tweak, pubNonces, derivation)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*MuSig2SessionInfo), args.Error(1)
+}
@@ -2,7 +2,9 @@ package input
 
 import (
 	"bytes"
+	"crypto/hmac"
 	"crypto/sha256"
+	"encoding/binary"
 	"fmt"
 
 	"github.com/btcsuite/btcd/btcec/v2"
@@ -29,6 +31,11 @@ const (
 )
 
 const (
+	// muSig2NonceDerivationTag is mixed into the derivation of
+	// deterministic MuSig2 nonces to separate them from other uses of the
+	// signing key.
+	muSig2NonceDerivationTag = "lnd/musig2/nonce"
+
 	// MuSig2PartialSigSize is the size of a MuSig2 partial signature.
 	// Because a partial signature is just the s value, this corresponds to
 	// the length of a scalar.
@@ -83,6 +90,35 @@ type MuSig2Signer interface {
 
 	// MuSig2Cleanup removes a session from memory to free up resources.
 	MuSig2Cleanup(MuSig2SessionID) error
+
+	// MuSig2DeriveNonce deterministically derives the public nonce of the
+	// local key identified by the key locator for the given derivation.
+	// The secret nonce never leaves the signer, it is derived again when a
+	// session is created with MuSig2CreateDerivedSession.
+	MuSig2DeriveNonce(keychain.KeyLocator,
+		MuSig2NonceDerivation) ([musig2.PubNonceSize]byte, error)
+
+	// MuSig2CreateDerivedSession creates a new MuSig2 signing session like
+	// MuSig2CreateSession, except that the local nonces are derived by the
+	// signer for the given derivation instead of being generated randomly
+	// or provided by the caller.
+	MuSig2CreateDerivedSession(MuSig2Version, keychain.KeyLocator,
+		[]*btcec.PublicKey, *MuSig2Tweaks, [][musig2.PubNonceSize]byte,
+		MuSig2NonceDerivation) (*MuSig2SessionInfo, error)
+}
+
+// MuSig2NonceDerivation identifies a set of nonces a signer derives
+// deterministically from one of its keys. This allows the secret nonces to be
+// re-created after a restart without ever leaving the signer. A derivation
+// must never be used to sign more than one message.
+type MuSig2NonceDerivation struct {
+	// Context separates the nonces of different uses of the same key, for
+	// example different channels.
+	Context [32]byte
+
+	// Index is the index of the nonces within the context, for example a
+	// commitment height.
+	Index uint64
 }
 
 // MuSig2Context is an interface that is an abstraction over the MuSig2 signing
@@ -377,6 +413,28 @@ func combineKeysV040(allSignerPubKeys []*btcec.PublicKey, sortKeys bool,
 	}, err
 }
 
+// DeriveMuSig2Nonces deterministically derives the nonces of the given private
+// key for a derivation. The randomness used to generate the nonces is an HMAC
+// of the derivation keyed with the private key, so the nonces can only be
+// derived by the holder of the key.
+func DeriveMuSig2Nonces(privKey *btcec.PrivateKey,
+	derivation MuSig2NonceDerivation) (*musig2.Nonces, error) {
+
+	var index [8]byte
+	binary.BigEndian.PutUint64(index[:], derivation.Index)
+
+	mac := hmac.New(sha256.New, privKey.Serialize())
+	_, _ = mac.Write([]byte(muSig2NonceDerivationTag))
+	_, _ = mac.Write(derivation.Context[:])
+	_, _ = mac.Write(index[:])
+
+	return musig2.GenNonces(
+		musig2.WithPublicKey(privKey.PubKey()),
+		musig2.WithNonceSecretKeyAux(privKey),
+		musig2.WithCustomRand(bytes.NewReader(mac.Sum(nil))),
+	)
+}
+
 // MuSig2CreateContext creates a new MuSig2 signing context.
 func MuSig2CreateContext(bipVersion MuSig2Version, privKey *btcec.PrivateKey,
 	allSignerPubKeys []*btcec.PublicKey, tweaks *MuSig2Tweaks,
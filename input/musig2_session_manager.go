@@ -142,6 +142,62 @@ func (m *MusigSessionManager) MuSig2CreateSession(bipVersion MuSig2Version,
 	return &session.MuSig2SessionInfo, nil
 }
 
+// MuSig2DeriveNonce deterministically derives the public nonce of the local
+// key identified by the key locator for the given derivation.
+func (m *MusigSessionManager) MuSig2DeriveNonce(keyLoc keychain.KeyLocator,
+	derivation MuSig2NonceDerivation) ([musig2.PubNonceSize]byte, error) {
+
+	var pubNonce [musig2.PubNonceSize]byte
+
+	privKey, err := m.keyFetcher(&keychain.KeyDescriptor{
+		KeyLocator: keyLoc,
+	})
+	if err != nil {
+		return pubNonce, fmt.Errorf("error deriving private key: %w",
+			err)
+	}
+
+	nonces, err := DeriveMuSig2Nonces(privKey, derivation)
+	if err != nil {
+		return pubNonce, fmt.Errorf("error deriving nonces: %w", err)
+	}
+
+	return nonces.PubNonce, nil
+}
+
+// MuSig2CreateDerivedSession creates a new MuSig2 signing session using the
+// local key identified by the key locator, with the local nonces derived for
+// the given derivation. Only version 1.0.0rc2 of the MuSig2 BIP draft supports
+// pre-generated nonces.
+func (m *MusigSessionManager) MuSig2CreateDerivedSession(
+	bipVersion MuSig2Version, keyLoc keychain.KeyLocator,
+	allSignerPubKeys []*btcec.PublicKey, tweaks *MuSig2Tweaks,
+	otherSignerNonces [][musig2.PubNonceSize]byte,
+	derivation MuSig2NonceDerivation) (*MuSig2SessionInfo, error) {
+
+	if bipVersion != MuSig2Version100RC2 {
+		return nil, fmt.Errorf("derived nonces are not supported by "+
+			"MuSig2 version %d", bipVersion)
+	}
+
+	privKey, err := m.keyFetcher(&keychain.KeyDescriptor{
+		KeyLocator: keyLoc,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error deriving private key: %w", err)
+	}
+
+	localNonces, err := DeriveMuSig2Nonces(privKey, derivation)
+	if err != nil {
+		return nil, fmt.Errorf("error deriving nonces: %w", err)
+	}
+
+	return m.MuSig2CreateSession(
+		bipVersion, keyLoc, allSignerPubKeys, tweaks,
+		otherSignerNonces, localNonces,
+	)
+}
+
 // MuSig2Sign creates a partial signature using the local signing key
 // that was specified when the session was created. This can only be
 // called when all public nonces of all participants are known and have
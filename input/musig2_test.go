@@ -6,6 +6,7 @@ import (
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/stretchr/testify/require"
 )
 
@@ -206,3 +207,56 @@ func TestMuSig2CombineKeys(t *testing.T) {
 		})
 	}
 }
+
+// TestMuSig2DerivedSession tests that nonces are derived deterministically and
+// that a derived session uses the nonce handed out for its derivation.
+func TestMuSig2DerivedSession(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	manager := NewMusigSessionManager(
+		func(*keychain.KeyDescriptor) (*btcec.PrivateKey, error) {
+			return privKey, nil
+		},
+	)
+
+	derivation := MuSig2NonceDerivation{Context: [32]byte{1}, Index: 5}
+	pubNonce, err := manager.MuSig2DeriveNonce(
+		keychain.KeyLocator{}, derivation,
+	)
+	require.NoError(t, err)
+
+	// The same derivation always results in the same nonce, while a
+	// different index or context results in a different one.
+	nonces, err := DeriveMuSig2Nonces(privKey, derivation)
+	require.NoError(t, err)
+	require.Equal(t, pubNonce, nonces.PubNonce)
+
+	otherIndex := MuSig2NonceDerivation{Context: [32]byte{1}, Index: 6}
+	nonces, err = DeriveMuSig2Nonces(privKey, otherIndex)
+	require.NoError(t, err)
+	require.NotEqual(t, pubNonce, nonces.PubNonce)
+
+	otherContext := MuSig2NonceDerivation{Context: [32]byte{2}, Index: 5}
+	nonces, err = DeriveMuSig2Nonces(privKey, otherContext)
+	require.NoError(t, err)
+	require.NotEqual(t, pubNonce, nonces.PubNonce)
+
+	signers := []*btcec.PublicKey{privKey.PubKey(), dummyPubKey1}
+	session, err := manager.MuSig2CreateDerivedSession(
+		MuSig2Version100RC2, keychain.KeyLocator{}, signers,
+		&MuSig2Tweaks{}, nil, derivation,
+	)
+	require.NoError(t, err)
+	require.Equal(t, pubNonce, session.PublicNonce)
+
+	// The older version of the BIP draft doesn't support pre-generated
+	// nonces.
+	_, err = manager.MuSig2CreateDerivedSession(
+		MuSig2Version040, keychain.KeyLocator{}, signers,
+		&MuSig2Tweaks{}, nil, derivation,
+	)
+	require.ErrorContains(t, err, "not supported")
+}
@@ -55,7 +55,7 @@ func (f *interceptedForward) Resume() error {
 // ResumeModified notifies the intention to resume an existing hold forward with
 // a modified htlc.
 func (f *interceptedForward) ResumeModified(_, _ fn.Option[lnwire.MilliSatoshi],
-	_ fn.Option[lnwire.CustomRecords]) error {
+	_ fn.Option[lnwire.CustomRecords], _ fn.Option[uint32]) error {
 
 	return ErrCannotResume
 }
@@ -0,0 +1,280 @@
+package invoices
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/btcsuite/btcd/btcutil/bech32"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// bolt12Charset is the bech32 character set that BOLT 12 strings are
+	// encoded with.
+	bolt12Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+	// signatureTypeStart and signatureTypeEnd delimit the inclusive range
+	// of TLV types that hold signatures. These are excluded from the
+	// merkle root that is signed.
+	signatureTypeStart = 240
+	signatureTypeEnd   = 1000
+)
+
+var (
+	// leafTag, nonceTag and branchTag are the tags of the hashes of the
+	// BOLT 12 merkle tree.
+	leafTag   = []byte("LnLeaf")
+	nonceTag  = []byte("LnNonce")
+	branchTag = []byte("LnBranch")
+
+	// errMixedCase is returned when a BOLT 12 string mixes upper and lower
+	// case characters.
+	errMixedCase = errors.New("bolt12 string has mixed case")
+)
+
+// tlvRecord is a single raw record of a TLV stream.
+type tlvRecord struct {
+	// recordType is the type of the record.
+	recordType uint64
+
+	// raw is the complete encoding of the record, including its type and
+	// length.
+	raw []byte
+}
+
+// splitTLVStream splits the serialized TLV stream into its raw records. The
+// record types must be strictly increasing.
+func splitTLVStream(stream []byte) ([]tlvRecord, error) {
+	var (
+		records []tlvRecord
+		buf     [8]byte
+		r       = bytes.NewReader(stream)
+	)
+	for r.Len() > 0 {
+		start := len(stream) - r.Len()
+
+		recordType, err := tlv.ReadVarInt(r, &buf)
+		if err != nil {
+			return nil, err
+		}
+
+		length, err := tlv.ReadVarInt(r, &buf)
+		if err != nil {
+			return nil, err
+		}
+		if length > uint64(r.Len()) {
+			return nil, fmt.Errorf("tlv record %d exceeds stream",
+				recordType)
+		}
+
+		numRecords := len(records)
+		if numRecords > 0 &&
+			recordType <= records[numRecords-1].recordType {
+
+			return nil, fmt.Errorf("tlv record %d out of order",
+				recordType)
+		}
+
+		if _, err := r.Seek(int64(length), io.SeekCurrent); err != nil {
+			return nil, err
+		}
+
+		end := len(stream) - r.Len()
+		records = append(records, tlvRecord{
+			recordType: recordType,
+			raw:        stream[start:end],
+		})
+	}
+
+	return records, nil
+}
+
+// joinTLVRecords serializes the given raw records into a TLV stream, ordered
+// by their types.
+func joinTLVRecords(records []tlvRecord) []byte {
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].recordType < records[j].recordType
+	})
+
+	var b bytes.Buffer
+	for _, record := range records {
+		b.Write(record.raw)
+	}
+
+	return b.Bytes()
+}
+
+// filterTLVRecords returns the records whose types are within the inclusive
+// range [start, end].
+func filterTLVRecords(records []tlvRecord, start,
+	end uint64) []tlvRecord {
+
+	var filtered []tlvRecord
+	for _, record := range records {
+		if record.recordType < start || record.recordType > end {
+			continue
+		}
+
+		filtered = append(filtered, record)
+	}
+
+	return filtered
+}
+
+// isSignatureType returns true if the TLV type holds a signature.
+func isSignatureType(recordType uint64) bool {
+	return recordType >= signatureTypeStart &&
+		recordType <= signatureTypeEnd
+}
+
+// merkleBranch returns the inner node of the merkle tree for the given
+// children, which are hashed in ascending order.
+func merkleBranch(a, b *chainhash.Hash) *chainhash.Hash {
+	if bytes.Compare(a[:], b[:]) > 0 {
+		a, b = b, a
+	}
+
+	return chainhash.TaggedHash(branchTag, a[:], b[:])
+}
+
+// merkleRoot computes the BOLT 12 merkle root of the given records. Every
+// record that doesn't hold a signature contributes a leaf that commits to
+// the record and to a nonce derived from the first record of the stream. If
+// the number of leaves isn't a power of two, the lowest-order leaves are put
+// deepest into the tree.
+func merkleRoot(records []tlvRecord) (*chainhash.Hash, error) {
+	if len(records) == 0 {
+		return nil, errors.New("no tlv records to hash")
+	}
+
+	nonceTagFull := append(
+		append([]byte{}, nonceTag...), records[0].raw...,
+	)
+
+	var (
+		level []*chainhash.Hash
+		buf   [8]byte
+	)
+	for _, record := range records {
+		if isSignatureType(record.recordType) {
+			continue
+		}
+
+		var recordType bytes.Buffer
+		err := tlv.WriteVarInt(&recordType, record.recordType, &buf)
+		if err != nil {
+			return nil, err
+		}
+
+		leaf := chainhash.TaggedHash(leafTag, record.raw)
+		nonce := chainhash.TaggedHash(
+			nonceTagFull, recordType.Bytes(),
+		)
+		level = append(level, merkleBranch(leaf, nonce))
+	}
+
+	for len(level) > 1 {
+		next := make([]*chainhash.Hash, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+				continue
+			}
+
+			next = append(next, merkleBranch(level[i], level[i+1]))
+		}
+
+		level = next
+	}
+
+	return level[0], nil
+}
+
+// signatureTag returns the tag that is used to sign the merkle root of the
+// given BOLT 12 message.
+func signatureTag(messageName, fieldName string) []byte {
+	return []byte("lightning" + messageName + fieldName)
+}
+
+// encodeBolt12 encodes the data as a BOLT 12 string with the given human
+// readable part. Unlike bech32, BOLT 12 strings don't carry a checksum.
+func encodeBolt12(hrp string, data []byte) (string, error) {
+	converted, err := bech32.ConvertBits(data, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.Grow(len(hrp) + 1 + len(converted))
+	b.WriteString(hrp)
+	b.WriteByte('1')
+	for _, c := range converted {
+		b.WriteByte(bolt12Charset[c])
+	}
+
+	return b.String(), nil
+}
+
+// decodeBolt12 decodes a BOLT 12 string with the given human readable part.
+// Long strings may be split into several parts that are joined by a '+',
+// optionally followed by whitespace.
+func decodeBolt12(hrp, s string) ([]byte, error) {
+	lower, upper := strings.ToLower(s), strings.ToUpper(s)
+	if s != lower && s != upper {
+		return nil, errMixedCase
+	}
+
+	var (
+		b       strings.Builder
+		skipWS  bool
+		lastAdd = -1
+	)
+	for i, c := range lower {
+		switch {
+		case c == '+':
+			if b.Len() == 0 || lastAdd == b.Len() {
+				return nil, fmt.Errorf("invalid '+' at "+
+					"position %d", i)
+			}
+			lastAdd = b.Len()
+			skipWS = true
+
+		case unicode.IsSpace(c):
+			if !skipWS {
+				return nil, fmt.Errorf("invalid whitespace "+
+					"at position %d", i)
+			}
+
+		default:
+			skipWS = false
+			b.WriteRune(c)
+		}
+	}
+	if lastAdd == b.Len() {
+		return nil, errors.New("bolt12 string ends with '+'")
+	}
+
+	joined := b.String()
+	prefix := hrp + "1"
+	if !strings.HasPrefix(joined, prefix) {
+		return nil, fmt.Errorf("expected prefix %q", prefix)
+	}
+
+	values := make([]byte, 0, len(joined)-len(prefix))
+	for _, c := range joined[len(prefix):] {
+		value := strings.IndexRune(bolt12Charset, c)
+		if value < 0 {
+			return nil, fmt.Errorf("invalid character %q", c)
+		}
+
+		values = append(values, byte(value))
+	}
+
+	return bech32.ConvertBits(values, 5, 8, false)
+}
@@ -94,6 +94,13 @@ var (
 	// ErrNoPaymentsCreated is returned when bucket of payments hasn't been
 	// created.
 	ErrNoPaymentsCreated = errors.New("there are no existing payments")
+
+	// ErrOfferNotFound is returned when a targeted offer can't be found.
+	ErrOfferNotFound = errors.New("unable to locate offer")
+
+	// ErrDuplicateOffer is returned when an offer with the same id already
+	// exists.
+	ErrDuplicateOffer = errors.New("offer with id already exists")
 )
 
 // ErrDuplicateSetID is an error returned when attempting to adding an AMP HTLC
@@ -0,0 +1,253 @@
+package invoices
+
+import (
+	"sort"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// InvoiceEventType is the type of a state transition of an invoice.
+type InvoiceEventType uint8
+
+const (
+	// InvoiceEventCreated is the creation of the invoice.
+	InvoiceEventCreated InvoiceEventType = iota
+
+	// InvoiceEventHtlcAccepted is the acceptance of an htlc paying to the
+	// invoice.
+	InvoiceEventHtlcAccepted
+
+	// InvoiceEventHtlcSettled is the settlement of an htlc paying to the
+	// invoice.
+	InvoiceEventHtlcSettled
+
+	// InvoiceEventHtlcCanceled is the cancellation of an htlc paying to
+	// the invoice.
+	InvoiceEventHtlcCanceled
+
+	// InvoiceEventSetComplete is the arrival of the htlc that completed
+	// the set of htlcs paying the invoice.
+	InvoiceEventSetComplete
+
+	// InvoiceEventSettled is the settlement of the invoice.
+	InvoiceEventSettled
+
+	// InvoiceEventCanceled is the cancellation of the invoice.
+	InvoiceEventCanceled
+)
+
+// String returns a human-readable representation of the event type.
+func (t InvoiceEventType) String() string {
+	switch t {
+	case InvoiceEventCreated:
+		return "Created"
+
+	case InvoiceEventHtlcAccepted:
+		return "HtlcAccepted"
+
+	case InvoiceEventHtlcSettled:
+		return "HtlcSettled"
+
+	case InvoiceEventHtlcCanceled:
+		return "HtlcCanceled"
+
+	case InvoiceEventSetComplete:
+		return "SetComplete"
+
+	case InvoiceEventSettled:
+		return "Settled"
+
+	case InvoiceEventCanceled:
+		return "Canceled"
+
+	default:
+		return "Unknown"
+	}
+}
+
+// InvoiceEvent is a single state transition of an invoice.
+type InvoiceEvent struct {
+	// Type is the type of the transition.
+	Type InvoiceEventType
+
+	// Timestamp is the time of the transition. It is zero if the time
+	// isn't known.
+	Timestamp time.Time
+
+	// CircuitKey identifies the htlc of htlc events, and the htlc that
+	// completed the set of InvoiceEventSetComplete events.
+	CircuitKey *CircuitKey
+
+	// Amt is the amount of the htlc for htlc events, and the total amount
+	// of the completed set for InvoiceEventSetComplete events.
+	Amt lnwire.MilliSatoshi
+}
+
+// InvoiceHistoryUpdate is sent to the subscribers of invoice history updates
+// whenever an invoice changes.
+type InvoiceHistoryUpdate struct {
+	// Hash is the payment hash of the invoice.
+	Hash lntypes.Hash
+
+	// History is the full history of the invoice after the change.
+	History []InvoiceEvent
+}
+
+// InvoiceHistory reconstructs the state transitions of the given invoice from
+// the timestamps that are persisted with the invoice and its htlcs. The
+// events are ordered by their timestamps. Events whose time isn't known are
+// put at the end.
+//
+// The completion of the htlc set isn't reported for AMP invoices, as each of
+// their sub-invoices is paid by a set of its own.
+func InvoiceHistory(invoice *Invoice) []InvoiceEvent {
+	events := []InvoiceEvent{{
+		Type:      InvoiceEventCreated,
+		Timestamp: invoice.CreationDate,
+	}}
+
+	htlcs := sortedHtlcs(invoice)
+
+	var lastResolveTime time.Time
+	for _, htlc := range htlcs {
+		key := htlc.key
+		events = append(events, InvoiceEvent{
+			Type:       InvoiceEventHtlcAccepted,
+			Timestamp:  htlc.AcceptTime,
+			CircuitKey: &key,
+			Amt:        htlc.Amt,
+		})
+
+		var eventType InvoiceEventType
+		switch htlc.State {
+		case HtlcStateSettled:
+			eventType = InvoiceEventHtlcSettled
+
+		case HtlcStateCanceled:
+			eventType = InvoiceEventHtlcCanceled
+
+		default:
+			continue
+		}
+
+		events = append(events, InvoiceEvent{
+			Type:       eventType,
+			Timestamp:  htlc.ResolveTime,
+			CircuitKey: &key,
+			Amt:        htlc.Amt,
+		})
+
+		if htlc.ResolveTime.After(lastResolveTime) {
+			lastResolveTime = htlc.ResolveTime
+		}
+	}
+
+	isPaid := invoice.State == ContractAccepted ||
+		invoice.State == ContractSettled
+	if isPaid && !invoice.IsAMP() {
+		if event, ok := setCompleteEvent(invoice, htlcs); ok {
+			events = append(events, event)
+		}
+	}
+
+	switch invoice.State {
+	case ContractSettled:
+		events = append(events, InvoiceEvent{
+			Type:      InvoiceEventSettled,
+			Timestamp: invoice.SettleDate,
+		})
+
+	// The cancellation time of an invoice isn't persisted. If htlcs were
+	// canceled along with the invoice, their resolve time is the best
+	// estimate that we have.
+	case ContractCanceled:
+		events = append(events, InvoiceEvent{
+			Type:      InvoiceEventCanceled,
+			Timestamp: lastResolveTime,
+		})
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[j].Timestamp.IsZero() {
+			return !events[i].Timestamp.IsZero()
+		}
+		if events[i].Timestamp.IsZero() {
+			return false
+		}
+
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+
+	return events
+}
+
+// keyedHtlc is an invoice htlc together with its circuit key.
+type keyedHtlc struct {
+	*InvoiceHTLC
+
+	key CircuitKey
+}
+
+// sortedHtlcs returns the htlcs of the invoice ordered by their accept time,
+// and by their circuit keys if they were accepted at the same time.
+func sortedHtlcs(invoice *Invoice) []keyedHtlc {
+	htlcs := make([]keyedHtlc, 0, len(invoice.Htlcs))
+	for key, htlc := range invoice.Htlcs {
+		htlcs = append(htlcs, keyedHtlc{InvoiceHTLC: htlc, key: key})
+	}
+
+	sort.Slice(htlcs, func(i, j int) bool {
+		a, b := htlcs[i], htlcs[j]
+		if !a.AcceptTime.Equal(b.AcceptTime) {
+			return a.AcceptTime.Before(b.AcceptTime)
+		}
+
+		aChanID, bChanID := a.key.ChanID.ToUint64(),
+			b.key.ChanID.ToUint64()
+		if aChanID != bChanID {
+			return aChanID < bChanID
+		}
+
+		return a.key.HtlcID < b.key.HtlcID
+	})
+
+	return htlcs
+}
+
+// setCompleteEvent returns the event of the htlc that completed the set of
+// htlcs paying the invoice. Canceled htlcs never were part of the final set,
+// so they are skipped.
+func setCompleteEvent(invoice *Invoice, htlcs []keyedHtlc) (InvoiceEvent,
+	bool) {
+
+	var setTotal lnwire.MilliSatoshi
+	for _, htlc := range htlcs {
+		if htlc.State == HtlcStateCanceled {
+			continue
+		}
+
+		setTotal += htlc.Amt
+
+		// The expected total is the mpp total amount of the htlcs, or
+		// the invoice value for htlcs that aren't part of an mpp set.
+		target := htlc.MppTotalAmt
+		if target == 0 {
+			target = invoice.Terms.Value
+		}
+		if setTotal < target {
+			continue
+		}
+
+		key := htlc.key
+		return InvoiceEvent{
+			Type:       InvoiceEventSetComplete,
+			Timestamp:  htlc.AcceptTime,
+			CircuitKey: &key,
+			Amt:        setTotal,
+		}, true
+	}
+
+	return InvoiceEvent{}, false
+}
@@ -0,0 +1,115 @@
+package invoices
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestInvoiceHistory tests that the history of an invoice is reconstructed
+// from the timestamps of the invoice and its htlcs.
+func TestInvoiceHistory(t *testing.T) {
+	t.Parallel()
+
+	created := time.Unix(1_000, 0)
+	at := func(seconds int64) time.Time {
+		return created.Add(time.Duration(seconds) * time.Second)
+	}
+
+	key1 := CircuitKey{ChanID: lnwire.NewShortChanIDFromInt(1), HtlcID: 1}
+	key2 := CircuitKey{ChanID: lnwire.NewShortChanIDFromInt(1), HtlcID: 2}
+	key3 := CircuitKey{ChanID: lnwire.NewShortChanIDFromInt(2), HtlcID: 1}
+
+	invoice := &Invoice{
+		CreationDate: created,
+		SettleDate:   at(40),
+		State:        ContractSettled,
+		Terms: ContractTerm{
+			Value: 1_000,
+		},
+		Htlcs: map[CircuitKey]*InvoiceHTLC{
+			// The first part of the set timed out and was canceled.
+			key1: {
+				Amt:         600,
+				MppTotalAmt: 1_000,
+				AcceptTime:  at(10),
+				ResolveTime: at(20),
+				State:       HtlcStateCanceled,
+			},
+			key2: {
+				Amt:         400,
+				MppTotalAmt: 1_000,
+				AcceptTime:  at(25),
+				ResolveTime: at(40),
+				State:       HtlcStateSettled,
+			},
+			key3: {
+				Amt:         600,
+				MppTotalAmt: 1_000,
+				AcceptTime:  at(30),
+				ResolveTime: at(40),
+				State:       HtlcStateSettled,
+			},
+		},
+	}
+
+	require.Equal(t, []InvoiceEvent{
+		{Type: InvoiceEventCreated, Timestamp: created},
+		{
+			Type:       InvoiceEventHtlcAccepted,
+			Timestamp:  at(10),
+			CircuitKey: &key1,
+			Amt:        600,
+		},
+		{
+			Type:       InvoiceEventHtlcCanceled,
+			Timestamp:  at(20),
+			CircuitKey: &key1,
+			Amt:        600,
+		},
+		{
+			Type:       InvoiceEventHtlcAccepted,
+			Timestamp:  at(25),
+			CircuitKey: &key2,
+			Amt:        400,
+		},
+		{
+			Type:       InvoiceEventHtlcAccepted,
+			Timestamp:  at(30),
+			CircuitKey: &key3,
+			Amt:        600,
+		},
+		{
+			Type:       InvoiceEventSetComplete,
+			Timestamp:  at(30),
+			CircuitKey: &key3,
+			Amt:        1_000,
+		},
+		{
+			Type:       InvoiceEventHtlcSettled,
+			Timestamp:  at(40),
+			CircuitKey: &key2,
+			Amt:        400,
+		},
+		{
+			Type:       InvoiceEventHtlcSettled,
+			Timestamp:  at(40),
+			CircuitKey: &key3,
+			Amt:        600,
+		},
+		{Type: InvoiceEventSettled, Timestamp: at(40)},
+	}, InvoiceHistory(invoice))
+
+	// The cancellation time of an invoice without htlcs isn't known, so
+	// the event is put at the end without a timestamp.
+	canceled := &Invoice{
+		CreationDate: created,
+		State:        ContractCanceled,
+	}
+	require.Equal(t, []InvoiceEvent{
+		{Type: InvoiceEventCreated, Timestamp: created},
+		{Type: InvoiceEventCanceled},
+	}, InvoiceHistory(canceled))
+}
@@ -88,6 +88,38 @@ type InvoiceDB interface {
 	DeleteCanceledInvoices(ctx context.Context) error
 }
 
+// OfferDB is the database that stores the offers that we issued, together
+// with the state of their payments.
+type OfferDB interface {
+	// AddOffer inserts the offer into the database. If an offer with the
+	// same id already exists, ErrDuplicateOffer is returned.
+	AddOffer(ctx context.Context, offer *OfferState) error
+
+	// FetchOffer returns the offer with the given id. If the offer isn't
+	// found, ErrOfferNotFound is returned.
+	FetchOffer(ctx context.Context, id OfferID) (*OfferState, error)
+
+	// FetchOffers returns all offers, ordered by their creation time.
+	FetchOffers(ctx context.Context) ([]*OfferState, error)
+
+	// AddOfferInvoice links the invoice with the given payment hash to the
+	// offer that it was created for.
+	AddOfferInvoice(ctx context.Context, id OfferID,
+		paymentHash lntypes.Hash) error
+
+	// RecordOfferPayment records the settlement of the invoice with the
+	// given payment hash and settle index. If the invoice belongs to an
+	// offer, the amount is added to the payments of the offer. The settle
+	// index is stored either way, so that settlements can be resumed after
+	// a restart.
+	RecordOfferPayment(ctx context.Context, paymentHash lntypes.Hash,
+		amt lnwire.MilliSatoshi, settleIndex uint64) error
+
+	// OfferSettleIndex returns the settle index of the last invoice
+	// settlement that was recorded.
+	OfferSettleIndex(ctx context.Context) (uint64, error)
+}
+
 // Payload abstracts access to any additional fields provided in the final hop's
 // TLV onion payload.
 type Payload interface {
@@ -0,0 +1,402 @@
+package invoices
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// invreqMetadataType is the TLV type of the payer-chosen metadata of
+	// an invoice request.
+	invreqMetadataType tlv.Type = 0
+
+	// invreqChainType is the TLV type of the chain that the payer wants
+	// to pay on.
+	invreqChainType tlv.Type = 80
+
+	// invreqAmountType is the TLV type of the amount that the payer wants
+	// to pay.
+	invreqAmountType tlv.Type = 82
+
+	// invreqFeaturesType is the TLV type of the features of an invoice
+	// request.
+	invreqFeaturesType tlv.Type = 84
+
+	// invreqQuantityType is the TLV type of the number of items that the
+	// payer requests.
+	invreqQuantityType tlv.Type = 86
+
+	// invreqPayerIDType is the TLV type of the public key of the payer.
+	invreqPayerIDType tlv.Type = 88
+
+	// invreqPayerNoteType is the TLV type of the note of the payer.
+	invreqPayerNoteType tlv.Type = 89
+
+	// invoicePathsType is the TLV type of the blinded paths that an
+	// invoice can be paid through.
+	invoicePathsType tlv.Type = 160
+
+	// invoiceBlindedPayType is the TLV type of the fees and constraints
+	// of the blinded paths of an invoice.
+	invoiceBlindedPayType tlv.Type = 162
+
+	// invoiceCreatedAtType is the TLV type of the creation time of an
+	// invoice.
+	invoiceCreatedAtType tlv.Type = 164
+
+	// invoiceRelativeExpiryType is the TLV type of the number of seconds
+	// after its creation that an invoice expires.
+	invoiceRelativeExpiryType tlv.Type = 166
+
+	// invoicePaymentHashType is the TLV type of the payment hash of an
+	// invoice.
+	invoicePaymentHashType tlv.Type = 168
+
+	// invoiceAmountType is the TLV type of the amount of an invoice.
+	invoiceAmountType tlv.Type = 170
+
+	// invoiceNodeIDType is the TLV type of the public key of the node
+	// that issued an invoice.
+	invoiceNodeIDType tlv.Type = 176
+
+	// signatureType is the TLV type of the signature of invoice requests
+	// and invoices.
+	signatureType tlv.Type = 240
+
+	// invoiceErrorMessageType is the TLV type of the message of an
+	// invoice_error.
+	invoiceErrorMessageType tlv.Type = 5
+
+	// invreqTypeStart and invreqTypeEnd delimit the inclusive range of
+	// TLV types that belong to an invoice request, besides its metadata
+	// and the fields of the offer.
+	invreqTypeStart = 80
+	invreqTypeEnd   = 159
+)
+
+// InvoiceRequest is a BOLT 12 invoice_request, which a payer sends to the
+// issuer of an offer to request an invoice.
+type InvoiceRequest struct {
+	// Offer is the offer that the invoice is requested for.
+	Offer *Offer
+
+	// Metadata is data that the payer chose to include in the request.
+	Metadata []byte
+
+	// Chain is the chain that the payer wants to pay on. If none, the
+	// payer wants to pay on bitcoin.
+	Chain fn.Option[chainhash.Hash]
+
+	// Amount is the amount that the payer wants to pay. If zero, the
+	// payer pays the amount of the offer.
+	Amount lnwire.MilliSatoshi
+
+	// Quantity is the number of items that the payer requests.
+	Quantity fn.Option[uint64]
+
+	// PayerID is the public key of the payer, which signed the request.
+	PayerID *btcec.PublicKey
+
+	// PayerNote is a note of the payer for the issuer.
+	PayerNote string
+
+	// records are the raw records of the request, which are mirrored by
+	// the invoice that answers it.
+	records []tlvRecord
+}
+
+// DecodeInvoiceRequest decodes an invoice request and verifies its signature.
+func DecodeInvoiceRequest(data []byte) (*InvoiceRequest, error) {
+	records, err := splitTLVStream(data)
+	if err != nil {
+		return nil, err
+	}
+
+	offerRecords := filterTLVRecords(records, offerTypeStart, offerTypeEnd)
+	offer := &Offer{}
+	err = offer.Decode(bytes.NewReader(joinTLVRecords(offerRecords)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid offer fields: %w", err)
+	}
+
+	var (
+		metadata, features, payerNote []byte
+		chain                         [32]byte
+		amount, quantity              uint64
+		payerID                       *btcec.PublicKey
+		sig                           [64]byte
+	)
+	stream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(invreqMetadataType, &metadata),
+		tlv.MakePrimitiveRecord(invreqChainType, &chain),
+		truncatedUint64Record(invreqAmountType, &amount),
+		tlv.MakePrimitiveRecord(invreqFeaturesType, &features),
+		truncatedUint64Record(invreqQuantityType, &quantity),
+		tlv.MakePrimitiveRecord(invreqPayerIDType, &payerID),
+		tlv.MakePrimitiveRecord(invreqPayerNoteType, &payerNote),
+		tlv.MakePrimitiveRecord(signatureType, &sig),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	requestRecords := filterTLVRecords(
+		records, uint64(invreqMetadataType),
+		uint64(invreqMetadataType),
+	)
+	requestRecords = append(requestRecords, filterTLVRecords(
+		records, invreqTypeStart, invreqTypeEnd,
+	)...)
+	requestRecords = append(requestRecords, filterTLVRecords(
+		records, signatureTypeStart, signatureTypeEnd,
+	)...)
+
+	parsedTypes, err := stream.DecodeWithParsedTypesP2P(
+		bytes.NewReader(joinTLVRecords(requestRecords)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, recordType := range []tlv.Type{
+		invreqMetadataType, invreqPayerIDType, signatureType,
+	} {
+		if _, ok := parsedTypes[recordType]; !ok {
+			return nil, fmt.Errorf("invoice request is missing "+
+				"tlv type %d", recordType)
+		}
+	}
+
+	for _, b := range features {
+		if b != 0 {
+			return nil, errors.New("invoice request requires " +
+				"unknown features")
+		}
+	}
+
+	if !utf8.Valid(payerNote) {
+		return nil, errors.New("payer note is not valid utf-8")
+	}
+
+	// The signature commits to all fields of the request.
+	root, err := merkleRoot(records)
+	if err != nil {
+		return nil, err
+	}
+	digest := chainhash.TaggedHash(
+		signatureTag("invoice_request", "signature"), root[:],
+	)
+
+	signature, err := schnorr.ParseSignature(sig[:])
+	if err != nil {
+		return nil, err
+	}
+	if !signature.Verify(digest[:], payerID) {
+		return nil, errors.New("invalid invoice request signature")
+	}
+
+	req := &InvoiceRequest{
+		Offer:     offer,
+		Metadata:  metadata,
+		Amount:    lnwire.MilliSatoshi(amount),
+		PayerID:   payerID,
+		PayerNote: string(payerNote),
+		records:   records,
+	}
+
+	if _, ok := parsedTypes[invreqChainType]; ok {
+		req.Chain = fn.Some(chainhash.Hash(chain))
+	}
+
+	if _, ok := parsedTypes[invreqQuantityType]; ok {
+		req.Quantity = fn.Some(quantity)
+	}
+
+	return req, nil
+}
+
+// OfferPaymentPath is a blinded path that the invoice of an offer can be paid
+// through, together with the fees and constraints of the path.
+type OfferPaymentPath struct {
+	// Path is the blinded path to our node.
+	Path *lnwire.BlindedPath
+
+	// FeeBaseMsat is the total base fee of the path.
+	FeeBaseMsat uint32
+
+	// FeeRate is the total proportional fee of the path in millionths.
+	FeeRate uint32
+
+	// CltvExpiryDelta is the total time lock delta of the path.
+	CltvExpiryDelta uint16
+
+	// HTLCMinMsat is the minimum htlc amount of the path.
+	HTLCMinMsat uint64
+
+	// HTLCMaxMsat is the maximum htlc amount of the path.
+	HTLCMaxMsat uint64
+}
+
+// encodePayInfo serializes the fees and constraints of the path as a
+// blinded_payinfo, without any features.
+func (p *OfferPaymentPath) encodePayInfo(b *bytes.Buffer) {
+	var buf [8]byte
+
+	binary.BigEndian.PutUint32(buf[:4], p.FeeBaseMsat)
+	b.Write(buf[:4])
+
+	binary.BigEndian.PutUint32(buf[:4], p.FeeRate)
+	b.Write(buf[:4])
+
+	binary.BigEndian.PutUint16(buf[:2], p.CltvExpiryDelta)
+	b.Write(buf[:2])
+
+	binary.BigEndian.PutUint64(buf[:], p.HTLCMinMsat)
+	b.Write(buf[:])
+
+	binary.BigEndian.PutUint64(buf[:], p.HTLCMaxMsat)
+	b.Write(buf[:])
+
+	// The length of the features.
+	binary.BigEndian.PutUint16(buf[:2], 0)
+	b.Write(buf[:2])
+}
+
+// offerInvoice holds the fields that an invoice adds to the invoice request
+// it answers.
+type offerInvoice struct {
+	paths       []*OfferPaymentPath
+	createdAt   time.Time
+	expiry      time.Duration
+	paymentHash lntypes.Hash
+	amount      lnwire.MilliSatoshi
+	nodeID      *btcec.PublicKey
+}
+
+// invoiceSigner signs the given message with the key of our node, using the
+// tagged hash of the message with the given tag as the digest.
+type invoiceSigner func(msg, tag []byte) (*schnorr.Signature, error)
+
+// encodeOfferInvoice creates the BOLT 12 invoice that answers the invoice
+// request. The invoice mirrors all fields of the request except for its
+// signature, and is signed with the key of our node.
+func encodeOfferInvoice(req *InvoiceRequest, inv *offerInvoice,
+	sign invoiceSigner) ([]byte, error) {
+
+	if len(inv.paths) == 0 {
+		return nil, errors.New("invoice must have paths")
+	}
+
+	var pathsBuf, payInfoBuf bytes.Buffer
+	for _, path := range inv.paths {
+		if err := path.Path.Encode(&pathsBuf); err != nil {
+			return nil, err
+		}
+
+		path.encodePayInfo(&payInfoBuf)
+	}
+
+	var (
+		paths       = pathsBuf.Bytes()
+		payInfo     = payInfoBuf.Bytes()
+		createdAt   = uint64(inv.createdAt.Unix())
+		expiry      = uint64(inv.expiry / time.Second)
+		paymentHash = [32]byte(inv.paymentHash)
+		amount      = uint64(inv.amount)
+		nodeID      = inv.nodeID
+	)
+	stream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(invoicePathsType, &paths),
+		tlv.MakePrimitiveRecord(invoiceBlindedPayType, &payInfo),
+		truncatedUint64Record(invoiceCreatedAtType, &createdAt),
+		truncatedUint64Record(invoiceRelativeExpiryType, &expiry),
+		tlv.MakePrimitiveRecord(invoicePaymentHashType, &paymentHash),
+		truncatedUint64Record(invoiceAmountType, &amount),
+		tlv.MakePrimitiveRecord(invoiceNodeIDType, &nodeID),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := stream.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	invoiceRecords, err := splitTLVStream(b.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the records up to the invoice request range are mirrored, so
+	// that the signature remains the last record of the invoice.
+	for _, record := range req.records {
+		if record.recordType > invreqTypeEnd {
+			continue
+		}
+
+		invoiceRecords = append(invoiceRecords, record)
+	}
+
+	unsigned := joinTLVRecords(invoiceRecords)
+	records, err := splitTLVStream(unsigned)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := merkleRoot(records)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := sign(root[:], signatureTag("invoice", "signature"))
+	if err != nil {
+		return nil, err
+	}
+
+	var sigBytes [64]byte
+	copy(sigBytes[:], sig.Serialize())
+	sigStream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(signatureType, &sigBytes),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	signed := bytes.NewBuffer(unsigned)
+	if err := sigStream.Encode(signed); err != nil {
+		return nil, err
+	}
+
+	return signed.Bytes(), nil
+}
+
+// encodeInvoiceError creates a BOLT 12 invoice_error with the given message.
+func encodeInvoiceError(message string) ([]byte, error) {
+	msg := []byte(message)
+	stream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(invoiceErrorMessageType, &msg),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := stream.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
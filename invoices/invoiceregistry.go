@@ -13,6 +13,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/queue"
 	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/subscribe"
 )
 
 var (
@@ -78,6 +79,10 @@ type RegistryConfig struct {
 	// HtlcInterceptor is an interface that allows the invoice registry to
 	// let clients intercept invoices before they are settled.
 	HtlcInterceptor HtlcInterceptor
+
+	// OverpaymentPolicy is the node wide overpayment policy. It's used for
+	// invoices that don't have their own policy.
+	OverpaymentPolicy OverpaymentPolicy
 }
 
 // htlcReleaseEvent describes an htlc auto-release event. It is used to release
@@ -133,6 +138,10 @@ type InvoiceRegistry struct {
 	// carried.
 	invoiceEvents chan *invoiceEvent
 
+	// historyServer dispatches the history of every changed invoice to
+	// its subscribers.
+	historyServer *subscribe.Server
+
 	// hodlSubscriptionsMux locks the hodlSubscriptions and
 	// hodlReverseSubscriptions. Using a separate mutex for these maps is
 	// necessary to avoid deadlocks in the registry when processing invoice
@@ -171,6 +180,7 @@ func NewRegistry(idb InvoiceDB, expiryWatcher *InvoiceExpiryWatcher,
 		notificationClients:       notificationClients,
 		singleNotificationClients: singleNotificationClients,
 		invoiceEvents:             make(chan *invoiceEvent, 100),
+		historyServer:             subscribe.NewServer(),
 		hodlSubscriptions: make(
 			map[CircuitKey]map[chan<- interface{}]struct{},
 		),
@@ -227,6 +237,11 @@ func (i *InvoiceRegistry) Start() error {
 	if i.started.Swap(true) {
 		return fmt.Errorf("InvoiceRegistry started more than once")
 	}
+
+	if err := i.historyServer.Start(); err != nil {
+		return err
+	}
+
 	// Start InvoiceExpiryWatcher and prepopulate it with existing
 	// active invoices.
 	err = i.expiryWatcher.Start(
@@ -277,6 +292,10 @@ func (i *InvoiceRegistry) Stop() error {
 
 	i.wg.Wait()
 
+	if stopErr := i.historyServer.Stop(); stopErr != nil && err == nil {
+		err = stopErr
+	}
+
 	log.Debug("InvoiceRegistry shutdown complete")
 
 	return err
@@ -592,6 +611,7 @@ func (i *InvoiceRegistry) AddInvoice(ctx context.Context, invoice *Invoice,
 	// Now that we've added the invoice, we'll send dispatch a message to
 	// notify the clients of this new invoice.
 	i.notifyClients(paymentHash, invoice, nil)
+	i.notifyHistory(paymentHash, invoice)
 	i.Unlock()
 
 	// InvoiceExpiryWatcher.AddInvoice must not be locked by InvoiceRegistry
@@ -618,6 +638,19 @@ func (i *InvoiceRegistry) LookupInvoice(ctx context.Context,
 	return i.idb.LookupInvoice(ctx, ref)
 }
 
+// LookupInvoiceHistory looks up the invoice with the given payment hash and
+// returns its state transitions.
+func (i *InvoiceRegistry) LookupInvoiceHistory(ctx context.Context,
+	rHash lntypes.Hash) ([]InvoiceEvent, error) {
+
+	invoice, err := i.LookupInvoice(ctx, rHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return InvoiceHistory(&invoice), nil
+}
+
 // LookupInvoiceByRef looks up an invoice by the given reference, if found
 // then we're able to pull the funds pending within an HTLC.
 func (i *InvoiceRegistry) LookupInvoiceByRef(ctx context.Context,
@@ -744,6 +777,10 @@ func (i *InvoiceRegistry) cancelSingleHtlc(invoiceRef InvoiceRef,
 		return nil
 	}
 
+	if hash := invoiceRef.PayHash(); hash != nil {
+		i.notifyHistory(*hash, invoice)
+	}
+
 	// The invoice has been updated. Notify subscribers of the htlc
 	// resolution.
 	htlc, ok := invoice.Htlcs[key]
@@ -937,6 +974,8 @@ func (i *InvoiceRegistry) NotifyExitHopHtlc(rHash lntypes.Hash,
 		metadata:             payload.Metadata(),
 		pathID:               payload.PathID(),
 		totalAmtMsat:         payload.TotalAmtMsat(),
+
+		defaultOverpaymentPolicy: i.cfg.OverpaymentPolicy,
 	}
 
 	switch {
@@ -1080,6 +1119,7 @@ func (i *InvoiceRegistry) notifyExitHopHtlcLocked(
 	var (
 		resolution        HtlcResolution
 		updateSubscribers bool
+		updateHistory     bool
 	)
 	callback := func(inv *Invoice) (*InvoiceUpdateDesc, error) {
 		updateDesc, res, err := updateInvoice(ctx, inv)
@@ -1091,6 +1131,10 @@ func (i *InvoiceRegistry) notifyExitHopHtlcLocked(
 		updateSubscribers = updateDesc != nil &&
 			updateDesc.State != nil
 
+		// The history also changes if only htlcs were added or
+		// canceled.
+		updateHistory = updateDesc != nil
+
 		// Assign resolution to outer scope variable.
 		resolution = res
 
@@ -1279,6 +1323,9 @@ func (i *InvoiceRegistry) notifyExitHopHtlcLocked(
 
 		i.notifyClients(ctx.hash, invoice, setID)
 	}
+	if updateHistory {
+		i.notifyHistory(ctx.hash, invoice)
+	}
 
 	return resolution, invoiceToExpire, nil
 }
@@ -1342,6 +1389,7 @@ func (i *InvoiceRegistry) SettleHodlInvoice(ctx context.Context,
 		i.notifyHodlSubscribers(resolution)
 	}
 	i.notifyClients(hash, invoice, nil)
+	i.notifyHistory(hash, invoice)
 
 	return nil
 }
@@ -1436,6 +1484,7 @@ func (i *InvoiceRegistry) cancelInvoiceImpl(ctx context.Context,
 		)
 	}
 	i.notifyClients(payHash, invoice, nil)
+	i.notifyHistory(payHash, invoice)
 
 	// Attempt to also delete the invoice if requested through the registry
 	// config.
@@ -1481,6 +1530,34 @@ func (i *InvoiceRegistry) notifyClients(hash lntypes.Hash,
 	}
 }
 
+// notifyHistory sends the history of the changed invoice to all subscribers
+// of invoice history updates.
+func (i *InvoiceRegistry) notifyHistory(hash lntypes.Hash, invoice *Invoice) {
+	// The history server only runs while the registry is active.
+	if !i.started.Load() || i.stopped.Load() {
+		return
+	}
+
+	err := i.historyServer.SendUpdate(&InvoiceHistoryUpdate{
+		Hash:    hash,
+		History: InvoiceHistory(invoice),
+	})
+	if err != nil {
+		log.Errorf("Unable to send history of invoice %v: %v", hash,
+			err)
+	}
+}
+
+// SubscribeInvoiceHistory returns a client that receives an
+// InvoiceHistoryUpdate with the full history of an invoice whenever the
+// invoice changes. This includes changes that don't affect the state of the
+// invoice, such as accepted and canceled htlcs.
+func (i *InvoiceRegistry) SubscribeInvoiceHistory() (*subscribe.Client,
+	error) {
+
+	return i.historyServer.Subscribe()
+}
+
 // invoiceSubscriptionKit defines that are common to both all invoice
 // subscribers and single invoice subscribers.
 type invoiceSubscriptionKit struct {
@@ -76,6 +76,10 @@ func TestInvoiceRegistry(t *testing.T) {
 			name: "MppPaymentWithOverpayment",
 			test: testMppPaymentWithOverpayment,
 		},
+		{
+			name: "MppPaymentOverpaymentPolicy",
+			test: testMppPaymentOverpaymentPolicy,
+		},
 		{
 			name: "InvoiceExpiryWithRegistry",
 			test: testInvoiceExpiryWithRegistry,
@@ -1207,6 +1211,90 @@ func testMppPaymentWithOverpayment(t *testing.T,
 	}
 }
 
+// testMppPaymentOverpaymentPolicy tests that htlc sets that pay more than the
+// overpayment policy of the invoice allows are rejected, and that the excess
+// of accepted overpayments is reported.
+func testMppPaymentOverpaymentPolicy(t *testing.T,
+	makeDB func(t *testing.T) (invpkg.InvoiceDB, *clock.TestClock)) {
+
+	t.Parallel()
+
+	ctxb := context.Background()
+
+	// Reject any overpayment for invoices that don't have their own
+	// policy.
+	cfg := defaultRegistryConfig()
+	cfg.OverpaymentPolicy = invpkg.OverpaymentPolicy{
+		Mode: invpkg.OverpaymentModeReject,
+	}
+
+	// sendHtlc sends a single htlc that pays the given set total.
+	sendHtlc := func(ctx *testContext, total lnwire.MilliSatoshi,
+		htlcID uint64) invpkg.HtlcResolution {
+
+		mppPayload := &mockPayload{
+			mpp: record.NewMPP(total, [32]byte{}),
+		}
+		resolution, err := ctx.registry.NotifyExitHopHtlc(
+			testInvoicePaymentHash, total, testHtlcExpiry,
+			testCurrentHeight, getCircuitKey(htlcID),
+			make(chan interface{}, 1), nil, mppPayload,
+		)
+		require.NoError(t, err)
+
+		return resolution
+	}
+
+	// An invoice without its own policy rejects any overpayment.
+	ctx := newTestContext(t, &cfg, makeDB)
+	testInvoice := newInvoice(t, false)
+	_, err := ctx.registry.AddInvoice(
+		ctxb, testInvoice, testInvoicePaymentHash,
+	)
+	require.NoError(t, err)
+
+	resolution := sendHtlc(ctx, testInvoiceAmount+1, 10)
+	failResolution, ok := resolution.(*invpkg.HtlcFailResolution)
+	require.Truef(t, ok, "expected fail resolution, got: %T", resolution)
+	require.Equal(
+		t, invpkg.ResultHtlcSetOverpayment, failResolution.Outcome,
+	)
+
+	// An invoice that accepts overpayments of up to 10% rejects larger
+	// ones, even though the node wide policy rejects all of them.
+	ctx = newTestContext(t, &cfg, makeDB)
+	testInvoice = newInvoice(t, false)
+	testInvoice.OverpaymentPolicy = invpkg.OverpaymentPolicy{
+		Mode:       invpkg.OverpaymentModeLimit,
+		MaxPercent: 10,
+	}
+	_, err = ctx.registry.AddInvoice(
+		ctxb, testInvoice, testInvoicePaymentHash,
+	)
+	require.NoError(t, err)
+
+	resolution = sendHtlc(ctx, testInvoiceAmount*12/10, 11)
+	failResolution, ok = resolution.(*invpkg.HtlcFailResolution)
+	require.Truef(t, ok, "expected fail resolution, got: %T", resolution)
+	require.Equal(
+		t, invpkg.ResultHtlcSetOverpayment, failResolution.Outcome,
+	)
+
+	// An overpayment within the limit settles the invoice, and the
+	// excess is reported.
+	resolution = sendHtlc(ctx, testInvoiceAmount*105/100, 12)
+	settleResolution, ok := resolution.(*invpkg.HtlcSettleResolution)
+	require.Truef(t, ok, "expected settle resolution, got: %T",
+		resolution)
+	require.Equal(t, invpkg.ResultSettled, settleResolution.Outcome)
+
+	inv, err := ctx.registry.LookupInvoice(ctxb, testInvoicePaymentHash)
+	require.NoError(t, err)
+	require.Equal(t, invpkg.ContractSettled, inv.State)
+	require.Equal(t, testInvoice.OverpaymentPolicy, inv.OverpaymentPolicy)
+	require.Equal(t, testInvoiceAmount*5/100, inv.AmtOverpaid())
+}
+
 // testInvoiceExpiryWithRegistry tests that invoices are canceled after
 // expiration.
 func testInvoiceExpiryWithRegistry(t *testing.T,
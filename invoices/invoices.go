@@ -419,6 +419,42 @@ type Invoice struct {
 	// HodlInvoice indicates whether the invoice should be held in the
 	// Accepted state or be settled right away.
 	HodlInvoice bool
+
+	// OverpaymentPolicy describes which payments that pay more than the
+	// invoice amount are accepted. If it's the default policy, the node
+	// wide overpayment policy is used.
+	OverpaymentPolicy OverpaymentPolicy
+}
+
+// AmtOverpaid returns the amount that was paid to the invoice in excess of its
+// amount. For AMP invoices, it's the sum of the excess paid by each settled
+// sub-invoice. Zero-valued invoices can't be overpaid.
+func (i *Invoice) AmtOverpaid() lnwire.MilliSatoshi {
+	value := i.Terms.Value
+	if value == 0 {
+		return 0
+	}
+
+	if !i.IsAMP() {
+		if i.AmtPaid <= value {
+			return 0
+		}
+
+		return i.AmtPaid - value
+	}
+
+	var overpaid lnwire.MilliSatoshi
+	for _, ampState := range i.AMPState {
+		if ampState.State != HtlcStateSettled {
+			continue
+		}
+
+		if ampState.AmtPaid > value {
+			overpaid += ampState.AmtPaid - value
+		}
+	}
+
+	return overpaid
 }
 
 // HTLCSet returns the set of HTLCs belonging to setID and in the provided
@@ -796,6 +832,10 @@ func ValidateInvoice(i *Invoice, paymentHash lntypes.Hash) error {
 		return ErrInvoiceHasHtlcs
 	}
 
+	if err := i.OverpaymentPolicy.Validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -837,8 +877,9 @@ func CopyInvoice(src *Invoice) (*Invoice, error) {
 		Htlcs: make(
 			map[CircuitKey]*InvoiceHTLC, len(src.Htlcs),
 		),
-		AMPState:    make(map[SetID]InvoiceStateAMP),
-		HodlInvoice: src.HodlInvoice,
+		AMPState:          make(map[SetID]InvoiceStateAMP),
+		HodlInvoice:       src.HodlInvoice,
+		OverpaymentPolicy: src.OverpaymentPolicy,
 	}
 
 	dest.Terms.Features = src.Terms.Features.Clone()
@@ -0,0 +1,421 @@
+package invoices
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+	"unicode/utf8"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// offerChainsType is the TLV type of the chains that an offer can be
+	// paid on.
+	offerChainsType tlv.Type = 2
+
+	// offerMetadataType is the TLV type of the metadata of an offer.
+	offerMetadataType tlv.Type = 4
+
+	// offerCurrencyType is the TLV type of the currency that the amount
+	// of an offer is denominated in.
+	offerCurrencyType tlv.Type = 6
+
+	// offerAmountType is the TLV type of the amount of an offer.
+	offerAmountType tlv.Type = 8
+
+	// offerDescriptionType is the TLV type of the description of an
+	// offer.
+	offerDescriptionType tlv.Type = 10
+
+	// offerFeaturesType is the TLV type of the features of an offer.
+	offerFeaturesType tlv.Type = 12
+
+	// offerAbsoluteExpiryType is the TLV type of the time after which an
+	// offer expires.
+	offerAbsoluteExpiryType tlv.Type = 14
+
+	// offerPathsType is the TLV type of the blinded paths to the issuer of
+	// an offer.
+	offerPathsType tlv.Type = 16
+
+	// offerIssuerType is the TLV type of the issuer of an offer.
+	offerIssuerType tlv.Type = 18
+
+	// offerQuantityMaxType is the TLV type of the maximum quantity of
+	// items that can be requested from an offer.
+	offerQuantityMaxType tlv.Type = 20
+
+	// offerIssuerIDType is the TLV type of the public key of the issuer of
+	// an offer.
+	offerIssuerIDType tlv.Type = 22
+
+	// offerTypeStart and offerTypeEnd delimit the inclusive range of TLV
+	// types that belong to an offer. Invoice requests and invoices mirror
+	// all records of this range.
+	offerTypeStart = 1
+	offerTypeEnd   = 79
+
+	// offerHRP is the human readable part of encoded offers.
+	offerHRP = "lno"
+)
+
+var (
+	// ErrOfferCurrencyUnsupported is returned when an offer denominates
+	// its amount in a currency other than bitcoin.
+	ErrOfferCurrencyUnsupported = errors.New("offer currency not " +
+		"supported")
+
+	// ErrOfferUnknownFeatures is returned when an offer requires features
+	// that we don't know.
+	ErrOfferUnknownFeatures = errors.New("offer requires unknown features")
+)
+
+// OfferID is the identifier of an offer, which is the merkle root of its
+// fields.
+type OfferID [32]byte
+
+// String returns the hex encoding of the offer id.
+func (o OfferID) String() string {
+	return hex.EncodeToString(o[:])
+}
+
+// Offer is a BOLT 12 offer. It is a static and reusable payment code, from
+// which payers request an invoice to pay through onion messages.
+type Offer struct {
+	// Chains are the genesis hashes of the chains that the offer can be
+	// paid on. If empty, the offer can only be paid on bitcoin.
+	Chains []chainhash.Hash
+
+	// Metadata is data that the issuer chose to include in the offer for
+	// its own use.
+	Metadata []byte
+
+	// Amount is the amount that is requested for a single item. If zero,
+	// the payer chooses the amount.
+	Amount lnwire.MilliSatoshi
+
+	// Description describes the purpose of the payment.
+	Description string
+
+	// AbsoluteExpiry is the time after which the offer can no longer be
+	// paid. If zero, the offer never expires.
+	AbsoluteExpiry time.Time
+
+	// Paths are blinded paths to the issuer, which payers send their
+	// invoice requests along.
+	Paths []*lnwire.BlindedPath
+
+	// Issuer is a human-readable name of the issuer.
+	Issuer string
+
+	// QuantityMax is the maximum number of items that can be requested.
+	// If none, the offer is for a single item. If zero, the number of
+	// items is unlimited.
+	QuantityMax fn.Option[uint64]
+
+	// IssuerID is the public key of the issuer. Payers send their invoice
+	// requests to it if no paths are set.
+	IssuerID *btcec.PublicKey
+}
+
+// truncatedUint64Record returns a record for a truncated uint64 of the given
+// type.
+func truncatedUint64Record(recordType tlv.Type, value *uint64) tlv.Record {
+	return tlv.MakeDynamicRecord(
+		recordType, value, func() uint64 {
+			return tlv.SizeTUint64(*value)
+		}, tlv.ETUint64, tlv.DTUint64,
+	)
+}
+
+// Encode serializes the offer into the given writer as a TLV stream.
+func (o *Offer) Encode(w io.Writer) error {
+	var records []tlv.Record
+
+	if len(o.Chains) > 0 {
+		chains := make([]byte, 0, len(o.Chains)*chainhash.HashSize)
+		for _, chain := range o.Chains {
+			chains = append(chains, chain[:]...)
+		}
+
+		records = append(records, tlv.MakePrimitiveRecord(
+			offerChainsType, &chains,
+		))
+	}
+
+	if o.Metadata != nil {
+		records = append(records, tlv.MakePrimitiveRecord(
+			offerMetadataType, &o.Metadata,
+		))
+	}
+
+	if o.Amount != 0 {
+		amount := uint64(o.Amount)
+		records = append(records, truncatedUint64Record(
+			offerAmountType, &amount,
+		))
+	}
+
+	if o.Description != "" {
+		description := []byte(o.Description)
+		records = append(records, tlv.MakePrimitiveRecord(
+			offerDescriptionType, &description,
+		))
+	}
+
+	if !o.AbsoluteExpiry.IsZero() {
+		expiry := uint64(o.AbsoluteExpiry.Unix())
+		records = append(records, truncatedUint64Record(
+			offerAbsoluteExpiryType, &expiry,
+		))
+	}
+
+	if len(o.Paths) > 0 {
+		var b bytes.Buffer
+		for _, path := range o.Paths {
+			if err := path.Encode(&b); err != nil {
+				return err
+			}
+		}
+
+		paths := b.Bytes()
+		records = append(records, tlv.MakePrimitiveRecord(
+			offerPathsType, &paths,
+		))
+	}
+
+	if o.Issuer != "" {
+		issuer := []byte(o.Issuer)
+		records = append(records, tlv.MakePrimitiveRecord(
+			offerIssuerType, &issuer,
+		))
+	}
+
+	o.QuantityMax.WhenSome(func(quantityMax uint64) {
+		records = append(records, truncatedUint64Record(
+			offerQuantityMaxType, &quantityMax,
+		))
+	})
+
+	if o.IssuerID != nil {
+		records = append(records, tlv.MakePrimitiveRecord(
+			offerIssuerIDType, &o.IssuerID,
+		))
+	}
+
+	stream, err := tlv.NewStream(records...)
+	if err != nil {
+		return err
+	}
+
+	return stream.Encode(w)
+}
+
+// Decode deserializes the offer from the given TLV stream. Offers that
+// denominate their amount in another currency or that require features are
+// rejected.
+func (o *Offer) Decode(r io.Reader) error {
+	var (
+		chains, currency, description []byte
+		features, paths, issuer       []byte
+		amount, expiry, quantityMax   uint64
+	)
+	stream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(offerChainsType, &chains),
+		tlv.MakePrimitiveRecord(offerMetadataType, &o.Metadata),
+		tlv.MakePrimitiveRecord(offerCurrencyType, &currency),
+		truncatedUint64Record(offerAmountType, &amount),
+		tlv.MakePrimitiveRecord(offerDescriptionType, &description),
+		tlv.MakePrimitiveRecord(offerFeaturesType, &features),
+		truncatedUint64Record(offerAbsoluteExpiryType, &expiry),
+		tlv.MakePrimitiveRecord(offerPathsType, &paths),
+		tlv.MakePrimitiveRecord(offerIssuerType, &issuer),
+		truncatedUint64Record(offerQuantityMaxType, &quantityMax),
+		tlv.MakePrimitiveRecord(offerIssuerIDType, &o.IssuerID),
+	)
+	if err != nil {
+		return err
+	}
+
+	parsedTypes, err := stream.DecodeWithParsedTypesP2P(r)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := parsedTypes[offerCurrencyType]; ok {
+		return ErrOfferCurrencyUnsupported
+	}
+
+	// There are no offer features defined yet, so any feature bit that is
+	// set is unknown to us.
+	for _, b := range features {
+		if b != 0 {
+			return ErrOfferUnknownFeatures
+		}
+	}
+
+	if len(chains)%chainhash.HashSize != 0 {
+		return fmt.Errorf("invalid offer chains length %d",
+			len(chains))
+	}
+	o.Chains = nil
+	for i := 0; i < len(chains); i += chainhash.HashSize {
+		var chain chainhash.Hash
+		copy(chain[:], chains[i:i+chainhash.HashSize])
+		o.Chains = append(o.Chains, chain)
+	}
+
+	o.Amount = lnwire.MilliSatoshi(amount)
+	o.Description = string(description)
+	o.Issuer = string(issuer)
+
+	o.AbsoluteExpiry = time.Time{}
+	if _, ok := parsedTypes[offerAbsoluteExpiryType]; ok {
+		o.AbsoluteExpiry = time.Unix(int64(expiry), 0)
+	}
+
+	o.QuantityMax = fn.None[uint64]()
+	if _, ok := parsedTypes[offerQuantityMaxType]; ok {
+		o.QuantityMax = fn.Some(quantityMax)
+	}
+
+	o.Paths = nil
+	if _, ok := parsedTypes[offerPathsType]; ok {
+		pathsReader := bytes.NewReader(paths)
+		for pathsReader.Len() > 0 {
+			path := &lnwire.BlindedPath{}
+			if err := path.Decode(pathsReader); err != nil {
+				return fmt.Errorf("invalid offer path: %w", err)
+			}
+
+			o.Paths = append(o.Paths, path)
+		}
+
+		if len(o.Paths) == 0 {
+			return errors.New("offer paths are empty")
+		}
+	}
+
+	return nil
+}
+
+// Validate checks that the offer is well-formed.
+func (o *Offer) Validate() error {
+	if o.Amount != 0 && o.Description == "" {
+		return errors.New("offer with an amount must have a " +
+			"description")
+	}
+
+	if o.IssuerID == nil && len(o.Paths) == 0 {
+		return errors.New("offer must have an issuer id or paths")
+	}
+
+	if !utf8.ValidString(o.Description) {
+		return errors.New("offer description is not valid utf-8")
+	}
+
+	if !utf8.ValidString(o.Issuer) {
+		return errors.New("offer issuer is not valid utf-8")
+	}
+
+	return nil
+}
+
+// Expired returns true if the offer can no longer be paid at the given time.
+func (o *Offer) Expired(now time.Time) bool {
+	return !o.AbsoluteExpiry.IsZero() && now.After(o.AbsoluteExpiry)
+}
+
+// SupportsChain returns true if the offer can be paid on the chain with the
+// given genesis hash.
+func (o *Offer) SupportsChain(chain chainhash.Hash) bool {
+	if len(o.Chains) == 0 {
+		return chain == *chaincfg.MainNetParams.GenesisHash
+	}
+
+	for _, offerChain := range o.Chains {
+		if offerChain == chain {
+			return true
+		}
+	}
+
+	return false
+}
+
+// records returns the raw TLV records of the offer.
+func (o *Offer) records() ([]tlvRecord, error) {
+	var b bytes.Buffer
+	if err := o.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	return splitTLVStream(b.Bytes())
+}
+
+// ID returns the identifier of the offer.
+func (o *Offer) ID() (OfferID, error) {
+	records, err := o.records()
+	if err != nil {
+		return OfferID{}, err
+	}
+
+	root, err := merkleRoot(records)
+	if err != nil {
+		return OfferID{}, err
+	}
+
+	return OfferID(*root), nil
+}
+
+// EncodeString encodes the offer as a BOLT 12 string, which is what the issuer
+// shares with payers.
+func (o *Offer) EncodeString() (string, error) {
+	var b bytes.Buffer
+	if err := o.Encode(&b); err != nil {
+		return "", err
+	}
+
+	return encodeBolt12(offerHRP, b.Bytes())
+}
+
+// DecodeOffer decodes and validates an offer from its BOLT 12 string.
+func DecodeOffer(encoded string) (*Offer, error) {
+	data, err := decodeBolt12(offerHRP, encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := splitTLVStream(data)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range records {
+		if record.recordType < offerTypeStart ||
+			record.recordType > offerTypeEnd {
+
+			return nil, fmt.Errorf("invalid offer tlv type %d",
+				record.recordType)
+		}
+	}
+
+	offer := &Offer{}
+	if err := offer.Decode(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+
+	if err := offer.Validate(); err != nil {
+		return nil, err
+	}
+
+	return offer, nil
+}
@@ -0,0 +1,497 @@
+package invoices
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tlv"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOfferEncoding tests that offers survive a round trip through their
+// BOLT 12 string encoding.
+func TestOfferEncoding(t *testing.T) {
+	t.Parallel()
+
+	priv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	offer := &Offer{
+		Chains:         []chainhash.Hash{{1}},
+		Metadata:       []byte{1, 2, 3},
+		Amount:         50_000,
+		Description:    "coffee",
+		AbsoluteExpiry: time.Unix(1_700_000_000, 0),
+		Issuer:         "cafe",
+		QuantityMax:    fn.Some(uint64(0)),
+		IssuerID:       priv.PubKey(),
+	}
+	require.NoError(t, offer.Validate())
+
+	encoded, err := offer.EncodeString()
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(encoded, "lno1"))
+
+	decoded, err := DecodeOffer(encoded)
+	require.NoError(t, err)
+	require.Equal(t, offer.Chains, decoded.Chains)
+	require.Equal(t, offer.Metadata, decoded.Metadata)
+	require.Equal(t, offer.Amount, decoded.Amount)
+	require.Equal(t, offer.Description, decoded.Description)
+	require.Equal(t, offer.AbsoluteExpiry, decoded.AbsoluteExpiry)
+	require.Equal(t, offer.Issuer, decoded.Issuer)
+	require.Equal(t, offer.QuantityMax, decoded.QuantityMax)
+	require.True(t, offer.IssuerID.IsEqual(decoded.IssuerID))
+
+	// The id only depends on the fields of the offer.
+	id, err := offer.ID()
+	require.NoError(t, err)
+	decodedID, err := decoded.ID()
+	require.NoError(t, err)
+	require.Equal(t, id, decodedID)
+
+	// Upper case strings and strings that are split with a '+' decode to
+	// the same offer.
+	_, err = DecodeOffer(strings.ToUpper(encoded))
+	require.NoError(t, err)
+
+	split := encoded[:20] + "+\n  " + encoded[20:]
+	decoded, err = DecodeOffer(split)
+	require.NoError(t, err)
+	decodedID, err = decoded.ID()
+	require.NoError(t, err)
+	require.Equal(t, id, decodedID)
+
+	// Malformed strings are rejected.
+	_, err = DecodeOffer(encoded[:20] + "Q" + encoded[21:])
+	require.ErrorIs(t, err, errMixedCase)
+
+	_, err = DecodeOffer(encoded + "+")
+	require.Error(t, err)
+
+	_, err = DecodeOffer(encoded[:20] + " " + encoded[20:])
+	require.Error(t, err)
+
+	_, err = DecodeOffer("lni1" + encoded[4:])
+	require.Error(t, err)
+
+	// An offer without an issuer id or paths can't be paid.
+	offer.IssuerID = nil
+	encoded, err = offer.EncodeString()
+	require.NoError(t, err)
+	_, err = DecodeOffer(encoded)
+	require.Error(t, err)
+}
+
+// TestMerkleRoot tests the BOLT 12 merkle root against the test vector of the
+// spec for a stream with a single record.
+func TestMerkleRoot(t *testing.T) {
+	t.Parallel()
+
+	// The TLV stream of a single record of type 1 with the value 1000.
+	records, err := splitTLVStream([]byte{0x01, 0x02, 0x03, 0xe8})
+	require.NoError(t, err)
+
+	root, err := merkleRoot(records)
+	require.NoError(t, err)
+
+	leaf := chainhash.TaggedHash(leafTag, records[0].raw)
+	nonce := chainhash.TaggedHash(
+		append(append([]byte{}, nonceTag...), records[0].raw...),
+		[]byte{0x01},
+	)
+	require.Equal(t, merkleBranch(leaf, nonce), root)
+
+	// Streams with records out of order are rejected.
+	_, err = splitTLVStream([]byte{0x03, 0x00, 0x01, 0x00})
+	require.Error(t, err)
+}
+
+// mockOfferDB is an in-memory OfferDB.
+type mockOfferDB struct {
+	offers      map[OfferID]*OfferState
+	invoices    map[lntypes.Hash]OfferID
+	settleIndex uint64
+}
+
+func newMockOfferDB() *mockOfferDB {
+	return &mockOfferDB{
+		offers:   make(map[OfferID]*OfferState),
+		invoices: make(map[lntypes.Hash]OfferID),
+	}
+}
+
+func (m *mockOfferDB) AddOffer(_ context.Context, offer *OfferState) error {
+	if _, ok := m.offers[offer.ID]; ok {
+		return ErrDuplicateOffer
+	}
+	m.offers[offer.ID] = offer
+
+	return nil
+}
+
+func (m *mockOfferDB) FetchOffer(_ context.Context,
+	id OfferID) (*OfferState, error) {
+
+	offer, ok := m.offers[id]
+	if !ok {
+		return nil, ErrOfferNotFound
+	}
+
+	return offer, nil
+}
+
+func (m *mockOfferDB) FetchOffers(_ context.Context) ([]*OfferState, error) {
+	var offers []*OfferState
+	for _, offer := range m.offers {
+		offers = append(offers, offer)
+	}
+
+	return offers, nil
+}
+
+func (m *mockOfferDB) AddOfferInvoice(_ context.Context, id OfferID,
+	paymentHash lntypes.Hash) error {
+
+	m.invoices[paymentHash] = id
+
+	return nil
+}
+
+func (m *mockOfferDB) RecordOfferPayment(_ context.Context,
+	paymentHash lntypes.Hash, amt lnwire.MilliSatoshi,
+	settleIndex uint64) error {
+
+	m.settleIndex = settleIndex
+	if id, ok := m.invoices[paymentHash]; ok {
+		m.offers[id].NumPayments++
+		m.offers[id].AmtPaid += amt
+	}
+
+	return nil
+}
+
+func (m *mockOfferDB) OfferSettleIndex(_ context.Context) (uint64, error) {
+	return m.settleIndex, nil
+}
+
+// signInvoiceRequest creates an invoice request for the offer that is signed
+// by the given payer key.
+func signInvoiceRequest(t *testing.T, offer *Offer, payer *btcec.PrivateKey,
+	amount uint64, quantity fn.Option[uint64]) []byte {
+
+	var offerBytes bytes.Buffer
+	require.NoError(t, offer.Encode(&offerBytes))
+
+	metadata := []byte{9, 9, 9}
+	payerID := payer.PubKey()
+	records := []tlv.Record{
+		tlv.MakePrimitiveRecord(invreqMetadataType, &metadata),
+		tlv.MakePrimitiveRecord(invreqPayerIDType, &payerID),
+	}
+	if amount != 0 {
+		records = append(records, truncatedUint64Record(
+			invreqAmountType, &amount,
+		))
+	}
+	quantity.WhenSome(func(q uint64) {
+		records = append(records, truncatedUint64Record(
+			invreqQuantityType, &q,
+		))
+	})
+	tlv.SortRecords(records)
+
+	stream, err := tlv.NewStream(records...)
+	require.NoError(t, err)
+
+	var requestBytes bytes.Buffer
+	require.NoError(t, stream.Encode(&requestBytes))
+
+	offerRecords, err := splitTLVStream(offerBytes.Bytes())
+	require.NoError(t, err)
+	requestRecords, err := splitTLVStream(requestBytes.Bytes())
+	require.NoError(t, err)
+
+	unsigned := joinTLVRecords(append(offerRecords, requestRecords...))
+	allRecords, err := splitTLVStream(unsigned)
+	require.NoError(t, err)
+
+	root, err := merkleRoot(allRecords)
+	require.NoError(t, err)
+	digest := chainhash.TaggedHash(
+		signatureTag("invoice_request", "signature"), root[:],
+	)
+	sig, err := schnorr.Sign(payer, digest[:])
+	require.NoError(t, err)
+
+	var sigBytes [64]byte
+	copy(sigBytes[:], sig.Serialize())
+	sigStream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(signatureType, &sigBytes),
+	)
+	require.NoError(t, err)
+
+	signed := bytes.NewBuffer(unsigned)
+	require.NoError(t, sigStream.Encode(signed))
+
+	return signed.Bytes()
+}
+
+// TestOffersManagerInvoiceRequest tests that the offers manager answers
+// invoice requests for its offers with signed invoices.
+func TestOffersManagerInvoiceRequest(t *testing.T) {
+	t.Parallel()
+
+	nodeKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	payerKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	pathKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	path := &lnwire.BlindedPath{
+		IntroductionNode: nodeKey.PubKey(),
+		BlindingPoint:    pathKey.PubKey(),
+		Hops: []lnwire.BlindedHop{{
+			BlindedNodeID: pathKey.PubKey(),
+			EncryptedData: []byte{1, 2, 3},
+		}},
+	}
+
+	var (
+		db         = newMockOfferDB()
+		now        = time.Unix(1_000, 0)
+		hash       = lntypes.Hash{7}
+		invoiceAmt lnwire.MilliSatoshi
+		replies    []*lnwire.OnionMessagePayload
+	)
+	mgr := NewOffersManager(&OffersManagerConfig{
+		NodeID:    nodeKey.PubKey(),
+		ChainHash: *chaincfg.MainNetParams.GenesisHash,
+		DB:        db,
+		Clock:     clock.NewTestClock(now),
+		SignInvoice: func(msg, tag []byte) (*schnorr.Signature,
+			error) {
+
+			digest := chainhash.TaggedHash(tag, msg)
+
+			return schnorr.Sign(nodeKey, digest[:])
+		},
+		CreateInvoice: func(_ context.Context, amt lnwire.MilliSatoshi,
+			_ string, _ time.Duration) (lntypes.Hash,
+			[]*OfferPaymentPath, error) {
+
+			invoiceAmt = amt
+
+			return hash, []*OfferPaymentPath{{
+				Path:            path,
+				CltvExpiryDelta: 80,
+				HTLCMaxMsat:     1_000_000,
+			}}, nil
+		},
+		SendReply: func(_ *lnwire.BlindedPath,
+			payload *lnwire.OnionMessagePayload) error {
+
+			replies = append(replies, payload)
+
+			return nil
+		},
+	})
+
+	ctx := context.Background()
+	state, err := mgr.AddOffer(ctx, &AddOfferRequest{
+		Description: "coffee",
+		Amount:      1_000,
+		QuantityMax: fn.Some(uint64(5)),
+	})
+	require.NoError(t, err)
+	require.True(t, state.Offer.IssuerID.IsEqual(nodeKey.PubKey()))
+	require.Empty(t, state.Offer.Chains)
+
+	// Request an invoice for three items.
+	request := signInvoiceRequest(
+		t, state.Offer, payerKey, 0, fn.Some(uint64(3)),
+	)
+	err = mgr.HandleInvoiceRequest(ctx, &lnwire.OnionMessagePayload{
+		ReplyPath:      path,
+		InvoiceRequest: request,
+	})
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	require.Nil(t, replies[0].InvoiceError)
+	require.NotNil(t, replies[0].Invoice)
+	require.EqualValues(t, 3_000, invoiceAmt)
+	require.Equal(t, state.ID, db.invoices[hash])
+
+	// The invoice mirrors the request, and is signed by our node.
+	records, err := splitTLVStream(replies[0].Invoice)
+	require.NoError(t, err)
+
+	var (
+		paymentHash [32]byte
+		amount      uint64
+		sigBytes    [64]byte
+	)
+	stream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(invoicePaymentHashType, &paymentHash),
+		truncatedUint64Record(invoiceAmountType, &amount),
+		tlv.MakePrimitiveRecord(signatureType, &sigBytes),
+	)
+	require.NoError(t, err)
+	invoiceRecords := append(
+		filterTLVRecords(records, uint64(invoicePaymentHashType),
+			uint64(invoiceAmountType)),
+		filterTLVRecords(records, uint64(signatureType),
+			uint64(signatureType))...,
+	)
+	_, err = stream.DecodeWithParsedTypes(
+		bytes.NewReader(joinTLVRecords(invoiceRecords)),
+	)
+	require.NoError(t, err)
+	require.EqualValues(t, hash, paymentHash)
+	require.EqualValues(t, 3_000, amount)
+
+	root, err := merkleRoot(records)
+	require.NoError(t, err)
+	digest := chainhash.TaggedHash(
+		signatureTag("invoice", "signature"), root[:],
+	)
+	sig, err := schnorr.ParseSignature(sigBytes[:])
+	require.NoError(t, err)
+	require.True(t, sig.Verify(digest[:], nodeKey.PubKey()))
+
+	// Requests for more items than the offer allows are answered with an
+	// invoice error.
+	request = signInvoiceRequest(
+		t, state.Offer, payerKey, 0, fn.Some(uint64(6)),
+	)
+	err = mgr.HandleInvoiceRequest(ctx, &lnwire.OnionMessagePayload{
+		ReplyPath:      path,
+		InvoiceRequest: request,
+	})
+	require.NoError(t, err)
+	require.Len(t, replies, 2)
+	require.Nil(t, replies[1].Invoice)
+	require.NotNil(t, replies[1].InvoiceError)
+
+	// So are requests that were tampered with.
+	request = signInvoiceRequest(
+		t, state.Offer, payerKey, 0, fn.Some(uint64(1)),
+	)
+	request[len(request)-1] ^= 1
+	err = mgr.HandleInvoiceRequest(ctx, &lnwire.OnionMessagePayload{
+		ReplyPath:      path,
+		InvoiceRequest: request,
+	})
+	require.NoError(t, err)
+	require.Len(t, replies, 3)
+	require.NotNil(t, replies[2].InvoiceError)
+
+	// And requests for offers that we don't know.
+	unknown := *state.Offer
+	unknown.Metadata = []byte{1}
+	request = signInvoiceRequest(
+		t, &unknown, payerKey, 0, fn.Some(uint64(1)),
+	)
+	err = mgr.HandleInvoiceRequest(ctx, &lnwire.OnionMessagePayload{
+		ReplyPath:      path,
+		InvoiceRequest: request,
+	})
+	require.NoError(t, err)
+	require.Len(t, replies, 4)
+	require.NotNil(t, replies[3].InvoiceError)
+}
+
+// TestOfferInvoiceAmount tests the amount of the invoices that answer invoice
+// requests.
+func TestOfferInvoiceAmount(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		offerAmt    lnwire.MilliSatoshi
+		quantityMax fn.Option[uint64]
+		reqAmt      lnwire.MilliSatoshi
+		quantity    fn.Option[uint64]
+		expectedAmt lnwire.MilliSatoshi
+		expectErr   bool
+	}{{
+		name:        "offer amount",
+		offerAmt:    1_000,
+		expectedAmt: 1_000,
+	}, {
+		name:        "payer pays more",
+		offerAmt:    1_000,
+		reqAmt:      1_500,
+		expectedAmt: 1_500,
+	}, {
+		name:      "payer pays less",
+		offerAmt:  1_000,
+		reqAmt:    500,
+		expectErr: true,
+	}, {
+		name:        "payer chosen amount",
+		reqAmt:      500,
+		expectedAmt: 500,
+	}, {
+		name:      "missing amount",
+		expectErr: true,
+	}, {
+		name:        "unlimited quantity",
+		offerAmt:    1_000,
+		quantityMax: fn.Some(uint64(0)),
+		quantity:    fn.Some(uint64(100)),
+		expectedAmt: 100_000,
+	}, {
+		name:        "missing quantity",
+		offerAmt:    1_000,
+		quantityMax: fn.Some(uint64(2)),
+		expectErr:   true,
+	}, {
+		name:      "unexpected quantity",
+		offerAmt:  1_000,
+		quantity:  fn.Some(uint64(1)),
+		expectErr: true,
+	}, {
+		name:        "zero quantity",
+		offerAmt:    1_000,
+		quantityMax: fn.Some(uint64(2)),
+		quantity:    fn.Some(uint64(0)),
+		expectErr:   true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			offer := &Offer{
+				Amount:      test.offerAmt,
+				QuantityMax: test.quantityMax,
+			}
+			req := &InvoiceRequest{
+				Amount:   test.reqAmt,
+				Quantity: test.quantity,
+			}
+
+			amt, err := offerInvoiceAmount(offer, req)
+			if test.expectErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectedAmt, amt)
+		})
+	}
+}
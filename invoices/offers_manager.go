@@ -0,0 +1,454 @@
+package invoices
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+const (
+	// DefaultOfferInvoiceExpiry is the default time after which the
+	// invoices that are created for offers expire.
+	DefaultOfferInvoiceExpiry = 2 * time.Hour
+
+	// offerMetadataSize is the size of the random metadata of the offers
+	// that we create, which makes their ids unique.
+	offerMetadataSize = 16
+)
+
+var (
+	// ErrOfferExpired is returned when an invoice is requested for an
+	// offer that expired.
+	ErrOfferExpired = errors.New("offer expired")
+
+	// ErrOfferInvoicesUnsupported is returned when invoice requests can't
+	// be answered because the manager wasn't configured to create
+	// invoices for offers.
+	ErrOfferInvoicesUnsupported = errors.New("creating invoices for " +
+		"offers is not supported")
+)
+
+// OfferState is an offer that we issued, together with the state of its
+// payments.
+type OfferState struct {
+	// ID is the id of the offer.
+	ID OfferID
+
+	// Offer is the offer itself.
+	Offer *Offer
+
+	// CreationDate is the time at which the offer was created.
+	CreationDate time.Time
+
+	// NumPayments is the number of invoices of the offer that were paid.
+	NumPayments uint64
+
+	// AmtPaid is the total amount that was paid to the offer.
+	AmtPaid lnwire.MilliSatoshi
+}
+
+// AddOfferRequest holds the parameters of an offer that is to be created.
+type AddOfferRequest struct {
+	// Description describes the purpose of the payment.
+	Description string
+
+	// Amount is the amount that is requested for a single item. If zero,
+	// the payer chooses the amount.
+	Amount lnwire.MilliSatoshi
+
+	// Issuer is an optional human-readable name of the issuer.
+	Issuer string
+
+	// QuantityMax is the maximum number of items that can be requested.
+	// If none, the offer is for a single item. If zero, the number of
+	// items is unlimited.
+	QuantityMax fn.Option[uint64]
+
+	// AbsoluteExpiry is the time after which the offer can no longer be
+	// paid. If zero, the offer never expires.
+	AbsoluteExpiry time.Time
+}
+
+// OffersManagerConfig contains the dependencies of the OffersManager.
+type OffersManagerConfig struct {
+	// NodeID is the public key of our node, which issues the offers.
+	NodeID *btcec.PublicKey
+
+	// ChainHash is the genesis hash of the chain that we are on.
+	ChainHash chainhash.Hash
+
+	// DB is the database that the offers and their payments are stored
+	// in.
+	DB OfferDB
+
+	// Clock is used to determine the creation and expiry times of offers
+	// and their invoices.
+	Clock clock.Clock
+
+	// SubscribeSettled subscribes to the invoices that were settled after
+	// the given settle index.
+	SubscribeSettled func(settleIndex uint64) (*InvoiceSubscription,
+		error)
+
+	// SignInvoice signs the tagged hash of the message with the given tag
+	// with the key of our node.
+	SignInvoice func(msg, tag []byte) (*schnorr.Signature, error)
+
+	// CreateInvoice adds an invoice for the given amount to the invoice
+	// registry, and returns its payment hash and the blinded paths that
+	// it can be paid through. If nil, invoice requests are rejected.
+	CreateInvoice func(ctx context.Context, amt lnwire.MilliSatoshi,
+		description string, expiry time.Duration) (lntypes.Hash,
+		[]*OfferPaymentPath, error)
+
+	// SendReply sends the onion message payload along the given blinded
+	// reply path.
+	SendReply func(replyPath *lnwire.BlindedPath,
+		payload *lnwire.OnionMessagePayload) error
+
+	// InvoiceExpiry is the time after which the invoices that are created
+	// for offers expire.
+	InvoiceExpiry time.Duration
+}
+
+// OffersManager manages the BOLT 12 offers that we issue. It answers the
+// invoice requests of payers with invoices of our node, and keeps track of
+// the payments that each offer received. Unlike BOLT 11 invoices, an offer is
+// static and can be paid any number of times, so no external service is
+// needed to hand out fresh invoices.
+type OffersManager struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *OffersManagerConfig
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// NewOffersManager creates a new offers manager from the given config.
+func NewOffersManager(cfg *OffersManagerConfig) *OffersManager {
+	return &OffersManager{
+		cfg:  cfg,
+		quit: make(chan struct{}),
+	}
+}
+
+// Start subscribes to invoice settlements and launches the goroutine that
+// records the payments of offers.
+func (m *OffersManager) Start() error {
+	var startErr error
+	m.started.Do(func() {
+		log.Info("Offers manager starting")
+
+		settleIndex, err := m.cfg.DB.OfferSettleIndex(
+			context.Background(),
+		)
+		if err != nil {
+			startErr = fmt.Errorf("unable to fetch offer settle "+
+				"index: %w", err)
+
+			return
+		}
+
+		client, err := m.cfg.SubscribeSettled(settleIndex)
+		if err != nil {
+			startErr = fmt.Errorf("unable to subscribe to invoice "+
+				"settlements: %w", err)
+
+			return
+		}
+
+		m.wg.Add(1)
+		go m.trackPayments(client, settleIndex)
+	})
+
+	return startErr
+}
+
+// Stop signals the offers manager to shut down and waits for it to exit.
+func (m *OffersManager) Stop() error {
+	m.stopped.Do(func() {
+		log.Info("Offers manager shutting down...")
+		defer log.Debug("Offers manager shutdown complete")
+
+		close(m.quit)
+		m.wg.Wait()
+	})
+
+	return nil
+}
+
+// trackPayments records the settled invoices that belong to offers.
+//
+// NOTE: This MUST be run as a goroutine.
+func (m *OffersManager) trackPayments(client *InvoiceSubscription,
+	settleIndex uint64) {
+
+	defer m.wg.Done()
+	defer client.Cancel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for {
+		select {
+		case invoice, ok := <-client.SettledInvoices:
+			if !ok {
+				return
+			}
+
+			// Settlements that were already recorded may be
+			// replayed as part of the backlog.
+			if invoice.SettleIndex <= settleIndex {
+				continue
+			}
+
+			// Only invoices with a known preimage can be created
+			// for offers.
+			if invoice.Terms.PaymentPreimage == nil {
+				continue
+			}
+
+			hash := invoice.Terms.PaymentPreimage.Hash()
+			err := m.cfg.DB.RecordOfferPayment(
+				ctx, hash, invoice.AmtPaid, invoice.SettleIndex,
+			)
+			if err != nil {
+				log.Errorf("Unable to record payment of "+
+					"invoice %v: %v", hash, err)
+
+				continue
+			}
+
+			settleIndex = invoice.SettleIndex
+
+		case <-m.quit:
+			return
+		}
+	}
+}
+
+// AddOffer creates a new offer that is issued by our node and stores it.
+func (m *OffersManager) AddOffer(ctx context.Context,
+	req *AddOfferRequest) (*OfferState, error) {
+
+	metadata := make([]byte, offerMetadataSize)
+	if _, err := rand.Read(metadata); err != nil {
+		return nil, err
+	}
+
+	offer := &Offer{
+		Metadata:       metadata,
+		Amount:         req.Amount,
+		Description:    req.Description,
+		AbsoluteExpiry: req.AbsoluteExpiry,
+		Issuer:         req.Issuer,
+		QuantityMax:    req.QuantityMax,
+		IssuerID:       m.cfg.NodeID,
+	}
+
+	// Offers without chains can only be paid on bitcoin, so we'll list
+	// our chain explicitly if we're on another one.
+	if m.cfg.ChainHash != *chaincfg.MainNetParams.GenesisHash {
+		offer.Chains = []chainhash.Hash{m.cfg.ChainHash}
+	}
+
+	if err := offer.Validate(); err != nil {
+		return nil, err
+	}
+
+	id, err := offer.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	state := &OfferState{
+		ID:           id,
+		Offer:        offer,
+		CreationDate: m.cfg.Clock.Now(),
+	}
+	if err := m.cfg.DB.AddOffer(ctx, state); err != nil {
+		return nil, err
+	}
+
+	log.Infof("Added offer %v", id)
+
+	return state, nil
+}
+
+// ListOffers returns all offers that we issued.
+func (m *OffersManager) ListOffers(ctx context.Context) ([]*OfferState,
+	error) {
+
+	return m.cfg.DB.FetchOffers(ctx)
+}
+
+// HandleInvoiceRequest answers the invoice request of the given onion message
+// payload. The invoice, or an invoice error if the request can't be answered,
+// is sent back along the reply path of the message.
+func (m *OffersManager) HandleInvoiceRequest(ctx context.Context,
+	payload *lnwire.OnionMessagePayload) error {
+
+	if payload.InvoiceRequest == nil {
+		return errors.New("onion message has no invoice request")
+	}
+
+	if payload.ReplyPath == nil {
+		return errors.New("invoice request has no reply path")
+	}
+
+	if m.cfg.SendReply == nil {
+		return errors.New("unable to send onion message replies")
+	}
+
+	reply := &lnwire.OnionMessagePayload{}
+
+	invoice, err := m.createInvoice(ctx, payload.InvoiceRequest)
+	if err != nil {
+		log.Debugf("Rejecting invoice request: %v", err)
+
+		reply.InvoiceError, err = encodeInvoiceError(err.Error())
+		if err != nil {
+			return err
+		}
+	} else {
+		reply.Invoice = invoice
+	}
+
+	return m.cfg.SendReply(payload.ReplyPath, reply)
+}
+
+// createInvoice creates the invoice for the serialized invoice request.
+func (m *OffersManager) createInvoice(ctx context.Context,
+	data []byte) ([]byte, error) {
+
+	if m.cfg.CreateInvoice == nil || m.cfg.SignInvoice == nil {
+		return nil, ErrOfferInvoicesUnsupported
+	}
+
+	req, err := DecodeInvoiceRequest(data)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := req.Offer.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := m.cfg.DB.FetchOffer(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	offer := state.Offer
+
+	now := m.cfg.Clock.Now()
+	if offer.Expired(now) {
+		return nil, ErrOfferExpired
+	}
+
+	chain := req.Chain.UnwrapOr(*chaincfg.MainNetParams.GenesisHash)
+	if chain != m.cfg.ChainHash || !offer.SupportsChain(chain) {
+		return nil, fmt.Errorf("unsupported chain %v", chain)
+	}
+
+	amt, err := offerInvoiceAmount(offer, req)
+	if err != nil {
+		return nil, err
+	}
+
+	expiry := m.cfg.InvoiceExpiry
+	if expiry == 0 {
+		expiry = DefaultOfferInvoiceExpiry
+	}
+
+	hash, paths, err := m.cfg.CreateInvoice(
+		ctx, amt, offer.Description, expiry,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.cfg.DB.AddOfferInvoice(ctx, id, hash); err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Created invoice %v for offer %v", hash, id)
+
+	return encodeOfferInvoice(req, &offerInvoice{
+		paths:       paths,
+		createdAt:   now,
+		expiry:      expiry,
+		paymentHash: hash,
+		amount:      amt,
+		nodeID:      m.cfg.NodeID,
+	}, m.cfg.SignInvoice)
+}
+
+// offerInvoiceAmount returns the amount of the invoice that answers the
+// invoice request, after checking that the requested quantity and amount are
+// allowed by the offer.
+func offerInvoiceAmount(offer *Offer,
+	req *InvoiceRequest) (lnwire.MilliSatoshi, error) {
+
+	quantity := uint64(1)
+	switch {
+	case offer.QuantityMax.IsNone() && req.Quantity.IsSome():
+		return 0, errors.New("offer doesn't allow a quantity")
+
+	case offer.QuantityMax.IsSome():
+		var err error
+		quantity, err = req.Quantity.UnwrapOrErr(
+			errors.New("quantity is required"),
+		)
+		if err != nil {
+			return 0, err
+		}
+
+		quantityMax := offer.QuantityMax.UnwrapOr(0)
+		if quantity == 0 ||
+			(quantityMax != 0 && quantity > quantityMax) {
+
+			return 0, fmt.Errorf("invalid quantity %d", quantity)
+		}
+	}
+
+	// If the offer doesn't specify an amount, the payer has to.
+	if offer.Amount == 0 {
+		if req.Amount == 0 {
+			return 0, errors.New("amount is required")
+		}
+
+		return req.Amount, nil
+	}
+
+	if quantity > math.MaxUint64/uint64(offer.Amount) {
+		return 0, fmt.Errorf("quantity %d overflows amount", quantity)
+	}
+	expected := offer.Amount * lnwire.MilliSatoshi(quantity)
+
+	// Payers may pay more than requested, but not less.
+	if req.Amount == 0 {
+		return expected, nil
+	}
+	if req.Amount < expected {
+		return 0, fmt.Errorf("amount %v is less than %v", req.Amount,
+			expected)
+	}
+
+	return req.Amount, nil
+}
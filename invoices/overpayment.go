@@ -0,0 +1,151 @@
+package invoices
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// OverpaymentMode describes how payments that pay more than the invoice
+// amount are handled.
+type OverpaymentMode uint8
+
+const (
+	// OverpaymentModeDefault is used for invoices that follow the node
+	// wide overpayment policy. As node wide mode, it accepts overpayments
+	// of any amount.
+	OverpaymentModeDefault OverpaymentMode = iota
+
+	// OverpaymentModeAccept accepts overpayments of any amount. The
+	// excess is recorded as part of the amount paid, and reported
+	// separately by Invoice.AmtOverpaid.
+	OverpaymentModeAccept
+
+	// OverpaymentModeLimit accepts overpayments up to a percentage of the
+	// invoice amount, and rejects payments that exceed it.
+	OverpaymentModeLimit
+
+	// OverpaymentModeReject rejects any payment that pays more than the
+	// invoice amount.
+	OverpaymentModeReject
+)
+
+// String returns a human-readable name of the overpayment mode, which is also
+// used to configure the node wide overpayment policy.
+func (m OverpaymentMode) String() string {
+	switch m {
+	case OverpaymentModeDefault:
+		return "default"
+
+	case OverpaymentModeAccept:
+		return "accept"
+
+	case OverpaymentModeLimit:
+		return "limit"
+
+	case OverpaymentModeReject:
+		return "reject"
+
+	default:
+		return fmt.Sprintf("unknown<%d>", uint8(m))
+	}
+}
+
+// ParseOverpaymentMode parses the name of an overpayment mode.
+func ParseOverpaymentMode(name string) (OverpaymentMode, error) {
+	for _, mode := range []OverpaymentMode{
+		OverpaymentModeDefault, OverpaymentModeAccept,
+		OverpaymentModeLimit, OverpaymentModeReject,
+	} {
+		if mode.String() == name {
+			return mode, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown overpayment mode: %v", name)
+}
+
+// OverpaymentPolicy describes which payments that pay more than the invoice
+// amount are accepted. Zero-valued invoices can't be overpaid, so the policy
+// only applies to invoices with an amount.
+type OverpaymentPolicy struct {
+	// Mode is the way overpayments are handled.
+	Mode OverpaymentMode
+
+	// MaxPercent is the maximum overpayment that is accepted, as a
+	// percentage of the invoice amount. It's only used with
+	// OverpaymentModeLimit.
+	MaxPercent uint32
+}
+
+// IsDefault returns true if the policy defers to the node wide policy.
+func (p OverpaymentPolicy) IsDefault() bool {
+	return p.Mode == OverpaymentModeDefault
+}
+
+// Validate checks that the overpayment policy is sane.
+func (p OverpaymentPolicy) Validate() error {
+	switch p.Mode {
+	case OverpaymentModeDefault, OverpaymentModeAccept,
+		OverpaymentModeReject:
+
+		if p.MaxPercent != 0 {
+			return fmt.Errorf("max overpayment percent can only "+
+				"be set with the %v overpayment mode",
+				OverpaymentModeLimit)
+		}
+
+		return nil
+
+	case OverpaymentModeLimit:
+		return nil
+
+	default:
+		return fmt.Errorf("unknown overpayment mode: %v", p.Mode)
+	}
+}
+
+// MaxAmount returns the maximum amount that is accepted for an invoice with
+// the given amount. Zero is returned if there is no maximum.
+func (p OverpaymentPolicy) MaxAmount(
+	value lnwire.MilliSatoshi) lnwire.MilliSatoshi {
+
+	if value == 0 {
+		return 0
+	}
+
+	switch p.Mode {
+	case OverpaymentModeLimit:
+		// Split the invoice amount to not overflow when applying the
+		// percentage to large amounts.
+		maxPercent := lnwire.MilliSatoshi(p.MaxPercent)
+		maxExcess := value/100*maxPercent + value%100*maxPercent/100
+
+		return value + maxExcess
+
+	case OverpaymentModeReject:
+		return value
+
+	default:
+		return 0
+	}
+}
+
+// allows returns true if the policy accepts the given amount to be paid to an
+// invoice with the given amount.
+func (p OverpaymentPolicy) allows(value, amtPaid lnwire.MilliSatoshi) bool {
+	maxAmt := p.MaxAmount(value)
+
+	return maxAmt == 0 || amtPaid <= maxAmt
+}
+
+// overpaymentPolicy returns the overpayment policy that applies to the given
+// invoice. The policy of the invoice takes precedence over the node wide
+// policy, unless it is the default one.
+func (i *invoiceUpdateCtx) overpaymentPolicy(inv *Invoice) OverpaymentPolicy {
+	if inv.OverpaymentPolicy.IsDefault() {
+		return i.defaultOverpaymentPolicy
+	}
+
+	return inv.OverpaymentPolicy
+}
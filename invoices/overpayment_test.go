@@ -0,0 +1,131 @@
+package invoices
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOverpaymentPolicyAllows tests which amounts are accepted by the
+// different overpayment policies.
+func TestOverpaymentPolicyAllows(t *testing.T) {
+	t.Parallel()
+
+	const value = lnwire.MilliSatoshi(100_000)
+
+	limit := OverpaymentPolicy{
+		Mode:       OverpaymentModeLimit,
+		MaxPercent: 10,
+	}
+	reject := OverpaymentPolicy{
+		Mode: OverpaymentModeReject,
+	}
+
+	testCases := []struct {
+		name    string
+		policy  OverpaymentPolicy
+		value   lnwire.MilliSatoshi
+		amtPaid lnwire.MilliSatoshi
+		allowed bool
+	}{
+		{
+			name:    "default accepts any overpayment",
+			value:   value,
+			amtPaid: value * 10,
+			allowed: true,
+		},
+		{
+			name: "accept accepts any overpayment",
+			policy: OverpaymentPolicy{
+				Mode: OverpaymentModeAccept,
+			},
+			value:   value,
+			amtPaid: value * 10,
+			allowed: true,
+		},
+		{
+			name:    "limit accepts overpayment within limit",
+			policy:  limit,
+			value:   value,
+			amtPaid: value + value/10,
+			allowed: true,
+		},
+		{
+			name:    "limit rejects overpayment above limit",
+			policy:  limit,
+			value:   value,
+			amtPaid: value + value/10 + 1,
+		},
+		{
+			name:    "reject accepts exact payment",
+			policy:  reject,
+			value:   value,
+			amtPaid: value,
+			allowed: true,
+		},
+		{
+			name:    "reject rejects overpayment",
+			policy:  reject,
+			value:   value,
+			amtPaid: value + 1,
+		},
+		{
+			name:    "zero-valued invoice can't be overpaid",
+			policy:  reject,
+			amtPaid: value,
+			allowed: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(
+				t, tc.allowed, tc.policy.allows(
+					tc.value, tc.amtPaid,
+				),
+			)
+		})
+	}
+
+	// The limit is applied without overflowing for large amounts.
+	const largeValue = lnwire.MilliSatoshi(1 << 62)
+	largeLimit := OverpaymentPolicy{
+		Mode:       OverpaymentModeLimit,
+		MaxPercent: 100,
+	}
+	require.Equal(t, 2*largeValue, largeLimit.MaxAmount(largeValue))
+}
+
+// TestOverpaymentPolicyValidate tests the validation of overpayment policies
+// and the parsing of overpayment modes.
+func TestOverpaymentPolicyValidate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, OverpaymentPolicy{}.Validate())
+	require.NoError(t, OverpaymentPolicy{
+		Mode:       OverpaymentModeLimit,
+		MaxPercent: 5,
+	}.Validate())
+
+	// A max percent can only be used with the limit mode.
+	require.Error(t, OverpaymentPolicy{
+		Mode:       OverpaymentModeReject,
+		MaxPercent: 5,
+	}.Validate())
+	require.Error(t, OverpaymentPolicy{
+		Mode: OverpaymentModeReject + 1,
+	}.Validate())
+
+	for _, mode := range []OverpaymentMode{
+		OverpaymentModeDefault, OverpaymentModeAccept,
+		OverpaymentModeLimit, OverpaymentModeReject,
+	} {
+		parsed, err := ParseOverpaymentMode(mode.String())
+		require.NoError(t, err)
+		require.Equal(t, mode, parsed)
+	}
+
+	_, err := ParseOverpaymentMode("refund")
+	require.Error(t, err)
+}
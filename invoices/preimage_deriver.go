@@ -0,0 +1,63 @@
+package invoices
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lntypes"
+)
+
+// preimageRootKeyLoc is the KeyLocator of the base key that the root key for
+// deterministic invoice preimages is derived from. It shares the family of the
+// base encryption key, at the next index.
+var preimageRootKeyLoc = keychain.KeyLocator{
+	Family: keychain.KeyFamilyBaseEncryption,
+	Index:  1,
+}
+
+// preimageTag is the domain separation tag of deterministic invoice
+// preimages.
+var preimageTag = []byte("lnd-invoice-preimage")
+
+// PreimageDeriver derives invoice preimages deterministically from the wallet
+// seed and the payment address of the invoice. This allows a node that is
+// restored from its seed to settle invoices it issued before, as long as their
+// payment addresses are known, e.g. from the payment requests.
+type PreimageDeriver struct {
+	rootKey [32]byte
+}
+
+// NewPreimageDeriver creates a new PreimageDeriver whose root key is derived
+// from the passed key ring.
+//
+// Like the static channel backup encryption key, the root key is the sha2 of
+// a base key that we get from the keyring. This way we don't require remote
+// signers to know how preimages are derived.
+func NewPreimageDeriver(keyRing keychain.KeyRing) (*PreimageDeriver, error) {
+	baseKey, err := keyRing.DeriveKey(preimageRootKeyLoc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PreimageDeriver{
+		rootKey: sha256.Sum256(baseKey.PubKey.SerializeCompressed()),
+	}, nil
+}
+
+// DerivePreimage returns the preimage of the invoice with the given payment
+// address:
+//
+//	preimage = HMAC-SHA256(rootKey, "lnd-invoice-preimage" || paymentAddr)
+func (d *PreimageDeriver) DerivePreimage(
+	paymentAddr [32]byte) lntypes.Preimage {
+
+	mac := hmac.New(sha256.New, d.rootKey[:])
+	_, _ = mac.Write(preimageTag)
+	_, _ = mac.Write(paymentAddr[:])
+
+	var preimage lntypes.Preimage
+	copy(preimage[:], mac.Sum(nil))
+
+	return preimage
+}
@@ -0,0 +1,59 @@
+package invoices_test
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/stretchr/testify/require"
+)
+
+// staticKeyRing is a keychain.KeyRing that derives the same key for any
+// locator.
+type staticKeyRing struct {
+	key *btcec.PrivateKey
+}
+
+func (s *staticKeyRing) DeriveNextKey(
+	_ keychain.KeyFamily) (keychain.KeyDescriptor, error) {
+
+	return keychain.KeyDescriptor{PubKey: s.key.PubKey()}, nil
+}
+
+func (s *staticKeyRing) DeriveKey(
+	_ keychain.KeyLocator) (keychain.KeyDescriptor, error) {
+
+	return keychain.KeyDescriptor{PubKey: s.key.PubKey()}, nil
+}
+
+// TestPreimageDeriver tests that invoice preimages are derived
+// deterministically from the key ring and the payment address.
+func TestPreimageDeriver(t *testing.T) {
+	t.Parallel()
+
+	newDeriver := func(seed byte) *invoices.PreimageDeriver {
+		key, _ := btcec.PrivKeyFromBytes([]byte{seed})
+		deriver, err := invoices.NewPreimageDeriver(
+			&staticKeyRing{key: key},
+		)
+		require.NoError(t, err)
+
+		return deriver
+	}
+
+	deriver := newDeriver(1)
+	restored := newDeriver(1)
+	other := newDeriver(2)
+
+	addrA := [32]byte{1}
+	addrB := [32]byte{2}
+
+	// A restored node derives the same preimages.
+	preimageA := deriver.DerivePreimage(addrA)
+	require.Equal(t, preimageA, restored.DerivePreimage(addrA))
+
+	// Different payment addresses or seeds result in different preimages.
+	require.NotEqual(t, preimageA, deriver.DerivePreimage(addrB))
+	require.NotEqual(t, preimageA, other.DerivePreimage(addrA))
+}
@@ -120,6 +120,10 @@ const (
 	// ResultAmpReconstruction is returned when the derived child
 	// hash/preimage pairs were invalid for at least one HTLC in the set.
 	ResultAmpReconstruction
+
+	// ResultAmountTooHigh is returned when an invoice is paid more than
+	// its overpayment policy allows.
+	ResultAmountTooHigh
 )
 
 // String returns a string representation of the result.
@@ -189,6 +193,9 @@ func (f FailResolutionResult) FailureString() string {
 	case ResultAmpReconstruction:
 		return "amp reconstruction failed"
 
+	case ResultAmountTooHigh:
+		return "amount too high"
+
 	default:
 		return "unknown failure resolution result"
 	}
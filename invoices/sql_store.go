@@ -59,6 +59,12 @@ type SQLInvoiceQueries interface { //nolint:interfacebloat
 	GetInvoiceHTLCs(ctx context.Context,
 		invoiceID int64) ([]sqlc.InvoiceHtlc, error)
 
+	InsertInvoiceOverpaymentPolicy(ctx context.Context,
+		arg sqlc.InsertInvoiceOverpaymentPolicyParams) error
+
+	GetInvoiceOverpaymentPolicy(ctx context.Context,
+		invoiceID int64) (sqlc.InvoiceOverpaymentPolicy, error)
+
 	UpdateInvoiceState(ctx context.Context,
 		arg sqlc.UpdateInvoiceStateParams) (sql.Result, error)
 
@@ -289,6 +295,23 @@ func (i *SQLStore) AddInvoice(ctx context.Context,
 			}
 		}
 
+		// Only store the overpayment policy if the invoice doesn't use
+		// the node wide one.
+		policy := newInvoice.OverpaymentPolicy
+		if !policy.IsDefault() {
+			params := sqlc.InsertInvoiceOverpaymentPolicyParams{
+				InvoiceID:  invoiceID,
+				Mode:       int16(policy.Mode),
+				MaxPercent: int64(policy.MaxPercent),
+			}
+
+			err := db.InsertInvoiceOverpaymentPolicy(ctx, params)
+			if err != nil {
+				return fmt.Errorf("unable to insert invoice "+
+					"overpayment policy: %w", err)
+			}
+		}
+
 		// Finally add a new event for this invoice.
 		return db.OnInvoiceCreated(ctx, sqlc.OnInvoiceCreatedParams{
 			AddedAt:   newInvoice.CreationDate.UTC(),
@@ -1481,6 +1504,14 @@ func fetchInvoiceData(ctx context.Context, db SQLInvoiceQueries,
 
 	invoice.Terms.Features = features
 
+	// Fetch the overpayment policy of the invoice, if it has its own.
+	policy, err := getInvoiceOverpaymentPolicy(ctx, db, row.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	invoice.OverpaymentPolicy = policy
+
 	// If this is an AMP invoice, we'll need fetch the AMP state along
 	// with the HTLCs (if requested).
 	if invoice.IsAMP() {
@@ -1536,6 +1567,28 @@ func getInvoiceFeatures(ctx context.Context, db SQLInvoiceQueries,
 	return features, nil
 }
 
+// getInvoiceOverpaymentPolicy fetches the overpayment policy for the given
+// invoice id. The default policy is returned if the invoice doesn't have its
+// own policy.
+func getInvoiceOverpaymentPolicy(ctx context.Context, db SQLInvoiceQueries,
+	invoiceID int64) (OverpaymentPolicy, error) {
+
+	row, err := db.GetInvoiceOverpaymentPolicy(ctx, invoiceID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return OverpaymentPolicy{}, nil
+
+	case err != nil:
+		return OverpaymentPolicy{}, fmt.Errorf("unable to get invoice "+
+			"overpayment policy: %w", err)
+	}
+
+	return OverpaymentPolicy{
+		Mode:       OverpaymentMode(row.Mode),
+		MaxPercent: uint32(row.MaxPercent),
+	}, nil
+}
+
 // getInvoiceHtlcs fetches the invoice htlcs for the given invoice id.
 func getInvoiceHtlcs(ctx context.Context, db SQLInvoiceQueries,
 	invoiceID int64) (map[CircuitKey]*InvoiceHTLC, error) {
@@ -22,6 +22,10 @@ type invoiceUpdateCtx struct {
 	currentHeight        int32
 	finalCltvRejectDelta int32
 
+	// defaultOverpaymentPolicy is the node wide overpayment policy, which
+	// is used for invoices that don't have their own policy.
+	defaultOverpaymentPolicy OverpaymentPolicy
+
 	// wireCustomRecords are the custom records that were included with the
 	// HTLC wire message.
 	wireCustomRecords lnwire.CustomRecords
@@ -252,6 +256,18 @@ func updateMpp(ctx *invoiceUpdateCtx, inv *Invoice) (*InvoiceUpdateDesc,
 	// Add amount of new htlc.
 	newSetTotal += ctx.amtPaid
 
+	// Check that the set doesn't pay more than the overpayment policy of
+	// the invoice allows. The htlcs of the set may add up to more than
+	// the set total they commit to, so the highest of the two is used.
+	setAmt := totalAmt
+	if newSetTotal > setAmt {
+		setAmt = newSetTotal
+	}
+	policy := ctx.overpaymentPolicy(inv)
+	if !policy.allows(inv.Terms.Value, setAmt) {
+		return nil, ctx.failRes(ResultHtlcSetOverpayment), nil
+	}
+
 	// The invoice is still open. Check the expiry.
 	if ctx.expiry < uint32(ctx.currentHeight+ctx.finalCltvRejectDelta) {
 		return nil, ctx.failRes(ResultExpiryTooSoon), nil
@@ -417,6 +433,13 @@ func updateLegacy(ctx *invoiceUpdateCtx,
 		return nil, ctx.failRes(ResultAmountTooLow), nil
 	}
 
+	// Also check that the htlc doesn't pay more than the overpayment
+	// policy of the invoice allows.
+	policy := ctx.overpaymentPolicy(inv)
+	if !policy.allows(inv.Terms.Value, ctx.amtPaid) {
+		return nil, ctx.failRes(ResultAmountTooHigh), nil
+	}
+
 	// If the invoice had the required feature bit set at this point, then
 	// if we're in this method it means that the remote party didn't supply
 	// the expected payload. However if this is a keysend payment, then
@@ -0,0 +1,42 @@
+package lnd
+
+import (
+	"github.com/lightningnetwork/lnd/contractcourt"
+	"github.com/lightningnetwork/lnd/sweep"
+)
+
+// extJusticeDelegate is a justice delegate that submits the justice
+// transactions of detected breaches to the external broadcasters.
+type extJusticeDelegate struct {
+	publisher *sweep.ExternalPublisher
+}
+
+// A compile time check to ensure extJusticeDelegate implements the
+// contractcourt.JusticeDelegate interface.
+var _ contractcourt.JusticeDelegate = (*extJusticeDelegate)(nil)
+
+// newExtJusticeDelegate creates a new justice delegate that submits justice
+// transactions using the given external publisher.
+func newExtJusticeDelegate(
+	publisher *sweep.ExternalPublisher) *extJusticeDelegate {
+
+	return &extJusticeDelegate{
+		publisher: publisher,
+	}
+}
+
+// DelegateJustice submits the justice transaction that sweeps all breached
+// outputs to the external broadcasters. The fallback transactions conflict
+// with it, so they are left to the local breach handling which publishes them
+// if it doesn't confirm in time.
+//
+// NOTE: This is part of the contractcourt.JusticeDelegate interface.
+func (e *extJusticeDelegate) DelegateJustice(
+	justice *contractcourt.BreachJustice) error {
+
+	if justice.SpendAll != nil {
+		e.publisher.Publish(justice.SpendAll)
+	}
+
+	return nil
+}
@@ -0,0 +1,331 @@
+//go:build !js
+// +build !js
+
+package kvdb
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// snapshotDirFormat is the time format of the directory names of
+	// database snapshots. It sorts lexicographically in chronological
+	// order.
+	snapshotDirFormat = "20060102-150405"
+
+	// partialSnapshotSuffix is the suffix of a snapshot directory that is
+	// still being written.
+	partialSnapshotSuffix = ".partial"
+
+	// DefaultSnapshotsToKeep is the default number of database snapshots
+	// that are kept before the oldest ones are removed.
+	DefaultSnapshotsToKeep = 3
+)
+
+var (
+	// ErrSnapshotAborted is returned if a snapshot is aborted because the
+	// snapshotter is shutting down.
+	ErrSnapshotAborted = errors.New("snapshot aborted")
+)
+
+// throttledWriter is an io.Writer that limits the rate at which bytes are
+// written to the underlying writer.
+type throttledWriter struct {
+	w io.Writer
+
+	// rateLimit is the maximum number of bytes written per second. Zero
+	// means no limit.
+	rateLimit uint64
+
+	start   time.Time
+	written uint64
+
+	quit <-chan struct{}
+}
+
+// Write writes p to the underlying writer, then blocks until the average
+// write rate is within the rate limit again.
+//
+// NOTE: This is part of the io.Writer interface.
+func (t *throttledWriter) Write(p []byte) (int, error) {
+	n, err := t.w.Write(p)
+	if err != nil || t.rateLimit == 0 {
+		return n, err
+	}
+
+	t.written += uint64(n)
+	seconds := float64(t.written) / float64(t.rateLimit)
+	expected := time.Duration(seconds * float64(time.Second))
+
+	wait := expected - time.Since(t.start)
+	if wait <= 0 {
+		return n, nil
+	}
+
+	select {
+	case <-time.After(wait):
+		return n, nil
+
+	case <-t.quit:
+		return n, ErrSnapshotAborted
+	}
+}
+
+// SnapshotBolt writes a consistent copy of the database to destPath while the
+// database remains in use. The copy is taken within a single read
+// transaction, so it reflects the state of the database at the time the
+// snapshot started. The copy is first written to a temporary file that is
+// only renamed to destPath once it is complete and synced, so destPath never
+// contains a partial snapshot.
+//
+// If rateLimit is non-zero, the copy is written at no more than rateLimit
+// bytes per second to limit the I/O impact on the running node. Note that
+// the read transaction stays open while the copy is written, which prevents
+// bbolt from reusing freed pages in the meantime.
+func SnapshotBolt(db Backend, destPath string, rateLimit uint64,
+	quit <-chan struct{}) (int64, error) {
+
+	tempPath := destPath + partialSnapshotSuffix
+	file, err := os.OpenFile(
+		tempPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("unable to create snapshot file: %w", err)
+	}
+
+	cleanUp := func() {
+		_ = file.Close()
+		_ = os.Remove(tempPath)
+	}
+
+	writer := &throttledWriter{
+		w:         file,
+		rateLimit: rateLimit,
+		start:     time.Now(),
+		quit:      quit,
+	}
+	if err := db.Copy(writer); err != nil {
+		cleanUp()
+		return 0, fmt.Errorf("unable to copy database: %w", err)
+	}
+
+	if err := file.Sync(); err != nil {
+		cleanUp()
+		return 0, fmt.Errorf("unable to sync snapshot file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		cleanUp()
+		return 0, err
+	}
+
+	if err := file.Close(); err != nil {
+		_ = os.Remove(tempPath)
+		return 0, err
+	}
+
+	if err := os.Rename(tempPath, destPath); err != nil {
+		_ = os.Remove(tempPath)
+		return 0, err
+	}
+
+	return info.Size(), nil
+}
+
+// SnapshotConfig holds the configuration of a Snapshotter.
+type SnapshotConfig struct {
+	// DBs are the bolt databases to snapshot, keyed by their file name.
+	DBs map[string]Backend
+
+	// Dir is the directory the snapshots are stored in. Each snapshot is
+	// written to its own sub directory that is named after the time the
+	// snapshot was taken.
+	Dir string
+
+	// Interval is the interval at which snapshots are taken. A zero value
+	// disables scheduled snapshots, they can still be taken with
+	// Snapshot.
+	Interval time.Duration
+
+	// RateLimit is the maximum number of bytes per second written while
+	// taking a snapshot. Zero means no limit.
+	RateLimit uint64
+
+	// NumToKeep is the number of snapshots that are kept. Older snapshots
+	// are removed after each new snapshot. Zero keeps all snapshots.
+	NumToKeep int
+}
+
+// Snapshotter takes online snapshots of a set of bolt databases, either on a
+// schedule or on demand.
+type Snapshotter struct {
+	cfg *SnapshotConfig
+
+	// mu makes sure only one snapshot is taken at a time.
+	mu sync.Mutex
+
+	started sync.Once
+	stopped sync.Once
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewSnapshotter creates a new Snapshotter.
+func NewSnapshotter(cfg *SnapshotConfig) *Snapshotter {
+	return &Snapshotter{
+		cfg:  cfg,
+		quit: make(chan struct{}),
+	}
+}
+
+// Start starts taking scheduled snapshots, if an interval is configured.
+func (s *Snapshotter) Start() {
+	s.started.Do(func() {
+		if s.cfg.Interval == 0 {
+			return
+		}
+
+		log.Infof("Taking database snapshots every %v in %v",
+			s.cfg.Interval, s.cfg.Dir)
+
+		s.wg.Add(1)
+		go s.snapshotLoop()
+	})
+}
+
+// Stop stops taking scheduled snapshots and aborts a running snapshot.
+func (s *Snapshotter) Stop() {
+	s.stopped.Do(func() {
+		close(s.quit)
+		s.wg.Wait()
+	})
+}
+
+// snapshotLoop takes a snapshot each time the configured interval elapses.
+//
+// NOTE: This MUST be run as a goroutine.
+func (s *Snapshotter) snapshotLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			dir, err := s.Snapshot()
+			if err != nil {
+				log.Errorf("Unable to take database "+
+					"snapshot: %v", err)
+
+				continue
+			}
+
+			log.Infof("Took database snapshot in %v", dir)
+
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// Snapshot takes a snapshot of all databases and returns the directory it
+// was written to. Each database is copied in its own read transaction, so
+// the snapshots of different databases may reflect slightly different points
+// in time. Once the snapshot is complete, old snapshots exceeding the number
+// of snapshots to keep are removed.
+func (s *Snapshotter) Snapshot() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := time.Now().UTC().Format(snapshotDirFormat)
+	dir := filepath.Join(s.cfg.Dir, name)
+	tempDir := dir + partialSnapshotSuffix
+
+	if err := os.MkdirAll(tempDir, 0700); err != nil {
+		return "", err
+	}
+
+	for fileName, db := range s.cfg.DBs {
+		start := time.Now()
+		size, err := SnapshotBolt(
+			db, filepath.Join(tempDir, fileName), s.cfg.RateLimit,
+			s.quit,
+		)
+		if err != nil {
+			_ = os.RemoveAll(tempDir)
+			return "", fmt.Errorf("unable to snapshot %v: %w",
+				fileName, err)
+		}
+
+		log.Debugf("Snapshot of %v took %v, size=%d bytes", fileName,
+			time.Since(start), size)
+	}
+
+	if err := os.Rename(tempDir, dir); err != nil {
+		_ = os.RemoveAll(tempDir)
+		return "", err
+	}
+
+	if err := s.pruneSnapshots(); err != nil {
+		log.Warnf("Unable to remove old database snapshots: %v", err)
+	}
+
+	return dir, nil
+}
+
+// pruneSnapshots removes the oldest snapshots exceeding the number of
+// snapshots to keep, as well as snapshots that were never completed.
+func (s *Snapshotter) pruneSnapshots() error {
+	entries, err := os.ReadDir(s.cfg.Dir)
+	if err != nil {
+		return err
+	}
+
+	var snapshots []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		name := entry.Name()
+		if filepath.Ext(name) == partialSnapshotSuffix {
+			if err := os.RemoveAll(
+				filepath.Join(s.cfg.Dir, name),
+			); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if _, err := time.Parse(snapshotDirFormat, name); err != nil {
+			continue
+		}
+
+		snapshots = append(snapshots, name)
+	}
+
+	if s.cfg.NumToKeep == 0 || len(snapshots) <= s.cfg.NumToKeep {
+		return nil
+	}
+
+	sort.Strings(snapshots)
+	for _, name := range snapshots[:len(snapshots)-s.cfg.NumToKeep] {
+		err := os.RemoveAll(filepath.Join(s.cfg.Dir, name))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+//go:build !js
+// +build !js
+
+package kvdb
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	snapshotTestBucket = []byte("bucket")
+	snapshotTestKey    = []byte("key")
+	snapshotTestValue  = []byte("value")
+)
+
+// newSnapshotTestDB creates a bolt database in the given directory that
+// contains a single key.
+func newSnapshotTestDB(t *testing.T, dir, fileName string) Backend {
+	t.Helper()
+
+	db, err := GetBoltBackend(&BoltBackendConfig{
+		DBPath:     dir,
+		DBFileName: fileName,
+		DBTimeout:  DefaultDBTimeout,
+	})
+	require.NoError(t, err)
+
+	err = Update(db, func(tx RwTx) error {
+		bucket, err := tx.CreateTopLevelBucket(snapshotTestBucket)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(snapshotTestKey, snapshotTestValue)
+	}, func() {})
+	require.NoError(t, err)
+
+	return db
+}
+
+// assertSnapshotContent opens the snapshot at the given path and asserts
+// that it contains the test key.
+func assertSnapshotContent(t *testing.T, dir, fileName string) {
+	t.Helper()
+
+	db, err := GetBoltBackend(&BoltBackendConfig{
+		DBPath:     dir,
+		DBFileName: fileName,
+		DBTimeout:  DefaultDBTimeout,
+	})
+	require.NoError(t, err)
+	defer db.Close()
+
+	err = View(db, func(tx RTx) error {
+		bucket := tx.ReadBucket(snapshotTestBucket)
+		require.NotNil(t, bucket)
+		require.Equal(t, snapshotTestValue, bucket.Get(snapshotTestKey))
+
+		return nil
+	}, func() {})
+	require.NoError(t, err)
+}
+
+// TestSnapshotBolt tests that a snapshot of a database in use can be opened
+// and contains the data of the database.
+func TestSnapshotBolt(t *testing.T) {
+	t.Parallel()
+
+	dbDir := t.TempDir()
+	db := newSnapshotTestDB(t, dbDir, "test.db")
+	defer db.Close()
+
+	snapshotDir := t.TempDir()
+	snapshotPath := filepath.Join(snapshotDir, "test.db")
+	quit := make(chan struct{})
+
+	size, err := SnapshotBolt(db, snapshotPath, 1<<20, quit)
+	require.NoError(t, err)
+	require.Positive(t, size)
+	assertSnapshotContent(t, snapshotDir, "test.db")
+
+	// An aborted snapshot doesn't leave any files behind.
+	close(quit)
+	abortedPath := filepath.Join(snapshotDir, "aborted.db")
+	_, err = SnapshotBolt(db, abortedPath, 1, quit)
+	require.ErrorIs(t, err, ErrSnapshotAborted)
+
+	entries, err := os.ReadDir(snapshotDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
+
+// TestSnapshotter tests that the snapshotter snapshots all databases and
+// removes old and partial snapshots.
+func TestSnapshotter(t *testing.T) {
+	t.Parallel()
+
+	dbDir := t.TempDir()
+	db1 := newSnapshotTestDB(t, dbDir, "one.db")
+	defer db1.Close()
+	db2 := newSnapshotTestDB(t, dbDir, "two.db")
+	defer db2.Close()
+
+	// Create some old snapshots, a partial one and an unrelated
+	// directory.
+	snapshotDir := t.TempDir()
+	for _, name := range []string{
+		"20200101-000000", "20200102-000000",
+		"20200103-000000" + partialSnapshotSuffix, "other",
+	} {
+		err := os.Mkdir(filepath.Join(snapshotDir, name), 0700)
+		require.NoError(t, err)
+	}
+
+	snapshotter := NewSnapshotter(&SnapshotConfig{
+		DBs: map[string]Backend{
+			"one.db": db1,
+			"two.db": db2,
+		},
+		Dir:       snapshotDir,
+		NumToKeep: 2,
+	})
+	snapshotter.Start()
+	defer snapshotter.Stop()
+
+	dir, err := snapshotter.Snapshot()
+	require.NoError(t, err)
+
+	assertSnapshotContent(t, dir, "one.db")
+	assertSnapshotContent(t, dir, "two.db")
+
+	entries, err := os.ReadDir(snapshotDir)
+	require.NoError(t, err)
+
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	require.ElementsMatch(t, []string{
+		"20200102-000000", filepath.Base(dir), "other",
+	}, names)
+}
@@ -42,4 +42,12 @@ type BoltConfig struct {
 	AutoCompactMinAge time.Duration `long:"auto-compact-min-age" description:"How long ago the last compaction of a database file must be for it to be considered for auto compaction again. Can be set to 0 to compact on every startup."`
 
 	DBTimeout time.Duration `long:"dbtimeout" description:"Specify the timeout value used when opening the database."`
+
+	SnapshotInterval time.Duration `long:"snapshot-interval" description:"The interval at which consistent snapshots of the bolt databases are taken while lnd is running. Set to 0 to disable scheduled snapshots."`
+
+	SnapshotDir string `long:"snapshot-dir" description:"The directory the database snapshots are written to. Defaults to a snapshots directory next to the channel database."`
+
+	SnapshotRateLimit uint64 `long:"snapshot-rate-limit" description:"The maximum number of bytes per second written while taking a database snapshot, to limit the I/O impact on the running node. Set to 0 to disable the limit."`
+
+	SnapshotsToKeep int `long:"snapshots-to-keep" description:"The number of database snapshots to keep. Older snapshots are removed after each new snapshot. Set to 0 to keep all snapshots."`
 }
@@ -0,0 +1,330 @@
+package kvdb
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+)
+
+// TxLatencyBuckets are the upper bounds of the buckets transaction latencies
+// are sorted into.
+var TxLatencyBuckets = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// LatencyHistogram is a snapshot of the latencies of database transactions.
+type LatencyHistogram struct {
+	// Count is the number of observed transactions.
+	Count uint64
+
+	// Sum is the total duration of all observed transactions.
+	Sum time.Duration
+
+	// Buckets maps the upper bound of each bucket in TxLatencyBuckets to
+	// the number of transactions that took at most that long.
+	Buckets map[time.Duration]uint64
+}
+
+// latencyHistogram records the latencies of database transactions.
+type latencyHistogram struct {
+	mu     sync.Mutex
+	counts []uint64
+	count  uint64
+	sum    time.Duration
+}
+
+// newLatencyHistogram creates a new histogram with the TxLatencyBuckets.
+func newLatencyHistogram() *latencyHistogram {
+	return &latencyHistogram{
+		counts: make([]uint64, len(TxLatencyBuckets)),
+	}
+}
+
+// observe records a single transaction latency.
+func (h *latencyHistogram) observe(latency time.Duration) {
+	idx := sort.Search(len(TxLatencyBuckets), func(i int) bool {
+		return latency <= TxLatencyBuckets[i]
+	})
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.count++
+	h.sum += latency
+	if idx < len(h.counts) {
+		h.counts[idx]++
+	}
+}
+
+// snapshot returns the current state of the histogram with cumulative bucket
+// counts.
+func (h *latencyHistogram) snapshot() LatencyHistogram {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	buckets := make(map[time.Duration]uint64, len(TxLatencyBuckets))
+	var cumulative uint64
+	for i, bound := range TxLatencyBuckets {
+		cumulative += h.counts[i]
+		buckets[bound] = cumulative
+	}
+
+	return LatencyHistogram{
+		Count:   h.count,
+		Sum:     h.sum,
+		Buckets: buckets,
+	}
+}
+
+// InstrumentedBackend wraps a Backend and records the latencies of its read
+// and write transactions. The latency of a transaction spans from its start
+// until it is committed or rolled back, including any retries of the
+// backend.
+type InstrumentedBackend struct {
+	Backend
+
+	subsystem string
+
+	readLatency  *latencyHistogram
+	writeLatency *latencyHistogram
+}
+
+// A compile-time check to ensure InstrumentedBackend retains batching
+// support.
+var _ walletdb.BatchDB = (*InstrumentedBackend)(nil)
+
+// NewInstrumentedBackend wraps the given backend of a subsystem to record the
+// latencies of its transactions.
+func NewInstrumentedBackend(db Backend,
+	subsystem string) *InstrumentedBackend {
+
+	return &InstrumentedBackend{
+		Backend:      db,
+		subsystem:    subsystem,
+		readLatency:  newLatencyHistogram(),
+		writeLatency: newLatencyHistogram(),
+	}
+}
+
+// Subsystem returns the name of the subsystem the backend belongs to.
+func (b *InstrumentedBackend) Subsystem() string {
+	return b.subsystem
+}
+
+// ReadLatency returns a snapshot of the latencies of the read transactions.
+func (b *InstrumentedBackend) ReadLatency() LatencyHistogram {
+	return b.readLatency.snapshot()
+}
+
+// WriteLatency returns a snapshot of the latencies of the write transactions.
+func (b *InstrumentedBackend) WriteLatency() LatencyHistogram {
+	return b.writeLatency.snapshot()
+}
+
+// BeginReadTx opens a database read transaction.
+//
+// NOTE: Part of the walletdb.DB interface.
+func (b *InstrumentedBackend) BeginReadTx() (walletdb.ReadTx, error) {
+	start := time.Now()
+	tx, err := b.Backend.BeginReadTx()
+	if err != nil {
+		return nil, err
+	}
+
+	return &instrumentedReadTx{
+		ReadTx: tx,
+		done: func() {
+			b.readLatency.observe(time.Since(start))
+		},
+	}, nil
+}
+
+// BeginReadWriteTx opens a database read+write transaction.
+//
+// NOTE: Part of the walletdb.DB interface.
+func (b *InstrumentedBackend) BeginReadWriteTx() (walletdb.ReadWriteTx,
+	error) {
+
+	start := time.Now()
+	tx, err := b.Backend.BeginReadWriteTx()
+	if err != nil {
+		return nil, err
+	}
+
+	return &instrumentedReadWriteTx{
+		ReadWriteTx: tx,
+		done: func() {
+			b.writeLatency.observe(time.Since(start))
+		},
+	}, nil
+}
+
+// View opens a database read transaction and executes the function f with
+// the transaction passed as a parameter.
+//
+// NOTE: Part of the walletdb.DB interface.
+func (b *InstrumentedBackend) View(f func(tx walletdb.ReadTx) error,
+	reset func()) error {
+
+	start := time.Now()
+	defer func() {
+		b.readLatency.observe(time.Since(start))
+	}()
+
+	return b.Backend.View(f, reset)
+}
+
+// Update opens a database read/write transaction and executes the function f
+// with the transaction passed as a parameter.
+//
+// NOTE: Part of the walletdb.DB interface.
+func (b *InstrumentedBackend) Update(f func(tx walletdb.ReadWriteTx) error,
+	reset func()) error {
+
+	start := time.Now()
+	defer func() {
+		b.writeLatency.observe(time.Since(start))
+	}()
+
+	return b.Backend.Update(f, reset)
+}
+
+// Batch attempts to combine the given write transaction with others if the
+// wrapped backend supports batching, otherwise it is executed as a regular
+// update. The recorded latency includes the time spent waiting for the
+// batch.
+//
+// NOTE: Part of the walletdb.BatchDB interface.
+func (b *InstrumentedBackend) Batch(
+	f func(tx walletdb.ReadWriteTx) error) error {
+
+	batchDB, ok := b.Backend.(walletdb.BatchDB)
+	if !ok {
+		return b.Update(f, func() {})
+	}
+
+	start := time.Now()
+	defer func() {
+		b.writeLatency.observe(time.Since(start))
+	}()
+
+	return batchDB.Batch(f)
+}
+
+// instrumentedReadTx is a read transaction that reports its latency once it
+// is rolled back.
+type instrumentedReadTx struct {
+	walletdb.ReadTx
+
+	once sync.Once
+	done func()
+}
+
+// Rollback closes the transaction.
+//
+// NOTE: Part of the walletdb.ReadTx interface.
+func (tx *instrumentedReadTx) Rollback() error {
+	defer tx.once.Do(tx.done)
+
+	return tx.ReadTx.Rollback()
+}
+
+// instrumentedReadWriteTx is a read/write transaction that reports its latency
+// once it is committed or rolled back.
+type instrumentedReadWriteTx struct {
+	walletdb.ReadWriteTx
+
+	once sync.Once
+	done func()
+}
+
+// Commit commits the transaction.
+//
+// NOTE: Part of the walletdb.ReadWriteTx interface.
+func (tx *instrumentedReadWriteTx) Commit() error {
+	defer tx.once.Do(tx.done)
+
+	return tx.ReadWriteTx.Commit()
+}
+
+// Rollback closes the transaction, discarding its changes.
+//
+// NOTE: Part of the walletdb.ReadTx interface.
+func (tx *instrumentedReadWriteTx) Rollback() error {
+	defer tx.once.Do(tx.done)
+
+	return tx.ReadWriteTx.Rollback()
+}
+
+// BucketSize is an estimate of the size of a bucket.
+type BucketSize struct {
+	// Keys is the number of keys in the bucket and all its nested
+	// buckets, including the keys of the nested buckets themselves.
+	Keys uint64
+
+	// Bytes is the total size of the keys and values in the bucket and all
+	// its nested buckets. It doesn't account for the overhead of the
+	// storage format, so it is a lower bound of the space used on disk.
+	Bytes uint64
+}
+
+// TopLevelBucketSizes walks all top level buckets of the database and returns
+// their size, keyed by bucket name. As this reads the entire database, it can
+// take a while for large databases and shouldn't be called frequently.
+func TopLevelBucketSizes(db Backend) (map[string]BucketSize, error) {
+	var sizes map[string]BucketSize
+	err := View(db, func(tx RTx) error {
+		return tx.ForEachBucket(func(name []byte) error {
+			bucket := tx.ReadBucket(name)
+			if bucket == nil {
+				return nil
+			}
+
+			var size BucketSize
+			if err := addBucketSize(bucket, &size); err != nil {
+				return err
+			}
+			sizes[string(name)] = size
+
+			return nil
+		})
+	}, func() {
+		sizes = make(map[string]BucketSize)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sizes, nil
+}
+
+// addBucketSize adds the size of the given bucket and its nested buckets to
+// size.
+func addBucketSize(bucket RBucket, size *BucketSize) error {
+	return bucket.ForEach(func(k, v []byte) error {
+		size.Keys++
+		size.Bytes += uint64(len(k) + len(v))
+
+		// A nil value indicates a nested bucket.
+		if v != nil {
+			return nil
+		}
+
+		nested := bucket.NestedReadBucket(k)
+		if nested == nil {
+			return nil
+		}
+
+		return addBucketSize(nested, size)
+	})
+}
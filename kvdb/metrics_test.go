@@ -0,0 +1,84 @@
+//go:build !js
+// +build !js
+
+package kvdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestInstrumentedBackend asserts that the instrumented backend records the
+// latencies of read and write transactions, and that the sizes of the top
+// level buckets include their nested buckets.
+func TestInstrumentedBackend(t *testing.T) {
+	t.Parallel()
+
+	boltDB, err := GetBoltBackend(&BoltBackendConfig{
+		DBPath:     t.TempDir(),
+		DBFileName: "metrics.db",
+		DBTimeout:  DefaultDBTimeout,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, boltDB.Close())
+	})
+
+	db := NewInstrumentedBackend(boltDB, "test")
+	require.Equal(t, "test", db.Subsystem())
+
+	err = Update(db, func(tx RwTx) error {
+		top, err := tx.CreateTopLevelBucket([]byte("top"))
+		if err != nil {
+			return err
+		}
+		if err := top.Put([]byte("k"), []byte("value")); err != nil {
+			return err
+		}
+
+		nested, err := top.CreateBucket([]byte("nested"))
+		if err != nil {
+			return err
+		}
+
+		return nested.Put([]byte("key"), []byte("v"))
+	}, func() {})
+	require.NoError(t, err)
+
+	// A batched update and a manually managed transaction are recorded as
+	// write transactions as well.
+	err = Batch(db, func(tx RwTx) error {
+		_, err := tx.CreateTopLevelBucket([]byte("empty"))
+		return err
+	})
+	require.NoError(t, err)
+
+	tx, err := db.BeginReadWriteTx()
+	require.NoError(t, err)
+	require.NoError(t, tx.Rollback())
+
+	writes := db.WriteLatency()
+	require.EqualValues(t, 3, writes.Count)
+	require.Len(t, writes.Buckets, len(TxLatencyBuckets))
+	require.EqualValues(
+		t, 3, writes.Buckets[TxLatencyBuckets[len(TxLatencyBuckets)-1]],
+	)
+
+	sizes, err := TopLevelBucketSizes(db)
+	require.NoError(t, err)
+	require.Equal(t, map[string]BucketSize{
+		// The top bucket contains the key "k", the nested bucket and
+		// the key "key" within the nested bucket.
+		"top": {
+			Keys:  3,
+			Bytes: 1 + 5 + 6 + 3 + 1,
+		},
+		"empty": {},
+	}, sizes)
+
+	reads := db.ReadLatency()
+	require.EqualValues(t, 1, reads.Count)
+	require.Greater(t, reads.Sum, time.Duration(0))
+}
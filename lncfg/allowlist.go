@@ -0,0 +1,32 @@
+package lncfg
+
+import (
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+// AllowList holds the configuration of the peer allow list.
+//
+//nolint:lll
+type AllowList struct {
+	Enable bool `long:"enable" description:"If true, only peers on the allow list may connect to us or be connected to. Peers can be added to the allow list through this config or at runtime."`
+
+	PeersRaw []string `long:"peer" description:"A peer that is always on the allow list. The value should be a hex-encoded pubkey, the flag can be specified multiple times to add multiple peers."`
+
+	Peers []route.Vertex
+}
+
+// Parse parses the pubkeys of the statically allowed peers.
+func (a *AllowList) Parse() error {
+	peers := make([]route.Vertex, 0, len(a.PeersRaw))
+	for _, pubkeyStr := range a.PeersRaw {
+		vertex, err := route.NewVertexFromStr(pubkeyStr)
+		if err != nil {
+			return err
+		}
+		peers = append(peers, vertex)
+	}
+
+	a.Peers = peers
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package lncfg
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/lightningnetwork/lnd/chanbackup"
+)
+
+// BackupReplication holds the configuration of the remote targets that the
+// static channel backup file is replicated to.
+//
+//nolint:lll
+type BackupReplication struct {
+	MaxVersions int           `long:"maxversions" description:"The number of backup versions that are kept at each remote target. Older versions are deleted after a new one was stored successfully. Set to 0 to keep all versions."`
+	Timeout     time.Duration `long:"timeout" description:"The time to wait for a single replication attempt to a remote target to complete."`
+	MinBackoff  time.Duration `long:"minbackoff" description:"The time to wait before retrying a failed replication. The wait time is doubled after each consecutive failure."`
+	MaxBackoff  time.Duration `long:"maxbackoff" description:"The maximum time to wait before retrying a failed replication."`
+
+	S3   *BackupS3   `group:"s3" namespace:"s3" description:"Replicate the channel backup to an S3 compatible object storage."`
+	SFTP *BackupSFTP `group:"sftp" namespace:"sftp" description:"Replicate the channel backup to a directory on an SFTP server."`
+}
+
+// BackupS3 holds the configuration of an S3 compatible replication target.
+//
+//nolint:lll
+type BackupS3 struct {
+	Endpoint        string `long:"endpoint" description:"The URL of the S3 compatible API, e.g. https://s3.us-east-1.amazonaws.com. The S3 target is enabled if set."`
+	Bucket          string `long:"bucket" description:"The bucket the backup versions are stored in."`
+	Region          string `long:"region" description:"The region of the bucket used to sign the requests."`
+	AccessKeyID     string `long:"accesskeyid" description:"The access key ID used to sign the requests."`
+	SecretAccessKey string `long:"secretaccesskey" description:"The secret access key used to sign the requests."`
+	Prefix          string `long:"prefix" description:"An optional key prefix the backup versions are stored under, which allows multiple nodes to share a bucket."`
+}
+
+// BackupSFTP holds the configuration of an SFTP replication target.
+//
+//nolint:lll
+type BackupSFTP struct {
+	Addr           string `long:"addr" description:"The host:port of the SSH server. The SFTP target is enabled if set."`
+	User           string `long:"user" description:"The user to log in as."`
+	Password       string `long:"password" description:"The password of the user."`
+	PrivateKeyPath string `long:"privatekeypath" description:"The path to a PEM encoded private key used to log in instead of or in addition to the password."`
+	HostKey        string `long:"hostkey" description:"The public key of the SSH server in the authorized_keys format, e.g. 'ssh-ed25519 AAAA...'."`
+	Dir            string `long:"dir" description:"The existing remote directory the backup versions are stored in."`
+}
+
+// DefaultBackupReplication returns the default replication configuration,
+// which doesn't enable any target.
+func DefaultBackupReplication() *BackupReplication {
+	return &BackupReplication{
+		MaxVersions: chanbackup.DefaultMaxBackupVersions,
+		Timeout:     chanbackup.DefaultReplicationTimeout,
+		MinBackoff:  chanbackup.DefaultMinReplicationBackoff,
+		MaxBackoff:  chanbackup.DefaultMaxReplicationBackoff,
+		S3:          &BackupS3{},
+		SFTP:        &BackupSFTP{},
+	}
+}
+
+// Validate checks the values configured for the backup replication.
+func (b *BackupReplication) Validate() error {
+	if b == nil {
+		return nil
+	}
+
+	if b.MaxVersions < 0 {
+		return fmt.Errorf("backupreplication.maxversions must not be " +
+			"negative")
+	}
+
+	if b.Timeout <= 0 {
+		return fmt.Errorf("backupreplication.timeout must be positive")
+	}
+
+	if b.MinBackoff <= 0 || b.MaxBackoff < b.MinBackoff {
+		return fmt.Errorf("backupreplication.minbackoff must be " +
+			"positive and not exceed backupreplication.maxbackoff")
+	}
+
+	// Constructing the targets checks their configuration without
+	// connecting to them.
+	_, err := b.Targets()
+
+	return err
+}
+
+// Targets creates the replication targets that are enabled in the
+// configuration.
+func (b *BackupReplication) Targets() ([]chanbackup.BackupTarget, error) {
+	if b == nil {
+		return nil, nil
+	}
+
+	var targets []chanbackup.BackupTarget
+	if b.S3 != nil && b.S3.Endpoint != "" {
+		target, err := chanbackup.NewS3Target(chanbackup.S3TargetConfig{
+			Endpoint:        b.S3.Endpoint,
+			Bucket:          b.S3.Bucket,
+			Region:          b.S3.Region,
+			AccessKeyID:     b.S3.AccessKeyID,
+			SecretAccessKey: b.S3.SecretAccessKey,
+			Prefix:          b.S3.Prefix,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid backupreplication.s3 "+
+				"config: %w", err)
+		}
+
+		targets = append(targets, target)
+	}
+
+	if b.SFTP != nil && b.SFTP.Addr != "" {
+		var privateKey []byte
+		if b.SFTP.PrivateKeyPath != "" {
+			var err error
+			privateKey, err = os.ReadFile(
+				CleanAndExpandPath(b.SFTP.PrivateKeyPath),
+			)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read sftp "+
+					"private key: %w", err)
+			}
+		}
+
+		target, err := chanbackup.NewSFTPTarget(
+			chanbackup.SFTPTargetConfig{
+				Addr:       b.SFTP.Addr,
+				User:       b.SFTP.User,
+				Password:   b.SFTP.Password,
+				PrivateKey: privateKey,
+				HostKey:    b.SFTP.HostKey,
+				Dir:        b.SFTP.Dir,
+			},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("invalid backupreplication."+
+				"sftp config: %w", err)
+		}
+
+		targets = append(targets, target)
+	}
+
+	return targets, nil
+}
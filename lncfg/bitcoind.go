@@ -1,6 +1,9 @@
 package lncfg
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 const (
 	// DefaultTxPollingJitter defines the default TxPollingIntervalJitter
@@ -27,4 +30,29 @@ type Bitcoind struct {
 	RPCPolling           bool          `long:"rpcpolling" description:"Poll the bitcoind RPC interface for block and transaction notifications instead of using the ZMQ interface"`
 	BlockPollingInterval time.Duration `long:"blockpollinginterval" description:"The interval that will be used to poll bitcoind for new blocks. Only used if rpcpolling is true."`
 	TxPollingInterval    time.Duration `long:"txpollinginterval" description:"The interval that will be used to poll bitcoind for new tx. Only used if rpcpolling is true."`
+	TxPollingJitter      float64       `long:"txpollingjitter" description:"The fraction of the tx polling interval that is randomly added to or subtracted from each poll interval, in the range [0, 1]. This spreads out the load of the mempool polling on the bitcoind node."`
+	RPCBatchSize         uint32        `long:"rpcbatchsize" description:"The number of getrawtransaction requests that are batched when fetching the transactions that entered the mempool since the previous poll. If not set, the default of the chain backend is used."`
+	RPCBatchInterval     time.Duration `long:"rpcbatchinterval" description:"The time to wait between two batches of getrawtransaction requests when fetching new mempool transactions. If not set, the default of the chain backend is used."`
+}
+
+// Validate checks the values configured for the bitcoind backend.
+func (b *Bitcoind) Validate() error {
+	if b.BlockPollingInterval < 0 {
+		return fmt.Errorf("blockpollinginterval must not be negative")
+	}
+
+	if b.TxPollingInterval < 0 {
+		return fmt.Errorf("txpollinginterval must not be negative")
+	}
+
+	if b.TxPollingJitter < 0 || b.TxPollingJitter > 1 {
+		return fmt.Errorf("txpollingjitter must be in the range "+
+			"[0, 1], got %v", b.TxPollingJitter)
+	}
+
+	if b.RPCBatchInterval < 0 {
+		return fmt.Errorf("rpcbatchinterval must not be negative")
+	}
+
+	return nil
 }
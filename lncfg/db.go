@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/btcsuite/btclog"
+	"github.com/lightningnetwork/lnd/channeldb/encrypteddb"
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/kvdb/etcd"
 	"github.com/lightningnetwork/lnd/kvdb/postgres"
@@ -38,6 +39,10 @@ const (
 	SqliteBackend              = "sqlite"
 	DefaultBatchCommitInterval = 500 * time.Millisecond
 
+	// DefaultClosedChanReapInterval is the default interval at which the
+	// data of closed channels past their retention is pruned.
+	DefaultClosedChanReapInterval = 24 * time.Hour
+
 	defaultPostgresMaxConnections = 50
 	defaultSqliteMaxConnections   = 2
 
@@ -87,22 +92,36 @@ type DB struct {
 
 	UseNativeSQL bool `long:"use-native-sql" description:"Use native SQL for tables that already support it."`
 
+	UseNativeSQLGraph bool `long:"use-native-sql-graph" description:"Store the channel graph in native SQL tables. The existing graph is copied to them on the first startup. Requires use-native-sql."`
+
 	NoGraphCache bool `long:"no-graph-cache" description:"Don't use the in-memory graph cache for path finding. Much slower but uses less RAM. Can only be used with a bolt database backend."`
 
 	PruneRevocation bool `long:"prune-revocation" description:"Run the optional migration that prunes the revocation logs to save disk space."`
 
 	NoRevLogAmtData bool `long:"no-rev-log-amt-data" description:"If set, the to-local and to-remote output amounts of revoked commitment transactions will not be stored in the revocation log. Note that once this data is lost, a watchtower client will not be able to back up the revoked state."`
+
+	CheckIntegrity bool `long:"check-integrity" description:"Check the references between the channel graph and the channel state database on startup, and log the edges and forwarding packages of channels that no longer exist."`
+
+	ClosedChanRetention uint32 `long:"closed-chan-retention" description:"The number of blocks after which the historical state, final HTLC resolutions and leftover forwarding packages of fully closed channels are pruned from the channel database. The close summaries are kept, but the initiator of older closed channels will be reported as unknown. Set to 0 to retain this data forever."`
+
+	ClosedChanReapInterval time.Duration `long:"closed-chan-reap-interval" description:"The interval at which the data of closed channels past closed-chan-retention is pruned."`
+
+	PruneOrphans bool `long:"prune-orphans" description:"Check the references between the channel graph and the channel state database on startup, and remove the edges and forwarding packages of channels that no longer exist."`
+
+	Encryption *DBEncryption `group:"encryption" namespace:"encryption" description:"Channel database encryption at rest settings."`
 }
 
 // DefaultDB creates and returns a new default DB config.
 func DefaultDB() *DB {
 	return &DB{
-		Backend:             BoltBackend,
-		BatchCommitInterval: DefaultBatchCommitInterval,
+		Backend:                BoltBackend,
+		BatchCommitInterval:    DefaultBatchCommitInterval,
+		ClosedChanReapInterval: DefaultClosedChanReapInterval,
 		Bolt: &kvdb.BoltConfig{
 			NoFreelistSync:    true,
 			AutoCompactMinAge: kvdb.DefaultBoltAutoCompactMinAge,
 			DBTimeout:         kvdb.DefaultDBTimeout,
+			SnapshotsToKeep:   kvdb.DefaultSnapshotsToKeep,
 		},
 		Etcd: &etcd.Config{
 			// Allow at most 32 MiB messages by default.
@@ -116,6 +135,7 @@ func DefaultDB() *DB {
 			BusyTimeout:    defaultSqliteBusyTimeout,
 		},
 		UseNativeSQL: false,
+		Encryption:   &DBEncryption{},
 	}
 }
 
@@ -128,6 +148,16 @@ func (db *DB) Validate() error {
 				"backend")
 		}
 
+		if db.Bolt.SnapshotInterval < 0 {
+			return fmt.Errorf("bolt.snapshot-interval must not " +
+				"be negative")
+		}
+
+		if db.Bolt.SnapshotsToKeep < 0 {
+			return fmt.Errorf("bolt.snapshots-to-keep must not " +
+				"be negative")
+		}
+
 	case SqliteBackend:
 	case PostgresBackend:
 		if err := db.Postgres.Validate(); err != nil {
@@ -158,12 +188,22 @@ func (db *DB) Validate() error {
 	// files), we can keep the graph in memory instead. But for mobile
 	// devices the tradeoff between a smaller memory footprint and the
 	// longer time needed for path finding might be a desirable one.
+	if db.UseNativeSQLGraph && !db.UseNativeSQL {
+		return fmt.Errorf("use-native-sql-graph requires " +
+			"use-native-sql")
+	}
+
 	if db.NoGraphCache && db.Backend != BoltBackend {
 		return fmt.Errorf("cannot use no-graph-cache with database "+
 			"backend '%v'", db.Backend)
 	}
 
-	return nil
+	if db.ClosedChanRetention > 0 && db.ClosedChanReapInterval <= 0 {
+		return fmt.Errorf("closed-chan-reap-interval must be " +
+			"positive")
+	}
+
+	return db.Encryption.Validate(db.Backend)
 }
 
 // Init should be called upon start to pre-initialize database access dependent
@@ -243,6 +283,11 @@ type DatabaseBackends struct {
 	// CloseFuncs is a map of close functions for each of the initialized
 	// DB backends keyed by their namespace name.
 	CloseFuncs map[string]func() error
+
+	// BoltDBs are the local bolt databases keyed by their file name. They
+	// are used to take online snapshots of the databases. This is nil if a
+	// remote database backend is used.
+	BoltDBs map[string]kvdb.Backend
 }
 
 // GetPostgresConfigKVDB converts a sqldb.PostgresConfig to a kvdb
@@ -632,6 +677,32 @@ func (db *DB) GetBackends(ctx context.Context, chanDBPath,
 	}
 	closeFuncs[NSChannelDB] = boltBackend.Close
 
+	// If enabled, all values of the channel DB are transparently
+	// encrypted. An encrypted channel DB can't be opened without its key,
+	// as its values would be read as plaintext.
+	var chanDBBackend kvdb.Backend = boltBackend
+	if db.Encryption.Enable {
+		key, err := db.Encryption.Key()
+		if err != nil {
+			return nil, err
+		}
+
+		chanDBBackend, err = encrypteddb.New(boltBackend, key)
+		if err != nil {
+			return nil, fmt.Errorf("error opening encrypted bolt "+
+				"DB: %w", err)
+		}
+	} else {
+		encrypted, err := encrypteddb.IsEncrypted(boltBackend)
+		if err != nil {
+			return nil, err
+		}
+		if encrypted {
+			return nil, fmt.Errorf("channel DB is encrypted, " +
+				"db.encryption.enable must be set")
+		}
+	}
+
 	macaroonBackend, err := kvdb.GetBoltBackend(&kvdb.BoltBackendConfig{
 		DBPath:            walletDBPath,
 		DBFileName:        MacaroonDBName,
@@ -700,12 +771,26 @@ func (db *DB) GetBackends(ctx context.Context, chanDBPath,
 		closeFuncs[NSTowerServerDB] = towerServerBackend.Close
 	}
 
+	// We snapshot the raw bolt backends, so an encrypted channel DB stays
+	// encrypted in its snapshots.
+	boltDBs := map[string]kvdb.Backend{
+		ChannelDBName:    boltBackend,
+		MacaroonDBName:   macaroonBackend,
+		DecayedLogDbName: decayedLogBackend,
+	}
+	if towerClientBackend != nil {
+		boltDBs[TowerClientDBName] = towerClientBackend
+	}
+	if towerServerBackend != nil {
+		boltDBs[TowerServerDBName] = towerServerBackend
+	}
+
 	returnEarly = false
 
 	return &DatabaseBackends{
-		GraphDB:       boltBackend,
-		ChanStateDB:   boltBackend,
-		HeightHintDB:  boltBackend,
+		GraphDB:       chanDBBackend,
+		ChanStateDB:   chanDBBackend,
+		HeightHintDB:  chanDBBackend,
 		MacaroonDB:    macaroonBackend,
 		DecayedLogDB:  decayedLogBackend,
 		TowerClientDB: towerClientBackend,
@@ -719,6 +804,7 @@ func (db *DB) GetBackends(ctx context.Context, chanDBPath,
 			walletDBPath, db.Bolt.NoFreelistSync, db.Bolt.DBTimeout,
 		),
 		CloseFuncs: closeFuncs,
+		BoltDBs:    boltDBs,
 	}, nil
 }
 
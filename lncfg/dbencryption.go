@@ -0,0 +1,94 @@
+package lncfg
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/lightningnetwork/lnd/channeldb/encrypteddb"
+)
+
+// DBEncryption holds the configuration for the encryption at rest of the
+// channel database.
+//
+//nolint:lll
+type DBEncryption struct {
+	Enable bool `long:"enable" description:"Encrypt all values stored in the channel database (channel state, payments, invoices and graph). Only supported for the bolt backend, and only for new databases."`
+
+	KeyFile string `long:"keyfile" description:"Path to a file containing the hex encoded 32-byte encryption key."`
+
+	KeyCommand string `long:"keycommand" description:"Command that is executed to retrieve the hex encoded 32-byte encryption key from its standard output, e.g. to fetch it from an external key management service."`
+}
+
+// Validate checks that the encryption config is consistent.
+func (e *DBEncryption) Validate(backend string) error {
+	if !e.Enable {
+		return nil
+	}
+
+	if backend != BoltBackend {
+		return fmt.Errorf("database encryption is only supported "+
+			"with the %v backend", BoltBackend)
+	}
+
+	switch {
+	case e.KeyFile == "" && e.KeyCommand == "":
+		return errors.New("database encryption requires either a " +
+			"key file or a key command")
+
+	case e.KeyFile != "" && e.KeyCommand != "":
+		return errors.New("database encryption key file and key " +
+			"command are mutually exclusive")
+	}
+
+	return nil
+}
+
+// Key retrieves the encryption key from the configured key file or key
+// command.
+func (e *DBEncryption) Key() ([]byte, error) {
+	var (
+		encoded []byte
+		err     error
+	)
+	switch {
+	case e.KeyFile != "":
+		encoded, err = os.ReadFile(CleanAndExpandPath(e.KeyFile))
+		if err != nil {
+			return nil, fmt.Errorf("unable to read database "+
+				"encryption key file: %w", err)
+		}
+
+	default:
+		args := strings.Fields(e.KeyCommand)
+		if len(args) == 0 {
+			return nil, errors.New("empty database encryption " +
+				"key command")
+		}
+
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Stderr = os.Stderr
+		encoded, err = cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("unable to execute database "+
+				"encryption key command: %w", err)
+		}
+	}
+
+	key, err := hex.DecodeString(string(bytes.TrimSpace(encoded)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid database encryption key: %w",
+			err)
+	}
+
+	if len(key) != encrypteddb.KeySize {
+		return nil, fmt.Errorf("database encryption key must be %d "+
+			"bytes, got %d", encrypteddb.KeySize, len(key))
+	}
+
+	return key, nil
+}
@@ -0,0 +1,31 @@
+package lncfg
+
+import (
+	"fmt"
+	"time"
+)
+
+// Electrum holds the configuration options for the Electrum server that can
+// be used as the chain notifier of the neutrino backend.
+//
+//nolint:lll
+type Electrum struct {
+	Server         string        `long:"server" description:"The host:port of an Electrum server that is used instead of neutrino to watch for new blocks, confirmations and spends. Only supported with bitcoin.node=neutrino, which is still used by the wallet and to sync the channel graph"`
+	NoTLS          bool          `long:"notls" description:"Connect to the Electrum server without TLS"`
+	TLSCertPath    string        `long:"tlscertpath" description:"Path to the TLS certificate of the Electrum server, if it isn't signed by a trusted certificate authority"`
+	RequestTimeout time.Duration `long:"requesttimeout" description:"The time to wait for the Electrum server to answer a request"`
+}
+
+// Validate checks the values configured for the Electrum server.
+func (e *Electrum) Validate() error {
+	if e.RequestTimeout < 0 {
+		return fmt.Errorf("requesttimeout must not be negative")
+	}
+
+	if e.NoTLS && e.TLSCertPath != "" {
+		return fmt.Errorf("notls and tlscertpath are mutually " +
+			"exclusive")
+	}
+
+	return nil
+}
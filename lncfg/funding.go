@@ -0,0 +1,85 @@
+package lncfg
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnwallet"
+)
+
+const (
+	// DefaultMinRemoteDelay is the default minimum CSV delay we require the
+	// remote to use for its commitment transaction.
+	DefaultMinRemoteDelay = 144
+
+	// DefaultMaxRemoteDelay is the default maximum CSV delay we require the
+	// remote to use for its commitment transaction.
+	DefaultMaxRemoteDelay = 2016
+)
+
+// Funding holds the bounds the funding manager enforces on the commitment
+// parameters negotiated when opening or accepting channels.
+//
+//nolint:lll
+type Funding struct {
+	MinRemoteDelay uint16 `long:"min-remote-delay" description:"The smallest CSV delay we require the remote party to use for its commitment. Unless bitcoin.defaultremotedelay is set, the delay we propose is scaled linearly with the channel size between min-remote-delay and max-remote-delay."`
+
+	MaxRemoteDelay uint16 `long:"max-remote-delay" description:"The largest CSV delay we require the remote party to use for its commitment. This value is proposed for channels of wumbo size and above."`
+
+	MinMaxAcceptedHtlcs uint16 `long:"min-max-accepted-htlcs" description:"The smallest number of concurrent HTLCs the remote party must allow us to add to its commitment. Channels proposing a lower max_accepted_htlcs are rejected."`
+
+	MaxReservePercent uint8 `long:"max-reserve-percent" description:"The largest channel reserve the remote party may require us to keep, as a percentage of the channel capacity. Channels proposing a larger reserve are rejected."`
+
+	MaxDustLimit btcutil.Amount `long:"max-dust-limit" description:"The largest dust limit in satoshis the remote party may use for our commitment. Channels proposing a larger dust limit are rejected. If 0, three times the dust limit of the largest witness script is used."`
+}
+
+// DefaultFunding returns the default funding bounds config.
+func DefaultFunding() *Funding {
+	return &Funding{
+		MinRemoteDelay:      DefaultMinRemoteDelay,
+		MaxRemoteDelay:      DefaultMaxRemoteDelay,
+		MinMaxAcceptedHtlcs: lnwallet.DefaultMinMaxAcceptedHtlcs,
+		MaxReservePercent:   lnwallet.DefaultMaxChanReservePercent,
+	}
+}
+
+// NegotiationBounds returns the bounds we enforce on the commitment
+// parameters the remote party dictates for our commitment.
+func (f *Funding) NegotiationBounds() *lnwallet.NegotiationBounds {
+	return &lnwallet.NegotiationBounds{
+		MinMaxAcceptedHtlcs:   f.MinMaxAcceptedHtlcs,
+		MaxChanReservePercent: f.MaxReservePercent,
+		MaxDustLimit:          f.MaxDustLimit,
+	}
+}
+
+// Validate checks that the funding bounds are sane.
+//
+// NOTE: this is part of the Validator interface.
+func (f *Funding) Validate() error {
+	if f.MinRemoteDelay == 0 {
+		return fmt.Errorf("funding.min-remote-delay must be positive")
+	}
+
+	if f.MinRemoteDelay > f.MaxRemoteDelay {
+		return fmt.Errorf("funding.min-remote-delay (%d) must not "+
+			"exceed funding.max-remote-delay (%d)",
+			f.MinRemoteDelay, f.MaxRemoteDelay)
+	}
+
+	if f.MinMaxAcceptedHtlcs == 0 {
+		return fmt.Errorf("funding.min-max-accepted-htlcs must be " +
+			"positive")
+	}
+
+	if f.MaxReservePercent == 0 {
+		return fmt.Errorf("funding.max-reserve-percent must be " +
+			"positive")
+	}
+
+	if err := f.NegotiationBounds().Validate(); err != nil {
+		return fmt.Errorf("invalid funding bounds: %w", err)
+	}
+
+	return nil
+}
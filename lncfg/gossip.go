@@ -17,10 +17,29 @@ type Gossip struct {
 
 	ChannelUpdateInterval time.Duration `long:"channel-update-interval" description:"The interval used to determine how often lnd should allow a burst of new updates for a specific channel and direction."`
 
+	InitialSyncPeers int `long:"initial-sync-peers" description:"The number of peers the initial graph sync is split across. Each block range is queried from two of them and their answers are reconciled, so the sync is faster and doesn't rely on a single peer. A value of 1 performs the initial graph sync with a single peer."`
+
 	SubBatchDelay time.Duration `long:"sub-batch-delay" description:"The duration to wait before sending the next announcement batch if there are multiple. Use a small value if there are a lot announcements and they need to be broadcast quickly."`
+
+	EgressRateBytes uint64 `long:"egress-rate-bytes" description:"The maximum number of bytes per second of gossip that lnd proactively sends to all of its peers combined. Replies to gossip queries aren't counted. A value of 0 disables the limit."`
+
+	EgressBurstBytes uint64 `long:"egress-burst-bytes" description:"The number of bytes of gossip that may be sent to all peers at once before egress-rate-bytes kicks in. Must be at least 65533 if the limit is enabled."`
+
+	PeerEgressRateBytes uint64 `long:"peer-egress-rate-bytes" description:"The maximum number of bytes per second of gossip that lnd proactively sends to a single peer. Replies to gossip queries aren't counted. A value of 0 disables the limit."`
+
+	PeerEgressBurstBytes uint64 `long:"peer-egress-burst-bytes" description:"The number of bytes of gossip that may be sent to a single peer at once before peer-egress-rate-bytes kicks in. Must be at least 65533 if the limit is enabled."`
+
+	PassivePeersRaw []string `long:"passive-peers" description:"A set of gossip-passive peers that lnd never proactively floods gossip to. Their gossip queries are still answered. The value should be a hex-encoded pubkey, the flag can be specified multiple times to add multiple peers."`
+
+	PassivePeers []route.Vertex
+
+	StrictValidation bool `long:"strict-validation" description:"Drop gossip messages with internally inconsistent fields, such as channel updates whose htlc_minimum_msat exceeds htlc_maximum_msat or whose fees exceed the total supply, right after decoding them instead of passing them to the gossiper."`
+
+	Bridge bool `long:"bridge" description:"Enable the gossip bridge, which exports all accepted gossip announcements and allows injecting externally obtained announcements. Only allowed on regtest, simnet and signet."`
 }
 
-// Parse the pubkeys for the pinned syncers.
+// Parse the pubkeys for the pinned syncers and passive peers, and validate the
+// gossip egress limits.
 func (g *Gossip) Parse() error {
 	pinnedSyncers := make(discovery.PinnedSyncers)
 	for _, pubkeyStr := range g.PinnedSyncersRaw {
@@ -33,5 +52,30 @@ func (g *Gossip) Parse() error {
 
 	g.PinnedSyncers = pinnedSyncers
 
-	return nil
+	passivePeers := make([]route.Vertex, 0, len(g.PassivePeersRaw))
+	for _, pubkeyStr := range g.PassivePeersRaw {
+		vertex, err := route.NewVertexFromStr(pubkeyStr)
+		if err != nil {
+			return err
+		}
+		passivePeers = append(passivePeers, vertex)
+	}
+
+	g.PassivePeers = passivePeers
+
+	egressCfg := g.EgressConfig()
+
+	return egressCfg.Validate()
+}
+
+// EgressConfig returns the limits of the gossip we proactively send to our
+// peers.
+func (g *Gossip) EgressConfig() discovery.EgressConfig {
+	return discovery.EgressConfig{
+		MaxBytesPerSec:  g.EgressRateBytes,
+		MaxBurstBytes:   g.EgressBurstBytes,
+		PeerBytesPerSec: g.PeerEgressRateBytes,
+		PeerBurstBytes:  g.PeerEgressBurstBytes,
+		PassivePeers:    g.PassivePeers,
+	}
 }
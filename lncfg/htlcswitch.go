@@ -2,7 +2,11 @@ package lncfg
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
 )
 
 var (
@@ -16,6 +20,57 @@ var (
 //nolint:lll
 type Htlcswitch struct {
 	MailboxDeliveryTimeout time.Duration `long:"mailboxdeliverytimeout" description:"The timeout value when delivering HTLCs to a channel link. Setting this value too small will result in local payment failures if large number of payments are sent over a short period."`
+
+	MaxHeldHtlcMemory uint64 `long:"maxheldhtlcmemory" description:"The maximum number of bytes that HTLCs held by an HTLC interceptor may occupy in memory. Once it is exceeded, new HTLCs with the lowest value are failed back before they are offered to the interceptor. HTLCs that the interceptor has seen already are never failed back. Set to 0 to disable the limit."`
+
+	OutgoingCltvRejectDelta uint32 `long:"outgoingcltvrejectdelta" description:"The number of blocks before the expiry of an outgoing HTLC at which we no longer offer it to the next peer and fail it back instead. A larger value leaves more room for a slow round trip to the next peer, a smaller value accepts forwards with tighter expiries."`
+
+	ChanOutgoingCltvRejectDelta []string `long:"chanoutgoingcltvrejectdelta" description:"Overrides outgoingcltvrejectdelta for a single channel, in the format <scid>:<blocks> where scid is the short channel ID in its integer form. Can be specified multiple times."`
+
+	LocalHtlcSlotReserve float64 `long:"localhtlcslotreserve" description:"The fraction of the HTLC slots in each direction of a channel that is reserved for payments we send or receive. Forwards that would use a reserved slot are failed back, so heavy forwarding traffic can't prevent us from sending or receiving payments. Must be below 1. Set to 0 to disable the reservation."`
+
+	HtlcChainCostMultiplier float64 `long:"htlcchaincostmultiplier" description:"Fail forwards whose value is below this multiple of the worst-case on-chain cost of resolving the outgoing HTLC, i.e. the fees of its commitment output, the second-level timeout transaction and the sweep at the current network fee rate. Such HTLCs aren't worth the fees if they force a channel close. Set to 0 to disable the check."`
+}
+
+// ChanOutgoingCltvRejectDeltas parses the per-channel outgoing cltv reject
+// delta overrides.
+func (h *Htlcswitch) ChanOutgoingCltvRejectDeltas() (
+	map[lnwire.ShortChannelID]uint32, error) {
+
+	deltas := make(
+		map[lnwire.ShortChannelID]uint32,
+		len(h.ChanOutgoingCltvRejectDelta),
+	)
+	for _, override := range h.ChanOutgoingCltvRejectDelta {
+		parts := strings.Split(override, ":")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid channel outgoing cltv "+
+				"reject delta %q, expected <scid>:<blocks>",
+				override)
+		}
+
+		scid, err := strconv.ParseUint(parts[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid short channel id "+
+				"in %q: %w", override, err)
+		}
+
+		delta, err := strconv.ParseUint(parts[1], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid delta in %q: %w",
+				override, err)
+		}
+
+		chanID := lnwire.NewShortChanIDFromInt(scid)
+		if _, ok := deltas[chanID]; ok {
+			return nil, fmt.Errorf("duplicate channel outgoing "+
+				"cltv reject delta for %v", scid)
+		}
+
+		deltas[chanID] = uint32(delta)
+	}
+
+	return deltas, nil
 }
 
 // Validate checks the values configured for htlcswitch.
@@ -30,5 +85,36 @@ func (h *Htlcswitch) Validate() error {
 			MaxMailboxDeliveryTimeout)
 	}
 
+	// The reject delta must keep outgoing htlcs out of the broadcast
+	// window, otherwise we would offer htlcs that immediately cause us to
+	// force close the channel.
+	minDelta := uint32(DefaultOutgoingBroadcastDelta + 1)
+	if h.OutgoingCltvRejectDelta < minDelta {
+		return fmt.Errorf("outgoingcltvrejectdelta must be at "+
+			"least %v", minDelta)
+	}
+
+	if h.LocalHtlcSlotReserve < 0 || h.LocalHtlcSlotReserve >= 1 {
+		return fmt.Errorf("localhtlcslotreserve must be in the range "+
+			"[0, 1), got %v", h.LocalHtlcSlotReserve)
+	}
+
+	if h.HtlcChainCostMultiplier < 0 {
+		return fmt.Errorf("htlcchaincostmultiplier must not be "+
+			"negative, got %v", h.HtlcChainCostMultiplier)
+	}
+
+	deltas, err := h.ChanOutgoingCltvRejectDeltas()
+	if err != nil {
+		return err
+	}
+	for chanID, delta := range deltas {
+		if delta < minDelta {
+			return fmt.Errorf("chanoutgoingcltvrejectdelta of "+
+				"channel %v must be at least %v", chanID,
+				minDelta)
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,44 @@
+package lncfg
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHtlcswitchCltvRejectDelta tests the validation of the global and
+// per-channel outgoing cltv reject deltas.
+func TestHtlcswitchCltvRejectDelta(t *testing.T) {
+	t.Parallel()
+
+	newConfig := func(overrides ...string) *Htlcswitch {
+		return &Htlcswitch{
+			MailboxDeliveryTimeout:      MaxMailboxDeliveryTimeout,
+			OutgoingCltvRejectDelta:     3,
+			ChanOutgoingCltvRejectDelta: overrides,
+		}
+	}
+
+	cfg := newConfig("123:6", "456:1")
+	require.NoError(t, cfg.Validate())
+
+	deltas, err := cfg.ChanOutgoingCltvRejectDeltas()
+	require.NoError(t, err)
+	require.Equal(t, map[lnwire.ShortChannelID]uint32{
+		lnwire.NewShortChanIDFromInt(123): 6,
+		lnwire.NewShortChanIDFromInt(456): 1,
+	}, deltas)
+
+	cfg = newConfig()
+	cfg.OutgoingCltvRejectDelta = 0
+	require.Error(t, cfg.Validate())
+
+	for _, override := range []string{
+		"123", "123:6:1", "abc:6", "123:-1", "123:0",
+	} {
+		require.Error(t, newConfig(override).Validate(), override)
+	}
+
+	require.Error(t, newConfig("123:6", "123:7").Validate())
+}
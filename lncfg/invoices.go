@@ -1,5 +1,7 @@
 package lncfg
 
+import "errors"
+
 const (
 	// DefaultHoldInvoiceExpiryDelta defines the number of blocks before the
 	// expiry height of a hold invoice's htlc that lnd will automatically
@@ -32,6 +34,11 @@ const (
 	// used to decrease certain blinded hop policy values in order to add a
 	// probing buffer.
 	DefaultBlindedPathPolicyDecreaseMultiplier = 0.9
+
+	// DefaultOverpaymentPolicy is the default node wide policy for
+	// payments that pay more than the invoice amount. Overpayments of any
+	// amount are accepted.
+	DefaultOverpaymentPolicy = "accept"
 )
 
 // Invoices holds the configuration options for invoices.
@@ -39,6 +46,12 @@ const (
 //nolint:lll
 type Invoices struct {
 	HoldExpiryDelta uint32 `long:"holdexpirydelta" description:"The number of blocks before a hold invoice's htlc expires that the invoice should be canceled to prevent a force close. Force closes will not be prevented if this value is not greater than DefaultIncomingBroadcastDelta."`
+
+	DeterministicPreimages bool `long:"deterministic-preimages" description:"Derive the preimages of new invoices from the wallet seed and their payment address instead of generating them randomly. This allows a node restored from seed to settle invoices it issued before, given their payment requests."`
+
+	OverpaymentPolicy string `long:"overpayment-policy" choice:"accept" choice:"limit" choice:"reject" description:"How payments that pay more than the invoice amount are handled, unless an invoice sets its own policy. 'accept' accepts overpayments of any amount, 'limit' accepts overpayments of up to max-overpayment-percent of the invoice amount and 'reject' rejects any overpayment."`
+
+	MaxOverpaymentPercent uint32 `long:"max-overpayment-percent" description:"The maximum overpayment that is accepted with the 'limit' overpayment policy, as a percentage of the invoice amount."`
 }
 
 // Validate checks that the various invoice config options are sane.
@@ -56,5 +69,10 @@ func (i *Invoices) Validate() error {
 			i.HoldExpiryDelta, DefaultIncomingBroadcastDelta)
 	}
 
+	if i.MaxOverpaymentPercent != 0 && i.OverpaymentPolicy != "limit" {
+		return errors.New("max-overpayment-percent can only be set " +
+			"with the limit overpayment policy")
+	}
+
 	return nil
 }
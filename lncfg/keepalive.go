@@ -0,0 +1,155 @@
+package lncfg
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+const (
+	// DefaultClearnetPingInterval is the default interval at which pings
+	// are sent to clearnet peers.
+	DefaultClearnetPingInterval = time.Minute
+
+	// DefaultClearnetPingTimeout is the default duration we wait for a
+	// pong from a clearnet peer.
+	DefaultClearnetPingTimeout = 30 * time.Second
+
+	// DefaultTorPingInterval is the default interval at which pings are
+	// sent to peers connected over Tor.
+	DefaultTorPingInterval = 3 * time.Minute
+
+	// DefaultTorPingTimeout is the default duration we wait for a pong
+	// from a peer connected over Tor.
+	DefaultTorPingTimeout = 90 * time.Second
+
+	// DefaultMaxPongBytes is the default upper bound of the random number
+	// of pong bytes requested with each ping.
+	DefaultMaxPongBytes = 4096
+
+	// DefaultTCPKeepaliveIdle is the default duration a connection needs
+	// to be idle before the first TCP keepalive probe is sent.
+	DefaultTCPKeepaliveIdle = 15 * time.Second
+
+	// DefaultTCPKeepaliveInterval is the default duration between
+	// unacknowledged TCP keepalive probes.
+	DefaultTCPKeepaliveInterval = 5 * time.Second
+
+	// DefaultTCPKeepaliveCount is the default number of unacknowledged
+	// TCP keepalive probes after which a connection is considered dead.
+	DefaultTCPKeepaliveCount = 3
+
+	// DefaultTCPUserTimeout is the default maximum duration transmitted
+	// data may remain unacknowledged before a connection is considered
+	// dead.
+	DefaultTCPUserTimeout = 30 * time.Second
+)
+
+// PeerPing holds the ping configuration of a class of peers.
+//
+//nolint:lll
+type PeerPing struct {
+	Interval time.Duration `long:"ping-interval" description:"The interval at which pings are sent to the peer."`
+
+	Timeout time.Duration `long:"ping-timeout" description:"The duration to wait for a pong before the peer is disconnected. Must be smaller than the ping interval."`
+
+	MaxPongBytes uint16 `long:"max-pong-bytes" description:"The upper bound of the random number of pong bytes requested with each ping. Larger values probe the connection with more traffic."`
+}
+
+// Validate checks the values configured for the ping configuration.
+func (p *PeerPing) Validate() error {
+	if p.Interval <= 0 {
+		return fmt.Errorf("ping-interval must be positive")
+	}
+
+	if p.Timeout <= 0 || p.Timeout >= p.Interval {
+		return fmt.Errorf("ping-timeout must be positive and smaller "+
+			"than ping-interval %v", p.Interval)
+	}
+
+	if p.MaxPongBytes == 0 || p.MaxPongBytes > lnwire.MaxPongBytes {
+		return fmt.Errorf("max-pong-bytes must be between 1 and %d",
+			lnwire.MaxPongBytes)
+	}
+
+	return nil
+}
+
+// Keepalive holds the configuration used to detect dead peer connections.
+//
+//nolint:lll
+type Keepalive struct {
+	Clearnet *PeerPing `group:"clearnet" namespace:"clearnet" description:"The ping configuration of clearnet peers."`
+
+	Tor *PeerPing `group:"tor" namespace:"tor" description:"The ping configuration of peers connected over Tor."`
+
+	TCPKeepaliveIdle time.Duration `long:"tcp-keepalive-idle" description:"The duration a peer connection needs to be idle before the first TCP keepalive probe is sent. Set to 0 to disable TCP keepalives."`
+
+	TCPKeepaliveInterval time.Duration `long:"tcp-keepalive-interval" description:"The duration between unacknowledged TCP keepalive probes. Only supported on Linux."`
+
+	TCPKeepaliveCount int `long:"tcp-keepalive-count" description:"The number of unacknowledged TCP keepalive probes after which a peer connection is considered dead. Only supported on Linux."`
+
+	TCPUserTimeout time.Duration `long:"tcp-user-timeout" description:"The maximum duration data sent to a peer may remain unacknowledged before the connection is considered dead. Set to 0 to use the system default. Only supported on Linux."`
+}
+
+// DefaultKeepaliveConfig returns the default keepalive configuration.
+func DefaultKeepaliveConfig() *Keepalive {
+	return &Keepalive{
+		Clearnet: &PeerPing{
+			Interval:     DefaultClearnetPingInterval,
+			Timeout:      DefaultClearnetPingTimeout,
+			MaxPongBytes: DefaultMaxPongBytes,
+		},
+		Tor: &PeerPing{
+			Interval:     DefaultTorPingInterval,
+			Timeout:      DefaultTorPingTimeout,
+			MaxPongBytes: DefaultMaxPongBytes,
+		},
+		TCPKeepaliveIdle:     DefaultTCPKeepaliveIdle,
+		TCPKeepaliveInterval: DefaultTCPKeepaliveInterval,
+		TCPKeepaliveCount:    DefaultTCPKeepaliveCount,
+		TCPUserTimeout:       DefaultTCPUserTimeout,
+	}
+}
+
+// Compile-time constraint to ensure Keepalive implements the Validator
+// interface.
+var _ Validator = (*Keepalive)(nil)
+
+// Validate checks the values configured for the keepalive configuration.
+//
+// NOTE: this is part of the Validator interface.
+func (k *Keepalive) Validate() error {
+	if err := k.Clearnet.Validate(); err != nil {
+		return fmt.Errorf("clearnet: %w", err)
+	}
+
+	if err := k.Tor.Validate(); err != nil {
+		return fmt.Errorf("tor: %w", err)
+	}
+
+	if k.TCPKeepaliveIdle < 0 {
+		return fmt.Errorf("tcp-keepalive-idle must not be negative")
+	}
+
+	if k.TCPKeepaliveIdle != 0 && k.TCPKeepaliveIdle < time.Second {
+		return fmt.Errorf("tcp-keepalive-idle must be at least 1s")
+	}
+
+	if k.TCPKeepaliveInterval != 0 &&
+		k.TCPKeepaliveInterval < time.Second {
+
+		return fmt.Errorf("tcp-keepalive-interval must be at least 1s")
+	}
+
+	if k.TCPKeepaliveCount < 0 {
+		return fmt.Errorf("tcp-keepalive-count must not be negative")
+	}
+
+	if k.TCPUserTimeout < 0 {
+		return fmt.Errorf("tcp-user-timeout must not be negative")
+	}
+
+	return nil
+}
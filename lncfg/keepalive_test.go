@@ -0,0 +1,79 @@
+package lncfg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestKeepaliveValidate tests the validation of the keepalive configuration.
+func TestKeepaliveValidate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		modify func(k *Keepalive)
+		errStr string
+	}{
+		{
+			name:   "defaults",
+			modify: func(k *Keepalive) {},
+		},
+		{
+			name: "timeout not below interval",
+			modify: func(k *Keepalive) {
+				k.Tor.Timeout = k.Tor.Interval
+			},
+			errStr: "tor: ping-timeout",
+		},
+		{
+			name: "zero pong bytes",
+			modify: func(k *Keepalive) {
+				k.Clearnet.MaxPongBytes = 0
+			},
+			errStr: "clearnet: max-pong-bytes",
+		},
+		{
+			name: "tcp keepalives disabled",
+			modify: func(k *Keepalive) {
+				k.TCPKeepaliveIdle = 0
+				k.TCPKeepaliveInterval = 0
+				k.TCPUserTimeout = 0
+			},
+		},
+		{
+			name: "sub-second keepalive interval",
+			modify: func(k *Keepalive) {
+				k.TCPKeepaliveInterval = time.Millisecond
+			},
+			errStr: "tcp-keepalive-interval",
+		},
+		{
+			name: "negative user timeout",
+			modify: func(k *Keepalive) {
+				k.TCPUserTimeout = -time.Second
+			},
+			errStr: "tcp-user-timeout",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := DefaultKeepaliveConfig()
+			tc.modify(cfg)
+
+			err := cfg.Validate()
+			if tc.errStr == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.ErrorContains(t, err, tc.errStr)
+		})
+	}
+}
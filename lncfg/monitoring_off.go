@@ -18,3 +18,10 @@ func DefaultPrometheus() Prometheus {
 func (p *Prometheus) Enabled() bool {
 	return false
 }
+
+// DBMetricsEnabled returns whether the database metrics should be exported.
+// Monitoring is currently disabled, so DBMetricsEnabled will always return
+// false.
+func (p *Prometheus) DBMetricsEnabled() bool {
+	return false
+}
@@ -21,6 +21,11 @@ type Prometheus struct {
 	// generates additional data, and consume more memory for the
 	// Prometheus server.
 	PerfHistograms bool `long:"perfhistograms" description:"enable additional histogram to track gRPC call processing performance (latency, etc)"`
+
+	// DBMetrics indicates if the transaction latencies and bucket sizes of
+	// the databases should be exported. Determining the bucket sizes
+	// requires periodically reading the entire databases.
+	DBMetrics bool `long:"dbmetrics" description:"export database transaction latencies and bucket sizes per subsystem"`
 }
 
 // DefaultPrometheus is the default configuration for the Prometheus metrics
@@ -37,3 +42,9 @@ func DefaultPrometheus() Prometheus {
 func (p *Prometheus) Enabled() bool {
 	return p.Enable
 }
+
+// DBMetricsEnabled returns whether the database metrics should be exported,
+// which requires Prometheus monitoring to be enabled.
+func (p *Prometheus) DBMetricsEnabled() bool {
+	return p.Enable && p.DBMetrics
+}
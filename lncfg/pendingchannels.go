@@ -0,0 +1,69 @@
+package lncfg
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
+)
+
+const (
+	// DefaultResponderConfTimeout is the default number of blocks we wait
+	// for the funding transaction of a channel we didn't initiate to
+	// confirm before forgetting the channel.
+	DefaultResponderConfTimeout = 2016
+
+	// MinReservationTimeout is the minimum idle time of a reservation
+	// before it is canceled.
+	MinReservationTimeout = time.Minute
+
+	// MinInitiatorConfTimeout is the minimum number of blocks we wait for
+	// the funding transaction of a channel we initiated to confirm before
+	// it can be abandoned.
+	MinInitiatorConfTimeout = 144
+)
+
+// PendingChannels holds the configuration for the timeouts of channels that
+// are still pending open.
+//
+//nolint:lll
+type PendingChannels struct {
+	ReservationTimeout time.Duration `long:"reservationtimeout" description:"The idle time after which a channel negotiation is canceled if the peer doesn't continue the funding flow (e.g. never sends funding_signed), releasing the UTXOs reserved for the funding transaction. Can't exceed the 10m the UTXOs are reserved for."`
+
+	ResponderConfTimeout uint32 `long:"responderconftimeout" description:"The number of blocks after which a pending channel we didn't initiate is forgotten if its funding transaction hasn't confirmed."`
+
+	InitiatorConfTimeout uint32 `long:"initiatorconftimeout" description:"The number of blocks after which a pending channel we initiated is abandoned if its funding transaction hasn't confirmed. The inputs of the funding transaction are released back to the wallet, and should be double spent to make sure the funding transaction can't confirm anymore. Only applies to channels funded by the internal wallet. Set to 0 to disable."`
+}
+
+// DefaultPendingChannels returns the default pending channel config.
+func DefaultPendingChannels() *PendingChannels {
+	return &PendingChannels{
+		ReservationTimeout:   chanfunding.DefaultReservationTimeout,
+		ResponderConfTimeout: DefaultResponderConfTimeout,
+	}
+}
+
+// Validate checks the values configured for the pending channel timeouts.
+func (p *PendingChannels) Validate() error {
+	if p.ReservationTimeout < MinReservationTimeout ||
+		p.ReservationTimeout > chanfunding.DefaultReservationTimeout {
+
+		return fmt.Errorf("pendingchannels.reservationtimeout must be "+
+			"between %v and %v", MinReservationTimeout,
+			chanfunding.DefaultReservationTimeout)
+	}
+
+	if p.ResponderConfTimeout == 0 {
+		return fmt.Errorf("pendingchannels.responderconftimeout must " +
+			"be positive")
+	}
+
+	if p.InitiatorConfTimeout != 0 &&
+		p.InitiatorConfTimeout < MinInitiatorConfTimeout {
+
+		return fmt.Errorf("pendingchannels.initiatorconftimeout must "+
+			"be 0 or at least %d blocks", MinInitiatorConfTimeout)
+	}
+
+	return nil
+}
@@ -37,6 +37,14 @@ type ProtocolOptions struct {
 	// the experimental taproot overlay chan type.
 	TaprootOverlayChans bool `long:"simple-taproot-overlay-chans" description:"if set, then lnd will create and accept requests for channels using the taproot overlay commitment type"`
 
+	// DualFunding should be set if we want to accept the negotiation of
+	// experimental dual funded channels initiated by our peers.
+	DualFunding bool `long:"dual-funding" description:"EXPERIMENTAL: if set, then lnd will signal the dual-fund feature bit and accept the interactive construction of dual funded channels initiated by peers. Channels are not opened yet, the negotiation is aborted once the funding transaction was constructed"`
+
+	// OnionMessages should be set if we want to relay and receive onion
+	// messages.
+	OnionMessages bool `long:"onion-messages" description:"EXPERIMENTAL: if set, then lnd will signal the onion messages feature bit, relay onion messages along blinded paths and accept onion messages addressed to it"`
+
 	// Anchors enables anchor commitments.
 	// TODO(halseth): transition itests to anchors instead!
 	Anchors bool `long:"anchors" description:"enable support for anchor commitments"`
@@ -9,6 +9,14 @@ const (
 	// DefaultRemoteSignerRPCTimeout is the default timeout that is used
 	// when forwarding a request to the remote signer through RPC.
 	DefaultRemoteSignerRPCTimeout = 5 * time.Second
+
+	// DefaultGossipSignConcurrency is the default maximum number of gossip
+	// messages that are sent to the remote signer concurrently.
+	DefaultGossipSignConcurrency = 8
+
+	// DefaultGossipSigCacheSize is the default number of gossip message
+	// signatures that are cached.
+	DefaultGossipSigCacheSize = 1000
 )
 
 // RemoteSigner holds the configuration options for a remote RPC signer.
@@ -21,6 +29,9 @@ type RemoteSigner struct {
 	TLSCertPath      string        `long:"tlscertpath" description:"The TLS certificate to use for establishing the remote signer's identity"`
 	Timeout          time.Duration `long:"timeout" description:"The timeout for connecting to and signing requests with the remote signer. Valid time units are {s, m, h}."`
 	MigrateWatchOnly bool          `long:"migrate-wallet-to-watch-only" description:"If a wallet with private key material already exists, migrate it into a watch-only wallet on first startup. WARNING: This cannot be undone! Make sure you have backed up your seed before you use this flag! All private keys will be purged from the wallet after first unlock with this flag!"`
+
+	GossipSignConcurrency int `long:"gossip-sign-concurrency" description:"The maximum number of gossip messages (channel updates, node announcements) that are sent to the remote signer to be signed concurrently."`
+	GossipSigCacheSize    int `long:"gossip-sig-cache-size" description:"The number of recent gossip message signatures that are cached to avoid repeated remote signer round trips for identical messages."`
 }
 
 // Validate checks the values configured for our remote RPC signer.
@@ -35,6 +46,18 @@ func (r *RemoteSigner) Validate() error {
 			time.Millisecond)
 	}
 
+	if r.GossipSignConcurrency < 1 {
+		return fmt.Errorf("remote signer: gossip sign concurrency "+
+			"of %d is invalid, must be positive",
+			r.GossipSignConcurrency)
+	}
+
+	if r.GossipSigCacheSize < 1 {
+		return fmt.Errorf("remote signer: gossip signature cache "+
+			"size of %d is invalid, must be positive",
+			r.GossipSigCacheSize)
+	}
+
 	if r.MigrateWatchOnly && !r.Enable {
 		return fmt.Errorf("remote signer: cannot turn on wallet " +
 			"migration to watch-only if remote signing is not " +
@@ -1,6 +1,23 @@
 package lncfg
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultFeeSuggestLookback is the default duration of forwarding
+	// history the fee suggester analyzes.
+	DefaultFeeSuggestLookback = 7 * 24 * time.Hour
+
+	// DefaultFeeSuggestMaxFeeRate is the default highest fee rate in
+	// parts per million the fee suggester suggests.
+	DefaultFeeSuggestMaxFeeRate = 5000
+
+	// DefaultFeeSuggestMaxChange is the default largest relative change of
+	// a fee rate the fee suggester suggests at once.
+	DefaultFeeSuggestMaxChange = 0.25
+)
 
 // Routing holds the configuration options for routing.
 //
@@ -11,6 +28,20 @@ type Routing struct {
 	StrictZombiePruning bool `long:"strictgraphpruning" description:"If true, then the graph will be pruned more aggressively for zombies. In practice this means that edges with a single stale edge will be considered a zombie."`
 
 	BlindedPaths BlindedPaths `group:"blinding" namespace:"blinding"`
+
+	FeeSuggest FeeSuggest `group:"feesuggest" namespace:"feesuggest"`
+}
+
+// FeeSuggest holds the configuration options for suggesting the fee rates of
+// our channels.
+//
+//nolint:lll
+type FeeSuggest struct {
+	AutoApplyInterval time.Duration `long:"auto-apply-interval" description:"The interval at which the suggested fee rates are automatically applied to our channels. Set to 0 to disable auto-applying."`
+	Lookback          time.Duration `long:"lookback" description:"The duration of forwarding history that is analyzed to suggest fee rates."`
+	MinFeeRate        uint32        `long:"min-fee-rate" description:"The lowest fee rate in parts per million that is suggested."`
+	MaxFeeRate        uint32        `long:"max-fee-rate" description:"The highest fee rate in parts per million that is suggested."`
+	MaxChange         float64       `long:"max-change" description:"The largest relative change of a channel's fee rate that is suggested at once, e.g. 0.25 for 25%."`
 }
 
 // BlindedPaths holds the configuration options for blinded path construction.
@@ -46,5 +77,25 @@ func (r *Routing) Validate() error {
 			"multiplier must be in the range (0,1]")
 	}
 
+	if r.FeeSuggest.AutoApplyInterval < 0 {
+		return fmt.Errorf("the fee suggestion auto-apply interval " +
+			"must not be negative")
+	}
+
+	if r.FeeSuggest.Lookback <= 0 {
+		return fmt.Errorf("the fee suggestion lookback must be " +
+			"positive")
+	}
+
+	if r.FeeSuggest.MinFeeRate > r.FeeSuggest.MaxFeeRate {
+		return fmt.Errorf("the fee suggestion min fee rate must not " +
+			"exceed the max fee rate")
+	}
+
+	if r.FeeSuggest.MaxChange <= 0 || r.FeeSuggest.MaxChange >= 1 {
+		return fmt.Errorf("the fee suggestion max change must be in " +
+			"the range (0,1)")
+	}
+
 	return nil
 }
@@ -0,0 +1,108 @@
+package lncfg
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/lightningnetwork/lnd/lnwallet/signpolicy"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+// SignPolicy holds the configuration of the policy that is enforced before
+// the internal signer signs away the funds of the wallet.
+//
+//nolint:lll
+type SignPolicy struct {
+	MaxSpend         int64         `long:"maxspend" description:"The maximum total amount in satoshis that may be sent to outputs not belonging to the wallet, including channel funding outputs, within the spend window. Set to 0 to disable the velocity limit."`
+	SpendWindow      time.Duration `long:"spendwindow" description:"The sliding time window over which the maxspend limit is enforced."`
+	AllowedAddresses []string      `long:"allowedaddress" description:"An address the funds of the wallet may be sent to. The flag can be specified multiple times. If none is set, funds may be sent to any address."`
+	AllowedPeers     []string      `long:"allowedpeer" description:"The hex-encoded pubkey of a node channels may be funded with. The flag can be specified multiple times. If none is set, channels may be funded with any node."`
+}
+
+// DefaultSignPolicy returns the default signing policy, which doesn't restrict
+// anything.
+func DefaultSignPolicy() *SignPolicy {
+	return &SignPolicy{
+		SpendWindow: 24 * time.Hour,
+	}
+}
+
+// Validate checks the values configured for the signing policy.
+func (s *SignPolicy) Validate() error {
+	if s == nil {
+		return nil
+	}
+
+	if s.MaxSpend < 0 {
+		return fmt.Errorf("signpolicy.maxspend must not be negative")
+	}
+
+	if s.MaxSpend > 0 && s.SpendWindow <= 0 {
+		return fmt.Errorf("signpolicy.spendwindow must be positive " +
+			"if signpolicy.maxspend is set")
+	}
+
+	for _, peer := range s.AllowedPeers {
+		if _, err := route.NewVertexFromStr(peer); err != nil {
+			return fmt.Errorf("invalid signpolicy.allowedpeer "+
+				"%v: %w", peer, err)
+		}
+	}
+
+	return nil
+}
+
+// Policy parses the configuration into the policy enforced by the signer. The
+// allowed addresses are decoded for the given network.
+func (s *SignPolicy) Policy(params *chaincfg.Params) (signpolicy.Policy,
+	error) {
+
+	var policy signpolicy.Policy
+	if s == nil {
+		return policy, nil
+	}
+
+	policy.MaxSpend = btcutil.Amount(s.MaxSpend)
+	policy.SpendWindow = s.SpendWindow
+
+	for _, addrStr := range s.AllowedAddresses {
+		addr, err := btcutil.DecodeAddress(addrStr, params)
+		if err != nil {
+			return policy, fmt.Errorf("invalid signpolicy."+
+				"allowedaddress %v: %w", addrStr, err)
+		}
+
+		if !addr.IsForNet(params) {
+			return policy, fmt.Errorf("signpolicy.allowedaddress "+
+				"%v is not for network %v", addrStr,
+				params.Name)
+		}
+
+		pkScript, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			return policy, fmt.Errorf("invalid signpolicy."+
+				"allowedaddress %v: %w", addrStr, err)
+		}
+
+		policy.AllowedDestinations = append(
+			policy.AllowedDestinations, pkScript,
+		)
+	}
+
+	for _, peerStr := range s.AllowedPeers {
+		peer, err := route.NewVertexFromStr(peerStr)
+		if err != nil {
+			return policy, fmt.Errorf("invalid signpolicy."+
+				"allowedpeer %v: %w", peerStr, err)
+		}
+
+		policy.AllowedCounterparties = append(
+			policy.AllowedCounterparties, peer,
+		)
+	}
+
+	return policy, policy.Validate()
+}
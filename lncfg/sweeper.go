@@ -26,7 +26,11 @@ type Sweeper struct {
 
 	NoDeadlineConfTarget uint32 `long:"nodeadlineconftarget" description:"The conf target to use when sweeping non-time-sensitive outputs. This is useful for sweeping outputs that are not time-sensitive, and can be swept at a lower fee rate."`
 
+	CommitRebroadcastInterval uint32 `long:"commitrebroadcastinterval" description:"The number of blocks after which an unconfirmed force close tx is rebroadcast and the budget used to CPFP it through its anchor is escalated by budget.anchorcpfpescalation. Set to 0 to disable periodic rebroadcasts."`
+
 	Budget *contractcourt.BudgetConfig `group:"sweeper.budget" namespace:"budget" long:"budget" description:"An optional config group that's used for the automatic sweep fee estimation. The Budget config gives options to limits ones fee exposure when sweeping unilateral close outputs and the fee rate calculated from budgets is capped at sweeper.maxfeerate. Check the budget config options for more details."`
+
+	ExtBroadcast *sweep.ExtBroadcastConfig `group:"sweeper.extbroadcast" namespace:"extbroadcast" long:"extbroadcast" description:"An optional config group that configures external services that sweep and force close transactions are additionally submitted to, e.g. multiple bitcoind nodes, public broadcast APIs or mining pool accelerators."`
 }
 
 // Validate checks the values configured for the sweeper.
@@ -55,6 +59,11 @@ func (s *Sweeper) Validate() error {
 		return fmt.Errorf("invalid budget config: %w", err)
 	}
 
+	// Validate the external broadcast configuration.
+	if err := s.ExtBroadcast.Validate(); err != nil {
+		return fmt.Errorf("invalid extbroadcast config: %w", err)
+	}
+
 	return nil
 }
 
@@ -63,6 +72,11 @@ func DefaultSweeperConfig() *Sweeper {
 	return &Sweeper{
 		MaxFeeRate:           sweep.DefaultMaxFeeRate,
 		NoDeadlineConfTarget: uint32(sweep.DefaultDeadlineDelta),
-		Budget:               contractcourt.DefaultBudgetConfig(),
+		CommitRebroadcastInterval: contractcourt.
+			DefaultCommitRebroadcastInterval,
+		Budget: contractcourt.DefaultBudgetConfig(),
+		ExtBroadcast: &sweep.ExtBroadcastConfig{
+			Timeout: sweep.DefaultExtBroadcastTimeout,
+		},
 	}
 }
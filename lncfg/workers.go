@@ -29,6 +29,9 @@ type Workers struct {
 
 	// Sig is the maximum number of concurrent sig pool workers.
 	Sig int `long:"sig" description:"Maximum number of concurrent sig pool workers. This number should be proportional to the number of CPUs on the host."`
+
+	// AutoTune enables the auto tuning of the read and write pool sizes.
+	AutoTune bool `long:"autotune" description:"If set, the read and write pools grow beyond their configured number of workers when tasks queue up, and shrink back once the load subsides. Each pool grows up to four times its configured size or four workers per CPU, whichever is larger."`
 }
 
 // Validate checks the Workers configuration to ensure that the input values are
@@ -31,6 +31,10 @@ type WtClient struct {
 	// MaxUpdates is the maximum number of updates to be backed up in a
 	// single tower sessions.
 	MaxUpdates uint16 `long:"max-updates" description:"The maximum number of updates to be backed up in a single session."`
+
+	// MaxInFlightUpdates is the maximum number of state updates that are
+	// sent to a tower before waiting for their acks.
+	MaxInFlightUpdates uint16 `long:"max-in-flight-updates" description:"The maximum number of state updates that are sent to a tower before waiting for their acks. Higher values reduce the number of round trips needed to back up states, which helps towers reached over tor."`
 }
 
 // DefaultWtClientCfg returns the WtClient config struct with some default
@@ -47,6 +51,7 @@ func DefaultWtClientCfg() *WtClient {
 		SessionCloseRange:  wtclient.DefaultSessionCloseRange,
 		MaxTasksInMemQueue: wtclient.DefaultMaxTasksInMemQueue,
 		MaxUpdates:         wtpolicy.DefaultMaxUpdates,
+		MaxInFlightUpdates: wtclient.DefaultMaxInFlightUpdates,
 	}
 }
 
@@ -70,6 +75,10 @@ func (c *WtClient) Validate() error {
 		return fmt.Errorf("session-close-range must be non-zero")
 	}
 
+	if c.MaxInFlightUpdates == 0 {
+		return fmt.Errorf("max-in-flight-updates must be non-zero")
+	}
+
 	return nil
 }
 
@@ -318,9 +318,11 @@ func Main(cfg *Config, lisCfg ListenerCfg, implCfg *ImplementationCfg,
 	}
 
 	// Create a new RPC interceptor that we'll add to the GRPC server. This
-	// will be used to log the API calls invoked on the GRPC server.
+	// will be used to log the API calls invoked on the GRPC server and to
+	// attach error codes to the errors they return.
 	interceptorChain := rpcperms.NewInterceptorChain(
 		rpcsLog, cfg.NoMacaroons, cfg.RPCMiddleware.Mandatory,
+		newRPCErrorCodeMapper(),
 	)
 	if err := interceptorChain.Start(); err != nil {
 		return mkErr("error starting interceptor chain: %v", err)
@@ -607,6 +609,18 @@ func Main(cfg *Config, lisCfg ListenerCfg, implCfg *ImplementationCfg,
 		return mkErr("unable to create server: %v", err)
 	}
 
+	// If Prometheus monitoring is enabled, export the stats of the htlcs
+	// held by the htlc interceptor.
+	if cfg.Prometheus.Enabled() {
+		err := monitoring.RegisterHeldHtlcMetrics(
+			server.interceptableSwitch.HeldHtlcStats,
+		)
+		if err != nil {
+			return mkErr("unable to register held htlc metrics: "+
+				"%v", err)
+		}
+	}
+
 	// Set up an autopilot manager from the current config. This will be
 	// used to manage the underlying autopilot agent, starting and stopping
 	// it at will.
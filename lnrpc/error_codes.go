@@ -0,0 +1,273 @@
+package lnrpc
+
+import (
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain is the domain of the errdetails.ErrorInfo that is attached to
+// all errors returned by lnd's RPC servers.
+const ErrorDomain = "lnd.lightning.network"
+
+// ErrorCode is a stable, machine readable code that identifies the reason an
+// RPC call failed. It is attached to every error returned by lnd's RPC servers
+// as the reason of an errdetails.ErrorInfo in the gRPC status details, so
+// clients don't need to match on error strings. Codes are never renamed or
+// reused, new codes may be added in future versions.
+type ErrorCode string
+
+const (
+	// ErrorCodeUnknown is used for errors that don't have a more specific
+	// code.
+	ErrorCodeUnknown ErrorCode = "UNKNOWN"
+
+	// ErrorCodeInvalidArgument indicates that the request contained an
+	// invalid or malformed argument.
+	ErrorCodeInvalidArgument ErrorCode = "INVALID_ARGUMENT"
+
+	// ErrorCodeNotFound indicates that a requested entity doesn't exist.
+	ErrorCodeNotFound ErrorCode = "NOT_FOUND"
+
+	// ErrorCodeAlreadyExists indicates that an entity that was to be
+	// created already exists.
+	ErrorCodeAlreadyExists ErrorCode = "ALREADY_EXISTS"
+
+	// ErrorCodePermissionDenied indicates that the caller isn't allowed to
+	// call the RPC.
+	ErrorCodePermissionDenied ErrorCode = "PERMISSION_DENIED"
+
+	// ErrorCodeUnauthenticated indicates that the caller didn't provide
+	// valid credentials.
+	ErrorCodeUnauthenticated ErrorCode = "UNAUTHENTICATED"
+
+	// ErrorCodeUnavailable indicates that the RPC can't be served right
+	// now, e.g. because lnd is starting up or shutting down.
+	ErrorCodeUnavailable ErrorCode = "UNAVAILABLE"
+
+	// ErrorCodeFailedPrecondition indicates that the RPC was rejected
+	// because the system isn't in the state required to execute it.
+	ErrorCodeFailedPrecondition ErrorCode = "FAILED_PRECONDITION"
+
+	// ErrorCodeDeadlineExceeded indicates that the RPC didn't complete in
+	// time.
+	ErrorCodeDeadlineExceeded ErrorCode = "DEADLINE_EXCEEDED"
+
+	// ErrorCodeCanceled indicates that the RPC was canceled by the caller.
+	ErrorCodeCanceled ErrorCode = "CANCELED"
+
+	// ErrorCodeUnimplemented indicates that the RPC isn't supported by
+	// this lnd build.
+	ErrorCodeUnimplemented ErrorCode = "UNIMPLEMENTED"
+
+	// ErrorCodeWalletNotCreated indicates that no wallet exists yet.
+	ErrorCodeWalletNotCreated ErrorCode = "WALLET_NOT_CREATED"
+
+	// ErrorCodeWalletLocked indicates that the wallet must be unlocked
+	// first.
+	ErrorCodeWalletLocked ErrorCode = "WALLET_LOCKED"
+
+	// ErrorCodeWalletAlreadyUnlocked indicates that the wallet was already
+	// unlocked.
+	ErrorCodeWalletAlreadyUnlocked ErrorCode = "WALLET_ALREADY_UNLOCKED"
+
+	// ErrorCodeServerStarting indicates that lnd is still starting up.
+	ErrorCodeServerStarting ErrorCode = "SERVER_STARTING"
+
+	// ErrorCodeInvoiceNotFound indicates that the referenced invoice
+	// doesn't exist.
+	ErrorCodeInvoiceNotFound ErrorCode = "INVOICE_NOT_FOUND"
+
+	// ErrorCodeInvoiceAlreadyExists indicates that an invoice with the
+	// same payment hash or payment address already exists.
+	ErrorCodeInvoiceAlreadyExists ErrorCode = "INVOICE_ALREADY_EXISTS"
+
+	// ErrorCodeInvoiceAlreadySettled indicates that the invoice was
+	// already settled.
+	ErrorCodeInvoiceAlreadySettled ErrorCode = "INVOICE_ALREADY_SETTLED"
+
+	// ErrorCodeInvoiceAlreadyCanceled indicates that the invoice was
+	// already canceled.
+	ErrorCodeInvoiceAlreadyCanceled ErrorCode = "INVOICE_ALREADY_CANCELED"
+
+	// ErrorCodeInvoiceInvalidState indicates that the invoice can't be
+	// moved to the requested state.
+	ErrorCodeInvoiceInvalidState ErrorCode = "INVOICE_INVALID_STATE"
+
+	// ErrorCodePaymentAlreadySucceeded indicates that the payment hash
+	// was already paid successfully.
+	ErrorCodePaymentAlreadySucceeded ErrorCode = "PAYMENT_ALREADY_SUCCEEDED"
+
+	// ErrorCodePaymentInFlight indicates that a payment to the same
+	// payment hash is still in flight.
+	ErrorCodePaymentInFlight ErrorCode = "PAYMENT_IN_FLIGHT"
+
+	// ErrorCodePaymentAlreadyExists indicates that a payment to the same
+	// payment hash already exists.
+	ErrorCodePaymentAlreadyExists ErrorCode = "PAYMENT_ALREADY_EXISTS"
+
+	// ErrorCodeChannelNotFound indicates that the referenced channel
+	// doesn't exist.
+	ErrorCodeChannelNotFound ErrorCode = "CHANNEL_NOT_FOUND"
+
+	// ErrorCodeChannelAlreadyExists indicates that a channel with the same
+	// outpoint already exists.
+	ErrorCodeChannelAlreadyExists ErrorCode = "CHANNEL_ALREADY_EXISTS"
+
+	// ErrorCodeNodeNotFound indicates that the referenced node isn't
+	// known.
+	ErrorCodeNodeNotFound ErrorCode = "NODE_NOT_FOUND"
+
+	// ErrorCodeEdgeNotFound indicates that the referenced channel isn't
+	// part of the channel graph.
+	ErrorCodeEdgeNotFound ErrorCode = "EDGE_NOT_FOUND"
+
+	// ErrorCodeInsufficientFunds indicates that the wallet doesn't have
+	// enough funds to complete the request.
+	ErrorCodeInsufficientFunds ErrorCode = "INSUFFICIENT_FUNDS"
+
+	// ErrorCodeFundingPolicyViolation indicates that the parameters of a
+	// channel to be opened violate the policy of us or our peer.
+	ErrorCodeFundingPolicyViolation ErrorCode = "FUNDING_POLICY_VIOLATION"
+
+	// ErrorCodeFundingTimeout indicates that the funding transaction of a
+	// channel didn't confirm in time.
+	ErrorCodeFundingTimeout ErrorCode = "FUNDING_TIMEOUT"
+
+	// ErrorCodeInvalidRouteRequest indicates that the parameters of a
+	// payment or route request contradict each other.
+	ErrorCodeInvalidRouteRequest ErrorCode = "INVALID_ROUTE_REQUEST"
+
+	// ErrorCodeShuttingDown indicates that lnd or one of its subsystems is
+	// shutting down.
+	ErrorCodeShuttingDown ErrorCode = "SHUTTING_DOWN"
+)
+
+// grpcErrorCodes maps gRPC status codes to the generic error code that is
+// used if an error doesn't have a more specific code.
+var grpcErrorCodes = map[codes.Code]ErrorCode{
+	codes.InvalidArgument:    ErrorCodeInvalidArgument,
+	codes.OutOfRange:         ErrorCodeInvalidArgument,
+	codes.NotFound:           ErrorCodeNotFound,
+	codes.AlreadyExists:      ErrorCodeAlreadyExists,
+	codes.PermissionDenied:   ErrorCodePermissionDenied,
+	codes.Unauthenticated:    ErrorCodeUnauthenticated,
+	codes.Unavailable:        ErrorCodeUnavailable,
+	codes.FailedPrecondition: ErrorCodeFailedPrecondition,
+	codes.DeadlineExceeded:   ErrorCodeDeadlineExceeded,
+	codes.Canceled:           ErrorCodeCanceled,
+	codes.Unimplemented:      ErrorCodeUnimplemented,
+}
+
+// ErrorMapping maps an internal error to its error code and the gRPC status
+// code that is returned to the client.
+type ErrorMapping struct {
+	// Match returns true if the error is covered by this mapping.
+	Match func(error) bool
+
+	// Code is the error code attached to matching errors.
+	Code ErrorCode
+
+	// GRPCCode is the gRPC status code of matching errors. It is only used
+	// if the error doesn't already carry a gRPC status.
+	GRPCCode codes.Code
+}
+
+// MatchError returns an ErrorMapping for errors that wrap target.
+func MatchError(target error, code ErrorCode,
+	grpcCode codes.Code) ErrorMapping {
+
+	return ErrorMapping{
+		Match: func(err error) bool {
+			return errors.Is(err, target)
+		},
+		Code:     code,
+		GRPCCode: grpcCode,
+	}
+}
+
+// ErrorCodeMapper attaches error codes to the errors returned by RPC calls.
+type ErrorCodeMapper struct {
+	mappings []ErrorMapping
+}
+
+// NewErrorCodeMapper creates a new ErrorCodeMapper with the given mappings.
+// Mappings are checked in order, the first matching one is used.
+func NewErrorCodeMapper(mappings ...ErrorMapping) *ErrorCodeMapper {
+	return &ErrorCodeMapper{
+		mappings: mappings,
+	}
+}
+
+// AttachCode returns a gRPC status error that carries the error code of the
+// given error in its details. The message of the error is preserved, as is
+// its gRPC status code if it has one. Errors that already carry an error
+// code are returned unchanged.
+func (m *ErrorCodeMapper) AttachCode(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := ErrorCodeFromError(err); ok {
+		return err
+	}
+
+	code := ErrorCodeUnknown
+	grpcStatus, isStatus := status.FromError(err)
+
+	var matched bool
+	for _, mapping := range m.mappings {
+		if !mapping.Match(err) {
+			continue
+		}
+
+		code = mapping.Code
+		if !isStatus {
+			grpcStatus = status.New(mapping.GRPCCode, err.Error())
+		}
+		matched = true
+
+		break
+	}
+
+	// Errors without a specific mapping get the generic code of their gRPC
+	// status code. Errors that aren't a gRPC status were already converted
+	// to one with the Unknown code by status.FromError.
+	if !matched {
+		if c, ok := grpcErrorCodes[grpcStatus.Code()]; ok {
+			code = c
+		}
+	}
+
+	withDetails, detailsErr := grpcStatus.WithDetails(&errdetails.ErrorInfo{
+		Reason: string(code),
+		Domain: ErrorDomain,
+	})
+	if detailsErr != nil {
+		return err
+	}
+
+	return withDetails.Err()
+}
+
+// ErrorCodeFromError extracts the error code from an error returned by an
+// RPC call. False is returned if the error doesn't carry an error code.
+func ErrorCodeFromError(err error) (ErrorCode, bool) {
+	grpcStatus, ok := status.FromError(err)
+	if !ok {
+		return "", false
+	}
+
+	for _, detail := range grpcStatus.Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if !ok || info.Domain != ErrorDomain {
+			continue
+		}
+
+		return ErrorCode(info.Reason), true
+	}
+
+	return "", false
+}
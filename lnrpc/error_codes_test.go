@@ -0,0 +1,78 @@
+package lnrpc
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestErrorCodeMapper tests that error codes are attached to all errors while
+// preserving their message and gRPC status code.
+func TestErrorCodeMapper(t *testing.T) {
+	t.Parallel()
+
+	errNotFound := errors.New("invoice not found")
+	mapper := NewErrorCodeMapper(MatchError(
+		errNotFound, ErrorCodeInvoiceNotFound, codes.NotFound,
+	))
+
+	testCases := []struct {
+		name         string
+		err          error
+		expectedCode ErrorCode
+		expectedGRPC codes.Code
+	}{
+		{
+			name:         "mapped error",
+			err:          fmt.Errorf("lookup: %w", errNotFound),
+			expectedCode: ErrorCodeInvoiceNotFound,
+			expectedGRPC: codes.NotFound,
+		},
+		{
+			name:         "unmapped error",
+			err:          errors.New("something went wrong"),
+			expectedCode: ErrorCodeUnknown,
+			expectedGRPC: codes.Unknown,
+		},
+		{
+			name: "status error",
+			err: status.Error(
+				codes.InvalidArgument, "invalid amount",
+			),
+			expectedCode: ErrorCodeInvalidArgument,
+			expectedGRPC: codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := mapper.AttachCode(tc.err)
+
+			code, ok := ErrorCodeFromError(err)
+			require.True(t, ok)
+			require.Equal(t, tc.expectedCode, code)
+
+			s, ok := status.FromError(err)
+			require.True(t, ok)
+			require.Equal(t, tc.expectedGRPC, s.Code())
+			expectedMsg := status.Convert(tc.err).Message()
+			require.Equal(t, expectedMsg, s.Message())
+
+			// Attaching a code again doesn't change the error.
+			require.Equal(t, err, mapper.AttachCode(err))
+		})
+	}
+
+	require.NoError(t, mapper.AttachCode(nil))
+
+	_, ok := ErrorCodeFromError(errors.New("plain error"))
+	require.False(t, ok)
+}
@@ -75,7 +75,7 @@ type AddInvoiceConfig struct {
 	ChanDB *channeldb.ChannelStateDB
 
 	// Graph holds a reference to the ChannelGraph database.
-	Graph *channeldb.ChannelGraph
+	Graph channeldb.GraphStore
 
 	// GenInvoiceFeatures returns a feature containing feature bits that
 	// should be advertised on freshly generated invoices.
@@ -96,6 +96,11 @@ type AddInvoiceConfig struct {
 	// QueryBlindedRoutes can be used to generate a few routes to this node
 	// that can then be used in the construction of a blinded payment path.
 	QueryBlindedRoutes func(lnwire.MilliSatoshi) ([]*route.Route, error)
+
+	// DerivePreimage is an optional function that derives the preimage of
+	// a regular invoice from its payment address. If nil, preimages that
+	// aren't supplied by the caller are generated randomly.
+	DerivePreimage func(paymentAddr [32]byte) lntypes.Preimage
 }
 
 // AddInvoiceData contains the required data to create a new invoice.
@@ -156,6 +161,11 @@ type AddInvoiceData struct {
 	// RouteHints are optional route hints that can each be individually
 	// used to assist in reaching the invoice's destination.
 	RouteHints [][]zpay32.HopHint
+
+	// OverpaymentPolicy describes which payments that pay more than the
+	// invoice amount are accepted. If it's the default policy, the node
+	// wide overpayment policy is used.
+	OverpaymentPolicy invoices.OverpaymentPolicy
 }
 
 // BlindedPathConfig holds the configuration values required for blinded path
@@ -290,6 +300,31 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 			"are not yet supported")
 	}
 
+	// Generate and set a random payment address for this payment. If the
+	// sender understands payment addresses, this can be used to avoid
+	// intermediaries probing the receiver. If the invoice does not have
+	// blinded paths, then this will be encoded in the invoice itself.
+	// Otherwise, it will instead be embedded in the encrypted recipient
+	// data of blinded paths. In the blinded path case, this will be used
+	// for the PathID.
+	var paymentAddr [32]byte
+	if _, err := rand.Read(paymentAddr[:]); err != nil {
+		return nil, nil, err
+	}
+
+	// If deterministic preimages are enabled, the preimage of a regular
+	// invoice is derived from its payment address instead of generated
+	// randomly.
+	if cfg.DerivePreimage != nil && !invoice.Amp &&
+		invoice.Preimage == nil && invoice.Hash == nil {
+
+		preimage := cfg.DerivePreimage(paymentAddr)
+
+		invoiceCopy := *invoice
+		invoiceCopy.Preimage = &preimage
+		invoice = &invoiceCopy
+	}
+
 	paymentPreimage, paymentHash, err := invoice.paymentHashAndPreimage()
 	if err != nil {
 		return nil, nil, err
@@ -488,18 +523,6 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 	}
 	options = append(options, zpay32.Features(invoiceFeatures))
 
-	// Generate and set a random payment address for this payment. If the
-	// sender understands payment addresses, this can be used to avoid
-	// intermediaries probing the receiver. If the invoice does not have
-	// blinded paths, then this will be encoded in the invoice itself.
-	// Otherwise, it will instead be embedded in the encrypted recipient
-	// data of blinded paths. In the blinded path case, this will be used
-	// for the PathID.
-	var paymentAddr [32]byte
-	if _, err := rand.Read(paymentAddr[:]); err != nil {
-		return nil, nil, err
-	}
-
 	if blind {
 		blindCfg := invoice.BlindedPathCfg
 
@@ -606,7 +629,8 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 			PaymentAddr:     paymentAddr,
 			Features:        invoiceFeatures,
 		},
-		HodlInvoice: invoice.HodlInvoice,
+		HodlInvoice:       invoice.HodlInvoice,
+		OverpaymentPolicy: invoice.OverpaymentPolicy,
 	}
 
 	log.Tracef("[addinvoice] adding new invoice %v",
@@ -717,7 +741,8 @@ type HopHintInfo struct {
 	ConfirmedScidZC uint64
 
 	// ScidAliasFeature denotes whether the channel has negotiated the
-	// option-scid-alias feature bit.
+	// option-scid-alias feature bit or channel type. Hop hints for such
+	// channels must only use the peer's alias.
 	ScidAliasFeature bool
 }
 
@@ -732,10 +757,18 @@ func newHopHintInfo(c *channeldb.OpenChannel, isActive bool) *HopHintInfo {
 		RemoteBalance:    c.LocalCommitment.RemoteBalance,
 		ShortChannelID:   c.ShortChannelID.ToUint64(),
 		ConfirmedScidZC:  c.ZeroConfRealScid().ToUint64(),
-		ScidAliasFeature: c.ChanType.HasScidAliasFeature(),
+		ScidAliasFeature: requiresAlias(c),
 	}
 }
 
+// requiresAlias returns true if the channel negotiated the option-scid-alias
+// feature bit or channel type. Unless such a channel is announced, it must
+// only be referred to by an alias outside of our own node.
+func requiresAlias(c *channeldb.OpenChannel) bool {
+	return c.ChanType.HasScidAliasFeature() ||
+		c.ChanType.HasScidAliasChan()
+}
+
 // newHopHint returns a new hop hint using the relevant data from a hopHintInfo
 // and a ChannelEdgePolicy.
 func newHopHint(hopHintInfo *HopHintInfo,
@@ -4,6 +4,7 @@
 package invoicesrpc
 
 import (
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/invoices"
@@ -53,7 +54,7 @@ type Config struct {
 
 	// GraphDB is a global database instance which is needed to access the
 	// channel graph.
-	GraphDB *channeldb.ChannelGraph
+	GraphDB channeldb.GraphStore
 
 	// ChanStateDB is a possibly replicated db instance which contains the
 	// channel state
@@ -74,4 +75,17 @@ type Config struct {
 	// ParseAuxData is a function that can be used to parse the auxiliary
 	// data from the invoice.
 	ParseAuxData func(message proto.Message) error
+
+	// NewAddress returns a fresh on-chain address of the default wallet
+	// account, which is used as fallback address when presenting invoices
+	// that don't have one.
+	NewAddress func() (btcutil.Address, error)
+
+	// OffersManager creates the BOLT 12 offers issued by our node. It is
+	// nil if the invoice database doesn't support offers.
+	OffersManager *invoices.OffersManager
+
+	// TemplateManager manages the invoice templates that invoices can be
+	// minted from.
+	TemplateManager *TemplateManager
 }
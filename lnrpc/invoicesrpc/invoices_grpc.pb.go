@@ -44,6 +44,48 @@ type InvoicesClient interface {
 	// server will send HTLCs of invoices to the client and the client can modify
 	// some aspects of the HTLC in order to pass the invoice acceptance tests.
 	HtlcModifier(ctx context.Context, opts ...grpc.CallOption) (Invoices_HtlcModifierClient, error)
+	// lncli: `presentinvoice`
+	// PresentInvoice turns a payment request into a unified BIP-21 URI that can
+	// be paid both on-chain and via lightning, so wallets can display an invoice
+	// as a single QR code. If the invoice doesn't carry a fallback address, a new
+	// address of the default wallet account is created for it. The on-chain
+	// amount is rounded up to the next satoshi.
+	PresentInvoice(ctx context.Context, in *PresentInvoiceRequest, opts ...grpc.CallOption) (*PresentInvoiceResponse, error)
+	// SubscribeInvoiceHistory returns a uni-directional stream (server -> client)
+	// that sends the full state transition history of an invoice whenever the
+	// invoice changes. Unlike SubscribeSingleInvoice, this includes changes that
+	// don't affect the state of the invoice, such as accepted and canceled htlcs.
+	SubscribeInvoiceHistory(ctx context.Context, in *SubscribeInvoiceHistoryRequest, opts ...grpc.CallOption) (Invoices_SubscribeInvoiceHistoryClient, error)
+	// lncli: `addoffer`
+	// AddOffer creates a new BOLT 12 offer that is issued by our node. Payers
+	// request invoices for the offer by sending invoice requests to our node
+	// over onion messages.
+	AddOffer(ctx context.Context, in *AddOfferRequest, opts ...grpc.CallOption) (*OfferState, error)
+	// lncli: `listoffers`
+	// ListOffers returns all offers issued by our node, together with the
+	// payments that were received for them.
+	ListOffers(ctx context.Context, in *ListOffersRequest, opts ...grpc.CallOption) (*ListOffersResponse, error)
+	// lncli: `decodeoffer`
+	// DecodeOffer decodes a BOLT 12 offer string.
+	DecodeOffer(ctx context.Context, in *DecodeOfferRequest, opts ...grpc.CallOption) (*Offer, error)
+	// lncli: `addinvoicetemplate`
+	// AddInvoiceTemplate adds a new invoice template. Invoices can be minted from
+	// a template on demand using MintInvoice. If the template has a recurrence
+	// interval, a new invoice is also minted from it automatically whenever the
+	// interval has passed. These invoices are reported like any other new invoice,
+	// for example through SubscribeInvoices.
+	AddInvoiceTemplate(ctx context.Context, in *InvoiceTemplateInfo, opts ...grpc.CallOption) (*AddInvoiceTemplateResponse, error)
+	// lncli: `removeinvoicetemplate`
+	// RemoveInvoiceTemplate removes the invoice template with the given name.
+	// Invoices that were minted from it are not affected.
+	RemoveInvoiceTemplate(ctx context.Context, in *RemoveInvoiceTemplateRequest, opts ...grpc.CallOption) (*RemoveInvoiceTemplateResponse, error)
+	// lncli: `listinvoicetemplates`
+	// ListInvoiceTemplates returns all invoice templates, ordered by name.
+	ListInvoiceTemplates(ctx context.Context, in *ListInvoiceTemplatesRequest, opts ...grpc.CallOption) (*ListInvoiceTemplatesResponse, error)
+	// lncli: `mintinvoice`
+	// MintInvoice creates a new invoice from the invoice template with the given
+	// name.
+	MintInvoice(ctx context.Context, in *MintInvoiceRequest, opts ...grpc.CallOption) (*lnrpc.AddInvoiceResponse, error)
 }
 
 type invoicesClient struct {
@@ -153,6 +195,110 @@ func (x *invoicesHtlcModifierClient) Recv() (*HtlcModifyRequest, error) {
 	return m, nil
 }
 
+func (c *invoicesClient) PresentInvoice(ctx context.Context, in *PresentInvoiceRequest, opts ...grpc.CallOption) (*PresentInvoiceResponse, error) {
+	out := new(PresentInvoiceResponse)
+	err := c.cc.Invoke(ctx, "/invoicesrpc.Invoices/PresentInvoice", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoicesClient) SubscribeInvoiceHistory(ctx context.Context, in *SubscribeInvoiceHistoryRequest, opts ...grpc.CallOption) (Invoices_SubscribeInvoiceHistoryClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Invoices_ServiceDesc.Streams[2], "/invoicesrpc.Invoices/SubscribeInvoiceHistory", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &invoicesSubscribeInvoiceHistoryClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Invoices_SubscribeInvoiceHistoryClient interface {
+	Recv() (*InvoiceHistoryUpdate, error)
+	grpc.ClientStream
+}
+
+type invoicesSubscribeInvoiceHistoryClient struct {
+	grpc.ClientStream
+}
+
+func (x *invoicesSubscribeInvoiceHistoryClient) Recv() (*InvoiceHistoryUpdate, error) {
+	m := new(InvoiceHistoryUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *invoicesClient) AddOffer(ctx context.Context, in *AddOfferRequest, opts ...grpc.CallOption) (*OfferState, error) {
+	out := new(OfferState)
+	err := c.cc.Invoke(ctx, "/invoicesrpc.Invoices/AddOffer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoicesClient) ListOffers(ctx context.Context, in *ListOffersRequest, opts ...grpc.CallOption) (*ListOffersResponse, error) {
+	out := new(ListOffersResponse)
+	err := c.cc.Invoke(ctx, "/invoicesrpc.Invoices/ListOffers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoicesClient) DecodeOffer(ctx context.Context, in *DecodeOfferRequest, opts ...grpc.CallOption) (*Offer, error) {
+	out := new(Offer)
+	err := c.cc.Invoke(ctx, "/invoicesrpc.Invoices/DecodeOffer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoicesClient) AddInvoiceTemplate(ctx context.Context, in *InvoiceTemplateInfo, opts ...grpc.CallOption) (*AddInvoiceTemplateResponse, error) {
+	out := new(AddInvoiceTemplateResponse)
+	err := c.cc.Invoke(ctx, "/invoicesrpc.Invoices/AddInvoiceTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoicesClient) RemoveInvoiceTemplate(ctx context.Context, in *RemoveInvoiceTemplateRequest, opts ...grpc.CallOption) (*RemoveInvoiceTemplateResponse, error) {
+	out := new(RemoveInvoiceTemplateResponse)
+	err := c.cc.Invoke(ctx, "/invoicesrpc.Invoices/RemoveInvoiceTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoicesClient) ListInvoiceTemplates(ctx context.Context, in *ListInvoiceTemplatesRequest, opts ...grpc.CallOption) (*ListInvoiceTemplatesResponse, error) {
+	out := new(ListInvoiceTemplatesResponse)
+	err := c.cc.Invoke(ctx, "/invoicesrpc.Invoices/ListInvoiceTemplates", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoicesClient) MintInvoice(ctx context.Context, in *MintInvoiceRequest, opts ...grpc.CallOption) (*lnrpc.AddInvoiceResponse, error) {
+	out := new(lnrpc.AddInvoiceResponse)
+	err := c.cc.Invoke(ctx, "/invoicesrpc.Invoices/MintInvoice", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // InvoicesServer is the server API for Invoices service.
 // All implementations must embed UnimplementedInvoicesServer
 // for forward compatibility
@@ -182,6 +328,48 @@ type InvoicesServer interface {
 	// server will send HTLCs of invoices to the client and the client can modify
 	// some aspects of the HTLC in order to pass the invoice acceptance tests.
 	HtlcModifier(Invoices_HtlcModifierServer) error
+	// lncli: `presentinvoice`
+	// PresentInvoice turns a payment request into a unified BIP-21 URI that can
+	// be paid both on-chain and via lightning, so wallets can display an invoice
+	// as a single QR code. If the invoice doesn't carry a fallback address, a new
+	// address of the default wallet account is created for it. The on-chain
+	// amount is rounded up to the next satoshi.
+	PresentInvoice(context.Context, *PresentInvoiceRequest) (*PresentInvoiceResponse, error)
+	// SubscribeInvoiceHistory returns a uni-directional stream (server -> client)
+	// that sends the full state transition history of an invoice whenever the
+	// invoice changes. Unlike SubscribeSingleInvoice, this includes changes that
+	// don't affect the state of the invoice, such as accepted and canceled htlcs.
+	SubscribeInvoiceHistory(*SubscribeInvoiceHistoryRequest, Invoices_SubscribeInvoiceHistoryServer) error
+	// lncli: `addoffer`
+	// AddOffer creates a new BOLT 12 offer that is issued by our node. Payers
+	// request invoices for the offer by sending invoice requests to our node
+	// over onion messages.
+	AddOffer(context.Context, *AddOfferRequest) (*OfferState, error)
+	// lncli: `listoffers`
+	// ListOffers returns all offers issued by our node, together with the
+	// payments that were received for them.
+	ListOffers(context.Context, *ListOffersRequest) (*ListOffersResponse, error)
+	// lncli: `decodeoffer`
+	// DecodeOffer decodes a BOLT 12 offer string.
+	DecodeOffer(context.Context, *DecodeOfferRequest) (*Offer, error)
+	// lncli: `addinvoicetemplate`
+	// AddInvoiceTemplate adds a new invoice template. Invoices can be minted from
+	// a template on demand using MintInvoice. If the template has a recurrence
+	// interval, a new invoice is also minted from it automatically whenever the
+	// interval has passed. These invoices are reported like any other new invoice,
+	// for example through SubscribeInvoices.
+	AddInvoiceTemplate(context.Context, *InvoiceTemplateInfo) (*AddInvoiceTemplateResponse, error)
+	// lncli: `removeinvoicetemplate`
+	// RemoveInvoiceTemplate removes the invoice template with the given name.
+	// Invoices that were minted from it are not affected.
+	RemoveInvoiceTemplate(context.Context, *RemoveInvoiceTemplateRequest) (*RemoveInvoiceTemplateResponse, error)
+	// lncli: `listinvoicetemplates`
+	// ListInvoiceTemplates returns all invoice templates, ordered by name.
+	ListInvoiceTemplates(context.Context, *ListInvoiceTemplatesRequest) (*ListInvoiceTemplatesResponse, error)
+	// lncli: `mintinvoice`
+	// MintInvoice creates a new invoice from the invoice template with the given
+	// name.
+	MintInvoice(context.Context, *MintInvoiceRequest) (*lnrpc.AddInvoiceResponse, error)
 	mustEmbedUnimplementedInvoicesServer()
 }
 
@@ -207,6 +395,33 @@ func (UnimplementedInvoicesServer) LookupInvoiceV2(context.Context, *LookupInvoi
 func (UnimplementedInvoicesServer) HtlcModifier(Invoices_HtlcModifierServer) error {
 	return status.Errorf(codes.Unimplemented, "method HtlcModifier not implemented")
 }
+func (UnimplementedInvoicesServer) PresentInvoice(context.Context, *PresentInvoiceRequest) (*PresentInvoiceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PresentInvoice not implemented")
+}
+func (UnimplementedInvoicesServer) SubscribeInvoiceHistory(*SubscribeInvoiceHistoryRequest, Invoices_SubscribeInvoiceHistoryServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeInvoiceHistory not implemented")
+}
+func (UnimplementedInvoicesServer) AddOffer(context.Context, *AddOfferRequest) (*OfferState, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddOffer not implemented")
+}
+func (UnimplementedInvoicesServer) ListOffers(context.Context, *ListOffersRequest) (*ListOffersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOffers not implemented")
+}
+func (UnimplementedInvoicesServer) DecodeOffer(context.Context, *DecodeOfferRequest) (*Offer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DecodeOffer not implemented")
+}
+func (UnimplementedInvoicesServer) AddInvoiceTemplate(context.Context, *InvoiceTemplateInfo) (*AddInvoiceTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddInvoiceTemplate not implemented")
+}
+func (UnimplementedInvoicesServer) RemoveInvoiceTemplate(context.Context, *RemoveInvoiceTemplateRequest) (*RemoveInvoiceTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveInvoiceTemplate not implemented")
+}
+func (UnimplementedInvoicesServer) ListInvoiceTemplates(context.Context, *ListInvoiceTemplatesRequest) (*ListInvoiceTemplatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInvoiceTemplates not implemented")
+}
+func (UnimplementedInvoicesServer) MintInvoice(context.Context, *MintInvoiceRequest) (*lnrpc.AddInvoiceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MintInvoice not implemented")
+}
 func (UnimplementedInvoicesServer) mustEmbedUnimplementedInvoicesServer() {}
 
 // UnsafeInvoicesServer may be embedded to opt out of forward compatibility for this service.
@@ -339,6 +554,171 @@ func (x *invoicesHtlcModifierServer) Recv() (*HtlcModifyResponse, error) {
 	return m, nil
 }
 
+func _Invoices_PresentInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PresentInvoiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoicesServer).PresentInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/invoicesrpc.Invoices/PresentInvoice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoicesServer).PresentInvoice(ctx, req.(*PresentInvoiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Invoices_SubscribeInvoiceHistory_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeInvoiceHistoryRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(InvoicesServer).SubscribeInvoiceHistory(m, &invoicesSubscribeInvoiceHistoryServer{stream})
+}
+
+type Invoices_SubscribeInvoiceHistoryServer interface {
+	Send(*InvoiceHistoryUpdate) error
+	grpc.ServerStream
+}
+
+type invoicesSubscribeInvoiceHistoryServer struct {
+	grpc.ServerStream
+}
+
+func (x *invoicesSubscribeInvoiceHistoryServer) Send(m *InvoiceHistoryUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Invoices_AddOffer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddOfferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoicesServer).AddOffer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/invoicesrpc.Invoices/AddOffer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoicesServer).AddOffer(ctx, req.(*AddOfferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Invoices_ListOffers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOffersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoicesServer).ListOffers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/invoicesrpc.Invoices/ListOffers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoicesServer).ListOffers(ctx, req.(*ListOffersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Invoices_DecodeOffer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecodeOfferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoicesServer).DecodeOffer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/invoicesrpc.Invoices/DecodeOffer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoicesServer).DecodeOffer(ctx, req.(*DecodeOfferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Invoices_AddInvoiceTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InvoiceTemplateInfo)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoicesServer).AddInvoiceTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/invoicesrpc.Invoices/AddInvoiceTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoicesServer).AddInvoiceTemplate(ctx, req.(*InvoiceTemplateInfo))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Invoices_RemoveInvoiceTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveInvoiceTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoicesServer).RemoveInvoiceTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/invoicesrpc.Invoices/RemoveInvoiceTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoicesServer).RemoveInvoiceTemplate(ctx, req.(*RemoveInvoiceTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Invoices_ListInvoiceTemplates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInvoiceTemplatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoicesServer).ListInvoiceTemplates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/invoicesrpc.Invoices/ListInvoiceTemplates",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoicesServer).ListInvoiceTemplates(ctx, req.(*ListInvoiceTemplatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Invoices_MintInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MintInvoiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoicesServer).MintInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/invoicesrpc.Invoices/MintInvoice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoicesServer).MintInvoice(ctx, req.(*MintInvoiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Invoices_ServiceDesc is the grpc.ServiceDesc for Invoices service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -362,6 +742,38 @@ var Invoices_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "LookupInvoiceV2",
 			Handler:    _Invoices_LookupInvoiceV2_Handler,
 		},
+		{
+			MethodName: "PresentInvoice",
+			Handler:    _Invoices_PresentInvoice_Handler,
+		},
+		{
+			MethodName: "AddOffer",
+			Handler:    _Invoices_AddOffer_Handler,
+		},
+		{
+			MethodName: "ListOffers",
+			Handler:    _Invoices_ListOffers_Handler,
+		},
+		{
+			MethodName: "DecodeOffer",
+			Handler:    _Invoices_DecodeOffer_Handler,
+		},
+		{
+			MethodName: "AddInvoiceTemplate",
+			Handler:    _Invoices_AddInvoiceTemplate_Handler,
+		},
+		{
+			MethodName: "RemoveInvoiceTemplate",
+			Handler:    _Invoices_RemoveInvoiceTemplate_Handler,
+		},
+		{
+			MethodName: "ListInvoiceTemplates",
+			Handler:    _Invoices_ListInvoiceTemplates_Handler,
+		},
+		{
+			MethodName: "MintInvoice",
+			Handler:    _Invoices_MintInvoice_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -375,6 +787,11 @@ var Invoices_ServiceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "SubscribeInvoiceHistory",
+			Handler:       _Invoices_SubscribeInvoiceHistory_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "invoicesrpc/invoices.proto",
 }
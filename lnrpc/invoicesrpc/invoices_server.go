@@ -9,11 +9,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/lightningnetwork/lnd/fn"
 	"github.com/lightningnetwork/lnd/invoices"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/macaroons"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -33,6 +36,11 @@ var (
 	// ErrServerShuttingDown is returned when the server is shutting down.
 	ErrServerShuttingDown = errors.New("server shutting down")
 
+	// errOffersUnsupported is returned by the offer RPCs if the invoice
+	// database doesn't support offers.
+	errOffersUnsupported = status.Error(codes.Unimplemented, "offers "+
+		"aren't supported by the invoice database")
+
 	// macaroonOps are the set of capabilities that our minted macaroon (if
 	// it doesn't already exist) will have.
 	macaroonOps = []bakery.Op{
@@ -72,6 +80,45 @@ var (
 			Entity: "invoices",
 			Action: "write",
 		}},
+		"/invoicesrpc.Invoices/SubscribeInvoiceHistory": {{
+			Entity: "invoices",
+			Action: "read",
+		}},
+		"/invoicesrpc.Invoices/AddOffer": {{
+			Entity: "invoices",
+			Action: "write",
+		}},
+		"/invoicesrpc.Invoices/ListOffers": {{
+			Entity: "invoices",
+			Action: "read",
+		}},
+		"/invoicesrpc.Invoices/DecodeOffer": {{
+			Entity: "invoices",
+			Action: "read",
+		}},
+		"/invoicesrpc.Invoices/AddInvoiceTemplate": {{
+			Entity: "invoices",
+			Action: "write",
+		}},
+		"/invoicesrpc.Invoices/RemoveInvoiceTemplate": {{
+			Entity: "invoices",
+			Action: "write",
+		}},
+		"/invoicesrpc.Invoices/ListInvoiceTemplates": {{
+			Entity: "invoices",
+			Action: "read",
+		}},
+		"/invoicesrpc.Invoices/MintInvoice": {{
+			Entity: "invoices",
+			Action: "write",
+		}},
+		"/invoicesrpc.Invoices/PresentInvoice": {{
+			Entity: "invoices",
+			Action: "read",
+		}, {
+			Entity: "address",
+			Action: "write",
+		}},
 	}
 
 	// DefaultInvoicesMacFilename is the default name of the invoices
@@ -294,6 +341,209 @@ func (s *Server) SubscribeSingleInvoice(req *SubscribeSingleInvoiceRequest,
 	}
 }
 
+// SubscribeInvoiceHistory returns a uni-directional stream (server -> client)
+// that sends the full state transition history of an invoice whenever the
+// invoice changes. If a payment hash is given, only the history of that invoice
+// is sent.
+func (s *Server) SubscribeInvoiceHistory(req *SubscribeInvoiceHistoryRequest,
+	updateStream Invoices_SubscribeInvoiceHistoryServer) error {
+
+	var filterHash *lntypes.Hash
+	if len(req.RHash) > 0 {
+		hash, err := lntypes.MakeHash(req.RHash)
+		if err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		filterHash = &hash
+	}
+
+	historyClient, err := s.cfg.InvoiceRegistry.SubscribeInvoiceHistory()
+	if err != nil {
+		return err
+	}
+	defer historyClient.Cancel()
+
+	log.Debugf("Created new invoice history subscription")
+
+	for {
+		select {
+		case e := <-historyClient.Updates():
+			update, ok := e.(*invoices.InvoiceHistoryUpdate)
+			if !ok {
+				return fmt.Errorf("unexpected history "+
+					"update: %T", e)
+			}
+
+			if filterHash != nil && update.Hash != *filterHash {
+				continue
+			}
+
+			history := CreateRPCInvoiceHistory(update.History)
+			err := updateStream.Send(&InvoiceHistoryUpdate{
+				RHash:   update.Hash[:],
+				History: history,
+			})
+			if err != nil {
+				return err
+			}
+
+		case <-historyClient.Quit():
+			return ErrServerShuttingDown
+
+		case <-updateStream.Context().Done():
+			return updateStream.Context().Err()
+
+		case <-s.quit:
+			return nil
+		}
+	}
+}
+
+// AddOffer creates a new BOLT 12 offer that is issued by our node.
+func (s *Server) AddOffer(ctx context.Context,
+	req *AddOfferRequest) (*OfferState, error) {
+
+	if s.cfg.OffersManager == nil {
+		return nil, errOffersUnsupported
+	}
+
+	if req.QuantityMax != 0 && !req.AllowQuantity {
+		return nil, status.Error(codes.InvalidArgument, "quantity_max "+
+			"requires allow_quantity to be set")
+	}
+
+	if req.AbsoluteExpiry < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid "+
+			"absolute expiry: %v", req.AbsoluteExpiry)
+	}
+
+	addReq := &invoices.AddOfferRequest{
+		Description: req.Description,
+		Amount:      lnwire.MilliSatoshi(req.AmountMsat),
+		Issuer:      req.Issuer,
+	}
+	if req.AllowQuantity {
+		addReq.QuantityMax = fn.Some(req.QuantityMax)
+	}
+	if req.AbsoluteExpiry > 0 {
+		addReq.AbsoluteExpiry = time.Unix(req.AbsoluteExpiry, 0)
+	}
+
+	state, err := s.cfg.OffersManager.AddOffer(ctx, addReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return CreateRPCOfferState(state)
+}
+
+// ListOffers returns all offers issued by our node.
+func (s *Server) ListOffers(ctx context.Context,
+	_ *ListOffersRequest) (*ListOffersResponse, error) {
+
+	if s.cfg.OffersManager == nil {
+		return nil, errOffersUnsupported
+	}
+
+	offers, err := s.cfg.OffersManager.ListOffers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ListOffersResponse{
+		Offers: make([]*OfferState, 0, len(offers)),
+	}
+	for _, offer := range offers {
+		rpcOffer, err := CreateRPCOfferState(offer)
+		if err != nil {
+			return nil, err
+		}
+		resp.Offers = append(resp.Offers, rpcOffer)
+	}
+
+	return resp, nil
+}
+
+// DecodeOffer decodes a BOLT 12 offer string.
+func (s *Server) DecodeOffer(_ context.Context,
+	req *DecodeOfferRequest) (*Offer, error) {
+
+	offer, err := invoices.DecodeOffer(req.Offer)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unable to "+
+			"decode offer: %v", err)
+	}
+
+	return CreateRPCOffer(offer)
+}
+
+// AddInvoiceTemplate adds a new invoice template.
+func (s *Server) AddInvoiceTemplate(_ context.Context,
+	req *InvoiceTemplateInfo) (*AddInvoiceTemplateResponse, error) {
+
+	template, err := UnmarshalInvoiceTemplate(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err = s.cfg.TemplateManager.AddTemplate(template)
+	if errors.Is(err, ErrTemplateExists) {
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &AddInvoiceTemplateResponse{}, nil
+}
+
+// RemoveInvoiceTemplate removes the invoice template with the given name.
+func (s *Server) RemoveInvoiceTemplate(_ context.Context,
+	req *RemoveInvoiceTemplateRequest) (*RemoveInvoiceTemplateResponse,
+	error) {
+
+	err := s.cfg.TemplateManager.RemoveTemplate(req.Name)
+	if errors.Is(err, ErrTemplateNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &RemoveInvoiceTemplateResponse{}, nil
+}
+
+// ListInvoiceTemplates returns all invoice templates, ordered by name.
+func (s *Server) ListInvoiceTemplates(_ context.Context,
+	_ *ListInvoiceTemplatesRequest) (*ListInvoiceTemplatesResponse, error) {
+
+	templates := s.cfg.TemplateManager.ListTemplates()
+
+	resp := &ListInvoiceTemplatesResponse{
+		Templates: make([]*InvoiceTemplateInfo, 0, len(templates)),
+	}
+	for _, template := range templates {
+		resp.Templates = append(
+			resp.Templates, MarshalInvoiceTemplate(template),
+		)
+	}
+
+	return resp, nil
+}
+
+// MintInvoice creates a new invoice from the invoice template with the given
+// name.
+func (s *Server) MintInvoice(ctx context.Context,
+	req *MintInvoiceRequest) (*lnrpc.AddInvoiceResponse, error) {
+
+	resp, err := s.cfg.TemplateManager.MintInvoice(ctx, req.Name)
+	if errors.Is(err, ErrTemplateNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	return resp, err
+}
+
 // SettleInvoice settles an accepted invoice. If the invoice is already settled,
 // this call will succeed.
 func (s *Server) SettleInvoice(ctx context.Context,
@@ -398,6 +648,13 @@ func (s *Server) AddHoldInvoice(ctx context.Context,
 func (s *Server) LookupInvoiceV2(ctx context.Context,
 	req *LookupInvoiceMsg) (*lnrpc.Invoice, error) {
 
+	// Payment addresses and set IDs are looked up through their exact
+	// index entries, so we reject malformed values instead of silently
+	// padding or truncating them.
+	if err := validateInvoiceRefLen(req); err != nil {
+		return nil, err
+	}
+
 	var invoiceRef invoices.InvoiceRef
 
 	// First, we'll attempt to parse out the invoice ref from the proto
@@ -475,6 +732,32 @@ func (s *Server) LookupInvoiceV2(ctx context.Context,
 	return rpcInvoice, nil
 }
 
+// PresentInvoice turns a payment request into a unified BIP-21 URI that can be
+// paid both on-chain and via lightning. If the invoice doesn't carry a
+// fallback address, a new address is created for it.
+func (s *Server) PresentInvoice(_ context.Context,
+	req *PresentInvoiceRequest) (*PresentInvoiceResponse, error) {
+
+	if req.PaymentRequest == "" {
+		return nil, status.Error(codes.InvalidArgument,
+			"payment request must be set")
+	}
+
+	presentation, err := PresentInvoice(&PresentationConfig{
+		ChainParams: s.cfg.ChainParams,
+		NewAddress:  s.cfg.NewAddress,
+	}, req.PaymentRequest, req.Label)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PresentInvoiceResponse{
+		PaymentRequest: presentation.PaymentRequest,
+		FallbackAddr:   presentation.FallbackAddr.String(),
+		Bip21Uri:       presentation.BIP21URI,
+	}, nil
+}
+
 // HtlcModifier is a bidirectional streaming RPC that allows a client to
 // intercept and modify the HTLCs that attempt to settle the given invoice. The
 // server will send HTLCs of invoices to the client and the client can modify
@@ -507,3 +790,23 @@ func (s *Server) HtlcModifier(
 		}
 	}
 }
+
+// validateInvoiceRefLen ensures that a payment address or set ID that is used
+// to look up an invoice is exactly 32 bytes long.
+func validateInvoiceRefLen(req *LookupInvoiceMsg) error {
+	switch {
+	case req.GetPaymentAddr() != nil && len(req.GetPaymentAddr()) != 32:
+		return status.Error(codes.InvalidArgument, fmt.Sprintf(
+			"payment addr must be exactly 32 bytes, is instead %v",
+			len(req.GetPaymentAddr()),
+		))
+
+	case req.GetSetId() != nil && len(req.GetSetId()) != 32:
+		return status.Error(codes.InvalidArgument, fmt.Sprintf(
+			"set id must be exactly 32 bytes, is instead %v",
+			len(req.GetSetId()),
+		))
+	}
+
+	return nil
+}
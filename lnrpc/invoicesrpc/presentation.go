@@ -0,0 +1,337 @@
+package invoicesrpc
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/bech32"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/zpay32"
+)
+
+const (
+	// lnurlHRP is the human readable part of bech32 encoded LNURLs.
+	lnurlHRP = "lnurl"
+
+	// bip21Scheme is the URI scheme of BIP-21 payment URIs.
+	bip21Scheme = "bitcoin"
+
+	// lnurlPayTag is the tag that identifies an LNURL-pay response.
+	lnurlPayTag = "payRequest"
+)
+
+// EncodeLNURL encodes the given URL as a bech32 LNURL as defined by LUD-01.
+// The result is upper case, which allows QR codes to use the more compact
+// alphanumeric mode.
+func EncodeLNURL(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid url: %w", err)
+	}
+
+	// LNURLs must use https, except for onion services.
+	isOnion := strings.HasSuffix(u.Hostname(), ".onion")
+	if u.Scheme != "https" && !(isOnion && u.Scheme == "http") {
+		return "", fmt.Errorf("url must use https, got %q", u.Scheme)
+	}
+
+	lnurl, err := bech32.EncodeFromBase256(lnurlHRP, []byte(rawURL))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.ToUpper(lnurl), nil
+}
+
+// DecodeLNURL decodes a bech32 LNURL into the URL it encodes.
+func DecodeLNURL(lnurl string) (string, error) {
+	hrp, data, err := bech32.DecodeNoLimit(lnurl)
+	if err != nil {
+		return "", err
+	}
+	if hrp != lnurlHRP {
+		return "", fmt.Errorf("unexpected hrp %q", hrp)
+	}
+
+	decoded, err := bech32.ConvertBits(data, 5, 8, false)
+	if err != nil {
+		return "", err
+	}
+
+	return string(decoded), nil
+}
+
+// LNURLPayMetadata describes the payee of an LNURL-pay request as defined by
+// LUD-06. Invoices generated for the request must commit to the hash of the
+// encoded metadata as their description hash.
+type LNURLPayMetadata struct {
+	// Description is the short description shown to the payer.
+	Description string
+
+	// LongDescription is an optional longer description.
+	LongDescription string
+
+	// Identifier is an optional internet identifier, such as a lightning
+	// address, that the payments are made to.
+	Identifier string
+
+	// Email is an optional email address that the payments are made to.
+	Email string
+
+	// PNGImage and JPEGImage are an optional base64 encoded thumbnail of
+	// the payee. At most one of them may be set.
+	PNGImage  string
+	JPEGImage string
+}
+
+// Encode returns the metadata as the JSON encoded array of entries that is
+// sent to the payer.
+func (m *LNURLPayMetadata) Encode() (string, error) {
+	if m.Description == "" {
+		return "", errors.New("metadata requires a description")
+	}
+	if m.PNGImage != "" && m.JPEGImage != "" {
+		return "", errors.New("metadata can contain at most one image")
+	}
+
+	entries := [][2]string{{"text/plain", m.Description}}
+
+	optional := []struct {
+		mimeType string
+		value    string
+	}{
+		{"text/long-desc", m.LongDescription},
+		{"text/identifier", m.Identifier},
+		{"text/email", m.Email},
+		{"image/png;base64", m.PNGImage},
+		{"image/jpeg;base64", m.JPEGImage},
+	}
+	for _, entry := range optional {
+		if entry.value == "" {
+			continue
+		}
+
+		entries = append(
+			entries, [2]string{entry.mimeType, entry.value},
+		)
+	}
+
+	encoded, err := json.Marshal(entries)
+	if err != nil {
+		return "", err
+	}
+
+	return string(encoded), nil
+}
+
+// DescriptionHash returns the hash of the encoded metadata, which is to be
+// used as the description hash of invoices for the request.
+func (m *LNURLPayMetadata) DescriptionHash() ([32]byte, error) {
+	encoded, err := m.Encode()
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	return sha256.Sum256([]byte(encoded)), nil
+}
+
+// LNURLPayResponse is the response to the first request of the LNURL-pay flow
+// as defined by LUD-06.
+type LNURLPayResponse struct {
+	// Callback is the URL the payer requests the invoice from.
+	Callback string `json:"callback"`
+
+	// MaxSendable is the maximum amount the payer may send in msat.
+	MaxSendable uint64 `json:"maxSendable"`
+
+	// MinSendable is the minimum amount the payer may send in msat.
+	MinSendable uint64 `json:"minSendable"`
+
+	// Metadata is the encoded metadata of the payee.
+	Metadata string `json:"metadata"`
+
+	// Tag identifies the response as LNURL-pay response.
+	Tag string `json:"tag"`
+}
+
+// NewLNURLPayResponse creates the response to the first request of the
+// LNURL-pay flow for the given callback, amount range and payee metadata.
+func NewLNURLPayResponse(callback string, minSendable,
+	maxSendable lnwire.MilliSatoshi,
+	metadata *LNURLPayMetadata) (*LNURLPayResponse, error) {
+
+	if minSendable == 0 || minSendable > maxSendable {
+		return nil, fmt.Errorf("invalid amount range [%v, %v]",
+			minSendable, maxSendable)
+	}
+
+	if _, err := url.ParseRequestURI(callback); err != nil {
+		return nil, fmt.Errorf("invalid callback: %w", err)
+	}
+
+	encoded, err := metadata.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	return &LNURLPayResponse{
+		Callback:    callback,
+		MaxSendable: uint64(maxSendable),
+		MinSendable: uint64(minSendable),
+		Metadata:    encoded,
+		Tag:         lnurlPayTag,
+	}, nil
+}
+
+// BIP21Params are the parameters of a BIP-21 payment URI.
+type BIP21Params struct {
+	// Addr is the on-chain address to pay to.
+	Addr btcutil.Address
+
+	// Amount is the amount to pay. If zero, it is omitted.
+	Amount btcutil.Amount
+
+	// Label is an optional label for the recipient.
+	Label string
+
+	// Message is an optional message describing the payment.
+	Message string
+
+	// PaymentRequest is an optional BOLT 11 payment request that wallets
+	// supporting lightning prefer over the on-chain address.
+	PaymentRequest string
+}
+
+// EncodeBIP21 encodes a BIP-21 payment URI. If a payment request is given,
+// it is added as lightning parameter, which results in a unified URI that
+// can be paid both on-chain and via lightning.
+func EncodeBIP21(params *BIP21Params) (string, error) {
+	if params.Addr == nil {
+		return "", errors.New("address required")
+	}
+	if params.Amount < 0 {
+		return "", errors.New("amount must not be negative")
+	}
+
+	var query []string
+	addParam := func(key, value string) {
+		if value == "" {
+			return
+		}
+
+		// BIP-21 requires percent encoding, which encodes spaces as
+		// %20 rather than the + of form encoding.
+		escaped := url.QueryEscape(value)
+		escaped = strings.ReplaceAll(escaped, "+", "%20")
+		query = append(query, key+"="+escaped)
+	}
+
+	if params.Amount > 0 {
+		addParam("amount", strconv.FormatFloat(
+			params.Amount.ToBTC(), 'f', -1, 64,
+		))
+	}
+	addParam("label", params.Label)
+	addParam("message", params.Message)
+	addParam("lightning", params.PaymentRequest)
+
+	uri := bip21Scheme + ":" + params.Addr.EncodeAddress()
+	if len(query) > 0 {
+		uri += "?" + strings.Join(query, "&")
+	}
+
+	return uri, nil
+}
+
+// PresentationConfig contains the dependencies to generate the presentation
+// of an invoice.
+type PresentationConfig struct {
+	// ChainParams are used to decode payment requests.
+	ChainParams *chaincfg.Params
+
+	// NewAddress returns a fresh on-chain address, which is used as
+	// fallback for invoices that don't have a fallback address yet.
+	NewAddress func() (btcutil.Address, error)
+}
+
+// InvoicePresentation holds the payloads that present an invoice to a payer.
+type InvoicePresentation struct {
+	// PaymentRequest is the BOLT 11 payment request of the invoice.
+	PaymentRequest string
+
+	// FallbackAddr is the on-chain address the invoice can be paid to
+	// alternatively.
+	FallbackAddr btcutil.Address
+
+	// BIP21URI is the unified BIP-21 URI, containing both the fallback
+	// address and the payment request.
+	BIP21URI string
+}
+
+// PresentInvoice generates the payloads that present the invoice with the
+// given payment request to a payer, so that wallets can display it as a
+// single QR code that can be paid both on-chain and via lightning. If the
+// invoice doesn't carry a fallback address, a new address is created for it.
+// The optional label is added to the BIP-21 URI.
+func PresentInvoice(cfg *PresentationConfig, payReq,
+	label string) (*InvoicePresentation, error) {
+
+	invoice, err := zpay32.Decode(payReq, cfg.ChainParams)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode payment request: %w",
+			err)
+	}
+
+	fallbackAddr := invoice.FallbackAddr
+	if fallbackAddr == nil {
+		if cfg.NewAddress == nil {
+			return nil, errors.New("invoice has no fallback " +
+				"address")
+		}
+
+		fallbackAddr, err = cfg.NewAddress()
+		if err != nil {
+			return nil, fmt.Errorf("unable to create fallback "+
+				"address: %w", err)
+		}
+	}
+
+	// The on-chain amount is rounded up to the next satoshi, so that
+	// paying on-chain never pays less than the invoice.
+	var amt btcutil.Amount
+	if invoice.MilliSat != nil {
+		amt = invoice.MilliSat.ToSatoshis()
+		if lnwire.NewMSatFromSatoshis(amt) < *invoice.MilliSat {
+			amt++
+		}
+	}
+
+	var message string
+	if invoice.Description != nil {
+		message = *invoice.Description
+	}
+
+	uri, err := EncodeBIP21(&BIP21Params{
+		Addr:           fallbackAddr,
+		Amount:         amt,
+		Label:          label,
+		Message:        message,
+		PaymentRequest: payReq,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &InvoicePresentation{
+		PaymentRequest: payReq,
+		FallbackAddr:   fallbackAddr,
+		BIP21URI:       uri,
+	}, nil
+}
@@ -0,0 +1,140 @@
+package invoicesrpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/zpay32"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// testLNURLUrl and testLNURL are the test vector of LUD-01.
+	testLNURLUrl = "https://service.com/api?q=3fc3645b439ce8e7f2553a69e5" +
+		"267081d96dcd340693afabe04be7b0ccd178df"
+	testLNURL = "LNURL1DP68GURN8GHJ7UM9WFMXJCM99E3K7MF0V9CXJ0M385EKVCE" +
+		"NXC6R2C35XVUKXEFCV5MKVV34X5EKZD3EV56NYD3HXQURZEPEXEJXXEPNXS" +
+		"CRVWFNV9NXZCN9XQ6XYEFHVGCXXCMYXYMNSERXFQ5FNS"
+
+	testPresentationAddr = "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"
+)
+
+// TestLNURL tests the encoding and decoding of LNURLs.
+func TestLNURL(t *testing.T) {
+	t.Parallel()
+
+	lnurl, err := EncodeLNURL(testLNURLUrl)
+	require.NoError(t, err)
+	require.Equal(t, testLNURL, lnurl)
+
+	decoded, err := DecodeLNURL(lnurl)
+	require.NoError(t, err)
+	require.Equal(t, testLNURLUrl, decoded)
+
+	// Plain http is only allowed for onion services.
+	_, err = EncodeLNURL("http://service.com/api")
+	require.Error(t, err)
+
+	_, err = EncodeLNURL("http://service.onion/api")
+	require.NoError(t, err)
+}
+
+// TestLNURLPayMetadata tests the encoding of LNURL-pay metadata and the
+// response it is part of.
+func TestLNURLPayMetadata(t *testing.T) {
+	t.Parallel()
+
+	metadata := &LNURLPayMetadata{
+		Description: "coffee",
+		Identifier:  "alice@service.com",
+	}
+
+	encoded, err := metadata.Encode()
+	require.NoError(t, err)
+	require.Equal(
+		t, `[["text/plain","coffee"],`+
+			`["text/identifier","alice@service.com"]]`, encoded,
+	)
+
+	hash, err := metadata.DescriptionHash()
+	require.NoError(t, err)
+	require.NotEqual(t, [32]byte{}, hash)
+
+	resp, err := NewLNURLPayResponse(
+		"https://service.com/callback", 1000, 100000, metadata,
+	)
+	require.NoError(t, err)
+	require.Equal(t, encoded, resp.Metadata)
+	require.Equal(t, "payRequest", resp.Tag)
+
+	_, err = NewLNURLPayResponse(
+		"https://service.com/callback", 2000, 1000, metadata,
+	)
+	require.Error(t, err)
+
+	_, err = (&LNURLPayMetadata{}).Encode()
+	require.Error(t, err)
+}
+
+// TestPresentInvoice tests that an invoice is presented as a unified BIP-21
+// URI, with a fallback address being created if the invoice has none.
+func TestPresentInvoice(t *testing.T) {
+	t.Parallel()
+
+	params := &chaincfg.MainNetParams
+	addr, err := btcutil.DecodeAddress(testPresentationAddr, params)
+	require.NoError(t, err)
+
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	invoice, err := zpay32.NewInvoice(
+		params, [32]byte{1}, time.Unix(1700000000, 0),
+		zpay32.Amount(lnwire.MilliSatoshi(150_000_500)),
+		zpay32.Description("coffee beans"),
+	)
+	require.NoError(t, err)
+
+	payReq, err := invoice.Encode(zpay32.MessageSigner{
+		SignCompact: func(msg []byte) ([]byte, error) {
+			hash := chainhash.HashB(msg)
+
+			return ecdsa.SignCompact(privKey, hash, true), nil
+		},
+	})
+	require.NoError(t, err)
+
+	var newAddrCalls int
+	cfg := &PresentationConfig{
+		ChainParams: params,
+		NewAddress: func() (btcutil.Address, error) {
+			newAddrCalls++
+
+			return addr, nil
+		},
+	}
+
+	presentation, err := PresentInvoice(cfg, payReq, "shop")
+	require.NoError(t, err)
+	require.Equal(t, 1, newAddrCalls)
+	require.Equal(t, addr, presentation.FallbackAddr)
+
+	// The amount is rounded up to the next satoshi.
+	require.Equal(
+		t, "bitcoin:"+testPresentationAddr+"?amount=0.00150001"+
+			"&label=shop&message=coffee%20beans&lightning="+payReq,
+		presentation.BIP21URI,
+	)
+
+	// Without a way to create an address, an invoice without fallback
+	// address can't be presented.
+	cfg.NewAddress = nil
+	_, err = PresentInvoice(cfg, payReq, "")
+	require.Error(t, err)
+}
@@ -0,0 +1,116 @@
+package invoicesrpc
+
+import (
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/zpay32"
+)
+
+// ScidLeak is a route hint of an invoice that reveals the confirmed SCID of
+// an unannounced channel which negotiated the option-scid-alias feature, and
+// should therefore only have been referred to by its alias.
+type ScidLeak struct {
+	// ChanPoint is the funding outpoint of the channel.
+	ChanPoint wire.OutPoint
+
+	// Scid is the confirmed SCID of the channel found in the hint.
+	Scid lnwire.ShortChannelID
+
+	// AddIndex is the add index of the invoice containing the hint.
+	AddIndex uint64
+}
+
+// confirmedScid returns the confirmed SCID of the channel, if it has one.
+func confirmedScid(c *channeldb.OpenChannel) (lnwire.ShortChannelID, bool) {
+	switch {
+	case c.IsPending:
+		return lnwire.ShortChannelID{}, false
+
+	// The SCID of a zero-conf channel is an alias, its confirmed SCID is
+	// only known once the funding tx confirmed.
+	case c.IsZeroConf():
+		if !c.ZeroConfConfirmed() {
+			return lnwire.ShortChannelID{}, false
+		}
+
+		return c.ZeroConfRealScid(), true
+
+	default:
+		return c.ShortChannelID, true
+	}
+}
+
+// aliasOnlyScids returns the confirmed SCIDs of the given channels that must
+// only be referred to by an alias, mapped to their funding outpoints.
+func aliasOnlyScids(
+	channels []*channeldb.OpenChannel) map[uint64]wire.OutPoint {
+
+	scids := make(map[uint64]wire.OutPoint)
+	for _, c := range channels {
+		isPublic := c.ChannelFlags&lnwire.FFAnnounceChannel != 0
+		if isPublic || !requiresAlias(c) {
+			continue
+		}
+
+		scid, ok := confirmedScid(c)
+		if !ok {
+			continue
+		}
+
+		scids[scid.ToUint64()] = c.FundingOutpoint
+	}
+
+	return scids
+}
+
+// FindScidLeaks decodes the payment requests of the given invoices and returns
+// every route hint that refers to one of the given channels by its confirmed
+// SCID, even though the channel must only be referred to by an alias. Invoices
+// without a decodable payment request, such as keysend invoices, are skipped.
+func FindScidLeaks(channels []*channeldb.OpenChannel,
+	invoiceList []invoices.Invoice, params *chaincfg.Params) []ScidLeak {
+
+	scids := aliasOnlyScids(channels)
+	if len(scids) == 0 {
+		return nil
+	}
+
+	var leaks []ScidLeak
+	for _, invoice := range invoiceList {
+		if len(invoice.PaymentRequest) == 0 {
+			continue
+		}
+
+		payReq, err := zpay32.Decode(
+			string(invoice.PaymentRequest), params,
+		)
+		if err != nil {
+			log.Debugf("Skipping invoice with add index %v in "+
+				"scid audit: %v", invoice.AddIndex, err)
+
+			continue
+		}
+
+		for _, route := range payReq.RouteHints {
+			for _, hint := range route {
+				chanPoint, ok := scids[hint.ChannelID]
+				if !ok {
+					continue
+				}
+
+				leaks = append(leaks, ScidLeak{
+					ChanPoint: chanPoint,
+					Scid: lnwire.NewShortChanIDFromInt(
+						hint.ChannelID,
+					),
+					AddIndex: invoice.AddIndex,
+				})
+			}
+		}
+	}
+
+	return leaks
+}
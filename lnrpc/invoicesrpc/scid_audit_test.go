@@ -0,0 +1,123 @@
+package invoicesrpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/zpay32"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFindScidLeaks asserts that only route hints referring to unannounced
+// option-scid-alias channels by their confirmed SCID are reported.
+func TestFindScidLeaks(t *testing.T) {
+	t.Parallel()
+
+	params := &chaincfg.RegressionNetParams
+
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	newChannel := func(index uint32, scid lnwire.ShortChannelID,
+		chanType channeldb.ChannelType,
+		flags lnwire.FundingFlag) *channeldb.OpenChannel {
+
+		return &channeldb.OpenChannel{
+			ChanType:        chanType,
+			ChannelFlags:    flags,
+			ShortChannelID:  scid,
+			FundingOutpoint: wire.OutPoint{Index: index},
+		}
+	}
+
+	var (
+		privateScid = lnwire.NewShortChanIDFromInt(1_000)
+		aliasScid   = lnwire.ShortChannelID{BlockHeight: 16_000_000}
+		publicScid  = lnwire.NewShortChanIDFromInt(2_000)
+		noAliasScid = lnwire.NewShortChanIDFromInt(3_000)
+		zeroConf    = lnwire.ShortChannelID{BlockHeight: 16_000_001}
+	)
+
+	channels := []*channeldb.OpenChannel{
+		newChannel(
+			0, privateScid, channeldb.ScidAliasFeatureBit, 0,
+		),
+		newChannel(
+			1, publicScid, channeldb.ScidAliasFeatureBit,
+			lnwire.FFAnnounceChannel,
+		),
+		newChannel(2, noAliasScid, channeldb.SingleFunderBit, 0),
+		newChannel(
+			3, zeroConf,
+			channeldb.ZeroConfBit|channeldb.ScidAliasChanBit, 0,
+		),
+	}
+
+	newPayReq := func(scids ...lnwire.ShortChannelID) []byte {
+		hints := make([]zpay32.HopHint, 0, len(scids))
+		for _, scid := range scids {
+			hints = append(hints, zpay32.HopHint{
+				NodeID:    privKey.PubKey(),
+				ChannelID: scid.ToUint64(),
+			})
+		}
+
+		invoice, err := zpay32.NewInvoice(
+			params, [32]byte{1}, time.Unix(1700000000, 0),
+			zpay32.Description("audit"),
+			zpay32.RouteHint(hints),
+		)
+		require.NoError(t, err)
+
+		payReq, err := invoice.Encode(zpay32.MessageSigner{
+			SignCompact: func(msg []byte) ([]byte, error) {
+				hash := chainhash.HashB(msg)
+
+				return ecdsa.SignCompact(privKey, hash, true),
+					nil
+			},
+		})
+		require.NoError(t, err)
+
+		return []byte(payReq)
+	}
+
+	invoiceList := []invoices.Invoice{
+		{
+			AddIndex:       1,
+			PaymentRequest: newPayReq(aliasScid, zeroConf),
+		},
+		{
+			// Keysend invoices don't have a payment request.
+			AddIndex: 2,
+		},
+		{
+			AddIndex: 3,
+			PaymentRequest: newPayReq(
+				publicScid, noAliasScid, privateScid,
+			),
+		},
+		{
+			AddIndex:       4,
+			PaymentRequest: []byte("invalid"),
+		},
+	}
+
+	leaks := FindScidLeaks(channels, invoiceList, params)
+	require.Equal(t, []ScidLeak{{
+		ChanPoint: wire.OutPoint{Index: 0},
+		Scid:      privateScid,
+		AddIndex:  3,
+	}}, leaks)
+
+	// Without channels that require an alias, nothing can leak.
+	require.Empty(t, FindScidLeaks(channels[1:], invoiceList, params))
+}
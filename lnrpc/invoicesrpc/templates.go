@@ -0,0 +1,721 @@
+package invoicesrpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/ticker"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// DefaultTemplateCheckInterval is the default interval at which the
+	// template manager checks whether recurring invoices are due.
+	DefaultTemplateCheckInterval = time.Minute
+
+	// MinRecurrenceInterval is the smallest interval at which invoices can
+	// be generated from a template.
+	MinRecurrenceInterval = time.Minute
+
+	// maxTemplateNameLen is the maximum length of a template name.
+	maxTemplateNameLen = 64
+)
+
+const (
+	templateValueType              tlv.Type = 0
+	templateMemoType               tlv.Type = 1
+	templateExpiryType             tlv.Type = 2
+	templateFallbackAddrType       tlv.Type = 3
+	templateCltvExpiryType         tlv.Type = 4
+	templatePrivateType            tlv.Type = 5
+	templateBlindedType            tlv.Type = 6
+	templateMinNumRealHopsType     tlv.Type = 7
+	templateNumHopsType            tlv.Type = 8
+	templateMaxNumPathsType        tlv.Type = 9
+	templateRecurrenceIntervalType tlv.Type = 10
+	templateLastMintedType         tlv.Type = 11
+)
+
+var (
+	// invoiceTemplateBucket is the top level bucket that stores all
+	// invoice templates, keyed by their name.
+	invoiceTemplateBucket = []byte("invoice-templates")
+
+	// ErrTemplateExists is returned when a template is added with a name
+	// that is already in use.
+	ErrTemplateExists = errors.New("invoice template already exists")
+
+	// ErrTemplateNotFound is returned when a template can't be found.
+	ErrTemplateNotFound = errors.New("invoice template not found")
+)
+
+// BlindedPathPolicy defines how the blinded paths of invoices minted from a
+// template are constructed. Unset values fall back to the node's defaults.
+type BlindedPathPolicy struct {
+	// MinNumRealHops is the minimum number of real hops to include in a
+	// blinded path.
+	MinNumRealHops fn.Option[uint8]
+
+	// NumHops is the number of hops that each blinded path should
+	// consist of.
+	NumHops fn.Option[uint8]
+
+	// MaxNumPaths is the maximum number of blinded paths to include in an
+	// invoice.
+	MaxNumPaths fn.Option[uint8]
+}
+
+// InvoiceTemplate holds the parameters that are used to mint new invoices.
+type InvoiceTemplate struct {
+	// Name is the unique name of the template.
+	Name string
+
+	// Value is the value of the minted invoices. A zero value creates
+	// invoices that can be paid with any amount.
+	Value lnwire.MilliSatoshi
+
+	// Memo is the memo attached to the minted invoices.
+	Memo string
+
+	// Expiry is the expiry of the minted invoices. If zero, the default
+	// invoice expiry is used.
+	Expiry time.Duration
+
+	// FallbackAddr is an optional on-chain fallback address.
+	FallbackAddr string
+
+	// CltvExpiry is the delta of the final hop's time-lock. If zero, the
+	// default delta is used.
+	CltvExpiry uint64
+
+	// Private indicates whether the minted invoices should include
+	// routing hints for private channels.
+	Private bool
+
+	// BlindedPaths, if set, instructs to add blinded paths to the minted
+	// invoices instead of revealing the node's public key.
+	BlindedPaths fn.Option[BlindedPathPolicy]
+
+	// RecurrenceInterval, if non-zero, is the interval at which new
+	// invoices are minted from the template automatically.
+	RecurrenceInterval time.Duration
+
+	// LastMinted is the time at which an invoice was last minted
+	// automatically from the template.
+	LastMinted time.Time
+}
+
+// Validate checks that the template is sane.
+func (t *InvoiceTemplate) Validate() error {
+	switch {
+	case len(t.Name) == 0:
+		return errors.New("template name must be set")
+
+	case len(t.Name) > maxTemplateNameLen:
+		return fmt.Errorf("template name exceeds %d characters",
+			maxTemplateNameLen)
+
+	case t.Expiry < 0:
+		return errors.New("template expiry must not be negative")
+
+	case t.RecurrenceInterval < 0:
+		return errors.New("template recurrence interval must not be " +
+			"negative")
+
+	case t.RecurrenceInterval != 0 &&
+		t.RecurrenceInterval < MinRecurrenceInterval:
+
+		return fmt.Errorf("template recurrence interval must be at "+
+			"least %v", MinRecurrenceInterval)
+
+	case t.BlindedPaths.IsSome() && t.Private:
+		return errors.New("private hop hints can't be combined with " +
+			"blinded paths")
+	}
+
+	return nil
+}
+
+// invoice returns the rpc invoice that is used to mint a new invoice from the
+// template.
+func (t *InvoiceTemplate) invoice() *lnrpc.Invoice {
+	invoice := &lnrpc.Invoice{
+		Memo:         t.Memo,
+		ValueMsat:    int64(t.Value),
+		Expiry:       int64(t.Expiry.Seconds()),
+		FallbackAddr: t.FallbackAddr,
+		CltvExpiry:   t.CltvExpiry,
+		Private:      t.Private,
+	}
+
+	t.BlindedPaths.WhenSome(func(policy BlindedPathPolicy) {
+		toUint32 := func(o fn.Option[uint8]) *uint32 {
+			var val *uint32
+			o.WhenSome(func(v uint8) {
+				v32 := uint32(v)
+				val = &v32
+			})
+
+			return val
+		}
+
+		invoice.IsBlinded = true
+		invoice.BlindedPathConfig = &lnrpc.BlindedPathConfig{
+			MinNumRealHops: toUint32(policy.MinNumRealHops),
+			NumHops:        toUint32(policy.NumHops),
+			MaxNumPaths:    toUint32(policy.MaxNumPaths),
+		}
+	})
+
+	return invoice
+}
+
+// UnmarshalInvoiceTemplate creates an invoice template from its RPC
+// counterpart. The time of the last automatically minted invoice is ignored.
+func UnmarshalInvoiceTemplate(info *InvoiceTemplateInfo) (*InvoiceTemplate,
+	error) {
+
+	if info.Expiry < 0 {
+		return nil, errors.New("template expiry must not be negative")
+	}
+
+	template := &InvoiceTemplate{
+		Name:         info.Name,
+		Value:        lnwire.MilliSatoshi(info.ValueMsat),
+		Memo:         info.Memo,
+		Expiry:       time.Duration(info.Expiry) * time.Second,
+		FallbackAddr: info.FallbackAddr,
+		CltvExpiry:   info.CltvExpiry,
+		Private:      info.Private,
+		RecurrenceInterval: time.Duration(info.RecurrenceInterval) *
+			time.Second,
+	}
+
+	if !info.IsBlinded {
+		if info.BlindedPathConfig != nil {
+			return nil, errors.New("blinded path config requires " +
+				"is_blinded to be set")
+		}
+
+		return template, nil
+	}
+
+	var policy BlindedPathPolicy
+	if cfg := info.BlindedPathConfig; cfg != nil {
+		if len(cfg.NodeOmissionList) > 0 {
+			return nil, errors.New("node omission lists aren't " +
+				"supported by invoice templates")
+		}
+
+		toUint8 := func(val *uint32) (fn.Option[uint8], error) {
+			if val == nil {
+				return fn.None[uint8](), nil
+			}
+
+			if *val > math.MaxUint8 {
+				return fn.None[uint8](), fmt.Errorf("blinded "+
+					"path config value %d exceeds %d", *val,
+					math.MaxUint8)
+			}
+
+			return fn.Some(uint8(*val)), nil
+		}
+
+		var err error
+		policy.MinNumRealHops, err = toUint8(cfg.MinNumRealHops)
+		if err != nil {
+			return nil, err
+		}
+		policy.NumHops, err = toUint8(cfg.NumHops)
+		if err != nil {
+			return nil, err
+		}
+		policy.MaxNumPaths, err = toUint8(cfg.MaxNumPaths)
+		if err != nil {
+			return nil, err
+		}
+	}
+	template.BlindedPaths = fn.Some(policy)
+
+	return template, nil
+}
+
+// MarshalInvoiceTemplate converts an invoice template into its RPC
+// counterpart.
+func MarshalInvoiceTemplate(t *InvoiceTemplate) *InvoiceTemplateInfo {
+	info := &InvoiceTemplateInfo{
+		Name:               t.Name,
+		ValueMsat:          uint64(t.Value),
+		Memo:               t.Memo,
+		Expiry:             int64(t.Expiry.Seconds()),
+		FallbackAddr:       t.FallbackAddr,
+		CltvExpiry:         t.CltvExpiry,
+		Private:            t.Private,
+		RecurrenceInterval: uint64(t.RecurrenceInterval.Seconds()),
+	}
+	if !t.LastMinted.IsZero() {
+		info.LastMinted = t.LastMinted.Unix()
+	}
+
+	// The blinded path config of the template's invoice is the RPC
+	// representation of the template's policy.
+	invoice := t.invoice()
+	info.IsBlinded = invoice.IsBlinded
+	info.BlindedPathConfig = invoice.BlindedPathConfig
+
+	return info
+}
+
+// encode writes the template, except for its name which is used as the key,
+// to the passed writer.
+func (t *InvoiceTemplate) encode(w io.Writer) error {
+	var (
+		value              = uint64(t.Value)
+		memo               = []byte(t.Memo)
+		expiry             = uint64(t.Expiry)
+		fallbackAddr       = []byte(t.FallbackAddr)
+		cltvExpiry         = t.CltvExpiry
+		private            = t.Private
+		recurrenceInterval = uint64(t.RecurrenceInterval)
+		lastMinted         uint64
+	)
+	if !t.LastMinted.IsZero() {
+		lastMinted = uint64(t.LastMinted.UnixNano())
+	}
+
+	records := []tlv.Record{
+		tlv.MakePrimitiveRecord(templateValueType, &value),
+		tlv.MakePrimitiveRecord(templateMemoType, &memo),
+		tlv.MakePrimitiveRecord(templateExpiryType, &expiry),
+		tlv.MakePrimitiveRecord(
+			templateFallbackAddrType, &fallbackAddr,
+		),
+		tlv.MakePrimitiveRecord(templateCltvExpiryType, &cltvExpiry),
+		tlv.MakePrimitiveRecord(templatePrivateType, &private),
+	}
+
+	t.BlindedPaths.WhenSome(func(policy BlindedPathPolicy) {
+		blinded := true
+		records = append(records, tlv.MakePrimitiveRecord(
+			templateBlindedType, &blinded,
+		))
+
+		optionalRecord := func(typ tlv.Type, o fn.Option[uint8]) {
+			o.WhenSome(func(v uint8) {
+				record := tlv.MakePrimitiveRecord(typ, &v)
+				records = append(records, record)
+			})
+		}
+		optionalRecord(
+			templateMinNumRealHopsType, policy.MinNumRealHops,
+		)
+		optionalRecord(templateNumHopsType, policy.NumHops)
+		optionalRecord(templateMaxNumPathsType, policy.MaxNumPaths)
+	})
+
+	records = append(records,
+		tlv.MakePrimitiveRecord(
+			templateRecurrenceIntervalType, &recurrenceInterval,
+		),
+		tlv.MakePrimitiveRecord(templateLastMintedType, &lastMinted),
+	)
+
+	stream, err := tlv.NewStream(records...)
+	if err != nil {
+		return err
+	}
+
+	return stream.Encode(w)
+}
+
+// decodeInvoiceTemplate reads a template with the given name from the passed
+// reader.
+func decodeInvoiceTemplate(name string, r io.Reader) (*InvoiceTemplate,
+	error) {
+
+	var (
+		value              uint64
+		memo               []byte
+		expiry             uint64
+		fallbackAddr       []byte
+		cltvExpiry         uint64
+		private            bool
+		blinded            bool
+		minNumRealHops     uint8
+		numHops            uint8
+		maxNumPaths        uint8
+		recurrenceInterval uint64
+		lastMinted         uint64
+	)
+
+	stream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(templateValueType, &value),
+		tlv.MakePrimitiveRecord(templateMemoType, &memo),
+		tlv.MakePrimitiveRecord(templateExpiryType, &expiry),
+		tlv.MakePrimitiveRecord(
+			templateFallbackAddrType, &fallbackAddr,
+		),
+		tlv.MakePrimitiveRecord(templateCltvExpiryType, &cltvExpiry),
+		tlv.MakePrimitiveRecord(templatePrivateType, &private),
+		tlv.MakePrimitiveRecord(templateBlindedType, &blinded),
+		tlv.MakePrimitiveRecord(
+			templateMinNumRealHopsType, &minNumRealHops,
+		),
+		tlv.MakePrimitiveRecord(templateNumHopsType, &numHops),
+		tlv.MakePrimitiveRecord(templateMaxNumPathsType, &maxNumPaths),
+		tlv.MakePrimitiveRecord(
+			templateRecurrenceIntervalType, &recurrenceInterval,
+		),
+		tlv.MakePrimitiveRecord(templateLastMintedType, &lastMinted),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedTypes, err := stream.DecodeWithParsedTypes(r)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &InvoiceTemplate{
+		Name:               name,
+		Value:              lnwire.MilliSatoshi(value),
+		Memo:               string(memo),
+		Expiry:             time.Duration(expiry),
+		FallbackAddr:       string(fallbackAddr),
+		CltvExpiry:         cltvExpiry,
+		Private:            private,
+		RecurrenceInterval: time.Duration(recurrenceInterval),
+	}
+	if lastMinted != 0 {
+		template.LastMinted = time.Unix(0, int64(lastMinted))
+	}
+
+	if blinded {
+		optional := func(typ tlv.Type, v uint8) fn.Option[uint8] {
+			if _, ok := parsedTypes[typ]; ok {
+				return fn.Some(v)
+			}
+
+			return fn.None[uint8]()
+		}
+
+		template.BlindedPaths = fn.Some(BlindedPathPolicy{
+			MinNumRealHops: optional(
+				templateMinNumRealHopsType, minNumRealHops,
+			),
+			NumHops: optional(templateNumHopsType, numHops),
+			MaxNumPaths: optional(
+				templateMaxNumPathsType, maxNumPaths,
+			),
+		})
+	}
+
+	return template, nil
+}
+
+// TemplateManagerConfig houses the dependencies of the TemplateManager.
+type TemplateManagerConfig struct {
+	// DB is the database that the templates are persisted in.
+	DB kvdb.Backend
+
+	// AddInvoice is used to add a new invoice to the invoice registry.
+	AddInvoice func(ctx context.Context,
+		invoice *lnrpc.Invoice) (*lnrpc.AddInvoiceResponse, error)
+
+	// Clock is used to determine whether recurring invoices are due.
+	Clock clock.Clock
+
+	// Ticker is used to periodically check whether recurring invoices are
+	// due.
+	Ticker ticker.Ticker
+}
+
+// TemplateManager manages invoice templates. It allows invoices to be minted
+// from a template on demand and generates invoices from templates with a
+// recurrence interval on schedule, which eases the integration of
+// subscription-style payments.
+type TemplateManager struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *TemplateManagerConfig
+
+	// templates is the in-memory view of all templates, keyed by name.
+	templates map[string]*InvoiceTemplate
+
+	mu sync.Mutex
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// NewTemplateManager creates a new template manager from the given config.
+func NewTemplateManager(cfg *TemplateManagerConfig) *TemplateManager {
+	return &TemplateManager{
+		cfg:       cfg,
+		templates: make(map[string]*InvoiceTemplate),
+		quit:      make(chan struct{}),
+	}
+}
+
+// Start loads all templates from disk and launches the goroutine that mints
+// recurring invoices.
+func (m *TemplateManager) Start() error {
+	var startErr error
+	m.started.Do(func() {
+		log.Info("Invoice template manager starting")
+
+		templates, err := fetchInvoiceTemplates(m.cfg.DB)
+		if err != nil {
+			startErr = err
+			return
+		}
+
+		m.mu.Lock()
+		for _, t := range templates {
+			m.templates[t.Name] = t
+		}
+		m.mu.Unlock()
+
+		m.cfg.Ticker.Resume()
+
+		m.wg.Add(1)
+		go m.run()
+	})
+
+	return startErr
+}
+
+// Stop signals the template manager to shut down and waits for it to exit.
+func (m *TemplateManager) Stop() error {
+	m.stopped.Do(func() {
+		log.Info("Invoice template manager shutting down...")
+		defer log.Debug("Invoice template manager shutdown complete")
+
+		close(m.quit)
+		m.wg.Wait()
+
+		m.cfg.Ticker.Stop()
+	})
+
+	return nil
+}
+
+// AddTemplate validates and persists a new template.
+func (m *TemplateManager) AddTemplate(template *InvoiceTemplate) error {
+	if err := template.Validate(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.templates[template.Name]; ok {
+		return fmt.Errorf("%w: %v", ErrTemplateExists, template.Name)
+	}
+
+	// A template doesn't mint any invoices when it's created, so the
+	// first recurring invoice is due one interval from now.
+	t := *template
+	t.LastMinted = m.cfg.Clock.Now()
+
+	return m.storeTemplate(&t)
+}
+
+// RemoveTemplate removes the template with the given name.
+func (m *TemplateManager) RemoveTemplate(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.templates[name]; !ok {
+		return fmt.Errorf("%w: %v", ErrTemplateNotFound, name)
+	}
+
+	err := kvdb.Update(m.cfg.DB, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(invoiceTemplateBucket)
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.Delete([]byte(name))
+	}, func() {})
+	if err != nil {
+		return err
+	}
+
+	delete(m.templates, name)
+
+	return nil
+}
+
+// ListTemplates returns all templates ordered by name.
+func (m *TemplateManager) ListTemplates() []*InvoiceTemplate {
+	m.mu.Lock()
+	templates := make([]*InvoiceTemplate, 0, len(m.templates))
+	for _, t := range m.templates {
+		template := *t
+		templates = append(templates, &template)
+	}
+	m.mu.Unlock()
+
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+
+	return templates
+}
+
+// MintInvoice creates a new invoice from the template with the given name.
+func (m *TemplateManager) MintInvoice(ctx context.Context,
+	name string) (*lnrpc.AddInvoiceResponse, error) {
+
+	m.mu.Lock()
+	template, ok := m.templates[name]
+	m.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrTemplateNotFound, name)
+	}
+
+	return m.cfg.AddInvoice(ctx, template.invoice())
+}
+
+// run is the main event loop of the template manager.
+//
+// NOTE: This MUST be run as a goroutine.
+func (m *TemplateManager) run() {
+	defer m.wg.Done()
+
+	for {
+		select {
+		case <-m.cfg.Ticker.Ticks():
+			m.mintRecurringInvoices()
+
+		case <-m.quit:
+			return
+		}
+	}
+}
+
+// mintRecurringInvoices mints an invoice for every recurring template that is
+// due.
+func (m *TemplateManager) mintRecurringInvoices() {
+	now := m.cfg.Clock.Now()
+
+	m.mu.Lock()
+	var due []*InvoiceTemplate
+	for _, t := range m.templates {
+		if t.RecurrenceInterval == 0 {
+			continue
+		}
+
+		if now.Before(t.LastMinted.Add(t.RecurrenceInterval)) {
+			continue
+		}
+
+		template := *t
+		due = append(due, &template)
+	}
+	m.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for _, t := range due {
+		resp, err := m.cfg.AddInvoice(ctx, t.invoice())
+		if err != nil {
+			log.Errorf("Unable to mint recurring invoice from "+
+				"template %v: %v", t.Name, err)
+
+			continue
+		}
+
+		log.Infof("Minted recurring invoice %x from template %v",
+			resp.RHash, t.Name)
+
+		t.LastMinted = now
+
+		m.mu.Lock()
+
+		// Only update the template if it hasn't been removed in the
+		// meantime.
+		if _, ok := m.templates[t.Name]; ok {
+			err = m.storeTemplate(t)
+		}
+		m.mu.Unlock()
+
+		if err != nil {
+			log.Errorf("Unable to update template %v: %v", t.Name,
+				err)
+		}
+	}
+}
+
+// storeTemplate persists the template and updates the in-memory view.
+//
+// NOTE: The caller MUST hold the manager's mutex.
+func (m *TemplateManager) storeTemplate(t *InvoiceTemplate) error {
+	var b bytes.Buffer
+	if err := t.encode(&b); err != nil {
+		return err
+	}
+
+	err := kvdb.Update(m.cfg.DB, func(tx kvdb.RwTx) error {
+		bucket, err := tx.CreateTopLevelBucket(invoiceTemplateBucket)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put([]byte(t.Name), b.Bytes())
+	}, func() {})
+	if err != nil {
+		return err
+	}
+
+	m.templates[t.Name] = t
+
+	return nil
+}
+
+// fetchInvoiceTemplates reads all templates from disk.
+func fetchInvoiceTemplates(db kvdb.Backend) ([]*InvoiceTemplate, error) {
+	var templates []*InvoiceTemplate
+	err := kvdb.View(db, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(invoiceTemplateBucket)
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.ForEach(func(k, v []byte) error {
+			t, err := decodeInvoiceTemplate(
+				string(k), bytes.NewReader(v),
+			)
+			if err != nil {
+				return err
+			}
+
+			templates = append(templates, t)
+
+			return nil
+		})
+	}, func() {
+		templates = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
@@ -0,0 +1,245 @@
+package invoicesrpc
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/ticker"
+	"github.com/stretchr/testify/require"
+)
+
+// TestInvoiceTemplateEncoding tests that templates survive an encoding round
+// trip.
+func TestInvoiceTemplateEncoding(t *testing.T) {
+	t.Parallel()
+
+	templates := []*InvoiceTemplate{
+		{
+			Name: "empty",
+		},
+		{
+			Name:               "full",
+			Value:              1000,
+			Memo:               "subscription",
+			Expiry:             time.Hour,
+			FallbackAddr:       "bcrt1qfallback",
+			CltvExpiry:         80,
+			Private:            true,
+			RecurrenceInterval: 24 * time.Hour,
+			LastMinted:         time.Unix(0, 1234),
+		},
+		{
+			Name: "blinded",
+			BlindedPaths: fn.Some(BlindedPathPolicy{
+				MinNumRealHops: fn.Some(uint8(0)),
+				NumHops:        fn.None[uint8](),
+				MaxNumPaths:    fn.Some(uint8(3)),
+			}),
+		},
+	}
+
+	for _, template := range templates {
+		var b bytes.Buffer
+		require.NoError(t, template.encode(&b))
+
+		decoded, err := decodeInvoiceTemplate(template.Name, &b)
+		require.NoError(t, err)
+		require.Equal(t, template, decoded)
+	}
+}
+
+// TestInvoiceTemplateRPCConversion tests that templates survive a conversion
+// to their RPC representation and back, and that invalid RPC templates are
+// rejected.
+func TestInvoiceTemplateRPCConversion(t *testing.T) {
+	t.Parallel()
+
+	templates := []*InvoiceTemplate{
+		{
+			Name:               "full",
+			Value:              1000,
+			Memo:               "subscription",
+			Expiry:             time.Hour,
+			FallbackAddr:       "bcrt1qfallback",
+			CltvExpiry:         80,
+			Private:            true,
+			RecurrenceInterval: 24 * time.Hour,
+		},
+		{
+			Name: "blinded",
+			BlindedPaths: fn.Some(BlindedPathPolicy{
+				MinNumRealHops: fn.Some(uint8(0)),
+				NumHops:        fn.None[uint8](),
+				MaxNumPaths:    fn.Some(uint8(3)),
+			}),
+		},
+	}
+
+	for _, template := range templates {
+		info := MarshalInvoiceTemplate(template)
+
+		unmarshaled, err := UnmarshalInvoiceTemplate(info)
+		require.NoError(t, err)
+		require.Equal(t, template, unmarshaled)
+	}
+
+	// The time of the last minted invoice is only reported.
+	lastMinted := time.Unix(1234, 0)
+	info := MarshalInvoiceTemplate(&InvoiceTemplate{
+		Name:       "minted",
+		LastMinted: lastMinted,
+	})
+	require.Equal(t, lastMinted.Unix(), info.LastMinted)
+
+	tooLarge := uint32(256)
+	invalid := []*InvoiceTemplateInfo{
+		{
+			Name:   "negative expiry",
+			Expiry: -1,
+		},
+		{
+			Name:              "not blinded",
+			BlindedPathConfig: &lnrpc.BlindedPathConfig{},
+		},
+		{
+			Name:      "too many paths",
+			IsBlinded: true,
+			BlindedPathConfig: &lnrpc.BlindedPathConfig{
+				MaxNumPaths: &tooLarge,
+			},
+		},
+	}
+	for _, info := range invalid {
+		_, err := UnmarshalInvoiceTemplate(info)
+		require.Error(t, err, info.Name)
+	}
+}
+
+// TestTemplateManager tests that invoices are minted from templates on demand
+// and on schedule, and that templates are persisted.
+func TestTemplateManager(t *testing.T) {
+	t.Parallel()
+
+	db, err := kvdb.Create(
+		kvdb.BoltBackendName, filepath.Join(t.TempDir(), "test.db"),
+		true, kvdb.DefaultDBTimeout,
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	testClock := clock.NewTestClock(time.Unix(1000, 0))
+	invoices := make(chan *lnrpc.Invoice, 1)
+
+	var (
+		manager     *TemplateManager
+		forceTicker *ticker.Force
+	)
+	start := func() {
+		forceTicker = ticker.NewForce(time.Hour)
+		manager = NewTemplateManager(&TemplateManagerConfig{
+			DB: db,
+			AddInvoice: func(_ context.Context,
+				invoice *lnrpc.Invoice) (
+				*lnrpc.AddInvoiceResponse, error) {
+
+				invoices <- invoice
+
+				return &lnrpc.AddInvoiceResponse{}, nil
+			},
+			Clock:  testClock,
+			Ticker: forceTicker,
+		})
+		require.NoError(t, manager.Start())
+	}
+	start()
+
+	// Invalid templates should be rejected.
+	err = manager.AddTemplate(&InvoiceTemplate{})
+	require.Error(t, err)
+
+	err = manager.AddTemplate(&InvoiceTemplate{
+		Name:               "too-frequent",
+		RecurrenceInterval: time.Second,
+	})
+	require.Error(t, err)
+
+	template := &InvoiceTemplate{
+		Name:               "monthly",
+		Value:              5000,
+		Memo:               "subscription",
+		Expiry:             time.Hour,
+		RecurrenceInterval: 30 * 24 * time.Hour,
+		BlindedPaths: fn.Some(BlindedPathPolicy{
+			MaxNumPaths: fn.Some(uint8(2)),
+		}),
+	}
+	require.NoError(t, manager.AddTemplate(template))
+
+	err = manager.AddTemplate(template)
+	require.ErrorIs(t, err, ErrTemplateExists)
+
+	// Minting an invoice on demand should use the template's values.
+	maxNumPaths := uint32(2)
+	expectedInvoice := &lnrpc.Invoice{
+		Memo:      "subscription",
+		ValueMsat: 5000,
+		Expiry:    3600,
+		IsBlinded: true,
+		BlindedPathConfig: &lnrpc.BlindedPathConfig{
+			MaxNumPaths: &maxNumPaths,
+		},
+	}
+
+	_, err = manager.MintInvoice(context.Background(), "monthly")
+	require.NoError(t, err)
+	require.Equal(t, expectedInvoice, <-invoices)
+
+	_, err = manager.MintInvoice(context.Background(), "unknown")
+	require.ErrorIs(t, err, ErrTemplateNotFound)
+
+	// The template should survive a restart.
+	require.NoError(t, manager.Stop())
+	start()
+	t.Cleanup(func() {
+		require.NoError(t, manager.Stop())
+	})
+
+	templates := manager.ListTemplates()
+	require.Len(t, templates, 1)
+	require.Equal(t, template.Name, templates[0].Name)
+	require.Equal(t, testClock.Now(), templates[0].LastMinted)
+
+	// No recurring invoice should be minted before the interval elapsed.
+	forceTicker.Force <- time.Time{}
+	select {
+	case <-invoices:
+		t.Fatal("recurring invoice minted too early")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Once the interval elapsed, a new invoice should be minted.
+	testClock.SetTime(testClock.Now().Add(template.RecurrenceInterval))
+	forceTicker.Force <- time.Time{}
+	select {
+	case invoice := <-invoices:
+		require.Equal(t, expectedInvoice, invoice)
+	case <-time.After(time.Second):
+		t.Fatal("recurring invoice not minted")
+	}
+
+	// Removing the template should stop the recurring invoices.
+	require.NoError(t, manager.RemoveTemplate("monthly"))
+	require.Empty(t, manager.ListTemplates())
+
+	err = manager.RemoveTemplate("monthly")
+	require.ErrorIs(t, err, ErrTemplateNotFound)
+}
@@ -3,6 +3,7 @@ package invoicesrpc
 import (
 	"encoding/hex"
 	"fmt"
+	"sort"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/chaincfg"
@@ -160,6 +161,27 @@ func CreateRPCInvoice(invoice *invoices.Invoice,
 		rpcHtlcs = append(rpcHtlcs, &rpcHtlc)
 	}
 
+	// The htlcs are stored in a map, so we sort them by the order in which
+	// they were accepted to return them in a stable order. This allows
+	// callers, e.g. of hold invoices, to tell over which channels the
+	// parts of a payment arrived and in which order.
+	sort.Slice(rpcHtlcs, func(i, j int) bool {
+		a, b := rpcHtlcs[i], rpcHtlcs[j]
+		switch {
+		case a.AcceptHeight != b.AcceptHeight:
+			return a.AcceptHeight < b.AcceptHeight
+
+		case a.AcceptTime != b.AcceptTime:
+			return a.AcceptTime < b.AcceptTime
+
+		case a.ChanId != b.ChanId:
+			return a.ChanId < b.ChanId
+
+		default:
+			return a.HtlcIndex < b.HtlcIndex
+		}
+	})
+
 	rpcInvoice := &lnrpc.Invoice{
 		Memo:            string(invoice.Memo),
 		RHash:           rHash,
@@ -187,6 +209,13 @@ func CreateRPCInvoice(invoice *invoices.Invoice,
 		PaymentAddr:     invoice.Terms.PaymentAddr[:],
 		IsAmp:           invoice.IsAMP(),
 		IsBlinded:       invoice.IsBlinded(),
+		History: CreateRPCInvoiceHistory(
+			invoices.InvoiceHistory(invoice),
+		),
+		AmtOverpaidMsat: uint64(invoice.AmtOverpaid()),
+		OverpaymentPolicy: CreateRPCOverpaymentPolicy(
+			invoice.OverpaymentPolicy,
+		),
 	}
 
 	rpcInvoice.AmpInvoiceState = make(map[string]*lnrpc.AMPInvoiceState)
@@ -228,6 +257,157 @@ func CreateRPCInvoice(invoice *invoices.Invoice,
 	return rpcInvoice, nil
 }
 
+// CreateRPCInvoiceHistory converts the state transitions of an invoice into
+// their lnrpc counterparts.
+func CreateRPCInvoiceHistory(
+	history []invoices.InvoiceEvent) []*lnrpc.InvoiceEvent {
+
+	rpcHistory := make([]*lnrpc.InvoiceEvent, 0, len(history))
+	for _, event := range history {
+		rpcEvent := &lnrpc.InvoiceEvent{
+			Type:    lnrpc.InvoiceEventType(event.Type),
+			AmtMsat: uint64(event.Amt),
+		}
+		if !event.Timestamp.IsZero() {
+			rpcEvent.Timestamp = event.Timestamp.Unix()
+		}
+		if event.CircuitKey != nil {
+			rpcEvent.ChanId = event.CircuitKey.ChanID.ToUint64()
+			rpcEvent.HtlcIndex = event.CircuitKey.HtlcID
+		}
+
+		rpcHistory = append(rpcHistory, rpcEvent)
+	}
+
+	return rpcHistory
+}
+
+// CreateRPCOverpaymentPolicy converts an invoice's overpayment policy into
+// its lnrpc counterpart. Nil is returned for the default policy.
+func CreateRPCOverpaymentPolicy(
+	policy invoices.OverpaymentPolicy) *lnrpc.OverpaymentPolicy {
+
+	if policy.IsDefault() {
+		return nil
+	}
+
+	return &lnrpc.OverpaymentPolicy{
+		Mode:       lnrpc.OverpaymentMode(policy.Mode),
+		MaxPercent: policy.MaxPercent,
+	}
+}
+
+// UnmarshalOverpaymentPolicy converts an lnrpc overpayment policy into the
+// one used by the invoice registry. A nil policy results in the default one.
+func UnmarshalOverpaymentPolicy(
+	policy *lnrpc.OverpaymentPolicy) (invoices.OverpaymentPolicy, error) {
+
+	if policy == nil {
+		return invoices.OverpaymentPolicy{}, nil
+	}
+
+	var mode invoices.OverpaymentMode
+	switch policy.Mode {
+	case lnrpc.OverpaymentMode_OVERPAYMENT_DEFAULT:
+		mode = invoices.OverpaymentModeDefault
+
+	case lnrpc.OverpaymentMode_OVERPAYMENT_ACCEPT:
+		mode = invoices.OverpaymentModeAccept
+
+	case lnrpc.OverpaymentMode_OVERPAYMENT_LIMIT:
+		mode = invoices.OverpaymentModeLimit
+
+	case lnrpc.OverpaymentMode_OVERPAYMENT_REJECT:
+		mode = invoices.OverpaymentModeReject
+
+	default:
+		return invoices.OverpaymentPolicy{}, fmt.Errorf("unknown "+
+			"overpayment mode: %v", policy.Mode)
+	}
+
+	overpaymentPolicy := invoices.OverpaymentPolicy{
+		Mode:       mode,
+		MaxPercent: policy.MaxPercent,
+	}
+	if err := overpaymentPolicy.Validate(); err != nil {
+		return invoices.OverpaymentPolicy{}, err
+	}
+
+	return overpaymentPolicy, nil
+}
+
+// CreateRPCOffer converts a BOLT 12 offer into its RPC counterpart.
+func CreateRPCOffer(offer *invoices.Offer) (*Offer, error) {
+	id, err := offer.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	rpcOffer := &Offer{
+		OfferId:     id[:],
+		Metadata:    offer.Metadata,
+		AmountMsat:  uint64(offer.Amount),
+		Description: offer.Description,
+		Issuer:      offer.Issuer,
+	}
+	for _, chain := range offer.Chains {
+		rpcOffer.Chains = append(rpcOffer.Chains, chain[:])
+	}
+	if !offer.AbsoluteExpiry.IsZero() {
+		rpcOffer.AbsoluteExpiry = offer.AbsoluteExpiry.Unix()
+	}
+	offer.QuantityMax.WhenSome(func(quantityMax uint64) {
+		rpcOffer.AllowQuantity = true
+		rpcOffer.QuantityMax = quantityMax
+	})
+	if offer.IssuerID != nil {
+		rpcOffer.IssuerId = offer.IssuerID.SerializeCompressed()
+	}
+
+	for _, path := range offer.Paths {
+		rpcPath := &lnrpc.BlindedPath{
+			IntroductionNode: path.IntroductionNode.
+				SerializeCompressed(),
+			BlindingPoint: path.BlindingPoint.SerializeCompressed(),
+		}
+		for _, hop := range path.Hops {
+			rpcPath.BlindedHops = append(
+				rpcPath.BlindedHops, &lnrpc.BlindedHop{
+					BlindedNode: hop.BlindedNodeID.
+						SerializeCompressed(),
+					EncryptedData: hop.EncryptedData,
+				},
+			)
+		}
+
+		rpcOffer.Paths = append(rpcOffer.Paths, rpcPath)
+	}
+
+	return rpcOffer, nil
+}
+
+// CreateRPCOfferState converts the state of an offer issued by our node into
+// its RPC counterpart.
+func CreateRPCOfferState(state *invoices.OfferState) (*OfferState, error) {
+	encoded, err := state.Offer.EncodeString()
+	if err != nil {
+		return nil, err
+	}
+
+	rpcOffer, err := CreateRPCOffer(state.Offer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OfferState{
+		EncodedOffer: encoded,
+		Offer:        rpcOffer,
+		CreationDate: state.CreationDate.Unix(),
+		NumPayments:  state.NumPayments,
+		AmtPaidMsat:  uint64(state.AmtPaid),
+	}, nil
+}
+
 // CreateRPCFeatures maps a feature vector into a list of lnrpc.Features.
 func CreateRPCFeatures(fv *lnwire.FeatureVector) map[uint32]*lnrpc.Feature {
 	if fv == nil {
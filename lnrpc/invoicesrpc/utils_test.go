@@ -0,0 +1,278 @@
+package invoicesrpc
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCreateRPCInvoiceHtlcs tests that the accepted htlcs of an invoice are
+// returned with their details in the order they were accepted.
+func TestCreateRPCInvoiceHtlcs(t *testing.T) {
+	t.Parallel()
+
+	acceptTime := time.Unix(1000, 0)
+	chanA := lnwire.NewShortChanIDFromInt(2)
+	chanB := lnwire.NewShortChanIDFromInt(1)
+
+	invoice := &invoices.Invoice{
+		State: invoices.ContractAccepted,
+		Htlcs: map[invoices.CircuitKey]*invoices.InvoiceHTLC{
+			{ChanID: chanA, HtlcID: 5}: {
+				Amt:          3000,
+				MppTotalAmt:  5000,
+				AcceptHeight: 101,
+				AcceptTime:   acceptTime,
+				Expiry:       200,
+				State:        invoices.HtlcStateAccepted,
+			},
+			{ChanID: chanA, HtlcID: 4}: {
+				Amt:          1000,
+				MppTotalAmt:  5000,
+				AcceptHeight: 100,
+				AcceptTime:   acceptTime,
+				Expiry:       190,
+				State:        invoices.HtlcStateAccepted,
+				CustomRecords: record.CustomSet{
+					65536: []byte{1, 2, 3},
+				},
+			},
+			{ChanID: chanB, HtlcID: 7}: {
+				Amt:          1000,
+				MppTotalAmt:  5000,
+				AcceptHeight: 100,
+				AcceptTime:   acceptTime,
+				Expiry:       195,
+				State:        invoices.HtlcStateAccepted,
+			},
+		},
+	}
+
+	rpcInvoice, err := CreateRPCInvoice(
+		invoice, &chaincfg.RegressionNetParams,
+	)
+	require.NoError(t, err)
+	require.Equal(t, lnrpc.Invoice_ACCEPTED, rpcInvoice.State)
+	require.Len(t, rpcInvoice.Htlcs, 3)
+
+	expected := []struct {
+		chanID       uint64
+		htlcIndex    uint64
+		expiryHeight int32
+	}{
+		{chanB.ToUint64(), 7, 195},
+		{chanA.ToUint64(), 4, 190},
+		{chanA.ToUint64(), 5, 200},
+	}
+	for i, htlc := range rpcInvoice.Htlcs {
+		require.Equal(t, expected[i].chanID, htlc.ChanId)
+		require.Equal(t, expected[i].htlcIndex, htlc.HtlcIndex)
+		require.Equal(t, expected[i].expiryHeight, htlc.ExpiryHeight)
+		require.Equal(t, lnrpc.InvoiceHTLCState_ACCEPTED, htlc.State)
+		require.EqualValues(t, 5000, htlc.MppTotalAmtMsat)
+		require.Zero(t, htlc.ResolveTime)
+	}
+
+	require.Equal(
+		t, map[uint64][]byte{65536: {1, 2, 3}},
+		rpcInvoice.Htlcs[1].CustomRecords,
+	)
+}
+
+// TestCreateRPCInvoiceHistory tests that the state transitions of an invoice
+// are converted into their RPC counterparts.
+func TestCreateRPCInvoiceHistory(t *testing.T) {
+	t.Parallel()
+
+	chanID := lnwire.NewShortChanIDFromInt(3)
+	key := invoices.CircuitKey{ChanID: chanID, HtlcID: 9}
+	history := []invoices.InvoiceEvent{
+		{
+			Type:      invoices.InvoiceEventCreated,
+			Timestamp: time.Unix(100, 0),
+		},
+		{
+			Type:       invoices.InvoiceEventHtlcAccepted,
+			Timestamp:  time.Unix(200, 0),
+			CircuitKey: &key,
+			Amt:        1000,
+		},
+		{
+			Type:       invoices.InvoiceEventSetComplete,
+			Timestamp:  time.Unix(200, 0),
+			CircuitKey: &key,
+			Amt:        1000,
+		},
+		{
+			Type: invoices.InvoiceEventCanceled,
+		},
+	}
+
+	require.Equal(t, []*lnrpc.InvoiceEvent{
+		{
+			Type:      lnrpc.InvoiceEventType_INVOICE_CREATED,
+			Timestamp: 100,
+		},
+		{
+			Type:      lnrpc.InvoiceEventType_INVOICE_HTLC_ACCEPTED,
+			Timestamp: 200,
+			ChanId:    chanID.ToUint64(),
+			HtlcIndex: 9,
+			AmtMsat:   1000,
+		},
+		{
+			Type:      lnrpc.InvoiceEventType_INVOICE_SET_COMPLETE,
+			Timestamp: 200,
+			ChanId:    chanID.ToUint64(),
+			HtlcIndex: 9,
+			AmtMsat:   1000,
+		},
+		{
+			Type: lnrpc.InvoiceEventType_INVOICE_CANCELED,
+		},
+	}, CreateRPCInvoiceHistory(history))
+
+	// The event types must map onto the RPC event types one by one.
+	for eventType := invoices.InvoiceEventCreated; eventType <=
+		invoices.InvoiceEventCanceled; eventType++ {
+
+		rpcType := lnrpc.InvoiceEventType(eventType).String()
+		require.Equal(
+			t, strings.ToUpper("Invoice"+eventType.String()),
+			strings.ReplaceAll(rpcType, "_", ""),
+		)
+	}
+}
+
+// TestCreateRPCInvoiceOverpayment tests that the overpaid amount and the
+// overpayment policy of an invoice are returned over RPC.
+func TestCreateRPCInvoiceOverpayment(t *testing.T) {
+	t.Parallel()
+
+	invoice := &invoices.Invoice{
+		Terms: invoices.ContractTerm{
+			Value: 10_000,
+		},
+		AmtPaid: 10_500,
+		State:   invoices.ContractSettled,
+		OverpaymentPolicy: invoices.OverpaymentPolicy{
+			Mode:       invoices.OverpaymentModeLimit,
+			MaxPercent: 10,
+		},
+	}
+
+	rpcInvoice, err := CreateRPCInvoice(
+		invoice, &chaincfg.RegressionNetParams,
+	)
+	require.NoError(t, err)
+	require.EqualValues(t, 500, rpcInvoice.AmtOverpaidMsat)
+	require.Equal(t, &lnrpc.OverpaymentPolicy{
+		Mode:       lnrpc.OverpaymentMode_OVERPAYMENT_LIMIT,
+		MaxPercent: 10,
+	}, rpcInvoice.OverpaymentPolicy)
+
+	// The policy must survive a round trip, so it can be passed back to
+	// AddInvoice.
+	policy, err := UnmarshalOverpaymentPolicy(rpcInvoice.OverpaymentPolicy)
+	require.NoError(t, err)
+	require.Equal(t, invoice.OverpaymentPolicy, policy)
+
+	// Invoices that follow the node wide policy don't report one.
+	invoice.OverpaymentPolicy = invoices.OverpaymentPolicy{}
+	rpcInvoice, err = CreateRPCInvoice(
+		invoice, &chaincfg.RegressionNetParams,
+	)
+	require.NoError(t, err)
+	require.Nil(t, rpcInvoice.OverpaymentPolicy)
+
+	policy, err = UnmarshalOverpaymentPolicy(nil)
+	require.NoError(t, err)
+	require.True(t, policy.IsDefault())
+
+	// A max percent is only allowed with the limit mode.
+	_, err = UnmarshalOverpaymentPolicy(&lnrpc.OverpaymentPolicy{
+		Mode:       lnrpc.OverpaymentMode_OVERPAYMENT_REJECT,
+		MaxPercent: 10,
+	})
+	require.Error(t, err)
+
+	_, err = UnmarshalOverpaymentPolicy(&lnrpc.OverpaymentPolicy{
+		Mode: 99,
+	})
+	require.Error(t, err)
+}
+
+// TestCreateRPCOffer tests that BOLT 12 offers and their state are converted
+// into their RPC counterparts.
+func TestCreateRPCOffer(t *testing.T) {
+	t.Parallel()
+
+	issuerKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	blindingKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	offer := &invoices.Offer{
+		Metadata:       []byte{1, 2},
+		Amount:         5000,
+		Description:    "coffee",
+		AbsoluteExpiry: time.Unix(2000, 0),
+		Paths: []*lnwire.BlindedPath{{
+			IntroductionNode: issuerKey.PubKey(),
+			BlindingPoint:    blindingKey.PubKey(),
+			Hops: []lnwire.BlindedHop{{
+				BlindedNodeID: blindingKey.PubKey(),
+				EncryptedData: []byte{3},
+			}},
+		}},
+		QuantityMax: fn.Some[uint64](0),
+		IssuerID:    issuerKey.PubKey(),
+	}
+	id, err := offer.ID()
+	require.NoError(t, err)
+
+	state, err := CreateRPCOfferState(&invoices.OfferState{
+		ID:           id,
+		Offer:        offer,
+		CreationDate: time.Unix(1000, 0),
+		NumPayments:  2,
+		AmtPaid:      10000,
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, 1000, state.CreationDate)
+	require.EqualValues(t, 2, state.NumPayments)
+	require.EqualValues(t, 10000, state.AmtPaidMsat)
+
+	// The encoded offer must decode to the same offer.
+	decoded, err := invoices.DecodeOffer(state.EncodedOffer)
+	require.NoError(t, err)
+	decodedID, err := decoded.ID()
+	require.NoError(t, err)
+	require.Equal(t, id, decodedID)
+
+	rpcOffer := state.Offer
+	require.Equal(t, id[:], rpcOffer.OfferId)
+	require.Equal(t, []byte{1, 2}, rpcOffer.Metadata)
+	require.EqualValues(t, 5000, rpcOffer.AmountMsat)
+	require.Equal(t, "coffee", rpcOffer.Description)
+	require.EqualValues(t, 2000, rpcOffer.AbsoluteExpiry)
+	require.True(t, rpcOffer.AllowQuantity)
+	require.Zero(t, rpcOffer.QuantityMax)
+	require.Equal(
+		t, issuerKey.PubKey().SerializeCompressed(), rpcOffer.IssuerId,
+	)
+	require.Len(t, rpcOffer.Paths, 1)
+	require.Len(t, rpcOffer.Paths[0].BlindedHops, 1)
+	require.Equal(
+		t, []byte{3}, rpcOffer.Paths[0].BlindedHops[0].EncryptedData,
+	)
+}
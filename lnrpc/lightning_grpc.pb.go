@@ -97,6 +97,31 @@ type LightningClient interface {
 	// the client in which any events relevant to the state of peers are sent
 	// over. Events include peers going online and offline.
 	SubscribePeerEvents(ctx context.Context, in *PeerEventSubscription, opts ...grpc.CallOption) (Lightning_SubscribePeerEventsClient, error)
+	// lncli: `allowpeer`
+	// AllowPeer adds a peer to the allow list. This call fails if the allow list
+	// isn't enabled with allowlist.enable. Additions are persisted.
+	AllowPeer(ctx context.Context, in *AllowPeerRequest, opts ...grpc.CallOption) (*AllowPeerResponse, error)
+	// lncli: `disallowpeer`
+	// DisallowPeer removes a peer from the allow list. If we're connected to the
+	// peer, the connection is closed and we stop reconnecting to it. This call
+	// fails if the allow list isn't enabled with allowlist.enable.
+	DisallowPeer(ctx context.Context, in *DisallowPeerRequest, opts ...grpc.CallOption) (*DisallowPeerResponse, error)
+	// lncli: `listallowedpeers`
+	// ListAllowedPeers returns all peers on the allow list. This call fails if
+	// the allow list isn't enabled with allowlist.enable.
+	ListAllowedPeers(ctx context.Context, in *ListAllowedPeersRequest, opts ...grpc.CallOption) (*ListAllowedPeersResponse, error)
+	// lncli: `getgossipegress`
+	// GetGossipEgressLimits returns the limits currently enforced on the gossip
+	// we proactively send to our peers, i.e. the announcements we flood to them
+	// and the backlog we send once they set their gossip_timestamp_range.
+	GetGossipEgressLimits(ctx context.Context, in *GetGossipEgressLimitsRequest, opts ...grpc.CallOption) (*GossipEgressLimits, error)
+	// lncli: `setgossipegress`
+	// UpdateGossipEgressLimits replaces the limits enforced on the gossip we
+	// proactively send to our peers. The new limits apply to the gossip that is
+	// already queued for our peers as well. They aren't persisted, so the
+	// configured gossip.*egress* and gossip.passive-peers options apply again
+	// after a restart.
+	UpdateGossipEgressLimits(ctx context.Context, in *GossipEgressLimits, opts ...grpc.CallOption) (*UpdateGossipEgressLimitsResponse, error)
 	// lncli: `getinfo`
 	// GetInfo returns general information concerning the lightning node including
 	// it's identity pubkey, alias, the chains it is connected to, and information
@@ -112,6 +137,23 @@ type LightningClient interface {
 	// whether it's in a recovery mode, whether the recovery is finished, and the
 	// progress made so far.
 	GetRecoveryInfo(ctx context.Context, in *GetRecoveryInfoRequest, opts ...grpc.CallOption) (*GetRecoveryInfoResponse, error)
+	// lncli: `checkdbintegrity`
+	// CheckDBIntegrity validates the references between the channel graph and
+	// the channel state database. Edges of our own node and forwarding packages
+	// that refer to channels that don't exist anymore are reported as orphans.
+	CheckDBIntegrity(ctx context.Context, in *CheckDBIntegrityRequest, opts ...grpc.CallOption) (*CheckDBIntegrityResponse, error)
+	// lncli: `prunedborphans`
+	// PruneDBOrphans runs the same check as CheckDBIntegrity and removes all
+	// orphaned references it finds. Orphaned edges are removed without being
+	// marked as zombies, so they can be re-added if they're announced again.
+	PruneDBOrphans(ctx context.Context, in *PruneDBOrphansRequest, opts ...grpc.CallOption) (*PruneDBOrphansResponse, error)
+	// lncli: `createdbsnapshot`
+	// CreateDBSnapshot takes a consistent snapshot of the local bolt databases
+	// while lnd keeps running. The snapshot is written to its own directory
+	// within the configured snapshot directory, old snapshots exceeding the
+	// configured number of snapshots to keep are removed. Only supported with
+	// the bolt database backend.
+	CreateDBSnapshot(ctx context.Context, in *CreateDBSnapshotRequest, opts ...grpc.CallOption) (*CreateDBSnapshotResponse, error)
 	// lncli: `pendingchannels`
 	// PendingChannels returns a list of all the channels that are currently
 	// considered "pending". A channel is pending if it has finished the funding
@@ -124,8 +166,9 @@ type LightningClient interface {
 	ListChannels(ctx context.Context, in *ListChannelsRequest, opts ...grpc.CallOption) (*ListChannelsResponse, error)
 	// SubscribeChannelEvents creates a uni-directional stream from the server to
 	// the client in which any updates relevant to the state of the channels are
-	// sent over. Events include new active channels, inactive channels, and closed
-	// channels.
+	// sent over. Events include new active channels, inactive channels, closed
+	// channels and channels that were force closed by the remote with a stale
+	// state after it lost data.
 	SubscribeChannelEvents(ctx context.Context, in *ChannelEventSubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelEventsClient, error)
 	// lncli: `closedchannels`
 	// ClosedChannels returns a description of all the closed channels that
@@ -303,12 +346,36 @@ type LightningClient interface {
 	// channels being advertised, updates in the routing policy for a directional
 	// channel edge, and when channels are closed on-chain.
 	SubscribeChannelGraph(ctx context.Context, in *GraphTopologySubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelGraphClient, error)
+	// lncli: `subscribegossip`
+	// SubscribeGossipBridge exports every channel announcement, channel update
+	// and node announcement accepted by the gossiper as a stream of raw wire
+	// messages. It is only available if the gossip bridge is enabled with
+	// gossip.bridge, which is meant for private test networks.
+	SubscribeGossipBridge(ctx context.Context, in *GossipBridgeSubscription, opts ...grpc.CallOption) (Lightning_SubscribeGossipBridgeClient, error)
+	// lncli: `injectgossip`
+	// InjectGossip hands a raw channel announcement, channel update or node
+	// announcement obtained outside of the peer-to-peer network to the gossiper.
+	// The announcement is fully validated before it is added to the graph and
+	// broadcast to our peers. It is only available if the gossip bridge is
+	// enabled with gossip.bridge.
+	InjectGossip(ctx context.Context, in *GossipMessage, opts ...grpc.CallOption) (*InjectGossipResponse, error)
 	// lncli: `debuglevel`
 	// DebugLevel allows a caller to programmatically set the logging verbosity of
 	// lnd. The logging can be targeted according to a coarse daemon-wide logging
 	// level, or in a granular fashion to specify the logging for a target
 	// sub-system.
 	DebugLevel(ctx context.Context, in *DebugLevelRequest, opts ...grpc.CallOption) (*DebugLevelResponse, error)
+	// lncli: `workerpools`
+	// GetWorkerPoolStats returns the utilization of the worker pools that
+	// serialize and deserialize peer messages.
+	GetWorkerPoolStats(ctx context.Context, in *GetWorkerPoolStatsRequest, opts ...grpc.CallOption) (*GetWorkerPoolStatsResponse, error)
+	// lncli: `resizeworkerpool`
+	// ResizeWorkerPool sets the maximum number of workers of one of the worker
+	// pools that serialize and deserialize peer messages. Excess workers retire
+	// once they finish their current task. If auto tuning is enabled, the pool
+	// keeps adjusting its size within the configured bounds starting from the
+	// new size. The size is not persisted across restarts.
+	ResizeWorkerPool(ctx context.Context, in *ResizeWorkerPoolRequest, opts ...grpc.CallOption) (*ResizeWorkerPoolResponse, error)
 	// lncli: `feereport`
 	// FeeReport allows the caller to obtain a report detailing the current fee
 	// schedule enforced by the node globally for each channel.
@@ -317,6 +384,17 @@ type LightningClient interface {
 	// UpdateChannelPolicy allows the caller to update the fee schedule and
 	// channel policies for all channels globally, or a particular channel.
 	UpdateChannelPolicy(ctx context.Context, in *PolicyUpdateRequest, opts ...grpc.CallOption) (*PolicyUpdateResponse, error)
+	// lncli: `suggestfees`
+	// SuggestFees returns fee rate suggestions for our channels based on their
+	// forwarding history, their local balance and the fee rates other nodes
+	// charge for forwarding to the same peer. This is a dry run, no channel
+	// policies are changed.
+	SuggestFees(ctx context.Context, in *SuggestFeesRequest, opts ...grpc.CallOption) (*SuggestFeesResponse, error)
+	// lncli: `suggestfees --apply`
+	// ApplyFeeSuggestions computes the same fee rate suggestions as SuggestFees
+	// and updates the fee rate of every channel whose suggested fee rate differs
+	// from its current one. All other policy parameters are left unchanged.
+	ApplyFeeSuggestions(ctx context.Context, in *ApplyFeeSuggestionsRequest, opts ...grpc.CallOption) (*ApplyFeeSuggestionsResponse, error)
 	// lncli: `fwdinghistory`
 	// ForwardingHistory allows the caller to query the htlcswitch for a record of
 	// all HTLCs forwarded within the target time range, and integer offset
@@ -363,6 +441,11 @@ type LightningClient interface {
 	// ups, but the updated set of encrypted multi-chan backups with the closed
 	// channel(s) removed.
 	SubscribeChannelBackups(ctx context.Context, in *ChannelBackupSubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelBackupsClient, error)
+	// lncli: `backupreplicationstatus`
+	// BackupReplicationStatus returns the state of the replication of the
+	// channel backup file to each configured remote target, including the time
+	// of the last successful replication.
+	BackupReplicationStatus(ctx context.Context, in *BackupReplicationStatusRequest, opts ...grpc.CallOption) (*BackupReplicationStatusResponse, error)
 	// lncli: `bakemacaroon`
 	// BakeMacaroon allows the creation of a new macaroon with custom read and
 	// write permissions. No first-party caveats are added since this can be done
@@ -416,6 +499,47 @@ type LightningClient interface {
 	// If the htlc has no final resolution yet, a NotFound grpc status code is
 	// returned.
 	LookupHtlcResolution(ctx context.Context, in *LookupHtlcResolutionRequest, opts ...grpc.CallOption) (*LookupHtlcResolutionResponse, error)
+	// lncli: `commitmentfeeinfo`
+	// CommitmentFeeInfo returns the weight, fee and set of dust HTLCs of the
+	// current local or remote commitment transaction of a channel, along with the
+	// same values at each of the requested alternative fee rates. This can be
+	// used to reason about the impact of an update_fee, or the cost of a force
+	// close.
+	CommitmentFeeInfo(ctx context.Context, in *CommitmentFeeInfoRequest, opts ...grpc.CallOption) (*CommitmentFeeInfoResponse, error)
+	// lncli: `exportresolutions`
+	// ExportResolutions returns a snapshot of all contracts that are currently
+	// being resolved on-chain, including their type, amounts, maturity height and
+	// the witness required to sweep them. The snapshot is a versioned JSON
+	// document signed by the node's identity key, so it can be handed to an
+	// auditor as proof of the funds that are in flight.
+	//
+	// NOTE: Outputs that were handed off to the utxo nursery aren't tracked by a
+	// resolver and thus aren't part of the snapshot.
+	ExportResolutions(ctx context.Context, in *ExportResolutionsRequest, opts ...grpc.CallOption) (*ExportResolutionsResponse, error)
+	// lncli: `timelockedfunds`
+	// TimeLockedFunds lists the outputs of force closed channels that are still
+	// encumbered by a CSV or CLTV lock, together with the heights and projected
+	// times at which they can be swept back to the wallet, and the total amount
+	// that is locked.
+	//
+	// NOTE: Outputs that were handed off to the utxo nursery aren't tracked by a
+	// resolver and thus aren't part of the summary.
+	TimeLockedFunds(ctx context.Context, in *TimeLockedFundsRequest, opts ...grpc.CallOption) (*TimeLockedFundsResponse, error)
+	// lncli: `pendingresolutions`
+	// ListPendingResolutions lists all contracts that are currently being
+	// resolved on-chain, together with the state of their resolver, the height
+	// by which they must be swept and the estimated fee to do so. The most urgent
+	// contracts are listed first.
+	//
+	// NOTE: Outputs that were handed off to the utxo nursery aren't tracked by a
+	// resolver and thus aren't listed.
+	ListPendingResolutions(ctx context.Context, in *ListPendingResolutionsRequest, opts ...grpc.CallOption) (*ListPendingResolutionsResponse, error)
+	// lncli: `auditscidleaks`
+	// AuditScidLeaks scans the payment requests of our invoices for route hints
+	// that reveal the confirmed SCID of an unannounced channel which negotiated
+	// the option-scid-alias feature, and should therefore only have been referred
+	// to by its alias.
+	AuditScidLeaks(ctx context.Context, in *AuditScidLeaksRequest, opts ...grpc.CallOption) (*AuditScidLeaksResponse, error)
 }
 
 type lightningClient struct {
@@ -607,6 +731,51 @@ func (x *lightningSubscribePeerEventsClient) Recv() (*PeerEvent, error) {
 	return m, nil
 }
 
+func (c *lightningClient) AllowPeer(ctx context.Context, in *AllowPeerRequest, opts ...grpc.CallOption) (*AllowPeerResponse, error) {
+	out := new(AllowPeerResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/AllowPeer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) DisallowPeer(ctx context.Context, in *DisallowPeerRequest, opts ...grpc.CallOption) (*DisallowPeerResponse, error) {
+	out := new(DisallowPeerResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/DisallowPeer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListAllowedPeers(ctx context.Context, in *ListAllowedPeersRequest, opts ...grpc.CallOption) (*ListAllowedPeersResponse, error) {
+	out := new(ListAllowedPeersResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ListAllowedPeers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) GetGossipEgressLimits(ctx context.Context, in *GetGossipEgressLimitsRequest, opts ...grpc.CallOption) (*GossipEgressLimits, error) {
+	out := new(GossipEgressLimits)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/GetGossipEgressLimits", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) UpdateGossipEgressLimits(ctx context.Context, in *GossipEgressLimits, opts ...grpc.CallOption) (*UpdateGossipEgressLimitsResponse, error) {
+	out := new(UpdateGossipEgressLimitsResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/UpdateGossipEgressLimits", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) GetInfo(ctx context.Context, in *GetInfoRequest, opts ...grpc.CallOption) (*GetInfoResponse, error) {
 	out := new(GetInfoResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/GetInfo", in, out, opts...)
@@ -634,6 +803,33 @@ func (c *lightningClient) GetRecoveryInfo(ctx context.Context, in *GetRecoveryIn
 	return out, nil
 }
 
+func (c *lightningClient) CheckDBIntegrity(ctx context.Context, in *CheckDBIntegrityRequest, opts ...grpc.CallOption) (*CheckDBIntegrityResponse, error) {
+	out := new(CheckDBIntegrityResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/CheckDBIntegrity", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) PruneDBOrphans(ctx context.Context, in *PruneDBOrphansRequest, opts ...grpc.CallOption) (*PruneDBOrphansResponse, error) {
+	out := new(PruneDBOrphansResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/PruneDBOrphans", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) CreateDBSnapshot(ctx context.Context, in *CreateDBSnapshotRequest, opts ...grpc.CallOption) (*CreateDBSnapshotResponse, error) {
+	out := new(CreateDBSnapshotResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/CreateDBSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) PendingChannels(ctx context.Context, in *PendingChannelsRequest, opts ...grpc.CallOption) (*PendingChannelsResponse, error) {
 	out := new(PendingChannelsResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/PendingChannels", in, out, opts...)
@@ -1096,6 +1292,47 @@ func (x *lightningSubscribeChannelGraphClient) Recv() (*GraphTopologyUpdate, err
 	return m, nil
 }
 
+func (c *lightningClient) SubscribeGossipBridge(ctx context.Context, in *GossipBridgeSubscription, opts ...grpc.CallOption) (Lightning_SubscribeGossipBridgeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[10], "/lnrpc.Lightning/SubscribeGossipBridge", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSubscribeGossipBridgeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_SubscribeGossipBridgeClient interface {
+	Recv() (*GossipMessage, error)
+	grpc.ClientStream
+}
+
+type lightningSubscribeGossipBridgeClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSubscribeGossipBridgeClient) Recv() (*GossipMessage, error) {
+	m := new(GossipMessage)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) InjectGossip(ctx context.Context, in *GossipMessage, opts ...grpc.CallOption) (*InjectGossipResponse, error) {
+	out := new(InjectGossipResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/InjectGossip", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) DebugLevel(ctx context.Context, in *DebugLevelRequest, opts ...grpc.CallOption) (*DebugLevelResponse, error) {
 	out := new(DebugLevelResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/DebugLevel", in, out, opts...)
@@ -1105,6 +1342,24 @@ func (c *lightningClient) DebugLevel(ctx context.Context, in *DebugLevelRequest,
 	return out, nil
 }
 
+func (c *lightningClient) GetWorkerPoolStats(ctx context.Context, in *GetWorkerPoolStatsRequest, opts ...grpc.CallOption) (*GetWorkerPoolStatsResponse, error) {
+	out := new(GetWorkerPoolStatsResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/GetWorkerPoolStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ResizeWorkerPool(ctx context.Context, in *ResizeWorkerPoolRequest, opts ...grpc.CallOption) (*ResizeWorkerPoolResponse, error) {
+	out := new(ResizeWorkerPoolResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ResizeWorkerPool", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) FeeReport(ctx context.Context, in *FeeReportRequest, opts ...grpc.CallOption) (*FeeReportResponse, error) {
 	out := new(FeeReportResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/FeeReport", in, out, opts...)
@@ -1123,6 +1378,24 @@ func (c *lightningClient) UpdateChannelPolicy(ctx context.Context, in *PolicyUpd
 	return out, nil
 }
 
+func (c *lightningClient) SuggestFees(ctx context.Context, in *SuggestFeesRequest, opts ...grpc.CallOption) (*SuggestFeesResponse, error) {
+	out := new(SuggestFeesResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/SuggestFees", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ApplyFeeSuggestions(ctx context.Context, in *ApplyFeeSuggestionsRequest, opts ...grpc.CallOption) (*ApplyFeeSuggestionsResponse, error) {
+	out := new(ApplyFeeSuggestionsResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ApplyFeeSuggestions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) ForwardingHistory(ctx context.Context, in *ForwardingHistoryRequest, opts ...grpc.CallOption) (*ForwardingHistoryResponse, error) {
 	out := new(ForwardingHistoryResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ForwardingHistory", in, out, opts...)
@@ -1169,7 +1442,7 @@ func (c *lightningClient) RestoreChannelBackups(ctx context.Context, in *Restore
 }
 
 func (c *lightningClient) SubscribeChannelBackups(ctx context.Context, in *ChannelBackupSubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelBackupsClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[10], "/lnrpc.Lightning/SubscribeChannelBackups", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[11], "/lnrpc.Lightning/SubscribeChannelBackups", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1200,6 +1473,15 @@ func (x *lightningSubscribeChannelBackupsClient) Recv() (*ChanBackupSnapshot, er
 	return m, nil
 }
 
+func (c *lightningClient) BackupReplicationStatus(ctx context.Context, in *BackupReplicationStatusRequest, opts ...grpc.CallOption) (*BackupReplicationStatusResponse, error) {
+	out := new(BackupReplicationStatusResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/BackupReplicationStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) BakeMacaroon(ctx context.Context, in *BakeMacaroonRequest, opts ...grpc.CallOption) (*BakeMacaroonResponse, error) {
 	out := new(BakeMacaroonResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/BakeMacaroon", in, out, opts...)
@@ -1246,7 +1528,7 @@ func (c *lightningClient) CheckMacaroonPermissions(ctx context.Context, in *Chec
 }
 
 func (c *lightningClient) RegisterRPCMiddleware(ctx context.Context, opts ...grpc.CallOption) (Lightning_RegisterRPCMiddlewareClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[11], "/lnrpc.Lightning/RegisterRPCMiddleware", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[12], "/lnrpc.Lightning/RegisterRPCMiddleware", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1286,7 +1568,7 @@ func (c *lightningClient) SendCustomMessage(ctx context.Context, in *SendCustomM
 }
 
 func (c *lightningClient) SubscribeCustomMessages(ctx context.Context, in *SubscribeCustomMessagesRequest, opts ...grpc.CallOption) (Lightning_SubscribeCustomMessagesClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[12], "/lnrpc.Lightning/SubscribeCustomMessages", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[13], "/lnrpc.Lightning/SubscribeCustomMessages", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1335,6 +1617,51 @@ func (c *lightningClient) LookupHtlcResolution(ctx context.Context, in *LookupHt
 	return out, nil
 }
 
+func (c *lightningClient) CommitmentFeeInfo(ctx context.Context, in *CommitmentFeeInfoRequest, opts ...grpc.CallOption) (*CommitmentFeeInfoResponse, error) {
+	out := new(CommitmentFeeInfoResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/CommitmentFeeInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ExportResolutions(ctx context.Context, in *ExportResolutionsRequest, opts ...grpc.CallOption) (*ExportResolutionsResponse, error) {
+	out := new(ExportResolutionsResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ExportResolutions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) TimeLockedFunds(ctx context.Context, in *TimeLockedFundsRequest, opts ...grpc.CallOption) (*TimeLockedFundsResponse, error) {
+	out := new(TimeLockedFundsResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/TimeLockedFunds", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListPendingResolutions(ctx context.Context, in *ListPendingResolutionsRequest, opts ...grpc.CallOption) (*ListPendingResolutionsResponse, error) {
+	out := new(ListPendingResolutionsResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ListPendingResolutions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) AuditScidLeaks(ctx context.Context, in *AuditScidLeaksRequest, opts ...grpc.CallOption) (*AuditScidLeaksResponse, error) {
+	out := new(AuditScidLeaksResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/AuditScidLeaks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // LightningServer is the server API for Lightning service.
 // All implementations must embed UnimplementedLightningServer
 // for forward compatibility
@@ -1418,6 +1745,31 @@ type LightningServer interface {
 	// the client in which any events relevant to the state of peers are sent
 	// over. Events include peers going online and offline.
 	SubscribePeerEvents(*PeerEventSubscription, Lightning_SubscribePeerEventsServer) error
+	// lncli: `allowpeer`
+	// AllowPeer adds a peer to the allow list. This call fails if the allow list
+	// isn't enabled with allowlist.enable. Additions are persisted.
+	AllowPeer(context.Context, *AllowPeerRequest) (*AllowPeerResponse, error)
+	// lncli: `disallowpeer`
+	// DisallowPeer removes a peer from the allow list. If we're connected to the
+	// peer, the connection is closed and we stop reconnecting to it. This call
+	// fails if the allow list isn't enabled with allowlist.enable.
+	DisallowPeer(context.Context, *DisallowPeerRequest) (*DisallowPeerResponse, error)
+	// lncli: `listallowedpeers`
+	// ListAllowedPeers returns all peers on the allow list. This call fails if
+	// the allow list isn't enabled with allowlist.enable.
+	ListAllowedPeers(context.Context, *ListAllowedPeersRequest) (*ListAllowedPeersResponse, error)
+	// lncli: `getgossipegress`
+	// GetGossipEgressLimits returns the limits currently enforced on the gossip
+	// we proactively send to our peers, i.e. the announcements we flood to them
+	// and the backlog we send once they set their gossip_timestamp_range.
+	GetGossipEgressLimits(context.Context, *GetGossipEgressLimitsRequest) (*GossipEgressLimits, error)
+	// lncli: `setgossipegress`
+	// UpdateGossipEgressLimits replaces the limits enforced on the gossip we
+	// proactively send to our peers. The new limits apply to the gossip that is
+	// already queued for our peers as well. They aren't persisted, so the
+	// configured gossip.*egress* and gossip.passive-peers options apply again
+	// after a restart.
+	UpdateGossipEgressLimits(context.Context, *GossipEgressLimits) (*UpdateGossipEgressLimitsResponse, error)
 	// lncli: `getinfo`
 	// GetInfo returns general information concerning the lightning node including
 	// it's identity pubkey, alias, the chains it is connected to, and information
@@ -1433,6 +1785,23 @@ type LightningServer interface {
 	// whether it's in a recovery mode, whether the recovery is finished, and the
 	// progress made so far.
 	GetRecoveryInfo(context.Context, *GetRecoveryInfoRequest) (*GetRecoveryInfoResponse, error)
+	// lncli: `checkdbintegrity`
+	// CheckDBIntegrity validates the references between the channel graph and
+	// the channel state database. Edges of our own node and forwarding packages
+	// that refer to channels that don't exist anymore are reported as orphans.
+	CheckDBIntegrity(context.Context, *CheckDBIntegrityRequest) (*CheckDBIntegrityResponse, error)
+	// lncli: `prunedborphans`
+	// PruneDBOrphans runs the same check as CheckDBIntegrity and removes all
+	// orphaned references it finds. Orphaned edges are removed without being
+	// marked as zombies, so they can be re-added if they're announced again.
+	PruneDBOrphans(context.Context, *PruneDBOrphansRequest) (*PruneDBOrphansResponse, error)
+	// lncli: `createdbsnapshot`
+	// CreateDBSnapshot takes a consistent snapshot of the local bolt databases
+	// while lnd keeps running. The snapshot is written to its own directory
+	// within the configured snapshot directory, old snapshots exceeding the
+	// configured number of snapshots to keep are removed. Only supported with
+	// the bolt database backend.
+	CreateDBSnapshot(context.Context, *CreateDBSnapshotRequest) (*CreateDBSnapshotResponse, error)
 	// lncli: `pendingchannels`
 	// PendingChannels returns a list of all the channels that are currently
 	// considered "pending". A channel is pending if it has finished the funding
@@ -1445,8 +1814,9 @@ type LightningServer interface {
 	ListChannels(context.Context, *ListChannelsRequest) (*ListChannelsResponse, error)
 	// SubscribeChannelEvents creates a uni-directional stream from the server to
 	// the client in which any updates relevant to the state of the channels are
-	// sent over. Events include new active channels, inactive channels, and closed
-	// channels.
+	// sent over. Events include new active channels, inactive channels, closed
+	// channels and channels that were force closed by the remote with a stale
+	// state after it lost data.
 	SubscribeChannelEvents(*ChannelEventSubscription, Lightning_SubscribeChannelEventsServer) error
 	// lncli: `closedchannels`
 	// ClosedChannels returns a description of all the closed channels that
@@ -1624,12 +1994,36 @@ type LightningServer interface {
 	// channels being advertised, updates in the routing policy for a directional
 	// channel edge, and when channels are closed on-chain.
 	SubscribeChannelGraph(*GraphTopologySubscription, Lightning_SubscribeChannelGraphServer) error
+	// lncli: `subscribegossip`
+	// SubscribeGossipBridge exports every channel announcement, channel update
+	// and node announcement accepted by the gossiper as a stream of raw wire
+	// messages. It is only available if the gossip bridge is enabled with
+	// gossip.bridge, which is meant for private test networks.
+	SubscribeGossipBridge(*GossipBridgeSubscription, Lightning_SubscribeGossipBridgeServer) error
+	// lncli: `injectgossip`
+	// InjectGossip hands a raw channel announcement, channel update or node
+	// announcement obtained outside of the peer-to-peer network to the gossiper.
+	// The announcement is fully validated before it is added to the graph and
+	// broadcast to our peers. It is only available if the gossip bridge is
+	// enabled with gossip.bridge.
+	InjectGossip(context.Context, *GossipMessage) (*InjectGossipResponse, error)
 	// lncli: `debuglevel`
 	// DebugLevel allows a caller to programmatically set the logging verbosity of
 	// lnd. The logging can be targeted according to a coarse daemon-wide logging
 	// level, or in a granular fashion to specify the logging for a target
 	// sub-system.
 	DebugLevel(context.Context, *DebugLevelRequest) (*DebugLevelResponse, error)
+	// lncli: `workerpools`
+	// GetWorkerPoolStats returns the utilization of the worker pools that
+	// serialize and deserialize peer messages.
+	GetWorkerPoolStats(context.Context, *GetWorkerPoolStatsRequest) (*GetWorkerPoolStatsResponse, error)
+	// lncli: `resizeworkerpool`
+	// ResizeWorkerPool sets the maximum number of workers of one of the worker
+	// pools that serialize and deserialize peer messages. Excess workers retire
+	// once they finish their current task. If auto tuning is enabled, the pool
+	// keeps adjusting its size within the configured bounds starting from the
+	// new size. The size is not persisted across restarts.
+	ResizeWorkerPool(context.Context, *ResizeWorkerPoolRequest) (*ResizeWorkerPoolResponse, error)
 	// lncli: `feereport`
 	// FeeReport allows the caller to obtain a report detailing the current fee
 	// schedule enforced by the node globally for each channel.
@@ -1638,6 +2032,17 @@ type LightningServer interface {
 	// UpdateChannelPolicy allows the caller to update the fee schedule and
 	// channel policies for all channels globally, or a particular channel.
 	UpdateChannelPolicy(context.Context, *PolicyUpdateRequest) (*PolicyUpdateResponse, error)
+	// lncli: `suggestfees`
+	// SuggestFees returns fee rate suggestions for our channels based on their
+	// forwarding history, their local balance and the fee rates other nodes
+	// charge for forwarding to the same peer. This is a dry run, no channel
+	// policies are changed.
+	SuggestFees(context.Context, *SuggestFeesRequest) (*SuggestFeesResponse, error)
+	// lncli: `suggestfees --apply`
+	// ApplyFeeSuggestions computes the same fee rate suggestions as SuggestFees
+	// and updates the fee rate of every channel whose suggested fee rate differs
+	// from its current one. All other policy parameters are left unchanged.
+	ApplyFeeSuggestions(context.Context, *ApplyFeeSuggestionsRequest) (*ApplyFeeSuggestionsResponse, error)
 	// lncli: `fwdinghistory`
 	// ForwardingHistory allows the caller to query the htlcswitch for a record of
 	// all HTLCs forwarded within the target time range, and integer offset
@@ -1684,6 +2089,11 @@ type LightningServer interface {
 	// ups, but the updated set of encrypted multi-chan backups with the closed
 	// channel(s) removed.
 	SubscribeChannelBackups(*ChannelBackupSubscription, Lightning_SubscribeChannelBackupsServer) error
+	// lncli: `backupreplicationstatus`
+	// BackupReplicationStatus returns the state of the replication of the
+	// channel backup file to each configured remote target, including the time
+	// of the last successful replication.
+	BackupReplicationStatus(context.Context, *BackupReplicationStatusRequest) (*BackupReplicationStatusResponse, error)
 	// lncli: `bakemacaroon`
 	// BakeMacaroon allows the creation of a new macaroon with custom read and
 	// write permissions. No first-party caveats are added since this can be done
@@ -1737,6 +2147,47 @@ type LightningServer interface {
 	// If the htlc has no final resolution yet, a NotFound grpc status code is
 	// returned.
 	LookupHtlcResolution(context.Context, *LookupHtlcResolutionRequest) (*LookupHtlcResolutionResponse, error)
+	// lncli: `commitmentfeeinfo`
+	// CommitmentFeeInfo returns the weight, fee and set of dust HTLCs of the
+	// current local or remote commitment transaction of a channel, along with the
+	// same values at each of the requested alternative fee rates. This can be
+	// used to reason about the impact of an update_fee, or the cost of a force
+	// close.
+	CommitmentFeeInfo(context.Context, *CommitmentFeeInfoRequest) (*CommitmentFeeInfoResponse, error)
+	// lncli: `exportresolutions`
+	// ExportResolutions returns a snapshot of all contracts that are currently
+	// being resolved on-chain, including their type, amounts, maturity height and
+	// the witness required to sweep them. The snapshot is a versioned JSON
+	// document signed by the node's identity key, so it can be handed to an
+	// auditor as proof of the funds that are in flight.
+	//
+	// NOTE: Outputs that were handed off to the utxo nursery aren't tracked by a
+	// resolver and thus aren't part of the snapshot.
+	ExportResolutions(context.Context, *ExportResolutionsRequest) (*ExportResolutionsResponse, error)
+	// lncli: `timelockedfunds`
+	// TimeLockedFunds lists the outputs of force closed channels that are still
+	// encumbered by a CSV or CLTV lock, together with the heights and projected
+	// times at which they can be swept back to the wallet, and the total amount
+	// that is locked.
+	//
+	// NOTE: Outputs that were handed off to the utxo nursery aren't tracked by a
+	// resolver and thus aren't part of the summary.
+	TimeLockedFunds(context.Context, *TimeLockedFundsRequest) (*TimeLockedFundsResponse, error)
+	// lncli: `pendingresolutions`
+	// ListPendingResolutions lists all contracts that are currently being
+	// resolved on-chain, together with the state of their resolver, the height
+	// by which they must be swept and the estimated fee to do so. The most urgent
+	// contracts are listed first.
+	//
+	// NOTE: Outputs that were handed off to the utxo nursery aren't tracked by a
+	// resolver and thus aren't listed.
+	ListPendingResolutions(context.Context, *ListPendingResolutionsRequest) (*ListPendingResolutionsResponse, error)
+	// lncli: `auditscidleaks`
+	// AuditScidLeaks scans the payment requests of our invoices for route hints
+	// that reveal the confirmed SCID of an unannounced channel which negotiated
+	// the option-scid-alias feature, and should therefore only have been referred
+	// to by its alias.
+	AuditScidLeaks(context.Context, *AuditScidLeaksRequest) (*AuditScidLeaksResponse, error)
 	mustEmbedUnimplementedLightningServer()
 }
 
@@ -1789,6 +2240,21 @@ func (UnimplementedLightningServer) ListPeers(context.Context, *ListPeersRequest
 func (UnimplementedLightningServer) SubscribePeerEvents(*PeerEventSubscription, Lightning_SubscribePeerEventsServer) error {
 	return status.Errorf(codes.Unimplemented, "method SubscribePeerEvents not implemented")
 }
+func (UnimplementedLightningServer) AllowPeer(context.Context, *AllowPeerRequest) (*AllowPeerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AllowPeer not implemented")
+}
+func (UnimplementedLightningServer) DisallowPeer(context.Context, *DisallowPeerRequest) (*DisallowPeerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisallowPeer not implemented")
+}
+func (UnimplementedLightningServer) ListAllowedPeers(context.Context, *ListAllowedPeersRequest) (*ListAllowedPeersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAllowedPeers not implemented")
+}
+func (UnimplementedLightningServer) GetGossipEgressLimits(context.Context, *GetGossipEgressLimitsRequest) (*GossipEgressLimits, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGossipEgressLimits not implemented")
+}
+func (UnimplementedLightningServer) UpdateGossipEgressLimits(context.Context, *GossipEgressLimits) (*UpdateGossipEgressLimitsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateGossipEgressLimits not implemented")
+}
 func (UnimplementedLightningServer) GetInfo(context.Context, *GetInfoRequest) (*GetInfoResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetInfo not implemented")
 }
@@ -1798,6 +2264,15 @@ func (UnimplementedLightningServer) GetDebugInfo(context.Context, *GetDebugInfoR
 func (UnimplementedLightningServer) GetRecoveryInfo(context.Context, *GetRecoveryInfoRequest) (*GetRecoveryInfoResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetRecoveryInfo not implemented")
 }
+func (UnimplementedLightningServer) CheckDBIntegrity(context.Context, *CheckDBIntegrityRequest) (*CheckDBIntegrityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckDBIntegrity not implemented")
+}
+func (UnimplementedLightningServer) PruneDBOrphans(context.Context, *PruneDBOrphansRequest) (*PruneDBOrphansResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PruneDBOrphans not implemented")
+}
+func (UnimplementedLightningServer) CreateDBSnapshot(context.Context, *CreateDBSnapshotRequest) (*CreateDBSnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDBSnapshot not implemented")
+}
 func (UnimplementedLightningServer) PendingChannels(context.Context, *PendingChannelsRequest) (*PendingChannelsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PendingChannels not implemented")
 }
@@ -1891,15 +2366,33 @@ func (UnimplementedLightningServer) StopDaemon(context.Context, *StopRequest) (*
 func (UnimplementedLightningServer) SubscribeChannelGraph(*GraphTopologySubscription, Lightning_SubscribeChannelGraphServer) error {
 	return status.Errorf(codes.Unimplemented, "method SubscribeChannelGraph not implemented")
 }
+func (UnimplementedLightningServer) SubscribeGossipBridge(*GossipBridgeSubscription, Lightning_SubscribeGossipBridgeServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeGossipBridge not implemented")
+}
+func (UnimplementedLightningServer) InjectGossip(context.Context, *GossipMessage) (*InjectGossipResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InjectGossip not implemented")
+}
 func (UnimplementedLightningServer) DebugLevel(context.Context, *DebugLevelRequest) (*DebugLevelResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DebugLevel not implemented")
 }
+func (UnimplementedLightningServer) GetWorkerPoolStats(context.Context, *GetWorkerPoolStatsRequest) (*GetWorkerPoolStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWorkerPoolStats not implemented")
+}
+func (UnimplementedLightningServer) ResizeWorkerPool(context.Context, *ResizeWorkerPoolRequest) (*ResizeWorkerPoolResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResizeWorkerPool not implemented")
+}
 func (UnimplementedLightningServer) FeeReport(context.Context, *FeeReportRequest) (*FeeReportResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FeeReport not implemented")
 }
 func (UnimplementedLightningServer) UpdateChannelPolicy(context.Context, *PolicyUpdateRequest) (*PolicyUpdateResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateChannelPolicy not implemented")
 }
+func (UnimplementedLightningServer) SuggestFees(context.Context, *SuggestFeesRequest) (*SuggestFeesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SuggestFees not implemented")
+}
+func (UnimplementedLightningServer) ApplyFeeSuggestions(context.Context, *ApplyFeeSuggestionsRequest) (*ApplyFeeSuggestionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApplyFeeSuggestions not implemented")
+}
 func (UnimplementedLightningServer) ForwardingHistory(context.Context, *ForwardingHistoryRequest) (*ForwardingHistoryResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ForwardingHistory not implemented")
 }
@@ -1918,6 +2411,9 @@ func (UnimplementedLightningServer) RestoreChannelBackups(context.Context, *Rest
 func (UnimplementedLightningServer) SubscribeChannelBackups(*ChannelBackupSubscription, Lightning_SubscribeChannelBackupsServer) error {
 	return status.Errorf(codes.Unimplemented, "method SubscribeChannelBackups not implemented")
 }
+func (UnimplementedLightningServer) BackupReplicationStatus(context.Context, *BackupReplicationStatusRequest) (*BackupReplicationStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BackupReplicationStatus not implemented")
+}
 func (UnimplementedLightningServer) BakeMacaroon(context.Context, *BakeMacaroonRequest) (*BakeMacaroonResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BakeMacaroon not implemented")
 }
@@ -1948,6 +2444,21 @@ func (UnimplementedLightningServer) ListAliases(context.Context, *ListAliasesReq
 func (UnimplementedLightningServer) LookupHtlcResolution(context.Context, *LookupHtlcResolutionRequest) (*LookupHtlcResolutionResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method LookupHtlcResolution not implemented")
 }
+func (UnimplementedLightningServer) CommitmentFeeInfo(context.Context, *CommitmentFeeInfoRequest) (*CommitmentFeeInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CommitmentFeeInfo not implemented")
+}
+func (UnimplementedLightningServer) ExportResolutions(context.Context, *ExportResolutionsRequest) (*ExportResolutionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportResolutions not implemented")
+}
+func (UnimplementedLightningServer) TimeLockedFunds(context.Context, *TimeLockedFundsRequest) (*TimeLockedFundsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TimeLockedFunds not implemented")
+}
+func (UnimplementedLightningServer) ListPendingResolutions(context.Context, *ListPendingResolutionsRequest) (*ListPendingResolutionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPendingResolutions not implemented")
+}
+func (UnimplementedLightningServer) AuditScidLeaks(context.Context, *AuditScidLeaksRequest) (*AuditScidLeaksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AuditScidLeaks not implemented")
+}
 func (UnimplementedLightningServer) mustEmbedUnimplementedLightningServer() {}
 
 // UnsafeLightningServer may be embedded to opt out of forward compatibility for this service.
@@ -2237,6 +2748,96 @@ func (x *lightningSubscribePeerEventsServer) Send(m *PeerEvent) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Lightning_AllowPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AllowPeerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).AllowPeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/AllowPeer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).AllowPeer(ctx, req.(*AllowPeerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_DisallowPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisallowPeerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).DisallowPeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/DisallowPeer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).DisallowPeer(ctx, req.(*DisallowPeerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ListAllowedPeers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAllowedPeersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ListAllowedPeers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ListAllowedPeers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ListAllowedPeers(ctx, req.(*ListAllowedPeersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_GetGossipEgressLimits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetGossipEgressLimitsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).GetGossipEgressLimits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/GetGossipEgressLimits",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).GetGossipEgressLimits(ctx, req.(*GetGossipEgressLimitsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_UpdateGossipEgressLimits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GossipEgressLimits)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).UpdateGossipEgressLimits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/UpdateGossipEgressLimits",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).UpdateGossipEgressLimits(ctx, req.(*GossipEgressLimits))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_GetInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetInfoRequest)
 	if err := dec(in); err != nil {
@@ -2291,6 +2892,60 @@ func _Lightning_GetRecoveryInfo_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_CheckDBIntegrity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckDBIntegrityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).CheckDBIntegrity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/CheckDBIntegrity",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).CheckDBIntegrity(ctx, req.(*CheckDBIntegrityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_PruneDBOrphans_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PruneDBOrphansRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).PruneDBOrphans(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/PruneDBOrphans",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).PruneDBOrphans(ctx, req.(*PruneDBOrphansRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_CreateDBSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateDBSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).CreateDBSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/CreateDBSnapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).CreateDBSnapshot(ctx, req.(*CreateDBSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_PendingChannels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(PendingChannelsRequest)
 	if err := dec(in); err != nil {
@@ -2888,6 +3543,45 @@ func (x *lightningSubscribeChannelGraphServer) Send(m *GraphTopologyUpdate) erro
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Lightning_SubscribeGossipBridge_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GossipBridgeSubscription)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).SubscribeGossipBridge(m, &lightningSubscribeGossipBridgeServer{stream})
+}
+
+type Lightning_SubscribeGossipBridgeServer interface {
+	Send(*GossipMessage) error
+	grpc.ServerStream
+}
+
+type lightningSubscribeGossipBridgeServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningSubscribeGossipBridgeServer) Send(m *GossipMessage) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Lightning_InjectGossip_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GossipMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).InjectGossip(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/InjectGossip",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).InjectGossip(ctx, req.(*GossipMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_DebugLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DebugLevelRequest)
 	if err := dec(in); err != nil {
@@ -2906,6 +3600,42 @@ func _Lightning_DebugLevel_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_GetWorkerPoolStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWorkerPoolStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).GetWorkerPoolStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/GetWorkerPoolStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).GetWorkerPoolStats(ctx, req.(*GetWorkerPoolStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ResizeWorkerPool_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResizeWorkerPoolRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ResizeWorkerPool(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ResizeWorkerPool",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ResizeWorkerPool(ctx, req.(*ResizeWorkerPoolRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_FeeReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(FeeReportRequest)
 	if err := dec(in); err != nil {
@@ -2942,6 +3672,42 @@ func _Lightning_UpdateChannelPolicy_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_SuggestFees_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuggestFeesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).SuggestFees(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/SuggestFees",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).SuggestFees(ctx, req.(*SuggestFeesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ApplyFeeSuggestions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApplyFeeSuggestionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ApplyFeeSuggestions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ApplyFeeSuggestions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ApplyFeeSuggestions(ctx, req.(*ApplyFeeSuggestionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_ForwardingHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ForwardingHistoryRequest)
 	if err := dec(in); err != nil {
@@ -3053,6 +3819,24 @@ func (x *lightningSubscribeChannelBackupsServer) Send(m *ChanBackupSnapshot) err
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Lightning_BackupReplicationStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BackupReplicationStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).BackupReplicationStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/BackupReplicationStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).BackupReplicationStatus(ctx, req.(*BackupReplicationStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_BakeMacaroon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(BakeMacaroonRequest)
 	if err := dec(in); err != nil {
@@ -3244,6 +4028,96 @@ func _Lightning_LookupHtlcResolution_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_CommitmentFeeInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommitmentFeeInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).CommitmentFeeInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/CommitmentFeeInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).CommitmentFeeInfo(ctx, req.(*CommitmentFeeInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ExportResolutions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportResolutionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ExportResolutions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ExportResolutions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ExportResolutions(ctx, req.(*ExportResolutionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_TimeLockedFunds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TimeLockedFundsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).TimeLockedFunds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/TimeLockedFunds",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).TimeLockedFunds(ctx, req.(*TimeLockedFundsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ListPendingResolutions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPendingResolutionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ListPendingResolutions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ListPendingResolutions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ListPendingResolutions(ctx, req.(*ListPendingResolutionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_AuditScidLeaks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuditScidLeaksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).AuditScidLeaks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/AuditScidLeaks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).AuditScidLeaks(ctx, req.(*AuditScidLeaksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Lightning_ServiceDesc is the grpc.ServiceDesc for Lightning service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -3303,6 +4177,26 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListPeers",
 			Handler:    _Lightning_ListPeers_Handler,
 		},
+		{
+			MethodName: "AllowPeer",
+			Handler:    _Lightning_AllowPeer_Handler,
+		},
+		{
+			MethodName: "DisallowPeer",
+			Handler:    _Lightning_DisallowPeer_Handler,
+		},
+		{
+			MethodName: "ListAllowedPeers",
+			Handler:    _Lightning_ListAllowedPeers_Handler,
+		},
+		{
+			MethodName: "GetGossipEgressLimits",
+			Handler:    _Lightning_GetGossipEgressLimits_Handler,
+		},
+		{
+			MethodName: "UpdateGossipEgressLimits",
+			Handler:    _Lightning_UpdateGossipEgressLimits_Handler,
+		},
 		{
 			MethodName: "GetInfo",
 			Handler:    _Lightning_GetInfo_Handler,
@@ -3315,6 +4209,18 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetRecoveryInfo",
 			Handler:    _Lightning_GetRecoveryInfo_Handler,
 		},
+		{
+			MethodName: "CheckDBIntegrity",
+			Handler:    _Lightning_CheckDBIntegrity_Handler,
+		},
+		{
+			MethodName: "PruneDBOrphans",
+			Handler:    _Lightning_PruneDBOrphans_Handler,
+		},
+		{
+			MethodName: "CreateDBSnapshot",
+			Handler:    _Lightning_CreateDBSnapshot_Handler,
+		},
 		{
 			MethodName: "PendingChannels",
 			Handler:    _Lightning_PendingChannels_Handler,
@@ -3407,10 +4313,22 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "StopDaemon",
 			Handler:    _Lightning_StopDaemon_Handler,
 		},
+		{
+			MethodName: "InjectGossip",
+			Handler:    _Lightning_InjectGossip_Handler,
+		},
 		{
 			MethodName: "DebugLevel",
 			Handler:    _Lightning_DebugLevel_Handler,
 		},
+		{
+			MethodName: "GetWorkerPoolStats",
+			Handler:    _Lightning_GetWorkerPoolStats_Handler,
+		},
+		{
+			MethodName: "ResizeWorkerPool",
+			Handler:    _Lightning_ResizeWorkerPool_Handler,
+		},
 		{
 			MethodName: "FeeReport",
 			Handler:    _Lightning_FeeReport_Handler,
@@ -3419,6 +4337,14 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateChannelPolicy",
 			Handler:    _Lightning_UpdateChannelPolicy_Handler,
 		},
+		{
+			MethodName: "SuggestFees",
+			Handler:    _Lightning_SuggestFees_Handler,
+		},
+		{
+			MethodName: "ApplyFeeSuggestions",
+			Handler:    _Lightning_ApplyFeeSuggestions_Handler,
+		},
 		{
 			MethodName: "ForwardingHistory",
 			Handler:    _Lightning_ForwardingHistory_Handler,
@@ -3439,6 +4365,10 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RestoreChannelBackups",
 			Handler:    _Lightning_RestoreChannelBackups_Handler,
 		},
+		{
+			MethodName: "BackupReplicationStatus",
+			Handler:    _Lightning_BackupReplicationStatus_Handler,
+		},
 		{
 			MethodName: "BakeMacaroon",
 			Handler:    _Lightning_BakeMacaroon_Handler,
@@ -3471,6 +4401,26 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "LookupHtlcResolution",
 			Handler:    _Lightning_LookupHtlcResolution_Handler,
 		},
+		{
+			MethodName: "CommitmentFeeInfo",
+			Handler:    _Lightning_CommitmentFeeInfo_Handler,
+		},
+		{
+			MethodName: "ExportResolutions",
+			Handler:    _Lightning_ExportResolutions_Handler,
+		},
+		{
+			MethodName: "TimeLockedFunds",
+			Handler:    _Lightning_TimeLockedFunds_Handler,
+		},
+		{
+			MethodName: "ListPendingResolutions",
+			Handler:    _Lightning_ListPendingResolutions_Handler,
+		},
+		{
+			MethodName: "AuditScidLeaks",
+			Handler:    _Lightning_AuditScidLeaks_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -3526,6 +4476,11 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Lightning_SubscribeChannelGraph_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SubscribeGossipBridge",
+			Handler:       _Lightning_SubscribeGossipBridge_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "SubscribeChannelBackups",
 			Handler:       _Lightning_SubscribeChannelBackups_Handler,
@@ -304,6 +304,69 @@ func (s *Server) updateFeatures(currentfeatures *lnwire.RawFeatureVector,
 	return raw, ops, nil
 }
 
+// updateCustomRecords computes the custom records update that is applied to
+// the node announcement after executing the update actions.
+func (s *Server) updateCustomRecords(currentExtraData lnwire.ExtraOpaqueData,
+	updates []*UpdateCustomRecordAction) (netann.CustomRecordsUpdate,
+	*lnrpc.Op, error) {
+
+	var update netann.CustomRecordsUpdate
+
+	current, err := netann.CustomRecords(currentExtraData)
+	if err != nil {
+		return update, nil, fmt.Errorf("unable to parse current "+
+			"custom records: %w", err)
+	}
+
+	ops := &lnrpc.Op{Entity: "custom_records"}
+	update.Set = make(lnwire.CustomRecords)
+	removed := make(map[uint64]struct{})
+
+	for _, u := range updates {
+		_, isSet := update.Set[u.Type]
+		_, isRemoved := removed[u.Type]
+		if isSet || isRemoved {
+			return update, nil, fmt.Errorf("multiple update "+
+				"actions for custom record %d", u.Type)
+		}
+
+		switch u.Action {
+		case UpdateAction_ADD:
+			update.Set[u.Type] = u.Value
+
+			action := fmt.Sprintf("%d added", u.Type)
+			if _, ok := current[u.Type]; ok {
+				action = fmt.Sprintf("%d replaced", u.Type)
+			}
+			ops.Actions = append(ops.Actions, action)
+
+		case UpdateAction_REMOVE:
+			if _, ok := current[u.Type]; !ok {
+				return update, nil, fmt.Errorf("invalid "+
+					"remove action for custom record %d, "+
+					"record is not set", u.Type)
+			}
+
+			removed[u.Type] = struct{}{}
+			update.Remove = append(update.Remove, u.Type)
+			ops.Actions = append(
+				ops.Actions, fmt.Sprintf("%d removed", u.Type),
+			)
+
+		default:
+			return update, nil, fmt.Errorf("invalid update "+
+				"action (%v) for custom record %d", u.Action,
+				u.Type)
+		}
+	}
+
+	if err := update.Validate(); err != nil {
+		return update, nil, err
+	}
+
+	return update, ops, nil
+}
+
 // UpdateNodeAnnouncement allows the caller to update the node parameters
 // and broadcasts a new version of the node announcement to its peers.
 func (s *Server) UpdateNodeAnnouncement(_ context.Context,
@@ -387,6 +450,24 @@ func (s *Server) UpdateNodeAnnouncement(_ context.Context,
 		)
 	}
 
+	if len(req.CustomRecordUpdates) > 0 {
+		update, ops, err := s.updateCustomRecords(
+			currentNodeAnn.ExtraOpaqueData, req.CustomRecordUpdates,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error trying to update node "+
+				"custom records: %w", err)
+		}
+
+		modifier, err := netann.NodeAnnUpdateCustomRecords(update)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Ops = append(resp.Ops, ops)
+		nodeModifiers = append(nodeModifiers, modifier)
+	}
+
 	if len(nodeModifiers) == 0 && !featureUpdates {
 		return nil, fmt.Errorf("unable to detect any new values to " +
 			"update the node announcement")
@@ -1,11 +1,58 @@
 package routerrpc
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/lightningnetwork/lnd/aliasmgr"
+	"github.com/lightningnetwork/lnd/graph"
+	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/macaroons"
 	"github.com/lightningnetwork/lnd/routing"
 )
 
+// PaymentRetryConfig holds the configuration of the durable payment retry
+// queue.
+//
+//nolint:lll
+type PaymentRetryConfig struct {
+	// Enable indicates whether payments that opted in with
+	// schedule_retries and fail with a retryable failure reason should
+	// automatically be retried.
+	Enable bool `long:"enable" description:"If true, payments sent with SendPaymentV2 and schedule_retries set that fail because no route was found, the node had insufficient balance or the payment timed out are automatically retried in the background, even across restarts."`
+
+	// Interval is the base delay after which a failed payment is retried.
+	Interval time.Duration `long:"interval" description:"The delay after which a failed payment is retried for the first time. The delay is doubled for every subsequent retry."`
+
+	// MaxInterval is the maximum delay between two retries.
+	MaxInterval time.Duration `long:"maxinterval" description:"The maximum delay between two retries of the same payment."`
+
+	// MaxRetries is the maximum number of retries of a payment.
+	MaxRetries uint32 `long:"maxretries" description:"The maximum number of times a failed payment is retried before giving up."`
+}
+
+// Validate checks the values configured for the payment retry queue.
+func (p *PaymentRetryConfig) Validate() error {
+	if !p.Enable {
+		return nil
+	}
+
+	if p.Interval <= 0 {
+		return fmt.Errorf("payment retry interval must be positive")
+	}
+
+	if p.MaxInterval < p.Interval {
+		return fmt.Errorf("payment retry max interval must not be "+
+			"lower than the interval %v", p.Interval)
+	}
+
+	if p.MaxRetries == 0 {
+		return fmt.Errorf("payment retry max retries must be positive")
+	}
+
+	return nil
+}
+
 // Config is the main configuration file for the router RPC server. It contains
 // all the items required for the router RPC server to carry out its duties.
 // The fields with struct tags are meant to be parsed as normal configuration
@@ -23,6 +70,10 @@ type Config struct {
 	// deployed to the network(v0.20.0).
 	UseStatusInitiated bool `long:"usestatusinitiated" description:"If true, the router will send Payment_INITIATED for new payments, otherwise Payment_In_FLIGHT will be sent for compatibility concerns."`
 
+	// PaymentRetry holds the configuration of the durable payment retry
+	// queue.
+	PaymentRetry *PaymentRetryConfig `group:"paymentretry" namespace:"paymentretry"`
+
 	// RouterMacPath is the path for the router macaroon. If unspecified
 	// then we assume that the macaroon will be found under the network
 	// directory, named DefaultRouterMacFilename.
@@ -51,6 +102,14 @@ type Config struct {
 	// AliasMgr is the alias manager instance that is used to handle all the
 	// SCID alias related information for channels.
 	AliasMgr *aliasmgr.Manager
+
+	// PaymentRetryDB is the database that payments scheduled for a retry
+	// are persisted in.
+	PaymentRetryDB kvdb.Backend
+
+	// SubscribeTopology is used to subscribe to graph topology changes,
+	// which trigger retries of payments to the updated nodes.
+	SubscribeTopology func() (*graph.TopologyClient, error)
 }
 
 // DefaultConfig defines the config defaults.
@@ -75,10 +134,18 @@ func DefaultConfig() *Config {
 			DecayTime:  routing.DefaultBimodalDecayTime,
 		},
 		FeeEstimationTimeout: routing.DefaultFeeEstimationTimeout,
+
+		ShardReservationTimeout: routing.DefaultShardReservationTimeout,
+		ShardPacingInterval:     routing.DefaultShardPacingInterval,
 	}
 
 	return &Config{
 		RoutingConfig: defaultRoutingConfig,
+		PaymentRetry: &PaymentRetryConfig{
+			Interval:    routing.DefaultPaymentRetryInterval,
+			MaxInterval: routing.DefaultMaxPaymentRetryInterval,
+			MaxRetries:  routing.DefaultMaxPaymentRetries,
+		},
 	}
 }
 
@@ -103,5 +170,9 @@ func GetRoutingConfig(cfg *Config) *RoutingConfig {
 			DecayTime:  cfg.BimodalConfig.DecayTime,
 		},
 		FeeEstimationTimeout: cfg.FeeEstimationTimeout,
+
+		ShardReservationTimeout: cfg.ShardReservationTimeout,
+		ShardPacingInterval:     cfg.ShardPacingInterval,
+		LearnFromForwards:       cfg.LearnFromForwards,
 	}
 }
@@ -154,6 +154,11 @@ func (r *forwardInterceptor) resolveFromClient(
 			outWireCustomRecords = fn.Some[lnwire.CustomRecords](cr)
 		}
 
+		outExpiry := fn.None[uint32]()
+		if in.OutExpiry > 0 {
+			outExpiry = fn.Some(in.OutExpiry)
+		}
+
 		//nolint:lll
 		return r.htlcSwitch.Resolve(&htlcswitch.FwdResolution{
 			Key:                  circuitKey,
@@ -161,6 +166,7 @@ func (r *forwardInterceptor) resolveFromClient(
 			InAmountMsat:         inAmtMsat,
 			OutAmountMsat:        outAmtMsat,
 			OutWireCustomRecords: outWireCustomRecords,
+			OutExpiry:            outExpiry,
 		})
 
 	case ResolveHoldForwardAction_FAIL:
@@ -0,0 +1,172 @@
+package routerrpc
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/fn"
+)
+
+// maxPaymentGroupLen is the maximum length of a payment group.
+const maxPaymentGroupLen = 64
+
+// PaymentTransitions selects the payment status transitions a payment stream
+// delivers.
+type PaymentTransitions uint8
+
+const (
+	// AllTransitions delivers every update of a payment.
+	AllTransitions PaymentTransitions = iota
+
+	// FinalTransitions only delivers updates that move a payment into a
+	// final state, which is either succeeded or failed.
+	FinalTransitions
+
+	// InFlightTransitions only delivers updates of payments that are not
+	// final yet, which includes new payments and updates of their HTLC
+	// attempts.
+	InFlightTransitions
+)
+
+// String returns a human readable representation of the transitions.
+func (t PaymentTransitions) String() string {
+	switch t {
+	case AllTransitions:
+		return "all"
+
+	case FinalTransitions:
+		return "final"
+
+	case InFlightTransitions:
+		return "in-flight"
+
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(t))
+	}
+}
+
+// matches returns true if an update to the given status is delivered.
+func (t PaymentTransitions) matches(status channeldb.PaymentStatus) bool {
+	isInFlight := status == channeldb.StatusInitiated ||
+		status == channeldb.StatusInFlight
+
+	switch t {
+	case FinalTransitions:
+		return !isInFlight
+
+	case InFlightTransitions:
+		return isInFlight
+
+	default:
+		return true
+	}
+}
+
+// TrackPaymentsFilter describes the payment updates a payment stream
+// delivers, which allows high-volume senders to only receive the updates
+// they are interested in.
+type TrackPaymentsFilter struct {
+	// Transitions selects the status transitions that are delivered.
+	Transitions PaymentTransitions
+
+	// Groups restricts the stream to payments that were sent with one of
+	// the given payment groups. If empty, payments of all groups,
+	// including ones without a group, are delivered.
+	Groups fn.Set[string]
+}
+
+// Validate checks that the filter is sane.
+func (f *TrackPaymentsFilter) Validate() error {
+	if f.Transitions > InFlightTransitions {
+		return fmt.Errorf("unknown payment transitions: %v",
+			f.Transitions)
+	}
+
+	for group := range f.Groups {
+		if err := validatePaymentGroup(group); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// matches returns true if the given payment update is delivered.
+func (f *TrackPaymentsFilter) matches(payment *channeldb.MPPayment) bool {
+	if !f.Transitions.matches(payment.Status) {
+		return false
+	}
+
+	if f.Groups.IsEmpty() {
+		return true
+	}
+
+	return f.Groups.Contains(payment.Info.PaymentGroup)
+}
+
+// transitionsFilter returns a filter that delivers the updates of all payment
+// groups, optionally skipping in-flight updates.
+func transitionsFilter(noInflightUpdates bool) *TrackPaymentsFilter {
+	if noInflightUpdates {
+		return &TrackPaymentsFilter{Transitions: FinalTransitions}
+	}
+
+	return &TrackPaymentsFilter{Transitions: AllTransitions}
+}
+
+// unmarshalTrackPaymentsFilter creates the filter described by a track
+// payments request.
+func unmarshalTrackPaymentsFilter(
+	req *TrackPaymentsRequest) (*TrackPaymentsFilter, error) {
+
+	var transitions PaymentTransitions
+	switch req.Transitions {
+	case PaymentTransitionFilter_ALL_TRANSITIONS:
+		transitions = AllTransitions
+
+	case PaymentTransitionFilter_FINAL_TRANSITIONS:
+		transitions = FinalTransitions
+
+	case PaymentTransitionFilter_IN_FLIGHT_TRANSITIONS:
+		transitions = InFlightTransitions
+
+	default:
+		return nil, fmt.Errorf("unknown payment transitions: %v",
+			req.Transitions)
+	}
+
+	if req.NoInflightUpdates {
+		if transitions != AllTransitions {
+			return nil, fmt.Errorf("no_inflight_updates can't be " +
+				"combined with transitions")
+		}
+		transitions = FinalTransitions
+	}
+
+	filter := &TrackPaymentsFilter{
+		Transitions: transitions,
+	}
+	if len(req.PaymentGroups) > 0 {
+		filter.Groups = fn.NewSet(req.PaymentGroups...)
+	}
+
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+// validatePaymentGroup checks that the given payment group is usable.
+func validatePaymentGroup(group string) error {
+	switch {
+	case group == "":
+		return fmt.Errorf("payment group must not be empty")
+
+	case len(group) > maxPaymentGroupLen:
+		return fmt.Errorf("payment group exceeds %d bytes",
+			maxPaymentGroupLen)
+	}
+
+	return nil
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/netann"
 	"github.com/lightningnetwork/lnd/record"
 	"github.com/lightningnetwork/lnd/routing"
 	"github.com/lightningnetwork/lnd/routing/route"
@@ -60,7 +61,14 @@ type RouterBackend struct {
 
 	// FindRoute is a closure that abstracts away how we locate/query for
 	// routes.
-	FindRoute func(*routing.RouteRequest) (*route.Route, float64, error)
+	FindRoute func(context.Context, *routing.RouteRequest) (*route.Route,
+		float64, error)
+
+	// FindRouteToAny is a closure that queries for the best route to any
+	// of the given destination candidates. The selected destination is
+	// returned along with the route.
+	FindRouteToAny func(context.Context, *routing.RouteRequest,
+		[]route.Vertex) (*route.Route, float64, route.Vertex, error)
 
 	MissionControl MissionControl
 
@@ -100,6 +108,23 @@ type RouterBackend struct {
 	// management after manually setting channel status.
 	SetChannelAuto func(wire.OutPoint) error
 
+	// ScheduleMaintenance schedules a maintenance window that disables the
+	// given channels, or all public channels if none are given, for the
+	// given duration and returns the ID of the window.
+	ScheduleMaintenance func(start time.Time, duration time.Duration,
+		chanPoints ...wire.OutPoint) (uint64, error)
+
+	// CancelMaintenance cancels the maintenance window with the given ID.
+	CancelMaintenance func(id uint64) error
+
+	// MaintenanceWindows returns the scheduled and active maintenance
+	// windows.
+	MaintenanceWindows func() []netann.MaintenanceWindow
+
+	// ForwardLatencies returns the forward latency histograms of all
+	// channels.
+	ForwardLatencies func() htlcswitch.ChannelLatencies
+
 	// UseStatusInitiated is a boolean that indicates whether the router
 	// should use the new status code `Payment_INITIATED`.
 	//
@@ -123,6 +148,11 @@ type MissionControl interface {
 	// state as if no payment attempts have been made.
 	ResetHistory() error
 
+	// ResetHistoryFiltered clears the part of the mission control history
+	// that is selected by the passed filter and returns the number of node
+	// pairs that were affected.
+	ResetHistoryFiltered(filter *routing.ResetFilter) (int, error)
+
 	// GetHistorySnapshot takes a snapshot from the current mission control
 	// state and actual probability estimates.
 	GetHistorySnapshot() *routing.MissionControlSnapshot
@@ -164,10 +194,31 @@ func (r *RouterBackend) QueryRoutes(ctx context.Context,
 
 	// Query the channel router for a possible path to the destination that
 	// can carry `in.Amt` satoshis _including_ the total fee required on
-	// the route
-	route, successProb, err := r.FindRoute(routeReq)
-	if err != nil {
-		return nil, err
+	// the route. If destination candidates were given, the destination is
+	// selected among them.
+	var (
+		route        *route.Route
+		successProb  float64
+		selectedDest []byte
+	)
+	if len(in.DestCandidates) > 0 {
+		candidates, err := parseDestCandidates(in.DestCandidates)
+		if err != nil {
+			return nil, err
+		}
+
+		rt, prob, dest, err := r.FindRouteToAny(
+			ctx, routeReq, candidates,
+		)
+		if err != nil {
+			return nil, err
+		}
+		route, successProb, selectedDest = rt, prob, dest[:]
+	} else {
+		route, successProb, err = r.FindRoute(ctx, routeReq)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// For each valid route, we'll convert the result into the format
@@ -178,13 +229,30 @@ func (r *RouterBackend) QueryRoutes(ctx context.Context,
 	}
 
 	routeResp := &lnrpc.QueryRoutesResponse{
-		Routes:      []*lnrpc.Route{rpcRoute},
-		SuccessProb: successProb,
+		Routes:       []*lnrpc.Route{rpcRoute},
+		SuccessProb:  successProb,
+		SelectedDest: selectedDest,
 	}
 
 	return routeResp, nil
 }
 
+// parseDestCandidates parses the raw public keys of a set of destination
+// candidates.
+func parseDestCandidates(rawCandidates [][]byte) ([]route.Vertex, error) {
+	candidates := make([]route.Vertex, 0, len(rawCandidates))
+	for _, rawCandidate := range rawCandidates {
+		candidate, err := route.NewVertexFromBytes(rawCandidate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid destination "+
+				"candidate: %w", err)
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	return candidates, nil
+}
+
 func parsePubKey(key string) (route.Vertex, error) {
 	pubKeyBytes, err := hex.DecodeString(key)
 	if err != nil {
@@ -313,9 +381,9 @@ func (r *RouterBackend) parseQueryRoutesRequest(in *lnrpc.QueryRoutesRequest) (
 			destinationFeatures = pathFeatures.Clone()
 		}
 	} else {
-		// If we do not have a blinded path, a target pubkey must be
-		// set.
-		pk, err := parsePubKey(in.PubKey)
+		// If we do not have a blinded path, a target pubkey or a set of
+		// destination candidates must be set.
+		pk, err := parseQueryRoutesTarget(in)
 		if err != nil {
 			return nil, err
 		}
@@ -431,6 +499,35 @@ func (r *RouterBackend) parseQueryRoutesRequest(in *lnrpc.QueryRoutesRequest) (
 	)
 }
 
+// parseQueryRoutesTarget returns the target of a query routes request that
+// doesn't use blinded paths. If destination candidates are given, the target is
+// selected among them by path finding, so the first candidate only serves as a
+// placeholder.
+func parseQueryRoutesTarget(in *lnrpc.QueryRoutesRequest) (route.Vertex,
+	error) {
+
+	if len(in.DestCandidates) == 0 {
+		return parsePubKey(in.PubKey)
+	}
+
+	if in.PubKey != "" {
+		return route.Vertex{}, errors.New("pub_key and " +
+			"dest_candidates can't both be set")
+	}
+
+	if len(in.RouteHints) > 0 {
+		return route.Vertex{}, errors.New("route hints and " +
+			"dest_candidates can't both be set")
+	}
+
+	candidates, err := parseDestCandidates(in.DestCandidates)
+	if err != nil {
+		return route.Vertex{}, err
+	}
+
+	return candidates[0], nil
+}
+
 func parseBlindedPaymentPaths(in *lnrpc.QueryRoutesRequest) (
 	*routing.BlindedPaymentPathSet, error) {
 
@@ -439,6 +536,11 @@ func parseBlindedPaymentPaths(in *lnrpc.QueryRoutesRequest) (
 			"when blinded path is provided", in.PubKey)
 	}
 
+	if len(in.DestCandidates) > 0 {
+		return nil, errors.New("destination candidates and blinded " +
+			"path can't both be set")
+	}
+
 	if len(in.RouteHints) > 0 {
 		return nil, errors.New("route hints and blinded path can't " +
 			"both be set")
@@ -894,6 +996,15 @@ func (r *RouterBackend) extractIntentFromSendRequest(
 	payIntent.PayAttemptTimeout = time.Second *
 		time.Duration(rpcPayReq.TimeoutSeconds)
 
+	// Assign the payment to the requested payment group, if any.
+	if rpcPayReq.PaymentGroup != "" {
+		err := validatePaymentGroup(rpcPayReq.PaymentGroup)
+		if err != nil {
+			return nil, err
+		}
+		payIntent.PaymentGroup = rpcPayReq.PaymentGroup
+	}
+
 	// Route hints.
 	routeHints, err := unmarshallRouteHints(
 		rpcPayReq.RouteHints,
@@ -1059,12 +1170,21 @@ func (r *RouterBackend) extractIntentFromSendRequest(
 		// (and a custom route wasn't specified), construct the payment
 		// from the other fields.
 
-		// Payment destination.
-		target, err := route.NewVertexFromBytes(rpcPayReq.Dest)
-		if err != nil {
-			return nil, err
+		// Payment destination. If destination candidates are given,
+		// the destination is only selected once the payment intent is
+		// complete.
+		if len(rpcPayReq.DestCandidates) > 0 {
+			err := r.validateDestCandidates(rpcPayReq)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			target, err := route.NewVertexFromBytes(rpcPayReq.Dest)
+			if err != nil {
+				return nil, err
+			}
+			payIntent.Target = target
 		}
-		payIntent.Target = target
 
 		// Final payment CLTV delta.
 		if rpcPayReq.FinalCltvDelta != 0 {
@@ -1192,6 +1312,83 @@ func (r *RouterBackend) extractIntentFromSendRequest(
 	return payIntent, nil
 }
 
+// validateDestCandidates checks that the destination candidates of a send
+// request are valid and aren't combined with conflicting fields.
+func (r *RouterBackend) validateDestCandidates(
+	rpcPayReq *SendPaymentRequest) error {
+
+	if len(rpcPayReq.Dest) > 0 {
+		return errors.New("dest and dest_candidates can't both be set")
+	}
+
+	if len(rpcPayReq.RouteHints) > 0 {
+		return errors.New("route hints and dest_candidates can't " +
+			"both be set")
+	}
+
+	candidates, err := parseDestCandidates(rpcPayReq.DestCandidates)
+	if err != nil {
+		return err
+	}
+
+	for _, candidate := range candidates {
+		if !rpcPayReq.AllowSelfPayment && candidate == r.SelfNode {
+			return errors.New("self-payments not allowed")
+		}
+	}
+
+	return nil
+}
+
+// selectDestCandidate selects the destination of a payment among the given
+// destination candidates and sets it as the payment's target. The candidate
+// that the best route can currently be found to is selected.
+func (r *RouterBackend) selectDestCandidate(ctx context.Context,
+	payIntent *routing.LightningPayment, rawCandidates [][]byte) error {
+
+	candidates, err := parseDestCandidates(rawCandidates)
+	if err != nil {
+		return err
+	}
+
+	// Path finding is unaware of the final cltv delta, so we subtract it
+	// from the limit, like the payment session does.
+	restrictions := &routing.RestrictParams{
+		ProbabilitySource:  r.MissionControl.GetProbability,
+		FeeLimit:           payIntent.FeeLimit,
+		OutgoingChannelIDs: payIntent.OutgoingChannelIDs,
+		LastHop:            payIntent.LastHop,
+		CltvLimit: payIntent.CltvLimit -
+			uint32(payIntent.FinalCLTVDelta),
+		DestCustomRecords: payIntent.DestCustomRecords,
+		DestFeatures:      payIntent.DestFeatures,
+		PaymentAddr:       payIntent.PaymentAddr,
+	}
+
+	// The target of the request is ignored when searching a route to any
+	// of the candidates.
+	routeReq, err := routing.NewRouteRequest(
+		r.SelfNode, &candidates[0], payIntent.Amount,
+		payIntent.TimePref, restrictions, payIntent.DestCustomRecords,
+		nil, nil, payIntent.FinalCLTVDelta,
+	)
+	if err != nil {
+		return err
+	}
+
+	_, _, dest, err := r.FindRouteToAny(ctx, routeReq, candidates)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Selected destination %v for payment %v", dest,
+		payIntent.Identifier())
+
+	payIntent.Target = dest
+
+	return nil
+}
+
 // BuildBlindedPathSet marshals a set of zpay32.BlindedPaymentPath and uses
 // the result to build a new routing.BlindedPaymentPathSet.
 func BuildBlindedPathSet(paths []*zpay32.BlindedPaymentPath) (
@@ -120,8 +120,8 @@ func testQueryRoutes(t *testing.T, useMissionControl bool, useMsat bool,
 		}
 	}
 
-	findRoute := func(req *routing.RouteRequest) (*route.Route, float64,
-		error) {
+	findRoute := func(_ context.Context, req *routing.RouteRequest) (
+		*route.Route, float64, error) {
 
 		if int64(req.Amount) != amtSat*1000 {
 			t.Fatal("unexpected amount")
@@ -241,8 +241,75 @@ func testQueryRoutes(t *testing.T, useMissionControl bool, useMsat bool,
 	}
 }
 
+// TestQueryRoutesDestCandidates asserts that the destination candidates of a
+// query routes request are passed on to path finding, and that the selected
+// destination is returned.
+func TestQueryRoutesDestCandidates(t *testing.T) {
+	t.Parallel()
+
+	candidates := []route.Vertex{node1, node2}
+	findRouteToAny := func(_ context.Context, req *routing.RouteRequest,
+		reqCandidates []route.Vertex) (*route.Route, float64,
+		route.Vertex, error) {
+
+		require.Equal(t, candidates, reqCandidates)
+
+		rt, err := route.NewRouteFromHops(
+			req.Amount, 144, req.Source,
+			[]*route.Hop{{PubKeyBytes: node2}},
+		)
+
+		return rt, 0.5, node2, err
+	}
+
+	backend := &RouterBackend{
+		FindRoute: func(context.Context, *routing.RouteRequest) (
+			*route.Route, float64, error) {
+
+			t.Fatal("unexpected single destination route query")
+			return nil, 0, nil
+		},
+		FindRouteToAny: findRouteToAny,
+		SelfNode:       route.Vertex{1, 2, 3},
+		FetchChannelCapacity: func(chanID uint64) (
+			btcutil.Amount, error) {
+
+			return 1, nil
+		},
+		MissionControl:   &mockMissionControl{},
+		MaxTotalTimelock: 1000,
+	}
+
+	request := &lnrpc.QueryRoutesRequest{
+		Amt:            100000,
+		FinalCltvDelta: 100,
+		DestCandidates: [][]byte{node1[:], node2[:]},
+	}
+
+	resp, err := backend.QueryRoutes(context.Background(), request)
+	require.NoError(t, err)
+	require.Equal(t, node2[:], resp.SelectedDest)
+	require.Equal(t, 0.5, resp.SuccessProb)
+	require.Len(t, resp.Routes, 1)
+
+	// A target pubkey can't be combined with destination candidates.
+	request.PubKey = hex.EncodeToString(node1[:])
+	_, err = backend.QueryRoutes(context.Background(), request)
+	require.ErrorContains(t, err, "dest_candidates")
+
+	// Neither can malformed candidates be used.
+	request.PubKey = ""
+	request.DestCandidates = [][]byte{{1, 2, 3}}
+	_, err = backend.QueryRoutes(context.Background(), request)
+	require.ErrorContains(t, err, "invalid destination candidate")
+}
+
 type mockMissionControl struct {
 	MissionControl
+
+	resetAll      bool
+	resetFilter   *routing.ResetFilter
+	numPairsReset int
 }
 
 func (m *mockMissionControl) GetProbability(fromNode, toNode route.Vertex,
@@ -252,9 +319,17 @@ func (m *mockMissionControl) GetProbability(fromNode, toNode route.Vertex,
 }
 
 func (m *mockMissionControl) ResetHistory() error {
+	m.resetAll = true
 	return nil
 }
 
+func (m *mockMissionControl) ResetHistoryFiltered(
+	filter *routing.ResetFilter) (int, error) {
+
+	m.resetFilter = filter
+	return m.numPairsReset, nil
+}
+
 func (m *mockMissionControl) GetHistorySnapshot() *routing.MissionControlSnapshot {
 	return nil
 }
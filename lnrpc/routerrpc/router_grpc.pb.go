@@ -55,7 +55,8 @@ type RouterClient interface {
 	SendToRouteV2(ctx context.Context, in *SendToRouteRequest, opts ...grpc.CallOption) (*lnrpc.HTLCAttempt, error)
 	// lncli: `resetmc`
 	// ResetMissionControl clears all mission control state and starts with a clean
-	// slate.
+	// slate. If any of the filter fields of the request are set, only the matching
+	// part of the mission control state is cleared.
 	ResetMissionControl(ctx context.Context, in *ResetMissionControlRequest, opts ...grpc.CallOption) (*ResetMissionControlResponse, error)
 	// lncli: `querymc`
 	// QueryMissionControl exposes the internal mission control state to callers.
@@ -116,6 +117,27 @@ type RouterClient interface {
 	// channel to stay disabled until a subsequent manual request of either
 	// "enable" or "auto".
 	UpdateChanStatus(ctx context.Context, in *UpdateChanStatusRequest, opts ...grpc.CallOption) (*UpdateChanStatusResponse, error)
+	// lncli: `schedulemaintenance`
+	// ScheduleMaintenance schedules a maintenance window during which the given
+	// channels, or all public channels if none are given, are disabled on the
+	// network. The channels are re-enabled once the window ends. Channels that
+	// were manually disabled before the window started stay disabled. Windows
+	// aren't persisted and need to be scheduled again after a restart.
+	ScheduleMaintenance(ctx context.Context, in *ScheduleMaintenanceRequest, opts ...grpc.CallOption) (*ScheduleMaintenanceResponse, error)
+	// lncli: `cancelmaintenance`
+	// CancelMaintenance cancels a maintenance window. If the window already
+	// started, its channels are re-enabled.
+	CancelMaintenance(ctx context.Context, in *CancelMaintenanceRequest, opts ...grpc.CallOption) (*CancelMaintenanceResponse, error)
+	// lncli: `listmaintenance`
+	// ListMaintenanceWindows returns the scheduled and active maintenance
+	// windows, ordered by their start time.
+	ListMaintenanceWindows(ctx context.Context, in *ListMaintenanceWindowsRequest, opts ...grpc.CallOption) (*ListMaintenanceWindowsResponse, error)
+	// lncli: `fwdlatencies`
+	// ForwardLatencies returns per channel histograms of the time htlcs spent
+	// between being locked in and forwarded (incoming) and between being
+	// forwarded and resolved by the peer (outgoing), aggregated since startup.
+	// They allow finding slow peers that delay the resolution of htlcs.
+	ForwardLatencies(ctx context.Context, in *ForwardLatenciesRequest, opts ...grpc.CallOption) (*ForwardLatenciesResponse, error)
 	// XAddLocalChanAliases is an experimental API that creates a set of new
 	// channel SCID alias mappings. The final total set of aliases in the manager
 	// after the add operation is returned. This is only a locally stored alias,
@@ -128,6 +150,29 @@ type RouterClient interface {
 	// operation is returned. The deletion will not be communicated to the channel
 	// peer via any message.
 	XDeleteLocalChanAliases(ctx context.Context, in *DeleteAliasesRequest, opts ...grpc.CallOption) (*DeleteAliasesResponse, error)
+	// XAllocateLocalChanAlias is an experimental API that allocates the next
+	// free alias from the SCID alias range and adds it as a local alias of the
+	// given channel. Like aliases added with XAddLocalChanAliases, the alias is
+	// not communicated to the channel peer. Aliases can be retired again with
+	// XDeleteLocalChanAliases.
+	XAllocateLocalChanAlias(ctx context.Context, in *AllocateAliasRequest, opts ...grpc.CallOption) (*AllocateAliasResponse, error)
+	// XLookupLocalChanAlias is an experimental API that maps any local alias
+	// back to the base SCID of its channel. An error is returned if the alias
+	// is unknown.
+	XLookupLocalChanAlias(ctx context.Context, in *LookupAliasRequest, opts ...grpc.CallOption) (*LookupAliasResponse, error)
+	// lncli: `listscheduledpayments`
+	// ListScheduledPayments returns all payments that are tracked by the payment
+	// retry queue, ordered by the time of their next attempt. Payments are only
+	// added to the queue if they were sent with schedule_retries set.
+	ListScheduledPayments(ctx context.Context, in *ListScheduledPaymentsRequest, opts ...grpc.CallOption) (*ListScheduledPaymentsResponse, error)
+	// lncli: `sendpaymentbatch`
+	// SendPaymentBatch sends a batch of payments that share a common fee budget
+	// on a best-effort basis, and returns the outcome of each of them once all
+	// of them completed. A failed payment doesn't abort the remaining ones. The
+	// payments are sent one after the other, largest first, so they don't
+	// compete for the same local liquidity. Any share of the fee budget that a
+	// payment doesn't use is handed on to the payments after it.
+	SendPaymentBatch(ctx context.Context, in *SendPaymentBatchRequest, opts ...grpc.CallOption) (*SendPaymentBatchResponse, error)
 }
 
 type routerClient struct {
@@ -463,6 +508,42 @@ func (c *routerClient) UpdateChanStatus(ctx context.Context, in *UpdateChanStatu
 	return out, nil
 }
 
+func (c *routerClient) ScheduleMaintenance(ctx context.Context, in *ScheduleMaintenanceRequest, opts ...grpc.CallOption) (*ScheduleMaintenanceResponse, error) {
+	out := new(ScheduleMaintenanceResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/ScheduleMaintenance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) CancelMaintenance(ctx context.Context, in *CancelMaintenanceRequest, opts ...grpc.CallOption) (*CancelMaintenanceResponse, error) {
+	out := new(CancelMaintenanceResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/CancelMaintenance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) ListMaintenanceWindows(ctx context.Context, in *ListMaintenanceWindowsRequest, opts ...grpc.CallOption) (*ListMaintenanceWindowsResponse, error) {
+	out := new(ListMaintenanceWindowsResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/ListMaintenanceWindows", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) ForwardLatencies(ctx context.Context, in *ForwardLatenciesRequest, opts ...grpc.CallOption) (*ForwardLatenciesResponse, error) {
+	out := new(ForwardLatenciesResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/ForwardLatencies", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *routerClient) XAddLocalChanAliases(ctx context.Context, in *AddAliasesRequest, opts ...grpc.CallOption) (*AddAliasesResponse, error) {
 	out := new(AddAliasesResponse)
 	err := c.cc.Invoke(ctx, "/routerrpc.Router/XAddLocalChanAliases", in, out, opts...)
@@ -481,6 +562,42 @@ func (c *routerClient) XDeleteLocalChanAliases(ctx context.Context, in *DeleteAl
 	return out, nil
 }
 
+func (c *routerClient) XAllocateLocalChanAlias(ctx context.Context, in *AllocateAliasRequest, opts ...grpc.CallOption) (*AllocateAliasResponse, error) {
+	out := new(AllocateAliasResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/XAllocateLocalChanAlias", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) XLookupLocalChanAlias(ctx context.Context, in *LookupAliasRequest, opts ...grpc.CallOption) (*LookupAliasResponse, error) {
+	out := new(LookupAliasResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/XLookupLocalChanAlias", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) ListScheduledPayments(ctx context.Context, in *ListScheduledPaymentsRequest, opts ...grpc.CallOption) (*ListScheduledPaymentsResponse, error) {
+	out := new(ListScheduledPaymentsResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/ListScheduledPayments", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) SendPaymentBatch(ctx context.Context, in *SendPaymentBatchRequest, opts ...grpc.CallOption) (*SendPaymentBatchResponse, error) {
+	out := new(SendPaymentBatchResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/SendPaymentBatch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RouterServer is the server API for Router service.
 // All implementations must embed UnimplementedRouterServer
 // for forward compatibility
@@ -521,7 +638,8 @@ type RouterServer interface {
 	SendToRouteV2(context.Context, *SendToRouteRequest) (*lnrpc.HTLCAttempt, error)
 	// lncli: `resetmc`
 	// ResetMissionControl clears all mission control state and starts with a clean
-	// slate.
+	// slate. If any of the filter fields of the request are set, only the matching
+	// part of the mission control state is cleared.
 	ResetMissionControl(context.Context, *ResetMissionControlRequest) (*ResetMissionControlResponse, error)
 	// lncli: `querymc`
 	// QueryMissionControl exposes the internal mission control state to callers.
@@ -582,6 +700,27 @@ type RouterServer interface {
 	// channel to stay disabled until a subsequent manual request of either
 	// "enable" or "auto".
 	UpdateChanStatus(context.Context, *UpdateChanStatusRequest) (*UpdateChanStatusResponse, error)
+	// lncli: `schedulemaintenance`
+	// ScheduleMaintenance schedules a maintenance window during which the given
+	// channels, or all public channels if none are given, are disabled on the
+	// network. The channels are re-enabled once the window ends. Channels that
+	// were manually disabled before the window started stay disabled. Windows
+	// aren't persisted and need to be scheduled again after a restart.
+	ScheduleMaintenance(context.Context, *ScheduleMaintenanceRequest) (*ScheduleMaintenanceResponse, error)
+	// lncli: `cancelmaintenance`
+	// CancelMaintenance cancels a maintenance window. If the window already
+	// started, its channels are re-enabled.
+	CancelMaintenance(context.Context, *CancelMaintenanceRequest) (*CancelMaintenanceResponse, error)
+	// lncli: `listmaintenance`
+	// ListMaintenanceWindows returns the scheduled and active maintenance
+	// windows, ordered by their start time.
+	ListMaintenanceWindows(context.Context, *ListMaintenanceWindowsRequest) (*ListMaintenanceWindowsResponse, error)
+	// lncli: `fwdlatencies`
+	// ForwardLatencies returns per channel histograms of the time htlcs spent
+	// between being locked in and forwarded (incoming) and between being
+	// forwarded and resolved by the peer (outgoing), aggregated since startup.
+	// They allow finding slow peers that delay the resolution of htlcs.
+	ForwardLatencies(context.Context, *ForwardLatenciesRequest) (*ForwardLatenciesResponse, error)
 	// XAddLocalChanAliases is an experimental API that creates a set of new
 	// channel SCID alias mappings. The final total set of aliases in the manager
 	// after the add operation is returned. This is only a locally stored alias,
@@ -594,6 +733,29 @@ type RouterServer interface {
 	// operation is returned. The deletion will not be communicated to the channel
 	// peer via any message.
 	XDeleteLocalChanAliases(context.Context, *DeleteAliasesRequest) (*DeleteAliasesResponse, error)
+	// XAllocateLocalChanAlias is an experimental API that allocates the next
+	// free alias from the SCID alias range and adds it as a local alias of the
+	// given channel. Like aliases added with XAddLocalChanAliases, the alias is
+	// not communicated to the channel peer. Aliases can be retired again with
+	// XDeleteLocalChanAliases.
+	XAllocateLocalChanAlias(context.Context, *AllocateAliasRequest) (*AllocateAliasResponse, error)
+	// XLookupLocalChanAlias is an experimental API that maps any local alias
+	// back to the base SCID of its channel. An error is returned if the alias
+	// is unknown.
+	XLookupLocalChanAlias(context.Context, *LookupAliasRequest) (*LookupAliasResponse, error)
+	// lncli: `listscheduledpayments`
+	// ListScheduledPayments returns all payments that are tracked by the payment
+	// retry queue, ordered by the time of their next attempt. Payments are only
+	// added to the queue if they were sent with schedule_retries set.
+	ListScheduledPayments(context.Context, *ListScheduledPaymentsRequest) (*ListScheduledPaymentsResponse, error)
+	// lncli: `sendpaymentbatch`
+	// SendPaymentBatch sends a batch of payments that share a common fee budget
+	// on a best-effort basis, and returns the outcome of each of them once all
+	// of them completed. A failed payment doesn't abort the remaining ones. The
+	// payments are sent one after the other, largest first, so they don't
+	// compete for the same local liquidity. Any share of the fee budget that a
+	// payment doesn't use is handed on to the payments after it.
+	SendPaymentBatch(context.Context, *SendPaymentBatchRequest) (*SendPaymentBatchResponse, error)
 	mustEmbedUnimplementedRouterServer()
 }
 
@@ -655,12 +817,36 @@ func (UnimplementedRouterServer) HtlcInterceptor(Router_HtlcInterceptorServer) e
 func (UnimplementedRouterServer) UpdateChanStatus(context.Context, *UpdateChanStatusRequest) (*UpdateChanStatusResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateChanStatus not implemented")
 }
+func (UnimplementedRouterServer) ScheduleMaintenance(context.Context, *ScheduleMaintenanceRequest) (*ScheduleMaintenanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ScheduleMaintenance not implemented")
+}
+func (UnimplementedRouterServer) CancelMaintenance(context.Context, *CancelMaintenanceRequest) (*CancelMaintenanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelMaintenance not implemented")
+}
+func (UnimplementedRouterServer) ListMaintenanceWindows(context.Context, *ListMaintenanceWindowsRequest) (*ListMaintenanceWindowsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMaintenanceWindows not implemented")
+}
+func (UnimplementedRouterServer) ForwardLatencies(context.Context, *ForwardLatenciesRequest) (*ForwardLatenciesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ForwardLatencies not implemented")
+}
 func (UnimplementedRouterServer) XAddLocalChanAliases(context.Context, *AddAliasesRequest) (*AddAliasesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method XAddLocalChanAliases not implemented")
 }
 func (UnimplementedRouterServer) XDeleteLocalChanAliases(context.Context, *DeleteAliasesRequest) (*DeleteAliasesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method XDeleteLocalChanAliases not implemented")
 }
+func (UnimplementedRouterServer) XAllocateLocalChanAlias(context.Context, *AllocateAliasRequest) (*AllocateAliasResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method XAllocateLocalChanAlias not implemented")
+}
+func (UnimplementedRouterServer) XLookupLocalChanAlias(context.Context, *LookupAliasRequest) (*LookupAliasResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method XLookupLocalChanAlias not implemented")
+}
+func (UnimplementedRouterServer) ListScheduledPayments(context.Context, *ListScheduledPaymentsRequest) (*ListScheduledPaymentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListScheduledPayments not implemented")
+}
+func (UnimplementedRouterServer) SendPaymentBatch(context.Context, *SendPaymentBatchRequest) (*SendPaymentBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendPaymentBatch not implemented")
+}
 func (UnimplementedRouterServer) mustEmbedUnimplementedRouterServer() {}
 
 // UnsafeRouterServer may be embedded to opt out of forward compatibility for this service.
@@ -1024,6 +1210,78 @@ func _Router_UpdateChanStatus_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Router_ScheduleMaintenance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduleMaintenanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).ScheduleMaintenance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/ScheduleMaintenance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).ScheduleMaintenance(ctx, req.(*ScheduleMaintenanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_CancelMaintenance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelMaintenanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).CancelMaintenance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/CancelMaintenance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).CancelMaintenance(ctx, req.(*CancelMaintenanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_ListMaintenanceWindows_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMaintenanceWindowsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).ListMaintenanceWindows(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/ListMaintenanceWindows",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).ListMaintenanceWindows(ctx, req.(*ListMaintenanceWindowsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_ForwardLatencies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ForwardLatenciesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).ForwardLatencies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/ForwardLatencies",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).ForwardLatencies(ctx, req.(*ForwardLatenciesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Router_XAddLocalChanAliases_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(AddAliasesRequest)
 	if err := dec(in); err != nil {
@@ -1060,6 +1318,78 @@ func _Router_XDeleteLocalChanAliases_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Router_XAllocateLocalChanAlias_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AllocateAliasRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).XAllocateLocalChanAlias(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/XAllocateLocalChanAlias",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).XAllocateLocalChanAlias(ctx, req.(*AllocateAliasRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_XLookupLocalChanAlias_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LookupAliasRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).XLookupLocalChanAlias(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/XLookupLocalChanAlias",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).XLookupLocalChanAlias(ctx, req.(*LookupAliasRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_ListScheduledPayments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListScheduledPaymentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).ListScheduledPayments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/ListScheduledPayments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).ListScheduledPayments(ctx, req.(*ListScheduledPaymentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_SendPaymentBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendPaymentBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).SendPaymentBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/SendPaymentBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).SendPaymentBatch(ctx, req.(*SendPaymentBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Router_ServiceDesc is the grpc.ServiceDesc for Router service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -1111,6 +1441,22 @@ var Router_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateChanStatus",
 			Handler:    _Router_UpdateChanStatus_Handler,
 		},
+		{
+			MethodName: "ScheduleMaintenance",
+			Handler:    _Router_ScheduleMaintenance_Handler,
+		},
+		{
+			MethodName: "CancelMaintenance",
+			Handler:    _Router_CancelMaintenance_Handler,
+		},
+		{
+			MethodName: "ListMaintenanceWindows",
+			Handler:    _Router_ListMaintenanceWindows_Handler,
+		},
+		{
+			MethodName: "ForwardLatencies",
+			Handler:    _Router_ForwardLatencies_Handler,
+		},
 		{
 			MethodName: "XAddLocalChanAliases",
 			Handler:    _Router_XAddLocalChanAliases_Handler,
@@ -1119,6 +1465,22 @@ var Router_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "XDeleteLocalChanAliases",
 			Handler:    _Router_XDeleteLocalChanAliases_Handler,
 		},
+		{
+			MethodName: "XAllocateLocalChanAlias",
+			Handler:    _Router_XAllocateLocalChanAlias_Handler,
+		},
+		{
+			MethodName: "XLookupLocalChanAlias",
+			Handler:    _Router_XLookupLocalChanAlias_Handler,
+		},
+		{
+			MethodName: "ListScheduledPayments",
+			Handler:    _Router_ListScheduledPayments_Handler,
+		},
+		{
+			MethodName: "SendPaymentBatch",
+			Handler:    _Router_SendPaymentBatch_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
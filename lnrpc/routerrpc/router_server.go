@@ -16,18 +16,22 @@ import (
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/lightningnetwork/lnd/aliasmgr"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/clock"
 	"github.com/lightningnetwork/lnd/fn"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/invoicesrpc"
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/macaroons"
+	"github.com/lightningnetwork/lnd/netann"
 	"github.com/lightningnetwork/lnd/routing"
 	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/ticker"
 	"github.com/lightningnetwork/lnd/zpay32"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"gopkg.in/macaroon-bakery.v2/bakery"
 )
 
@@ -64,6 +68,11 @@ var (
 	// allowed SCID aliases.
 	ErrNoValidAlias = errors.New("not a valid alias")
 
+	// errPaymentRetriesDisabled is returned if automatic payment retries
+	// are requested or listed, but the payment retry queue isn't enabled.
+	errPaymentRetriesDisabled = errors.New("payment retries are not " +
+		"enabled, set routerrpc.paymentretry.enable")
+
 	// macaroonOps are the set of capabilities that our minted macaroon (if
 	// it doesn't already exist) will have.
 	macaroonOps = []bakery.Op{
@@ -151,6 +160,22 @@ var (
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/routerrpc.Router/ScheduleMaintenance": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/routerrpc.Router/CancelMaintenance": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/routerrpc.Router/ListMaintenanceWindows": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/routerrpc.Router/ForwardLatencies": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
 		"/routerrpc.Router/XAddLocalChanAliases": {{
 			Entity: "offchain",
 			Action: "write",
@@ -159,6 +184,22 @@ var (
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/routerrpc.Router/XAllocateLocalChanAlias": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/routerrpc.Router/XLookupLocalChanAlias": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/routerrpc.Router/ListScheduledPayments": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/routerrpc.Router/SendPaymentBatch": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
 	}
 
 	// DefaultRouterMacFilename is the default name of the router macaroon
@@ -188,6 +229,11 @@ type Server struct {
 
 	cfg *Config
 
+	// retryQueue is the queue of payments that are automatically retried
+	// if they fail with a retryable failure reason. It is nil if automatic
+	// payment retries are disabled.
+	retryQueue *routing.PaymentRetryQueue
+
 	quit chan struct{}
 }
 
@@ -245,6 +291,22 @@ func New(cfg *Config) (*Server, lnrpc.MacaroonPerms, error) {
 		quit: make(chan struct{}),
 	}
 
+	if cfg.PaymentRetry != nil && cfg.PaymentRetry.Enable {
+		checkInterval := routing.DefaultPaymentRetryCheckInterval
+		retryCfg := &routing.PaymentRetryQueueConfig{
+			DB:                cfg.PaymentRetryDB,
+			Control:           cfg.RouterBackend.Tower,
+			SubscribeTopology: cfg.SubscribeTopology,
+			Resend:            routerServer.resendPayment,
+			Clock:             clock.NewDefaultClock(),
+			Ticker:            ticker.New(checkInterval),
+			RetryInterval:     cfg.PaymentRetry.Interval,
+			MaxRetryInterval:  cfg.PaymentRetry.MaxInterval,
+			MaxRetries:        cfg.PaymentRetry.MaxRetries,
+		}
+		routerServer.retryQueue = routing.NewPaymentRetryQueue(retryCfg)
+	}
+
 	return routerServer, macPermissions, nil
 }
 
@@ -256,6 +318,13 @@ func (s *Server) Start() error {
 		return nil
 	}
 
+	if s.retryQueue != nil {
+		if err := s.retryQueue.Start(); err != nil {
+			return fmt.Errorf("unable to start payment retry "+
+				"queue: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -268,6 +337,11 @@ func (s *Server) Stop() error {
 	}
 
 	close(s.quit)
+
+	if s.retryQueue != nil {
+		return s.retryQueue.Stop()
+	}
+
 	return nil
 }
 
@@ -344,11 +418,41 @@ func (r *ServerShell) CreateSubServer(configRegistry lnrpc.SubServerConfigDispat
 func (s *Server) SendPaymentV2(req *SendPaymentRequest,
 	stream Router_SendPaymentV2Server) error {
 
+	// Payments can only be scheduled for retries if the retry queue is
+	// enabled. AMP payments are never retried, as a new set ID is
+	// generated for every send request.
+	if req.ScheduleRetries {
+		switch {
+		case s.retryQueue == nil:
+			return status.Error(
+				codes.FailedPrecondition,
+				errPaymentRetriesDisabled.Error(),
+			)
+
+		case req.Amp:
+			return status.Error(
+				codes.InvalidArgument, "retries can't be "+
+					"scheduled for AMP payments",
+			)
+		}
+	}
+
 	payment, err := s.cfg.RouterBackend.extractIntentFromSendRequest(req)
 	if err != nil {
 		return err
 	}
 
+	// If destination candidates were given, we select the destination
+	// among them before initiating the payment.
+	if len(req.DestCandidates) > 0 {
+		err := s.cfg.RouterBackend.selectDestCandidate(
+			stream.Context(), payment, req.DestCandidates,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Get the payment hash.
 	payHash := payment.Identifier()
 
@@ -371,6 +475,11 @@ func (s *Server) SendPaymentV2(req *SendPaymentRequest,
 		return err
 	}
 
+	// If the caller asked for automatic retries, add the payment to the
+	// retry queue before sending it, so the outcome of the first attempt
+	// isn't missed.
+	s.schedulePaymentRetry(req, payment)
+
 	// Subscribe to the payment before sending it to make sure we won't
 	// miss events.
 	sub, err := s.subscribePayment(payHash)
@@ -399,6 +508,185 @@ func (s *Server) SendPaymentV2(req *SendPaymentRequest,
 	return s.trackPayment(sub, payHash, stream, req.NoInflightUpdates)
 }
 
+// schedulePaymentRetry adds the payment to the retry queue, if the caller
+// asked for automatic retries. Failing to schedule the payment isn't fatal,
+// the payment is still sent out without being retried.
+func (s *Server) schedulePaymentRetry(req *SendPaymentRequest,
+	payment *routing.LightningPayment) {
+
+	if s.retryQueue == nil || !req.ScheduleRetries {
+		return
+	}
+
+	// Retries are made to the destination that was selected for the
+	// initial attempt.
+	if len(req.DestCandidates) > 0 {
+		req = proto.Clone(req).(*SendPaymentRequest)
+		req.Dest = payment.Target[:]
+		req.DestCandidates = nil
+	}
+
+	request, err := proto.Marshal(req)
+	if err == nil {
+		err = s.retryQueue.Schedule(
+			payment.Identifier(), payment.Target, payment.Amount,
+			request,
+		)
+	}
+	if err != nil {
+		log.Warnf("Unable to schedule retries for payment %v: %v",
+			payment.Identifier(), err)
+	}
+}
+
+// resendPayment sends out a payment that was scheduled for a retry again. The
+// passed request is the serialized SendPaymentRequest of the payment. The
+// payment loop is stopped once the passed context is canceled.
+func (s *Server) resendPayment(ctx context.Context, request []byte) error {
+	req := &SendPaymentRequest{}
+	if err := proto.Unmarshal(request, req); err != nil {
+		return err
+	}
+
+	payment, err := s.cfg.RouterBackend.extractIntentFromSendRequest(req)
+	if err != nil {
+		return err
+	}
+
+	paySession, shardTracker, err := s.cfg.Router.PreparePayment(payment)
+	if err != nil {
+		return err
+	}
+
+	// The stream of the original request is long gone, so the payment is
+	// bound to the context of the retry queue instead, which is canceled
+	// once the queue shuts down.
+	s.cfg.Router.SendPaymentAsync(ctx, payment, paySession, shardTracker)
+
+	return nil
+}
+
+// ListScheduledPayments returns all payments that are tracked by the payment
+// retry queue, ordered by the time of their next attempt.
+func (s *Server) ListScheduledPayments(_ context.Context,
+	_ *ListScheduledPaymentsRequest) (*ListScheduledPaymentsResponse,
+	error) {
+
+	if s.retryQueue == nil {
+		return nil, status.Error(
+			codes.FailedPrecondition,
+			errPaymentRetriesDisabled.Error(),
+		)
+	}
+
+	scheduled := s.retryQueue.ListScheduledPayments()
+
+	resp := &ListScheduledPaymentsResponse{
+		Payments: make([]*ScheduledPayment, 0, len(scheduled)),
+	}
+	for _, p := range scheduled {
+		lastFailure, err := marshallPaymentFailureReason(
+			p.LastFailure,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		var nextAttempt int64
+		if !p.InFlight() {
+			nextAttempt = p.NextAttempt.UnixNano()
+		}
+
+		resp.Payments = append(resp.Payments, &ScheduledPayment{
+			PaymentHash:   p.PaymentHash[:],
+			Dest:          p.Target[:],
+			AmtMsat:       int64(p.Amount),
+			Retries:       p.Retries,
+			NextAttemptNs: nextAttempt,
+			LastFailure:   lastFailure,
+		})
+	}
+
+	return resp, nil
+}
+
+// SendPaymentBatch sends a batch of payments that share a common fee budget
+// and returns the outcome of each of them once all of them completed.
+func (s *Server) SendPaymentBatch(_ context.Context,
+	req *SendPaymentBatchRequest) (*SendPaymentBatchResponse, error) {
+
+	batch := &routing.BatchPayment{
+		Payments: make(
+			[]*routing.LightningPayment, 0, len(req.Payments),
+		),
+		FeeBudget: lnwire.MilliSatoshi(req.FeeBudgetMsat),
+	}
+	for i, payReq := range req.Payments {
+		// The outcome of a batch payment is only reported once, so it
+		// can't be retried in the background.
+		if payReq.ScheduleRetries {
+			return nil, status.Errorf(codes.InvalidArgument,
+				"payment %d: retries can't be scheduled for "+
+					"batch payments", i)
+		}
+
+		if len(payReq.DestCandidates) > 0 {
+			return nil, status.Errorf(codes.InvalidArgument,
+				"payment %d: destination candidates aren't "+
+					"supported for batch payments", i)
+		}
+
+		payment, err := s.cfg.RouterBackend.
+			extractIntentFromSendRequest(payReq)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument,
+				"payment %d: %v", i, err)
+		}
+
+		batch.Payments = append(batch.Payments, payment)
+	}
+
+	results, err := s.cfg.Router.SendPaymentBatch(batch)
+	switch {
+	case errors.Is(err, routing.ErrEmptyBatch),
+		errors.Is(err, routing.ErrDuplicateBatchPayment):
+
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+
+	case err != nil:
+		return nil, err
+	}
+
+	resp := &SendPaymentBatchResponse{
+		Results: make([]*BatchPaymentResult, 0, len(results)),
+	}
+	for i, result := range results {
+		hash := batch.Payments[i].Identifier()
+		rpcResult := &BatchPaymentResult{
+			PaymentHash:  hash[:],
+			FeeLimitMsat: int64(result.FeeLimit),
+		}
+		resp.Results = append(resp.Results, rpcResult)
+
+		if result.Err != nil {
+			rpcResult.Error = result.Err.Error()
+			continue
+		}
+
+		rpcRoute, err := s.cfg.RouterBackend.MarshallRoute(
+			result.Route,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		rpcResult.PaymentPreimage = result.Preimage[:]
+		rpcResult.Route = rpcRoute
+	}
+
+	return resp, nil
+}
+
 // EstimateRouteFee allows callers to obtain an expected value w.r.t how much it
 // may cost to send an HTLC to the target end destination. This method sends
 // probe payments to the target node, based on target invoice parameters and a
@@ -426,7 +714,7 @@ func (s *Server) EstimateRouteFee(ctx context.Context,
 			return nil, errors.New("amount must be greater than 0")
 
 		default:
-			return s.probeDestination(req.Dest, req.AmtSat)
+			return s.probeDestination(ctx, req.Dest, req.AmtSat)
 		}
 
 	case isProbeInvoice:
@@ -440,8 +728,8 @@ func (s *Server) EstimateRouteFee(ctx context.Context,
 
 // probeDestination estimates fees along a route to a destination based on the
 // contents of the local graph.
-func (s *Server) probeDestination(dest []byte, amtSat int64) (*RouteFeeResponse,
-	error) {
+func (s *Server) probeDestination(ctx context.Context, dest []byte,
+	amtSat int64) (*RouteFeeResponse, error) {
 
 	destNode, err := route.NewVertexFromBytes(dest)
 	if err != nil {
@@ -469,7 +757,7 @@ func (s *Server) probeDestination(dest []byte, amtSat int64) (*RouteFeeResponse,
 		return nil, err
 	}
 
-	route, _, err := s.cfg.Router.FindRoute(routeReq)
+	route, _, err := s.cfg.Router.FindRoute(ctx, routeReq)
 	if err != nil {
 		return nil, err
 	}
@@ -923,12 +1211,44 @@ func (s *Server) SendToRouteV2(ctx context.Context,
 func (s *Server) ResetMissionControl(ctx context.Context,
 	req *ResetMissionControlRequest) (*ResetMissionControlResponse, error) {
 
-	err := s.cfg.RouterBackend.MissionControl.ResetHistory()
+	mc := s.cfg.RouterBackend.MissionControl
+
+	// Without any filter set, we clear the full mission control state.
+	if len(req.Node) == 0 && req.OlderThan == 0 && !req.FailuresOnly {
+		if err := mc.ResetHistory(); err != nil {
+			return nil, err
+		}
+
+		return &ResetMissionControlResponse{}, nil
+	}
+
+	if req.OlderThan < 0 {
+		return nil, fmt.Errorf("invalid older_than timestamp: %v",
+			req.OlderThan)
+	}
+
+	filter := &routing.ResetFilter{
+		FailuresOnly: req.FailuresOnly,
+	}
+	if len(req.Node) > 0 {
+		node, err := route.NewVertexFromBytes(req.Node)
+		if err != nil {
+			return nil, err
+		}
+		filter.Node = fn.Some(node)
+	}
+	if req.OlderThan > 0 {
+		filter.OlderThan = time.Unix(req.OlderThan, 0)
+	}
+
+	reset, err := mc.ResetHistoryFiltered(filter)
 	if err != nil {
 		return nil, err
 	}
 
-	return &ResetMissionControlResponse{}, nil
+	return &ResetMissionControlResponse{
+		NumPairsReset: uint32(reset),
+	}, nil
 }
 
 // GetMissionControlConfig returns our current mission control config.
@@ -1332,7 +1652,8 @@ func (s *Server) trackPayment(subscription routing.ControlTowerSubscriber,
 	noInflightUpdates bool) error {
 
 	err := s.trackPaymentStream(
-		stream.Context(), subscription, noInflightUpdates, stream.Send,
+		stream.Context(), subscription,
+		transitionsFilter(noInflightUpdates), stream.Send,
 	)
 
 	// If the context is canceled, we don't return an error.
@@ -1355,6 +1676,28 @@ func (s *Server) TrackPayments(request *TrackPaymentsRequest,
 
 	log.Debug("TrackPayments called")
 
+	filter, err := unmarshalTrackPaymentsFilter(request)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return s.TrackPaymentsFiltered(stream.Context(), filter, stream.Send)
+}
+
+// TrackPaymentsFiltered streams the updates of all payments that match the
+// given filter to the send function until the context is canceled. This
+// allows callers to only receive the status transitions they are interested
+// in, or to only follow the payments of specific payment groups.
+func (s *Server) TrackPaymentsFiltered(ctx context.Context,
+	filter *TrackPaymentsFilter, send func(*lnrpc.Payment) error) error {
+
+	if filter == nil {
+		filter = &TrackPaymentsFilter{}
+	}
+	if err := filter.Validate(); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
 	router := s.cfg.RouterBackend
 
 	// Subscribe to payments.
@@ -1364,10 +1707,7 @@ func (s *Server) TrackPayments(request *TrackPaymentsRequest,
 	}
 
 	// Stream updates to the client.
-	err = s.trackPaymentStream(
-		stream.Context(), subscription, request.NoInflightUpdates,
-		stream.Send,
-	)
+	err = s.trackPaymentStream(ctx, subscription, filter, send)
 
 	if errors.Is(err, context.Canceled) {
 		log.Debugf("TrackPayments payment stream canceled.")
@@ -1376,10 +1716,11 @@ func (s *Server) TrackPayments(request *TrackPaymentsRequest,
 	return err
 }
 
-// trackPaymentStream streams payment updates to the client.
+// trackPaymentStream streams the payment updates matching the filter to the
+// client.
 func (s *Server) trackPaymentStream(context context.Context,
-	subscription routing.ControlTowerSubscriber, noInflightUpdates bool,
-	send func(*lnrpc.Payment) error) error {
+	subscription routing.ControlTowerSubscriber,
+	filter *TrackPaymentsFilter, send func(*lnrpc.Payment) error) error {
 
 	defer subscription.Close()
 
@@ -1396,14 +1737,9 @@ func (s *Server) trackPaymentStream(context context.Context,
 			log.Tracef("Payment %v updated to state %v",
 				result.Info.PaymentIdentifier, result.Status)
 
-			// Skip in-flight updates unless requested.
-			if noInflightUpdates {
-				if result.Status == channeldb.StatusInitiated {
-					continue
-				}
-				if result.Status == channeldb.StatusInFlight {
-					continue
-				}
+			// Skip the updates the client isn't interested in.
+			if !filter.matches(result) {
+				continue
 			}
 
 			rpcPayment, err := s.cfg.RouterBackend.MarshallPayment(
@@ -1698,6 +2034,43 @@ func (s *Server) XDeleteLocalChanAliases(_ context.Context,
 	}, nil
 }
 
+// XAllocateLocalChanAlias is an experimental API that allocates the next free
+// alias from the SCID alias range and adds it as a local alias of the given
+// channel. The alias is not communicated to the channel peer.
+func (s *Server) XAllocateLocalChanAlias(_ context.Context,
+	in *AllocateAliasRequest) (*AllocateAliasResponse, error) {
+
+	baseScid := lnwire.NewShortChanIDFromInt(in.BaseScid)
+
+	alias, err := s.cfg.AliasMgr.AllocateLocalAlias(baseScid)
+	if err != nil {
+		return nil, fmt.Errorf("error allocating scid alias, "+
+			"base_scid=%v: %w", baseScid, err)
+	}
+
+	return &AllocateAliasResponse{
+		AliasScid: alias.ToUint64(),
+		AliasMaps: lnrpc.MarshalAliasMap(s.cfg.AliasMgr.ListAliases()),
+	}, nil
+}
+
+// XLookupLocalChanAlias is an experimental API that maps any local alias back
+// to the base SCID of its channel.
+func (s *Server) XLookupLocalChanAlias(_ context.Context,
+	in *LookupAliasRequest) (*LookupAliasResponse, error) {
+
+	alias := lnwire.NewShortChanIDFromInt(in.AliasScid)
+
+	baseScid, err := s.cfg.AliasMgr.LookupBase(alias)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LookupAliasResponse{
+		BaseScid: baseScid.ToUint64(),
+	}, nil
+}
+
 func extractOutPoint(req *UpdateChanStatusRequest) (*wire.OutPoint, error) {
 	chanPoint := req.GetChanPoint()
 	txid, err := lnrpc.GetChanPointFundingTxid(chanPoint)
@@ -1738,3 +2111,110 @@ func (s *Server) UpdateChanStatus(_ context.Context,
 	}
 	return &UpdateChanStatusResponse{}, nil
 }
+
+// ScheduleMaintenance schedules a maintenance window during which channels are
+// disabled on the network.
+func (s *Server) ScheduleMaintenance(_ context.Context,
+	req *ScheduleMaintenanceRequest) (*ScheduleMaintenanceResponse,
+	error) {
+
+	start := time.Now()
+	if req.StartTime != 0 {
+		start = time.Unix(req.StartTime, 0)
+	}
+	duration := time.Duration(req.DurationSeconds) * time.Second
+
+	chanPoints := make([]wire.OutPoint, 0, len(req.ChanPoints))
+	for _, chanPoint := range req.ChanPoints {
+		txid, err := lnrpc.GetChanPointFundingTxid(chanPoint)
+		if err != nil {
+			return nil, err
+		}
+
+		chanPoints = append(chanPoints, wire.OutPoint{
+			Hash:  *txid,
+			Index: chanPoint.OutputIndex,
+		})
+	}
+
+	id, err := s.cfg.RouterBackend.ScheduleMaintenance(
+		start, duration, chanPoints...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ScheduleMaintenanceResponse{
+		WindowId: id,
+	}, nil
+}
+
+// CancelMaintenance cancels a scheduled or active maintenance window.
+func (s *Server) CancelMaintenance(_ context.Context,
+	req *CancelMaintenanceRequest) (*CancelMaintenanceResponse, error) {
+
+	err := s.cfg.RouterBackend.CancelMaintenance(req.WindowId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CancelMaintenanceResponse{}, nil
+}
+
+// ListMaintenanceWindows returns the scheduled and active maintenance windows.
+func (s *Server) ListMaintenanceWindows(_ context.Context,
+	_ *ListMaintenanceWindowsRequest) (*ListMaintenanceWindowsResponse,
+	error) {
+
+	windows := s.cfg.RouterBackend.MaintenanceWindows()
+
+	resp := &ListMaintenanceWindowsResponse{
+		Windows: make([]*MaintenanceWindow, 0, len(windows)),
+	}
+	for _, window := range windows {
+		resp.Windows = append(
+			resp.Windows, marshalMaintenanceWindow(window),
+		)
+	}
+
+	return resp, nil
+}
+
+// ForwardLatencies returns the forward latency histograms of all channels that
+// htlcs were sent, received or forwarded over since startup.
+func (s *Server) ForwardLatencies(_ context.Context,
+	_ *ForwardLatenciesRequest) (*ForwardLatenciesResponse, error) {
+
+	latencies := s.cfg.RouterBackend.ForwardLatencies()
+
+	return marshalForwardLatencies(latencies), nil
+}
+
+// marshalMaintenanceWindow converts a maintenance window into its RPC
+// representation.
+func marshalMaintenanceWindow(
+	window netann.MaintenanceWindow) *MaintenanceWindow {
+
+	rpcWindow := &MaintenanceWindow{
+		WindowId:        window.ID,
+		StartTime:       window.Start.Unix(),
+		DurationSeconds: uint64(window.Duration / time.Second),
+		ChanPoints: make(
+			[]*lnrpc.ChannelPoint, 0, len(window.ChanPoints),
+		),
+		Active: window.Active,
+	}
+	for _, chanPoint := range window.ChanPoints {
+		txid := &lnrpc.ChannelPoint_FundingTxidBytes{
+			FundingTxidBytes: chanPoint.Hash[:],
+		}
+		rpcWindow.ChanPoints = append(
+			rpcWindow.ChanPoints, &lnrpc.ChannelPoint{
+				FundingTxid: txid,
+				OutputIndex: chanPoint.Index,
+			},
+		)
+	}
+
+	return rpcWindow
+}
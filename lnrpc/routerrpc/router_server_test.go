@@ -6,11 +6,17 @@ import (
 	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/htlcswitch"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/netann"
 	"github.com/lightningnetwork/lnd/queue"
 	"github.com/lightningnetwork/lnd/routing"
+	"github.com/lightningnetwork/lnd/routing/route"
 	"github.com/lightningnetwork/lnd/zpay32"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
@@ -218,6 +224,206 @@ func TestTrackPaymentsNoInflightUpdates(t *testing.T) {
 	require.Equal(t, lnrpc.Payment_SUCCEEDED, payment.Status)
 }
 
+// TestTrackPaymentsFiltered tests that only the updates of payments that match
+// the filter are propagated to the client.
+func TestTrackPaymentsFiltered(t *testing.T) {
+	towerMock := makeControlTowerMock()
+
+	streamCtx, cancelStream := context.WithCancel(context.Background())
+	stream := makeStreamMock(streamCtx)
+	defer cancelStream()
+
+	server := &Server{
+		cfg: &Config{
+			RouterBackend: &RouterBackend{
+				Tower:              towerMock,
+				UseStatusInitiated: true,
+			},
+		},
+	}
+
+	// Only follow the in-flight updates of the payments of two groups.
+	filter := &TrackPaymentsFilter{
+		Transitions: InFlightTransitions,
+		Groups:      fn.NewSet("payroll", "refunds"),
+	}
+
+	// Listen to payment updates in a goroutine.
+	go func() {
+		err := server.TrackPaymentsFiltered(
+			streamCtx, filter, stream.Send,
+		)
+		require.Equal(t, context.Canceled, err)
+	}()
+
+	makeUpdate := func(group string,
+		status channeldb.PaymentStatus) *channeldb.MPPayment {
+
+		return &channeldb.MPPayment{
+			Info: &channeldb.PaymentCreationInfo{
+				PaymentGroup: group,
+			},
+			Status: status,
+		}
+	}
+
+	// Enqueue payment updates on the mock, of which only the in-flight
+	// updates of the followed groups must be sent.
+	towerMock.queue.ChanIn() <- makeUpdate("", channeldb.StatusInFlight)
+	towerMock.queue.ChanIn() <- makeUpdate(
+		"other", channeldb.StatusInFlight,
+	)
+	towerMock.queue.ChanIn() <- makeUpdate(
+		"payroll", channeldb.StatusSucceeded,
+	)
+	towerMock.queue.ChanIn() <- makeUpdate(
+		"payroll", channeldb.StatusInitiated,
+	)
+	towerMock.queue.ChanIn() <- makeUpdate(
+		"refunds", channeldb.StatusInFlight,
+	)
+
+	// Wait until there's 2 updates or the deadline is exceeded.
+	deadline := time.Now().Add(1 * time.Second)
+	for {
+		if len(stream.sentFromServer) == 2 {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			require.FailNow(t, "deadline exceeded.")
+		}
+	}
+
+	payment := <-stream.sentFromServer
+	require.Equal(t, lnrpc.Payment_INITIATED, payment.Status)
+	payment = <-stream.sentFromServer
+	require.Equal(t, lnrpc.Payment_IN_FLIGHT, payment.Status)
+}
+
+// TestTrackPaymentsFilterValidate tests that invalid filters are rejected.
+func TestTrackPaymentsFilterValidate(t *testing.T) {
+	t.Parallel()
+
+	filter := &TrackPaymentsFilter{Transitions: InFlightTransitions + 1}
+	require.Error(t, filter.Validate())
+
+	filter = &TrackPaymentsFilter{Groups: fn.NewSet("")}
+	require.Error(t, filter.Validate())
+
+	filter = &TrackPaymentsFilter{
+		Groups: fn.NewSet(string(make([]byte, maxPaymentGroupLen+1))),
+	}
+	require.Error(t, filter.Validate())
+
+	filter = &TrackPaymentsFilter{
+		Transitions: FinalTransitions,
+		Groups:      fn.NewSet("payroll"),
+	}
+	require.NoError(t, filter.Validate())
+}
+
+// TestUnmarshalTrackPaymentsFilter tests that the filter of a track payments
+// request is parsed correctly.
+func TestUnmarshalTrackPaymentsFilter(t *testing.T) {
+	t.Parallel()
+
+	filter, err := unmarshalTrackPaymentsFilter(&TrackPaymentsRequest{})
+	require.NoError(t, err)
+	require.Equal(t, &TrackPaymentsFilter{}, filter)
+
+	filter, err = unmarshalTrackPaymentsFilter(&TrackPaymentsRequest{
+		NoInflightUpdates: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, FinalTransitions, filter.Transitions)
+
+	filter, err = unmarshalTrackPaymentsFilter(&TrackPaymentsRequest{
+		Transitions:   PaymentTransitionFilter_IN_FLIGHT_TRANSITIONS,
+		PaymentGroups: []string{"payroll", "refunds"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, &TrackPaymentsFilter{
+		Transitions: InFlightTransitions,
+		Groups:      fn.NewSet("payroll", "refunds"),
+	}, filter)
+
+	// The legacy flag can't be combined with explicit transitions.
+	inFlight := PaymentTransitionFilter_IN_FLIGHT_TRANSITIONS
+	_, err = unmarshalTrackPaymentsFilter(&TrackPaymentsRequest{
+		NoInflightUpdates: true,
+		Transitions:       inFlight,
+	})
+	require.Error(t, err)
+
+	_, err = unmarshalTrackPaymentsFilter(&TrackPaymentsRequest{
+		Transitions: 3,
+	})
+	require.Error(t, err)
+
+	_, err = unmarshalTrackPaymentsFilter(&TrackPaymentsRequest{
+		PaymentGroups: []string{""},
+	})
+	require.Error(t, err)
+}
+
+// TestResetMissionControlFilter tests that a reset request without any filter
+// clears all mission control state, while a filtered request is translated into
+// the matching mission control filter.
+func TestResetMissionControlFilter(t *testing.T) {
+	t.Parallel()
+
+	mc := &mockMissionControl{numPairsReset: 3}
+	server := &Server{
+		cfg: &Config{
+			RouterBackend: &RouterBackend{
+				MissionControl: mc,
+			},
+		},
+	}
+
+	resp, err := server.ResetMissionControl(
+		context.Background(), &ResetMissionControlRequest{},
+	)
+	require.NoError(t, err)
+	require.True(t, mc.resetAll)
+	require.Nil(t, mc.resetFilter)
+	require.Zero(t, resp.NumPairsReset)
+
+	// A negative timestamp is rejected.
+	_, err = server.ResetMissionControl(
+		context.Background(), &ResetMissionControlRequest{
+			OlderThan: -1,
+		},
+	)
+	require.Error(t, err)
+
+	// So is a malformed node key.
+	_, err = server.ResetMissionControl(
+		context.Background(), &ResetMissionControlRequest{
+			Node: []byte{1, 2, 3},
+		},
+	)
+	require.Error(t, err)
+
+	var node route.Vertex
+	node[0] = 2
+	resp, err = server.ResetMissionControl(
+		context.Background(), &ResetMissionControlRequest{
+			Node:         node[:],
+			OlderThan:    1000,
+			FailuresOnly: true,
+		},
+	)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, resp.NumPairsReset)
+	require.Equal(t, &routing.ResetFilter{
+		Node:         fn.Some(node),
+		OlderThan:    time.Unix(1000, 0),
+		FailuresOnly: true,
+	}, mc.resetFilter)
+}
+
 // TestIsLsp tests the isLSP heuristic. Combinations of different route hints
 // with different fees and cltv deltas are tested to ensure that the heuristic
 // correctly identifies whether a route leads to an LSP or not.
@@ -400,3 +606,169 @@ func TestIsLsp(t *testing.T) {
 		})
 	}
 }
+
+// TestMaintenanceWindows tests that maintenance window requests are converted
+// and forwarded to the router backend.
+func TestMaintenanceWindows(t *testing.T) {
+	t.Parallel()
+
+	chanPoint := wire.OutPoint{Hash: chainhash.Hash{1}, Index: 2}
+	start := time.Unix(1000, 0)
+
+	var (
+		scheduledStart    time.Time
+		scheduledDuration time.Duration
+		scheduledChans    []wire.OutPoint
+	)
+	s := &Server{cfg: &Config{RouterBackend: &RouterBackend{
+		ScheduleMaintenance: func(start time.Time,
+			duration time.Duration,
+			chanPoints ...wire.OutPoint) (uint64, error) {
+
+			scheduledStart = start
+			scheduledDuration = duration
+			scheduledChans = chanPoints
+
+			return 7, nil
+		},
+		MaintenanceWindows: func() []netann.MaintenanceWindow {
+			return []netann.MaintenanceWindow{{
+				ID:         7,
+				Start:      scheduledStart,
+				Duration:   scheduledDuration,
+				ChanPoints: scheduledChans,
+				Active:     true,
+			}}
+		},
+	}}}
+
+	rpcChanPoint := &lnrpc.ChannelPoint{
+		FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{
+			FundingTxidBytes: chanPoint.Hash[:],
+		},
+		OutputIndex: chanPoint.Index,
+	}
+
+	resp, err := s.ScheduleMaintenance(
+		context.Background(), &ScheduleMaintenanceRequest{
+			StartTime:       start.Unix(),
+			DurationSeconds: 3600,
+			ChanPoints:      []*lnrpc.ChannelPoint{rpcChanPoint},
+		},
+	)
+	require.NoError(t, err)
+	require.EqualValues(t, 7, resp.WindowId)
+	require.Equal(t, start, scheduledStart)
+	require.Equal(t, time.Hour, scheduledDuration)
+	require.Equal(t, []wire.OutPoint{chanPoint}, scheduledChans)
+
+	list, err := s.ListMaintenanceWindows(
+		context.Background(), &ListMaintenanceWindowsRequest{},
+	)
+	require.NoError(t, err)
+	require.Len(t, list.Windows, 1)
+
+	window := list.Windows[0]
+	require.EqualValues(t, 7, window.WindowId)
+	require.Equal(t, start.Unix(), window.StartTime)
+	require.EqualValues(t, 3600, window.DurationSeconds)
+	require.True(t, window.Active)
+	require.Len(t, window.ChanPoints, 1)
+	rpcChanPoint = window.ChanPoints[0]
+	require.Equal(t, chanPoint.Hash[:], rpcChanPoint.GetFundingTxidBytes())
+	require.Equal(t, chanPoint.Index, rpcChanPoint.OutputIndex)
+}
+
+// TestMarshalForwardLatencies tests that the forward latency histograms are
+// marshalled in channel order with their bucket bounds in microseconds.
+func TestMarshalForwardLatencies(t *testing.T) {
+	t.Parallel()
+
+	var slow, fast htlcswitch.ChannelLatency
+	slow.Incoming.Observe(time.Minute + time.Second)
+	fast.Incoming.Observe(5 * time.Millisecond)
+	fast.Outgoing.Observe(20 * time.Millisecond)
+	fast.Outgoing.Observe(40 * time.Millisecond)
+
+	resp := marshalForwardLatencies(htlcswitch.ChannelLatencies{
+		lnwire.NewShortChanIDFromInt(2): slow,
+		lnwire.NewShortChanIDFromInt(1): fast,
+	})
+
+	require.Len(t, resp.BucketBoundsUs, len(htlcswitch.LatencyBuckets))
+	require.EqualValues(t, 10_000, resp.BucketBoundsUs[0])
+	require.EqualValues(t, 60_000_000, resp.BucketBoundsUs[10])
+
+	require.Len(t, resp.Channels, 2)
+	require.EqualValues(t, 1, resp.Channels[0].ChanId)
+	require.EqualValues(t, 2, resp.Channels[1].ChanId)
+
+	// The fast channel has one incoming observation in the first bucket
+	// and two outgoing observations in the second one.
+	incoming := resp.Channels[0].Incoming
+	require.EqualValues(t, 1, incoming.Count)
+	require.EqualValues(t, 1, incoming.Counts[0])
+	require.EqualValues(t, 5_000, incoming.SumUs)
+
+	outgoing := resp.Channels[0].Outgoing
+	require.EqualValues(t, 2, outgoing.Count)
+	require.EqualValues(t, 2, outgoing.Counts[1])
+	require.EqualValues(t, 60_000, outgoing.SumUs)
+
+	// The slow channel's observation exceeds the largest bound and ends
+	// up in the overflow bucket.
+	overflow := resp.Channels[1].Incoming.Counts
+	require.Len(t, overflow, len(htlcswitch.LatencyBuckets)+1)
+	require.EqualValues(t, 1, overflow[len(overflow)-1])
+	require.Zero(t, resp.Channels[1].Outgoing.Count)
+}
+
+// TestRPCTiming tests the conversion of htlc forward timings.
+func TestRPCTiming(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, rpcTiming(nil))
+
+	forwarded := time.Unix(100, 5)
+	timing := rpcTiming(&htlcswitch.ForwardTiming{
+		Forwarded: forwarded,
+	})
+
+	// Locally initiated sends don't have a received time, which must be
+	// reported as zero rather than a negative unix time.
+	require.Zero(t, timing.ReceivedTimeNs)
+	require.EqualValues(t, forwarded.UnixNano(), timing.ForwardedTimeNs)
+
+	info := rpcInfo(htlcswitch.HtlcInfo{ReceivedTime: forwarded})
+	require.EqualValues(t, forwarded.UnixNano(), info.ReceivedTimeNs)
+}
+
+// TestRPCOutgoingFailureExpiry tests that htlcs rejected for expiry reasons
+// are reported with dedicated rpc failure details.
+func TestRPCOutgoingFailureExpiry(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		failure  htlcswitch.OutgoingFailure
+		expected FailureDetail
+	}{
+		{
+			failure:  htlcswitch.OutgoingFailureExpiryTooSoon,
+			expected: FailureDetail_EXPIRY_TOO_SOON,
+		},
+		{
+			failure:  htlcswitch.OutgoingFailureExpiryTooFar,
+			expected: FailureDetail_EXPIRY_TOO_FAR,
+		},
+		{
+			failure:  htlcswitch.OutgoingFailureIncorrectCltvExpiry,
+			expected: FailureDetail_INCORRECT_CLTV_EXPIRY,
+		},
+	}
+
+	for _, test := range tests {
+		detail, err := rpcOutgoingFailure(test.failure)
+		require.NoError(t, err)
+		require.Equal(t, test.expected, detail)
+	}
+}
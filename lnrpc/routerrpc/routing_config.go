@@ -44,6 +44,19 @@ type RoutingConfig struct {
 
 	// FeeEstimationTimeout is the maximum time to wait for routing fees to be estimated.
 	FeeEstimationTimeout time.Duration `long:"fee-estimation-timeout" description:"the maximum time to wait for routing fees to be estimated by payment probes"`
+
+	// ShardReservationTimeout is the maximum duration for which the
+	// outbound liquidity used by a newly launched shard is deducted from
+	// the bandwidth of the channel during path finding.
+	ShardReservationTimeout time.Duration `long:"shard-reservation-timeout" description:"the maximum duration for which the outbound liquidity used by a newly launched payment shard is reserved, so concurrent shards aren't routed over the same channel based on its stale balance; 0 disables reservations"`
+
+	// ShardPacingInterval is the minimum interval between launching
+	// consecutive shards of a payment.
+	ShardPacingInterval time.Duration `long:"shard-pacing-interval" description:"the minimum interval between launching consecutive shards of a payment; 0 disables pacing"`
+
+	// LearnFromForwards enables feeding the htlcs that are forwarded
+	// through our node into mission control.
+	LearnFromForwards bool `long:"learn-from-forwards" description:"if set, the htlcs that are forwarded through our node are used to learn about the liquidity of the channels to our peers"`
 }
 
 // AprioriConfig defines parameters for the apriori probability.
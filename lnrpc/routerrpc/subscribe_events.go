@@ -2,6 +2,7 @@ package routerrpc
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/lightningnetwork/lnd/htlcswitch"
@@ -32,7 +33,9 @@ func rpcHtlcEvent(htlcEvent interface{}) (*HtlcEvent, error) {
 
 	case *htlcswitch.ForwardingFailEvent:
 		event = &HtlcEvent_ForwardFailEvent{
-			ForwardFailEvent: &ForwardFailEvent{},
+			ForwardFailEvent: &ForwardFailEvent{
+				Timing: rpcTiming(e.ForwardTiming),
+			},
 		}
 
 		key = e.HtlcKey
@@ -64,6 +67,7 @@ func rpcHtlcEvent(htlcEvent interface{}) (*HtlcEvent, error) {
 		event = &HtlcEvent_SettleEvent{
 			SettleEvent: &SettleEvent{
 				Preimage: e.Preimage[:],
+				Timing:   rpcTiming(e.ForwardTiming),
 			},
 		}
 
@@ -126,6 +130,73 @@ func rpcInfo(info htlcswitch.HtlcInfo) *HtlcInfo {
 		OutgoingTimelock: info.OutgoingTimeLock,
 		IncomingAmtMsat:  uint64(info.IncomingAmt),
 		OutgoingAmtMsat:  uint64(info.OutgoingAmt),
+		ReceivedTimeNs:   unixNano(info.ReceivedTime),
+	}
+}
+
+// rpcTiming returns the rpc representation of the timings of a forward, or nil
+// if the forward wasn't tracked.
+func rpcTiming(timing *htlcswitch.ForwardTiming) *HtlcTiming {
+	if timing == nil {
+		return nil
+	}
+
+	return &HtlcTiming{
+		ReceivedTimeNs:  unixNano(timing.Received),
+		ForwardedTimeNs: unixNano(timing.Forwarded),
+	}
+}
+
+// unixNano returns the given time in unix nanoseconds, or zero if the time
+// isn't set.
+func unixNano(t time.Time) uint64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return uint64(t.UnixNano())
+}
+
+// marshalForwardLatencies converts the forward latency histograms of all
+// channels into their rpc representation, ordered by channel id.
+func marshalForwardLatencies(
+	latencies htlcswitch.ChannelLatencies) *ForwardLatenciesResponse {
+
+	resp := &ForwardLatenciesResponse{
+		BucketBoundsUs: make(
+			[]uint64, 0, len(htlcswitch.LatencyBuckets),
+		),
+		Channels: make([]*ChannelLatency, 0, len(latencies)),
+	}
+	for _, bound := range htlcswitch.LatencyBuckets {
+		resp.BucketBoundsUs = append(
+			resp.BucketBoundsUs, uint64(bound.Microseconds()),
+		)
+	}
+
+	for chanID, latency := range latencies {
+		resp.Channels = append(resp.Channels, &ChannelLatency{
+			ChanId:   chanID.ToUint64(),
+			Incoming: marshalLatencyHistogram(latency.Incoming),
+			Outgoing: marshalLatencyHistogram(latency.Outgoing),
+		})
+	}
+	sort.Slice(resp.Channels, func(i, j int) bool {
+		return resp.Channels[i].ChanId < resp.Channels[j].ChanId
+	})
+
+	return resp
+}
+
+// marshalLatencyHistogram converts a latency histogram into its rpc
+// representation.
+func marshalLatencyHistogram(
+	histogram htlcswitch.LatencyHistogram) *LatencyHistogram {
+
+	return &LatencyHistogram{
+		Counts: append([]uint64(nil), histogram.Counts[:]...),
+		Count:  histogram.Count,
+		SumUs:  uint64(histogram.Sum.Microseconds()),
 	}
 }
 
@@ -201,6 +272,9 @@ func rpcFailureResolution(invoiceFailure invoices.FailResolutionResult) (
 	case invoices.ResultHtlcSetOverpayment:
 		return FailureDetail_SET_OVERPAID, nil
 
+	case invoices.ResultAmountTooHigh:
+		return FailureDetail_SET_OVERPAID, nil
+
 	case invoices.ResultInvoiceNotFound:
 		return FailureDetail_UNKNOWN_INVOICE, nil
 
@@ -254,6 +328,15 @@ func rpcOutgoingFailure(failureDetail htlcswitch.OutgoingFailure) (
 	case htlcswitch.OutgoingFailureForwardsDisabled:
 		return FailureDetail_FORWARDS_DISABLED, nil
 
+	case htlcswitch.OutgoingFailureExpiryTooSoon:
+		return FailureDetail_EXPIRY_TOO_SOON, nil
+
+	case htlcswitch.OutgoingFailureExpiryTooFar:
+		return FailureDetail_EXPIRY_TOO_FAR, nil
+
+	case htlcswitch.OutgoingFailureIncorrectCltvExpiry:
+		return FailureDetail_INCORRECT_CLTV_EXPIRY, nil
+
 	default:
 		return 0, fmt.Errorf("unknown outgoing failure "+
 			"detail: %v", failureDetail.FailureString())
@@ -118,6 +118,17 @@ type SignerClient interface {
 	// considered to be HIGHLY EXPERIMENTAL and subject to change in upcoming
 	// releases. Backward compatibility is not guaranteed!
 	MuSig2Cleanup(ctx context.Context, in *MuSig2CleanupRequest, opts ...grpc.CallOption) (*MuSig2CleanupResponse, error)
+	// MuSig2DeriveNonce (experimental!) deterministically derives the public
+	// nonce of a local key for the given nonce derivation. The secret nonce never
+	// leaves the signer, a session using it is created by setting the same
+	// nonce_derivation in MuSig2CreateSession. This allows a watch-only node to
+	// hand out nonces ahead of time without knowing the secret nonces. Only
+	// version 1.0.0rc2 of the MuSig2 BIP draft is supported.
+	//
+	// NOTE: The MuSig2 BIP is not final yet and therefore this API must be
+	// considered to be HIGHLY EXPERIMENTAL and subject to change in upcoming
+	// releases. Backward compatibility is not guaranteed!
+	MuSig2DeriveNonce(ctx context.Context, in *MuSig2DeriveNonceRequest, opts ...grpc.CallOption) (*MuSig2DeriveNonceResponse, error)
 }
 
 type signerClient struct {
@@ -227,6 +238,15 @@ func (c *signerClient) MuSig2Cleanup(ctx context.Context, in *MuSig2CleanupReque
 	return out, nil
 }
 
+func (c *signerClient) MuSig2DeriveNonce(ctx context.Context, in *MuSig2DeriveNonceRequest, opts ...grpc.CallOption) (*MuSig2DeriveNonceResponse, error) {
+	out := new(MuSig2DeriveNonceResponse)
+	err := c.cc.Invoke(ctx, "/signrpc.Signer/MuSig2DeriveNonce", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SignerServer is the server API for Signer service.
 // All implementations must embed UnimplementedSignerServer
 // for forward compatibility
@@ -331,6 +351,17 @@ type SignerServer interface {
 	// considered to be HIGHLY EXPERIMENTAL and subject to change in upcoming
 	// releases. Backward compatibility is not guaranteed!
 	MuSig2Cleanup(context.Context, *MuSig2CleanupRequest) (*MuSig2CleanupResponse, error)
+	// MuSig2DeriveNonce (experimental!) deterministically derives the public
+	// nonce of a local key for the given nonce derivation. The secret nonce never
+	// leaves the signer, a session using it is created by setting the same
+	// nonce_derivation in MuSig2CreateSession. This allows a watch-only node to
+	// hand out nonces ahead of time without knowing the secret nonces. Only
+	// version 1.0.0rc2 of the MuSig2 BIP draft is supported.
+	//
+	// NOTE: The MuSig2 BIP is not final yet and therefore this API must be
+	// considered to be HIGHLY EXPERIMENTAL and subject to change in upcoming
+	// releases. Backward compatibility is not guaranteed!
+	MuSig2DeriveNonce(context.Context, *MuSig2DeriveNonceRequest) (*MuSig2DeriveNonceResponse, error)
 	mustEmbedUnimplementedSignerServer()
 }
 
@@ -371,6 +402,9 @@ func (UnimplementedSignerServer) MuSig2CombineSig(context.Context, *MuSig2Combin
 func (UnimplementedSignerServer) MuSig2Cleanup(context.Context, *MuSig2CleanupRequest) (*MuSig2CleanupResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method MuSig2Cleanup not implemented")
 }
+func (UnimplementedSignerServer) MuSig2DeriveNonce(context.Context, *MuSig2DeriveNonceRequest) (*MuSig2DeriveNonceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MuSig2DeriveNonce not implemented")
+}
 func (UnimplementedSignerServer) mustEmbedUnimplementedSignerServer() {}
 
 // UnsafeSignerServer may be embedded to opt out of forward compatibility for this service.
@@ -582,6 +616,24 @@ func _Signer_MuSig2Cleanup_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Signer_MuSig2DeriveNonce_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MuSig2DeriveNonceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignerServer).MuSig2DeriveNonce(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/signrpc.Signer/MuSig2DeriveNonce",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignerServer).MuSig2DeriveNonce(ctx, req.(*MuSig2DeriveNonceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Signer_ServiceDesc is the grpc.ServiceDesc for Signer service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -633,6 +685,10 @@ var Signer_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "MuSig2Cleanup",
 			Handler:    _Signer_MuSig2Cleanup_Handler,
 		},
+		{
+			MethodName: "MuSig2DeriveNonce",
+			Handler:    _Signer_MuSig2DeriveNonce_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "signrpc/signer.proto",
@@ -98,6 +98,10 @@ var (
 			Entity: "signer",
 			Action: "generate",
 		}},
+		"/signrpc.Signer/MuSig2DeriveNonce": {{
+			Entity: "signer",
+			Action: "generate",
+		}},
 	}
 
 	// DefaultSignerMacFilename is the default name of the signer macaroon
@@ -1002,6 +1006,22 @@ func (s *Server) MuSig2CreateSession(_ context.Context,
 		}
 	}
 
+	// Instead of pre generated local nonces, the nonces can also be
+	// derived by us, so they never have to leave the signer.
+	var derivation *input.MuSig2NonceDerivation
+	if in.NonceDerivation != nil {
+		if localNonces != nil {
+			return nil, fmt.Errorf("pregenerated_local_nonce and " +
+				"nonce_derivation can't both be set")
+		}
+
+		d, err := UnmarshalMuSig2NonceDerivation(in.NonceDerivation)
+		if err != nil {
+			return nil, err
+		}
+		derivation = &d
+	}
+
 	// Parse all other nonces we might already know.
 	otherSignerNonces, err := parseMuSig2PublicNonces(
 		in.OtherSignerPublicNonces, true,
@@ -1018,10 +1038,18 @@ func (s *Server) MuSig2CreateSession(_ context.Context,
 	}
 
 	// Register the session with the internal wallet/signer now.
-	session, err := s.cfg.Signer.MuSig2CreateSession(
-		version, keyLoc, allSignerPubKeys, tweaks, otherSignerNonces,
-		localNonces,
-	)
+	var session *input.MuSig2SessionInfo
+	if derivation != nil {
+		session, err = s.cfg.Signer.MuSig2CreateDerivedSession(
+			version, keyLoc, allSignerPubKeys, tweaks,
+			otherSignerNonces, *derivation,
+		)
+	} else {
+		session, err = s.cfg.Signer.MuSig2CreateSession(
+			version, keyLoc, allSignerPubKeys, tweaks,
+			otherSignerNonces, localNonces,
+		)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("error registering session: %w", err)
 	}
@@ -1180,6 +1208,37 @@ func (s *Server) MuSig2Cleanup(_ context.Context,
 	return &MuSig2CleanupResponse{}, nil
 }
 
+// MuSig2DeriveNonce deterministically derives the public nonce of a local key
+// for the given nonce derivation.
+func (s *Server) MuSig2DeriveNonce(_ context.Context,
+	in *MuSig2DeriveNonceRequest) (*MuSig2DeriveNonceResponse, error) {
+
+	if in.KeyLoc == nil {
+		return nil, fmt.Errorf("missing key_loc")
+	}
+	keyLoc := keychain.KeyLocator{
+		Family: keychain.KeyFamily(in.KeyLoc.KeyFamily),
+		Index:  uint32(in.KeyLoc.KeyIndex),
+	}
+
+	if in.NonceDerivation == nil {
+		return nil, fmt.Errorf("missing nonce_derivation")
+	}
+	derivation, err := UnmarshalMuSig2NonceDerivation(in.NonceDerivation)
+	if err != nil {
+		return nil, err
+	}
+
+	pubNonce, err := s.cfg.Signer.MuSig2DeriveNonce(keyLoc, derivation)
+	if err != nil {
+		return nil, fmt.Errorf("error deriving nonce: %w", err)
+	}
+
+	return &MuSig2DeriveNonceResponse{
+		PublicNonce: pubNonce[:],
+	}, nil
+}
+
 // parseRawKeyBytes checks that the provided raw public key is valid and returns
 // the public key. A nil public key is returned if the length of the rawKeyBytes
 // is zero.
@@ -40,3 +40,33 @@ func MarshalMuSig2Version(version input.MuSig2Version) (MuSig2Version, error) {
 		return 0, fmt.Errorf("unknown MuSig2 version <%d>", version)
 	}
 }
+
+// UnmarshalMuSig2NonceDerivation parses the RPC MuSig2 nonce derivation into
+// its native counterpart.
+func UnmarshalMuSig2NonceDerivation(
+	rpcDerivation *MuSig2NonceDerivation) (input.MuSig2NonceDerivation,
+	error) {
+
+	var derivation input.MuSig2NonceDerivation
+	if len(rpcDerivation.Context) != len(derivation.Context) {
+		return derivation, fmt.Errorf("nonce derivation context must "+
+			"be %d bytes, got %d", len(derivation.Context),
+			len(rpcDerivation.Context))
+	}
+
+	copy(derivation.Context[:], rpcDerivation.Context)
+	derivation.Index = rpcDerivation.Index
+
+	return derivation, nil
+}
+
+// MarshalMuSig2NonceDerivation turns the native MuSig2 nonce derivation into
+// its RPC counterpart.
+func MarshalMuSig2NonceDerivation(
+	derivation input.MuSig2NonceDerivation) *MuSig2NonceDerivation {
+
+	return &MuSig2NonceDerivation{
+		Context: derivation.Context[:],
+		Index:   derivation.Index,
+	}
+}
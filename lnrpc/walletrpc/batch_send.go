@@ -0,0 +1,424 @@
+//go:build walletrpc
+// +build walletrpc
+
+package walletrpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	base "github.com/btcsuite/btcwallet/wallet"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/labels"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+)
+
+const (
+	// defaultBatchSendConfTarget is the confirmation target used to
+	// estimate the fee rate of a batch send if no fee rate is given.
+	defaultBatchSendConfTarget = 6
+)
+
+// batchSendOutput is a single output of a batch send.
+type batchSendOutput struct {
+	// Addr is the address to pay to.
+	Addr string
+
+	// Amount is the value of the output.
+	Amount btcutil.Amount
+
+	// Label is an optional label that describes the output, e.g. which
+	// withdrawal it belongs to. It is returned alongside the output's
+	// outpoint once the transaction is published.
+	Label string
+}
+
+// batchWalletSendParams describes a transaction paying to multiple outputs
+// at once and the constraints for selecting the coins to fund it with.
+type batchWalletSendParams struct {
+	// Outputs are the outputs the transaction should pay to.
+	Outputs []batchSendOutput
+
+	// Label is the label of the transaction within the wallet. If empty,
+	// the generic label for transactions sent through the API is used.
+	Label string
+
+	// FeeRate is the fee rate the transaction should pay. If zero, the fee
+	// rate is estimated for TargetConf.
+	FeeRate chainfee.SatPerKWeight
+
+	// TargetConf is the number of blocks the transaction should confirm
+	// within, used to estimate the fee rate if none is given.
+	TargetConf uint32
+
+	// MinConfs is the minimum number of confirmations of the coins that
+	// may be spent.
+	MinConfs int32
+
+	// IncludeOutpoints restricts the coin selection to the given coins, if
+	// non-empty.
+	IncludeOutpoints []wire.OutPoint
+
+	// ExcludeOutpoints are coins that must not be spent.
+	ExcludeOutpoints []wire.OutPoint
+
+	// Strategy is the coin selection strategy. If nil, the wallet's
+	// default strategy is used.
+	Strategy base.CoinSelectionStrategy
+}
+
+// batchSendOutputResult is an output of a published batch send.
+type batchSendOutputResult struct {
+	batchSendOutput
+
+	// OutPoint is the outpoint of the output in the published
+	// transaction.
+	OutPoint wire.OutPoint
+}
+
+// batchWalletSendResult is the result of a published batch send.
+type batchWalletSendResult struct {
+	// Tx is the published transaction.
+	Tx *wire.MsgTx
+
+	// Outputs are the requested outputs, in the order they were
+	// requested, along with their outpoints.
+	Outputs []batchSendOutputResult
+
+	// ChangeOutPoint is the outpoint of the change output, if any.
+	ChangeOutPoint fn.Option[wire.OutPoint]
+}
+
+// BatchWalletSend builds, signs and publishes a single transaction paying to
+// all the requested outputs and returns the outpoint of each of them.
+//
+// NOTE: This is part of the WalletKitServer interface.
+func (w *WalletKit) BatchWalletSend(ctx context.Context,
+	req *BatchWalletSendRequest) (*BatchWalletSendResponse, error) {
+
+	strategy, err := lnrpc.UnmarshallCoinSelectionStrategy(
+		req.CoinSelectionStrategy, w.cfg.CoinSelectionStrategy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	params := &batchWalletSendParams{
+		Outputs:    make([]batchSendOutput, 0, len(req.Outputs)),
+		Label:      req.Label,
+		TargetConf: req.TargetConf,
+		MinConfs:   req.MinConfs,
+		Strategy:   strategy,
+	}
+
+	if req.SatPerVbyte != 0 {
+		params.FeeRate = chainfee.SatPerKVByte(
+			req.SatPerVbyte * 1000,
+		).FeePerKWeight()
+	}
+
+	for _, output := range req.Outputs {
+		params.Outputs = append(params.Outputs, batchSendOutput{
+			Addr:   output.Addr,
+			Amount: btcutil.Amount(output.AmountSat),
+			Label:  output.Label,
+		})
+	}
+
+	for _, op := range req.IncludeOutpoints {
+		outpoint, err := UnmarshallOutPoint(op)
+		if err != nil {
+			return nil, err
+		}
+		params.IncludeOutpoints = append(
+			params.IncludeOutpoints, *outpoint,
+		)
+	}
+	for _, op := range req.ExcludeOutpoints {
+		outpoint, err := UnmarshallOutPoint(op)
+		if err != nil {
+			return nil, err
+		}
+		params.ExcludeOutpoints = append(
+			params.ExcludeOutpoints, *outpoint,
+		)
+	}
+
+	result, err := w.batchWalletSend(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var rawTx bytes.Buffer
+	if err := result.Tx.Serialize(&rawTx); err != nil {
+		return nil, err
+	}
+
+	resp := &BatchWalletSendResponse{
+		RawTx: rawTx.Bytes(),
+		Txid:  result.Tx.TxHash().String(),
+		Outputs: make(
+			[]*BatchSendOutputResult, 0, len(result.Outputs),
+		),
+	}
+	for _, output := range result.Outputs {
+		resp.Outputs = append(resp.Outputs, &BatchSendOutputResult{
+			Output: &BatchSendOutput{
+				Addr:      output.Addr,
+				AmountSat: int64(output.Amount),
+				Label:     output.Label,
+			},
+			Outpoint: lnrpc.MarshalOutPoint(&output.OutPoint),
+		})
+	}
+	result.ChangeOutPoint.WhenSome(func(op wire.OutPoint) {
+		resp.ChangeOutpoint = lnrpc.MarshalOutPoint(&op)
+	})
+
+	return resp, nil
+}
+
+// batchWalletSend builds, signs and publishes a single transaction paying to
+// all the requested outputs. Compared to sending to each of them separately,
+// this avoids fragmenting the wallet's coins and paying for multiple
+// transactions. The coins to spend can be constrained through explicit include
+// and exclude lists.
+func (w *WalletKit) batchWalletSend(ctx context.Context,
+	req *batchWalletSendParams) (*batchWalletSendResult, error) {
+
+	if len(req.Outputs) == 0 {
+		return nil, errors.New("must specify at least one output to " +
+			"create")
+	}
+
+	if req.MinConfs < 0 {
+		return nil, errors.New("min confs must be non-negative")
+	}
+
+	outputs := make([]*wire.TxOut, 0, len(req.Outputs))
+	var totalOutputValue btcutil.Amount
+	for i, output := range req.Outputs {
+		txOut, err := w.batchSendTxOut(output)
+		if err != nil {
+			return nil, fmt.Errorf("invalid output %d: %w", i, err)
+		}
+
+		outputs = append(outputs, txOut)
+		totalOutputValue += output.Amount
+	}
+
+	label, err := labels.ValidateAPI(req.Label)
+	if err != nil {
+		return nil, err
+	}
+
+	feeRate := req.FeeRate
+	if feeRate == 0 {
+		targetConf := req.TargetConf
+		if targetConf == 0 {
+			targetConf = defaultBatchSendConfTarget
+		}
+
+		feeRate, err = w.cfg.FeeEstimator.EstimateFeePerKW(targetConf)
+		if err != nil {
+			return nil, fmt.Errorf("unable to estimate fee "+
+				"rate: %w", err)
+		}
+	}
+
+	// Before sending out funds we need to ensure that the remainder of our
+	// wallet funds would cover for the anchor reserve requirement.
+	walletBalance, err := w.cfg.Wallet.ConfirmedBalance(
+		0, lnwallet.DefaultAccountName,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	reserve, err := w.RequiredReserve(ctx, &RequiredReserveRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	if walletBalance-totalOutputValue <
+		btcutil.Amount(reserve.RequiredReserve) {
+
+		return nil, ErrInsufficientReserve
+	}
+
+	strategy := req.Strategy
+	if strategy == nil {
+		strategy = w.cfg.CoinSelectionStrategy
+	}
+
+	// We hold the coin selection lock while determining the coins we may
+	// spend and publishing the transaction, so no concurrent funding flow
+	// can pick the same coins in the meantime.
+	var tx *wire.MsgTx
+	err = w.cfg.CoinSelectionLocker.WithCoinSelectLock(func() error {
+		inputs, err := w.batchSendInputs(req)
+		if err != nil {
+			return err
+		}
+
+		tx, err = w.cfg.Wallet.SendOutputs(
+			inputs, outputs, feeRate, req.MinConfs, label,
+			strategy,
+		)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Published batch send %v paying to %d outputs",
+		tx.TxHash(), len(outputs))
+
+	return matchBatchSendOutputs(tx, req.Outputs, outputs)
+}
+
+// batchSendTxOut validates the given output and returns the transaction output
+// paying to it.
+func (w *WalletKit) batchSendTxOut(output batchSendOutput) (*wire.TxOut,
+	error) {
+
+	if output.Amount <= 0 {
+		return nil, errors.New("amount must be positive")
+	}
+
+	if len(output.Label) > wtxmgr.TxLabelLimit {
+		return nil, fmt.Errorf("label length %v exceeds limit of %v",
+			len(output.Label), wtxmgr.TxLabelLimit)
+	}
+
+	addr, err := btcutil.DecodeAddress(output.Addr, w.cfg.ChainParams)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode address: %w", err)
+	}
+
+	if !addr.IsForNet(w.cfg.ChainParams) {
+		return nil, fmt.Errorf("address %v is not for %v", output.Addr,
+			w.cfg.ChainParams.Name)
+	}
+
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return wire.NewTxOut(int64(output.Amount), pkScript), nil
+}
+
+// batchSendInputs returns the set of coins the wallet may select from to fund
+// the batch send. An empty set leaves the choice to the wallet.
+//
+// NOTE: This method requires the global coin selection lock to be held.
+func (w *WalletKit) batchSendInputs(
+	req *batchWalletSendParams) (fn.Set[wire.OutPoint], error) {
+
+	if len(req.IncludeOutpoints) == 0 && len(req.ExcludeOutpoints) == 0 {
+		return nil, nil
+	}
+
+	// If coins are to be excluded without an explicit list of coins to
+	// include, we start out with all the coins the wallet could spend.
+	candidates := req.IncludeOutpoints
+	if len(candidates) == 0 {
+		utxos, err := w.cfg.Wallet.ListUnspentWitness(
+			req.MinConfs, defaultMaxConf,
+			lnwallet.DefaultAccountName,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, utxo := range utxos {
+			candidates = append(candidates, utxo.OutPoint)
+		}
+	}
+
+	inputs := fn.NewSet(candidates...)
+	for _, op := range req.ExcludeOutpoints {
+		inputs.Remove(op)
+	}
+
+	if inputs.IsEmpty() {
+		return nil, errors.New("no coins left to fund the " +
+			"transaction after applying the coin constraints")
+	}
+
+	return inputs, nil
+}
+
+// matchBatchSendOutputs locates the requested outputs within the published
+// transaction. The wallet may reorder the outputs, so each of them is matched
+// by its script and value, and the remaining output, if any, is the change.
+func matchBatchSendOutputs(tx *wire.MsgTx, requested []batchSendOutput,
+	outputs []*wire.TxOut) (*batchWalletSendResult, error) {
+
+	txHash := tx.TxHash()
+	used := make(map[uint32]struct{}, len(outputs))
+
+	resp := &batchWalletSendResult{
+		Tx:      tx,
+		Outputs: make([]batchSendOutputResult, 0, len(requested)),
+	}
+	for i, output := range outputs {
+		idx, ok := findTxOut(tx, output, used)
+		if !ok {
+			return nil, fmt.Errorf("output %d not found in "+
+				"transaction %v", i, txHash)
+		}
+		used[idx] = struct{}{}
+
+		resp.Outputs = append(resp.Outputs, batchSendOutputResult{
+			batchSendOutput: requested[i],
+			OutPoint: wire.OutPoint{
+				Hash:  txHash,
+				Index: idx,
+			},
+		})
+	}
+
+	for idx := range tx.TxOut {
+		if _, ok := used[uint32(idx)]; ok {
+			continue
+		}
+
+		resp.ChangeOutPoint = fn.Some(wire.OutPoint{
+			Hash:  txHash,
+			Index: uint32(idx),
+		})
+	}
+
+	return resp, nil
+}
+
+// findTxOut returns the index of the first output of the transaction that
+// matches the given output and isn't used yet.
+func findTxOut(tx *wire.MsgTx, output *wire.TxOut,
+	used map[uint32]struct{}) (uint32, bool) {
+
+	for idx, txOut := range tx.TxOut {
+		if _, ok := used[uint32(idx)]; ok {
+			continue
+		}
+
+		if txOut.Value == output.Value &&
+			bytes.Equal(txOut.PkScript, output.PkScript) {
+
+			return uint32(idx), true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,73 @@
+//go:build walletrpc
+// +build walletrpc
+
+package walletrpc
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMatchBatchSendOutputs tests that the requested outputs of a batch send
+// are located within the published transaction, even if the wallet reordered
+// them, and that the remaining output is reported as change.
+func TestMatchBatchSendOutputs(t *testing.T) {
+	t.Parallel()
+
+	requested := []batchSendOutput{
+		{Addr: "a", Amount: 1000, Label: "first"},
+		{Addr: "b", Amount: 1000, Label: "second"},
+		{Addr: "c", Amount: 2000, Label: "third"},
+	}
+	outputs := []*wire.TxOut{
+		wire.NewTxOut(1000, []byte{0x01}),
+		wire.NewTxOut(1000, []byte{0x01}),
+		wire.NewTxOut(2000, []byte{0x02}),
+	}
+	change := wire.NewTxOut(500, []byte{0x03})
+
+	// The wallet shuffles the outputs and adds a change output.
+	tx := wire.NewMsgTx(2)
+	tx.AddTxOut(outputs[2])
+	tx.AddTxOut(change)
+	tx.AddTxOut(outputs[0])
+	tx.AddTxOut(outputs[1])
+	txHash := tx.TxHash()
+
+	resp, err := matchBatchSendOutputs(tx, requested, outputs)
+	require.NoError(t, err)
+	require.Len(t, resp.Outputs, 3)
+
+	// Identical outputs are each matched to a distinct index.
+	expectedIndexes := []uint32{2, 3, 0}
+	for i, output := range resp.Outputs {
+		require.Equal(t, requested[i], output.batchSendOutput)
+		require.Equal(t, wire.OutPoint{
+			Hash:  txHash,
+			Index: expectedIndexes[i],
+		}, output.OutPoint)
+	}
+
+	require.True(t, resp.ChangeOutPoint.IsSome())
+	require.Equal(
+		t, wire.OutPoint{Hash: txHash, Index: 1},
+		resp.ChangeOutPoint.UnwrapOr(wire.OutPoint{}),
+	)
+
+	// A transaction without change reports none.
+	tx = wire.NewMsgTx(2)
+	for _, output := range outputs {
+		tx.AddTxOut(output)
+	}
+	resp, err = matchBatchSendOutputs(tx, requested, outputs)
+	require.NoError(t, err)
+	require.True(t, resp.ChangeOutPoint.IsNone())
+
+	// A requested output missing from the transaction is an error.
+	tx = wire.NewMsgTx(2)
+	tx.AddTxOut(outputs[0])
+	_, err = matchBatchSendOutputs(tx, requested, outputs)
+	require.Error(t, err)
+}
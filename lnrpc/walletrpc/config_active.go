@@ -10,6 +10,7 @@ import (
 	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwallet/signpolicy"
 	"github.com/lightningnetwork/lnd/macaroons"
 	"github.com/lightningnetwork/lnd/sweep"
 )
@@ -32,6 +33,11 @@ type Config struct {
 	// directory, named DefaultWalletKitMacFilename.
 	WalletKitMacPath string `long:"walletkitmacaroonpath" description:"Path to the wallet kit macaroon"`
 
+	// SignPolicyMacPath is the path for the signpolicy macaroon. If
+	// unspecified then we assume that the macaroon will be found under the
+	// network directory, named DefaultSignPolicyMacFilename.
+	SignPolicyMacPath string `long:"signpolicymacaroonpath" description:"Path to the signpolicy macaroon, which is required to manage the signing policy"`
+
 	// NetworkDir is the main network directory wherein the signer rpc
 	// server will find the macaroon named DefaultWalletKitMacFilename.
 	NetworkDir string
@@ -80,4 +86,8 @@ type Config struct {
 
 	// ChanStateDB is the reference to the channel db.
 	ChanStateDB *channeldb.ChannelStateDB
+
+	// SignPolicy enforces the signing policy in front of the internal
+	// signer. This is nil if a remote signer is used.
+	SignPolicy *signpolicy.Enforcer
 }
@@ -0,0 +1,761 @@
+//go:build walletrpc
+// +build walletrpc
+
+package walletrpc
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnwallet"
+)
+
+const (
+	// descriptorInputCharset is the character set of output descriptors
+	// as defined by BIP-0380, ordered so that the checksum can be computed
+	// from the position of each character.
+	descriptorInputCharset = "0123456789()[],'/*abcdefgh@:$%{}" +
+		"IJKLMNOPQRSTUVWXYZ&+-.;<=>?!^_|~" +
+		"ijklmnopqrstuvwxyzABCDEFGH`#\"\\ "
+
+	// descriptorChecksumCharset is the character set of descriptor
+	// checksums.
+	descriptorChecksumCharset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+	// descriptorChecksumLen is the length of a descriptor checksum.
+	descriptorChecksumLen = 8
+)
+
+var (
+	// descriptorGenerator is the generator of the BCH code used for
+	// descriptor checksums.
+	descriptorGenerator = [5]uint64{
+		0xf5dee51989, 0xa9fdca3312, 0x1bab10e32d, 0x3706b1677a,
+		0x644d626ffd,
+	}
+
+	// ErrInvalidDescriptorChecksum is returned if the checksum of an
+	// output descriptor doesn't match the descriptor.
+	ErrInvalidDescriptorChecksum = errors.New("invalid descriptor " +
+		"checksum")
+
+	// ErrUnsupportedDescriptor is returned if an output descriptor can't
+	// be imported into the wallet.
+	ErrUnsupportedDescriptor = errors.New("unsupported descriptor")
+)
+
+// descriptorPolymod computes the BCH checksum of the given symbols.
+func descriptorPolymod(symbols []uint64) uint64 {
+	chk := uint64(1)
+	for _, value := range symbols {
+		top := chk >> 35
+		chk = (chk&0x7ffffffff)<<5 ^ value
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				chk ^= descriptorGenerator[i]
+			}
+		}
+	}
+
+	return chk
+}
+
+// DescriptorChecksum computes the BIP-0380 checksum of the given output
+// descriptor, which must not include a checksum already.
+func DescriptorChecksum(desc string) (string, error) {
+	symbols := make([]uint64, 0, len(desc)*2)
+	groups := make([]uint64, 0, 3)
+	for _, c := range desc {
+		pos := strings.IndexRune(descriptorInputCharset, c)
+		if pos < 0 {
+			return "", fmt.Errorf("invalid descriptor character %q",
+				c)
+		}
+
+		symbols = append(symbols, uint64(pos&31))
+		groups = append(groups, uint64(pos>>5))
+		if len(groups) == 3 {
+			symbols = append(
+				symbols, groups[0]*9+groups[1]*3+groups[2],
+			)
+			groups = groups[:0]
+		}
+	}
+
+	switch len(groups) {
+	case 1:
+		symbols = append(symbols, groups[0])
+
+	case 2:
+		symbols = append(symbols, groups[0]*3+groups[1])
+	}
+
+	symbols = append(symbols, make([]uint64, descriptorChecksumLen)...)
+	chk := descriptorPolymod(symbols) ^ 1
+
+	var checksum strings.Builder
+	for i := 0; i < descriptorChecksumLen; i++ {
+		shift := 5 * (descriptorChecksumLen - 1 - i)
+		checksum.WriteByte(descriptorChecksumCharset[(chk>>shift)&31])
+	}
+
+	return checksum.String(), nil
+}
+
+// addDescriptorChecksum appends the checksum to the given descriptor.
+func addDescriptorChecksum(desc string) (string, error) {
+	checksum, err := DescriptorChecksum(desc)
+	if err != nil {
+		return "", err
+	}
+
+	return desc + "#" + checksum, nil
+}
+
+// stripDescriptorChecksum removes the checksum of the given descriptor after
+// verifying it. Descriptors without a checksum are returned unchanged.
+func stripDescriptorChecksum(desc string) (string, error) {
+	desc = strings.TrimSpace(desc)
+
+	idx := strings.LastIndexByte(desc, '#')
+	if idx < 0 {
+		return desc, nil
+	}
+
+	expected, err := DescriptorChecksum(desc[:idx])
+	if err != nil {
+		return "", err
+	}
+	if desc[idx+1:] != expected {
+		return "", fmt.Errorf("%w: %v", ErrInvalidDescriptorChecksum,
+			desc)
+	}
+
+	return desc[:idx], nil
+}
+
+// accountScriptTypes returns the descriptor script wrappers of the external
+// and internal branch of the given account, which are formatted with the key
+// expression of the branch.
+func accountScriptTypes(account *waddrmgr.AccountProperties) (string,
+	string, error) {
+
+	const (
+		wpkh   = "wpkh(%s)"
+		shWpkh = "sh(wpkh(%s))"
+		tr     = "tr(%s)"
+	)
+
+	switch account.KeyScope {
+	case waddrmgr.KeyScopeBIP0084:
+		return wpkh, wpkh, nil
+
+	case waddrmgr.KeyScopeBIP0086:
+		return tr, tr, nil
+
+	case waddrmgr.KeyScopeBIP0049Plus:
+		// Without an address schema, the account uses our hybrid
+		// schema of nested external and native internal addresses.
+		if account.AddrSchema == nil {
+			return shWpkh, wpkh, nil
+		}
+
+		if *account.AddrSchema == waddrmgr.KeyScopeBIP0049AddrSchema {
+			return shWpkh, shWpkh, nil
+		}
+	}
+
+	return "", "", fmt.Errorf("account %v has unsupported key scope %v",
+		account.AccountName, account.KeyScope)
+}
+
+// accountDescriptors returns the ranged descriptors of the external and
+// internal addresses of the given account.
+func accountDescriptors(account *waddrmgr.AccountProperties,
+	params *chaincfg.Params) ([]*WalletDescriptor, error) {
+
+	external, internal, err := accountScriptTypes(account)
+	if err != nil {
+		return nil, err
+	}
+
+	// Descriptors only know the generic extended key version, so the
+	// scope specific version the wallet may use is replaced.
+	accountKey, err := account.AccountPubKey.CloneWithVersion(
+		params.HDPublicKeyID[:],
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// The key origin can only be given if the master key fingerprint is
+	// known.
+	var origin string
+	if account.MasterKeyFingerprint != 0 {
+		var mkfp [4]byte
+		binary.BigEndian.PutUint32(
+			mkfp[:], account.MasterKeyFingerprint,
+		)
+		origin = fmt.Sprintf("[%x/%dh/%dh/%dh]", mkfp[:],
+			account.KeyScope.Purpose, account.KeyScope.Coin,
+			account.AccountNumber)
+	}
+
+	branches := []struct {
+		script   string
+		branch   uint32
+		internal bool
+	}{
+		{script: external, branch: waddrmgr.ExternalBranch},
+		{
+			script:   internal,
+			branch:   waddrmgr.InternalBranch,
+			internal: true,
+		},
+	}
+
+	descs := make([]*WalletDescriptor, 0, len(branches))
+	for _, b := range branches {
+		keyExpr := fmt.Sprintf("%s%s/%d/*", origin, accountKey,
+			b.branch)
+
+		desc, err := addDescriptorChecksum(
+			fmt.Sprintf(b.script, keyExpr),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		branchName := "external"
+		if b.internal {
+			branchName = "internal"
+		}
+
+		descs = append(descs, &WalletDescriptor{
+			Descriptor_: desc,
+			Label: fmt.Sprintf("account %v (%v)",
+				account.AccountName, branchName),
+			Internal: b.internal,
+		})
+	}
+
+	return descs, nil
+}
+
+// channelScripts returns the output scripts of the given channel that pay to
+// our keys without the wallet tracking them, mapped to their labels. These
+// are our to_remote output and our anchor on the remote commitment. Their
+// scripts don't change throughout the lifetime of the channel, except for
+// legacy channels which tweak the to_remote key with every state and
+// channels with custom tapscript leaves, which are skipped.
+func channelScripts(c *channeldb.OpenChannel) (map[string][]byte, error) {
+	scripts := make(map[string][]byte)
+	if !c.ChanType.IsTweakless() || c.ChanType.HasTapscriptRoot() {
+		return scripts, nil
+	}
+
+	var leaseExpiry uint32
+	if c.ChanType.HasLeaseExpiration() {
+		leaseExpiry = c.ThawHeight
+	}
+
+	// The to_remote output on the remote commitment pays to our payment
+	// base point.
+	ourKey := c.LocalChanCfg.PaymentBasePoint.PubKey
+	toRemote, _, err := lnwallet.CommitScriptToRemote(
+		c.ChanType, !c.IsInitiator, ourKey, leaseExpiry,
+		input.NoneTapLeaf(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	scripts["to_remote"] = toRemote.PkScript()
+
+	if !c.ChanType.HasAnchors() {
+		return scripts, nil
+	}
+
+	// For taproot channels, our anchor on the remote commitment uses our
+	// payment base point.
+	if c.ChanType.IsTaproot() {
+		anchor, err := input.NewAnchorScriptTree(ourKey)
+		if err != nil {
+			return nil, err
+		}
+		scripts["anchor"] = anchor.PkScript()
+
+		return scripts, nil
+	}
+
+	// Regular channels use our multisig key for the anchor on both
+	// commitments.
+	anchorScript, err := input.CommitScriptAnchor(
+		c.LocalChanCfg.MultiSigKey.PubKey,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	scripts["anchor"], err = input.WitnessScriptHash(anchorScript)
+	if err != nil {
+		return nil, err
+	}
+
+	return scripts, nil
+}
+
+// channelDescriptors returns address descriptors for the channel outputs
+// paying to us that the wallet doesn't track itself.
+func channelDescriptors(channels []*channeldb.OpenChannel,
+	params *chaincfg.Params) ([]*WalletDescriptor, error) {
+
+	var descs []*WalletDescriptor
+	for _, c := range channels {
+		scripts, err := channelScripts(c)
+		if err != nil {
+			return nil, fmt.Errorf("unable to derive scripts of "+
+				"channel %v: %w", c.FundingOutpoint, err)
+		}
+
+		for _, name := range []string{"to_remote", "anchor"} {
+			pkScript, ok := scripts[name]
+			if !ok {
+				continue
+			}
+
+			_, addrs, _, err := txscript.ExtractPkScriptAddrs(
+				pkScript, params,
+			)
+			if err != nil {
+				return nil, err
+			}
+			if len(addrs) != 1 {
+				return nil, fmt.Errorf("unexpected %v script "+
+					"of channel %v", name,
+					c.FundingOutpoint)
+			}
+
+			desc, err := addDescriptorChecksum(
+				fmt.Sprintf("addr(%s)", addrs[0]),
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			descs = append(descs, &WalletDescriptor{
+				Descriptor_: desc,
+				Label: fmt.Sprintf("channel %v %v",
+					c.FundingOutpoint, name),
+			})
+		}
+	}
+
+	return descs, nil
+}
+
+// ExportDescriptors returns output descriptors covering the scripts of the
+// wallet accounts and, optionally, the channel outputs paying to us. They can
+// be imported into external watch-only wallets, such as bitcoind, to monitor
+// the funds of the node. Sweeps pay to addresses of the default account, so
+// they're covered by the account descriptors.
+//
+// NOTE: The descriptors don't contain any private keys, but they reveal all
+// addresses of the wallet to whoever obtains them.
+//
+// NOTE: This is part of the WalletKitServer interface.
+func (w *WalletKit) ExportDescriptors(_ context.Context,
+	req *ExportDescriptorsRequest) (*ExportDescriptorsResponse, error) {
+
+	accounts, err := w.cfg.Wallet.ListAccounts(req.AccountName, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var descs []*WalletDescriptor
+	for _, account := range accounts {
+		// The default imported accounts and the accounts of our
+		// internal key families don't have addresses we can describe
+		// with a single key.
+		if account.AccountName == waddrmgr.ImportedAddrAccountName ||
+			account.KeyScope == w.internalScope() {
+
+			continue
+		}
+
+		accountDescs, err := accountDescriptors(
+			account, w.cfg.ChainParams,
+		)
+		if err != nil {
+			return nil, err
+		}
+		descs = append(descs, accountDescs...)
+	}
+
+	if !req.IncludeChannels {
+		return &ExportDescriptorsResponse{Descriptors: descs}, nil
+	}
+
+	channels, err := w.cfg.ChanStateDB.FetchAllChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	chanDescs, err := channelDescriptors(channels, w.cfg.ChainParams)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExportDescriptorsResponse{
+		Descriptors: append(descs, chanDescs...),
+	}, nil
+}
+
+// descriptorScript is the script type of a parsed descriptor.
+type descriptorScript uint8
+
+const (
+	// scriptWpkh is a native segwit v0 pay-to-pubkey-hash script.
+	scriptWpkh descriptorScript = iota
+
+	// scriptShWpkh is a pay-to-pubkey-hash script nested in P2SH.
+	scriptShWpkh
+
+	// scriptTr is a BIP-0086 taproot key spend script.
+	scriptTr
+)
+
+// parsedDescriptor is an output descriptor that can be imported into the
+// wallet. Exactly one of accountKey and pubKey is set.
+type parsedDescriptor struct {
+	// script is the script type of the descriptor.
+	script descriptorScript
+
+	// fingerprint is the master key fingerprint of the key origin, if
+	// given.
+	fingerprint uint32
+
+	// accountKey is the extended account key of a ranged descriptor.
+	accountKey *hdkeychain.ExtendedKey
+
+	// internal is true if the ranged descriptor covers the internal
+	// branch of the account.
+	internal bool
+
+	// pubKey is the single public key of a non-ranged descriptor.
+	pubKey []byte
+}
+
+// parseDescriptor parses a descriptor without checksum. Only descriptors of a
+// single key or a ranged key of the external or internal branch of an
+// account, wrapped in wpkh, sh(wpkh) or tr, are supported.
+func parseDescriptor(desc string) (*parsedDescriptor, error) {
+	parsed := &parsedDescriptor{}
+
+	var keyExpr string
+	switch {
+	case strings.HasPrefix(desc, "sh(wpkh(") &&
+		strings.HasSuffix(desc, "))"):
+
+		parsed.script = scriptShWpkh
+		keyExpr = desc[len("sh(wpkh(") : len(desc)-2]
+
+	case strings.HasPrefix(desc, "wpkh(") && strings.HasSuffix(desc, ")"):
+		parsed.script = scriptWpkh
+		keyExpr = desc[len("wpkh(") : len(desc)-1]
+
+	case strings.HasPrefix(desc, "tr(") && strings.HasSuffix(desc, ")"):
+		parsed.script = scriptTr
+		keyExpr = desc[len("tr(") : len(desc)-1]
+
+	default:
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedDescriptor, desc)
+	}
+
+	// Parse the optional key origin, of which only the fingerprint is
+	// relevant for the wallet.
+	if strings.HasPrefix(keyExpr, "[") {
+		end := strings.IndexByte(keyExpr, ']')
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated key origin: %v",
+				desc)
+		}
+
+		origin := keyExpr[1:end]
+		keyExpr = keyExpr[end+1:]
+
+		fingerprint, _, _ := strings.Cut(origin, "/")
+		fp, err := hex.DecodeString(fingerprint)
+		if err != nil || len(fp) != 4 {
+			return nil, fmt.Errorf("invalid key origin "+
+				"fingerprint: %v", desc)
+		}
+		parsed.fingerprint = binary.BigEndian.Uint32(fp)
+	}
+
+	key, path, ranged := strings.Cut(keyExpr, "/")
+	if !ranged {
+		pubKey, err := hex.DecodeString(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key: %v", desc)
+		}
+
+		if parsed.script == scriptTr {
+			_, err = schnorr.ParsePubKey(pubKey)
+		} else {
+			_, err = btcec.ParsePubKey(pubKey)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key: %w", err)
+		}
+
+		parsed.pubKey = pubKey
+
+		return parsed, nil
+	}
+
+	switch path {
+	case "0/*":
+
+	case "1/*":
+		parsed.internal = true
+
+	default:
+		return nil, fmt.Errorf("%w: derivation path %v of %v",
+			ErrUnsupportedDescriptor, path, desc)
+	}
+
+	accountKey, err := hdkeychain.NewKeyFromString(key)
+	if err != nil {
+		return nil, err
+	}
+	if accountKey.IsPrivate() {
+		return nil, fmt.Errorf("descriptor %v contains a private key",
+			desc)
+	}
+	parsed.accountKey = accountKey
+
+	return parsed, nil
+}
+
+// accountImport collects the descriptors of the same account key.
+type accountImport struct {
+	key         *hdkeychain.ExtendedKey
+	fingerprint uint32
+	external    fn.Option[descriptorScript]
+	internal    fn.Option[descriptorScript]
+}
+
+// addrType returns the address type to import the account with, given the
+// script types of its external and internal branch. The returned bool is true
+// if the account uses our hybrid schema, which nests external addresses only.
+// If only one branch is given, the other one is assumed to use the same
+// script type.
+func (a *accountImport) addrType() (waddrmgr.AddressType, bool, error) {
+	external := a.external.UnwrapOr(a.internal.UnwrapOr(scriptWpkh))
+	internal := a.internal.UnwrapOr(external)
+
+	switch {
+	case external == scriptTr && internal == scriptTr:
+		return waddrmgr.TaprootPubKey, false, nil
+
+	case external == scriptWpkh && internal == scriptWpkh:
+		return waddrmgr.WitnessPubKey, false, nil
+
+	case external == scriptShWpkh && internal == scriptShWpkh:
+		return waddrmgr.NestedWitnessPubKey, false, nil
+
+	// The hybrid schema is selected by importing a BIP-0049 key as
+	// witness pubkey account.
+	case external == scriptShWpkh && internal == scriptWpkh:
+		return waddrmgr.WitnessPubKey, true, nil
+
+	default:
+		return 0, false, fmt.Errorf("%w: mixed script types for "+
+			"account key %v", ErrUnsupportedDescriptor, a.key)
+	}
+}
+
+// bip49KeyVersion returns the SLIP-0132 version of BIP-0049 account keys
+// corresponding to the generic extended key version of the given network.
+func bip49KeyVersion(params *chaincfg.Params) ([]byte, error) {
+	var version uint32
+	switch binary.BigEndian.Uint32(params.HDPublicKeyID[:]) {
+	// xpub -> ypub.
+	case 0x0488b21e:
+		version = 0x049d7cb2
+
+	// tpub -> upub.
+	case 0x043587cf:
+		version = 0x044a5262
+
+	default:
+		return nil, fmt.Errorf("nested accounts not supported on %v",
+			params.Name)
+	}
+
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], version)
+
+	return b[:], nil
+}
+
+// ImportDescriptors imports the given descriptors into the wallet, which is
+// used to watch the funds described by descriptors exported from another
+// wallet, for example after recovering a node. Ranged descriptors of an
+// account key are imported as account and single key descriptors as public
+// key. Address descriptors are skipped. All descriptors are validated before
+// any of them is imported.
+//
+// NOTE: The wallet only finds past transactions of the imported keys after a
+// rescan from a height before their first use.
+//
+// NOTE: This is part of the WalletKitServer interface.
+func (w *WalletKit) ImportDescriptors(_ context.Context,
+	req *ImportDescriptorsRequest) (*ImportDescriptorsResponse, error) {
+
+	if req.AccountName == "" {
+		return nil, errors.New("account name must be specified")
+	}
+
+	var (
+		resp     = &ImportDescriptorsResponse{}
+		accounts []*accountImport
+		byKey    = make(map[string]*accountImport)
+		pubKeys  []*parsedDescriptor
+	)
+	for _, rawDesc := range req.Descriptors {
+		desc, err := stripDescriptorChecksum(rawDesc)
+		if err != nil {
+			return nil, err
+		}
+
+		if strings.HasPrefix(desc, "addr(") ||
+			strings.HasPrefix(desc, "raw(") {
+
+			resp.Skipped = append(resp.Skipped, rawDesc)
+			continue
+		}
+
+		parsed, err := parseDescriptor(desc)
+		if err != nil {
+			return nil, err
+		}
+
+		if parsed.accountKey == nil {
+			pubKeys = append(pubKeys, parsed)
+			continue
+		}
+
+		keyStr := parsed.accountKey.String()
+		account, ok := byKey[keyStr]
+		if !ok {
+			account = &accountImport{
+				key:         parsed.accountKey,
+				fingerprint: parsed.fingerprint,
+			}
+			byKey[keyStr] = account
+			accounts = append(accounts, account)
+		}
+
+		if parsed.internal {
+			account.internal = fn.Some(parsed.script)
+		} else {
+			account.external = fn.Some(parsed.script)
+		}
+	}
+
+	// Determine the address types of all accounts before importing the
+	// first one.
+	addrTypes := make([]waddrmgr.AddressType, len(accounts))
+	for i, account := range accounts {
+		addrType, isHybrid, err := account.addrType()
+		if err != nil {
+			return nil, err
+		}
+		addrTypes[i] = addrType
+
+		if !isHybrid {
+			continue
+		}
+
+		version, err := bip49KeyVersion(w.cfg.ChainParams)
+		if err != nil {
+			return nil, err
+		}
+
+		account.key, err = account.key.CloneWithVersion(version)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i, account := range accounts {
+		name := req.AccountName
+		if i > 0 {
+			name = name + "-" + strconv.Itoa(i)
+		}
+
+		props, _, _, err := w.cfg.Wallet.ImportAccount(
+			name, account.key, account.fingerprint, &addrTypes[i],
+			false,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to import account %v: "+
+				"%w", name, err)
+		}
+
+		rpcAccount, err := marshalWalletAccount(
+			w.internalScope(), props,
+		)
+		if err != nil {
+			return nil, err
+		}
+		resp.Accounts = append(resp.Accounts, rpcAccount)
+	}
+
+	for _, parsed := range pubKeys {
+		var (
+			pubKey   *btcec.PublicKey
+			addrType waddrmgr.AddressType
+			err      error
+		)
+		switch parsed.script {
+		case scriptTr:
+			pubKey, err = schnorr.ParsePubKey(parsed.pubKey)
+			addrType = waddrmgr.TaprootPubKey
+
+		case scriptShWpkh:
+			pubKey, err = btcec.ParsePubKey(parsed.pubKey)
+			addrType = waddrmgr.NestedWitnessPubKey
+
+		default:
+			pubKey, err = btcec.ParsePubKey(parsed.pubKey)
+			addrType = waddrmgr.WitnessPubKey
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		err = w.cfg.Wallet.ImportPublicKey(pubKey, addrType)
+		if err != nil {
+			return nil, err
+		}
+		resp.NumPublicKeys++
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,228 @@
+//go:build walletrpc
+// +build walletrpc
+
+package walletrpc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// testDescriptorKey is the account key of the descriptor examples of
+	// Bitcoin Core.
+	testDescriptorKey = "xpub6DJ2dNUysrn5Vt36jH2KLBT2i1auw1tTSSomg8PhqNi" +
+		"Utx8QX2SvC9nrHu81fT41fvDUnhMjEzQgXnQjKEu3oaqMSzhSrHMxy" +
+		"yoEAmUHQbY"
+)
+
+// TestDescriptorChecksum asserts that descriptor checksums match the known
+// test vectors and that invalid checksums are rejected.
+func TestDescriptorChecksum(t *testing.T) {
+	t.Parallel()
+
+	checksum, err := DescriptorChecksum("raw(deadbeef)")
+	require.NoError(t, err)
+	require.Equal(t, "89f8spxm", checksum)
+
+	desc := "wpkh([d34db33f/84h/0h/0h]" + testDescriptorKey + "/0/*)"
+	withChecksum, err := addDescriptorChecksum(desc)
+	require.NoError(t, err)
+	require.Equal(t, desc+"#cjjspncu", withChecksum)
+
+	stripped, err := stripDescriptorChecksum(withChecksum)
+	require.NoError(t, err)
+	require.Equal(t, desc, stripped)
+
+	// Descriptors without checksum are accepted as well.
+	stripped, err = stripDescriptorChecksum(desc)
+	require.NoError(t, err)
+	require.Equal(t, desc, stripped)
+
+	_, err = stripDescriptorChecksum(desc + "#cjjspncv")
+	require.ErrorIs(t, err, ErrInvalidDescriptorChecksum)
+
+	_, err = DescriptorChecksum("raw(deadbeef)\n")
+	require.Error(t, err)
+}
+
+// TestAccountDescriptors asserts that the exported descriptors of an account
+// can be parsed again and result in the account's address type.
+func TestAccountDescriptors(t *testing.T) {
+	t.Parallel()
+
+	params := &chaincfg.RegressionNetParams
+
+	seed := make([]byte, hdkeychain.RecommendedSeedLen)
+	rootKey, err := hdkeychain.NewMaster(seed, params)
+	require.NoError(t, err)
+	accountKey, err := rootKey.Neuter()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		scope      waddrmgr.KeyScope
+		schema     *waddrmgr.ScopeAddrSchema
+		prefixes   [2]string
+		addrType   waddrmgr.AddressType
+		isHybrid   bool
+		hasOrigin  bool
+		expectsErr bool
+	}{
+		{
+			name:      "native segwit",
+			scope:     waddrmgr.KeyScopeBIP0084,
+			prefixes:  [2]string{"wpkh(", "wpkh("},
+			addrType:  waddrmgr.WitnessPubKey,
+			hasOrigin: true,
+		},
+		{
+			name:     "taproot",
+			scope:    waddrmgr.KeyScopeBIP0086,
+			prefixes: [2]string{"tr(", "tr("},
+			addrType: waddrmgr.TaprootPubKey,
+		},
+		{
+			name:     "hybrid nested segwit",
+			scope:    waddrmgr.KeyScopeBIP0049Plus,
+			prefixes: [2]string{"sh(wpkh(", "wpkh("},
+			addrType: waddrmgr.WitnessPubKey,
+			isHybrid: true,
+		},
+		{
+			name:     "nested segwit",
+			scope:    waddrmgr.KeyScopeBIP0049Plus,
+			schema:   &waddrmgr.KeyScopeBIP0049AddrSchema,
+			prefixes: [2]string{"sh(wpkh(", "sh(wpkh("},
+			addrType: waddrmgr.NestedWitnessPubKey,
+		},
+		{
+			name: "unsupported scope",
+			scope: waddrmgr.KeyScope{
+				Purpose: 1017,
+				Coin:    1,
+			},
+			expectsErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			account := &waddrmgr.AccountProperties{
+				AccountNumber: 3,
+				AccountName:   "test",
+				KeyScope:      test.scope,
+				AddrSchema:    test.schema,
+				AccountPubKey: accountKey,
+			}
+			if test.hasOrigin {
+				account.MasterKeyFingerprint = 0xd34db33f
+			}
+
+			descs, err := accountDescriptors(account, params)
+			if test.expectsErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, descs, 2)
+
+			imported := &accountImport{}
+			for i, desc := range descs {
+				require.Equal(t, i == 1, desc.Internal)
+				require.True(t, strings.HasPrefix(
+					desc.GetDescriptor_(), test.prefixes[i],
+				))
+
+				raw, err := stripDescriptorChecksum(
+					desc.GetDescriptor_(),
+				)
+				require.NoError(t, err)
+
+				parsed, err := parseDescriptor(raw)
+				require.NoError(t, err)
+				require.Equal(t, desc.Internal, parsed.internal)
+				require.Equal(
+					t, accountKey.String(),
+					parsed.accountKey.String(),
+				)
+
+				if test.hasOrigin {
+					require.Contains(
+						t, raw, "[d34db33f/84h/0h/3h]",
+					)
+					require.EqualValues(
+						t, 0xd34db33f,
+						parsed.fingerprint,
+					)
+				}
+
+				if parsed.internal {
+					imported.internal = fn.Some(
+						parsed.script,
+					)
+				} else {
+					imported.external = fn.Some(
+						parsed.script,
+					)
+				}
+			}
+
+			addrType, isHybrid, err := imported.addrType()
+			require.NoError(t, err)
+			require.Equal(t, test.addrType, addrType)
+			require.Equal(t, test.isHybrid, isHybrid)
+		})
+	}
+}
+
+// TestParseDescriptor asserts that single key descriptors are parsed and
+// that unsupported descriptors are rejected.
+func TestParseDescriptor(t *testing.T) {
+	t.Parallel()
+
+	const pubKey = "03a34b99f22c790c4e36b2b3c2c35a36db06226e41c692fc82b8" +
+		"b56ac1c540c5bd"
+
+	parsed, err := parseDescriptor("sh(wpkh(" + pubKey + "))")
+	require.NoError(t, err)
+	require.Equal(t, scriptShWpkh, parsed.script)
+	require.Nil(t, parsed.accountKey)
+	require.Len(t, parsed.pubKey, 33)
+
+	parsed, err = parseDescriptor("tr(" + pubKey[2:] + ")")
+	require.NoError(t, err)
+	require.Equal(t, scriptTr, parsed.script)
+	require.Len(t, parsed.pubKey, 32)
+
+	unsupported := []string{
+		"pkh(" + pubKey + ")",
+		"wsh(pk(" + pubKey + "))",
+		"wpkh(" + testDescriptorKey + "/0h/*)",
+		"wpkh(" + testDescriptorKey + "/<0;1>/*)",
+	}
+	for _, desc := range unsupported {
+		_, err := parseDescriptor(desc)
+		require.ErrorIs(t, err, ErrUnsupportedDescriptor, desc)
+	}
+
+	_, err = parseDescriptor("wpkh(" + pubKey[2:] + ")")
+	require.Error(t, err)
+
+	mixed := &accountImport{
+		external: fn.Some(scriptTr),
+		internal: fn.Some(scriptWpkh),
+	}
+	_, _, err = mixed.addrType()
+	require.ErrorIs(t, err, ErrUnsupportedDescriptor)
+}
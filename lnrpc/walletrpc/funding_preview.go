@@ -0,0 +1,251 @@
+//go:build walletrpc
+// +build walletrpc
+
+package walletrpc
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	base "github.com/btcsuite/btcwallet/wallet"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
+)
+
+// fundingPreviewParams describes a transaction output the wallet should fund
+// and the constraints for selecting the coins to fund it with.
+type fundingPreviewParams struct {
+	// Amount is the value of the output to fund.
+	Amount btcutil.Amount
+
+	// OutputScript is the pkScript of the output to fund, e.g. a channel
+	// funding output. It is only used to estimate the transaction weight.
+	// If empty, a P2WSH output is assumed.
+	OutputScript []byte
+
+	// FeeRate is the fee rate the transaction should pay.
+	FeeRate chainfee.SatPerKWeight
+
+	// MinConfs is the minimum number of confirmations of the coins that
+	// may be selected.
+	MinConfs int32
+
+	// MaxConfs is the maximum number of confirmations of the coins that
+	// may be selected. Zero means no maximum.
+	MaxConfs int32
+
+	// Account is the wallet account to select coins from.
+	Account string
+
+	// Outpoints restricts the coin selection to the given coins, if
+	// non-empty.
+	Outpoints []wire.OutPoint
+
+	// ExcludeOutpoints are coins that must not be selected.
+	ExcludeOutpoints []wire.OutPoint
+
+	// ChangeType is the type of the change output. Only
+	// chanfunding.P2WKHChangeAddress and chanfunding.P2TRChangeAddress
+	// are supported.
+	ChangeType chanfunding.ChangeAddressType
+
+	// Strategy is the coin selection strategy. If nil, the wallet's
+	// default strategy is used.
+	Strategy base.CoinSelectionStrategy
+}
+
+// FundingPreview returns the inputs, change and fee the wallet would choose to
+// fund an output of the given amount, without locking any coins or otherwise
+// modifying the wallet. This lets applications show users what a channel open
+// or send will do before committing to it.
+//
+// NOTE: This is part of the WalletKitServer interface.
+func (w *WalletKit) FundingPreview(_ context.Context,
+	req *FundingPreviewRequest) (*FundingPreviewResponse, error) {
+
+	params := &fundingPreviewParams{
+		Amount:       btcutil.Amount(req.AmountSat),
+		OutputScript: req.OutputScript,
+		MinConfs:     req.MinConfs,
+		MaxConfs:     req.MaxConfs,
+		Account:      req.Account,
+		ChangeType:   chanfunding.P2WKHChangeAddress,
+	}
+
+	if params.Account == "" {
+		params.Account = lnwallet.DefaultAccountName
+	}
+
+	if req.ChangeType == ChangeAddressType_CHANGE_ADDRESS_TYPE_P2TR {
+		params.ChangeType = chanfunding.P2TRChangeAddress
+	}
+
+	var err error
+	switch {
+	case req.GetTargetConf() != 0:
+		targetConf := req.GetTargetConf()
+		if targetConf < 2 {
+			return nil, fmt.Errorf("confirmation target must be " +
+				"greater than 1")
+		}
+
+		params.FeeRate, err = w.cfg.FeeEstimator.EstimateFeePerKW(
+			targetConf,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("could not estimate fee: %w",
+				err)
+		}
+
+	case req.GetSatPerVbyte() != 0:
+		params.FeeRate = chainfee.SatPerKVByte(
+			req.GetSatPerVbyte() * 1000,
+		).FeePerKWeight()
+
+	default:
+		return nil, fmt.Errorf("fee definition missing, need to " +
+			"specify either target_conf or sat_per_vbyte")
+	}
+
+	params.Strategy, err = lnrpc.UnmarshallCoinSelectionStrategy(
+		req.CoinSelectionStrategy, w.cfg.CoinSelectionStrategy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, op := range req.Outpoints {
+		outpoint, err := UnmarshallOutPoint(op)
+		if err != nil {
+			return nil, err
+		}
+		params.Outpoints = append(params.Outpoints, *outpoint)
+	}
+	for _, op := range req.ExcludeOutpoints {
+		outpoint, err := UnmarshallOutPoint(op)
+		if err != nil {
+			return nil, err
+		}
+		params.ExcludeOutpoints = append(
+			params.ExcludeOutpoints, *outpoint,
+		)
+	}
+
+	preview, err := w.fundingPreview(params)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &FundingPreviewResponse{
+		Inputs:          make([]*lnrpc.Utxo, 0, len(preview.Inputs)),
+		ChangeAmountSat: int64(preview.ChangeAmt),
+		FeeSat:          int64(preview.Fee),
+	}
+	for _, coin := range preview.Inputs {
+		resp.Inputs = append(resp.Inputs, &lnrpc.Utxo{
+			AmountSat: coin.Value,
+			PkScript:  hex.EncodeToString(coin.PkScript),
+			Outpoint:  lnrpc.MarshalOutPoint(&coin.OutPoint),
+		})
+	}
+
+	return resp, nil
+}
+
+// fundingPreview returns the inputs, change and fee the wallet would choose to
+// fund the described output, without locking any coins or otherwise modifying
+// the wallet.
+func (w *WalletKit) fundingPreview(req *fundingPreviewParams) (
+	*chanfunding.CoinSelectPreview, error) {
+
+	if req.Amount <= 0 {
+		return nil, errors.New("amount must be positive")
+	}
+
+	if req.MinConfs < 0 {
+		return nil, errors.New("min confs must be non-negative")
+	}
+
+	maxConfs := req.MaxConfs
+	if maxConfs == 0 {
+		maxConfs = defaultMaxConf
+	}
+	if maxConfs < req.MinConfs {
+		return nil, errors.New("max confs must be greater than or " +
+			"equal to min confs")
+	}
+
+	var changeDustLimit btcutil.Amount
+	switch req.ChangeType {
+	case chanfunding.P2TRChangeAddress:
+		changeDustLimit = lnwallet.DustLimitForSize(input.P2TRSize)
+
+	case chanfunding.P2WKHChangeAddress:
+		changeDustLimit = lnwallet.DustLimitForSize(input.P2WPKHSize)
+
+	default:
+		return nil, fmt.Errorf("unsupported change type: %v",
+			req.ChangeType)
+	}
+
+	strategy := req.Strategy
+	if strategy == nil {
+		strategy = w.cfg.CoinSelectionStrategy
+	}
+
+	var estimator input.TxWeightEstimator
+	if len(req.OutputScript) == 0 {
+		estimator.AddP2WSHOutput()
+	} else {
+		estimator.AddOutput(req.OutputScript)
+	}
+
+	// We hold the coin selection lock while listing and selecting the
+	// coins, so the preview reflects a consistent view of the coins that
+	// aren't locked by a concurrent funding flow.
+	var preview *chanfunding.CoinSelectPreview
+	err := w.cfg.CoinSelectionLocker.WithCoinSelectLock(func() error {
+		utxos, err := w.cfg.Wallet.ListUnspentWitness(
+			req.MinConfs, maxConfs, req.Account,
+		)
+		if err != nil {
+			return err
+		}
+
+		coins := make([]base.Coin, len(utxos))
+		for i, utxo := range utxos {
+			coins[i] = base.Coin{
+				TxOut: wire.TxOut{
+					Value:    int64(utxo.Value),
+					PkScript: utxo.PkScript,
+				},
+				OutPoint: utxo.OutPoint,
+			}
+		}
+		coins = chanfunding.FilterCoins(
+			coins, req.Outpoints, req.ExcludeOutpoints,
+		)
+
+		preview, err = chanfunding.PreviewCoinSelect(
+			req.FeeRate, req.Amount, changeDustLimit, coins,
+			strategy, estimator, req.ChangeType,
+		)
+		if err != nil {
+			return fmt.Errorf("error selecting coins: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return preview, nil
+}
@@ -0,0 +1,108 @@
+//go:build walletrpc
+// +build walletrpc
+
+package walletrpc
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/lightningnetwork/lnd/lncfg"
+	"github.com/lightningnetwork/lnd/lnwallet/signpolicy"
+)
+
+// errNoSignPolicy is returned if the signing policy is managed while lnd uses
+// a remote signer, which doesn't enforce it.
+var errNoSignPolicy = errors.New("signing policy is only enforced by the " +
+	"internal signer")
+
+// GetSignPolicy returns the policy the internal signer enforces before it
+// signs away the funds of the wallet.
+func (w *WalletKit) GetSignPolicy(_ context.Context,
+	_ *GetSignPolicyRequest) (*SignPolicy, error) {
+
+	if w.cfg.SignPolicy == nil {
+		return nil, errNoSignPolicy
+	}
+
+	return marshalSignPolicy(w.cfg.SignPolicy.Policy(), w.cfg.ChainParams)
+}
+
+// SetSignPolicy replaces the policy the internal signer enforces before it
+// signs away the funds of the wallet.
+func (w *WalletKit) SetSignPolicy(_ context.Context,
+	req *SignPolicy) (*SetSignPolicyResponse, error) {
+
+	if w.cfg.SignPolicy == nil {
+		return nil, errNoSignPolicy
+	}
+
+	policy, err := unmarshalSignPolicy(req, w.cfg.ChainParams)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := w.cfg.SignPolicy.SetPolicy(policy); err != nil {
+		return nil, err
+	}
+
+	return &SetSignPolicyResponse{}, nil
+}
+
+// unmarshalSignPolicy parses an RPC signing policy the same way as the
+// signing policy of the configuration.
+func unmarshalSignPolicy(req *SignPolicy,
+	params *chaincfg.Params) (signpolicy.Policy, error) {
+
+	cfg := &lncfg.SignPolicy{
+		MaxSpend: req.MaxSpendSat,
+		SpendWindow: time.Duration(req.SpendWindowSeconds) *
+			time.Second,
+		AllowedAddresses: req.AllowedAddresses,
+		AllowedPeers:     req.AllowedPeers,
+	}
+	if err := cfg.Validate(); err != nil {
+		return signpolicy.Policy{}, err
+	}
+
+	return cfg.Policy(params)
+}
+
+// marshalSignPolicy converts a signing policy into its RPC representation.
+func marshalSignPolicy(policy signpolicy.Policy,
+	params *chaincfg.Params) (*SignPolicy, error) {
+
+	rpcPolicy := &SignPolicy{
+		MaxSpendSat:        int64(policy.MaxSpend),
+		SpendWindowSeconds: uint64(policy.SpendWindow / time.Second),
+	}
+
+	for _, pkScript := range policy.AllowedDestinations {
+		_, addrs, _, err := txscript.ExtractPkScriptAddrs(
+			pkScript, params,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if len(addrs) != 1 {
+			return nil, errors.New("allowed destination is not " +
+				"an address")
+		}
+
+		rpcPolicy.AllowedAddresses = append(
+			rpcPolicy.AllowedAddresses, addrs[0].String(),
+		)
+	}
+
+	for _, peer := range policy.AllowedCounterparties {
+		rpcPolicy.AllowedPeers = append(
+			rpcPolicy.AllowedPeers, hex.EncodeToString(peer[:]),
+		)
+	}
+
+	return rpcPolicy, nil
+}
@@ -0,0 +1,57 @@
+//go:build walletrpc
+// +build walletrpc
+
+package walletrpc
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSignPolicyConversion tests that signing policies are converted from and
+// into their RPC representation.
+func TestSignPolicyConversion(t *testing.T) {
+	t.Parallel()
+
+	params := &chaincfg.RegressionNetParams
+	addr, err := btcutil.NewAddressWitnessPubKeyHash(
+		make([]byte, 20), params,
+	)
+	require.NoError(t, err)
+
+	peer := strings.Repeat("02", route.VertexSize)
+
+	rpcPolicy := &SignPolicy{
+		MaxSpendSat:        100_000,
+		SpendWindowSeconds: 3600,
+		AllowedAddresses:   []string{addr.String()},
+		AllowedPeers:       []string{peer},
+	}
+
+	policy, err := unmarshalSignPolicy(rpcPolicy, params)
+	require.NoError(t, err)
+	require.EqualValues(t, 100_000, policy.MaxSpend)
+	require.Equal(t, time.Hour, policy.SpendWindow)
+	require.Len(t, policy.AllowedDestinations, 1)
+	require.Len(t, policy.AllowedCounterparties, 1)
+
+	marshaled, err := marshalSignPolicy(policy, params)
+	require.NoError(t, err)
+	require.Equal(t, rpcPolicy, marshaled)
+
+	// A velocity limit without a window is rejected.
+	_, err = unmarshalSignPolicy(&SignPolicy{MaxSpendSat: 1}, params)
+	require.ErrorContains(t, err, "spendwindow must be positive")
+
+	// So are addresses of another network.
+	_, err = unmarshalSignPolicy(&SignPolicy{
+		AllowedAddresses: []string{addr.String()},
+	}, &chaincfg.MainNetParams)
+	require.Error(t, err)
+}
@@ -0,0 +1,78 @@
+//go:build walletrpc
+// +build walletrpc
+
+package walletrpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/sweep"
+)
+
+// SweepFeeReport reports, for each of the given fee rates, the outcome of
+// sweeping the inputs that are currently pending in the sweeper at that fee
+// rate. No transactions are created or published.
+func (w *WalletKit) SweepFeeReport(_ context.Context,
+	req *SweepFeeReportRequest) (*SweepFeeReportResponse, error) {
+
+	if len(req.Candidates) == 0 {
+		return nil, errors.New("at least one fee rate candidate must " +
+			"be given")
+	}
+
+	candidates := make([]sweep.FeeCandidate, 0, len(req.Candidates))
+	for _, c := range req.Candidates {
+		candidates = append(candidates, sweep.FeeCandidate{
+			FeeRate: chainfee.SatPerVByte(
+				c.SatPerVbyte,
+			).FeePerKWeight(),
+			ConfTarget: c.TargetConf,
+		})
+	}
+
+	reports, err := w.cfg.Sweeper.FeeReport(candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &SweepFeeReportResponse{
+		Reports: make([]*SweepFeeReport, 0, len(reports)),
+	}
+	for _, report := range reports {
+		resp.Reports = append(resp.Reports, marshalFeeReport(report))
+	}
+
+	return resp, nil
+}
+
+// marshalFeeReport converts a sweep fee report into its RPC representation.
+func marshalFeeReport(report *sweep.FeeReport) *SweepFeeReport {
+	rpcReport := &SweepFeeReport{
+		SatPerVbyte:        uint64(report.FeeRate.FeePerVByte()),
+		TargetConf:         report.ConfTarget,
+		TotalFeeSat:        int64(report.TotalFee),
+		NumWithinBudget:    uint32(report.NumWithinBudget),
+		BudgetShortfallSat: int64(report.BudgetShortfall),
+	}
+
+	for _, set := range report.Sets {
+		rpcSet := &SweepInputSetReport{
+			DeadlineHeight: set.DeadlineHeight,
+			BudgetSat:      int64(set.Budget),
+			Weight:         uint64(set.Weight),
+			FeeSat:         int64(set.Fee),
+			WithinBudget:   set.WithinBudget,
+		}
+		for i := range set.Inputs {
+			op := lnrpc.MarshalOutPoint(&set.Inputs[i])
+			rpcSet.Inputs = append(rpcSet.Inputs, op)
+		}
+
+		rpcReport.Sets = append(rpcReport.Sets, rpcSet)
+	}
+
+	return rpcReport
+}
@@ -0,0 +1,53 @@
+//go:build walletrpc
+// +build walletrpc
+
+package walletrpc
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/sweep"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMarshalFeeReport tests that sweep fee reports are converted into their
+// RPC representation.
+func TestMarshalFeeReport(t *testing.T) {
+	t.Parallel()
+
+	op := wire.OutPoint{Hash: chainhash.Hash{1}, Index: 2}
+	report := &sweep.FeeReport{
+		FeeRate:    chainfee.SatPerVByte(10).FeePerKWeight(),
+		ConfTarget: 6,
+		Sets: []sweep.SetFeeReport{{
+			Inputs:         []wire.OutPoint{op},
+			DeadlineHeight: 800,
+			Budget:         1000,
+			Weight:         600,
+			Fee:            1500,
+		}},
+		TotalFee:        1500,
+		BudgetShortfall: 500,
+	}
+
+	rpcReport := marshalFeeReport(report)
+	require.EqualValues(t, 10, rpcReport.SatPerVbyte)
+	require.EqualValues(t, 6, rpcReport.TargetConf)
+	require.EqualValues(t, 1500, rpcReport.TotalFeeSat)
+	require.Zero(t, rpcReport.NumWithinBudget)
+	require.EqualValues(t, 500, rpcReport.BudgetShortfallSat)
+
+	require.Len(t, rpcReport.Sets, 1)
+	set := rpcReport.Sets[0]
+	require.Len(t, set.Inputs, 1)
+	require.Equal(t, op.Hash.String(), set.Inputs[0].TxidStr)
+	require.EqualValues(t, 2, set.Inputs[0].OutputIndex)
+	require.EqualValues(t, 800, set.DeadlineHeight)
+	require.EqualValues(t, 1000, set.BudgetSat)
+	require.EqualValues(t, 600, set.Weight)
+	require.EqualValues(t, 1500, set.FeeSat)
+	require.False(t, set.WithinBudget)
+}
@@ -0,0 +1,156 @@
+//go:build walletrpc
+// +build walletrpc
+
+package walletrpc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/sweep"
+)
+
+const (
+	// maxSweepJournalEntries is the maximum number of journal entries
+	// returned by a single query.
+	maxSweepJournalEntries = 1000
+)
+
+// SweepJournal returns the recorded outcomes of past sweep attempts matching
+// the query. Entries are returned in the order they were recorded, with at
+// most maxSweepJournalEntries entries per page.
+func (w *WalletKit) SweepJournal(_ context.Context,
+	req *SweepJournalRequest) (*SweepJournalResponse, error) {
+
+	query, err := unmarshalJournalQuery(req)
+	if err != nil {
+		return nil, err
+	}
+
+	slice, err := w.cfg.Sweeper.QueryJournal(query)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &SweepJournalResponse{
+		Entries: make(
+			[]*SweepJournalEntry, 0, len(slice.Entries),
+		),
+		LastIndexOffset: slice.LastIndexOffset,
+	}
+	for _, entry := range slice.Entries {
+		resp.Entries = append(resp.Entries, marshalJournalEntry(entry))
+	}
+
+	summary := sweep.SummarizeJournal(slice.Entries)
+	resp.Outcomes, resp.ConflictedInputs = marshalJournalSummary(summary)
+
+	return resp, nil
+}
+
+// unmarshalJournalQuery converts a sweep journal request into a query of the
+// sweeper's journal.
+func unmarshalJournalQuery(req *SweepJournalRequest) (sweep.JournalQuery,
+	error) {
+
+	query := sweep.JournalQuery{
+		IndexOffset: req.IndexOffset,
+		MaxEntries:  req.MaxEntries,
+		Outcomes:    fn.NewSet[sweep.SweepOutcome](),
+		StartHeight: req.StartHeight,
+		EndHeight:   req.EndHeight,
+	}
+
+	if query.EndHeight != 0 && query.StartHeight > query.EndHeight {
+		return query, fmt.Errorf("start height %v exceeds end "+
+			"height %v", query.StartHeight, query.EndHeight)
+	}
+
+	if query.MaxEntries == 0 || query.MaxEntries > maxSweepJournalEntries {
+		query.MaxEntries = maxSweepJournalEntries
+	}
+
+	for _, outcome := range req.Outcomes {
+		if _, ok := SweepOutcome_name[int32(outcome)]; !ok {
+			return query, fmt.Errorf("unknown sweep outcome %v",
+				outcome)
+		}
+
+		query.Outcomes.Add(sweep.SweepOutcome(outcome))
+	}
+
+	return query, nil
+}
+
+// marshalJournalEntry converts a sweep journal entry into its RPC
+// representation.
+func marshalJournalEntry(entry *sweep.JournalEntry) *SweepJournalEntry {
+	rpcEntry := &SweepJournalEntry{
+		Index:     entry.Index,
+		Txid:      entry.Txid.String(),
+		Outcome:   SweepOutcome(entry.Outcome),
+		FeeSat:    int64(entry.Fee),
+		SatPerKw:  int64(entry.FeeRate),
+		Inputs:    make([]*lnrpc.OutPoint, 0, len(entry.Inputs)),
+		Height:    entry.Height,
+		Timestamp: entry.Timestamp.Unix(),
+		Reason:    entry.Reason,
+	}
+
+	for i := range entry.Inputs {
+		op := lnrpc.MarshalOutPoint(&entry.Inputs[i])
+		rpcEntry.Inputs = append(rpcEntry.Inputs, op)
+	}
+
+	entry.RelatedTxid.WhenSome(func(txid chainhash.Hash) {
+		rpcEntry.RelatedTxid = txid.String()
+	})
+
+	return rpcEntry
+}
+
+// marshalJournalSummary converts a sweep journal summary into the RPC
+// summaries of each outcome, ordered by outcome, and the conflicted inputs,
+// ordered by their number of conflicts.
+func marshalJournalSummary(summary *sweep.JournalSummary) (
+	[]*SweepOutcomeSummary, []*ConflictedSweepInput) {
+
+	outcomes := make([]*SweepOutcomeSummary, 0, len(summary.Outcomes))
+	for outcome, outcomeSummary := range summary.Outcomes {
+		outcomes = append(outcomes, &SweepOutcomeSummary{
+			Outcome:     SweepOutcome(outcome),
+			Count:       uint32(outcomeSummary.Count),
+			TotalFeeSat: int64(outcomeSummary.TotalFee),
+		})
+	}
+	sort.Slice(outcomes, func(i, j int) bool {
+		return outcomes[i].Outcome < outcomes[j].Outcome
+	})
+
+	conflicts := make([]*ConflictedSweepInput, 0, len(summary.Conflicts))
+	for op, count := range summary.Conflicts {
+		conflicts = append(conflicts, &ConflictedSweepInput{
+			Outpoint:     lnrpc.MarshalOutPoint(&op),
+			NumConflicts: uint32(count),
+		})
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		if conflicts[i].NumConflicts != conflicts[j].NumConflicts {
+			return conflicts[i].NumConflicts >
+				conflicts[j].NumConflicts
+		}
+
+		opI, opJ := conflicts[i].Outpoint, conflicts[j].Outpoint
+		if opI.TxidStr != opJ.TxidStr {
+			return opI.TxidStr < opJ.TxidStr
+		}
+
+		return opI.OutputIndex < opJ.OutputIndex
+	})
+
+	return outcomes, conflicts
+}
@@ -0,0 +1,120 @@
+//go:build walletrpc
+// +build walletrpc
+
+package walletrpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/sweep"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUnmarshalJournalQuery tests that sweep journal requests are validated
+// and converted into journal queries.
+func TestUnmarshalJournalQuery(t *testing.T) {
+	t.Parallel()
+
+	query, err := unmarshalJournalQuery(&SweepJournalRequest{
+		IndexOffset: 5,
+		Outcomes: []SweepOutcome{
+			SweepOutcome_SWEEP_CONFLICTED,
+			SweepOutcome_SWEEP_ABANDONED,
+		},
+		StartHeight: 100,
+		EndHeight:   200,
+	})
+	require.NoError(t, err)
+	require.Equal(t, sweep.JournalQuery{
+		IndexOffset: 5,
+		MaxEntries:  maxSweepJournalEntries,
+		Outcomes: fn.NewSet(
+			sweep.SweepConflicted, sweep.SweepAbandoned,
+		),
+		StartHeight: 100,
+		EndHeight:   200,
+	}, query)
+
+	_, err = unmarshalJournalQuery(&SweepJournalRequest{
+		StartHeight: 200,
+		EndHeight:   100,
+	})
+	require.ErrorContains(t, err, "exceeds end height")
+
+	_, err = unmarshalJournalQuery(&SweepJournalRequest{
+		Outcomes: []SweepOutcome{4},
+	})
+	require.ErrorContains(t, err, "unknown sweep outcome")
+}
+
+// TestMarshalJournal tests that journal entries and their summary are
+// converted into their RPC representation.
+func TestMarshalJournal(t *testing.T) {
+	t.Parallel()
+
+	opA := wire.OutPoint{Hash: chainhash.Hash{1}, Index: 0}
+	opB := wire.OutPoint{Hash: chainhash.Hash{2}, Index: 1}
+	related := chainhash.Hash{3}
+
+	entries := []*sweep.JournalEntry{
+		{
+			Index:     1,
+			Txid:      chainhash.Hash{4},
+			Outcome:   sweep.SweepConflicted,
+			Fee:       1000,
+			FeeRate:   2500,
+			Inputs:    []wire.OutPoint{opA, opB},
+			Height:    100,
+			Timestamp: time.Unix(1000, 0),
+			Reason:    "input spent by third party",
+		},
+		{
+			Index:       2,
+			Txid:        chainhash.Hash{5},
+			Outcome:     sweep.SweepConflicted,
+			Fee:         1500,
+			Inputs:      []wire.OutPoint{opB},
+			RelatedTxid: fn.Some(related),
+		},
+		{
+			Index:   3,
+			Txid:    chainhash.Hash{6},
+			Outcome: sweep.SweepConfirmed,
+			Fee:     2000,
+		},
+	}
+
+	rpcEntry := marshalJournalEntry(entries[0])
+	require.Equal(t, SweepOutcome_SWEEP_CONFLICTED, rpcEntry.Outcome)
+	require.EqualValues(t, 1000, rpcEntry.FeeSat)
+	require.EqualValues(t, 2500, rpcEntry.SatPerKw)
+	require.Len(t, rpcEntry.Inputs, 2)
+	require.Equal(t, opB.Hash.String(), rpcEntry.Inputs[1].TxidStr)
+	require.EqualValues(t, 1000, rpcEntry.Timestamp)
+	require.Empty(t, rpcEntry.RelatedTxid)
+
+	rpcEntry = marshalJournalEntry(entries[1])
+	require.Equal(t, related.String(), rpcEntry.RelatedTxid)
+
+	outcomes, conflicts := marshalJournalSummary(
+		sweep.SummarizeJournal(entries),
+	)
+	require.Len(t, outcomes, 2)
+	require.Equal(t, SweepOutcome_SWEEP_CONFIRMED, outcomes[0].Outcome)
+	require.EqualValues(t, 1, outcomes[0].Count)
+	require.EqualValues(t, 2000, outcomes[0].TotalFeeSat)
+	require.Equal(t, SweepOutcome_SWEEP_CONFLICTED, outcomes[1].Outcome)
+	require.EqualValues(t, 2, outcomes[1].Count)
+	require.EqualValues(t, 2500, outcomes[1].TotalFeeSat)
+
+	// The input that was conflicted twice is listed first.
+	require.Len(t, conflicts, 2)
+	require.Equal(t, opB.Hash.String(), conflicts[0].Outpoint.TxidStr)
+	require.EqualValues(t, 2, conflicts[0].NumConflicts)
+	require.Equal(t, opA.Hash.String(), conflicts[1].Outpoint.TxidStr)
+	require.EqualValues(t, 1, conflicts[1].NumConflicts)
+}
@@ -279,6 +279,69 @@ type WalletKitClient interface {
 	// caller's responsibility to either publish the transaction on success or
 	// unlock/release any locked UTXOs in case of an error in this method.
 	FinalizePsbt(ctx context.Context, in *FinalizePsbtRequest, opts ...grpc.CallOption) (*FinalizePsbtResponse, error)
+	// lncli: `wallet fundingpreview`
+	// FundingPreview returns the inputs, change and fee the wallet would choose
+	// to fund an output of the given amount, without locking any coins or
+	// otherwise modifying the wallet. This lets applications show users what a
+	// channel open or send will do before committing to it. The preview is only
+	// accurate as long as the set of available coins doesn't change.
+	FundingPreview(ctx context.Context, in *FundingPreviewRequest, opts ...grpc.CallOption) (*FundingPreviewResponse, error)
+	// lncli: `wallet batchsend`
+	// BatchWalletSend builds, signs and publishes a single transaction paying to
+	// all the requested outputs. Compared to sending to each of them separately,
+	// this avoids fragmenting the wallet's coins and paying for multiple
+	// transactions. The coins to spend can be constrained through explicit
+	// include and exclude lists. The outpoint of each requested output is
+	// returned along with its label.
+	BatchWalletSend(ctx context.Context, in *BatchWalletSendRequest, opts ...grpc.CallOption) (*BatchWalletSendResponse, error)
+	// lncli: `wallet descriptors export`
+	// ExportDescriptors returns output descriptors covering the scripts of the
+	// wallet accounts and, optionally, the channel outputs paying to us. They can
+	// be imported into external watch-only wallets, such as bitcoind, to monitor
+	// the funds of the node. Sweeps pay to addresses of the default account, so
+	// they're covered by the account descriptors.
+	//
+	// NOTE: The descriptors don't contain any private keys, but they reveal all
+	// addresses of the wallet to whoever obtains them.
+	ExportDescriptors(ctx context.Context, in *ExportDescriptorsRequest, opts ...grpc.CallOption) (*ExportDescriptorsResponse, error)
+	// lncli: `wallet descriptors import`
+	// ImportDescriptors imports the given descriptors into the wallet, which is
+	// used to watch the funds described by descriptors exported from another
+	// wallet, for example after recovering a node. Ranged descriptors of an
+	// account key are imported as account and single key descriptors as public
+	// key. Address descriptors are skipped. All descriptors are validated before
+	// any of them is imported.
+	//
+	// NOTE: The wallet only finds past transactions of the imported keys after a
+	// rescan from a height before their first use.
+	ImportDescriptors(ctx context.Context, in *ImportDescriptorsRequest, opts ...grpc.CallOption) (*ImportDescriptorsResponse, error)
+	// lncli: `wallet sweepjournal`
+	// SweepJournal returns the recorded outcomes of past sweep attempts, in the
+	// order they were recorded. Each attempt is classified as confirmed,
+	// replaced, conflicted or abandoned. The response also summarizes the fees
+	// per outcome and lists the inputs of conflicted attempts, as inputs that
+	// are conflicted repeatedly may be the target of a pinning attack.
+	SweepJournal(ctx context.Context, in *SweepJournalRequest, opts ...grpc.CallOption) (*SweepJournalResponse, error)
+	// lncli: `wallet sweepfeereport`
+	// SweepFeeReport reports, for each of the given fee rates, the outcome of
+	// sweeping the inputs that are currently pending in the sweeper at that fee
+	// rate. The inputs are clustered into sets the same way they would be swept,
+	// but no transactions are created or published. This helps to decide
+	// whether the budgets of the pending inputs should be bumped.
+	SweepFeeReport(ctx context.Context, in *SweepFeeReportRequest, opts ...grpc.CallOption) (*SweepFeeReportResponse, error)
+	// lncli: `wallet getsignpolicy`
+	// GetSignPolicy returns the policy the internal signer enforces before it
+	// signs away the funds of the wallet. This call requires the dedicated
+	// signpolicy macaroon, which is not covered by the admin macaroon.
+	GetSignPolicy(ctx context.Context, in *GetSignPolicyRequest, opts ...grpc.CallOption) (*SignPolicy, error)
+	// lncli: `wallet setsignpolicy`
+	// SetSignPolicy replaces the policy the internal signer enforces before it
+	// signs away the funds of the wallet. Spends that were authorized before
+	// still count towards the velocity limit of the new policy. The policy is
+	// not persisted, it's reset to the configured policy on restart. This call
+	// requires the dedicated signpolicy macaroon, which is not covered by the
+	// admin macaroon.
+	SetSignPolicy(ctx context.Context, in *SignPolicy, opts ...grpc.CallOption) (*SetSignPolicyResponse, error)
 }
 
 type walletKitClient struct {
@@ -541,6 +604,78 @@ func (c *walletKitClient) FinalizePsbt(ctx context.Context, in *FinalizePsbtRequ
 	return out, nil
 }
 
+func (c *walletKitClient) FundingPreview(ctx context.Context, in *FundingPreviewRequest, opts ...grpc.CallOption) (*FundingPreviewResponse, error) {
+	out := new(FundingPreviewResponse)
+	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/FundingPreview", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletKitClient) BatchWalletSend(ctx context.Context, in *BatchWalletSendRequest, opts ...grpc.CallOption) (*BatchWalletSendResponse, error) {
+	out := new(BatchWalletSendResponse)
+	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/BatchWalletSend", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletKitClient) ExportDescriptors(ctx context.Context, in *ExportDescriptorsRequest, opts ...grpc.CallOption) (*ExportDescriptorsResponse, error) {
+	out := new(ExportDescriptorsResponse)
+	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/ExportDescriptors", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletKitClient) ImportDescriptors(ctx context.Context, in *ImportDescriptorsRequest, opts ...grpc.CallOption) (*ImportDescriptorsResponse, error) {
+	out := new(ImportDescriptorsResponse)
+	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/ImportDescriptors", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletKitClient) SweepJournal(ctx context.Context, in *SweepJournalRequest, opts ...grpc.CallOption) (*SweepJournalResponse, error) {
+	out := new(SweepJournalResponse)
+	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/SweepJournal", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletKitClient) SweepFeeReport(ctx context.Context, in *SweepFeeReportRequest, opts ...grpc.CallOption) (*SweepFeeReportResponse, error) {
+	out := new(SweepFeeReportResponse)
+	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/SweepFeeReport", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletKitClient) GetSignPolicy(ctx context.Context, in *GetSignPolicyRequest, opts ...grpc.CallOption) (*SignPolicy, error) {
+	out := new(SignPolicy)
+	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/GetSignPolicy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletKitClient) SetSignPolicy(ctx context.Context, in *SignPolicy, opts ...grpc.CallOption) (*SetSignPolicyResponse, error) {
+	out := new(SetSignPolicyResponse)
+	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/SetSignPolicy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // WalletKitServer is the server API for WalletKit service.
 // All implementations must embed UnimplementedWalletKitServer
 // for forward compatibility
@@ -804,6 +939,69 @@ type WalletKitServer interface {
 	// caller's responsibility to either publish the transaction on success or
 	// unlock/release any locked UTXOs in case of an error in this method.
 	FinalizePsbt(context.Context, *FinalizePsbtRequest) (*FinalizePsbtResponse, error)
+	// lncli: `wallet fundingpreview`
+	// FundingPreview returns the inputs, change and fee the wallet would choose
+	// to fund an output of the given amount, without locking any coins or
+	// otherwise modifying the wallet. This lets applications show users what a
+	// channel open or send will do before committing to it. The preview is only
+	// accurate as long as the set of available coins doesn't change.
+	FundingPreview(context.Context, *FundingPreviewRequest) (*FundingPreviewResponse, error)
+	// lncli: `wallet batchsend`
+	// BatchWalletSend builds, signs and publishes a single transaction paying to
+	// all the requested outputs. Compared to sending to each of them separately,
+	// this avoids fragmenting the wallet's coins and paying for multiple
+	// transactions. The coins to spend can be constrained through explicit
+	// include and exclude lists. The outpoint of each requested output is
+	// returned along with its label.
+	BatchWalletSend(context.Context, *BatchWalletSendRequest) (*BatchWalletSendResponse, error)
+	// lncli: `wallet descriptors export`
+	// ExportDescriptors returns output descriptors covering the scripts of the
+	// wallet accounts and, optionally, the channel outputs paying to us. They can
+	// be imported into external watch-only wallets, such as bitcoind, to monitor
+	// the funds of the node. Sweeps pay to addresses of the default account, so
+	// they're covered by the account descriptors.
+	//
+	// NOTE: The descriptors don't contain any private keys, but they reveal all
+	// addresses of the wallet to whoever obtains them.
+	ExportDescriptors(context.Context, *ExportDescriptorsRequest) (*ExportDescriptorsResponse, error)
+	// lncli: `wallet descriptors import`
+	// ImportDescriptors imports the given descriptors into the wallet, which is
+	// used to watch the funds described by descriptors exported from another
+	// wallet, for example after recovering a node. Ranged descriptors of an
+	// account key are imported as account and single key descriptors as public
+	// key. Address descriptors are skipped. All descriptors are validated before
+	// any of them is imported.
+	//
+	// NOTE: The wallet only finds past transactions of the imported keys after a
+	// rescan from a height before their first use.
+	ImportDescriptors(context.Context, *ImportDescriptorsRequest) (*ImportDescriptorsResponse, error)
+	// lncli: `wallet sweepjournal`
+	// SweepJournal returns the recorded outcomes of past sweep attempts, in the
+	// order they were recorded. Each attempt is classified as confirmed,
+	// replaced, conflicted or abandoned. The response also summarizes the fees
+	// per outcome and lists the inputs of conflicted attempts, as inputs that
+	// are conflicted repeatedly may be the target of a pinning attack.
+	SweepJournal(context.Context, *SweepJournalRequest) (*SweepJournalResponse, error)
+	// lncli: `wallet sweepfeereport`
+	// SweepFeeReport reports, for each of the given fee rates, the outcome of
+	// sweeping the inputs that are currently pending in the sweeper at that fee
+	// rate. The inputs are clustered into sets the same way they would be swept,
+	// but no transactions are created or published. This helps to decide
+	// whether the budgets of the pending inputs should be bumped.
+	SweepFeeReport(context.Context, *SweepFeeReportRequest) (*SweepFeeReportResponse, error)
+	// lncli: `wallet getsignpolicy`
+	// GetSignPolicy returns the policy the internal signer enforces before it
+	// signs away the funds of the wallet. This call requires the dedicated
+	// signpolicy macaroon, which is not covered by the admin macaroon.
+	GetSignPolicy(context.Context, *GetSignPolicyRequest) (*SignPolicy, error)
+	// lncli: `wallet setsignpolicy`
+	// SetSignPolicy replaces the policy the internal signer enforces before it
+	// signs away the funds of the wallet. Spends that were authorized before
+	// still count towards the velocity limit of the new policy. The policy is
+	// not persisted, it's reset to the configured policy on restart. This call
+	// requires the dedicated signpolicy macaroon, which is not covered by the
+	// admin macaroon.
+	SetSignPolicy(context.Context, *SignPolicy) (*SetSignPolicyResponse, error)
 	mustEmbedUnimplementedWalletKitServer()
 }
 
@@ -895,6 +1093,30 @@ func (UnimplementedWalletKitServer) SignPsbt(context.Context, *SignPsbtRequest)
 func (UnimplementedWalletKitServer) FinalizePsbt(context.Context, *FinalizePsbtRequest) (*FinalizePsbtResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FinalizePsbt not implemented")
 }
+func (UnimplementedWalletKitServer) FundingPreview(context.Context, *FundingPreviewRequest) (*FundingPreviewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FundingPreview not implemented")
+}
+func (UnimplementedWalletKitServer) BatchWalletSend(context.Context, *BatchWalletSendRequest) (*BatchWalletSendResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchWalletSend not implemented")
+}
+func (UnimplementedWalletKitServer) ExportDescriptors(context.Context, *ExportDescriptorsRequest) (*ExportDescriptorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportDescriptors not implemented")
+}
+func (UnimplementedWalletKitServer) ImportDescriptors(context.Context, *ImportDescriptorsRequest) (*ImportDescriptorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportDescriptors not implemented")
+}
+func (UnimplementedWalletKitServer) SweepJournal(context.Context, *SweepJournalRequest) (*SweepJournalResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SweepJournal not implemented")
+}
+func (UnimplementedWalletKitServer) SweepFeeReport(context.Context, *SweepFeeReportRequest) (*SweepFeeReportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SweepFeeReport not implemented")
+}
+func (UnimplementedWalletKitServer) GetSignPolicy(context.Context, *GetSignPolicyRequest) (*SignPolicy, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSignPolicy not implemented")
+}
+func (UnimplementedWalletKitServer) SetSignPolicy(context.Context, *SignPolicy) (*SetSignPolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetSignPolicy not implemented")
+}
 func (UnimplementedWalletKitServer) mustEmbedUnimplementedWalletKitServer() {}
 
 // UnsafeWalletKitServer may be embedded to opt out of forward compatibility for this service.
@@ -1412,6 +1634,150 @@ func _WalletKit_FinalizePsbt_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WalletKit_FundingPreview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FundingPreviewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletKitServer).FundingPreview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletKit/FundingPreview",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletKitServer).FundingPreview(ctx, req.(*FundingPreviewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletKit_BatchWalletSend_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchWalletSendRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletKitServer).BatchWalletSend(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletKit/BatchWalletSend",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletKitServer).BatchWalletSend(ctx, req.(*BatchWalletSendRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletKit_ExportDescriptors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportDescriptorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletKitServer).ExportDescriptors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletKit/ExportDescriptors",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletKitServer).ExportDescriptors(ctx, req.(*ExportDescriptorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletKit_ImportDescriptors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportDescriptorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletKitServer).ImportDescriptors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletKit/ImportDescriptors",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletKitServer).ImportDescriptors(ctx, req.(*ImportDescriptorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletKit_SweepJournal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SweepJournalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletKitServer).SweepJournal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletKit/SweepJournal",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletKitServer).SweepJournal(ctx, req.(*SweepJournalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletKit_SweepFeeReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SweepFeeReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletKitServer).SweepFeeReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletKit/SweepFeeReport",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletKitServer).SweepFeeReport(ctx, req.(*SweepFeeReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletKit_GetSignPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSignPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletKitServer).GetSignPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletKit/GetSignPolicy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletKitServer).GetSignPolicy(ctx, req.(*GetSignPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletKit_SetSignPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignPolicy)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletKitServer).SetSignPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletKit/SetSignPolicy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletKitServer).SetSignPolicy(ctx, req.(*SignPolicy))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // WalletKit_ServiceDesc is the grpc.ServiceDesc for WalletKit service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -1531,6 +1897,38 @@ var WalletKit_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "FinalizePsbt",
 			Handler:    _WalletKit_FinalizePsbt_Handler,
 		},
+		{
+			MethodName: "FundingPreview",
+			Handler:    _WalletKit_FundingPreview_Handler,
+		},
+		{
+			MethodName: "BatchWalletSend",
+			Handler:    _WalletKit_BatchWalletSend_Handler,
+		},
+		{
+			MethodName: "ExportDescriptors",
+			Handler:    _WalletKit_ExportDescriptors_Handler,
+		},
+		{
+			MethodName: "ImportDescriptors",
+			Handler:    _WalletKit_ImportDescriptors_Handler,
+		},
+		{
+			MethodName: "SweepJournal",
+			Handler:    _WalletKit_SweepJournal_Handler,
+		},
+		{
+			MethodName: "SweepFeeReport",
+			Handler:    _WalletKit_SweepFeeReport_Handler,
+		},
+		{
+			MethodName: "GetSignPolicy",
+			Handler:    _WalletKit_GetSignPolicy_Handler,
+		},
+		{
+			MethodName: "SetSignPolicy",
+			Handler:    _WalletKit_SetSignPolicy_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "walletrpc/walletkit.proto",
@@ -184,8 +184,60 @@ var (
 			Entity: "onchain",
 			Action: "write",
 		}},
+		"/walletrpc.WalletKit/FundingPreview": {{
+			Entity: "onchain",
+			Action: "read",
+		}},
+		"/walletrpc.WalletKit/BatchWalletSend": {{
+			Entity: "onchain",
+			Action: "write",
+		}},
+		"/walletrpc.WalletKit/ExportDescriptors": {{
+			Entity: "onchain",
+			Action: "read",
+		}},
+		"/walletrpc.WalletKit/ImportDescriptors": {{
+			Entity: "onchain",
+			Action: "write",
+		}},
+		"/walletrpc.WalletKit/SweepJournal": {{
+			Entity: "onchain",
+			Action: "read",
+		}},
+		"/walletrpc.WalletKit/SweepFeeReport": {{
+			Entity: "onchain",
+			Action: "read",
+		}},
+		"/walletrpc.WalletKit/GetSignPolicy": {{
+			Entity: "signpolicy",
+			Action: "read",
+		}},
+		"/walletrpc.WalletKit/SetSignPolicy": {{
+			Entity: "signpolicy",
+			Action: "write",
+		}},
+	}
+
+	// signPolicyMacaroonOps are the capabilities of the dedicated
+	// signpolicy macaroon. They are not part of any other macaroon lnd
+	// creates and can't be baked through the BakeMacaroon call, so a
+	// leaked admin macaroon can't be used to loosen the signing policy.
+	signPolicyMacaroonOps = []bakery.Op{
+		{
+			Entity: "signpolicy",
+			Action: "read",
+		},
+		{
+			Entity: "signpolicy",
+			Action: "write",
+		},
 	}
 
+	// DefaultSignPolicyMacFilename is the default name of the signpolicy
+	// macaroon that we expect to find via a file handle within the main
+	// configuration file in this package.
+	DefaultSignPolicyMacFilename = "signpolicy.macaroon"
+
 	// DefaultWalletKitMacFilename is the default name of the wallet kit
 	// macaroon that we expect to find via a file handle within the main
 	// configuration file in this package.
@@ -270,6 +322,12 @@ func New(cfg *Config) (*WalletKit, lnrpc.MacaroonPerms, error) {
 		)
 	}
 
+	if cfg.SignPolicyMacPath == "" {
+		cfg.SignPolicyMacPath = filepath.Join(
+			cfg.NetworkDir, DefaultSignPolicyMacFilename,
+		)
+	}
+
 	// Now that we know the full path of the wallet kit macaroon, we can
 	// check to see if we need to create it or not. If stateless_init is set
 	// then we don't write the macaroons.
@@ -283,20 +341,29 @@ func New(cfg *Config) (*WalletKit, lnrpc.MacaroonPerms, error) {
 		// At this point, we know that the wallet kit macaroon doesn't
 		// yet, exist, so we need to create it with the help of the
 		// main macaroon service.
-		walletKitMac, err := cfg.MacService.NewMacaroon(
-			context.Background(), macaroons.DefaultRootKeyID,
-			macaroonOps...,
+		err := saveMacaroon(
+			cfg.MacService, macFilePath, macaroonOps, 0644,
 		)
 		if err != nil {
 			return nil, nil, err
 		}
-		walletKitMacBytes, err := walletKitMac.M().MarshalBinary()
-		if err != nil {
-			return nil, nil, err
-		}
-		err = os.WriteFile(macFilePath, walletKitMacBytes, 0644)
+	}
+
+	// The signing policy can only be managed with its own macaroon, which
+	// we create the same way. It is only readable by the owner, as it
+	// guards the funds of the wallet.
+	macFilePath = cfg.SignPolicyMacPath
+	if cfg.SignPolicy != nil && cfg.MacService != nil &&
+		!cfg.MacService.StatelessInit &&
+		!lnrpc.FileExists(macFilePath) {
+
+		log.Infof("Baking signpolicy macaroon at: %v", macFilePath)
+
+		err := saveMacaroon(
+			cfg.MacService, macFilePath, signPolicyMacaroonOps,
+			0600,
+		)
 		if err != nil {
-			_ = os.Remove(macFilePath)
 			return nil, nil, err
 		}
 	}
@@ -308,6 +375,30 @@ func New(cfg *Config) (*WalletKit, lnrpc.MacaroonPerms, error) {
 	return walletKit, macPermissions, nil
 }
 
+// saveMacaroon bakes a macaroon with the given permissions and writes it to
+// the given file with the given file permissions.
+func saveMacaroon(svc *macaroons.Service, macFilePath string,
+	ops []bakery.Op, perm os.FileMode) error {
+
+	mac, err := svc.NewMacaroon(
+		context.Background(), macaroons.DefaultRootKeyID, ops...,
+	)
+	if err != nil {
+		return err
+	}
+	macBytes, err := mac.M().MarshalBinary()
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(macFilePath, macBytes, perm)
+	if err != nil {
+		_ = os.Remove(macFilePath)
+		return err
+	}
+
+	return nil
+}
+
 // Start launches any helper goroutines required for the sub-server to function.
 //
 // NOTE: This is part of the lnrpc.SubServer interface.
@@ -100,6 +100,25 @@ func (d *DummySigner) MuSig2Cleanup(input.MuSig2SessionID) error {
 	return nil
 }
 
+// MuSig2DeriveNonce deterministically derives the public nonce of the local
+// key identified by the key locator for the given derivation.
+func (d *DummySigner) MuSig2DeriveNonce(keychain.KeyLocator,
+	input.MuSig2NonceDerivation) ([musig2.PubNonceSize]byte, error) {
+
+	return [musig2.PubNonceSize]byte{}, nil
+}
+
+// MuSig2CreateDerivedSession creates a new MuSig2 signing session using the
+// local key identified by the key locator, with the local nonces derived for
+// the given derivation.
+func (d *DummySigner) MuSig2CreateDerivedSession(input.MuSig2Version,
+	keychain.KeyLocator, []*btcec.PublicKey, *input.MuSig2Tweaks,
+	[][musig2.PubNonceSize]byte,
+	input.MuSig2NonceDerivation) (*input.MuSig2SessionInfo, error) {
+
+	return nil, nil
+}
+
 // SingleSigner is an implementation of the Signer interface that signs
 // everything with a single private key.
 type SingleSigner struct {
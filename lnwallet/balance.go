@@ -0,0 +1,50 @@
+package lnwallet
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+)
+
+// BalanceObligations is a breakdown of the on-chain funds of the wallet that
+// need to stay available to keep our channels safe. Funds that are spent
+// beyond these obligations may leave us unable to fee bump the transactions
+// that resolve our channels.
+type BalanceObligations struct {
+	// AnchorReserve is the amount that is reserved to fee bump the
+	// commitment transactions of our anchor channels.
+	AnchorReserve btcutil.Amount
+
+	// SweepsInFlight is the amount of wallet funds that the sweeps which
+	// were already published may still use for fees.
+	SweepsInFlight btcutil.Amount
+
+	// CPFPObligations is the amount of wallet funds that the sweeps which
+	// weren't published yet may use for fees. These are mostly the CPFPs
+	// of our commitment transactions through their anchors.
+	CPFPObligations btcutil.Amount
+}
+
+// Total returns the sum of all obligations.
+func (b *BalanceObligations) Total() btcutil.Amount {
+	return b.AnchorReserve + b.SweepsInFlight + b.CPFPObligations
+}
+
+// Spendable returns the part of the given wallet balance that can be spent
+// without cutting into the obligations. It is zero if the balance doesn't
+// cover the obligations.
+func (b *BalanceObligations) Spendable(balance btcutil.Amount) btcutil.Amount {
+	total := b.Total()
+	if balance <= total {
+		return 0
+	}
+
+	return balance - total
+}
+
+// String returns a human-readable representation of the obligations.
+func (b *BalanceObligations) String() string {
+	return fmt.Sprintf("anchor_reserve=%v, sweeps_in_flight=%v, "+
+		"cpfp_obligations=%v", b.AnchorReserve, b.SweepsInFlight,
+		b.CPFPObligations)
+}
@@ -0,0 +1,55 @@
+package lnwallet
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBalanceObligationsSpendable tests that the spendable balance is the
+// balance net of all obligations, and never negative.
+func TestBalanceObligationsSpendable(t *testing.T) {
+	t.Parallel()
+
+	obligations := &BalanceObligations{
+		AnchorReserve:   10_000,
+		SweepsInFlight:  2_000,
+		CPFPObligations: 3_000,
+	}
+	require.Equal(t, btcutil.Amount(15_000), obligations.Total())
+
+	testCases := []struct {
+		name      string
+		balance   btcutil.Amount
+		spendable btcutil.Amount
+	}{
+		{
+			name:      "balance above obligations",
+			balance:   100_000,
+			spendable: 85_000,
+		},
+		{
+			name:      "balance equal to obligations",
+			balance:   15_000,
+			spendable: 0,
+		},
+		{
+			name:      "balance below obligations",
+			balance:   5_000,
+			spendable: 0,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(
+				t, testCase.spendable,
+				obligations.Spendable(testCase.balance),
+			)
+		})
+	}
+}
@@ -56,6 +56,12 @@ var (
 
 	// errEmptyCache is used when the fee rate cache is empty.
 	errEmptyCache = errors.New("fee rate cache is empty")
+
+	// ErrFeeRateBelowRelayFee is returned when a fee rate is below the
+	// minimum fee rate the backend currently requires for a transaction to
+	// be accepted to its mempool.
+	ErrFeeRateBelowRelayFee = errors.New("fee rate below min relay fee " +
+		"rate")
 )
 
 // Estimator provides the ability to estimate on-chain transaction fees for
@@ -81,6 +87,19 @@ type Estimator interface {
 	RelayFeePerKW() SatPerKWeight
 }
 
+// CheckRelayFee returns ErrFeeRateBelowRelayFee if a transaction paying the
+// given fee rate would be rejected by the backend's mempool, as the fee rate is
+// below the current minimum relay fee rate of the estimator.
+func CheckRelayFee(e Estimator, feeRate SatPerKWeight) error {
+	minFeeRate := e.RelayFeePerKW()
+	if feeRate < minFeeRate {
+		return fmt.Errorf("%w: got %v, minimum is %v",
+			ErrFeeRateBelowRelayFee, feeRate, minFeeRate)
+	}
+
+	return nil
+}
+
 // StaticEstimator will return a static value for all fee calculation requests.
 // It is designed to be replaced by a proper fee calculation implementation.
 // The fees are not accessible directly, because changing them would not be
@@ -206,6 +225,7 @@ func (b *BtcdEstimator) Start() error {
 		return err
 	}
 	b.minFeeManager = minRelayFeeManager
+	b.minFeeManager.start()
 
 	b.filterManager.Start()
 
@@ -235,6 +255,9 @@ func (b *BtcdEstimator) fetchMinRelayFee() (SatPerKWeight, error) {
 //
 // NOTE: This method is part of the Estimator interface.
 func (b *BtcdEstimator) Stop() error {
+	if b.minFeeManager != nil {
+		b.minFeeManager.stop()
+	}
 	b.filterManager.Stop()
 
 	b.btcdConn.Shutdown()
@@ -399,13 +422,14 @@ func (b *BitcoindEstimator) Start() error {
 	// initialise the minimum relay fee manager which will query
 	// the backend node for its minimum mempool fee.
 	relayFeeManager, err := newMinFeeManager(
-		defaultUpdateInterval,
+		mempoolMinFeeUpdateInterval,
 		b.fetchMinMempoolFee,
 	)
 	if err != nil {
 		return err
 	}
 	b.minFeeManager = relayFeeManager
+	b.minFeeManager.start()
 
 	b.filterManager.Start()
 
@@ -446,6 +470,9 @@ func (b *BitcoindEstimator) fetchMinMempoolFee() (SatPerKWeight, error) {
 //
 // NOTE: This method is part of the Estimator interface.
 func (b *BitcoindEstimator) Stop() error {
+	if b.minFeeManager != nil {
+		b.minFeeManager.stop()
+	}
 	b.filterManager.Stop()
 	return nil
 }
@@ -5,17 +5,35 @@ import (
 	"time"
 )
 
-const defaultUpdateInterval = 10 * time.Minute
+const (
+	// defaultUpdateInterval is the interval at which the static minimum
+	// relay fee of the backend is refreshed.
+	defaultUpdateInterval = 10 * time.Minute
+
+	// mempoolMinFeeUpdateInterval is the interval at which the minimum
+	// mempool fee of the backend is refreshed. The mempool min fee rises
+	// quickly once the backend's mempool is full, so it's polled more
+	// often than the static relay fee.
+	mempoolMinFeeUpdateInterval = time.Minute
+)
 
 // minFeeManager is used to store and update the minimum fee that is required
 // by a transaction to be accepted to the mempool. The minFeeManager ensures
-// that the backend used to fetch the fee is not queried too regularly.
+// that the backend used to fetch the fee is not queried too regularly. Once
+// started, it also polls the backend continuously, such that a changed fee
+// floor is picked up even if no caller asks for the fee.
 type minFeeManager struct {
 	mu                sync.Mutex
 	minFeePerKW       SatPerKWeight
 	lastUpdatedTime   time.Time
 	minUpdateInterval time.Duration
 	fetchFeeFunc      fetchFee
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+
+	wg   sync.WaitGroup
+	quit chan struct{}
 }
 
 // fetchFee represents a function that can be used to fetch a fee.
@@ -43,9 +61,50 @@ func newMinFeeManager(minUpdateInterval time.Duration,
 		lastUpdatedTime:   time.Now(),
 		minUpdateInterval: minUpdateInterval,
 		fetchFeeFunc:      fetchMinFee,
+		quit:              make(chan struct{}),
 	}, nil
 }
 
+// start launches the goroutine that polls the backend for its minimum fee.
+func (m *minFeeManager) start() {
+	m.startOnce.Do(func() {
+		m.wg.Add(1)
+		go m.pollMinFee()
+	})
+}
+
+// stop stops polling the backend for its minimum fee.
+func (m *minFeeManager) stop() {
+	m.stopOnce.Do(func() {
+		close(m.quit)
+		m.wg.Wait()
+	})
+}
+
+// pollMinFee refreshes the minimum fee every minUpdateInterval until the
+// manager is stopped.
+func (m *minFeeManager) pollMinFee() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(m.minUpdateInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.mu.Lock()
+			sinceUpdate := time.Since(m.lastUpdatedTime)
+			if sinceUpdate >= m.minUpdateInterval {
+				m.updateMinFee()
+			}
+			m.mu.Unlock()
+
+		case <-m.quit:
+			return
+		}
+	}
+}
+
 // fetchMinFee returns the stored minFeePerKW if it has been updated recently
 // or if the call to the chain backend fails. Otherwise, it sets the stored
 // minFeePerKW to the fee returned from the backend and floors it based on
@@ -58,25 +117,39 @@ func (m *minFeeManager) fetchMinFee() SatPerKWeight {
 		return m.minFeePerKW
 	}
 
+	m.updateMinFee()
+
+	return m.minFeePerKW
+}
+
+// updateMinFee queries the chain backend for its current minimum fee and
+// stores it, floored by our fee floor. If the query fails, the last known min
+// fee is kept.
+//
+// NOTE: The caller must hold the mutex.
+func (m *minFeeManager) updateMinFee() {
 	newMinFee, err := m.fetchFeeFunc()
 	if err != nil {
 		log.Errorf("Unable to fetch updated min fee from chain "+
 			"backend. Using last known min fee instead: %v", err)
 
-		return m.minFeePerKW
+		return
 	}
 
 	// By default, we'll use the backend node's minimum fee as the
 	// minimum fee rate we'll propose for transactions. However, if this
 	// happens to be lower than our fee floor, we'll enforce that instead.
-	m.minFeePerKW = newMinFee
-	if m.minFeePerKW < FeePerKwFloor {
-		m.minFeePerKW = FeePerKwFloor
+	if newMinFee < FeePerKwFloor {
+		newMinFee = FeePerKwFloor
 	}
 	m.lastUpdatedTime = time.Now()
 
+	if newMinFee != m.minFeePerKW {
+		log.Infof("Minimum fee rate of chain backend changed from "+
+			"%v to %v", m.minFeePerKW, newMinFee)
+	}
+	m.minFeePerKW = newMinFee
+
 	log.Debugf("Using minimum fee rate of %v sat/kw",
 		int64(m.minFeePerKW))
-
-	return m.minFeePerKW
 }
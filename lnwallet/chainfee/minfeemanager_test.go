@@ -1,6 +1,7 @@
 package chainfee
 
 import (
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -57,3 +58,35 @@ func TestMinFeeManager(t *testing.T) {
 	require.Equal(t, SatPerKWeight(2000), minFee)
 	require.Equal(t, 2, chainBackend.callCount)
 }
+
+// TestMinFeeManagerPolling tests that a started minFeeManager picks up a
+// changed min fee from the chain backend without being asked for it.
+func TestMinFeeManagerPolling(t *testing.T) {
+	t.Parallel()
+
+	var minFee atomic.Int64
+	minFee.Store(int64(FeePerKwFloor))
+	fetchFee := func() (SatPerKWeight, error) {
+		return SatPerKWeight(minFee.Load()), nil
+	}
+
+	const updateInterval = 10 * time.Millisecond
+	feeManager, err := newMinFeeManager(updateInterval, fetchFee)
+	require.NoError(t, err)
+
+	feeManager.start()
+	t.Cleanup(feeManager.stop)
+
+	// Raise the min fee of the backend, e.g. because its mempool filled
+	// up.
+	minFee.Store(2000)
+
+	// The stored min fee is read directly, so only the poller can have
+	// updated it.
+	require.Eventually(t, func() bool {
+		feeManager.mu.Lock()
+		defer feeManager.mu.Unlock()
+
+		return feeManager.minFeePerKW == 2000
+	}, time.Second, updateInterval)
+}
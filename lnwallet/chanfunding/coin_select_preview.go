@@ -0,0 +1,85 @@
+package chanfunding
+
+import (
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/wallet"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+)
+
+// CoinSelectPreview describes the transaction a coin selection would result
+// in.
+type CoinSelectPreview struct {
+	// Inputs are the coins that would be spent.
+	Inputs []wallet.Coin
+
+	// ChangeAmt is the value of the change output, zero if no change
+	// output would be created.
+	ChangeAmt btcutil.Amount
+
+	// Fee is the total fee the transaction would pay.
+	Fee btcutil.Amount
+}
+
+// PreviewCoinSelect runs the same coin selection as CoinSelect and returns the
+// inputs, change and fee it results in. Since it only operates on the passed
+// coins, nothing is locked and the wallet isn't modified, so the preview is
+// only accurate as long as the set of available coins doesn't change.
+func PreviewCoinSelect(feeRate chainfee.SatPerKWeight, amt,
+	dustLimit btcutil.Amount, coins []wallet.Coin,
+	strategy wallet.CoinSelectionStrategy,
+	existingWeight input.TxWeightEstimator,
+	changeType ChangeAddressType) (*CoinSelectPreview, error) {
+
+	selected, changeAmt, err := CoinSelect(
+		feeRate, amt, dustLimit, coins, strategy, existingWeight,
+		changeType,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var totalIn btcutil.Amount
+	for _, coin := range selected {
+		totalIn += btcutil.Amount(coin.Value)
+	}
+
+	return &CoinSelectPreview{
+		Inputs:    selected,
+		ChangeAmt: changeAmt,
+		Fee:       totalIn - amt - changeAmt,
+	}, nil
+}
+
+// FilterCoins returns the coins that are eligible for coin selection. If
+// include is non-empty, only the coins spending one of its outpoints are
+// returned. Coins spending one of the outpoints in exclude are never returned.
+func FilterCoins(coins []wallet.Coin, include,
+	exclude []wire.OutPoint) []wallet.Coin {
+
+	toSet := func(outpoints []wire.OutPoint) map[wire.OutPoint]struct{} {
+		set := make(map[wire.OutPoint]struct{}, len(outpoints))
+		for _, op := range outpoints {
+			set[op] = struct{}{}
+		}
+
+		return set
+	}
+	includeSet, excludeSet := toSet(include), toSet(exclude)
+
+	filtered := make([]wallet.Coin, 0, len(coins))
+	for _, coin := range coins {
+		if _, ok := excludeSet[coin.OutPoint]; ok {
+			continue
+		}
+
+		if _, ok := includeSet[coin.OutPoint]; len(include) > 0 && !ok {
+			continue
+		}
+
+		filtered = append(filtered, coin)
+	}
+
+	return filtered
+}
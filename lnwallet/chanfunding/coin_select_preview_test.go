@@ -0,0 +1,89 @@
+package chanfunding
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/wallet"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPreviewCoinSelect tests that the coin selection preview reports the
+// selected inputs, change and fee consistently.
+func TestPreviewCoinSelect(t *testing.T) {
+	t.Parallel()
+
+	const (
+		feeRate   = chainfee.SatPerKWeight(1000)
+		dustLimit = btcutil.Amount(330)
+		amt       = btcutil.Amount(500_000)
+	)
+
+	coins := []wallet.Coin{
+		{
+			TxOut: wire.TxOut{
+				PkScript: p2wkhScript,
+				Value:    int64(btcutil.SatoshiPerBitcoin),
+			},
+			OutPoint: wire.OutPoint{Index: 0},
+		},
+	}
+
+	var estimator input.TxWeightEstimator
+	estimator.AddP2WSHOutput()
+
+	preview, err := PreviewCoinSelect(
+		feeRate, amt, dustLimit, coins, wallet.CoinSelectionLargest,
+		estimator, defaultChanFundingChangeType,
+	)
+	require.NoError(t, err)
+	require.Equal(t, coins, preview.Inputs)
+
+	expectedFee := fundingFee(feeRate, 1, true)
+	require.Equal(t, expectedFee, preview.Fee)
+	require.Equal(
+		t, btcutil.SatoshiPerBitcoin-amt-expectedFee,
+		preview.ChangeAmt,
+	)
+
+	// Excluding the only coin leaves nothing to select from.
+	_, err = PreviewCoinSelect(
+		feeRate, amt, dustLimit,
+		FilterCoins(coins, nil, []wire.OutPoint{coins[0].OutPoint}),
+		wallet.CoinSelectionLargest, estimator,
+		defaultChanFundingChangeType,
+	)
+	require.ErrorAs(t, err, new(*ErrInsufficientFunds))
+}
+
+// TestFilterCoins tests that coins are included and excluded as requested.
+func TestFilterCoins(t *testing.T) {
+	t.Parallel()
+
+	coins := make([]wallet.Coin, 3)
+	for i := range coins {
+		coins[i].OutPoint = wire.OutPoint{Index: uint32(i)}
+	}
+	op := func(i uint32) wire.OutPoint {
+		return wire.OutPoint{Index: i}
+	}
+
+	require.Equal(t, coins, FilterCoins(coins, nil, nil))
+	require.Equal(
+		t, coins[1:2], FilterCoins(coins, []wire.OutPoint{op(1)}, nil),
+	)
+	require.Equal(
+		t, []wallet.Coin{coins[0], coins[2]},
+		FilterCoins(coins, nil, []wire.OutPoint{op(1)}),
+	)
+	require.Equal(
+		t, coins[2:3],
+		FilterCoins(
+			coins, []wire.OutPoint{op(1), op(2)},
+			[]wire.OutPoint{op(1)},
+		),
+	)
+}
@@ -9353,6 +9353,15 @@ func (lc *LightningChannel) MarkDataLoss(commitPoint *btcec.PublicKey) error {
 	return lc.channelState.MarkDataLoss(commitPoint)
 }
 
+// MarkRemoteDataLoss sets the channel status to RemoteDataLoss, recording
+// that the remote party lost channel state.
+func (lc *LightningChannel) MarkRemoteDataLoss() error {
+	lc.Lock()
+	defer lc.Unlock()
+
+	return lc.channelState.MarkRemoteDataLoss()
+}
+
 // ActiveHtlcs returns a slice of HTLC's which are currently active on *both*
 // commitment transactions.
 func (lc *LightningChannel) ActiveHtlcs() []channeldb.HTLC {
@@ -9362,6 +9371,28 @@ func (lc *LightningChannel) ActiveHtlcs() []channeldb.HTLC {
 	return lc.channelState.ActiveHtlcs()
 }
 
+// HtlcSlots returns the number of HTLCs offered by the given party that are
+// still in its update log, and the maximum number of HTLCs the other party
+// accepts from it. The used slots include HTLCs that aren't committed yet, as
+// well as HTLCs that are in the process of being removed.
+func (lc *LightningChannel) HtlcSlots(
+	whoseUpdates lntypes.ChannelParty) (uint16, uint16) {
+
+	lc.RLock()
+	defer lc.RUnlock()
+
+	used := len(lc.updateLogs.GetForParty(whoseUpdates).htlcIndex)
+
+	// The HTLCs we offer are limited by the remote party's config, and
+	// vice versa.
+	maxAccepted := lc.channelState.RemoteChanCfg.MaxAcceptedHtlcs
+	if whoseUpdates.IsRemote() {
+		maxAccepted = lc.channelState.LocalChanCfg.MaxAcceptedHtlcs
+	}
+
+	return uint16(used), maxAccepted
+}
+
 // LocalChanReserve returns our local ChanReserve requirement for the remote party.
 func (lc *LightningChannel) LocalChanReserve() btcutil.Amount {
 	return lc.channelState.LocalChanCfg.ChanReserve
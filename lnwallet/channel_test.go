@@ -9409,6 +9409,45 @@ func TestMayAddOutgoingHtlc(t *testing.T) {
 	require.NoError(t, aliceChannel.MayAddOutgoingHtlc(0))
 }
 
+// TestHtlcSlots tests that the HTLC slots used by each party are counted as
+// soon as an HTLC is added, and that they're limited by the other party's max
+// accepted HTLCs.
+func TestHtlcSlots(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, err := CreateTestChannels(
+		t, channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err)
+
+	aliceChannel.channelState.LocalChanCfg.MaxAcceptedHtlcs = 10
+	aliceChannel.channelState.RemoteChanCfg.MaxAcceptedHtlcs = 20
+
+	used, maxAccepted := aliceChannel.HtlcSlots(lntypes.Local)
+	require.Zero(t, used)
+	require.EqualValues(t, 20, maxAccepted)
+
+	used, maxAccepted = aliceChannel.HtlcSlots(lntypes.Remote)
+	require.Zero(t, used)
+	require.EqualValues(t, 10, maxAccepted)
+
+	// An HTLC uses a slot before it's committed.
+	htlc, _ := createHTLC(0, lnwire.MilliSatoshi(10_000_000))
+	_, err = aliceChannel.AddHTLC(htlc, nil)
+	require.NoError(t, err)
+	_, err = bobChannel.ReceiveHTLC(htlc)
+	require.NoError(t, err)
+
+	used, _ = aliceChannel.HtlcSlots(lntypes.Local)
+	require.EqualValues(t, 1, used)
+
+	used, _ = bobChannel.HtlcSlots(lntypes.Remote)
+	require.EqualValues(t, 1, used)
+
+	used, _ = bobChannel.HtlcSlots(lntypes.Local)
+	require.Zero(t, used)
+}
+
 // TestIsChannelClean tests that IsChannelClean returns the expected values
 // in different channel states.
 func TestIsChannelClean(t *testing.T) {
@@ -11250,3 +11289,78 @@ func TestCreateCooperativeCloseTx(t *testing.T) {
 		})
 	}
 }
+
+// TestCommitmentFeeInfo tests that the commitment fee info reports the
+// current weight, fee and dust HTLCs of a commitment, and how they change at
+// an alternative fee rate.
+func TestCommitmentFeeInfo(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, err := CreateTestChannels(
+		t, channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err, "unable to create test channels")
+
+	chanState := aliceChannel.channelState
+	dustLimit := chanState.LocalChanCfg.DustLimit
+	feeRate := chainfee.SatPerKWeight(chanState.LocalCommitment.FeePerKw)
+	highFeeRate := feeRate * 10
+
+	// Add an HTLC that is dust at the current fee rate, and one that only
+	// becomes dust at the higher fee rate. As Alice offers the HTLCs, a
+	// timeout transaction is used on her commitment.
+	timeoutFee := HtlcTimeoutFee(chanState.ChanType, feeRate)
+	amounts := []btcutil.Amount{
+		dustLimit + timeoutFee - 1,
+		dustLimit + 5*timeoutFee,
+	}
+	for i, amt := range amounts {
+		htlc, _ := createHTLC(i, lnwire.NewMSatFromSatoshis(amt))
+		_, err := aliceChannel.AddHTLC(htlc, nil)
+		require.NoError(t, err)
+		_, err = bobChannel.ReceiveHTLC(htlc)
+		require.NoError(t, err)
+	}
+	require.NoError(t, ForceStateTransition(aliceChannel, bobChannel))
+
+	info := aliceChannel.CommitmentFeeInfo(lntypes.Local, highFeeRate)
+	commit := aliceChannel.channelState.LocalCommitment
+	require.Equal(t, commit.CommitHeight, info.CommitHeight)
+
+	// At the current fee rate, only the first HTLC is dust.
+	current := info.Current
+	require.Equal(t, feeRate, current.FeeRate)
+	require.Equal(t, commit.CommitFee, current.Fee)
+	require.Equal(
+		t, CommitWeight(chanState.ChanType)+input.HTLCWeight,
+		current.Weight,
+	)
+	require.Len(t, current.DustHtlcs, 1)
+	require.Equal(t, amounts[0], current.DustHtlcAmount)
+	require.Equal(
+		t, commit.LocalBalance.ToSatoshis(), current.InitiatorBalance,
+	)
+	require.True(t, current.AboveReserve)
+
+	// At the higher fee rate, both HTLCs are dust and the initiator pays
+	// the higher fee.
+	require.Len(t, info.Alternatives, 1)
+	alt := info.Alternatives[0]
+	require.Equal(t, highFeeRate, alt.FeeRate)
+	require.Equal(t, CommitWeight(chanState.ChanType), alt.Weight)
+	require.Equal(t, highFeeRate.FeeForWeight(alt.Weight), alt.Fee)
+	require.Len(t, alt.DustHtlcs, 2)
+	require.Equal(t, amounts[0]+amounts[1], alt.DustHtlcAmount)
+	require.Equal(
+		t, commit.LocalBalance.ToSatoshis()+commit.CommitFee-alt.Fee,
+		alt.InitiatorBalance,
+	)
+
+	// Bob isn't the initiator, but should see the same values on his
+	// remote commitment.
+	bobInfo := bobChannel.CommitmentFeeInfo(lntypes.Remote, highFeeRate)
+	require.Equal(t, current.Weight, bobInfo.Current.Weight)
+	require.Equal(t, current.Fee, bobInfo.Current.Fee)
+	require.Equal(t, alt.Fee, bobInfo.Alternatives[0].Fee)
+	require.Len(t, bobInfo.Alternatives[0].DustHtlcs, 2)
+}
@@ -0,0 +1,141 @@
+package lnwallet
+
+import (
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+)
+
+// CommitmentFeeEstimate describes the weight and fee of a commitment
+// transaction at a particular fee rate.
+type CommitmentFeeEstimate struct {
+	// FeeRate is the fee rate the estimate is based on.
+	FeeRate chainfee.SatPerKWeight
+
+	// Weight is the weight of the commitment transaction that is used to
+	// calculate its fee. It only accounts for HTLCs that aren't dust at
+	// the fee rate.
+	Weight lntypes.WeightUnit
+
+	// Fee is the fee of the commitment transaction.
+	Fee btcutil.Amount
+
+	// DustHtlcs is the set of HTLCs that are trimmed from the commitment
+	// transaction at the fee rate, as their value wouldn't cover the fee
+	// of their second-level transaction.
+	DustHtlcs []channeldb.HTLC
+
+	// DustHtlcAmount is the total value of the dust HTLCs, which is added
+	// to the fee of the commitment transaction.
+	DustHtlcAmount btcutil.Amount
+
+	// InitiatorBalance is the balance of the channel initiator after
+	// paying the fee, not accounting for the channel reserve. A negative
+	// value means the initiator can't afford the fee rate.
+	InitiatorBalance btcutil.Amount
+
+	// AboveReserve is true if the initiator's balance after paying the fee
+	// is still above its channel reserve.
+	AboveReserve bool
+}
+
+// CommitmentFeeInfo describes the current fee situation of a commitment
+// transaction, and how it would change at alternative fee rates.
+type CommitmentFeeInfo struct {
+	// WhoseCommit denotes whose commitment transaction is described.
+	WhoseCommit lntypes.ChannelParty
+
+	// CommitHeight is the height of the commitment transaction.
+	CommitHeight uint64
+
+	// Current describes the commitment transaction at its current fee
+	// rate.
+	Current CommitmentFeeEstimate
+
+	// Alternatives describe the commitment transaction at each of the
+	// requested alternative fee rates.
+	Alternatives []CommitmentFeeEstimate
+}
+
+// CommitmentFeeInfo returns the weight, fee and set of dust HTLCs of the
+// current local or remote commitment transaction, along with the same values
+// at each of the passed alternative fee rates. This can be used to reason
+// about the impact of an update_fee, or the cost of a force close.
+func (lc *LightningChannel) CommitmentFeeInfo(
+	whoseCommit lntypes.ChannelParty,
+	feeRates ...chainfee.SatPerKWeight) *CommitmentFeeInfo {
+
+	lc.RLock()
+	defer lc.RUnlock()
+
+	chanState := lc.channelState
+	commit := chanState.LocalCommitment
+	dustLimit := chanState.LocalChanCfg.DustLimit
+	if whoseCommit.IsRemote() {
+		commit = chanState.RemoteCommitment
+		dustLimit = chanState.RemoteChanCfg.DustLimit
+	}
+
+	// The fee is paid by the initiator, so we add the current fee back to
+	// its balance to determine its balance at other fee rates.
+	initiatorBalance := commit.RemoteBalance.ToSatoshis()
+	initiatorReserve := chanState.RemoteChanCfg.ChanReserve
+	if chanState.IsInitiator {
+		initiatorBalance = commit.LocalBalance.ToSatoshis()
+		initiatorReserve = chanState.LocalChanCfg.ChanReserve
+	}
+	initiatorBalance += commit.CommitFee
+
+	estimate := func(
+		feeRate chainfee.SatPerKWeight) CommitmentFeeEstimate {
+
+		est := CommitmentFeeEstimate{
+			FeeRate: feeRate,
+			Weight:  CommitWeight(chanState.ChanType),
+		}
+
+		for _, htlc := range commit.Htlcs {
+			amt := htlc.Amt.ToSatoshis()
+			if HtlcIsDust(
+				chanState.ChanType, htlc.Incoming, whoseCommit,
+				feeRate, amt, dustLimit,
+			) {
+
+				est.DustHtlcs = append(est.DustHtlcs, htlc)
+				est.DustHtlcAmount += amt
+
+				continue
+			}
+
+			est.Weight += input.HTLCWeight
+		}
+
+		est.Fee = feeRate.FeeForWeight(est.Weight)
+		est.InitiatorBalance = initiatorBalance - est.Fee
+		est.AboveReserve = est.InitiatorBalance >= initiatorReserve
+
+		return est
+	}
+
+	info := &CommitmentFeeInfo{
+		WhoseCommit:  whoseCommit,
+		CommitHeight: commit.CommitHeight,
+		Current:      estimate(chainfee.SatPerKWeight(commit.FeePerKw)),
+		Alternatives: make([]CommitmentFeeEstimate, 0, len(feeRates)),
+	}
+
+	// The fee of the current commitment is the one that was actually
+	// committed to, so we use it instead of the estimated one.
+	info.Current.Fee = commit.CommitFee
+	info.Current.InitiatorBalance = initiatorBalance - commit.CommitFee
+	info.Current.AboveReserve = info.Current.InitiatorBalance >=
+		initiatorReserve
+
+	for _, feeRate := range feeRates {
+		info.Alternatives = append(info.Alternatives, estimate(feeRate))
+	}
+
+	return info
+}
@@ -71,4 +71,8 @@ type Config struct {
 	// AuxSigner is an optional signer that can be used to sign auxiliary
 	// leaves for certain custom channel types.
 	AuxSigner fn.Option[AuxSigner]
+
+	// FundingPolicy is an optional policy that is consulted before the
+	// funding transaction of a channel is signed.
+	FundingPolicy fn.Option[FundingPolicy]
 }
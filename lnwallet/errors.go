@@ -21,6 +21,48 @@ type ReservationError struct {
 // interface.
 var _ error = (*ReservationError)(nil)
 
+// Unwrap returns the underlying error, which allows callers to inspect a
+// ConstraintError carried by the reservation error.
+func (r ReservationError) Unwrap() error {
+	return r.error
+}
+
+// ConstraintError is returned when a commitment parameter proposed during the
+// funding negotiation is outside of the bounds we accept. Violation allows
+// callers to react to the rejection reason without parsing the message.
+type ConstraintError struct {
+	// Violation is the reason the parameter was rejected.
+	Violation ConstraintViolation
+
+	// Err describes the rejected value and the bound it violates.
+	Err error
+}
+
+// Error returns the description of the rejected value.
+//
+// NOTE: This is part of the error interface.
+func (c *ConstraintError) Error() string {
+	return c.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (c *ConstraintError) Unwrap() error {
+	return c.Err
+}
+
+// newConstraintError returns a ReservationError for the given constraint
+// violation, so it is sent to the remote peer when we fail the funding flow.
+func newConstraintError(violation ConstraintViolation,
+	err error) ReservationError {
+
+	return ReservationError{
+		&ConstraintError{
+			Violation: violation,
+			Err:       err,
+		},
+	}
+}
+
 // ErrZeroCapacity returns an error indicating the funder attempted to put zero
 // funds into the channel.
 func ErrZeroCapacity() ReservationError {
@@ -53,29 +95,32 @@ func ErrFunderBalanceDust(commitFee, funderBalance,
 // ErrCsvDelayTooLarge returns an error indicating that the CSV delay was to
 // large to be accepted, along with the current max.
 func ErrCsvDelayTooLarge(remoteDelay, maxDelay uint16) ReservationError {
-	return ReservationError{
+	return newConstraintError(
+		ViolationCsvDelayTooLarge,
 		fmt.Errorf("CSV delay too large: %v, max is %v",
 			remoteDelay, maxDelay),
-	}
+	)
 }
 
 // ErrChanReserveTooSmall returns an error indicating that the channel reserve
 // the remote is requiring is too small to be accepted.
 func ErrChanReserveTooSmall(reserve, dustLimit btcutil.Amount) ReservationError {
-	return ReservationError{
+	return newConstraintError(
+		ViolationChanReserveTooSmall,
 		fmt.Errorf("channel reserve of %v sat is too small, min is %v "+
 			"sat", int64(reserve), int64(dustLimit)),
-	}
+	)
 }
 
 // ErrChanReserveTooLarge returns an error indicating that the chan reserve the
 // remote is requiring, is too large to be accepted.
 func ErrChanReserveTooLarge(reserve,
 	maxReserve btcutil.Amount) ReservationError {
-	return ReservationError{
+	return newConstraintError(
+		ViolationChanReserveTooLarge,
 		fmt.Errorf("channel reserve is too large: %v sat, max "+
 			"is %v sat", int64(reserve), int64(maxReserve)),
-	}
+	)
 }
 
 // ErrNonZeroPushAmount is returned by a remote peer that receives a
@@ -89,38 +134,42 @@ func ErrNonZeroPushAmount() ReservationError {
 // remote required is too large to be accepted.
 func ErrMinHtlcTooLarge(minHtlc,
 	maxMinHtlc lnwire.MilliSatoshi) ReservationError {
-	return ReservationError{
+	return newConstraintError(
+		ViolationMinHtlcTooLarge,
 		fmt.Errorf("minimum HTLC value is too large: %v, max is %v",
 			minHtlc, maxMinHtlc),
-	}
+	)
 }
 
 // ErrMaxHtlcNumTooLarge returns an error indicating that the 'max HTLCs in
 // flight' value the remote required is too large to be accepted.
 func ErrMaxHtlcNumTooLarge(maxHtlc, maxMaxHtlc uint16) ReservationError {
-	return ReservationError{
+	return newConstraintError(
+		ViolationMaxHtlcNumTooLarge,
 		fmt.Errorf("maxHtlcs is too large: %d, max is %d",
 			maxHtlc, maxMaxHtlc),
-	}
+	)
 }
 
 // ErrMaxHtlcNumTooSmall returns an error indicating that the 'max HTLCs in
 // flight' value the remote required is too small to be accepted.
 func ErrMaxHtlcNumTooSmall(maxHtlc, minMaxHtlc uint16) ReservationError {
-	return ReservationError{
+	return newConstraintError(
+		ViolationMaxHtlcNumTooSmall,
 		fmt.Errorf("maxHtlcs is too small: %d, min is %d",
 			maxHtlc, minMaxHtlc),
-	}
+	)
 }
 
 // ErrMaxValueInFlightTooSmall returns an error indicating that the 'max HTLC
 // value in flight' the remote required is too small to be accepted.
 func ErrMaxValueInFlightTooSmall(maxValInFlight,
 	minMaxValInFlight lnwire.MilliSatoshi) ReservationError {
-	return ReservationError{
+	return newConstraintError(
+		ViolationMaxValueInFlightTooSmall,
 		fmt.Errorf("maxValueInFlight too small: %v, min is %v",
 			maxValInFlight, minMaxValInFlight),
-	}
+	)
 }
 
 // ErrNumConfsTooLarge returns an error indicating that the number of
@@ -155,9 +204,22 @@ func ErrChanTooLarge(chanSize, maxChanSize btcutil.Amount) ReservationError {
 // ErrInvalidDustLimit returns an error indicating that a proposed DustLimit
 // was rejected.
 func ErrInvalidDustLimit(dustLimit btcutil.Amount) ReservationError {
-	return ReservationError{
+	return newConstraintError(
+		ViolationInvalidDustLimit,
 		fmt.Errorf("dust limit %v is invalid", dustLimit),
-	}
+	)
+}
+
+// ErrDustLimitTooLarge returns an error indicating that the DustLimit the
+// remote proposed is above the maximum we accept.
+func ErrDustLimitTooLarge(dustLimit,
+	maxDustLimit btcutil.Amount) ReservationError {
+
+	return newConstraintError(
+		ViolationDustLimitTooLarge,
+		fmt.Errorf("dust limit is too large: %v, max is %v",
+			dustLimit, maxDustLimit),
+	)
 }
 
 // ErrHtlcIndexAlreadyFailed is returned when the HTLC index has already been
@@ -600,6 +600,15 @@ type MessageSigner interface {
 		doubleHash bool) (*ecdsa.Signature, error)
 }
 
+// FundingPolicy is an optional policy that decides whether the wallet may
+// fund a channel with a given peer.
+type FundingPolicy interface {
+	// AuthorizeFunding is called right before the funding transaction of
+	// a channel with the given peer, paying to the given funding output
+	// script, is signed. A non-nil error aborts the funding flow.
+	AuthorizeFunding(peer *btcec.PublicKey, pkScript []byte) error
+}
+
 // AddrWithKey wraps a normal addr, but also includes the internal key for the
 // delivery addr if known.
 type AddrWithKey struct {
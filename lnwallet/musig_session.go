@@ -265,25 +265,96 @@ func NewPartialMusigSession(verificationNonce musig2.Nonces,
 	}
 }
 
+// NewClosingMusigSession creates a new musig2 session that'll be used to sign
+// the co-op close transaction. Unlike the commitment sessions, the local nonce
+// is sent before the nonce of the remote party is known, so the session is
+// created right away, letting the signer generate the local nonce. The remote
+// nonce is then bound to the session with RegisterRemoteNonce.
+func NewClosingMusigSession(localKey, remoteKey keychain.KeyDescriptor,
+	signer input.MuSig2Signer, inputTxOut *wire.TxOut,
+	tapscriptTweak fn.Option[input.MuSig2Tweaks]) (*MusigSession, error) {
+
+	m := &MusigSession{
+		remoteKey:  remoteKey,
+		localKey:   localKey,
+		inputTxOut: inputTxOut,
+		signerKeys: []*btcec.PublicKey{
+			localKey.PubKey, remoteKey.PubKey,
+		},
+		signer:         signer,
+		commitType:     RemoteMusigCommit,
+		tapscriptTweak: tapscriptTweak,
+	}
+
+	if err := m.createSession(nil, nil); err != nil {
+		return nil, err
+	}
+
+	m.nonces.SigningNonce = musig2.Nonces{
+		PubNonce: m.session.PublicNonce,
+	}
+
+	return m, nil
+}
+
+// RegisterRemoteNonce binds the nonce of the remote party to a session that
+// was created with NewClosingMusigSession. Once this method returns, the
+// session can be used to sign.
+func (m *MusigSession) RegisterRemoteNonce(remoteNonce musig2.Nonces) error {
+	if m.session == nil {
+		return ErrSessionNotFinalized
+	}
+
+	m.nonces.VerificationNonce = remoteNonce
+
+	haveAllNonces, err := m.signer.MuSig2RegisterNonces(
+		m.session.SessionID,
+		[][musig2.PubNonceSize]byte{remoteNonce.PubNonce},
+	)
+	if err != nil {
+		return err
+	}
+	if !haveAllNonces {
+		return fmt.Errorf("session %x is still missing nonces",
+			m.session.SessionID[:])
+	}
+
+	return m.combineNonces()
+}
+
+// LocalNonce returns the public nonce that we'll use to sign with this
+// session. It's only known once the session has been created.
+func (m *MusigSession) LocalNonce() (*musig2.Nonces, error) {
+	if m.session == nil {
+		return nil, ErrSessionNotFinalized
+	}
+
+	return &musig2.Nonces{
+		PubNonce: m.session.PublicNonce,
+	}, nil
+}
+
 // FinalizeSession finalizes the session given the signer nonce.  This is
 // called before signing or verifying a new commitment.
 func (m *MusigSession) FinalizeSession(signingNonce musig2.Nonces) error {
+	return m.finalizeSession(&signingNonce)
+}
+
+// finalizeSession finalizes the session given the signer nonce. If no signing
+// nonce is passed for the remote commitment, then the signer generates it
+// when the session is created, so the secret nonce never leaves the signer.
+func (m *MusigSession) finalizeSession(signingNonce *musig2.Nonces) error {
 	var (
-		localNonce, remoteNonce musig2.Nonces
-		err                     error
+		localNonce  *musig2.Nonces
+		remoteNonce musig2.Nonces
 	)
 
-	// First, we'll stash the freshly generated signing nonce. Depending on
-	// who's commitment we're handling, this'll either be our generated
-	// nonce, or the one we just got from the remote party.
-	m.nonces.SigningNonce = signingNonce
-
 	switch m.commitType {
 	// If we're making a session for the remote commitment, then the nonce
 	// we use to sign is actually will be the signing nonce for the
 	// session, and their nonce the verification nonce.
 	case RemoteMusigCommit:
-		localNonce = m.nonces.SigningNonce
+		localNonce = signingNonce
 		remoteNonce = m.nonces.VerificationNonce
 
 	// Otherwise, we're generating/receiving a signature for our local
@@ -291,22 +362,59 @@ func (m *MusigSession) FinalizeSession(signingNonce musig2.Nonces) error {
 	// we've already generated, and we want to bind their new signing
 	// nonce.
 	case LocalMusigCommit:
-		localNonce = m.nonces.VerificationNonce
-		remoteNonce = m.nonces.SigningNonce
+		if signingNonce == nil {
+			return fmt.Errorf("remote signing nonce not set")
+		}
+
+		localNonce = &m.nonces.VerificationNonce
+		remoteNonce = *signingNonce
+	}
+
+	err := m.createSession(
+		localNonce, [][musig2.PubNonceSize]byte{remoteNonce.PubNonce},
+	)
+	if err != nil {
+		return err
+	}
+
+	// Next, we'll stash the signing nonce. Depending on who's commitment
+	// we're handling, this'll either be our freshly generated nonce, or
+	// the one we just got from the remote party. If the signer generated
+	// our nonce, then we only know the public part of it.
+	switch {
+	case signingNonce != nil:
+		m.nonces.SigningNonce = *signingNonce
+
+	default:
+		m.nonces.SigningNonce = musig2.Nonces{
+			PubNonce: m.session.PublicNonce,
+		}
 	}
 
+	return m.combineNonces()
+}
+
+// createSession creates the backing musig2 session with the signer. If no
+// local nonce is passed, then the signer will generate it.
+func (m *MusigSession) createSession(localNonce *musig2.Nonces,
+	otherNonces [][musig2.PubNonceSize]byte) error {
+
 	tweakDesc := m.tapscriptTweak.UnwrapOr(input.MuSig2Tweaks{
 		TaprootBIP0086Tweak: true,
 	})
+
+	var err error
 	m.session, err = m.signer.MuSig2CreateSession(
 		input.MuSig2Version100RC2, m.localKey.KeyLocator, m.signerKeys,
-		&tweakDesc, [][musig2.PubNonceSize]byte{remoteNonce.PubNonce},
-		&localNonce,
+		&tweakDesc, otherNonces, localNonce,
 	)
-	if err != nil {
-		return err
-	}
 
+	return err
+}
+
+// combineNonces aggregates the signing and verification nonces of the
+// session.
+func (m *MusigSession) combineNonces() error {
 	// We'll need the raw combined nonces later to be able to verify
 	// partial signatures, and also combine partial signatures, so we'll
 	// generate it now ourselves.
@@ -315,7 +423,7 @@ func (m *MusigSession) FinalizeSession(signingNonce musig2.Nonces) error {
 		m.nonces.VerificationNonce.PubNonce,
 	})
 	if err != nil {
-		return nil
+		return err
 	}
 
 	m.combinedNonce = aggNonce
@@ -347,18 +455,12 @@ func (m *MusigSession) SignCommit(tx *wire.MsgTx) (*MusigPartialSig, error) {
 	// If we already have a session, then we don't need to finalize as this
 	// was done up front (symmetric nonce case, like for co-op close).
 	case m.session == nil && m.commitType == RemoteMusigCommit:
-		// Before we can sign a new commitment, we'll need to generate
-		// a fresh nonce that'll be sent along side our signature. With
-		// the nonce in hand, we can finalize the session.
-		txHash := tx.TxHash()
-		signingNonce, err := musig2.GenNonces(
-			musig2.WithPublicKey(m.localKey.PubKey),
-			musig2.WithNonceAuxInput(txHash[:]),
-		)
-		if err != nil {
-			return nil, err
-		}
-		if err := m.FinalizeSession(*signingNonce); err != nil {
+		// Before we can sign a new commitment, we'll need a fresh
+		// nonce that'll be sent along side our signature. We let the
+		// signer generate it while finalizing the session, so the
+		// secret nonce never leaves the signer, which may be a remote
+		// signer instance.
+		if err := m.finalizeSession(nil); err != nil {
 			return nil, err
 		}
 
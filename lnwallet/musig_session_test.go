@@ -6,6 +6,7 @@ import (
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/schnorr/musig2"
 	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/fn"
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/stretchr/testify/require"
@@ -271,3 +272,72 @@ func TestMusigSesssion(t *testing.T) {
 		require.ErrorIs(t, err, ErrSessionNotFinalized)
 	})
 }
+
+// TestClosingMusigSession tests that two parties are able to create a co-op
+// close signature with sessions whose local nonces are generated by the
+// signer, before the nonce of the remote party is known.
+func TestClosingMusigSession(t *testing.T) {
+	t.Parallel()
+
+	alicePriv, alicePub := btcec.PrivKeyFromBytes(testWalletPrivKey)
+	aliceSigner := input.NewMockSigner([]*btcec.PrivateKey{alicePriv}, nil)
+	aliceKey := keychain.KeyDescriptor{PubKey: alicePub}
+
+	bobPriv, bobPub := btcec.PrivKeyFromBytes(bobsPrivKey)
+	bobSigner := input.NewMockSigner([]*btcec.PrivateKey{bobPriv}, nil)
+	bobKey := keychain.KeyDescriptor{PubKey: bobPub}
+
+	inputTxOut := &wire.TxOut{
+		Value:    1000,
+		PkScript: testHdSeed[:],
+	}
+
+	closeTx := wire.NewMsgTx(2)
+	closeTx.AddTxIn(&wire.TxIn{})
+
+	// Both parties create their session first, which gives them the nonce
+	// they'll send to the other party.
+	noTweak := fn.None[input.MuSig2Tweaks]()
+	aliceSession, err := NewClosingMusigSession(
+		aliceKey, bobKey, aliceSigner, inputTxOut, noTweak,
+	)
+	require.NoError(t, err)
+	bobSession, err := NewClosingMusigSession(
+		bobKey, aliceKey, bobSigner, inputTxOut, noTweak,
+	)
+	require.NoError(t, err)
+
+	// We can't sign before the nonce of the remote party is known.
+	_, err = aliceSession.SignCommit(closeTx)
+	require.Error(t, err)
+
+	aliceNonce, err := aliceSession.LocalNonce()
+	require.NoError(t, err)
+	bobNonce, err := bobSession.LocalNonce()
+	require.NoError(t, err)
+
+	require.NoError(t, aliceSession.RegisterRemoteNonce(*bobNonce))
+	require.NoError(t, bobSession.RegisterRemoteNonce(*aliceNonce))
+
+	aliceSig, err := aliceSession.SignCommit(closeTx)
+	require.NoError(t, err)
+	bobSig, err := bobSession.SignCommit(closeTx)
+	require.NoError(t, err)
+
+	sigHash, err := taprootKeyspendSighash(
+		closeTx, inputTxOut.PkScript, inputTxOut.Value,
+	)
+	require.NoError(t, err)
+
+	require.True(t, aliceSig.Verify(sigHash, alicePub))
+	require.True(t, bobSig.Verify(sigHash, bobPub))
+
+	// Finally, the partial signatures are combined into a signature that
+	// is valid for the combined key. The session already holds our own
+	// partial signature, so only the remote one is passed in.
+	finalSig, err := aliceSession.CombineSigs(bobSig.sig)
+	require.NoError(t, err)
+	require.True(
+		t, finalSig.Verify(sigHash, aliceSession.session.CombinedKey),
+	)
+}
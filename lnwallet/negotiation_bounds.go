@@ -0,0 +1,176 @@
+package lnwallet
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/input"
+)
+
+const (
+	// DefaultMinMaxAcceptedHtlcs is the smallest max_accepted_htlcs value
+	// the remote party may set for our commitment by default. If this is
+	// too small, we cannot offer many HTLCs to the remote.
+	DefaultMinMaxAcceptedHtlcs = 5
+
+	// DefaultMaxChanReservePercent is the largest channel reserve, as a
+	// percentage of the channel capacity, the remote party may require us
+	// to keep by default.
+	DefaultMaxChanReservePercent = 20
+)
+
+// ConstraintViolation describes why a commitment parameter proposed during the
+// funding negotiation was rejected.
+type ConstraintViolation uint8
+
+const (
+	// ViolationCsvDelayTooLarge is used when the to_self_delay for our
+	// funds exceeds our maximum.
+	ViolationCsvDelayTooLarge ConstraintViolation = iota + 1
+
+	// ViolationChanReserveTooSmall is used when the channel reserve is
+	// below the dust limit.
+	ViolationChanReserveTooSmall
+
+	// ViolationChanReserveTooLarge is used when the channel reserve
+	// exceeds the share of the capacity we accept.
+	ViolationChanReserveTooLarge
+
+	// ViolationInvalidDustLimit is used when the dust limit is below the
+	// dust limit of the largest witness script.
+	ViolationInvalidDustLimit
+
+	// ViolationDustLimitTooLarge is used when the dust limit exceeds our
+	// maximum.
+	ViolationDustLimitTooLarge
+
+	// ViolationMinHtlcTooLarge is used when the minimum HTLC value exceeds
+	// the maximum value in flight.
+	ViolationMinHtlcTooLarge
+
+	// ViolationMaxHtlcNumTooLarge is used when max_accepted_htlcs exceeds
+	// the limit of BOLT #2.
+	ViolationMaxHtlcNumTooLarge
+
+	// ViolationMaxHtlcNumTooSmall is used when max_accepted_htlcs is below
+	// our minimum.
+	ViolationMaxHtlcNumTooSmall
+
+	// ViolationMaxValueInFlightTooSmall is used when the maximum value in
+	// flight doesn't allow for enough HTLCs of the minimum value.
+	ViolationMaxValueInFlightTooSmall
+)
+
+// String returns a human-readable name of the violation.
+func (v ConstraintViolation) String() string {
+	switch v {
+	case ViolationCsvDelayTooLarge:
+		return "CsvDelayTooLarge"
+
+	case ViolationChanReserveTooSmall:
+		return "ChanReserveTooSmall"
+
+	case ViolationChanReserveTooLarge:
+		return "ChanReserveTooLarge"
+
+	case ViolationInvalidDustLimit:
+		return "InvalidDustLimit"
+
+	case ViolationDustLimitTooLarge:
+		return "DustLimitTooLarge"
+
+	case ViolationMinHtlcTooLarge:
+		return "MinHtlcTooLarge"
+
+	case ViolationMaxHtlcNumTooLarge:
+		return "MaxHtlcNumTooLarge"
+
+	case ViolationMaxHtlcNumTooSmall:
+		return "MaxHtlcNumTooSmall"
+
+	case ViolationMaxValueInFlightTooSmall:
+		return "MaxValueInFlightTooSmall"
+
+	default:
+		return fmt.Sprintf("ConstraintViolation(%d)", uint8(v))
+	}
+}
+
+// NegotiationBounds holds the limits we enforce on the commitment parameters
+// the remote party dictates for our commitment during the funding negotiation.
+// Zero values fall back to the defaults.
+type NegotiationBounds struct {
+	// MinMaxAcceptedHtlcs is the smallest max_accepted_htlcs value we
+	// accept.
+	MinMaxAcceptedHtlcs uint16
+
+	// MaxChanReservePercent is the largest channel reserve we accept, as
+	// a percentage of the channel capacity.
+	MaxChanReservePercent uint8
+
+	// MaxDustLimit is the largest dust limit we accept.
+	MaxDustLimit btcutil.Amount
+}
+
+// DefaultMaxDustLimit returns the largest dust limit we accept by default,
+// which is three times the dust limit of the largest witness script.
+func DefaultMaxDustLimit() btcutil.Amount {
+	return 3 * DustLimitForSize(input.UnknownWitnessSize)
+}
+
+// minMaxAcceptedHtlcs returns the configured minimum max_accepted_htlcs, or
+// the default if none is set.
+func (b *NegotiationBounds) minMaxAcceptedHtlcs() uint16 {
+	if b == nil || b.MinMaxAcceptedHtlcs == 0 {
+		return DefaultMinMaxAcceptedHtlcs
+	}
+
+	return b.MinMaxAcceptedHtlcs
+}
+
+// maxChanReserve returns the largest channel reserve we accept for a channel
+// of the given capacity.
+func (b *NegotiationBounds) maxChanReserve(
+	capacity btcutil.Amount) btcutil.Amount {
+
+	percent := btcutil.Amount(DefaultMaxChanReservePercent)
+	if b != nil && b.MaxChanReservePercent != 0 {
+		percent = btcutil.Amount(b.MaxChanReservePercent)
+	}
+
+	return capacity * percent / 100
+}
+
+// maxDustLimit returns the configured maximum dust limit, or the default if
+// none is set.
+func (b *NegotiationBounds) maxDustLimit() btcutil.Amount {
+	if b == nil || b.MaxDustLimit == 0 {
+		return DefaultMaxDustLimit()
+	}
+
+	return b.MaxDustLimit
+}
+
+// Validate checks that the bounds don't contradict the limits of BOLT #2 and
+// the dust limits we require ourselves.
+func (b *NegotiationBounds) Validate() error {
+	maxHtlcs := uint16(input.MaxHTLCNumber / 2)
+	if b.MinMaxAcceptedHtlcs > maxHtlcs {
+		return fmt.Errorf("minimum max accepted htlcs of %d exceeds "+
+			"the maximum of %d", b.MinMaxAcceptedHtlcs, maxHtlcs)
+	}
+
+	if b.MaxChanReservePercent > 100 {
+		return fmt.Errorf("maximum channel reserve of %d%% exceeds "+
+			"100%%", b.MaxChanReservePercent)
+	}
+
+	minDustLimit := DustLimitForSize(input.UnknownWitnessSize)
+	if b.MaxDustLimit != 0 && b.MaxDustLimit < minDustLimit {
+		return fmt.Errorf("maximum dust limit of %v is below the "+
+			"minimum dust limit of %v", b.MaxDustLimit,
+			minDustLimit)
+	}
+
+	return nil
+}
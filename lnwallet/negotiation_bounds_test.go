@@ -0,0 +1,186 @@
+package lnwallet
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestVerifyConstraintsNegotiationBounds tests that the channel constraints
+// are checked against the configured negotiation bounds, and that violations
+// are reported with their typed reason.
+func TestVerifyConstraintsNegotiationBounds(t *testing.T) {
+	t.Parallel()
+
+	const (
+		capacity    = btcutil.Amount(1_000_000)
+		maxCSVDelay = 2016
+	)
+
+	minDustLimit := DustLimitForSize(input.UnknownWitnessSize)
+
+	validBounds := func() *channeldb.ChannelStateBounds {
+		return &channeldb.ChannelStateBounds{
+			ChanReserve:      capacity / 100,
+			MaxPendingAmount: lnwire.NewMSatFromSatoshis(capacity),
+			MinHTLC:          1,
+			MaxAcceptedHtlcs: 483,
+		}
+	}
+	validParams := func() *channeldb.CommitmentParams {
+		return &channeldb.CommitmentParams{
+			DustLimit: minDustLimit,
+			CsvDelay:  144,
+		}
+	}
+
+	tests := []struct {
+		name   string
+		limits *NegotiationBounds
+		modify func(*channeldb.ChannelStateBounds,
+			*channeldb.CommitmentParams)
+		violation ConstraintViolation
+	}{
+		{
+			name: "valid with default bounds",
+			modify: func(*channeldb.ChannelStateBounds,
+				*channeldb.CommitmentParams) {
+			},
+		},
+		{
+			name: "csv delay too large",
+			modify: func(_ *channeldb.ChannelStateBounds,
+				p *channeldb.CommitmentParams) {
+
+				p.CsvDelay = maxCSVDelay + 1
+			},
+			violation: ViolationCsvDelayTooLarge,
+		},
+		{
+			name: "default max reserve exceeded",
+			modify: func(b *channeldb.ChannelStateBounds,
+				_ *channeldb.CommitmentParams) {
+
+				b.ChanReserve = capacity/5 + 1
+			},
+			violation: ViolationChanReserveTooLarge,
+		},
+		{
+			name: "custom max reserve exceeded",
+			limits: &NegotiationBounds{
+				MaxChanReservePercent: 2,
+			},
+			modify: func(b *channeldb.ChannelStateBounds,
+				_ *channeldb.CommitmentParams) {
+
+				b.ChanReserve = capacity/50 + 1
+			},
+			violation: ViolationChanReserveTooLarge,
+		},
+		{
+			name: "custom max reserve respected",
+			limits: &NegotiationBounds{
+				MaxChanReservePercent: 2,
+			},
+			modify: func(b *channeldb.ChannelStateBounds,
+				_ *channeldb.CommitmentParams) {
+
+				b.ChanReserve = capacity / 50
+			},
+		},
+		{
+			name: "default max dust limit exceeded",
+			modify: func(b *channeldb.ChannelStateBounds,
+				p *channeldb.CommitmentParams) {
+
+				p.DustLimit = DefaultMaxDustLimit() + 1
+				b.ChanReserve = p.DustLimit
+			},
+			violation: ViolationDustLimitTooLarge,
+		},
+		{
+			name: "custom max dust limit exceeded",
+			limits: &NegotiationBounds{
+				MaxDustLimit: minDustLimit,
+			},
+			modify: func(_ *channeldb.ChannelStateBounds,
+				p *channeldb.CommitmentParams) {
+
+				p.DustLimit = minDustLimit + 1
+			},
+			violation: ViolationDustLimitTooLarge,
+		},
+		{
+			name: "dust limit too small",
+			modify: func(_ *channeldb.ChannelStateBounds,
+				p *channeldb.CommitmentParams) {
+
+				p.DustLimit = minDustLimit - 1
+			},
+			violation: ViolationInvalidDustLimit,
+		},
+		{
+			name: "custom min max accepted htlcs",
+			limits: &NegotiationBounds{
+				MinMaxAcceptedHtlcs: 30,
+			},
+			modify: func(b *channeldb.ChannelStateBounds,
+				_ *channeldb.CommitmentParams) {
+
+				b.MaxAcceptedHtlcs = 29
+			},
+			violation: ViolationMaxHtlcNumTooSmall,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bounds, params := validBounds(), validParams()
+			test.modify(bounds, params)
+
+			err := VerifyConstraints(
+				bounds, params, maxCSVDelay, test.limits,
+				capacity,
+			)
+			if test.violation == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			// The error must still be sent to the remote peer, and
+			// carry the reason it was rejected for.
+			require.IsType(t, ReservationError{}, err)
+
+			var constraintErr *ConstraintError
+			require.True(t, errors.As(err, &constraintErr))
+			require.Equal(
+				t, test.violation, constraintErr.Violation,
+			)
+		})
+	}
+}
+
+// TestNegotiationBoundsValidate tests that contradicting negotiation bounds
+// are rejected.
+func TestNegotiationBoundsValidate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, (&NegotiationBounds{}).Validate())
+
+	require.Error(t, (&NegotiationBounds{
+		MinMaxAcceptedHtlcs: input.MaxHTLCNumber/2 + 1,
+	}).Validate())
+
+	require.Error(t, (&NegotiationBounds{
+		MaxChanReservePercent: 101,
+	}).Validate())
+
+	require.Error(t, (&NegotiationBounds{
+		MaxDustLimit: DustLimitForSize(input.UnknownWitnessSize) - 1,
+	}).Validate())
+}
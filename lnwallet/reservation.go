@@ -569,12 +569,13 @@ func (r *ChannelReservation) IsTaproot() bool {
 // the type of commitments that we can generate for them. These constraints
 // include several parameters that serve as flow control restricting the amount
 // of satoshis that can be transferred in a single commitment. This function
-// will also attempt to verify the constraints for sanity, returning an error
-// if the parameters are seemed unsound.
+// will also attempt to verify the constraints for sanity against the passed
+// negotiation limits, returning an error if the parameters are seemed unsound.
+// A nil set of limits uses the defaults.
 func (r *ChannelReservation) CommitConstraints(
 	bounds *channeldb.ChannelStateBounds,
 	commitParams *channeldb.CommitmentParams,
-	maxLocalCSVDelay uint16,
+	maxLocalCSVDelay uint16, limits *NegotiationBounds,
 	responder bool) error {
 
 	r.Lock()
@@ -582,7 +583,8 @@ func (r *ChannelReservation) CommitConstraints(
 
 	// First, verify the sanity of the channel constraints.
 	err := VerifyConstraints(
-		bounds, commitParams, maxLocalCSVDelay, r.partialState.Capacity,
+		bounds, commitParams, maxLocalCSVDelay, limits,
+		r.partialState.Capacity,
 	)
 	if err != nil {
 		return err
@@ -921,10 +923,12 @@ func (r *ChannelReservation) CommitmentKeyRings() lntypes.Dual[CommitmentKeyRing
 }
 
 // VerifyConstraints is a helper function that can be used to check the sanity
-// of various channel constraints.
+// of various channel constraints. The limits configure the bounds we accept for
+// the max accepted htlcs, the channel reserve and the dust limit. A nil set of
+// limits uses the defaults.
 func VerifyConstraints(bounds *channeldb.ChannelStateBounds,
 	commitParams *channeldb.CommitmentParams, maxLocalCSVDelay uint16,
-	channelCapacity btcutil.Amount) error {
+	limits *NegotiationBounds, channelCapacity btcutil.Amount) error {
 
 	// Fail if the csv delay for our funds exceeds our maximum.
 	if commitParams.CsvDelay > maxLocalCSVDelay {
@@ -944,15 +948,20 @@ func VerifyConstraints(bounds *channeldb.ChannelStateBounds,
 	// Validate against the maximum-sized witness script dust limit, and
 	// also ensure that the DustLimit is not too large.
 	maxWitnessLimit := DustLimitForSize(input.UnknownWitnessSize)
-	if commitParams.DustLimit < maxWitnessLimit ||
-		commitParams.DustLimit > 3*maxWitnessLimit {
-
+	if commitParams.DustLimit < maxWitnessLimit {
 		return ErrInvalidDustLimit(commitParams.DustLimit)
 	}
 
-	// Fail if we consider the channel reserve to be too large.  We
-	// currently fail if it is greater than 20% of the channel capacity.
-	maxChanReserve := channelCapacity / 5
+	maxDustLimit := limits.maxDustLimit()
+	if commitParams.DustLimit > maxDustLimit {
+		return ErrDustLimitTooLarge(
+			commitParams.DustLimit, maxDustLimit,
+		)
+	}
+
+	// Fail if we consider the channel reserve to be too large. By default
+	// we fail if it is greater than 20% of the channel capacity.
+	maxChanReserve := limits.maxChanReserve(channelCapacity)
 	if bounds.ChanReserve > maxChanReserve {
 		return ErrChanReserveTooLarge(
 			bounds.ChanReserve, maxChanReserve,
@@ -979,7 +988,7 @@ func VerifyConstraints(bounds *channeldb.ChannelStateBounds,
 
 	// Fail if we consider maxHtlcs too small. If this is too small we
 	// cannot offer many HTLCs to the remote.
-	const minNumHtlc = 5
+	minNumHtlc := limits.minMaxAcceptedHtlcs()
 	if bounds.MaxAcceptedHtlcs < minNumHtlc {
 		return ErrMaxHtlcNumTooSmall(
 			bounds.MaxAcceptedHtlcs, minNumHtlc,
@@ -988,9 +997,10 @@ func VerifyConstraints(bounds *channeldb.ChannelStateBounds,
 
 	// Fail if we consider maxValueInFlight too small. We currently require
 	// the remote to at least allow minNumHtlc * minHtlc in flight.
-	if bounds.MaxPendingAmount < minNumHtlc*bounds.MinHTLC {
+	minMaxValue := lnwire.MilliSatoshi(minNumHtlc) * bounds.MinHTLC
+	if bounds.MaxPendingAmount < minMaxValue {
 		return ErrMaxValueInFlightTooSmall(
-			bounds.MaxPendingAmount, minNumHtlc*bounds.MinHTLC,
+			bounds.MaxPendingAmount, minMaxValue,
 		)
 	}
 
@@ -662,6 +662,88 @@ func (r *RPCKeyRing) MuSig2CreateSession(bipVersion input.MuSig2Version,
 	tweaks *input.MuSig2Tweaks, otherNonces [][musig2.PubNonceSize]byte,
 	localNonces *musig2.Nonces) (*input.MuSig2SessionInfo, error) {
 
+	req, err := muSig2SessionRequest(
+		bipVersion, keyLoc, pubKeys, tweaks, otherNonces,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if localNonces != nil {
+		req.PregeneratedLocalNonce = localNonces.SecNonce[:]
+	}
+
+	return r.createMuSig2Session(bipVersion, tweaks, req)
+}
+
+// MuSig2CreateDerivedSession creates a new MuSig2 signing session using the
+// local key identified by the key locator, with the local nonces derived by
+// the remote signer for the given derivation.
+func (r *RPCKeyRing) MuSig2CreateDerivedSession(
+	bipVersion input.MuSig2Version, keyLoc keychain.KeyLocator,
+	pubKeys []*btcec.PublicKey, tweaks *input.MuSig2Tweaks,
+	otherNonces [][musig2.PubNonceSize]byte,
+	derivation input.MuSig2NonceDerivation) (*input.MuSig2SessionInfo,
+	error) {
+
+	req, err := muSig2SessionRequest(
+		bipVersion, keyLoc, pubKeys, tweaks, otherNonces,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	req.NonceDerivation = signrpc.MarshalMuSig2NonceDerivation(derivation)
+
+	return r.createMuSig2Session(bipVersion, tweaks, req)
+}
+
+// MuSig2DeriveNonce deterministically derives the public nonce of the local
+// key identified by the key locator for the given derivation. The secret nonce
+// never leaves the remote signer.
+func (r *RPCKeyRing) MuSig2DeriveNonce(keyLoc keychain.KeyLocator,
+	derivation input.MuSig2NonceDerivation) ([musig2.PubNonceSize]byte,
+	error) {
+
+	var pubNonce [musig2.PubNonceSize]byte
+
+	ctxt, cancel := context.WithTimeout(context.Background(), r.rpcTimeout)
+	defer cancel()
+
+	resp, err := r.signerClient.MuSig2DeriveNonce(
+		ctxt, &signrpc.MuSig2DeriveNonceRequest{
+			KeyLoc: &signrpc.KeyLocator{
+				KeyFamily: int32(keyLoc.Family),
+				KeyIndex:  int32(keyLoc.Index),
+			},
+			NonceDerivation: signrpc.MarshalMuSig2NonceDerivation(
+				derivation,
+			),
+		},
+	)
+	if err != nil {
+		considerShutdown(err)
+		return pubNonce, fmt.Errorf("error deriving MuSig2 nonce in "+
+			"remote signer instance: %v", err)
+	}
+
+	if len(resp.PublicNonce) != musig2.PubNonceSize {
+		return pubNonce, fmt.Errorf("invalid public nonce length %d",
+			len(resp.PublicNonce))
+	}
+	copy(pubNonce[:], resp.PublicNonce)
+
+	return pubNonce, nil
+}
+
+// muSig2SessionRequest serializes the common parameters of a MuSig2 session
+// into a session request for the remote signer.
+func muSig2SessionRequest(bipVersion input.MuSig2Version,
+	keyLoc keychain.KeyLocator, pubKeys []*btcec.PublicKey,
+	tweaks *input.MuSig2Tweaks,
+	otherNonces [][musig2.PubNonceSize]byte) (*signrpc.MuSig2SessionRequest,
+	error) {
+
 	apiVersion, err := signrpc.MarshalMuSig2Version(bipVersion)
 	if err != nil {
 		return nil, err
@@ -709,9 +791,14 @@ func (r *RPCKeyRing) MuSig2CreateSession(bipVersion input.MuSig2Version,
 		}
 	}
 
-	if localNonces != nil {
-		req.PregeneratedLocalNonce = localNonces.SecNonce[:]
-	}
+	return req, nil
+}
+
+// createMuSig2Session creates a MuSig2 session in the remote signer and
+// parses the session info of the response.
+func (r *RPCKeyRing) createMuSig2Session(bipVersion input.MuSig2Version,
+	tweaks *input.MuSig2Tweaks,
+	req *signrpc.MuSig2SessionRequest) (*input.MuSig2SessionInfo, error) {
 
 	ctxt, cancel := context.WithTimeout(context.Background(), r.rpcTimeout)
 	defer cancel()
@@ -0,0 +1,278 @@
+package signpolicy
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+// defaultRetention is the time authorized spends are remembered for if no
+// velocity limit is set, such that a transaction whose inputs are signed
+// separately is only counted once.
+const defaultRetention = 24 * time.Hour
+
+// Config holds the dependencies of the Enforcer.
+type Config struct {
+	// IsOwnScript returns true if the output script belongs to the wallet.
+	// Outputs paying back to the wallet aren't subject to the policy.
+	IsOwnScript func(pkScript []byte) bool
+
+	// Clock is used to track the spends within the spend window.
+	Clock clock.Clock
+}
+
+// spendRecord is a transaction that was authorized to be signed.
+type spendRecord struct {
+	// key identifies the transaction independently of its input scripts.
+	key chainhash.Hash
+
+	// amount is the value sent to outputs not belonging to the wallet.
+	amount btcutil.Amount
+
+	// timestamp is the time the transaction was authorized.
+	timestamp time.Time
+}
+
+// Enforcer checks transactions spending the funds of the wallet and channel
+// fundings against a signing policy. It's a software approximation of the
+// policy engines of hardware signers, so it only protects against misuse
+// through lnd's own interfaces and not against a compromise of the host.
+type Enforcer struct {
+	cfg Config
+
+	mu sync.Mutex
+
+	policy Policy
+
+	// allowedDests and allowedPeers are the allow-lists of the policy
+	// indexed for lookups.
+	allowedDests map[string]struct{}
+	allowedPeers map[route.Vertex]struct{}
+
+	// fundingScripts are the funding outputs of channels with allowed
+	// counterparties that haven't been signed yet.
+	fundingScripts map[string]struct{}
+
+	// spends are the transactions authorized within the spend window,
+	// ordered by the time they were authorized.
+	spends []spendRecord
+}
+
+// NewEnforcer creates a new Enforcer that enforces the given policy.
+func NewEnforcer(cfg Config, policy Policy) (*Enforcer, error) {
+	e := &Enforcer{
+		cfg:            cfg,
+		fundingScripts: make(map[string]struct{}),
+	}
+	if err := e.SetPolicy(policy); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// SetPolicy replaces the enforced policy. Spends that were authorized before
+// still count towards the velocity limit of the new policy.
+func (e *Enforcer) SetPolicy(policy Policy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+
+	allowedDests := make(map[string]struct{})
+	for _, pkScript := range policy.AllowedDestinations {
+		allowedDests[string(pkScript)] = struct{}{}
+	}
+
+	allowedPeers := make(map[route.Vertex]struct{})
+	for _, peer := range policy.AllowedCounterparties {
+		allowedPeers[peer] = struct{}{}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.policy = policy
+	e.allowedDests = allowedDests
+	e.allowedPeers = allowedPeers
+
+	log.Infof("Updated signing policy: max_spend=%v, spend_window=%v, "+
+		"allowed_destinations=%d, allowed_counterparties=%d",
+		policy.MaxSpend, policy.SpendWindow,
+		len(policy.AllowedDestinations),
+		len(policy.AllowedCounterparties))
+
+	return nil
+}
+
+// Policy returns the enforced policy.
+func (e *Enforcer) Policy() Policy {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.policy
+}
+
+// CheckCounterparty returns an error if channels may not be funded with the
+// given node.
+func (e *Enforcer) CheckCounterparty(peer route.Vertex) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.checkCounterparty(peer)
+}
+
+// checkCounterparty returns an error if channels may not be funded with the
+// given node.
+//
+// NOTE: The mutex must be held.
+func (e *Enforcer) checkCounterparty(peer route.Vertex) error {
+	if len(e.allowedPeers) == 0 {
+		return nil
+	}
+
+	if _, ok := e.allowedPeers[peer]; !ok {
+		log.Warnf("Rejected channel funding with %v", peer)
+
+		return fmt.Errorf("%w: %v", ErrCounterpartyNotAllowed, peer)
+	}
+
+	return nil
+}
+
+// AuthorizeFunding checks that a channel may be funded with the given node
+// and, if so, allows the funding output to be signed even if it isn't on the
+// destination allow-list. The value of the funding output still counts
+// towards the velocity limit.
+//
+// NOTE: This is part of the lnwallet.FundingPolicy interface.
+func (e *Enforcer) AuthorizeFunding(peer *btcec.PublicKey,
+	pkScript []byte) error {
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if err := e.checkCounterparty(route.NewVertex(peer)); err != nil {
+		return err
+	}
+
+	e.fundingScripts[string(pkScript)] = struct{}{}
+
+	return nil
+}
+
+// CheckSpend returns an error if signing the inputs of the given transaction
+// would violate the policy. Otherwise the value the transaction sends to
+// outputs not belonging to the wallet is counted towards the velocity limit.
+// As each input of a transaction is signed separately, a transaction is only
+// counted once, no matter how often it's checked.
+func (e *Enforcer) CheckSpend(tx *wire.MsgTx) error {
+	key := spendKey(tx)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := e.cfg.Clock.Now()
+	e.pruneSpends(now)
+
+	var spent btcutil.Amount
+	for _, spend := range e.spends {
+		if spend.key == key {
+			return nil
+		}
+
+		spent += spend.amount
+	}
+
+	var (
+		amount         btcutil.Amount
+		fundingScripts []string
+	)
+	for _, txOut := range tx.TxOut {
+		if e.cfg.IsOwnScript(txOut.PkScript) {
+			continue
+		}
+
+		amount += btcutil.Amount(txOut.Value)
+
+		script := string(txOut.PkScript)
+		if _, ok := e.fundingScripts[script]; ok {
+			fundingScripts = append(fundingScripts, script)
+			continue
+		}
+
+		if len(e.allowedDests) == 0 {
+			continue
+		}
+
+		if _, ok := e.allowedDests[script]; !ok {
+			log.Warnf("Rejected signing of transaction %v sending "+
+				"%v to %x", tx.TxHash(),
+				btcutil.Amount(txOut.Value), txOut.PkScript)
+
+			return fmt.Errorf("%w: %x", ErrDestinationNotAllowed,
+				txOut.PkScript)
+		}
+	}
+
+	if e.policy.MaxSpend > 0 && spent+amount > e.policy.MaxSpend {
+		log.Warnf("Rejected signing of transaction %v sending %v, "+
+			"%v of %v were spent within the last %v", tx.TxHash(),
+			amount, spent, e.policy.MaxSpend,
+			e.policy.SpendWindow)
+
+		return fmt.Errorf("%w: sending %v would exceed the limit of "+
+			"%v per %v, %v were spent already",
+			ErrSpendLimitExceeded, amount, e.policy.MaxSpend,
+			e.policy.SpendWindow, spent)
+	}
+
+	// The funding outputs are only authorized for a single transaction.
+	for _, script := range fundingScripts {
+		delete(e.fundingScripts, script)
+	}
+
+	e.spends = append(e.spends, spendRecord{
+		key:       key,
+		amount:    amount,
+		timestamp: now,
+	})
+
+	return nil
+}
+
+// pruneSpends removes the spends that are outside the spend window.
+//
+// NOTE: The mutex must be held.
+func (e *Enforcer) pruneSpends(now time.Time) {
+	window := e.policy.SpendWindow
+	if window <= 0 {
+		window = defaultRetention
+	}
+
+	cutoff := now.Add(-window)
+
+	var i int
+	for i < len(e.spends) && !e.spends[i].timestamp.After(cutoff) {
+		i++
+	}
+	e.spends = e.spends[i:]
+}
+
+// spendKey identifies a transaction independently of its input scripts,
+// which are filled in while its inputs are signed one after another.
+func spendKey(tx *wire.MsgTx) chainhash.Hash {
+	stripped := tx.Copy()
+	for _, txIn := range stripped.TxIn {
+		txIn.SignatureScript = nil
+		txIn.Witness = nil
+	}
+
+	return stripped.TxHash()
+}
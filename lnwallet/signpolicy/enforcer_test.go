@@ -0,0 +1,176 @@
+package signpolicy
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	ownScript   = bytes.Repeat([]byte{0x01}, 22)
+	allowedDest = bytes.Repeat([]byte{0x02}, 22)
+	otherDest   = bytes.Repeat([]byte{0x03}, 22)
+	fundingDest = bytes.Repeat([]byte{0x04}, 34)
+)
+
+// newTestEnforcer creates an enforcer for the given policy that treats
+// ownScript as the wallet's own script.
+func newTestEnforcer(t *testing.T, policy Policy) (*Enforcer,
+	*clock.TestClock) {
+
+	t.Helper()
+
+	testClock := clock.NewTestClock(testTime)
+	enforcer, err := NewEnforcer(Config{
+		IsOwnScript: func(pkScript []byte) bool {
+			return bytes.Equal(pkScript, ownScript)
+		},
+		Clock: testClock,
+	}, policy)
+	require.NoError(t, err)
+
+	return enforcer, testClock
+}
+
+// newTestTx creates a transaction with a single input from the given
+// outpoint index that pays the given amounts to the given scripts.
+func newTestTx(index uint32, outputs ...*wire.TxOut) *wire.MsgTx {
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Index: index},
+	})
+	for _, txOut := range outputs {
+		tx.AddTxOut(txOut)
+	}
+
+	return tx
+}
+
+// TestPolicyValidate tests that inconsistent policies are rejected.
+func TestPolicyValidate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, (&Policy{}).Validate())
+	require.Error(t, (&Policy{MaxSpend: -1}).Validate())
+	require.Error(t, (&Policy{MaxSpend: 1}).Validate())
+	require.NoError(t, (&Policy{
+		MaxSpend:    1,
+		SpendWindow: time.Hour,
+	}).Validate())
+	require.Error(t, (&Policy{
+		AllowedDestinations: [][]byte{nil},
+	}).Validate())
+}
+
+// TestCheckSpendVelocity tests that the value sent to foreign outputs is
+// limited within the spend window, while change outputs aren't counted.
+func TestCheckSpendVelocity(t *testing.T) {
+	t.Parallel()
+
+	enforcer, testClock := newTestEnforcer(t, Policy{
+		MaxSpend:    100_000,
+		SpendWindow: time.Hour,
+	})
+
+	// The change output doesn't count towards the limit.
+	tx1 := newTestTx(
+		0, wire.NewTxOut(60_000, otherDest),
+		wire.NewTxOut(500_000, ownScript),
+	)
+	require.NoError(t, enforcer.CheckSpend(tx1))
+
+	// Signing the other inputs of the same transaction doesn't count the
+	// transaction again.
+	tx1.TxIn[0].Witness = wire.TxWitness{{0x01}}
+	require.NoError(t, enforcer.CheckSpend(tx1))
+
+	// A second transaction exceeding the remaining budget is rejected.
+	tx2 := newTestTx(1, wire.NewTxOut(50_000, otherDest))
+	require.ErrorIs(t, enforcer.CheckSpend(tx2), ErrSpendLimitExceeded)
+
+	// One that stays within the budget is fine.
+	tx3 := newTestTx(2, wire.NewTxOut(40_000, otherDest))
+	require.NoError(t, enforcer.CheckSpend(tx3))
+
+	// Once the first spend leaves the window, its value is available
+	// again.
+	testClock.SetTime(testTime.Add(time.Hour + time.Second))
+	require.NoError(t, enforcer.CheckSpend(tx2))
+}
+
+// TestCheckSpendAllowList tests that funds may only be sent to allowed
+// destinations and the wallet itself.
+func TestCheckSpendAllowList(t *testing.T) {
+	t.Parallel()
+
+	enforcer, _ := newTestEnforcer(t, Policy{
+		AllowedDestinations: [][]byte{allowedDest},
+	})
+
+	tx := newTestTx(
+		0, wire.NewTxOut(1_000, allowedDest),
+		wire.NewTxOut(1_000, ownScript),
+	)
+	require.NoError(t, enforcer.CheckSpend(tx))
+
+	tx = newTestTx(1, wire.NewTxOut(1_000, otherDest))
+	require.ErrorIs(t, enforcer.CheckSpend(tx), ErrDestinationNotAllowed)
+
+	// Lifting the allow-list at runtime allows the transaction.
+	require.NoError(t, enforcer.SetPolicy(Policy{}))
+	require.NoError(t, enforcer.CheckSpend(tx))
+}
+
+// TestAuthorizeFunding tests that the funding outputs of channels with
+// allowed counterparties may be signed once, and still count towards the
+// velocity limit.
+func TestAuthorizeFunding(t *testing.T) {
+	t.Parallel()
+
+	allowedKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	otherKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	enforcer, _ := newTestEnforcer(t, Policy{
+		MaxSpend:            150_000,
+		SpendWindow:         time.Hour,
+		AllowedDestinations: [][]byte{allowedDest},
+		AllowedCounterparties: []route.Vertex{
+			route.NewVertex(allowedKey.PubKey()),
+		},
+	})
+
+	// Channels with nodes that aren't on the allow-list are rejected.
+	err = enforcer.AuthorizeFunding(otherKey.PubKey(), fundingDest)
+	require.ErrorIs(t, err, ErrCounterpartyNotAllowed)
+
+	// Without authorization, the funding output isn't allowed.
+	tx := newTestTx(0, wire.NewTxOut(100_000, fundingDest))
+	require.ErrorIs(t, enforcer.CheckSpend(tx), ErrDestinationNotAllowed)
+
+	// Once authorized, the funding transaction can be signed.
+	err = enforcer.AuthorizeFunding(allowedKey.PubKey(), fundingDest)
+	require.NoError(t, err)
+	require.NoError(t, enforcer.CheckSpend(tx))
+
+	// The authorization is consumed by the funding transaction.
+	tx2 := newTestTx(1, wire.NewTxOut(1_000, fundingDest))
+	require.ErrorIs(t, enforcer.CheckSpend(tx2), ErrDestinationNotAllowed)
+
+	// The funding amount counts towards the velocity limit.
+	err = enforcer.AuthorizeFunding(allowedKey.PubKey(), fundingDest)
+	require.NoError(t, err)
+	require.NoError(t, enforcer.CheckSpend(tx2))
+
+	tx3 := newTestTx(2, wire.NewTxOut(50_000, allowedDest))
+	require.ErrorIs(t, enforcer.CheckSpend(tx3), ErrSpendLimitExceeded)
+}
@@ -0,0 +1,70 @@
+package signpolicy
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+var (
+	// ErrDestinationNotAllowed is returned if a transaction sends funds to
+	// an output script that isn't on the destination allow-list.
+	ErrDestinationNotAllowed = errors.New("destination not allowed by " +
+		"signing policy")
+
+	// ErrCounterpartyNotAllowed is returned if a channel is funded with a
+	// node that isn't on the counterparty allow-list.
+	ErrCounterpartyNotAllowed = errors.New("channel counterparty not " +
+		"allowed by signing policy")
+
+	// ErrSpendLimitExceeded is returned if signing a transaction would
+	// exceed the spend velocity limit.
+	ErrSpendLimitExceeded = errors.New("spend velocity limit of " +
+		"signing policy exceeded")
+)
+
+// Policy is the set of rules that are enforced before the funds of the
+// wallet are signed away. The zero value doesn't restrict anything.
+type Policy struct {
+	// MaxSpend is the maximum total value that may be sent to outputs not
+	// belonging to the wallet within the SpendWindow. Zero disables the
+	// velocity limit.
+	MaxSpend btcutil.Amount
+
+	// SpendWindow is the sliding time window over which the MaxSpend
+	// limit is enforced.
+	SpendWindow time.Duration
+
+	// AllowedDestinations are the output scripts funds may be sent to,
+	// besides the wallet's own scripts and the funding outputs of channels
+	// with allowed counterparties. If empty, funds may be sent anywhere.
+	AllowedDestinations [][]byte
+
+	// AllowedCounterparties are the nodes channels may be funded with. If
+	// empty, channels may be funded with any node.
+	AllowedCounterparties []route.Vertex
+}
+
+// Validate checks that the policy is consistent.
+func (p *Policy) Validate() error {
+	if p.MaxSpend < 0 {
+		return fmt.Errorf("max spend must not be negative")
+	}
+
+	if p.MaxSpend > 0 && p.SpendWindow <= 0 {
+		return fmt.Errorf("spend window must be positive if a max " +
+			"spend is set")
+	}
+
+	for _, pkScript := range p.AllowedDestinations {
+		if len(pkScript) == 0 {
+			return fmt.Errorf("allowed destination must not be " +
+				"empty")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package signpolicy
+
+import (
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/input"
+)
+
+// Signer is an input.Signer that checks the transactions spending the inputs
+// of the wallet against the policy of an Enforcer before signing them.
+//
+// NOTE: Only ComputeInputScript, which signs the wallet's own inputs, is
+// guarded. SignOutputRaw is used to sign channel state, which is governed by
+// the channel's counterparty rather than the signing policy.
+type Signer struct {
+	input.Signer
+
+	enforcer *Enforcer
+}
+
+// A compile-time check to ensure Signer implements the input.Signer
+// interface.
+var _ input.Signer = (*Signer)(nil)
+
+// NewSigner wraps the given signer such that the policy of the enforcer is
+// applied.
+func NewSigner(signer input.Signer, enforcer *Enforcer) *Signer {
+	return &Signer{
+		Signer:   signer,
+		enforcer: enforcer,
+	}
+}
+
+// ComputeInputScript generates a complete InputIndex for the passed
+// transaction with the signature as defined within the passed SignDescriptor,
+// if the transaction doesn't violate the signing policy.
+//
+// NOTE: This is part of the input.Signer interface.
+func (s *Signer) ComputeInputScript(tx *wire.MsgTx,
+	signDesc *input.SignDescriptor) (*input.Script, error) {
+
+	if err := s.enforcer.CheckSpend(tx); err != nil {
+		return nil, err
+	}
+
+	return s.Signer.ComputeInputScript(tx, signDesc)
+}
@@ -434,7 +434,7 @@ func testDualFundingReservationWorkflow(miner *rpctest.Harness,
 		CsvDelay:  csvDelay,
 	}
 	err = aliceChanReservation.CommitConstraints(
-		bounds, commitParams, defaultMaxLocalCsvDelay, false,
+		bounds, commitParams, defaultMaxLocalCsvDelay, nil, false,
 	)
 	require.NoError(t, err, "unable to verify constraints")
 
@@ -466,7 +466,7 @@ func testDualFundingReservationWorkflow(miner *rpctest.Harness,
 	bobChanReservation, err := bob.InitChannelReservation(bobReq)
 	require.NoError(t, err, "bob unable to init channel reservation")
 	err = bobChanReservation.CommitConstraints(
-		bounds, commitParams, defaultMaxLocalCsvDelay, true,
+		bounds, commitParams, defaultMaxLocalCsvDelay, nil, true,
 	)
 	require.NoError(t, err, "unable to verify constraints")
 	bobChanReservation.SetNumConfsRequired(numReqConfs)
@@ -841,7 +841,7 @@ func testSingleFunderReservationWorkflow(miner *rpctest.Harness,
 		CsvDelay:  csvDelay,
 	}
 	err = aliceChanReservation.CommitConstraints(
-		bounds, commitParams, defaultMaxLocalCsvDelay, false,
+		bounds, commitParams, defaultMaxLocalCsvDelay, nil, false,
 	)
 	require.NoError(t, err, "unable to verify constraints")
 
@@ -880,7 +880,7 @@ func testSingleFunderReservationWorkflow(miner *rpctest.Harness,
 	bobChanReservation, err := bob.InitChannelReservation(bobReq)
 	require.NoError(t, err, "unable to create bob reservation")
 	err = bobChanReservation.CommitConstraints(
-		bounds, commitParams, defaultMaxLocalCsvDelay, true,
+		bounds, commitParams, defaultMaxLocalCsvDelay, nil, true,
 	)
 	require.NoError(t, err, "unable to verify constraints")
 	bobChanReservation.SetNumConfsRequired(numReqConfs)
@@ -1690,6 +1690,28 @@ func (l *LightningWallet) handleContributionMsg(req *addContributionMsg) {
 			theirContribution.MultiSigKey.PubKey,
 		)
 
+		// Before we sign anything, we'll make sure the funding policy,
+		// if any, allows us to fund a channel with this peer.
+		_, fundingOutput, err := fundingIntent.FundingOutput()
+		if err != nil {
+			req.err <- fmt.Errorf("unable to obtain funding "+
+				"output: %w", err)
+			return
+		}
+		peer := pendingReservation.partialState.IdentityPub
+		err = fn.MapOptionZ(
+			l.Cfg.FundingPolicy, func(p FundingPolicy) error {
+				return p.AuthorizeFunding(
+					peer, fundingOutput.PkScript,
+				)
+			},
+		)
+		if err != nil {
+			req.err <- fmt.Errorf("funding not authorized: %w",
+				err)
+			return
+		}
+
 		// With our keys bound, we can now construct+sign the final
 		// funding transaction and also obtain the chanPoint that
 		// creates the channel.
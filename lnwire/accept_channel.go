@@ -2,6 +2,7 @@ package lnwire
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 
 	"github.com/btcsuite/btcd/btcec/v2"
@@ -112,6 +113,11 @@ type AcceptChannel struct {
 	// negotiated.
 	LocalNonce OptMusig2NonceTLV
 
+	// CustomRecords maps TLV types to byte slices, storing arbitrary data
+	// intended for inclusion in the ExtraData field of the AcceptChannel
+	// message. All types must be in the custom records range.
+	CustomRecords CustomRecords
+
 	// ExtraData is the set of data that was appended to this message to
 	// fill out the full maximum transport message size. These fields can
 	// be used to specify optional data such as custom TLV fields.
@@ -128,6 +134,23 @@ type AcceptChannel struct {
 // interface.
 var _ Message = (*AcceptChannel)(nil)
 
+// ValidateAcceptChannelRecords checks that the given custom records can be
+// sent in an accept_channel message. Besides being in the custom range, they
+// must not use the type of the lease expiry, which is a known record within
+// that range.
+func ValidateAcceptChannelRecords(records CustomRecords) error {
+	if err := records.Validate(); err != nil {
+		return err
+	}
+
+	if _, ok := records[uint64(LeaseExpiryRecordType)]; ok {
+		return fmt.Errorf("custom record type %d is reserved for the "+
+			"lease expiry", LeaseExpiryRecordType)
+	}
+
+	return nil
+}
+
 // Encode serializes the target AcceptChannel into the passed io.Writer
 // implementation. Serialization will observe the rules defined by the passed
 // protocol version.
@@ -144,7 +167,19 @@ func (a *AcceptChannel) Encode(w *bytes.Buffer, pver uint32) error {
 	a.LocalNonce.WhenSome(func(localNonce Musig2NonceTLV) {
 		recordProducers = append(recordProducers, &localNonce)
 	})
-	err := EncodeMessageExtraData(&a.ExtraData, recordProducers...)
+
+	if err := ValidateAcceptChannelRecords(a.CustomRecords); err != nil {
+		return err
+	}
+
+	recordProducers, err := a.CustomRecords.ExtendRecordProducers(
+		recordProducers,
+	)
+	if err != nil {
+		return err
+	}
+
+	err = EncodeMessageExtraData(&a.ExtraData, recordProducers...)
 	if err != nil {
 		return err
 	}
@@ -269,6 +304,22 @@ func (a *AcceptChannel) Decode(r io.Reader, pver uint32) error {
 		a.LocalNonce = tlv.SomeRecordT(localNonce)
 	}
 
+	// The records in the custom range are made available separately, while
+	// the raw extra data still contains them. The lease expiry is a known
+	// record within the custom range, so it's not one of them.
+	customTlvs := make(tlv.TypeMap)
+	for tlvType, val := range typeMap {
+		if tlvType >= MinCustomRecordsTlvType &&
+			tlvType != LeaseExpiryRecordType {
+
+			customTlvs[tlvType] = val
+		}
+	}
+	a.CustomRecords, err = NewCustomRecords(customTlvs)
+	if err != nil {
+		return err
+	}
+
 	a.ExtraData = tlvRecords
 
 	return nil
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/stretchr/testify/require"
 )
 
 // TestDecodeAcceptChannel tests decoding of an accept channel wire message with
@@ -70,3 +71,55 @@ func TestDecodeAcceptChannel(t *testing.T) {
 		})
 	}
 }
+
+// TestAcceptChannelCustomRecords tests that custom records are encoded in and
+// decoded from the extra data of an accept channel message, and that records
+// outside of the custom range are rejected.
+func TestAcceptChannelCustomRecords(t *testing.T) {
+	t.Parallel()
+
+	priv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	pk := priv.PubKey()
+	leaseExpiry := LeaseExpiry(1000)
+
+	encoded := &AcceptChannel{
+		FundingKey:           pk,
+		RevocationPoint:      pk,
+		PaymentPoint:         pk,
+		DelayedPaymentPoint:  pk,
+		HtlcPoint:            pk,
+		FirstCommitmentPoint: pk,
+		LeaseExpiry:          &leaseExpiry,
+		CustomRecords: CustomRecords{
+			MinCustomRecordsTlvType + 1: []byte{1, 2, 3},
+			MinCustomRecordsTlvType + 2: []byte{},
+		},
+	}
+
+	var buf bytes.Buffer
+	_, err = WriteMessage(&buf, encoded, 0)
+	require.NoError(t, err)
+
+	msg, err := ReadMessage(&buf, 0)
+	require.NoError(t, err)
+
+	decoded, ok := msg.(*AcceptChannel)
+	require.True(t, ok)
+	require.Equal(t, encoded.CustomRecords, decoded.CustomRecords)
+	require.Equal(t, encoded.ExtraData, decoded.ExtraData)
+
+	require.Equal(t, encoded.LeaseExpiry, decoded.LeaseExpiry)
+
+	// Records below the custom range can't be sent as custom records.
+	encoded.CustomRecords = CustomRecords{1: []byte{1}}
+	_, err = WriteMessage(&buf, encoded, 0)
+	require.Error(t, err)
+
+	// Neither can a record that uses the type of the lease expiry.
+	encoded.CustomRecords = CustomRecords{
+		uint64(LeaseExpiryRecordType): []byte{1},
+	}
+	_, err = WriteMessage(&buf, encoded, 0)
+	require.ErrorContains(t, err, "reserved for the lease expiry")
+}
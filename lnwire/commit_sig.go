@@ -45,6 +45,16 @@ type CommitSig struct {
 	// being signed for. In this case, the above Sig type MUST be blank.
 	PartialSig OptPartialSigWithNonceTLV
 
+	// BatchSize is the number of CommitSig messages in the logical batch
+	// this message is part of. During a splice, a commitment is signed for
+	// each pending funding transaction and the signatures are sent as a
+	// batch of CommitSig messages that must all be received before any of
+	// them is applied.
+	//
+	// NOTE: This field is only populated if the message is part of a
+	// batch.
+	BatchSize tlv.OptionalRecordT[tlv.TlvType0, uint16]
+
 	// CustomRecords maps TLV types to byte slices, storing arbitrary data
 	// intended for inclusion in the ExtraData field.
 	CustomRecords CustomRecords
@@ -84,16 +94,20 @@ func (c *CommitSig) Decode(r io.Reader, pver uint32) error {
 	}
 
 	// Extract TLV records from the extra data field.
+	batchSize := c.BatchSize.Zero()
 	partialSig := c.PartialSig.Zero()
 
 	customRecords, parsed, extraData, err := ParseAndExtractCustomRecords(
-		msgExtraData, &partialSig,
+		msgExtraData, &batchSize, &partialSig,
 	)
 	if err != nil {
 		return err
 	}
 
 	// Set the corresponding TLV types if they were included in the stream.
+	if _, ok := parsed[batchSize.TlvType()]; ok {
+		c.BatchSize = tlv.SomeRecordT(batchSize)
+	}
 	if _, ok := parsed[partialSig.TlvType()]; ok {
 		c.PartialSig = tlv.SomeRecordT(partialSig)
 	}
@@ -109,7 +123,12 @@ func (c *CommitSig) Decode(r io.Reader, pver uint32) error {
 //
 // This is part of the lnwire.Message interface.
 func (c *CommitSig) Encode(w *bytes.Buffer, pver uint32) error {
-	recordProducers := make([]tlv.RecordProducer, 0, 1)
+	recordProducers := make([]tlv.RecordProducer, 0, 2)
+	c.BatchSize.WhenSome(
+		func(size tlv.RecordT[tlv.TlvType0, uint16]) {
+			recordProducers = append(recordProducers, &size)
+		},
+	)
 	c.PartialSig.WhenSome(func(sig PartialSigWithNonceTLV) {
 		recordProducers = append(recordProducers, &sig)
 	})
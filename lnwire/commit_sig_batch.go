@@ -0,0 +1,100 @@
+package lnwire
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+var (
+	// ErrInvalidCommitSigBatch is returned if a CommitSig message doesn't
+	// fit into the batch that is currently being received.
+	ErrInvalidCommitSigBatch = errors.New("invalid commit sig batch")
+)
+
+// CommitSigBatch collects the CommitSig messages of a logical batch, so they
+// are only applied once all of them were received. A CommitSig message that
+// isn't part of a batch forms a batch of its own.
+//
+// NOTE: This isn't safe for concurrent use.
+type CommitSigBatch struct {
+	// size is the size of the batch currently being received, zero if no
+	// batch is pending.
+	size uint16
+
+	// sigs are the messages of the pending batch received so far.
+	sigs []*CommitSig
+}
+
+// Add adds the message to the pending batch. Once the batch is complete, all
+// of its messages are returned in the order they were received and a new
+// batch is started. Nil is returned while the batch is incomplete. An error
+// is returned if the message doesn't fit into the pending batch, in which
+// case the pending batch is discarded.
+func (b *CommitSigBatch) Add(msg *CommitSig) ([]*CommitSig, error) {
+	size := msg.BatchSize.ValOpt().UnwrapOr(1)
+
+	if err := b.validate(msg, size); err != nil {
+		b.Reset()
+		return nil, err
+	}
+
+	b.size = size
+	b.sigs = append(b.sigs, msg)
+	if len(b.sigs) < int(b.size) {
+		return nil, nil
+	}
+
+	sigs := b.sigs
+	b.Reset()
+
+	return sigs, nil
+}
+
+// validate checks that the message with the given batch size fits into the
+// pending batch.
+func (b *CommitSigBatch) validate(msg *CommitSig, size uint16) error {
+	if size == 0 {
+		return fmt.Errorf("%w: zero batch size",
+			ErrInvalidCommitSigBatch)
+	}
+
+	// This message starts a new batch.
+	if len(b.sigs) == 0 {
+		return nil
+	}
+
+	if msg.ChanID != b.sigs[0].ChanID {
+		return fmt.Errorf("%w: expected channel %v, got %v",
+			ErrInvalidCommitSigBatch, b.sigs[0].ChanID,
+			msg.ChanID)
+	}
+
+	if size != b.size {
+		return fmt.Errorf("%w: expected batch size %d, got %d after "+
+			"%d messages", ErrInvalidCommitSigBatch, b.size, size,
+			len(b.sigs))
+	}
+
+	return nil
+}
+
+// Pending returns true if a batch was started but isn't complete yet.
+func (b *CommitSigBatch) Pending() bool {
+	return len(b.sigs) > 0
+}
+
+// Reset discards the pending batch.
+func (b *CommitSigBatch) Reset() {
+	b.size = 0
+	b.sigs = nil
+}
+
+// NewCommitSigBatchSize returns the optional batch size record of a CommitSig
+// message that is part of a batch of the given size.
+func NewCommitSigBatchSize(
+	size uint16) tlv.OptionalRecordT[tlv.TlvType0, uint16] {
+
+	return tlv.SomeRecordT(tlv.NewPrimitiveRecord[tlv.TlvType0](size))
+}
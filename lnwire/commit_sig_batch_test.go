@@ -0,0 +1,71 @@
+package lnwire
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestCommitSigBatch tests that CommitSig messages are only released once
+// their batch is complete and that messages that don't fit into the pending
+// batch are rejected.
+func TestCommitSigBatch(t *testing.T) {
+	t.Parallel()
+
+	chanID := ChannelID{1}
+	newSig := func(chanID ChannelID, batchSize uint16) *CommitSig {
+		msg := &CommitSig{ChanID: chanID}
+		if batchSize != 0 {
+			msg.BatchSize = NewCommitSigBatchSize(batchSize)
+		}
+
+		return msg
+	}
+
+	var batch CommitSigBatch
+
+	// A message without a batch size forms a batch of its own.
+	single := newSig(chanID, 0)
+	sigs, err := batch.Add(single)
+	require.NoError(t, err)
+	require.Equal(t, []*CommitSig{single}, sigs)
+	require.False(t, batch.Pending())
+
+	// A batch of three is only released with its last message.
+	first, second, third := newSig(chanID, 3), newSig(chanID, 3),
+		newSig(chanID, 3)
+
+	sigs, err = batch.Add(first)
+	require.NoError(t, err)
+	require.Nil(t, sigs)
+	require.True(t, batch.Pending())
+
+	sigs, err = batch.Add(second)
+	require.NoError(t, err)
+	require.Nil(t, sigs)
+
+	sigs, err = batch.Add(third)
+	require.NoError(t, err)
+	require.Equal(t, []*CommitSig{first, second, third}, sigs)
+	require.False(t, batch.Pending())
+
+	// A message with a different batch size or channel can't complete a
+	// pending batch.
+	_, err = batch.Add(newSig(chanID, 2))
+	require.NoError(t, err)
+	_, err = batch.Add(newSig(chanID, 0))
+	require.ErrorIs(t, err, ErrInvalidCommitSigBatch)
+	require.False(t, batch.Pending())
+
+	_, err = batch.Add(newSig(chanID, 2))
+	require.NoError(t, err)
+	_, err = batch.Add(newSig(ChannelID{2}, 2))
+	require.ErrorIs(t, err, ErrInvalidCommitSigBatch)
+
+	// A batch size of zero is invalid.
+	_, err = batch.Add(newSig(chanID, 0))
+	require.NoError(t, err)
+	msg := &CommitSig{ChanID: chanID, BatchSize: NewCommitSigBatchSize(0)}
+	_, err = batch.Add(msg)
+	require.ErrorIs(t, err, ErrInvalidCommitSigBatch)
+}
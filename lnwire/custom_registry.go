@@ -0,0 +1,128 @@
+package lnwire
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+var (
+	// ErrCustomMessageRegistered is returned when a decoder is registered
+	// for a custom message type that already has one.
+	ErrCustomMessageRegistered = errors.New("custom message type already " +
+		"registered")
+
+	// ErrNotCustomMessageType is returned when a decoder is registered for
+	// a message type that is neither in the custom range nor overridden.
+	ErrNotCustomMessageType = errors.New("message type not in custom " +
+		"range and not overridden")
+)
+
+// CustomMessageDecoder decodes the payload of a custom message, which follows
+// the message type on the wire, into a typed message. The typed message must
+// return the same message type, and encode the same payload, so it can be
+// written with WriteMessage.
+type CustomMessageDecoder func(r io.Reader) (Message, error)
+
+// CustomMessageRegistry maps custom message types to the decoders that parse
+// them into typed messages. Custom messages without a registered decoder are
+// read as a Custom message holding the raw payload.
+type CustomMessageRegistry struct {
+	decoders map[MessageType]CustomMessageDecoder
+	mu       sync.RWMutex
+}
+
+// NewCustomMessageRegistry creates a new, empty custom message registry.
+func NewCustomMessageRegistry() *CustomMessageRegistry {
+	return &CustomMessageRegistry{
+		decoders: make(map[MessageType]CustomMessageDecoder),
+	}
+}
+
+// Register registers the decoder for the given custom message type. The type
+// must either be in the custom range or be overridden to be treated as a
+// custom message, and no other decoder may be registered for it.
+func (c *CustomMessageRegistry) Register(msgType MessageType,
+	decoder CustomMessageDecoder) error {
+
+	if msgType < CustomTypeStart && !IsCustomOverride(msgType) {
+		return fmt.Errorf("%w: %v", ErrNotCustomMessageType, msgType)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.decoders[msgType]; ok {
+		return fmt.Errorf("%w: %v", ErrCustomMessageRegistered,
+			msgType)
+	}
+	c.decoders[msgType] = decoder
+
+	return nil
+}
+
+// Unregister removes the decoder of the given custom message type, if any.
+func (c *CustomMessageRegistry) Unregister(msgType MessageType) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.decoders, msgType)
+}
+
+// decoder returns the decoder registered for the given message type, if any.
+func (c *CustomMessageRegistry) decoder(
+	msgType MessageType) (CustomMessageDecoder, bool) {
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	decoder, ok := c.decoders[msgType]
+
+	return decoder, ok
+}
+
+// decode decodes a custom message of the given type using its registered
+// decoder. False is returned if no decoder is registered for the type.
+func (c *CustomMessageRegistry) decode(msgType MessageType,
+	r io.Reader) (Message, bool, error) {
+
+	decoder, ok := c.decoder(msgType)
+	if !ok {
+		return nil, false, nil
+	}
+
+	msg, err := decoder(r)
+	if err != nil {
+		return nil, true, fmt.Errorf("unable to decode custom "+
+			"message of type %v: %w", msgType, err)
+	}
+
+	// The typed message must be sent with the same type it was read with.
+	if msg.MsgType() != msgType {
+		return nil, true, fmt.Errorf("decoder for custom message "+
+			"type %v returned message of type %v", msgType,
+			msg.MsgType())
+	}
+
+	return msg, true, nil
+}
+
+// customMessages is the registry consulted by ReadMessage.
+var customMessages = NewCustomMessageRegistry()
+
+// RegisterCustomMessage registers the decoder for the given custom message
+// type, so that ReadMessage parses messages of this type into typed messages
+// instead of a Custom message holding the raw payload. The decoder is passed
+// the payload following the message type.
+func RegisterCustomMessage(msgType MessageType,
+	decoder CustomMessageDecoder) error {
+
+	return customMessages.Register(msgType, decoder)
+}
+
+// UnregisterCustomMessage removes the decoder of the given custom message
+// type, after which messages of this type are read as Custom messages again.
+func UnregisterCustomMessage(msgType MessageType) {
+	customMessages.Unregister(msgType)
+}
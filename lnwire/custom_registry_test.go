@@ -0,0 +1,96 @@
+package lnwire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testCustomMsg is a typed custom message carrying a single counter.
+type testCustomMsg struct {
+	msgType MessageType
+	counter uint32
+}
+
+func (m *testCustomMsg) Decode(r io.Reader, _ uint32) error {
+	return binary.Read(r, binary.BigEndian, &m.counter)
+}
+
+func (m *testCustomMsg) Encode(w *bytes.Buffer, _ uint32) error {
+	return binary.Write(w, binary.BigEndian, m.counter)
+}
+
+func (m *testCustomMsg) MsgType() MessageType {
+	return m.msgType
+}
+
+// TestCustomMessageRegistry tests that custom messages with a registered
+// decoder are read as typed messages, and that conflicting registrations are
+// rejected.
+func TestCustomMessageRegistry(t *testing.T) {
+	t.Parallel()
+
+	const msgType = CustomTypeStart + 1000
+
+	decoder := func(typ MessageType) CustomMessageDecoder {
+		return func(r io.Reader) (Message, error) {
+			msg := &testCustomMsg{msgType: typ}
+			return msg, msg.Decode(r, 0)
+		}
+	}
+
+	// Types outside the custom range can't be registered.
+	err := RegisterCustomMessage(MsgPing, decoder(MsgPing))
+	require.ErrorIs(t, err, ErrNotCustomMessageType)
+
+	require.NoError(t, RegisterCustomMessage(msgType, decoder(msgType)))
+	t.Cleanup(func() {
+		UnregisterCustomMessage(msgType)
+	})
+
+	err = RegisterCustomMessage(msgType, decoder(msgType))
+	require.ErrorIs(t, err, ErrCustomMessageRegistered)
+
+	// A registered message round trips as its typed form.
+	msg := &testCustomMsg{msgType: msgType, counter: 42}
+
+	var b bytes.Buffer
+	_, err = WriteMessage(&b, msg, 0)
+	require.NoError(t, err)
+
+	readMsg, err := ReadMessage(bytes.NewReader(b.Bytes()), 0)
+	require.NoError(t, err)
+	require.Equal(t, msg, readMsg)
+
+	// A decoder returning a different type is rejected.
+	const mismatchType = msgType + 1
+	err = RegisterCustomMessage(mismatchType, decoder(msgType))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		UnregisterCustomMessage(mismatchType)
+	})
+
+	b.Reset()
+	_, err = WriteMessage(
+		&b, &testCustomMsg{msgType: mismatchType, counter: 1}, 0,
+	)
+	require.NoError(t, err)
+
+	_, err = ReadMessage(bytes.NewReader(b.Bytes()), 0)
+	require.Error(t, err)
+
+	// Without a decoder, the message is read as a raw custom message.
+	UnregisterCustomMessage(msgType)
+
+	readMsg, err = ReadMessage(bytes.NewReader(
+		[]byte{0x83, 0xe8, 0, 0, 0, 42},
+	), 0)
+	require.NoError(t, err)
+	require.Equal(t, &Custom{
+		Type: msgType,
+		Data: []byte{0, 0, 0, 42},
+	}, readMsg)
+}
@@ -0,0 +1,68 @@
+package lnwire
+
+import (
+	"bytes"
+	"crypto/sha256"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+// NewDualFundingChanID returns the channel ID of a dual funded channel, which
+// is the sha256 of the revocation basepoints of both parties in
+// lexicographical order.
+func NewDualFundingChanID(a, b *btcec.PublicKey) ChannelID {
+	lesser, greater := a.SerializeCompressed(), b.SerializeCompressed()
+	if bytes.Compare(lesser, greater) > 0 {
+		lesser, greater = greater, lesser
+	}
+
+	return sha256.Sum256(append(lesser, greater...))
+}
+
+// dualFundingRecords returns the record producers of the optional TLVs that
+// open_channel2 and accept_channel2 share.
+func dualFundingRecords(upfrontShutdown *DeliveryAddress,
+	chanType *ChannelType,
+	requireConfirmed OptRequireConfirmedInputsTLV) []tlv.RecordProducer {
+
+	var recordProducers []tlv.RecordProducer
+	if len(*upfrontShutdown) != 0 {
+		recordProducers = append(recordProducers, upfrontShutdown)
+	}
+	if chanType != nil {
+		recordProducers = append(recordProducers, chanType)
+	}
+
+	return append(
+		recordProducers,
+		requireConfirmedInputsRecords(requireConfirmed)...,
+	)
+}
+
+// decodeDualFundingRecords extracts the optional TLVs that open_channel2 and
+// accept_channel2 share from the given records.
+func decodeDualFundingRecords(tlvRecords ExtraOpaqueData,
+	upfrontShutdown *DeliveryAddress, chanType **ChannelType,
+	requireConfirmed *OptRequireConfirmedInputsTLV) error {
+
+	var (
+		channelType ChannelType
+		confirmed   = tlv.ZeroRecordT[tlv.TlvType2, TrueBoolean]()
+	)
+	typeMap, err := tlvRecords.ExtractRecords(
+		upfrontShutdown, &channelType, &confirmed,
+	)
+	if err != nil {
+		return err
+	}
+
+	if val, ok := typeMap[ChannelTypeRecordType]; ok && val == nil {
+		*chanType = &channelType
+	}
+	if _, ok := typeMap[requireConfirmed.TlvType()]; ok {
+		*requireConfirmed = tlv.SomeRecordT(confirmed)
+	}
+
+	return nil
+}
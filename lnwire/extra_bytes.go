@@ -2,6 +2,7 @@ package lnwire
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 
@@ -9,6 +10,21 @@ import (
 	"github.com/lightningnetwork/lnd/tlv"
 )
 
+// MaxExtraOpaqueDataSize is the maximum size of the extra opaque data, which
+// can't be larger than the message body it is part of.
+const MaxExtraOpaqueDataSize = MaxMsgBody
+
+var (
+	// ErrExtraDataTooLarge is returned when the extra opaque data exceeds
+	// MaxExtraOpaqueDataSize.
+	ErrExtraDataTooLarge = errors.New("extra opaque data too large")
+
+	// ErrUnknownEvenType is returned when the extra opaque data contains
+	// a record of an unknown even type, which must be understood by the
+	// reader.
+	ErrUnknownEvenType = errors.New("unknown even tlv type")
+)
+
 // ExtraOpaqueData is the set of data that was appended to this message, some
 // of which we may not actually know how to iterate or parse. By holding onto
 // this data, we ensure that we're able to properly validate the set of
@@ -97,6 +113,120 @@ func (e *ExtraOpaqueData) ExtractRecords(
 	return DecodeRecordsP2P(extraBytesReader, records...)
 }
 
+// extractOptions holds the options of ExtractKnownRecords.
+type extractOptions struct {
+	// preserveUnknownEven indicates that records of unknown even types
+	// are kept instead of rejected.
+	preserveUnknownEven bool
+}
+
+// ExtractOption is a functional option for ExtractKnownRecords.
+type ExtractOption func(*extractOptions)
+
+// WithPreserveUnknownEven keeps records of unknown even types in the remaining
+// extra data, instead of failing with ErrUnknownEvenType.
+func WithPreserveUnknownEven() ExtractOption {
+	return func(o *extractOptions) {
+		o.preserveUnknownEven = true
+	}
+}
+
+// ExtractKnownRecords decodes the given known records from the internal raw
+// bytes, and returns the set of known types that were found along with the
+// remaining, unknown records. Following the "it's OK to be odd" rule, a record
+// of an unknown even type results in ErrUnknownEvenType, unless the
+// WithPreserveUnknownEven option is passed, in which case it's kept in the
+// remaining records like unknown odd types.
+func (e *ExtraOpaqueData) ExtractKnownRecords(
+	knownRecords []tlv.RecordProducer,
+	opts ...ExtractOption) (fn.Set[tlv.Type], ExtraOpaqueData, error) {
+
+	var options extractOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	knownTypes := fn.NewSet(fn.Map(func(p tlv.RecordProducer) tlv.Type {
+		r := p.Record()
+		return r.Type()
+	}, knownRecords)...)
+
+	tlvMap, err := e.ExtractRecords(knownRecords...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	parsedTypes := make(fn.Set[tlv.Type])
+	remaining := make(tlv.TypeMap)
+	for typ, value := range tlvMap {
+		switch {
+		case knownTypes.Contains(typ):
+			parsedTypes.Add(typ)
+
+		case typ%2 == 0 && !options.preserveUnknownEven:
+			return nil, nil, fmt.Errorf("%w: %d",
+				ErrUnknownEvenType, typ)
+
+		default:
+			remaining[typ] = value
+		}
+	}
+
+	remainingData, err := NewExtraOpaqueData(remaining)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return parsedTypes, remainingData, nil
+}
+
+// MergeRecords merges the given records with the records that are already
+// encoded in the ExtraOpaqueData instance, and encodes the union back into it
+// as a sorted TLV stream. Unlike PackRecords, the existing records are kept.
+// An error is returned if a given record has the same type as an existing one.
+func (e *ExtraOpaqueData) MergeRecords(
+	recordProducers ...tlv.RecordProducer) error {
+
+	mergedRecords, err := e.RecordProducers()
+	if err != nil {
+		return err
+	}
+	mergedRecords = append(mergedRecords, recordProducers...)
+
+	sortedRecords := ProduceRecordsSorted(mergedRecords...)
+	if err := AssertUniqueTypes(sortedRecords); err != nil {
+		return err
+	}
+
+	encoded, err := EncodeRecords(sortedRecords)
+	if err != nil {
+		return err
+	}
+
+	*e = encoded
+
+	return nil
+}
+
+// ValidateTLV asserts that the internal raw bytes are a canonical TLV stream,
+// with records sorted by type and no duplicates, that is no larger than
+// MaxExtraOpaqueDataSize.
+func (e *ExtraOpaqueData) ValidateTLV() error {
+	if len(*e) > MaxExtraOpaqueDataSize {
+		return fmt.Errorf("%w: %d bytes exceeds maximum of %d bytes",
+			ErrExtraDataTooLarge, len(*e), MaxExtraOpaqueDataSize)
+	}
+
+	// Decoding the stream without any known records only checks its
+	// structure.
+	tlvStream, err := tlv.NewStream()
+	if err != nil {
+		return err
+	}
+
+	return tlvStream.DecodeP2P(bytes.NewReader(*e))
+}
+
 // RecordProducers parses ExtraOpaqueData into a slice of TLV record producers
 // by interpreting it as a TLV map.
 func (e *ExtraOpaqueData) RecordProducers() ([]tlv.RecordProducer, error) {
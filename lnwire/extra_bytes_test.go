@@ -511,3 +511,135 @@ func TestExtractAndMerge(t *testing.T) {
 		})
 	}
 }
+
+// TestMergeRecords tests that merging records keeps the existing records, and
+// that records with colliding types are rejected.
+func TestMergeRecords(t *testing.T) {
+	t.Parallel()
+
+	var (
+		record1 = tlv.NewPrimitiveRecord[tlv.TlvType1]([]byte("one"))
+		record2 = tlv.NewPrimitiveRecord[tlv.TlvType2]([]byte("two"))
+		record3 = tlv.NewPrimitiveRecord[tlv.TlvType3]([]byte("three"))
+	)
+
+	var extraData ExtraOpaqueData
+	require.NoError(t, extraData.PackRecords(&record1, &record3))
+	require.NoError(t, extraData.MergeRecords(&record2))
+
+	// The merged stream is the same as if all records were packed at
+	// once.
+	var expected ExtraOpaqueData
+	require.NoError(t, expected.PackRecords(&record1, &record2, &record3))
+	require.Equal(t, expected, extraData)
+	require.NoError(t, extraData.ValidateTLV())
+
+	// Merging a record of an existing type fails and leaves the data
+	// untouched.
+	require.Error(t, extraData.MergeRecords(&record2))
+	require.Equal(t, expected, extraData)
+}
+
+// TestValidateTLV tests that only canonical TLV streams within the size bound
+// are accepted.
+func TestValidateTLV(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		extraData ExtraOpaqueData
+		expectErr error
+	}{
+		{
+			name:      "empty",
+			extraData: ExtraOpaqueData{},
+		},
+		{
+			name: "sorted",
+			extraData: ExtraOpaqueData{
+				0x01, 0x01, 0xaa, 0x02, 0x00,
+			},
+		},
+		{
+			name: "unsorted",
+			extraData: ExtraOpaqueData{
+				0x02, 0x00, 0x01, 0x01, 0xaa,
+			},
+			expectErr: tlv.ErrStreamNotCanonical,
+		},
+		{
+			name:      "duplicate",
+			extraData: ExtraOpaqueData{0x01, 0x00, 0x01, 0x00},
+			expectErr: tlv.ErrStreamNotCanonical,
+		},
+		{
+			name: "too large",
+			extraData: make(
+				ExtraOpaqueData, MaxExtraOpaqueDataSize+1,
+			),
+			expectErr: ErrExtraDataTooLarge,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.extraData.ValidateTLV()
+			if tc.expectErr == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			require.ErrorIs(t, err, tc.expectErr)
+		})
+	}
+
+	// A truncated record is rejected as well.
+	truncated := ExtraOpaqueData{0x01, 0x02, 0xaa}
+	require.Error(t, truncated.ValidateTLV())
+}
+
+// TestExtractKnownRecords tests that known records are extracted and unknown
+// records are returned, with unknown even types only being kept if requested.
+func TestExtractKnownRecords(t *testing.T) {
+	t.Parallel()
+
+	var (
+		known   = tlv.NewPrimitiveRecord[tlv.TlvType1]([]byte("one"))
+		unknown = tlv.NewPrimitiveRecord[tlv.TlvType3]([]byte("three"))
+		even    = tlv.NewPrimitiveRecord[tlv.TlvType4]([]byte("four"))
+	)
+
+	var extraData ExtraOpaqueData
+	require.NoError(t, extraData.PackRecords(&known, &unknown))
+
+	decoded := tlv.ZeroRecordT[tlv.TlvType1, []byte]()
+	knownRecords := []tlv.RecordProducer{&decoded}
+
+	parsed, remaining, err := extraData.ExtractKnownRecords(knownRecords)
+	require.NoError(t, err)
+	require.True(t, parsed.Contains(known.TlvType()))
+	require.Equal(t, known.Val, decoded.Val)
+
+	var expected ExtraOpaqueData
+	require.NoError(t, expected.PackRecords(&unknown))
+	require.Equal(t, expected, remaining)
+
+	// An unknown even type is rejected by default.
+	require.NoError(t, extraData.MergeRecords(&even))
+
+	_, _, err = extraData.ExtractKnownRecords(knownRecords)
+	require.ErrorIs(t, err, ErrUnknownEvenType)
+
+	// Unless it's explicitly preserved.
+	_, remaining, err = extraData.ExtractKnownRecords(
+		knownRecords, WithPreserveUnknownEven(),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, expected.PackRecords(&unknown, &even))
+	require.Equal(t, expected, remaining)
+}
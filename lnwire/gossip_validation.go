@@ -0,0 +1,119 @@
+package lnwire
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcutil"
+)
+
+var (
+	// ErrMissingMaxHtlc is returned if a channel update doesn't signal
+	// the mandatory htlc_maximum_msat field.
+	ErrMissingMaxHtlc = errors.New("htlc_maximum_msat not signaled")
+
+	// ErrZeroMaxHtlc is returned if a channel update doesn't allow any
+	// HTLC to be forwarded.
+	ErrZeroMaxHtlc = errors.New("htlc_maximum_msat is zero")
+
+	// ErrMinHtlcExceedsMax is returned if the minimum HTLC of a channel
+	// update is larger than its maximum HTLC.
+	ErrMinHtlcExceedsMax = errors.New("htlc_minimum_msat exceeds " +
+		"htlc_maximum_msat")
+
+	// ErrMaxHtlcExceedsSupply is returned if the maximum HTLC of a channel
+	// update exceeds the total supply of bitcoin.
+	ErrMaxHtlcExceedsSupply = errors.New("htlc_maximum_msat exceeds " +
+		"total supply")
+
+	// ErrExcessiveFee is returned if the fee of a channel update for
+	// forwarding its maximum HTLC exceeds the total supply of bitcoin,
+	// which no payment can ever pay.
+	ErrExcessiveFee = errors.New("fee for htlc_maximum_msat exceeds " +
+		"total supply")
+)
+
+// maxSupplyMsat is the total supply of bitcoin in msat.
+var maxSupplyMsat = NewMSatFromSatoshis(btcutil.MaxSatoshi)
+
+// SemanticValidator is implemented by messages that can check that their
+// fields are consistent with each other after they have been decoded. Such
+// checks don't require any context beyond the message itself, so messages
+// failing them can be discarded before being processed further.
+type SemanticValidator interface {
+	// ValidateSemantics returns an error if the fields of the message are
+	// inconsistent.
+	ValidateSemantics() error
+}
+
+// ValidateSemantics validates the semantics of the given message if it
+// implements the SemanticValidator interface. Other messages are considered
+// valid.
+func ValidateSemantics(msg Message) error {
+	validator, ok := msg.(SemanticValidator)
+	if !ok {
+		return nil
+	}
+
+	return validator.ValidateSemantics()
+}
+
+// validateForwardingPolicy checks that the forwarding policy of a channel
+// update is internally consistent.
+func validateForwardingPolicy(policy *ForwardingPolicy) error {
+	switch {
+	case !policy.HasMaxHTLC:
+		return ErrMissingMaxHtlc
+
+	case policy.MaxHTLC == 0:
+		return ErrZeroMaxHtlc
+
+	case policy.MinHTLC > policy.MaxHTLC:
+		return fmt.Errorf("%w: %v > %v", ErrMinHtlcExceedsMax,
+			policy.MinHTLC, policy.MaxHTLC)
+
+	case policy.MaxHTLC > maxSupplyMsat:
+		return fmt.Errorf("%w: %v", ErrMaxHtlcExceedsSupply,
+			policy.MaxHTLC)
+	}
+
+	// The proportional fee of the maximum HTLC may overflow 64 bits, so
+	// it is computed with arbitrary precision.
+	fee := new(big.Int).SetUint64(uint64(policy.MaxHTLC))
+	fee.Mul(fee, new(big.Int).SetUint64(uint64(policy.FeeRate)))
+	fee.Quo(fee, big.NewInt(1_000_000))
+	fee.Add(fee, new(big.Int).SetUint64(uint64(policy.BaseFee)))
+
+	if fee.Cmp(new(big.Int).SetUint64(uint64(maxSupplyMsat))) > 0 {
+		return fmt.Errorf("%w: base_fee=%v, fee_rate=%v, "+
+			"htlc_maximum_msat=%v", ErrExcessiveFee,
+			uint64(policy.BaseFee), uint64(policy.FeeRate),
+			policy.MaxHTLC)
+	}
+
+	return nil
+}
+
+// ValidateSemantics checks that the HTLC limits and fees of the update are
+// consistent with each other.
+//
+// NOTE: Part of the SemanticValidator interface.
+func (a *ChannelUpdate1) ValidateSemantics() error {
+	return validateForwardingPolicy(a.ForwardingPolicy())
+}
+
+// ValidateSemantics checks that the HTLC limits and fees of the update are
+// consistent with each other.
+//
+// NOTE: Part of the SemanticValidator interface.
+func (c *ChannelUpdate2) ValidateSemantics() error {
+	return validateForwardingPolicy(c.ForwardingPolicy())
+}
+
+// A compile time check to ensure the channel updates implement the
+// SemanticValidator interface.
+var (
+	_ SemanticValidator = (*ChannelUpdate1)(nil)
+	_ SemanticValidator = (*ChannelUpdate2)(nil)
+)
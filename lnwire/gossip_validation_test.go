@@ -0,0 +1,122 @@
+package lnwire
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/tlv"
+	"github.com/stretchr/testify/require"
+)
+
+// TestChannelUpdateSemantics asserts that channel updates with inconsistent
+// HTLC limits or fees are rejected with the matching error.
+func TestChannelUpdateSemantics(t *testing.T) {
+	t.Parallel()
+
+	validUpdate := func() *ChannelUpdate1 {
+		return &ChannelUpdate1{
+			MessageFlags:    ChanUpdateRequiredMaxHtlc,
+			HtlcMinimumMsat: 1_000,
+			HtlcMaximumMsat: 100_000_000,
+			BaseFee:         1_000,
+			FeeRate:         1,
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*ChannelUpdate1)
+		err    error
+	}{
+		{
+			name:   "valid",
+			modify: func(*ChannelUpdate1) {},
+		},
+		{
+			name: "min equals max",
+			modify: func(u *ChannelUpdate1) {
+				u.HtlcMinimumMsat = u.HtlcMaximumMsat
+			},
+		},
+		{
+			name: "missing max htlc",
+			modify: func(u *ChannelUpdate1) {
+				u.MessageFlags = 0
+			},
+			err: ErrMissingMaxHtlc,
+		},
+		{
+			name: "zero max htlc",
+			modify: func(u *ChannelUpdate1) {
+				u.HtlcMinimumMsat = 0
+				u.HtlcMaximumMsat = 0
+			},
+			err: ErrZeroMaxHtlc,
+		},
+		{
+			name: "min exceeds max",
+			modify: func(u *ChannelUpdate1) {
+				u.HtlcMinimumMsat = u.HtlcMaximumMsat + 1
+			},
+			err: ErrMinHtlcExceedsMax,
+		},
+		{
+			name: "max exceeds supply",
+			modify: func(u *ChannelUpdate1) {
+				u.HtlcMaximumMsat = maxSupplyMsat + 1
+			},
+			err: ErrMaxHtlcExceedsSupply,
+		},
+		{
+			name: "high but payable fee",
+			modify: func(u *ChannelUpdate1) {
+				u.BaseFee = math.MaxUint32
+				u.FeeRate = 1_000_000
+			},
+		},
+		{
+			name: "fee exceeds supply",
+			modify: func(u *ChannelUpdate1) {
+				u.HtlcMaximumMsat = maxSupplyMsat
+				u.FeeRate = math.MaxUint32
+			},
+			err: ErrExcessiveFee,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			update := validUpdate()
+			test.modify(update)
+
+			err := ValidateSemantics(update)
+			require.ErrorIs(t, err, test.err)
+		})
+	}
+}
+
+// TestChannelUpdate2Semantics asserts that the semantic validation applies to
+// ChannelUpdate2 messages, and that messages without semantic checks are
+// considered valid.
+func TestChannelUpdate2Semantics(t *testing.T) {
+	t.Parallel()
+
+	update := &ChannelUpdate2{
+		HTLCMinimumMsat: tlv.NewPrimitiveRecord[tlv.TlvType12](
+			MilliSatoshi(2_000),
+		),
+		HTLCMaximumMsat: tlv.NewPrimitiveRecord[tlv.TlvType14](
+			MilliSatoshi(1_000),
+		),
+	}
+	require.ErrorIs(t, ValidateSemantics(update), ErrMinHtlcExceedsMax)
+
+	update.HTLCMaximumMsat.Val = 2_000
+	require.NoError(t, ValidateSemantics(update))
+
+	require.NoError(t, ValidateSemantics(&Ping{}))
+}
@@ -0,0 +1,85 @@
+package lnwire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+// readDataWithLength reads a byte slice that is prefixed with its length as a
+// 2-byte big-endian integer, as written by writeDataWithLength.
+func readDataWithLength(r io.Reader) ([]byte, error) {
+	var l [2]byte
+	if _, err := io.ReadFull(r, l[:]); err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, binary.BigEndian.Uint16(l[:]))
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// writeWitness serializes the witness stack in the bitcoin wire format and
+// writes it to the buffer prefixed with its length.
+func writeWitness(buf *bytes.Buffer, witness wire.TxWitness) error {
+	var b bytes.Buffer
+	if err := wire.WriteVarInt(&b, 0, uint64(len(witness))); err != nil {
+		return err
+	}
+	for _, item := range witness {
+		if err := wire.WriteVarBytes(&b, 0, item); err != nil {
+			return err
+		}
+	}
+
+	if b.Len() > MaxSliceLength {
+		return fmt.Errorf("witness of %d bytes is too large", b.Len())
+	}
+
+	return writeDataWithLength(buf, b.Bytes())
+}
+
+// readWitness reads a length prefixed witness stack in the bitcoin wire
+// format, as written by writeWitness.
+func readWitness(r io.Reader) (wire.TxWitness, error) {
+	data, err := readDataWithLength(r)
+	if err != nil {
+		return nil, err
+	}
+
+	witnessReader := bytes.NewReader(data)
+	numItems, err := wire.ReadVarInt(witnessReader, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each item takes up at least one byte, which bounds the number of
+	// items we allocate for.
+	if numItems > uint64(witnessReader.Len()) {
+		return nil, fmt.Errorf("witness with %d items exceeds its "+
+			"length", numItems)
+	}
+
+	witness := make(wire.TxWitness, numItems)
+	for i := range witness {
+		witness[i], err = wire.ReadVarBytes(
+			witnessReader, 0, MaxSliceLength, "witness item",
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if witnessReader.Len() != 0 {
+		return nil, fmt.Errorf("witness has %d trailing bytes",
+			witnessReader.Len())
+	}
+
+	return witness, nil
+}
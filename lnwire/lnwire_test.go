@@ -144,6 +144,27 @@ func randPubKey() (*btcec.PublicKey, error) {
 	return priv.PubKey(), nil
 }
 
+// randPubKeys sets each of the passed keys to a random public key.
+func randPubKeys(t *testing.T, keys ...**btcec.PublicKey) {
+	for _, key := range keys {
+		var err error
+		*key, err = randPubKey()
+		if err != nil {
+			t.Fatalf("unable to generate key: %v", err)
+		}
+	}
+}
+
+// randBytes returns a random byte slice with a length between 1 and maxLen.
+func randBytes(t *testing.T, r *rand.Rand, maxLen int) []byte {
+	b := make([]byte, r.Intn(maxLen)+1)
+	if _, err := r.Read(b); err != nil {
+		t.Fatalf("unable to generate bytes: %v", err)
+	}
+
+	return b
+}
+
 // pubkeyFromHex parses a Bitcoin public key from a hex encoded string.
 func pubkeyFromHex(keyHex string) (*btcec.PublicKey, error) {
 	pubKeyBytes, err := hex.DecodeString(keyHex)
@@ -973,6 +994,13 @@ func TestLightningWireProtocol(t *testing.T) {
 				req.PartialSig = somePartialSigWithNonce(t, r)
 			}
 
+			// 50/50 chance to make the message part of a batch.
+			if r.Int31()%2 == 0 {
+				req.BatchSize = NewCommitSigBatchSize(
+					uint16(r.Int31n(10) + 1),
+				)
+			}
+
 			v[0] = reflect.ValueOf(*req)
 		},
 		MsgRevokeAndAck: func(v []reflect.Value, r *rand.Rand) {
@@ -1441,6 +1469,314 @@ func TestLightningWireProtocol(t *testing.T) {
 
 			v[0] = reflect.ValueOf(req)
 		},
+		MsgOpenChannel2: func(v []reflect.Value, r *rand.Rand) {
+			req := OpenChannel2{
+				FundingFeePerKw:  r.Uint32(),
+				CommitFeePerKw:   r.Uint32(),
+				FundingAmount:    btcutil.Amount(r.Int63()),
+				DustLimit:        btcutil.Amount(r.Int63()),
+				MaxValueInFlight: MilliSatoshi(r.Int63()),
+				HtlcMinimum:      MilliSatoshi(r.Int31()),
+				CsvDelay:         uint16(r.Int31()),
+				MaxAcceptedHTLCs: uint16(r.Int31()),
+				LockTime:         r.Uint32(),
+				ChannelFlags:     FundingFlag(uint8(r.Int31())),
+			}
+			if _, err := r.Read(req.ChainHash[:]); err != nil {
+				t.Fatalf("unable to generate chain hash: %v",
+					err)
+			}
+			_, err := r.Read(req.PendingChannelID[:])
+			if err != nil {
+				t.Fatalf("unable to generate pending chan "+
+					"id: %v", err)
+			}
+
+			randPubKeys(
+				t, &req.FundingKey, &req.RevocationPoint,
+				&req.PaymentPoint, &req.DelayedPaymentPoint,
+				&req.HtlcPoint, &req.FirstCommitmentPoint,
+				&req.SecondCommitmentPoint,
+			)
+
+			// 1/2 chance of TLV records.
+			if r.Intn(2) == 0 {
+				script, err := randDeliveryAddress(r)
+				if err != nil {
+					t.Fatalf("unable to generate delivery "+
+						"address: %v", err)
+				}
+				req.UpfrontShutdownScript = script
+
+				req.ChannelType = new(ChannelType)
+				*req.ChannelType = ChannelType(
+					*randRawFeatureVector(r),
+				)
+
+				req.RequireConfirmedInputs = tlv.SomeRecordT(
+					tlv.ZeroRecordT[
+						tlv.TlvType2, TrueBoolean,
+					](),
+				)
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgAcceptChannel2: func(v []reflect.Value, r *rand.Rand) {
+			req := AcceptChannel2{
+				FundingAmount:    btcutil.Amount(r.Int63()),
+				DustLimit:        btcutil.Amount(r.Int63()),
+				MaxValueInFlight: MilliSatoshi(r.Int63()),
+				HtlcMinimum:      MilliSatoshi(r.Int31()),
+				MinAcceptDepth:   uint32(r.Int31()),
+				CsvDelay:         uint16(r.Int31()),
+				MaxAcceptedHTLCs: uint16(r.Int31()),
+			}
+			_, err := r.Read(req.PendingChannelID[:])
+			if err != nil {
+				t.Fatalf("unable to generate pending chan "+
+					"id: %v", err)
+			}
+
+			randPubKeys(
+				t, &req.FundingKey, &req.RevocationPoint,
+				&req.PaymentPoint, &req.DelayedPaymentPoint,
+				&req.HtlcPoint, &req.FirstCommitmentPoint,
+				&req.SecondCommitmentPoint,
+			)
+
+			// 1/2 chance of TLV records.
+			if r.Intn(2) == 0 {
+				script, err := randDeliveryAddress(r)
+				if err != nil {
+					t.Fatalf("unable to generate delivery "+
+						"address: %v", err)
+				}
+				req.UpfrontShutdownScript = script
+
+				req.ChannelType = new(ChannelType)
+				*req.ChannelType = ChannelType(
+					*randRawFeatureVector(r),
+				)
+
+				req.RequireConfirmedInputs = tlv.SomeRecordT(
+					tlv.ZeroRecordT[
+						tlv.TlvType2, TrueBoolean,
+					](),
+				)
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgTxAddInput: func(v []reflect.Value, r *rand.Rand) {
+			req := TxAddInput{
+				SerialID:   r.Uint64(),
+				PrevTx:     randBytes(t, r, 1000),
+				PrevTxVout: r.Uint32(),
+				Sequence:   r.Uint32(),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate ChanID: %v", err)
+			}
+
+			// 1/2 chance additional TLV data.
+			if r.Intn(2) == 0 {
+				req.ExtraData = []byte{0xfd, 0x00, 0xff, 0x00}
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgTxAddOutput: func(v []reflect.Value, r *rand.Rand) {
+			req := TxAddOutput{
+				SerialID: r.Uint64(),
+				Amount:   btcutil.Amount(r.Int63()),
+				PkScript: randBytes(t, r, 100),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate ChanID: %v", err)
+			}
+
+			// 1/2 chance additional TLV data.
+			if r.Intn(2) == 0 {
+				req.ExtraData = []byte{0xfd, 0x00, 0xff, 0x00}
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgTxRemoveInput: func(v []reflect.Value, r *rand.Rand) {
+			req := TxRemoveInput{
+				SerialID: r.Uint64(),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate ChanID: %v", err)
+			}
+
+			// 1/2 chance additional TLV data.
+			if r.Intn(2) == 0 {
+				req.ExtraData = []byte{0xfd, 0x00, 0xff, 0x00}
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgTxRemoveOutput: func(v []reflect.Value, r *rand.Rand) {
+			req := TxRemoveOutput{
+				SerialID: r.Uint64(),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate ChanID: %v", err)
+			}
+
+			// 1/2 chance additional TLV data.
+			if r.Intn(2) == 0 {
+				req.ExtraData = []byte{0xfd, 0x00, 0xff, 0x00}
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgTxComplete: func(v []reflect.Value, r *rand.Rand) {
+			req := TxComplete{}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate ChanID: %v", err)
+			}
+
+			// 1/2 chance additional TLV data.
+			if r.Intn(2) == 0 {
+				req.ExtraData = []byte{0xfd, 0x00, 0xff, 0x00}
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgTxSignatures: func(v []reflect.Value, r *rand.Rand) {
+			req := TxSignatures{}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate ChanID: %v", err)
+			}
+			if _, err := r.Read(req.TxID[:]); err != nil {
+				t.Fatalf("unable to generate txid: %v", err)
+			}
+
+			numWitnesses := r.Intn(4)
+			for i := 0; i < numWitnesses; i++ {
+				witness := make(wire.TxWitness, r.Intn(3)+1)
+				for j := range witness {
+					witness[j] = randBytes(t, r, 100)
+				}
+				req.Witnesses = append(req.Witnesses, witness)
+			}
+
+			// 1/2 chance additional TLV data.
+			if r.Intn(2) == 0 {
+				req.ExtraData = []byte{0xfd, 0x00, 0xff, 0x00}
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgTxAbort: func(v []reflect.Value, r *rand.Rand) {
+			req := TxAbort{
+				Data: randBytes(t, r, 100),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate ChanID: %v", err)
+			}
+
+			// 1/2 chance additional TLV data.
+			if r.Intn(2) == 0 {
+				req.ExtraData = []byte{0xfd, 0x00, 0xff, 0x00}
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgOnionMessage: func(v []reflect.Value, r *rand.Rand) {
+			req := OnionMessage{
+				OnionBlob: randBytes(t, r, 1000),
+			}
+			randPubKeys(t, &req.BlindingPoint)
+
+			// 1/2 chance additional TLV data.
+			if r.Intn(2) == 0 {
+				req.ExtraData = []byte{0xfd, 0x00, 0xff, 0x00}
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgSpliceInit: func(v []reflect.Value, r *rand.Rand) {
+			req := SpliceInit{
+				FundingContribution: btcutil.Amount(r.Int63()),
+				FundingFeePerKw:     r.Uint32(),
+				Locktime:            r.Uint32(),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate ChanID: %v", err)
+			}
+
+			// 1/2 chance of removing funds from the channel.
+			if r.Intn(2) == 0 {
+				req.FundingContribution *= -1
+			}
+
+			var err error
+			req.FundingPubKey, err = randPubKey()
+			if err != nil {
+				t.Fatalf("unable to generate key: %v", err)
+			}
+
+			// 1/2 chance of requiring confirmed inputs.
+			if r.Intn(2) == 0 {
+				req.RequireConfirmedInputs = tlv.SomeRecordT(
+					tlv.ZeroRecordT[
+						tlv.TlvType2, TrueBoolean,
+					](),
+				)
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgSpliceAck: func(v []reflect.Value, r *rand.Rand) {
+			req := SpliceAck{
+				FundingContribution: btcutil.Amount(r.Int63()),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate ChanID: %v", err)
+			}
+
+			// 1/2 chance of removing funds from the channel.
+			if r.Intn(2) == 0 {
+				req.FundingContribution *= -1
+			}
+
+			var err error
+			req.FundingPubKey, err = randPubKey()
+			if err != nil {
+				t.Fatalf("unable to generate key: %v", err)
+			}
+
+			// 1/2 chance of requiring confirmed inputs.
+			if r.Intn(2) == 0 {
+				req.RequireConfirmedInputs = tlv.SomeRecordT(
+					tlv.ZeroRecordT[
+						tlv.TlvType2, TrueBoolean,
+					](),
+				)
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgSpliceLocked: func(v []reflect.Value, r *rand.Rand) {
+			req := SpliceLocked{}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate ChanID: %v", err)
+			}
+			if _, err := r.Read(req.SpliceTxid[:]); err != nil {
+				t.Fatalf("unable to generate txid: %v", err)
+			}
+
+			// 1/2 chance additional TLV data.
+			if r.Intn(2) == 0 {
+				req.ExtraData = []byte{0xfd, 0x00, 0xff, 0x00}
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
 		MsgUpdateAddHTLC: func(v []reflect.Value, r *rand.Rand) {
 			req := &UpdateAddHTLC{
 				ID:     r.Uint64(),
@@ -1900,6 +2236,84 @@ func TestLightningWireProtocol(t *testing.T) {
 				return mainScenario(&m)
 			},
 		},
+		{
+			msgType: MsgOpenChannel2,
+			scenario: func(m OpenChannel2) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgAcceptChannel2,
+			scenario: func(m AcceptChannel2) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxAddInput,
+			scenario: func(m TxAddInput) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxAddOutput,
+			scenario: func(m TxAddOutput) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxRemoveInput,
+			scenario: func(m TxRemoveInput) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxRemoveOutput,
+			scenario: func(m TxRemoveOutput) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxComplete,
+			scenario: func(m TxComplete) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxSignatures,
+			scenario: func(m TxSignatures) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxAbort,
+			scenario: func(m TxAbort) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgOnionMessage,
+			scenario: func(m OnionMessage) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgSpliceInit,
+			scenario: func(m SpliceInit) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgSpliceAck,
+			scenario: func(m SpliceAck) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgSpliceLocked,
+			scenario: func(m SpliceLocked) bool {
+				return mainScenario(&m)
+			},
+		},
 		{
 			msgType: MsgAnnounceSignatures2,
 			scenario: func(m AnnounceSignatures2) bool {
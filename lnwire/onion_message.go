@@ -0,0 +1,82 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// OnionMessage is sent to relay a message through the network along a path
+// of blinded hops, without the need for a payment. It's the transport for
+// BOLT 12 offers.
+type OnionMessage struct {
+	// BlindingPoint is the path key that the receiver uses to derive the
+	// shared secret that decrypts its blinded route data.
+	BlindingPoint *btcec.PublicKey
+
+	// OnionBlob is the serialized onion packet. Unlike the onion of an
+	// htlc, its length isn't fixed.
+	OnionBlob []byte
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure OnionMessage implements the lnwire.Message
+// interface.
+var _ Message = (*OnionMessage)(nil)
+
+// Encode serializes the target OnionMessage into the passed io.Writer.
+// Serialization will observe the rules defined by the passed protocol version.
+//
+// This is a part of the lnwire.Message interface.
+func (o *OnionMessage) Encode(w *bytes.Buffer, _ uint32) error {
+	if err := WritePublicKey(w, o.BlindingPoint); err != nil {
+		return err
+	}
+
+	if err := writeDataWithLength(w, o.OnionBlob); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, o.ExtraData)
+}
+
+// Decode deserializes the serialized OnionMessage stored in the passed
+// io.Reader into the target OnionMessage using the deserialization rules
+// defined by the passed protocol version.
+//
+// This is a part of the lnwire.Message interface.
+func (o *OnionMessage) Decode(r io.Reader, _ uint32) error {
+	if err := ReadElements(r, &o.BlindingPoint); err != nil {
+		return err
+	}
+
+	onionBlob, err := readDataWithLength(r)
+	if err != nil {
+		return err
+	}
+	o.OnionBlob = onionBlob
+
+	if err := ReadElements(r, &o.ExtraData); err != nil {
+		return err
+	}
+
+	// This is required to pass the fuzz test round trip equality check.
+	if len(o.ExtraData) == 0 {
+		o.ExtraData = nil
+	}
+
+	return nil
+}
+
+// MsgType returns the MessageType code which uniquely identifies this message
+// as an OnionMessage on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (o *OnionMessage) MsgType() MessageType {
+	return MsgOnionMessage
+}
@@ -0,0 +1,272 @@
+package lnwire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// ReplyPathType is the onion message payload type of the blinded path
+	// that the recipient should use to reply.
+	ReplyPathType tlv.Type = 2
+
+	// EncryptedDataType is the onion message payload type of the
+	// encrypted recipient data of a blinded hop.
+	EncryptedDataType tlv.Type = 4
+
+	// InvoiceRequestType is the onion message payload type of a BOLT 12
+	// invoice_request.
+	InvoiceRequestType tlv.Type = 64
+
+	// InvoiceType is the onion message payload type of a BOLT 12 invoice.
+	InvoiceType tlv.Type = 66
+
+	// InvoiceErrorType is the onion message payload type of a BOLT 12
+	// invoice_error.
+	InvoiceErrorType tlv.Type = 68
+
+	// maxBlindedPathHops is the maximum number of hops of a blinded path,
+	// which is bound by the single byte used to encode the hop count.
+	maxBlindedPathHops = 255
+)
+
+// BlindedHop is a single hop of a blinded path.
+type BlindedHop struct {
+	// BlindedNodeID is the blinded public key of the hop's node.
+	BlindedNodeID *btcec.PublicKey
+
+	// EncryptedData is the route data that only the hop's node can
+	// decrypt.
+	EncryptedData []byte
+}
+
+// BlindedPath is a blinded route to a node, as used for the reply path of
+// onion messages and for BOLT 12 offers.
+type BlindedPath struct {
+	// IntroductionNode is the public key of the first node of the path,
+	// which isn't blinded.
+	IntroductionNode *btcec.PublicKey
+
+	// BlindingPoint is the first path key, which the introduction node
+	// uses to decrypt its route data.
+	BlindingPoint *btcec.PublicKey
+
+	// Hops are the blinded hops of the path, starting with the
+	// introduction node.
+	Hops []BlindedHop
+}
+
+// Encode serializes the blinded path into the given writer.
+func (b *BlindedPath) Encode(w io.Writer) error {
+	if len(b.Hops) == 0 || len(b.Hops) > maxBlindedPathHops {
+		return fmt.Errorf("blinded path must have between 1 and %d "+
+			"hops, has %d", maxBlindedPathHops, len(b.Hops))
+	}
+
+	_, err := w.Write(b.IntroductionNode.SerializeCompressed())
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(b.BlindingPoint.SerializeCompressed())
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write([]byte{uint8(len(b.Hops))}); err != nil {
+		return err
+	}
+
+	for _, hop := range b.Hops {
+		_, err := w.Write(hop.BlindedNodeID.SerializeCompressed())
+		if err != nil {
+			return err
+		}
+
+		if len(hop.EncryptedData) > MaxSliceLength {
+			return fmt.Errorf("encrypted data of %d bytes is too "+
+				"large", len(hop.EncryptedData))
+		}
+
+		var l [2]byte
+		dataLen := uint16(len(hop.EncryptedData))
+		binary.BigEndian.PutUint16(l[:], dataLen)
+		if _, err := w.Write(l[:]); err != nil {
+			return err
+		}
+
+		if _, err := w.Write(hop.EncryptedData); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Decode deserializes a blinded path from the given reader.
+func (b *BlindedPath) Decode(r io.Reader) error {
+	var err error
+	b.IntroductionNode, err = readPubKey(r)
+	if err != nil {
+		return err
+	}
+
+	b.BlindingPoint, err = readPubKey(r)
+	if err != nil {
+		return err
+	}
+
+	var numHops [1]byte
+	if _, err := io.ReadFull(r, numHops[:]); err != nil {
+		return err
+	}
+	if numHops[0] == 0 {
+		return fmt.Errorf("blinded path has no hops")
+	}
+
+	b.Hops = make([]BlindedHop, numHops[0])
+	for i := range b.Hops {
+		b.Hops[i].BlindedNodeID, err = readPubKey(r)
+		if err != nil {
+			return err
+		}
+
+		b.Hops[i].EncryptedData, err = readDataWithLength(r)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readPubKey reads a compressed public key from the reader.
+func readPubKey(r io.Reader) (*btcec.PublicKey, error) {
+	var pubKey [btcec.PubKeyBytesLenCompressed]byte
+	if _, err := io.ReadFull(r, pubKey[:]); err != nil {
+		return nil, err
+	}
+
+	return btcec.ParsePubKey(pubKey[:])
+}
+
+// OnionMessagePayload is the payload of an onion message for a single hop,
+// which is carried in the hop's onion packet as a TLV stream.
+type OnionMessagePayload struct {
+	// ReplyPath is the blinded path that the recipient should use to
+	// reply, if any.
+	ReplyPath *BlindedPath
+
+	// EncryptedData is the hop's encrypted recipient data of the blinded
+	// path the message is sent along.
+	EncryptedData []byte
+
+	// InvoiceRequest is a serialized BOLT 12 invoice_request TLV stream.
+	// It's only set in the payload of the final hop.
+	InvoiceRequest []byte
+
+	// Invoice is a serialized BOLT 12 invoice TLV stream. It's only set
+	// in the payload of the final hop.
+	Invoice []byte
+
+	// InvoiceError is a serialized BOLT 12 invoice_error TLV stream. It's
+	// only set in the payload of the final hop.
+	InvoiceError []byte
+}
+
+// Encode serializes the payload into the given writer as a TLV stream.
+func (p *OnionMessagePayload) Encode(w io.Writer) error {
+	var records []tlv.Record
+
+	if p.ReplyPath != nil {
+		var b bytes.Buffer
+		if err := p.ReplyPath.Encode(&b); err != nil {
+			return err
+		}
+
+		replyPath := b.Bytes()
+		records = append(records, tlv.MakePrimitiveRecord(
+			ReplyPathType, &replyPath,
+		))
+	}
+
+	fields := []struct {
+		recordType tlv.Type
+		value      *[]byte
+	}{
+		{EncryptedDataType, &p.EncryptedData},
+		{InvoiceRequestType, &p.InvoiceRequest},
+		{InvoiceType, &p.Invoice},
+		{InvoiceErrorType, &p.InvoiceError},
+	}
+	for _, field := range fields {
+		if *field.value == nil {
+			continue
+		}
+
+		records = append(records, tlv.MakePrimitiveRecord(
+			field.recordType, field.value,
+		))
+	}
+
+	stream, err := tlv.NewStream(records...)
+	if err != nil {
+		return err
+	}
+
+	return stream.Encode(w)
+}
+
+// Decode deserializes the payload from the given TLV stream. Unknown odd
+// types are ignored, while unknown even types result in an error.
+func (p *OnionMessagePayload) Decode(r io.Reader) error {
+	var replyPath []byte
+	stream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(ReplyPathType, &replyPath),
+		tlv.MakePrimitiveRecord(EncryptedDataType, &p.EncryptedData),
+		tlv.MakePrimitiveRecord(
+			InvoiceRequestType, &p.InvoiceRequest,
+		),
+		tlv.MakePrimitiveRecord(InvoiceType, &p.Invoice),
+		tlv.MakePrimitiveRecord(InvoiceErrorType, &p.InvoiceError),
+	)
+	if err != nil {
+		return err
+	}
+
+	parsedTypes, err := stream.DecodeWithParsedTypesP2P(r)
+	if err != nil {
+		return err
+	}
+
+	// The stream ignores all unknown types, so we need to reject the
+	// unknown even ones ourselves. Only unknown types carry their value in
+	// the type map.
+	for recordType, value := range parsedTypes {
+		if value != nil && recordType%2 == 0 {
+			return fmt.Errorf("unknown required onion message "+
+				"payload type %d", recordType)
+		}
+	}
+
+	if _, ok := parsedTypes[ReplyPathType]; ok {
+		replyPathReader := bytes.NewReader(replyPath)
+
+		p.ReplyPath = &BlindedPath{}
+		if err := p.ReplyPath.Decode(replyPathReader); err != nil {
+			return fmt.Errorf("invalid reply path: %w", err)
+		}
+
+		if replyPathReader.Len() != 0 {
+			return fmt.Errorf("reply path has %d trailing bytes",
+				replyPathReader.Len())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package lnwire
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOnionMessagePayloadEncodeDecode tests that onion message payloads round
+// trip, and that unknown even types are rejected.
+func TestOnionMessagePayloadEncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	pubKey := func() *btcec.PublicKey {
+		key, err := randPubKey()
+		require.NoError(t, err)
+
+		return key
+	}
+
+	testCases := []struct {
+		name    string
+		payload OnionMessagePayload
+	}{
+		{
+			name:    "empty payload",
+			payload: OnionMessagePayload{},
+		},
+		{
+			name: "forwarding hop",
+			payload: OnionMessagePayload{
+				EncryptedData: []byte{1, 2, 3},
+			},
+		},
+		{
+			name: "final hop with invoice request",
+			payload: OnionMessagePayload{
+				ReplyPath: &BlindedPath{
+					IntroductionNode: pubKey(),
+					BlindingPoint:    pubKey(),
+					Hops: []BlindedHop{{
+						BlindedNodeID: pubKey(),
+						EncryptedData: []byte{4, 5},
+					}, {
+						BlindedNodeID: pubKey(),
+						EncryptedData: []byte{6},
+					}},
+				},
+				EncryptedData:  []byte{7},
+				InvoiceRequest: []byte{8, 9},
+			},
+		},
+		{
+			name: "final hop with invoice and error",
+			payload: OnionMessagePayload{
+				Invoice:      []byte{10},
+				InvoiceError: []byte{11},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			var b bytes.Buffer
+			require.NoError(t, testCase.payload.Encode(&b))
+
+			var decoded OnionMessagePayload
+			require.NoError(t, decoded.Decode(&b))
+			require.Equal(t, testCase.payload, decoded)
+		})
+	}
+
+	// An unknown even type can't be decoded, an unknown odd type is
+	// ignored.
+	var payload OnionMessagePayload
+	err := payload.Decode(bytes.NewReader([]byte{0x06, 0x01, 0x00}))
+	require.Error(t, err)
+
+	err = payload.Decode(bytes.NewReader([]byte{0x07, 0x01, 0x00}))
+	require.NoError(t, err)
+}
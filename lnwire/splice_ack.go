@@ -0,0 +1,119 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+)
+
+// SpliceAck is sent by the acceptor of a splice in response to SpliceInit. It's
+// the draft BOLT 2 splice_ack message.
+type SpliceAck struct {
+	// ChanID identifies the channel that is to be spliced.
+	ChanID ChannelID
+
+	// FundingContribution is the amount the acceptor adds to the channel,
+	// or removes from it if negative.
+	FundingContribution btcutil.Amount
+
+	// FundingPubKey is the acceptor's key for the new funding output.
+	FundingPubKey *btcec.PublicKey
+
+	// RequireConfirmedInputs, if set, indicates that the initiator must
+	// only contribute confirmed inputs to the splice transaction.
+	RequireConfirmedInputs OptRequireConfirmedInputsTLV
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure SpliceAck implements the lnwire.Message
+// interface.
+var _ Message = (*SpliceAck)(nil)
+
+// Encode serializes the target SpliceAck into the passed io.Writer.
+// Serialization will observe the rules defined by the passed protocol version.
+//
+// This is a part of the lnwire.Message interface.
+func (s *SpliceAck) Encode(w *bytes.Buffer, _ uint32) error {
+	if err := WriteChannelID(w, s.ChanID); err != nil {
+		return err
+	}
+
+	if err := WriteSatoshi(w, s.FundingContribution); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, s.FundingPubKey); err != nil {
+		return err
+	}
+
+	err := EncodeMessageExtraData(
+		&s.ExtraData, requireConfirmedInputsRecords(
+			s.RequireConfirmedInputs,
+		)...,
+	)
+	if err != nil {
+		return err
+	}
+
+	return WriteBytes(w, s.ExtraData)
+}
+
+// Decode deserializes the serialized SpliceAck stored in the passed io.Reader
+// into the target SpliceAck using the deserialization rules defined by the
+// passed protocol version.
+//
+// This is a part of the lnwire.Message interface.
+func (s *SpliceAck) Decode(r io.Reader, _ uint32) error {
+	err := ReadElements(r,
+		&s.ChanID,
+		&s.FundingContribution,
+		&s.FundingPubKey,
+	)
+	if err != nil {
+		return err
+	}
+
+	var tlvRecords ExtraOpaqueData
+	if err := ReadElements(r, &tlvRecords); err != nil {
+		return err
+	}
+
+	s.RequireConfirmedInputs, err = decodeRequireConfirmedInputs(
+		tlvRecords,
+	)
+	if err != nil {
+		return err
+	}
+
+	if len(tlvRecords) != 0 {
+		s.ExtraData = tlvRecords
+	}
+
+	return nil
+}
+
+// MsgType returns the MessageType code which uniquely identifies this message
+// as a SpliceAck on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceAck) MsgType() MessageType {
+	return MsgSpliceAck
+}
+
+// A compile time check to ensure SpliceAck implements the
+// lnwire.LinkUpdater interface.
+var _ LinkUpdater = (*SpliceAck)(nil)
+
+// TargetChanID returns the channel id of the link for which this message is
+// intended.
+//
+// NOTE: Part of peer.LinkUpdater interface.
+func (s *SpliceAck) TargetChanID() ChannelID {
+	return s.ChanID
+}
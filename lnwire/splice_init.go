@@ -0,0 +1,184 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+type (
+	// RequireConfirmedInputsTLV is the require_confirmed_inputs TLV of
+	// the splice messages. Its presence indicates that the receiver must
+	// only contribute confirmed inputs.
+	RequireConfirmedInputsTLV = tlv.RecordT[tlv.TlvType2, TrueBoolean]
+
+	// OptRequireConfirmedInputsTLV is the optional
+	// require_confirmed_inputs TLV of the splice messages.
+	OptRequireConfirmedInputsTLV = tlv.OptionalRecordT[
+		tlv.TlvType2, TrueBoolean,
+	]
+)
+
+// SpliceInit is sent by the initiator of a splice to propose changing the
+// funding output of a quiescent channel. It's the draft BOLT 2 splice_init
+// message.
+type SpliceInit struct {
+	// ChanID identifies the channel that is to be spliced.
+	ChanID ChannelID
+
+	// FundingContribution is the amount the initiator adds to the channel,
+	// or removes from it if negative.
+	FundingContribution btcutil.Amount
+
+	// FundingFeePerKw is the fee rate in sat/kw that the initiator wants
+	// to use for the splice transaction.
+	FundingFeePerKw uint32
+
+	// Locktime is the locktime of the splice transaction.
+	Locktime uint32
+
+	// FundingPubKey is the initiator's key for the new funding output.
+	FundingPubKey *btcec.PublicKey
+
+	// RequireConfirmedInputs, if set, indicates that the acceptor must
+	// only contribute confirmed inputs to the splice transaction.
+	RequireConfirmedInputs OptRequireConfirmedInputsTLV
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure SpliceInit implements the lnwire.Message
+// interface.
+var _ Message = (*SpliceInit)(nil)
+
+// Encode serializes the target SpliceInit into the passed io.Writer.
+// Serialization will observe the rules defined by the passed protocol version.
+//
+// This is a part of the lnwire.Message interface.
+func (s *SpliceInit) Encode(w *bytes.Buffer, _ uint32) error {
+	if err := WriteChannelID(w, s.ChanID); err != nil {
+		return err
+	}
+
+	if err := WriteSatoshi(w, s.FundingContribution); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, s.FundingFeePerKw); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, s.Locktime); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, s.FundingPubKey); err != nil {
+		return err
+	}
+
+	err := EncodeMessageExtraData(
+		&s.ExtraData, requireConfirmedInputsRecords(
+			s.RequireConfirmedInputs,
+		)...,
+	)
+	if err != nil {
+		return err
+	}
+
+	return WriteBytes(w, s.ExtraData)
+}
+
+// Decode deserializes the serialized SpliceInit stored in the passed
+// io.Reader into the target SpliceInit using the deserialization rules
+// defined by the passed protocol version.
+//
+// This is a part of the lnwire.Message interface.
+func (s *SpliceInit) Decode(r io.Reader, _ uint32) error {
+	err := ReadElements(r,
+		&s.ChanID,
+		&s.FundingContribution,
+		&s.FundingFeePerKw,
+		&s.Locktime,
+		&s.FundingPubKey,
+	)
+	if err != nil {
+		return err
+	}
+
+	var tlvRecords ExtraOpaqueData
+	if err := ReadElements(r, &tlvRecords); err != nil {
+		return err
+	}
+
+	s.RequireConfirmedInputs, err = decodeRequireConfirmedInputs(
+		tlvRecords,
+	)
+	if err != nil {
+		return err
+	}
+
+	if len(tlvRecords) != 0 {
+		s.ExtraData = tlvRecords
+	}
+
+	return nil
+}
+
+// MsgType returns the MessageType code which uniquely identifies this message
+// as a SpliceInit on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceInit) MsgType() MessageType {
+	return MsgSpliceInit
+}
+
+// A compile time check to ensure SpliceInit implements the
+// lnwire.LinkUpdater interface.
+var _ LinkUpdater = (*SpliceInit)(nil)
+
+// TargetChanID returns the channel id of the link for which this message is
+// intended.
+//
+// NOTE: Part of peer.LinkUpdater interface.
+func (s *SpliceInit) TargetChanID() ChannelID {
+	return s.ChanID
+}
+
+// requireConfirmedInputsRecords returns the record producers of the
+// require_confirmed_inputs TLV, which is only encoded if it's set.
+func requireConfirmedInputsRecords(
+	opt OptRequireConfirmedInputsTLV) []tlv.RecordProducer {
+
+	var recordProducers []tlv.RecordProducer
+	opt.WhenSome(func(r RequireConfirmedInputsTLV) {
+		recordProducers = append(recordProducers, &r)
+	})
+
+	return recordProducers
+}
+
+// decodeRequireConfirmedInputs extracts the require_confirmed_inputs TLV from
+// the given records. Its presence indicates "true".
+func decodeRequireConfirmedInputs(
+	tlvRecords ExtraOpaqueData) (OptRequireConfirmedInputsTLV, error) {
+
+	var opt OptRequireConfirmedInputsTLV
+
+	requireConfirmed := tlv.ZeroRecordT[tlv.TlvType2, TrueBoolean]()
+	typeMap, err := tlvRecords.ExtractRecords(&requireConfirmed)
+	if err != nil {
+		return opt, err
+	}
+
+	if _, ok := typeMap[opt.TlvType()]; ok {
+		opt = tlv.SomeRecordT(requireConfirmed)
+	}
+
+	return opt, nil
+}
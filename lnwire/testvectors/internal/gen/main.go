@@ -0,0 +1,45 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/lightningnetwork/lnd/lnwire/testvectors"
+)
+
+const defaultOutputFile = "vectors.json"
+
+func main() {
+	outputFile := flag.String(
+		"o", defaultOutputFile, "file to write the test vectors to",
+	)
+	flag.Parse()
+
+	if err := run(*outputFile); err != nil {
+		fmt.Fprintf(os.Stderr, "unable to generate test vectors: %v\n",
+			err)
+		os.Exit(1)
+	}
+}
+
+// run generates the test vectors, verifies that they round trip and writes
+// them to the output file.
+func run(outputFile string) error {
+	vectors, err := testvectors.Generate()
+	if err != nil {
+		return err
+	}
+
+	if err := testvectors.Verify(vectors); err != nil {
+		return err
+	}
+
+	vectorsJSON, err := json.MarshalIndent(vectors, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(outputFile, append(vectorsJSON, '\n'), 0644)
+}
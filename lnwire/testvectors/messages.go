@@ -0,0 +1,606 @@
+package testvectors
+
+import (
+	"net"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+// NamedMessage is a message of the registry together with the name of its
+// test vector.
+type NamedMessage struct {
+	// Name uniquely identifies the test vector of the message.
+	Name string
+
+	// Msg is the message.
+	Msg lnwire.Message
+}
+
+var (
+	// chainHash is the chain hash used by all messages.
+	chainHash = *chaincfg.MainNetParams.GenesisHash
+
+	// chanID is the channel ID used by all channel messages.
+	chanID = lnwire.ChannelID{0x01, 0x02, 0x03}
+
+	// scid is the short channel ID used by all messages.
+	scid = lnwire.NewShortChanIDFromInt(0x0000010000020003)
+
+	// pendingChanID is the pending channel ID used by the funding
+	// messages.
+	pendingChanID = [32]byte{0x04, 0x05, 0x06}
+
+	// pkScript is a P2WKH script used as delivery address and output
+	// script.
+	pkScript = append([]byte{0x00, 0x14}, make([]byte, 20)...)
+)
+
+// privKey returns the private key with the given scalar.
+func privKey(i byte) *btcec.PrivateKey {
+	var scalar [32]byte
+	scalar[31] = i
+
+	priv, _ := btcec.PrivKeyFromBytes(scalar[:])
+
+	return priv
+}
+
+// pubKey returns the public key of the private key with the given scalar.
+func pubKey(i byte) *btcec.PublicKey {
+	return privKey(i).PubKey()
+}
+
+// rawPubKey returns the compressed public key of the private key with the
+// given scalar.
+func rawPubKey(i byte) [33]byte {
+	var raw [33]byte
+	copy(raw[:], pubKey(i).SerializeCompressed())
+
+	return raw
+}
+
+// Messages returns the registry of messages that test vectors are generated
+// for. It contains every message type lnwire decodes, and additional vectors
+// for the messages' optional TLV extensions. All values are deterministic, so
+// the encoded vectors stay stable across runs.
+func Messages() ([]NamedMessage, error) {
+	// ECDSA signing is deterministic as the nonce is derived as specified
+	// by RFC6979.
+	sig, err := lnwire.NewSigFromSignature(ecdsa.Sign(
+		privKey(1), chainhash.DoubleHashB([]byte("lnwire test vector")),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	alias, err := lnwire.NewNodeAlias("test-vector")
+	if err != nil {
+		return nil, err
+	}
+
+	var partialSig btcec.ModNScalar
+	partialSig.SetInt(42)
+
+	chanType := lnwire.ChannelType(*lnwire.NewRawFeatureVector(
+		lnwire.StaticRemoteKeyRequired,
+		lnwire.AnchorsZeroFeeHtlcTxRequired,
+	))
+	leaseExpiry := lnwire.LeaseExpiry(1337)
+	aliasScid := lnwire.NewShortChanIDFromInt(0x0000000000000001)
+
+	requireConfirmed := tlv.SomeRecordT(
+		tlv.ZeroRecordT[tlv.TlvType2, lnwire.TrueBoolean](),
+	)
+	customRecords := lnwire.CustomRecords{
+		lnwire.MinCustomRecordsTlvType: []byte{0x01, 0x02},
+	}
+
+	closerSig := tlv.ZeroRecordT[tlv.TlvType1, lnwire.Sig]()
+	closerSig.Val = sig
+	bothSig := tlv.ZeroRecordT[tlv.TlvType3, lnwire.Sig]()
+	bothSig.Val = sig
+
+	blindingPoint := tlv.SomeRecordT(
+		tlv.NewPrimitiveRecord[lnwire.BlindingPointTlvType](pubKey(6)),
+	)
+	witness := wire.TxWitness{
+		sig.RawBytes(), pubKey(3).SerializeCompressed(),
+	}
+
+	// unknownOddTLV is an unknown odd TLV record (type 255, empty value)
+	// that decoders must preserve.
+	unknownOddTLV := lnwire.ExtraOpaqueData{0xfd, 0x00, 0xff, 0x00}
+
+	messages := []NamedMessage{
+		{"warning", &lnwire.Warning{
+			ChanID: chanID,
+			Data:   lnwire.WarningData("test warning"),
+		}},
+		{"stfu", &lnwire.Stfu{
+			ChanID:    chanID,
+			Initiator: true,
+		}},
+		{"init", &lnwire.Init{
+			GlobalFeatures: lnwire.NewRawFeatureVector(),
+			Features: lnwire.NewRawFeatureVector(
+				lnwire.DataLossProtectRequired,
+				lnwire.StaticRemoteKeyRequired,
+				lnwire.PaymentAddrRequired,
+			),
+		}},
+		{"error", &lnwire.Error{
+			ChanID: chanID,
+			Data:   lnwire.ErrorData("test error"),
+		}},
+		{"ping", &lnwire.Ping{
+			NumPongBytes: 4,
+			PaddingBytes: make(lnwire.PingPayload, 8),
+		}},
+		{"pong", &lnwire.Pong{
+			PongBytes: make(lnwire.PongPayload, 4),
+		}},
+		{"open_channel", newOpenChannel()},
+		{"open_channel_with_tlvs", func() lnwire.Message {
+			msg := newOpenChannel()
+			msg.UpfrontShutdownScript = pkScript
+			msg.ChannelType = &chanType
+			msg.LeaseExpiry = &leaseExpiry
+
+			return msg
+		}()},
+		{"accept_channel", newAcceptChannel()},
+		{"accept_channel_with_tlvs", func() lnwire.Message {
+			msg := newAcceptChannel()
+			msg.UpfrontShutdownScript = pkScript
+			msg.ChannelType = &chanType
+			msg.LeaseExpiry = &leaseExpiry
+
+			return msg
+		}()},
+		{"funding_created", &lnwire.FundingCreated{
+			PendingChannelID: pendingChanID,
+			FundingPoint: wire.OutPoint{
+				Hash:  chainhash.Hash{0x07},
+				Index: 1,
+			},
+			CommitSig: sig,
+		}},
+		{"funding_signed", &lnwire.FundingSigned{
+			ChanID:    chanID,
+			CommitSig: sig,
+		}},
+		{"channel_ready", &lnwire.ChannelReady{
+			ChanID:                 chanID,
+			NextPerCommitmentPoint: pubKey(2),
+		}},
+		{"channel_ready_with_alias", &lnwire.ChannelReady{
+			ChanID:                 chanID,
+			NextPerCommitmentPoint: pubKey(2),
+			AliasScid:              &aliasScid,
+		}},
+		{"shutdown", &lnwire.Shutdown{
+			ChannelID: chanID,
+			Address:   pkScript,
+		}},
+		{"closing_signed", &lnwire.ClosingSigned{
+			ChannelID:   chanID,
+			FeeSatoshis: 1000,
+			Signature:   sig,
+		}},
+		{"closing_complete", &lnwire.ClosingComplete{
+			ChannelID:   chanID,
+			FeeSatoshis: 1000,
+			Sequence:    wire.MaxTxInSequenceNum - 1,
+			ClosingSigs: lnwire.ClosingSigs{
+				CloserAndClosee: tlv.SomeRecordT(bothSig),
+			},
+		}},
+		{"closing_sig", &lnwire.ClosingSig{
+			ChannelID: chanID,
+			ClosingSigs: lnwire.ClosingSigs{
+				CloserNoClosee: tlv.SomeRecordT(closerSig),
+			},
+		}},
+		{"open_channel2", newOpenChannel2()},
+		{"open_channel2_with_tlvs", func() lnwire.Message {
+			msg := newOpenChannel2()
+			msg.UpfrontShutdownScript = pkScript
+			msg.ChannelType = &chanType
+			msg.RequireConfirmedInputs = requireConfirmed
+
+			return msg
+		}()},
+		{"accept_channel2", newAcceptChannel2()},
+		{"accept_channel2_with_tlvs", func() lnwire.Message {
+			msg := newAcceptChannel2()
+			msg.UpfrontShutdownScript = pkScript
+			msg.ChannelType = &chanType
+			msg.RequireConfirmedInputs = requireConfirmed
+
+			return msg
+		}()},
+		{"tx_add_input", &lnwire.TxAddInput{
+			ChanID:     chanID,
+			SerialID:   2,
+			PrevTx:     []byte{0x02, 0x00, 0x00, 0x00},
+			PrevTxVout: 1,
+			Sequence:   wire.MaxTxInSequenceNum - 2,
+		}},
+		{"tx_add_input_with_unknown_tlv", &lnwire.TxAddInput{
+			ChanID:     chanID,
+			SerialID:   2,
+			PrevTx:     []byte{0x02, 0x00, 0x00, 0x00},
+			PrevTxVout: 1,
+			Sequence:   wire.MaxTxInSequenceNum - 2,
+			ExtraData:  unknownOddTLV,
+		}},
+		{"tx_add_output", &lnwire.TxAddOutput{
+			ChanID:   chanID,
+			SerialID: 4,
+			Amount:   100_000,
+			PkScript: pkScript,
+		}},
+		{"tx_remove_input", &lnwire.TxRemoveInput{
+			ChanID:   chanID,
+			SerialID: 2,
+		}},
+		{"tx_remove_output", &lnwire.TxRemoveOutput{
+			ChanID:   chanID,
+			SerialID: 4,
+		}},
+		{"tx_complete", &lnwire.TxComplete{
+			ChanID: chanID,
+		}},
+		{"tx_signatures", &lnwire.TxSignatures{
+			ChanID:    chanID,
+			TxID:      chainhash.Hash{0x08},
+			Witnesses: []wire.TxWitness{witness},
+		}},
+		{"tx_abort", &lnwire.TxAbort{
+			ChanID: chanID,
+			Data:   lnwire.ErrorData("test abort"),
+		}},
+		{"splice_locked", &lnwire.SpliceLocked{
+			ChanID:     chanID,
+			SpliceTxid: chainhash.Hash{0x09},
+		}},
+		{"splice_init", &lnwire.SpliceInit{
+			ChanID:              chanID,
+			FundingContribution: 100_000,
+			FundingFeePerKw:     253,
+			Locktime:            800_000,
+			FundingPubKey:       pubKey(4),
+		}},
+		{"splice_init_with_tlvs", &lnwire.SpliceInit{
+			ChanID:                 chanID,
+			FundingContribution:    -100_000,
+			FundingFeePerKw:        253,
+			Locktime:               800_000,
+			FundingPubKey:          pubKey(4),
+			RequireConfirmedInputs: requireConfirmed,
+		}},
+		{"splice_ack", &lnwire.SpliceAck{
+			ChanID:              chanID,
+			FundingContribution: 50_000,
+			FundingPubKey:       pubKey(5),
+		}},
+		{"dyn_propose", &lnwire.DynPropose{
+			ChanID:    chanID,
+			Initiator: true,
+			DustLimit: fn.Some(btcutil.Amount(354)),
+			CsvDelay:  fn.Some(uint16(144)),
+		}},
+		{"dyn_ack", &lnwire.DynAck{
+			ChanID: chanID,
+		}},
+		{"dyn_reject", &lnwire.DynReject{
+			ChanID:           chanID,
+			UpdateRejections: *lnwire.NewRawFeatureVector(0, 2),
+		}},
+		{"update_add_htlc", &lnwire.UpdateAddHTLC{
+			ChanID:      chanID,
+			ID:          1,
+			Amount:      100_000_000,
+			PaymentHash: [32]byte{0x0a},
+			Expiry:      800_144,
+		}},
+		{"update_add_htlc_with_tlvs", &lnwire.UpdateAddHTLC{
+			ChanID:        chanID,
+			ID:            1,
+			Amount:        100_000_000,
+			PaymentHash:   [32]byte{0x0a},
+			Expiry:        800_144,
+			BlindingPoint: blindingPoint,
+			CustomRecords: customRecords,
+		}},
+		{"update_fulfill_htlc", &lnwire.UpdateFulfillHTLC{
+			ChanID:          chanID,
+			ID:              1,
+			PaymentPreimage: [32]byte{0x0b},
+		}},
+		{"update_fulfill_htlc_with_tlvs", &lnwire.UpdateFulfillHTLC{
+			ChanID:          chanID,
+			ID:              1,
+			PaymentPreimage: [32]byte{0x0b},
+			CustomRecords:   customRecords,
+		}},
+		{"update_fail_htlc", &lnwire.UpdateFailHTLC{
+			ChanID: chanID,
+			ID:     1,
+			Reason: lnwire.OpaqueReason{0x0c, 0x0d},
+		}},
+		{"commit_sig", &lnwire.CommitSig{
+			ChanID:    chanID,
+			CommitSig: sig,
+			HtlcSigs:  []lnwire.Sig{sig, sig},
+		}},
+		{"commit_sig_with_tlvs", &lnwire.CommitSig{
+			ChanID:        chanID,
+			CommitSig:     sig,
+			CustomRecords: customRecords,
+		}},
+		{"revoke_and_ack", &lnwire.RevokeAndAck{
+			ChanID:            chanID,
+			Revocation:        [32]byte{0x0e},
+			NextRevocationKey: pubKey(7),
+		}},
+		{"update_fee", &lnwire.UpdateFee{
+			ChanID:   chanID,
+			FeePerKw: 253,
+		}},
+		{"update_fail_malformed_htlc", &lnwire.UpdateFailMalformedHTLC{
+			ChanID:       chanID,
+			ID:           1,
+			ShaOnionBlob: [32]byte{0x0f},
+			FailureCode:  lnwire.CodeInvalidOnionHmac,
+		}},
+		{"channel_reestablish", &lnwire.ChannelReestablish{
+			ChanID:                    chanID,
+			NextLocalCommitHeight:     2,
+			RemoteCommitTailHeight:    1,
+			LastRemoteCommitSecret:    [32]byte{0x10},
+			LocalUnrevokedCommitPoint: pubKey(8),
+		}},
+		{"channel_announcement", &lnwire.ChannelAnnouncement1{
+			NodeSig1:       sig,
+			NodeSig2:       sig,
+			BitcoinSig1:    sig,
+			BitcoinSig2:    sig,
+			Features:       lnwire.NewRawFeatureVector(),
+			ChainHash:      chainHash,
+			ShortChannelID: scid,
+			NodeID1:        rawPubKey(9),
+			NodeID2:        rawPubKey(10),
+			BitcoinKey1:    rawPubKey(11),
+			BitcoinKey2:    rawPubKey(12),
+		}},
+		{"node_announcement", &lnwire.NodeAnnouncement{
+			Signature: sig,
+			Features:  lnwire.NewRawFeatureVector(),
+			Timestamp: 1_700_000_000,
+			NodeID:    rawPubKey(9),
+			Alias:     alias,
+			Addresses: []net.Addr{&net.TCPAddr{
+				IP:   net.IPv4(127, 0, 0, 1).To4(),
+				Port: 9735,
+			}},
+		}},
+		{"channel_update", &lnwire.ChannelUpdate1{
+			Signature:       sig,
+			ChainHash:       chainHash,
+			ShortChannelID:  scid,
+			Timestamp:       1_700_000_000,
+			MessageFlags:    lnwire.ChanUpdateRequiredMaxHtlc,
+			TimeLockDelta:   80,
+			HtlcMinimumMsat: 1000,
+			BaseFee:         1000,
+			FeeRate:         1,
+			HtlcMaximumMsat: 1_000_000_000,
+		}},
+		{"announcement_signatures", &lnwire.AnnounceSignatures1{
+			ChannelID:        chanID,
+			ShortChannelID:   scid,
+			NodeSignature:    sig,
+			BitcoinSignature: sig,
+		}},
+		{"announcement_signatures_2", &lnwire.AnnounceSignatures2{
+			ChannelID:        chanID,
+			ShortChannelID:   scid,
+			PartialSignature: lnwire.NewPartialSig(partialSig),
+		}},
+		{"query_short_channel_ids", &lnwire.QueryShortChanIDs{
+			ChainHash:    chainHash,
+			EncodingType: lnwire.EncodingSortedPlain,
+			ShortChanIDs: []lnwire.ShortChannelID{aliasScid, scid},
+		}},
+		{"reply_short_channel_ids_end", &lnwire.ReplyShortChanIDsEnd{
+			ChainHash: chainHash,
+			Complete:  1,
+		}},
+		{"query_channel_range", &lnwire.QueryChannelRange{
+			ChainHash:        chainHash,
+			FirstBlockHeight: 800_000,
+			NumBlocks:        1000,
+		}},
+		{"query_channel_range_with_tlvs", &lnwire.QueryChannelRange{
+			ChainHash:        chainHash,
+			FirstBlockHeight: 800_000,
+			NumBlocks:        1000,
+			QueryOptions:     lnwire.NewTimestampQueryOption(),
+		}},
+		{"reply_channel_range", &lnwire.ReplyChannelRange{
+			ChainHash:        chainHash,
+			FirstBlockHeight: 800_000,
+			NumBlocks:        1000,
+			Complete:         1,
+			EncodingType:     lnwire.EncodingSortedPlain,
+			ShortChanIDs:     []lnwire.ShortChannelID{scid},
+		}},
+		{"gossip_timestamp_filter", &lnwire.GossipTimestampRange{
+			ChainHash:      chainHash,
+			FirstTimestamp: 1_700_000_000,
+			TimestampRange: 3600,
+		}},
+		{
+			Name: "gossip_timestamp_filter_with_tlvs",
+			Msg: &lnwire.GossipTimestampRange{
+				ChainHash:      chainHash,
+				FirstTimestamp: 1_700_000_000,
+				TimestampRange: 3600,
+				FirstBlockHeight: tlv.SomeRecordT(
+					tlv.NewPrimitiveRecord[tlv.TlvType2](
+						uint32(800_000),
+					),
+				),
+				BlockRange: tlv.SomeRecordT(
+					tlv.NewPrimitiveRecord[tlv.TlvType4](
+						uint32(1000),
+					),
+				),
+			},
+		},
+		{"channel_announcement_2", newChannelAnnouncement2(sig)},
+		{"channel_update_2", newChannelUpdate2(sig)},
+		{"onion_message", &lnwire.OnionMessage{
+			BlindingPoint: pubKey(13),
+			OnionBlob:     []byte{0x00, 0x01, 0x02, 0x03},
+		}},
+		{"kickoff_sig", &lnwire.KickoffSig{
+			ChanID:    chanID,
+			Signature: sig,
+		}},
+	}
+
+	return messages, nil
+}
+
+// newOpenChannel returns an open_channel message without optional TLVs.
+func newOpenChannel() *lnwire.OpenChannel {
+	return &lnwire.OpenChannel{
+		ChainHash:            chainHash,
+		PendingChannelID:     pendingChanID,
+		FundingAmount:        1_000_000,
+		PushAmount:           1000,
+		DustLimit:            354,
+		MaxValueInFlight:     990_000_000,
+		ChannelReserve:       10_000,
+		HtlcMinimum:          1,
+		FeePerKiloWeight:     253,
+		CsvDelay:             144,
+		MaxAcceptedHTLCs:     483,
+		FundingKey:           pubKey(20),
+		RevocationPoint:      pubKey(21),
+		PaymentPoint:         pubKey(22),
+		DelayedPaymentPoint:  pubKey(23),
+		HtlcPoint:            pubKey(24),
+		FirstCommitmentPoint: pubKey(25),
+		ChannelFlags:         lnwire.FFAnnounceChannel,
+	}
+}
+
+// newAcceptChannel returns an accept_channel message without optional TLVs.
+func newAcceptChannel() *lnwire.AcceptChannel {
+	return &lnwire.AcceptChannel{
+		PendingChannelID:     pendingChanID,
+		DustLimit:            354,
+		MaxValueInFlight:     990_000_000,
+		ChannelReserve:       10_000,
+		HtlcMinimum:          1,
+		MinAcceptDepth:       3,
+		CsvDelay:             144,
+		MaxAcceptedHTLCs:     483,
+		FundingKey:           pubKey(30),
+		RevocationPoint:      pubKey(31),
+		PaymentPoint:         pubKey(32),
+		DelayedPaymentPoint:  pubKey(33),
+		HtlcPoint:            pubKey(34),
+		FirstCommitmentPoint: pubKey(35),
+	}
+}
+
+// newOpenChannel2 returns an open_channel2 message without optional TLVs.
+func newOpenChannel2() *lnwire.OpenChannel2 {
+	return &lnwire.OpenChannel2{
+		ChainHash:             chainHash,
+		PendingChannelID:      pendingChanID,
+		FundingFeePerKw:       253,
+		CommitFeePerKw:        253,
+		FundingAmount:         1_000_000,
+		DustLimit:             354,
+		MaxValueInFlight:      990_000_000,
+		HtlcMinimum:           1,
+		CsvDelay:              144,
+		MaxAcceptedHTLCs:      483,
+		LockTime:              800_000,
+		FundingKey:            pubKey(40),
+		RevocationPoint:       pubKey(41),
+		PaymentPoint:          pubKey(42),
+		DelayedPaymentPoint:   pubKey(43),
+		HtlcPoint:             pubKey(44),
+		FirstCommitmentPoint:  pubKey(45),
+		SecondCommitmentPoint: pubKey(46),
+		ChannelFlags:          lnwire.FFAnnounceChannel,
+	}
+}
+
+// newAcceptChannel2 returns an accept_channel2 message without optional
+// TLVs.
+func newAcceptChannel2() *lnwire.AcceptChannel2 {
+	return &lnwire.AcceptChannel2{
+		PendingChannelID:      pendingChanID,
+		FundingAmount:         500_000,
+		DustLimit:             354,
+		MaxValueInFlight:      990_000_000,
+		HtlcMinimum:           1,
+		MinAcceptDepth:        3,
+		CsvDelay:              144,
+		MaxAcceptedHTLCs:      483,
+		FundingKey:            pubKey(50),
+		RevocationPoint:       pubKey(51),
+		PaymentPoint:          pubKey(52),
+		DelayedPaymentPoint:   pubKey(53),
+		HtlcPoint:             pubKey(54),
+		FirstCommitmentPoint:  pubKey(55),
+		SecondCommitmentPoint: pubKey(56),
+	}
+}
+
+// newChannelAnnouncement2 returns a channel_announcement_2 message.
+func newChannelAnnouncement2(sig lnwire.Sig) *lnwire.ChannelAnnouncement2 {
+	msg := &lnwire.ChannelAnnouncement2{
+		Signature: sig,
+	}
+	msg.ChainHash.Val = chainHash
+	msg.Features.Val = *lnwire.NewRawFeatureVector()
+	msg.ShortChannelID.Val = scid
+	msg.Capacity.Val = 1_000_000
+	msg.NodeID1.Val = rawPubKey(9)
+	msg.NodeID2.Val = rawPubKey(10)
+
+	return msg
+}
+
+// newChannelUpdate2 returns a channel_update_2 message.
+func newChannelUpdate2(sig lnwire.Sig) *lnwire.ChannelUpdate2 {
+	msg := &lnwire.ChannelUpdate2{
+		Signature: sig,
+	}
+	msg.ChainHash.Val = chainHash
+	msg.ShortChannelID.Val = scid
+	msg.BlockHeight.Val = 800_000
+	msg.CLTVExpiryDelta.Val = 80
+	msg.HTLCMinimumMsat.Val = 1000
+	msg.HTLCMaximumMsat.Val = 1_000_000_000
+	msg.FeeBaseMsat.Val = 1000
+	msg.FeeProportionalMillionths.Val = 1
+
+	return msg
+}
@@ -0,0 +1,96 @@
+// Package testvectors contains a registry of canonical lnwire messages and
+// exports them as hex encoded test vectors, which other implementations can
+// use to test that they encode and decode the wire messages the same way.
+package testvectors
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+//go:generate go run ./internal/gen -o vectors.json
+
+// Vector is the test vector of a single wire message.
+type Vector struct {
+	// Name uniquely identifies the vector.
+	Name string `json:"name"`
+
+	// Type is the message type of the vector.
+	Type uint16 `json:"type"`
+
+	// TypeName is the human-readable name of the message type.
+	TypeName string `json:"type_name"`
+
+	// Hex is the hex encoded wire message, including its type prefix.
+	Hex string `json:"hex"`
+}
+
+// Generate encodes the messages of the registry into test vectors.
+func Generate() ([]Vector, error) {
+	messages, err := Messages()
+	if err != nil {
+		return nil, err
+	}
+
+	vectors := make([]Vector, 0, len(messages))
+	for _, m := range messages {
+		var b bytes.Buffer
+		if _, err := lnwire.WriteMessage(&b, m.Msg, 0); err != nil {
+			return nil, fmt.Errorf("unable to encode %v: %w",
+				m.Name, err)
+		}
+
+		vectors = append(vectors, Vector{
+			Name:     m.Name,
+			Type:     uint16(m.Msg.MsgType()),
+			TypeName: m.Msg.MsgType().String(),
+			Hex:      hex.EncodeToString(b.Bytes()),
+		})
+	}
+
+	return vectors, nil
+}
+
+// Verify checks that each vector decodes into a message of its type, and that
+// the decoded message encodes into the exact same bytes again.
+func Verify(vectors []Vector) error {
+	for _, vector := range vectors {
+		if err := verifyVector(vector); err != nil {
+			return fmt.Errorf("vector %v: %w", vector.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// verifyVector checks that the vector round trips.
+func verifyVector(vector Vector) error {
+	rawMsg, err := hex.DecodeString(vector.Hex)
+	if err != nil {
+		return err
+	}
+
+	msg, err := lnwire.ReadMessage(bytes.NewReader(rawMsg), 0)
+	if err != nil {
+		return fmt.Errorf("unable to decode: %w", err)
+	}
+
+	if uint16(msg.MsgType()) != vector.Type {
+		return fmt.Errorf("decoded type %v, expected %d",
+			msg.MsgType(), vector.Type)
+	}
+
+	var b bytes.Buffer
+	if _, err := lnwire.WriteMessage(&b, msg, 0); err != nil {
+		return fmt.Errorf("unable to re-encode: %w", err)
+	}
+
+	if !bytes.Equal(b.Bytes(), rawMsg) {
+		return fmt.Errorf("re-encoded as %x", b.Bytes())
+	}
+
+	return nil
+}
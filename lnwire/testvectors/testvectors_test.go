@@ -0,0 +1,82 @@
+package testvectors
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestVectorsRoundTrip tests that the generated vectors are deterministic
+// and that every vector round trips.
+func TestVectorsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	vectors, err := Generate()
+	require.NoError(t, err)
+	require.NoError(t, Verify(vectors))
+
+	// Generating the vectors again must result in the exact same bytes.
+	vectors2, err := Generate()
+	require.NoError(t, err)
+	require.Equal(t, vectors, vectors2)
+
+	// Vector names must be unique.
+	names := fn.NewSet[string]()
+	for _, vector := range vectors {
+		require.False(t, names.Contains(vector.Name), vector.Name)
+		names.Add(vector.Name)
+	}
+}
+
+// TestVectorsCoverAllMessages tests that the registry contains a vector for
+// every message type that lnwire knows how to decode.
+func TestVectorsCoverAllMessages(t *testing.T) {
+	t.Parallel()
+
+	vectors, err := Generate()
+	require.NoError(t, err)
+
+	covered := fn.NewSet[uint16]()
+	for _, vector := range vectors {
+		covered.Add(vector.Type)
+	}
+
+	for i := 0; i < int(lnwire.CustomTypeStart); i++ {
+		msgType := uint16(i)
+
+		var typeBytes [2]byte
+		binary.BigEndian.PutUint16(typeBytes[:], msgType)
+
+		// Reading a message that only consists of its type fails with
+		// an UnknownMessage error if lnwire doesn't know the type.
+		_, err := lnwire.ReadMessage(bytes.NewReader(typeBytes[:]), 0)
+
+		var unknownErr *lnwire.UnknownMessage
+		if errors.As(err, &unknownErr) {
+			continue
+		}
+
+		require.Truef(t, covered.Contains(msgType),
+			"no test vector for message type %v",
+			lnwire.MessageType(msgType))
+	}
+}
+
+// TestVerifyDetectsMismatch tests that a vector whose type doesn't match its
+// encoding fails verification.
+func TestVerifyDetectsMismatch(t *testing.T) {
+	t.Parallel()
+
+	vectors, err := Generate()
+	require.NoError(t, err)
+
+	vector := vectors[0]
+	vector.Type++
+
+	require.Error(t, Verify([]Vector{vector}))
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/hex"
 	"fmt"
+	"strings"
+	"sync"
+	"time"
 
 	"google.golang.org/grpc/metadata"
 	"gopkg.in/macaroon-bakery.v2/bakery"
@@ -34,6 +37,11 @@ var (
 
 	// ErrInvalidID is returned when a macaroon ID is invalid.
 	ErrInvalidID = fmt.Errorf("invalid ID")
+
+	// ErrRotationUnsupported is returned when the root key store of the
+	// service doesn't support rotating root keys.
+	ErrRotationUnsupported = fmt.Errorf("root key store doesn't support " +
+		"root key rotation")
 )
 
 // MacaroonValidator is an interface type that can check if macaroons are valid.
@@ -79,6 +87,41 @@ type ExtendedRootKeyStore interface {
 	// SetRootKey calls the underlying root key store's SetRootKey and
 	// returns the result.
 	SetRootKey(rootKey []byte) error
+
+	// RotateRootKey replaces the root key with the given ID with a new
+	// one, keeping the previous key valid until the given expiry.
+	RotateRootKey(rootKeyID []byte, expiry time.Time) error
+
+	// GetRetired returns the root key with the given ID that was rotated
+	// out, as long as it hasn't expired yet.
+	GetRetired(ctx context.Context, id []byte) ([]byte, error)
+}
+
+// retiredRootKeyStore is a bakery.RootKeyStore that exposes the root keys that
+// were rotated out of an ExtendedRootKeyStore, so that the macaroons baked with
+// them can still be verified until they expire.
+type retiredRootKeyStore struct {
+	rks ExtendedRootKeyStore
+}
+
+// Get returns the retired root key with the given ID.
+//
+// NOTE: This is part of the bakery.RootKeyStore interface.
+func (r *retiredRootKeyStore) Get(ctx context.Context, id []byte) ([]byte,
+	error) {
+
+	return r.rks.GetRetired(ctx, id)
+}
+
+// RootKey always fails, as retired root keys must never be used to bake new
+// macaroons.
+//
+// NOTE: This is part of the bakery.RootKeyStore interface.
+func (r *retiredRootKeyStore) RootKey(context.Context) ([]byte, []byte,
+	error) {
+
+	return nil, nil, fmt.Errorf("retired root keys can't be used to " +
+		"bake macaroons")
 }
 
 // Service encapsulates bakery.Bakery and adds a Close() method that zeroes the
@@ -89,6 +132,15 @@ type Service struct {
 
 	rks bakery.RootKeyStore
 
+	// retired is the bakery used to verify macaroons that were baked with
+	// a root key that was rotated out. It is nil if the root key store
+	// doesn't support rotating root keys.
+	retired *bakery.Bakery
+
+	// checkerMtx guards the caveat checkers of both bakeries, so custom
+	// checkers can be registered while macaroons are being validated.
+	checkerMtx sync.RWMutex
+
 	// ExternalValidators is a map between an absolute gRPC URIs and the
 	// corresponding external macaroon validator to be used for that URI.
 	// If no external validator for an URI is specified, the service will
@@ -121,24 +173,50 @@ func NewService(keyStore bakery.RootKeyStore, location string,
 
 	svc := bakery.New(macaroonParams)
 
+	// If the root key store supports rotating root keys, we'll need a
+	// second bakery that verifies macaroons against the retired keys.
+	var retired *bakery.Bakery
+	if extendedRKS, ok := keyStore.(ExtendedRootKeyStore); ok {
+		retiredParams := macaroonParams
+		retiredParams.RootKeyStore = &retiredRootKeyStore{
+			rks: extendedRKS,
+		}
+		retired = bakery.New(retiredParams)
+	}
+
 	// Register all custom caveat checkers with the bakery's checker.
 	// TODO(aakselrod): Add more checks as required.
-	checker := svc.Checker.FirstPartyCaveatChecker.(*checkers.Checker)
 	for _, check := range checks {
 		cond, fun := check()
-		if !isRegistered(checker, cond) {
-			checker.Register(cond, "std", fun)
+		registerChecker(svc, cond, fun)
+		if retired != nil {
+			registerChecker(retired, cond, fun)
 		}
 	}
 
 	return &Service{
 		Bakery:             *svc,
 		rks:                keyStore,
+		retired:            retired,
 		ExternalValidators: make(map[string]MacaroonValidator),
 		StatelessInit:      statelessInit,
 	}, nil
 }
 
+// registerChecker registers the given caveat checker with the checker of the
+// bakery, unless a checker for the condition is already registered. It returns
+// false if the checker wasn't registered.
+func registerChecker(b *bakery.Bakery, cond string, fun checkers.Func) bool {
+	checker := b.Checker.FirstPartyCaveatChecker.(*checkers.Checker)
+	if isRegistered(checker, cond) {
+		return false
+	}
+
+	checker.Register(cond, "std", fun)
+
+	return true
+}
+
 // isRegistered checks to see if the required checker has already been
 // registered in order to avoid a panic caused by double registration.
 func isRegistered(c *checkers.Checker, name string) bool {
@@ -178,6 +256,37 @@ func (svc *Service) RegisterExternalValidator(fullMethod string,
 	return nil
 }
 
+// RegisterCaveatChecker registers a checker for first party caveats with the
+// given condition name. This allows external components to enforce their own
+// caveats, for example to restrict a macaroon to certain channels or peers.
+// Macaroons with a caveat that no checker is registered for are rejected. It
+// is safe to register checkers while macaroons are being validated.
+func (svc *Service) RegisterCaveatChecker(cond string,
+	check checkers.Func) error {
+
+	if cond == "" || strings.ContainsAny(cond, " \t\n") {
+		return fmt.Errorf("invalid caveat condition name: %q", cond)
+	}
+
+	if check == nil {
+		return fmt.Errorf("caveat checker cannot be nil")
+	}
+
+	svc.checkerMtx.Lock()
+	defer svc.checkerMtx.Unlock()
+
+	if !registerChecker(&svc.Bakery, cond, check) {
+		return fmt.Errorf("caveat checker for condition %s already "+
+			"registered", cond)
+	}
+
+	if svc.retired != nil {
+		registerChecker(svc.retired, cond, check)
+	}
+
+	return nil
+}
+
 // ValidateMacaroon validates the capabilities of a given request given a
 // bakery service, context, and uri. Within the passed context.Context, we
 // expect a macaroon to be encoded as request metadata using the key
@@ -232,10 +341,38 @@ func (svc *Service) CheckMacAuth(ctx context.Context, macBytes []byte,
 		return ErrInvalidID
 	}
 
-	// Check the method being called against the permitted operation, the
-	// expiration time and IP address and return the result.
-	authChecker := svc.Checker.Auth(macaroon.Slice{mac})
-	_, err = authChecker.Allow(ctx, requiredPermissions...)
+	svc.checkerMtx.RLock()
+	defer svc.checkerMtx.RUnlock()
+
+	err = checkAllowed(
+		ctx, svc.Checker, mac, requiredPermissions, fullMethod,
+	)
+	if err == nil || svc.retired == nil {
+		return err
+	}
+
+	// The macaroon might have been baked with a root key that was rotated
+	// out but hasn't expired yet. If it wasn't, we return the original
+	// error.
+	retiredErr := checkAllowed(
+		ctx, svc.retired.Checker, mac, requiredPermissions, fullMethod,
+	)
+	if retiredErr == nil {
+		return nil
+	}
+
+	return err
+}
+
+// checkAllowed uses the given checker to check the method being called against
+// the permitted operation, the expiration time, the IP address and any other
+// caveats of the macaroon.
+func checkAllowed(ctx context.Context, checker *bakery.Checker,
+	mac *macaroon.Macaroon, requiredPermissions []bakery.Op,
+	fullMethod string) error {
+
+	authChecker := checker.Auth(macaroon.Slice{mac})
+	_, err := authChecker.Allow(ctx, requiredPermissions...)
 
 	// If the macaroon contains broad permissions and checks out, we're
 	// done.
@@ -339,6 +476,17 @@ func (svc *Service) SetRootKey(rootKey []byte) error {
 	return nil
 }
 
+// RotateRootKey replaces the root key with the given ID with a new one. The
+// macaroons that were baked with the previous key remain valid until the
+// given expiry, so they can be replaced gradually after a key compromise.
+func (svc *Service) RotateRootKey(rootKeyID []byte, expiry time.Time) error {
+	if boltRKS, ok := svc.rks.(ExtendedRootKeyStore); ok {
+		return boltRKS.RotateRootKey(rootKeyID, expiry)
+	}
+
+	return ErrRotationUnsupported
+}
+
 // ChangePassword calls the underlying root key store's ChangePassword and
 // returns the result.
 func (svc *Service) ChangePassword(oldPw, newPw []byte) error {
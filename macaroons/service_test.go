@@ -3,8 +3,10 @@ package macaroons_test
 import (
 	"context"
 	"encoding/hex"
+	"fmt"
 	"path"
 	"testing"
+	"time"
 
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/macaroons"
@@ -360,3 +362,129 @@ func TestMacaroonVersionDecode(t *testing.T) {
 		require.ErrorIs(t, err, macaroons.ErrInvalidID)
 	})
 }
+
+// newTestMacaroonContext bakes a macaroon for the test operation with the
+// given service, adds the given caveats and returns an incoming context that
+// contains it.
+func newTestMacaroonContext(t *testing.T, service *macaroons.Service,
+	caveats ...string) context.Context {
+
+	mac, err := service.NewMacaroon(
+		context.TODO(), macaroons.DefaultRootKeyID, testOperation,
+	)
+	require.NoError(t, err)
+
+	for _, caveat := range caveats {
+		require.NoError(t, mac.M().AddFirstPartyCaveat([]byte(caveat)))
+	}
+
+	macBinary, err := mac.M().MarshalBinary()
+	require.NoError(t, err)
+
+	md := metadata.New(map[string]string{
+		"macaroon": hex.EncodeToString(macBinary),
+	})
+
+	return metadata.NewIncomingContext(context.Background(), md)
+}
+
+// TestRotateRootKey tests that macaroons baked with a root key that was
+// rotated out remain valid until the retired key expires.
+func TestRotateRootKey(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestRootKeyStorage(t)
+	rootKeyStore, err := macaroons.NewRootKeyStorage(db)
+	require.NoError(t, err)
+	service, err := macaroons.NewService(rootKeyStore, "lnd", false)
+	require.NoError(t, err)
+	defer service.Close()
+
+	err = service.CreateUnlock(&defaultPw)
+	require.NoError(t, err)
+
+	validate := func(ctx context.Context) error {
+		return service.ValidateMacaroon(
+			ctx, []bakery.Op{testOperation}, "FooMethod",
+		)
+	}
+
+	oldCtx := newTestMacaroonContext(t, service)
+	require.NoError(t, validate(oldCtx))
+
+	// After rotating the root key, both the old and new macaroons are
+	// valid.
+	err = service.RotateRootKey(
+		macaroons.DefaultRootKeyID, time.Now().Add(time.Hour),
+	)
+	require.NoError(t, err)
+
+	newCtx := newTestMacaroonContext(t, service)
+	require.NoError(t, validate(oldCtx))
+	require.NoError(t, validate(newCtx))
+
+	// Once the old key is rotated out again with an immediate expiry,
+	// only the newest macaroons are valid.
+	err = service.RotateRootKey(
+		macaroons.DefaultRootKeyID, time.Now().Add(-time.Second),
+	)
+	require.NoError(t, err)
+
+	require.Error(t, validate(oldCtx))
+	require.Error(t, validate(newCtx))
+	require.NoError(t, validate(newTestMacaroonContext(t, service)))
+}
+
+// TestRegisterCaveatChecker tests that custom first party caveat checkers can
+// be registered with the service.
+func TestRegisterCaveatChecker(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestRootKeyStorage(t)
+	rootKeyStore, err := macaroons.NewRootKeyStorage(db)
+	require.NoError(t, err)
+	service, err := macaroons.NewService(rootKeyStore, "lnd", false)
+	require.NoError(t, err)
+	defer service.Close()
+
+	err = service.CreateUnlock(&defaultPw)
+	require.NoError(t, err)
+
+	ctx := newTestMacaroonContext(t, service, "peer abc")
+	validate := func() error {
+		return service.ValidateMacaroon(
+			ctx, []bakery.Op{testOperation}, "FooMethod",
+		)
+	}
+
+	// Without a checker for the caveat, the macaroon is rejected.
+	require.Error(t, validate())
+
+	// Invalid condition names and checkers are rejected.
+	check := func(_ context.Context, _, arg string) error {
+		if arg != "abc" {
+			return fmt.Errorf("wrong peer %v", arg)
+		}
+
+		return nil
+	}
+	require.Error(t, service.RegisterCaveatChecker("", check))
+	require.Error(t, service.RegisterCaveatChecker("a peer", check))
+	require.Error(t, service.RegisterCaveatChecker("peer", nil))
+
+	// Once the checker is registered, the macaroon is accepted.
+	require.NoError(t, service.RegisterCaveatChecker("peer", check))
+	require.NoError(t, validate())
+
+	// The same condition, including the default ones, can't be
+	// registered twice.
+	require.Error(t, service.RegisterCaveatChecker("peer", check))
+	require.Error(t, service.RegisterCaveatChecker("time-before", check))
+
+	// The checker is also used for macaroons baked with a retired key.
+	err = service.RotateRootKey(
+		macaroons.DefaultRootKeyID, time.Now().Add(time.Hour),
+	)
+	require.NoError(t, err)
+	require.NoError(t, validate())
+}
@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"context"
 	"crypto/rand"
+	"encoding/binary"
 	"fmt"
 	"io"
 	"sync"
+	"time"
 
 	"github.com/btcsuite/btcwallet/snacl"
 	"github.com/btcsuite/btcwallet/walletdb"
@@ -22,6 +24,12 @@ var (
 	// rootKeyBucketName is the name of the root key store bucket.
 	rootKeyBucketName = []byte("macrootkeys")
 
+	// retiredRootKeyBucketName is the name of the bucket that stores the
+	// root keys that were rotated out, keyed by their root key ID. Each
+	// value is the unix timestamp at which the key expires, followed by
+	// the encrypted key.
+	retiredRootKeyBucketName = []byte("macretiredrootkeys")
+
 	// DefaultRootKeyID is the ID of the default root key. The first is
 	// just 0, to emulate the memory storage that comes with bakery.
 	DefaultRootKeyID = []byte("0")
@@ -58,6 +66,14 @@ var (
 	// ErrDefaultRootKeyNotFound is returned when the default root key is
 	// not found in the DB when it is expected to be.
 	ErrDefaultRootKeyNotFound = fmt.Errorf("default root key not found")
+
+	// ErrRootKeyNotFound is returned when a root key that should be
+	// rotated doesn't exist.
+	ErrRootKeyNotFound = fmt.Errorf("root key not found")
+
+	// ErrRetiredRootKeyNotFound is returned when there is no retired root
+	// key for a root key ID, or when it has expired.
+	ErrRetiredRootKeyNotFound = fmt.Errorf("retired root key not found")
 )
 
 // RootKeyStorage implements the bakery.RootKeyStorage interface.
@@ -73,6 +89,11 @@ func NewRootKeyStorage(db kvdb.Backend) (*RootKeyStorage, error) {
 	// If the store's bucket doesn't exist, create it.
 	err := kvdb.Update(db, func(tx kvdb.RwTx) error {
 		_, err := tx.CreateTopLevelBucket(rootKeyBucketName)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.CreateTopLevelBucket(retiredRootKeyBucketName)
 		return err
 	}, func() {})
 	if err != nil {
@@ -229,6 +250,39 @@ func (r *RootKeyStorage) ChangePassword(oldPw, newPw []byte) error {
 			return ErrDefaultRootKeyNotFound
 		}
 
+		// The root keys that were rotated out but are still valid need
+		// to be re-encrypted as well.
+		retiredBucket := tx.ReadWriteBucket(retiredRootKeyBucketName)
+		if retiredBucket != nil {
+			err = retiredBucket.ForEach(func(k, v []byte) error {
+				expiry, encKey, err := decodeRetiredRootKey(v)
+				if err != nil {
+					return err
+				}
+
+				decryptedKey, err := encKeyOld.Decrypt(encKey)
+				if err != nil {
+					return err
+				}
+
+				encryptedKey, err := encKeyNew.Encrypt(
+					decryptedKey,
+				)
+				if err != nil {
+					return err
+				}
+
+				return retiredBucket.Put(
+					k, encodeRetiredRootKey(
+						expiry, encryptedKey,
+					),
+				)
+			})
+			if err != nil {
+				return err
+			}
+		}
+
 		// Finally, store the new encryption key parameters in the DB
 		// as well.
 		err = bucket.Put(encryptionKeyID, encKeyNew.Marshal())
@@ -360,6 +414,12 @@ func (r *RootKeyStorage) GenerateNewRootKey() error {
 			return err
 		}
 
+		// As all macaroons are meant to be invalidated, the root keys
+		// that were rotated out are removed as well.
+		if err := deleteRetiredRootKeys(tx); err != nil {
+			return err
+		}
+
 		// Now iterate over all the other root keys that may exist
 		// and re-generate each of them.
 		return bucket.ForEach(func(k, v []byte) error {
@@ -380,6 +440,113 @@ func (r *RootKeyStorage) GenerateNewRootKey() error {
 	}, func() {})
 }
 
+// RotateRootKey replaces the root key with the given ID with a new random key.
+// Unlike GenerateNewRootKey, the previous key is retired instead of being
+// removed: macaroons that were baked with it remain valid until the given
+// expiry. Only one retired key is kept per root key ID, so rotating a key again
+// replaces the previously retired one.
+func (r *RootKeyStorage) RotateRootKey(rootKeyID []byte,
+	expiry time.Time) error {
+
+	r.encKeyMtx.RLock()
+	defer r.encKeyMtx.RUnlock()
+
+	if r.encKey == nil {
+		return ErrStoreLocked
+	}
+
+	if len(rootKeyID) == 0 {
+		return ErrMissingRootKeyID
+	}
+
+	if bytes.Equal(rootKeyID, encryptionKeyID) {
+		return ErrKeyValueForbidden
+	}
+
+	return kvdb.Update(r.Backend, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(rootKeyBucketName)
+		if bucket == nil {
+			return ErrRootKeyBucketNotFound
+		}
+
+		retiredBucket := tx.ReadWriteBucket(retiredRootKeyBucketName)
+		if retiredBucket == nil {
+			return ErrRootKeyBucketNotFound
+		}
+
+		dbKey := bucket.Get(rootKeyID)
+		if len(dbKey) == 0 {
+			return fmt.Errorf("%w: %s", ErrRootKeyNotFound,
+				string(rootKeyID))
+		}
+
+		// The key is stored as is, as it's already encrypted with the
+		// current encryption key.
+		err := retiredBucket.Put(
+			rootKeyID, encodeRetiredRootKey(expiry, dbKey),
+		)
+		if err != nil {
+			return err
+		}
+
+		_, err = generateAndStoreNewRootKey(bucket, rootKeyID, r.encKey)
+
+		return err
+	}, func() {})
+}
+
+// GetRetired returns the root key with the given ID that was rotated out, as
+// long as it hasn't expired yet.
+func (r *RootKeyStorage) GetRetired(_ context.Context, id []byte) ([]byte,
+	error) {
+
+	r.encKeyMtx.RLock()
+	defer r.encKeyMtx.RUnlock()
+
+	if r.encKey == nil {
+		return nil, ErrStoreLocked
+	}
+
+	var rootKey []byte
+	err := kvdb.View(r.Backend, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(retiredRootKeyBucketName)
+		if bucket == nil {
+			return ErrRootKeyBucketNotFound
+		}
+
+		dbValue := bucket.Get(id)
+		if len(dbValue) == 0 {
+			return ErrRetiredRootKeyNotFound
+		}
+
+		expiry, encKey, err := decodeRetiredRootKey(dbValue)
+		if err != nil {
+			return err
+		}
+
+		if !time.Now().Before(expiry) {
+			return ErrRetiredRootKeyNotFound
+		}
+
+		decKey, err := r.encKey.Decrypt(encKey)
+		if err != nil {
+			return err
+		}
+
+		rootKey = make([]byte, len(decKey))
+		copy(rootKey, decKey)
+
+		return nil
+	}, func() {
+		rootKey = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rootKey, nil
+}
+
 // SetRootKey sets the default macaroon root key, replacing the previous root
 // key if it existed.
 func (r *RootKeyStorage) SetRootKey(rootKey []byte) error {
@@ -443,6 +610,56 @@ func generateAndStoreNewRootKey(bucket walletdb.ReadWriteBucket, id []byte,
 	return rootKey, bucket.Put(id, encryptedKey)
 }
 
+// encodeRetiredRootKey encodes the expiry and encrypted key of a retired root
+// key into the value that is stored in the retired root key bucket.
+func encodeRetiredRootKey(expiry time.Time, encKey []byte) []byte {
+	value := make([]byte, 8+len(encKey))
+	binary.BigEndian.PutUint64(value[:8], uint64(expiry.Unix()))
+	copy(value[8:], encKey)
+
+	return value
+}
+
+// decodeRetiredRootKey decodes a value of the retired root key bucket into the
+// expiry and the encrypted key of the retired root key.
+func decodeRetiredRootKey(value []byte) (time.Time, []byte, error) {
+	if len(value) <= 8 {
+		return time.Time{}, nil, fmt.Errorf("invalid retired root "+
+			"key length: %d", len(value))
+	}
+
+	expiry := time.Unix(int64(binary.BigEndian.Uint64(value[:8])), 0)
+
+	return expiry, value[8:], nil
+}
+
+// deleteRetiredRootKeys removes all root keys that were rotated out.
+func deleteRetiredRootKeys(tx kvdb.RwTx) error {
+	bucket := tx.ReadWriteBucket(retiredRootKeyBucketName)
+	if bucket == nil {
+		return nil
+	}
+
+	// We first gather the IDs, as the bucket can't be modified while
+	// iterating.
+	var ids [][]byte
+	err := bucket.ForEach(func(k, _ []byte) error {
+		ids = append(ids, append([]byte(nil), k...))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := bucket.Delete(id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // ListMacaroonIDs returns all the root key ID values except the value of
 // encryptedKeyID.
 func (r *RootKeyStorage) ListMacaroonIDs(_ context.Context) ([][]byte, error) {
@@ -514,10 +731,18 @@ func (r *RootKeyStorage) DeleteMacaroonID(
 			return nil
 		}
 
-		// Once the key is found, we do the deletion.
+		// Once the key is found, we do the deletion. Any key that was
+		// rotated out for the same ID is removed as well.
 		if err := bucket.Delete(rootKeyID); err != nil {
 			return err
 		}
+
+		retiredBucket := tx.ReadWriteBucket(retiredRootKeyBucketName)
+		if retiredBucket != nil {
+			if err := retiredBucket.Delete(rootKeyID); err != nil {
+				return err
+			}
+		}
 		rootKeyIDDeleted = rootKeyID
 
 		return nil
@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"path"
 	"testing"
+	"time"
 
 	"github.com/btcsuite/btcwallet/snacl"
 	"github.com/lightningnetwork/lnd/kvdb"
@@ -271,3 +272,79 @@ func TestStoreChangePassword(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, rootKey2, rootKeyDB2)
 }
+
+// TestStoreRotateRootKey tests that rotating a root key replaces it while the
+// previous key remains available until it expires, also across password
+// changes.
+func TestStoreRotateRootKey(t *testing.T) {
+	tempDir, store := newTestStore(t)
+
+	// The store must be unlocked to rotate the root keys.
+	expiry := time.Now().Add(time.Hour)
+	err := store.RotateRootKey(macaroons.DefaultRootKeyID, expiry)
+	require.Equal(t, macaroons.ErrStoreLocked, err)
+
+	pw := []byte("weks")
+	err = store.CreateUnlock(&pw)
+	require.NoError(t, err)
+
+	rootKey1, _, err := store.RootKey(defaultRootKeyIDContext)
+	require.NoError(t, err)
+
+	// There is no retired key before the first rotation, and only
+	// existing keys can be rotated.
+	_, err = store.GetRetired(context.Background(), []byte("0"))
+	require.ErrorIs(t, err, macaroons.ErrRetiredRootKeyNotFound)
+
+	err = store.RotateRootKey([]byte("unknown"), expiry)
+	require.ErrorIs(t, err, macaroons.ErrRootKeyNotFound)
+
+	// After rotating, a new key is used while the previous one is
+	// retired.
+	err = store.RotateRootKey(macaroons.DefaultRootKeyID, expiry)
+	require.NoError(t, err)
+
+	rootKey2, _, err := store.RootKey(defaultRootKeyIDContext)
+	require.NoError(t, err)
+	require.NotEqual(t, rootKey1, rootKey2)
+
+	retiredKey, err := store.GetRetired(context.Background(), []byte("0"))
+	require.NoError(t, err)
+	require.Equal(t, rootKey1, retiredKey)
+
+	// The retired key must survive a password change.
+	newPw := []byte("newpassword")
+	err = store.ChangePassword(pw, newPw)
+	require.NoError(t, err)
+
+	require.NoError(t, store.Close())
+	require.NoError(t, store.Backend.Close())
+
+	store = openTestStore(t, tempDir)
+	err = store.CreateUnlock(&newPw)
+	require.NoError(t, err)
+
+	retiredKey, err = store.GetRetired(context.Background(), []byte("0"))
+	require.NoError(t, err)
+	require.Equal(t, rootKey1, retiredKey)
+
+	// Rotating again replaces the retired key. Once it expired, it isn't
+	// returned anymore.
+	err = store.RotateRootKey(
+		macaroons.DefaultRootKeyID, time.Now().Add(-time.Second),
+	)
+	require.NoError(t, err)
+
+	_, err = store.GetRetired(context.Background(), []byte("0"))
+	require.ErrorIs(t, err, macaroons.ErrRetiredRootKeyNotFound)
+
+	// Generating new root keys removes all retired keys.
+	err = store.RotateRootKey(macaroons.DefaultRootKeyID, expiry)
+	require.NoError(t, err)
+
+	err = store.GenerateNewRootKey()
+	require.NoError(t, err)
+
+	_, err = store.GetRetired(context.Background(), []byte("0"))
+	require.ErrorIs(t, err, macaroons.ErrRetiredRootKeyNotFound)
+}
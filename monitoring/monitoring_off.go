@@ -6,6 +6,8 @@ package monitoring
 import (
 	"fmt"
 
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lncfg"
 	"google.golang.org/grpc"
 )
@@ -23,3 +25,17 @@ func ExportPrometheusMetrics(_ *grpc.Server, _ lncfg.Prometheus) error {
 	return fmt.Errorf("lnd must be built with the monitoring tag to " +
 		"enable exporting Prometheus metrics")
 }
+
+// RegisterHeldHtlcMetrics is required for lnd to compile so that Prometheus
+// metric exporting can be hidden behind a build tag.
+func RegisterHeldHtlcMetrics(_ func() htlcswitch.HeldHtlcStats) error {
+	return fmt.Errorf("lnd must be built with the monitoring tag to " +
+		"enable exporting Prometheus metrics")
+}
+
+// RegisterDBMetrics is required for lnd to compile so that Prometheus metric
+// exporting can be hidden behind a build tag.
+func RegisterDBMetrics(_ []*kvdb.InstrumentedBackend) error {
+	return fmt.Errorf("lnd must be built with the monitoring tag to " +
+		"enable exporting Prometheus metrics")
+}
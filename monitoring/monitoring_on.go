@@ -4,17 +4,264 @@
 package monitoring
 
 import (
+	"encoding/hex"
 	"net/http"
 	"sync"
+	"time"
+	"unicode"
 
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lncfg"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc"
 )
 
 var started sync.Once
 
+var (
+	heldHtlcsDesc = prometheus.NewDesc(
+		"lnd_held_htlcs", "Number of htlcs held by the htlc "+
+			"interceptor.", nil, nil,
+	)
+
+	heldHtlcMemoryDesc = prometheus.NewDesc(
+		"lnd_held_htlcs_memory_bytes", "Approximate memory used by "+
+			"htlcs held by the htlc interceptor.", nil, nil,
+	)
+
+	heldHtlcsEvictedDesc = prometheus.NewDesc(
+		"lnd_held_htlcs_evicted_total", "Number of held htlcs that "+
+			"were failed back because the memory ceiling was "+
+			"reached.", nil, nil,
+	)
+
+	linkHeldHtlcsDesc = prometheus.NewDesc(
+		"lnd_link_held_htlcs", "Number of htlcs held by the htlc "+
+			"interceptor per incoming channel.",
+		[]string{"chan_id"}, nil,
+	)
+
+	linkHeldHtlcMemoryDesc = prometheus.NewDesc(
+		"lnd_link_held_htlcs_memory_bytes", "Approximate memory used "+
+			"by htlcs held by the htlc interceptor per incoming "+
+			"channel.", []string{"chan_id"}, nil,
+	)
+)
+
+// heldHtlcCollector is a prometheus.Collector that exports the stats of the
+// htlcs held by the htlc interceptor.
+type heldHtlcCollector struct {
+	stats func() htlcswitch.HeldHtlcStats
+}
+
+// Describe sends the descriptors of all the metrics of the collector to the
+// given channel.
+//
+// NOTE: This is part of the prometheus.Collector interface.
+func (c *heldHtlcCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- heldHtlcsDesc
+	ch <- heldHtlcMemoryDesc
+	ch <- heldHtlcsEvictedDesc
+	ch <- linkHeldHtlcsDesc
+	ch <- linkHeldHtlcMemoryDesc
+}
+
+// Collect sends the current values of all the metrics of the collector to the
+// given channel.
+//
+// NOTE: This is part of the prometheus.Collector interface.
+func (c *heldHtlcCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.stats()
+
+	ch <- prometheus.MustNewConstMetric(
+		heldHtlcsDesc, prometheus.GaugeValue, float64(stats.NumHeld),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		heldHtlcMemoryDesc, prometheus.GaugeValue,
+		float64(stats.MemoryUsage),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		heldHtlcsEvictedDesc, prometheus.CounterValue,
+		float64(stats.NumEvicted),
+	)
+
+	for chanID, link := range stats.Links {
+		ch <- prometheus.MustNewConstMetric(
+			linkHeldHtlcsDesc, prometheus.GaugeValue,
+			float64(link.NumHeld), chanID.String(),
+		)
+		ch <- prometheus.MustNewConstMetric(
+			linkHeldHtlcMemoryDesc, prometheus.GaugeValue,
+			float64(link.MemoryUsage), chanID.String(),
+		)
+	}
+}
+
+// RegisterHeldHtlcMetrics registers the Prometheus metrics that export the
+// number of htlcs held by the htlc interceptor and the memory they consume.
+func RegisterHeldHtlcMetrics(stats func() htlcswitch.HeldHtlcStats) error {
+	return prometheus.Register(&heldHtlcCollector{stats: stats})
+}
+
+// dbBucketSizeInterval is the minimum interval between two walks of the
+// databases to determine the size of their top level buckets. As this reads
+// the entire databases, the sizes are refreshed in the background and the
+// last known values are exported in the meantime.
+const dbBucketSizeInterval = 30 * time.Minute
+
+var (
+	dbReadTxLatencyDesc = prometheus.NewDesc(
+		"lnd_db_read_tx_latency_seconds", "Latency of database read "+
+			"transactions.", []string{"subsystem"}, nil,
+	)
+
+	dbWriteTxLatencyDesc = prometheus.NewDesc(
+		"lnd_db_write_tx_latency_seconds", "Latency of database "+
+			"write transactions, including retries.",
+		[]string{"subsystem"}, nil,
+	)
+
+	dbBucketKeysDesc = prometheus.NewDesc(
+		"lnd_db_bucket_keys", "Number of keys in a top level "+
+			"database bucket, including nested buckets.",
+		[]string{"subsystem", "bucket"}, nil,
+	)
+
+	dbBucketBytesDesc = prometheus.NewDesc(
+		"lnd_db_bucket_bytes", "Estimated size of the keys and "+
+			"values in a top level database bucket, including "+
+			"nested buckets.", []string{"subsystem", "bucket"}, nil,
+	)
+)
+
+// dbCollector is a prometheus.Collector that exports the transaction
+// latencies and bucket sizes of the databases of lnd's subsystems.
+type dbCollector struct {
+	dbs []*kvdb.InstrumentedBackend
+
+	mu          sync.Mutex
+	bucketSizes map[string]map[string]kvdb.BucketSize
+	lastRefresh time.Time
+	refreshing  bool
+}
+
+// Describe sends the descriptors of all the metrics of the collector to the
+// given channel.
+//
+// NOTE: This is part of the prometheus.Collector interface.
+func (c *dbCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- dbReadTxLatencyDesc
+	ch <- dbWriteTxLatencyDesc
+	ch <- dbBucketKeysDesc
+	ch <- dbBucketBytesDesc
+}
+
+// Collect sends the current values of all the metrics of the collector to the
+// given channel.
+//
+// NOTE: This is part of the prometheus.Collector interface.
+func (c *dbCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, db := range c.dbs {
+		ch <- newLatencyMetric(
+			dbReadTxLatencyDesc, db.ReadLatency(), db.Subsystem(),
+		)
+		ch <- newLatencyMetric(
+			dbWriteTxLatencyDesc, db.WriteLatency(),
+			db.Subsystem(),
+		)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for subsystem, sizes := range c.bucketSizes {
+		for name, size := range sizes {
+			bucket := bucketLabel(name)
+			ch <- prometheus.MustNewConstMetric(
+				dbBucketKeysDesc, prometheus.GaugeValue,
+				float64(size.Keys), subsystem, bucket,
+			)
+			ch <- prometheus.MustNewConstMetric(
+				dbBucketBytesDesc, prometheus.GaugeValue,
+				float64(size.Bytes), subsystem, bucket,
+			)
+		}
+	}
+
+	if !c.refreshing && time.Since(c.lastRefresh) > dbBucketSizeInterval {
+		c.refreshing = true
+		go c.refreshBucketSizes()
+	}
+}
+
+// refreshBucketSizes walks the databases to update their bucket sizes.
+// Subsystems that share the same database backend are only walked once.
+//
+// NOTE: This method MUST be run as a goroutine.
+func (c *dbCollector) refreshBucketSizes() {
+	bucketSizes := make(map[string]map[string]kvdb.BucketSize)
+	walked := make(map[kvdb.Backend]struct{})
+	for _, db := range c.dbs {
+		if _, ok := walked[db.Backend]; ok {
+			continue
+		}
+		walked[db.Backend] = struct{}{}
+
+		sizes, err := kvdb.TopLevelBucketSizes(db.Backend)
+		if err != nil {
+			log.Errorf("Unable to determine bucket sizes of %v "+
+				"database: %v", db.Subsystem(), err)
+
+			continue
+		}
+		bucketSizes[db.Subsystem()] = sizes
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.bucketSizes = bucketSizes
+	c.lastRefresh = time.Now()
+	c.refreshing = false
+}
+
+// newLatencyMetric converts the given latency histogram into a Prometheus
+// histogram with the latencies in seconds.
+func newLatencyMetric(desc *prometheus.Desc, h kvdb.LatencyHistogram,
+	subsystem string) prometheus.Metric {
+
+	buckets := make(map[float64]uint64, len(h.Buckets))
+	for bound, count := range h.Buckets {
+		buckets[bound.Seconds()] = count
+	}
+
+	return prometheus.MustNewConstHistogram(
+		desc, h.Count, h.Sum.Seconds(), buckets, subsystem,
+	)
+}
+
+// bucketLabel returns the label of a bucket with the given name. Most buckets
+// have human readable names, the remaining ones are hex encoded.
+func bucketLabel(name string) string {
+	for _, r := range name {
+		if r > unicode.MaxASCII || !unicode.IsPrint(r) {
+			return hex.EncodeToString([]byte(name))
+		}
+	}
+
+	return name
+}
+
+// RegisterDBMetrics registers the Prometheus metrics that export the
+// transaction latencies and the top level bucket sizes of the given
+// databases.
+func RegisterDBMetrics(dbs []*kvdb.InstrumentedBackend) error {
+	return prometheus.Register(&dbCollector{dbs: dbs})
+}
+
 // GetPromInterceptors returns the set of interceptors for Prometheus
 // monitoring.
 func GetPromInterceptors() ([]grpc.UnaryServerInterceptor, []grpc.StreamServerInterceptor) {
@@ -0,0 +1,260 @@
+package netann
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"sync"
+
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/lightninglabs/neutrino/cache/lru"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// SignRequest is a request to sign a single message as part of a batch.
+type SignRequest struct {
+	// KeyLoc is the locator of the key to sign with.
+	KeyLoc keychain.KeyLocator
+
+	// Msg is the message to sign.
+	Msg []byte
+
+	// DoubleHash indicates whether the double-sha256 of the message is
+	// signed, instead of its single sha256.
+	DoubleHash bool
+}
+
+// BatchMessageSigner is a MessageSigner that is able to sign multiple
+// messages at once.
+type BatchMessageSigner interface {
+	lnwallet.MessageSigner
+
+	// SignMessages signs all passed messages and returns the signatures
+	// in the same order.
+	SignMessages(reqs []SignRequest) ([]*ecdsa.Signature, error)
+}
+
+// sigCacheKey identifies a signature in the signature cache.
+type sigCacheKey [sha256.Size]byte
+
+// newSigCacheKey derives the cache key of a signing request.
+func newSigCacheKey(req SignRequest) sigCacheKey {
+	h := sha256.New()
+
+	var keyLoc [9]byte
+	binary.BigEndian.PutUint32(keyLoc[:4], uint32(req.KeyLoc.Family))
+	binary.BigEndian.PutUint32(keyLoc[4:8], req.KeyLoc.Index)
+	if req.DoubleHash {
+		keyLoc[8] = 1
+	}
+	_, _ = h.Write(keyLoc[:])
+	_, _ = h.Write(req.Msg)
+
+	var key sigCacheKey
+	copy(key[:], h.Sum(nil))
+
+	return key
+}
+
+// cachedSig is a signature that is stored in the signature cache.
+type cachedSig struct {
+	sig *ecdsa.Signature
+}
+
+// Size returns the "size" of an entry. We return 1 as we just want to limit
+// the total number of entries rather than do accurate size accounting.
+func (c *cachedSig) Size() (uint64, error) {
+	return 1, nil
+}
+
+// BatchSigner wraps a MessageSigner, typically one that forwards requests to
+// a remote signer, to reduce the number of sequential round trips needed to
+// sign gossip messages. Batches of messages are signed concurrently, and
+// identical requests are answered from a cache of recent signatures. As
+// signatures are deterministic (RFC6979), a cached signature is identical to
+// a freshly created one.
+type BatchSigner struct {
+	signer lnwallet.MessageSigner
+
+	// concurrency is the maximum number of requests that are forwarded to
+	// the wrapped signer concurrently.
+	concurrency int
+
+	sigCache *lru.Cache[sigCacheKey, *cachedSig]
+}
+
+// A compile time check to ensure that BatchSigner implements the
+// BatchMessageSigner interface.
+var _ BatchMessageSigner = (*BatchSigner)(nil)
+
+// NewBatchSigner creates a new BatchSigner that forwards at most concurrency
+// requests to the wrapped signer at the same time and caches up to
+// cacheSize signatures.
+func NewBatchSigner(signer lnwallet.MessageSigner, concurrency,
+	cacheSize int) (*BatchSigner, error) {
+
+	if concurrency < 1 {
+		return nil, errors.New("signing concurrency must be positive")
+	}
+
+	if cacheSize < 1 {
+		return nil, errors.New("signature cache size must be positive")
+	}
+
+	return &BatchSigner{
+		signer:      signer,
+		concurrency: concurrency,
+		sigCache: lru.NewCache[sigCacheKey, *cachedSig](
+			uint64(cacheSize),
+		),
+	}, nil
+}
+
+// SignMessage signs the passed message, returning a cached signature if the
+// same message was signed recently.
+//
+// NOTE: This is part of the lnwallet.MessageSigner interface.
+func (b *BatchSigner) SignMessage(keyLoc keychain.KeyLocator, msg []byte,
+	doubleHash bool) (*ecdsa.Signature, error) {
+
+	sigs, err := b.SignMessages([]SignRequest{{
+		KeyLoc:     keyLoc,
+		Msg:        msg,
+		DoubleHash: doubleHash,
+	}})
+	if err != nil {
+		return nil, err
+	}
+
+	return sigs[0], nil
+}
+
+// SignMessages signs all passed messages and returns the signatures in the
+// same order. Cached and duplicate requests are only signed once, and the
+// remaining requests are forwarded to the wrapped signer concurrently.
+//
+// NOTE: This is part of the BatchMessageSigner interface.
+func (b *BatchSigner) SignMessages(reqs []SignRequest) ([]*ecdsa.Signature,
+	error) {
+
+	sigs := make([]*ecdsa.Signature, len(reqs))
+
+	// Collect the requests that need to be signed, deduplicated by their
+	// cache key.
+	var (
+		keys    = make([]sigCacheKey, len(reqs))
+		pending = make(map[sigCacheKey]SignRequest)
+	)
+	for i, req := range reqs {
+		keys[i] = newSigCacheKey(req)
+
+		cached, err := b.sigCache.Get(keys[i])
+		if err == nil {
+			sigs[i] = cached.sig
+			continue
+		}
+
+		pending[keys[i]] = req
+	}
+
+	if len(pending) > 0 {
+		log.Debugf("Signing %d of %d messages, %d answered from "+
+			"cache", len(pending), len(reqs),
+			len(reqs)-len(pending))
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mtx      sync.Mutex
+		firstErr error
+		results  = make(map[sigCacheKey]*ecdsa.Signature, len(pending))
+		sem      = make(chan struct{}, b.concurrency)
+	)
+	for key, req := range pending {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(key sigCacheKey, req SignRequest) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			sig, err := b.signer.SignMessage(
+				req.KeyLoc, req.Msg, req.DoubleHash,
+			)
+
+			mtx.Lock()
+			defer mtx.Unlock()
+
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+
+				return
+			}
+
+			results[key] = sig
+		}(key, req)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	for key, sig := range results {
+		_, _ = b.sigCache.Put(key, &cachedSig{sig: sig})
+	}
+
+	for i := range sigs {
+		if sigs[i] == nil {
+			sigs[i] = results[keys[i]]
+		}
+	}
+
+	return sigs, nil
+}
+
+// SignAnnouncements signs all passed gossip messages. If the signer supports
+// batching, the messages are signed as a single batch, otherwise they're
+// signed one by one.
+func SignAnnouncements(signer lnwallet.MessageSigner,
+	keyLoc keychain.KeyLocator,
+	msgs []lnwire.Message) ([]*ecdsa.Signature, error) {
+
+	reqs := make([]SignRequest, 0, len(msgs))
+	for _, msg := range msgs {
+		data, err := announcementDataToSign(msg)
+		if err != nil {
+			return nil, err
+		}
+
+		reqs = append(reqs, SignRequest{
+			KeyLoc:     keyLoc,
+			Msg:        data,
+			DoubleHash: true,
+		})
+	}
+
+	if batchSigner, ok := signer.(BatchMessageSigner); ok {
+		return batchSigner.SignMessages(reqs)
+	}
+
+	sigs := make([]*ecdsa.Signature, 0, len(reqs))
+	for _, req := range reqs {
+		sig, err := signer.SignMessage(
+			req.KeyLoc, req.Msg, req.DoubleHash,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		sigs = append(sigs, sig)
+	}
+
+	return sigs, nil
+}
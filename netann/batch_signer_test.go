@@ -0,0 +1,83 @@
+package netann_test
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/netann"
+	"github.com/stretchr/testify/require"
+)
+
+// countingSigner is a MessageSigner that counts the number of signing
+// requests it receives.
+type countingSigner struct {
+	lnwallet.MessageSigner
+
+	calls atomic.Int32
+}
+
+func (c *countingSigner) SignMessage(keyLoc keychain.KeyLocator, msg []byte,
+	doubleHash bool) (*ecdsa.Signature, error) {
+
+	c.calls.Add(1)
+
+	return c.MessageSigner.SignMessage(keyLoc, msg, doubleHash)
+}
+
+// TestBatchSigner tests that the batch signer produces the same signatures as
+// the wrapped signer, and that cached and duplicate messages are only sent to
+// the wrapped signer once.
+func TestBatchSigner(t *testing.T) {
+	t.Parallel()
+
+	nodeSigner := netann.NewNodeSigner(privKeySigner)
+	signer := &countingSigner{MessageSigner: nodeSigner}
+
+	batchSigner, err := netann.NewBatchSigner(signer, 2, 10)
+	require.NoError(t, err)
+
+	newUpdate := func(scid uint64) *lnwire.ChannelUpdate1 {
+		return &lnwire.ChannelUpdate1{
+			ShortChannelID: lnwire.NewShortChanIDFromInt(scid),
+			Timestamp:      1,
+		}
+	}
+
+	// Sign a batch containing a duplicate message.
+	msgs := []lnwire.Message{newUpdate(1), newUpdate(2), newUpdate(1)}
+	sigs, err := netann.SignAnnouncements(batchSigner, testKeyLoc, msgs)
+	require.NoError(t, err)
+	require.Len(t, sigs, len(msgs))
+	require.EqualValues(t, 2, signer.calls.Load())
+
+	for i, msg := range msgs {
+		expected, err := netann.SignAnnouncement(
+			nodeSigner, testKeyLoc, msg,
+		)
+		require.NoError(t, err)
+		require.Equal(t, expected.Serialize(), sigs[i].Serialize())
+	}
+
+	// Signing a message that was signed before should be answered from
+	// the cache.
+	_, err = netann.SignAnnouncement(batchSigner, testKeyLoc, newUpdate(2))
+	require.NoError(t, err)
+	require.EqualValues(t, 2, signer.calls.Load())
+
+	_, err = netann.SignAnnouncement(batchSigner, testKeyLoc, newUpdate(3))
+	require.NoError(t, err)
+	require.EqualValues(t, 3, signer.calls.Load())
+
+	// Errors of the wrapped signer are returned for the whole batch.
+	failingSigner, err := netann.NewBatchSigner(
+		&mockSigner{err: errFailedToSign}, 2, 10,
+	)
+	require.NoError(t, err)
+
+	_, err = netann.SignAnnouncements(failingSigner, testKeyLoc, msgs)
+	require.ErrorIs(t, err, errFailedToSign)
+}
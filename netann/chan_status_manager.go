@@ -126,6 +126,21 @@ type ChanStatusManager struct {
 	// become inactive.
 	statusSampleTicker *time.Ticker
 
+	// maintenanceWindows are the scheduled and active maintenance
+	// windows, keyed by their ID.
+	maintenanceWindows map[uint64]*maintenanceState
+
+	// nextMaintenanceID is the ID of the last scheduled maintenance
+	// window.
+	nextMaintenanceID uint64
+
+	// maintenanceChans counts the active maintenance windows that hold
+	// each channel disabled.
+	maintenanceChans map[wire.OutPoint]int
+
+	// maintenanceMtx guards the maintenance window state.
+	maintenanceMtx sync.Mutex
+
 	wg   sync.WaitGroup
 	quit chan struct{}
 }
@@ -166,6 +181,8 @@ func NewChanStatusManager(cfg *ChanStatusConfig) (*ChanStatusManager, error) {
 		enableRequests:     make(chan statusRequest),
 		disableRequests:    make(chan statusRequest),
 		autoRequests:       make(chan statusRequest),
+		maintenanceWindows: make(map[uint64]*maintenanceState),
+		maintenanceChans:   make(map[wire.OutPoint]int),
 		quit:               make(chan struct{}),
 	}, nil
 }
@@ -363,6 +380,10 @@ func (m *ChanStatusManager) statusManager() {
 			// if the inactive chan timeout has elapsed.
 			m.disableInactiveChannels()
 
+			// Finally, start and end any scheduled maintenance
+			// windows.
+			m.processMaintenanceWindows()
+
 		case <-m.quit:
 			return
 		}
@@ -480,6 +480,27 @@ func (h *testHarness) assertAuto(outpoint wire.OutPoint, expErr error) {
 	}
 }
 
+// scheduleMaintenance schedules a maintenance window for the passed channels,
+// or all channels if none are passed, that starts now and lasts for the given
+// duration.
+func (h *testHarness) scheduleMaintenance(duration time.Duration,
+	channels ...*channeldb.OpenChannel) uint64 {
+
+	h.t.Helper()
+
+	var chanPoints []wire.OutPoint
+	for _, channel := range channels {
+		chanPoints = append(chanPoints, channel.FundingOutpoint)
+	}
+
+	id, err := h.mgr.ScheduleMaintenance(
+		time.Now(), duration, chanPoints...,
+	)
+	require.NoError(h.t, err)
+
+	return id
+}
+
 // assertNoUpdates waits for the specified duration, and asserts that no updates
 // are announced on the network.
 func (h *testHarness) assertNoUpdates(duration time.Duration) {
@@ -906,6 +927,96 @@ var stateMachineTests = []stateMachineTest{
 			)
 		},
 	},
+	{
+		name:         "maintenance window",
+		startActive:  true,
+		startEnabled: true,
+		fn: func(h testHarness) {
+			// Windows without a duration can't be scheduled.
+			_, err := h.mgr.ScheduleMaintenance(time.Now(), 0)
+			require.ErrorIs(
+				h.t, err, netann.ErrInvalidMaintenanceWindow,
+			)
+
+			// Schedule a window for all channels that starts now.
+			h.scheduleMaintenance(2 * h.safeDisableTimeout)
+
+			// Expect to see them all disabled on the network.
+			h.assertUpdates(
+				h.graph.chans(), false, h.safeDisableTimeout,
+			)
+
+			windows := h.mgr.MaintenanceWindows()
+			require.Len(h.t, windows, 1)
+			require.True(h.t, windows[0].Active)
+
+			// While the window is active, the channels can't be
+			// enabled automatically.
+			h.assertEnables(
+				h.graph.chans(),
+				netann.ErrEnableManuallyDisabledChan, false,
+			)
+
+			// Once the window ends, they're all enabled again.
+			h.assertUpdates(
+				h.graph.chans(), true, 2*h.safeDisableTimeout,
+			)
+			require.Empty(h.t, h.mgr.MaintenanceWindows())
+		},
+	},
+	{
+		name:         "maintenance window keeps manual disables",
+		startActive:  true,
+		startEnabled: true,
+		fn: func(h testHarness) {
+			// Manually disable half of the channels.
+			manualChans := h.graph.chans()[:h.numChannels/2]
+			otherChans := h.graph.chans()[h.numChannels/2:]
+
+			h.assertDisables(manualChans, nil, true)
+			h.assertUpdates(
+				manualChans, false, h.safeDisableTimeout,
+			)
+
+			// A window for all channels only disables the other
+			// half.
+			h.scheduleMaintenance(2 * h.safeDisableTimeout)
+			h.assertUpdates(otherChans, false, h.safeDisableTimeout)
+
+			// Once the window ends, only the other half is enabled
+			// again.
+			h.assertUpdates(
+				otherChans, true, 2*h.safeDisableTimeout,
+			)
+		},
+	},
+	{
+		name:         "cancel maintenance window",
+		startActive:  true,
+		startEnabled: true,
+		fn: func(h testHarness) {
+			// Schedule a long window for the channels.
+			id := h.scheduleMaintenance(
+				time.Hour, h.graph.chans()...,
+			)
+			h.assertUpdates(
+				h.graph.chans(), false, h.safeDisableTimeout,
+			)
+
+			// Cancelling the active window enables the channels
+			// again.
+			require.NoError(h.t, h.mgr.CancelMaintenance(id))
+			h.assertUpdates(
+				h.graph.chans(), true, h.safeDisableTimeout,
+			)
+
+			require.Empty(h.t, h.mgr.MaintenanceWindows())
+			require.ErrorIs(
+				h.t, h.mgr.CancelMaintenance(id),
+				netann.ErrUnknownMaintenanceWindow,
+			)
+		},
+	},
 }
 
 // TestChanStatusManagerStateMachine tests the possible state transitions that
@@ -0,0 +1,169 @@
+package netann
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+var (
+	// ErrCustomRecordTypeOfficial is returned when a custom record would
+	// use a TLV type that is reserved for records defined by the spec.
+	ErrCustomRecordTypeOfficial = fmt.Errorf("custom record type must "+
+		"be at least %d", lnwire.MinCustomRecordsTlvType)
+
+	// ErrCustomRecordTypeEven is returned when a custom record would use
+	// an even TLV type. Nodes that don't understand an even type reject
+	// the whole announcement, so only odd types can be advertised.
+	ErrCustomRecordTypeEven = errors.New("custom record type must be odd")
+)
+
+// CustomRecordsUpdate describes a change to the custom records that are
+// included in the extra opaque data of an announcement.
+type CustomRecordsUpdate struct {
+	// Set is the set of records to add to the announcement. Existing
+	// records of the same type are replaced.
+	Set lnwire.CustomRecords
+
+	// Remove is the set of record types to remove from the announcement.
+	Remove []uint64
+}
+
+// ValidateCustomRecordType checks that a custom record of the given type can
+// be included in an announcement.
+func ValidateCustomRecordType(recordType uint64) error {
+	if recordType < lnwire.MinCustomRecordsTlvType {
+		return fmt.Errorf("%w: %d", ErrCustomRecordTypeOfficial,
+			recordType)
+	}
+
+	if recordType%2 == 0 {
+		return fmt.Errorf("%w: %d", ErrCustomRecordTypeEven,
+			recordType)
+	}
+
+	return nil
+}
+
+// Validate checks that all records of the update can be included in an
+// announcement, and that no record is both set and removed.
+func (u CustomRecordsUpdate) Validate() error {
+	for recordType := range u.Set {
+		if err := ValidateCustomRecordType(recordType); err != nil {
+			return err
+		}
+	}
+
+	for _, recordType := range u.Remove {
+		if err := ValidateCustomRecordType(recordType); err != nil {
+			return err
+		}
+
+		if _, ok := u.Set[recordType]; ok {
+			return fmt.Errorf("custom record %d can't be both set "+
+				"and removed", recordType)
+		}
+	}
+
+	return nil
+}
+
+// apply returns the given extra opaque data with the update applied. Records
+// that aren't custom records are left untouched.
+func (u CustomRecordsUpdate) apply(
+	extraData lnwire.ExtraOpaqueData) (lnwire.ExtraOpaqueData, error) {
+
+	tlvMap, err := extraData.ExtractRecords()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, recordType := range u.Remove {
+		delete(tlvMap, tlv.Type(recordType))
+	}
+	for recordType, value := range u.Set {
+		tlvMap[tlv.Type(recordType)] = value
+	}
+
+	newExtraData, err := lnwire.NewExtraOpaqueData(tlvMap)
+	if err != nil {
+		return nil, err
+	}
+
+	// Make sure the announcement can still be stored in the graph, as
+	// it would otherwise be rejected after being signed.
+	if len(newExtraData) > channeldb.MaxAllowedExtraOpaqueBytes {
+		return nil, channeldb.ErrTooManyExtraOpaqueBytes(
+			len(newExtraData),
+		)
+	}
+
+	return newExtraData, nil
+}
+
+// CustomRecords returns the custom records that are included in the given
+// extra opaque data of an announcement.
+func CustomRecords(
+	extraData lnwire.ExtraOpaqueData) (lnwire.CustomRecords, error) {
+
+	customRecords, _, _, err := lnwire.ParseAndExtractCustomRecords(
+		extraData,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return customRecords, nil
+}
+
+// NodeAnnUpdateCustomRecords returns a functional option that applies the
+// given update to the custom records of a node announcement. The update is
+// validated up front, so an error is returned if it contains records that
+// can't be advertised.
+func NodeAnnUpdateCustomRecords(
+	update CustomRecordsUpdate) (NodeAnnModifier, error) {
+
+	if err := update.Validate(); err != nil {
+		return nil, err
+	}
+
+	return func(nodeAnn *lnwire.NodeAnnouncement) {
+		extraData, err := update.apply(nodeAnn.ExtraOpaqueData)
+		if err != nil {
+			log.Errorf("Unable to update custom records of node "+
+				"announcement: %v", err)
+
+			return
+		}
+
+		nodeAnn.ExtraOpaqueData = extraData
+	}, nil
+}
+
+// ChanUpdUpdateCustomRecords returns a functional option that applies the
+// given update to the custom records of a channel update. The update is
+// validated up front, so an error is returned if it contains records that
+// can't be advertised.
+func ChanUpdUpdateCustomRecords(
+	update CustomRecordsUpdate) (ChannelUpdateModifier, error) {
+
+	if err := update.Validate(); err != nil {
+		return nil, err
+	}
+
+	return func(chanUpdate *lnwire.ChannelUpdate1) {
+		extraData, err := update.apply(chanUpdate.ExtraOpaqueData)
+		if err != nil {
+			log.Errorf("Unable to update custom records of "+
+				"channel update for %v: %v",
+				chanUpdate.ShortChannelID, err)
+
+			return
+		}
+
+		chanUpdate.ExtraOpaqueData = extraData
+	}, nil
+}
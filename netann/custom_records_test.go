@@ -0,0 +1,148 @@
+package netann
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tlv"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCustomRecordsUpdateValidate tests that only odd custom record types
+// that don't collide with official types can be advertised.
+func TestCustomRecordsUpdateValidate(t *testing.T) {
+	t.Parallel()
+
+	const oddType = lnwire.MinCustomRecordsTlvType + 1
+
+	testCases := []struct {
+		name   string
+		update CustomRecordsUpdate
+		err    error
+	}{
+		{
+			name: "valid update",
+			update: CustomRecordsUpdate{
+				Set: lnwire.CustomRecords{
+					oddType: []byte("service"),
+				},
+				Remove: []uint64{oddType + 2},
+			},
+		},
+		{
+			name: "official type",
+			update: CustomRecordsUpdate{
+				Set: lnwire.CustomRecords{
+					1: []byte("service"),
+				},
+			},
+			err: ErrCustomRecordTypeOfficial,
+		},
+		{
+			name: "even type",
+			update: CustomRecordsUpdate{
+				Set: lnwire.CustomRecords{
+					oddType + 1: []byte("service"),
+				},
+			},
+			err: ErrCustomRecordTypeEven,
+		},
+		{
+			name: "remove official type",
+			update: CustomRecordsUpdate{
+				Remove: []uint64{3},
+			},
+			err: ErrCustomRecordTypeOfficial,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NodeAnnUpdateCustomRecords(tc.update)
+			require.ErrorIs(t, err, tc.err)
+
+			_, err = ChanUpdUpdateCustomRecords(tc.update)
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+
+	// A record can't be set and removed in the same update.
+	_, err := NodeAnnUpdateCustomRecords(CustomRecordsUpdate{
+		Set: lnwire.CustomRecords{
+			oddType: []byte("service"),
+		},
+		Remove: []uint64{oddType},
+	})
+	require.Error(t, err)
+}
+
+// TestNodeAnnUpdateCustomRecords tests that custom records can be set,
+// replaced and removed from a node announcement, without touching other
+// records in its extra opaque data.
+func TestNodeAnnUpdateCustomRecords(t *testing.T) {
+	t.Parallel()
+
+	const (
+		serviceType = lnwire.MinCustomRecordsTlvType + 1
+		assetType   = lnwire.MinCustomRecordsTlvType + 3
+	)
+
+	// Start with an announcement that already carries a record of a type
+	// that isn't a custom record.
+	extraData, err := lnwire.NewExtraOpaqueData(tlv.TypeMap{
+		1: []byte("other"),
+	})
+	require.NoError(t, err)
+
+	nodeAnn := &lnwire.NodeAnnouncement{
+		ExtraOpaqueData: extraData,
+	}
+
+	applyUpdate := func(update CustomRecordsUpdate) lnwire.CustomRecords {
+		modifier, err := NodeAnnUpdateCustomRecords(update)
+		require.NoError(t, err)
+
+		modifier(nodeAnn)
+
+		records, err := CustomRecords(nodeAnn.ExtraOpaqueData)
+		require.NoError(t, err)
+
+		return records
+	}
+
+	records := applyUpdate(CustomRecordsUpdate{
+		Set: lnwire.CustomRecords{
+			serviceType: []byte("service"),
+			assetType:   []byte("asset"),
+		},
+	})
+	require.Equal(t, lnwire.CustomRecords{
+		serviceType: []byte("service"),
+		assetType:   []byte("asset"),
+	}, records)
+
+	records = applyUpdate(CustomRecordsUpdate{
+		Set: lnwire.CustomRecords{
+			serviceType: []byte("new-service"),
+		},
+		Remove: []uint64{assetType},
+	})
+	require.Equal(t, lnwire.CustomRecords{
+		serviceType: []byte("new-service"),
+	}, records)
+
+	// The record that isn't a custom record is still there.
+	tlvMap, err := nodeAnn.ExtraOpaqueData.ExtractRecords()
+	require.NoError(t, err)
+	require.Equal(t, []byte("other"), tlvMap[1])
+
+	// An update that makes the extra data too large to be stored leaves
+	// the announcement untouched.
+	oldExtraData := nodeAnn.ExtraOpaqueData
+	applyUpdate(CustomRecordsUpdate{
+		Set: lnwire.CustomRecords{
+			assetType: make([]byte, 10_000),
+		},
+	})
+	require.Equal(t, oldExtraData, nodeAnn.ExtraOpaqueData)
+}
@@ -0,0 +1,246 @@
+package netann
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+var (
+	// ErrInvalidMaintenanceWindow signals that a maintenance window could
+	// not be scheduled because it has no duration or already ended.
+	ErrInvalidMaintenanceWindow = errors.New("maintenance window must " +
+		"have a positive duration and end in the future")
+
+	// ErrUnknownMaintenanceWindow signals that no maintenance window with
+	// the requested ID is scheduled.
+	ErrUnknownMaintenanceWindow = errors.New("unknown maintenance window")
+)
+
+// MaintenanceWindow is a period of planned downtime during which the
+// ChanStatusManager keeps channels disabled on the network.
+type MaintenanceWindow struct {
+	// ID uniquely identifies the window.
+	ID uint64
+
+	// Start is the time at which the channels are disabled.
+	Start time.Time
+
+	// Duration is how long the channels stay disabled.
+	Duration time.Duration
+
+	// ChanPoints are the channels that are disabled during the window. If
+	// empty, all public channels are disabled.
+	ChanPoints []wire.OutPoint
+
+	// Active is true if the window has started and its channels are
+	// disabled.
+	Active bool
+}
+
+// End returns the time at which the channels are re-enabled.
+func (w *MaintenanceWindow) End() time.Time {
+	return w.Start.Add(w.Duration)
+}
+
+// maintenanceState tracks a scheduled maintenance window.
+type maintenanceState struct {
+	MaintenanceWindow
+
+	// cancelled is set if the window was cancelled while active. Its
+	// channels are re-enabled on the next sample.
+	cancelled bool
+
+	// disabled are the channels that were disabled by the window and are
+	// to be re-enabled once it ends.
+	disabled []wire.OutPoint
+}
+
+// ScheduleMaintenance schedules a maintenance window that disables the given
+// channels, or all public channels if none are given, at start and re-enables
+// them after duration. While the window is active, the channels are treated as
+// manually disabled so that they aren't re-enabled automatically. Channels that
+// were already manually disabled when the window starts are left disabled once
+// it ends.
+//
+// NOTE: Windows are evaluated every ChanStatusSampleInterval, so channels are
+// disabled and re-enabled up to one interval late. Windows aren't persisted
+// and need to be scheduled again after a restart.
+func (m *ChanStatusManager) ScheduleMaintenance(start time.Time,
+	duration time.Duration, chanPoints ...wire.OutPoint) (uint64, error) {
+
+	if duration <= 0 || !start.Add(duration).After(time.Now()) {
+		return 0, ErrInvalidMaintenanceWindow
+	}
+
+	m.maintenanceMtx.Lock()
+	defer m.maintenanceMtx.Unlock()
+
+	m.nextMaintenanceID++
+	id := m.nextMaintenanceID
+
+	m.maintenanceWindows[id] = &maintenanceState{
+		MaintenanceWindow: MaintenanceWindow{
+			ID:         id,
+			Start:      start,
+			Duration:   duration,
+			ChanPoints: chanPoints,
+		},
+	}
+
+	log.Infof("Scheduled maintenance window %d from %v for %v", id, start,
+		duration)
+
+	return id, nil
+}
+
+// CancelMaintenance cancels the maintenance window with the given ID. If the
+// window is already active, its channels are re-enabled on the next sample.
+func (m *ChanStatusManager) CancelMaintenance(id uint64) error {
+	m.maintenanceMtx.Lock()
+	defer m.maintenanceMtx.Unlock()
+
+	window, ok := m.maintenanceWindows[id]
+	if !ok || window.cancelled {
+		return ErrUnknownMaintenanceWindow
+	}
+
+	log.Infof("Cancelling maintenance window %d", id)
+
+	if !window.Active {
+		delete(m.maintenanceWindows, id)
+		return nil
+	}
+
+	window.cancelled = true
+
+	return nil
+}
+
+// MaintenanceWindows returns the scheduled and active maintenance windows,
+// ordered by their start time.
+func (m *ChanStatusManager) MaintenanceWindows() []MaintenanceWindow {
+	m.maintenanceMtx.Lock()
+	defer m.maintenanceMtx.Unlock()
+
+	windows := make([]MaintenanceWindow, 0, len(m.maintenanceWindows))
+	for _, window := range m.maintenanceWindows {
+		if window.cancelled {
+			continue
+		}
+
+		windows = append(windows, window.MaintenanceWindow)
+	}
+
+	sort.Slice(windows, func(i, j int) bool {
+		return windows[i].Start.Before(windows[j].Start)
+	})
+
+	return windows
+}
+
+// processMaintenanceWindows starts the maintenance windows whose start time has
+// passed, and ends those that expired or were cancelled.
+func (m *ChanStatusManager) processMaintenanceWindows() {
+	m.maintenanceMtx.Lock()
+	defer m.maintenanceMtx.Unlock()
+
+	now := time.Now()
+	for id, window := range m.maintenanceWindows {
+		ended := window.cancelled || !window.End().After(now)
+
+		switch {
+		case ended:
+			if window.Active {
+				m.endMaintenance(window)
+			}
+			delete(m.maintenanceWindows, id)
+
+		case !window.Active && !window.Start.After(now):
+			m.startMaintenance(window)
+		}
+	}
+}
+
+// startMaintenance disables the channels of the maintenance window.
+func (m *ChanStatusManager) startMaintenance(window *maintenanceState) {
+	log.Infof("Starting maintenance window %d", window.ID)
+
+	window.Active = true
+
+	chanPoints := window.ChanPoints
+	if len(chanPoints) == 0 {
+		channels, err := m.fetchChannels()
+		if err != nil {
+			log.Errorf("Unable to load channels for maintenance "+
+				"window %d: %v", window.ID, err)
+			return
+		}
+
+		for _, c := range channels {
+			chanPoints = append(chanPoints, c.FundingOutpoint)
+		}
+	}
+
+	for _, outpoint := range chanPoints {
+		// If another window already disabled the channel, we only
+		// record that this window holds it as well.
+		if m.maintenanceChans[outpoint] > 0 {
+			m.maintenanceChans[outpoint]++
+			window.disabled = append(window.disabled, outpoint)
+
+			continue
+		}
+
+		curState, err := m.getOrInitChanStatus(outpoint)
+		if err != nil {
+			log.Errorf("Unable to retrieve chan status for "+
+				"Channel(%v): %v", outpoint, err)
+			continue
+		}
+
+		// Channels the user disabled are left alone, so that they
+		// stay disabled once the window ends.
+		if curState.Status == ChanStatusManuallyDisabled {
+			continue
+		}
+
+		if err := m.processDisableRequest(outpoint, true); err != nil {
+			log.Errorf("Unable to disable channel(%v) for "+
+				"maintenance: %v", outpoint, err)
+			continue
+		}
+
+		m.maintenanceChans[outpoint] = 1
+		window.disabled = append(window.disabled, outpoint)
+	}
+}
+
+// endMaintenance re-enables the channels of the maintenance window that aren't
+// held by another active window. Channels whose peer is offline are returned
+// to automatic management, and are enabled once the peer reconnects.
+func (m *ChanStatusManager) endMaintenance(window *maintenanceState) {
+	log.Infof("Ending maintenance window %d", window.ID)
+
+	for _, outpoint := range window.disabled {
+		m.maintenanceChans[outpoint]--
+		if m.maintenanceChans[outpoint] > 0 {
+			continue
+		}
+		delete(m.maintenanceChans, outpoint)
+
+		if err := m.processAutoRequest(outpoint); err != nil {
+			log.Errorf("Unable to restore automatic control of "+
+				"channel(%v): %v", outpoint, err)
+			continue
+		}
+
+		err := m.processEnableRequest(outpoint, false)
+		if err != nil && !errors.Is(err, ErrEnableInactiveChan) {
+			log.Errorf("Unable to enable channel(%v) after "+
+				"maintenance: %v", outpoint, err)
+		}
+	}
+}
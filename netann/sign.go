@@ -14,6 +14,17 @@ import (
 func SignAnnouncement(signer lnwallet.MessageSigner, keyLoc keychain.KeyLocator,
 	msg lnwire.Message) (input.Signature, error) {
 
+	data, err := announcementDataToSign(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return signer.SignMessage(keyLoc, data, true)
+}
+
+// announcementDataToSign returns the data of a gossip message that is covered
+// by its signature.
+func announcementDataToSign(msg lnwire.Message) ([]byte, error) {
 	var (
 		data []byte
 		err  error
@@ -33,5 +44,5 @@ func SignAnnouncement(signer lnwallet.MessageSigner, keyLoc keychain.KeyLocator,
 		return nil, fmt.Errorf("unable to get data to sign: %w", err)
 	}
 
-	return signer.SignMessage(keyLoc, data, true)
+	return data, nil
 }
@@ -0,0 +1,164 @@
+package peer
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+var (
+	// allowListBucket is the top level bucket that stores the peers that
+	// were added to the allow list at runtime.
+	allowListBucket = []byte("peer-allow-list")
+
+	// ErrPeerNotAllowed is returned when a connection to or from a peer
+	// that isn't on the allow list is attempted.
+	ErrPeerNotAllowed = errors.New("peer is not on the allow list")
+
+	// ErrStaticAllowedPeer is returned when a peer that is on the allow
+	// list through the config is removed at runtime.
+	ErrStaticAllowedPeer = errors.New("peer is on the allow list " +
+		"through the config and can't be removed")
+)
+
+// AllowList is the set of peers that are allowed to connect to us, and that
+// we're allowed to connect to. It consists of the peers configured at startup
+// and the peers added at runtime, of which only the latter are persisted.
+type AllowList struct {
+	db kvdb.Backend
+
+	// static is the set of peers that were configured at startup.
+	static map[route.Vertex]struct{}
+
+	// dynamic is the set of peers that were added at runtime.
+	dynamic map[route.Vertex]struct{}
+
+	mu sync.RWMutex
+}
+
+// NewAllowList creates a new allow list from the statically configured peers
+// and the peers that were previously added at runtime and persisted in the
+// passed database.
+func NewAllowList(db kvdb.Backend,
+	static []route.Vertex) (*AllowList, error) {
+
+	a := &AllowList{
+		db:      db,
+		static:  make(map[route.Vertex]struct{}, len(static)),
+		dynamic: make(map[route.Vertex]struct{}),
+	}
+	for _, peer := range static {
+		a.static[peer] = struct{}{}
+	}
+
+	err := kvdb.Update(db, func(tx kvdb.RwTx) error {
+		bucket, err := tx.CreateTopLevelBucket(allowListBucket)
+		if err != nil {
+			return err
+		}
+
+		return bucket.ForEach(func(k, _ []byte) error {
+			peer, err := route.NewVertexFromBytes(k)
+			if err != nil {
+				return err
+			}
+			a.dynamic[peer] = struct{}{}
+
+			return nil
+		})
+	}, func() {
+		a.dynamic = make(map[route.Vertex]struct{})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// IsAllowed returns true if the given peer is on the allow list.
+func (a *AllowList) IsAllowed(peer route.Vertex) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if _, ok := a.static[peer]; ok {
+		return true
+	}
+	_, ok := a.dynamic[peer]
+
+	return ok
+}
+
+// Add adds the given peer to the allow list.
+func (a *AllowList) Add(peer route.Vertex) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	err := kvdb.Update(a.db, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(allowListBucket)
+		if bucket == nil {
+			return kvdb.ErrBucketNotFound
+		}
+
+		return bucket.Put(peer[:], nil)
+	}, func() {})
+	if err != nil {
+		return err
+	}
+
+	a.dynamic[peer] = struct{}{}
+
+	return nil
+}
+
+// Remove removes the given peer from the allow list. Peers that are on the
+// allow list through the config can't be removed.
+func (a *AllowList) Remove(peer route.Vertex) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, ok := a.static[peer]; ok {
+		return ErrStaticAllowedPeer
+	}
+
+	err := kvdb.Update(a.db, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(allowListBucket)
+		if bucket == nil {
+			return kvdb.ErrBucketNotFound
+		}
+
+		return bucket.Delete(peer[:])
+	}, func() {})
+	if err != nil {
+		return err
+	}
+
+	delete(a.dynamic, peer)
+
+	return nil
+}
+
+// List returns all peers on the allow list, sorted by their public key.
+func (a *AllowList) List() []route.Vertex {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	peers := make([]route.Vertex, 0, len(a.static)+len(a.dynamic))
+	for peer := range a.static {
+		peers = append(peers, peer)
+	}
+	for peer := range a.dynamic {
+		if _, ok := a.static[peer]; !ok {
+			peers = append(peers, peer)
+		}
+	}
+
+	sort.Slice(peers, func(i, j int) bool {
+		return string(peers[i][:]) < string(peers[j][:])
+	})
+
+	return peers
+}
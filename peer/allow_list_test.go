@@ -0,0 +1,62 @@
+package peer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAllowList tests that peers can be added to and removed from the allow
+// list, that statically configured peers can't be removed, and that the
+// peers added at runtime are persisted.
+func TestAllowList(t *testing.T) {
+	t.Parallel()
+
+	db, err := kvdb.Create(
+		kvdb.BoltBackendName, filepath.Join(t.TempDir(), "test.db"),
+		true, kvdb.DefaultDBTimeout,
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	var (
+		static  = route.Vertex{1}
+		dynamic = route.Vertex{2}
+		unknown = route.Vertex{3}
+	)
+
+	allowList, err := NewAllowList(db, []route.Vertex{static})
+	require.NoError(t, err)
+
+	require.True(t, allowList.IsAllowed(static))
+	require.False(t, allowList.IsAllowed(dynamic))
+	require.False(t, allowList.IsAllowed(unknown))
+
+	require.NoError(t, allowList.Add(dynamic))
+	require.True(t, allowList.IsAllowed(dynamic))
+	require.Equal(t, []route.Vertex{static, dynamic}, allowList.List())
+
+	// Statically configured peers can't be removed.
+	err = allowList.Remove(static)
+	require.ErrorIs(t, err, ErrStaticAllowedPeer)
+	require.True(t, allowList.IsAllowed(static))
+
+	// The dynamically added peer should survive a restart, while the
+	// static peers are only taken from the config.
+	allowList, err = NewAllowList(db, nil)
+	require.NoError(t, err)
+	require.False(t, allowList.IsAllowed(static))
+	require.True(t, allowList.IsAllowed(dynamic))
+
+	require.NoError(t, allowList.Remove(dynamic))
+	require.False(t, allowList.IsAllowed(dynamic))
+
+	allowList, err = NewAllowList(db, nil)
+	require.NoError(t, err)
+	require.Empty(t, allowList.List())
+}
@@ -2,9 +2,9 @@ package peer
 
 import (
 	"bytes"
-	"container/list"
 	"errors"
 	"fmt"
+	"io"
 	"math/rand"
 	"net"
 	"strings"
@@ -45,6 +45,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/msgmux"
 	"github.com/lightningnetwork/lnd/netann"
+	"github.com/lightningnetwork/lnd/peernotifier"
 	"github.com/lightningnetwork/lnd/pool"
 	"github.com/lightningnetwork/lnd/queue"
 	"github.com/lightningnetwork/lnd/subscribe"
@@ -90,6 +91,26 @@ const (
 	// torTimeoutMultiplier is the scaling factor we use on network timeouts
 	// for Tor peers.
 	torTimeoutMultiplier = 3
+
+	// outgoingQueueLen is the maximum number of low priority messages the
+	// queueHandler buffers. Once it's reached, new low priority messages
+	// are dropped rather than blocking the sub-systems sending them. High
+	// priority messages are never dropped.
+	outgoingQueueLen = 10000
+
+	// priorityMsgWeight and lazyMsgWeight are the weights of the high and
+	// low priority messages in the outgoing message queue. Each round,
+	// up to priorityMsgWeight high priority messages are sent for every
+	// low priority message.
+	priorityMsgWeight = 8
+	lazyMsgWeight     = 1
+)
+
+const (
+	// priorityMsgClass and lazyMsgClass are the classes of the high and
+	// low priority messages in the outgoing message queue.
+	priorityMsgClass = iota
+	lazyMsgClass
 )
 
 var (
@@ -97,6 +118,18 @@ var (
 	// either the Brontide doesn't know of it, or the channel in question
 	// is pending.
 	ErrChannelNotFound = fmt.Errorf("channel not found")
+
+	// ErrPingTimeout is the disconnect reason used when the peer doesn't
+	// answer our pings, or doesn't send us anything at all, in time.
+	ErrPingTimeout = errors.New("ping timeout")
+
+	// ErrRemoteClosed is the disconnect reason used when the peer closed
+	// the connection.
+	ErrRemoteClosed = errors.New("connection closed by remote peer")
+
+	// ErrProtocolError is the disconnect reason used when we fail to read
+	// a message sent by the peer.
+	ErrProtocolError = errors.New("protocol error")
 )
 
 // outgoingMsg packages an lnwire.Message to be sent out on the wire, along with
@@ -201,6 +234,10 @@ type Config struct {
 	// an htlc where we don't offer it anymore.
 	OutgoingCltvRejectDelta uint32
 
+	// ChanCltvRejectDeltas overrides OutgoingCltvRejectDelta for
+	// individual channels.
+	ChanCltvRejectDeltas map[lnwire.ShortChannelID]uint32
+
 	// ChanActiveTimeout specifies the duration the peer will wait to request
 	// a channel reenable, beginning from the time the peer was started.
 	ChanActiveTimeout time.Duration
@@ -236,7 +273,7 @@ type Config struct {
 
 	// ChannelGraph is a pointer to the channel graph which is used to
 	// query information about the set of known active channels.
-	ChannelGraph *channeldb.ChannelGraph
+	ChannelGraph channeldb.GraphStore
 
 	// ChainArb is used to subscribe to channel events, update contract signals,
 	// and force close channels.
@@ -246,6 +283,11 @@ type Config struct {
 	// gossiper and process remote channel announcements.
 	AuthGossiper *discovery.AuthenticatedGossiper
 
+	// ValidateGossipSemantics indicates whether gossip messages are checked
+	// for internally inconsistent fields after being decoded. Messages
+	// failing the check are dropped before reaching the gossiper.
+	ValidateGossipSemantics bool
+
 	// ChanStatusMgr is used to set or un-set the disabled bit in channel
 	// updates.
 	ChanStatusMgr *netann.ChanStatusManager
@@ -320,6 +362,12 @@ type Config struct {
 	FetchLastChanUpdate func(lnwire.ShortChannelID) (*lnwire.ChannelUpdate1,
 		error)
 
+	// FetchAliasChanUpdate fetches our latest channel update for an
+	// unannounced channel that negotiated the option-scid-alias feature,
+	// using the peer's alias instead of the confirmed SCID.
+	FetchAliasChanUpdate func(*channeldb.OpenChannel) (
+		*lnwire.ChannelUpdate1, error)
+
 	// FundingManager is an implementation of the funding.Controller interface.
 	FundingManager funding.Controller
 
@@ -376,6 +424,10 @@ type Config struct {
 	// from the peer.
 	HandleCustomMessage func(peer [33]byte, msg *lnwire.Custom) error
 
+	// HandleOnionMessage is called whenever an onion message is received
+	// from the peer.
+	HandleOnionMessage func(peer [33]byte, msg *lnwire.OnionMessage) error
+
 	// GetAliases is passed to created links so the Switch and link can be
 	// aware of the channel's aliases.
 	GetAliases func(base lnwire.ShortChannelID) []lnwire.ShortChannelID
@@ -400,6 +452,15 @@ type Config struct {
 	// way contracts are resolved.
 	AuxResolver fn.Option[lnwallet.AuxContractResolver]
 
+	// ClearnetPing is the ping configuration used for clearnet peers.
+	// Zero values are replaced by the defaults.
+	ClearnetPing PingConfig
+
+	// TorPing is the ping configuration used for peers that are connected
+	// over Tor. Zero values are replaced by the defaults scaled by the Tor
+	// timeout multiplier.
+	TorPing PingConfig
+
 	// PongBuf is a slice we'll reuse instead of allocating memory on the
 	// heap. Since only reads will occur and no writes, there is no need
 	// for any synchronization primitives. As a result, it's safe to share
@@ -415,6 +476,16 @@ type Config struct {
 	// This value will be passed to created links.
 	MaxFeeExposure lnwire.MilliSatoshi
 
+	// LocalHtlcSlotReserve is the fraction of the HTLC slots of each
+	// channel that is reserved for our own payments. This value will be
+	// passed to created links.
+	LocalHtlcSlotReserve float64
+
+	// HtlcChainCostMultiplier is the multiple of the worst-case on-chain
+	// cost of resolving an outgoing HTLC that a forward must at least be
+	// worth. This value will be passed to created links.
+	HtlcChainCostMultiplier float64
+
 	// MsgRouter is an optional instance of the main message router that
 	// the peer will use. If None, then a new default version will be used
 	// in place.
@@ -439,6 +510,10 @@ type Brontide struct {
 	started    int32
 	disconnect int32
 
+	// disconnectErr is the reason the peer was disconnected with. It is
+	// set once by the first call to Disconnect.
+	disconnectErr atomic.Pointer[error]
+
 	// MUST be used atomically.
 	bytesReceived uint64
 	bytesSent     uint64
@@ -662,19 +737,20 @@ func NewBrontide(cfg Config) *Brontide {
 	// NOTE(proofofkeags): this was changed to be dynamic to allow better
 	// pong identification, however, more thought is needed to make this
 	// actually usable as a traffic decoy.
+	pingCfg := p.pingConfig()
 	randPongSize := func() uint16 {
 		return uint16(
 			// We don't need cryptographic randomness here.
 			/* #nosec */
-			rand.Intn(pongSizeCeiling) + 1,
+			rand.Intn(int(pingCfg.MaxPongBytes)) + 1,
 		)
 	}
 
 	p.pingManager = NewPingManager(&PingManagerConfig{
 		NewPingPayload:   newPingPayload,
 		NewPongSize:      randPongSize,
-		IntervalDuration: p.scaleTimeout(pingInterval),
-		TimeoutDuration:  p.scaleTimeout(pingTimeout),
+		IntervalDuration: pingCfg.Interval,
+		TimeoutDuration:  pingCfg.Timeout,
 		SendPing: func(ping *lnwire.Ping) {
 			p.queueMsg(ping, nil)
 		},
@@ -682,7 +758,8 @@ func NewBrontide(cfg Config) *Brontide {
 			eStr := "pong response failure for %s: %v " +
 				"-- disconnecting"
 			p.log.Warnf(eStr, p, err)
-			go p.Disconnect(fmt.Errorf(eStr, p, err))
+			go p.Disconnect(fmt.Errorf("%w: "+eStr, ErrPingTimeout,
+				p, err))
 		},
 	})
 
@@ -1277,6 +1354,14 @@ func (p *Brontide) addLink(chanPoint *wire.OutPoint,
 		return p.cfg.ChainArb.NotifyContractUpdate(*chanPoint, update)
 	}
 
+	// Use the channel's own outgoing cltv reject delta if one is
+	// configured.
+	rejectDelta := p.cfg.OutgoingCltvRejectDelta
+	scid := lnChan.ShortChanID()
+	if delta, ok := p.cfg.ChanCltvRejectDeltas[scid]; ok {
+		rejectDelta = delta
+	}
+
 	//nolint:lll
 	linkCfg := htlcswitch.ChannelLinkConfig{
 		Peer:                   p,
@@ -1305,7 +1390,7 @@ func (p *Brontide) addLink(chanPoint *wire.OutPoint,
 		UnsafeReplay:            p.cfg.UnsafeReplay,
 		MinUpdateTimeout:        htlcswitch.DefaultMinLinkFeeUpdateTimeout,
 		MaxUpdateTimeout:        htlcswitch.DefaultMaxLinkFeeUpdateTimeout,
-		OutgoingCltvRejectDelta: p.cfg.OutgoingCltvRejectDelta,
+		OutgoingCltvRejectDelta: rejectDelta,
 		TowerClient:             p.cfg.TowerClient,
 		MaxOutgoingCltvExpiry:   p.cfg.MaxOutgoingCltvExpiry,
 		MaxFeeAllocation:        p.cfg.MaxChannelFeeAllocation,
@@ -1319,6 +1404,8 @@ func (p *Brontide) addLink(chanPoint *wire.OutPoint,
 		PreviouslySentShutdown:  shutdownMsg,
 		DisallowRouteBlinding:   p.cfg.DisallowRouteBlinding,
 		MaxFeeExposure:          p.cfg.MaxFeeExposure,
+		LocalHtlcSlotReserve:    p.cfg.LocalHtlcSlotReserve,
+		HtlcChainCostMultiplier: p.cfg.HtlcChainCostMultiplier,
 	}
 
 	// Before adding our new link, purge the switch of any pending or live
@@ -1402,7 +1489,20 @@ func (p *Brontide) maybeSendChannelUpdates() {
 		// to fetch the update to send to the remote peer. If the
 		// channel is pending, and not a zero conf channel, we'll get
 		// an error here which we'll ignore.
-		chanUpd, err := p.cfg.FetchLastChanUpdate(scid)
+		//
+		// Unannounced channels that negotiated the option-scid-alias
+		// feature must only be referred to by the peer's alias, so
+		// their update never carries the confirmed SCID.
+		var (
+			chanUpd *lnwire.ChannelUpdate1
+			err     error
+		)
+		isPublic := dbChan.ChannelFlags&lnwire.FFAnnounceChannel != 0
+		if dbChan.NegotiatedAliasFeature() && !isPublic {
+			chanUpd, err = p.cfg.FetchAliasChanUpdate(dbChan)
+		} else {
+			chanUpd, err = p.cfg.FetchLastChanUpdate(scid)
+		}
 		if err != nil {
 			p.log.Debugf("Unable to fetch channel update for "+
 				"ChannelPoint(%v), scid=%v: %v",
@@ -1473,6 +1573,8 @@ func (p *Brontide) Disconnect(reason error) {
 		return
 	}
 
+	p.disconnectErr.Store(&reason)
+
 	err := fmt.Errorf("disconnecting %s, reason: %v", p, reason)
 	p.storeError(err)
 
@@ -1866,8 +1968,8 @@ func (p *Brontide) readHandler() {
 	// We'll stop the timer after a new messages is received, and also
 	// reset it after we process the next message.
 	idleTimer := time.AfterFunc(idleTimeout, func() {
-		err := fmt.Errorf("peer %s no answer for %s -- disconnecting",
-			p, idleTimeout)
+		err := fmt.Errorf("%w: peer %s no answer for %s -- "+
+			"disconnecting", ErrPingTimeout, p, idleTimeout)
 		p.Disconnect(err)
 	})
 
@@ -1882,6 +1984,10 @@ func (p *Brontide) readHandler() {
 	discStream := newDiscMsgStream(p)
 	discStream.Start()
 	defer discStream.Stop()
+
+	// readErr is the error that made us stop reading from the peer, if
+	// any.
+	var readErr error
 out:
 	for atomic.LoadInt32(&p.disconnect) == 0 {
 		nextMsg, err := p.readNextMessage()
@@ -1930,6 +2036,7 @@ out:
 			// didn't recognize, then we'll stop all processing as
 			// this is a fatal error.
 			default:
+				readErr = err
 				break out
 			}
 		}
@@ -1975,7 +2082,14 @@ out:
 			*lnwire.AcceptChannel,
 			*lnwire.FundingCreated,
 			*lnwire.FundingSigned,
-			*lnwire.ChannelReady:
+			*lnwire.ChannelReady,
+			*lnwire.OpenChannel2,
+			*lnwire.TxAddInput,
+			*lnwire.TxAddOutput,
+			*lnwire.TxRemoveInput,
+			*lnwire.TxRemoveOutput,
+			*lnwire.TxComplete,
+			*lnwire.TxAbort:
 
 			p.cfg.FundingManager.ProcessFundingMsg(msg, p)
 
@@ -2046,6 +2160,16 @@ out:
 			*lnwire.ReplyChannelRange,
 			*lnwire.ReplyShortChanIDsEnd:
 
+			if p.cfg.ValidateGossipSemantics {
+				err := lnwire.ValidateSemantics(msg)
+				if err != nil {
+					p.log.Debugf("Dropping invalid %v: %v",
+						nextMsg.MsgType(), err)
+
+					break
+				}
+			}
+
 			discStream.AddMsg(msg)
 
 		case *lnwire.Custom:
@@ -2055,6 +2179,13 @@ out:
 				p.log.Errorf("%v", err)
 			}
 
+		case *lnwire.OnionMessage:
+			err := p.handleOnionMessage(msg)
+			if err != nil {
+				p.storeError(err)
+				p.log.Errorf("%v", err)
+			}
+
 		default:
 			// If the message we received is unknown to us, store
 			// the type to track the failure.
@@ -2074,7 +2205,11 @@ out:
 		idleTimer.Reset(idleTimeout)
 	}
 
-	p.Disconnect(errors.New("read handler closed"))
+	if readErr != nil {
+		p.Disconnect(wrapReadErr(readErr))
+	} else {
+		p.Disconnect(errors.New("read handler closed"))
+	}
 
 	p.log.Trace("readHandler for peer done")
 }
@@ -2090,6 +2225,16 @@ func (p *Brontide) handleCustomMessage(msg *lnwire.Custom) error {
 	return p.cfg.HandleCustomMessage(p.PubKey(), msg)
 }
 
+// handleOnionMessage handles the given onion message if a handler is
+// registered.
+func (p *Brontide) handleOnionMessage(msg *lnwire.OnionMessage) error {
+	if p.cfg.HandleOnionMessage == nil {
+		return fmt.Errorf("no onion message handler")
+	}
+
+	return p.cfg.HandleOnionMessage(p.PubKey(), msg)
+}
+
 // isLoadedFromDisk returns true if the provided channel ID is loaded from
 // disk.
 //
@@ -2409,11 +2554,8 @@ func (p *Brontide) logWireMessage(msg lnwire.Message, read bool) {
 	p.log.Tracef(prefix+": %v", lnutils.SpewLogClosure(msg))
 }
 
-// writeMessage writes and flushes the target lnwire.Message to the remote peer.
-// If the passed message is nil, this method will only try to flush an existing
-// message buffered on the connection. It is safe to call this method again
-// with a nil message iff a timeout error is returned. This will continue to
-// flush the pending message to the wire.
+// writeMessage writes and flushes the target lnwire.Message to the remote peer,
+// using the write deadline of the message's deadline class.
 //
 // NOTE:
 // Besides its usage in Start, this function should not be used elsewhere
@@ -2421,6 +2563,22 @@ func (p *Brontide) logWireMessage(msg lnwire.Message, read bool) {
 // time, panics can occur because WriteMessage and Flush don't use any locking
 // internally.
 func (p *Brontide) writeMessage(msg lnwire.Message) error {
+	return p.writeMessageWithDeadline(
+		msg, deadlineClass(msg).deadlines().write,
+	)
+}
+
+// writeMessageWithDeadline writes and flushes the target lnwire.Message to the
+// remote peer, failing with a timeout error if the flush doesn't complete
+// within the given write timeout. If the passed message is nil, this method
+// will only try to flush an existing message buffered on the connection. It is
+// safe to call this method again with a nil message iff a timeout error is
+// returned. This will continue to flush the pending message to the wire.
+//
+// NOTE: The same restrictions as for writeMessage apply.
+func (p *Brontide) writeMessageWithDeadline(msg lnwire.Message,
+	writeTimeout time.Duration) error {
+
 	// Only log the message on the first attempt.
 	if msg != nil {
 		p.logWireMessage(msg, false)
@@ -2431,9 +2589,7 @@ func (p *Brontide) writeMessage(msg lnwire.Message) error {
 	flushMsg := func() error {
 		// Ensure the write deadline is set before we attempt to send
 		// the message.
-		writeDeadline := time.Now().Add(
-			p.scaleTimeout(writeMessageTimeout),
-		)
+		writeDeadline := time.Now().Add(p.scaleTimeout(writeTimeout))
 		err := noiseConn.SetWriteDeadline(writeDeadline)
 		if err != nil {
 			return err
@@ -2507,18 +2663,55 @@ out:
 			// message.
 			startTime := time.Now()
 
+			// The deadlines of the message depend on its class.
+			// Messages of the commitment dance use a short stall
+			// timeout, so that a peer that stopped reading doesn't
+			// leave our HTLCs hanging until the idle timeout.
+			msgType := outMsg.msg.MsgType()
+			class := deadlineClass(outMsg.msg)
+			deadlines := class.deadlines()
+			stallDetector := newWriteStallDetector(
+				p.scaleTimeout(deadlines.stall),
+				atomic.LoadUint64(&p.bytesSent), startTime,
+			)
+
 		retry:
 			// Write out the message to the socket. If a timeout
 			// error is encountered, we will catch this and retry
 			// after backing off in case the remote peer is just
 			// slow to process messages from the wire.
-			err := p.writeMessage(outMsg.msg)
+			err := p.writeMessageWithDeadline(
+				outMsg.msg, deadlines.write,
+			)
 			if nerr, ok := err.(net.Error); ok && nerr.Timeout() {
 				p.log.Debugf("Write timeout detected for "+
 					"peer, first write for message "+
 					"attempted %v ago",
 					time.Since(startTime))
 
+				// If no bytes made it to the connection for
+				// longer than the stall timeout of the
+				// message's class, the peer stopped reading
+				// from the connection. We'll disconnect so
+				// that the connection is re-established.
+				sinceProgress, stalled := stallDetector.stalled(
+					atomic.LoadUint64(&p.bytesSent),
+					time.Now(),
+				)
+				if stalled {
+					err = fmt.Errorf("%w: no progress "+
+						"writing %v message %v for %v",
+						ErrWriteStalled, class, msgType,
+						sinceProgress)
+
+					if outMsg.errChan != nil {
+						outMsg.errChan <- err
+					}
+
+					exitErr = err
+					break out
+				}
+
 				// If we received a timeout error, this implies
 				// that the message was buffered on the
 				// connection successfully and that a flush was
@@ -2575,62 +2768,73 @@ out:
 func (p *Brontide) queueHandler() {
 	defer p.wg.Done()
 
-	// priorityMsgs holds an in order list of messages deemed high-priority
-	// to be added to the sendQueue. This predominately includes messages
-	// from the funding manager and htlcswitch.
-	priorityMsgs := list.New()
+	// msgQueue holds the messages to be added to the sendQueue. High
+	// priority messages, predominately from the funding manager and
+	// htlcswitch, are served before low priority messages, predominately
+	// from the gossiper, in weighted round robin order. This ensures that
+	// a large backlog of gossip can't starve channel updates, while a
+	// steady stream of channel updates can't starve gossip either.
+	//
+	// Only the low priority messages are bounded, so that neither the
+	// link nor the gossiper ever block on a peer that reads slowly.
+	msgQueue, err := fn.NewFairQueue[outgoingMsg](
+		fn.FairClass{Weight: priorityMsgWeight},
+		fn.FairClass{
+			Weight:   lazyMsgWeight,
+			Capacity: outgoingQueueLen,
+		},
+	)
+	if err != nil {
+		p.log.Errorf("Unable to create outgoing message queue: %v",
+			err)
 
-	// lazyMsgs holds an in order list of messages deemed low-priority to be
-	// added to the sendQueue only after all high-priority messages have
-	// been queued. This predominately includes messages from the gossiper.
-	lazyMsgs := list.New()
+		return
+	}
 
 	for {
-		// Examine the front of the priority queue, if it is empty check
-		// the low priority queue.
-		elem := priorityMsgs.Front()
-		if elem == nil {
-			elem = lazyMsgs.Front()
-		}
+		// If there's a message in the queue, we'll try adding it to
+		// the sendQueue. We also watch for messages on the
+		// outgoingQueue, in case the writeHandler cannot accept
+		// messages on the sendQueue.
+		var (
+			sendQueue chan outgoingMsg
+			front     outgoingMsg
+		)
+		msgQueue.Peek().WhenSome(func(msg outgoingMsg) {
+			sendQueue = p.sendQueue
+			front = msg
+		})
 
-		if elem != nil {
-			front := elem.Value.(outgoingMsg)
+		select {
+		case sendQueue <- front:
+			msgQueue.Dequeue()
 
-			// There's an element on the queue, try adding
-			// it to the sendQueue. We also watch for
-			// messages on the outgoingQueue, in case the
-			// writeHandler cannot accept messages on the
-			// sendQueue.
-			select {
-			case p.sendQueue <- front:
-				if front.priority {
-					priorityMsgs.Remove(elem)
-				} else {
-					lazyMsgs.Remove(elem)
-				}
-			case msg := <-p.outgoingQueue:
-				if msg.priority {
-					priorityMsgs.PushBack(msg)
-				} else {
-					lazyMsgs.PushBack(msg)
-				}
-			case <-p.quit:
-				return
+		case msg := <-p.outgoingQueue:
+			class := lazyMsgClass
+			if msg.priority {
+				class = priorityMsgClass
 			}
-		} else {
-			// If there weren't any messages to send to the
-			// writeHandler, then we'll accept a new message
-			// into the queue from outside sub-systems.
-			select {
-			case msg := <-p.outgoingQueue:
-				if msg.priority {
-					priorityMsgs.PushBack(msg)
-				} else {
-					lazyMsgs.PushBack(msg)
-				}
-			case <-p.quit:
-				return
+
+			err := msgQueue.Enqueue(class, msg)
+			switch {
+			// If the peer can't keep up with our low priority
+			// messages, we drop new ones rather than buffering
+			// them without bound.
+			case errors.Is(err, fn.ErrQueueFull):
+				p.log.Debugf("Dropping %v, outgoing queue is "+
+					"full", msg.msg.MsgType())
+
+			case err != nil:
+				p.log.Errorf("Unable to queue %v: %v",
+					msg.msg.MsgType(), err)
 			}
+
+			if err != nil && msg.errChan != nil {
+				msg.errChan <- err
+			}
+
+		case <-p.quit:
+			return
 		}
 	}
 }
@@ -4116,6 +4320,64 @@ func (p *Brontide) Inbound() bool {
 	return p.cfg.Inbound
 }
 
+// DisconnectReason returns the reason the peer was disconnected for, along
+// with the error it was disconnected with. The reason is unknown if the peer
+// hasn't been disconnected yet.
+func (p *Brontide) DisconnectReason() (peernotifier.DisconnectReason, error) {
+	errPtr := p.disconnectErr.Load()
+	if errPtr == nil {
+		return peernotifier.DisconnectReasonUnknown, nil
+	}
+
+	return disconnectReason(*errPtr), *errPtr
+}
+
+// disconnectReason maps the error a peer was disconnected with to the reason
+// that is reported to peer event subscribers. Any error that isn't caused by
+// the remote peer means we decided to disconnect.
+func disconnectReason(err error) peernotifier.DisconnectReason {
+	switch {
+	case err == nil:
+		return peernotifier.DisconnectReasonUnknown
+
+	case errors.Is(err, ErrPingTimeout):
+		return peernotifier.DisconnectReasonPingTimeout
+
+	case errors.Is(err, ErrRemoteClosed):
+		return peernotifier.DisconnectReasonRemoteClosed
+
+	case errors.Is(err, ErrProtocolError):
+		return peernotifier.DisconnectReasonProtocolError
+
+	default:
+		return peernotifier.DisconnectReasonLocal
+	}
+}
+
+// wrapReadErr wraps the error that made us stop reading from the peer with the
+// matching disconnect reason.
+func wrapReadErr(err error) error {
+	var netErr net.Error
+
+	switch {
+	// A read that timed out means the peer stopped responding.
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return fmt.Errorf("%w: %w", ErrPingTimeout, err)
+
+	// Any other failure of the underlying connection, including the peer
+	// hanging up on us, means the connection was closed remotely.
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF),
+		errors.As(err, &netErr):
+
+		return fmt.Errorf("%w: %w", ErrRemoteClosed, err)
+
+	// Otherwise, the peer sent us something we couldn't decrypt or
+	// decode.
+	default:
+		return fmt.Errorf("%w: %w", ErrProtocolError, err)
+	}
+}
+
 // ConnReq is a getter for the Brontide's connReq in cfg.
 func (p *Brontide) ConnReq() *connmgr.ConnReq {
 	return p.cfg.ConnReq
@@ -4451,3 +4713,24 @@ func (p *Brontide) scaleTimeout(timeout time.Duration) time.Duration {
 
 	return timeout
 }
+
+// pingConfig returns the ping configuration for this peer depending on whether
+// it is connected over Tor. Unset values are replaced by the defaults.
+func (p *Brontide) pingConfig() PingConfig {
+	pingCfg := p.cfg.ClearnetPing
+	if p.isTorConnection {
+		pingCfg = p.cfg.TorPing
+	}
+
+	if pingCfg.Interval == 0 {
+		pingCfg.Interval = p.scaleTimeout(pingInterval)
+	}
+	if pingCfg.Timeout == 0 {
+		pingCfg.Timeout = p.scaleTimeout(pingTimeout)
+	}
+	if pingCfg.MaxPongBytes == 0 {
+		pingCfg.MaxPongBytes = pongSizeCeiling
+	}
+
+	return pingCfg
+}
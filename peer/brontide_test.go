@@ -2,7 +2,12 @@ package peer
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"net"
+	"os"
+	"syscall"
 	"testing"
 	"time"
 
@@ -19,6 +24,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chancloser"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/peernotifier"
 	"github.com/stretchr/testify/require"
 )
 
@@ -1457,3 +1463,94 @@ func TestRemovePendingChannel(t *testing.T) {
 
 	require.NoError(t, err)
 }
+
+// TestPingConfig tests that the ping configuration of a peer depends on
+// whether it is connected over Tor and that unset values use the defaults.
+func TestPingConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := Config{
+		ClearnetPing: PingConfig{
+			Interval: 10 * time.Second,
+			Timeout:  5 * time.Second,
+		},
+		TorPing: PingConfig{
+			MaxPongBytes: 100,
+		},
+	}
+
+	clearnetPeer := &Brontide{cfg: cfg}
+	require.Equal(t, PingConfig{
+		Interval:     10 * time.Second,
+		Timeout:      5 * time.Second,
+		MaxPongBytes: pongSizeCeiling,
+	}, clearnetPeer.pingConfig())
+
+	torPeer := &Brontide{cfg: cfg, isTorConnection: true}
+	require.Equal(t, PingConfig{
+		Interval:     pingInterval * torTimeoutMultiplier,
+		Timeout:      pingTimeout * torTimeoutMultiplier,
+		MaxPongBytes: 100,
+	}, torPeer.pingConfig())
+}
+
+// TestDisconnectReason tests that the errors a peer is disconnected with are
+// mapped to the correct disconnect reason.
+func TestDisconnectReason(t *testing.T) {
+	t.Parallel()
+
+	timeoutErr := &net.OpError{Op: "read", Err: os.ErrDeadlineExceeded}
+	resetErr := &net.OpError{Op: "read", Err: syscall.ECONNRESET}
+
+	tests := []struct {
+		name   string
+		err    error
+		reason peernotifier.DisconnectReason
+	}{
+		{
+			name:   "not disconnected",
+			reason: peernotifier.DisconnectReasonUnknown,
+		},
+		{
+			name:   "local",
+			err:    errors.New("link requested disconnect"),
+			reason: peernotifier.DisconnectReasonLocal,
+		},
+		{
+			name:   "pong failure",
+			err:    fmt.Errorf("%w: pong timeout", ErrPingTimeout),
+			reason: peernotifier.DisconnectReasonPingTimeout,
+		},
+		{
+			name:   "read timeout",
+			err:    wrapReadErr(timeoutErr),
+			reason: peernotifier.DisconnectReasonPingTimeout,
+		},
+		{
+			name: "eof",
+			err: wrapReadErr(
+				fmt.Errorf("read next header: %w", io.EOF),
+			),
+			reason: peernotifier.DisconnectReasonRemoteClosed,
+		},
+		{
+			name:   "connection reset",
+			err:    wrapReadErr(resetErr),
+			reason: peernotifier.DisconnectReasonRemoteClosed,
+		},
+		{
+			name:   "invalid message",
+			err:    wrapReadErr(errors.New("invalid mac")),
+			reason: peernotifier.DisconnectReasonProtocolError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			reason := disconnectReason(test.err)
+			require.Equal(t, test.reason, reason)
+		})
+	}
+}
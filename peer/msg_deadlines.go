@@ -0,0 +1,174 @@
+package peer
+
+import (
+	"errors"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// ErrWriteStalled is returned when the remote peer accepted our
+	// connection but stopped reading from it, such that no part of a
+	// pending message could be written for longer than the stall timeout
+	// of the message's class.
+	ErrWriteStalled = errors.New("peer stopped reading our messages")
+)
+
+// msgDeadlineClass groups wire messages that share the same write deadlines.
+type msgDeadlineClass uint8
+
+const (
+	// channelUpdateDeadlineClass holds the messages of the commitment
+	// dance and channel reestablishment. HTLCs hang while these messages
+	// can't be delivered, so a stall is detected quickly.
+	channelUpdateDeadlineClass msgDeadlineClass = iota
+
+	// controlDeadlineClass holds the connection level messages, such as
+	// pings and errors.
+	controlDeadlineClass
+
+	// defaultDeadlineClass holds all other messages, most notably gossip,
+	// for which a slow peer is tolerated for longer.
+	defaultDeadlineClass
+)
+
+// String returns a human-readable name of the class.
+func (c msgDeadlineClass) String() string {
+	switch c {
+	case channelUpdateDeadlineClass:
+		return "channel update"
+
+	case controlDeadlineClass:
+		return "control"
+
+	case defaultDeadlineClass:
+		return "default"
+
+	default:
+		return "unknown"
+	}
+}
+
+// msgDeadlines are the deadlines used when writing a message to the peer.
+type msgDeadlines struct {
+	// write is the deadline of a single attempt to flush the message to
+	// the connection. If it expires, the flush is retried.
+	write time.Duration
+
+	// stall is the duration after which a message that couldn't be
+	// flushed, without any of its bytes making it to the connection,
+	// means that the peer stopped reading our messages.
+	stall time.Duration
+}
+
+var (
+	// channelUpdateDeadlines are the deadlines of the messages in the
+	// channelUpdateDeadlineClass.
+	channelUpdateDeadlines = msgDeadlines{
+		write: 2 * time.Second,
+		stall: 30 * time.Second,
+	}
+
+	// controlDeadlines are the deadlines of the messages in the
+	// controlDeadlineClass. The stall timeout matches the ping interval,
+	// as the ping manager detects peers that don't answer our pings.
+	controlDeadlines = msgDeadlines{
+		write: writeMessageTimeout,
+		stall: pingInterval,
+	}
+
+	// defaultDeadlines are the deadlines of the messages in the
+	// defaultDeadlineClass.
+	defaultDeadlines = msgDeadlines{
+		write: writeMessageTimeout,
+		stall: 2 * time.Minute,
+	}
+)
+
+// deadlineClass returns the deadline class of the message.
+func deadlineClass(msg lnwire.Message) msgDeadlineClass {
+	switch msg.(type) {
+	case *lnwire.UpdateAddHTLC,
+		*lnwire.UpdateFulfillHTLC,
+		*lnwire.UpdateFailHTLC,
+		*lnwire.UpdateFailMalformedHTLC,
+		*lnwire.UpdateFee,
+		*lnwire.CommitSig,
+		*lnwire.RevokeAndAck,
+		*lnwire.ChannelReestablish:
+
+		return channelUpdateDeadlineClass
+
+	case *lnwire.Init,
+		*lnwire.Ping,
+		*lnwire.Pong,
+		*lnwire.Error,
+		*lnwire.Warning:
+
+		return controlDeadlineClass
+
+	default:
+		return defaultDeadlineClass
+	}
+}
+
+// deadlines returns the write deadlines of the given class.
+func (c msgDeadlineClass) deadlines() msgDeadlines {
+	switch c {
+	case channelUpdateDeadlineClass:
+		return channelUpdateDeadlines
+
+	case controlDeadlineClass:
+		return controlDeadlines
+
+	default:
+		return defaultDeadlines
+	}
+}
+
+// writeStallDetector detects a peer that stopped reading our messages while a
+// message is being written to it. A write that times out, but still flushes
+// some bytes, means that the peer is slow rather than stalled, so only the
+// time since the last progress is counted.
+type writeStallDetector struct {
+	// stallTimeout is the time without progress after which the peer is
+	// considered stalled.
+	stallTimeout time.Duration
+
+	// lastProgress is the last time at which bytes were written.
+	lastProgress time.Time
+
+	// lastBytesSent is the total number of bytes that were sent to the
+	// peer at lastProgress.
+	lastBytesSent uint64
+}
+
+// newWriteStallDetector creates a writeStallDetector for a message that is
+// first written at the given time, when the given total number of bytes had
+// been sent to the peer.
+func newWriteStallDetector(stallTimeout time.Duration, bytesSent uint64,
+	now time.Time) *writeStallDetector {
+
+	return &writeStallDetector{
+		stallTimeout:  stallTimeout,
+		lastProgress:  now,
+		lastBytesSent: bytesSent,
+	}
+}
+
+// stalled is called after a write attempt timed out with the total number of
+// bytes that were sent to the peer so far. It returns the time since the last
+// progress was made, and whether the peer is considered stalled.
+func (d *writeStallDetector) stalled(bytesSent uint64,
+	now time.Time) (time.Duration, bool) {
+
+	if bytesSent != d.lastBytesSent {
+		d.lastBytesSent = bytesSent
+		d.lastProgress = now
+	}
+
+	sinceProgress := now.Sub(d.lastProgress)
+
+	return sinceProgress, sinceProgress >= d.stallTimeout
+}
@@ -0,0 +1,76 @@
+package peer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDeadlineClass tests that messages are assigned to the expected deadline
+// classes.
+func TestDeadlineClass(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		msg   lnwire.Message
+		class msgDeadlineClass
+	}{
+		{&lnwire.UpdateAddHTLC{}, channelUpdateDeadlineClass},
+		{&lnwire.CommitSig{}, channelUpdateDeadlineClass},
+		{&lnwire.RevokeAndAck{}, channelUpdateDeadlineClass},
+		{&lnwire.ChannelReestablish{}, channelUpdateDeadlineClass},
+		{&lnwire.Ping{}, controlDeadlineClass},
+		{&lnwire.Error{}, controlDeadlineClass},
+		{&lnwire.ChannelUpdate1{}, defaultDeadlineClass},
+		{&lnwire.NodeAnnouncement{}, defaultDeadlineClass},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(
+			t, tc.class, deadlineClass(tc.msg), tc.msg.MsgType(),
+		)
+	}
+
+	// The messages of the commitment dance must be detected as stalled
+	// before any other message.
+	require.Less(
+		t, channelUpdateDeadlines.stall, controlDeadlines.stall,
+	)
+	require.Less(
+		t, channelUpdateDeadlines.stall, defaultDeadlines.stall,
+	)
+}
+
+// TestWriteStallDetector tests that a peer is only considered stalled if no
+// bytes were written for the stall timeout.
+func TestWriteStallDetector(t *testing.T) {
+	t.Parallel()
+
+	const stallTimeout = 30 * time.Second
+
+	start := time.Unix(1_000, 0)
+	detector := newWriteStallDetector(stallTimeout, 100, start)
+
+	// Without progress, the peer isn't stalled before the stall timeout.
+	sinceProgress, stalled := detector.stalled(
+		100, start.Add(10*time.Second),
+	)
+	require.False(t, stalled)
+	require.Equal(t, 10*time.Second, sinceProgress)
+
+	// Progress resets the time without progress.
+	now := start.Add(25 * time.Second)
+	sinceProgress, stalled = detector.stalled(150, now)
+	require.False(t, stalled)
+	require.Zero(t, sinceProgress)
+
+	// The stall timeout is counted from the last progress.
+	_, stalled = detector.stalled(150, start.Add(40*time.Second))
+	require.False(t, stalled)
+
+	sinceProgress, stalled = detector.stalled(150, now.Add(stallTimeout))
+	require.True(t, stalled)
+	require.Equal(t, stallTimeout, sinceProgress)
+}
@@ -21,6 +21,10 @@ type MusigChanCloser struct {
 
 	localNonce  *musig2.Nonces
 	remoteNonce *musig2.Nonces
+
+	// remoteNonceRegistered is true once the remote nonce has been bound
+	// to the musig session.
+	remoteNonceRegistered bool
 }
 
 // NewMusigChanCloser creates a new musig chan closer from a normal channel.
@@ -43,21 +47,15 @@ func (m *MusigChanCloser) ProposalClosingOpts() (
 		return nil, fmt.Errorf("remote nonce not generated")
 	}
 
-	localKey, remoteKey := m.channel.MultiSigKeys()
-
-	tapscriptTweak := fn.MapOption(lnwallet.TapscriptRootToTweak)(
-		m.channel.State().TapscriptRoot,
-	)
-
-	m.musigSession = lnwallet.NewPartialMusigSession(
-		*m.remoteNonce, localKey, remoteKey,
-		m.channel.Signer, m.channel.FundingTxOut(),
-		lnwallet.RemoteMusigCommit, tapscriptTweak,
-	)
+	// The session was created along with our local nonce, so we only
+	// need to bind the remote nonce to it, which we only do once.
+	if !m.remoteNonceRegistered {
+		err := m.musigSession.RegisterRemoteNonce(*m.remoteNonce)
+		if err != nil {
+			return nil, err
+		}
 
-	err := m.musigSession.FinalizeSession(*m.localNonce)
-	if err != nil {
-		return nil, err
+		m.remoteNonceRegistered = true
 	}
 
 	return []lnwallet.ChanCloseOpt{
@@ -111,14 +109,29 @@ func (m *MusigChanCloser) ClosingNonce() (*musig2.Nonces, error) {
 		return m.localNonce, nil
 	}
 
-	localKey, _ := m.channel.MultiSigKeys()
-	nonce, err := musig2.GenNonces(
-		musig2.WithPublicKey(localKey.PubKey),
+	localKey, remoteKey := m.channel.MultiSigKeys()
+
+	tapscriptTweak := fn.MapOption(lnwallet.TapscriptRootToTweak)(
+		m.channel.State().TapscriptRoot,
+	)
+
+	// We create the musig session right away, so the signer generates our
+	// nonce and the secret nonce never leaves it. This allows the closing
+	// signature to be created by a remote signer.
+	musigSession, err := lnwallet.NewClosingMusigSession(
+		localKey, remoteKey, m.channel.Signer,
+		m.channel.FundingTxOut(), tapscriptTweak,
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	nonce, err := musigSession.LocalNonce()
+	if err != nil {
+		return nil, err
+	}
+
+	m.musigSession = musigSession
 	m.localNonce = nonce
 
 	return nonce, nil
@@ -39,6 +39,22 @@ type PingManagerConfig struct {
 	OnPongFailure func(error)
 }
 
+// PingConfig holds the parameters of the pings we send to a peer in order to
+// detect dead connections.
+type PingConfig struct {
+	// Interval is the duration between pings.
+	Interval time.Duration
+
+	// Timeout is the duration we wait for a pong before we consider the
+	// peer unresponsive. It must be smaller than Interval.
+	Timeout time.Duration
+
+	// MaxPongBytes is the upper bound of the random number of pong bytes
+	// we request with each ping. Larger values probe the connection with
+	// more traffic, at the cost of bandwidth.
+	MaxPongBytes uint16
+}
+
 // PingManager is a structure that is designed to manage the internal state
 // of the ping pong lifecycle with the remote peer. We assume there is only one
 // ping outstanding at once.
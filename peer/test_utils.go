@@ -725,6 +725,11 @@ func createTestPeer(t *testing.T) *peerTestCtx {
 		FetchLastChanUpdate: func(chanID lnwire.ShortChannelID,
 		) (*lnwire.ChannelUpdate1, error) {
 
+			return &lnwire.ChannelUpdate1{}, nil
+		},
+		FetchAliasChanUpdate: func(*channeldb.OpenChannel) (
+			*lnwire.ChannelUpdate1, error) {
+
 			return &lnwire.ChannelUpdate1{}, nil
 		},
 	}
@@ -0,0 +1,178 @@
+package lnd
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnpeer"
+)
+
+const (
+	// startupActivityWindow is the period of forwarding activity that is
+	// taken into account when prioritizing peers on startup.
+	startupActivityWindow = 7 * 24 * time.Hour
+
+	// maxStartupActivityEvents is the maximum number of forwarding events
+	// that are read to prioritize peers on startup, which bounds the
+	// startup cost for nodes with a high volume of forwards.
+	maxStartupActivityEvents = 100_000
+
+	// numParallelInitReconnect is the maximum number of peers we attempt
+	// to reconnect to concurrently on startup.
+	numParallelInitReconnect = 10
+
+	// initReconnectTimeout is the time we wait for a peer to come online
+	// on startup before moving on to the next peer.
+	initReconnectTimeout = 10 * time.Second
+)
+
+// peerPriority describes how important a peer is to us economically, which
+// determines the order in which we reconnect to peers on startup.
+type peerPriority struct {
+	// capacity is the aggregate capacity of our channels with the peer.
+	capacity btcutil.Amount
+
+	// forwarded is the volume recently forwarded through our channels
+	// with the peer, in either direction.
+	forwarded btcutil.Amount
+}
+
+// score returns the priority score of the peer. Capacity and forwarding
+// volume are weighted equally, such that both large idle channels and
+// smaller, but busy channels are considered important.
+func (p *peerPriority) score() btcutil.Amount {
+	return p.capacity + p.forwarded
+}
+
+// sortPeersByPriority sorts the given peers by descending priority. Peers
+// without a known priority come last, and ties are broken by public key to
+// make the order deterministic.
+func sortPeersByPriority(peers []string,
+	priorities map[string]*peerPriority) {
+
+	score := func(pubStr string) btcutil.Amount {
+		priority, ok := priorities[pubStr]
+		if !ok {
+			return 0
+		}
+
+		return priority.score()
+	}
+
+	sort.SliceStable(peers, func(i, j int) bool {
+		scoreI, scoreJ := score(peers[i]), score(peers[j])
+		if scoreI != scoreJ {
+			return scoreI > scoreJ
+		}
+
+		return strings.Compare(peers[i], peers[j]) < 0
+	})
+}
+
+// addForwardingActivity adds the recent forwarding volume of our channels to
+// the priorities of the peers the channels are with. Channels are identified
+// by their short channel ID.
+func (s *server) addForwardingActivity(chanPeers map[uint64]string,
+	priorities map[string]*peerPriority) error {
+
+	addVolume := func(chanID uint64, amt btcutil.Amount) {
+		pubStr, ok := chanPeers[chanID]
+		if !ok {
+			return
+		}
+
+		priorities[pubStr].forwarded += amt
+	}
+
+	now := time.Now()
+	query := channeldb.ForwardingEventQuery{
+		StartTime:    now.Add(-startupActivityWindow),
+		EndTime:      now,
+		NumMaxEvents: maxStartupActivityEvents,
+	}
+
+	var numEvents int
+	for numEvents < maxStartupActivityEvents {
+		timeSlice, err := s.miscDB.ForwardingLog().Query(query)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range timeSlice.ForwardingEvents {
+			addVolume(
+				event.IncomingChanID.ToUint64(),
+				event.AmtIn.ToSatoshis(),
+			)
+			addVolume(
+				event.OutgoingChanID.ToUint64(),
+				event.AmtOut.ToSatoshis(),
+			)
+		}
+
+		numEvents += len(timeSlice.ForwardingEvents)
+		if len(timeSlice.ForwardingEvents) < int(query.NumMaxEvents) {
+			break
+		}
+
+		query.IndexOffset = timeSlice.LastIndexOffset
+		query.NumMaxEvents = uint32(
+			maxStartupActivityEvents - numEvents,
+		)
+	}
+
+	return nil
+}
+
+// connectPeersByPriority reconnects to the given persistent peers in the
+// given order, with at most numParallelInitReconnect reconnection attempts
+// in flight at a time. An attempt is considered done once the peer comes
+// online or initReconnectTimeout passed, such that unreachable peers don't
+// hold up the remaining ones for long.
+//
+// NOTE: This method MUST be run as a goroutine.
+func (s *server) connectPeersByPriority(peers []string) {
+	defer s.wg.Done()
+
+	var (
+		wg        sync.WaitGroup
+		semaphore = make(chan struct{}, numParallelInitReconnect)
+	)
+	defer wg.Wait()
+
+	for _, pubStr := range peers {
+		select {
+		case semaphore <- struct{}{}:
+		case <-s.quit:
+			return
+		}
+
+		wg.Add(1)
+		go func(pubStr string) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			s.connectToPersistentPeer(pubStr)
+
+			// The listener channel is buffered, so notifying it
+			// doesn't block even once we stopped waiting.
+			var pubKey [33]byte
+			copy(pubKey[:], pubStr)
+			peerChan := make(chan lnpeer.Peer, 1)
+			s.NotifyWhenOnline(pubKey, peerChan)
+
+			select {
+			case <-peerChan:
+			case <-time.After(initReconnectTimeout):
+				srvrLog.Debugf("Peer %x not online after %v, "+
+					"continuing startup reconnection",
+					pubKey, initReconnectTimeout)
+
+			case <-s.quit:
+			}
+		}(pubStr)
+	}
+}
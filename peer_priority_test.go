@@ -0,0 +1,34 @@
+package lnd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestSortPeersByPriority tests that peers are ordered by their aggregate
+// channel capacity and recent forwarding volume.
+func TestSortPeersByPriority(t *testing.T) {
+	t.Parallel()
+
+	priorities := map[string]*peerPriority{
+		// A large, idle channel.
+		"a": {capacity: 5_000_000},
+
+		// A smaller channel with a lot of forwarding activity.
+		"b": {capacity: 1_000_000, forwarded: 6_000_000},
+
+		// A small, idle channel.
+		"c": {capacity: 500_000},
+
+		// A peer with the same score as the large, idle channel.
+		"d": {capacity: 2_000_000, forwarded: 3_000_000},
+	}
+
+	// Peers without a known priority, such as peers we only know from
+	// prior links, come last.
+	peers := []string{"e", "c", "d", "a", "b", "f"}
+	sortPeersByPriority(peers, priorities)
+
+	require.Equal(t, []string{"b", "a", "d", "c", "e", "f"}, peers)
+}
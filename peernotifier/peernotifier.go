@@ -1,8 +1,10 @@
 package peernotifier
 
 import (
+	"net"
 	"sync"
 
+	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/subscribe"
 )
 
@@ -16,16 +18,87 @@ type PeerNotifier struct {
 	ntfnServer *subscribe.Server
 }
 
+// DisconnectReason describes why the connection to a peer was closed.
+type DisconnectReason uint8
+
+const (
+	// DisconnectReasonUnknown is used when the reason for the disconnect
+	// isn't known.
+	DisconnectReasonUnknown DisconnectReason = iota
+
+	// DisconnectReasonLocal is used when we decided to close the
+	// connection, for example because it was requested over RPC or the
+	// connection was replaced by a newer one.
+	DisconnectReasonLocal
+
+	// DisconnectReasonPingTimeout is used when the peer didn't answer our
+	// pings in time.
+	DisconnectReasonPingTimeout
+
+	// DisconnectReasonProtocolError is used when the peer sent us data we
+	// couldn't process.
+	DisconnectReasonProtocolError
+
+	// DisconnectReasonRemoteClosed is used when the peer closed the
+	// connection.
+	DisconnectReasonRemoteClosed
+)
+
+// String returns a human-readable description of the disconnect reason.
+func (r DisconnectReason) String() string {
+	switch r {
+	case DisconnectReasonLocal:
+		return "local"
+
+	case DisconnectReasonPingTimeout:
+		return "ping_timeout"
+
+	case DisconnectReasonProtocolError:
+		return "protocol_error"
+
+	case DisconnectReasonRemoteClosed:
+		return "remote_closed"
+
+	default:
+		return "unknown"
+	}
+}
+
+// ConnectionInfo holds the metadata of the connection to a peer.
+type ConnectionInfo struct {
+	// Address is the network address of the peer.
+	Address net.Addr
+
+	// Inbound is true if the peer initiated the connection.
+	Inbound bool
+}
+
 // PeerOnlineEvent represents a new event where a peer comes online.
 type PeerOnlineEvent struct {
 	// PubKey is the peer's compressed public key.
 	PubKey [33]byte
+
+	// Conn holds the metadata of the connection to the peer.
+	Conn ConnectionInfo
+
+	// Features is the set of features the peer sent us in its init
+	// message.
+	Features *lnwire.FeatureVector
 }
 
 // PeerOfflineEvent represents a new event where a peer goes offline.
 type PeerOfflineEvent struct {
 	// PubKey is the peer's compressed public key.
 	PubKey [33]byte
+
+	// Conn holds the metadata of the connection to the peer.
+	Conn ConnectionInfo
+
+	// Reason describes why the connection was closed.
+	Reason DisconnectReason
+
+	// Err is the error that caused the disconnect, if any.
+	Err error
 }
 
 // New creates a new peer notifier which notifies clients of peer online
@@ -68,8 +141,14 @@ func (p *PeerNotifier) SubscribePeerEvents() (*subscribe.Client, error) {
 
 // NotifyPeerOnline sends a peer online event to all clients subscribed to the
 // peer notifier.
-func (p *PeerNotifier) NotifyPeerOnline(pubKey [33]byte) {
-	event := PeerOnlineEvent{PubKey: pubKey}
+func (p *PeerNotifier) NotifyPeerOnline(pubKey [33]byte, conn ConnectionInfo,
+	features *lnwire.FeatureVector) {
+
+	event := PeerOnlineEvent{
+		PubKey:   pubKey,
+		Conn:     conn,
+		Features: features,
+	}
 
 	log.Debugf("PeerNotifier notifying peer: %x online", pubKey)
 
@@ -80,10 +159,18 @@ func (p *PeerNotifier) NotifyPeerOnline(pubKey [33]byte) {
 
 // NotifyPeerOffline sends a peer offline event to all the clients subscribed
 // to the peer notifier.
-func (p *PeerNotifier) NotifyPeerOffline(pubKey [33]byte) {
-	event := PeerOfflineEvent{PubKey: pubKey}
+func (p *PeerNotifier) NotifyPeerOffline(pubKey [33]byte, conn ConnectionInfo,
+	reason DisconnectReason, err error) {
+
+	event := PeerOfflineEvent{
+		PubKey: pubKey,
+		Conn:   conn,
+		Reason: reason,
+		Err:    err,
+	}
 
-	log.Debugf("PeerNotifier notifying peer: %x offline", pubKey)
+	log.Debugf("PeerNotifier notifying peer: %x offline (reason=%v)",
+		pubKey, reason)
 
 	if err := p.ntfnServer.SendUpdate(event); err != nil {
 		log.Warnf("Unable to send peer offline update: %v", err)
@@ -0,0 +1,194 @@
+package pool
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+)
+
+const (
+	// DefaultAutoTuneTargetLatency is the default queue latency above
+	// which an auto tuned pool grows.
+	DefaultAutoTuneTargetLatency = 5 * time.Millisecond
+
+	// DefaultAutoTuneInterval is the default interval at which an auto
+	// tuned pool reevaluates its size.
+	DefaultAutoTuneInterval = 10 * time.Second
+
+	// autoTuneShrinkDivisor is the divisor of the target latency below
+	// which an auto tuned pool shrinks. Keeping a gap between the growing
+	// and shrinking thresholds prevents the pool from oscillating.
+	autoTuneShrinkDivisor = 4
+)
+
+// AutoTuneConfig parametrizes the auto tuning of a Worker pool's size.
+type AutoTuneConfig struct {
+	// MinWorkers is the lower bound of the pool's maximum number of
+	// workers.
+	MinWorkers int
+
+	// MaxWorkers is the upper bound of the pool's maximum number of
+	// workers.
+	MaxWorkers int
+
+	// TargetLatency is the average duration a task may wait before it is
+	// picked up by a worker. If the average latency over an interval
+	// exceeds it, the pool grows by one worker. If it stays well below,
+	// the pool shrinks by one worker.
+	TargetLatency time.Duration
+
+	// Interval is the interval at which the pool's size is reevaluated.
+	Interval time.Duration
+}
+
+// Validate checks that the auto tuning parameters are sane.
+func (c *AutoTuneConfig) Validate() error {
+	switch {
+	case c.MinWorkers <= 0:
+		return fmt.Errorf("min workers (%d) must be positive",
+			c.MinWorkers)
+
+	case c.MaxWorkers < c.MinWorkers:
+		return fmt.Errorf("max workers (%d) must not be below min "+
+			"workers (%d)", c.MaxWorkers, c.MinWorkers)
+
+	case c.TargetLatency <= 0:
+		return fmt.Errorf("target latency must be positive")
+
+	case c.Interval <= 0:
+		return fmt.Errorf("auto tune interval must be positive")
+	}
+
+	return nil
+}
+
+// DefaultAutoTuneConfig returns an AutoTuneConfig that lets a pool with the
+// given number of workers grow up to four times its size or four workers per
+// available CPU, whichever is larger.
+func DefaultAutoTuneConfig(numWorkers int) *AutoTuneConfig {
+	maxWorkers := 4 * numWorkers
+	if procs := 4 * runtime.GOMAXPROCS(0); procs > maxWorkers {
+		maxWorkers = procs
+	}
+
+	return &AutoTuneConfig{
+		MinWorkers:    numWorkers,
+		MaxWorkers:    maxWorkers,
+		TargetLatency: DefaultAutoTuneTargetLatency,
+		Interval:      DefaultAutoTuneInterval,
+	}
+}
+
+// WithAutoTune enables the auto tuning of a pool's size using the given
+// parameters.
+func WithAutoTune(cfg *AutoTuneConfig) WorkerOption {
+	return func(c *WorkerConfig) {
+		c.AutoTune = cfg
+	}
+}
+
+// WorkerStats is a snapshot of the utilization of a Worker pool.
+type WorkerStats struct {
+	// NumWorkers is the number of running worker goroutines.
+	NumWorkers int
+
+	// MaxWorkers is the current maximum number of worker goroutines.
+	MaxWorkers int
+
+	// TasksProcessed is the total number of tasks the pool has processed.
+	TasksProcessed uint64
+
+	// AvgQueueLatency is the average duration submitted tasks waited
+	// before being picked up by a worker.
+	AvgQueueLatency time.Duration
+}
+
+// workerStats accumulates the task statistics of a Worker pool.
+type workerStats struct {
+	tasks        uint64
+	totalLatency time.Duration
+}
+
+// recordQueueLatency records that a task was picked up by a worker after
+// waiting for the given duration.
+func (w *Worker) recordQueueLatency(latency time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.stats.tasks++
+	w.stats.totalLatency += latency
+}
+
+// Stats returns a snapshot of the pool's utilization.
+func (w *Worker) Stats() WorkerStats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	stats := WorkerStats{
+		NumWorkers:     w.numWorkers,
+		MaxWorkers:     w.maxWorkers,
+		TasksProcessed: w.stats.tasks,
+	}
+	if w.stats.tasks > 0 {
+		stats.AvgQueueLatency = w.stats.totalLatency /
+			time.Duration(w.stats.tasks)
+	}
+
+	return stats
+}
+
+// autoTuner periodically adjusts the maximum number of workers of the pool
+// based on the average queue latency observed during the last interval.
+//
+// NOTE: This method MUST be run as a goroutine.
+func (w *Worker) autoTuner(cfg *AutoTuneConfig) {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	var last workerStats
+	for {
+		select {
+		case <-ticker.C:
+			grown := w.tune(cfg, &last)
+			if grown {
+				w.signalSlotFreed()
+			}
+
+		case <-w.quit:
+			return
+		}
+	}
+}
+
+// tune adjusts the maximum number of workers of the pool based on the task
+// statistics accumulated since last, which is updated to the current
+// statistics. True is returned if the pool was grown.
+func (w *Worker) tune(cfg *AutoTuneConfig, last *workerStats) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	tasks := w.stats.tasks - last.tasks
+	latency := w.stats.totalLatency - last.totalLatency
+	*last = w.stats
+
+	// Without any tasks, there's nothing to base a decision on.
+	if tasks == 0 {
+		return false
+	}
+	avgLatency := latency / time.Duration(tasks)
+
+	switch {
+	case avgLatency > cfg.TargetLatency && w.maxWorkers < cfg.MaxWorkers:
+		w.maxWorkers++
+		return true
+
+	case avgLatency < cfg.TargetLatency/autoTuneShrinkDivisor &&
+		w.maxWorkers > cfg.MinWorkers:
+
+		w.maxWorkers--
+	}
+
+	return false
+}
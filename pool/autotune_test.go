@@ -0,0 +1,117 @@
+package pool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// nopWorkerState is a WorkerState without any state.
+type nopWorkerState struct{}
+
+func (nopWorkerState) Reset()   {}
+func (nopWorkerState) Cleanup() {}
+
+// TestWorkerAutoTune tests that the maximum number of workers of a pool grows
+// when tasks wait too long and shrinks back when the load subsides, within
+// the configured bounds.
+func TestWorkerAutoTune(t *testing.T) {
+	t.Parallel()
+
+	cfg := &AutoTuneConfig{
+		MinWorkers:    2,
+		MaxWorkers:    3,
+		TargetLatency: 4 * time.Millisecond,
+		Interval:      time.Second,
+	}
+	require.NoError(t, cfg.Validate())
+
+	w := NewWorker(&WorkerConfig{NumWorkers: 2})
+
+	var last workerStats
+	tune := func(latency time.Duration) bool {
+		w.recordQueueLatency(latency)
+		return w.tune(cfg, &last)
+	}
+
+	// High latencies grow the pool up to its upper bound.
+	require.True(t, tune(10*time.Millisecond))
+	require.Equal(t, 3, w.Stats().MaxWorkers)
+	require.False(t, tune(10*time.Millisecond))
+	require.Equal(t, 3, w.Stats().MaxWorkers)
+
+	// Without any tasks, the size is left untouched.
+	require.False(t, w.tune(cfg, &last))
+	require.Equal(t, 3, w.Stats().MaxWorkers)
+
+	// Latencies between the thresholds keep the pool's size.
+	require.False(t, tune(2*time.Millisecond))
+	require.Equal(t, 3, w.Stats().MaxWorkers)
+
+	// Low latencies shrink the pool down to its lower bound.
+	require.False(t, tune(0))
+	require.Equal(t, 2, w.Stats().MaxWorkers)
+	require.False(t, tune(0))
+	require.Equal(t, 2, w.Stats().MaxWorkers)
+
+	stats := w.Stats()
+	require.EqualValues(t, 5, stats.TasksProcessed)
+	require.Equal(t, 22*time.Millisecond/5, stats.AvgQueueLatency)
+}
+
+// TestWorkerResize tests that a pool can be resized at runtime, and that
+// excess workers retire after the pool is shrunk.
+func TestWorkerResize(t *testing.T) {
+	t.Parallel()
+
+	w := NewWorker(&WorkerConfig{
+		NewWorkerState: func() WorkerState { return nopWorkerState{} },
+		NumWorkers:     1,
+		WorkerTimeout:  time.Minute,
+	})
+	require.NoError(t, w.Start())
+	t.Cleanup(func() {
+		require.NoError(t, w.Stop())
+	})
+
+	require.Error(t, w.Resize(0))
+	require.NoError(t, w.Resize(3))
+
+	// Block three tasks at once, which requires three workers.
+	const numTasks = 3
+	started := make(chan struct{}, numTasks)
+	release := make(chan struct{})
+	errChan := make(chan error, numTasks)
+	for i := 0; i < numTasks; i++ {
+		go func() {
+			errChan <- w.Submit(func(WorkerState) error {
+				started <- struct{}{}
+				<-release
+
+				return nil
+			})
+		}()
+	}
+	for i := 0; i < numTasks; i++ {
+		select {
+		case <-started:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("task %d not started", i)
+		}
+	}
+	require.Equal(t, 3, w.Stats().NumWorkers)
+
+	// Once the pool is shrunk, the excess workers exit after finishing
+	// their tasks.
+	require.NoError(t, w.Resize(1))
+	close(release)
+	for i := 0; i < numTasks; i++ {
+		require.NoError(t, <-errChan)
+	}
+
+	require.Eventually(t, func() bool {
+		return w.Stats().NumWorkers == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, 1, w.Stats().MaxWorkers)
+}
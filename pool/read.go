@@ -17,20 +17,34 @@ type Read struct {
 // NewRead creates a new Read pool, using an underlying ReadBuffer pool to
 // recycle buffer.Read objects across the lifetime of the Read pool's workers.
 func NewRead(readBufferPool *ReadBuffer, numWorkers int,
-	workerTimeout time.Duration) *Read {
+	workerTimeout time.Duration, opts ...WorkerOption) *Read {
 
 	r := &Read{
 		bufferPool: readBufferPool,
 	}
-	r.workerPool = NewWorker(&WorkerConfig{
+	cfg := &WorkerConfig{
 		NewWorkerState: r.newWorkerState,
 		NumWorkers:     numWorkers,
 		WorkerTimeout:  workerTimeout,
-	})
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	r.workerPool = NewWorker(cfg)
 
 	return r
 }
 
+// Stats returns a snapshot of the Read pool's utilization.
+func (r *Read) Stats() WorkerStats {
+	return r.workerPool.Stats()
+}
+
+// Resize sets the maximum number of workers of the Read pool.
+func (r *Read) Resize(numWorkers int) error {
+	return r.workerPool.Resize(numWorkers)
+}
+
 // Start safely spins up the Read pool.
 func (r *Read) Start() error {
 	return r.workerPool.Start()
@@ -2,6 +2,7 @@ package pool
 
 import (
 	"errors"
+	"fmt"
 	"sync"
 	"time"
 )
@@ -44,8 +45,17 @@ type (
 		// WorkerTimeout is the duration after which a worker goroutine
 		// will exit after having received no newly submitted tasks.
 		WorkerTimeout time.Duration
+
+		// AutoTune, if set, lets the pool adjust its maximum number of
+		// workers based on the observed queue latency, starting from
+		// NumWorkers.
+		AutoTune *AutoTuneConfig
 	}
 
+	// WorkerOption is a functional option that modifies the WorkerConfig
+	// of a pool.
+	WorkerOption func(*WorkerConfig)
+
 	// Worker maintains a pool of goroutines that process submitted function
 	// closures, and enable more efficient reuse of expensive state.
 	Worker struct {
@@ -63,10 +73,24 @@ type (
 		// read by active worker goroutines.
 		work chan *request
 
-		// workerSem is a channel-based semaphore that is used to limit
-		// the total number of worker goroutines to the number
-		// prescribed by the WorkerConfig.
-		workerSem chan struct{}
+		// slotFreed is signaled whenever a worker goroutine exits or
+		// the maximum number of workers is raised, so that a request
+		// waiting for an active worker can try to spawn a new one.
+		slotFreed chan struct{}
+
+		// mu guards the fields below.
+		mu sync.Mutex
+
+		// numWorkers is the number of running worker goroutines.
+		numWorkers int
+
+		// maxWorkers is the maximum number of worker goroutines. It
+		// starts out as the configured NumWorkers, and can be changed
+		// at runtime.
+		maxWorkers int
+
+		// stats holds the task statistics of the pool.
+		stats workerStats
 
 		wg   sync.WaitGroup
 		quit chan struct{}
@@ -76,19 +100,21 @@ type (
 	// both submit a task to the pool and respond with any errors
 	// encountered during the task's execution.
 	request struct {
-		fn      func(WorkerState) error
-		errChan chan error
+		fn        func(WorkerState) error
+		errChan   chan error
+		submitted time.Time
 	}
 )
 
 // NewWorker initializes a new Worker pool using the provided WorkerConfig.
 func NewWorker(cfg *WorkerConfig) *Worker {
 	return &Worker{
-		cfg:       cfg,
-		requests:  make(chan *request),
-		workerSem: make(chan struct{}, cfg.NumWorkers),
-		work:      make(chan *request),
-		quit:      make(chan struct{}),
+		cfg:        cfg,
+		requests:   make(chan *request),
+		work:       make(chan *request),
+		slotFreed:  make(chan struct{}, 1),
+		maxWorkers: cfg.NumWorkers,
+		quit:       make(chan struct{}),
 	}
 }
 
@@ -97,6 +123,11 @@ func (w *Worker) Start() error {
 	w.started.Do(func() {
 		w.wg.Add(1)
 		go w.requestHandler()
+
+		if w.cfg.AutoTune != nil {
+			w.wg.Add(1)
+			go w.autoTuner(w.cfg.AutoTune)
+		}
 	})
 	return nil
 }
@@ -115,8 +146,9 @@ func (w *Worker) Stop() error {
 // a shutdown is requested.
 func (w *Worker) Submit(fn func(WorkerState) error) error {
 	req := &request{
-		fn:      fn,
-		errChan: make(chan error, 1),
+		fn:        fn,
+		errChan:   make(chan error, 1),
+		submitted: time.Now(),
 	}
 
 	select {
@@ -152,19 +184,7 @@ func (w *Worker) requestHandler() {
 	for {
 		select {
 		case req := <-w.requests:
-			select {
-
-			// If we have not reached our maximum number of workers,
-			// spawn one to process the submitted request.
-			case w.workerSem <- struct{}{}:
-				w.wg.Add(1)
-				go w.spawnWorker(req)
-
-			// Otherwise, submit the task to any of the active
-			// workers.
-			case w.work <- req:
-
-			case <-w.quit:
+			if !w.dispatch(req) {
 				return
 			}
 
@@ -174,6 +194,108 @@ func (w *Worker) requestHandler() {
 	}
 }
 
+// dispatch hands the request to a new worker goroutine if the maximum number
+// of workers hasn't been reached, or to any of the active workers otherwise.
+// False is returned if the pool is shutting down.
+func (w *Worker) dispatch(req *request) bool {
+	for {
+		// If we have not reached our maximum number of workers, spawn
+		// one to process the submitted request.
+		if w.acquireWorker() {
+			w.wg.Add(1)
+			go w.spawnWorker(req)
+
+			return true
+		}
+
+		select {
+		// Otherwise, submit the task to any of the active workers.
+		case w.work <- req:
+			return true
+
+		// If a worker exited or the pool grew in the meantime, we'll
+		// try to spawn a new worker again.
+		case <-w.slotFreed:
+
+		case <-w.quit:
+			return false
+		}
+	}
+}
+
+// acquireWorker reserves a slot for a new worker goroutine, returning false if
+// the maximum number of workers is already running.
+func (w *Worker) acquireWorker() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.numWorkers >= w.maxWorkers {
+		return false
+	}
+	w.numWorkers++
+
+	return true
+}
+
+// releaseWorker releases the slot of an exiting worker goroutine.
+func (w *Worker) releaseWorker() {
+	w.mu.Lock()
+	w.numWorkers--
+	w.mu.Unlock()
+
+	w.signalSlotFreed()
+}
+
+// retireExcessWorker releases the slot of the calling worker goroutine if more
+// workers are running than the current maximum allows, e.g. after the pool
+// was shrunk. True is returned if the caller should exit.
+func (w *Worker) retireExcessWorker() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.numWorkers <= w.maxWorkers {
+		return false
+	}
+	w.numWorkers--
+
+	return true
+}
+
+// signalSlotFreed notifies a request waiting for an active worker that it may
+// be able to spawn a new worker.
+func (w *Worker) signalSlotFreed() {
+	select {
+	case w.slotFreed <- struct{}{}:
+	default:
+	}
+}
+
+// process executes the task of the request with the given worker state.
+func (w *Worker) process(req *request, state WorkerState) {
+	w.recordQueueLatency(time.Since(req.submitted))
+
+	req.errChan <- req.fn(state)
+}
+
+// Resize sets the maximum number of worker goroutines of the pool. If the pool
+// is shrunk, excess workers exit once they've finished their current task or
+// their timeout elapses. If auto tuning is enabled, it continues from the new
+// size.
+func (w *Worker) Resize(numWorkers int) error {
+	if numWorkers <= 0 {
+		return fmt.Errorf("number of workers (%d) must be positive",
+			numWorkers)
+	}
+
+	w.mu.Lock()
+	w.maxWorkers = numWorkers
+	w.mu.Unlock()
+
+	w.signalSlotFreed()
+
+	return nil
+}
+
 // spawnWorker is used when the Worker pool wishes to create a new worker
 // goroutine. The worker's state is initialized by calling the config's
 // NewWorkerState method, and will continue to process incoming tasks until the
@@ -183,12 +305,20 @@ func (w *Worker) requestHandler() {
 // NOTE: This method MUST be run as a goroutine.
 func (w *Worker) spawnWorker(req *request) {
 	defer w.wg.Done()
-	defer func() { <-w.workerSem }()
+
+	// Unless the worker retires because the pool was shrunk, in which
+	// case its slot was already released, we release its slot on exit.
+	retired := false
+	defer func() {
+		if !retired {
+			w.releaseWorker()
+		}
+	}()
 
 	state := w.cfg.NewWorkerState()
 	defer state.Cleanup()
 
-	req.errChan <- req.fn(state)
+	w.process(req, state)
 
 	// We'll use a timer to implement the worker timeouts, as this reduces
 	// the number of total allocations that would otherwise be necessary
@@ -200,13 +330,20 @@ func (w *Worker) spawnWorker(req *request) {
 		// state.
 		state.Reset()
 
+		// If the pool was shrunk, excess workers exit before taking
+		// another task.
+		if w.retireExcessWorker() {
+			retired = true
+			return
+		}
+
 		select {
 
 		// Process any new requests that get submitted. We use a
 		// non-blocking case first so that under high load we can spare
 		// allocating a timeout.
 		case req := <-w.work:
-			req.errChan <- req.fn(state)
+			w.process(req, state)
 			continue
 
 		case <-w.quit:
@@ -229,7 +366,7 @@ func (w *Worker) spawnWorker(req *request) {
 
 		// Process any new requests that get submitted.
 		case req := <-w.work:
-			req.errChan <- req.fn(state)
+			w.process(req, state)
 
 			// Stop the timer, draining the timer's channel if a
 			// notification was already delivered.
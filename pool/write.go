@@ -20,20 +20,34 @@ type Write struct {
 // recycle buffer.Write objects across the lifetime of the Write pool's
 // workers.
 func NewWrite(writeBufferPool *WriteBuffer, numWorkers int,
-	workerTimeout time.Duration) *Write {
+	workerTimeout time.Duration, opts ...WorkerOption) *Write {
 
 	w := &Write{
 		bufferPool: writeBufferPool,
 	}
-	w.workerPool = NewWorker(&WorkerConfig{
+	cfg := &WorkerConfig{
 		NewWorkerState: w.newWorkerState,
 		NumWorkers:     numWorkers,
 		WorkerTimeout:  workerTimeout,
-	})
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	w.workerPool = NewWorker(cfg)
 
 	return w
 }
 
+// Stats returns a snapshot of the Write pool's utilization.
+func (w *Write) Stats() WorkerStats {
+	return w.workerPool.Stats()
+}
+
+// Resize sets the maximum number of workers of the Write pool.
+func (w *Write) Resize(numWorkers int) error {
+	return w.workerPool.Resize(numWorkers)
+}
+
 // Start safely spins up the Write pool.
 func (w *Write) Start() error {
 	return w.workerPool.Start()
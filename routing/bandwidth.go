@@ -263,3 +263,53 @@ func (b *bandwidthManager) availableChanBandwidth(channelID uint64,
 func (b *bandwidthManager) firstHopCustomBlob() fn.Option[tlv.Blob] {
 	return b.firstHopBlob
 }
+
+// capacityBandwidthHints provides bandwidth hints that assume the full
+// capacity of the source node's channels to be available. It is used for
+// path finding on a graph without any live channel state to consult.
+type capacityBandwidthHints struct {
+	capacities map[uint64]lnwire.MilliSatoshi
+}
+
+// newCapacityBandwidthHints creates bandwidth hints from the capacities of
+// the source node's channels in the given graph.
+func newCapacityBandwidthHints(g Graph,
+	source route.Vertex) (*capacityBandwidthHints, error) {
+
+	capacities := make(map[uint64]lnwire.MilliSatoshi)
+	err := g.ForEachNodeChannel(source,
+		func(channel *channeldb.DirectedChannel) error {
+			capacities[channel.ChannelID] =
+				lnwire.NewMSatFromSatoshis(channel.Capacity)
+
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &capacityBandwidthHints{
+		capacities: capacities,
+	}, nil
+}
+
+// availableChanBandwidth returns the capacity of the given channel and a bool
+// indicating whether the channel is known.
+//
+// NOTE: Part of the bandwidthHints interface.
+func (c *capacityBandwidthHints) availableChanBandwidth(channelID uint64,
+	_ lnwire.MilliSatoshi) (lnwire.MilliSatoshi, bool) {
+
+	capacity, ok := c.capacities[channelID]
+
+	return capacity, ok
+}
+
+// firstHopCustomBlob returns the custom blob for the first hop of the payment,
+// which is never available for capacity based hints.
+//
+// NOTE: Part of the bandwidthHints interface.
+func (c *capacityBandwidthHints) firstHopCustomBlob() fn.Option[tlv.Blob] {
+	return fn.None[tlv.Blob]()
+}
@@ -0,0 +1,184 @@
+package routing
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"sort"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+var (
+	// ErrEmptyBatch is returned if a payment batch doesn't contain any
+	// payments.
+	ErrEmptyBatch = errors.New("payment batch is empty")
+
+	// ErrDuplicateBatchPayment is returned if a payment batch contains
+	// multiple payments with the same identifier.
+	ErrDuplicateBatchPayment = errors.New("duplicate payment in batch")
+)
+
+// BatchPayment describes a set of payments that are sent together, e.g. the
+// invoices of a payroll run, and share a common fee budget.
+type BatchPayment struct {
+	// Payments are the payments of the batch.
+	Payments []*LightningPayment
+
+	// FeeBudget is the maximum amount of fees in millisatoshis that all
+	// payments of the batch may pay together. A non-zero FeeLimit of the
+	// individual payments is still honored if it is lower than their
+	// share of the budget.
+	FeeBudget lnwire.MilliSatoshi
+}
+
+// BatchPaymentResult is the outcome of a single payment of a batch.
+type BatchPaymentResult struct {
+	// Preimage is the preimage of the payment if it succeeded.
+	Preimage [32]byte
+
+	// Route is the route the payment took if it succeeded.
+	Route *route.Route
+
+	// FeeLimit is the share of the fee budget the payment was allowed to
+	// spend.
+	FeeLimit lnwire.MilliSatoshi
+
+	// Err is the reason the payment failed, nil if it succeeded.
+	Err error
+}
+
+// Fee returns the fee paid by the payment, zero if it failed.
+func (r *BatchPaymentResult) Fee() lnwire.MilliSatoshi {
+	if r.Err != nil || r.Route == nil {
+		return 0
+	}
+
+	return r.Route.TotalFees()
+}
+
+// validate checks that the batch contains at least one payment and no
+// duplicates.
+func (b *BatchPayment) validate() error {
+	if len(b.Payments) == 0 {
+		return ErrEmptyBatch
+	}
+
+	seen := make(map[[32]byte]struct{}, len(b.Payments))
+	for _, payment := range b.Payments {
+		id := payment.Identifier()
+		if _, ok := seen[id]; ok {
+			return fmt.Errorf("%w: %x", ErrDuplicateBatchPayment,
+				id)
+		}
+		seen[id] = struct{}{}
+	}
+
+	return nil
+}
+
+// SendPaymentBatch sends all payments of the batch on a best-effort basis and
+// returns a result for each of them, in the order of the batch. A failed
+// payment doesn't abort the remaining ones, so the caller must inspect the
+// individual results.
+//
+// The payments are sent one after the other, largest first, so they don't
+// compete for the same local liquidity: each payment's pathfinding sees our
+// channel balances after the payments before it have completed, and the
+// largest payments, which are the hardest to route, get first pick. The fee
+// budget is shared between the payments proportionally to their amount. Any
+// share that a payment doesn't use, either because it paid less or failed,
+// is handed on to the payments after it.
+func (r *ChannelRouter) SendPaymentBatch(batch *BatchPayment) (
+	[]*BatchPaymentResult, error) {
+
+	return sendPaymentBatch(batch, r.SendPayment)
+}
+
+// sendPaymentBatch implements SendPaymentBatch using the given function to
+// send the individual payments.
+func sendPaymentBatch(batch *BatchPayment,
+	sendPayment func(*LightningPayment) ([32]byte, *route.Route,
+		error)) ([]*BatchPaymentResult, error) {
+
+	if err := batch.validate(); err != nil {
+		return nil, err
+	}
+
+	// Schedule the payments by descending amount, keeping track of their
+	// position in the batch for the results.
+	order := make([]int, len(batch.Payments))
+	var remainingAmt lnwire.MilliSatoshi
+	for i, payment := range batch.Payments {
+		order[i] = i
+		remainingAmt += payment.Amount
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return batch.Payments[order[i]].Amount >
+			batch.Payments[order[j]].Amount
+	})
+
+	results := make([]*BatchPaymentResult, len(batch.Payments))
+	remainingBudget := batch.FeeBudget
+	for _, idx := range order {
+		payment := batch.Payments[idx]
+
+		feeLimit := feeBudgetShare(
+			remainingBudget, payment.Amount, remainingAmt,
+		)
+		if payment.FeeLimit != 0 && payment.FeeLimit < feeLimit {
+			feeLimit = payment.FeeLimit
+		}
+		remainingAmt -= payment.Amount
+
+		result := &BatchPaymentResult{
+			FeeLimit: feeLimit,
+		}
+		results[idx] = result
+
+		// Send a copy of the payment so the caller's payment isn't
+		// modified.
+		p := *payment
+		p.FeeLimit = feeLimit
+
+		log.Debugf("Sending payment %x of batch with amount=%v, "+
+			"fee_limit=%v", p.Identifier(), p.Amount, feeLimit)
+
+		result.Preimage, result.Route, result.Err = sendPayment(&p)
+		if result.Err != nil {
+			log.Debugf("Payment %x of batch failed: %v",
+				p.Identifier(), result.Err)
+
+			continue
+		}
+
+		// Never let a fee above the limit underflow the budget.
+		fee := result.Fee()
+		if fee > remainingBudget {
+			fee = remainingBudget
+		}
+		remainingBudget -= fee
+	}
+
+	return results, nil
+}
+
+// feeBudgetShare returns the share of the remaining fee budget for a payment
+// of amt, given that the payments that are still to be sent total
+// remainingAmt.
+func feeBudgetShare(budget, amt,
+	remainingAmt lnwire.MilliSatoshi) lnwire.MilliSatoshi {
+
+	if remainingAmt == 0 || amt >= remainingAmt {
+		return budget
+	}
+
+	// Compute budget * amt / remainingAmt with a 128-bit intermediate
+	// product so large amounts can't overflow. The quotient can't
+	// overflow as amt < remainingAmt.
+	hi, lo := bits.Mul64(uint64(budget), uint64(amt))
+	share, _ := bits.Div64(hi, lo, uint64(remainingAmt))
+
+	return lnwire.MilliSatoshi(share)
+}
@@ -0,0 +1,120 @@
+package routing
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+// newBatchTestPayment creates a payment with the given hash byte and amount.
+func newBatchTestPayment(t *testing.T, id byte, amt,
+	feeLimit lnwire.MilliSatoshi) *LightningPayment {
+
+	payment := &LightningPayment{
+		Amount:   amt,
+		FeeLimit: feeLimit,
+	}
+	require.NoError(t, payment.SetPaymentHash(lntypes.Hash{id}))
+
+	return payment
+}
+
+// TestSendPaymentBatch tests that the payments of a batch are sent largest
+// first, share the fee budget and report their results in batch order.
+func TestSendPaymentBatch(t *testing.T) {
+	t.Parallel()
+
+	errNoRoute := errors.New("no route")
+
+	batch := &BatchPayment{
+		Payments: []*LightningPayment{
+			newBatchTestPayment(t, 1, 1_000, 0),
+			newBatchTestPayment(t, 2, 3_000, 0),
+			newBatchTestPayment(t, 3, 2_000, 0),
+			newBatchTestPayment(t, 4, 4_000, 5),
+		},
+		FeeBudget: 100,
+	}
+
+	// The largest payment fails, the others pay half their fee limit.
+	var (
+		sent      []byte
+		feeLimits []lnwire.MilliSatoshi
+	)
+	sendPayment := func(p *LightningPayment) ([32]byte, *route.Route,
+		error) {
+
+		id := p.Identifier()
+		sent = append(sent, id[0])
+		feeLimits = append(feeLimits, p.FeeLimit)
+
+		if id[0] == 2 {
+			return [32]byte{}, nil, errNoRoute
+		}
+
+		rt := &route.Route{
+			TotalAmount: p.Amount + p.FeeLimit/2,
+			Hops: []*route.Hop{
+				{AmtToForward: p.Amount},
+			},
+		}
+
+		return id, rt, nil
+	}
+
+	results, err := sendPaymentBatch(batch, sendPayment)
+	require.NoError(t, err)
+
+	// Payment 4 is capped at its own fee limit of 5 and pays 2. Payment 2
+	// gets 3/6 of the remaining 98 and fails. Payment 3 gets 2/3 of 98
+	// and pays 32, leaving 66 for the last payment.
+	require.Equal(t, []byte{4, 2, 3, 1}, sent)
+	require.Equal(
+		t, []lnwire.MilliSatoshi{5, 49, 65, 66}, feeLimits,
+	)
+
+	require.Len(t, results, 4)
+	require.NoError(t, results[0].Err)
+	require.EqualValues(t, 33, results[0].Fee())
+	require.ErrorIs(t, results[1].Err, errNoRoute)
+	require.Zero(t, results[1].Fee())
+	require.NoError(t, results[2].Err)
+	require.EqualValues(t, 32, results[2].Fee())
+	require.NoError(t, results[3].Err)
+	require.EqualValues(t, 2, results[3].Fee())
+	require.Equal(t, [32]byte{3}, results[2].Preimage)
+
+	// The caller's payments aren't modified.
+	require.Zero(t, batch.Payments[0].FeeLimit)
+
+	// Invalid batches are rejected.
+	_, err = sendPaymentBatch(&BatchPayment{}, sendPayment)
+	require.ErrorIs(t, err, ErrEmptyBatch)
+
+	_, err = sendPaymentBatch(&BatchPayment{
+		Payments: []*LightningPayment{
+			newBatchTestPayment(t, 1, 1_000, 0),
+			newBatchTestPayment(t, 1, 2_000, 0),
+		},
+	}, sendPayment)
+	require.ErrorIs(t, err, ErrDuplicateBatchPayment)
+}
+
+// TestFeeBudgetShare tests the proportional split of the fee budget.
+func TestFeeBudgetShare(t *testing.T) {
+	t.Parallel()
+
+	require.EqualValues(t, 25, feeBudgetShare(100, 1, 4))
+	require.EqualValues(t, 100, feeBudgetShare(100, 4, 4))
+	require.EqualValues(t, 100, feeBudgetShare(100, 0, 0))
+	require.EqualValues(t, 0, feeBudgetShare(0, 1, 4))
+
+	// Large values don't overflow.
+	require.EqualValues(
+		t, 1<<40, feeBudgetShare(1<<41, 1<<62, 1<<63),
+	)
+}
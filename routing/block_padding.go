@@ -0,0 +1,75 @@
+package routing
+
+import (
+	"math"
+	"time"
+
+	"github.com/lightningnetwork/lnd/chainntnfs"
+)
+
+const (
+	// MinBlockPadding is the lower bound of the block padding when it is
+	// derived from the observed block intervals.
+	MinBlockPadding uint16 = 2
+
+	// MaxBlockPadding is the upper bound of the block padding when it is
+	// derived from the observed block intervals.
+	MaxBlockPadding uint16 = 12
+
+	// targetBlockInterval is the block interval BlockPadding is sized for.
+	targetBlockInterval = 10 * time.Minute
+
+	// minBlockIntervalSamples is the minimum number of observed block
+	// intervals required to deviate from the default BlockPadding.
+	minBlockIntervalSamples = 12
+
+	// minBlockInterval is the lowest block interval that is assumed when
+	// deriving the block padding, to bound the effect of blocks with
+	// skewed timestamps.
+	minBlockInterval = time.Minute
+)
+
+// BlockIntervalSource provides statistics about the intervals between
+// recently mined blocks.
+type BlockIntervalSource interface {
+	// BlockIntervalStats returns statistics about the recently observed
+	// block intervals.
+	BlockIntervalStats() (*chainntnfs.BlockIntervalStats, error)
+}
+
+// blockPaddingFromStats derives the block padding of the final hop from the
+// observed block intervals. BlockPadding covers the time an HTLC may be in
+// flight assuming blocks are found every ten minutes. When blocks are found
+// faster, more of them may be mined during that time and the padding is
+// increased, so the HTLC isn't rejected by the receiver for expiring too
+// soon. When blocks are found slower, the padding is decreased to not lock up
+// funds for longer than needed.
+//
+// To account for the uncertainty of the observed block rate, the mean block
+// interval is reduced by its standard error before deriving the padding. The
+// padding is clamped to [MinBlockPadding, MaxBlockPadding], and the default
+// BlockPadding is returned if there are too few samples to rely on.
+func blockPaddingFromStats(stats *chainntnfs.BlockIntervalStats) uint16 {
+	if stats == nil || stats.Samples < minBlockIntervalSamples {
+		return BlockPadding
+	}
+
+	interval := stats.Mean - stats.StdErr()
+	if interval < minBlockInterval {
+		interval = minBlockInterval
+	}
+
+	inFlightTime := time.Duration(BlockPadding) * targetBlockInterval
+	padding := math.Round(float64(inFlightTime) / float64(interval))
+
+	switch {
+	case padding < float64(MinBlockPadding):
+		return MinBlockPadding
+
+	case padding > float64(MaxBlockPadding):
+		return MaxBlockPadding
+
+	default:
+		return uint16(padding)
+	}
+}
@@ -0,0 +1,79 @@
+package routing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBlockPaddingFromStats tests that the block padding is derived from the
+// observed block intervals.
+func TestBlockPaddingFromStats(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		stats   *chainntnfs.BlockIntervalStats
+		padding uint16
+	}{
+		{
+			name:    "no stats",
+			padding: BlockPadding,
+		},
+		{
+			name: "too few samples",
+			stats: &chainntnfs.BlockIntervalStats{
+				Samples: minBlockIntervalSamples - 1,
+				Mean:    time.Minute,
+			},
+			padding: BlockPadding,
+		},
+		{
+			name: "regular blocks",
+			stats: &chainntnfs.BlockIntervalStats{
+				Samples: 144,
+				Mean:    10 * time.Minute,
+				StdDev:  10 * time.Minute,
+			},
+			padding: BlockPadding,
+		},
+		{
+			name: "fast blocks",
+			stats: &chainntnfs.BlockIntervalStats{
+				Samples: 144,
+				Mean:    5 * time.Minute,
+				StdDev:  5 * time.Minute,
+			},
+			padding: 7,
+		},
+		{
+			name: "slow blocks",
+			stats: &chainntnfs.BlockIntervalStats{
+				Samples: 144,
+				Mean:    20 * time.Minute,
+				StdDev:  20 * time.Minute,
+			},
+			padding: MinBlockPadding,
+		},
+		{
+			name: "very fast blocks",
+			stats: &chainntnfs.BlockIntervalStats{
+				Samples: 144,
+				Mean:    10 * time.Second,
+			},
+			padding: MaxBlockPadding,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(
+				t, tc.padding, blockPaddingFromStats(tc.stats),
+			)
+		})
+	}
+}
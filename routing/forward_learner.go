@@ -0,0 +1,199 @@
+package routing
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/subscribe"
+)
+
+// maxPendingForwards is the maximum number of in-flight forwards that the
+// ForwardLearner keeps track of. Forwards beyond this limit are only learned
+// from on the incoming side.
+const maxPendingForwards = 50_000
+
+// ForwardLearnerConfig contains the dependencies of the ForwardLearner.
+type ForwardLearnerConfig struct {
+	// SelfNode is the pubkey of our own node.
+	SelfNode route.Vertex
+
+	// SubscribeHtlcEvents is used to subscribe to the htlc events of the
+	// switch.
+	SubscribeHtlcEvents func() (*subscribe.Client, error)
+
+	// ChannelPeer returns the pubkey of the peer that we have the channel
+	// with the given short channel id with.
+	ChannelPeer func(lnwire.ShortChannelID) (route.Vertex, error)
+
+	// ReportSuccess is called for every channel that was able to carry a
+	// forwarded htlc of the given amount.
+	ReportSuccess func(fromNode, toNode route.Vertex,
+		amt lnwire.MilliSatoshi)
+}
+
+// pendingForward is a forward whose outgoing htlc hasn't been resolved yet.
+type pendingForward struct {
+	outgoingPeer route.Vertex
+	amt          lnwire.MilliSatoshi
+}
+
+// ForwardLearner feeds the htlcs that are forwarded through our node into
+// mission control. Every forward tells us that the incoming peer had enough
+// liquidity on its side of the channel to send us the htlc, and once the
+// outgoing htlc is settled or failed back by the outgoing peer, we know that
+// our channel to the outgoing peer was able to carry it as well.
+//
+// This improves the liquidity estimates of the channels around our node, and
+// the node probabilities of our peers, without sending any probes.
+type ForwardLearner struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *ForwardLearnerConfig
+
+	// pending holds the forwards whose outgoing htlc is in flight. It is
+	// only accessed by the event loop.
+	pending map[htlcswitch.HtlcKey]pendingForward
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// NewForwardLearner creates a new forward learner from the given config.
+func NewForwardLearner(cfg *ForwardLearnerConfig) *ForwardLearner {
+	return &ForwardLearner{
+		cfg:     cfg,
+		pending: make(map[htlcswitch.HtlcKey]pendingForward),
+		quit:    make(chan struct{}),
+	}
+}
+
+// Start subscribes to htlc events and launches the goroutine that learns from
+// them.
+func (f *ForwardLearner) Start() error {
+	var startErr error
+	f.started.Do(func() {
+		log.Info("Forward learner starting")
+
+		client, err := f.cfg.SubscribeHtlcEvents()
+		if err != nil {
+			startErr = fmt.Errorf("unable to subscribe to htlc "+
+				"events: %w", err)
+
+			return
+		}
+
+		f.wg.Add(1)
+		go f.run(client)
+	})
+
+	return startErr
+}
+
+// Stop signals the forward learner to shut down and waits for it to exit.
+func (f *ForwardLearner) Stop() error {
+	f.stopped.Do(func() {
+		log.Info("Forward learner shutting down...")
+		defer log.Debug("Forward learner shutdown complete")
+
+		close(f.quit)
+		f.wg.Wait()
+	})
+
+	return nil
+}
+
+// run is the event loop of the forward learner.
+//
+// NOTE: This MUST be run as a goroutine.
+func (f *ForwardLearner) run(client *subscribe.Client) {
+	defer f.wg.Done()
+	defer client.Cancel()
+
+	for {
+		select {
+		case event, ok := <-client.Updates():
+			if !ok {
+				return
+			}
+
+			f.handleEvent(event)
+
+		case <-f.quit:
+			return
+		}
+	}
+}
+
+// handleEvent learns from a single htlc event. Only forwards are taken into
+// account, as the results of our own payments are reported to mission control
+// by the payment lifecycle.
+func (f *ForwardLearner) handleEvent(event interface{}) {
+	switch e := event.(type) {
+	case *htlcswitch.ForwardingEvent:
+		if e.HtlcEventType != htlcswitch.HtlcEventTypeForward {
+			return
+		}
+
+		f.handleForward(e)
+
+	case *htlcswitch.SettleEvent:
+		f.resolveForward(e.HtlcKey)
+
+	// A failure that is sent back by the outgoing peer still means that
+	// the htlc was locked in on our outgoing channel.
+	case *htlcswitch.ForwardingFailEvent:
+		f.resolveForward(e.HtlcKey)
+
+	// If we failed to add the htlc to the outgoing channel, then there is
+	// nothing to learn about it, as we already know the balances of our
+	// own channels.
+	case *htlcswitch.LinkFailEvent:
+		delete(f.pending, e.HtlcKey)
+	}
+}
+
+// handleForward reports the success of the incoming channel of a forward, and
+// tracks the outgoing htlc until it is resolved.
+func (f *ForwardLearner) handleForward(e *htlcswitch.ForwardingEvent) {
+	incomingPeer, err := f.cfg.ChannelPeer(e.IncomingCircuit.ChanID)
+	if err != nil {
+		log.Debugf("Unable to find peer of incoming channel %v: %v",
+			e.IncomingCircuit.ChanID, err)
+	} else {
+		f.cfg.ReportSuccess(incomingPeer, f.cfg.SelfNode, e.IncomingAmt)
+	}
+
+	outgoingPeer, err := f.cfg.ChannelPeer(e.OutgoingCircuit.ChanID)
+	if err != nil {
+		log.Debugf("Unable to find peer of outgoing channel %v: %v",
+			e.OutgoingCircuit.ChanID, err)
+
+		return
+	}
+
+	if len(f.pending) >= maxPendingForwards {
+		return
+	}
+
+	f.pending[e.HtlcKey] = pendingForward{
+		outgoingPeer: outgoingPeer,
+		amt:          e.OutgoingAmt,
+	}
+}
+
+// resolveForward reports the success of the outgoing channel of a forward
+// whose outgoing htlc was resolved by the outgoing peer. Forwards that aren't
+// tracked, e.g. because they were made before a restart, are ignored.
+func (f *ForwardLearner) resolveForward(key htlcswitch.HtlcKey) {
+	fwd, ok := f.pending[key]
+	if !ok {
+		return
+	}
+	delete(f.pending, key)
+
+	f.cfg.ReportSuccess(f.cfg.SelfNode, fwd.outgoingPeer, fwd.amt)
+}
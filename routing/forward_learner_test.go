@@ -0,0 +1,124 @@
+package routing
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+// forwardReport is a success that was reported by the forward learner.
+type forwardReport struct {
+	from, to route.Vertex
+	amt      lnwire.MilliSatoshi
+}
+
+// TestForwardLearner tests that the forward learner reports the incoming
+// channel of a forward right away, and the outgoing channel once the outgoing
+// htlc was resolved by the outgoing peer.
+func TestForwardLearner(t *testing.T) {
+	t.Parallel()
+
+	var (
+		self     = route.Vertex{1}
+		incoming = route.Vertex{2}
+		outgoing = route.Vertex{3}
+
+		incomingChan = lnwire.NewShortChanIDFromInt(10)
+		outgoingChan = lnwire.NewShortChanIDFromInt(20)
+	)
+
+	var reports []forwardReport
+	learner := NewForwardLearner(&ForwardLearnerConfig{
+		SelfNode: self,
+		ChannelPeer: func(scid lnwire.ShortChannelID) (route.Vertex,
+			error) {
+
+			switch scid {
+			case incomingChan:
+				return incoming, nil
+
+			case outgoingChan:
+				return outgoing, nil
+			}
+
+			return route.Vertex{}, errors.New("unknown channel")
+		},
+		ReportSuccess: func(from, to route.Vertex,
+			amt lnwire.MilliSatoshi) {
+
+			reports = append(reports, forwardReport{from, to, amt})
+		},
+	})
+
+	htlcKey := func(htlcID uint64) htlcswitch.HtlcKey {
+		return htlcswitch.HtlcKey{
+			IncomingCircuit: models.CircuitKey{
+				ChanID: incomingChan,
+				HtlcID: htlcID,
+			},
+			OutgoingCircuit: models.CircuitKey{
+				ChanID: outgoingChan,
+				HtlcID: htlcID,
+			},
+		}
+	}
+	forward := func(htlcID uint64) {
+		learner.handleEvent(&htlcswitch.ForwardingEvent{
+			HtlcKey: htlcKey(htlcID),
+			HtlcInfo: htlcswitch.HtlcInfo{
+				IncomingAmt: 1_100,
+				OutgoingAmt: 1_000,
+			},
+			HtlcEventType: htlcswitch.HtlcEventTypeForward,
+		})
+	}
+
+	incomingReport := forwardReport{incoming, self, 1_100}
+	outgoingReport := forwardReport{self, outgoing, 1_000}
+
+	// A forward is reported on the incoming side right away.
+	forward(1)
+	require.Equal(t, []forwardReport{incomingReport}, reports)
+
+	// Once it settles, the outgoing side is reported too. A second settle
+	// of the same htlc isn't reported again.
+	learner.handleEvent(&htlcswitch.SettleEvent{HtlcKey: htlcKey(1)})
+	learner.handleEvent(&htlcswitch.SettleEvent{HtlcKey: htlcKey(1)})
+	require.Equal(t, []forwardReport{
+		incomingReport, outgoingReport,
+	}, reports)
+
+	// A failure that is sent back by the outgoing peer is reported as a
+	// success of the outgoing channel as well.
+	reports = nil
+	forward(2)
+	learner.handleEvent(&htlcswitch.ForwardingFailEvent{
+		HtlcKey: htlcKey(2),
+	})
+	require.Equal(t, []forwardReport{
+		incomingReport, outgoingReport,
+	}, reports)
+
+	// If we fail to add the htlc to the outgoing channel, then only the
+	// incoming side is reported.
+	reports = nil
+	forward(3)
+	learner.handleEvent(&htlcswitch.LinkFailEvent{HtlcKey: htlcKey(3)})
+	learner.handleEvent(&htlcswitch.SettleEvent{HtlcKey: htlcKey(3)})
+	require.Equal(t, []forwardReport{incomingReport}, reports)
+	require.Empty(t, learner.pending)
+
+	// Our own payments aren't taken into account.
+	reports = nil
+	learner.handleEvent(&htlcswitch.ForwardingEvent{
+		HtlcKey:       htlcKey(4),
+		HtlcEventType: htlcswitch.HtlcEventTypeSend,
+	})
+	learner.handleEvent(&htlcswitch.SettleEvent{HtlcKey: htlcKey(4)})
+	require.Empty(t, reports)
+}
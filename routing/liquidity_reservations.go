@@ -0,0 +1,155 @@
+package routing
+
+import (
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+const (
+	// DefaultShardReservationTimeout is the default duration for which
+	// the outbound liquidity used by a newly launched shard is reserved.
+	DefaultShardReservationTimeout = 2 * time.Second
+
+	// DefaultShardPacingInterval is the default minimum interval between
+	// launching consecutive shards of a payment. A value of zero disables
+	// pacing.
+	DefaultShardPacingInterval = time.Duration(0)
+)
+
+// liquidityReservation is the outbound liquidity reserved on one of our
+// channels for a shard that was just launched.
+type liquidityReservation struct {
+	chanID lnwire.ShortChannelID
+	amt    lnwire.MilliSatoshi
+	expiry time.Time
+}
+
+// LiquidityReservations keeps track of the outbound liquidity of our channels
+// that is used by recently launched shards. After a shard is handed to the
+// switch, it takes a moment before the link has added the HTLC and reports a
+// lower bandwidth. Without the reservations, the next shards would be routed
+// over the same channel based on its stale bandwidth and fail locally.
+//
+// A reservation is released once the result of its shard is known or its
+// timeout expires, whichever comes first. By then, the link's bandwidth is
+// expected to account for the HTLC.
+type LiquidityReservations struct {
+	timeout time.Duration
+	clock   clock.Clock
+
+	// reservations holds the active reservations, keyed by the attempt ID
+	// of the shard.
+	reservations map[uint64]liquidityReservation
+
+	mu sync.Mutex
+}
+
+// NewLiquidityReservations creates a new set of liquidity reservations, which
+// time out after the given duration.
+func NewLiquidityReservations(timeout time.Duration,
+	clock clock.Clock) *LiquidityReservations {
+
+	return &LiquidityReservations{
+		timeout:      timeout,
+		clock:        clock,
+		reservations: make(map[uint64]liquidityReservation),
+	}
+}
+
+// Reserve reserves amt of the outbound liquidity of the given channel for the
+// shard with the given attempt ID.
+func (l *LiquidityReservations) Reserve(attemptID uint64,
+	chanID lnwire.ShortChannelID, amt lnwire.MilliSatoshi) {
+
+	if l == nil || l.timeout <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.reservations[attemptID] = liquidityReservation{
+		chanID: chanID,
+		amt:    amt,
+		expiry: l.clock.Now().Add(l.timeout),
+	}
+}
+
+// Release releases the liquidity reserved for the shard with the given attempt
+// ID, if any.
+func (l *LiquidityReservations) Release(attemptID uint64) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.reservations, attemptID)
+}
+
+// Reserved returns the total outbound liquidity of the given channel that is
+// currently reserved. Expired reservations are released.
+func (l *LiquidityReservations) Reserved(
+	chanID lnwire.ShortChannelID) lnwire.MilliSatoshi {
+
+	if l == nil {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.clock.Now()
+
+	var reserved lnwire.MilliSatoshi
+	for attemptID, r := range l.reservations {
+		if !now.Before(r.expiry) {
+			delete(l.reservations, attemptID)
+			continue
+		}
+
+		if r.chanID == chanID {
+			reserved += r.amt
+		}
+	}
+
+	return reserved
+}
+
+// reservedBandwidthHints wraps a set of bandwidth hints, deducting the
+// liquidity that is reserved for recently launched shards from the bandwidth
+// of our channels.
+type reservedBandwidthHints struct {
+	bandwidthHints
+
+	reservations *LiquidityReservations
+}
+
+// availableChanBandwidth returns the total available bandwidth for a channel
+// minus the liquidity reserved on it, and a bool indicating whether the
+// channel hint was found.
+//
+// NOTE: Part of the bandwidthHints interface.
+func (r *reservedBandwidthHints) availableChanBandwidth(channelID uint64,
+	amount lnwire.MilliSatoshi) (lnwire.MilliSatoshi, bool) {
+
+	bandwidth, ok := r.bandwidthHints.availableChanBandwidth(
+		channelID, amount,
+	)
+	if !ok {
+		return 0, false
+	}
+
+	reserved := r.reservations.Reserved(
+		lnwire.NewShortChanIDFromInt(channelID),
+	)
+	if reserved >= bandwidth {
+		return 0, true
+	}
+
+	return bandwidth - reserved, true
+}
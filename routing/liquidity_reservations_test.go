@@ -0,0 +1,75 @@
+package routing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLiquidityReservations tests that reserved liquidity is deducted from the
+// bandwidth of our channels until it is released or times out.
+func TestLiquidityReservations(t *testing.T) {
+	t.Parallel()
+
+	const timeout = time.Second
+
+	testClock := clock.NewTestClock(time.Unix(1000, 0))
+	reservations := NewLiquidityReservations(timeout, testClock)
+
+	hints := &reservedBandwidthHints{
+		bandwidthHints: &mockBandwidthHints{
+			hints: map[uint64]lnwire.MilliSatoshi{
+				1: 10_000,
+				2: 5_000,
+			},
+		},
+		reservations: reservations,
+	}
+
+	chanA := lnwire.NewShortChanIDFromInt(1)
+	chanB := lnwire.NewShortChanIDFromInt(2)
+
+	requireBandwidth := func(chanID uint64, expected lnwire.MilliSatoshi) {
+		t.Helper()
+
+		bandwidth, ok := hints.availableChanBandwidth(chanID, 0)
+		require.True(t, ok)
+		require.Equal(t, expected, bandwidth)
+	}
+
+	// Two shards over the first channel and one exceeding the bandwidth
+	// of the second channel.
+	reservations.Reserve(1, chanA, 3_000)
+	reservations.Reserve(2, chanA, 4_000)
+	reservations.Reserve(3, chanB, 6_000)
+
+	requireBandwidth(1, 3_000)
+	requireBandwidth(2, 0)
+
+	_, ok := hints.availableChanBandwidth(3, 0)
+	require.False(t, ok)
+
+	// A failed shard releases its liquidity.
+	reservations.Release(2)
+	requireBandwidth(1, 7_000)
+
+	// The remaining reservations are released once they time out.
+	testClock.SetTime(testClock.Now().Add(timeout))
+	requireBandwidth(1, 10_000)
+	requireBandwidth(2, 5_000)
+	require.Empty(t, reservations.reservations)
+
+	// Without a timeout, nothing is reserved.
+	disabled := NewLiquidityReservations(0, testClock)
+	disabled.Reserve(1, chanA, 3_000)
+	require.Zero(t, disabled.Reserved(chanA))
+
+	// A nil set of reservations is safe to use.
+	var nilReservations *LiquidityReservations
+	nilReservations.Reserve(1, chanA, 3_000)
+	nilReservations.Release(1)
+	require.Zero(t, nilReservations.Reserved(chanA))
+}
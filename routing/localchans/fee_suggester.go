@@ -0,0 +1,464 @@
+package localchans
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+const (
+	// lowLiquidityRatio is the share of the channel capacity below which
+	// our local balance is considered scarce.
+	lowLiquidityRatio = 0.2
+
+	// highLiquidityRatio is the share of the channel capacity above which
+	// our local balance is considered abundant.
+	highLiquidityRatio = 0.8
+)
+
+// FeeSuggesterConfig holds the configuration of the FeeSuggester.
+type FeeSuggesterConfig struct {
+	// SelfNode is our own node.
+	SelfNode route.Vertex
+
+	// ForAllOutgoingChannels is used to iterate over all our channels
+	// along with our current policy.
+	ForAllOutgoingChannels func(cb func(kvdb.RTx,
+		*models.ChannelEdgeInfo,
+		*models.ChannelEdgePolicy) error) error
+
+	// FetchChannel is used to query the balance of our channels.
+	FetchChannel func(tx kvdb.RTx, chanPoint wire.OutPoint) (
+		*channeldb.OpenChannel, error)
+
+	// ForEachNodeChannel iterates over the channels of the given node in
+	// the graph. The second policy passed to the callback is the policy
+	// of the node on the other end of the channel.
+	ForEachNodeChannel func(node route.Vertex, cb func(kvdb.RTx,
+		*models.ChannelEdgeInfo, *models.ChannelEdgePolicy,
+		*models.ChannelEdgePolicy) error) error
+
+	// ForwardingEvents returns all forwarding events within the given
+	// time range.
+	ForwardingEvents func(start, end time.Time) (
+		[]channeldb.ForwardingEvent, error)
+
+	// UpdatePolicy applies a new policy to the given channels.
+	UpdatePolicy func(routing.ChannelPolicy, ...wire.OutPoint) (
+		[]*lnrpc.FailedUpdate, error)
+
+	// Lookback is the duration of forwarding history that is analyzed.
+	Lookback time.Duration
+
+	// MinFeeRate is the lowest fee rate in parts per million that is
+	// ever suggested.
+	MinFeeRate uint32
+
+	// MaxFeeRate is the highest fee rate in parts per million that is
+	// ever suggested.
+	MaxFeeRate uint32
+
+	// MaxChange is the largest relative change of a channel's fee rate
+	// that is suggested at once, e.g. 0.25 for 25%.
+	MaxChange float64
+
+	// AutoApplyInterval is the interval at which suggestions are
+	// automatically applied. A zero value disables auto-applying.
+	AutoApplyInterval time.Duration
+
+	// Clock is used to determine the forwarding history window.
+	Clock clock.Clock
+}
+
+// FeeSuggestion is the suggested fee rate for one of our channels along with
+// the data it is based on.
+type FeeSuggestion struct {
+	// ChanPoint is the funding outpoint of the channel.
+	ChanPoint wire.OutPoint
+
+	// ChannelID is the short channel ID of the channel.
+	ChannelID uint64
+
+	// CurrentFeeRate is our current fee rate in parts per million.
+	CurrentFeeRate uint32
+
+	// SuggestedFeeRate is the suggested fee rate in parts per million.
+	SuggestedFeeRate uint32
+
+	// PeerMedianFeeRate is the median fee rate other nodes charge for
+	// forwarding to the same peer, zero if there are none.
+	PeerMedianFeeRate uint32
+
+	// LocalBalanceRatio is the share of the channel capacity that is on
+	// our side.
+	LocalBalanceRatio float64
+
+	// NumForwards is the number of payments we forwarded over the channel
+	// within the lookback window.
+	NumForwards int
+
+	// ForwardedAmt is the amount we forwarded over the channel within the
+	// lookback window.
+	ForwardedAmt lnwire.MilliSatoshi
+
+	// FeesEarned are the fees we earned for forwarding over the channel
+	// within the lookback window.
+	FeesEarned lnwire.MilliSatoshi
+
+	// Reason explains the suggestion.
+	Reason string
+}
+
+// Changed returns true if the suggested fee rate differs from the current one.
+func (f *FeeSuggestion) Changed() bool {
+	return f.SuggestedFeeRate != f.CurrentFeeRate
+}
+
+// channelStats holds the forwarding statistics of a channel.
+type channelStats struct {
+	numForwards  int
+	forwardedAmt lnwire.MilliSatoshi
+	feesEarned   lnwire.MilliSatoshi
+}
+
+// FeeSuggester analyzes the policies of the channels in our graph
+// neighborhood and our forwarding history to suggest fee rates for our
+// channels. The suggestions follow a few simple rules that aim at
+// maximizing the expected forwarding revenue:
+//   - Channels that are in demand but run out of outbound liquidity get more
+//     expensive, so the remaining liquidity is used for the most valuable
+//     forwards.
+//   - Channels that are in demand but priced below the other channels to the
+//     same peer move towards their price.
+//   - Idle channels with plenty of outbound liquidity get cheaper, down to
+//     the price of the other channels to the same peer.
+//
+// All suggestions are bounded by the configured minimum and maximum fee rate
+// and the maximum relative change.
+type FeeSuggester struct {
+	cfg *FeeSuggesterConfig
+
+	started sync.Once
+	stopped sync.Once
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewFeeSuggester creates a new FeeSuggester.
+func NewFeeSuggester(cfg *FeeSuggesterConfig) *FeeSuggester {
+	return &FeeSuggester{
+		cfg:  cfg,
+		quit: make(chan struct{}),
+	}
+}
+
+// Start starts auto-applying suggestions, if enabled.
+func (f *FeeSuggester) Start() error {
+	f.started.Do(func() {
+		if f.cfg.AutoApplyInterval == 0 {
+			return
+		}
+
+		log.Infof("Auto-applying fee suggestions every %v",
+			f.cfg.AutoApplyInterval)
+
+		f.wg.Add(1)
+		go f.autoApply()
+	})
+
+	return nil
+}
+
+// Stop stops auto-applying suggestions.
+func (f *FeeSuggester) Stop() error {
+	f.stopped.Do(func() {
+		close(f.quit)
+		f.wg.Wait()
+	})
+
+	return nil
+}
+
+// autoApply applies the fee suggestions each time the auto-apply interval
+// elapses.
+//
+// NOTE: This MUST be run as a goroutine.
+func (f *FeeSuggester) autoApply() {
+	defer f.wg.Done()
+
+	ticker := time.NewTicker(f.cfg.AutoApplyInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			suggestions, err := f.Suggest()
+			if err != nil {
+				log.Errorf("Unable to suggest fees: %v", err)
+				continue
+			}
+
+			if err := f.Apply(suggestions); err != nil {
+				log.Errorf("Unable to apply fee suggestions: "+
+					"%v", err)
+			}
+
+		case <-f.quit:
+			return
+		}
+	}
+}
+
+// Suggest returns a fee suggestion for each of our channels without applying
+// any of them.
+func (f *FeeSuggester) Suggest() ([]*FeeSuggestion, error) {
+	end := f.cfg.Clock.Now()
+	events, err := f.cfg.ForwardingEvents(end.Add(-f.cfg.Lookback), end)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query forwarding history: "+
+			"%w", err)
+	}
+
+	stats := make(map[uint64]*channelStats)
+	for _, event := range events {
+		chanID := event.OutgoingChanID.ToUint64()
+		s, ok := stats[chanID]
+		if !ok {
+			s = &channelStats{}
+			stats[chanID] = s
+		}
+
+		s.numForwards++
+		s.forwardedAmt += event.AmtOut
+		if event.AmtIn > event.AmtOut {
+			s.feesEarned += event.AmtIn - event.AmtOut
+		}
+	}
+
+	var suggestions []*FeeSuggestion
+	err = f.cfg.ForAllOutgoingChannels(func(tx kvdb.RTx,
+		info *models.ChannelEdgeInfo,
+		edge *models.ChannelEdgePolicy) error {
+
+		// Skip channels that are still in the graph but were already
+		// closed.
+		channel, err := f.cfg.FetchChannel(tx, info.ChannelPoint)
+		if errors.Is(err, channeldb.ErrChannelNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		peer := route.Vertex(info.NodeKey1Bytes)
+		if peer == f.cfg.SelfNode {
+			peer = info.NodeKey2Bytes
+		}
+
+		peerMedian, err := f.peerMedianFeeRate(peer)
+		if err != nil {
+			return err
+		}
+
+		localBalance := channel.LocalCommitment.LocalBalance
+		capacity := lnwire.NewMSatFromSatoshis(channel.Capacity)
+
+		var ratio float64
+		if capacity > 0 {
+			ratio = float64(localBalance) / float64(capacity)
+		}
+
+		suggestion := &FeeSuggestion{
+			ChanPoint: info.ChannelPoint,
+			ChannelID: info.ChannelID,
+			CurrentFeeRate: uint32(
+				edge.FeeProportionalMillionths,
+			),
+			PeerMedianFeeRate: peerMedian,
+			LocalBalanceRatio: ratio,
+		}
+		if s, ok := stats[info.ChannelID]; ok {
+			suggestion.NumForwards = s.numForwards
+			suggestion.ForwardedAmt = s.forwardedAmt
+			suggestion.FeesEarned = s.feesEarned
+		}
+		f.suggestFeeRate(suggestion)
+
+		suggestions = append(suggestions, suggestion)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return suggestions, nil
+}
+
+// peerMedianFeeRate returns the median fee rate that other nodes charge for
+// forwarding to the given peer, zero if there are none.
+func (f *FeeSuggester) peerMedianFeeRate(peer route.Vertex) (uint32, error) {
+	var feeRates []uint32
+	err := f.cfg.ForEachNodeChannel(peer, func(_ kvdb.RTx,
+		info *models.ChannelEdgeInfo, _ *models.ChannelEdgePolicy,
+		inPolicy *models.ChannelEdgePolicy) error {
+
+		// Skip our own channels, unknown policies and disabled
+		// channels.
+		if info.NodeKey1Bytes == f.cfg.SelfNode ||
+			info.NodeKey2Bytes == f.cfg.SelfNode ||
+			inPolicy == nil || inPolicy.IsDisabled() {
+
+			return nil
+		}
+
+		feeRates = append(
+			feeRates, uint32(inPolicy.FeeProportionalMillionths),
+		)
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if len(feeRates) == 0 {
+		return 0, nil
+	}
+
+	sort.Slice(feeRates, func(i, j int) bool {
+		return feeRates[i] < feeRates[j]
+	})
+
+	return feeRates[len(feeRates)/2], nil
+}
+
+// suggestFeeRate sets the suggested fee rate and its reason based on the
+// statistics of the suggestion.
+func (f *FeeSuggester) suggestFeeRate(s *FeeSuggestion) {
+	current := float64(s.CurrentFeeRate)
+	median := float64(s.PeerMedianFeeRate)
+	inDemand := s.NumForwards > 0
+
+	target := current
+	switch {
+	case inDemand && s.LocalBalanceRatio < lowLiquidityRatio:
+		target = current * (1 + f.cfg.MaxChange)
+
+		// A zero fee rate can't be increased relatively, so we start
+		// from the minimum instead.
+		if target == 0 {
+			target = math.Max(median, 1)
+		}
+		s.Reason = "outbound liquidity is scarce while the channel " +
+			"is in demand"
+
+	case inDemand && current < median:
+		target = median
+		s.Reason = "priced below the other channels to the peer " +
+			"while the channel is in demand"
+
+	case !inDemand && s.LocalBalanceRatio > highLiquidityRatio:
+		target = current * (1 - f.cfg.MaxChange)
+		if s.PeerMedianFeeRate != 0 && current > median {
+			target = math.Max(target, median)
+		}
+		s.Reason = "idle channel with abundant outbound liquidity"
+
+	default:
+		s.Reason = "no change needed"
+	}
+
+	// Limit the relative change, unless we're starting from zero.
+	if current > 0 {
+		target = math.Min(target, current*(1+f.cfg.MaxChange))
+		target = math.Max(target, current*(1-f.cfg.MaxChange))
+	}
+
+	// Finally, make sure we stay within the configured bounds.
+	target = math.Max(target, float64(f.cfg.MinFeeRate))
+	if f.cfg.MaxFeeRate != 0 {
+		target = math.Min(target, float64(f.cfg.MaxFeeRate))
+	}
+
+	s.SuggestedFeeRate = uint32(math.Round(target))
+}
+
+// Apply applies the changed fee rates of the given suggestions. All other
+// policy parameters of the channels are left unchanged.
+func (f *FeeSuggester) Apply(suggestions []*FeeSuggestion) error {
+	changed := make(map[wire.OutPoint]*FeeSuggestion)
+	for _, s := range suggestions {
+		if s.Changed() {
+			changed[s.ChanPoint] = s
+		}
+	}
+
+	if len(changed) == 0 {
+		return nil
+	}
+
+	// We need the current policies so we can keep all parameters except
+	// for the fee rate.
+	policies := make(map[wire.OutPoint]routing.ChannelPolicy)
+	err := f.cfg.ForAllOutgoingChannels(func(_ kvdb.RTx,
+		info *models.ChannelEdgeInfo,
+		edge *models.ChannelEdgePolicy) error {
+
+		s, ok := changed[info.ChannelPoint]
+		if !ok {
+			return nil
+		}
+
+		minHTLC := edge.MinHTLC
+		policies[info.ChannelPoint] = routing.ChannelPolicy{
+			FeeSchema: routing.FeeSchema{
+				BaseFee: edge.FeeBaseMSat,
+				FeeRate: s.SuggestedFeeRate,
+			},
+			TimeLockDelta: uint32(edge.TimeLockDelta),
+			MaxHTLC:       edge.MaxHTLC,
+			MinHTLC:       &minHTLC,
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for chanPoint, policy := range policies {
+		s := changed[chanPoint]
+		failed, err := f.cfg.UpdatePolicy(policy, chanPoint)
+		if err != nil {
+			return err
+		}
+		if len(failed) > 0 {
+			log.Warnf("Unable to apply fee rate %d ppm to "+
+				"ChannelPoint(%v): %v", s.SuggestedFeeRate,
+				chanPoint, failed[0].UpdateError)
+
+			continue
+		}
+
+		log.Infof("Applied fee rate %d ppm (was %d ppm) to "+
+			"ChannelPoint(%v): %v", s.SuggestedFeeRate,
+			s.CurrentFeeRate, chanPoint, s.Reason)
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package localchans
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestSuggestFeeRate tests the fee rate heuristics of the FeeSuggester.
+func TestSuggestFeeRate(t *testing.T) {
+	t.Parallel()
+
+	f := NewFeeSuggester(&FeeSuggesterConfig{
+		MinFeeRate: 1,
+		MaxFeeRate: 2000,
+		MaxChange:  0.25,
+	})
+
+	tests := []struct {
+		name         string
+		suggestion   FeeSuggestion
+		expectedRate uint32
+	}{
+		{
+			name: "scarce liquidity in demand",
+			suggestion: FeeSuggestion{
+				CurrentFeeRate:    100,
+				PeerMedianFeeRate: 100,
+				LocalBalanceRatio: 0.1,
+				NumForwards:       5,
+			},
+			expectedRate: 125,
+		},
+		{
+			name: "scarce liquidity from zero fee rate",
+			suggestion: FeeSuggestion{
+				CurrentFeeRate:    0,
+				PeerMedianFeeRate: 300,
+				LocalBalanceRatio: 0.1,
+				NumForwards:       5,
+			},
+			expectedRate: 300,
+		},
+		{
+			name: "priced below peer median",
+			suggestion: FeeSuggestion{
+				CurrentFeeRate:    100,
+				PeerMedianFeeRate: 110,
+				LocalBalanceRatio: 0.5,
+				NumForwards:       5,
+			},
+			expectedRate: 110,
+		},
+		{
+			name: "priced far below peer median",
+			suggestion: FeeSuggestion{
+				CurrentFeeRate:    100,
+				PeerMedianFeeRate: 1000,
+				LocalBalanceRatio: 0.5,
+				NumForwards:       5,
+			},
+			expectedRate: 125,
+		},
+		{
+			name: "idle with abundant liquidity",
+			suggestion: FeeSuggestion{
+				CurrentFeeRate:    100,
+				PeerMedianFeeRate: 90,
+				LocalBalanceRatio: 0.9,
+			},
+			expectedRate: 90,
+		},
+		{
+			name: "idle without peer median",
+			suggestion: FeeSuggestion{
+				CurrentFeeRate:    100,
+				LocalBalanceRatio: 0.9,
+			},
+			expectedRate: 75,
+		},
+		{
+			name: "balanced channel unchanged",
+			suggestion: FeeSuggestion{
+				CurrentFeeRate:    100,
+				PeerMedianFeeRate: 50,
+				LocalBalanceRatio: 0.5,
+				NumForwards:       5,
+			},
+			expectedRate: 100,
+		},
+		{
+			name: "capped at max fee rate",
+			suggestion: FeeSuggestion{
+				CurrentFeeRate:    1900,
+				LocalBalanceRatio: 0.1,
+				NumForwards:       5,
+			},
+			expectedRate: 2000,
+		},
+		{
+			name: "bounded by min fee rate",
+			suggestion: FeeSuggestion{
+				CurrentFeeRate:    1,
+				LocalBalanceRatio: 0.9,
+			},
+			expectedRate: 1,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := test.suggestion
+			f.suggestFeeRate(&s)
+
+			require.Equal(t, test.expectedRate, s.SuggestedFeeRate)
+			require.NotEmpty(t, s.Reason)
+			require.Equal(
+				t, test.expectedRate != s.CurrentFeeRate,
+				s.Changed(),
+			)
+		})
+	}
+}
@@ -504,6 +504,99 @@ func (m *MissionControl) ResetHistory() error {
 	return nil
 }
 
+// ResetFilter selects the mission control data that is cleared by a selective
+// reset. Only data that matches all of the set criteria is cleared.
+type ResetFilter struct {
+	// Node, if set, restricts the reset to pairs that involve this node.
+	Node fn.Option[route.Vertex]
+
+	// OlderThan, if non-zero, restricts the reset to results that were
+	// recorded before this time. For pairs that have both a success and a
+	// failure recorded, only the outdated part is cleared.
+	OlderThan time.Time
+
+	// FailuresOnly restricts the reset to pairs that have failed, but never
+	// succeeded.
+	FailuresOnly bool
+}
+
+// matchesPair returns true if the node pair is selected by the filter's node
+// restriction.
+func (f *ResetFilter) matchesPair(from, to route.Vertex) bool {
+	return fn.ElimOption(
+		f.Node, func() bool { return true },
+		func(node route.Vertex) bool {
+			return node == from || node == to
+		},
+	)
+}
+
+// isOutdated returns true if the given timestamp is selected by the filter's
+// time restriction. Zero timestamps are never selected.
+func (f *ResetFilter) isOutdated(timestamp time.Time) bool {
+	if timestamp.IsZero() {
+		return false
+	}
+
+	return f.OlderThan.IsZero() || timestamp.Before(f.OlderThan)
+}
+
+// matchesResult returns true if the raw payment result is selected by the
+// filter.
+func (f *ResetFilter) matchesResult(result *paymentResult) bool {
+	if f.FailuresOnly && result.success {
+		return false
+	}
+
+	if !f.isOutdated(result.timeReply) {
+		return false
+	}
+
+	return fn.ElimOption(
+		f.Node, func() bool { return true },
+		func(node route.Vertex) bool {
+			if result.route.sourcePubKey == node {
+				return true
+			}
+			for _, hop := range result.route.hops {
+				if hop.pubKeyBytes == node {
+					return true
+				}
+			}
+
+			return false
+		},
+	)
+}
+
+// ResetHistoryFiltered clears the part of the mission control history that is
+// selected by the passed filter, leaving all other learned state in place. It
+// returns the number of node pairs that were affected.
+//
+// The raw payment results that the state is rederived from on startup are
+// pruned using the same criteria, so that the reset survives a restart. As a
+// single payment result may carry information about several pairs, this can
+// drop some information about pairs that weren't selected by the filter once
+// the state is rederived.
+func (m *MissionControl) ResetHistoryFiltered(filter *ResetFilter) (int,
+	error) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	deleted, err := m.store.deleteResults(filter.matchesResult)
+	if err != nil {
+		return 0, err
+	}
+
+	reset := m.state.resetFiltered(filter)
+
+	m.log.Debugf("Mission control history of %d pairs cleared, %d stored "+
+		"results deleted", reset, deleted)
+
+	return reset, nil
+}
+
 // GetProbability is expected to return the success probability of a payment
 // from fromNode along edge.
 func (m *MissionControl) GetProbability(fromNode, toNode route.Vertex,
@@ -622,6 +715,31 @@ func (m *MissionControl) ReportPaymentSuccess(paymentID uint64,
 	return err
 }
 
+// ReportForwardSuccess reports that a channel between two nodes was able to
+// carry the given amount. It is used to learn from htlcs that were forwarded
+// through our node, which tell us about the liquidity of the channels to our
+// peers without probing them.
+//
+// Unlike payment results, forwarding results are only applied to the
+// in-memory state and aren't persisted, so they don't evict the payment
+// results that are stored on disk.
+func (m *MissionControl) ReportForwardSuccess(fromNode, toNode route.Vertex,
+	amt lnwire.MilliSatoshi) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.log.Tracef("Reporting forward success to Mission Control: "+
+		"pair=%v -> %v, amt=%v", fromNode, toNode, amt)
+
+	m.state.setLastPairResult(
+		fromNode, toNode, m.cfg.clock.Now(), &pairResult{
+			amt:     amt,
+			success: true,
+		}, false,
+	)
+}
+
 // processPaymentResult stores a payment result in the mission control store and
 // updates mission control's in-memory state.
 func (m *MissionControl) processPaymentResult(result *paymentResult) (
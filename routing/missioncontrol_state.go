@@ -53,6 +53,60 @@ func (m *missionControlState) resetHistory() {
 	m.lastSecondChance = make(map[DirectedNodePair]time.Time)
 }
 
+// resetFiltered clears the pair results that are selected by the passed
+// filter and returns the number of pairs that were affected.
+func (m *missionControlState) resetFiltered(filter *ResetFilter) int {
+	var reset int
+	for fromNode, nodePairs := range m.lastPairResult {
+		for toNode, result := range nodePairs {
+			if !filter.matchesPair(fromNode, toNode) {
+				continue
+			}
+
+			if filter.FailuresOnly && !result.SuccessTime.IsZero() {
+				continue
+			}
+
+			failOutdated := filter.isOutdated(result.FailTime)
+			successOutdated := filter.isOutdated(result.SuccessTime)
+			if !failOutdated && !successOutdated {
+				continue
+			}
+
+			if failOutdated {
+				result.FailTime = time.Time{}
+				result.FailAmt = 0
+			}
+			if successOutdated {
+				result.SuccessTime = time.Time{}
+				result.SuccessAmt = 0
+			}
+
+			if result.FailTime.IsZero() &&
+				result.SuccessTime.IsZero() {
+
+				delete(nodePairs, toNode)
+			} else {
+				nodePairs[toNode] = result
+			}
+
+			reset++
+		}
+
+		if len(nodePairs) == 0 {
+			delete(m.lastPairResult, fromNode)
+		}
+	}
+
+	for pair := range m.lastSecondChance {
+		if filter.matchesPair(pair.From, pair.To) {
+			delete(m.lastSecondChance, pair)
+		}
+	}
+
+	return reset
+}
+
 // setLastPairResult stores a result for a node pair.
 func (m *missionControlState) setLastPairResult(fromNode, toNode route.Vertex,
 	timestamp time.Time, result *pairResult, force bool) {
@@ -80,6 +80,10 @@ type missionControlStore struct {
 	// flushInterval is the configured interval we use to store new results
 	// and delete outdated ones from the db.
 	flushInterval time.Duration
+
+	// storeMtx serializes the writes to the db and the accompanying
+	// updates of keys and keysMap.
+	storeMtx sync.Mutex
 }
 
 func newMissionControlStore(db missionControlDB, maxRecords int,
@@ -137,6 +141,76 @@ func (b *missionControlStore) clear() error {
 	return nil
 }
 
+// deleteResults removes all results that match the given predicate from the
+// db and the queue of pending results. It returns the number of results that
+// were removed from the db.
+func (b *missionControlStore) deleteResults(
+	match func(*paymentResult) bool) (int, error) {
+
+	b.storeMtx.Lock()
+	defer b.storeMtx.Unlock()
+
+	b.queueCond.L.Lock()
+	for e := b.queue.Front(); e != nil; {
+		next := e.Next()
+		if pr, ok := e.Value.(*paymentResult); ok && match(pr) {
+			b.queue.Remove(e)
+		}
+		e = next
+	}
+	b.queueCond.L.Unlock()
+
+	var delKeys []string
+	err := b.db.update(func(bucket kvdb.RwBucket) error {
+		err := bucket.ForEach(func(k, v []byte) error {
+			result, err := deserializeResult(k, v)
+			if err != nil {
+				return err
+			}
+
+			if match(result) {
+				delKeys = append(delKeys, string(k))
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, key := range delKeys {
+			if err := bucket.Delete([]byte(key)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func() {
+		delKeys = nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// DB Update was successful, update the in-memory cache.
+	deleted := make(map[string]struct{}, len(delKeys))
+	for _, key := range delKeys {
+		delete(b.keysMap, key)
+		deleted[key] = struct{}{}
+	}
+	for e := b.keys.Front(); e != nil; {
+		next := e.Next()
+		if key, ok := e.Value.(string); ok {
+			if _, ok := deleted[key]; ok {
+				b.keys.Remove(e)
+			}
+		}
+		e = next
+	}
+
+	return len(delKeys), nil
+}
+
 // fetchAll returns all results currently stored in the database.
 func (b *missionControlStore) fetchAll() ([]*paymentResult, error) {
 	var results []*paymentResult
@@ -439,6 +513,9 @@ func (b *missionControlStore) run() {
 
 // storeResults stores all accumulated results.
 func (b *missionControlStore) storeResults() error {
+	b.storeMtx.Lock()
+	defer b.storeMtx.Unlock()
+
 	// We copy a reference to the queue and clear the original queue to be
 	// able to release the lock.
 	b.queueCond.L.Lock()
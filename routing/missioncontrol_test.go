@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/fn"
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/routing/route"
@@ -226,6 +227,66 @@ func TestMissionControl(t *testing.T) {
 	ctx.reportSuccess()
 }
 
+// TestMissionControlResetFiltered tests that selective resets only clear the
+// selected part of the mission control history, and that they survive a
+// restart.
+func TestMissionControlResetFiltered(t *testing.T) {
+	ctx := createMcTestContext(t)
+
+	// A failure reported by the first hop results in a success for our
+	// channel and a failure for the first hop's outgoing channel.
+	ctx.reportFailure(0, lnwire.NewTemporaryChannelFailure(nil))
+
+	// A later success is recorded for both pairs.
+	later := mcTestTime.Add(time.Hour)
+	ctx.clock.setTime(later)
+	ctx.reportSuccess()
+
+	pair := ctx.mc.GetPairHistorySnapshot(mcTestNode1, mcTestNode2)
+	require.False(t, pair.FailTime.IsZero())
+	require.False(t, pair.SuccessTime.IsZero())
+
+	// Pairs that succeeded aren't affected when only resetting failures.
+	reset, err := ctx.mc.ResetHistoryFiltered(&ResetFilter{
+		FailuresOnly: true,
+	})
+	require.NoError(t, err)
+	require.Zero(t, reset)
+
+	// Resetting everything older than the success should only clear the
+	// failure.
+	reset, err = ctx.mc.ResetHistoryFiltered(&ResetFilter{
+		OlderThan: later,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, reset)
+
+	assertPair := func(from, to route.Vertex, failed, succeeded bool) {
+		t.Helper()
+
+		pair := ctx.mc.GetPairHistorySnapshot(from, to)
+		require.Equal(t, failed, !pair.FailTime.IsZero())
+		require.Equal(t, succeeded, !pair.SuccessTime.IsZero())
+	}
+	assertPair(mcTestSelf, mcTestNode1, false, true)
+	assertPair(mcTestNode1, mcTestNode2, false, true)
+
+	// The failure should still be gone after a restart.
+	ctx.restartMc()
+	assertPair(mcTestSelf, mcTestNode1, false, true)
+	assertPair(mcTestNode1, mcTestNode2, false, true)
+
+	// Resetting the pairs of a node shouldn't affect other pairs.
+	reset, err = ctx.mc.ResetHistoryFiltered(&ResetFilter{
+		Node: fn.Some(mcTestSelf),
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, reset)
+	assertPair(mcTestSelf, mcTestNode1, false, false)
+	assertPair(mcTestNode1, mcTestNode2, false, true)
+	require.Len(t, ctx.mc.GetHistorySnapshot().Pairs, 1)
+}
+
 // TestMissionControlChannelUpdate tests that the first channel update is not
 // penalizing the channel yet.
 func TestMissionControlChannelUpdate(t *testing.T) {
@@ -246,6 +307,22 @@ func TestMissionControlChannelUpdate(t *testing.T) {
 	ctx.expectP(100, 0)
 }
 
+// TestMissionControlForwardSuccess tests that forwarding results are applied
+// to the in-memory state, but aren't persisted.
+func TestMissionControlForwardSuccess(t *testing.T) {
+	ctx := createMcTestContext(t)
+
+	ctx.expectP(1000, testAprioriHopProbability)
+
+	ctx.mc.ReportForwardSuccess(mcTestNode1, mcTestNode2, 1000)
+	ctx.expectP(1000, testAprioriHopProbability+0.05)
+
+	// After a restart, the state is rederived from the stored payment
+	// results only, so the forwarding result is gone.
+	ctx.restartMc()
+	ctx.expectP(1000, testAprioriHopProbability)
+}
+
 // TestMissionControlNamespaces tests that the results reported to a
 // MissionControl instance in one namespace does not affect the query results in
 // another namespace.
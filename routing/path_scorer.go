@@ -0,0 +1,116 @@
+package routing
+
+import (
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+// ScoredEdge describes an edge that path finding considers adding to a path.
+// Because the search runs backwards from the target, the rest of the path
+// from ToNode to the target is already known when the edge is scored.
+type ScoredEdge struct {
+	// FromNode is the node that the edge starts at.
+	FromNode route.Vertex
+
+	// ToNode is the node that the edge ends at.
+	ToNode route.Vertex
+
+	// Amt is the amount that FromNode needs to send over the edge.
+	Amt lnwire.MilliSatoshi
+
+	// Capacity is the capacity of the channel of the edge.
+	Capacity btcutil.Amount
+
+	// Fee is the total fee that FromNode charges for the forward, including
+	// the inbound fee of ToNode. It is zero if FromNode is the source.
+	Fee lnwire.MilliSatoshi
+
+	// TimeLockDelta is the time lock delta that FromNode requires for the
+	// forward. It is zero if FromNode is the source.
+	TimeLockDelta uint16
+}
+
+// PathScore is the score of a partial path from a node to the target.
+type PathScore struct {
+	// Distance is the distance of the path that path finding minimizes.
+	Distance int64
+
+	// Weight is the summed up penalty of the edges of the path.
+	Weight int64
+
+	// Probability is the success probability of the path.
+	Probability float64
+}
+
+// PathScorer determines how path finding values the edges of the graph. It
+// allows the trade-off between fees, time locks and the success probability
+// of a payment to be replaced as a whole or in parts.
+type PathScorer interface {
+	// EdgeProbability returns the success probability of sending the
+	// amount of the edge over it. A probability of zero excludes the edge
+	// from the search.
+	EdgeProbability(r *RestrictParams, edge *ScoredEdge) float64
+
+	// EdgePenalty returns the weight of the edge. Dijkstra doesn't support
+	// negative edge weights, so the penalty must not be negative.
+	EdgePenalty(edge *ScoredEdge) int64
+
+	// Distance combines the summed up weight of a path with its success
+	// probability and the virtual cost of a failed attempt into the
+	// distance that path finding minimizes.
+	Distance(weight int64, probability, attemptCost float64) int64
+
+	// BreakTie reports whether the candidate path should replace the
+	// current path to a node if both have the same distance. It must
+	// return false if the candidate isn't strictly better, otherwise path
+	// finding may never terminate.
+	BreakTie(candidate, current *PathScore) bool
+}
+
+// MissionControlScorer is the default PathScorer. It takes the success
+// probabilities from the probability source of the path finding restrictions,
+// which is backed by mission control, and weighs edges by their fee and time
+// lock. Custom scorers can embed it to only override some of its methods.
+type MissionControlScorer struct{}
+
+// A compile-time check to ensure MissionControlScorer implements the
+// PathScorer interface.
+var _ PathScorer = (*MissionControlScorer)(nil)
+
+// EdgeProbability returns the success probability of the edge as estimated by
+// the probability source of the restrictions.
+//
+// NOTE: Part of the PathScorer interface.
+func (m *MissionControlScorer) EdgeProbability(r *RestrictParams,
+	edge *ScoredEdge) float64 {
+
+	return r.ProbabilitySource(
+		edge.FromNode, edge.ToNode, edge.Amt, edge.Capacity,
+	)
+}
+
+// EdgePenalty returns the fee of the edge plus a penalty for the time that the
+// amount is locked up.
+//
+// NOTE: Part of the PathScorer interface.
+func (m *MissionControlScorer) EdgePenalty(edge *ScoredEdge) int64 {
+	return edgeWeight(edge.Amt, edge.Fee, edge.TimeLockDelta)
+}
+
+// Distance returns the weight plus the attempt cost divided by the success
+// probability.
+//
+// NOTE: Part of the PathScorer interface.
+func (m *MissionControlScorer) Distance(weight int64, probability,
+	attemptCost float64) int64 {
+
+	return getProbabilityBasedDist(weight, probability, attemptCost)
+}
+
+// BreakTie prefers the path with the higher success probability.
+//
+// NOTE: Part of the PathScorer interface.
+func (m *MissionControlScorer) BreakTie(candidate, current *PathScore) bool {
+	return candidate.Probability > current.Probability
+}
@@ -0,0 +1,75 @@
+package routing
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+// avoidNodeScorer is a path scorer that adds a penalty to all edges that
+// start at a given node.
+type avoidNodeScorer struct {
+	MissionControlScorer
+
+	node    route.Vertex
+	penalty int64
+}
+
+// EdgePenalty adds the penalty to the weight of the edges of the node.
+func (a *avoidNodeScorer) EdgePenalty(edge *ScoredEdge) int64 {
+	weight := a.MissionControlScorer.EdgePenalty(edge)
+	if edge.FromNode == a.node {
+		weight += a.penalty
+	}
+
+	return weight
+}
+
+// TestPathScorer tests that path finding uses the configured path scorer.
+func TestPathScorer(t *testing.T) {
+	t.Parallel()
+
+	// Set up a test graph with two paths to the target. The path via a is
+	// cheaper than the one via b.
+	testChannels := []*testChannel{
+		symmetricTestChannel(
+			"source", "a", 100000, &testChannelPolicy{}, 3,
+		),
+		symmetricTestChannel(
+			"source", "b", 100000, &testChannelPolicy{}, 4,
+		),
+		symmetricTestChannel("a", "target", 100000, &testChannelPolicy{
+			Expiry:      144,
+			FeeBaseMsat: lnwire.NewMSatFromSatoshis(1),
+			MinHTLC:     1,
+		}, 1),
+		symmetricTestChannel("b", "target", 100000, &testChannelPolicy{
+			Expiry:      144,
+			FeeBaseMsat: lnwire.NewMSatFromSatoshis(10),
+			MinHTLC:     1,
+		}, 2),
+	}
+
+	ctx := newPathFindingTestContext(t, true, testChannels, "source")
+
+	paymentAmt := lnwire.NewMSatFromSatoshis(100)
+	target := ctx.keyFromAlias("target")
+
+	// Without a scorer, the mission control scorer is used and the cheaper
+	// path is found.
+	path, err := ctx.findPath(target, paymentAmt)
+	require.NoError(t, err)
+	ctx.assertPath(path, []uint64{3, 1})
+
+	// A scorer that penalizes a makes path finding choose the path via b.
+	ctx.pathFindingConfig.Scorer = &avoidNodeScorer{
+		node:    ctx.keyFromAlias("a"),
+		penalty: int64(lnwire.NewMSatFromSatoshis(100)),
+	}
+
+	path, err = ctx.findPath(target, paymentAmt)
+	require.NoError(t, err)
+	ctx.assertPath(path, []uint64{4, 2})
+}
@@ -0,0 +1,251 @@
+// Package pathbench provides a harness to benchmark and profile path finding
+// offline. It replays recorded payment requests against a snapshot of the
+// channel graph and reports latency and success metrics, so that changes to
+// path finding or its configuration can be evaluated before deployment.
+package pathbench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"runtime/pprof"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/zpay32"
+)
+
+// Config is a path finding configuration that is evaluated by the harness.
+type Config struct {
+	// Name identifies the configuration in the report.
+	Name string
+
+	// PathFinding contains the global path finding parameters.
+	PathFinding routing.PathFindingConfig
+
+	// Estimator is used to estimate the success probability of the
+	// channels in the graph. As there is no payment history to take into
+	// account, only its a priori assumptions are effective. If nil, the
+	// a priori estimator with its default configuration is used.
+	Estimator routing.Estimator
+
+	// TimePreference expresses the time preference for path finding,
+	// ranging from -1 (optimize for fees) to 1 (optimize for reliability).
+	TimePreference float64
+
+	// DefaultFinalCLTVDelta is the final cltv delta used for requests
+	// that don't specify one. If zero, the default assumed final cltv
+	// delta of invoices is used.
+	DefaultFinalCLTVDelta uint16
+
+	// CurrentHeight is the block height that is used to compute the
+	// absolute time locks of the routes.
+	CurrentHeight int32
+
+	// RequestTimeout is the maximum time a single path finding request
+	// may take. If zero, requests aren't time limited.
+	RequestTimeout time.Duration
+}
+
+// DefaultConfig returns a configuration with the default path finding
+// parameters of lnd.
+func DefaultConfig() *Config {
+	return &Config{
+		Name: "default",
+		PathFinding: routing.PathFindingConfig{
+			AttemptCost:    routing.DefaultAttemptCost,
+			AttemptCostPPM: routing.DefaultAttemptCostPPM,
+			MinProbability: routing.DefaultMinRouteProbability,
+		},
+	}
+}
+
+// Harness replays recorded payment requests against a graph snapshot and
+// reports path finding metrics, so the impact of changes to path finding and
+// its configuration can be evaluated offline.
+type Harness struct {
+	snapshot *Snapshot
+	source   route.Vertex
+	requests []*Request
+}
+
+// New creates a new harness that replays the given requests from the source
+// node against the snapshot.
+func New(snapshot *Snapshot, source route.Vertex,
+	requests []*Request) *Harness {
+
+	return &Harness{
+		snapshot: snapshot,
+		source:   source,
+		requests: requests,
+	}
+}
+
+// Run replays all requests with the given configuration and returns the
+// resulting report. An error is only returned if the configuration is invalid
+// or the context is canceled, a request that fails to find a route is
+// recorded in the report instead.
+func (h *Harness) Run(ctx context.Context, cfg *Config) (*Report, error) {
+	estimator := cfg.Estimator
+	if estimator == nil {
+		var err error
+		estimator, err = routing.NewAprioriEstimator(
+			routing.DefaultAprioriConfig(),
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.TimePreference < -1 || cfg.TimePreference > 1 {
+		return nil, errors.New("time preference out of range")
+	}
+
+	report := &Report{
+		Name:    cfg.Name,
+		Results: make([]*Result, 0, len(h.requests)),
+	}
+
+	probabilitySource := newProbabilitySource(estimator, h.source)
+	for _, req := range h.requests {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		result, err := h.replay(ctx, cfg, probabilitySource, req)
+		if err != nil {
+			return nil, err
+		}
+
+		report.Results = append(report.Results, result)
+	}
+
+	return report, nil
+}
+
+// RunAll replays all requests for each of the given configurations, so their
+// reports can be compared.
+func (h *Harness) RunAll(ctx context.Context,
+	cfgs ...*Config) ([]*Report, error) {
+
+	reports := make([]*Report, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		report, err := h.Run(ctx, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("unable to run config %v: %w",
+				cfg.Name, err)
+		}
+
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+// Profile replays all requests with the given configuration like Run, while
+// writing a CPU profile of the path finding to w. The profile can be
+// inspected with `go tool pprof`.
+func (h *Harness) Profile(ctx context.Context, cfg *Config,
+	w io.Writer) (*Report, error) {
+
+	if err := pprof.StartCPUProfile(w); err != nil {
+		return nil, fmt.Errorf("unable to start CPU profile: %w", err)
+	}
+	defer pprof.StopCPUProfile()
+
+	return h.Run(ctx, cfg)
+}
+
+// replay executes path finding for a single request.
+func (h *Harness) replay(ctx context.Context, cfg *Config,
+	probabilitySource func(route.Vertex, route.Vertex,
+		lnwire.MilliSatoshi, btcutil.Amount) float64,
+	req *Request) (*Result, error) {
+
+	feeLimit := req.FeeLimit
+	if feeLimit == 0 {
+		feeLimit = lnwire.MaxMilliSatoshi
+	}
+
+	cltvLimit := req.CltvLimit
+	if cltvLimit == 0 {
+		cltvLimit = math.MaxUint32
+	}
+
+	finalCLTVDelta := req.FinalCLTVDelta
+	if finalCLTVDelta == 0 {
+		finalCLTVDelta = cfg.DefaultFinalCLTVDelta
+	}
+	if finalCLTVDelta == 0 {
+		finalCLTVDelta = zpay32.DefaultAssumedFinalCLTVDelta
+	}
+
+	restrictions := &routing.RestrictParams{
+		ProbabilitySource: probabilitySource,
+		FeeLimit:          feeLimit,
+		CltvLimit:         cltvLimit,
+	}
+
+	target := req.Target
+	routeReq, err := routing.NewRouteRequest(
+		h.source, &target, req.Amount, cfg.TimePreference,
+		restrictions, nil, nil, nil, finalCLTVDelta,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	reqCtx := ctx
+	if cfg.RequestTimeout != 0 {
+		var cancel context.CancelFunc
+		reqCtx, cancel = context.WithTimeout(ctx, cfg.RequestTimeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	rt, probability, err := routing.FindRouteInGraph(
+		reqCtx, h.snapshot, &cfg.PathFinding, routeReq,
+		cfg.CurrentHeight,
+	)
+	latency := time.Since(start)
+
+	// If the parent context was canceled, the run is aborted rather than
+	// recording the request as failed.
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
+
+	return &Result{
+		Request:     req,
+		Route:       rt,
+		Probability: probability,
+		Latency:     latency,
+		Err:         err,
+	}, nil
+}
+
+// newProbabilitySource returns a probability source for path finding that is
+// backed by the given estimator, without any payment history.
+func newProbabilitySource(estimator routing.Estimator,
+	self route.Vertex) func(route.Vertex, route.Vertex,
+	lnwire.MilliSatoshi, btcutil.Amount) float64 {
+
+	now := time.Now()
+
+	return func(fromNode, toNode route.Vertex, amt lnwire.MilliSatoshi,
+		capacity btcutil.Amount) float64 {
+
+		if fromNode == self {
+			return estimator.LocalPairProbability(now, nil, toNode)
+		}
+
+		return estimator.PairProbability(
+			now, nil, toNode, amt, capacity,
+		)
+	}
+}
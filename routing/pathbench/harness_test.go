@@ -0,0 +1,169 @@
+package pathbench
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	alicePub = "02" +
+		"0000000000000000000000000000000000000000000000000000000000000a"
+	bobPub = "02" +
+		"0000000000000000000000000000000000000000000000000000000000000b"
+	carolPub = "02" +
+		"0000000000000000000000000000000000000000000000000000000000000c"
+	davePub = "02" +
+		"0000000000000000000000000000000000000000000000000000000000000d"
+)
+
+// testSnapshot is a graph snapshot in the format of `lncli describegraph` with
+// the channels alice -> bob -> carol. Dave is part of the graph, but doesn't
+// have any channels.
+var testSnapshot = `{
+	"nodes": [
+		{"pub_key": "` + alicePub + `", "alias": "alice",
+		 "features": {"9": {"name": "tlv-onion"}}},
+		{"pub_key": "` + bobPub + `", "alias": "bob", "features": {}},
+		{"pub_key": "` + carolPub + `", "alias": "carol",
+		 "features": {"9": {"name": "tlv-onion"}}},
+		{"pub_key": "` + davePub + `", "alias": "dave", "features": {}}
+	],
+	"edges": [
+		{
+			"channel_id": "1",
+			"node1_pub": "` + alicePub + `",
+			"node2_pub": "` + bobPub + `",
+			"capacity": "1000000",
+			"node1_policy": {
+				"time_lock_delta": 40,
+				"min_htlc": "1000",
+				"max_htlc_msat": "990000000",
+				"fee_base_msat": "0",
+				"fee_rate_milli_msat": "0",
+				"disabled": false
+			},
+			"node2_policy": {
+				"time_lock_delta": 40,
+				"min_htlc": "1000",
+				"max_htlc_msat": "990000000",
+				"fee_base_msat": "1000",
+				"fee_rate_milli_msat": "1",
+				"disabled": false
+			}
+		},
+		{
+			"channel_id": "2",
+			"node1_pub": "` + bobPub + `",
+			"node2_pub": "` + carolPub + `",
+			"capacity": "1000000",
+			"node1_policy": {
+				"time_lock_delta": 40,
+				"min_htlc": "1000",
+				"max_htlc_msat": "990000000",
+				"fee_base_msat": "1000",
+				"fee_rate_milli_msat": "0",
+				"disabled": false
+			},
+			"node2_policy": null
+		}
+	]
+}`
+
+// testRequests are the recorded requests replayed against testSnapshot.
+var testRequests = `
+{"target": "` + carolPub + `", "amt_msat": 100000}
+{"target": "` + carolPub + `", "amt_msat": 100000, "fee_limit_msat": 500}
+{"target": "` + davePub + `", "amt_msat": 100000, "final_cltv_delta": 40}
+`
+
+// TestHarnessRun tests that recorded requests are replayed against a graph
+// snapshot and that the results are reported.
+func TestHarnessRun(t *testing.T) {
+	t.Parallel()
+
+	snapshot, err := LoadSnapshot(strings.NewReader(testSnapshot))
+	require.NoError(t, err)
+	require.Equal(t, 4, snapshot.NumNodes())
+	require.Equal(t, 2, snapshot.NumChannels())
+
+	requests, err := LoadRequests(strings.NewReader(testRequests))
+	require.NoError(t, err)
+	require.Len(t, requests, 3)
+	require.EqualValues(t, 500, requests[1].FeeLimit)
+	require.EqualValues(t, 40, requests[2].FinalCLTVDelta)
+
+	alice, err := route.NewVertexFromStr(alicePub)
+	require.NoError(t, err)
+
+	harness := New(snapshot, alice, requests)
+
+	cfg := DefaultConfig()
+	cfg.CurrentHeight = 100
+	report, err := harness.Run(context.Background(), cfg)
+	require.NoError(t, err)
+	require.Len(t, report.Results, 3)
+
+	// The first request is routed through bob, who charges his base fee
+	// for the forward to carol.
+	result := report.Results[0]
+	require.NoError(t, result.Err)
+	require.Len(t, result.Route.Hops, 2)
+	require.Equal(t, lnwire.MilliSatoshi(1000), result.Route.TotalFees())
+	require.Positive(t, result.Probability)
+
+	// The second request can't afford bob's fee, and dave can't be reached
+	// at all.
+	require.Error(t, report.Results[1].Err)
+	require.Error(t, report.Results[2].Err)
+
+	require.Equal(t, 1, report.Successes())
+	require.InDelta(t, 1.0/3, report.SuccessRate(), 1e-9)
+	require.Equal(t, lnwire.MilliSatoshi(1000), report.MeanFee())
+	require.Equal(t, 2.0, report.MeanHops())
+	require.NotEmpty(t, report.Failures())
+	require.Contains(t, report.String(), cfg.Name)
+
+	// Running multiple configurations yields a report for each of them.
+	lowCost := DefaultConfig()
+	lowCost.Name = "low attempt cost"
+	lowCost.PathFinding.AttemptCost = 0
+
+	reports, err := harness.RunAll(context.Background(), cfg, lowCost)
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+	require.Equal(t, lowCost.Name, reports[1].Name)
+
+	// A canceled run is aborted instead of recording failures.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = harness.Run(ctx, cfg)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+// TestReportLatencyPercentile tests the latency percentiles of a report.
+func TestReportLatencyPercentile(t *testing.T) {
+	t.Parallel()
+
+	report := &Report{}
+	require.Zero(t, report.LatencyPercentile(50))
+	require.Zero(t, report.MeanLatency())
+
+	for i := 10; i > 0; i-- {
+		report.Results = append(report.Results, &Result{
+			Latency: time.Duration(i) * time.Millisecond,
+		})
+	}
+
+	require.Equal(t, time.Millisecond, report.LatencyPercentile(0))
+	require.Equal(t, 6*time.Millisecond, report.LatencyPercentile(50))
+	require.Equal(t, 10*time.Millisecond, report.LatencyPercentile(90))
+	require.Equal(t, 10*time.Millisecond, report.LatencyPercentile(100))
+	require.Equal(t, 5500*time.Microsecond, report.MeanLatency())
+}
@@ -0,0 +1,192 @@
+package pathbench
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+// Result is the outcome of path finding for a single request.
+type Result struct {
+	// Request is the replayed request.
+	Request *Request
+
+	// Route is the route that was found, or nil if path finding failed.
+	Route *route.Route
+
+	// Probability is the estimated success probability of the route.
+	Probability float64
+
+	// Latency is the time path finding took.
+	Latency time.Duration
+
+	// Err is the reason path finding failed, or nil if a route was found.
+	Err error
+}
+
+// Report contains the results of replaying the requests with a single
+// configuration.
+type Report struct {
+	// Name is the name of the configuration the report belongs to.
+	Name string
+
+	// Results contains the result of each request, in the order the
+	// requests were replayed.
+	Results []*Result
+}
+
+// Successes returns the number of requests for which a route was found.
+func (r *Report) Successes() int {
+	var successes int
+	for _, result := range r.Results {
+		if result.Err == nil {
+			successes++
+		}
+	}
+
+	return successes
+}
+
+// SuccessRate returns the fraction of requests for which a route was found.
+func (r *Report) SuccessRate() float64 {
+	if len(r.Results) == 0 {
+		return 0
+	}
+
+	return float64(r.Successes()) / float64(len(r.Results))
+}
+
+// Failures returns the number of failed requests, keyed by failure reason.
+func (r *Report) Failures() map[string]int {
+	failures := make(map[string]int)
+	for _, result := range r.Results {
+		if result.Err != nil {
+			failures[result.Err.Error()]++
+		}
+	}
+
+	return failures
+}
+
+// LatencyPercentile returns the path finding latency at the given
+// percentile, in the range [0, 100], over all requests.
+func (r *Report) LatencyPercentile(percentile float64) time.Duration {
+	if len(r.Results) == 0 {
+		return 0
+	}
+
+	latencies := make([]time.Duration, 0, len(r.Results))
+	for _, result := range r.Results {
+		latencies = append(latencies, result.Latency)
+	}
+	sort.Slice(latencies, func(i, j int) bool {
+		return latencies[i] < latencies[j]
+	})
+
+	// Use the nearest-rank method to select the percentile.
+	rank := int(percentile / 100 * float64(len(latencies)))
+	if rank >= len(latencies) {
+		rank = len(latencies) - 1
+	}
+	if rank < 0 {
+		rank = 0
+	}
+
+	return latencies[rank]
+}
+
+// MeanLatency returns the mean path finding latency over all requests.
+func (r *Report) MeanLatency() time.Duration {
+	if len(r.Results) == 0 {
+		return 0
+	}
+
+	var total time.Duration
+	for _, result := range r.Results {
+		total += result.Latency
+	}
+
+	return total / time.Duration(len(r.Results))
+}
+
+// MeanFee returns the mean fee of the routes that were found.
+func (r *Report) MeanFee() lnwire.MilliSatoshi {
+	successes := r.Successes()
+	if successes == 0 {
+		return 0
+	}
+
+	var total lnwire.MilliSatoshi
+	for _, result := range r.Results {
+		if result.Route != nil {
+			total += result.Route.TotalFees()
+		}
+	}
+
+	return total / lnwire.MilliSatoshi(successes)
+}
+
+// MeanHops returns the mean number of hops of the routes that were found.
+func (r *Report) MeanHops() float64 {
+	successes := r.Successes()
+	if successes == 0 {
+		return 0
+	}
+
+	var total int
+	for _, result := range r.Results {
+		if result.Route != nil {
+			total += len(result.Route.Hops)
+		}
+	}
+
+	return float64(total) / float64(successes)
+}
+
+// MeanProbability returns the mean estimated success probability of the routes
+// that were found.
+func (r *Report) MeanProbability() float64 {
+	successes := r.Successes()
+	if successes == 0 {
+		return 0
+	}
+
+	var total float64
+	for _, result := range r.Results {
+		if result.Err == nil {
+			total += result.Probability
+		}
+	}
+
+	return total / float64(successes)
+}
+
+// String returns a human-readable summary of the report.
+func (r *Report) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%v: requests=%d, success_rate=%.2f%%, "+
+		"latency(mean=%v, p50=%v, p90=%v, p99=%v, max=%v), "+
+		"mean_fee=%v, mean_hops=%.2f, mean_probability=%.4f",
+		r.Name, len(r.Results), r.SuccessRate()*100, r.MeanLatency(),
+		r.LatencyPercentile(50), r.LatencyPercentile(90),
+		r.LatencyPercentile(99), r.LatencyPercentile(100),
+		r.MeanFee(), r.MeanHops(), r.MeanProbability())
+
+	failures := r.Failures()
+	reasons := make([]string, 0, len(failures))
+	for reason := range failures {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+
+	for _, reason := range reasons {
+		fmt.Fprintf(&b, "\n\tfailure %q: %d", reason, failures[reason])
+	}
+
+	return b.String()
+}
@@ -0,0 +1,95 @@
+package pathbench
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+// Request is a recorded payment request that is replayed against a graph
+// snapshot.
+type Request struct {
+	// Target is the destination of the payment.
+	Target route.Vertex
+
+	// Amount is the amount to be delivered to the target.
+	Amount lnwire.MilliSatoshi
+
+	// FinalCLTVDelta is the cltv delta of the final hop. If zero, the
+	// default of the harness configuration is used.
+	FinalCLTVDelta uint16
+
+	// FeeLimit is the maximum fee that may be paid for the route. If zero,
+	// the fee isn't limited.
+	FeeLimit lnwire.MilliSatoshi
+
+	// CltvLimit is the maximum time lock of the route, excluding the final
+	// cltv delta. If zero, the time lock isn't limited.
+	CltvLimit uint32
+}
+
+// jsonRequest is the JSON representation of a recorded payment request.
+type jsonRequest struct {
+	Target         string `json:"target"`
+	AmtMsat        uint64 `json:"amt_msat"`
+	FinalCLTVDelta uint16 `json:"final_cltv_delta"`
+	FeeLimitMsat   uint64 `json:"fee_limit_msat"`
+	CltvLimit      uint32 `json:"cltv_limit"`
+}
+
+// LoadRequestsFile loads the recorded payment requests from the file at the
+// given path.
+func LoadRequestsFile(path string) ([]*Request, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return LoadRequests(f)
+}
+
+// LoadRequests loads recorded payment requests from the given reader. The
+// requests are expected to be a stream of JSON objects, one per request, such
+// that new requests can simply be appended to a recording.
+func LoadRequests(r io.Reader) ([]*Request, error) {
+	var (
+		decoder  = json.NewDecoder(r)
+		requests []*Request
+	)
+	for {
+		var req jsonRequest
+		err := decoder.Decode(&req)
+		if errors.Is(err, io.EOF) {
+			return requests, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode request "+
+				"%d: %w", len(requests), err)
+		}
+
+		target, err := route.NewVertexFromStr(req.Target)
+		if err != nil {
+			return nil, fmt.Errorf("invalid target for request "+
+				"%d: %w", len(requests), err)
+		}
+
+		if req.AmtMsat == 0 {
+			return nil, fmt.Errorf("request %d has zero amount",
+				len(requests))
+		}
+
+		requests = append(requests, &Request{
+			Target:         target,
+			Amount:         lnwire.MilliSatoshi(req.AmtMsat),
+			FinalCLTVDelta: req.FinalCLTVDelta,
+			FeeLimit:       lnwire.MilliSatoshi(req.FeeLimitMsat),
+			CltvLimit:      req.CltvLimit,
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package pathbench
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+// jsonGraph is the JSON representation of a graph snapshot. It matches the
+// output of `lncli describegraph`, so a snapshot of a node's view of the
+// network can be taken without any additional tooling.
+type jsonGraph struct {
+	Nodes []jsonNode `json:"nodes"`
+	Edges []jsonEdge `json:"edges"`
+}
+
+// jsonNode is the JSON representation of a node within a graph snapshot.
+type jsonNode struct {
+	PubKey   string              `json:"pub_key"`
+	Alias    string              `json:"alias"`
+	Features map[string]struct{} `json:"features"`
+}
+
+// jsonEdge is the JSON representation of a channel within a graph snapshot.
+type jsonEdge struct {
+	ChannelID   uint64      `json:"channel_id,string"`
+	Node1Pub    string      `json:"node1_pub"`
+	Node2Pub    string      `json:"node2_pub"`
+	Capacity    int64       `json:"capacity,string"`
+	Node1Policy *jsonPolicy `json:"node1_policy"`
+	Node2Policy *jsonPolicy `json:"node2_policy"`
+}
+
+// jsonPolicy is the JSON representation of a directed channel policy within a
+// graph snapshot.
+type jsonPolicy struct {
+	TimeLockDelta           uint16 `json:"time_lock_delta"`
+	MinHtlc                 int64  `json:"min_htlc,string"`
+	MaxHtlcMsat             uint64 `json:"max_htlc_msat,string"`
+	FeeBaseMsat             int64  `json:"fee_base_msat,string"`
+	FeeRateMilliMsat        int64  `json:"fee_rate_milli_msat,string"`
+	Disabled                bool   `json:"disabled"`
+	InboundFeeBaseMsat      int32  `json:"inbound_fee_base_msat"`
+	InboundFeeRateMilliMsat int32  `json:"inbound_fee_rate_milli_msat"`
+}
+
+// Snapshot is an in-memory, read-only view of the channel graph that can be
+// used for path finding.
+type Snapshot struct {
+	cache *channeldb.GraphCache
+
+	numNodes    int
+	numChannels int
+}
+
+// A compile-time check to ensure Snapshot implements the routing.Graph
+// interface.
+var _ routing.Graph = (*Snapshot)(nil)
+
+// LoadSnapshotFile loads a graph snapshot from the JSON file at the given
+// path.
+func LoadSnapshotFile(path string) (*Snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return LoadSnapshot(f)
+}
+
+// LoadSnapshot loads a graph snapshot from the given reader. The snapshot is
+// expected to be encoded in the JSON format produced by `lncli describegraph`.
+func LoadSnapshot(r io.Reader) (*Snapshot, error) {
+	var graph jsonGraph
+	if err := json.NewDecoder(r).Decode(&graph); err != nil {
+		return nil, fmt.Errorf("unable to decode graph snapshot: %w",
+			err)
+	}
+
+	s := &Snapshot{
+		cache:       channeldb.NewGraphCache(len(graph.Nodes)),
+		numNodes:    len(graph.Nodes),
+		numChannels: len(graph.Edges),
+	}
+
+	for _, node := range graph.Nodes {
+		cacheNode, err := newSnapshotNode(node)
+		if err != nil {
+			return nil, err
+		}
+
+		s.cache.AddNodeFeatures(cacheNode)
+	}
+
+	for _, edge := range graph.Edges {
+		info, policy1, policy2, err := parseEdge(edge)
+		if err != nil {
+			return nil, err
+		}
+
+		s.cache.AddChannel(info, policy1, policy2)
+	}
+
+	return s, nil
+}
+
+// NumNodes returns the number of nodes in the snapshot.
+func (s *Snapshot) NumNodes() int {
+	return s.numNodes
+}
+
+// NumChannels returns the number of channels in the snapshot.
+func (s *Snapshot) NumChannels() int {
+	return s.numChannels
+}
+
+// ForEachNodeChannel calls the callback for every channel of the given node.
+//
+// NOTE: Part of the routing.Graph interface.
+func (s *Snapshot) ForEachNodeChannel(nodePub route.Vertex,
+	cb func(channel *channeldb.DirectedChannel) error) error {
+
+	return s.cache.ForEachChannel(nodePub, cb)
+}
+
+// FetchNodeFeatures returns the features of the given node.
+//
+// NOTE: Part of the routing.Graph interface.
+func (s *Snapshot) FetchNodeFeatures(nodePub route.Vertex) (
+	*lnwire.FeatureVector, error) {
+
+	return s.cache.GetFeatures(nodePub), nil
+}
+
+// snapshotNode is a node of a graph snapshot that is added to the graph cache.
+type snapshotNode struct {
+	pubKey   route.Vertex
+	features *lnwire.FeatureVector
+}
+
+// A compile-time check to ensure snapshotNode implements the
+// channeldb.GraphCacheNode interface.
+var _ channeldb.GraphCacheNode = (*snapshotNode)(nil)
+
+// newSnapshotNode parses the given JSON node.
+func newSnapshotNode(node jsonNode) (*snapshotNode, error) {
+	pubKey, err := route.NewVertexFromStr(node.PubKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pubkey for node %v: %w",
+			node.Alias, err)
+	}
+
+	rawFeatures := lnwire.NewRawFeatureVector()
+	for bitStr := range node.Features {
+		bit, err := strconv.ParseUint(bitStr, 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("invalid feature bit %v for "+
+				"node %v: %w", bitStr, pubKey, err)
+		}
+
+		rawFeatures.Set(lnwire.FeatureBit(bit))
+	}
+
+	return &snapshotNode{
+		pubKey:   pubKey,
+		features: lnwire.NewFeatureVector(rawFeatures, lnwire.Features),
+	}, nil
+}
+
+// PubKey is the node's public identity key.
+//
+// NOTE: Part of the channeldb.GraphCacheNode interface.
+func (n *snapshotNode) PubKey() route.Vertex {
+	return n.pubKey
+}
+
+// Features returns the node's p2p features.
+//
+// NOTE: Part of the channeldb.GraphCacheNode interface.
+func (n *snapshotNode) Features() *lnwire.FeatureVector {
+	return n.features
+}
+
+// ForEachChannel is a no-op, the channels of a snapshot are added to the
+// cache separately.
+//
+// NOTE: Part of the channeldb.GraphCacheNode interface.
+func (n *snapshotNode) ForEachChannel(kvdb.RTx,
+	func(kvdb.RTx, *models.ChannelEdgeInfo, *models.ChannelEdgePolicy,
+		*models.ChannelEdgePolicy) error) error {
+
+	return nil
+}
+
+// parseEdge parses the given JSON edge into the channel info and the policies
+// of both directions. A policy is nil if it wasn't announced.
+func parseEdge(edge jsonEdge) (*models.ChannelEdgeInfo,
+	*models.ChannelEdgePolicy, *models.ChannelEdgePolicy, error) {
+
+	node1, err := route.NewVertexFromStr(edge.Node1Pub)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid node1 pubkey for "+
+			"channel %v: %w", edge.ChannelID, err)
+	}
+
+	node2, err := route.NewVertexFromStr(edge.Node2Pub)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid node2 pubkey for "+
+			"channel %v: %w", edge.ChannelID, err)
+	}
+
+	info := &models.ChannelEdgeInfo{
+		ChannelID:     edge.ChannelID,
+		NodeKey1Bytes: node1,
+		NodeKey2Bytes: node2,
+		Capacity:      btcutil.Amount(edge.Capacity),
+	}
+
+	policy1, err := parsePolicy(edge.ChannelID, edge.Node1Policy, node2, 0)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	policy2, err := parsePolicy(
+		edge.ChannelID, edge.Node2Policy, node1,
+		lnwire.ChanUpdateDirection,
+	)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return info, policy1, policy2, nil
+}
+
+// parsePolicy parses the given JSON policy of the channel towards toNode.
+func parsePolicy(chanID uint64, policy *jsonPolicy, toNode route.Vertex,
+	direction lnwire.ChanUpdateChanFlags) (*models.ChannelEdgePolicy,
+	error) {
+
+	if policy == nil {
+		return nil, nil
+	}
+
+	channelFlags := direction
+	if policy.Disabled {
+		channelFlags |= lnwire.ChanUpdateDisabled
+	}
+
+	var messageFlags lnwire.ChanUpdateMsgFlags
+	if policy.MaxHtlcMsat != 0 {
+		messageFlags |= lnwire.ChanUpdateRequiredMaxHtlc
+	}
+
+	var extraData lnwire.ExtraOpaqueData
+	if policy.InboundFeeBaseMsat != 0 ||
+		policy.InboundFeeRateMilliMsat != 0 {
+
+		inboundFee := lnwire.Fee{
+			BaseFee: policy.InboundFeeBaseMsat,
+			FeeRate: policy.InboundFeeRateMilliMsat,
+		}
+		if err := extraData.PackRecords(&inboundFee); err != nil {
+			return nil, fmt.Errorf("unable to encode inbound fee "+
+				"for channel %v: %w", chanID, err)
+		}
+	}
+
+	return &models.ChannelEdgePolicy{
+		ChannelID:     chanID,
+		MessageFlags:  messageFlags,
+		ChannelFlags:  channelFlags,
+		TimeLockDelta: policy.TimeLockDelta,
+		MinHTLC:       lnwire.MilliSatoshi(policy.MinHtlc),
+		MaxHTLC:       lnwire.MilliSatoshi(policy.MaxHtlcMsat),
+		FeeBaseMSat:   lnwire.MilliSatoshi(policy.FeeBaseMsat),
+		FeeProportionalMillionths: lnwire.MilliSatoshi(
+			policy.FeeRateMilliMsat,
+		),
+		ToNode:          toNode,
+		ExtraOpaqueData: extraData,
+	}, nil
+}
@@ -3,6 +3,7 @@ package routing
 import (
 	"bytes"
 	"container/heap"
+	"context"
 	"errors"
 	"fmt"
 	"math"
@@ -47,13 +48,17 @@ const (
 	// This is a high number, which expresses that a hop hint channel should
 	// be able to route payments.
 	fakeHopHintCapacity = btcutil.Amount(10 * btcutil.SatoshiPerBitcoin)
+
+	// pathFindingCancelCheckInterval is the number of nodes visited during
+	// path finding after which we check whether the search was canceled.
+	pathFindingCancelCheckInterval = 1000
 )
 
 // pathFinder defines the interface of a path finding algorithm.
-type pathFinder = func(g *graphParams, r *RestrictParams,
-	cfg *PathFindingConfig, self, source, target route.Vertex,
-	amt lnwire.MilliSatoshi, timePref float64, finalHtlcExpiry int32) (
-	[]*unifiedEdge, float64, error)
+type pathFinder = func(ctx context.Context, g *graphParams,
+	r *RestrictParams, cfg *PathFindingConfig, self, source,
+	target route.Vertex, amt lnwire.MilliSatoshi, timePref float64,
+	finalHtlcExpiry int32) ([]*unifiedEdge, float64, error)
 
 var (
 	// DefaultEstimator is the default estimator used for computing
@@ -486,6 +491,10 @@ type PathFindingConfig struct {
 	// MinProbability defines the minimum success probability of the
 	// returned route.
 	MinProbability float64
+
+	// Scorer determines how edges are valued during path finding. If nil,
+	// the MissionControlScorer is used.
+	Scorer PathScorer
 }
 
 // getOutgoingBalance returns the maximum available balance in any of the
@@ -557,11 +566,12 @@ func getOutgoingBalance(node route.Vertex, outgoingChans map[uint64]struct{},
 // source. The search is performed backwards from destination node back to
 // source. This is to properly accumulate fees that need to be paid along the
 // path and accurately check the amount to forward at every node against the
-// available bandwidth.
-func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
-	self, source, target route.Vertex, amt lnwire.MilliSatoshi,
-	timePref float64, finalHtlcExpiry int32) ([]*unifiedEdge, float64,
-	error) {
+// available bandwidth. If the passed context is canceled while the search is
+// in progress, the search is aborted and the context's error is returned.
+func findPath(ctx context.Context, g *graphParams, r *RestrictParams,
+	cfg *PathFindingConfig, self, source, target route.Vertex,
+	amt lnwire.MilliSatoshi, timePref float64, finalHtlcExpiry int32) (
+	[]*unifiedEdge, float64, error) {
 
 	// Pathfinding can be a significant portion of the total payment
 	// latency, especially on low-powered devices. Log several metrics to
@@ -708,28 +718,22 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 	// if the cltv limit is MaxUint32.
 	absoluteCltvLimit := uint64(r.CltvLimit) + uint64(finalHtlcExpiry)
 
-	// Calculate the default attempt cost as configured globally.
-	defaultAttemptCost := float64(
-		cfg.AttemptCost +
-			amt*lnwire.MilliSatoshi(cfg.AttemptCostPPM)/1000000,
-	)
-
 	// Validate time preference value.
 	if math.Abs(timePref) > 1 {
 		return nil, 0, fmt.Errorf("time preference %v out of range "+
 			"[-1, 1]", timePref)
 	}
 
-	// Scale to avoid the extremes -1 and 1 which run into infinity issues.
-	timePref *= 0.9
-
-	// Apply time preference. At 0, the default attempt cost will
-	// be used.
-	absoluteAttemptCost := defaultAttemptCost * (1/(0.5-timePref/2) - 1)
+	absoluteAttemptCost := getAbsoluteAttemptCost(cfg, amt, timePref)
 
 	log.Debugf("Pathfinding absolute attempt cost: %v sats",
 		absoluteAttemptCost/1000)
 
+	var scorer PathScorer = &MissionControlScorer{}
+	if cfg.Scorer != nil {
+		scorer = cfg.Scorer
+	}
+
 	// processEdge is a helper closure that will be used to make sure edges
 	// satisfy our specific requirements.
 	processEdge := func(fromVertex route.Vertex,
@@ -777,11 +781,15 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 			return
 		}
 
+		scoredEdge := &ScoredEdge{
+			FromNode: fromVertex,
+			ToNode:   toNodeDist.node,
+			Amt:      amountToSend,
+			Capacity: edge.capacity,
+		}
+
 		// Request the success probability for this edge.
-		edgeProbability := r.ProbabilitySource(
-			fromVertex, toNodeDist.node, amountToSend,
-			edge.capacity,
-		)
+		edgeProbability := scorer.EdgeProbability(r, scoredEdge)
 
 		log.Trace(lnutils.NewLogClosure(func() string {
 			return fmt.Sprintf("path finding probability: fromnode=%v,"+
@@ -859,7 +867,9 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 		// weight composed of the fee that this node will charge and
 		// the amount that will be locked for timeLockDelta blocks in
 		// the HTLC that is handed out to fromVertex.
-		weight := edgeWeight(amountToSend, fee, timeLockDelta)
+		scoredEdge.Fee = fee
+		scoredEdge.TimeLockDelta = timeLockDelta
+		weight := scorer.EdgePenalty(scoredEdge)
 
 		// Compute the tentative weight to this new channel/edge
 		// which is the weight from our toNode to the target node
@@ -871,9 +881,8 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 		// is to prevent a highly negative fee from cancelling out the
 		// extra factor. We don't want an always-failing node to attract
 		// traffic using a highly negative fee and escape penalization.
-		tempDist := getProbabilityBasedDist(
-			tempWeight, probability,
-			absoluteAttemptCost,
+		tempDist := scorer.Distance(
+			tempWeight, probability, absoluteAttemptCost,
 		)
 
 		// If there is already a best route stored, compare this
@@ -886,14 +895,24 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 				return
 			}
 
-			// If the route is equally good and the probability
-			// isn't better, skip this route. It is important to
-			// also return if both cost and probability are equal,
-			// because otherwise the algorithm could run into an
-			// endless loop.
-			probNotBetter := probability <= current.probability
-			if tempDist == current.dist && probNotBetter {
-				return
+			// If the route is equally good, let the scorer break
+			// the tie. It is important to also return if both
+			// routes are equal in every respect, because otherwise
+			// the algorithm could run into an endless loop.
+			if tempDist == current.dist {
+				candidate := &PathScore{
+					Distance:    tempDist,
+					Weight:      tempWeight,
+					Probability: probability,
+				}
+				best := &PathScore{
+					Distance:    current.dist,
+					Weight:      current.weight,
+					Probability: current.probability,
+				}
+				if !scorer.BreakTie(candidate, best) {
+					return
+				}
 			}
 		}
 
@@ -999,6 +1018,16 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 	for {
 		nodesVisited++
 
+		// Periodically check whether the caller is still interested
+		// in the result, so a canceled request doesn't keep traversing
+		// a large graph. We also check on the very first iteration to
+		// bail out early if the request was canceled up front.
+		if nodesVisited%pathFindingCancelCheckInterval == 1 &&
+			ctx.Err() != nil {
+
+			return nil, 0, ctx.Err()
+		}
+
 		pivot := partialPath.node
 		isExitHop := partialPath.nextHop == nil
 
@@ -1405,6 +1434,26 @@ func getProbabilityBasedDist(weight int64, probability float64,
 	return int64(dist)
 }
 
+// getAbsoluteAttemptCost returns the virtual cost of a failed payment attempt
+// for the given amount, adjusted by the time preference. The time preference
+// must be in the range [-1, 1].
+func getAbsoluteAttemptCost(cfg *PathFindingConfig, amt lnwire.MilliSatoshi,
+	timePref float64) float64 {
+
+	// Calculate the default attempt cost as configured globally.
+	defaultAttemptCost := float64(
+		cfg.AttemptCost +
+			amt*lnwire.MilliSatoshi(cfg.AttemptCostPPM)/1000000,
+	)
+
+	// Scale to avoid the extremes -1 and 1 which run into infinity issues.
+	timePref *= 0.9
+
+	// Apply time preference. At 0, the default attempt cost will
+	// be used.
+	return defaultAttemptCost * (1/(0.5-timePref/2) - 1)
+}
+
 // lastHopPayloadSize calculates the payload size of the final hop in a route.
 // It depends on the tlv types which are present and also whether the hop is
 // part of a blinded route or not.
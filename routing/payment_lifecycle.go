@@ -259,7 +259,7 @@ lifecycle:
 		}
 
 		// Now request a route to be used to create our HTLC attempt.
-		rt, err := p.requestRoute(ps)
+		rt, err := p.requestRoute(ctx, ps)
 		if err != nil {
 			return exitWithErr(err)
 		}
@@ -290,16 +290,36 @@ lifecycle:
 			return exitWithErr(err)
 		}
 
+		// Reserve the outbound liquidity the shard uses on our
+		// channel, so the next shards aren't routed over it based on
+		// its stale bandwidth.
+		p.router.cfg.Reservations.Reserve(
+			attempt.AttemptID,
+			lnwire.NewShortChanIDFromInt(rt.Hops[0].ChannelID),
+			rt.FirstHopAmount.Val.Int(),
+		)
+
 		// Once the attempt is created, send it to the htlcswitch.
 		result, err := p.sendAttempt(attempt)
 		if err != nil {
+			p.router.cfg.Reservations.Release(attempt.AttemptID)
 			return exitWithErr(err)
 		}
 
-		// Now that the shard was successfully sent, launch a go
-		// routine that will handle its result when its back.
-		if result.err == nil {
-			p.resultCollector(attempt)
+		// If the shard failed to be sent, its liquidity is released
+		// right away. Otherwise, launch a go routine that will handle
+		// its result when its back.
+		if result.err != nil {
+			p.router.cfg.Reservations.Release(attempt.AttemptID)
+			continue lifecycle
+		}
+
+		p.resultCollector(attempt)
+
+		// Give the link a moment to pick up the shard before we
+		// launch the next one.
+		if err := p.paceShard(); err != nil {
+			return exitWithErr(err)
 		}
 	}
 
@@ -323,6 +343,26 @@ lifecycle:
 	return [32]byte{}, nil, *failure
 }
 
+// paceShard waits for the configured shard pacing interval to pass, unless
+// the payment lifecycle or router is exiting.
+func (p *paymentLifecycle) paceShard() error {
+	interval := p.router.cfg.ShardPacing
+	if interval <= 0 {
+		return nil
+	}
+
+	select {
+	case <-p.router.cfg.Clock.TickAfter(interval):
+		return nil
+
+	case <-p.quit:
+		return ErrPaymentLifecycleExiting
+
+	case <-p.router.quit:
+		return ErrRouterShuttingDown
+	}
+}
+
 // checkContext checks whether the payment context has been canceled.
 // Cancellation occurs manually or if the context times out.
 func (p *paymentLifecycle) checkContext(ctx context.Context) error {
@@ -365,15 +405,15 @@ func (p *paymentLifecycle) checkContext(ctx context.Context) error {
 }
 
 // requestRoute is responsible for finding a route to be used to create an HTLC
-// attempt.
-func (p *paymentLifecycle) requestRoute(
+// attempt. Path finding is aborted if the payment's context is canceled.
+func (p *paymentLifecycle) requestRoute(ctx context.Context,
 	ps *channeldb.MPPaymentState) (*route.Route, error) {
 
 	remainingFees := p.calcFeeBudget(ps.FeesPaid)
 
 	// Query our payment session to construct a route.
 	rt, err := p.paySession.RequestRoute(
-		ps.RemainingAmt, remainingFees,
+		ctx, ps.RemainingAmt, remainingFees,
 		uint32(ps.NumAttemptsInFlight), uint32(p.currentHeight),
 		p.firstHopCustomRecords,
 	)
@@ -383,6 +423,14 @@ func (p *paymentLifecycle) requestRoute(
 		return rt, nil
 	}
 
+	// If path finding was aborted because the payment's context was
+	// canceled, we fail the payment in the same way as if the cancellation
+	// was noticed at the start of the lifecycle loop. No route is returned
+	// so that the results of inflight HTLCs are still collected.
+	if ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+		return nil, p.checkContext(ctx)
+	}
+
 	// Otherwise we need to handle the error.
 	log.Warnf("Failed to find route for payment %v: %v", p.identifier, err)
 
@@ -443,6 +491,11 @@ func (p *paymentLifecycle) collectResultAsync(attempt *channeldb.HTLCAttempt) {
 	go func() {
 		// Block until the result is available.
 		_, err := p.collectResult(attempt)
+
+		// Now that the shard either settled or failed, the liquidity
+		// reserved for it is released.
+		p.router.cfg.Reservations.Release(attempt.AttemptID)
+
 		if err != nil {
 			log.Errorf("Error collecting result for attempt %v "+
 				"in payment %v: %v", attempt.AttemptID,
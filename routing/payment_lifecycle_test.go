@@ -383,7 +383,7 @@ func TestRequestRouteSucceed(t *testing.T) {
 		mock.Anything,
 	).Return(dummyRoute, nil)
 
-	result, err := p.requestRoute(ps)
+	result, err := p.requestRoute(context.Background(), ps)
 	require.NoError(t, err, "expect no error")
 	require.Equal(t, dummyRoute, result, "returned route not matched")
 
@@ -420,7 +420,7 @@ func TestRequestRouteHandleCriticalErr(t *testing.T) {
 		mock.Anything,
 	).Return(nil, errDummy)
 
-	result, err := p.requestRoute(ps)
+	result, err := p.requestRoute(context.Background(), ps)
 
 	// Expect an error is returned since it's critical.
 	require.ErrorIs(t, err, errDummy, "error not matched")
@@ -460,13 +460,55 @@ func TestRequestRouteHandleNoRouteErr(t *testing.T) {
 		p.identifier, channeldb.FailureReasonNoRoute,
 	).Return(nil).Once()
 
-	result, err := p.requestRoute(ps)
+	result, err := p.requestRoute(context.Background(), ps)
 
 	// Expect no error is returned since it's not critical.
 	require.NoError(t, err, "expected no error")
 	require.Nil(t, result, "expected no route returned")
 }
 
+// TestRequestRouteHandleCanceledContext checks that `requestRoute` fails the
+// payment when path finding is aborted because the payment's context was
+// canceled.
+func TestRequestRouteHandleCanceledContext(t *testing.T) {
+	t.Parallel()
+
+	// Create a paymentLifecycle with mockers.
+	p, m := newTestPaymentLifecycle(t)
+
+	// Create a dummy payment state.
+	ps := &channeldb.MPPaymentState{
+		NumAttemptsInFlight: 1,
+		RemainingAmt:        1,
+		FeesPaid:            100,
+	}
+
+	// Mock remainingFees to be 1.
+	p.feeLimit = ps.FeesPaid + 1
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Mock the paySession's `RequestRoute` method to return the error of
+	// the canceled context.
+	m.paySession.On("RequestRoute",
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything,
+	).Return(nil, context.Canceled)
+
+	// The payment should be failed with reason canceled.
+	m.control.On("FailPayment",
+		p.identifier, channeldb.FailureReasonCanceled,
+	).Return(nil).Once()
+
+	result, err := p.requestRoute(ctx, ps)
+
+	// Expect no error is returned so the inflight HTLCs are still
+	// collected.
+	require.NoError(t, err, "expected no error")
+	require.Nil(t, result, "expected no route returned")
+}
+
 // TestRequestRouteFailPaymentError checks that `requestRoute` returns the
 // error from calling `FailPayment`.
 func TestRequestRouteFailPaymentError(t *testing.T) {
@@ -503,7 +545,7 @@ func TestRequestRouteFailPaymentError(t *testing.T) {
 		mock.Anything,
 	).Return(nil, errNoTlvPayload)
 
-	result, err := p.requestRoute(ps)
+	result, err := p.requestRoute(context.Background(), ps)
 
 	// Expect an error is returned.
 	require.ErrorIs(t, err, errDummy, "error not matched")
@@ -0,0 +1,773 @@
+package routing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/graph"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/ticker"
+)
+
+const (
+	// DefaultPaymentRetryInterval is the default base delay after which a
+	// payment that failed with a retryable failure reason is attempted
+	// again. The delay is doubled for every subsequent retry.
+	DefaultPaymentRetryInterval = 5 * time.Minute
+
+	// DefaultMaxPaymentRetryInterval is the default upper bound for the
+	// delay between two retries of the same payment.
+	DefaultMaxPaymentRetryInterval = 6 * time.Hour
+
+	// DefaultMaxPaymentRetries is the default number of times a payment is
+	// retried before it is removed from the retry queue.
+	DefaultMaxPaymentRetries = 10
+
+	// DefaultPaymentRetryCheckInterval is the default interval at which
+	// the retry queue checks for payments that are due to be retried.
+	DefaultPaymentRetryCheckInterval = 30 * time.Second
+
+	// maxScheduledPaymentRequestSize is the maximum size of the opaque
+	// payment request that we'll read from disk.
+	maxScheduledPaymentRequestSize = 65536
+
+	// noFailureReason is the database encoding of a missing failure
+	// reason.
+	noFailureReason = 0xff
+)
+
+var (
+	// paymentRetryQueueBucket is the top level bucket that stores all the
+	// payments scheduled for a retry, keyed by their payment hash.
+	paymentRetryQueueBucket = []byte("payment-retry-queue")
+
+	// ErrPaymentRetryQueueNotStarted is returned when a payment is
+	// scheduled before the retry queue has been started.
+	ErrPaymentRetryQueueNotStarted = errors.New("payment retry queue not " +
+		"started")
+)
+
+// ScheduledPayment is a payment that is tracked by the PaymentRetryQueue. If
+// the payment fails with a retryable failure reason, it is sent again at a
+// later point in time.
+type ScheduledPayment struct {
+	// PaymentHash is the hash of the tracked payment.
+	PaymentHash lntypes.Hash
+
+	// Target is the destination of the payment. Fresh gossip for any of
+	// the target's channels triggers an immediate retry.
+	Target route.Vertex
+
+	// Amount is the amount that is sent to the target.
+	Amount lnwire.MilliSatoshi
+
+	// Retries is the number of times the payment has been retried so far.
+	Retries uint32
+
+	// NextAttempt is the time at which the payment will be retried. It is
+	// the zero time while an attempt of the payment is in flight.
+	NextAttempt time.Time
+
+	// LastFailure is the reason of the last failed attempt, if any.
+	LastFailure *channeldb.FailureReason
+
+	// Request is the opaque, serialized request that is handed back to
+	// the Resend callback to retry the payment.
+	Request []byte
+}
+
+// InFlight returns true if an attempt of the payment is currently in flight.
+func (s *ScheduledPayment) InFlight() bool {
+	return s.NextAttempt.IsZero()
+}
+
+// copy returns a deep copy of the scheduled payment.
+func (s *ScheduledPayment) copy() *ScheduledPayment {
+	cp := *s
+	cp.Request = append([]byte(nil), s.Request...)
+	if s.LastFailure != nil {
+		reason := *s.LastFailure
+		cp.LastFailure = &reason
+	}
+
+	return &cp
+}
+
+// PaymentRetryQueueConfig houses the dependencies of the PaymentRetryQueue.
+type PaymentRetryQueueConfig struct {
+	// DB is the database that the scheduled payments are persisted in.
+	DB kvdb.Backend
+
+	// Control is used to learn about the outcome of tracked payments.
+	Control ControlTower
+
+	// SubscribeTopology is used to subscribe to graph topology changes,
+	// so payments can be retried as soon as there is fresh gossip for the
+	// channels of their destination.
+	SubscribeTopology func() (*graph.TopologyClient, error)
+
+	// Resend is called with the opaque request of a scheduled payment
+	// when the payment is due to be retried. The passed context is
+	// canceled once the queue is stopped, which should stop the payment
+	// loop of the resent payment. If it returns an error, then the payment
+	// is removed from the queue.
+	Resend func(ctx context.Context, request []byte) error
+
+	// Clock is used to determine the time of the next retry.
+	Clock clock.Clock
+
+	// Ticker is used to periodically check for payments that are due to
+	// be retried.
+	Ticker ticker.Ticker
+
+	// RetryInterval is the base delay after which a failed payment is
+	// retried. The delay is doubled for every subsequent retry.
+	RetryInterval time.Duration
+
+	// MaxRetryInterval is the upper bound of the delay between two
+	// retries.
+	MaxRetryInterval time.Duration
+
+	// MaxRetries is the number of times a payment is retried before it's
+	// given up on.
+	MaxRetries uint32
+}
+
+// PaymentRetryQueue is a durable queue of payments that are automatically
+// retried if they fail with a retryable failure reason. Retries are either
+// scheduled with an exponential backoff, or triggered as soon as fresh gossip
+// for the channels of the payment's destination is received.
+type PaymentRetryQueue struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *PaymentRetryQueueConfig
+
+	// payments is the in-memory view of all scheduled payments.
+	payments map[lntypes.Hash]*ScheduledPayment
+
+	// running is set once the queue has been started.
+	running bool
+
+	mu sync.Mutex
+
+	// ctx is the context that all resent payments are bound to. It is
+	// canceled by cancel once the queue is stopped.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// NewPaymentRetryQueue creates a new payment retry queue from the given
+// config.
+func NewPaymentRetryQueue(cfg *PaymentRetryQueueConfig) *PaymentRetryQueue {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &PaymentRetryQueue{
+		cfg:      cfg,
+		payments: make(map[lntypes.Hash]*ScheduledPayment),
+		ctx:      ctx,
+		cancel:   cancel,
+		quit:     make(chan struct{}),
+	}
+}
+
+// Start loads all scheduled payments from disk and launches the goroutine
+// that retries them.
+func (q *PaymentRetryQueue) Start() error {
+	var startErr error
+	q.started.Do(func() {
+		log.Info("Payment retry queue starting")
+		startErr = q.start()
+	})
+
+	return startErr
+}
+
+// start is the internal implementation of Start.
+func (q *PaymentRetryQueue) start() error {
+	// Subscribe to all payments before looking at the persisted ones, so
+	// we won't miss the outcome of a payment that is still in flight.
+	paymentSub, err := q.cfg.Control.SubscribeAllPayments()
+	if err != nil {
+		return fmt.Errorf("unable to subscribe to payments: %w", err)
+	}
+
+	payments, err := fetchScheduledPayments(q.cfg.DB)
+	if err != nil {
+		paymentSub.Close()
+		return err
+	}
+
+	q.mu.Lock()
+	for _, p := range payments {
+		q.payments[p.PaymentHash] = p
+	}
+	q.running = true
+	q.mu.Unlock()
+
+	// Payments that were in flight when we shut down might have been
+	// resolved in the meantime, in which case we won't receive an update
+	// for them.
+	for _, p := range payments {
+		if !p.InFlight() {
+			continue
+		}
+
+		payment, err := q.cfg.Control.FetchPayment(p.PaymentHash)
+		switch {
+		case errors.Is(err, channeldb.ErrPaymentNotInitiated):
+			log.Warnf("Removing scheduled payment %v which is "+
+				"unknown to the control tower", p.PaymentHash)
+
+			q.mu.Lock()
+			err = q.removePayment(p.PaymentHash)
+			q.mu.Unlock()
+			if err != nil {
+				paymentSub.Close()
+				return err
+			}
+
+			continue
+
+		case err != nil:
+			paymentSub.Close()
+			return err
+		}
+
+		_, reason := payment.TerminalInfo()
+		err = q.handlePaymentStatus(
+			p.PaymentHash, payment.GetStatus(), reason,
+		)
+		if err != nil {
+			paymentSub.Close()
+			return err
+		}
+	}
+
+	log.Infof("Payment retry queue started with %d scheduled payments",
+		len(payments))
+
+	q.cfg.Ticker.Resume()
+
+	q.wg.Add(1)
+	go q.run(paymentSub)
+
+	return nil
+}
+
+// Stop signals the retry queue to shut down and waits for it to exit.
+func (q *PaymentRetryQueue) Stop() error {
+	q.stopped.Do(func() {
+		log.Info("Payment retry queue shutting down...")
+		defer log.Debug("Payment retry queue shutdown complete")
+
+		close(q.quit)
+		q.wg.Wait()
+
+		// Cancel the payments we resent only after the event loop
+		// exited, so their cancellation isn't mistaken for a
+		// permanent failure. They're picked up again on the next
+		// start.
+		q.cancel()
+
+		q.cfg.Ticker.Stop()
+	})
+
+	return nil
+}
+
+// Schedule adds a payment to the retry queue. It must be called after the
+// payment has been initialized with the control tower, but before it is sent
+// out, so the outcome of the first attempt is guaranteed to be observed. If
+// the payment is already scheduled, then its entry is replaced.
+func (q *PaymentRetryQueue) Schedule(hash lntypes.Hash, target route.Vertex,
+	amt lnwire.MilliSatoshi, request []byte) error {
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if !q.running {
+		return ErrPaymentRetryQueueNotStarted
+	}
+
+	p := &ScheduledPayment{
+		PaymentHash: hash,
+		Target:      target,
+		Amount:      amt,
+		Request:     append([]byte(nil), request...),
+	}
+
+	// If the payment was already scheduled, then we keep the number of
+	// retries, so a user can't exceed the limit by manually retrying.
+	if existing, ok := q.payments[hash]; ok {
+		p.Retries = existing.Retries
+		p.LastFailure = existing.LastFailure
+	}
+
+	log.Debugf("Scheduling payment %v to %v for automatic retries", hash,
+		target)
+
+	return q.storePayment(p)
+}
+
+// ListScheduledPayments returns all the payments that are currently tracked
+// by the retry queue, ordered by the time of their next attempt. Payments
+// that are currently in flight are listed first.
+func (q *PaymentRetryQueue) ListScheduledPayments() []*ScheduledPayment {
+	q.mu.Lock()
+	payments := make([]*ScheduledPayment, 0, len(q.payments))
+	for _, p := range q.payments {
+		payments = append(payments, p.copy())
+	}
+	q.mu.Unlock()
+
+	sort.Slice(payments, func(i, j int) bool {
+		if !payments[i].NextAttempt.Equal(payments[j].NextAttempt) {
+			return payments[i].NextAttempt.Before(
+				payments[j].NextAttempt,
+			)
+		}
+
+		return bytes.Compare(
+			payments[i].PaymentHash[:], payments[j].PaymentHash[:],
+		) < 0
+	})
+
+	return payments
+}
+
+// run is the main event loop of the retry queue.
+//
+// NOTE: This MUST be run as a goroutine.
+func (q *PaymentRetryQueue) run(paymentSub ControlTowerSubscriber) {
+	defer q.wg.Done()
+	defer paymentSub.Close()
+
+	// The graph might not be running yet when we are started, so we keep
+	// on trying to subscribe to topology changes on every tick until we
+	// succeed.
+	var topologyClient *graph.TopologyClient
+	defer func() {
+		if topologyClient != nil {
+			topologyClient.Cancel()
+		}
+	}()
+
+	subscribeTopology := func() {
+		if topologyClient != nil {
+			return
+		}
+
+		client, err := q.cfg.SubscribeTopology()
+		if err != nil {
+			log.Debugf("Unable to subscribe to topology changes: "+
+				"%v", err)
+
+			return
+		}
+		topologyClient = client
+	}
+	subscribeTopology()
+
+	for {
+		var topologyChanges <-chan *graph.TopologyChange
+		if topologyClient != nil {
+			topologyChanges = topologyClient.TopologyChanges
+		}
+
+		select {
+		case update, ok := <-paymentSub.Updates():
+			if !ok {
+				return
+			}
+
+			payment, ok := update.(*channeldb.MPPayment)
+			if !ok {
+				log.Errorf("Unexpected payment update type %T",
+					update)
+
+				continue
+			}
+
+			err := q.handlePaymentStatus(
+				payment.Info.PaymentIdentifier, payment.Status,
+				payment.FailureReason,
+			)
+			if err != nil {
+				log.Errorf("Unable to handle update of "+
+					"payment %v: %v",
+					payment.Info.PaymentIdentifier, err)
+			}
+
+		case change, ok := <-topologyChanges:
+			if !ok {
+				topologyClient = nil
+				continue
+			}
+
+			if err := q.handleTopologyChange(change); err != nil {
+				log.Errorf("Unable to handle topology change: "+
+					"%v", err)
+			}
+
+			q.retryDuePayments()
+
+		case <-q.cfg.Ticker.Ticks():
+			subscribeTopology()
+			q.retryDuePayments()
+
+		case <-q.quit:
+			return
+		}
+	}
+}
+
+// isRetryableFailure returns true if a payment that failed with the given
+// reason might succeed if it's attempted again later on.
+func isRetryableFailure(reason channeldb.FailureReason) bool {
+	switch reason {
+	case channeldb.FailureReasonTimeout,
+		channeldb.FailureReasonNoRoute,
+		channeldb.FailureReasonInsufficientBalance:
+
+		return true
+
+	default:
+		return false
+	}
+}
+
+// retryDelay returns the backoff delay before the given retry.
+func (q *PaymentRetryQueue) retryDelay(retry uint32) time.Duration {
+	delay := q.cfg.RetryInterval
+	for i := uint32(0); i < retry && delay < q.cfg.MaxRetryInterval; i++ {
+		delay *= 2
+	}
+
+	if delay > q.cfg.MaxRetryInterval {
+		delay = q.cfg.MaxRetryInterval
+	}
+
+	return delay
+}
+
+// handlePaymentStatus updates the scheduled payment with the given hash, if
+// any, according to the status of the payment.
+func (q *PaymentRetryQueue) handlePaymentStatus(hash lntypes.Hash,
+	status channeldb.PaymentStatus,
+	reason *channeldb.FailureReason) error {
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	p, ok := q.payments[hash]
+
+	// We're only interested in the outcome of in-flight attempts of the
+	// payments we track.
+	if !ok || !p.InFlight() {
+		return nil
+	}
+
+	switch status {
+	case channeldb.StatusSucceeded:
+		log.Infof("Scheduled payment %v succeeded after %d retries",
+			hash, p.Retries)
+
+		return q.removePayment(hash)
+
+	case channeldb.StatusFailed:
+
+	default:
+		return nil
+	}
+
+	switch {
+	// Only the payments resent by us are bound to our context, which is
+	// only canceled if we were stopped while the payment was in flight.
+	// We retry it right away without counting the interrupted attempt.
+	// If the first attempt was canceled, then it was canceled by the
+	// caller that sent it.
+	case reason != nil && *reason == channeldb.FailureReasonCanceled &&
+		p.Retries > 0:
+
+		log.Infof("Scheduled payment %v was interrupted by a "+
+			"shutdown, retrying it now", hash)
+
+		updated := p.copy()
+		updated.Retries--
+		updated.NextAttempt = q.cfg.Clock.Now()
+
+		return q.storePayment(updated)
+
+	case reason == nil || !isRetryableFailure(*reason):
+		log.Infof("Scheduled payment %v failed permanently: %v", hash,
+			reason)
+
+		return q.removePayment(hash)
+
+	case p.Retries >= q.cfg.MaxRetries:
+		log.Infof("Scheduled payment %v failed after %d retries, "+
+			"giving up", hash, p.Retries)
+
+		return q.removePayment(hash)
+	}
+
+	updated := p.copy()
+	updated.LastFailure = reason
+	updated.NextAttempt = q.cfg.Clock.Now().Add(q.retryDelay(p.Retries))
+
+	log.Debugf("Payment %v failed with %v, retrying at %v", hash, *reason,
+		updated.NextAttempt)
+
+	return q.storePayment(updated)
+}
+
+// handleTopologyChange moves the next attempt of all payments whose
+// destination is part of the topology change to now.
+func (q *PaymentRetryQueue) handleTopologyChange(
+	change *graph.TopologyChange) error {
+
+	nodes := make(map[route.Vertex]struct{})
+	for _, update := range change.NodeUpdates {
+		nodes[route.NewVertex(update.IdentityKey)] = struct{}{}
+	}
+	for _, update := range change.ChannelEdgeUpdates {
+		nodes[route.NewVertex(update.AdvertisingNode)] = struct{}{}
+		nodes[route.NewVertex(update.ConnectingNode)] = struct{}{}
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.cfg.Clock.Now()
+	for _, p := range q.payments {
+		if p.InFlight() || !p.NextAttempt.After(now) {
+			continue
+		}
+
+		if _, ok := nodes[p.Target]; !ok {
+			continue
+		}
+
+		log.Debugf("Received fresh gossip for destination %v, "+
+			"retrying payment %v now", p.Target, p.PaymentHash)
+
+		updated := p.copy()
+		updated.NextAttempt = now
+		if err := q.storePayment(updated); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// retryDuePayments resends all the payments whose next attempt is due.
+func (q *PaymentRetryQueue) retryDuePayments() {
+	q.mu.Lock()
+	now := q.cfg.Clock.Now()
+
+	var due []*ScheduledPayment
+	for _, p := range q.payments {
+		if p.InFlight() || p.NextAttempt.After(now) {
+			continue
+		}
+
+		// Mark the payment as in flight before resending it, so the
+		// outcome of the new attempt is picked up.
+		updated := p.copy()
+		updated.NextAttempt = time.Time{}
+		updated.Retries++
+		if err := q.storePayment(updated); err != nil {
+			log.Errorf("Unable to update scheduled payment %v: %v",
+				p.PaymentHash, err)
+
+			continue
+		}
+
+		due = append(due, updated)
+	}
+	q.mu.Unlock()
+
+	for _, p := range due {
+		log.Infof("Retrying payment %v (retry %d of %d)",
+			p.PaymentHash, p.Retries, q.cfg.MaxRetries)
+
+		err := q.cfg.Resend(q.ctx, p.Request)
+		if err == nil {
+			continue
+		}
+
+		log.Errorf("Unable to retry payment %v, removing it from "+
+			"the retry queue: %v", p.PaymentHash, err)
+
+		q.mu.Lock()
+		err = q.removePayment(p.PaymentHash)
+		q.mu.Unlock()
+		if err != nil {
+			log.Errorf("Unable to remove scheduled payment %v: %v",
+				p.PaymentHash, err)
+		}
+	}
+}
+
+// storePayment persists the given scheduled payment and updates the in-memory
+// view.
+//
+// NOTE: The caller MUST hold the queue's mutex.
+func (q *PaymentRetryQueue) storePayment(p *ScheduledPayment) error {
+	var b bytes.Buffer
+	if err := serializeScheduledPayment(&b, p); err != nil {
+		return err
+	}
+
+	err := kvdb.Update(q.cfg.DB, func(tx kvdb.RwTx) error {
+		bucket, err := tx.CreateTopLevelBucket(paymentRetryQueueBucket)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(p.PaymentHash[:], b.Bytes())
+	}, func() {})
+	if err != nil {
+		return err
+	}
+
+	q.payments[p.PaymentHash] = p
+
+	return nil
+}
+
+// removePayment removes the scheduled payment with the given hash from disk
+// and the in-memory view.
+//
+// NOTE: The caller MUST hold the queue's mutex.
+func (q *PaymentRetryQueue) removePayment(hash lntypes.Hash) error {
+	err := kvdb.Update(q.cfg.DB, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(paymentRetryQueueBucket)
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.Delete(hash[:])
+	}, func() {})
+	if err != nil {
+		return err
+	}
+
+	delete(q.payments, hash)
+
+	return nil
+}
+
+// fetchScheduledPayments reads all scheduled payments from disk.
+func fetchScheduledPayments(db kvdb.Backend) ([]*ScheduledPayment, error) {
+	var payments []*ScheduledPayment
+	err := kvdb.View(db, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(paymentRetryQueueBucket)
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.ForEach(func(k, v []byte) error {
+			p, err := deserializeScheduledPayment(
+				bytes.NewReader(v),
+			)
+			if err != nil {
+				return err
+			}
+			copy(p.PaymentHash[:], k)
+
+			payments = append(payments, p)
+
+			return nil
+		})
+	}, func() {
+		payments = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return payments, nil
+}
+
+// serializeScheduledPayment writes the given scheduled payment, except for its
+// payment hash which is used as the key, to the passed writer.
+func serializeScheduledPayment(w io.Writer, p *ScheduledPayment) error {
+	var nextAttempt int64
+	if !p.InFlight() {
+		nextAttempt = p.NextAttempt.UnixNano()
+	}
+
+	lastFailure := uint8(noFailureReason)
+	if p.LastFailure != nil {
+		lastFailure = uint8(*p.LastFailure)
+	}
+
+	if _, err := w.Write(p.Target[:]); err != nil {
+		return err
+	}
+
+	err := channeldb.WriteElements(
+		w, p.Amount, p.Retries, nextAttempt, lastFailure,
+	)
+	if err != nil {
+		return err
+	}
+
+	return wire.WriteVarBytes(w, 0, p.Request)
+}
+
+// deserializeScheduledPayment reads a scheduled payment from the passed
+// reader.
+func deserializeScheduledPayment(r io.Reader) (*ScheduledPayment, error) {
+	var (
+		p           ScheduledPayment
+		nextAttempt int64
+		lastFailure uint8
+	)
+
+	if _, err := io.ReadFull(r, p.Target[:]); err != nil {
+		return nil, err
+	}
+
+	err := channeldb.ReadElements(
+		r, &p.Amount, &p.Retries, &nextAttempt, &lastFailure,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if nextAttempt != 0 {
+		p.NextAttempt = time.Unix(0, nextAttempt)
+	}
+	if lastFailure != noFailureReason {
+		reason := channeldb.FailureReason(lastFailure)
+		p.LastFailure = &reason
+	}
+
+	p.Request, err = wire.ReadVarBytes(
+		r, 0, maxScheduledPaymentRequestSize, "request",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
@@ -0,0 +1,332 @@
+package routing
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/graph"
+	"github.com/lightningnetwork/lnd/lntest/wait"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/ticker"
+	"github.com/stretchr/testify/require"
+)
+
+const testPaymentRetryInterval = time.Minute
+
+// retryQueueTestHarness is the harness for a PaymentRetryQueue test.
+type retryQueueTestHarness struct {
+	t *testing.T
+
+	db      *channeldb.DB
+	control ControlTower
+	clock   *clock.TestClock
+	ticker  *ticker.Force
+
+	topologyChanges chan *graph.TopologyChange
+	resends         chan *resentPayment
+
+	queue *PaymentRetryQueue
+}
+
+// resentPayment is a payment that was resent by the retry queue.
+type resentPayment struct {
+	ctx     context.Context
+	request []byte
+}
+
+// newRetryQueueTestHarness creates a new retry queue test harness.
+func newRetryQueueTestHarness(t *testing.T) *retryQueueTestHarness {
+	db, err := initDB(t, false)
+	require.NoError(t, err)
+
+	control := NewControlTower(channeldb.NewPaymentControl(db))
+	h := &retryQueueTestHarness{
+		t:               t,
+		db:              db,
+		control:         control,
+		clock:           clock.NewTestClock(time.Unix(1, 0)),
+		topologyChanges: make(chan *graph.TopologyChange),
+		resends:         make(chan *resentPayment, 1),
+	}
+	h.start()
+
+	return h
+}
+
+// start creates and starts a new retry queue on top of the harness' database.
+func (h *retryQueueTestHarness) start() {
+	h.ticker = ticker.NewForce(time.Hour)
+	h.queue = NewPaymentRetryQueue(&PaymentRetryQueueConfig{
+		DB:      h.db,
+		Control: h.control,
+		SubscribeTopology: func() (*graph.TopologyClient, error) {
+			return &graph.TopologyClient{
+				TopologyChanges: h.topologyChanges,
+				Cancel:          func() {},
+			}, nil
+		},
+		Resend: func(ctx context.Context, request []byte) error {
+			h.resends <- &resentPayment{
+				ctx:     ctx,
+				request: request,
+			}
+
+			return nil
+		},
+		Clock:            h.clock,
+		Ticker:           h.ticker,
+		RetryInterval:    testPaymentRetryInterval,
+		MaxRetryInterval: 3 * testPaymentRetryInterval,
+		MaxRetries:       2,
+	})
+	require.NoError(h.t, h.queue.Start())
+}
+
+// restart stops the current retry queue and starts a new one.
+func (h *retryQueueTestHarness) restart() {
+	require.NoError(h.t, h.queue.Stop())
+	h.start()
+}
+
+// assertScheduled waits for the queue to contain exactly the given payment.
+func (h *retryQueueTestHarness) assertScheduled(expected *ScheduledPayment) {
+	err := wait.NoError(func() error {
+		payments := h.queue.ListScheduledPayments()
+		if len(payments) != 1 {
+			return fmt.Errorf("expected 1 scheduled payment, "+
+				"got %d", len(payments))
+		}
+
+		if !payments[0].NextAttempt.Equal(expected.NextAttempt) ||
+			payments[0].Retries != expected.Retries {
+
+			return fmt.Errorf("payment not updated")
+		}
+
+		return nil
+	}, time.Second)
+	require.NoError(h.t, err)
+
+	payment := h.queue.ListScheduledPayments()[0]
+	payment.NextAttempt = expected.NextAttempt
+	require.Equal(h.t, expected, payment)
+}
+
+// assertResend asserts that the given request is sent again and returns the
+// context it was sent with.
+func (h *retryQueueTestHarness) assertResend(
+	request []byte) context.Context {
+
+	select {
+	case resent := <-h.resends:
+		require.Equal(h.t, request, resent.request)
+		return resent.ctx
+
+	case <-time.After(time.Second):
+		h.t.Fatal("payment not resent")
+		return nil
+	}
+}
+
+// TestPaymentRetryQueue tests that payments which fail with a retryable
+// failure reason are retried with a backoff, or as soon as there's fresh
+// gossip for their destination, and that they're removed from the queue once
+// they fail permanently.
+func TestPaymentRetryQueue(t *testing.T) {
+	t.Parallel()
+
+	h := newRetryQueueTestHarness(t)
+
+	info, _, _, err := genInfo()
+	require.NoError(t, err)
+	hash := info.PaymentIdentifier
+
+	targetKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	target := route.NewVertex(targetKey.PubKey())
+
+	request := []byte("request")
+	require.NoError(t, h.control.InitPayment(hash, info))
+	require.NoError(t, h.queue.Schedule(hash, target, info.Value, request))
+
+	expected := &ScheduledPayment{
+		PaymentHash: hash,
+		Target:      target,
+		Amount:      info.Value,
+		Request:     request,
+	}
+	h.assertScheduled(expected)
+
+	// When the payment fails because no route was found, it should be
+	// scheduled for a retry.
+	noRoute := channeldb.FailureReasonNoRoute
+	require.NoError(t, h.control.FailPayment(hash, noRoute))
+
+	expected.LastFailure = &noRoute
+	expected.NextAttempt = h.clock.Now().Add(testPaymentRetryInterval)
+	h.assertScheduled(expected)
+
+	// A tick before the retry is due shouldn't resend the payment.
+	h.ticker.Force <- time.Time{}
+	select {
+	case <-h.resends:
+		t.Fatal("payment resent before it was due")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Once it's due, the payment should be resent and marked as in
+	// flight.
+	h.clock.SetTime(expected.NextAttempt)
+	h.ticker.Force <- time.Time{}
+	h.assertResend(request)
+
+	expected.Retries = 1
+	expected.NextAttempt = time.Time{}
+	h.assertScheduled(expected)
+
+	// Fail the payment again, which should double the backoff. The
+	// scheduled retries should survive a restart.
+	require.NoError(t, h.control.InitPayment(hash, info))
+	require.NoError(t, h.control.FailPayment(hash, noRoute))
+
+	expected.NextAttempt = h.clock.Now().Add(2 * testPaymentRetryInterval)
+	h.assertScheduled(expected)
+
+	h.restart()
+	h.assertScheduled(expected)
+
+	// Fresh gossip for the destination should trigger an immediate retry.
+	h.topologyChanges <- &graph.TopologyChange{
+		NodeUpdates: []*graph.NetworkNodeUpdate{{
+			IdentityKey: targetKey.PubKey(),
+		}},
+	}
+	h.assertResend(request)
+
+	expected.Retries = 2
+	expected.NextAttempt = time.Time{}
+	h.assertScheduled(expected)
+
+	// As the maximum number of retries is reached now, the next failure
+	// should remove the payment from the queue.
+	require.NoError(t, h.control.InitPayment(hash, info))
+	require.NoError(t, h.control.FailPayment(hash, noRoute))
+
+	err = wait.NoError(func() error {
+		if len(h.queue.ListScheduledPayments()) != 0 {
+			return fmt.Errorf("payment still scheduled")
+		}
+
+		return nil
+	}, time.Second)
+	require.NoError(t, err)
+
+	// The removal should be persisted.
+	h.restart()
+	require.Empty(t, h.queue.ListScheduledPayments())
+	require.NoError(t, h.queue.Stop())
+}
+
+// TestPaymentRetryQueuePermanentFailure tests that a payment that fails with a
+// non-retryable failure reason isn't retried.
+func TestPaymentRetryQueuePermanentFailure(t *testing.T) {
+	t.Parallel()
+
+	h := newRetryQueueTestHarness(t)
+	t.Cleanup(func() {
+		require.NoError(t, h.queue.Stop())
+	})
+
+	info, _, _, err := genInfo()
+	require.NoError(t, err)
+	hash := info.PaymentIdentifier
+
+	require.NoError(t, h.control.InitPayment(hash, info))
+	err = h.queue.Schedule(hash, route.Vertex{1}, info.Value, nil)
+	require.NoError(t, err)
+	require.Len(t, h.queue.ListScheduledPayments(), 1)
+
+	err = h.control.FailPayment(hash, channeldb.FailureReasonPaymentDetails)
+	require.NoError(t, err)
+
+	err = wait.NoError(func() error {
+		if len(h.queue.ListScheduledPayments()) != 0 {
+			return fmt.Errorf("payment still scheduled")
+		}
+
+		return nil
+	}, time.Second)
+	require.NoError(t, err)
+}
+
+// TestPaymentRetryQueueShutdown tests that resent payments are canceled when
+// the queue is stopped, and that they're retried right away on the next start
+// instead of being treated as canceled by the user.
+func TestPaymentRetryQueueShutdown(t *testing.T) {
+	t.Parallel()
+
+	h := newRetryQueueTestHarness(t)
+
+	info, _, _, err := genInfo()
+	require.NoError(t, err)
+	hash := info.PaymentIdentifier
+
+	target := route.Vertex{1}
+	request := []byte("request")
+	require.NoError(t, h.control.InitPayment(hash, info))
+	require.NoError(t, h.queue.Schedule(hash, target, info.Value, request))
+
+	noRoute := channeldb.FailureReasonNoRoute
+	require.NoError(t, h.control.FailPayment(hash, noRoute))
+
+	expected := &ScheduledPayment{
+		PaymentHash: hash,
+		Target:      target,
+		Amount:      info.Value,
+		Request:     request,
+		LastFailure: &noRoute,
+		NextAttempt: h.clock.Now().Add(testPaymentRetryInterval),
+	}
+	h.assertScheduled(expected)
+
+	// Resend the payment and initialize it again, as the router would.
+	h.clock.SetTime(expected.NextAttempt)
+	h.ticker.Force <- time.Time{}
+	ctx := h.assertResend(request)
+	require.NoError(t, h.control.InitPayment(hash, info))
+
+	expected.Retries = 1
+	expected.NextAttempt = time.Time{}
+	h.assertScheduled(expected)
+
+	// Stopping the queue should cancel the context of the resent payment.
+	require.NoError(t, h.queue.Stop())
+	select {
+	case <-ctx.Done():
+	default:
+		t.Fatal("context of resent payment not canceled")
+	}
+
+	// The payment lifecycle then fails the payment as canceled. On the
+	// next start, the interrupted attempt shouldn't be counted and the
+	// payment should be retried right away.
+	canceled := channeldb.FailureReasonCanceled
+	require.NoError(t, h.control.FailPayment(hash, canceled))
+
+	h.start()
+	t.Cleanup(func() {
+		require.NoError(t, h.queue.Stop())
+	})
+
+	expected.Retries = 0
+	expected.NextAttempt = h.clock.Now()
+	h.assertScheduled(expected)
+
+	h.ticker.Force <- time.Time{}
+	h.assertResend(request)
+}
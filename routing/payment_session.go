@@ -1,6 +1,7 @@
 package routing
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/btcsuite/btcd/btcec/v2"
@@ -137,8 +138,9 @@ type PaymentSession interface {
 	// splitting strategy accordingly.
 	//
 	// A noRouteError is returned if a non-critical error is encountered
-	// during path finding.
-	RequestRoute(maxAmt, feeLimit lnwire.MilliSatoshi,
+	// during path finding. If the passed context is canceled, path finding
+	// is aborted and the context's error is returned.
+	RequestRoute(ctx context.Context, maxAmt, feeLimit lnwire.MilliSatoshi,
 		activeShards, height uint32,
 		firstHopCustomRecords lnwire.CustomRecords) (*route.Route,
 		error)
@@ -186,6 +188,11 @@ type paymentSession struct {
 
 	missionControl MissionControlQuerier
 
+	// blockPadding is the number of blocks added to the final cltv delta,
+	// so the receiving node doesn't reject the HTLC if some blocks are
+	// mined while it's in-flight.
+	blockPadding uint16
+
 	// minShardAmt is the amount beyond which we won't try to further split
 	// the payment if no route is found. If the maximum number of htlcs
 	// specified in the payment is one, under no circumstances splitting
@@ -231,6 +238,7 @@ func newPaymentSession(p *LightningPayment, selfNode route.Vertex,
 		graphSessFactory:  graphSessFactory,
 		pathFindingConfig: pathFindingConfig,
 		missionControl:    missionControl,
+		blockPadding:      BlockPadding,
 		minShardAmt:       DefaultShardMinAmt,
 		log:               build.NewPrefixLog(logPrefix, log),
 	}, nil
@@ -245,18 +253,27 @@ func newPaymentSession(p *LightningPayment, selfNode route.Vertex,
 //
 // NOTE: This function is safe for concurrent access.
 // NOTE: Part of the PaymentSession interface.
-func (p *paymentSession) RequestRoute(maxAmt, feeLimit lnwire.MilliSatoshi,
-	activeShards, height uint32,
+func (p *paymentSession) RequestRoute(ctx context.Context,
+	maxAmt, feeLimit lnwire.MilliSatoshi, activeShards, height uint32,
 	firstHopCustomRecords lnwire.CustomRecords) (*route.Route, error) {
 
 	if p.empty {
 		return nil, errEmptyPaySession
 	}
 
-	// Add BlockPadding to the finalCltvDelta so that the receiving node
-	// does not reject the HTLC if some blocks are mined while it's in-flight.
+	// Add the block padding to the finalCltvDelta so that the receiving
+	// node does not reject the HTLC if some blocks are mined while it's
+	// in-flight. The cltv limit of the payment is only validated against
+	// the default BlockPadding, so we fall back to it if a larger padding
+	// would exhaust the limit.
+	padding := p.blockPadding
+	if uint32(p.payment.FinalCLTVDelta)+uint32(padding) >=
+		p.payment.CltvLimit {
+
+		padding = BlockPadding
+	}
 	finalCltvDelta := p.payment.FinalCLTVDelta
-	finalCltvDelta += BlockPadding
+	finalCltvDelta += padding
 
 	// We need to subtract the final delta before passing it into path
 	// finding. The optimal path is independent of the final cltv delta and
@@ -322,9 +339,10 @@ func (p *paymentSession) RequestRoute(maxAmt, feeLimit lnwire.MilliSatoshi,
 
 		p.log.Debugf("pathfinding for amt=%v", maxAmt)
 
-		// Find a route for the current amount.
+		// Find a route for the current amount. The search is aborted
+		// once the payment's context is canceled.
 		path, _, err := p.pathFinder(
-			&graphParams{
+			ctx, &graphParams{
 				additionalEdges: p.additionalEdges,
 				bandwidthHints:  bandwidthHints,
 				graph:           graph,
@@ -1,7 +1,11 @@
 package routing
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/btcsuite/btcd/btcec/v2"
+	sphinx "github.com/lightningnetwork/lightning-onion"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/channeldb/models"
 	"github.com/lightningnetwork/lnd/fn"
@@ -45,6 +49,18 @@ type SessionSource struct {
 	// PathFindingConfig defines global parameters that control the
 	// trade-off in path finding between fees and probability.
 	PathFindingConfig PathFindingConfig
+
+	// Reservations is the outbound liquidity of our channels that is
+	// reserved for recently launched shards. It is deducted from the
+	// bandwidth of our channels during path finding. If nil, no liquidity
+	// is reserved.
+	Reservations *LiquidityReservations
+
+	// BlockIntervals provides statistics about the recently observed
+	// block intervals, which are used to adjust the block padding of the
+	// final hop to the current block rate. If nil, the default
+	// BlockPadding is used.
+	BlockIntervals BlockIntervalSource
 }
 
 // NewPaymentSession creates a new payment session backed by the latest prune
@@ -56,10 +72,22 @@ func (m *SessionSource) NewPaymentSession(p *LightningPayment,
 	trafficShaper fn.Option[TlvTrafficShaper]) (PaymentSession, error) {
 
 	getBandwidthHints := func(graph Graph) (bandwidthHints, error) {
-		return newBandwidthManager(
+		hints, err := newBandwidthManager(
 			graph, m.SourceNode.PubKeyBytes, m.GetLink,
 			firstHopBlob, trafficShaper,
 		)
+		if err != nil {
+			return nil, err
+		}
+
+		if m.Reservations == nil {
+			return hints, nil
+		}
+
+		return &reservedBandwidthHints{
+			bandwidthHints: hints,
+			reservations:   m.Reservations,
+		}, nil
 	}
 
 	session, err := newPaymentSession(
@@ -69,10 +97,32 @@ func (m *SessionSource) NewPaymentSession(p *LightningPayment,
 	if err != nil {
 		return nil, err
 	}
+	session.blockPadding = m.blockPadding()
 
 	return session, nil
 }
 
+// blockPadding returns the block padding of the final hop for a new payment
+// session, based on the recently observed block intervals if available.
+func (m *SessionSource) blockPadding() uint16 {
+	if m.BlockIntervals == nil {
+		return BlockPadding
+	}
+
+	stats, err := m.BlockIntervals.BlockIntervalStats()
+	if err != nil {
+		log.Debugf("Using default block padding: %v", err)
+
+		return BlockPadding
+	}
+
+	padding := blockPaddingFromStats(stats)
+	log.Debugf("Using block padding of %v blocks for mean block interval "+
+		"of %v over %v blocks", padding, stats.Mean, stats.Samples)
+
+	return padding
+}
+
 // NewPaymentSessionEmpty creates a new paymentSession instance that is empty,
 // and will be exhausted immediately. Used for failure reporting to
 // missioncontrol for resumed payment we don't want to make more attempts for.
@@ -82,17 +132,91 @@ func (m *SessionSource) NewPaymentSessionEmpty() PaymentSession {
 	}
 }
 
+var (
+	// ErrRouteHintMissingNode is returned when a hop of a route hint
+	// doesn't specify the node at the start of its channel.
+	ErrRouteHintMissingNode = errors.New("route hint hop has no node id")
+
+	// ErrRouteHintLoop is returned when a route hint visits the same node
+	// more than once.
+	ErrRouteHintLoop = errors.New("route hint visits a node more than " +
+		"once")
+
+	// ErrRouteHintTooLong is returned when a route hint has more hops than
+	// can fit into a route.
+	ErrRouteHintTooLong = fmt.Errorf("route hint has more than %d hops",
+		maxRouteHintHops)
+)
+
+// maxRouteHintHops is the maximum number of hops a route hint can have. At
+// least one more hop is needed to reach the start of the hint, so it's one
+// less than the maximum number of hops of a route.
+const maxRouteHintHops = sphinx.NumMaxHops - 1
+
+// hopHintKey uniquely identifies a hop hint, so that hops that are part of
+// multiple route hints are only added to pathfinding once.
+type hopHintKey struct {
+	fromNode  route.Vertex
+	toNode    route.Vertex
+	channelID uint64
+	feeBase   uint32
+	feeRate   uint32
+	cltvDelta uint16
+}
+
+// validateRouteHint checks that the hops of the given route hint can be
+// chained together into a path towards the destination.
+func validateRouteHint(routeHint []zpay32.HopHint) error {
+	if len(routeHint) > maxRouteHintHops {
+		return ErrRouteHintTooLong
+	}
+
+	visited := make(map[route.Vertex]struct{}, len(routeHint))
+	for _, hopHint := range routeHint {
+		if hopHint.NodeID == nil {
+			return ErrRouteHintMissingNode
+		}
+
+		node := route.NewVertex(hopHint.NodeID)
+		if _, ok := visited[node]; ok {
+			return ErrRouteHintLoop
+		}
+		visited[node] = struct{}{}
+	}
+
+	return nil
+}
+
 // RouteHintsToEdges converts a list of invoice route hints to an edge map that
-// can be passed into pathfinding.
+// can be passed into pathfinding. Route hints with multiple hops are chained
+// together, so that destinations behind several private channels can be
+// reached. Route hints that can't be part of a route are skipped.
 func RouteHintsToEdges(routeHints [][]zpay32.HopHint, target route.Vertex) (
 	map[route.Vertex][]AdditionalEdge, error) {
 
 	edges := make(map[route.Vertex][]AdditionalEdge)
+	added := make(map[hopHintKey]struct{})
 
 	// Traverse through all of the available hop hints and include them in
 	// our edges map, indexed by the public key of the channel's starting
 	// node.
 	for _, routeHint := range routeHints {
+		err := validateRouteHint(routeHint)
+		switch {
+		// A hop without a node can't be part of any route, which
+		// indicates that the hints weren't decoded properly.
+		case errors.Is(err, ErrRouteHintMissingNode):
+			return nil, err
+
+		case err != nil:
+			log.Warnf("Skipping route hint with first channel "+
+				"%v: %v", lnwire.NewShortChanIDFromInt(
+				routeHint[0].ChannelID,
+			), err)
+
+			continue
+		}
+
 		// If multiple hop hints are provided within a single route
 		// hint, we'll assume they must be chained together and sorted
 		// in forward order in order to reach the target successfully.
@@ -114,6 +238,24 @@ func RouteHintsToEdges(routeHints [][]zpay32.HopHint, target route.Vertex) (
 				endNode.AddPubKey(targetPubKey)
 			}
 
+			v := route.NewVertex(hopHint.NodeID)
+
+			// Hops that are shared by multiple route hints, e.g.
+			// the last private channel to the destination, only
+			// need to be added once.
+			key := hopHintKey{
+				fromNode:  v,
+				toNode:    endNode.PubKeyBytes,
+				channelID: hopHint.ChannelID,
+				feeBase:   hopHint.FeeBaseMSat,
+				feeRate:   hopHint.FeeProportionalMillionths,
+				cltvDelta: hopHint.CLTVExpiryDelta,
+			}
+			if _, ok := added[key]; ok {
+				continue
+			}
+			added[key] = struct{}{}
+
 			// Finally, create the channel edge from the hop hint
 			// and add it to list of edges corresponding to the node
 			// at the start of the channel.
@@ -136,7 +278,6 @@ func RouteHintsToEdges(routeHints [][]zpay32.HopHint, target route.Vertex) (
 				policy: edgePolicy,
 			}
 
-			v := route.NewVertex(hopHint.NodeID)
 			edges[v] = append(edges[v], edge)
 		}
 	}
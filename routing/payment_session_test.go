@@ -1,9 +1,11 @@
 package routing
 
 import (
+	"context"
 	"testing"
 	"time"
 
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/channeldb/models"
 	"github.com/lightningnetwork/lnd/lntypes"
@@ -206,8 +208,8 @@ func TestRequestRoute(t *testing.T) {
 	}
 
 	// Override pathfinder with a mock.
-	session.pathFinder = func(_ *graphParams, r *RestrictParams,
-		_ *PathFindingConfig, _, _, _ route.Vertex,
+	session.pathFinder = func(_ context.Context, _ *graphParams,
+		r *RestrictParams, _ *PathFindingConfig, _, _, _ route.Vertex,
 		_ lnwire.MilliSatoshi, _ float64, _ int32) ([]*unifiedEdge,
 		float64, error) {
 
@@ -234,7 +236,8 @@ func TestRequestRoute(t *testing.T) {
 	}
 
 	route, err := session.RequestRoute(
-		payment.Amount, payment.FeeLimit, 0, height,
+		context.Background(), payment.Amount, payment.FeeLimit, 0,
+		height,
 		lnwire.CustomRecords{
 			lnwire.MinCustomRecordsTlvType + 123: []byte{1, 2, 3},
 		},
@@ -258,3 +261,90 @@ type sessionGraph struct {
 func (g *sessionGraph) sourceNode() route.Vertex {
 	return route.Vertex{}
 }
+
+// TestRouteHintsToEdges tests that multi-hop route hints are chained together
+// towards the target, and that route hints that can't be part of a route are
+// skipped.
+func TestRouteHintsToEdges(t *testing.T) {
+	t.Parallel()
+
+	targetPriv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	target := route.NewVertex(targetPriv.PubKey())
+
+	node1 := priv1.PubKey()
+	node2 := priv2.PubKey()
+
+	hop1 := zpay32.HopHint{
+		NodeID:          node1,
+		ChannelID:       1,
+		FeeBaseMSat:     10,
+		CLTVExpiryDelta: 40,
+	}
+	hop2 := zpay32.HopHint{
+		NodeID:                    node2,
+		ChannelID:                 2,
+		FeeProportionalMillionths: 100,
+		CLTVExpiryDelta:           80,
+	}
+
+	// The route hint from node1 via node2 to the target shares its last
+	// hop with the route hint from node2, which should only be added
+	// once.
+	edges, err := RouteHintsToEdges(
+		[][]zpay32.HopHint{{hop1, hop2}, {hop2}}, target,
+	)
+	require.NoError(t, err)
+	require.Len(t, edges, 2)
+
+	edges1 := edges[route.NewVertex(node1)]
+	require.Len(t, edges1, 1)
+	policy1 := edges1[0].EdgePolicy()
+	require.Equal(t, route.NewVertex(node2), policy1.ToNodePubKey())
+	require.EqualValues(t, 1, policy1.ChannelID)
+	require.EqualValues(t, 10, policy1.FeeBaseMSat)
+	require.EqualValues(t, 40, policy1.TimeLockDelta)
+
+	edges2 := edges[route.NewVertex(node2)]
+	require.Len(t, edges2, 1)
+	policy2 := edges2[0].EdgePolicy()
+	require.Equal(t, target, policy2.ToNodePubKey())
+	require.EqualValues(t, 2, policy2.ChannelID)
+	require.EqualValues(t, 100, policy2.FeeProportionalMillionths)
+	require.EqualValues(t, 80, policy2.TimeLockDelta)
+
+	// A route hint that visits the same node twice is skipped.
+	loop := hop2
+	loop.NodeID = node1
+	edges, err = RouteHintsToEdges(
+		[][]zpay32.HopHint{{hop1, loop}, {hop2}}, target,
+	)
+	require.NoError(t, err)
+	require.Len(t, edges, 1)
+	require.Contains(t, edges, route.NewVertex(node2))
+
+	// A route hint that is too long to fit into a route is skipped.
+	tooLong := make([]zpay32.HopHint, maxRouteHintHops+1)
+	for i := range tooLong {
+		priv, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		tooLong[i] = zpay32.HopHint{
+			NodeID:    priv.PubKey(),
+			ChannelID: uint64(i + 10),
+		}
+	}
+	edges, err = RouteHintsToEdges(
+		[][]zpay32.HopHint{tooLong, {hop2}}, target,
+	)
+	require.NoError(t, err)
+	require.Len(t, edges, 1)
+
+	// A hop without a node id fails the conversion.
+	missingNode := hop1
+	missingNode.NodeID = nil
+	_, err = RouteHintsToEdges(
+		[][]zpay32.HopHint{{missingNode, hop2}}, target,
+	)
+	require.ErrorIs(t, err, ErrRouteHintMissingNode)
+}
@@ -101,6 +101,17 @@ var (
 	// ErrSkipTempErr is returned when a non-MPP is made yet the
 	// skipTempErr flag is set.
 	ErrSkipTempErr = errors.New("cannot skip temp error for non-MPP")
+
+	// ErrNoDestinationCandidates is returned when a route to any of a set
+	// of destinations is requested, but the set is empty.
+	ErrNoDestinationCandidates = errors.New("no destination candidates " +
+		"provided")
+
+	// ErrCandidatesAndBlinded is returned when a route to any of a set of
+	// destinations is requested for a blinded path set, which already
+	// determines the destination.
+	ErrCandidatesAndBlinded = errors.New("destination candidates and " +
+		"blinded paths are mutually exclusive")
 )
 
 // PaymentAttemptDispatcher is used by the router to send payment attempts onto
@@ -298,6 +309,15 @@ type Config struct {
 	// TrafficShaper is an optional traffic shaper that can be used to
 	// control the outgoing channel of a payment.
 	TrafficShaper fn.Option[TlvTrafficShaper]
+
+	// Reservations is the outbound liquidity of our channels that is
+	// reserved for recently launched shards. It must be the same instance
+	// that is used by the SessionSource. If nil, no liquidity is reserved.
+	Reservations *LiquidityReservations
+
+	// ShardPacing is the minimum interval between launching consecutive
+	// shards of a payment. A value of zero disables pacing.
+	ShardPacing time.Duration
 }
 
 // EdgeLocator is a struct used to identify a specific edge.
@@ -514,9 +534,10 @@ func getTargetNode(target *route.Vertex,
 
 // FindRoute attempts to query the ChannelRouter for the optimum path to a
 // particular target destination to which it is able to send `amt` after
-// factoring in channel capacities and cumulative fees along the route.
-func (r *ChannelRouter) FindRoute(req *RouteRequest) (*route.Route, float64,
-	error) {
+// factoring in channel capacities and cumulative fees along the route. The
+// search is aborted if the passed context is canceled.
+func (r *ChannelRouter) FindRoute(ctx context.Context, req *RouteRequest) (
+	*route.Route, float64, error) {
 
 	log.Debugf("Searching for path to %v, sending %v", req.Target,
 		req.Amount)
@@ -538,6 +559,36 @@ func (r *ChannelRouter) FindRoute(req *RouteRequest) (*route.Route, float64,
 		return nil, 0, err
 	}
 
+	return findRoute(
+		ctx, r.cfg.RoutingGraph, bandwidthHints,
+		&r.cfg.PathFindingConfig, r.cfg.SelfNode, req, currentHeight,
+	)
+}
+
+// FindRouteInGraph attempts to find the optimum route for the given request
+// within the passed graph, in isolation of any live channel state. As there
+// are no links to query, the full capacity of the source node's channels is
+// assumed to be available for sending. This makes it possible to evaluate
+// path finding offline, for example against a snapshot of the graph.
+func FindRouteInGraph(ctx context.Context, g Graph, cfg *PathFindingConfig,
+	req *RouteRequest, currentHeight int32) (*route.Route, float64, error) {
+
+	bandwidthHints, err := newCapacityBandwidthHints(g, req.Source)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return findRoute(
+		ctx, g, bandwidthHints, cfg, req.Source, req, currentHeight,
+	)
+}
+
+// findRoute executes path finding for the given request against the passed
+// graph and creates a route with absolute time lock values from the result.
+func findRoute(ctx context.Context, g Graph, bandwidthHints bandwidthHints,
+	cfg *PathFindingConfig, self route.Vertex, req *RouteRequest,
+	currentHeight int32) (*route.Route, float64, error) {
+
 	// Now that we know the destination is reachable within the graph, we'll
 	// execute our path finding algorithm.
 	finalHtlcExpiry := currentHeight + int32(req.FinalExpiry)
@@ -549,14 +600,13 @@ func (r *ChannelRouter) FindRoute(req *RouteRequest) (*route.Route, float64,
 	}
 
 	path, probability, err := findPath(
-		&graphParams{
+		ctx, &graphParams{
 			additionalEdges: req.RouteHints,
 			bandwidthHints:  bandwidthHints,
-			graph:           r.cfg.RoutingGraph,
+			graph:           g,
 		},
-		req.Restrictions, &r.cfg.PathFindingConfig,
-		r.cfg.SelfNode, req.Source, req.Target, req.Amount,
-		req.TimePreference, finalHtlcExpiry,
+		req.Restrictions, cfg, self, req.Source, req.Target,
+		req.Amount, req.TimePreference, finalHtlcExpiry,
 	)
 	if err != nil {
 		return nil, 0, err
@@ -582,6 +632,85 @@ func (r *ChannelRouter) FindRoute(req *RouteRequest) (*route.Route, float64,
 	return route, probability, nil
 }
 
+// FindRouteToAny attempts to query the ChannelRouter for the optimum path to
+// any of the given destination candidates, e.g. multiple nodes offering the
+// same service. A route is searched to each candidate, and the one with the
+// lowest combined fee and attempt cost, weighted by its success probability,
+// is returned together with the selected destination. Candidates that can't
+// be reached are skipped. The Target of the request is ignored.
+func (r *ChannelRouter) FindRouteToAny(ctx context.Context,
+	req *RouteRequest, candidates []route.Vertex) (*route.Route, float64,
+	route.Vertex, error) {
+
+	if len(candidates) == 0 {
+		return nil, 0, route.Vertex{}, ErrNoDestinationCandidates
+	}
+
+	if req.BlindedPathSet != nil {
+		return nil, 0, route.Vertex{}, ErrCandidatesAndBlinded
+	}
+
+	var (
+		bestRoute  *route.Route
+		bestProb   float64
+		bestTarget route.Vertex
+		bestDist   int64
+		firstErr   error
+	)
+	for _, target := range candidates {
+		targetReq := *req
+		targetReq.Target = target
+
+		rt, probability, err := r.FindRoute(ctx, &targetReq)
+
+		// Don't continue with the remaining candidates if the search
+		// was canceled.
+		if ctx.Err() != nil {
+			return nil, 0, route.Vertex{}, ctx.Err()
+		}
+
+		if err != nil {
+			log.Debugf("Unable to find route to destination "+
+				"candidate %v: %v", target, err)
+
+			if firstErr == nil {
+				firstErr = err
+			}
+
+			continue
+		}
+
+		// Rank the routes the same way path finding ranks the paths
+		// to a single destination.
+		attemptCost := getAbsoluteAttemptCost(
+			&r.cfg.PathFindingConfig, req.Amount,
+			req.TimePreference,
+		)
+		dist := getProbabilityBasedDist(
+			int64(rt.TotalFees()), probability, attemptCost,
+		)
+
+		if bestRoute == nil || dist < bestDist {
+			bestRoute = rt
+			bestProb = probability
+			bestTarget = target
+			bestDist = dist
+		}
+	}
+
+	if bestRoute == nil {
+		return nil, 0, route.Vertex{}, fmt.Errorf("unable to find "+
+			"route to any of %d destination candidates: %w",
+			len(candidates), firstErr)
+	}
+
+	log.Debugf("Selected destination candidate %v with success "+
+		"probability %v and fees %v", bestTarget, bestProb,
+		bestRoute.TotalFees())
+
+	return bestRoute, bestProb, bestTarget, nil
+}
+
 // probabilitySource defines the signature of a function that can be used to
 // query the success probability of sending a given amount between the two
 // given vertices.
@@ -879,6 +1008,10 @@ type LightningPayment struct {
 	// wire message and therefore do not affect the onion payload size.
 	FirstHopCustomRecords lnwire.CustomRecords
 
+	// PaymentGroup is an optional caller-supplied identifier that groups
+	// related payments, so that their updates can be tracked together.
+	PaymentGroup string
+
 	// MaxParts is the maximum number of partial payments that may be used
 	// to complete the full amount.
 	MaxParts uint32
@@ -1055,6 +1188,7 @@ func (r *ChannelRouter) PreparePayment(payment *LightningPayment) (
 		CreationTime:          r.cfg.Clock.Now(),
 		PaymentRequest:        payment.PaymentRequest,
 		FirstHopCustomRecords: payment.FirstHopCustomRecords,
+		PaymentGroup:          payment.PaymentGroup,
 	}
 
 	// Create a new ShardTracker that we'll use during the life cycle of
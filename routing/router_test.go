@@ -2,6 +2,7 @@ package routing
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"image/color"
 	"math"
@@ -270,7 +271,7 @@ func TestFindRoutesWithFeeLimit(t *testing.T) {
 	)
 	require.NoError(t, err, "invalid route request")
 
-	route, _, err := ctx.router.FindRoute(req)
+	route, _, err := ctx.router.FindRoute(context.Background(), req)
 	require.NoError(t, err, "unable to find any routes")
 
 	require.Falsef(t,
@@ -288,6 +289,88 @@ func TestFindRoutesWithFeeLimit(t *testing.T) {
 	)
 }
 
+// TestFindRouteCanceled asserts that path finding is aborted if the context
+// passed to FindRoute is canceled.
+func TestFindRouteCanceled(t *testing.T) {
+	t.Parallel()
+
+	const startingBlockHeight = 101
+	ctx := createTestCtxFromFile(t, startingBlockHeight, basicGraphFilePath)
+
+	target := ctx.aliases["sophon"]
+	paymentAmt := lnwire.NewMSatFromSatoshis(100)
+	restrictions := &RestrictParams{
+		FeeLimit:          noFeeLimit,
+		ProbabilitySource: noProbabilitySource,
+		CltvLimit:         math.MaxUint32,
+	}
+
+	req, err := NewRouteRequest(
+		ctx.router.cfg.SelfNode, &target, paymentAmt, 0,
+		restrictions, nil, nil, nil, MinCLTVDelta,
+	)
+	require.NoError(t, err, "invalid route request")
+
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _, err = ctx.router.FindRoute(cancelCtx, req)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+// TestFindRouteToAny asserts that the cheapest reachable destination is
+// selected from a set of destination candidates.
+func TestFindRouteToAny(t *testing.T) {
+	t.Parallel()
+
+	const startingBlockHeight = 101
+	ctx := createTestCtxFromFile(t, startingBlockHeight, basicGraphFilePath)
+
+	paymentAmt := lnwire.NewMSatFromSatoshis(100)
+	restrictions := &RestrictParams{
+		FeeLimit:          noFeeLimit,
+		ProbabilitySource: noProbabilitySource,
+		CltvLimit:         math.MaxUint32,
+	}
+
+	// The target of the request is replaced by the candidates.
+	target := ctx.aliases["satoshi"]
+	req, err := NewRouteRequest(
+		ctx.router.cfg.SelfNode, &target, paymentAmt, 0,
+		restrictions, nil, nil, nil, MinCLTVDelta,
+	)
+	require.NoError(t, err, "invalid route request")
+
+	// Both sophon and elst are reachable from roasbeef, but elst is only
+	// reachable through sophon, which makes the route to sophon cheaper.
+	// The unknown node can't be reached at all.
+	unknown := route.Vertex{1}
+	candidates := []route.Vertex{
+		ctx.aliases["elst"], unknown, ctx.aliases["sophon"],
+	}
+
+	rt, probability, selected, err := ctx.router.FindRouteToAny(
+		context.Background(), req, candidates,
+	)
+	require.NoError(t, err)
+	require.Equal(t, ctx.aliases["sophon"], selected)
+	require.Equal(
+		t, ctx.aliases["sophon"], rt.Hops[len(rt.Hops)-1].PubKeyBytes,
+	)
+	require.Positive(t, probability)
+
+	// If none of the candidates can be reached, an error is returned.
+	_, _, _, err = ctx.router.FindRouteToAny(
+		context.Background(), req, []route.Vertex{unknown},
+	)
+	require.Error(t, err)
+
+	_, _, _, err = ctx.router.FindRouteToAny(
+		context.Background(), req, nil,
+	)
+	require.ErrorIs(t, err, ErrNoDestinationCandidates)
+}
+
 // TestSendPaymentRouteFailureFallback tests that when sending a payment, if
 // one of the target routes is seen as unavailable, then the next route in the
 // queue is used instead. This process should continue until either a payment
@@ -2893,7 +2976,7 @@ func TestAddEdgeUnknownVertexes(t *testing.T) {
 		paymentAmt, 0, noRestrictions, nil, nil, nil, MinCLTVDelta,
 	)
 	require.NoError(t, err, "invalid route request")
-	_, _, err = ctx.router.FindRoute(req)
+	_, _, err = ctx.router.FindRoute(context.Background(), req)
 	require.NoError(t, err, "unable to find any routes")
 
 	// Now check that we can update the node info for the partial node
@@ -2932,7 +3015,7 @@ func TestAddEdgeUnknownVertexes(t *testing.T) {
 	)
 	require.NoError(t, err, "invalid route request")
 
-	_, _, err = ctx.router.FindRoute(req)
+	_, _, err = ctx.router.FindRoute(context.Background(), req)
 	require.NoError(t, err, "unable to find any routes")
 
 	copy1, err := ctx.graph.FetchLightningNode(pub1)
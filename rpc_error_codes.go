@@ -0,0 +1,167 @@
+package lnd
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/funding"
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
+	"github.com/lightningnetwork/lnd/routing"
+	"github.com/lightningnetwork/lnd/rpcperms"
+	"google.golang.org/grpc/codes"
+)
+
+// newRPCErrorCodeMapper creates the mapper that attaches error codes to the
+// errors returned by all of lnd's RPC servers. It maps the internal errors
+// that clients commonly need to handle to their stable error code.
+func newRPCErrorCodeMapper() *lnrpc.ErrorCodeMapper {
+	mappings := rpcperms.ErrorMappings()
+
+	mappings = append(mappings,
+		// Invoice errors.
+		lnrpc.MatchError(
+			invoices.ErrInvoiceNotFound,
+			lnrpc.ErrorCodeInvoiceNotFound, codes.NotFound,
+		),
+		lnrpc.MatchError(
+			invoices.ErrDuplicateInvoice,
+			lnrpc.ErrorCodeInvoiceAlreadyExists,
+			codes.AlreadyExists,
+		),
+		lnrpc.MatchError(
+			invoices.ErrDuplicatePayAddr,
+			lnrpc.ErrorCodeInvoiceAlreadyExists,
+			codes.AlreadyExists,
+		),
+		lnrpc.MatchError(
+			invoices.ErrInvoiceAlreadySettled,
+			lnrpc.ErrorCodeInvoiceAlreadySettled,
+			codes.FailedPrecondition,
+		),
+		lnrpc.MatchError(
+			invoices.ErrInvoiceAlreadyCanceled,
+			lnrpc.ErrorCodeInvoiceAlreadyCanceled,
+			codes.FailedPrecondition,
+		),
+		lnrpc.MatchError(
+			invoices.ErrInvoiceStillOpen,
+			lnrpc.ErrorCodeInvoiceInvalidState,
+			codes.FailedPrecondition,
+		),
+		lnrpc.MatchError(
+			invoices.ErrInvoiceCannotOpen,
+			lnrpc.ErrorCodeInvoiceInvalidState,
+			codes.FailedPrecondition,
+		),
+		lnrpc.MatchError(
+			invoices.ErrInvoiceCannotAccept,
+			lnrpc.ErrorCodeInvoiceInvalidState,
+			codes.FailedPrecondition,
+		),
+		lnrpc.MatchError(
+			invoices.ErrShuttingDown, lnrpc.ErrorCodeShuttingDown,
+			codes.Unavailable,
+		),
+
+		// Payment errors.
+		lnrpc.MatchError(
+			channeldb.ErrAlreadyPaid,
+			lnrpc.ErrorCodePaymentAlreadySucceeded,
+			codes.AlreadyExists,
+		),
+		lnrpc.MatchError(
+			channeldb.ErrPaymentInFlight,
+			lnrpc.ErrorCodePaymentInFlight, codes.AlreadyExists,
+		),
+		lnrpc.MatchError(
+			channeldb.ErrPaymentExists,
+			lnrpc.ErrorCodePaymentAlreadyExists,
+			codes.AlreadyExists,
+		),
+		lnrpc.MatchError(
+			routing.ErrHintsAndBlinded,
+			lnrpc.ErrorCodeInvalidRouteRequest,
+			codes.InvalidArgument,
+		),
+		lnrpc.MatchError(
+			routing.ErrExpiryAndBlinded,
+			lnrpc.ErrorCodeInvalidRouteRequest,
+			codes.InvalidArgument,
+		),
+		lnrpc.MatchError(
+			routing.ErrTargetAndBlinded,
+			lnrpc.ErrorCodeInvalidRouteRequest,
+			codes.InvalidArgument,
+		),
+		lnrpc.MatchError(
+			routing.ErrNoTarget, lnrpc.ErrorCodeInvalidRouteRequest,
+			codes.InvalidArgument,
+		),
+		lnrpc.MatchError(
+			routing.ErrRouterShuttingDown,
+			lnrpc.ErrorCodeShuttingDown, codes.Unavailable,
+		),
+
+		// Channel and graph errors.
+		lnrpc.MatchError(
+			channeldb.ErrChannelNotFound,
+			lnrpc.ErrorCodeChannelNotFound, codes.NotFound,
+		),
+		lnrpc.MatchError(
+			channeldb.ErrChanAlreadyExists,
+			lnrpc.ErrorCodeChannelAlreadyExists,
+			codes.AlreadyExists,
+		),
+		lnrpc.MatchError(
+			channeldb.ErrGraphNodeNotFound,
+			lnrpc.ErrorCodeNodeNotFound, codes.NotFound,
+		),
+		lnrpc.MatchError(
+			channeldb.ErrEdgeNotFound, lnrpc.ErrorCodeEdgeNotFound,
+			codes.NotFound,
+		),
+
+		// Funding errors.
+		lnrpc.ErrorMapping{
+			Match: func(err error) bool {
+				var e *chanfunding.ErrInsufficientFunds
+				return errors.As(err, &e)
+			},
+			Code:     lnrpc.ErrorCodeInsufficientFunds,
+			GRPCCode: codes.FailedPrecondition,
+		},
+		lnrpc.ErrorMapping{
+			Match: func(err error) bool {
+				var e lnwallet.ReservationError
+				return errors.As(err, &e)
+			},
+			Code:     lnrpc.ErrorCodeFundingPolicyViolation,
+			GRPCCode: codes.InvalidArgument,
+		},
+		lnrpc.MatchError(
+			funding.ErrConfirmationTimeout,
+			lnrpc.ErrorCodeFundingTimeout, codes.DeadlineExceeded,
+		),
+		lnrpc.MatchError(
+			funding.ErrFundingManagerShuttingDown,
+			lnrpc.ErrorCodeShuttingDown, codes.Unavailable,
+		),
+
+		// Context errors of canceled or timed out calls.
+		lnrpc.MatchError(
+			context.Canceled, lnrpc.ErrorCodeCanceled,
+			codes.Canceled,
+		),
+		lnrpc.MatchError(
+			context.DeadlineExceeded,
+			lnrpc.ErrorCodeDeadlineExceeded,
+			codes.DeadlineExceeded,
+		),
+	)
+
+	return lnrpc.NewErrorCodeMapper(mappings...)
+}
@@ -0,0 +1,41 @@
+package rpcperms
+
+import (
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"google.golang.org/grpc/codes"
+)
+
+// ErrorMappings returns the error code mappings of the errors returned by the
+// interceptor chain itself.
+func ErrorMappings() []lnrpc.ErrorMapping {
+	return []lnrpc.ErrorMapping{
+		lnrpc.MatchError(
+			ErrWaitingToStart, lnrpc.ErrorCodeServerStarting,
+			codes.Unavailable,
+		),
+		lnrpc.MatchError(
+			ErrRPCStarting, lnrpc.ErrorCodeServerStarting,
+			codes.Unavailable,
+		),
+		lnrpc.MatchError(
+			ErrNoWallet, lnrpc.ErrorCodeWalletNotCreated,
+			codes.FailedPrecondition,
+		),
+		lnrpc.MatchError(
+			ErrWalletLocked, lnrpc.ErrorCodeWalletLocked,
+			codes.FailedPrecondition,
+		),
+		lnrpc.MatchError(
+			ErrWalletUnlocked, lnrpc.ErrorCodeWalletAlreadyUnlocked,
+			codes.FailedPrecondition,
+		),
+		lnrpc.MatchError(
+			ErrShuttingDown, lnrpc.ErrorCodeShuttingDown,
+			codes.Unavailable,
+		),
+		lnrpc.MatchError(
+			ErrTimeoutReached, lnrpc.ErrorCodeDeadlineExceeded,
+			codes.DeadlineExceeded,
+		),
+	}
+}
@@ -189,6 +189,9 @@ type InterceptorChain struct {
 	// middleware crashes.
 	mandatoryMiddleware []string
 
+	// errorCodes attaches error codes to the errors returned by RPC calls.
+	errorCodes *lnrpc.ErrorCodeMapper
+
 	quit chan struct{}
 	sync.RWMutex
 }
@@ -199,7 +202,14 @@ var _ lnrpc.StateServer = (*InterceptorChain)(nil)
 
 // NewInterceptorChain creates a new InterceptorChain.
 func NewInterceptorChain(log btclog.Logger, noMacaroons bool,
-	mandatoryMiddleware []string) *InterceptorChain {
+	mandatoryMiddleware []string,
+	errorCodes *lnrpc.ErrorCodeMapper) *InterceptorChain {
+
+	// Without any mappings, errors still get the generic code of their
+	// gRPC status code.
+	if errorCodes == nil {
+		errorCodes = lnrpc.NewErrorCodeMapper()
+	}
 
 	return &InterceptorChain{
 		state:                     waitingToStart,
@@ -209,6 +219,7 @@ func NewInterceptorChain(log btclog.Logger, noMacaroons bool,
 		rpcsLog:                   log,
 		registeredMiddlewareNames: make(map[string]int),
 		mandatoryMiddleware:       mandatoryMiddleware,
+		errorCodes:                errorCodes,
 		quit:                      make(chan struct{}),
 	}
 }
@@ -536,7 +547,18 @@ func (r *InterceptorChain) CreateServerOpts() []grpc.ServerOption {
 	var unaryInterceptors []grpc.UnaryServerInterceptor
 	var strmInterceptors []grpc.StreamServerInterceptor
 
-	// The first interceptors we'll add to the chain is our logging
+	// The outermost interceptors attach error codes to all errors,
+	// including the ones returned by the other interceptors in the chain.
+	unaryInterceptors = append(
+		unaryInterceptors,
+		errorCodeUnaryServerInterceptor(r.errorCodes),
+	)
+	strmInterceptors = append(
+		strmInterceptors,
+		errorCodeStreamServerInterceptor(r.errorCodes),
+	)
+
+	// The next interceptors we'll add to the chain is our logging
 	// interceptors, so we can automatically log all errors that happen
 	// during RPC calls.
 	unaryInterceptors = append(
@@ -595,6 +617,33 @@ func (r *InterceptorChain) CreateServerOpts() []grpc.ServerOption {
 	return serverOpts
 }
 
+// errorCodeUnaryServerInterceptor is a UnaryServerInterceptor that attaches
+// an error code to any error returned by a unary request.
+func errorCodeUnaryServerInterceptor(
+	mapper *lnrpc.ErrorCodeMapper) grpc.UnaryServerInterceptor {
+
+	return func(ctx context.Context, req interface{},
+		_ *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+
+		resp, err := handler(ctx, req)
+
+		return resp, mapper.AttachCode(err)
+	}
+}
+
+// errorCodeStreamServerInterceptor is a StreamServerInterceptor that attaches
+// an error code to any error returned by a streaming RPC.
+func errorCodeStreamServerInterceptor(
+	mapper *lnrpc.ErrorCodeMapper) grpc.StreamServerInterceptor {
+
+	return func(srv interface{}, ss grpc.ServerStream,
+		_ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+
+		return mapper.AttachCode(handler(srv, ss))
+	}
+}
+
 // errorLogUnaryServerInterceptor is a simple UnaryServerInterceptor that will
 // automatically log any errors that occur when serving a client's unary
 // request.
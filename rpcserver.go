@@ -12,7 +12,6 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
-	"runtime"
 	"sort"
 	"strconv"
 	"strings"
@@ -44,6 +43,7 @@ import (
 	"github.com/lightningnetwork/lnd/channeldb/graphsession"
 	"github.com/lightningnetwork/lnd/channeldb/models"
 	"github.com/lightningnetwork/lnd/channelnotifier"
+	"github.com/lightningnetwork/lnd/clock"
 	"github.com/lightningnetwork/lnd/contractcourt"
 	"github.com/lightningnetwork/lnd/discovery"
 	"github.com/lightningnetwork/lnd/feature"
@@ -73,13 +73,16 @@ import (
 	"github.com/lightningnetwork/lnd/macaroons"
 	"github.com/lightningnetwork/lnd/peer"
 	"github.com/lightningnetwork/lnd/peernotifier"
+	"github.com/lightningnetwork/lnd/pool"
 	"github.com/lightningnetwork/lnd/record"
 	"github.com/lightningnetwork/lnd/routing"
 	"github.com/lightningnetwork/lnd/routing/blindedpath"
+	"github.com/lightningnetwork/lnd/routing/localchans"
 	"github.com/lightningnetwork/lnd/routing/route"
 	"github.com/lightningnetwork/lnd/rpcperms"
 	"github.com/lightningnetwork/lnd/signal"
 	"github.com/lightningnetwork/lnd/sweep"
+	"github.com/lightningnetwork/lnd/ticker"
 	"github.com/lightningnetwork/lnd/tlv"
 	"github.com/lightningnetwork/lnd/watchtower"
 	"github.com/lightningnetwork/lnd/zpay32"
@@ -310,6 +313,26 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "peers",
 			Action: "write",
 		}},
+		"/lnrpc.Lightning/AllowPeer": {{
+			Entity: "peers",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/DisallowPeer": {{
+			Entity: "peers",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/ListAllowedPeers": {{
+			Entity: "peers",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/GetGossipEgressLimits": {{
+			Entity: "peers",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/UpdateGossipEgressLimits": {{
+			Entity: "peers",
+			Action: "write",
+		}},
 		"/lnrpc.Lightning/OpenChannel": {{
 			Entity: "onchain",
 			Action: "write",
@@ -363,6 +386,18 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "info",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/CheckDBIntegrity": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/PruneDBOrphans": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/CreateDBSnapshot": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
 		"/lnrpc.Lightning/ListPeers": {{
 			Entity: "peers",
 			Action: "read",
@@ -467,6 +502,14 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "info",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/SubscribeGossipBridge": {{
+			Entity: "info",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/InjectGossip": {{
+			Entity: "peers",
+			Action: "write",
+		}},
 		"/lnrpc.Lightning/ListPayments": {{
 			Entity: "offchain",
 			Action: "read",
@@ -483,6 +526,14 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "info",
 			Action: "write",
 		}},
+		"/lnrpc.Lightning/GetWorkerPoolStats": {{
+			Entity: "info",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/ResizeWorkerPool": {{
+			Entity: "info",
+			Action: "write",
+		}},
 		"/lnrpc.Lightning/DecodePayReq": {{
 			Entity: "offchain",
 			Action: "read",
@@ -495,6 +546,14 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/lnrpc.Lightning/SuggestFees": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/ApplyFeeSuggestions": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
 		"/lnrpc.Lightning/ForwardingHistory": {{
 			Entity: "offchain",
 			Action: "read",
@@ -519,6 +578,10 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/BackupReplicationStatus": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/ChannelAcceptor": {{
 			Entity: "onchain",
 			Action: "write",
@@ -573,6 +636,29 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/CommitmentFeeInfo": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/ExportResolutions": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/TimeLockedFunds": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/ListPendingResolutions": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/AuditScidLeaks": {{
+			Entity: "invoices",
+			Action: "read",
+		}, {
+			Entity: "offchain",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/ListAliases": {{
 			Entity: "offchain",
 			Action: "read",
@@ -622,6 +708,10 @@ type rpcServer struct {
 	// method.
 	chanPredicate chanacceptor.MultiplexAcceptor
 
+	// invoiceTemplates manages the invoice templates that invoices can be
+	// minted from, either on demand or on a recurring schedule.
+	invoiceTemplates *invoicesrpc.TemplateManager
+
 	quit chan struct{}
 
 	// macService is the macaroon service that we need to mint new
@@ -729,12 +819,14 @@ func (r *rpcServer) addDeps(s *server, macService *macaroons.Service,
 			return info.NodeKey1Bytes, info.NodeKey2Bytes, nil
 		},
 		FindRoute:              s.chanRouter.FindRoute,
+		FindRouteToAny:         s.chanRouter.FindRouteToAny,
 		MissionControl:         s.defaultMC,
 		ActiveNetParams:        r.cfg.ActiveNetParams.Params,
 		Tower:                  s.controlTower,
 		MaxTotalTimelock:       r.cfg.MaxOutgoingCltvExpiry,
 		DefaultFinalCltvDelta:  uint16(r.cfg.Bitcoin.TimeLockDelta),
 		SubscribeHtlcEvents:    s.htlcNotifier.SubscribeHtlcEvents,
+		ForwardLatencies:       s.htlcNotifier.ForwardLatencies,
 		InterceptableForwarder: s.interceptableSwitch,
 		SetChannelEnabled: func(outpoint wire.OutPoint) error {
 			return s.chanStatusMgr.RequestEnable(outpoint, true)
@@ -742,8 +834,11 @@ func (r *rpcServer) addDeps(s *server, macService *macaroons.Service,
 		SetChannelDisabled: func(outpoint wire.OutPoint) error {
 			return s.chanStatusMgr.RequestDisable(outpoint, true)
 		},
-		SetChannelAuto:     s.chanStatusMgr.RequestAuto,
-		UseStatusInitiated: subServerCgs.RouterRPC.UseStatusInitiated,
+		SetChannelAuto:      s.chanStatusMgr.RequestAuto,
+		ScheduleMaintenance: s.chanStatusMgr.ScheduleMaintenance,
+		CancelMaintenance:   s.chanStatusMgr.CancelMaintenance,
+		MaintenanceWindows:  s.chanStatusMgr.MaintenanceWindows,
+		UseStatusInitiated:  subServerCgs.RouterRPC.UseStatusInitiated,
 		ParseCustomChannelData: func(msg proto.Message) error {
 			err = fn.MapOptionZ(
 				r.server.implCfg.AuxDataParser,
@@ -776,6 +871,19 @@ func (r *rpcServer) addDeps(s *server, macService *macaroons.Service,
 		subServerPerms []lnrpc.MacaroonPerms
 	)
 
+	// The invoice template manager is shared with the invoices
+	// sub-server, so it needs to be created before the sub-servers.
+	r.invoiceTemplates = invoicesrpc.NewTemplateManager(
+		&invoicesrpc.TemplateManagerConfig{
+			DB:         s.chanStateDB.GetParentDB(),
+			AddInvoice: r.AddInvoice,
+			Clock:      clock.NewDefaultClock(),
+			Ticker: ticker.New(
+				invoicesrpc.DefaultTemplateCheckInterval,
+			),
+		},
+	)
+
 	// Before we create any of the sub-servers, we need to ensure that all
 	// the dependencies they need are properly populated within each sub
 	// server configuration struct.
@@ -784,12 +892,13 @@ func (r *rpcServer) addDeps(s *server, macService *macaroons.Service,
 	err = subServerCgs.PopulateDependencies(
 		r.cfg, s.cc, r.cfg.networkDir, macService, atpl, invoiceRegistry,
 		s.htlcSwitch, r.cfg.ActiveNetParams.Params, s.chanRouter,
-		routerBackend, s.nodeSigner, s.graphDB, s.chanStateDB,
-		s.sweeper, tower, s.towerClientMgr, r.cfg.net.ResolveTCPAddr,
-		genInvoiceFeatures, genAmpInvoiceFeatures,
-		s.getNodeAnnouncement, s.updateAndBrodcastSelfNode, parseAddr,
-		rpcsLog, s.aliasMgr, r.implCfg.AuxDataParser,
-		invoiceHtlcModifier,
+		s.graphBuilder, routerBackend, s.nodeSigner, s.graphDB,
+		s.chanStateDB, s.sweeper, tower, s.towerClientMgr,
+		r.cfg.net.ResolveTCPAddr, genInvoiceFeatures,
+		genAmpInvoiceFeatures, s.getNodeAnnouncement,
+		s.updateAndBrodcastSelfNode, parseAddr, rpcsLog, s.aliasMgr,
+		r.implCfg.AuxDataParser, invoiceHtlcModifier, s.offersMgr,
+		r.invoiceTemplates,
 	)
 	if err != nil {
 		return err
@@ -931,6 +1040,11 @@ func (r *rpcServer) Start() error {
 		}
 	}
 
+	if err := r.invoiceTemplates.Start(); err != nil {
+		return fmt.Errorf("unable to start invoice template manager: "+
+			"%w", err)
+	}
+
 	return nil
 }
 
@@ -991,6 +1105,11 @@ func (r *rpcServer) Stop() error {
 
 	close(r.quit)
 
+	if err := r.invoiceTemplates.Stop(); err != nil {
+		rpcsLog.Errorf("unable to stop invoice template manager: %v",
+			err)
+	}
+
 	// After we've signalled all of our active goroutines to exit, we'll
 	// then do the same to signal a graceful shutdown of all the sub
 	// servers.
@@ -1183,8 +1302,20 @@ func (r *rpcServer) ListUnspent(ctx context.Context,
 	rpcsLog.Debugf("[listunspent] min=%v%v, generated utxos: %v", minConfs,
 		maxStr, utxos)
 
+	obligations, err := r.balanceObligations()
+	if err != nil {
+		return nil, err
+	}
+
+	var utxoBalance btcutil.Amount
+	for _, utxo := range utxos {
+		utxoBalance += utxo.Value
+	}
+
 	return &lnrpc.ListUnspentResponse{
-		Utxos: rpcUtxos,
+		Utxos:            rpcUtxos,
+		Obligations:      marshalBalanceObligations(obligations),
+		SpendableBalance: int64(obligations.Spendable(utxoBalance)),
 	}, nil
 }
 
@@ -1887,6 +2018,163 @@ func (r *rpcServer) DisconnectPeer(ctx context.Context,
 	return &lnrpc.DisconnectPeerResponse{}, nil
 }
 
+// parseAllowListPeer parses the hex-encoded compressed public key of a peer on
+// the allow list.
+func parseAllowListPeer(pubKeyStr string) (route.Vertex, error) {
+	pubKeyBytes, err := hex.DecodeString(pubKeyStr)
+	if err != nil {
+		return route.Vertex{}, fmt.Errorf("unable to decode pubkey "+
+			"bytes: %w", err)
+	}
+
+	pubKey, err := btcec.ParsePubKey(pubKeyBytes)
+	if err != nil {
+		return route.Vertex{}, fmt.Errorf("unable to parse pubkey: %w",
+			err)
+	}
+
+	return route.NewVertex(pubKey), nil
+}
+
+// AllowPeer adds a peer to the allow list.
+func (r *rpcServer) AllowPeer(_ context.Context,
+	in *lnrpc.AllowPeerRequest) (*lnrpc.AllowPeerResponse, error) {
+
+	rpcsLog.Debugf("[allowpeer] peer(%s)", in.PubKey)
+
+	pubKey, err := parseAllowListPeer(in.PubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.server.AllowPeer(pubKey); err != nil {
+		return nil, fmt.Errorf("unable to allow peer: %w", err)
+	}
+
+	return &lnrpc.AllowPeerResponse{}, nil
+}
+
+// DisallowPeer removes a peer from the allow list, and disconnects from it if
+// we're connected to it.
+func (r *rpcServer) DisallowPeer(_ context.Context,
+	in *lnrpc.DisallowPeerRequest) (*lnrpc.DisallowPeerResponse, error) {
+
+	rpcsLog.Debugf("[disallowpeer] peer(%s)", in.PubKey)
+
+	pubKey, err := parseAllowListPeer(in.PubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.server.DisallowPeer(pubKey); err != nil {
+		return nil, fmt.Errorf("unable to disallow peer: %w", err)
+	}
+
+	return &lnrpc.DisallowPeerResponse{}, nil
+}
+
+// ListAllowedPeers returns all peers on the allow list.
+func (r *rpcServer) ListAllowedPeers(_ context.Context,
+	_ *lnrpc.ListAllowedPeersRequest) (*lnrpc.ListAllowedPeersResponse,
+	error) {
+
+	peers, err := r.server.ListAllowedPeers()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.ListAllowedPeersResponse{
+		PubKeys: make([]string, 0, len(peers)),
+	}
+	for _, peer := range peers {
+		resp.PubKeys = append(resp.PubKeys, peer.String())
+	}
+
+	return resp, nil
+}
+
+// GetGossipEgressLimits returns the limits currently enforced on the gossip we
+// proactively send to our peers.
+func (r *rpcServer) GetGossipEgressLimits(_ context.Context,
+	_ *lnrpc.GetGossipEgressLimitsRequest) (*lnrpc.GossipEgressLimits,
+	error) {
+
+	cfg := r.server.authGossiper.SyncManager().EgressConfig()
+
+	return marshalGossipEgressLimits(cfg), nil
+}
+
+// UpdateGossipEgressLimits replaces the limits enforced on the gossip we
+// proactively send to our peers.
+func (r *rpcServer) UpdateGossipEgressLimits(_ context.Context,
+	in *lnrpc.GossipEgressLimits) (*lnrpc.UpdateGossipEgressLimitsResponse,
+	error) {
+
+	cfg, err := unmarshalGossipEgressLimits(in)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Infof("[updategossipegress] global=%d B/s, per-peer=%d B/s, "+
+		"%d passive peers", cfg.MaxBytesPerSec, cfg.PeerBytesPerSec,
+		len(cfg.PassivePeers))
+
+	err = r.server.authGossiper.SyncManager().UpdateEgressConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.UpdateGossipEgressLimitsResponse{}, nil
+}
+
+// marshalGossipEgressLimits converts the gossip egress config into its RPC
+// representation.
+func marshalGossipEgressLimits(
+	cfg discovery.EgressConfig) *lnrpc.GossipEgressLimits {
+
+	limits := &lnrpc.GossipEgressLimits{
+		MaxBytesPerSec:  cfg.MaxBytesPerSec,
+		MaxBurstBytes:   cfg.MaxBurstBytes,
+		PeerBytesPerSec: cfg.PeerBytesPerSec,
+		PeerBurstBytes:  cfg.PeerBurstBytes,
+		PassivePeers:    make([]string, 0, len(cfg.PassivePeers)),
+	}
+	for _, peer := range cfg.PassivePeers {
+		limits.PassivePeers = append(limits.PassivePeers, peer.String())
+	}
+
+	return limits
+}
+
+// unmarshalGossipEgressLimits converts the RPC gossip egress limits into a
+// validated gossip egress config.
+func unmarshalGossipEgressLimits(
+	limits *lnrpc.GossipEgressLimits) (discovery.EgressConfig, error) {
+
+	numPassive := len(limits.PassivePeers)
+	cfg := discovery.EgressConfig{
+		MaxBytesPerSec:  limits.MaxBytesPerSec,
+		MaxBurstBytes:   limits.MaxBurstBytes,
+		PeerBytesPerSec: limits.PeerBytesPerSec,
+		PeerBurstBytes:  limits.PeerBurstBytes,
+		PassivePeers:    make([]route.Vertex, 0, numPassive),
+	}
+	for _, pubKeyStr := range limits.PassivePeers {
+		peer, err := route.NewVertexFromStr(pubKeyStr)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid passive peer %v: %w",
+				pubKeyStr, err)
+		}
+		cfg.PassivePeers = append(cfg.PassivePeers, peer)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
+
 // newFundingShimAssembler returns a new fully populated
 // chanfunding.CannedAssembler using a FundingShim obtained from an RPC caller.
 func newFundingShimAssembler(chanPointShim *lnrpc.ChanPointShim, initiator bool,
@@ -3016,7 +3304,7 @@ func createRPCCloseUpdate(
 // abandonChanFromGraph attempts to remove a channel from the channel graph. If
 // we can't find the chanID in the graph, then we assume it has already been
 // removed, and will return a nop.
-func abandonChanFromGraph(chanGraph *channeldb.ChannelGraph,
+func abandonChanFromGraph(chanGraph channeldb.GraphStore,
 	chanPoint *wire.OutPoint) error {
 
 	// First, we'll obtain the channel ID. If we can't locate this, then
@@ -3338,6 +3626,103 @@ func (r *rpcServer) GetRecoveryInfo(ctx context.Context,
 	}, nil
 }
 
+// CheckDBIntegrity validates the references between the channel graph and the
+// channel state database and reports any orphaned references.
+func (r *rpcServer) CheckDBIntegrity(_ context.Context,
+	_ *lnrpc.CheckDBIntegrityRequest) (*lnrpc.CheckDBIntegrityResponse,
+	error) {
+
+	report, err := channeldb.CheckIntegrity(
+		r.server.graphDB, r.server.chanStateDB,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to check DB integrity: %w", err)
+	}
+
+	edges, fwdPkgs := marshalIntegrityReport(report)
+
+	return &lnrpc.CheckDBIntegrityResponse{
+		OrphanedEdges:   edges,
+		OrphanedFwdPkgs: fwdPkgs,
+	}, nil
+}
+
+// PruneDBOrphans checks the references between the channel graph and the
+// channel state database and removes any orphaned references.
+func (r *rpcServer) PruneDBOrphans(_ context.Context,
+	_ *lnrpc.PruneDBOrphansRequest) (*lnrpc.PruneDBOrphansResponse,
+	error) {
+
+	graph, chanState := r.server.graphDB, r.server.chanStateDB
+
+	report, err := channeldb.CheckIntegrity(graph, chanState)
+	if err != nil {
+		return nil, fmt.Errorf("unable to check DB integrity: %w", err)
+	}
+
+	removed, err := channeldb.RemoveOrphans(graph, chanState, report)
+	if err != nil {
+		return nil, fmt.Errorf("unable to remove orphaned "+
+			"references: %w", err)
+	}
+
+	rpcsLog.Infof("[prunedborphans] removed %d orphaned graph edges and "+
+		"forwarding packages of %d channels",
+		len(removed.OrphanedEdges), len(removed.OrphanedFwdPkgs))
+
+	edges, fwdPkgs := marshalIntegrityReport(removed)
+
+	return &lnrpc.PruneDBOrphansResponse{
+		RemovedEdges:   edges,
+		RemovedFwdPkgs: fwdPkgs,
+	}, nil
+}
+
+// CreateDBSnapshot takes a consistent snapshot of the local bolt databases
+// while we keep running.
+func (r *rpcServer) CreateDBSnapshot(_ context.Context,
+	_ *lnrpc.CreateDBSnapshotRequest) (*lnrpc.CreateDBSnapshotResponse,
+	error) {
+
+	if r.server.dbSnapshotter == nil {
+		return nil, errors.New("database snapshots are only " +
+			"supported with the bolt database backend")
+	}
+
+	dir, err := r.server.dbSnapshotter.Snapshot()
+	if err != nil {
+		return nil, fmt.Errorf("unable to take database snapshot: %w",
+			err)
+	}
+
+	rpcsLog.Infof("[createdbsnapshot] took database snapshot in %v", dir)
+
+	return &lnrpc.CreateDBSnapshotResponse{
+		SnapshotDir: dir,
+	}, nil
+}
+
+// marshalIntegrityReport converts the orphaned references of an integrity
+// report into their RPC representation.
+func marshalIntegrityReport(report *channeldb.IntegrityReport) (
+	[]*lnrpc.OrphanedGraphEdge, []uint64) {
+
+	edges := make([]*lnrpc.OrphanedGraphEdge, 0, len(report.OrphanedEdges))
+	for _, edge := range report.OrphanedEdges {
+		edges = append(edges, &lnrpc.OrphanedGraphEdge{
+			ChanId:       edge.ChannelID,
+			ChannelPoint: edge.ChannelPoint.String(),
+		})
+	}
+
+	fwdPkgs := make([]uint64, 0, len(report.OrphanedFwdPkgs))
+	for _, source := range report.OrphanedFwdPkgs {
+		fwdPkgs = append(fwdPkgs, source.ToUint64())
+	}
+
+	return edges, fwdPkgs
+}
+
 // ListPeers returns a verbose listing of all currently active peers.
 func (r *rpcServer) ListPeers(ctx context.Context,
 	in *lnrpc.ListPeersRequest) (*lnrpc.ListPeersResponse, error) {
@@ -3488,23 +3873,9 @@ func (r *rpcServer) SubscribePeerEvents(req *lnrpc.PeerEventSubscription,
 		// marshal it into the form expected by the gRPC client, then
 		// send it off to the client.
 		case e := <-peerEventSub.Updates():
-			var event *lnrpc.PeerEvent
-
-			switch peerEvent := e.(type) {
-			case peernotifier.PeerOfflineEvent:
-				event = &lnrpc.PeerEvent{
-					PubKey: hex.EncodeToString(peerEvent.PubKey[:]),
-					Type:   lnrpc.PeerEvent_PEER_OFFLINE,
-				}
-
-			case peernotifier.PeerOnlineEvent:
-				event = &lnrpc.PeerEvent{
-					PubKey: hex.EncodeToString(peerEvent.PubKey[:]),
-					Type:   lnrpc.PeerEvent_PEER_ONLINE,
-				}
-
-			default:
-				return fmt.Errorf("unexpected peer event: %v", event)
+			event, err := marshalPeerEvent(e)
+			if err != nil {
+				return err
 			}
 
 			if err := eventStream.Send(event); err != nil {
@@ -3526,6 +3897,72 @@ func (r *rpcServer) SubscribePeerEvents(req *lnrpc.PeerEventSubscription,
 	}
 }
 
+// marshalPeerEvent converts a peer notifier event into its RPC counterpart.
+func marshalPeerEvent(e interface{}) (*lnrpc.PeerEvent, error) {
+	switch peerEvent := e.(type) {
+	case peernotifier.PeerOfflineEvent:
+		event := &lnrpc.PeerEvent{
+			PubKey:  hex.EncodeToString(peerEvent.PubKey[:]),
+			Type:    lnrpc.PeerEvent_PEER_OFFLINE,
+			Inbound: peerEvent.Conn.Inbound,
+			DisconnectReason: marshalDisconnectReason(
+				peerEvent.Reason,
+			),
+		}
+		if peerEvent.Conn.Address != nil {
+			event.Address = peerEvent.Conn.Address.String()
+		}
+		if peerEvent.Err != nil {
+			event.DisconnectError = peerEvent.Err.Error()
+		}
+
+		return event, nil
+
+	case peernotifier.PeerOnlineEvent:
+		event := &lnrpc.PeerEvent{
+			PubKey:  hex.EncodeToString(peerEvent.PubKey[:]),
+			Type:    lnrpc.PeerEvent_PEER_ONLINE,
+			Inbound: peerEvent.Conn.Inbound,
+		}
+		if peerEvent.Conn.Address != nil {
+			event.Address = peerEvent.Conn.Address.String()
+		}
+		if peerEvent.Features != nil {
+			event.Features = invoicesrpc.CreateRPCFeatures(
+				peerEvent.Features,
+			)
+		}
+
+		return event, nil
+
+	default:
+		return nil, fmt.Errorf("unexpected peer event: %v", e)
+	}
+}
+
+// marshalDisconnectReason converts a disconnect reason into its RPC
+// counterpart.
+func marshalDisconnectReason(
+	reason peernotifier.DisconnectReason) lnrpc.PeerEvent_DisconnectReason {
+
+	switch reason {
+	case peernotifier.DisconnectReasonLocal:
+		return lnrpc.PeerEvent_DISCONNECT_LOCAL
+
+	case peernotifier.DisconnectReasonPingTimeout:
+		return lnrpc.PeerEvent_DISCONNECT_PING_TIMEOUT
+
+	case peernotifier.DisconnectReasonProtocolError:
+		return lnrpc.PeerEvent_DISCONNECT_PROTOCOL_ERROR
+
+	case peernotifier.DisconnectReasonRemoteClosed:
+		return lnrpc.PeerEvent_DISCONNECT_REMOTE_CLOSED
+
+	default:
+		return lnrpc.PeerEvent_DISCONNECT_UNKNOWN
+	}
+}
+
 // WalletBalance returns total unspent outputs(confirmed and unconfirmed), all
 // confirmed unspent outputs and all unconfirmed unspent outputs under control
 // by the wallet. This method can be modified by having the request specify
@@ -3623,31 +4060,79 @@ func (r *rpcServer) WalletBalance(ctx context.Context,
 		lockedBalance += btcutil.Amount(leasedOutput.Value)
 	}
 
-	// Get the current number of non-private anchor channels.
-	currentNumAnchorChans, err := r.server.cc.Wallet.CurrentNumAnchorChans()
+	// Get the required anchor reserve along with the other funds that
+	// need to stay available for our channels.
+	obligations, err := r.balanceObligations()
 	if err != nil {
 		return nil, err
 	}
-
-	// Get the required reserve for the wallet.
-	requiredReserve := r.server.cc.Wallet.RequiredReserve(
-		uint32(currentNumAnchorChans),
-	)
+	spendableBalance := obligations.Spendable(confirmedBalance)
+	rpcObligations := marshalBalanceObligations(obligations)
 
 	rpcsLog.Debugf("[walletbalance] Total balance=%v (confirmed=%v, "+
-		"unconfirmed=%v)", totalBalance, confirmedBalance,
-		unconfirmedBalance)
+		"unconfirmed=%v), obligations: %v, spendable=%v", totalBalance,
+		confirmedBalance, unconfirmedBalance, obligations,
+		spendableBalance)
 
 	return &lnrpc.WalletBalanceResponse{
 		TotalBalance:              int64(totalBalance),
 		ConfirmedBalance:          int64(confirmedBalance),
 		UnconfirmedBalance:        int64(unconfirmedBalance),
 		LockedBalance:             int64(lockedBalance),
-		ReservedBalanceAnchorChan: int64(requiredReserve),
+		ReservedBalanceAnchorChan: int64(obligations.AnchorReserve),
 		AccountBalance:            rpcAccountBalances,
+		Obligations:               rpcObligations,
+		SpendableBalance:          int64(spendableBalance),
 	}, nil
 }
 
+// balanceObligations returns the breakdown of the on-chain funds that need to
+// stay available to keep our channels safe.
+func (r *rpcServer) balanceObligations() (*lnwallet.BalanceObligations,
+	error) {
+
+	// Get the current number of non-private anchor channels.
+	currentNumAnchorChans, err := r.server.cc.Wallet.CurrentNumAnchorChans()
+	if err != nil {
+		return nil, err
+	}
+
+	pendingInputs, err := r.server.sweeper.PendingInputs()
+	if err != nil {
+		return nil, err
+	}
+
+	obligations := &lnwallet.BalanceObligations{
+		AnchorReserve: r.server.cc.Wallet.RequiredReserve(
+			uint32(currentNumAnchorChans),
+		),
+	}
+	for _, pendingInput := range pendingInputs {
+		budget := pendingInput.WalletBudget()
+		if pendingInput.BroadcastAttempts > 0 {
+			obligations.SweepsInFlight += budget
+			continue
+		}
+
+		obligations.CPFPObligations += budget
+	}
+
+	return obligations, nil
+}
+
+// marshalBalanceObligations converts balance obligations into their RPC
+// representation.
+func marshalBalanceObligations(
+	obligations *lnwallet.BalanceObligations) *lnrpc.BalanceObligations {
+
+	return &lnrpc.BalanceObligations{
+		AnchorReserve:   int64(obligations.AnchorReserve),
+		SweepsInFlight:  int64(obligations.SweepsInFlight),
+		CpfpObligations: int64(obligations.CPFPObligations),
+		Total:           int64(obligations.Total()),
+	}
+}
+
 // ChannelBalance returns the total available channel flow across all open
 // channels in satoshis.
 func (r *rpcServer) ChannelBalance(ctx context.Context,
@@ -3777,12 +4262,77 @@ type (
 	pendingOpenChannels  []*lnrpc.PendingChannelsResponse_PendingOpenChannel
 	pendingForceClose    []*lnrpc.PendingChannelsResponse_ForceClosedChannel
 	waitingCloseChannels []*lnrpc.PendingChannelsResponse_WaitingCloseChannel
+	negotiatingChannel   = lnrpc.PendingChannelsResponse_NegotiatingChannel
+	negotiatingChannels  []*negotiatingChannel
 )
 
+// fetchPendingChannelStatuses queries the funding manager for the status of
+// all channels that are pending open. The statuses of channels awaiting the
+// confirmation of their funding transaction are returned by funding outpoint,
+// and the channels that are still being negotiated are returned in the format
+// of the PendingChannels RPC.
+func (r *rpcServer) fetchPendingChannelStatuses() (
+	map[wire.OutPoint]*funding.PendingChannelStatus, negotiatingChannels,
+	error) {
+
+	_, currentHeight, err := r.server.cc.ChainIO.GetBestBlock()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	statuses, err := r.server.fundingMgr.PendingChannelStatuses(
+		uint32(currentHeight),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	confStatuses := make(map[wire.OutPoint]*funding.PendingChannelStatus)
+	var negotiating negotiatingChannels
+	for _, status := range statuses {
+		switch status.Stage {
+		case funding.StageAwaitingConfirmation:
+			confStatuses[status.ChanPoint] = status
+
+		case funding.StageNegotiating:
+			negotiating = append(
+				negotiating, marshalNegotiatingChannel(status),
+			)
+		}
+	}
+
+	return confStatuses, negotiating, nil
+}
+
+// marshalNegotiatingChannel converts the status of a channel that is still
+// being negotiated into its RPC representation.
+func marshalNegotiatingChannel(
+	status *funding.PendingChannelStatus) *negotiatingChannel {
+
+	var chanPoint string
+	if status.ChanPoint != (wire.OutPoint{}) {
+		chanPoint = status.ChanPoint.String()
+	}
+
+	return &negotiatingChannel{
+		RemoteNodePub: hex.EncodeToString(
+			status.PeerKey.SerializeCompressed(),
+		),
+		ChannelPoint: chanPoint,
+		Initiator:    rpcInitiator(status.Initiator),
+		IdleSeconds:  int64(status.IdleTime.Seconds()),
+		NextAction:   status.NextAction,
+	}
+}
+
 // fetchPendingOpenChannels queries the database for a list of channels that
-// have pending open state. The returned result is used in the response of the
+// have pending open state. The timeout of each channel is taken from the
+// passed funding statuses. The returned result is used in the response of the
 // PendingChannels RPC.
-func (r *rpcServer) fetchPendingOpenChannels() (pendingOpenChannels, error) {
+func (r *rpcServer) fetchPendingOpenChannels(
+	confStatuses map[wire.OutPoint]*funding.PendingChannelStatus) (
+	pendingOpenChannels, error) {
+
 	// First, we'll populate the response with all the channels that are
 	// soon to be opened. We can easily fetch this data from the database
 	// and map the db struct to the proto response.
@@ -3821,8 +4371,9 @@ func (r *rpcServer) fetchPendingOpenChannels() (pendingOpenChannels, error) {
 		commitWeight := commitBaseWeight + witnessWeight
 
 		// FundingExpiryBlocks is the distance from the current block
-		// height to the broadcast height + MaxWaitNumBlocksFundingConf.
-		maxFundingHeight := funding.MaxWaitNumBlocksFundingConf +
+		// height to the broadcast height + the configured confirmation
+		// timeout.
+		maxFundingHeight := r.cfg.PendingChannels.ResponderConfTimeout +
 			pendingChan.BroadcastHeight()
 		fundingExpiryBlocks := int32(maxFundingHeight) - currentHeight
 
@@ -3853,6 +4404,12 @@ func (r *rpcServer) fetchPendingOpenChannels() (pendingOpenChannels, error) {
 			FundingExpiryBlocks: fundingExpiryBlocks,
 			// TODO(roasbeef): need to track confirmation height
 		}
+
+		status, ok := confStatuses[pendingChan.FundingOutpoint]
+		if ok {
+			result[i].TimeoutHeight = status.TimeoutHeight
+			result[i].NextAction = status.NextAction
+		}
 	}
 
 	return result, nil
@@ -4182,8 +4739,15 @@ func (r *rpcServer) PendingChannels(ctx context.Context,
 
 	resp := &lnrpc.PendingChannelsResponse{}
 
-	// First, we find all the channels that will soon be opened.
-	pendingOpenChannels, err := r.fetchPendingOpenChannels()
+	// First, we find all the channels that will soon be opened, along
+	// with the ones that are still being negotiated.
+	confStatuses, negotiating, err := r.fetchPendingChannelStatuses()
+	if err != nil {
+		return nil, err
+	}
+	resp.NegotiatingChannels = negotiating
+
+	pendingOpenChannels, err := r.fetchPendingOpenChannels(confStatuses)
 	if err != nil {
 		return nil, err
 	}
@@ -4433,26 +4997,223 @@ func (r *rpcServer) LookupHtlcResolution(
 	_ context.Context, in *lnrpc.LookupHtlcResolutionRequest) (
 	*lnrpc.LookupHtlcResolutionResponse, error) {
 
-	if !r.cfg.StoreFinalHtlcResolutions {
-		return nil, status.Error(codes.Unavailable, "cannot lookup "+
-			"with flag --store-final-htlc-resolutions=false")
+	if !r.cfg.StoreFinalHtlcResolutions {
+		return nil, status.Error(codes.Unavailable, "cannot lookup "+
+			"with flag --store-final-htlc-resolutions=false")
+	}
+
+	chanID := lnwire.NewShortChanIDFromInt(in.ChanId)
+
+	info, err := r.server.chanStateDB.LookupFinalHtlc(chanID, in.HtlcIndex)
+	switch {
+	case errors.Is(err, channeldb.ErrHtlcUnknown):
+		return nil, status.Error(codes.NotFound, err.Error())
+
+	case err != nil:
+		return nil, err
+	}
+
+	return &lnrpc.LookupHtlcResolutionResponse{
+		Settled:  info.Settled,
+		Offchain: info.Offchain,
+	}, nil
+}
+
+// CommitmentFeeInfo returns the weight, fee and set of dust HTLCs of the
+// current local or remote commitment transaction of a channel, along with the
+// same values at each of the requested alternative fee rates.
+func (r *rpcServer) CommitmentFeeInfo(_ context.Context,
+	in *lnrpc.CommitmentFeeInfoRequest) (*lnrpc.CommitmentFeeInfoResponse,
+	error) {
+
+	txid, err := lnrpc.GetChanPointFundingTxid(in.GetChannelPoint())
+	if err != nil {
+		return nil, err
+	}
+	chanPoint := wire.NewOutPoint(txid, in.ChannelPoint.OutputIndex)
+
+	dbChan, err := r.server.chanStateDB.FetchChannel(nil, *chanPoint)
+	switch {
+	case errors.Is(err, channeldb.ErrChannelNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+
+	case err != nil:
+		return nil, err
+	}
+
+	channel, err := lnwallet.NewLightningChannel(
+		r.server.cc.Signer, dbChan, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	whoseCommit := lntypes.Local
+	if in.RemoteCommit {
+		whoseCommit = lntypes.Remote
+	}
+
+	feeRates := make([]chainfee.SatPerKWeight, 0,
+		len(in.AlternativeSatPerKw))
+	for _, feeRate := range in.AlternativeSatPerKw {
+		feeRates = append(feeRates, chainfee.SatPerKWeight(feeRate))
+	}
+
+	info := channel.CommitmentFeeInfo(whoseCommit, feeRates...)
+
+	resp := &lnrpc.CommitmentFeeInfoResponse{
+		CommitHeight: info.CommitHeight,
+		Current:      marshallCommitmentFeeEstimate(&info.Current),
+		Alternatives: make(
+			[]*lnrpc.CommitmentFeeEstimate, 0,
+			len(info.Alternatives),
+		),
+	}
+	for i := range info.Alternatives {
+		resp.Alternatives = append(
+			resp.Alternatives,
+			marshallCommitmentFeeEstimate(&info.Alternatives[i]),
+		)
+	}
+
+	return resp, nil
+}
+
+// ExportResolutions returns a snapshot of all contracts that are currently
+// being resolved on-chain, signed by the node's identity key.
+func (r *rpcServer) ExportResolutions(_ context.Context,
+	_ *lnrpc.ExportResolutionsRequest) (*lnrpc.ExportResolutionsResponse,
+	error) {
+
+	snapshot, err := r.server.chainArb.ExportResolutions()
+	if err != nil {
+		return nil, err
+	}
+
+	signed, err := contractcourt.SignResolutionSnapshot(
+		snapshot, r.server.nodeSigner,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.ExportResolutionsResponse{
+		Snapshot:  signed.Snapshot,
+		Signature: signed.Signature,
+	}, nil
+}
+
+// TimeLockedFunds lists the outputs of force closed channels that are still
+// time-locked, together with the heights and projected times at which they can
+// be swept back to the wallet.
+func (r *rpcServer) TimeLockedFunds(_ context.Context,
+	_ *lnrpc.TimeLockedFundsRequest) (*lnrpc.TimeLockedFundsResponse,
+	error) {
+
+	funds, err := r.server.chainArb.TimeLockedFunds()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.TimeLockedFundsResponse{
+		BestHeight:     uint32(funds.BestHeight),
+		TotalAmountSat: int64(funds.TotalAmount),
+		Outputs: make(
+			[]*lnrpc.TimeLockedOutput, 0, len(funds.Outputs),
+		),
+	}
+	for _, output := range funds.Outputs {
+		rpcOutput := &lnrpc.TimeLockedOutput{
+			ChannelPoint:      output.ChanPoint.String(),
+			Outpoint:          output.Outpoint.String(),
+			OutputType:        output.Type.String(),
+			Stage:             output.Stage,
+			AmountSat:         int64(output.Amount),
+			MaturityHeight:    output.MaturityHeight,
+			BlocksTilMaturity: output.BlocksTilMaturity,
+		}
+		if !output.ProjectedMaturity.IsZero() {
+			rpcOutput.ProjectedMaturity =
+				output.ProjectedMaturity.Unix()
+		}
+
+		resp.Outputs = append(resp.Outputs, rpcOutput)
+	}
+
+	return resp, nil
+}
+
+// ListPendingResolutions lists all contracts that are currently being resolved
+// on-chain, together with their deadline heights and estimated sweep fees.
+func (r *rpcServer) ListPendingResolutions(_ context.Context,
+	_ *lnrpc.ListPendingResolutionsRequest) (
+	*lnrpc.ListPendingResolutionsResponse, error) {
+
+	resolutions, err := r.server.chainArb.ListPendingResolutions()
+	if err != nil {
+		return nil, err
 	}
 
-	chanID := lnwire.NewShortChanIDFromInt(in.ChanId)
+	resp := &lnrpc.ListPendingResolutionsResponse{
+		Resolutions: make(
+			[]*lnrpc.PendingResolution, 0, len(resolutions),
+		),
+	}
+	for _, resolution := range resolutions {
+		report := resolution.Report
+		witness := resolution.RequiredWitness
+		deadline := resolution.DeadlineHeight.UnwrapOr(0)
+
+		rpcResolution := &lnrpc.PendingResolution{
+			ChannelPoint:        resolution.ChanPoint.String(),
+			Outpoint:            report.Outpoint.String(),
+			OutputType:          report.Type.String(),
+			AmountSat:           int64(report.Amount),
+			LimboBalanceSat:     int64(report.LimboBalance),
+			RecoveredBalanceSat: int64(report.RecoveredBalance),
+			MaturityHeight:      report.MaturityHeight,
+			Stage:               report.Stage,
+			RequiredWitness:     witness.String(),
+			State:               resolution.State.String(),
+			DeadlineHeight:      deadline,
+			BlocksUntilDeadline: resolution.BlocksUntilDeadline,
+			SatPerKw:            uint64(resolution.FeeRate),
+			EstimatedSweepFeeSat: int64(
+				resolution.EstimatedSweepFee,
+			),
+		}
 
-	info, err := r.server.chanStateDB.LookupFinalHtlc(chanID, in.HtlcIndex)
-	switch {
-	case errors.Is(err, channeldb.ErrHtlcUnknown):
-		return nil, status.Error(codes.NotFound, err.Error())
+		resp.Resolutions = append(resp.Resolutions, rpcResolution)
+	}
 
-	case err != nil:
-		return nil, err
+	return resp, nil
+}
+
+// marshallCommitmentFeeEstimate converts a commitment fee estimate to its rpc
+// representation.
+func marshallCommitmentFeeEstimate(
+	est *lnwallet.CommitmentFeeEstimate) *lnrpc.CommitmentFeeEstimate {
+
+	rpcEst := &lnrpc.CommitmentFeeEstimate{
+		SatPerKw:            uint64(est.FeeRate),
+		Weight:              int64(est.Weight),
+		FeeSat:              int64(est.Fee),
+		DustHtlcs:           make([]*lnrpc.HTLC, 0, len(est.DustHtlcs)),
+		DustHtlcAmountSat:   int64(est.DustHtlcAmount),
+		InitiatorBalanceSat: int64(est.InitiatorBalance),
+		AboveReserve:        est.AboveReserve,
+	}
+	for _, htlc := range est.DustHtlcs {
+		rpcEst.DustHtlcs = append(rpcEst.DustHtlcs, &lnrpc.HTLC{
+			Incoming:         htlc.Incoming,
+			Amount:           int64(htlc.Amt.ToSatoshis()),
+			HashLock:         htlc.RHash[:],
+			ExpirationHeight: htlc.RefundTimeout,
+			HtlcIndex:        htlc.HtlcIndex,
+		})
 	}
 
-	return &lnrpc.LookupHtlcResolutionResponse{
-		Settled:  info.Settled,
-		Offchain: info.Offchain,
-	}, nil
+	return rpcEst
 }
 
 // ListChannels returns a description of all the open channels that this node
@@ -4691,6 +5452,10 @@ func createRPCOpenChannel(r *rpcServer, dbChannel *channeldb.OpenChannel,
 			err)
 	}
 
+	// The HTLCs we offer are limited by the remote party's max accepted
+	// HTLCs, and vice versa.
+	slotReserve := r.cfg.Htlcswitch.LocalHtlcSlotReserve
+
 	channel := &lnrpc.Channel{
 		Active:                isActive,
 		Private:               isPrivate(dbChannel),
@@ -4724,6 +5489,12 @@ func createRPCOpenChannel(r *rpcServer, dbChannel *channeldb.OpenChannel,
 		ZeroConfConfirmedScid: dbChannel.ZeroConfRealScid().ToUint64(),
 		Memo:                  string(dbChannel.Memo),
 		CustomChannelData:     customChanBytes,
+		LocalReservedHtlcSlots: uint32(htlcswitch.ReservedHtlcSlots(
+			slotReserve, dbChannel.RemoteChanCfg.MaxAcceptedHtlcs,
+		)),
+		RemoteReservedHtlcSlots: uint32(htlcswitch.ReservedHtlcSlots(
+			slotReserve, dbChannel.LocalChanCfg.MaxAcceptedHtlcs,
+		)),
 		// TODO: remove the following deprecated fields
 		CsvDelay:             uint32(dbChannel.LocalChanCfg.CsvDelay),
 		LocalChanReserveSat:  int64(dbChannel.LocalChanCfg.ChanReserve),
@@ -5210,6 +5981,19 @@ func (r *rpcServer) SubscribeChannelEvents(req *lnrpc.ChannelEventSubscription,
 
 				continue
 
+			case channelnotifier.StaleRemoteCloseEvent:
+				update = &lnrpc.ChannelEventUpdate{
+					Type: lnrpc.ChannelEventUpdate_STALE_REMOTE_CLOSE_CHANNEL,
+					Channel: &lnrpc.ChannelEventUpdate_StaleRemoteCloseChannel{
+						StaleRemoteCloseChannel: &lnrpc.ChannelPoint{
+							FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{
+								FundingTxidBytes: event.ChannelPoint.Hash[:],
+							},
+							OutputIndex: event.ChannelPoint.Index,
+						},
+					},
+				}
+
 			case channelnotifier.FullyResolvedChannelEvent:
 				update = &lnrpc.ChannelEventUpdate{
 					Type: lnrpc.ChannelEventUpdate_FULLY_RESOLVED_CHANNEL,
@@ -6110,6 +6894,10 @@ func (r *rpcServer) AddInvoice(ctx context.Context,
 		},
 	}
 
+	if deriver := r.server.preimageDeriver; deriver != nil {
+		addInvoiceCfg.DerivePreimage = deriver.DerivePreimage
+	}
+
 	value, err := lnrpc.UnmarshallAmt(invoice.Value, invoice.ValueMsat)
 	if err != nil {
 		return nil, err
@@ -6147,17 +6935,25 @@ func (r *rpcServer) AddInvoice(ctx context.Context,
 		}
 	}
 
+	overpaymentPolicy, err := invoicesrpc.UnmarshalOverpaymentPolicy(
+		invoice.OverpaymentPolicy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	addInvoiceData := &invoicesrpc.AddInvoiceData{
-		Memo:            invoice.Memo,
-		Value:           value,
-		DescriptionHash: invoice.DescriptionHash,
-		Expiry:          invoice.Expiry,
-		FallbackAddr:    invoice.FallbackAddr,
-		CltvExpiry:      invoice.CltvExpiry,
-		Private:         invoice.Private,
-		RouteHints:      routeHints,
-		Amp:             invoice.IsAmp,
-		BlindedPathCfg:  blindedPathCfg,
+		Memo:              invoice.Memo,
+		Value:             value,
+		DescriptionHash:   invoice.DescriptionHash,
+		Expiry:            invoice.Expiry,
+		FallbackAddr:      invoice.FallbackAddr,
+		CltvExpiry:        invoice.CltvExpiry,
+		Private:           invoice.Private,
+		RouteHints:        routeHints,
+		Amp:               invoice.IsAmp,
+		BlindedPathCfg:    blindedPathCfg,
+		OverpaymentPolicy: overpaymentPolicy,
 	}
 
 	if invoice.RPreimage != nil {
@@ -6321,6 +7117,72 @@ func (r *rpcServer) ListInvoices(ctx context.Context,
 	return resp, nil
 }
 
+// AuditScidLeaks scans the payment requests of our invoices for route hints
+// that reveal the confirmed SCID of an unannounced channel which negotiated
+// the option-scid-alias feature.
+func (r *rpcServer) AuditScidLeaks(ctx context.Context,
+	in *lnrpc.AuditScidLeaksRequest) (*lnrpc.AuditScidLeaksResponse,
+	error) {
+
+	leaks, err := r.scidLeaks(ctx, in.PendingOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.AuditScidLeaksResponse{
+		Leaks: make([]*lnrpc.ScidLeak, 0, len(leaks)),
+	}
+	for _, leak := range leaks {
+		resp.Leaks = append(resp.Leaks, &lnrpc.ScidLeak{
+			ChannelPoint: leak.ChanPoint.String(),
+			Scid:         leak.Scid.ToUint64(),
+			AddIndex:     leak.AddIndex,
+		})
+	}
+
+	return resp, nil
+}
+
+// scidLeaks returns the route hints of our invoices that reveal the confirmed
+// SCID of an alias-only channel. If pendingOnly is set, only invoices that can
+// still be paid are scanned.
+func (r *rpcServer) scidLeaks(ctx context.Context,
+	pendingOnly bool) ([]invoicesrpc.ScidLeak, error) {
+
+	const pageSize = 1000
+
+	channels, err := r.server.chanStateDB.FetchAllChannels()
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch channels: %w", err)
+	}
+
+	var (
+		leaks []invoicesrpc.ScidLeak
+		q     = invoices.InvoiceQuery{
+			NumMaxInvoices: pageSize,
+			PendingOnly:    pendingOnly,
+		}
+	)
+	for {
+		invoiceSlice, err := r.server.invoicesDB.QueryInvoices(ctx, q)
+		if err != nil {
+			return nil, fmt.Errorf("unable to query invoices: %w",
+				err)
+		}
+
+		leaks = append(leaks, invoicesrpc.FindScidLeaks(
+			channels, invoiceSlice.Invoices,
+			r.cfg.ActiveNetParams.Params,
+		)...)
+
+		if len(invoiceSlice.Invoices) < pageSize {
+			return leaks, nil
+		}
+
+		q.IndexOffset = invoiceSlice.LastIndexOffset
+	}
+}
+
 // SubscribeInvoices returns a uni-directional stream (server -> client) for
 // notifying the client of newly added/settled invoices.
 func (r *rpcServer) SubscribeInvoices(req *lnrpc.InvoiceSubscription,
@@ -6682,26 +7544,17 @@ func (r *rpcServer) GetNodeMetrics(ctx context.Context,
 		BetweennessCentrality: make(map[string]*lnrpc.FloatMetric),
 	}
 
-	// Obtain the pointer to the global singleton channel graph, this will
-	// provide a consistent view of the graph due to bolt db's
-	// transactional model.
-	graph := r.server.graphDB
+	// The centrality is maintained by the centrality service, which only
+	// recomputes it if the structure of the graph changed. Note that
+	// depending on the graph size, the first computation may take up to a
+	// few minutes.
+	centralityService := r.server.centralityService
 
-	// Calculate betweenness centrality if requested. Note that depending on the
-	// graph size, this may take up to a few minutes.
-	channelGraph := autopilot.ChannelGraphFromDatabase(graph)
-	centralityMetric, err := autopilot.NewBetweennessCentralityMetric(
-		runtime.NumCPU(),
-	)
+	// Fill normalized and non normalized centrality.
+	centrality, err := centralityService.BetweennessCentrality(ctx, true)
 	if err != nil {
 		return nil, err
 	}
-	if err := centralityMetric.Refresh(channelGraph); err != nil {
-		return nil, err
-	}
-
-	// Fill normalized and non normalized centrality.
-	centrality := centralityMetric.GetMetric(true)
 	for nodeID, val := range centrality {
 		resp.BetweennessCentrality[hex.EncodeToString(nodeID[:])] =
 			&lnrpc.FloatMetric{
@@ -6709,9 +7562,17 @@ func (r *rpcServer) GetNodeMetrics(ctx context.Context,
 			}
 	}
 
-	centrality = centralityMetric.GetMetric(false)
+	centrality, err = centralityService.BetweennessCentrality(ctx, false)
+	if err != nil {
+		return nil, err
+	}
 	for nodeID, val := range centrality {
-		resp.BetweennessCentrality[hex.EncodeToString(nodeID[:])].Value = val
+		// The centrality may have been refreshed in between, so we
+		// skip nodes that weren't part of the normalized values.
+		key := hex.EncodeToString(nodeID[:])
+		if metric, ok := resp.BetweennessCentrality[key]; ok {
+			metric.Value = val
+		}
 	}
 
 	return resp, nil
@@ -7208,6 +8069,112 @@ func marshallTopologyChange(
 	}
 }
 
+// SubscribeGossipBridge exports the announcements accepted by the gossiper as
+// raw wire messages. It fails if the gossip bridge isn't enabled.
+func (r *rpcServer) SubscribeGossipBridge(
+	_ *lnrpc.GossipBridgeSubscription,
+	updateStream lnrpc.Lightning_SubscribeGossipBridgeServer) error {
+
+	bridge, err := r.server.authGossiper.Bridge()
+	if err != nil {
+		return err
+	}
+
+	client, err := bridge.SubscribeAnnouncements()
+	if err != nil {
+		return err
+	}
+	defer client.Cancel()
+
+	for {
+		select {
+		case update := <-client.Updates():
+			msg, ok := update.(lnwire.Message)
+			if !ok {
+				return fmt.Errorf("unexpected gossip bridge "+
+					"update %T", update)
+			}
+
+			rpcMsg, err := marshalGossipMessage(msg)
+			if err != nil {
+				return err
+			}
+
+			if err := updateStream.Send(rpcMsg); err != nil {
+				return err
+			}
+
+		case <-client.Quit():
+			return errors.New("gossip bridge shutting down")
+
+		case <-updateStream.Context().Done():
+			err := updateStream.Context().Err()
+			if errors.Is(err, context.Canceled) {
+				return nil
+			}
+			return err
+
+		case <-r.quit:
+			return nil
+		}
+	}
+}
+
+// InjectGossip hands an externally obtained announcement to the gossiper
+// through the gossip bridge. It fails if the gossip bridge isn't enabled.
+func (r *rpcServer) InjectGossip(_ context.Context,
+	req *lnrpc.GossipMessage) (*lnrpc.InjectGossipResponse, error) {
+
+	bridge, err := r.server.authGossiper.Bridge()
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := unmarshalGossipMessage(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := bridge.InjectAnnouncement(msg); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.InjectGossipResponse{}, nil
+}
+
+// marshalGossipMessage serializes a gossip message as it is sent on the wire.
+func marshalGossipMessage(msg lnwire.Message) (*lnrpc.GossipMessage, error) {
+	var b bytes.Buffer
+	if _, err := lnwire.WriteMessage(&b, msg, 0); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.GossipMessage{
+		MsgType: uint32(msg.MsgType()),
+		RawMsg:  b.Bytes(),
+	}, nil
+}
+
+// unmarshalGossipMessage parses a raw gossip message. If the message type is
+// set, it must match the type prefix of the raw message.
+func unmarshalGossipMessage(req *lnrpc.GossipMessage) (lnwire.Message,
+	error) {
+
+	msg, err := lnwire.ReadMessage(bytes.NewReader(req.RawMsg), 0)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse gossip message: %w",
+			err)
+	}
+
+	msgType := lnwire.MessageType(req.MsgType)
+	if req.MsgType != 0 && msgType != msg.MsgType() {
+		return nil, fmt.Errorf("message type %v doesn't match raw "+
+			"message of type %v", req.MsgType, msg.MsgType())
+	}
+
+	return msg, nil
+}
+
 // ListPayments returns a list of outgoing payments determined by a paginated
 // database query.
 func (r *rpcServer) ListPayments(ctx context.Context,
@@ -7361,6 +8328,76 @@ func (r *rpcServer) DebugLevel(ctx context.Context,
 	return &lnrpc.DebugLevelResponse{}, nil
 }
 
+// GetWorkerPoolStats returns the utilization of the read and write worker
+// pools.
+func (r *rpcServer) GetWorkerPoolStats(_ context.Context,
+	_ *lnrpc.GetWorkerPoolStatsRequest) (*lnrpc.GetWorkerPoolStatsResponse,
+	error) {
+
+	return &lnrpc.GetWorkerPoolStatsResponse{
+		Pools: []*lnrpc.WorkerPoolStats{
+			marshalWorkerPoolStats(
+				lnrpc.WorkerPool_WORKER_POOL_READ,
+				r.server.readPool.Stats(),
+			),
+			marshalWorkerPoolStats(
+				lnrpc.WorkerPool_WORKER_POOL_WRITE,
+				r.server.writePool.Stats(),
+			),
+		},
+	}, nil
+}
+
+// ResizeWorkerPool sets the maximum number of workers of the read or write
+// worker pool.
+func (r *rpcServer) ResizeWorkerPool(_ context.Context,
+	req *lnrpc.ResizeWorkerPoolRequest) (*lnrpc.ResizeWorkerPoolResponse,
+	error) {
+
+	// resizablePool is the common interface of the read and write pools.
+	type resizablePool interface {
+		Resize(numWorkers int) error
+		Stats() pool.WorkerStats
+	}
+
+	var workerPool resizablePool
+	switch req.Pool {
+	case lnrpc.WorkerPool_WORKER_POOL_READ:
+		workerPool = r.server.readPool
+
+	case lnrpc.WorkerPool_WORKER_POOL_WRITE:
+		workerPool = r.server.writePool
+
+	default:
+		return nil, fmt.Errorf("unknown worker pool %v", req.Pool)
+	}
+
+	if err := workerPool.Resize(int(req.NumWorkers)); err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Infof("[resizeworkerpool] resized %v to %d workers",
+		req.Pool, req.NumWorkers)
+
+	return &lnrpc.ResizeWorkerPoolResponse{
+		Stats: marshalWorkerPoolStats(req.Pool, workerPool.Stats()),
+	}, nil
+}
+
+// marshalWorkerPoolStats converts the stats of a worker pool into their RPC
+// representation.
+func marshalWorkerPoolStats(workerPool lnrpc.WorkerPool,
+	stats pool.WorkerStats) *lnrpc.WorkerPoolStats {
+
+	return &lnrpc.WorkerPoolStats{
+		Pool:              workerPool,
+		NumWorkers:        uint32(stats.NumWorkers),
+		MaxWorkers:        uint32(stats.MaxWorkers),
+		TasksProcessed:    stats.TasksProcessed,
+		AvgQueueLatencyUs: uint64(stats.AvgQueueLatency.Microseconds()),
+	}
+}
+
 // DecodePayReq takes an encoded payment request string and attempts to decode
 // it, returning a full description of the conditions encoded within the
 // payment request.
@@ -7741,6 +8778,80 @@ func (r *rpcServer) UpdateChannelPolicy(ctx context.Context,
 	}, nil
 }
 
+// SuggestFees returns fee rate suggestions for all of our channels without
+// changing any channel policies.
+func (r *rpcServer) SuggestFees(_ context.Context,
+	_ *lnrpc.SuggestFeesRequest) (*lnrpc.SuggestFeesResponse, error) {
+
+	suggestions, err := r.server.feeSuggester.Suggest()
+	if err != nil {
+		return nil, fmt.Errorf("unable to suggest fees: %w", err)
+	}
+
+	return &lnrpc.SuggestFeesResponse{
+		Suggestions: marshalFeeSuggestions(suggestions),
+	}, nil
+}
+
+// ApplyFeeSuggestions computes fee rate suggestions for all of our channels
+// and applies the ones that differ from the current fee rate.
+func (r *rpcServer) ApplyFeeSuggestions(_ context.Context,
+	_ *lnrpc.ApplyFeeSuggestionsRequest) (
+	*lnrpc.ApplyFeeSuggestionsResponse, error) {
+
+	suggestions, err := r.server.feeSuggester.Suggest()
+	if err != nil {
+		return nil, fmt.Errorf("unable to suggest fees: %w", err)
+	}
+
+	if err := r.server.feeSuggester.Apply(suggestions); err != nil {
+		return nil, fmt.Errorf("unable to apply fee suggestions: %w",
+			err)
+	}
+
+	var numChanged int
+	for _, s := range suggestions {
+		if s.Changed() {
+			numChanged++
+		}
+	}
+	rpcsLog.Infof("[applyfeesuggestions] updated the fee rate of %d "+
+		"channels", numChanged)
+
+	return &lnrpc.ApplyFeeSuggestionsResponse{
+		Suggestions: marshalFeeSuggestions(suggestions),
+	}, nil
+}
+
+// marshalFeeSuggestions converts fee rate suggestions into their RPC
+// representation.
+func marshalFeeSuggestions(
+	suggestions []*localchans.FeeSuggestion) []*lnrpc.ChannelFeeSuggestion {
+
+	rpcSuggestions := make(
+		[]*lnrpc.ChannelFeeSuggestion, 0, len(suggestions),
+	)
+	for _, s := range suggestions {
+		rpcSuggestions = append(rpcSuggestions,
+			&lnrpc.ChannelFeeSuggestion{
+				ChannelPoint:         s.ChanPoint.String(),
+				ChanId:               s.ChannelID,
+				CurrentFeeRatePpm:    s.CurrentFeeRate,
+				SuggestedFeeRatePpm:  s.SuggestedFeeRate,
+				PeerMedianFeeRatePpm: s.PeerMedianFeeRate,
+				LocalBalanceRatio:    s.LocalBalanceRatio,
+				NumForwards:          uint32(s.NumForwards),
+				ForwardedAmtMsat:     uint64(s.ForwardedAmt),
+				FeesEarnedMsat:       uint64(s.FeesEarned),
+				Reason:               s.Reason,
+				Changed:              s.Changed(),
+			},
+		)
+	}
+
+	return rpcSuggestions
+}
+
 // ForwardingHistory allows the caller to query the htlcswitch for a record of
 // all HTLC's forwarded within the target time range, and integer offset within
 // that time range. If no time-range is specified, then the first chunk of the
@@ -7929,7 +9040,7 @@ func (r *rpcServer) ExportChannelBackup(ctx context.Context,
 	// backup.
 	packedBackups, err := chanbackup.PackStaticChanBackups(
 		[]chanbackup.Single{*unpackedBackup},
-		r.server.cc.KeyRing,
+		r.server.backupKeyRing,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("packing of back ups failed: %w", err)
@@ -7986,7 +9097,11 @@ func (r *rpcServer) VerifyChanBackup(ctx context.Context,
 		// With our PackedSingles created, we'll attempt to unpack the
 		// backup. If this fails, then we know the backup is invalid for
 		// some reason.
-		_, err := chanBackup.Unpack(r.server.cc.KeyRing)
+		unpackSingle := func(k keychain.KeyRing) error {
+			_, err := chanBackup.Unpack(k)
+			return err
+		}
+		_, err := r.findBackupKeyRing(unpackSingle)
 		if err != nil {
 			return nil, fmt.Errorf("invalid single channel "+
 				"backup: %v", err)
@@ -8000,7 +9115,11 @@ func (r *rpcServer) VerifyChanBackup(ctx context.Context,
 
 		// We'll now attempt to unpack the Multi. If this fails, then we
 		// know it's invalid.
-		_, err := packedMulti.Unpack(r.server.cc.KeyRing)
+		unpackMulti := func(k keychain.KeyRing) error {
+			_, err := packedMulti.Unpack(k)
+			return err
+		}
+		_, err := r.findBackupKeyRing(unpackMulti)
 		if err != nil {
 			return nil, fmt.Errorf("invalid multi channel backup: "+
 				"%v", err)
@@ -8010,6 +9129,16 @@ func (r *rpcServer) VerifyChanBackup(ctx context.Context,
 	return &lnrpc.VerifyChanBackupResponse{}, nil
 }
 
+// findBackupKeyRing returns the first of the server's key rings that is able
+// to decrypt a static channel backup with the passed unpack closure.
+func (r *rpcServer) findBackupKeyRing(
+	unpack func(keychain.KeyRing) error) (keychain.KeyRing, error) {
+
+	return findBackupKeyRing(
+		r.server.backupKeyRing, r.server.cc.KeyRing, unpack,
+	)
+}
+
 // createBackupSnapshot converts the passed Single backup into a snapshot which
 // contains individual packed single backups, as well as a single packed multi
 // backup.
@@ -8019,7 +9148,7 @@ func (r *rpcServer) createBackupSnapshot(backups []chanbackup.Single) (
 	// Once we have the set of back ups, we'll attempt to pack them all
 	// into a series of single channel backups.
 	singleChanPackedBackups, err := chanbackup.PackStaticChanBackups(
-		backups, r.server.cc.KeyRing,
+		backups, r.server.backupKeyRing,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("unable to pack set of chan "+
@@ -8057,7 +9186,7 @@ func (r *rpcServer) createBackupSnapshot(backups []chanbackup.Single) (
 	unpackedMultiBackup := chanbackup.Multi{
 		StaticBackups: backups,
 	}
-	err = unpackedMultiBackup.PackToWriter(&b, r.server.cc.KeyRing)
+	err = unpackedMultiBackup.PackToWriter(&b, r.server.backupKeyRing)
 	if err != nil {
 		return nil, fmt.Errorf("unable to multi-pack backups: %w", err)
 	}
@@ -8137,13 +9266,26 @@ func (r *rpcServer) RestoreChannelBackups(ctx context.Context,
 			)
 		}
 
+		// Find the key ring that is able to decrypt the backups, as
+		// they may have been encrypted with either the wallet key ring
+		// or the backup passphrase.
+		packedSingles := chanbackup.PackedSingles(packedBackups)
+		unpackSingles := func(k keychain.KeyRing) error {
+			_, err := packedSingles.Unpack(k)
+			return err
+		}
+		keyRing, err := r.findBackupKeyRing(unpackSingles)
+		if err != nil {
+			return nil, fmt.Errorf("unable to unpack single "+
+				"backups: %v", err)
+		}
+
 		// With our backups obtained, we'll now restore them which will
 		// write the new backups to disk, and then attempt to connect
 		// out to any peers that we know of which were our prior
 		// channel peers.
-		err := chanbackup.UnpackAndRecoverSingles(
-			chanbackup.PackedSingles(packedBackups),
-			r.server.cc.KeyRing, chanRestorer, r.server,
+		err = chanbackup.UnpackAndRecoverSingles(
+			packedSingles, keyRing, chanRestorer, r.server,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("unable to unpack single "+
@@ -8158,9 +9300,18 @@ func (r *rpcServer) RestoreChannelBackups(ctx context.Context,
 		// out to any peers that we know of which were our prior
 		// channel peers.
 		packedMulti := chanbackup.PackedMulti(packedMultiBackup)
-		err := chanbackup.UnpackAndRecoverMulti(
-			packedMulti, r.server.cc.KeyRing, chanRestorer,
-			r.server,
+		unpackMulti := func(k keychain.KeyRing) error {
+			_, err := packedMulti.Unpack(k)
+			return err
+		}
+		keyRing, err := r.findBackupKeyRing(unpackMulti)
+		if err != nil {
+			return nil, fmt.Errorf("unable to unpack chan "+
+				"backup: %v", err)
+		}
+
+		err = chanbackup.UnpackAndRecoverMulti(
+			packedMulti, keyRing, chanRestorer, r.server,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("unable to unpack chan "+
@@ -8211,6 +9362,8 @@ func (r *rpcServer) SubscribeChannelBackups(req *lnrpc.ChannelBackupSubscription
 				continue
 			case channelnotifier.InactiveLinkEvent:
 				continue
+			case channelnotifier.StaleRemoteCloseEvent:
+				continue
 			}
 
 			// Now that we know the channel state has changed,
@@ -8252,6 +9405,62 @@ func (r *rpcServer) SubscribeChannelBackups(req *lnrpc.ChannelBackupSubscription
 	}
 }
 
+// BackupReplicationStatus returns the state of the replication of the channel
+// backup file to each configured remote target.
+func (r *rpcServer) BackupReplicationStatus(_ context.Context,
+	_ *lnrpc.BackupReplicationStatusRequest) (
+	*lnrpc.BackupReplicationStatusResponse, error) {
+
+	resp := &lnrpc.BackupReplicationStatusResponse{}
+	if r.server.backupReplicator == nil {
+		return resp, nil
+	}
+
+	resp.Targets = marshalReplicationStatus(
+		r.server.backupReplicator.Status(),
+	)
+
+	return resp, nil
+}
+
+// marshalReplicationStatus converts the replication state of the backup
+// targets, keyed by their name, into their RPC representation ordered by name.
+func marshalReplicationStatus(
+	status map[string]chanbackup.ReplicationStatus) (
+	targets []*lnrpc.BackupTargetStatus) {
+
+	// unixOrZero returns the unix timestamp of the given time, or zero if
+	// the time isn't set.
+	unixOrZero := func(t time.Time) int64 {
+		if t.IsZero() {
+			return 0
+		}
+
+		return t.Unix()
+	}
+
+	targets = make([]*lnrpc.BackupTargetStatus, 0, len(status))
+	for name, s := range status {
+		target := &lnrpc.BackupTargetStatus{
+			Name:        name,
+			LastSuccess: unixOrZero(s.LastSuccess),
+			LastVersion: s.LastVersion,
+			LastAttempt: unixOrZero(s.LastAttempt),
+			Pending:     s.Pending,
+		}
+		if s.LastErr != nil {
+			target.LastError = s.LastErr.Error()
+		}
+
+		targets = append(targets, target)
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].Name < targets[j].Name
+	})
+
+	return targets
+}
+
 // ChannelAcceptor dispatches a bi-directional streaming RPC in which
 // OpenChannel requests are sent to the client and the client responds with
 // a boolean that tells LND whether or not to accept the channel. This allows
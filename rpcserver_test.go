@@ -1,12 +1,28 @@
 package lnd
 
 import (
+	"errors"
 	"fmt"
+	"net"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/chanbackup"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/discovery"
 	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/funding"
+	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/peernotifier"
+	"github.com/lightningnetwork/lnd/pool"
+	"github.com/lightningnetwork/lnd/routing/localchans"
+	"github.com/lightningnetwork/lnd/routing/route"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/proto"
@@ -127,3 +143,328 @@ func TestRpcCommitmentType(t *testing.T) {
 		})
 	}
 }
+
+// TestMarshalPeerEvent tests that the connection metadata, features and
+// disconnect reasons of peer events are included in their RPC counterparts.
+func TestMarshalPeerEvent(t *testing.T) {
+	t.Parallel()
+
+	pubKey := [33]byte{2, 1}
+	addr := &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 9735}
+	conn := peernotifier.ConnectionInfo{
+		Address: addr,
+		Inbound: true,
+	}
+
+	features := lnwire.NewFeatureVector(
+		lnwire.NewRawFeatureVector(lnwire.StaticRemoteKeyRequired),
+		lnwire.Features,
+	)
+	event, err := marshalPeerEvent(peernotifier.PeerOnlineEvent{
+		PubKey:   pubKey,
+		Conn:     conn,
+		Features: features,
+	})
+	require.NoError(t, err)
+	require.Equal(t, lnrpc.PeerEvent_PEER_ONLINE, event.Type)
+	require.Equal(t, addr.String(), event.Address)
+	require.True(t, event.Inbound)
+	require.Contains(
+		t, event.Features, uint32(lnwire.StaticRemoteKeyRequired),
+	)
+
+	event, err = marshalPeerEvent(peernotifier.PeerOfflineEvent{
+		PubKey: pubKey,
+		Conn:   conn,
+		Reason: peernotifier.DisconnectReasonPingTimeout,
+		Err:    errors.New("ping timeout"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, lnrpc.PeerEvent_PEER_OFFLINE, event.Type)
+	require.Equal(
+		t, lnrpc.PeerEvent_DISCONNECT_PING_TIMEOUT,
+		event.DisconnectReason,
+	)
+	require.Equal(t, "ping timeout", event.DisconnectError)
+	require.Empty(t, event.Features)
+
+	_, err = marshalPeerEvent(struct{}{})
+	require.Error(t, err)
+}
+
+// TestMarshalNegotiatingChannel tests that channels that are still being
+// negotiated are reported with their funding outpoint only once it is known.
+func TestMarshalNegotiatingChannel(t *testing.T) {
+	t.Parallel()
+
+	priv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	status := &funding.PendingChannelStatus{
+		Stage:      funding.StageNegotiating,
+		PeerKey:    priv.PubKey(),
+		Initiator:  true,
+		IdleTime:   90 * time.Second,
+		NextAction: "waiting for peer",
+	}
+
+	rpcChan := marshalNegotiatingChannel(status)
+	require.Equal(t, &lnrpc.PendingChannelsResponse_NegotiatingChannel{
+		RemoteNodePub: fmt.Sprintf(
+			"%x", priv.PubKey().SerializeCompressed(),
+		),
+		Initiator:   lnrpc.Initiator_INITIATOR_LOCAL,
+		IdleSeconds: 90,
+		NextAction:  "waiting for peer",
+	}, rpcChan)
+
+	status.ChanPoint = wire.OutPoint{Index: 1}
+	rpcChan = marshalNegotiatingChannel(status)
+	require.Equal(t, status.ChanPoint.String(), rpcChan.ChannelPoint)
+}
+
+// TestGossipEgressLimits tests that gossip egress limits survive a conversion
+// to their RPC representation and back, and that invalid limits are rejected.
+func TestGossipEgressLimits(t *testing.T) {
+	t.Parallel()
+
+	priv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	cfg := discovery.EgressConfig{
+		MaxBytesPerSec:  100_000,
+		MaxBurstBytes:   lnwire.MaxMsgBody,
+		PeerBytesPerSec: 10_000,
+		PeerBurstBytes:  lnwire.MaxMsgBody,
+		PassivePeers:    []route.Vertex{route.NewVertex(priv.PubKey())},
+	}
+
+	limits := marshalGossipEgressLimits(cfg)
+	unmarshaled, err := unmarshalGossipEgressLimits(limits)
+	require.NoError(t, err)
+	require.Equal(t, cfg, unmarshaled)
+
+	limits.PeerBurstBytes = 1000
+	_, err = unmarshalGossipEgressLimits(limits)
+	require.ErrorContains(t, err, "burst")
+
+	limits.PeerBurstBytes = lnwire.MaxMsgBody
+	limits.PassivePeers = []string{"not a pubkey"}
+	_, err = unmarshalGossipEgressLimits(limits)
+	require.ErrorContains(t, err, "invalid passive peer")
+}
+
+// TestMarshalIntegrityReport tests that the orphaned references of an
+// integrity report are converted into their RPC representation.
+func TestMarshalIntegrityReport(t *testing.T) {
+	t.Parallel()
+
+	chanPoint := wire.OutPoint{Index: 3}
+	report := &channeldb.IntegrityReport{
+		OrphanedEdges: []channeldb.OrphanedEdge{{
+			ChannelID:    1234,
+			ChannelPoint: chanPoint,
+		}},
+		OrphanedFwdPkgs: []lnwire.ShortChannelID{
+			lnwire.NewShortChanIDFromInt(5678),
+		},
+	}
+
+	edges, fwdPkgs := marshalIntegrityReport(report)
+	require.Len(t, edges, 1)
+	require.EqualValues(t, 1234, edges[0].ChanId)
+	require.Equal(t, chanPoint.String(), edges[0].ChannelPoint)
+	require.Equal(t, []uint64{5678}, fwdPkgs)
+
+	edges, fwdPkgs = marshalIntegrityReport(&channeldb.IntegrityReport{})
+	require.Empty(t, edges)
+	require.Empty(t, fwdPkgs)
+}
+
+// TestMarshalFeeSuggestions tests that fee rate suggestions are converted into
+// their RPC representation.
+func TestMarshalFeeSuggestions(t *testing.T) {
+	t.Parallel()
+
+	chanPoint := wire.OutPoint{Index: 2}
+	suggestions := []*localchans.FeeSuggestion{{
+		ChanPoint:         chanPoint,
+		ChannelID:         1234,
+		CurrentFeeRate:    100,
+		SuggestedFeeRate:  150,
+		PeerMedianFeeRate: 200,
+		LocalBalanceRatio: 0.25,
+		NumForwards:       3,
+		ForwardedAmt:      300_000,
+		FeesEarned:        30,
+		Reason:            "low local balance",
+	}, {
+		CurrentFeeRate:   100,
+		SuggestedFeeRate: 100,
+	}}
+
+	rpcSuggestions := marshalFeeSuggestions(suggestions)
+	require.Len(t, rpcSuggestions, 2)
+	require.Equal(t, &lnrpc.ChannelFeeSuggestion{
+		ChannelPoint:         chanPoint.String(),
+		ChanId:               1234,
+		CurrentFeeRatePpm:    100,
+		SuggestedFeeRatePpm:  150,
+		PeerMedianFeeRatePpm: 200,
+		LocalBalanceRatio:    0.25,
+		NumForwards:          3,
+		ForwardedAmtMsat:     300_000,
+		FeesEarnedMsat:       30,
+		Reason:               "low local balance",
+		Changed:              true,
+	}, rpcSuggestions[0])
+	require.False(t, rpcSuggestions[1].Changed)
+}
+
+// TestCreateDBSnapshot tests that database snapshots can be taken over RPC and
+// that they're rejected if no snapshotter is available.
+func TestCreateDBSnapshot(t *testing.T) {
+	t.Parallel()
+
+	r := &rpcServer{server: &server{}}
+	_, err := r.CreateDBSnapshot(nil, &lnrpc.CreateDBSnapshotRequest{})
+	require.ErrorContains(t, err, "only supported with the bolt")
+
+	cdb, err := channeldb.Open(t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, cdb.Close())
+	})
+
+	r.server.dbSnapshotter = kvdb.NewSnapshotter(&kvdb.SnapshotConfig{
+		DBs: map[string]kvdb.Backend{
+			"channel.db": cdb.Backend,
+		},
+		Dir: t.TempDir(),
+	})
+
+	resp, err := r.CreateDBSnapshot(nil, &lnrpc.CreateDBSnapshotRequest{})
+	require.NoError(t, err)
+	require.FileExists(t, filepath.Join(resp.SnapshotDir, "channel.db"))
+}
+
+// TestMarshalBalanceObligations tests that balance obligations are converted
+// into their RPC representation.
+func TestMarshalBalanceObligations(t *testing.T) {
+	t.Parallel()
+
+	obligations := &lnwallet.BalanceObligations{
+		AnchorReserve:   10_000,
+		SweepsInFlight:  2_000,
+		CPFPObligations: 3_000,
+	}
+
+	require.Equal(t, &lnrpc.BalanceObligations{
+		AnchorReserve:   10_000,
+		SweepsInFlight:  2_000,
+		CpfpObligations: 3_000,
+		Total:           15_000,
+	}, marshalBalanceObligations(obligations))
+}
+
+// TestMarshalReplicationStatus tests that the replication state of the backup
+// targets is converted into its RPC representation.
+func TestMarshalReplicationStatus(t *testing.T) {
+	t.Parallel()
+
+	targets := marshalReplicationStatus(
+		map[string]chanbackup.ReplicationStatus{
+			"sftp": {
+				LastAttempt: time.Unix(2000, 0),
+				LastErr:     errors.New("connection refused"),
+				Pending:     true,
+			},
+			"s3": {
+				LastSuccess: time.Unix(1000, 0),
+				LastVersion: "v1",
+				LastAttempt: time.Unix(1000, 0),
+			},
+		},
+	)
+
+	require.Equal(t, []*lnrpc.BackupTargetStatus{{
+		Name:        "s3",
+		LastSuccess: 1000,
+		LastVersion: "v1",
+		LastAttempt: 1000,
+	}, {
+		Name:        "sftp",
+		LastAttempt: 2000,
+		LastError:   "connection refused",
+		Pending:     true,
+	}}, targets)
+}
+
+// TestResizeWorkerPool tests that the worker pools can be resized over RPC.
+func TestResizeWorkerPool(t *testing.T) {
+	t.Parallel()
+
+	readPool := pool.NewRead(
+		pool.NewReadBuffer(time.Minute, time.Minute), 2, time.Minute,
+	)
+	writePool := pool.NewWrite(
+		pool.NewWriteBuffer(time.Minute, time.Minute), 2, time.Minute,
+	)
+	r := &rpcServer{server: &server{
+		readPool:  readPool,
+		writePool: writePool,
+	}}
+
+	resp, err := r.ResizeWorkerPool(nil, &lnrpc.ResizeWorkerPoolRequest{
+		Pool:       lnrpc.WorkerPool_WORKER_POOL_WRITE,
+		NumWorkers: 5,
+	})
+	require.NoError(t, err)
+	require.Equal(t, lnrpc.WorkerPool_WORKER_POOL_WRITE, resp.Stats.Pool)
+	require.EqualValues(t, 5, resp.Stats.MaxWorkers)
+
+	_, err = r.ResizeWorkerPool(nil, &lnrpc.ResizeWorkerPoolRequest{
+		Pool: lnrpc.WorkerPool_WORKER_POOL_READ,
+	})
+	require.ErrorContains(t, err, "must be positive")
+
+	stats, err := r.GetWorkerPoolStats(
+		nil, &lnrpc.GetWorkerPoolStatsRequest{},
+	)
+	require.NoError(t, err)
+	require.Len(t, stats.Pools, 2)
+	require.EqualValues(t, 2, stats.Pools[0].MaxWorkers)
+	require.EqualValues(t, 5, stats.Pools[1].MaxWorkers)
+}
+
+// TestGossipMessageRoundTrip tests that gossip messages are exported and
+// parsed in their wire format.
+func TestGossipMessageRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	update := &lnwire.ChannelUpdate1{
+		ShortChannelID: lnwire.NewShortChanIDFromInt(123),
+		Timestamp:      1000,
+		TimeLockDelta:  80,
+		BaseFee:        1000,
+		FeeRate:        100,
+	}
+
+	rpcMsg, err := marshalGossipMessage(update)
+	require.NoError(t, err)
+	require.EqualValues(t, lnwire.MsgChannelUpdate, rpcMsg.MsgType)
+
+	msg, err := unmarshalGossipMessage(rpcMsg)
+	require.NoError(t, err)
+	require.IsType(t, &lnwire.ChannelUpdate1{}, msg)
+	require.Equal(t, update.ShortChannelID, msg.(*lnwire.ChannelUpdate1).
+		ShortChannelID)
+
+	// A message type that doesn't match the raw message is rejected.
+	rpcMsg.MsgType = uint32(lnwire.MsgNodeAnnouncement)
+	_, err = unmarshalGossipMessage(rpcMsg)
+	require.ErrorContains(t, err, "doesn't match")
+
+	_, err = unmarshalGossipMessage(&lnrpc.GossipMessage{})
+	require.ErrorContains(t, err, "unable to parse")
+}
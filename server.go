@@ -9,6 +9,8 @@ import (
 	"math/big"
 	prand "math/rand"
 	"net"
+	"os"
+	"runtime"
 	"strconv"
 	"strings"
 	"sync"
@@ -17,6 +19,7 @@ import (
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
@@ -58,7 +61,6 @@ import (
 	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
-	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
 	"github.com/lightningnetwork/lnd/lnwallet/rpcwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/nat"
@@ -174,6 +176,15 @@ type server struct {
 	// that's backed by the identity private key of the running lnd node.
 	nodeSigner *netann.NodeSigner
 
+	// preimageDeriver derives invoice preimages from the wallet seed. It
+	// is nil if deterministic preimages aren't enabled.
+	preimageDeriver *invoices.PreimageDeriver
+
+	// annSigner is the MessageSigner that is used to sign our gossip
+	// messages. When using a remote signer, it wraps the nodeSigner to
+	// batch and cache the signing requests.
+	annSigner lnwallet.MessageSigner
+
 	chanStatusMgr *netann.ChanStatusManager
 
 	// listenAddrs is the list of addresses the server is currently
@@ -202,6 +213,10 @@ type server struct {
 	inboundPeers  map[string]*peer.Brontide
 	outboundPeers map[string]*peer.Brontide
 
+	// onlinePeers is the set of peers that the peer notifier was informed
+	// of coming online, and which are still connected.
+	onlinePeers map[*peer.Brontide]struct{}
+
 	peerConnectedListeners    map[string][]chan<- lnpeer.Peer
 	peerDisconnectedListeners map[string][]chan<- struct{}
 
@@ -215,6 +230,11 @@ type server struct {
 	persistentConnReqs     map[string][]*connmgr.ConnReq
 	persistentRetryCancels map[string]chan struct{}
 
+	// peerAllowList is the set of peers that may connect to us or be
+	// connected to. It is nil if the allow list isn't enabled, in which
+	// case all peers are allowed.
+	peerAllowList *peer.AllowList
+
 	// peerErrors keeps a set of peer error buffers for peers that have
 	// disconnected from us. This allows us to track historic peer errors
 	// over connections. The string of the peer's compressed pubkey is used
@@ -244,7 +264,7 @@ type server struct {
 
 	fundingMgr *funding.Manager
 
-	graphDB *channeldb.ChannelGraph
+	graphDB channeldb.GraphStore
 
 	chanStateDB *channeldb.ChannelStateDB
 
@@ -254,6 +274,10 @@ type server struct {
 	// channel DB that haven't been separated out yet.
 	miscDB *channeldb.DB
 
+	// dbSnapshotter takes online snapshots of the local bolt databases. It
+	// is nil if a remote database backend is used.
+	dbSnapshotter *kvdb.Snapshotter
+
 	invoicesDB invoices.InvoiceDB
 
 	aliasMgr *aliasmgr.Manager
@@ -264,6 +288,10 @@ type server struct {
 
 	invoices *invoices.InvoiceRegistry
 
+	// offersMgr manages the BOLT 12 offers that we issue. It is nil if the
+	// invoice database doesn't support offers.
+	offersMgr *invoices.OffersManager
+
 	invoiceHtlcModifier *invoices.HtlcModificationInterceptor
 
 	channelNotifier *channelnotifier.ChannelNotifier
@@ -279,8 +307,14 @@ type server struct {
 	missionController *routing.MissionController
 	defaultMC         *routing.MissionControl
 
+	// forwardLearner feeds the htlcs that we forward into the default
+	// mission control. It is nil if learning from forwards is disabled.
+	forwardLearner *routing.ForwardLearner
+
 	graphBuilder *graph.Builder
 
+	centralityService *autopilot.CentralityService
+
 	chanRouter *routing.ChannelRouter
 
 	controlTower routing.ControlTower
@@ -289,6 +323,12 @@ type server struct {
 
 	localChanMgr *localchans.Manager
 
+	feeSuggester *localchans.FeeSuggester
+
+	// chanOutgoingCltvRejectDelta holds the configured per-channel
+	// overrides of the outgoing cltv reject delta.
+	chanOutgoingCltvRejectDelta map[lnwire.ShortChannelID]uint32
+
 	utxoNursery *contractcourt.UtxoNursery
 
 	sweeper *sweep.UtxoSweeper
@@ -327,6 +367,15 @@ type server struct {
 	// channelNotifier to be notified of newly opened and closed channels.
 	chanSubSwapper *chanbackup.SubSwapper
 
+	// backupReplicator replicates the channel backup file to the
+	// configured remote targets. It's nil if no target is configured.
+	backupReplicator *chanbackup.Replicator
+
+	// backupKeyRing is the key ring used to encrypt our static channel
+	// backups. This is either the main wallet key ring, or a key ring that
+	// derives the backup encryption key from a user supplied passphrase.
+	backupKeyRing keychain.KeyRing
+
 	// chanEventStore tracks the behaviour of channels and their remote peers to
 	// provide insights into their health and performance.
 	chanEventStore *chanfitness.ChannelEventStore
@@ -338,9 +387,17 @@ type server struct {
 
 	customMessageServer *subscribe.Server
 
+	// onionMessageServer dispatches the onion messages received from our
+	// peers to its subscribers.
+	onionMessageServer *subscribe.Server
+
 	// txPublisher is a publisher with fee-bumping capability.
 	txPublisher *sweep.TxPublisher
 
+	// extPublisher submits sweep and force close transactions to the
+	// configured external broadcasters. It is nil if none are configured.
+	extPublisher *sweep.ExternalPublisher
+
 	quit chan struct{}
 
 	wg sync.WaitGroup
@@ -437,6 +494,19 @@ type CustomMessage struct {
 	Msg *lnwire.Custom
 }
 
+// OnionMessage is an onion message that is received from a peer.
+type OnionMessage struct {
+	// Peer is the pubkey of the peer that forwarded the message to us.
+	Peer [33]byte
+
+	// Msg is the onion message.
+	Msg *lnwire.OnionMessage
+
+	// Hop is our decrypted layer of the message, which includes the
+	// payload that the sender addressed to us.
+	Hop *hop.OnionMessageHop
+}
+
 // parseAddr parses an address from its string format to a net.Addr.
 func parseAddr(address string, netCfg tor.Net) (net.Addr, error) {
 	var (
@@ -535,8 +605,19 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		pool.DefaultWriteBufferExpiryInterval,
 	)
 
+	var writePoolOpts, readPoolOpts []pool.WorkerOption
+	if cfg.Workers.AutoTune {
+		writePoolOpts = append(writePoolOpts, pool.WithAutoTune(
+			pool.DefaultAutoTuneConfig(cfg.Workers.Write),
+		))
+		readPoolOpts = append(readPoolOpts, pool.WithAutoTune(
+			pool.DefaultAutoTuneConfig(cfg.Workers.Read),
+		))
+	}
+
 	writePool := pool.NewWrite(
 		writeBufferPool, cfg.Workers.Write, pool.DefaultWorkerTimeout,
+		writePoolOpts...,
 	)
 
 	readBufferPool := pool.NewReadBuffer(
@@ -546,6 +627,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 
 	readPool := pool.NewRead(
 		readBufferPool, cfg.Workers.Read, pool.DefaultWorkerTimeout,
+		readPoolOpts...,
 	)
 
 	// If the taproot overlay flag is set, but we don't have an aux funding
@@ -571,12 +653,21 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		CustomFeatures:           cfg.ProtocolOptions.CustomFeatures(),
 		NoTaprootChans:           !cfg.ProtocolOptions.TaprootChans,
 		NoTaprootOverlay:         !cfg.ProtocolOptions.TaprootOverlayChans,
+		NoDualFunding:            !cfg.ProtocolOptions.DualFunding,
+		NoOnionMessages:          !cfg.ProtocolOptions.OnionMessages,
 		NoRouteBlinding:          cfg.ProtocolOptions.NoRouteBlinding(),
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	overpaymentMode, err := invoices.ParseOverpaymentMode(
+		cfg.Invoices.OverpaymentPolicy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	invoiceHtlcModifier := invoices.NewHtlcModificationInterceptor()
 	registryConfig := invoices.RegistryConfig{
 		FinalCltvRejectDelta:        lncfg.DefaultFinalCltvRejectDelta,
@@ -588,6 +679,10 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		GcCanceledInvoicesOnTheFly:  cfg.GcCanceledInvoicesOnTheFly,
 		KeysendHoldTime:             cfg.KeysendHoldTime,
 		HtlcInterceptor:             invoiceHtlcModifier,
+		OverpaymentPolicy: invoices.OverpaymentPolicy{
+			Mode:       overpaymentMode,
+			MaxPercent: cfg.Invoices.MaxOverpaymentPercent,
+		},
 	}
 
 	s := &server{
@@ -598,6 +693,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		addrSource:     dbs.ChanStateDB,
 		miscDB:         dbs.ChanStateDB,
 		invoicesDB:     dbs.InvoiceDB,
+		dbSnapshotter:  dbs.Snapshotter,
 		cc:             cc,
 		sigPool:        lnwallet.NewSigPool(cfg.Workers.Sig, cc.Signer),
 		writePool:      writePool,
@@ -633,12 +729,14 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		peersByPub:                make(map[string]*peer.Brontide),
 		inboundPeers:              make(map[string]*peer.Brontide),
 		outboundPeers:             make(map[string]*peer.Brontide),
+		onlinePeers:               make(map[*peer.Brontide]struct{}),
 		peerConnectedListeners:    make(map[string][]chan<- lnpeer.Peer),
 		peerDisconnectedListeners: make(map[string][]chan<- struct{}),
 
 		invoiceHtlcModifier: invoiceHtlcModifier,
 
 		customMessageServer: subscribe.NewServer(),
+		onionMessageServer:  subscribe.NewServer(),
 
 		tlsManager: tlsManager,
 
@@ -651,6 +749,16 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		return nil, err
 	}
 
+	if cfg.AllowList.Enable {
+		s.peerAllowList, err = peer.NewAllowList(
+			dbs.ChanStateDB, cfg.AllowList.Peers,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load peer allow "+
+				"list: %w", err)
+		}
+	}
+
 	expiryWatcher := invoices.NewInvoiceExpiryWatcher(
 		clock.NewDefaultClock(), cfg.Invoices.HoldExpiryDelta,
 		uint32(currentHeight), currentHash, cc.ChainNotifier,
@@ -659,6 +767,47 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		dbs.InvoiceDB, expiryWatcher, &registryConfig,
 	)
 
+	// Offers are only stored by the kv invoice database for now. No
+	// invoices are created for invoice requests yet, as the onion messages
+	// that carry them can't be decrypted.
+	if offerDB, ok := dbs.InvoiceDB.(invoices.OfferDB); ok {
+		subscribeSettled := func(settleIndex uint64) (
+			*invoices.InvoiceSubscription, error) {
+
+			return s.invoices.SubscribeNotifications(
+				context.Background(), 0, settleIndex,
+			)
+		}
+		signInvoice := func(msg, tag []byte) (*schnorr.Signature,
+			error) {
+
+			return cc.KeyRing.SignMessageSchnorr(
+				nodeKeyDesc.KeyLocator, msg, false, nil, tag,
+			)
+		}
+
+		chainHash := *cfg.ActiveNetParams.GenesisHash
+		s.offersMgr = invoices.NewOffersManager(
+			&invoices.OffersManagerConfig{
+				NodeID:           nodeKeyDesc.PubKey,
+				ChainHash:        chainHash,
+				DB:               offerDB,
+				Clock:            clock.NewDefaultClock(),
+				SubscribeSettled: subscribeSettled,
+				SignInvoice:      signInvoice,
+			},
+		)
+	}
+
+	if cfg.Invoices.DeterministicPreimages {
+		s.preimageDeriver, err = invoices.NewPreimageDeriver(
+			cc.KeyRing,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	s.htlcNotifier = htlcswitch.NewHtlcNotifier(time.Now)
 
 	thresholdSats := btcutil.Amount(cfg.MaxFeeExposure)
@@ -712,6 +861,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		RejectHTLC:             cfg.RejectHTLC,
 		Clock:                  clock.NewDefaultClock(),
 		MailboxDeliveryTimeout: cfg.Htlcswitch.MailboxDeliveryTimeout,
+		MaxMailboxAdds:         htlcswitch.DefaultMaxMailboxAdds,
 		MaxFeeExposure:         thresholdMSats,
 		SignAliasUpdate:        s.signAliasUpdate,
 		IsAlias:                aliasmgr.IsAlias,
@@ -726,12 +876,27 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 			CltvInterceptDelta: lncfg.DefaultCltvInterceptDelta,
 			RequireInterceptor: s.cfg.RequireInterceptor,
 			Notifier:           s.cc.ChainNotifier,
+			MaxHeldHtlcMemory:  s.cfg.Htlcswitch.MaxHeldHtlcMemory,
 		},
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	// With a remote signer, every gossip signature requires a round trip
+	// to the signer. To avoid adding latency for every channel on policy
+	// changes, we batch and cache those requests.
+	s.annSigner = s.nodeSigner
+	if cfg.RemoteSigner != nil && cfg.RemoteSigner.Enable {
+		s.annSigner, err = netann.NewBatchSigner(
+			s.nodeSigner, cfg.RemoteSigner.GossipSignConcurrency,
+			cfg.RemoteSigner.GossipSigCacheSize,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	s.witnessBeacon = newPreimageBeacon(
 		dbs.ChanStateDB.NewWitnessCache(),
 		s.interceptableSwitch.ForwardPacket,
@@ -743,7 +908,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		ChanDisableTimeout:       cfg.ChanDisableTimeout,
 		OurPubKey:                nodeKeyDesc.PubKey,
 		OurKeyLoc:                nodeKeyDesc.KeyLocator,
-		MessageSigner:            s.nodeSigner,
+		MessageSigner:            s.annSigner,
 		IsChannelActive:          s.htlcSwitch.HasActiveLink,
 		ApplyChannelUpdate:       s.applyChannelUpdate,
 		DB:                       s.chanStateDB,
@@ -883,7 +1048,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 	// With the announcement generated, we'll sign it to properly
 	// authenticate the message on the network.
 	authSig, err := netann.SignAnnouncement(
-		s.nodeSigner, nodeKeyDesc.KeyLocator, nodeAnn,
+		s.annSigner, nodeKeyDesc.KeyLocator, nodeAnn,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("unable to generate signature for "+
@@ -985,6 +1150,16 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 			"default namespace: %w", err)
 	}
 
+	if routingConfig.LearnFromForwards {
+		learnerCfg := &routing.ForwardLearnerConfig{
+			SelfNode:            selfNode.PubKeyBytes,
+			SubscribeHtlcEvents: s.htlcNotifier.SubscribeHtlcEvents,
+			ChannelPeer:         s.channelPeer,
+			ReportSuccess:       s.defaultMC.ReportForwardSuccess,
+		}
+		s.forwardLearner = routing.NewForwardLearner(learnerCfg)
+	}
+
 	srvrLog.Debugf("Instantiating payment session source with config: "+
 		"AttemptCost=%v + %v%%, MinRouteProbability=%v",
 		int64(routingConfig.AttemptCost),
@@ -1003,6 +1178,12 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 	if err != nil {
 		return nil, fmt.Errorf("error getting source node: %w", err)
 	}
+	// The outbound liquidity reserved for newly launched shards is shared
+	// between path finding and the payment lifecycles.
+	shardReservations := routing.NewLiquidityReservations(
+		routingConfig.ShardReservationTimeout, clock.NewDefaultClock(),
+	)
+
 	paymentSessionSource := &routing.SessionSource{
 		GraphSessionFactory: graphsession.NewGraphSessionFactory(
 			chanGraph,
@@ -1011,6 +1192,8 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		MissionControl:    s.defaultMC,
 		GetLink:           s.htlcSwitch.GetLinkByShortID,
 		PathFindingConfig: pathFindingConfig,
+		Reservations:      shardReservations,
+		BlockIntervals:    s.cc.BlockIntervalTracker,
 	}
 
 	paymentControl := channeldb.NewPaymentControl(dbs.ChanStateDB)
@@ -1037,6 +1220,23 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		return nil, fmt.Errorf("can't create graph builder: %w", err)
 	}
 
+	centralityGraph := autopilot.ChannelGraphFromDatabase(chanGraph)
+	s.centralityService, err = autopilot.NewCentralityService(
+		&autopilot.CentralityServiceConfig{
+			Self:              selfNode.PubKeyBytes,
+			Graph:             centralityGraph,
+			SubscribeTopology: s.graphBuilder.SubscribeTopology,
+			Workers:           runtime.NumCPU(),
+			RefreshTicker: ticker.New(
+				autopilot.DefaultCentralityRefreshInterval,
+			),
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("can't create centrality service: %w",
+			err)
+	}
+
 	s.chanRouter, err = routing.New(routing.Config{
 		SelfNode:           selfNode.PubKeyBytes,
 		RoutingGraph:       graphsession.NewRoutingGraph(chanGraph),
@@ -1052,6 +1252,8 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		ApplyChannelUpdate: s.graphBuilder.ApplyChannelUpdate,
 		ClosedSCIDs:        s.fetchClosedChannelSCIDs(),
 		TrafficShaper:      implCfg.TrafficShaper,
+		Reservations:       shardReservations,
+		ShardPacing:        routingConfig.ShardPacingInterval,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("can't create router: %w", err)
@@ -1090,10 +1292,11 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		RebroadcastInterval:     time.Hour * 24,
 		WaitingProofStore:       waitingProofStore,
 		MessageStore:            gossipMessageStore,
-		AnnSigner:               s.nodeSigner,
+		AnnSigner:               s.annSigner,
 		RotateTicker:            ticker.New(discovery.DefaultSyncerRotationInterval),
 		HistoricalSyncTicker:    ticker.New(cfg.HistoricalSyncInterval),
 		NumActiveSyncers:        cfg.NumGraphSyncPeers,
+		NumInitialSyncPeers:     cfg.Gossip.InitialSyncPeers,
 		NoTimestampQueries:      cfg.ProtocolOptions.NoTimestampQueryOption, //nolint:lll
 		MinimumBatchSize:        10,
 		SubBatchDelay:           cfg.Gossip.SubBatchDelay,
@@ -1108,6 +1311,8 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		FindChannel:             s.findChannel,
 		IsStillZombieChannel:    s.graphBuilder.IsZombieChannel,
 		ScidCloser:              scidCloserMan,
+		EnableBridge:            cfg.Gossip.Bridge,
+		Egress:                  cfg.Gossip.EgressConfig(),
 	}, nodeKeyDesc)
 
 	//nolint:lll
@@ -1118,6 +1323,34 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		FetchChannel:              s.chanStateDB.FetchChannel,
 	}
 
+	s.chanOutgoingCltvRejectDelta, err =
+		cfg.Htlcswitch.ChanOutgoingCltvRejectDeltas()
+	if err != nil {
+		return nil, err
+	}
+
+	feeSuggestCfg := cfg.Routing.FeeSuggest
+
+	//nolint:lll
+	s.feeSuggester = localchans.NewFeeSuggester(
+		&localchans.FeeSuggesterConfig{
+			SelfNode:               selfNode.PubKeyBytes,
+			ForAllOutgoingChannels: s.graphBuilder.ForAllOutgoingChannels,
+			FetchChannel:           s.chanStateDB.FetchChannel,
+			ForEachNodeChannel:     s.graphDB.ForEachNodeChannel,
+			ForwardingEvents: forwardingEventsInRange(
+				dbs.ChanStateDB.ForwardingLog(),
+			),
+			UpdatePolicy:      s.localChanMgr.UpdatePolicy,
+			Lookback:          feeSuggestCfg.Lookback,
+			MinFeeRate:        feeSuggestCfg.MinFeeRate,
+			MaxFeeRate:        feeSuggestCfg.MaxFeeRate,
+			MaxChange:         feeSuggestCfg.MaxChange,
+			AutoApplyInterval: feeSuggestCfg.AutoApplyInterval,
+			Clock:             clock.NewDefaultClock(),
+		},
+	)
+
 	utxnStore, err := contractcourt.NewNurseryStore(
 		s.cfg.ActiveNetParams.GenesisHash, dbs.ChanStateDB,
 	)
@@ -1134,17 +1367,39 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		return nil, err
 	}
 
+	sweepJournal, err := sweep.NewSweepJournal(dbs.ChanStateDB)
+	if err != nil {
+		srvrLog.Errorf("unable to create sweep journal: %v", err)
+		return nil, err
+	}
+
 	aggregator := sweep.NewBudgetAggregator(
 		cc.FeeEstimator, sweep.DefaultMaxInputsPerTx,
 		s.implCfg.AuxSweeper,
 	)
 
+	// Sweep and force close transactions are optionally also submitted to
+	// external broadcasters.
+	s.extPublisher, err = sweep.NewExternalPublisherFromConfig(
+		cfg.Sweeper.ExtBroadcast,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	publishTx := cc.Wallet.PublishTransaction
+	if s.extPublisher != nil {
+		publishTx = s.extPublisher.WrapPublish(publishTx)
+	}
+
 	s.txPublisher = sweep.NewTxPublisher(sweep.TxPublisherConfig{
 		Signer:     cc.Wallet.Cfg.Signer,
-		Wallet:     cc.Wallet,
+		Wallet:     newSweeperWallet(cc.Wallet, publishTx),
 		Estimator:  cc.FeeEstimator,
 		Notifier:   cc.ChainNotifier,
 		AuxSweeper: s.implCfg.AuxSweeper,
+
+		PackagePublisher: cc.PackagePublisher,
 	})
 
 	s.sweeper = sweep.New(&sweep.UtxoSweeperConfig{
@@ -1153,7 +1408,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 			cc.Wallet, s.cfg.ActiveNetParams.Params,
 		),
 		Signer:               cc.Wallet.Cfg.Signer,
-		Wallet:               newSweeperWallet(cc.Wallet),
+		Wallet:               newSweeperWallet(cc.Wallet, publishTx),
 		Mempool:              cc.MempoolNotifier,
 		Notifier:             cc.ChainNotifier,
 		Store:                sweeperStore,
@@ -1162,6 +1417,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		Aggregator:           aggregator,
 		Publisher:            s.txPublisher,
 		NoDeadlineConfTarget: cfg.Sweeper.NoDeadlineConfTarget,
+		Journal:              sweepJournal,
 	})
 
 	s.utxoNursery = contractcourt.NewUtxoNursery(&contractcourt.NurseryConfig{
@@ -1192,6 +1448,15 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 	// breach events from the ChannelArbitrator to the BreachArbitrator,
 	contractBreaches := make(chan *contractcourt.ContractBreachEvent, 1)
 
+	// If enabled, detected breaches are also handed to the external
+	// broadcasters.
+	var justiceDelegate fn.Option[contractcourt.JusticeDelegate]
+	if s.extPublisher != nil && cfg.Sweeper.ExtBroadcast.DelegateJustice {
+		justiceDelegate = fn.Some[contractcourt.JusticeDelegate](
+			newExtJusticeDelegate(s.extPublisher),
+		)
+	}
+
 	s.breachArbitrator = contractcourt.NewBreachArbitrator(
 		&contractcourt.BreachConfig{
 			CloseLink: closeLink,
@@ -1207,7 +1472,8 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 			Store: contractcourt.NewRetributionStore(
 				dbs.ChanStateDB,
 			),
-			AuxSweeper: s.implCfg.AuxSweeper,
+			AuxSweeper:      s.implCfg.AuxSweeper,
+			JusticeDelegate: justiceDelegate,
 		},
 	)
 
@@ -1226,7 +1492,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 
 			return addr.DeliveryAddress, nil
 		},
-		PublishTx: cc.Wallet.PublishTransaction,
+		PublishTx: publishTx,
 		DeliverResolutionMsg: func(msgs ...contractcourt.ResolutionMsg) error {
 			for _, msg := range msgs {
 				err := s.htlcSwitch.ProcessContractResolution(msg)
@@ -1236,6 +1502,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 			}
 			return nil
 		},
+		FailStrandedHTLCs: s.htlcSwitch.FailStrandedHTLCs,
 		IncubateOutputs: func(chanPoint wire.OutPoint,
 			outHtlcRes fn.Option[lnwallet.OutgoingHtlcResolution],
 			inHtlcRes fn.Option[lnwallet.IncomingHtlcResolution],
@@ -1307,6 +1574,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		Registry:                      s.invoices,
 		NotifyClosedChannel:           s.channelNotifier.NotifyClosedChannelEvent,
 		NotifyFullyResolvedChannel:    s.channelNotifier.NotifyFullyResolvedChannelEvent,
+		NotifyStaleRemoteClose:        s.channelNotifier.NotifyStaleRemoteCloseEvent,
 		OnionProcessor:                s.sphinx,
 		PaymentsExpirationGracePeriod: cfg.PaymentsExpirationGracePeriod,
 		IsForwardedHTLC:               s.htlcSwitch.IsForwardedHTLC,
@@ -1315,6 +1583,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		PutFinalHtlcOutcome:           s.chanStateDB.PutOnchainFinalHtlcOutcome,
 		HtlcNotifier:                  s.htlcNotifier,
 		Budget:                        *s.cfg.Sweeper.Budget,
+		CommitRebroadcastInterval:     s.cfg.Sweeper.CommitRebroadcastInterval,
 
 		// TODO(yy): remove this hack once PaymentCircuit is interfaced.
 		QueryIncomingCircuit: func(
@@ -1338,8 +1607,8 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 
 	// Select the configuration and funding parameters for Bitcoin.
 	chainCfg := cfg.Bitcoin
-	minRemoteDelay := funding.MinBtcRemoteDelay
-	maxRemoteDelay := funding.MaxBtcRemoteDelay
+	minRemoteDelay := cfg.Funding.MinRemoteDelay
+	maxRemoteDelay := cfg.Funding.MaxRemoteDelay
 
 	var chanIDSeed [32]byte
 	if _, err := rand.Read(chanIDSeed[:]); err != nil {
@@ -1389,7 +1658,8 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 	// For the reservationTimeout and the zombieSweeperInterval different
 	// values are set in case we are in a dev environment so enhance test
 	// capacilities.
-	reservationTimeout := chanfunding.DefaultReservationTimeout
+	pendingChanCfg := cfg.PendingChannels
+	reservationTimeout := pendingChanCfg.ReservationTimeout
 	zombieSweeperInterval := lncfg.DefaultZombieSweeperInterval
 
 	// Get the development config for funding manager. If we are not in
@@ -1412,6 +1682,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 	s.fundingMgr, err = funding.NewFundingManager(funding.Config{
 		Dev:                devCfg,
 		NoWumboChans:       !cfg.ProtocolOptions.Wumbo(),
+		EnableDualFunding:  cfg.ProtocolOptions.DualFunding,
 		IDKey:              nodeKeyDesc.PubKey,
 		IDKeyLoc:           nodeKeyDesc.KeyLocator,
 		Wallet:             cc.Wallet,
@@ -1567,11 +1838,14 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		},
 		ZombieSweeperInterval:         zombieSweeperInterval,
 		ReservationTimeout:            reservationTimeout,
+		ResponderConfTimeout:          pendingChanCfg.ResponderConfTimeout,
+		InitiatorConfTimeout:          pendingChanCfg.InitiatorConfTimeout,
 		MinChanSize:                   btcutil.Amount(cfg.MinChanSize),
 		MaxChanSize:                   btcutil.Amount(cfg.MaxChanSize),
 		MaxPendingChannels:            cfg.MaxPendingChannels,
 		RejectPush:                    cfg.RejectPush,
 		MaxLocalCSVDelay:              chainCfg.MaxLocalDelay,
+		NegotiationBounds:             cfg.Funding.NegotiationBounds(),
 		NotifyOpenChannelEvent:        s.channelNotifier.NotifyOpenChannelEvent,
 		OpenChannelPredicate:          chanPredicate,
 		NotifyPendingOpenChannelEvent: s.channelNotifier.NotifyPendingOpenChannelEvent,
@@ -1580,6 +1854,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 			s.cfg.MaxCommitFeeRateAnchors * 1000).FeePerKWeight(),
 		DeleteAliasEdge:      deleteAliasEdge,
 		AliasManager:         s.aliasMgr,
+		FetchAliasChanUpdate: s.fetchAliasChanUpdate,
 		IsSweeperOutpoint:    s.sweeper.IsSweeperOutpoint,
 		AuxFundingController: implCfg.AuxFundingController,
 		AuxSigner:            implCfg.AuxSigner,
@@ -1596,14 +1871,43 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		addrs:        dbs.ChanStateDB,
 	}
 	backupFile := chanbackup.NewMultiFile(cfg.BackupFilePath)
+	s.backupKeyRing, err = newBackupKeyRing(
+		cfg.BackupPassphraseFile, s.cc.KeyRing, backupFile,
+	)
+	if err != nil {
+		return nil, err
+	}
 	startingChans, err := chanbackup.FetchStaticChanBackups(
 		s.chanStateDB, s.addrSource,
 	)
 	if err != nil {
 		return nil, err
 	}
+
+	// If any remote targets are configured, each new version of the
+	// backup file is additionally replicated to them.
+	var backupSwapper chanbackup.Swapper = backupFile
+	backupTargets, err := cfg.BackupReplication.Targets()
+	if err != nil {
+		return nil, err
+	}
+	if len(backupTargets) > 0 {
+		s.backupReplicator = chanbackup.NewReplicator(
+			chanbackup.ReplicatorConfig{
+				Targets:     backupTargets,
+				MaxVersions: cfg.BackupReplication.MaxVersions,
+				Timeout:     cfg.BackupReplication.Timeout,
+				MinBackoff:  cfg.BackupReplication.MinBackoff,
+				MaxBackoff:  cfg.BackupReplication.MaxBackoff,
+			},
+		)
+		backupSwapper = chanbackup.NewReplicatingSwapper(
+			backupFile, s.backupReplicator,
+		)
+	}
+
 	s.chanSubSwapper, err = chanbackup.NewSubSwapper(
-		startingChans, chanNotifier, s.cc.KeyRing, backupFile,
+		startingChans, chanNotifier, s.backupKeyRing, backupSwapper,
 	)
 	if err != nil {
 		return nil, err
@@ -1725,6 +2029,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 			MinBackoff:         10 * time.Second,
 			MaxBackoff:         5 * time.Minute,
 			MaxTasksInMemQueue: cfg.WtClient.MaxTasksInMemQueue,
+			MaxInFlightUpdates: cfg.WtClient.MaxInFlightUpdates,
 		}, policy, anchorPolicy, taprootPolicy)
 		if err != nil {
 			return nil, err
@@ -2053,6 +2358,12 @@ func (s *server) Start() error {
 			return
 		}
 
+		cleanup = cleanup.add(s.onionMessageServer.Stop)
+		if err := s.onionMessageServer.Start(); err != nil {
+			startErr = err
+			return
+		}
+
 		if s.hostAnn != nil {
 			cleanup = cleanup.add(s.hostAnn.Stop)
 			if err := s.hostAnn.Start(); err != nil {
@@ -2104,6 +2415,12 @@ func (s *server) Start() error {
 			return
 		}
 
+		cleanup = cleanup.add(s.cc.BlockIntervalTracker.Stop)
+		if err := s.cc.BlockIntervalTracker.Start(); err != nil {
+			startErr = err
+			return
+		}
+
 		cleanup = cleanup.add(s.channelNotifier.Stop)
 		if err := s.channelNotifier.Start(); err != nil {
 			startErr = err
@@ -2124,6 +2441,14 @@ func (s *server) Start() error {
 			return
 		}
 
+		if s.forwardLearner != nil {
+			cleanup = cleanup.add(s.forwardLearner.Stop)
+			if err := s.forwardLearner.Start(); err != nil {
+				startErr = err
+				return
+			}
+		}
+
 		if s.towerClientMgr != nil {
 			cleanup = cleanup.add(s.towerClientMgr.Stop)
 			if err := s.towerClientMgr.Start(); err != nil {
@@ -2195,6 +2520,12 @@ func (s *server) Start() error {
 			return
 		}
 
+		cleanup = cleanup.add(s.centralityService.Stop)
+		if err := s.centralityService.Start(); err != nil {
+			startErr = err
+			return
+		}
+
 		cleanup = cleanup.add(s.chanRouter.Stop)
 		if err := s.chanRouter.Start(); err != nil {
 			startErr = err
@@ -2214,6 +2545,14 @@ func (s *server) Start() error {
 			return
 		}
 
+		if s.offersMgr != nil {
+			cleanup = cleanup.add(s.offersMgr.Stop)
+			if err := s.offersMgr.Start(); err != nil {
+				startErr = err
+				return
+			}
+		}
+
 		cleanup = cleanup.add(s.sphinx.Stop)
 		if err := s.sphinx.Start(); err != nil {
 			startErr = err
@@ -2226,6 +2565,12 @@ func (s *server) Start() error {
 			return
 		}
 
+		cleanup = cleanup.add(s.feeSuggester.Stop)
+		if err := s.feeSuggester.Start(); err != nil {
+			startErr = err
+			return
+		}
+
 		cleanup = cleanup.add(s.chanEventStore.Stop)
 		if err := s.chanEventStore.Start(); err != nil {
 			startErr = err
@@ -2247,25 +2592,21 @@ func (s *server) Start() error {
 			secretKeys: s.cc.KeyRing,
 			chainArb:   s.chainArb,
 		}
-		if len(s.chansToRestore.PackedSingleChanBackups) != 0 {
-			err := chanbackup.UnpackAndRecoverSingles(
-				s.chansToRestore.PackedSingleChanBackups,
-				s.cc.KeyRing, chanRestorer, s,
-			)
-			if err != nil {
-				startErr = fmt.Errorf("unable to unpack single "+
-					"backups: %v", err)
-				return
-			}
+		err := recoverChanBackups(
+			s.chansToRestore, s.backupKeyRing, s.cc.KeyRing,
+			chanRestorer, s,
+		)
+		if err != nil {
+			startErr = err
+			return
 		}
-		if len(s.chansToRestore.PackedMultiChanBackup) != 0 {
-			err := chanbackup.UnpackAndRecoverMulti(
-				s.chansToRestore.PackedMultiChanBackup,
-				s.cc.KeyRing, chanRestorer, s,
-			)
-			if err != nil {
-				startErr = fmt.Errorf("unable to unpack chan "+
-					"backup: %v", err)
+
+		// The backup replicator must be started before the
+		// chanSubSwapper, which writes the initial backup on startup.
+		if s.backupReplicator != nil {
+			cleanup = cleanup.add(s.backupReplicator.Stop)
+			if err := s.backupReplicator.Start(); err != nil {
+				startErr = err
 				return
 			}
 		}
@@ -2292,6 +2633,11 @@ func (s *server) Start() error {
 			go s.watchExternalIP()
 		}
 
+		if s.cfg.DB.ClosedChanRetention > 0 {
+			s.wg.Add(1)
+			go s.reapClosedChannels()
+		}
+
 		// Start connmgr last to prevent connections before init.
 		cleanup = cleanup.add(func() error {
 			s.connMgr.Stop()
@@ -2453,12 +2799,21 @@ func (s *server) Stop() error {
 		if err := s.chanStatusMgr.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop chanStatusMgr: %v", err)
 		}
+		if err := s.feeSuggester.Stop(); err != nil {
+			srvrLog.Warnf("failed to stop feeSuggester: %v", err)
+		}
 		if err := s.htlcSwitch.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop htlcSwitch: %v", err)
 		}
 		if err := s.sphinx.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop sphinx: %v", err)
 		}
+		if s.offersMgr != nil {
+			if err := s.offersMgr.Stop(); err != nil {
+				srvrLog.Warnf("failed to stop offersMgr: %v",
+					err)
+			}
+		}
 		if err := s.invoices.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop invoices: %v", err)
 		}
@@ -2473,6 +2828,10 @@ func (s *server) Stop() error {
 		if err := s.chanRouter.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop chanRouter: %v", err)
 		}
+		if err := s.centralityService.Stop(); err != nil {
+			srvrLog.Warnf("failed to stop centralityService: %v",
+				err)
+		}
 		if err := s.chainArb.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop chainArb: %v", err)
 		}
@@ -2495,12 +2854,21 @@ func (s *server) Stop() error {
 		if err := s.txPublisher.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop txPublisher: %v", err)
 		}
+		if s.extPublisher != nil {
+			s.extPublisher.Stop()
+		}
 		if err := s.channelNotifier.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop channelNotifier: %v", err)
 		}
 		if err := s.peerNotifier.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop peerNotifier: %v", err)
 		}
+		if s.forwardLearner != nil {
+			if err := s.forwardLearner.Stop(); err != nil {
+				srvrLog.Warnf("failed to stop forwardLearner: "+
+					"%v", err)
+			}
+		}
 		if err := s.htlcNotifier.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop htlcNotifier: %v", err)
 		}
@@ -2524,6 +2892,12 @@ func (s *server) Stop() error {
 		if err := s.chanSubSwapper.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop chanSubSwapper: %v", err)
 		}
+		if s.backupReplicator != nil {
+			if err := s.backupReplicator.Stop(); err != nil {
+				srvrLog.Warnf("failed to stop "+
+					"backupReplicator: %v", err)
+			}
+		}
 		if err := s.cc.ChainNotifier.Stop(); err != nil {
 			srvrLog.Warnf("Unable to stop ChainNotifier: %v", err)
 		}
@@ -2531,6 +2905,10 @@ func (s *server) Stop() error {
 			srvrLog.Warnf("Unable to stop BestBlockTracker: %v",
 				err)
 		}
+		if err := s.cc.BlockIntervalTracker.Stop(); err != nil {
+			srvrLog.Warnf("Unable to stop BlockIntervalTracker: %v",
+				err)
+		}
 		if err := s.chanEventStore.Stop(); err != nil {
 			srvrLog.Warnf("Unable to stop ChannelEventStore: %v",
 				err)
@@ -2619,6 +2997,56 @@ func (s *server) configurePortForwarding(ports ...uint16) ([]string, error) {
 	return externalIPs, nil
 }
 
+// reapClosedChannels periodically prunes the data of fully closed channels
+// that were closed more than ClosedChanRetention blocks ago, to keep the size
+// of the channel database bounded.
+//
+// NOTE: This MUST be run as a goroutine.
+func (s *server) reapClosedChannels() {
+	defer s.wg.Done()
+
+	reap := func() {
+		_, bestHeight, err := s.cc.ChainIO.GetBestBlock()
+		if err != nil {
+			srvrLog.Errorf("Unable to fetch best block to prune "+
+				"closed channels: %v", err)
+			return
+		}
+
+		retention := s.cfg.DB.ClosedChanRetention
+		if uint32(bestHeight) <= retention {
+			return
+		}
+
+		closeHeight := uint32(bestHeight) - retention
+		numPruned, err := s.chanStateDB.PruneClosedChannels(closeHeight)
+		if err != nil {
+			srvrLog.Errorf("Unable to prune closed channels: %v",
+				err)
+			return
+		}
+
+		if numPruned > 0 {
+			srvrLog.Infof("Pruned the data of %d channels closed "+
+				"before height %d", numPruned, closeHeight)
+		}
+	}
+
+	ticker := time.NewTicker(s.cfg.DB.ClosedChanReapInterval)
+	defer ticker.Stop()
+
+	reap()
+	for {
+		select {
+		case <-ticker.C:
+			reap()
+
+		case <-s.quit:
+			return
+		}
+	}
+}
+
 // removePortForwarding attempts to clear the forwarding rules for the different
 // ports the server is currently listening on.
 //
@@ -3233,7 +3661,7 @@ func (s *server) genNodeAnnouncement(features *lnwire.RawFeatureVector,
 
 	// Sign a new update after applying all of the passed modifiers.
 	err := netann.SignNodeAnnouncement(
-		s.nodeSigner, s.identityKeyLoc, s.currentNodeAnn,
+		s.annSigner, s.identityKeyLoc, s.currentNodeAnn,
 	)
 	if err != nil {
 		return lnwire.NodeAnnouncement{}, err
@@ -3269,6 +3697,7 @@ func (s *server) updateAndBrodcastSelfNode(features *lnwire.RawFeatureVector,
 	selfNode.Alias = newNodeAnn.Alias.String()
 	selfNode.Features = s.featureMgr.Get(feature.SetNodeAnn)
 	selfNode.Color = newNodeAnn.RGBColor
+	selfNode.ExtraOpaqueData = newNodeAnn.ExtraOpaqueData
 	selfNode.AuthSigBytes = newNodeAnn.Signature.ToSignatureBytes()
 
 	copy(selfNode.PubKeyBytes[:], s.identityECDH.PubKey().SerializeCompressed())
@@ -3327,6 +3756,14 @@ func (s *server) establishPersistentConnections() error {
 		return err
 	}
 
+	// While iterating over our channels, we'll also gather the aggregate
+	// capacity of the channels with each peer, so we can reconnect to the
+	// economically most important peers first.
+	var (
+		priorities = make(map[string]*peerPriority)
+		chanPeers  = make(map[uint64]string)
+	)
+
 	// TODO(roasbeef): instead iterate over link nodes and query graph for
 	// each of the nodes.
 	selfPub := s.identityECDH.PubKey().SerializeCompressed()
@@ -3356,6 +3793,14 @@ func (s *server) establishPersistentConnections() error {
 
 		pubStr := string(channelPeer.PubKeyBytes[:])
 
+		priority, ok := priorities[pubStr]
+		if !ok {
+			priority = &peerPriority{}
+			priorities[pubStr] = priority
+		}
+		priority.capacity += chanInfo.Capacity
+		chanPeers[chanInfo.ChannelID] = pubStr
+
 		// Add all unique addresses from channel
 		// graph/NodeAnnouncements to the list of addresses we'll
 		// connect to for this peer.
@@ -3414,6 +3859,14 @@ func (s *server) establishPersistentConnections() error {
 		return err
 	}
 
+	// Failing to read the forwarding activity only affects the order in
+	// which we reconnect, so it isn't fatal.
+	err = s.addForwardingActivity(chanPeers, priorities)
+	if err != nil {
+		srvrLog.Warnf("Unable to read forwarding activity to "+
+			"prioritize peers: %v", err)
+	}
+
 	srvrLog.Debugf("Establishing %v persistent connections on start",
 		len(nodeAddrsMap))
 
@@ -3423,9 +3876,20 @@ func (s *server) establishPersistentConnections() error {
 	defer s.mu.Unlock()
 
 	// Iterate through the combined list of addresses from prior links and
-	// node announcements and attempt to reconnect to each node.
-	var numOutboundConns int
+	// node announcements and record the peers we'll reconnect to.
+	peers := make([]string, 0, len(nodeAddrsMap))
 	for pubStr, nodeAddr := range nodeAddrsMap {
+		// Skip peers that aren't on the allow list, their connections
+		// would be dropped anyway.
+		var pubBytes route.Vertex
+		copy(pubBytes[:], pubStr)
+		if !s.isPeerAllowed(pubBytes) {
+			srvrLog.Debugf("Skipping persistent connection to %x "+
+				"not on the allow list", pubBytes[:])
+
+			continue
+		}
+
 		// Add this peer to the set of peers we should maintain a
 		// persistent connection with. We set the value to false to
 		// indicate that we should not continue to reconnect if the
@@ -3449,24 +3913,32 @@ func (s *server) establishPersistentConnections() error {
 				s.persistentPeerAddrs[pubStr], lnAddr)
 		}
 
-		// We'll connect to the first 10 peers immediately, then
-		// randomly stagger any remaining connections if the
-		// stagger initial reconnect flag is set. This ensures
-		// that mobile nodes or nodes with a small number of
-		// channels obtain connectivity quickly, but larger
-		// nodes are able to disperse the costs of connecting to
-		// all peers at once.
-		if numOutboundConns < numInstantInitReconnect ||
-			!s.cfg.StaggerInitialReconnect {
+		peers = append(peers, pubStr)
+	}
 
-			go s.connectToPersistentPeer(pubStr)
-		} else {
+	// We'll reconnect to the peers in the order of their aggregate channel
+	// capacity and recent forwarding activity, so that our economically
+	// most important channels come online first.
+	sortPeersByPriority(peers, priorities)
+
+	// We'll connect to the first 10 peers immediately, then randomly
+	// stagger any remaining connections if the stagger initial reconnect
+	// flag is set. This ensures that mobile nodes or nodes with a small
+	// number of channels obtain connectivity quickly, but larger nodes are
+	// able to disperse the costs of connecting to all peers at once.
+	instantPeers := peers
+	if s.cfg.StaggerInitialReconnect &&
+		len(peers) > numInstantInitReconnect {
+
+		instantPeers = peers[:numInstantInitReconnect]
+		for _, pubStr := range peers[numInstantInitReconnect:] {
 			go s.delayInitialReconnect(pubStr)
 		}
-
-		numOutboundConns++
 	}
 
+	s.wg.Add(1)
+	go s.connectPeersByPriority(instantPeers)
+
 	return nil
 }
 
@@ -3776,6 +4248,17 @@ func (s *server) InboundPeerConnected(conn net.Conn) {
 		return
 	}
 
+	// If the allow list is enabled and the remote node isn't on it, drop
+	// the connection.
+	if !s.isPeerAllowed(pubBytes) {
+		srvrLog.Debugf("Dropping connection for %x since they are "+
+			"not on the allow list.", pubSer)
+
+		conn.Close()
+
+		return
+	}
+
 	// If we already have an outbound connection to this peer, then ignore
 	// this new connection.
 	if p, ok := s.outboundPeers[pubStr]; ok {
@@ -3890,6 +4373,21 @@ func (s *server) OutboundPeerConnected(connReq *connmgr.ConnReq, conn net.Conn)
 		return
 	}
 
+	// If the allow list is enabled and the remote node isn't on it, drop
+	// the connection.
+	if !s.isPeerAllowed(pubBytes) {
+		srvrLog.Debugf("Dropping connection for %x since they are "+
+			"not on the allow list.", pubSer)
+
+		if connReq != nil {
+			s.connMgr.Remove(connReq.ID())
+		}
+
+		conn.Close()
+
+		return
+	}
+
 	// If we already have an inbound connection to this peer, then ignore
 	// this new connection.
 	if p, ok := s.inboundPeers[pubStr]; ok {
@@ -4056,6 +4554,101 @@ func (s *server) SubscribeCustomMessages() (*subscribe.Client, error) {
 	return s.customMessageServer.Subscribe()
 }
 
+// handleOnionMessage decrypts an incoming onion message and either relays it
+// to the next node of its blinded path, or dispatches it to subscribers if we
+// are its recipient. Onion messages that can't be processed are dropped, as
+// the protocol doesn't allow for replying with an error.
+func (s *server) handleOnionMessage(peer [33]byte,
+	msg *lnwire.OnionMessage) error {
+
+	srvrLog.Debugf("Onion message received: peer=%x, blob_len=%d",
+		peer, len(msg.OnionBlob))
+
+	if !s.cfg.ProtocolOptions.OnionMessages {
+		srvrLog.Debugf("Dropping onion message from peer %x, onion "+
+			"messages are disabled", peer)
+
+		return nil
+	}
+
+	onionHop, err := s.sphinx.ProcessOnionMessage(msg)
+	if err != nil {
+		srvrLog.Debugf("Dropping onion message from peer %x: %v",
+			peer, err)
+
+		return nil
+	}
+
+	if onionHop.IsFinal() {
+		return s.onionMessageServer.SendUpdate(&OnionMessage{
+			Peer: peer,
+			Msg:  msg,
+			Hop:  onionHop,
+		})
+	}
+
+	if err := s.relayOnionMessage(onionHop); err != nil {
+		srvrLog.Debugf("Unable to relay onion message from peer %x: "+
+			"%v", peer, err)
+	}
+
+	return nil
+}
+
+// relayOnionMessage sends the onion message of the given hop to the next node
+// of its blinded path. Onion messages are only relayed to peers that we're
+// connected to already.
+func (s *server) relayOnionMessage(onionHop *hop.OnionMessageHop) error {
+	routeData := onionHop.RouteData
+
+	// The next node is either specified directly, or by the channel that
+	// we have with it.
+	var nextNode route.Vertex
+	nextNodeID, err := routeData.NextNodeID.UnwrapOrErrV(hop.ErrNoNextHop)
+	if err == nil {
+		nextNode = route.NewVertex(nextNodeID)
+	} else {
+		scid, err := routeData.ShortChannelID.UnwrapOrErrV(
+			hop.ErrNoNextHop,
+		)
+		if err != nil {
+			return err
+		}
+
+		nextNode, err = s.channelPeer(scid)
+		if err != nil {
+			return err
+		}
+	}
+
+	peer, err := s.FindPeerByPubStr(string(nextNode[:]))
+	if err != nil {
+		return err
+	}
+
+	// Relaying is best effort, so we won't wait for the message to be
+	// sent.
+	return peer.SendMessageLazy(false, onionHop.NextMsg)
+}
+
+// channelPeer returns the pubkey of the peer that we have the channel with the
+// given short channel id with.
+func (s *server) channelPeer(scid lnwire.ShortChannelID) (route.Vertex,
+	error) {
+
+	link, err := s.htlcSwitch.GetLinkByShortID(scid)
+	if err != nil {
+		return route.Vertex{}, err
+	}
+
+	return route.Vertex(link.PeerPubKey()), nil
+}
+
+// SubscribeOnionMessages subscribes to a stream of incoming onion messages.
+func (s *server) SubscribeOnionMessages() (*subscribe.Client, error) {
+	return s.onionMessageServer.Subscribe()
+}
+
 // peerConnected is a function that handles initialization a newly connected
 // peer by adding it to the server's global list of all active peers, and
 // starting all the goroutines the peer needs to function properly. The inbound
@@ -4070,6 +4663,20 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 	srvrLog.Infof("Finalizing connection to %x@%s, inbound=%v",
 		pubKey.SerializeCompressed(), addr, inbound)
 
+	// Configure the TCP keepalive options of the connection so a dead
+	// connection is detected on the transport level within seconds.
+	keepalive := s.cfg.Keepalive
+	err := brontideConn.SetTCPKeepalive(&brontide.TCPKeepalive{
+		Idle:        keepalive.TCPKeepaliveIdle,
+		Interval:    keepalive.TCPKeepaliveInterval,
+		Count:       keepalive.TCPKeepaliveCount,
+		UserTimeout: keepalive.TCPUserTimeout,
+	})
+	if err != nil {
+		srvrLog.Warnf("Unable to set TCP keepalive options for %x@%s: "+
+			"%v", pubKey.SerializeCompressed(), addr, err)
+	}
+
 	peerAddr := &lnwire.NetAddress{
 		IdentityKey: pubKey,
 		Address:     addr,
@@ -4114,6 +4721,8 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 	// offered that would trigger channel closure. In case of outgoing
 	// htlcs, an extra block is added to prevent the channel from being
 	// closed when the htlc is outstanding and a new block comes in.
+	rejectDelta := s.cfg.Htlcswitch.OutgoingCltvRejectDelta
+	chainCostMultiplier := s.cfg.Htlcswitch.HtlcChainCostMultiplier
 	pCfg := peer.Config{
 		Conn:                    brontideConn,
 		ConnReq:                 connReq,
@@ -4121,7 +4730,8 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 		Inbound:                 inbound,
 		Features:                initFeatures,
 		LegacyFeatures:          legacyFeatures,
-		OutgoingCltvRejectDelta: lncfg.DefaultOutgoingCltvRejectDelta,
+		OutgoingCltvRejectDelta: rejectDelta,
+		ChanCltvRejectDeltas:    s.chanOutgoingCltvRejectDelta,
 		ChanActiveTimeout:       s.cfg.ChanEnableTimeout,
 		ErrorBuffer:             errBuffer,
 		WritePool:               s.writePool,
@@ -4132,6 +4742,7 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 		ChannelGraph:            s.graphDB,
 		ChainArb:                s.chainArb,
 		AuthGossiper:            s.authGossiper,
+		ValidateGossipSemantics: s.cfg.Gossip.StrictValidation,
 		ChanStatusMgr:           s.chanStatusMgr,
 		ChainIO:                 s.cc.ChainIO,
 		FeeEstimator:            s.cc.FeeEstimator,
@@ -4155,10 +4766,21 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 		},
 
 		PongBuf: s.pongBuf,
+		ClearnetPing: peer.PingConfig{
+			Interval:     keepalive.Clearnet.Interval,
+			Timeout:      keepalive.Clearnet.Timeout,
+			MaxPongBytes: keepalive.Clearnet.MaxPongBytes,
+		},
+		TorPing: peer.PingConfig{
+			Interval:     keepalive.Tor.Interval,
+			Timeout:      keepalive.Tor.Timeout,
+			MaxPongBytes: keepalive.Tor.MaxPongBytes,
+		},
 
 		PrunePersistentPeerConnection: s.prunePersistentPeerConnection,
 
-		FetchLastChanUpdate: s.fetchLastChanUpdate(),
+		FetchLastChanUpdate:  s.fetchLastChanUpdate(),
+		FetchAliasChanUpdate: s.fetchAliasChanUpdate,
 
 		FundingManager: s.fundingMgr,
 
@@ -4167,17 +4789,20 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 		MaxOutgoingCltvExpiry:   s.cfg.MaxOutgoingCltvExpiry,
 		MaxChannelFeeAllocation: s.cfg.MaxChannelFeeAllocation,
 		CoopCloseTargetConfs:    s.cfg.CoopCloseTargetConfs,
+		HtlcChainCostMultiplier: chainCostMultiplier,
 		MaxAnchorsCommitFeeRate: chainfee.SatPerKVByte(
 			s.cfg.MaxCommitFeeRateAnchors * 1000).FeePerKWeight(),
 		ChannelCommitInterval:  s.cfg.ChannelCommitInterval,
 		PendingCommitInterval:  s.cfg.PendingCommitInterval,
 		ChannelCommitBatchSize: s.cfg.ChannelCommitBatchSize,
 		HandleCustomMessage:    s.handleCustomMessage,
+		HandleOnionMessage:     s.handleOnionMessage,
 		GetAliases:             s.aliasMgr.GetAliases,
 		RequestAlias:           s.aliasMgr.RequestAlias,
 		AddLocalAlias:          s.aliasMgr.AddLocalAlias,
 		DisallowRouteBlinding:  s.cfg.ProtocolOptions.NoRouteBlinding(),
 		MaxFeeExposure:         thresholdMSats,
+		LocalHtlcSlotReserve:   s.cfg.Htlcswitch.LocalHtlcSlotReserve,
 		Quit:                   s.quit,
 		AuxLeafStore:           s.implCfg.AuxLeafStore,
 		AuxSigner:              s.implCfg.AuxSigner,
@@ -4236,13 +4861,6 @@ func (s *server) addPeer(p *peer.Brontide) {
 	} else {
 		s.outboundPeers[pubStr] = p
 	}
-
-	// Inform the peer notifier of a peer online event so that it can be reported
-	// to clients listening for peer events.
-	var pubKey [33]byte
-	copy(pubKey[:], pubSer)
-
-	s.peerNotifier.NotifyPeerOnline(pubKey)
 }
 
 // peerInitializer asynchronously starts a newly connected peer after it has
@@ -4294,12 +4912,26 @@ func (s *server) peerInitializer(p *peer.Brontide) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Check if there are listeners waiting for this peer to come online.
-	srvrLog.Debugf("Notifying that peer %v is online", p)
-
 	// TODO(guggero): Do a proper conversion to a string everywhere, or use
 	// route.Vertex as the key type of peerConnectedListeners.
 	pubStr := string(pubBytes)
+
+	// Now that we know the features the peer sent us in its init message,
+	// inform the peer notifier of a peer online event so that it can be
+	// reported to clients listening for peer events. If the peer was
+	// already removed again in the meantime, there's nothing to report.
+	if s.peersByPub[pubStr] == p {
+		var pubKey [33]byte
+		copy(pubKey[:], pubBytes)
+
+		s.onlinePeers[p] = struct{}{}
+		s.peerNotifier.NotifyPeerOnline(
+			pubKey, peerConnInfo(p), p.RemoteFeatures(),
+		)
+	}
+
+	// Check if there are listeners waiting for this peer to come online.
+	srvrLog.Debugf("Notifying that peer %v is online", p)
 	for _, peerChan := range s.peerConnectedListeners[pubStr] {
 		select {
 		case peerChan <- p:
@@ -4647,11 +5279,27 @@ func (s *server) removePeer(p *peer.Brontide) {
 	}
 
 	// Inform the peer notifier of a peer offline event so that it can be
-	// reported to clients listening for peer events.
+	// reported to clients listening for peer events. We only do so for
+	// peers that were reported online before.
+	if _, ok := s.onlinePeers[p]; !ok {
+		return
+	}
+	delete(s.onlinePeers, p)
+
 	var pubKey [33]byte
 	copy(pubKey[:], pubSer)
 
-	s.peerNotifier.NotifyPeerOffline(pubKey)
+	reason, err := p.DisconnectReason()
+	s.peerNotifier.NotifyPeerOffline(pubKey, peerConnInfo(p), reason, err)
+}
+
+// peerConnInfo returns the metadata of the connection to the given peer that
+// is reported to clients listening for peer events.
+func peerConnInfo(p *peer.Brontide) peernotifier.ConnectionInfo {
+	return peernotifier.ConnectionInfo{
+		Address: p.Address(),
+		Inbound: p.Inbound(),
+	}
 }
 
 // ConnectToPeer requests that the server connect to a Lightning Network peer
@@ -4664,6 +5312,14 @@ func (s *server) ConnectToPeer(addr *lnwire.NetAddress,
 
 	targetPub := string(addr.IdentityKey.SerializeCompressed())
 
+	// Refuse to connect to peers that aren't on the allow list.
+	var targetVertex route.Vertex
+	copy(targetVertex[:], targetPub)
+	if !s.isPeerAllowed(targetVertex) {
+		return fmt.Errorf("unable to connect to %x: %w",
+			targetVertex[:], peer.ErrPeerNotAllowed)
+	}
+
 	// Acquire mutex, but use explicit unlocking instead of defer for
 	// better granularity.  In certain conditions, this method requires
 	// making an outbound connection to a remote peer, which requires the
@@ -4793,6 +5449,67 @@ func (s *server) DisconnectPeer(pubKey *btcec.PublicKey) error {
 	return nil
 }
 
+// isPeerAllowed returns true if we may connect to the given peer or accept a
+// connection from it.
+func (s *server) isPeerAllowed(pubKey route.Vertex) bool {
+	return s.peerAllowList == nil || s.peerAllowList.IsAllowed(pubKey)
+}
+
+// AllowPeer adds the given peer to the allow list.
+//
+// NOTE: This function is safe for concurrent access.
+func (s *server) AllowPeer(pubKey route.Vertex) error {
+	if s.peerAllowList == nil {
+		return errors.New("peer allow list not enabled")
+	}
+
+	return s.peerAllowList.Add(pubKey)
+}
+
+// DisallowPeer removes the given peer from the allow list. If we're connected
+// to the peer, the connection is closed and we'll stop reconnecting to it.
+//
+// NOTE: This function is safe for concurrent access.
+func (s *server) DisallowPeer(pubKey route.Vertex) error {
+	if s.peerAllowList == nil {
+		return errors.New("peer allow list not enabled")
+	}
+
+	if err := s.peerAllowList.Remove(pubKey); err != nil {
+		return err
+	}
+
+	pubStr := string(pubKey[:])
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cancelConnReqs(pubStr, nil)
+	delete(s.persistentPeers, pubStr)
+	delete(s.persistentPeersBackoff, pubStr)
+
+	connectedPeer, err := s.findPeerByPubStr(pubStr)
+	if err == ErrPeerNotConnected {
+		return nil
+	}
+
+	srvrLog.Infof("Disconnecting from %v, peer removed from allow list",
+		connectedPeer)
+
+	connectedPeer.Disconnect(peer.ErrPeerNotAllowed)
+
+	return nil
+}
+
+// ListAllowedPeers returns all peers on the allow list.
+func (s *server) ListAllowedPeers() ([]route.Vertex, error) {
+	if s.peerAllowList == nil {
+		return nil, errors.New("peer allow list not enabled")
+	}
+
+	return s.peerAllowList.List(), nil
+}
+
 // OpenChannel sends a request to the server to open a channel to the specified
 // peer identified by nodeKey with the passed channel funding parameters.
 //
@@ -4936,6 +5653,46 @@ func (s *server) fetchLastChanUpdate() func(lnwire.ShortChannelID) (
 	}
 }
 
+// fetchAliasChanUpdate returns our latest channel update for an unannounced
+// channel that negotiated the option-scid-alias feature. The SCID of the
+// update is replaced by the alias the peer sent us and the update is signed
+// again, so that the confirmed SCID isn't revealed to the peer. An error is
+// returned if the peer's alias isn't known yet.
+func (s *server) fetchAliasChanUpdate(
+	c *channeldb.OpenChannel) (*lnwire.ChannelUpdate1, error) {
+
+	// The graph refers to zero-conf channels by their confirmed SCID once
+	// they confirmed.
+	scid := c.ShortChanID()
+	if c.IsZeroConf() && c.ZeroConfConfirmed() {
+		scid = c.ZeroConfRealScid()
+	}
+
+	update, err := s.fetchLastChanUpdate()(scid)
+	if err != nil {
+		return nil, err
+	}
+
+	chanID := lnwire.NewChanIDFromOutPoint(c.FundingOutpoint)
+	alias, err := s.aliasMgr.GetPeerAlias(chanID)
+	if err != nil {
+		return nil, err
+	}
+	update.ShortChannelID = alias
+
+	sig, err := s.signAliasUpdate(update)
+	if err != nil {
+		return nil, err
+	}
+
+	update.Signature, err = lnwire.NewSigFromSignature(sig)
+	if err != nil {
+		return nil, err
+	}
+
+	return update, nil
+}
+
 // applyChannelUpdate applies the channel update to the different sub-systems of
 // the server. The useAlias boolean denotes whether or not to send an alias in
 // place of the real SCID.
@@ -4998,6 +5755,30 @@ func (s *server) SendCustomMessage(peerPub [33]byte, msgType lnwire.MessageType,
 	return peer.SendMessageLazy(true, msg)
 }
 
+// SendOnionMessage sends an onion message to the peer with the specified
+// pubkey, which is the first hop of the message's onion route.
+func (s *server) SendOnionMessage(peerPub [33]byte,
+	msg *lnwire.OnionMessage) error {
+
+	peer, err := s.FindPeerByPubStr(string(peerPub[:]))
+	if err != nil {
+		return err
+	}
+
+	// We'll wait until the peer is active.
+	select {
+	case <-peer.ActiveSignal():
+	case <-peer.QuitSignal():
+		return fmt.Errorf("peer %x disconnected", peerPub)
+	case <-s.quit:
+		return ErrServerShuttingDown
+	}
+
+	// Onion messages aren't time critical, so they're sent as
+	// low-priority.
+	return peer.SendMessageLazy(true, msg)
+}
+
 // newSweepPkScriptGen creates closure that generates a new public key script
 // which should be used to sweep any funds into the on-chain wallet.
 // Specifically, the script generated is a version 0, pay-to-witness-pubkey-hash
@@ -5096,3 +5877,158 @@ func (s *server) fetchClosedChannelSCIDs() map[lnwire.ShortChannelID]struct{} {
 
 	return closedSCIDs
 }
+
+// newBackupKeyRing returns the key ring that should be used to encrypt our
+// static channel backups. If no backup passphrase file is set, then this is
+// simply the main wallet key ring. Otherwise, a key ring deriving the backup
+// encryption key from the passphrase is returned, and any existing backup file
+// that was encrypted with the wallet key ring is re-encrypted with it.
+func newBackupKeyRing(passphraseFile string, keyRing keychain.KeyRing,
+	backupFile *chanbackup.MultiFile) (keychain.KeyRing, error) {
+
+	if passphraseFile == "" {
+		return keyRing, nil
+	}
+
+	passphrase, err := os.ReadFile(passphraseFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read backup passphrase "+
+			"file %s: %w", passphraseFile, err)
+	}
+
+	// Remove any newlines at the end of the file, just like we do for the
+	// wallet unlock password file.
+	passphrase = bytes.TrimRight(passphrase, "\r\n")
+
+	backupKeyRing, err := chanbackup.NewPassphraseKeyRing(
+		keyRing, passphrase,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = chanbackup.ReencryptMultiFile(backupFile, keyRing, backupKeyRing)
+	if err != nil {
+		return nil, fmt.Errorf("unable to re-encrypt backup file: %w",
+			err)
+	}
+
+	return backupKeyRing, nil
+}
+
+// findBackupKeyRing returns the first key ring that is able to decrypt a
+// static channel backup with the passed unpack closure. The backup key ring is
+// tried first, followed by the wallet key ring if a backup passphrase is in
+// use, so backups created before the passphrase was set can still be restored.
+func findBackupKeyRing(backupKeyRing, walletKeyRing keychain.KeyRing,
+	unpack func(keychain.KeyRing) error) (keychain.KeyRing, error) {
+
+	keyRings := []keychain.KeyRing{backupKeyRing}
+	if backupKeyRing != walletKeyRing {
+		keyRings = append(keyRings, walletKeyRing)
+	}
+
+	var err error
+	for _, keyRing := range keyRings {
+		if err = unpack(keyRing); err == nil {
+			return keyRing, nil
+		}
+	}
+
+	return nil, err
+}
+
+// recoverChanBackups restores the channels of the static channel backups that
+// were passed in when the wallet was created or unlocked. The backups may have
+// been encrypted with either the backup key ring or the wallet key ring.
+func recoverChanBackups(chans walletunlocker.ChannelsToRecover,
+	backupKeyRing, walletKeyRing keychain.KeyRing,
+	restorer chanbackup.ChannelRestorer,
+	peerConnector chanbackup.PeerConnector) error {
+
+	if len(chans.PackedSingleChanBackups) != 0 {
+		packedSingles := chans.PackedSingleChanBackups
+		keyRing, err := findBackupKeyRing(
+			backupKeyRing, walletKeyRing,
+			func(k keychain.KeyRing) error {
+				_, err := packedSingles.Unpack(k)
+				return err
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("unable to unpack single backups: "+
+				"%v", err)
+		}
+
+		err = chanbackup.UnpackAndRecoverSingles(
+			packedSingles, keyRing, restorer, peerConnector,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to unpack single backups: "+
+				"%v", err)
+		}
+	}
+
+	if len(chans.PackedMultiChanBackup) != 0 {
+		packedMulti := chans.PackedMultiChanBackup
+		keyRing, err := findBackupKeyRing(
+			backupKeyRing, walletKeyRing,
+			func(k keychain.KeyRing) error {
+				_, err := packedMulti.Unpack(k)
+				return err
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("unable to unpack chan backup: %v",
+				err)
+		}
+
+		err = chanbackup.UnpackAndRecoverMulti(
+			packedMulti, keyRing, restorer, peerConnector,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to unpack chan backup: %v",
+				err)
+		}
+	}
+
+	return nil
+}
+
+// forwardingEventsInRange returns a function that queries all forwarding events
+// of the given log within a time range.
+func forwardingEventsInRange(fwdLog *channeldb.ForwardingLog) func(start,
+	end time.Time) ([]channeldb.ForwardingEvent, error) {
+
+	// maxEventsPerQuery is the number of events fetched from the database
+	// at once.
+	const maxEventsPerQuery = 10000
+
+	return func(start, end time.Time) ([]channeldb.ForwardingEvent,
+		error) {
+
+		var (
+			events []channeldb.ForwardingEvent
+			offset uint32
+		)
+		for {
+			query := channeldb.ForwardingEventQuery{
+				StartTime:    start,
+				EndTime:      end,
+				IndexOffset:  offset,
+				NumMaxEvents: maxEventsPerQuery,
+			}
+			resp, err := fwdLog.Query(query)
+			if err != nil {
+				return nil, err
+			}
+
+			events = append(events, resp.ForwardingEvents...)
+			if len(resp.ForwardingEvents) < maxEventsPerQuery {
+				return events, nil
+			}
+
+			offset = resp.LastIndexOffset
+		}
+	}
+}
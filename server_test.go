@@ -1,9 +1,18 @@
 package lnd
 
 import (
+	"bytes"
+	"net"
 	"testing"
 
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/chanbackup"
+	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lncfg"
+	"github.com/lightningnetwork/lnd/lntest/mock"
+	"github.com/lightningnetwork/lnd/walletunlocker"
+	"github.com/stretchr/testify/require"
 )
 
 // TestShouldPeerBootstrap tests that we properly skip network bootstrap for
@@ -80,3 +89,129 @@ func TestShouldPeerBootstrap(t *testing.T) {
 		}
 	}
 }
+
+// mockChanRestorer is a chanbackup.ChannelRestorer that records the restored
+// channel backups.
+type mockChanRestorer struct {
+	restored []chanbackup.Single
+}
+
+// RestoreChansFromSingles records the passed channel backups.
+func (m *mockChanRestorer) RestoreChansFromSingles(
+	backups ...chanbackup.Single) error {
+
+	m.restored = append(m.restored, backups...)
+
+	return nil
+}
+
+// mockPeerConnector is a chanbackup.PeerConnector that records the peers it's
+// asked to connect to.
+type mockPeerConnector struct {
+	peers []*btcec.PublicKey
+}
+
+// ConnectPeer records the passed peer.
+func (m *mockPeerConnector) ConnectPeer(node *btcec.PublicKey,
+	_ []net.Addr) error {
+
+	m.peers = append(m.peers, node)
+
+	return nil
+}
+
+// TestRecoverChanBackupsPassphrase tests that channel backups passed in on
+// wallet creation or unlock are restored with the backup passphrase key ring,
+// and that backups encrypted with the wallet key ring can still be restored
+// once a backup passphrase is in use.
+func TestRecoverChanBackupsPassphrase(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	pubKey := privKey.PubKey()
+
+	walletKeyRing := &mock.SecretKeyRing{RootKey: privKey}
+	backupKeyRing, err := chanbackup.NewPassphraseKeyRing(
+		walletKeyRing, []byte("backup passphrase"),
+	)
+	require.NoError(t, err)
+
+	single := chanbackup.Single{
+		Version:         chanbackup.AnchorsCommitVersion,
+		FundingOutpoint: wire.OutPoint{Index: 1},
+		RemoteNodePub:   pubKey,
+		Addresses: []net.Addr{
+			&net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 9735},
+		},
+		Capacity: 100_000,
+	}
+	single.RemoteChanCfg.MultiSigKey.PubKey = pubKey
+	single.RemoteChanCfg.RevocationBasePoint.PubKey = pubKey
+	single.RemoteChanCfg.PaymentBasePoint.PubKey = pubKey
+	single.RemoteChanCfg.DelayBasePoint.PubKey = pubKey
+	single.RemoteChanCfg.HtlcBasePoint.PubKey = pubKey
+	multi := chanbackup.Multi{
+		StaticBackups: []chanbackup.Single{single},
+	}
+
+	packMulti := func(keyRing keychain.KeyRing) []byte {
+		var b bytes.Buffer
+		require.NoError(t, multi.PackToWriter(&b, keyRing))
+
+		return b.Bytes()
+	}
+
+	testCases := []struct {
+		name   string
+		packed []byte
+	}{
+		{
+			name:   "passphrase encrypted",
+			packed: packMulti(backupKeyRing),
+		},
+		{
+			name:   "wallet encrypted",
+			packed: packMulti(walletKeyRing),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			chans := walletunlocker.ChannelsToRecover{
+				PackedMultiChanBackup: tc.packed,
+			}
+			restorer := &mockChanRestorer{}
+			connector := &mockPeerConnector{}
+
+			err := recoverChanBackups(
+				chans, backupKeyRing, walletKeyRing, restorer,
+				connector,
+			)
+			require.NoError(t, err)
+
+			require.Len(t, restorer.restored, 1)
+			require.Equal(
+				t, single.FundingOutpoint,
+				restorer.restored[0].FundingOutpoint,
+			)
+			require.Equal(
+				t, []*btcec.PublicKey{pubKey}, connector.peers,
+			)
+		})
+	}
+
+	// A backup encrypted with neither key ring can't be restored.
+	otherKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	chans := walletunlocker.ChannelsToRecover{
+		PackedMultiChanBackup: packMulti(
+			&mock.SecretKeyRing{RootKey: otherKey},
+		),
+	}
+	err = recoverChanBackups(
+		chans, backupKeyRing, walletKeyRing, &mockChanRestorer{},
+		&mockPeerConnector{},
+	)
+	require.Error(t, err)
+}
@@ -405,6 +405,19 @@ func (q *Queries) GetInvoiceHTLCs(ctx context.Context, invoiceID int64) ([]Invoi
 	return items, nil
 }
 
+const getInvoiceOverpaymentPolicy = `-- name: GetInvoiceOverpaymentPolicy :one
+SELECT invoice_id, mode, max_percent
+FROM invoice_overpayment_policies
+WHERE invoice_id = $1
+`
+
+func (q *Queries) GetInvoiceOverpaymentPolicy(ctx context.Context, invoiceID int64) (InvoiceOverpaymentPolicy, error) {
+	row := q.db.QueryRowContext(ctx, getInvoiceOverpaymentPolicy, invoiceID)
+	var i InvoiceOverpaymentPolicy
+	err := row.Scan(&i.InvoiceID, &i.Mode, &i.MaxPercent)
+	return i, err
+}
+
 const insertInvoice = `-- name: InsertInvoice :one
 INSERT INTO invoices (
     hash, preimage, memo, amount_msat, cltv_delta, expiry, payment_addr, 
@@ -533,6 +546,25 @@ func (q *Queries) InsertInvoiceHTLCCustomRecord(ctx context.Context, arg InsertI
 	return err
 }
 
+const insertInvoiceOverpaymentPolicy = `-- name: InsertInvoiceOverpaymentPolicy :exec
+INSERT INTO invoice_overpayment_policies (
+    invoice_id, mode, max_percent
+) VALUES (
+    $1, $2, $3
+)
+`
+
+type InsertInvoiceOverpaymentPolicyParams struct {
+	InvoiceID  int64
+	Mode       int16
+	MaxPercent int64
+}
+
+func (q *Queries) InsertInvoiceOverpaymentPolicy(ctx context.Context, arg InsertInvoiceOverpaymentPolicyParams) error {
+	_, err := q.db.ExecContext(ctx, insertInvoiceOverpaymentPolicy, arg.InvoiceID, arg.Mode, arg.MaxPercent)
+	return err
+}
+
 const nextInvoiceSettleIndex = `-- name: NextInvoiceSettleIndex :one
 UPDATE invoice_sequences SET current_value = current_value + 1
 WHERE name = 'settle_index'
@@ -0,0 +1,13 @@
+-- invoice_overpayment_policies stores the overpayment policy of invoices that
+-- don't use the node wide policy.
+CREATE TABLE IF NOT EXISTS invoice_overpayment_policies (
+    -- invoice_id is the reference to the invoice the policy applies to.
+    invoice_id BIGINT PRIMARY KEY REFERENCES invoices(id) ON DELETE CASCADE,
+
+    -- mode is the way overpayments of the invoice are handled.
+    mode SMALLINT NOT NULL,
+
+    -- max_percent is the maximum overpayment that is accepted, as a
+    -- percentage of the invoice amount.
+    max_percent BIGINT NOT NULL
+);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS tower_state_updates_hint_idx;
+DROP TABLE IF EXISTS tower_state_updates;
+DROP TABLE IF EXISTS tower_sessions;
+DROP TABLE IF EXISTS tower_lookout_tip;
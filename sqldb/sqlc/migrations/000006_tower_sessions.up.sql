@@ -0,0 +1,83 @@
+-- tower_sessions stores the sessions negotiated with the clients of the
+-- watchtower server.
+CREATE TABLE IF NOT EXISTS tower_sessions (
+    -- The id of the session.
+    id BIGINT PRIMARY KEY,
+
+    -- session_id is the 33-byte session id chosen by the client. The unique
+    -- constraint also indexes the sessions by it.
+    session_id BLOB NOT NULL UNIQUE,
+
+    -- blob_type is the blob format that must be used by all updates sent
+    -- under this session.
+    blob_type INTEGER NOT NULL,
+
+    -- max_updates is the maximum number of updates the tower will honor for
+    -- this session.
+    max_updates INTEGER NOT NULL,
+
+    -- reward_base is the fixed reward of the tower in satoshis.
+    reward_base BIGINT NOT NULL,
+
+    -- reward_rate is the proportional reward of the tower, expressed in
+    -- millionths of the total balance.
+    reward_rate BIGINT NOT NULL,
+
+    -- sweep_fee_rate is the fee rate in sat/kw that must be used by the
+    -- justice transactions of this session.
+    sweep_fee_rate BIGINT NOT NULL,
+
+    -- last_applied is the sequence number of the last accepted update.
+    last_applied INTEGER NOT NULL,
+
+    -- client_last_applied is the last last-applied value echoed back by the
+    -- client.
+    client_last_applied INTEGER NOT NULL,
+
+    -- reward_address is the address the tower's reward is paid to, if any.
+    reward_address BLOB
+);
+
+-- tower_state_updates stores the encrypted state updates sent by the clients
+-- of the watchtower server.
+CREATE TABLE IF NOT EXISTS tower_state_updates (
+    -- The id of the state update.
+    id BIGINT PRIMARY KEY,
+
+    -- session_id is the reference to the session the update was sent for.
+    session_id BIGINT NOT NULL REFERENCES tower_sessions(id) ON DELETE CASCADE,
+
+    -- hint is the 16-byte breach hint of the revoked commitment transaction.
+    hint BLOB NOT NULL,
+
+    -- seq_num is the sequence number of the update within its session.
+    seq_num INTEGER NOT NULL,
+
+    -- last_applied is the last-applied value echoed by the client in the
+    -- update.
+    last_applied INTEGER NOT NULL,
+
+    -- encrypted_blob is the encrypted justice kit of the update.
+    encrypted_blob BLOB NOT NULL,
+
+    -- A session only keeps a single update for each breach hint.
+    UNIQUE (session_id, hint)
+);
+
+-- The lookout queries state updates by their breach hint for every block, so
+-- we index them.
+CREATE INDEX IF NOT EXISTS tower_state_updates_hint_idx
+ON tower_state_updates(hint);
+
+-- tower_lookout_tip stores the last block processed by the lookout. It holds
+-- at most a single row.
+CREATE TABLE IF NOT EXISTS tower_lookout_tip (
+    -- id is always 0.
+    id INTEGER PRIMARY KEY CHECK (id = 0),
+
+    -- block_hash is the hash of the last processed block.
+    block_hash BLOB NOT NULL,
+
+    -- block_height is the height of the last processed block.
+    block_height INTEGER NOT NULL
+);
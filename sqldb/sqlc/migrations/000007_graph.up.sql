@@ -0,0 +1,208 @@
+-- graph_nodes stores the nodes of the channel graph. Nodes that we only know
+-- from a channel announcement are stored as shell nodes that only have a
+-- public key.
+CREATE TABLE IF NOT EXISTS graph_nodes (
+    -- The id of the node.
+    id BIGINT PRIMARY KEY,
+
+    -- pub_key is the 33-byte compressed public key of the node. The unique
+    -- constraint also indexes the nodes by it.
+    pub_key BLOB NOT NULL UNIQUE,
+
+    -- has_announcement is true if we received a node announcement for the
+    -- node. All the fields below except last_update are only set if it is.
+    has_announcement BOOLEAN NOT NULL,
+
+    -- last_update is the unix timestamp of the node's last announcement.
+    last_update BIGINT NOT NULL,
+
+    -- alias is the alias of the node.
+    alias TEXT,
+
+    -- color is the 3-byte RGB color of the node.
+    color BLOB,
+
+    -- features is the serialized feature vector of the node.
+    features BLOB,
+
+    -- addresses is the serialized list of addresses of the node.
+    addresses BLOB,
+
+    -- signature is the signature of the node's last announcement.
+    signature BLOB,
+
+    -- extra_opaque_data holds the unknown fields of the node's last
+    -- announcement.
+    extra_opaque_data BLOB
+);
+
+-- Node announcements are synced by their timestamp, so we index it.
+CREATE INDEX IF NOT EXISTS graph_nodes_last_update_idx
+ON graph_nodes(last_update);
+
+-- graph_source_node stores the public key of our own node. It holds at most a
+-- single row.
+CREATE TABLE IF NOT EXISTS graph_source_node (
+    -- id is always 0.
+    id INTEGER PRIMARY KEY CHECK (id = 0),
+
+    -- pub_key is the public key of our own node.
+    pub_key BLOB NOT NULL
+);
+
+-- graph_channels stores the channels of the channel graph.
+CREATE TABLE IF NOT EXISTS graph_channels (
+    -- The id of the channel.
+    id BIGINT PRIMARY KEY,
+
+    -- scid is the 8-byte big-endian short channel id of the channel, so that
+    -- ordering by it orders the channels by their block height. The unique
+    -- constraint also indexes the channels by it.
+    scid BLOB NOT NULL UNIQUE,
+
+    -- chain_hash is the hash of the genesis block of the channel's chain.
+    chain_hash BLOB NOT NULL,
+
+    -- node_key_1 is the public key of the first node of the channel.
+    node_key_1 BLOB NOT NULL,
+
+    -- node_key_2 is the public key of the second node of the channel.
+    node_key_2 BLOB NOT NULL,
+
+    -- bitcoin_key_1 is the funding key of the first node.
+    bitcoin_key_1 BLOB NOT NULL,
+
+    -- bitcoin_key_2 is the funding key of the second node.
+    bitcoin_key_2 BLOB NOT NULL,
+
+    -- features is the serialized feature vector of the channel.
+    features BLOB,
+
+    -- node_sig_1 is the signature of the first node's key, if the channel
+    -- has an authentication proof. The other signatures below are set if and
+    -- only if this one is.
+    node_sig_1 BLOB,
+
+    -- node_sig_2 is the signature of the second node's key.
+    node_sig_2 BLOB,
+
+    -- bitcoin_sig_1 is the signature of the first node's funding key.
+    bitcoin_sig_1 BLOB,
+
+    -- bitcoin_sig_2 is the signature of the second node's funding key.
+    bitcoin_sig_2 BLOB,
+
+    -- outpoint is the funding outpoint of the channel in the txid:index
+    -- format.
+    outpoint TEXT NOT NULL,
+
+    -- capacity is the capacity of the channel in satoshis.
+    capacity BIGINT NOT NULL,
+
+    -- tapscript_root is the 32-byte tapscript root of the funding output of
+    -- a taproot channel that commits to one.
+    tapscript_root BLOB,
+
+    -- extra_opaque_data holds the unknown fields of the channel
+    -- announcement.
+    extra_opaque_data BLOB
+);
+
+-- The channels of a node are looked up by the node's public key, so we index
+-- both node keys.
+CREATE INDEX IF NOT EXISTS graph_channels_node_key_1_idx
+ON graph_channels(node_key_1);
+
+CREATE INDEX IF NOT EXISTS graph_channels_node_key_2_idx
+ON graph_channels(node_key_2);
+
+-- Closed channels are looked up by their funding outpoint when the graph is
+-- pruned, so we index it.
+CREATE INDEX IF NOT EXISTS graph_channels_outpoint_idx
+ON graph_channels(outpoint);
+
+-- graph_channel_policies stores the routing policies of both directions of
+-- the channels of the channel graph.
+CREATE TABLE IF NOT EXISTS graph_channel_policies (
+    -- The id of the policy.
+    id BIGINT PRIMARY KEY,
+
+    -- channel_id is the reference to the channel of the policy.
+    channel_id BIGINT NOT NULL REFERENCES graph_channels(id) ON DELETE CASCADE,
+
+    -- direction is 0 for the policy of the first node and 1 for the policy
+    -- of the second node of the channel.
+    direction INTEGER NOT NULL,
+
+    -- last_update is the unix timestamp of the policy's channel update.
+    last_update BIGINT NOT NULL,
+
+    -- message_flags are the message flags of the channel update.
+    message_flags INTEGER NOT NULL,
+
+    -- channel_flags are the channel flags of the channel update.
+    channel_flags INTEGER NOT NULL,
+
+    -- time_lock_delta is the CLTV delta of the policy.
+    time_lock_delta INTEGER NOT NULL,
+
+    -- min_htlc_msat is the smallest HTLC the policy accepts.
+    min_htlc_msat BIGINT NOT NULL,
+
+    -- max_htlc_msat is the largest HTLC the policy accepts.
+    max_htlc_msat BIGINT NOT NULL,
+
+    -- fee_base_msat is the base fee of the policy.
+    fee_base_msat BIGINT NOT NULL,
+
+    -- fee_rate_ppm is the proportional fee of the policy in millionths.
+    fee_rate_ppm BIGINT NOT NULL,
+
+    -- signature is the signature of the channel update.
+    signature BLOB,
+
+    -- extra_opaque_data holds the unknown fields of the channel update.
+    extra_opaque_data BLOB,
+
+    -- A channel has a single policy for each direction.
+    UNIQUE (channel_id, direction)
+);
+
+-- Channel updates are synced by their timestamp, so we index it.
+CREATE INDEX IF NOT EXISTS graph_channel_policies_last_update_idx
+ON graph_channel_policies(last_update);
+
+-- graph_zombie_channels stores the channels that were pruned from the graph
+-- and that we don't accept again unless they are resurrected by a fresh
+-- update.
+CREATE TABLE IF NOT EXISTS graph_zombie_channels (
+    -- The id of the zombie channel.
+    id BIGINT PRIMARY KEY,
+
+    -- scid is the 8-byte big-endian short channel id of the channel.
+    scid BLOB NOT NULL UNIQUE,
+
+    -- node_key_1 is the public key of the first node allowed to resurrect
+    -- the channel. It's all zeroes if the node isn't allowed to.
+    node_key_1 BLOB NOT NULL,
+
+    -- node_key_2 is the public key of the second node allowed to resurrect
+    -- the channel. It's all zeroes if the node isn't allowed to.
+    node_key_2 BLOB NOT NULL
+);
+
+-- graph_closed_scids stores the short channel ids of the channels that are
+-- known to be closed.
+CREATE TABLE IF NOT EXISTS graph_closed_scids (
+    -- scid is the 8-byte big-endian short channel id of the channel.
+    scid BLOB PRIMARY KEY
+);
+
+-- graph_prune_log stores the blocks that the graph was pruned with.
+CREATE TABLE IF NOT EXISTS graph_prune_log (
+    -- block_height is the height of the block.
+    block_height INTEGER PRIMARY KEY,
+
+    -- block_hash is the hash of the block.
+    block_hash BLOB NOT NULL
+);
@@ -28,6 +28,75 @@ type AmpSubInvoiceHtlc struct {
 	Preimage   []byte
 }
 
+type GraphChannel struct {
+	ID              int64
+	Scid            []byte
+	ChainHash       []byte
+	NodeKey1        []byte
+	NodeKey2        []byte
+	BitcoinKey1     []byte
+	BitcoinKey2     []byte
+	Features        []byte
+	NodeSig1        []byte
+	NodeSig2        []byte
+	BitcoinSig1     []byte
+	BitcoinSig2     []byte
+	Outpoint        string
+	Capacity        int64
+	TapscriptRoot   []byte
+	ExtraOpaqueData []byte
+}
+
+type GraphChannelPolicy struct {
+	ID              int64
+	ChannelID       int64
+	Direction       int32
+	LastUpdate      int64
+	MessageFlags    int32
+	ChannelFlags    int32
+	TimeLockDelta   int32
+	MinHtlcMsat     int64
+	MaxHtlcMsat     int64
+	FeeBaseMsat     int64
+	FeeRatePpm      int64
+	Signature       []byte
+	ExtraOpaqueData []byte
+}
+
+type GraphClosedScid struct {
+	Scid []byte
+}
+
+type GraphNode struct {
+	ID              int64
+	PubKey          []byte
+	HasAnnouncement bool
+	LastUpdate      int64
+	Alias           sql.NullString
+	Color           []byte
+	Features        []byte
+	Addresses       []byte
+	Signature       []byte
+	ExtraOpaqueData []byte
+}
+
+type GraphPruneLog struct {
+	BlockHeight int32
+	BlockHash   []byte
+}
+
+type GraphSourceNode struct {
+	ID     int32
+	PubKey []byte
+}
+
+type GraphZombieChannel struct {
+	ID       int64
+	Scid     []byte
+	NodeKey1 []byte
+	NodeKey2 []byte
+}
+
 type Invoice struct {
 	ID                 int64
 	Hash               []byte
@@ -87,7 +156,41 @@ type InvoiceHtlcCustomRecord struct {
 	HtlcID int64
 }
 
+type InvoiceOverpaymentPolicy struct {
+	InvoiceID  int64
+	Mode       int16
+	MaxPercent int64
+}
+
 type InvoiceSequence struct {
 	Name         string
 	CurrentValue int64
 }
+
+type TowerLookoutTip struct {
+	ID          int32
+	BlockHash   []byte
+	BlockHeight int32
+}
+
+type TowerSession struct {
+	ID                int64
+	SessionID         []byte
+	BlobType          int32
+	MaxUpdates        int32
+	RewardBase        int64
+	RewardRate        int64
+	SweepFeeRate      int64
+	LastApplied       int32
+	ClientLastApplied int32
+	RewardAddress     []byte
+}
+
+type TowerStateUpdate struct {
+	ID            int64
+	SessionID     int64
+	Hint          []byte
+	SeqNum        int32
+	LastApplied   int32
+	EncryptedBlob []byte
+}
@@ -10,13 +10,28 @@ import (
 )
 
 type Querier interface {
+	CountGraphNodes(ctx context.Context) (int64, error)
+	CountGraphZombieChannels(ctx context.Context) (int64, error)
 	DeleteCanceledInvoices(ctx context.Context) (sql.Result, error)
+	DeleteGraphChannel(ctx context.Context, scid []byte) (sql.Result, error)
+	DeleteGraphNode(ctx context.Context, pubKey []byte) (sql.Result, error)
+	DeleteGraphPruneLogEntriesFrom(ctx context.Context, blockHeight int32) error
+	DeleteGraphZombieChannel(ctx context.Context, scid []byte) (sql.Result, error)
 	DeleteInvoice(ctx context.Context, arg DeleteInvoiceParams) (sql.Result, error)
+	DeleteTowerSession(ctx context.Context, sessionID []byte) (sql.Result, error)
 	FetchAMPSubInvoiceHTLCs(ctx context.Context, arg FetchAMPSubInvoiceHTLCsParams) ([]FetchAMPSubInvoiceHTLCsRow, error)
 	FetchAMPSubInvoices(ctx context.Context, arg FetchAMPSubInvoicesParams) ([]AmpSubInvoice, error)
 	FetchSettledAMPSubInvoices(ctx context.Context, arg FetchSettledAMPSubInvoicesParams) ([]FetchSettledAMPSubInvoicesRow, error)
+	FetchTowerStateUpdatesByHint(ctx context.Context, hint []byte) ([]FetchTowerStateUpdatesByHintRow, error)
 	FilterInvoices(ctx context.Context, arg FilterInvoicesParams) ([]Invoice, error)
 	GetAMPInvoiceID(ctx context.Context, setID []byte) (int64, error)
+	GetGraphChannel(ctx context.Context, scid []byte) (GraphChannel, error)
+	GetGraphChannelByOutpoint(ctx context.Context, outpoint string) (GraphChannel, error)
+	GetGraphHighestSCID(ctx context.Context) ([]byte, error)
+	GetGraphNode(ctx context.Context, pubKey []byte) (GraphNode, error)
+	GetGraphPruneTip(ctx context.Context) (GraphPruneLog, error)
+	GetGraphSourceNode(ctx context.Context) ([]byte, error)
+	GetGraphZombieChannel(ctx context.Context, scid []byte) (GraphZombieChannel, error)
 	// This method may return more than one invoice if filter using multiple fields
 	// from different invoices. It is the caller's responsibility to ensure that
 	// we bubble up an error in those cases.
@@ -25,11 +40,31 @@ type Querier interface {
 	GetInvoiceFeatures(ctx context.Context, invoiceID int64) ([]InvoiceFeature, error)
 	GetInvoiceHTLCCustomRecords(ctx context.Context, invoiceID int64) ([]GetInvoiceHTLCCustomRecordsRow, error)
 	GetInvoiceHTLCs(ctx context.Context, invoiceID int64) ([]InvoiceHtlc, error)
+	GetInvoiceOverpaymentPolicy(ctx context.Context, invoiceID int64) (InvoiceOverpaymentPolicy, error)
+	GetTowerLookoutTip(ctx context.Context) (GetTowerLookoutTipRow, error)
+	GetTowerSession(ctx context.Context, sessionID []byte) (TowerSession, error)
 	InsertAMPSubInvoiceHTLC(ctx context.Context, arg InsertAMPSubInvoiceHTLCParams) error
+	InsertGraphChannel(ctx context.Context, arg InsertGraphChannelParams) (int64, error)
+	InsertGraphClosedSCID(ctx context.Context, scid []byte) error
+	InsertGraphShellNode(ctx context.Context, pubKey []byte) error
 	InsertInvoice(ctx context.Context, arg InsertInvoiceParams) (int64, error)
 	InsertInvoiceFeature(ctx context.Context, arg InsertInvoiceFeatureParams) error
 	InsertInvoiceHTLC(ctx context.Context, arg InsertInvoiceHTLCParams) (int64, error)
 	InsertInvoiceHTLCCustomRecord(ctx context.Context, arg InsertInvoiceHTLCCustomRecordParams) error
+	InsertInvoiceOverpaymentPolicy(ctx context.Context, arg InsertInvoiceOverpaymentPolicyParams) error
+	IsGraphClosedSCID(ctx context.Context, scid []byte) (bool, error)
+	ListGraphChannelPolicies(ctx context.Context, arg ListGraphChannelPoliciesParams) ([]GraphChannelPolicy, error)
+	ListGraphChannelPoliciesInHorizon(ctx context.Context, arg ListGraphChannelPoliciesInHorizonParams) ([]GraphChannelPolicy, error)
+	ListGraphChannelPoliciesInSCIDRange(ctx context.Context, arg ListGraphChannelPoliciesInSCIDRangeParams) ([]GraphChannelPolicy, error)
+	ListGraphChannels(ctx context.Context, arg ListGraphChannelsParams) ([]GraphChannel, error)
+	ListGraphChannelsInHorizon(ctx context.Context, arg ListGraphChannelsInHorizonParams) ([]GraphChannel, error)
+	ListGraphChannelsInSCIDRange(ctx context.Context, arg ListGraphChannelsInSCIDRangeParams) ([]GraphChannel, error)
+	ListGraphDisabledChannels(ctx context.Context) ([][]byte, error)
+	ListGraphNodeChannelPolicies(ctx context.Context, nodeKey []byte) ([]GraphChannelPolicy, error)
+	ListGraphNodeChannels(ctx context.Context, nodeKey []byte) ([]GraphChannel, error)
+	ListGraphNodes(ctx context.Context, arg ListGraphNodesParams) ([]GraphNode, error)
+	ListGraphNodesInHorizon(ctx context.Context, arg ListGraphNodesInHorizonParams) ([]GraphNode, error)
+	ListGraphNodesWithoutChannels(ctx context.Context) ([][]byte, error)
 	NextInvoiceSettleIndex(ctx context.Context) (int64, error)
 	OnAMPSubInvoiceCanceled(ctx context.Context, arg OnAMPSubInvoiceCanceledParams) error
 	OnAMPSubInvoiceCreated(ctx context.Context, arg OnAMPSubInvoiceCreatedParams) error
@@ -39,11 +74,21 @@ type Querier interface {
 	OnInvoiceSettled(ctx context.Context, arg OnInvoiceSettledParams) error
 	UpdateAMPSubInvoiceHTLCPreimage(ctx context.Context, arg UpdateAMPSubInvoiceHTLCPreimageParams) (sql.Result, error)
 	UpdateAMPSubInvoiceState(ctx context.Context, arg UpdateAMPSubInvoiceStateParams) error
+	UpdateGraphChannel(ctx context.Context, arg UpdateGraphChannelParams) (sql.Result, error)
 	UpdateInvoiceAmountPaid(ctx context.Context, arg UpdateInvoiceAmountPaidParams) (sql.Result, error)
 	UpdateInvoiceHTLC(ctx context.Context, arg UpdateInvoiceHTLCParams) error
 	UpdateInvoiceHTLCs(ctx context.Context, arg UpdateInvoiceHTLCsParams) error
 	UpdateInvoiceState(ctx context.Context, arg UpdateInvoiceStateParams) (sql.Result, error)
+	UpdateTowerSessionLastApplied(ctx context.Context, arg UpdateTowerSessionLastAppliedParams) error
 	UpsertAMPSubInvoice(ctx context.Context, arg UpsertAMPSubInvoiceParams) (sql.Result, error)
+	UpsertGraphChannelPolicy(ctx context.Context, arg UpsertGraphChannelPolicyParams) error
+	UpsertGraphNode(ctx context.Context, arg UpsertGraphNodeParams) (int64, error)
+	UpsertGraphPruneLogEntry(ctx context.Context, arg UpsertGraphPruneLogEntryParams) error
+	UpsertGraphSourceNode(ctx context.Context, pubKey []byte) error
+	UpsertGraphZombieChannel(ctx context.Context, arg UpsertGraphZombieChannelParams) error
+	UpsertTowerLookoutTip(ctx context.Context, arg UpsertTowerLookoutTipParams) error
+	UpsertTowerSession(ctx context.Context, arg UpsertTowerSessionParams) (int64, error)
+	UpsertTowerStateUpdate(ctx context.Context, arg UpsertTowerStateUpdateParams) error
 }
 
 var _ Querier = (*Queries)(nil)
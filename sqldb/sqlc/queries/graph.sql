@@ -0,0 +1,259 @@
+-- name: UpsertGraphNode :one
+INSERT INTO graph_nodes (
+    pub_key, has_announcement, last_update, alias, color, features,
+    addresses, signature, extra_opaque_data
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+) ON CONFLICT (pub_key) DO UPDATE SET
+    has_announcement = EXCLUDED.has_announcement,
+    last_update = EXCLUDED.last_update,
+    alias = EXCLUDED.alias,
+    color = EXCLUDED.color,
+    features = EXCLUDED.features,
+    addresses = EXCLUDED.addresses,
+    signature = EXCLUDED.signature,
+    extra_opaque_data = EXCLUDED.extra_opaque_data
+RETURNING id;
+
+-- name: InsertGraphShellNode :exec
+INSERT INTO graph_nodes (
+    pub_key, has_announcement, last_update
+) VALUES (
+    $1, FALSE, 0
+) ON CONFLICT (pub_key) DO NOTHING;
+
+-- name: GetGraphNode :one
+SELECT *
+FROM graph_nodes
+WHERE pub_key = $1;
+
+-- name: ListGraphNodes :many
+SELECT *
+FROM graph_nodes
+WHERE id > @after_id
+ORDER BY id
+LIMIT @num_limit;
+
+-- name: ListGraphNodesInHorizon :many
+SELECT *
+FROM graph_nodes
+WHERE has_announcement = TRUE
+    AND last_update >= @start_time
+    AND last_update <= @end_time
+ORDER BY last_update, id;
+
+-- name: ListGraphNodesWithoutChannels :many
+SELECT n.pub_key
+FROM graph_nodes n
+WHERE NOT EXISTS (
+    SELECT 1
+    FROM graph_channels c
+    WHERE c.node_key_1 = n.pub_key OR c.node_key_2 = n.pub_key
+);
+
+-- name: CountGraphNodes :one
+SELECT COUNT(*)
+FROM graph_nodes;
+
+-- name: DeleteGraphNode :execresult
+DELETE FROM graph_nodes
+WHERE pub_key = $1;
+
+-- name: UpsertGraphSourceNode :exec
+INSERT INTO graph_source_node (
+    id, pub_key
+) VALUES (
+    0, $1
+) ON CONFLICT (id) DO UPDATE SET
+    pub_key = EXCLUDED.pub_key;
+
+-- name: GetGraphSourceNode :one
+SELECT pub_key
+FROM graph_source_node
+WHERE id = 0;
+
+-- name: InsertGraphChannel :one
+INSERT INTO graph_channels (
+    scid, chain_hash, node_key_1, node_key_2, bitcoin_key_1, bitcoin_key_2,
+    features, node_sig_1, node_sig_2, bitcoin_sig_1, bitcoin_sig_2, outpoint,
+    capacity, tapscript_root, extra_opaque_data
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+)
+RETURNING id;
+
+-- name: UpdateGraphChannel :execresult
+UPDATE graph_channels
+SET chain_hash = $1, node_key_1 = $2, node_key_2 = $3, bitcoin_key_1 = $4,
+    bitcoin_key_2 = $5, features = $6, node_sig_1 = $7, node_sig_2 = $8,
+    bitcoin_sig_1 = $9, bitcoin_sig_2 = $10, outpoint = $11, capacity = $12,
+    tapscript_root = $13, extra_opaque_data = $14
+WHERE scid = $15;
+
+-- name: GetGraphChannel :one
+SELECT *
+FROM graph_channels
+WHERE scid = $1;
+
+-- name: GetGraphChannelByOutpoint :one
+SELECT *
+FROM graph_channels
+WHERE outpoint = $1
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: ListGraphChannels :many
+SELECT *
+FROM graph_channels
+WHERE id > @after_id
+ORDER BY id
+LIMIT @num_limit;
+
+-- name: ListGraphNodeChannels :many
+SELECT *
+FROM graph_channels
+WHERE node_key_1 = @node_key OR node_key_2 = @node_key
+ORDER BY scid;
+
+-- name: ListGraphChannelsInSCIDRange :many
+SELECT *
+FROM graph_channels
+WHERE scid >= @start_scid AND scid <= @end_scid
+ORDER BY scid;
+
+-- name: ListGraphChannelsInHorizon :many
+SELECT c.*
+FROM graph_channels c
+WHERE EXISTS (
+    SELECT 1
+    FROM graph_channel_policies p
+    WHERE p.channel_id = c.id
+        AND p.last_update >= @start_time
+        AND p.last_update <= @end_time
+)
+ORDER BY c.scid;
+
+-- name: ListGraphDisabledChannels :many
+SELECT c.scid
+FROM graph_channels c
+JOIN graph_channel_policies p1
+    ON p1.channel_id = c.id AND p1.direction = 0
+JOIN graph_channel_policies p2
+    ON p2.channel_id = c.id AND p2.direction = 1
+WHERE (p1.channel_flags & 2) != 0 AND (p2.channel_flags & 2) != 0
+ORDER BY c.scid;
+
+-- name: GetGraphHighestSCID :one
+SELECT scid
+FROM graph_channels
+ORDER BY scid DESC
+LIMIT 1;
+
+-- name: DeleteGraphChannel :execresult
+DELETE FROM graph_channels
+WHERE scid = $1;
+
+-- name: UpsertGraphChannelPolicy :exec
+INSERT INTO graph_channel_policies (
+    channel_id, direction, last_update, message_flags, channel_flags,
+    time_lock_delta, min_htlc_msat, max_htlc_msat, fee_base_msat,
+    fee_rate_ppm, signature, extra_opaque_data
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+) ON CONFLICT (channel_id, direction) DO UPDATE SET
+    last_update = EXCLUDED.last_update,
+    message_flags = EXCLUDED.message_flags,
+    channel_flags = EXCLUDED.channel_flags,
+    time_lock_delta = EXCLUDED.time_lock_delta,
+    min_htlc_msat = EXCLUDED.min_htlc_msat,
+    max_htlc_msat = EXCLUDED.max_htlc_msat,
+    fee_base_msat = EXCLUDED.fee_base_msat,
+    fee_rate_ppm = EXCLUDED.fee_rate_ppm,
+    signature = EXCLUDED.signature,
+    extra_opaque_data = EXCLUDED.extra_opaque_data;
+
+-- name: ListGraphChannelPolicies :many
+SELECT *
+FROM graph_channel_policies
+WHERE channel_id >= @start_id AND channel_id <= @end_id
+ORDER BY channel_id, direction;
+
+-- name: ListGraphChannelPoliciesInHorizon :many
+SELECT p.*
+FROM graph_channel_policies p
+WHERE EXISTS (
+    SELECT 1
+    FROM graph_channel_policies u
+    WHERE u.channel_id = p.channel_id
+        AND u.last_update >= @start_time
+        AND u.last_update <= @end_time
+)
+ORDER BY p.channel_id, p.direction;
+
+-- name: ListGraphChannelPoliciesInSCIDRange :many
+SELECT p.*
+FROM graph_channel_policies p
+JOIN graph_channels c ON p.channel_id = c.id
+WHERE c.scid >= @start_scid AND c.scid <= @end_scid
+ORDER BY p.channel_id, p.direction;
+
+-- name: ListGraphNodeChannelPolicies :many
+SELECT p.*
+FROM graph_channel_policies p
+JOIN graph_channels c ON p.channel_id = c.id
+WHERE c.node_key_1 = @node_key OR c.node_key_2 = @node_key
+ORDER BY p.channel_id, p.direction;
+
+-- name: UpsertGraphZombieChannel :exec
+INSERT INTO graph_zombie_channels (
+    scid, node_key_1, node_key_2
+) VALUES (
+    $1, $2, $3
+) ON CONFLICT (scid) DO UPDATE SET
+    node_key_1 = EXCLUDED.node_key_1,
+    node_key_2 = EXCLUDED.node_key_2;
+
+-- name: GetGraphZombieChannel :one
+SELECT *
+FROM graph_zombie_channels
+WHERE scid = $1;
+
+-- name: DeleteGraphZombieChannel :execresult
+DELETE FROM graph_zombie_channels
+WHERE scid = $1;
+
+-- name: CountGraphZombieChannels :one
+SELECT COUNT(*)
+FROM graph_zombie_channels;
+
+-- name: InsertGraphClosedSCID :exec
+INSERT INTO graph_closed_scids (
+    scid
+) VALUES (
+    $1
+) ON CONFLICT (scid) DO NOTHING;
+
+-- name: IsGraphClosedSCID :one
+SELECT EXISTS (
+    SELECT 1
+    FROM graph_closed_scids
+    WHERE scid = $1
+);
+
+-- name: UpsertGraphPruneLogEntry :exec
+INSERT INTO graph_prune_log (
+    block_height, block_hash
+) VALUES (
+    $1, $2
+) ON CONFLICT (block_height) DO UPDATE SET
+    block_hash = EXCLUDED.block_hash;
+
+-- name: GetGraphPruneTip :one
+SELECT *
+FROM graph_prune_log
+ORDER BY block_height DESC
+LIMIT 1;
+
+-- name: DeleteGraphPruneLogEntriesFrom :exec
+DELETE FROM graph_prune_log
+WHERE block_height >= $1;
@@ -19,6 +19,18 @@ SELECT *
 FROM invoice_features
 WHERE invoice_id = $1;
 
+-- name: InsertInvoiceOverpaymentPolicy :exec
+INSERT INTO invoice_overpayment_policies (
+    invoice_id, mode, max_percent
+) VALUES (
+    $1, $2, $3
+);
+
+-- name: GetInvoiceOverpaymentPolicy :one
+SELECT *
+FROM invoice_overpayment_policies
+WHERE invoice_id = $1;
+
 -- This method may return more than one invoice if filter using multiple fields
 -- from different invoices. It is the caller's responsibility to ensure that 
 -- we bubble up an error in those cases.
@@ -0,0 +1,64 @@
+-- name: UpsertTowerSession :one
+INSERT INTO tower_sessions (
+    session_id, blob_type, max_updates, reward_base, reward_rate,
+    sweep_fee_rate, last_applied, client_last_applied, reward_address
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+) ON CONFLICT (session_id) DO UPDATE SET
+    blob_type = EXCLUDED.blob_type,
+    max_updates = EXCLUDED.max_updates,
+    reward_base = EXCLUDED.reward_base,
+    reward_rate = EXCLUDED.reward_rate,
+    sweep_fee_rate = EXCLUDED.sweep_fee_rate,
+    last_applied = EXCLUDED.last_applied,
+    client_last_applied = EXCLUDED.client_last_applied,
+    reward_address = EXCLUDED.reward_address
+RETURNING id;
+
+-- name: GetTowerSession :one
+SELECT *
+FROM tower_sessions
+WHERE session_id = $1;
+
+-- name: UpdateTowerSessionLastApplied :exec
+UPDATE tower_sessions
+SET last_applied = $1, client_last_applied = $2
+WHERE id = $3;
+
+-- name: DeleteTowerSession :execresult
+DELETE FROM tower_sessions
+WHERE session_id = $1;
+
+-- name: UpsertTowerStateUpdate :exec
+INSERT INTO tower_state_updates (
+    session_id, hint, seq_num, last_applied, encrypted_blob
+) VALUES (
+    $1, $2, $3, $4, $5
+) ON CONFLICT (session_id, hint) DO UPDATE SET
+    seq_num = EXCLUDED.seq_num,
+    last_applied = EXCLUDED.last_applied,
+    encrypted_blob = EXCLUDED.encrypted_blob;
+
+-- name: FetchTowerStateUpdatesByHint :many
+SELECT
+    s.session_id, s.blob_type, s.max_updates, s.reward_base, s.reward_rate,
+    s.sweep_fee_rate, s.last_applied, s.client_last_applied,
+    s.reward_address, u.seq_num, u.encrypted_blob
+FROM tower_state_updates u
+JOIN tower_sessions s ON u.session_id = s.id
+WHERE u.hint = $1
+ORDER BY s.session_id;
+
+-- name: UpsertTowerLookoutTip :exec
+INSERT INTO tower_lookout_tip (
+    id, block_hash, block_height
+) VALUES (
+    0, $1, $2
+) ON CONFLICT (id) DO UPDATE SET
+    block_hash = EXCLUDED.block_hash,
+    block_height = EXCLUDED.block_height;
+
+-- name: GetTowerLookoutTip :one
+SELECT block_hash, block_height
+FROM tower_lookout_tip
+WHERE id = 0;
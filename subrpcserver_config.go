@@ -5,6 +5,7 @@ import (
 	"net"
 	"reflect"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btclog"
 	"github.com/lightningnetwork/lnd/aliasmgr"
@@ -12,6 +13,7 @@ import (
 	"github.com/lightningnetwork/lnd/chainreg"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/graph"
 	"github.com/lightningnetwork/lnd/htlcswitch"
 	"github.com/lightningnetwork/lnd/invoices"
 	"github.com/lightningnetwork/lnd/lncfg"
@@ -26,6 +28,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnrpc/walletrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/watchtowerrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/wtclientrpc"
+	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/macaroons"
 	"github.com/lightningnetwork/lnd/netann"
@@ -110,9 +113,10 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 	htlcSwitch *htlcswitch.Switch,
 	activeNetParams *chaincfg.Params,
 	chanRouter *routing.ChannelRouter,
+	graphBuilder *graph.Builder,
 	routerBackend *routerrpc.RouterBackend,
 	nodeSigner *netann.NodeSigner,
-	graphDB *channeldb.ChannelGraph,
+	graphDB channeldb.GraphStore,
 	chanStateDB *channeldb.ChannelStateDB,
 	sweeper *sweep.UtxoSweeper,
 	tower *watchtower.Standalone,
@@ -126,7 +130,9 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 	parseAddr func(addr string) (net.Addr, error),
 	rpcLogger btclog.Logger, aliasMgr *aliasmgr.Manager,
 	auxDataParser fn.Option[AuxDataParser],
-	invoiceHtlcModifier *invoices.HtlcModificationInterceptor) error {
+	invoiceHtlcModifier *invoices.HtlcModificationInterceptor,
+	offersMgr *invoices.OffersManager,
+	invoiceTemplates *invoicesrpc.TemplateManager) error {
 
 	// First, we'll use reflect to obtain a version of the config struct
 	// that allows us to programmatically inspect its fields.
@@ -209,6 +215,9 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 			subCfgValue.FieldByName("ChanStateDB").Set(
 				reflect.ValueOf(chanStateDB),
 			)
+			subCfgValue.FieldByName("SignPolicy").Set(
+				reflect.ValueOf(cc.SignPolicy),
+			)
 
 		case *autopilotrpc.Config:
 			subCfgValue := extractReflectValue(subCfg)
@@ -291,6 +300,25 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 				reflect.ValueOf(parseAuxData),
 			)
 
+			// Unified invoice URIs are mostly scanned by wallets
+			// that don't support taproot yet, so the fallback
+			// address is a native segwit v0 one.
+			newAddress := func() (btcutil.Address, error) {
+				return cc.Wallet.NewAddress(
+					lnwallet.WitnessPubKey, false,
+					lnwallet.DefaultAccountName,
+				)
+			}
+			subCfgValue.FieldByName("NewAddress").Set(
+				reflect.ValueOf(newAddress),
+			)
+			subCfgValue.FieldByName("OffersManager").Set(
+				reflect.ValueOf(offersMgr),
+			)
+			subCfgValue.FieldByName("TemplateManager").Set(
+				reflect.ValueOf(invoiceTemplates),
+			)
+
 		case *neutrinorpc.Config:
 			subCfgValue := extractReflectValue(subCfg)
 
@@ -372,6 +400,8 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 	s.RouterRPC.MacService = macService
 	s.RouterRPC.Router = chanRouter
 	s.RouterRPC.RouterBackend = routerBackend
+	s.RouterRPC.PaymentRetryDB = chanStateDB.GetParentDB()
+	s.RouterRPC.SubscribeTopology = graphBuilder.SubscribeTopology
 
 	return nil
 }
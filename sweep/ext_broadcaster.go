@@ -0,0 +1,418 @@
+package sweep
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/rpcclient"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/lnwallet"
+)
+
+const (
+	// DefaultExtBroadcastTimeout is the default time we wait for an
+	// external broadcaster to accept a transaction.
+	DefaultExtBroadcastTimeout = 30 * time.Second
+
+	// maxExtBroadcastResponseSize is the maximum number of bytes we read
+	// from the response of an HTTP broadcast endpoint.
+	maxExtBroadcastResponseSize = 4096
+)
+
+// errInvalidBitcoindEndpoint is returned if a bitcoind endpoint isn't in the
+// expected format.
+var errInvalidBitcoindEndpoint = errors.New("extbroadcast.bitcoindendpoint " +
+	"must be in the form user:pass@host:port")
+
+// ExtBroadcastConfig houses the configuration of the external services that
+// sweep and force close transactions are additionally submitted to.
+//
+//nolint:lll
+type ExtBroadcastConfig struct {
+	HTTPEndpoints     []string      `long:"httpendpoint" description:"The URL of an HTTP endpoint that accepts a raw hex-encoded transaction as POST body, e.g. an Esplora compatible API (https://mempool.space/api/tx) or a mining pool accelerator. Can be specified multiple times."`
+	BitcoindEndpoints []string      `long:"bitcoindendpoint" description:"An additional bitcoind RPC endpoint in the form user:pass@host:port that transactions are submitted to using sendrawtransaction. Can be specified multiple times."`
+	Timeout           time.Duration `long:"timeout" description:"The time to wait for an external broadcaster to accept a transaction."`
+	DelegateJustice   bool          `long:"delegatejustice" description:"Also hand justice transactions for detected breaches to the external broadcasters, in addition to publishing them locally."`
+}
+
+// Validate checks the external broadcast configuration for invalid values.
+func (c *ExtBroadcastConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	if c.Timeout <= 0 {
+		return fmt.Errorf("extbroadcast.timeout must be positive")
+	}
+
+	for _, endpoint := range c.HTTPEndpoints {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return fmt.Errorf("invalid extbroadcast.httpendpoint "+
+				"%v: %w", endpoint, err)
+		}
+
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("extbroadcast.httpendpoint %v must "+
+				"use http or https", endpoint)
+		}
+	}
+
+	for _, endpoint := range c.BitcoindEndpoints {
+		if _, err := parseBitcoindEndpoint(endpoint); err != nil {
+			return err
+		}
+	}
+
+	if c.DelegateJustice &&
+		len(c.HTTPEndpoints)+len(c.BitcoindEndpoints) == 0 {
+
+		return fmt.Errorf("extbroadcast.delegatejustice requires at " +
+			"least one external endpoint")
+	}
+
+	return nil
+}
+
+// Broadcaster is an external service that transactions can be submitted to
+// in addition to the wallet's own chain backend.
+type Broadcaster interface {
+	// Name returns a unique, human-readable name of the broadcaster that
+	// is used for logging and success tracking.
+	Name() string
+
+	// Broadcast submits the transaction to the external service.
+	Broadcast(ctx context.Context, tx *wire.MsgTx) error
+}
+
+// BroadcastStats tracks the results of the submissions to a single external
+// broadcaster.
+type BroadcastStats struct {
+	// Attempts is the number of transactions submitted to the broadcaster.
+	Attempts uint64
+
+	// Successes is the number of transactions the broadcaster accepted.
+	Successes uint64
+
+	// LastSuccess is the time of the last accepted submission.
+	LastSuccess time.Time
+
+	// LastErr is the error of the last rejected submission, if any.
+	LastErr error
+}
+
+// ExternalPublisher submits transactions to a set of external broadcasters.
+// Submissions are best effort: they happen in the background and never fail
+// the publication through the wallet.
+type ExternalPublisher struct {
+	broadcasters []Broadcaster
+	timeout      time.Duration
+
+	statsMtx sync.Mutex
+	stats    map[string]*BroadcastStats
+
+	wg sync.WaitGroup
+}
+
+// NewExternalPublisher creates a new ExternalPublisher that submits
+// transactions to the given broadcasters.
+func NewExternalPublisher(timeout time.Duration,
+	broadcasters ...Broadcaster) *ExternalPublisher {
+
+	stats := make(map[string]*BroadcastStats, len(broadcasters))
+	for _, b := range broadcasters {
+		stats[b.Name()] = &BroadcastStats{}
+	}
+
+	return &ExternalPublisher{
+		broadcasters: broadcasters,
+		timeout:      timeout,
+		stats:        stats,
+	}
+}
+
+// NewExternalPublisherFromConfig creates an ExternalPublisher for all
+// endpoints of the given config. Nil is returned if no endpoint is
+// configured.
+func NewExternalPublisherFromConfig(
+	cfg *ExtBroadcastConfig) (*ExternalPublisher, error) {
+
+	if cfg == nil ||
+		len(cfg.HTTPEndpoints)+len(cfg.BitcoindEndpoints) == 0 {
+
+		return nil, nil
+	}
+
+	var broadcasters []Broadcaster
+	for _, endpoint := range cfg.HTTPEndpoints {
+		broadcasters = append(
+			broadcasters, NewHTTPBroadcaster(endpoint),
+		)
+	}
+
+	for _, endpoint := range cfg.BitcoindEndpoints {
+		b, err := NewBitcoindBroadcaster(endpoint)
+		if err != nil {
+			return nil, err
+		}
+
+		broadcasters = append(broadcasters, b)
+	}
+
+	return NewExternalPublisher(cfg.Timeout, broadcasters...), nil
+}
+
+// Publish submits the transaction to all external broadcasters in the
+// background.
+func (e *ExternalPublisher) Publish(tx *wire.MsgTx) {
+	txid := tx.TxHash()
+
+	for _, b := range e.broadcasters {
+		e.wg.Add(1)
+		go func(b Broadcaster) {
+			defer e.wg.Done()
+
+			ctx, cancel := context.WithTimeout(
+				context.Background(), e.timeout,
+			)
+			defer cancel()
+
+			err := b.Broadcast(ctx, tx)
+			e.recordResult(b.Name(), err)
+
+			if err != nil {
+				log.Warnf("External broadcaster %v rejected "+
+					"tx %v: %v", b.Name(), txid, err)
+
+				return
+			}
+
+			log.Debugf("External broadcaster %v accepted tx %v",
+				b.Name(), txid)
+		}(b)
+	}
+}
+
+// WrapPublish returns a publish function that publishes the transaction
+// using the given function and additionally submits it to all external
+// broadcasters. The result of the given function is returned unchanged.
+func (e *ExternalPublisher) WrapPublish(
+	publish func(*wire.MsgTx, string) error) func(*wire.MsgTx,
+	string) error {
+
+	return func(tx *wire.MsgTx, label string) error {
+		err := publish(tx, label)
+
+		// If the inputs are already spent there's no point in
+		// submitting the transaction anywhere else. For any other
+		// error, e.g. a fee rate below our backend's mempool minimum,
+		// an external service might still accept it.
+		if !errors.Is(err, lnwallet.ErrDoubleSpend) {
+			e.Publish(tx)
+		}
+
+		return err
+	}
+}
+
+// recordResult updates the stats of the named broadcaster.
+func (e *ExternalPublisher) recordResult(name string, err error) {
+	e.statsMtx.Lock()
+	defer e.statsMtx.Unlock()
+
+	stats := e.stats[name]
+	stats.Attempts++
+
+	if err != nil {
+		stats.LastErr = err
+		return
+	}
+
+	stats.Successes++
+	stats.LastSuccess = time.Now()
+}
+
+// Stats returns a snapshot of the submission results of all external
+// broadcasters, keyed by their name.
+func (e *ExternalPublisher) Stats() map[string]BroadcastStats {
+	e.statsMtx.Lock()
+	defer e.statsMtx.Unlock()
+
+	stats := make(map[string]BroadcastStats, len(e.stats))
+	for name, s := range e.stats {
+		stats[name] = *s
+	}
+
+	return stats
+}
+
+// Stop waits for all pending submissions to finish and stops the
+// broadcasters.
+func (e *ExternalPublisher) Stop() {
+	e.wg.Wait()
+
+	for _, b := range e.broadcasters {
+		if stopper, ok := b.(interface{ Stop() }); ok {
+			stopper.Stop()
+		}
+	}
+}
+
+// HTTPBroadcaster submits transactions to an HTTP endpoint that accepts the
+// raw hex-encoded transaction as POST body.
+type HTTPBroadcaster struct {
+	endpoint string
+	client   *http.Client
+}
+
+// A compile-time check to ensure HTTPBroadcaster implements Broadcaster.
+var _ Broadcaster = (*HTTPBroadcaster)(nil)
+
+// NewHTTPBroadcaster creates a new broadcaster for the given endpoint.
+func NewHTTPBroadcaster(endpoint string) *HTTPBroadcaster {
+	return &HTTPBroadcaster{
+		endpoint: endpoint,
+		client:   &http.Client{},
+	}
+}
+
+// Name returns the endpoint of the broadcaster.
+//
+// NOTE: This is part of the Broadcaster interface.
+func (h *HTTPBroadcaster) Name() string {
+	return h.endpoint
+}
+
+// Broadcast posts the hex-encoded transaction to the endpoint.
+//
+// NOTE: This is part of the Broadcaster interface.
+func (h *HTTPBroadcaster) Broadcast(ctx context.Context,
+	tx *wire.MsgTx) error {
+
+	var buf bytes.Buffer
+	if err := tx.Serialize(&buf); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, h.endpoint,
+		strings.NewReader(hex.EncodeToString(buf.Bytes())),
+	)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(
+			io.LimitReader(resp.Body, maxExtBroadcastResponseSize),
+		)
+
+		return fmt.Errorf("unexpected status %v: %s", resp.Status,
+			strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
+
+// BitcoindBroadcaster submits transactions to a bitcoind node using the
+// sendrawtransaction RPC.
+type BitcoindBroadcaster struct {
+	host   string
+	client *rpcclient.Client
+}
+
+// A compile-time check to ensure BitcoindBroadcaster implements Broadcaster.
+var _ Broadcaster = (*BitcoindBroadcaster)(nil)
+
+// NewBitcoindBroadcaster creates a new broadcaster for an endpoint in the
+// form user:pass@host:port.
+func NewBitcoindBroadcaster(endpoint string) (*BitcoindBroadcaster, error) {
+	connCfg, err := parseBitcoindEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := rpcclient.New(connCfg, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BitcoindBroadcaster{
+		host:   connCfg.Host,
+		client: client,
+	}, nil
+}
+
+// Name returns the host of the bitcoind node. The credentials are omitted so
+// they don't end up in the logs.
+//
+// NOTE: This is part of the Broadcaster interface.
+func (b *BitcoindBroadcaster) Name() string {
+	return "bitcoind@" + b.host
+}
+
+// Broadcast submits the transaction using sendrawtransaction.
+//
+// NOTE: This is part of the Broadcaster interface.
+func (b *BitcoindBroadcaster) Broadcast(ctx context.Context,
+	tx *wire.MsgTx) error {
+
+	future := b.client.SendRawTransactionAsync(tx, false)
+
+	errChan := make(chan error, 1)
+	go func() {
+		_, err := future.Receive()
+		errChan <- err
+	}()
+
+	select {
+	case err := <-errChan:
+		return err
+
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stop shuts down the RPC client.
+func (b *BitcoindBroadcaster) Stop() {
+	b.client.Shutdown()
+}
+
+// parseBitcoindEndpoint parses an endpoint in the form user:pass@host:port
+// into an RPC connection config.
+func parseBitcoindEndpoint(endpoint string) (*rpcclient.ConnConfig, error) {
+	creds, host, ok := strings.Cut(endpoint, "@")
+	if !ok || host == "" {
+		return nil, errInvalidBitcoindEndpoint
+	}
+
+	user, pass, ok := strings.Cut(creds, ":")
+	if !ok || user == "" {
+		return nil, errInvalidBitcoindEndpoint
+	}
+
+	return &rpcclient.ConnConfig{
+		Host:         host,
+		User:         user,
+		Pass:         pass,
+		DisableTLS:   true,
+		HTTPPostMode: true,
+	}, nil
+}
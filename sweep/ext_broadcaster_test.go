@@ -0,0 +1,138 @@
+package sweep
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/stretchr/testify/require"
+)
+
+// mockBroadcaster is a Broadcaster that returns a fixed error.
+type mockBroadcaster struct {
+	name string
+	err  error
+}
+
+func (m *mockBroadcaster) Name() string {
+	return m.name
+}
+
+func (m *mockBroadcaster) Broadcast(context.Context, *wire.MsgTx) error {
+	return m.err
+}
+
+// TestExternalPublisher checks that transactions are submitted to all external
+// broadcasters and that the results are tracked per broadcaster.
+func TestExternalPublisher(t *testing.T) {
+	t.Parallel()
+
+	errRejected := errors.New("rejected")
+	publisher := NewExternalPublisher(
+		time.Second,
+		&mockBroadcaster{name: "ok"},
+		&mockBroadcaster{name: "fail", err: errRejected},
+	)
+
+	// The result of the wrapped publish function is returned unchanged
+	// and doesn't prevent the external submission.
+	errLocal := errors.New("mempool min fee not met")
+	publish := publisher.WrapPublish(func(*wire.MsgTx, string) error {
+		return errLocal
+	})
+
+	tx := wire.NewMsgTx(2)
+	require.ErrorIs(t, publish(tx, ""), errLocal)
+	publisher.Stop()
+
+	stats := publisher.Stats()
+	require.EqualValues(t, 1, stats["ok"].Attempts)
+	require.EqualValues(t, 1, stats["ok"].Successes)
+	require.False(t, stats["ok"].LastSuccess.IsZero())
+
+	require.EqualValues(t, 1, stats["fail"].Attempts)
+	require.Zero(t, stats["fail"].Successes)
+	require.ErrorIs(t, stats["fail"].LastErr, errRejected)
+
+	// Transactions that double spend are not submitted externally.
+	publish = publisher.WrapPublish(func(*wire.MsgTx, string) error {
+		return lnwallet.ErrDoubleSpend
+	})
+	require.ErrorIs(t, publish(tx, ""), lnwallet.ErrDoubleSpend)
+	publisher.Stop()
+
+	require.EqualValues(t, 1, publisher.Stats()["ok"].Attempts)
+}
+
+// TestHTTPBroadcaster checks that the HTTP broadcaster posts the hex-encoded
+// transaction and reports rejections.
+func TestHTTPBroadcaster(t *testing.T) {
+	t.Parallel()
+
+	tx := wire.NewMsgTx(2)
+	tx.AddTxOut(&wire.TxOut{Value: 1000, PkScript: []byte{0x51}})
+
+	var buf bytes.Buffer
+	require.NoError(t, tx.Serialize(&buf))
+	txHex := hex.EncodeToString(buf.Bytes())
+
+	var reject atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			require.Equal(t, http.MethodPost, r.Method)
+			require.Equal(t, txHex, string(body))
+
+			if reject.Load() {
+				http.Error(w, "min relay fee not met",
+					http.StatusBadRequest)
+
+				return
+			}
+
+			_, _ = w.Write([]byte(tx.TxHash().String()))
+		},
+	))
+	t.Cleanup(server.Close)
+
+	broadcaster := NewHTTPBroadcaster(server.URL)
+	require.NoError(t, broadcaster.Broadcast(context.Background(), tx))
+
+	reject.Store(true)
+	err := broadcaster.Broadcast(context.Background(), tx)
+	require.ErrorContains(t, err, "min relay fee not met")
+}
+
+// TestExtBroadcastConfigValidate checks the validation of the external
+// broadcast config.
+func TestExtBroadcastConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	cfg := &ExtBroadcastConfig{
+		HTTPEndpoints:     []string{"https://mempool.space/api/tx"},
+		BitcoindEndpoints: []string{"user:pass@127.0.0.1:8332"},
+		Timeout:           time.Second,
+	}
+	require.NoError(t, cfg.Validate())
+
+	cfg.HTTPEndpoints = []string{"ftp://example.com"}
+	require.Error(t, cfg.Validate())
+
+	cfg.HTTPEndpoints = nil
+	cfg.BitcoindEndpoints = []string{"127.0.0.1:8332"}
+	require.Error(t, cfg.Validate())
+
+	cfg.BitcoindEndpoints = nil
+	cfg.Timeout = 0
+	require.Error(t, cfg.Validate())
+}
@@ -6,6 +6,7 @@ import (
 	"sync"
 	"sync/atomic"
 
+	"github.com/btcsuite/btcd/blockchain"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/rpcclient"
@@ -295,6 +296,11 @@ type TxPublisherConfig struct {
 	// AuxSweeper is an optional interface that can be used to modify the
 	// way sweep transaction are generated.
 	AuxSweeper fn.Option[AuxSweeper]
+
+	// PackagePublisher is an optional interface that is used to publish
+	// sweeping txns that spend from an unconfirmed parent, such as anchor
+	// spends, along with their parent as a package.
+	PackagePublisher fn.Option[PackagePublisher]
 }
 
 // TxPublisher is an implementation of the Bumper interface. It utilizes the
@@ -536,15 +542,37 @@ func (t *TxPublisher) storeRecord(tx *wire.MsgTx, req *BumpRequest,
 func (t *TxPublisher) createAndCheckTx(req *BumpRequest,
 	f FeeFunction) (*sweepTxCtx, error) {
 
+	// If the inputs spend from an unconfirmed parent that can be
+	// published along with the sweeping tx as a package, we'll use the
+	// same version as the parent if it's a TRUC tx, as its child must be
+	// a TRUC tx as well.
+	version := int32(2)
+	parent, support := t.packageParent(req.Inputs)
+	if parent != nil && parent.Version == TRUCVersion && support.TRUC {
+		version = TRUCVersion
+	}
+
 	// Create the sweep tx with max fee rate of 0 as the fee function
 	// guarantees the fee rate used here won't exceed the max fee rate.
 	sweepCtx, err := t.createSweepTx(
-		req.Inputs, req.DeliveryAddress, f.FeeRate(),
+		req.Inputs, req.DeliveryAddress, f.FeeRate(), version,
 	)
 	if err != nil {
 		return sweepCtx, fmt.Errorf("create sweep tx: %w", err)
 	}
 
+	// A TRUC child is limited in size, so it can't be used to pin its
+	// parent.
+	if version == TRUCVersion {
+		weight := blockchain.GetTransactionWeight(
+			btcutil.NewTx(sweepCtx.tx),
+		)
+		if weight > maxTRUCChildWeight {
+			return sweepCtx, fmt.Errorf("%w: weight=%v",
+				ErrTRUCChildTooLarge, weight)
+		}
+	}
+
 	// Sanity check the budget still covers the fee.
 	if sweepCtx.fee > req.Budget {
 		return sweepCtx, fmt.Errorf("%w: budget=%v, fee=%v",
@@ -568,20 +596,47 @@ func (t *TxPublisher) createAndCheckTx(req *BumpRequest,
 	if errors.Is(err, rpcclient.ErrBackendVersion) {
 		log.Errorf("TestMempoolAccept not supported by backend, " +
 			"consider upgrading it to a newer version")
-		return sweepCtx, nil
+
+		return sweepCtx, t.checkRelayFee(f.FeeRate())
 	}
 
 	// We are running on a backend that doesn't implement the RPC
 	// testmempoolaccept, eg, neutrino, so we'll skip the check.
 	if errors.Is(err, chain.ErrUnimplemented) {
 		log.Debug("Skipped testmempoolaccept due to not implemented")
-		return sweepCtx, nil
+
+		return sweepCtx, t.checkRelayFee(f.FeeRate())
+	}
+
+	// The parent of the tx may not be in the mempool if its fee rate is
+	// below the mempool min fee. As the tx will be published along with
+	// its parent as a package, the mempool can only evaluate it once the
+	// package is submitted.
+	if parent != nil && errors.Is(err, chain.ErrMissingInputs) {
+		log.Debugf("Skipped testmempoolaccept for tx=%v, its parent "+
+			"%v will be published as package", sweepCtx.tx.TxHash(),
+			parent.TxHash())
+
+		return sweepCtx, t.checkRelayFee(f.FeeRate())
 	}
 
 	return sweepCtx, fmt.Errorf("tx=%v failed mempool check: %w",
 		sweepCtx.tx.TxHash(), err)
 }
 
+// checkRelayFee is used when the mempool acceptance of a tx can't be tested. It
+// makes sure the given fee rate is not below the current fee floor of the
+// backend, as the tx would otherwise be dropped silently once published. An
+// ErrMempoolFee is returned in that case so the fee rate is increased.
+func (t *TxPublisher) checkRelayFee(feeRate chainfee.SatPerKWeight) error {
+	err := chainfee.CheckRelayFee(t.cfg.Estimator, feeRate)
+	if err != nil {
+		return fmt.Errorf("%w: %w", lnwallet.ErrMempoolFee, err)
+	}
+
+	return nil
+}
+
 // broadcast takes a monitored tx and publishes it to the network. Prior to the
 // broadcast, it will subscribe the tx's confirmation notification and attach
 // the event channel to the record. Any broadcast-related errors will not be
@@ -616,9 +671,7 @@ func (t *TxPublisher) broadcast(requestID uint64) (*BumpResult, error) {
 	// Publish the sweeping tx with customized label. If the publish fails,
 	// this error will be saved in the `BumpResult` and it will be removed
 	// from being monitored.
-	err = t.cfg.Wallet.PublishTransaction(
-		tx, labels.MakeLabel(labels.LabelTypeSweepTransaction, nil),
-	)
+	err = t.publish(record.req, tx)
 	if err != nil {
 		// NOTE: we decide to attach this error to the result instead
 		// of returning it here because by the time the tx reaches
@@ -644,6 +697,80 @@ func (t *TxPublisher) broadcast(requestID uint64) (*BumpResult, error) {
 	return result, nil
 }
 
+// packageParent returns the unconfirmed parent of the given inputs if the
+// sweeping tx can be published along with it as a 1-parent-1-child package,
+// together with the package relay features of the backend. Nil is returned if
+// the tx can't be published as a package.
+func (t *TxPublisher) packageParent(
+	inputs []input.Input) (*wire.MsgTx, PackageRelaySupport) {
+
+	var support PackageRelaySupport
+
+	if t.cfg.PackagePublisher.IsNone() {
+		return nil, support
+	}
+
+	parentHash, ok := unconfParentHash(inputs)
+	if !ok {
+		return nil, support
+	}
+
+	publisher := t.cfg.PackagePublisher.UnsafeFromSome()
+	support, err := publisher.PackageRelaySupport()
+	if err != nil {
+		log.Warnf("Unable to detect package relay support: %v", err)
+
+		return nil, support
+	}
+	if !support.Package {
+		return nil, support
+	}
+
+	// The parent is one of our own txns, such as a commitment tx, so the
+	// wallet should know about it.
+	parent, err := t.cfg.Wallet.FetchTx(parentHash)
+	if err != nil || parent == nil {
+		log.Debugf("Unable to fetch parent tx %v, skipping package "+
+			"relay: %v", parentHash, err)
+
+		return nil, support
+	}
+
+	return parent, support
+}
+
+// publish publishes the given sweeping tx. If it spends from an unconfirmed
+// parent and the backend supports package relay, the tx is first published
+// along with its parent as a package, so it can pay for a parent that is
+// below the mempool min fee. Otherwise, or if the package is rejected for
+// another reason than its fee, the tx is published on its own.
+func (t *TxPublisher) publish(req *BumpRequest, tx *wire.MsgTx) error {
+	parent, _ := t.packageParent(req.Inputs)
+	if parent != nil {
+		publisher := t.cfg.PackagePublisher.UnsafeFromSome()
+		err := publisher.PublishPackage(parent, tx)
+
+		switch {
+		// If the package didn't pay enough fees, we return the error
+		// so the fee of the tx is bumped.
+		case errors.Is(err, lnwallet.ErrMempoolFee):
+			return err
+
+		case err != nil:
+			log.Warnf("Unable to publish tx %v as package with "+
+				"parent %v, publishing it on its own: %v",
+				tx.TxHash(), parent.TxHash(), err)
+		}
+	}
+
+	// We always publish the tx through the wallet as well, so it keeps
+	// track of it. If the tx was already accepted as part of a package,
+	// it's already in the mempool, which the wallet tolerates.
+	return t.cfg.Wallet.PublishTransaction(
+		tx, labels.MakeLabel(labels.LabelTypeSweepTransaction, nil),
+	)
+}
+
 // notifyResult sends the result to the resultChan specified by the requestID.
 // This channel is expected to be read by the caller.
 func (t *TxPublisher) notifyResult(result *BumpResult) {
@@ -1202,10 +1329,10 @@ type sweepTxCtx struct {
 }
 
 // createSweepTx creates a sweeping tx based on the given inputs, change
-// address and fee rate.
+// address, fee rate and tx version.
 func (t *TxPublisher) createSweepTx(inputs []input.Input,
-	changePkScript lnwallet.AddrWithKey,
-	feeRate chainfee.SatPerKWeight) (*sweepTxCtx, error) {
+	changePkScript lnwallet.AddrWithKey, feeRate chainfee.SatPerKWeight,
+	version int32) (*sweepTxCtx, error) {
 
 	// Validate and calculate the fee and change amount.
 	txFee, changeOutputsOpt, locktimeOpt, err := prepareSweepTx(
@@ -1218,8 +1345,8 @@ func (t *TxPublisher) createSweepTx(inputs []input.Input,
 
 	var (
 		// Create the sweep transaction that we will be building. We
-		// use version 2 as it is required for CSV.
-		sweepTx = wire.NewMsgTx(2)
+		// use at least version 2 as it is required for CSV.
+		sweepTx = wire.NewMsgTx(version)
 
 		// We'll add the inputs as we go so we know the final ordering
 		// of inputs to sign.
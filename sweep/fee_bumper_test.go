@@ -474,6 +474,48 @@ func TestCreateAndCheckTx(t *testing.T) {
 	}
 }
 
+// TestCreateAndCheckTxNoMempoolAccept checks that `createAndCheckTx` enforces
+// the min relay fee rate of the backend when it can't test the mempool
+// acceptance of the tx.
+func TestCreateAndCheckTxNoMempoolAccept(t *testing.T) {
+	t.Parallel()
+
+	// Create a publisher using the mocks.
+	tp, m := createTestPublisher(t)
+
+	// Create a test bump request.
+	req := createTestBumpRequest()
+
+	// Create a test feerate and return it from the mock fee function.
+	feerate := chainfee.SatPerKWeight(1000)
+	m.feeFunc.On("FeeRate").Return(feerate)
+
+	// Mock the signer to always return a valid script.
+	script := &input.Script{}
+	m.signer.On("ComputeInputScript", mock.Anything,
+		mock.Anything).Return(script, nil)
+
+	// Mock the wallet to not support testmempoolaccept.
+	m.wallet.On("CheckMempoolAcceptance",
+		mock.Anything).Return(chain.ErrUnimplemented)
+
+	// When the fee rate is below the min relay fee rate, we expect an
+	// ErrMempoolFee so the fee rate is increased.
+	m.estimator.On("RelayFeePerKW").Return(feerate + 1).Once()
+
+	_, err := tp.createAndCheckTx(req, m.feeFunc)
+	require.ErrorIs(t, err, lnwallet.ErrMempoolFee)
+	require.ErrorIs(t, err, chainfee.ErrFeeRateBelowRelayFee)
+
+	// When the fee rate matches the min relay fee rate, the tx is
+	// returned.
+	m.estimator.On("RelayFeePerKW").Return(feerate).Once()
+
+	sweepCtx, err := tp.createAndCheckTx(req, m.feeFunc)
+	require.NoError(t, err)
+	require.NotNil(t, sweepCtx.tx)
+}
+
 // createTestBumpRequest creates a new bump request.
 func createTestBumpRequest() *BumpRequest {
 	// Create a test input.
@@ -0,0 +1,229 @@
+package sweep
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+)
+
+// FeeCandidate is a fee rate at which the pending sweeps are evaluated in a
+// fee report. Either a fee rate or a conf target must be set. If only a conf
+// target is set, the fee rate is estimated for it.
+type FeeCandidate struct {
+	// FeeRate is the fee rate to evaluate.
+	FeeRate chainfee.SatPerKWeight
+
+	// ConfTarget is the number of blocks within which the sweeps should
+	// confirm. It's used to estimate the fee rate if none is set.
+	ConfTarget uint32
+}
+
+// SetFeeReport reports the outcome of sweeping an input set at a fee rate.
+type SetFeeReport struct {
+	// Inputs are the outpoints of the inputs in the set.
+	Inputs []wire.OutPoint
+
+	// DeadlineHeight is the deadline height of the set.
+	DeadlineHeight int32
+
+	// Budget is the total budget of the inputs in the set.
+	Budget btcutil.Amount
+
+	// Weight is the estimated weight of the sweeping tx.
+	Weight lntypes.WeightUnit
+
+	// Fee is the fee the sweeping tx pays at the fee rate.
+	Fee btcutil.Amount
+
+	// WithinBudget is true if the fee is covered by the budget of the set
+	// and the fee rate doesn't exceed the sweeper's max fee rate.
+	WithinBudget bool
+}
+
+// FeeReport reports the outcome of sweeping all pending input sets at a fee
+// rate.
+type FeeReport struct {
+	// FeeRate is the evaluated fee rate.
+	FeeRate chainfee.SatPerKWeight
+
+	// ConfTarget is the conf target the fee rate was estimated for, if
+	// any.
+	ConfTarget uint32
+
+	// Sets reports the outcome for each of the input sets.
+	Sets []SetFeeReport
+
+	// TotalFee is the total fee paid by the sweeping txns of all sets.
+	TotalFee btcutil.Amount
+
+	// NumWithinBudget is the number of sets whose budget covers the fee.
+	NumWithinBudget int
+
+	// BudgetShortfall is the total amount by which the fees exceed the
+	// budgets of the sets that can't cover them. This is the budget that
+	// would need to be added to sweep all sets at the fee rate.
+	BudgetShortfall btcutil.Amount
+}
+
+// feeReportReq is an internal message we'll use to represent an external
+// caller's intent to retrieve a fee report for the pending inputs.
+type feeReportReq struct {
+	feeRates []FeeCandidate
+	respChan chan []*FeeReport
+	errChan  chan error
+}
+
+// FeeReport reports, for each of the candidates, the outcome of sweeping the
+// inputs that are currently pending in the UtxoSweeper at the candidate's fee
+// rate. The inputs are clustered into sets the same way they would be swept,
+// but no transactions are created or published. This helps to decide whether
+// the budgets of the pending inputs should be bumped.
+//
+// NOTE: All inputs that haven't reached a final state are included, regardless
+// of whether they have been published or are still timelocked. Wallet inputs
+// that may be needed to create a change output are not considered.
+func (s *UtxoSweeper) FeeReport(candidates []FeeCandidate) ([]*FeeReport,
+	error) {
+
+	// Estimate the fee rates up front, so the main event loop isn't
+	// blocked by the fee estimator.
+	feeRates := make([]FeeCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		if c.FeeRate == 0 {
+			if c.ConfTarget == 0 {
+				return nil, fmt.Errorf("either fee rate or " +
+					"conf target must be set")
+			}
+
+			feeRate, err := s.cfg.FeeEstimator.EstimateFeePerKW(
+				c.ConfTarget,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("unable to estimate "+
+					"fee rate for conf target %v: %w",
+					c.ConfTarget, err)
+			}
+			c.FeeRate = feeRate
+		}
+
+		feeRates = append(feeRates, c)
+	}
+
+	respChan := make(chan []*FeeReport, 1)
+	errChan := make(chan error, 1)
+	select {
+	case s.feeReportReqs <- &feeReportReq{
+		feeRates: feeRates,
+		respChan: respChan,
+		errChan:  errChan,
+	}:
+	case <-s.quit:
+		return nil, ErrSweeperShuttingDown
+	}
+
+	select {
+	case reports := <-respChan:
+		return reports, nil
+	case err := <-errChan:
+		return nil, err
+	case <-s.quit:
+		return nil, ErrSweeperShuttingDown
+	}
+}
+
+// handleFeeReportReq handles a request to create a fee report for the pending
+// inputs.
+func (s *UtxoSweeper) handleFeeReportReq(req *feeReportReq) {
+	inputs := make(InputsMap, len(s.inputs))
+	for op, inp := range s.inputs {
+		if inp.terminated() {
+			continue
+		}
+
+		inputs[op] = inp
+	}
+
+	changePkScript := dummyChangePkScript
+	s.currentOutputScript.WhenSome(func(addr lnwallet.AddrWithKey) {
+		changePkScript = addr.DeliveryAddress
+	})
+
+	sets := s.cfg.Aggregator.ClusterInputs(inputs)
+	reports, err := newFeeReports(
+		sets, req.feeRates, changePkScript,
+		s.cfg.MaxFeeRate.FeePerKWeight(),
+	)
+	if err != nil {
+		req.errChan <- err
+		return
+	}
+
+	req.respChan <- reports
+}
+
+// newFeeReports creates a fee report for each of the candidates, evaluating
+// the sweeping txns of the given input sets at the candidate's fee rate.
+func newFeeReports(sets []InputSet, candidates []FeeCandidate,
+	changePkScript []byte,
+	maxFeeRate chainfee.SatPerKWeight) ([]*FeeReport, error) {
+
+	// The weight of the sweeping txns doesn't depend on the fee rate, so
+	// we estimate it once for each set.
+	weights := make([]lntypes.WeightUnit, len(sets))
+	for i, set := range sets {
+		weight, err := calcSweepTxWeight(
+			set.Inputs(), [][]byte{changePkScript},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to estimate weight of "+
+				"sweep tx: %w", err)
+		}
+		weights[i] = weight
+	}
+
+	reports := make([]*FeeReport, 0, len(candidates))
+	for _, c := range candidates {
+		report := &FeeReport{
+			FeeRate:    c.FeeRate,
+			ConfTarget: c.ConfTarget,
+			Sets:       make([]SetFeeReport, 0, len(sets)),
+		}
+
+		for i, set := range sets {
+			inputs := set.Inputs()
+			outpoints := make([]wire.OutPoint, 0, len(inputs))
+			for _, inp := range inputs {
+				outpoints = append(outpoints, inp.OutPoint())
+			}
+
+			fee := c.FeeRate.FeeForWeight(weights[i])
+			budget := set.Budget()
+			withinBudget := fee <= budget && c.FeeRate <= maxFeeRate
+
+			report.Sets = append(report.Sets, SetFeeReport{
+				Inputs:         outpoints,
+				DeadlineHeight: set.DeadlineHeight(),
+				Budget:         budget,
+				Weight:         weights[i],
+				Fee:            fee,
+				WithinBudget:   withinBudget,
+			})
+
+			report.TotalFee += fee
+			if withinBudget {
+				report.NumWithinBudget++
+			}
+			if fee > budget {
+				report.BudgetShortfall += fee - budget
+			}
+		}
+
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
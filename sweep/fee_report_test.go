@@ -0,0 +1,92 @@
+package sweep
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewFeeReports tests that the fee reports evaluate each input set at the
+// candidate fee rates against its budget and the max fee rate.
+func TestNewFeeReports(t *testing.T) {
+	t.Parallel()
+
+	newSet := func(budget int64) InputSet {
+		inp := SweeperInput{
+			Input: createP2WKHInput(100_000),
+			params: Params{
+				Budget: btcutil.Amount(budget),
+			},
+		}
+		set, err := NewBudgetInputSet(
+			[]SweeperInput{inp}, testHeight, fn.None[AuxSweeper](),
+		)
+		require.NoError(t, err)
+
+		return set
+	}
+
+	sets := []InputSet{newSet(1_000), newSet(10_000)}
+
+	const maxFeeRate = chainfee.SatPerKWeight(20_000)
+	candidates := []FeeCandidate{
+		{FeeRate: 1_000},
+		{FeeRate: 10_000, ConfTarget: 6},
+		{FeeRate: 30_000},
+	}
+
+	reports, err := newFeeReports(
+		sets, candidates, dummyChangePkScript, maxFeeRate,
+	)
+	require.NoError(t, err)
+	require.Len(t, reports, len(candidates))
+
+	// Both sets have the same inputs, so their txns have the same weight.
+	weight := reports[0].Sets[0].Weight
+	require.NotZero(t, weight)
+
+	for i, report := range reports {
+		feeRate := candidates[i].FeeRate
+		fee := feeRate.FeeForWeight(weight)
+
+		require.Equal(t, feeRate, report.FeeRate)
+		require.Equal(t, candidates[i].ConfTarget, report.ConfTarget)
+		require.Len(t, report.Sets, len(sets))
+		require.Equal(t, 2*fee, report.TotalFee)
+
+		var (
+			numWithinBudget int
+			shortfall       btcutil.Amount
+		)
+		for j, setReport := range report.Sets {
+			budget := sets[j].Budget()
+
+			require.Equal(t, weight, setReport.Weight)
+			require.Equal(t, fee, setReport.Fee)
+			require.Equal(t, budget, setReport.Budget)
+			require.Len(t, setReport.Inputs, 1)
+
+			withinBudget := fee <= budget && feeRate <= maxFeeRate
+			require.Equal(t, withinBudget, setReport.WithinBudget)
+
+			if withinBudget {
+				numWithinBudget++
+			}
+			if fee > budget {
+				shortfall += fee - budget
+			}
+		}
+
+		require.Equal(t, numWithinBudget, report.NumWithinBudget)
+		require.Equal(t, shortfall, report.BudgetShortfall)
+	}
+
+	// At the lowest fee rate, both sets can be swept. At the highest, the
+	// max fee rate is exceeded.
+	require.Equal(t, 2, reports[0].NumWithinBudget)
+	require.Zero(t, reports[0].BudgetShortfall)
+	require.Zero(t, reports[2].NumWithinBudget)
+}
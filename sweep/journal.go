@@ -0,0 +1,481 @@
+package sweep
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+var (
+	// journalBucketKey is the key of the bucket that contains the outcomes
+	// of all sweep attempts.
+	//
+	// maps: index -> JournalEntry
+	journalBucketKey = []byte("sweeper-journal")
+
+	// errNoJournalBucket is returned if the journal bucket doesn't exist.
+	errNoJournalBucket = errors.New("sweep journal bucket does not exist")
+
+	// ErrNoSweepJournal is returned when the sweep journal is queried but
+	// the sweeper has been created without one.
+	ErrNoSweepJournal = errors.New("sweep journal not enabled")
+)
+
+// SweepOutcome classifies how a sweep attempt ended.
+type SweepOutcome uint8
+
+const (
+	// SweepConfirmed indicates that the sweeping tx confirmed.
+	SweepConfirmed SweepOutcome = iota
+
+	// SweepReplaced indicates that the sweeping tx was replaced by one of
+	// our own txns with a higher fee.
+	SweepReplaced
+
+	// SweepConflicted indicates that the sweeping tx lost against a tx of
+	// a third party, either because the third party tx spent one of its
+	// inputs or because publishing was rejected due to a conflicting tx.
+	// Repeated conflicts of the same input may indicate a pinning attack.
+	SweepConflicted
+
+	// SweepAbandoned indicates that the sweeping tx failed for another
+	// reason and the attempt was given up. Its inputs are retried with a
+	// new tx.
+	SweepAbandoned
+)
+
+// String returns a human-readable string for the outcome.
+func (o SweepOutcome) String() string {
+	switch o {
+	case SweepConfirmed:
+		return "Confirmed"
+
+	case SweepReplaced:
+		return "Replaced"
+
+	case SweepConflicted:
+		return "Conflicted"
+
+	case SweepAbandoned:
+		return "Abandoned"
+
+	default:
+		return fmt.Sprintf("Unknown(%d)", uint8(o))
+	}
+}
+
+// JournalEntry records the outcome of a single sweep attempt.
+type JournalEntry struct {
+	// Index is the position of the entry in the journal, assigned when it
+	// is added.
+	Index uint64
+
+	// Txid is the txid of the sweeping tx.
+	Txid chainhash.Hash
+
+	// Outcome is how the sweep attempt ended.
+	Outcome SweepOutcome
+
+	// Fee is the fee the sweeping tx pays. For confirmed sweeps, this is
+	// the fee that was actually paid.
+	Fee btcutil.Amount
+
+	// FeeRate is the fee rate of the sweeping tx.
+	FeeRate chainfee.SatPerKWeight
+
+	// Inputs are the inputs spent by the sweeping tx.
+	Inputs []wire.OutPoint
+
+	// Height is the best block height when the outcome was recorded.
+	Height int32
+
+	// Timestamp is the time the outcome was recorded.
+	Timestamp time.Time
+
+	// RelatedTxid is the tx that determined the outcome, if any. For
+	// replaced sweeps, this is the replacing tx. For sweeps conflicted by
+	// a third party spend, this is the spending tx.
+	RelatedTxid fn.Option[chainhash.Hash]
+
+	// Reason describes why a sweep was conflicted or abandoned, if known.
+	Reason string
+}
+
+// Tlv types used to serialize journal entries.
+//
+// NOTE: A migration should be added whenever the existing types change.
+const (
+	journalTxidType        tlv.Type = 0
+	journalOutcomeType     tlv.Type = 1
+	journalFeeType         tlv.Type = 2
+	journalFeeRateType     tlv.Type = 3
+	journalInputsType      tlv.Type = 4
+	journalHeightType      tlv.Type = 5
+	journalTimestampType   tlv.Type = 6
+	journalRelatedTxidType tlv.Type = 7
+	journalReasonType      tlv.Type = 8
+)
+
+// serializeJournalEntry serializes a JournalEntry based on tlv format. The
+// index is stored as the key, so it's not included here.
+func serializeJournalEntry(w io.Writer, e *JournalEntry) error {
+	var (
+		txid      = [32]byte(e.Txid)
+		outcome   = uint8(e.Outcome)
+		fee       = uint64(e.Fee)
+		feeRate   = uint64(e.FeeRate)
+		height    = uint32(e.Height)
+		timestamp = uint64(e.Timestamp.UnixNano())
+		reason    = []byte(e.Reason)
+	)
+
+	var inputs bytes.Buffer
+	for _, op := range e.Inputs {
+		if _, err := inputs.Write(op.Hash[:]); err != nil {
+			return err
+		}
+
+		var index [4]byte
+		byteOrder.PutUint32(index[:], op.Index)
+		if _, err := inputs.Write(index[:]); err != nil {
+			return err
+		}
+	}
+	inputBytes := inputs.Bytes()
+
+	records := []tlv.Record{
+		tlv.MakePrimitiveRecord(journalTxidType, &txid),
+		tlv.MakePrimitiveRecord(journalOutcomeType, &outcome),
+		tlv.MakeBigSizeRecord(journalFeeType, &fee),
+		tlv.MakeBigSizeRecord(journalFeeRateType, &feeRate),
+		tlv.MakePrimitiveRecord(journalInputsType, &inputBytes),
+		tlv.MakePrimitiveRecord(journalHeightType, &height),
+		tlv.MakePrimitiveRecord(journalTimestampType, &timestamp),
+	}
+
+	e.RelatedTxid.WhenSome(func(related chainhash.Hash) {
+		relatedTxid := [32]byte(related)
+		records = append(records, tlv.MakePrimitiveRecord(
+			journalRelatedTxidType, &relatedTxid,
+		))
+	})
+
+	if len(reason) > 0 {
+		records = append(records, tlv.MakePrimitiveRecord(
+			journalReasonType, &reason,
+		))
+	}
+
+	tlvStream, err := tlv.NewStream(records...)
+	if err != nil {
+		return err
+	}
+
+	return tlvStream.Encode(w)
+}
+
+// deserializeJournalEntry deserializes a JournalEntry based on tlv format.
+func deserializeJournalEntry(r io.Reader) (*JournalEntry, error) {
+	var (
+		txid, relatedTxid [32]byte
+		outcome           uint8
+		fee, feeRate      uint64
+		height            uint32
+		timestamp         uint64
+		inputs, reason    []byte
+	)
+
+	tlvStream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(journalTxidType, &txid),
+		tlv.MakePrimitiveRecord(journalOutcomeType, &outcome),
+		tlv.MakeBigSizeRecord(journalFeeType, &fee),
+		tlv.MakeBigSizeRecord(journalFeeRateType, &feeRate),
+		tlv.MakePrimitiveRecord(journalInputsType, &inputs),
+		tlv.MakePrimitiveRecord(journalHeightType, &height),
+		tlv.MakePrimitiveRecord(journalTimestampType, &timestamp),
+		tlv.MakePrimitiveRecord(journalRelatedTxidType, &relatedTxid),
+		tlv.MakePrimitiveRecord(journalReasonType, &reason),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := tlvStream.DecodeWithParsedTypes(r)
+	if err != nil {
+		return nil, err
+	}
+
+	const outpointLen = chainhash.HashSize + 4
+	if len(inputs)%outpointLen != 0 {
+		return nil, fmt.Errorf("invalid inputs length %d", len(inputs))
+	}
+
+	entry := &JournalEntry{
+		Txid:      txid,
+		Outcome:   SweepOutcome(outcome),
+		Fee:       btcutil.Amount(fee),
+		FeeRate:   chainfee.SatPerKWeight(feeRate),
+		Inputs:    make([]wire.OutPoint, 0, len(inputs)/outpointLen),
+		Height:    int32(height),
+		Timestamp: time.Unix(0, int64(timestamp)),
+		Reason:    string(reason),
+	}
+
+	for i := 0; i < len(inputs); i += outpointLen {
+		var op wire.OutPoint
+		copy(op.Hash[:], inputs[i:i+chainhash.HashSize])
+		op.Index = byteOrder.Uint32(
+			inputs[i+chainhash.HashSize : i+outpointLen],
+		)
+		entry.Inputs = append(entry.Inputs, op)
+	}
+
+	if _, ok := parsed[journalRelatedTxidType]; ok {
+		entry.RelatedTxid = fn.Some(chainhash.Hash(relatedTxid))
+	}
+
+	return entry, nil
+}
+
+// JournalQuery selects the entries returned from the sweep journal.
+type JournalQuery struct {
+	// IndexOffset is the index after which entries are returned, which
+	// allows paginating through the journal using the LastIndexOffset of
+	// the previous response.
+	IndexOffset uint64
+
+	// MaxEntries is the maximum number of entries returned. If zero, all
+	// matching entries are returned.
+	MaxEntries uint32
+
+	// Outcomes restricts the query to the given outcomes. If empty,
+	// entries of all outcomes are returned.
+	Outcomes fn.Set[SweepOutcome]
+
+	// StartHeight and EndHeight restrict the query to entries recorded
+	// within the inclusive height range. An EndHeight of zero doesn't
+	// limit the range.
+	StartHeight int32
+	EndHeight   int32
+}
+
+// matches returns true if the entry is selected by the query.
+func (q *JournalQuery) matches(e *JournalEntry) bool {
+	if !q.Outcomes.IsEmpty() && !q.Outcomes.Contains(e.Outcome) {
+		return false
+	}
+
+	if e.Height < q.StartHeight {
+		return false
+	}
+
+	return q.EndHeight == 0 || e.Height <= q.EndHeight
+}
+
+// JournalSlice is a page of entries returned from the sweep journal.
+type JournalSlice struct {
+	// Entries are the matching entries, ordered by their index.
+	Entries []*JournalEntry
+
+	// LastIndexOffset is the index of the last entry that was examined.
+	// It can be used as IndexOffset of the next query to continue where
+	// this one stopped.
+	LastIndexOffset uint64
+}
+
+// SweepJournal persists the outcomes of sweep attempts, so that the fee
+// efficiency of past sweeps can be analyzed and repeated conflicts can be
+// detected.
+type SweepJournal interface {
+	// AddEntry appends the entry to the journal and assigns its index.
+	AddEntry(entry *JournalEntry) error
+
+	// QueryJournal returns the journal entries selected by the query.
+	QueryJournal(query JournalQuery) (*JournalSlice, error)
+}
+
+// journalStore is a SweepJournal backed by a kvdb bucket.
+type journalStore struct {
+	db kvdb.Backend
+}
+
+// A compile-time check to ensure journalStore implements SweepJournal.
+var _ SweepJournal = (*journalStore)(nil)
+
+// NewSweepJournal returns a SweepJournal that stores its entries in the given
+// database.
+func NewSweepJournal(db kvdb.Backend) (SweepJournal, error) {
+	err := kvdb.Update(db, func(tx kvdb.RwTx) error {
+		_, err := tx.CreateTopLevelBucket(journalBucketKey)
+		return err
+	}, func() {})
+	if err != nil {
+		return nil, err
+	}
+
+	return &journalStore{db: db}, nil
+}
+
+// AddEntry appends the entry to the journal and assigns its index.
+//
+// NOTE: Part of the SweepJournal interface.
+func (j *journalStore) AddEntry(entry *JournalEntry) error {
+	var b bytes.Buffer
+	if err := serializeJournalEntry(&b, entry); err != nil {
+		return err
+	}
+
+	var index uint64
+	err := kvdb.Update(j.db, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(journalBucketKey)
+		if bucket == nil {
+			return errNoJournalBucket
+		}
+
+		var err error
+		index, err = bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		var key [8]byte
+		byteOrder.PutUint64(key[:], index)
+
+		return bucket.Put(key[:], b.Bytes())
+	}, func() {
+		index = 0
+	})
+	if err != nil {
+		return err
+	}
+
+	entry.Index = index
+
+	return nil
+}
+
+// QueryJournal returns the journal entries selected by the query.
+//
+// NOTE: Part of the SweepJournal interface.
+func (j *journalStore) QueryJournal(query JournalQuery) (*JournalSlice,
+	error) {
+
+	var slice *JournalSlice
+	err := kvdb.View(j.db, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(journalBucketKey)
+		if bucket == nil {
+			return errNoJournalBucket
+		}
+
+		var start [8]byte
+		byteOrder.PutUint64(start[:], query.IndexOffset+1)
+
+		cursor := bucket.ReadCursor()
+		k, v := cursor.Seek(start[:])
+		for ; k != nil; k, v = cursor.Next() {
+			if query.MaxEntries != 0 &&
+				len(slice.Entries) >= int(query.MaxEntries) {
+
+				break
+			}
+
+			index := byteOrder.Uint64(k)
+			slice.LastIndexOffset = index
+
+			entry, err := deserializeJournalEntry(
+				bytes.NewReader(v),
+			)
+			if err != nil {
+				return err
+			}
+			entry.Index = index
+
+			if query.matches(entry) {
+				slice.Entries = append(slice.Entries, entry)
+			}
+		}
+
+		return nil
+	}, func() {
+		slice = &JournalSlice{LastIndexOffset: query.IndexOffset}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return slice, nil
+}
+
+// OutcomeSummary aggregates the journal entries of a single outcome.
+type OutcomeSummary struct {
+	// Count is the number of sweep attempts with the outcome.
+	Count int
+
+	// TotalFee is the sum of the fees of these attempts.
+	TotalFee btcutil.Amount
+}
+
+// JournalSummary aggregates journal entries for fee efficiency analysis and
+// conflict alerting.
+type JournalSummary struct {
+	// Outcomes summarizes the entries by their outcome.
+	Outcomes map[SweepOutcome]OutcomeSummary
+
+	// Conflicts counts how often each input was part of a conflicted
+	// sweep attempt. Inputs that are conflicted repeatedly may be the
+	// target of a pinning attack.
+	Conflicts map[wire.OutPoint]int
+}
+
+// FeePaid returns the fees paid by the confirmed sweeps.
+func (s *JournalSummary) FeePaid() btcutil.Amount {
+	return s.Outcomes[SweepConfirmed].TotalFee
+}
+
+// RepeatedConflicts returns the inputs that were part of at least the given
+// number of conflicted sweep attempts.
+func (s *JournalSummary) RepeatedConflicts(threshold int) []wire.OutPoint {
+	var inputs []wire.OutPoint
+	for op, count := range s.Conflicts {
+		if count >= threshold {
+			inputs = append(inputs, op)
+		}
+	}
+
+	return inputs
+}
+
+// SummarizeJournal aggregates the given journal entries.
+func SummarizeJournal(entries []*JournalEntry) *JournalSummary {
+	summary := &JournalSummary{
+		Outcomes:  make(map[SweepOutcome]OutcomeSummary),
+		Conflicts: make(map[wire.OutPoint]int),
+	}
+
+	for _, entry := range entries {
+		outcome := summary.Outcomes[entry.Outcome]
+		outcome.Count++
+		outcome.TotalFee += entry.Fee
+		summary.Outcomes[entry.Outcome] = outcome
+
+		if entry.Outcome != SweepConflicted {
+			continue
+		}
+
+		for _, op := range entry.Inputs {
+			summary.Conflicts[op]++
+		}
+	}
+
+	return summary
+}
@@ -0,0 +1,119 @@
+package sweep
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSweepJournal asserts that journal entries are persisted, survive a
+// restart and can be queried with pagination and filters.
+func TestSweepJournal(t *testing.T) {
+	t.Parallel()
+
+	cdb, err := channeldb.MakeTestDB(t)
+	require.NoError(t, err)
+
+	journal, err := NewSweepJournal(cdb)
+	require.NoError(t, err)
+
+	pinned := wire.OutPoint{Hash: chainhash.Hash{1}, Index: 1}
+	other := wire.OutPoint{Hash: chainhash.Hash{2}, Index: 2}
+
+	entries := []*JournalEntry{
+		{
+			Txid:        chainhash.Hash{3},
+			Outcome:     SweepReplaced,
+			Fee:         1_000,
+			FeeRate:     chainfee.SatPerKWeight(2_500),
+			Inputs:      []wire.OutPoint{pinned, other},
+			Height:      100,
+			RelatedTxid: fn.Some(chainhash.Hash{4}),
+		},
+		{
+			Txid:        chainhash.Hash{4},
+			Outcome:     SweepConflicted,
+			Fee:         2_000,
+			FeeRate:     chainfee.SatPerKWeight(5_000),
+			Inputs:      []wire.OutPoint{pinned},
+			Height:      101,
+			RelatedTxid: fn.Some(chainhash.Hash{5}),
+			Reason:      ErrRemoteSpend.Error(),
+		},
+		{
+			Txid:    chainhash.Hash{6},
+			Outcome: SweepConflicted,
+			Fee:     3_000,
+			Inputs:  []wire.OutPoint{pinned},
+			Height:  102,
+			Reason:  "transaction rejected: output already spent",
+		},
+		{
+			Txid:    chainhash.Hash{7},
+			Outcome: SweepConfirmed,
+			Fee:     1_500,
+			FeeRate: chainfee.SatPerKWeight(3_000),
+			Inputs:  []wire.OutPoint{other},
+			Height:  103,
+		},
+	}
+
+	for i, entry := range entries {
+		entry.Timestamp = time.Unix(0, int64(i+1)*int64(time.Second))
+		require.NoError(t, journal.AddEntry(entry))
+		require.EqualValues(t, i+1, entry.Index)
+	}
+
+	// Recreate the journal to assert the entries were persisted.
+	journal, err = NewSweepJournal(cdb)
+	require.NoError(t, err)
+
+	slice, err := journal.QueryJournal(JournalQuery{})
+	require.NoError(t, err)
+	require.Equal(t, entries, slice.Entries)
+	require.EqualValues(t, 4, slice.LastIndexOffset)
+
+	// Paginate through the journal.
+	slice, err = journal.QueryJournal(JournalQuery{MaxEntries: 3})
+	require.NoError(t, err)
+	require.Equal(t, entries[:3], slice.Entries)
+	require.EqualValues(t, 3, slice.LastIndexOffset)
+
+	slice, err = journal.QueryJournal(JournalQuery{
+		IndexOffset: slice.LastIndexOffset,
+		MaxEntries:  3,
+	})
+	require.NoError(t, err)
+	require.Equal(t, entries[3:], slice.Entries)
+	require.EqualValues(t, 4, slice.LastIndexOffset)
+
+	// Filter by outcome and height.
+	slice, err = journal.QueryJournal(JournalQuery{
+		Outcomes:  fn.NewSet(SweepConflicted, SweepConfirmed),
+		EndHeight: 102,
+	})
+	require.NoError(t, err)
+	require.Equal(t, entries[1:3], slice.Entries)
+
+	slice, err = journal.QueryJournal(JournalQuery{StartHeight: 103})
+	require.NoError(t, err)
+	require.Equal(t, entries[3:], slice.Entries)
+
+	// Finally, summarize all entries.
+	summary := SummarizeJournal(entries)
+	require.Equal(t, OutcomeSummary{
+		Count:    2,
+		TotalFee: 5_000,
+	}, summary.Outcomes[SweepConflicted])
+	require.Equal(t, btcutil.Amount(1_500), summary.FeePaid())
+	require.Equal(
+		t, []wire.OutPoint{pinned}, summary.RepeatedConflicts(2),
+	)
+}
@@ -356,3 +356,33 @@ func (*MockAuxSweeper) NotifyBroadcast(_ *BumpRequest, _ *wire.MsgTx,
 
 	return nil
 }
+
+// MockPackagePublisher is a mock implementation of the PackagePublisher
+// interface.
+type MockPackagePublisher struct {
+	mock.Mock
+}
+
+// Compile-time constraint to ensure MockPackagePublisher implements
+// PackagePublisher.
+var _ PackagePublisher = (*MockPackagePublisher)(nil)
+
+// PackageRelaySupport returns the package relay features supported by the
+// chain backend.
+func (m *MockPackagePublisher) PackageRelaySupport() (PackageRelaySupport,
+	error) {
+
+	args := m.Called()
+
+	return args.Get(0).(PackageRelaySupport), args.Error(1)
+}
+
+// PublishPackage publishes the child tx along with its unconfirmed parent as
+// a 1-parent-1-child package.
+func (m *MockPackagePublisher) PublishPackage(parent,
+	child *wire.MsgTx) error {
+
+	args := m.Called(parent, child)
+
+	return args.Error(0)
+}
@@ -0,0 +1,230 @@
+package sweep
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/rpcclient"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnwallet"
+)
+
+const (
+	// minPackageRelayVersion is the first bitcoind version (v28.0) that
+	// accepts packages through `submitpackage` on all networks, relays
+	// 1-parent-1-child packages and treats v3 transactions as TRUC
+	// transactions.
+	minPackageRelayVersion = 280000
+
+	// TRUCVersion is the version of TRUC (topologically restricted until
+	// confirmation) transactions as defined in BIP 431.
+	TRUCVersion = 3
+
+	// maxTRUCChildWeight is the maximum weight of a TRUC transaction that
+	// spends an unconfirmed TRUC parent, which is 1,000 vbytes.
+	maxTRUCChildWeight = 4_000
+)
+
+var (
+	// ErrPackageRelayUnsupported is returned when a package is published
+	// to a backend that doesn't support package relay.
+	ErrPackageRelayUnsupported = errors.New("package relay not supported " +
+		"by backend")
+
+	// ErrTRUCChildTooLarge is returned when a sweeping tx that spends an
+	// unconfirmed TRUC parent exceeds the weight limit of a TRUC child.
+	ErrTRUCChildTooLarge = errors.New("TRUC child tx exceeds max weight")
+)
+
+// PackageRelaySupport describes the package relay features supported by the
+// chain backend.
+type PackageRelaySupport struct {
+	// Package is true if the backend accepts 1-parent-1-child packages,
+	// which allows a child to pay for a parent that is below the mempool
+	// min fee.
+	Package bool
+
+	// TRUC is true if the backend relays TRUC (v3) transactions.
+	TRUC bool
+}
+
+// PackagePublisher is implemented by chain backends that can publish a child
+// tx along with its unconfirmed parent as a package.
+type PackagePublisher interface {
+	// PackageRelaySupport returns the package relay features supported by
+	// the chain backend.
+	PackageRelaySupport() (PackageRelaySupport, error)
+
+	// PublishPackage publishes the child tx along with its unconfirmed
+	// parent as a 1-parent-1-child package.
+	PublishPackage(parent, child *wire.MsgTx) error
+}
+
+// BitcoindPackagePublisher is a PackagePublisher that uses the
+// `submitpackage` RPC of a bitcoind backend.
+type BitcoindPackagePublisher struct {
+	client *rpcclient.Client
+
+	// support caches the features of the backend once they've been
+	// detected.
+	support *PackageRelaySupport
+	mu      sync.Mutex
+}
+
+// NewBitcoindPackagePublisher creates a new package publisher that uses the
+// given bitcoind RPC client.
+func NewBitcoindPackagePublisher(
+	client *rpcclient.Client) *BitcoindPackagePublisher {
+
+	return &BitcoindPackagePublisher{
+		client: client,
+	}
+}
+
+// PackageRelaySupport returns the package relay features supported by the
+// bitcoind backend, which are derived from its version.
+//
+// NOTE: This is part of the PackagePublisher interface.
+func (b *BitcoindPackagePublisher) PackageRelaySupport() (PackageRelaySupport,
+	error) {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.support != nil {
+		return *b.support, nil
+	}
+
+	info, err := b.client.GetNetworkInfo()
+	if err != nil {
+		return PackageRelaySupport{}, fmt.Errorf("unable to get "+
+			"bitcoind version: %w", err)
+	}
+
+	supported := info.Version >= minPackageRelayVersion
+	b.support = &PackageRelaySupport{
+		Package: supported,
+		TRUC:    supported,
+	}
+
+	log.Infof("Detected bitcoind version %v, package relay supported: %v",
+		info.Version, supported)
+
+	return *b.support, nil
+}
+
+// submitPackageResult is the result of the `submitpackage` RPC.
+type submitPackageResult struct {
+	// PackageMsg is "success" if the package was accepted.
+	PackageMsg string `json:"package_msg"`
+
+	// TxResults holds the result of each tx, keyed by its wtxid.
+	TxResults map[string]struct {
+		TxID  string `json:"txid"`
+		Error string `json:"error"`
+	} `json:"tx-results"`
+}
+
+// PublishPackage publishes the child tx along with its unconfirmed parent
+// using the `submitpackage` RPC.
+//
+// NOTE: This is part of the PackagePublisher interface.
+func (b *BitcoindPackagePublisher) PublishPackage(parent,
+	child *wire.MsgTx) error {
+
+	support, err := b.PackageRelaySupport()
+	if err != nil {
+		return err
+	}
+	if !support.Package {
+		return ErrPackageRelayUnsupported
+	}
+
+	rawTxs := make([]string, 0, 2)
+	for _, tx := range []*wire.MsgTx{parent, child} {
+		var buf bytes.Buffer
+		if err := tx.Serialize(&buf); err != nil {
+			return err
+		}
+
+		rawTxs = append(rawTxs, hex.EncodeToString(buf.Bytes()))
+	}
+
+	param, err := json.Marshal(rawTxs)
+	if err != nil {
+		return err
+	}
+
+	resp, err := b.client.RawRequest(
+		"submitpackage", []json.RawMessage{param},
+	)
+	if err != nil {
+		return fmt.Errorf("submitpackage failed: %w", err)
+	}
+
+	var result submitPackageResult
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return fmt.Errorf("unable to parse submitpackage result: %w",
+			err)
+	}
+
+	if result.PackageMsg == "success" {
+		return nil
+	}
+
+	// The package was rejected, we'll map the error of the first
+	// rejected tx so the caller can bump the fee if it was too low.
+	for _, txResult := range result.TxResults {
+		if txResult.Error == "" {
+			continue
+		}
+
+		err := rpcclient.MapRPCErr(errors.New(txResult.Error))
+		if errors.Is(err, rpcclient.ErrMempoolMinFeeNotMet) ||
+			errors.Is(err, rpcclient.ErrInsufficientFee) {
+
+			return fmt.Errorf("%w: %w", lnwallet.ErrMempoolFee, err)
+		}
+
+		return fmt.Errorf("package rejected: tx %v: %w", txResult.TxID,
+			err)
+	}
+
+	return fmt.Errorf("package rejected: %v", result.PackageMsg)
+}
+
+// A compile-time check to ensure BitcoindPackagePublisher implements the
+// PackagePublisher interface.
+var _ PackagePublisher = (*BitcoindPackagePublisher)(nil)
+
+// unconfParentHash returns the hash of the single unconfirmed parent of the
+// given inputs. False is returned if no input has an unconfirmed parent, or
+// the inputs spend from more than one unconfirmed parent, in which case they
+// can't be published as a 1-parent-1-child package.
+func unconfParentHash(inputs []input.Input) (chainhash.Hash, bool) {
+	var (
+		parent chainhash.Hash
+		found  bool
+	)
+	for _, inp := range inputs {
+		if inp.UnconfParent() == nil {
+			continue
+		}
+
+		op := inp.OutPoint()
+		if found && op.Hash != parent {
+			return chainhash.Hash{}, false
+		}
+
+		parent = op.Hash
+		found = true
+	}
+
+	return parent, found
+}
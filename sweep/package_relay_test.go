@@ -0,0 +1,157 @@
+package sweep
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// createTestAnchorInput creates an anchor input that spends from the given
+// unconfirmed parent.
+func createTestAnchorInput(parent chainhash.Hash) input.Input {
+	inp := input.MakeBaseInput(
+		&wire.OutPoint{Hash: parent},
+		input.CommitmentAnchor,
+		&input.SignDescriptor{
+			Output: &wire.TxOut{
+				Value: 330,
+			},
+		},
+		0, &input.TxInfo{
+			Fee:    1000,
+			Weight: 1000,
+		},
+	)
+
+	return &inp
+}
+
+// TestUnconfParentHash checks that only inputs that spend from a single
+// unconfirmed parent can be published as a package.
+func TestUnconfParentHash(t *testing.T) {
+	t.Parallel()
+
+	parent := chainhash.Hash{1}
+	otherParent := chainhash.Hash{2}
+
+	confirmedInp := createTestInput(1000, input.WitnessKeyHash)
+	anchorInp := createTestAnchorInput(parent)
+
+	// No input spends from an unconfirmed parent.
+	_, ok := unconfParentHash([]input.Input{&confirmedInp})
+	require.False(t, ok)
+
+	// A single unconfirmed parent is found.
+	hash, ok := unconfParentHash([]input.Input{&confirmedInp, anchorInp})
+	require.True(t, ok)
+	require.Equal(t, parent, hash)
+
+	// Multiple unconfirmed parents can't be published as 1-parent-1-child
+	// package.
+	_, ok = unconfParentHash([]input.Input{
+		anchorInp, createTestAnchorInput(otherParent),
+	})
+	require.False(t, ok)
+}
+
+// TestTxPublisherPublishPackage checks that a tx that spends from an
+// unconfirmed parent is published as a package when the backend supports it,
+// and that we fall back to publishing it on its own otherwise.
+func TestTxPublisherPublishPackage(t *testing.T) {
+	t.Parallel()
+
+	parentTx := wire.NewMsgTx(2)
+	parentTx.AddTxOut(&wire.TxOut{Value: 330})
+	parent := parentTx.TxHash()
+
+	childTx := wire.NewMsgTx(2)
+	childTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Hash: parent},
+	})
+
+	req := &BumpRequest{
+		Inputs: []input.Input{createTestAnchorInput(parent)},
+	}
+
+	errPackage := errors.New("package error")
+
+	testCases := []struct {
+		name          string
+		support       PackageRelaySupport
+		packageErr    error
+		expectPublish bool
+		expectedErr   error
+	}{
+		{
+			name: "package published",
+			support: PackageRelaySupport{
+				Package: true,
+			},
+			expectPublish: true,
+		},
+		{
+			name: "package fee too low",
+			support: PackageRelaySupport{
+				Package: true,
+			},
+			packageErr:  lnwallet.ErrMempoolFee,
+			expectedErr: lnwallet.ErrMempoolFee,
+		},
+		{
+			name: "package rejected falls back",
+			support: PackageRelaySupport{
+				Package: true,
+			},
+			packageErr:    errPackage,
+			expectPublish: true,
+		},
+		{
+			name:          "package relay unsupported",
+			expectPublish: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tp, m := createTestPublisher(t)
+
+			publisher := &MockPackagePublisher{}
+			t.Cleanup(func() {
+				publisher.AssertExpectations(t)
+			})
+			tp.cfg.PackagePublisher = fn.Some[PackagePublisher](
+				publisher,
+			)
+
+			publisher.On("PackageRelaySupport").Return(
+				tc.support, nil,
+			)
+
+			if tc.support.Package {
+				m.wallet.On("FetchTx", parent).Return(
+					parentTx, nil,
+				)
+				publisher.On(
+					"PublishPackage", parentTx, childTx,
+				).Return(tc.packageErr).Once()
+			}
+
+			if tc.expectPublish {
+				m.wallet.On(
+					"PublishTransaction", childTx,
+					mock.Anything,
+				).Return(nil).Once()
+			}
+
+			err := tp.publish(req, childTx)
+			require.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
@@ -260,6 +261,18 @@ type PendingInputResponse struct {
 	DeadlineHeight uint32
 }
 
+// WalletBudget returns the part of the input's budget that isn't covered by
+// the input's own value, and that therefore may be paid from wallet funds.
+// This is the case for anchors, whose value is far below the fees needed to
+// CPFP their commitment transaction.
+func (p *PendingInputResponse) WalletBudget() btcutil.Amount {
+	if p.Params.Budget <= p.Amount {
+		return 0
+	}
+
+	return p.Params.Budget - p.Amount
+}
+
 // updateReq is an internal message we'll use to represent an external caller's
 // intent to update the sweep parameters of a given input.
 type updateReq struct {
@@ -290,6 +303,11 @@ type UtxoSweeper struct {
 	// UtxoSweeper is attempting to sweep.
 	pendingSweepsReqs chan *pendingSweepsReq
 
+	// feeReportReqs is a channel that will be sent requests by external
+	// callers in order to evaluate the pending inputs at alternative fee
+	// rates.
+	feeReportReqs chan *feeReportReq
+
 	// updateReqs is a channel that will be sent requests by external
 	// callers who wish to bump the fee rate of a given input.
 	updateReqs chan *updateReq
@@ -300,8 +318,6 @@ type UtxoSweeper struct {
 
 	currentOutputScript fn.Option[lnwallet.AddrWithKey]
 
-	relayFeeRate chainfee.SatPerKWeight
-
 	quit chan struct{}
 	wg   sync.WaitGroup
 
@@ -312,6 +328,12 @@ type UtxoSweeper struct {
 	// bumpResultChan is a channel that receives broadcast results from the
 	// TxPublisher.
 	bumpResultChan chan *BumpResult
+
+	// sweepAttempts tracks the sweeping txns published since startup whose
+	// outcome hasn't been recorded in the journal yet.
+	//
+	// NOTE: must only be accessed from the collector goroutine.
+	sweepAttempts map[chainhash.Hash]*JournalEntry
 }
 
 // UtxoSweeperConfig contains dependencies of UtxoSweeper.
@@ -362,6 +384,10 @@ type UtxoSweeperConfig struct {
 	// NoDeadlineConfTarget is the conf target to use when sweeping
 	// non-time-sensitive outputs.
 	NoDeadlineConfTarget uint32
+
+	// Journal records the outcome of every sweep attempt. If nil, the
+	// outcomes are not recorded.
+	Journal SweepJournal
 }
 
 // Result is the struct that is pushed through the result channel. Callers can
@@ -393,9 +419,11 @@ func New(cfg *UtxoSweeperConfig) *UtxoSweeper {
 		spendChan:         make(chan *chainntnfs.SpendDetail),
 		updateReqs:        make(chan *updateReq),
 		pendingSweepsReqs: make(chan *pendingSweepsReq),
+		feeReportReqs:     make(chan *feeReportReq),
 		quit:              make(chan struct{}),
 		inputs:            make(InputsMap),
 		bumpResultChan:    make(chan *BumpResult, 100),
+		sweepAttempts:     make(map[chainhash.Hash]*JournalEntry),
 	}
 }
 
@@ -407,10 +435,6 @@ func (s *UtxoSweeper) Start() error {
 
 	log.Info("Sweeper starting")
 
-	// Retrieve relay fee for dust limit calculation. Assume that this will
-	// not change from here on.
-	s.relayFeeRate = s.cfg.FeeEstimator.RelayFeePerKW()
-
 	// We need to register for block epochs and retry sweeping every block.
 	// We should get a notification with the current best block immediately
 	// if we don't provide any epoch. We'll wait for that in the collector.
@@ -459,9 +483,10 @@ func (s *UtxoSweeper) Start() error {
 }
 
 // RelayFeePerKW returns the minimum fee rate required for transactions to be
-// relayed.
+// relayed. The fee estimator tracks the fee floor of the backend, so the
+// returned fee rate reflects its current mempool min fee.
 func (s *UtxoSweeper) RelayFeePerKW() chainfee.SatPerKWeight {
-	return s.relayFeeRate
+	return s.cfg.FeeEstimator.RelayFeePerKW()
 }
 
 // Stop stops sweeper from listening to block epochs and constructing sweep
@@ -666,6 +691,12 @@ func (s *UtxoSweeper) collector(blockEpochs <-chan *chainntnfs.BlockEpoch) {
 		case req := <-s.pendingSweepsReqs:
 			s.handlePendingSweepsReq(req)
 
+		// A new external request has been received to report the
+		// outcome of sweeping the pending inputs at alternative fee
+		// rates.
+		case req := <-s.feeReportReqs:
+			s.handleFeeReportReq(req)
+
 		// A new external request has been received to bump the fee rate
 		// of a given input.
 		case req := <-s.updateReqs:
@@ -1378,6 +1409,10 @@ func (s *UtxoSweeper) handleInputSpent(spend *chainntnfs.SpendDetail) {
 			inputsSpent[txIn.PreviousOutPoint] = struct{}{}
 		}
 
+		// Our sweeping txns spending any of these inputs have lost
+		// against the third party tx.
+		s.recordConflictedSweeps(inputsSpent, spendHash)
+
 		log.Debugf("Attempting to remove descendant txns invalidated "+
 			"by (txid=%v): %v", spendingTx.TxHash(),
 			spew.Sdump(spendingTx))
@@ -1393,10 +1428,143 @@ func (s *UtxoSweeper) handleInputSpent(spend *chainntnfs.SpendDetail) {
 			lnutils.SpewLogClosure(spend.SpendingTx))
 	}
 
+	// A sweep tx usually spends multiple inputs, so we only record its
+	// confirmation for the first spend notification, which is the one
+	// that still finds pending inputs.
+	if isOurTx && s.hasPendingInputs(spend.SpendingTx) {
+		s.recordSweepOutcome(
+			spendHash, spend.SpendingTx, SweepConfirmed,
+			fn.None[chainhash.Hash](), nil,
+		)
+	}
+
 	// We now use the spending tx to update the state of the inputs.
 	s.markInputsSwept(spend.SpendingTx, isOurTx)
 }
 
+// hasPendingInputs returns true if any of the inputs spent by the given tx is
+// known to the sweeper and hasn't been terminated yet.
+func (s *UtxoSweeper) hasPendingInputs(tx *wire.MsgTx) bool {
+	for _, txIn := range tx.TxIn {
+		input, ok := s.inputs[txIn.PreviousOutPoint]
+		if ok && !input.terminated() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// trackSweepAttempt remembers the published sweeping tx so its outcome can be
+// recorded in the journal once it's known.
+func (s *UtxoSweeper) trackSweepAttempt(tx *wire.MsgTx, fee btcutil.Amount,
+	feeRate chainfee.SatPerKWeight) {
+
+	if s.cfg.Journal == nil {
+		return
+	}
+
+	inputs := make([]wire.OutPoint, 0, len(tx.TxIn))
+	for _, txIn := range tx.TxIn {
+		inputs = append(inputs, txIn.PreviousOutPoint)
+	}
+
+	txid := tx.TxHash()
+	s.sweepAttempts[txid] = &JournalEntry{
+		Txid:    txid,
+		Fee:     fee,
+		FeeRate: feeRate,
+		Inputs:  inputs,
+	}
+}
+
+// recordConflictedSweeps records all tracked sweep attempts that spend any of
+// the given inputs as conflicted by the given third party tx.
+func (s *UtxoSweeper) recordConflictedSweeps(
+	inputsSpent map[wire.OutPoint]struct{}, spendHash chainhash.Hash) {
+
+	for txid, attempt := range s.sweepAttempts {
+		for _, op := range attempt.Inputs {
+			if _, ok := inputsSpent[op]; !ok {
+				continue
+			}
+
+			s.recordSweepOutcome(
+				txid, nil, SweepConflicted,
+				fn.Some(spendHash), ErrRemoteSpend,
+			)
+
+			break
+		}
+	}
+}
+
+// recordSweepOutcome adds the outcome of the sweeping tx to the journal. If
+// the tx hasn't been tracked since startup, the entry is derived from the
+// given tx and its record in the sweeper store. Failing to record the outcome
+// is logged but not treated as fatal.
+func (s *UtxoSweeper) recordSweepOutcome(txid chainhash.Hash, tx *wire.MsgTx,
+	outcome SweepOutcome, related fn.Option[chainhash.Hash], reason error) {
+
+	if s.cfg.Journal == nil {
+		return
+	}
+
+	entry, ok := s.sweepAttempts[txid]
+	switch {
+	case ok:
+		delete(s.sweepAttempts, txid)
+
+	case tx != nil:
+		entry = &JournalEntry{Txid: txid}
+		for _, txIn := range tx.TxIn {
+			entry.Inputs = append(
+				entry.Inputs, txIn.PreviousOutPoint,
+			)
+		}
+
+		record, err := s.cfg.Store.GetTx(txid)
+		if err == nil {
+			entry.Fee = btcutil.Amount(record.Fee)
+			entry.FeeRate = chainfee.SatPerKWeight(record.FeeRate)
+		}
+
+	default:
+		log.Debugf("Skipped recording outcome %v of untracked sweep "+
+			"tx %v", outcome, txid)
+
+		return
+	}
+
+	entry.Outcome = outcome
+	entry.RelatedTxid = related
+	entry.Height = s.currentHeight
+	entry.Timestamp = time.Now()
+	if reason != nil {
+		entry.Reason = reason.Error()
+	}
+
+	if err := s.cfg.Journal.AddEntry(entry); err != nil {
+		log.Errorf("Unable to record outcome %v of sweep tx %v: %v",
+			outcome, txid, err)
+
+		return
+	}
+
+	log.Debugf("Recorded outcome %v of sweep tx %v", outcome, txid)
+}
+
+// QueryJournal returns the recorded sweep outcomes matching the query.
+func (s *UtxoSweeper) QueryJournal(query JournalQuery) (*JournalSlice,
+	error) {
+
+	if s.cfg.Journal == nil {
+		return nil, ErrNoSweepJournal
+	}
+
+	return s.cfg.Journal.QueryJournal(query)
+}
+
 // markInputsSwept marks all inputs swept by the spending transaction as swept.
 // It will also notify all the subscribers of this input.
 func (s *UtxoSweeper) markInputsSwept(tx *wire.MsgTx, isOurTx bool) {
@@ -1644,6 +1812,16 @@ func (s *UtxoSweeper) handleBumpEventTxFailed(r *BumpResult) error {
 		outpoints = append(outpoints, inp.PreviousOutPoint)
 	}
 
+	// A tx rejected due to a conflicting tx lost against it, any other
+	// failure means the attempt was given up.
+	outcome := SweepAbandoned
+	if errors.Is(err, lnwallet.ErrDoubleSpend) {
+		outcome = SweepConflicted
+	}
+	s.recordSweepOutcome(
+		tx.TxHash(), tx, outcome, fn.None[chainhash.Hash](), err,
+	)
+
 	// TODO(yy): should we also remove the failed tx from db?
 	s.markInputsPublishFailed(outpoints)
 
@@ -1691,6 +1869,12 @@ func (s *UtxoSweeper) handleBumpEventTxReplaced(r *BumpResult) error {
 		return err
 	}
 
+	// Record the replacement in the journal and track the new tx instead.
+	s.recordSweepOutcome(
+		oldTxid, oldTx, SweepReplaced, fn.Some(tr.Txid), nil,
+	)
+	s.trackSweepAttempt(newTx, r.Fee, r.FeeRate)
+
 	// Mark the inputs as published using the replacing tx.
 	return s.markInputsPublished(tr, r.Tx.TxIn)
 }
@@ -1715,6 +1899,8 @@ func (s *UtxoSweeper) handleBumpEventTxPublished(r *BumpResult) error {
 	log.Debugf("Published sweep tx %v, num_inputs=%v, height=%v",
 		tx.TxHash(), len(tx.TxIn), s.currentHeight)
 
+	s.trackSweepAttempt(tx, r.Fee, r.FeeRate)
+
 	// If there's no error, remove the output script. Otherwise keep it so
 	// that it can be reused for the next transaction and causes no address
 	// inflation.
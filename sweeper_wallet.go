@@ -2,6 +2,7 @@ package lnd
 
 import (
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/lnwallet"
 )
 
@@ -9,16 +10,31 @@ import (
 // sweeper's Wallet interface.
 type sweeperWallet struct {
 	*lnwallet.LightningWallet
+
+	// publishTx is used to publish transactions instead of the wallet's
+	// PublishTransaction, which allows additionally submitting them to
+	// external broadcasters.
+	publishTx func(*wire.MsgTx, string) error
 }
 
 // newSweeperWallet creates a new sweeper wallet from the given
-// LightningWallet.
-func newSweeperWallet(w *lnwallet.LightningWallet) *sweeperWallet {
+// LightningWallet that publishes transactions using publishTx.
+func newSweeperWallet(w *lnwallet.LightningWallet,
+	publishTx func(*wire.MsgTx, string) error) *sweeperWallet {
+
 	return &sweeperWallet{
 		LightningWallet: w,
+		publishTx:       publishTx,
 	}
 }
 
+// PublishTransaction publishes the given transaction.
+func (s *sweeperWallet) PublishTransaction(tx *wire.MsgTx,
+	label string) error {
+
+	return s.publishTx(tx, label)
+}
+
 // CancelRebroadcast cancels the rebroadcast of the given transaction.
 func (s *sweeperWallet) CancelRebroadcast(txid chainhash.Hash) {
 	// For neutrino, we don't config the rebroadcaster for the wallet as it
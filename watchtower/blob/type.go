@@ -78,6 +78,20 @@ const (
 	// taproot channel commitment to a sweep address controlled by the user,
 	// and does not give the tower a reward.
 	TypeAltruistTaprootCommit = Type(FlagCommitOutputs | FlagTaprootChannel)
+
+	// TypeRewardAnchorCommit sweeps only commitment outputs from an anchor
+	// commitment to a sweep address controlled by the user, and pays a
+	// negotiated reward to the tower.
+	TypeRewardAnchorCommit = Type(
+		FlagCommitOutputs | FlagAnchorChannel | FlagReward,
+	)
+
+	// TypeRewardTaprootCommit sweeps only the commitment outputs from a
+	// taproot channel commitment to a sweep address controlled by the
+	// user, and pays a negotiated reward to the tower.
+	TypeRewardTaprootCommit = Type(
+		FlagCommitOutputs | FlagTaprootChannel | FlagReward,
+	)
 )
 
 // TypeFromChannel returns the appropriate blob Type for the given channel
@@ -104,6 +118,10 @@ func (t Type) Identifier() (string, error) {
 		return "reward", nil
 	case TypeAltruistTaprootCommit:
 		return "taproot", nil
+	case TypeRewardAnchorCommit:
+		return "reward-anchor", nil
+	case TypeRewardTaprootCommit:
+		return "reward-taproot", nil
 	default:
 		return "", fmt.Errorf("unknown blob type: %v", t)
 	}
@@ -214,6 +232,8 @@ var supportedTypes = map[Type]struct{}{
 	TypeRewardCommit:          {},
 	TypeAltruistAnchorCommit:  {},
 	TypeAltruistTaprootCommit: {},
+	TypeRewardAnchorCommit:    {},
+	TypeRewardTaprootCommit:   {},
 }
 
 // IsSupportedType returns true if the given type is supported by the package.
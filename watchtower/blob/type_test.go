@@ -31,6 +31,22 @@ var typeStringTests = []typeStringTest{
 			"FlagCommitOutputs|" +
 			"FlagReward]",
 	},
+	{
+		name: "anchor commit reward",
+		typ:  blob.TypeRewardAnchorCommit,
+		expStr: "[No-FlagTaprootChannel|" +
+			"FlagAnchorChannel|" +
+			"FlagCommitOutputs|" +
+			"FlagReward]",
+	},
+	{
+		name: "taproot commit reward",
+		typ:  blob.TypeRewardTaprootCommit,
+		expStr: "[FlagTaprootChannel|" +
+			"No-FlagAnchorChannel|" +
+			"FlagCommitOutputs|" +
+			"FlagReward]",
+	},
 	{
 		name: "unknown flag",
 		typ:  unknownFlag.Type(),
@@ -138,6 +154,16 @@ func TestSupportedTypes(t *testing.T) {
 			blob.TypeAltruistAnchorCommit)
 	}
 
+	// Assert that the reward anchor and taproot commit types are
+	// supported.
+	for _, rewardType := range []blob.Type{
+		blob.TypeRewardAnchorCommit, blob.TypeRewardTaprootCommit,
+	} {
+		if !blob.IsSupportedType(rewardType) {
+			t.Fatalf("reward type %s is not supported", rewardType)
+		}
+	}
+
 	// Assert that all claimed supported types are actually supported.
 	for _, supType := range blob.SupportedTypes() {
 		if blob.IsSupportedType(supType) {
@@ -24,6 +24,11 @@ var (
 	// ErrUnknownSweepAddrType signals that client provided an output that
 	// was not p2wkh or p2wsh.
 	ErrUnknownSweepAddrType = errors.New("sweep addr is not p2wkh or p2wsh")
+
+	// ErrUnknownRewardAddrType signals that the session's reward address
+	// is not p2wkh, p2wsh or p2tr.
+	ErrUnknownRewardAddrType = errors.New("reward addr is not p2wkh, " +
+		"p2wsh or p2tr")
 )
 
 // JusticeDescriptor contains the information required to sweep a breached
@@ -238,9 +243,23 @@ func (p *JusticeDescriptor) CreateJusticeTxn() (*wire.MsgTx, error) {
 	}
 
 	// Add our reward address to the weight estimate if the policy's blob
-	// type specifies a reward output.
+	// type specifies a reward output. The client estimates the weight
+	// based on the type of the reward script, so we need to do the same.
 	if p.SessionInfo.Policy.BlobType.Has(blob.FlagReward) {
-		weightEstimate.AddP2WKHOutput()
+		rewardScript := p.SessionInfo.RewardAddress
+		switch txscript.GetScriptClass(rewardScript) {
+		case txscript.WitnessV0PubKeyHashTy:
+			weightEstimate.AddP2WKHOutput()
+
+		case txscript.WitnessV0ScriptHashTy:
+			weightEstimate.AddP2WSHOutput()
+
+		case txscript.WitnessV1TaprootTy:
+			weightEstimate.AddP2TROutput()
+
+		default:
+			return nil, ErrUnknownRewardAddrType
+		}
 	}
 
 	// Assemble the breached to-local output from the justice descriptor and
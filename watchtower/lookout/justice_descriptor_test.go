@@ -275,9 +275,14 @@ func testJusticeDescriptor(t *testing.T, blobType blob.Type) {
 			RewardRate:   900000,
 		},
 	}
+	// The tower estimates the weight of the reward output from its script
+	// type, so the reward address needs to be a valid P2WKH script.
+	rewardAddr := append(
+		[]byte{txscript.OP_0, txscript.OP_DATA_20}, makeAddrSlice(20)...,
+	)
 	sessionInfo := &wtdb.SessionInfo{
 		Policy:        policy,
-		RewardAddress: makeAddrSlice(22),
+		RewardAddress: rewardAddr,
 	}
 
 	breachInfo := &lnwallet.BreachRetribution{
@@ -1,6 +1,7 @@
 package wtclient
 
 import (
+	"bytes"
 	"fmt"
 
 	"github.com/btcsuite/btcd/blockchain"
@@ -350,5 +351,109 @@ func (t *backupTask) craftSessionPayload(
 		return hint, nil, err
 	}
 
+	// Before handing the blob off to the tower, we'll make sure that the
+	// tower will actually be able to use it. We do so by decrypting the
+	// blob exactly as the tower would, and validating the reconstructed
+	// witnesses against the justice transaction we just signed.
+	err = t.validateEncryptedBlob(
+		encBlob, key, justiceTxn, prevOutputFetcher, inputIndex,
+	)
+	if err != nil {
+		log.Errorf("Justice kit for %v failed local validation, "+
+			"refusing to upload: %v", t.id, err)
+
+		return hint, nil, err
+	}
+
 	return hint, encBlob, nil
 }
+
+// validateEncryptedBlob decrypts the passed encrypted justice kit and asserts
+// that the witnesses it produces are able to spend the breached outputs in the
+// given justice transaction. This catches any corruption of the justice kit,
+// e.g. a script mismatch or an invalid signature, before it is uploaded to the
+// tower, rather than at the time the tower needs to act on it.
+func (t *backupTask) validateEncryptedBlob(encBlob []byte, key blob.BreachKey,
+	justiceTxn *wire.MsgTx, prevOutputFetcher txscript.PrevOutputFetcher,
+	inputIndex map[wire.OutPoint]int) error {
+
+	kit, err := blob.Decrypt(key, encBlob, t.blobType)
+	if err != nil {
+		return fmt.Errorf("%w: unable to decrypt: %v",
+			ErrInvalidJusticeKit, err)
+	}
+
+	if !bytes.Equal(kit.SweepAddress(), t.sweepPkScript) {
+		return fmt.Errorf("%w: sweep address mismatch",
+			ErrInvalidJusticeKit)
+	}
+
+	toLocalWitnessType, err := t.commitmentType.ToLocalWitnessType()
+	if err != nil {
+		return err
+	}
+
+	// We'll work on a copy of the justice transaction, so we can attach
+	// the reconstructed witnesses without modifying the original.
+	validationTx := justiceTxn.Copy()
+	hashCache := txscript.NewTxSigHashes(validationTx, prevOutputFetcher)
+
+	for _, inp := range t.inputs() {
+		var (
+			pkScript *txscript.PkScript
+			witness  wire.TxWitness
+			sequence = inp.BlocksToMaturity()
+		)
+
+		// Reconstruct the spend info for this input from the justice
+		// kit, just like the tower would.
+		if inp.WitnessType() == toLocalWitnessType {
+			pkScript, witness, err = kit.ToLocalOutputSpendInfo()
+		} else {
+			if !kit.HasCommitToRemoteOutput() {
+				return fmt.Errorf("%w: missing to-remote "+
+					"output", ErrInvalidJusticeKit)
+			}
+
+			pkScript, witness, sequence, err =
+				kit.ToRemoteOutputSpendInfo()
+		}
+		if err != nil {
+			return fmt.Errorf("%w: unable to reconstruct spend "+
+				"info for %v: %v", ErrInvalidJusticeKit,
+				inp.OutPoint(), err)
+		}
+
+		// The script derived from the justice kit must match the
+		// output on the revoked commitment.
+		prevOut := inp.SignDesc().Output
+		if !bytes.Equal(pkScript.Script(), prevOut.PkScript) {
+			return fmt.Errorf("%w: script mismatch for %v",
+				ErrInvalidJusticeKit, inp.OutPoint())
+		}
+
+		i := inputIndex[inp.OutPoint()]
+		if validationTx.TxIn[i].Sequence != sequence {
+			return fmt.Errorf("%w: sequence mismatch for %v",
+				ErrInvalidJusticeKit, inp.OutPoint())
+		}
+		validationTx.TxIn[i].Witness = witness
+
+		// Finally, ensure the reconstructed witness, and hence the
+		// signature within the justice kit, is valid.
+		vm, err := txscript.NewEngine(
+			prevOut.PkScript, validationTx, i,
+			txscript.StandardVerifyFlags, nil, hashCache,
+			prevOut.Value, prevOutputFetcher,
+		)
+		if err != nil {
+			return err
+		}
+		if err := vm.Execute(); err != nil {
+			return fmt.Errorf("%w: invalid witness for %v: %v",
+				ErrInvalidJusticeKit, inp.OutPoint(), err)
+		}
+	}
+
+	return nil
+}
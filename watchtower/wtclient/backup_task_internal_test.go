@@ -171,13 +171,20 @@ func genTaskTest(
 				ControlBlock:  ctrlBytes,
 			}
 		} else {
+			toLocalScript, _ := input.CommitScriptToSelf(
+				csvDelay, toLocalPK, revPK,
+			)
+			pkScript, _ := input.WitnessScriptHash(toLocalScript)
+
 			toLocalSignDesc = &input.SignDescriptor{
 				KeyDesc: keychain.KeyDescriptor{
 					KeyLocator: revKeyLoc,
 					PubKey:     revPK,
 				},
+				WitnessScript: toLocalScript,
 				Output: &wire.TxOut{
-					Value: toLocalAmt,
+					Value:    toLocalAmt,
+					PkScript: pkScript,
 				},
 				HashType: txscript.SigHashAll,
 			}
@@ -227,13 +234,31 @@ func genTaskTest(
 				ControlBlock: ctrlBytes,
 			}
 		} else {
+			// Anchor channels use a CSV encumbered p2wsh to-remote
+			// output, while legacy channels use a plain p2wkh.
+			var toRemoteScript, pkScript []byte
+			if chanType.HasAnchors() {
+				ws, _ := input.CommitScriptToRemoteConfirmed(
+					toRemotePK,
+				)
+				pkScript, _ = input.WitnessScriptHash(ws)
+				toRemoteScript = ws
+			} else {
+				pkScript, _ = input.CommitScriptUnencumbered(
+					toRemotePK,
+				)
+				toRemoteScript = pkScript
+			}
+
 			toRemoteSignDesc = &input.SignDescriptor{
 				KeyDesc: keychain.KeyDescriptor{
 					KeyLocator: toRemoteKeyLoc,
 					PubKey:     toRemotePK,
 				},
+				WitnessScript: toRemoteScript,
 				Output: &wire.TxOut{
-					Value: toRemoteAmt,
+					Value:    toRemoteAmt,
+					PkScript: pkScript,
 				},
 				HashType: txscript.SigHashAll,
 			}
@@ -688,6 +713,50 @@ func testBackupTask(t *testing.T, test backupTaskTest) {
 	require.Equal(t, expectedKit, jKit)
 }
 
+// TestBackupTaskInvalidJusticeKit asserts that a justice kit that would not
+// allow the tower to sweep the breached outputs is caught before it is handed
+// off for upload.
+func TestBackupTaskInvalidJusticeKit(t *testing.T) {
+	t.Parallel()
+
+	chanTypes := []channeldb.ChannelType{
+		channeldb.SingleFunderTweaklessBit,
+		channeldb.AnchorOutputsBit,
+		channeldb.SimpleTaprootFeatureBit,
+	}
+
+	for _, chanType := range chanTypes {
+		test := genTaskTest(
+			t, "", 100, 200000, 100000, blobTypeCommitNoReward,
+			1000, nil, 0, 0, nil, chanType,
+		)
+
+		task := newBackupTask(wtdb.BackupID{
+			ChanID:       test.chanID,
+			CommitHeight: test.breachInfo.RevokedStateNum,
+		}, test.expSweepScript)
+
+		getBreachInfo := func(id lnwire.ChannelID,
+			commitHeight uint64) (*lnwallet.BreachRetribution,
+			channeldb.ChannelType, error) {
+
+			return test.breachInfo, test.chanType, nil
+		}
+		err := task.bindSession(test.session, getBreachInfo)
+		require.NoError(t, err)
+
+		// Swap out the revocation key used to derive the scripts in
+		// the justice kit, which no longer match the breached outputs.
+		_, otherPK := btcec.PrivKeyFromBytes(toLocalPrivBytes)
+		keyRing := *test.breachInfo.KeyRing
+		keyRing.RevocationKey = otherPK
+		task.breachInfo.KeyRing = &keyRing
+
+		_, _, err = task.craftSessionPayload(test.signer)
+		require.ErrorIs(t, err, ErrInvalidJusticeKit)
+	}
+}
+
 func makeSig(i int) lnwire.Sig {
 	var sigBytes [64]byte
 	binary.BigEndian.PutUint64(sigBytes[:8], uint64(i))
@@ -44,6 +44,11 @@ const (
 	// DefaultMaxTasksInMemQueue is the maximum number of items to be held
 	// in the in-memory queue.
 	DefaultMaxTasksInMemQueue = 2000
+
+	// DefaultMaxInFlightUpdates is the default number of state updates
+	// that a session queue will send to a tower before waiting for their
+	// acks.
+	DefaultMaxInFlightUpdates = 16
 )
 
 // genSessionFilter constructs a filter that can be used to select sessions only
@@ -88,6 +93,10 @@ type RegisteredTower struct {
 	// ActiveSessionCandidate determines whether the watchtower is currently
 	// being considered for new sessions.
 	ActiveSessionCandidate bool
+
+	// Stats holds the in-memory statistics of the backups that were
+	// uploaded to the watchtower since startup.
+	Stats TowerStats
 }
 
 // BreachRetributionBuilder is a function that can be used to construct a
@@ -1058,6 +1067,8 @@ func (c *client) newSessionQueue(s *ClientSession,
 		DB:                     c.cfg.DB,
 		MinBackoff:             c.cfg.MinBackoff,
 		MaxBackoff:             c.cfg.MaxBackoff,
+		MaxInFlightUpdates:     c.cfg.MaxInFlightUpdates,
+		Stats:                  c.stats,
 		Log:                    c.log,
 		BuildBreachRetribution: c.cfg.BuildBreachRetribution,
 		TaskPipeline:           c.pipeline,
@@ -1376,6 +1387,7 @@ func (c *client) registeredTowers(towers []*wtdb.Tower,
 			Tower:                  tower,
 			Sessions:               towerSessions[tower.ID],
 			ActiveSessionCandidate: isActive,
+			Stats:                  c.stats.getTowerStats(tower.ID),
 		})
 	}
 
@@ -1398,6 +1410,7 @@ func (c *client) lookupTower(tower *wtdb.Tower,
 		Tower:                  tower,
 		Sessions:               towerSessions,
 		ActiveSessionCandidate: c.candidateTowers.IsActive(tower.ID),
+		Stats:                  c.stats.getTowerStats(tower.ID),
 	}, nil
 }
 
@@ -133,8 +133,11 @@ func (m *mockNet) AuthDial(local keychain.SingleKeyECDH,
 		Port: 36723,
 	}
 
+	// The connection is buffered so that the client can pipeline its
+	// state updates, like it would over a real network connection.
 	localPeer, remotePeer := wtmock.NewMockConn(
-		localPk, netAddr.IdentityKey, localAddr, netAddr.Address, 0,
+		localPk, netAddr.IdentityKey, localAddr, netAddr.Address,
+		wtclient.DefaultMaxInFlightUpdates,
 	)
 
 	m.mu.RLock()
@@ -362,8 +365,8 @@ func (c *mockChannel) createRemoteCommitTx(t *testing.T) {
 
 	commitKeyRing := &lnwallet.CommitmentKeyRing{
 		RevocationKey: c.revPK,
-		ToRemoteKey:   c.toLocalPK,
-		ToLocalKey:    c.toRemotePK,
+		ToRemoteKey:   c.toRemotePK,
+		ToLocalKey:    c.toLocalPK,
 	}
 
 	retribution := &lnwallet.BreachRetribution{
@@ -1411,6 +1414,53 @@ var clientTests = []clientTest{
 			h.server.waitForUpdates(hints, waitTime)
 		},
 	},
+	{
+		// Asserts that state updates that are pipelined to the tower
+		// are all acked, and that the uploads are recorded in the
+		// tower's stats.
+		name: "pipelined backups recorded in tower stats",
+		cfg: harnessCfg{
+			localBalance:  localBalance,
+			remoteBalance: remoteBalance,
+			policy: wtpolicy.Policy{
+				TxPolicy:   defaultTxPolicy,
+				MaxUpdates: 100,
+			},
+		},
+		fn: func(h *testHarness) {
+			const (
+				numUpdates = 50
+				chanID     = 0
+			)
+
+			// Generate the retributions that will be backed up.
+			hints := h.advanceChannelN(chanID, numUpdates)
+
+			// Back up the states and wait for them to arrive at
+			// the tower.
+			h.backupStates(chanID, 0, numUpdates, nil)
+			h.server.waitForUpdates(hints, waitTime)
+
+			// All updates should eventually be recorded as acked
+			// by the tower.
+			err := wait.Predicate(func() bool {
+				resp, err := h.clientMgr.LookupTower(
+					h.server.addr.IdentityKey,
+				)
+				if err != nil {
+					return false
+				}
+
+				tower := resp[blob.TypeAltruistTaprootCommit]
+				stats := tower.Stats
+
+				return stats.NumUpdatesAcked == numUpdates &&
+					stats.NumBatches > 0 &&
+					stats.UpdatesPerSecond() > 0
+			}, waitTime)
+			require.NoError(h.t, err)
+		},
+	},
 	{
 		// Asserts that the client is able to support multiple links.
 		name: "multiple link backup",
@@ -2225,6 +2275,49 @@ var clientTests = []clientTest{
 			server2.waitForUpdates(hints[numUpdates/2:], waitTime)
 		},
 	},
+	{
+		// Asserts that the client doesn't negotiate sessions for
+		// taproot channels with towers that don't advertise support
+		// for them, and that it uses other towers instead.
+		name: "skip towers without taproot support",
+		cfg: harnessCfg{
+			localBalance:  localBalance,
+			remoteBalance: remoteBalance,
+			policy: wtpolicy.Policy{
+				TxPolicy:   defaultTxPolicy,
+				MaxUpdates: 5,
+			},
+			noServerStart: true,
+		},
+		fn: func(h *testHarness) {
+			const (
+				numUpdates = 5
+				chanID     = 0
+			)
+
+			// Start the main tower without taproot support.
+			h.server.cfg.DisableTaproot = true
+			h.server.start()
+			h.t.Cleanup(h.server.stop)
+
+			// Back up a few states. None of them should make it to
+			// the main tower, as the client won't negotiate a
+			// session with it.
+			hints := h.advanceChannelN(chanID, numUpdates)
+			h.backupStates(chanID, 0, numUpdates, nil)
+			h.server.waitForUpdates(nil, waitTime)
+
+			// Now we add a tower that supports taproot channels.
+			server2 := newServerHarness(
+				h.t, h.net, towerAddr2Str, nil,
+			)
+			server2.start()
+			h.addTower(server2.addr)
+
+			// All the states should be backed up to the new tower.
+			server2.waitForUpdates(hints, waitTime)
+		},
+	},
 	{
 		// Show that if a client switches to a new tower _after_ backup
 		// tasks have been bound to the session with the first old tower
@@ -39,4 +39,15 @@ var (
 	// create a new session with a tower with a session key that has already
 	// been used in the past.
 	ErrSessionKeyAlreadyUsed = errors.New("session key already used")
+
+	// ErrInvalidJusticeKit signals that a justice kit failed the local
+	// validation performed before uploading it to a tower, meaning that
+	// the tower would not be able to use it to sweep the breached outputs.
+	ErrInvalidJusticeKit = errors.New("invalid justice kit")
+
+	// ErrTowerFeaturesUnsupported signals that a tower doesn't advertise
+	// the features required by the client's session policy, e.g. because
+	// it predates support for taproot channels.
+	ErrTowerFeaturesUnsupported = errors.New("tower doesn't support the " +
+		"features of the session policy")
 )
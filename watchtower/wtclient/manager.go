@@ -154,6 +154,13 @@ type Config struct {
 	// MaxTasksInMemQueue is the maximum number of backup tasks that should
 	// be kept in-memory. Any more tasks will overflow to disk.
 	MaxTasksInMemQueue uint64
+
+	// MaxInFlightUpdates is the maximum number of state updates that are
+	// sent to a tower over a session's connection before waiting for
+	// their acks. Pipelining updates avoids paying a round trip per
+	// backup, which matters most for towers reached over tor. If the value
+	// is zero, the default will be used instead.
+	MaxInFlightUpdates uint16
 }
 
 // Manager manages the various tower clients that are active. A client is
@@ -196,6 +203,12 @@ func NewManager(config *Config, policies ...wtpolicy.Policy) (*Manager, error) {
 		cfg.WriteTimeout = DefaultWriteTimeout
 	}
 
+	// Set the number of in-flight updates to the default if none was
+	// provided.
+	if cfg.MaxInFlightUpdates == 0 {
+		cfg.MaxInFlightUpdates = DefaultMaxInFlightUpdates
+	}
+
 	chanInfos, err := cfg.DB.FetchChanInfos()
 	if err != nil {
 		return nil, err
@@ -518,6 +531,7 @@ func (m *Manager) Stats() ClientStats {
 		stats := client.getStats()
 		resp.NumTasksAccepted += stats.NumTasksAccepted
 		resp.NumTasksIneligible += stats.NumTasksIneligible
+		resp.NumTasksInvalid += stats.NumTasksInvalid
 		resp.NumTasksPending += stats.NumTasksPending
 		resp.NumSessionsAcquired += stats.NumSessionsAcquired
 		resp.NumSessionsExhausted += stats.NumSessionsExhausted
@@ -324,6 +324,17 @@ tryNextCandidate:
 				continue
 			}
 
+			// If the tower can't protect the channels of our
+			// policy, retrying it won't help. We'll move on to the
+			// next candidate without backing off, so that other
+			// towers can be used instead.
+			if errors.Is(err, ErrTowerFeaturesUnsupported) {
+				n.log.Infof("Skipping tower=%x for session "+
+					"negotiation: %v", towerPub, err)
+
+				goto tryNextCandidate
+			}
+
 			// An unexpected error occurred, update our backoff.
 			updateBackoff()
 
@@ -366,6 +377,11 @@ func (n *sessionNegotiator) createSession(tower *Tower, keyIndex uint32) error {
 		case errors.Is(err, ErrSessionKeyAlreadyUsed):
 			return err
 
+		// All addresses of the tower advertise the same features, so
+		// there's no need to try the others.
+		case errors.Is(err, ErrTowerFeaturesUnsupported):
+			return err
+
 		case errors.Is(err, ErrPermanentTowerFailure):
 			// TODO(conner): report to iterator? can then be reset
 			// with restart
@@ -429,7 +445,23 @@ func (n *sessionNegotiator) tryAddress(sessionKey keychain.SingleKeyECDH,
 		return err
 	}
 
+	// Make sure that the tower supports the features that our sessions
+	// rely on, such as the protection of taproot channels. Towers that
+	// predate these features don't advertise them, so we'll skip them
+	// rather than negotiating a session that they can't act on.
 	policy := n.cfg.Policy
+	remoteFeatures := lnwire.NewFeatureVector(
+		remoteInit.ConnFeatures, wtwire.FeatureNames,
+	)
+	for _, bit := range policy.FeatureBits() {
+		if remoteFeatures.HasFeature(bit) {
+			continue
+		}
+
+		return fmt.Errorf("%w: missing %s", ErrTowerFeaturesUnsupported,
+			wtwire.FeatureNames[bit])
+	}
+
 	createSession := &wtwire.CreateSession{
 		BlobType:     policy.BlobType,
 		MaxUpdates:   policy.MaxUpdates,
@@ -459,8 +491,15 @@ func (n *sessionNegotiator) tryAddress(sessionKey keychain.SingleKeyECDH,
 
 	switch createSessionReply.Code {
 	case wtwire.CodeOK:
-		// TODO(conner): validate reward address
+		// Make sure the reward script returned by the tower is one we
+		// can sign justice transactions for before accepting the
+		// session's terms.
 		rewardPkScript := createSessionReply.Data
+		err = policy.ValidateRewardScript(rewardPkScript)
+		if err != nil {
+			return fmt.Errorf("tower returned unusable reward "+
+				"script %x: %w", rewardPkScript, err)
+		}
 
 		sessionID := wtdb.NewSessionIDFromPubKey(sessionKey.PubKey())
 		dbClientSession := &wtdb.ClientSession{
@@ -2,6 +2,7 @@ package wtclient
 
 import (
 	"container/list"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -86,6 +87,13 @@ type sessionQueueConfig struct {
 	// to MaxBackoff.
 	MaxBackoff time.Duration
 
+	// MaxInFlightUpdates is the maximum number of state updates that are
+	// sent to the tower before waiting for their acks.
+	MaxInFlightUpdates uint16
+
+	// Stats records the upload statistics of the session's tower.
+	Stats *clientStats
+
 	// Log specifies the desired log output, which should be prefixed by the
 	// client type, e.g. anchor or legacy.
 	Log btclog.Logger
@@ -367,12 +375,31 @@ func (q *sessionQueue) sessionManager() {
 	}
 }
 
+// inFlightUpdate is a state update that was sent to the tower, but for which
+// no reply has been received yet.
+type inFlightUpdate struct {
+	// stateUpdate is the state update that was sent.
+	stateUpdate *wtwire.StateUpdate
+
+	// isPending is true if the update was taken from the pending queue
+	// rather than the commit queue.
+	isPending bool
+
+	// backupID identifies the backup carried by the update.
+	backupID wtdb.BackupID
+}
+
 // drainBackups attempts to send all pending updates in the queue to the tower.
+// Once the tower has acked the first update of a connection, up to
+// MaxInFlightUpdates state updates are pipelined over the connection before
+// waiting for the tower's acks, so that a round trip isn't paid for every
+// backup.
 func (q *sessionQueue) drainBackups() {
 	var (
 		conn      wtserver.Peer
 		err       error
 		towerAddr = q.tower.Addresses.Peek()
+		start     = time.Now()
 	)
 
 	for {
@@ -414,23 +441,121 @@ func (q *sessionQueue) drainBackups() {
 	}
 	defer conn.Close()
 
-	// Begin draining the queue of pending state updates. Before the first
-	// update is sent, we will precede it with an Init message. If the first
-	// is successful, subsequent updates can be streamed without sending an
-	// Init.
-	for sendInit := true; ; sendInit = false {
-		// Generate the next state update to upload to the tower. This
-		// method will first proceed in dequeuing committed updates
-		// before attempting to dequeue any pending updates.
-		stateUpdate, isPending, backupID, err := q.nextStateUpdate()
-		if err != nil {
-			q.log.Errorf("SessionQueue(%v) unable to get next "+
-				"state update: %v", q.ID(), err)
+	// Before the first update is sent, we will precede it with an Init
+	// message. If the tower accepts it, the updates can be streamed
+	// without sending another Init.
+	if err := q.sendInit(conn); err != nil {
+		q.log.Errorf("SessionQueue(%s) unable to init connection: %v",
+			q.ID(), err)
+
+		q.increaseBackoff()
+		select {
+		case <-time.After(q.retryBackoff):
+		case <-q.quit:
+		}
+		return
+	}
+
+	maxInFlight := int(q.cfg.MaxInFlightUpdates)
+	if maxInFlight == 0 {
+		maxInFlight = 1
+	}
+
+	var (
+		inFlight []*inFlightUpdate
+		sentLast bool
+		numAcked int
+	)
+
+	// Record the throughput of this batch once we're done with the
+	// connection, whether or not all updates made it to the tower.
+	defer func() {
+		if numAcked == 0 || q.cfg.Stats == nil {
 			return
 		}
 
-		// Now, send the state update to the tower and wait for a reply.
-		err = q.sendStateUpdate(conn, stateUpdate, sendInit, isPending)
+		q.cfg.Stats.batchUploaded(
+			q.tower.ID, numAcked, time.Since(start),
+		)
+	}()
+
+	// Begin draining the queue of pending state updates.
+	for {
+		// Only a single update is sent until the tower has acked one
+		// over this connection. If an earlier connection failed with
+		// updates in flight, the tower may have applied some of them
+		// already, in which case it releases the connection after
+		// rejecting the first one.
+		window := maxInFlight
+		if numAcked == 0 {
+			window = 1
+		}
+
+		// Send state updates until either the window of in-flight
+		// updates is full, or we've sent the last update in the
+		// queue.
+		for !sentLast && len(inFlight) < window {
+			// Always apply a small delay between sends, which makes
+			// the unit tests more reliable.
+			if len(inFlight) > 0 || numAcked > 0 {
+				select {
+				case <-time.After(time.Millisecond):
+				case <-q.quit:
+					return
+				}
+			}
+
+			// Generate the next state update to upload to the
+			// tower. This method will first proceed in dequeuing
+			// committed updates before attempting to dequeue any
+			// pending updates. Updates that are already in flight
+			// are skipped.
+			update, err := q.nextStateUpdate(len(inFlight))
+			switch {
+			// The task's justice kit was invalid and the task has
+			// been dropped from the queue. Move on to the next
+			// update, if there is one.
+			case errors.Is(err, ErrInvalidJusticeKit):
+				sentLast = !q.hasUnsentUpdates(len(inFlight))
+				continue
+
+			case err != nil:
+				q.log.Errorf("SessionQueue(%v) unable to get "+
+					"next state update: %v", q.ID(), err)
+				return
+			}
+
+			err = q.cfg.SendMessage(conn, update.stateUpdate)
+			if err != nil {
+				q.log.Errorf("SessionQueue(%s) unable to send "+
+					"state update: %v", q.ID(), err)
+
+				q.increaseBackoff()
+				select {
+				case <-time.After(q.retryBackoff):
+				case <-q.quit:
+				}
+				return
+			}
+
+			inFlight = append(inFlight, update)
+			sentLast = update.stateUpdate.IsComplete == 1
+		}
+
+		// If all updates were acked after the last one in the queue
+		// was sent, we'll exit and continue once more tasks are added
+		// to the queue. We'll also clear any accumulated backoff as
+		// this batch was able to be sent reliably.
+		if len(inFlight) == 0 {
+			q.resetBackoff()
+			return
+		}
+
+		// Now, wait for the reply to the oldest in-flight update. The
+		// tower processes the updates in order, so the replies arrive
+		// in the order that the updates were sent.
+		update := inFlight[0]
+		towerApplied, err := q.processStateUpdateReply(conn, update)
 		if err != nil {
 			q.log.Errorf("SessionQueue(%s) unable to send state "+
 				"update: %v", q.ID(), err)
@@ -443,39 +568,43 @@ func (q *sessionQueue) drainBackups() {
 			return
 		}
 
+		inFlight = inFlight[1:]
+		numAcked++
+
 		q.log.Infof("SessionQueue(%s) uploaded %v seqnum=%d",
-			q.ID(), backupID, stateUpdate.SeqNum)
+			q.ID(), update.backupID, update.stateUpdate.SeqNum)
+
+		// If the tower had already applied the update, it has released
+		// the connection. Any queued updates that it applied as well
+		// are acked right away, the rest is sent over a new
+		// connection.
+		if towerApplied != 0 {
+			n, err := q.ackAppliedUpdates(towerApplied)
+			numAcked += n
+			if err != nil {
+				q.log.Errorf("SessionQueue(%s) unable to ack "+
+					"applied updates: %v", q.ID(), err)
+				return
+			}
 
-		// If the last task was backed up successfully, we'll exit and
-		// continue once more tasks are added to the queue. We'll also
-		// clear any accumulated backoff as this batch was able to be
-		// sent reliably.
-		if stateUpdate.IsComplete == 1 {
 			q.resetBackoff()
 			return
 		}
-
-		// Always apply a small delay between sends, which makes the
-		// unit tests more reliable. If we were requested to back off,
-		// when we will do so.
-		select {
-		case <-time.After(time.Millisecond):
-		case <-q.quit:
-			return
-		}
 	}
 }
 
-// nextStateUpdate returns the next wtwire.StateUpdate to upload to the tower.
-// If any committed updates are present, this method will reconstruct the state
-// update from the committed update using the current last applied value found
-// in the database. Otherwise, it will select the next pending update, craft the
-// payload, and commit an update before returning the state update to send. The
-// boolean value in the response is true if the state update is taken from the
-// pending queue, allowing the caller to remove the update from either the
-// commit or pending queue if the update is successfully acked.
-func (q *sessionQueue) nextStateUpdate() (*wtwire.StateUpdate, bool,
-	wtdb.BackupID, error) {
+// nextStateUpdate returns the next wtwire.StateUpdate to upload to the tower,
+// skipping the given number of updates at the front of the queues that are
+// already in flight. If any committed updates are present, this method will
+// reconstruct the state update from the committed update using the current
+// last applied value found in the database. Otherwise, it will select the next
+// pending update, craft the payload, and commit an update before returning the
+// state update to send. The isPending value in the response is true if the
+// state update is taken from the pending queue, allowing the caller to remove
+// the update from either the commit or pending queue if the update is
+// successfully acked.
+func (q *sessionQueue) nextStateUpdate(numInFlight int) (*inFlightUpdate,
+	error) {
 
 	var (
 		seqNum    uint16
@@ -485,20 +614,32 @@ func (q *sessionQueue) nextStateUpdate() (*wtwire.StateUpdate, bool,
 	)
 
 	q.queueCond.L.Lock()
+	numCommitted := q.commitQueue.Len()
+	numQueued := numCommitted + q.pendingQueue.Len()
+
+	// If this is the last item in the queues, we will use the IsComplete
+	// flag in the StateUpdate to signal that the tower can release the
+	// connection after replying to free up resources.
+	isLast = numInFlight == numQueued-1
+
 	switch {
 
-	// If the commit queue is non-empty, parse the next committed update.
-	case q.commitQueue.Len() > 0:
+	// There's nothing left to send that isn't already in flight.
+	case numInFlight >= numQueued:
+		q.queueCond.L.Unlock()
+		return nil, fmt.Errorf("no state update to send after %d "+
+			"in-flight updates", numInFlight)
+
+	// If the commit queue still holds updates that aren't in flight,
+	// parse the next committed update.
+	case numInFlight < numCommitted:
 		next := q.commitQueue.Front()
+		for i := 0; i < numInFlight; i++ {
+			next = next.Next()
+		}
 
 		update = next.Value.(wtdb.CommittedUpdate)
 		seqNum = update.SeqNum
-
-		// If this is the last item in the commit queue and no items
-		// exist in the pending queue, we will use the IsComplete flag
-		// in the StateUpdate to signal that the tower can release the
-		// connection after replying to free up resources.
-		isLast = q.commitQueue.Len() == 1 && q.pendingQueue.Len() == 0
 		q.queueCond.L.Unlock()
 
 		q.log.Debugf("SessionQueue(%s) reprocessing committed state "+
@@ -510,26 +651,32 @@ func (q *sessionQueue) nextStateUpdate() (*wtwire.StateUpdate, bool,
 		isPending = true
 
 		// Determine the current sequence number to apply for this
-		// pending update.
-		seqNum = q.seqNum + 1
+		// pending update. Any pending updates that are in flight have
+		// already been assigned the sequence numbers that precede it.
+		numPendingInFlight := numInFlight - numCommitted
+		seqNum = q.seqNum + uint16(numPendingInFlight) + 1
 
 		// Obtain the next task from the queue.
 		next := q.pendingQueue.Front()
+		for i := 0; i < numPendingInFlight; i++ {
+			next = next.Next()
+		}
 		task := next.Value.(*backupTask)
-
-		// If this is the last item in the pending queue, we will use
-		// the IsComplete flag in the StateUpdate to signal that the
-		// tower can release the connection after replying to free up
-		// resources.
-		isLast = q.pendingQueue.Len() == 1
 		q.queueCond.L.Unlock()
 
 		hint, encBlob, err := task.craftSessionPayload(q.cfg.Signer)
+		if errors.Is(err, ErrInvalidJusticeKit) {
+			// Retrying the task would produce the same justice kit,
+			// so it's dropped from the queue to not block the
+			// updates behind it.
+			q.dropInvalidTask(next, err)
+
+			return nil, err
+		}
 		if err != nil {
-			// TODO(conner): mark will not send
 			err := fmt.Errorf("unable to craft session payload: %w",
 				err)
-			return nil, false, wtdb.BackupID{}, err
+			return nil, err
 		}
 		// TODO(conner): special case other obscure errors
 
@@ -562,7 +709,7 @@ func (q *sessionQueue) nextStateUpdate() (*wtwire.StateUpdate, bool,
 		// TODO(conner): mark failed/reschedule
 		err := fmt.Errorf("unable to commit state update for "+
 			"%v seqnum=%d: %v", update.BackupID, seqNum, err)
-		return nil, false, wtdb.BackupID{}, err
+		return nil, err
 	}
 
 	stateUpdate := &wtwire.StateUpdate{
@@ -577,78 +724,131 @@ func (q *sessionQueue) nextStateUpdate() (*wtwire.StateUpdate, bool,
 		stateUpdate.IsComplete = 1
 	}
 
-	return stateUpdate, isPending, update.BackupID, nil
+	return &inFlightUpdate{
+		stateUpdate: stateUpdate,
+		isPending:   isPending,
+		backupID:    update.BackupID,
+	}, nil
 }
 
-// sendStateUpdate sends a wtwire.StateUpdate to the watchtower and processes
-// the ACK before returning. If sendInit is true, this method will first send
-// the localInit message and verify that the tower supports our required feature
-// bits. And error is returned if any part of the send fails. The boolean return
-// variable indicates whether we should back off before attempting to send the
-// next state update.
-func (q *sessionQueue) sendStateUpdate(conn wtserver.Peer,
-	stateUpdate *wtwire.StateUpdate, sendInit, isPending bool) error {
+// hasUnsentUpdates returns true if the queues hold more updates than the given
+// number of in-flight updates.
+func (q *sessionQueue) hasUnsentUpdates(numInFlight int) bool {
+	q.queueCond.L.Lock()
+	defer q.queueCond.L.Unlock()
 
+	return q.commitQueue.Len()+q.pendingQueue.Len() > numInFlight
+}
+
+// dropInvalidTask removes the given element of the pending queue, whose task
+// failed to produce a valid justice kit, and marks the backup as ineligible so
+// that it isn't retried.
+func (q *sessionQueue) dropInvalidTask(next *list.Element, err error) {
+	q.queueCond.L.Lock()
+	q.pendingQueue.Remove(next)
+	q.queueCond.L.Unlock()
+
+	//nolint:forcetypeassert
+	task := next.Value.(*backupTask)
+
+	q.log.Errorf("SessionQueue(%s) dropping %v with invalid justice "+
+		"kit: %v", q.ID(), task.id, err)
+
+	if q.cfg.Stats != nil {
+		q.cfg.Stats.taskInvalid()
+	}
+
+	err = q.cfg.DB.MarkBackupIneligible(
+		task.id.ChanID, task.id.CommitHeight,
+	)
+	if err != nil {
+		q.log.Errorf("SessionQueue(%s) unable to mark %v ineligible: "+
+			"%v", q.ID(), task.id, err)
+	}
+}
+
+// sendInit sends the localInit message to the watchtower and verifies that the
+// tower supports our required feature bits. This must be done before any
+// state update is sent over the connection.
+func (q *sessionQueue) sendInit(conn wtserver.Peer) error {
 	towerAddr := &lnwire.NetAddress{
 		IdentityKey: conn.RemotePub(),
 		Address:     conn.RemoteAddr(),
 	}
 
-	// If this is the first message being sent to the tower, we must send an
-	// Init message to establish that server supports the features we
-	// require.
-	if sendInit {
-		// Send Init to tower.
-		err := q.cfg.SendMessage(conn, q.localInit)
-		if err != nil {
-			return err
-		}
-
-		// Receive Init from tower.
-		remoteMsg, err := q.cfg.ReadMessage(conn)
-		if err != nil {
-			return err
-		}
-
-		remoteInit, ok := remoteMsg.(*wtwire.Init)
-		if !ok {
-			return fmt.Errorf("watchtower %s responded with %T "+
-				"to Init", towerAddr, remoteMsg)
-		}
-
-		// Validate Init.
-		err = q.localInit.CheckRemoteInit(
-			remoteInit, wtwire.FeatureNames,
-		)
-		if err != nil {
-			return err
-		}
+	// Send Init to tower.
+	err := q.cfg.SendMessage(conn, q.localInit)
+	if err != nil {
+		return err
 	}
 
-	// Send StateUpdate to tower.
-	err := q.cfg.SendMessage(conn, stateUpdate)
+	// Receive Init from tower.
+	remoteMsg, err := q.cfg.ReadMessage(conn)
 	if err != nil {
 		return err
 	}
 
+	remoteInit, ok := remoteMsg.(*wtwire.Init)
+	if !ok {
+		return fmt.Errorf("watchtower %s responded with %T to Init",
+			towerAddr, remoteMsg)
+	}
+
+	// Validate Init.
+	return q.localInit.CheckRemoteInit(remoteInit, wtwire.FeatureNames)
+}
+
+// processStateUpdateReply reads the watchtower's reply to the given in-flight
+// state update and processes the ACK. An error is returned if the reply can't
+// be read or if the tower didn't accept the update.
+//
+// If the tower rejects the update because it has already applied it, the
+// update is acked all the same and the tower's last applied sequence number is
+// returned. This happens if an earlier connection failed while pipelined
+// updates were in flight, since the tower only tolerates a re-send of the last
+// update it applied. The caller can use the returned value to ack the other
+// queued updates that the tower has applied. Otherwise zero is returned.
+func (q *sessionQueue) processStateUpdateReply(conn wtserver.Peer,
+	update *inFlightUpdate) (uint16, error) {
+
+	towerAddr := &lnwire.NetAddress{
+		IdentityKey: conn.RemotePub(),
+		Address:     conn.RemoteAddr(),
+	}
+	stateUpdate := update.stateUpdate
+
 	// Receive StateUpdate from tower.
 	remoteMsg, err := q.cfg.ReadMessage(conn)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	stateUpdateReply, ok := remoteMsg.(*wtwire.StateUpdateReply)
 	if !ok {
-		return fmt.Errorf("watchtower %s responded with %T to "+
+		return 0, fmt.Errorf("watchtower %s responded with %T to "+
 			"StateUpdate", towerAddr, remoteMsg)
 	}
 
+	lastApplied := stateUpdateReply.LastApplied
+
 	// Process the reply from the tower.
-	switch stateUpdateReply.Code {
+	var towerApplied uint16
+	switch {
 
 	// The tower reported a successful update, validate the response and
 	// record the last applied returned.
-	case wtwire.CodeOK:
+	case stateUpdateReply.Code == wtwire.CodeOK:
+
+	// The tower has already applied this update, but the ack for it was
+	// lost along with the previous connection.
+	case stateUpdateReply.Code == wtwire.StateUpdateCodeSeqNumOutOfOrder &&
+		lastApplied >= stateUpdate.SeqNum:
+
+		q.log.Debugf("SessionQueue(%s) tower=%s already applied "+
+			"seqnum=%d, last_applied=%d", q.ID(), towerAddr,
+			stateUpdate.SeqNum, lastApplied)
+
+		towerApplied = lastApplied
 
 	// TODO(conner): handle other error cases properly, ban towers, etc.
 	default:
@@ -657,11 +857,72 @@ func (q *sessionQueue) sendStateUpdate(conn wtserver.Peer,
 			stateUpdateReply.Code, stateUpdate.SeqNum)
 		q.log.Warnf("SessionQueue(%s) unable to upload state update "+
 			"to tower=%s: %v", q.ID(), towerAddr, err)
-		return err
+		return 0, err
 	}
 
-	lastApplied := stateUpdateReply.LastApplied
-	err = q.cfg.DB.AckUpdate(q.ID(), stateUpdate.SeqNum, lastApplied)
+	return towerApplied, q.ackUpdate(update, lastApplied)
+}
+
+// ackAppliedUpdates acks the updates at the front of the queues whose sequence
+// numbers don't exceed the given last applied sequence number of the tower.
+// These updates were committed and sent over an earlier connection, which
+// failed before their acks were received. The number of acked updates is
+// returned.
+func (q *sessionQueue) ackAppliedUpdates(towerApplied uint16) (int, error) {
+	var numAcked int
+	for {
+		var update *inFlightUpdate
+
+		q.queueCond.L.Lock()
+		switch {
+		case q.commitQueue.Len() > 0:
+			next := q.commitQueue.Front()
+			committed := next.Value.(wtdb.CommittedUpdate)
+
+			update = &inFlightUpdate{
+				stateUpdate: &wtwire.StateUpdate{
+					SeqNum: committed.SeqNum,
+				},
+				backupID: committed.BackupID,
+			}
+
+		// Pending updates are assigned the sequence numbers that
+		// follow the session's current one, in order.
+		case q.pendingQueue.Len() > 0:
+			task := q.pendingQueue.Front().Value.(*backupTask)
+
+			update = &inFlightUpdate{
+				stateUpdate: &wtwire.StateUpdate{
+					SeqNum: q.seqNum + 1,
+				},
+				isPending: true,
+				backupID:  task.id,
+			}
+		}
+		q.queueCond.L.Unlock()
+
+		if update == nil || update.stateUpdate.SeqNum > towerApplied {
+			return numAcked, nil
+		}
+
+		if err := q.ackUpdate(update, towerApplied); err != nil {
+			return numAcked, err
+		}
+		numAcked++
+
+		q.log.Infof("SessionQueue(%s) uploaded %v seqnum=%d",
+			q.ID(), update.backupID, update.stateUpdate.SeqNum)
+	}
+}
+
+// ackUpdate records in the database that the tower has acked the given
+// in-flight update with the given last applied sequence number, and removes
+// the update from its queue.
+func (q *sessionQueue) ackUpdate(update *inFlightUpdate,
+	lastApplied uint16) error {
+
+	stateUpdate := update.stateUpdate
+	err := q.cfg.DB.AckUpdate(q.ID(), stateUpdate.SeqNum, lastApplied)
 	switch {
 	case err == wtdb.ErrUnallocatedLastApplied:
 		// TODO(conner): borked watchtower
@@ -687,8 +948,10 @@ func (q *sessionQueue) sendStateUpdate(conn wtserver.Peer,
 		return err
 	}
 
+	// Since the replies arrive in the order that the updates were sent,
+	// the acked update is always at the front of its queue.
 	q.queueCond.L.Lock()
-	if isPending {
+	if update.isPending {
 		// If a pending update was successfully sent, increment the
 		// sequence number and remove the item from the queue. This
 		// ensures the total number of backups in the session remains
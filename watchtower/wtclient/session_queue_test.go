@@ -0,0 +1,473 @@
+package wtclient
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lntest/wait"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/watchtower/blob"
+	"github.com/lightningnetwork/lnd/watchtower/wtdb"
+	"github.com/lightningnetwork/lnd/watchtower/wtmock"
+	"github.com/lightningnetwork/lnd/watchtower/wtpolicy"
+	"github.com/lightningnetwork/lnd/watchtower/wtserver"
+	"github.com/lightningnetwork/lnd/watchtower/wtwire"
+	"github.com/stretchr/testify/require"
+)
+
+// errConnDropped is returned by the harness when the connection to the tower
+// is dropped.
+var errConnDropped = errors.New("connection dropped")
+
+// sessionQueueHarness drives a sessionQueue against a tower whose replies are
+// scripted by the test.
+type sessionQueueHarness struct {
+	t *testing.T
+
+	db    *wtdb.ClientDB
+	q     *sessionQueue
+	stats *clientStats
+
+	// tasks holds the generated backup tasks indexed by commit height.
+	tasks map[uint64]backupTaskTest
+
+	// sent receives the messages that the session queue sends to the
+	// tower.
+	sent chan wtwire.Message
+
+	// replies holds the tower's replies that are read by the session
+	// queue. A nil message drops the connection.
+	replies chan wtwire.Message
+
+	quit chan struct{}
+}
+
+// newSessionQueueHarness creates a session queue that pipelines up to
+// maxInFlight updates. The session starts out with numCommitted committed
+// updates, and numTasks backup tasks are generated for commit heights starting
+// at 1 that can be added to the queue.
+func newSessionQueueHarness(t *testing.T, maxInFlight uint16, numTasks,
+	numCommitted int) *sessionQueueHarness {
+
+	dbCfg := &kvdb.BoltConfig{
+		DBTimeout: kvdb.DefaultDBTimeout,
+	}
+	bdb, err := wtdb.NewBoltBackendCreator(
+		true, t.TempDir(), "wtclient.db",
+	)(dbCfg)
+	require.NoError(t, err)
+
+	db, err := wtdb.OpenClientDB(bdb)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	// All tasks are generated from the same keys, so the signer of any of
+	// them is able to sign the justice transactions of the others.
+	var (
+		chanID lnwire.ChannelID
+		signer input.Signer = wtmock.NewMockSigner()
+		tasks               = make(map[uint64]backupTaskTest)
+	)
+	for i := 1; i <= numTasks; i++ {
+		test := genTaskTest(
+			t, "", uint64(i), 200000, 100000,
+			blobTypeCommitNoReward, 1000, nil, 0, 0, nil,
+			channeldb.SingleFunderTweaklessBit,
+		)
+		tasks[uint64(i)] = test
+		signer = test.signer
+	}
+	require.NoError(t, db.RegisterChannel(chanID, sweepAddr))
+
+	// Create the tower and a session with it.
+	towerSK, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	towerAddr := &lnwire.NetAddress{
+		IdentityKey: towerSK.PubKey(),
+		Address: &net.TCPAddr{
+			IP:   net.IPv4(18, 28, 243, 2),
+			Port: 9911,
+		},
+	}
+	dbTower, err := db.CreateTower(towerAddr)
+	require.NoError(t, err)
+
+	tower, err := NewTowerFromDBTower(dbTower)
+	require.NoError(t, err)
+
+	policy := wtpolicy.Policy{
+		TxPolicy: wtpolicy.TxPolicy{
+			BlobType:     blobTypeCommitNoReward,
+			SweepFeeRate: 1000,
+		},
+		MaxUpdates: 10,
+	}
+	keyIndex, err := db.NextSessionKeyIndex(
+		dbTower.ID, policy.BlobType, false,
+	)
+	require.NoError(t, err)
+
+	sessionSK, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	dbSession := &wtdb.ClientSession{
+		ID: wtdb.NewSessionIDFromPubKey(sessionSK.PubKey()),
+		ClientSessionBody: wtdb.ClientSessionBody{
+			TowerID:  dbTower.ID,
+			KeyIndex: keyIndex,
+			Policy:   policy,
+		},
+	}
+	require.NoError(t, db.CreateClientSession(dbSession))
+
+	// Commit the requested updates, which the session queue will resend
+	// before any of its pending tasks.
+	for i := 1; i <= numCommitted; i++ {
+		update := &wtdb.CommittedUpdate{
+			SeqNum: uint16(i),
+			CommittedUpdateBody: wtdb.CommittedUpdateBody{
+				BackupID: wtdb.BackupID{
+					ChanID:       chanID,
+					CommitHeight: uint64(100 + i),
+				},
+				Hint:          blob.BreachHint{byte(i)},
+				EncryptedBlob: []byte{byte(i), byte(i)},
+			},
+		}
+		_, err := db.CommitUpdate(&dbSession.ID, update)
+		require.NoError(t, err)
+	}
+	dbSession.SeqNum = uint16(numCommitted)
+
+	updates, err := db.FetchSessionCommittedUpdates(&dbSession.ID)
+	require.NoError(t, err)
+
+	taskPipeline, err := NewDiskOverflowQueue[*wtdb.BackupID](
+		db.GetDBQueue([]byte("test-namespace")), maxInMemItems, log,
+	)
+	require.NoError(t, err)
+	require.NoError(t, taskPipeline.Start())
+	t.Cleanup(func() {
+		require.NoError(t, taskPipeline.Stop())
+	})
+
+	h := &sessionQueueHarness{
+		t:       t,
+		db:      db,
+		stats:   &clientStats{},
+		tasks:   tasks,
+		sent:    make(chan wtwire.Message, 20),
+		replies: make(chan wtwire.Message, 20),
+		quit:    make(chan struct{}),
+	}
+
+	cfg := &sessionQueueConfig{
+		ClientSession: &ClientSession{
+			ID:                dbSession.ID,
+			ClientSessionBody: dbSession.ClientSessionBody,
+			Tower:             tower,
+		},
+		ChainHash: *chaincfg.TestNet3Params.GenesisHash,
+		Dial: func(keychain.SingleKeyECDH,
+			*lnwire.NetAddress) (wtserver.Peer, error) {
+
+			return wtmock.NewMockPeer(
+				nil, towerAddr.IdentityKey, towerAddr.Address,
+				0,
+			), nil
+		},
+		SendMessage: func(_ wtserver.Peer, msg wtwire.Message) error {
+			select {
+			case h.sent <- msg:
+				return nil
+			case <-h.quit:
+				return errConnDropped
+			}
+		},
+		ReadMessage: func(wtserver.Peer) (wtwire.Message, error) {
+			select {
+			case msg := <-h.replies:
+				if msg == nil {
+					return nil, errConnDropped
+				}
+
+				return msg, nil
+
+			case <-h.quit:
+				return nil, errConnDropped
+			}
+		},
+		Signer: signer,
+		BuildBreachRetribution: func(_ lnwire.ChannelID,
+			commitHeight uint64) (*lnwallet.BreachRetribution,
+			channeldb.ChannelType, error) {
+
+			test := tasks[commitHeight]
+
+			return test.breachInfo, test.chanType, nil
+		},
+		TaskPipeline:       taskPipeline,
+		DB:                 db,
+		MinBackoff:         time.Millisecond,
+		MaxBackoff:         10 * time.Millisecond,
+		MaxInFlightUpdates: maxInFlight,
+		Stats:              h.stats,
+		Log:                log,
+	}
+	h.q = newSessionQueue(cfg, updates)
+
+	t.Cleanup(func() {
+		close(h.quit)
+		require.NoError(t, h.q.Stop(false))
+	})
+
+	return h
+}
+
+// acceptTask adds the task of the given commit height to the session queue
+// and returns it.
+func (h *sessionQueueHarness) acceptTask(commitHeight uint64) *backupTask {
+	h.t.Helper()
+
+	test := h.tasks[commitHeight]
+	task := newBackupTask(wtdb.BackupID{
+		ChanID:       test.chanID,
+		CommitHeight: commitHeight,
+	}, test.expSweepScript)
+
+	_, accepted := h.q.AcceptTask(task)
+	require.True(h.t, accepted)
+
+	return task
+}
+
+// recvMsg waits for the next message that the session queue sends to the
+// tower.
+func (h *sessionQueueHarness) recvMsg() wtwire.Message {
+	h.t.Helper()
+
+	select {
+	case msg := <-h.sent:
+		return msg
+	case <-time.After(waitTime):
+		h.t.Fatalf("session queue didn't send a message")
+		return nil
+	}
+}
+
+// expectInit asserts that the session queue opens a new connection with an
+// Init message, to which the tower replies with its own Init.
+func (h *sessionQueueHarness) expectInit() {
+	h.t.Helper()
+
+	require.IsType(h.t, &wtwire.Init{}, h.recvMsg())
+
+	h.replies <- wtwire.NewInitMessage(
+		lnwire.NewRawFeatureVector(wtwire.AltruistSessionsRequired),
+		*chaincfg.TestNet3Params.GenesisHash,
+	)
+}
+
+// expectUpdate asserts that the next message sent to the tower is the state
+// update with the given sequence number and returns it.
+func (h *sessionQueueHarness) expectUpdate(seqNum uint16,
+	isComplete bool) *wtwire.StateUpdate {
+
+	h.t.Helper()
+
+	msg := h.recvMsg()
+	require.IsType(h.t, &wtwire.StateUpdate{}, msg)
+
+	//nolint:forcetypeassert
+	update := msg.(*wtwire.StateUpdate)
+	require.Equal(h.t, seqNum, update.SeqNum)
+	require.Equal(h.t, isComplete, update.IsComplete == 1)
+
+	return update
+}
+
+// ack replies to the state update with the given sequence number.
+func (h *sessionQueueHarness) ack(seqNum uint16) {
+	h.replies <- &wtwire.StateUpdateReply{
+		Code:        wtwire.CodeOK,
+		LastApplied: seqNum,
+	}
+}
+
+// waitDrained waits until both of the session queue's queues are empty and
+// asserts that all updates were acked with seqNum being the session's last
+// sequence number.
+func (h *sessionQueueHarness) waitDrained(seqNum uint16) {
+	h.t.Helper()
+
+	err := wait.Predicate(func() bool {
+		h.q.queueCond.L.Lock()
+		defer h.q.queueCond.L.Unlock()
+
+		return h.q.commitQueue.Len() == 0 &&
+			h.q.pendingQueue.Len() == 0 && h.q.seqNum == seqNum
+	}, waitTime)
+	require.NoError(h.t, err)
+
+	updates, err := h.db.FetchSessionCommittedUpdates(h.q.ID())
+	require.NoError(h.t, err)
+	require.Empty(h.t, updates)
+}
+
+// TestSessionQueueInvalidJusticeKit asserts that a task with an invalid justice
+// kit is dropped from the session queue, and doesn't block the tasks behind it
+// from being uploaded.
+func TestSessionQueueInvalidJusticeKit(t *testing.T) {
+	t.Parallel()
+
+	h := newSessionQueueHarness(t, 1, 2, 0)
+
+	// Swap out the revocation key of the first task, such that the scripts
+	// in its justice kit no longer match the breached outputs.
+	badTask := h.acceptTask(1)
+	_, otherPK := btcec.PrivKeyFromBytes(toLocalPrivBytes)
+	keyRing := *badTask.breachInfo.KeyRing
+	keyRing.RevocationKey = otherPK
+	badTask.breachInfo.KeyRing = &keyRing
+
+	h.acceptTask(2)
+	h.q.Start()
+
+	// The invalid task is skipped, so the valid task is the first and last
+	// update that is sent to the tower.
+	h.expectInit()
+	h.expectUpdate(1, true)
+
+	updates, err := h.db.FetchSessionCommittedUpdates(h.q.ID())
+	require.NoError(t, err)
+	require.Len(t, updates, 1)
+	require.EqualValues(t, 2, updates[0].BackupID.CommitHeight)
+
+	h.ack(1)
+	h.waitDrained(1)
+
+	require.Equal(t, 1, h.stats.getStatsCopy().NumTasksInvalid)
+}
+
+// TestSessionQueueResendAfterDrop asserts that committed updates that are in
+// flight when the connection drops are resent in order from the commit queue
+// once the session queue reconnects.
+func TestSessionQueueResendAfterDrop(t *testing.T) {
+	t.Parallel()
+
+	h := newSessionQueueHarness(t, 3, 0, 3)
+	h.q.Start()
+
+	// The first update of a connection is sent on its own. Once it's
+	// acked, the remaining committed updates fit into the window, so
+	// they're sent before the next ack is read.
+	h.expectInit()
+	h.expectUpdate(1, false)
+	h.ack(1)
+	sent := []*wtwire.StateUpdate{
+		h.expectUpdate(2, false),
+		h.expectUpdate(3, true),
+	}
+
+	// Drop the connection before the others are acked.
+	h.replies <- nil
+
+	// After reconnecting, the remaining committed updates are sent again
+	// in order and unchanged.
+	h.expectInit()
+	for i, update := range sent {
+		resent := h.expectUpdate(update.SeqNum, update.IsComplete == 1)
+		require.Equal(t, update.Hint, resent.Hint)
+		require.Equal(t, update.EncryptedBlob, resent.EncryptedBlob)
+		require.EqualValues(t, i+1, resent.LastApplied)
+
+		h.ack(update.SeqNum)
+	}
+
+	h.waitDrained(3)
+}
+
+// TestSessionQueueAlreadyApplied asserts that if the tower has already applied
+// the updates that were in flight when the connection dropped, the session
+// queue acks all of them once the tower rejects the first resent one.
+func TestSessionQueueAlreadyApplied(t *testing.T) {
+	t.Parallel()
+
+	h := newSessionQueueHarness(t, 3, 0, 3)
+	h.q.Start()
+
+	// Only the first update is sent until the tower replies to it.
+	h.expectInit()
+	h.expectUpdate(1, false)
+
+	// The tower has applied all three updates before, so it reports that
+	// the first one is out of order along with its last applied.
+	h.replies <- &wtwire.StateUpdateReply{
+		Code:        wtwire.StateUpdateCodeSeqNumOutOfOrder,
+		LastApplied: 3,
+	}
+
+	h.waitDrained(3)
+
+	// The batch is recorded once the session queue is done with the
+	// connection.
+	err := wait.Predicate(func() bool {
+		stats := h.stats.getTowerStats(h.q.tower.ID)
+		return stats.NumUpdatesAcked == 3
+	}, waitTime)
+	require.NoError(t, err)
+}
+
+// TestSessionQueueErrorReplyNonHead asserts that an error reply from the tower
+// to an update that isn't the oldest one in flight leaves the updates from that
+// point on in the queue, and that they're resent with the same sequence
+// numbers after reconnecting.
+func TestSessionQueueErrorReplyNonHead(t *testing.T) {
+	t.Parallel()
+
+	h := newSessionQueueHarness(t, 3, 3, 0)
+	for i := uint64(1); i <= 3; i++ {
+		h.acceptTask(i)
+	}
+	h.q.Start()
+
+	h.expectInit()
+	h.expectUpdate(1, false)
+	h.ack(1)
+	h.expectUpdate(2, false)
+	h.expectUpdate(3, true)
+
+	// Reject the second update.
+	h.replies <- &wtwire.StateUpdateReply{
+		Code:        wtwire.CodeTemporaryFailure,
+		LastApplied: 1,
+	}
+
+	// Once the session queue reconnects, only the rejected update and the
+	// one behind it are still committed.
+	h.expectInit()
+
+	updates, err := h.db.FetchSessionCommittedUpdates(h.q.ID())
+	require.NoError(t, err)
+	require.Len(t, updates, 2)
+	for i, update := range updates {
+		require.EqualValues(t, i+2, update.SeqNum)
+		require.EqualValues(t, i+2, update.BackupID.CommitHeight)
+	}
+
+	h.expectUpdate(2, false)
+	h.ack(2)
+	h.expectUpdate(3, true)
+	h.ack(3)
+	h.waitDrained(3)
+}
@@ -3,6 +3,9 @@ package wtclient
 import (
 	"fmt"
 	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/watchtower/wtdb"
 )
 
 // ClientStats is a collection of in-memory statistics of the actions the client
@@ -20,6 +23,11 @@ type ClientStats struct {
 	// exhausted watchtower sessions have failed to acknowledge.
 	NumTasksIneligible int
 
+	// NumTasksInvalid is the total number of backups that were dropped
+	// because their justice kit would not allow the tower to sweep the
+	// breached outputs.
+	NumTasksInvalid int
+
 	// NumSessionsAcquired is the total number of new sessions made to
 	// watchtowers.
 	NumSessionsAcquired int
@@ -29,12 +37,42 @@ type ClientStats struct {
 	NumSessionsExhausted int
 }
 
+// TowerStats is a collection of in-memory statistics of the backups that the
+// client has uploaded to a single tower since its creation.
+type TowerStats struct {
+	// NumUpdatesAcked is the total number of state updates that the tower
+	// has acknowledged.
+	NumUpdatesAcked int
+
+	// NumBatches is the total number of connections over which state
+	// updates were uploaded to the tower.
+	NumBatches int
+
+	// UploadTime is the total time spent uploading state updates to the
+	// tower, measured from dialing the tower until the last ack of a
+	// batch was received.
+	UploadTime time.Duration
+}
+
+// UpdatesPerSecond returns the average number of state updates that the tower
+// acknowledged per second of upload time.
+func (s TowerStats) UpdatesPerSecond() float64 {
+	if s.UploadTime <= 0 {
+		return 0
+	}
+
+	return float64(s.NumUpdatesAcked) / s.UploadTime.Seconds()
+}
+
 // clientStats wraps ClientStats with a mutex so that it's members can be
 // accessed in a thread safe manner.
 type clientStats struct {
 	mu sync.Mutex
 
 	ClientStats
+
+	// towers holds the upload statistics of each tower.
+	towers map[wtdb.TowerID]*TowerStats
 }
 
 // taskReceived increments the number of backup requests the client has received
@@ -75,6 +113,15 @@ func (s *clientStats) taskIneligible() {
 	s.NumTasksIneligible++
 }
 
+// taskInvalid increments the number of tasks that were dropped by a session
+// queue because they failed to produce a valid justice kit.
+func (s *clientStats) taskInvalid() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.NumTasksInvalid++
+}
+
 // sessionAcquired increments the number of sessions that have been successfully
 // negotiated by the client during this execution.
 func (s *clientStats) sessionAcquired() {
@@ -93,13 +140,50 @@ func (s *clientStats) sessionExhausted() {
 	s.NumSessionsExhausted++
 }
 
+// batchUploaded records that a batch of state updates was uploaded to the
+// given tower, of which numAcked were acknowledged in the given time.
+func (s *clientStats) batchUploaded(towerID wtdb.TowerID, numAcked int,
+	elapsed time.Duration) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.towers == nil {
+		s.towers = make(map[wtdb.TowerID]*TowerStats)
+	}
+
+	stats, ok := s.towers[towerID]
+	if !ok {
+		stats = &TowerStats{}
+		s.towers[towerID] = stats
+	}
+
+	stats.NumUpdatesAcked += numAcked
+	stats.NumBatches++
+	stats.UploadTime += elapsed
+}
+
+// getTowerStats returns a copy of the upload statistics of the given tower.
+func (s *clientStats) getTowerStats(towerID wtdb.TowerID) TowerStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats, ok := s.towers[towerID]
+	if !ok {
+		return TowerStats{}
+	}
+
+	return *stats
+}
+
 // String returns a human-readable summary of the client's metrics.
 func (s *clientStats) String() string {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	return fmt.Sprintf("tasks(received=%d accepted=%d ineligible=%d) "+
-		"sessions(acquired=%d exhausted=%d)", s.NumTasksPending,
-		s.NumTasksAccepted, s.NumTasksIneligible, s.NumSessionsAcquired,
+	return fmt.Sprintf("tasks(received=%d accepted=%d ineligible=%d "+
+		"invalid=%d) sessions(acquired=%d exhausted=%d)",
+		s.NumTasksPending, s.NumTasksAccepted, s.NumTasksIneligible,
+		s.NumTasksInvalid, s.NumSessionsAcquired,
 		s.NumSessionsExhausted)
 }
@@ -393,6 +393,36 @@ func (t *TowerDB) DeleteSession(target SessionID) error {
 	}, func() {})
 }
 
+// HasSessions returns true if at least one session is stored in the tower
+// database.
+func (t *TowerDB) HasSessions() (bool, error) {
+	var hasSessions bool
+	err := kvdb.View(t.db, func(tx kvdb.RTx) error {
+		sessions := tx.ReadBucket(sessionsBkt)
+		if sessions == nil {
+			return ErrUninitializedDB
+		}
+
+		err := isBucketEmpty(sessions)
+		switch {
+		case err == errBucketNotEmpty:
+			hasSessions = true
+
+		case err != nil:
+			return err
+		}
+
+		return nil
+	}, func() {
+		hasSessions = false
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return hasSessions, nil
+}
+
 // QueryMatches searches against all known state updates for any that match the
 // passed breachHints. More than one Match will be returned for a given hint if
 // they exist in the database.
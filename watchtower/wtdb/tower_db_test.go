@@ -2,12 +2,14 @@ package wtdb_test
 
 import (
 	"bytes"
+	"database/sql"
 	"encoding/binary"
 	"testing"
 
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/sqldb"
 	"github.com/lightningnetwork/lnd/watchtower"
 	"github.com/lightningnetwork/lnd/watchtower/blob"
 	"github.com/lightningnetwork/lnd/watchtower/wtdb"
@@ -636,6 +638,23 @@ func TestTowerDB(t *testing.T) {
 				return db
 			},
 		},
+		{
+			name: "sqlite",
+			init: func(t *testing.T) watchtower.DB {
+				db := sqldb.NewTestSqliteDB(t).BaseDB
+
+				createQuery := func(
+					tx *sql.Tx) wtdb.SQLTowerQueries {
+
+					return db.WithTx(tx)
+				}
+				executor := sqldb.NewTransactionExecutor(
+					db, createQuery,
+				)
+
+				return wtdb.NewSQLTowerStore(executor)
+			},
+		},
 		{
 			name: "mock",
 			init: func(t *testing.T) watchtower.DB {
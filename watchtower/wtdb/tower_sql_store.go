@@ -0,0 +1,461 @@
+package wtdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/sqldb"
+	"github.com/lightningnetwork/lnd/sqldb/sqlc"
+	"github.com/lightningnetwork/lnd/watchtower/blob"
+	"github.com/lightningnetwork/lnd/watchtower/wtpolicy"
+)
+
+// SQLTowerQueries is an interface that defines the set of operations that can
+// be executed against the tower SQL database.
+type SQLTowerQueries interface {
+	UpsertTowerSession(ctx context.Context,
+		arg sqlc.UpsertTowerSessionParams) (int64, error)
+
+	GetTowerSession(ctx context.Context,
+		sessionID []byte) (sqlc.TowerSession, error)
+
+	UpdateTowerSessionLastApplied(ctx context.Context,
+		arg sqlc.UpdateTowerSessionLastAppliedParams) error
+
+	DeleteTowerSession(ctx context.Context, sessionID []byte) (sql.Result,
+		error)
+
+	UpsertTowerStateUpdate(ctx context.Context,
+		arg sqlc.UpsertTowerStateUpdateParams) error
+
+	FetchTowerStateUpdatesByHint(ctx context.Context, hint []byte) (
+		[]sqlc.FetchTowerStateUpdatesByHintRow, error)
+
+	UpsertTowerLookoutTip(ctx context.Context,
+		arg sqlc.UpsertTowerLookoutTipParams) error
+
+	GetTowerLookoutTip(ctx context.Context) (sqlc.GetTowerLookoutTipRow,
+		error)
+}
+
+// SQLTowerQueriesTxOptions defines the set of db txn options the
+// SQLTowerQueries understands.
+type SQLTowerQueriesTxOptions struct {
+	// readOnly governs if a read only transaction is needed or not.
+	readOnly bool
+}
+
+// ReadOnly returns true if the transaction should be read only.
+//
+// NOTE: This implements the TxOptions.
+func (a *SQLTowerQueriesTxOptions) ReadOnly() bool {
+	return a.readOnly
+}
+
+// NewSQLTowerQueryReadTx creates a new read transaction option set.
+func NewSQLTowerQueryReadTx() SQLTowerQueriesTxOptions {
+	return SQLTowerQueriesTxOptions{
+		readOnly: true,
+	}
+}
+
+// BatchedSQLTowerQueries is a version of the SQLTowerQueries that's capable of
+// batched database operations.
+type BatchedSQLTowerQueries interface {
+	SQLTowerQueries
+
+	sqldb.BatchedTx[SQLTowerQueries]
+}
+
+// SQLTowerStore is a native SQL storage engine for the wtserver and lookout
+// subsystems. Unlike the TowerDB, it indexes state updates by their breach
+// hint and session, which lets large public towers scale beyond what a single
+// bbolt file allows.
+type SQLTowerStore struct {
+	db BatchedSQLTowerQueries
+}
+
+// NewSQLTowerStore creates a new SQLTowerStore instance given an open
+// BatchedSQLTowerQueries storage backend.
+func NewSQLTowerStore(db BatchedSQLTowerQueries) *SQLTowerStore {
+	return &SQLTowerStore{
+		db: db,
+	}
+}
+
+// GetSessionInfo retrieves the session for the passed session id. An error is
+// returned if the session could not be found.
+func (s *SQLTowerStore) GetSessionInfo(id *SessionID) (*SessionInfo, error) {
+	var (
+		ctx        = context.TODO()
+		readTxOpts = NewSQLTowerQueryReadTx()
+		session    *SessionInfo
+	)
+
+	err := s.db.ExecTx(ctx, &readTxOpts, func(db SQLTowerQueries) error {
+		dbSession, err := db.GetTowerSession(ctx, id[:])
+		if err != nil {
+			return err
+		}
+
+		session, err = unmarshalTowerSession(dbSession)
+
+		return err
+	}, func() {
+		session = nil
+	})
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, ErrSessionNotFound
+
+	case err != nil:
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// InsertSessionInfo records a negotiated session in the tower database. An
+// error is returned if the session already exists.
+func (s *SQLTowerStore) InsertSessionInfo(session *SessionInfo) error {
+	var (
+		ctx         = context.TODO()
+		writeTxOpts SQLTowerQueriesTxOptions
+	)
+
+	return s.db.ExecTx(ctx, &writeTxOpts, func(db SQLTowerQueries) error {
+		dbSession, err := db.GetTowerSession(ctx, session.ID[:])
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			// proceed.
+
+		case err != nil:
+			return fmt.Errorf("unable to fetch session: %w", err)
+
+		// Like the TowerDB, we allow a session to be overwritten as
+		// long as no updates were accepted for it.
+		case dbSession.LastApplied > 0:
+			return ErrSessionAlreadyExists
+		}
+
+		// Perform a quick sanity check on the session policy before
+		// accepting.
+		if err := session.Policy.Validate(); err != nil {
+			return err
+		}
+
+		policy := session.Policy
+		params := sqlc.UpsertTowerSessionParams{
+			SessionID:         session.ID[:],
+			BlobType:          int32(policy.BlobType),
+			MaxUpdates:        int32(policy.MaxUpdates),
+			RewardBase:        int64(policy.RewardBase),
+			RewardRate:        int64(policy.RewardRate),
+			SweepFeeRate:      int64(policy.SweepFeeRate),
+			LastApplied:       int32(session.LastApplied),
+			ClientLastApplied: int32(session.ClientLastApplied),
+			RewardAddress:     session.RewardAddress,
+		}
+
+		_, err = db.UpsertTowerSession(ctx, params)
+		if err != nil {
+			return fmt.Errorf("unable to insert session: %w", err)
+		}
+
+		return nil
+	}, func() {})
+}
+
+// InsertStateUpdate stores an update sent by the client after validating that
+// the update is well-formed in the context of other updates sent for the same
+// session. This include verifying that the sequence number is incremented
+// properly and the last applied values echoed by the client are sane.
+func (s *SQLTowerStore) InsertStateUpdate(
+	update *SessionStateUpdate) (uint16, error) {
+
+	var (
+		ctx         = context.TODO()
+		writeTxOpts SQLTowerQueriesTxOptions
+		lastApplied uint16
+	)
+
+	err := s.db.ExecTx(ctx, &writeTxOpts, func(db SQLTowerQueries) error {
+		// Fetch the session corresponding to the update's session id.
+		// This will be used to validate that the update's sequence
+		// number and last applied values are sane.
+		dbSession, err := db.GetTowerSession(ctx, update.ID[:])
+		if err != nil {
+			return err
+		}
+
+		session, err := unmarshalTowerSession(dbSession)
+		if err != nil {
+			return err
+		}
+
+		commitType, err := session.Policy.BlobType.CommitmentType(nil)
+		if err != nil {
+			return err
+		}
+
+		kit, err := commitType.EmptyJusticeKit()
+		if err != nil {
+			return err
+		}
+
+		// Assert that the blob is the correct size for the session's
+		// blob type.
+		if len(update.EncryptedBlob) != blob.Size(kit) {
+			return ErrInvalidBlobSize
+		}
+
+		// Validate the update against the current state of the session.
+		err = session.AcceptUpdateSequence(
+			update.SeqNum, update.LastApplied,
+		)
+		if err != nil {
+			return err
+		}
+
+		// Validation succeeded, therefore the update is committed and
+		// the session's last applied value is equal to the update's
+		// sequence number.
+		lastApplied = session.LastApplied
+
+		err = db.UpdateTowerSessionLastApplied(
+			ctx, sqlc.UpdateTowerSessionLastAppliedParams{
+				LastApplied: int32(session.LastApplied),
+				ClientLastApplied: int32(
+					session.ClientLastApplied,
+				),
+				ID: dbSession.ID,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("unable to update session: %w", err)
+		}
+
+		err = db.UpsertTowerStateUpdate(
+			ctx, sqlc.UpsertTowerStateUpdateParams{
+				SessionID:     dbSession.ID,
+				Hint:          update.Hint[:],
+				SeqNum:        int32(update.SeqNum),
+				LastApplied:   int32(update.LastApplied),
+				EncryptedBlob: update.EncryptedBlob,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("unable to insert state update: %w",
+				err)
+		}
+
+		return nil
+	}, func() {
+		lastApplied = 0
+	})
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return 0, ErrSessionNotFound
+
+	case err != nil:
+		return 0, err
+	}
+
+	return lastApplied, nil
+}
+
+// DeleteSession removes all data associated with a particular session id from
+// the tower's database.
+func (s *SQLTowerStore) DeleteSession(target SessionID) error {
+	var (
+		ctx         = context.TODO()
+		writeTxOpts SQLTowerQueriesTxOptions
+	)
+
+	return s.db.ExecTx(ctx, &writeTxOpts, func(db SQLTowerQueries) error {
+		// The state updates of the session are removed along with it
+		// through the foreign key cascade.
+		result, err := db.DeleteTowerSession(ctx, target[:])
+		if err != nil {
+			return fmt.Errorf("unable to delete session: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		// Fail if the session doesn't exit.
+		if rowsAffected == 0 {
+			return ErrSessionNotFound
+		}
+
+		return nil
+	}, func() {})
+}
+
+// QueryMatches searches against all known state updates for any that match the
+// passed breachHints. More than one Match will be returned for a given hint if
+// they exist in the database.
+func (s *SQLTowerStore) QueryMatches(
+	breachHints []blob.BreachHint) ([]Match, error) {
+
+	var (
+		ctx        = context.TODO()
+		readTxOpts = NewSQLTowerQueryReadTx()
+		matches    []Match
+	)
+
+	err := s.db.ExecTx(ctx, &readTxOpts, func(db SQLTowerQueries) error {
+		for _, hint := range breachHints {
+			rows, err := db.FetchTowerStateUpdatesByHint(
+				ctx, hint[:],
+			)
+			if err != nil {
+				return fmt.Errorf("unable to fetch state "+
+					"updates: %w", err)
+			}
+
+			for _, row := range rows {
+				session, err := unmarshalMatchSession(row)
+				if err != nil {
+					return err
+				}
+
+				matches = append(matches, Match{
+					ID:            session.ID,
+					SeqNum:        uint16(row.SeqNum),
+					Hint:          hint,
+					EncryptedBlob: row.EncryptedBlob,
+					SessionInfo:   session,
+				})
+			}
+		}
+
+		return nil
+	}, func() {
+		matches = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+// SetLookoutTip stores the provided epoch as the latest lookout tip epoch in
+// the tower database.
+func (s *SQLTowerStore) SetLookoutTip(epoch *chainntnfs.BlockEpoch) error {
+	var (
+		ctx         = context.TODO()
+		writeTxOpts SQLTowerQueriesTxOptions
+	)
+
+	return s.db.ExecTx(ctx, &writeTxOpts, func(db SQLTowerQueries) error {
+		return db.UpsertTowerLookoutTip(
+			ctx, sqlc.UpsertTowerLookoutTipParams{
+				BlockHash:   epoch.Hash[:],
+				BlockHeight: epoch.Height,
+			},
+		)
+	}, func() {})
+}
+
+// GetLookoutTip retrieves the current lookout tip block epoch from the tower
+// database. A nil epoch is returned if no tip has been stored yet.
+func (s *SQLTowerStore) GetLookoutTip() (*chainntnfs.BlockEpoch, error) {
+	var (
+		ctx        = context.TODO()
+		readTxOpts = NewSQLTowerQueryReadTx()
+		epoch      *chainntnfs.BlockEpoch
+	)
+
+	err := s.db.ExecTx(ctx, &readTxOpts, func(db SQLTowerQueries) error {
+		tip, err := db.GetTowerLookoutTip(ctx)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil
+
+		case err != nil:
+			return err
+		}
+
+		hash, err := chainhash.NewHash(tip.BlockHash)
+		if err != nil {
+			return err
+		}
+
+		epoch = &chainntnfs.BlockEpoch{
+			Hash:   hash,
+			Height: tip.BlockHeight,
+		}
+
+		return nil
+	}, func() {
+		epoch = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return epoch, nil
+}
+
+// unmarshalTowerSession converts a session row into a SessionInfo.
+func unmarshalTowerSession(dbSession sqlc.TowerSession) (*SessionInfo, error) {
+	if len(dbSession.SessionID) != SessionIDSize {
+		return nil, fmt.Errorf("invalid session id length: %d",
+			len(dbSession.SessionID))
+	}
+
+	var id SessionID
+	copy(id[:], dbSession.SessionID)
+
+	// The TowerDB always decodes the reward address as a non-nil slice, so
+	// we do the same for sessions without one.
+	rewardAddress := dbSession.RewardAddress
+	if rewardAddress == nil {
+		rewardAddress = []byte{}
+	}
+
+	return &SessionInfo{
+		ID: id,
+		Policy: wtpolicy.Policy{
+			TxPolicy: wtpolicy.TxPolicy{
+				BlobType:   blob.Type(dbSession.BlobType),
+				RewardBase: uint32(dbSession.RewardBase),
+				RewardRate: uint32(dbSession.RewardRate),
+				SweepFeeRate: chainfee.SatPerKWeight(
+					dbSession.SweepFeeRate,
+				),
+			},
+			MaxUpdates: uint16(dbSession.MaxUpdates),
+		},
+		LastApplied:       uint16(dbSession.LastApplied),
+		ClientLastApplied: uint16(dbSession.ClientLastApplied),
+		RewardAddress:     rewardAddress,
+	}, nil
+}
+
+// unmarshalMatchSession extracts the SessionInfo from a state update row that
+// was joined with its session.
+func unmarshalMatchSession(
+	row sqlc.FetchTowerStateUpdatesByHintRow) (*SessionInfo, error) {
+
+	return unmarshalTowerSession(sqlc.TowerSession{
+		SessionID:         row.SessionID,
+		BlobType:          row.BlobType,
+		MaxUpdates:        row.MaxUpdates,
+		RewardBase:        row.RewardBase,
+		RewardRate:        row.RewardRate,
+		SweepFeeRate:      row.SweepFeeRate,
+		LastApplied:       row.LastApplied,
+		ClientLastApplied: row.ClientLastApplied,
+		RewardAddress:     row.RewardAddress,
+	})
+}
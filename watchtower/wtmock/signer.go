@@ -178,6 +178,25 @@ func (s *MockSigner) MuSig2Cleanup(input.MuSig2SessionID) error {
 	return nil
 }
 
+// MuSig2DeriveNonce deterministically derives the public nonce of the local
+// key identified by the key locator for the given derivation.
+func (s *MockSigner) MuSig2DeriveNonce(keychain.KeyLocator,
+	input.MuSig2NonceDerivation) ([musig2.PubNonceSize]byte, error) {
+
+	return [musig2.PubNonceSize]byte{}, nil
+}
+
+// MuSig2CreateDerivedSession creates a new MuSig2 signing session using the
+// local key identified by the key locator, with the local nonces derived for
+// the given derivation.
+func (s *MockSigner) MuSig2CreateDerivedSession(input.MuSig2Version,
+	keychain.KeyLocator, []*btcec.PublicKey, *input.MuSig2Tweaks,
+	[][musig2.PubNonceSize]byte,
+	input.MuSig2NonceDerivation) (*input.MuSig2SessionInfo, error) {
+
+	return nil, nil
+}
+
 // AddPrivKey records the passed privKey in the MockSigner's registry of keys it
 // can sign with in the future. A unique key locator is returned, allowing the
 // caller to sign with this key when presented via an input.SignDescriptor.
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwallet"
@@ -62,6 +63,19 @@ var (
 	// ErrSweepFeeRateTooLow signals that the policy's fee rate is too low
 	// to get into the mempool during low congestion.
 	ErrSweepFeeRateTooLow = errors.New("sweep fee rate too low")
+
+	// ErrRewardRateTooHigh signals that the policy's reward rate would
+	// give the tower all of the swept funds.
+	ErrRewardRateTooHigh = errors.New("reward rate too high")
+
+	// ErrInvalidRewardScript signals that the reward script returned by
+	// the tower for a reward session is not a known segwit output script.
+	ErrInvalidRewardScript = errors.New("invalid reward script")
+
+	// ErrUnexpectedRewardScript signals that the tower returned a reward
+	// script for a session that doesn't pay a reward.
+	ErrUnexpectedRewardScript = errors.New("unexpected reward script for " +
+		"altruist session")
 )
 
 // DefaultPolicy returns a Policy containing the default parameters that can be
@@ -163,6 +177,11 @@ func (p *Policy) Validate() error {
 		return ErrAltruistReward
 	}
 
+	// The proportional reward must leave some funds for the victim.
+	if p.RewardRate >= RewardScale {
+		return ErrRewardRateTooHigh
+	}
+
 	// MaxUpdates must be positive.
 	if p.MaxUpdates == 0 {
 		return ErrNoMaxUpdates
@@ -177,6 +196,41 @@ func (p *Policy) Validate() error {
 	return nil
 }
 
+// ValidateRewardScript checks that the reward script returned by a tower
+// during session negotiation is usable under the policy. Reward sessions
+// require a P2WKH, P2WSH or P2TR script, since that is what the justice
+// transaction weight is estimated with, while altruist sessions must not have
+// a reward script at all.
+func (p *Policy) ValidateRewardScript(rewardPkScript []byte) error {
+	if !p.BlobType.Has(blob.FlagReward) {
+		if len(rewardPkScript) != 0 {
+			return ErrUnexpectedRewardScript
+		}
+
+		return nil
+	}
+
+	switch txscript.GetScriptClass(rewardPkScript) {
+	case txscript.WitnessV0PubKeyHashTy, txscript.WitnessV0ScriptHashTy,
+		txscript.WitnessV1TaprootTy:
+
+		return nil
+
+	default:
+		return ErrInvalidRewardScript
+	}
+}
+
+// omitsDustReward returns true if the policy's blob type drops a dust reward
+// output in favor of the victim instead of failing to create the justice
+// transaction. This only applies to the reward types of anchor and taproot
+// channels, the original reward type keeps its behavior so signatures for
+// existing sessions remain valid.
+func (p *Policy) omitsDustReward() bool {
+	return p.BlobType.Has(blob.FlagReward) &&
+		(p.BlobType.IsAnchorChannel() || p.BlobType.IsTaprootChannel())
+}
+
 // ComputeAltruistOutput computes the lone output value of a justice transaction
 // that pays no reward to the tower. The value is computed using the weight of
 // of the justice transaction and subtracting an amount that satisfies the
@@ -293,6 +347,18 @@ func (p *Policy) ComputeJusticeTxOuts(
 			return nil, err
 		}
 
+		// If the reward would be a dust output, the blob types that
+		// support it hand the reward to the victim instead. The weight
+		// of the reward output is still paid for, so both the client
+		// and the tower arrive at the same justice transaction.
+		rewardDust := lnwallet.DustLimitForSize(len(rewardPkScript))
+		if p.omitsDustReward() && rewardAmt < rewardDust {
+			return []*wire.TxOut{{
+				PkScript: sweepPkScript,
+				Value:    int64(sweepAmt + rewardAmt),
+			}}, nil
+		}
+
 		// Add the sweep and reward outputs to the list of txouts.
 		outputs = append(outputs, &wire.TxOut{
 			PkScript: sweepPkScript,
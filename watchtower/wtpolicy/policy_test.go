@@ -3,6 +3,8 @@ package wtpolicy_test
 import (
 	"testing"
 
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/watchtower/blob"
 	"github.com/lightningnetwork/lnd/watchtower/wtpolicy"
 	"github.com/stretchr/testify/require"
@@ -52,6 +54,30 @@ var validationTests = []struct {
 		},
 		expErr: wtpolicy.ErrSweepFeeRateTooLow,
 	},
+	{
+		name: "fail reward rate too high",
+		policy: wtpolicy.Policy{
+			TxPolicy: wtpolicy.TxPolicy{
+				BlobType:     blob.TypeRewardAnchorCommit,
+				RewardRate:   wtpolicy.RewardScale,
+				SweepFeeRate: wtpolicy.MinSweepFeeRate,
+			},
+			MaxUpdates: 1,
+		},
+		expErr: wtpolicy.ErrRewardRateTooHigh,
+	},
+	{
+		name: "valid taproot reward policy",
+		policy: wtpolicy.Policy{
+			TxPolicy: wtpolicy.TxPolicy{
+				BlobType:     blob.TypeRewardTaprootCommit,
+				RewardBase:   1000,
+				RewardRate:   wtpolicy.DefaultRewardRate,
+				SweepFeeRate: wtpolicy.MinSweepFeeRate,
+			},
+			MaxUpdates: 1,
+		},
+	},
 	{
 		name: "minimal valid altruist policy",
 		policy: wtpolicy.Policy{
@@ -122,3 +148,93 @@ func TestPolicyIsChannelType(t *testing.T) {
 	require.True(t, policyTaproot.IsTaprootChannel())
 	require.False(t, policyTaproot.IsAnchorChannel())
 }
+
+// TestValidateRewardScript asserts that only segwit reward scripts are
+// accepted for reward sessions, and no reward script for altruist sessions.
+func TestValidateRewardScript(t *testing.T) {
+	t.Parallel()
+
+	var (
+		p2wkh = append([]byte{0x00, 0x14}, make([]byte, 20)...)
+		p2tr  = append([]byte{0x51, 0x20}, make([]byte, 32)...)
+		p2pkh = append(
+			append([]byte{0x76, 0xa9, 0x14}, make([]byte, 20)...),
+			0x88, 0xac,
+		)
+	)
+
+	altruist := wtpolicy.Policy{
+		TxPolicy: wtpolicy.TxPolicy{
+			BlobType: blob.TypeAltruistAnchorCommit,
+		},
+	}
+	require.NoError(t, altruist.ValidateRewardScript(nil))
+	require.ErrorIs(
+		t, altruist.ValidateRewardScript(p2wkh),
+		wtpolicy.ErrUnexpectedRewardScript,
+	)
+
+	reward := wtpolicy.Policy{
+		TxPolicy: wtpolicy.TxPolicy{
+			BlobType: blob.TypeRewardTaprootCommit,
+		},
+	}
+	require.NoError(t, reward.ValidateRewardScript(p2wkh))
+	require.NoError(t, reward.ValidateRewardScript(p2tr))
+	require.ErrorIs(
+		t, reward.ValidateRewardScript(nil),
+		wtpolicy.ErrInvalidRewardScript,
+	)
+	require.ErrorIs(
+		t, reward.ValidateRewardScript(p2pkh),
+		wtpolicy.ErrInvalidRewardScript,
+	)
+}
+
+// TestComputeJusticeTxOutsDustReward asserts that the anchor and taproot
+// reward types hand a dust reward to the victim, while the legacy reward type
+// keeps both outputs.
+func TestComputeJusticeTxOutsDustReward(t *testing.T) {
+	t.Parallel()
+
+	var (
+		sweepScript  = append([]byte{0x00, 0x14}, make([]byte, 20)...)
+		rewardScript = append([]byte{0x51, 0x20}, make([]byte, 32)...)
+		totalAmt     = btcutil.Amount(100_000)
+		txWeight     = lntypes.WeightUnit(1000)
+	)
+
+	newPolicy := func(blobType blob.Type) wtpolicy.Policy {
+		return wtpolicy.Policy{
+			TxPolicy: wtpolicy.TxPolicy{
+				BlobType:     blobType,
+				RewardBase:   1,
+				SweepFeeRate: wtpolicy.MinSweepFeeRate,
+			},
+			MaxUpdates: 1,
+		}
+	}
+
+	txFee := wtpolicy.MinSweepFeeRate.FeeForWeight(txWeight)
+
+	for _, blobType := range []blob.Type{
+		blob.TypeRewardAnchorCommit, blob.TypeRewardTaprootCommit,
+	} {
+		policy := newPolicy(blobType)
+		outputs, err := policy.ComputeJusticeTxOuts(
+			totalAmt, txWeight, sweepScript, rewardScript,
+		)
+		require.NoError(t, err)
+		require.Len(t, outputs, 1)
+		require.Equal(t, sweepScript, outputs[0].PkScript)
+		require.EqualValues(t, totalAmt-txFee, outputs[0].Value)
+	}
+
+	policy := newPolicy(blob.TypeRewardCommit)
+	outputs, err := policy.ComputeJusticeTxOuts(
+		totalAmt, txWeight, sweepScript, rewardScript,
+	)
+	require.NoError(t, err)
+	require.Len(t, outputs, 2)
+	require.EqualValues(t, 1, outputs[1].Value)
+}
@@ -65,6 +65,17 @@ func (s *Server) handleCreateSession(peer Peer, id *wtdb.SessionID,
 		)
 	}
 
+	// If the request is for a taproot channel and the tower has them
+	// disabled, we will reject the request.
+	if s.cfg.DisableTaproot && req.BlobType.IsTaprootChannel() {
+		log.Debugf("Rejecting CreateSession from %s, taproot "+
+			"sessions disabled", id)
+		return s.replyCreateSession(
+			peer, id, wtwire.CreateSessionCodeRejectBlobType, 0,
+			nil,
+		)
+	}
+
 	// Now that we've established that this session does not exist in the
 	// database, retrieve the sweep address that will be given to the
 	// client. This address is to be included by the client when signing
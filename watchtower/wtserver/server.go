@@ -67,6 +67,11 @@ type Config struct {
 	// DisableReward causes the server to reject any session creation
 	// attempts that request rewards.
 	DisableReward bool
+
+	// DisableTaproot causes the server to reject any session creation
+	// attempts for taproot channels, and to not advertise support for
+	// them.
+	DisableTaproot bool
 }
 
 // Server houses the state required to handle watchtower peers. It's primary job
@@ -95,12 +100,16 @@ type Server struct {
 // clients connecting to the listener addresses, and allows them to open
 // sessions and send state updates.
 func New(cfg *Config) (*Server, error) {
+	features := []lnwire.FeatureBit{
+		wtwire.AltruistSessionsOptional,
+		wtwire.AnchorCommitOptional,
+	}
+	if !cfg.DisableTaproot {
+		features = append(features, wtwire.TaprootCommitOptional)
+	}
+
 	localInit := wtwire.NewInitMessage(
-		lnwire.NewRawFeatureVector(
-			wtwire.AltruistSessionsOptional,
-			wtwire.AnchorCommitOptional,
-		),
-		cfg.ChainHash,
+		lnwire.NewRawFeatureVector(features...), cfg.ChainHash,
 	)
 
 	s := &Server{